@@ -2,62 +2,341 @@ package main
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"flag"
 	"fmt"
 	"log"
 	"net/http"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
+	"text/tabwriter"
 	"time"
 
 	"golang-arch/internal/bootstrap"
+	i18n "golang-arch/internal/shared/domain/internationalization"
+	"golang-arch/internal/shared/schema"
 )
 
+const usage = `Usage: %s [command] [flags]
+
+Commands:
+  serve    Run the HTTP server (default)
+  schema   Generate JSON Schema and GraphQL definitions for the i18n types
+  doctor   Check config, DB, Redis, migrations and timezones; exits 1 on failure
+  routes   List the HTTP routes; exits 1 on duplicate or conflicting routes
+  projections status|rebuild <name>
+           Show the read-model projections' checkpoints and lag, or have the
+           worker rebuild a projection from the first event
+
+Run '%s <command> -h' for the command's flags.
+`
+
+// exitStatus ends the process with a status once the command has reported
+// its failure itself, e.g. after printing the usage
+type exitStatus int
+
+func (s exitStatus) Error() string {
+	return fmt.Sprintf("exit status %d", int(s))
+}
+
+// main exits only after run returns, so the commands' deferred cleanup
+// (closing the container, flushing metrics) runs on failures too
 func main() {
+	if err := run(os.Args[1:]); err != nil {
+		var status exitStatus
+		if errors.As(err, &status) {
+			os.Exit(int(status))
+		}
+		log.Print(err)
+		os.Exit(1)
+	}
+}
+
+// run dispatches to the command named by the first argument
+func run(args []string) error {
+	command := "serve"
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		command, args = args[0], args[1:]
+	}
+
+	switch command {
+	case "serve":
+		return serve(args)
+	case "schema":
+		return generateSchemas(args)
+	case "doctor":
+		return doctor(args)
+	case "routes":
+		return routes(args)
+	case "projections":
+		return projections(args)
+	case "help":
+		fmt.Fprintf(os.Stderr, usage, os.Args[0], os.Args[0])
+		return nil
+	default:
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n", command)
+		fmt.Fprintf(os.Stderr, usage, os.Args[0], os.Args[0])
+		return exitStatus(2)
+	}
+}
+
+// serve runs the HTTP server until SIGINT or SIGTERM
+func serve(args []string) error {
+	flags := flag.NewFlagSet("serve", flag.ExitOnError)
+	dev := flags.Bool("dev", false, "use in-memory SQLite and miniredis instead of Postgres and Redis (requires a -tags dev build)")
+	flags.Parse(args)
+
 	// Load configuration
 	config, err := bootstrap.LoadConfig()
 	if err != nil {
-		log.Fatalf("Failed to load config: %v", err)
+		return fmt.Errorf("failed to load config: %w", err)
 	}
 
 	// Initialize dependency injection container
-	container, err := bootstrap.NewContainer(config)
+	var container *bootstrap.Container
+	if *dev {
+		container, err = bootstrap.NewDevContainer(config)
+	} else {
+		container, err = bootstrap.NewContainer(config)
+	}
 	if err != nil {
-		log.Fatalf("Failed to create container: %v", err)
+		return fmt.Errorf("failed to create container: %w", err)
 	}
+	defer container.Close()
 
 	// Start the server
-	server := bootstrap.NewServer(container)
+	server, err := bootstrap.NewServer(container)
+	if err != nil {
+		return fmt.Errorf("failed to create server: %w", err)
+	}
+
+	// Register gRPC services here; they share the HTTP port when server.grpc is set
+	// Example: grpcServer := grpc.NewServer(); pb.RegisterUserServiceServer(grpcServer, impl); server.RegisterGRPC(grpcServer)
 
-	// Create HTTP server
-	httpServer := &http.Server{
-		Addr:    fmt.Sprintf(":%d", config.Server.Port),
-		Handler: server,
+	// Create the admin server first so a bad admin config fails before serving
+	adminServer, err := bootstrap.NewAdminServer(container)
+	if err != nil {
+		return fmt.Errorf("failed to create admin server: %w", err)
+	}
+
+	listener, err := bootstrap.Listen(config.Server)
+	if err != nil {
+		return fmt.Errorf("failed to listen: %w", err)
 	}
 
 	// Start server in a goroutine
+	serveErr := make(chan error, 1)
 	go func() {
-		log.Printf("Starting HTTP server on port %d", config.Server.Port)
-		if err := httpServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			log.Fatalf("Failed to start server: %v", err)
+		log.Printf("Starting HTTP server on %s", listener.Addr())
+		if err := server.Serve(listener); err != nil && err != http.ErrServerClosed {
+			serveErr <- err
 		}
 	}()
 
+	// Start the diagnostics admin server when enabled
+	if adminServer != nil {
+		go func() {
+			log.Printf("Starting admin server on %s", adminServer.Addr)
+			if err := adminServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+				log.Printf("Admin server stopped: %v", err)
+			}
+		}()
+	}
+
+	// Dump goroutine and heap profiles on SIGUSR1
+	stopDumpSignal := bootstrap.WatchDumpSignal(container)
+	defer stopDumpSignal()
+
 	// Wait for interrupt signal to gracefully shutdown the server
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
-	<-quit
+	select {
+	case <-quit:
+	case err := <-serveErr:
+		return fmt.Errorf("failed to start server: %w", err)
+	}
+
+	// Fail readiness first so load balancers stop routing here; a second
+	// signal skips the rest of the delay
+	log.Printf("Draining server for %s...", config.Shutdown.DrainDelay)
+	skip, stopSkip := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	server.Drain(skip, config.Shutdown.DrainDelay)
+	stopSkip()
 
 	log.Println("Shutting down server...")
 
-	// Create a deadline for server shutdown
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	// Create a deadline for in-flight requests and exports
+	ctx, cancel := context.WithTimeout(context.Background(), config.Shutdown.Timeout)
 	defer cancel()
 
 	// Attempt graceful shutdown
-	if err := httpServer.Shutdown(ctx); err != nil {
-		log.Fatalf("Server forced to shutdown: %v", err)
+	shutdownErr := server.Shutdown(ctx)
+
+	if adminServer != nil {
+		if err := adminServer.Shutdown(ctx); err != nil {
+			log.Printf("Admin server forced to shutdown: %v", err)
+		}
+	}
+	if shutdownErr != nil {
+		return fmt.Errorf("server forced to shutdown: %w", shutdownErr)
 	}
 
 	log.Println("Server exited")
+	return nil
+}
+
+// doctor prints the startup self-check report and exits non-zero when a
+// check failed, for CI and pre-deploy runs
+func doctor(args []string) error {
+	flags := flag.NewFlagSet("doctor", flag.ExitOnError)
+	migrations := flags.String("migrations", "src/migrations", "directory of the migration files to compare the database against")
+	timeout := flags.Duration("timeout", 5*time.Second, "timeout of each connection check")
+	noColor := flags.Bool("no-color", false, "disable colored output (also disabled by NO_COLOR or when not writing to a terminal)")
+	flags.Parse(args)
+
+	color := !*noColor && os.Getenv("NO_COLOR") == "" && isTerminal(os.Stdout)
+
+	config, err := bootstrap.LoadConfig()
+	if err != nil {
+		bootstrap.WriteDoctorReport(os.Stdout, []bootstrap.CheckResult{
+			{Name: "config", Status: bootstrap.CheckFail, Detail: err.Error()},
+		}, color)
+		return exitStatus(1)
+	}
+
+	results := bootstrap.RunDoctor(context.Background(), config, bootstrap.DoctorOptions{
+		MigrationsDir: *migrations,
+		Timeout:       *timeout,
+	})
+	if bootstrap.WriteDoctorReport(os.Stdout, results, color) {
+		return exitStatus(1)
+	}
+	return nil
+}
+
+// routes prints the HTTP routes the configuration produces, failing on
+// duplicate or conflicting registrations so CI catches them before startup
+func routes(args []string) error {
+	flags := flag.NewFlagSet("routes", flag.ExitOnError)
+	asJSON := flags.Bool("json", false, "print the routes as JSON")
+	flags.Parse(args)
+
+	config, err := bootstrap.LoadConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	list, err := bootstrap.RoutesFromConfig(config)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitStatus(1)
+	}
+
+	if *asJSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		err = encoder.Encode(list)
+	} else {
+		err = bootstrap.WriteRoutes(os.Stdout, list)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write routes: %w", err)
+	}
+	return nil
+}
+
+// projections reports the projection checkpoints or requests a rebuild,
+// which the worker's projections job carries out on its next run
+func projections(args []string) error {
+	flags := flag.NewFlagSet("projections", flag.ExitOnError)
+	asJSON := flags.Bool("json", false, "print the status as JSON")
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: %s projections status|rebuild <name> [flags]\n", os.Args[0])
+		flags.PrintDefaults()
+	}
+	if len(args) == 0 {
+		flags.Usage()
+		return exitStatus(2)
+	}
+	action := args[0]
+	flags.Parse(args[1:])
+
+	config, err := bootstrap.LoadConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	container, err := bootstrap.NewContainer(config)
+	if err != nil {
+		return fmt.Errorf("failed to create container: %w", err)
+	}
+	defer container.Close()
+	ctx := context.Background()
+
+	switch action {
+	case "status":
+		statuses, err := container.ReadSide.Status(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to read projection status: %w", err)
+		}
+		if *asJSON {
+			encoder := json.NewEncoder(os.Stdout)
+			encoder.SetIndent("", "  ")
+			err = encoder.Encode(statuses)
+		} else {
+			tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+			fmt.Fprintln(tw, "NAME\tPOSITION\tHEAD\tLAG\tREBUILD\tUPDATED")
+			for _, status := range statuses {
+				updated := "-"
+				if !status.UpdatedAt.IsZero() {
+					updated = status.UpdatedAt.Format(time.RFC3339)
+				}
+				fmt.Fprintf(tw, "%s\t%d\t%d\t%d\t%t\t%s\n",
+					status.Name, status.Position, status.Head, status.Lag, status.Rebuild, updated)
+			}
+			err = tw.Flush()
+		}
+		if err != nil {
+			return fmt.Errorf("failed to write projection status: %w", err)
+		}
+	case "rebuild":
+		if flags.NArg() != 1 {
+			flags.Usage()
+			return exitStatus(2)
+		}
+		if err := container.ReadSide.Rebuild(ctx, flags.Arg(0)); err != nil {
+			return fmt.Errorf("failed to request rebuild: %w", err)
+		}
+		fmt.Printf("Rebuild of %s requested; the worker runs it on its next projections run\n", flags.Arg(0))
+	default:
+		flags.Usage()
+		return exitStatus(2)
+	}
+	return nil
+}
+
+// isTerminal reports whether file is a character device such as a TTY
+func isTerminal(file *os.File) bool {
+	info, err := file.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// generateSchemas writes the JSON Schema documents and GraphQL scalars of the
+// i18n value objects
+func generateSchemas(args []string) error {
+	flags := flag.NewFlagSet("schema", flag.ExitOnError)
+	out := flags.String("out", "docs/09-internationalization/schemas", "directory to write the schema files to")
+	baseURL := flags.String("base-url", "", "URL prefix for the schema $id and GraphQL @specifiedBy URLs")
+	flags.Parse(args)
+
+	paths, err := schema.WriteFiles(*out, *baseURL, i18n.SchemaTypes...)
+	if err != nil {
+		return fmt.Errorf("failed to generate schemas: %w", err)
+	}
+	for _, path := range paths {
+		fmt.Println(path)
+	}
+	return nil
 }
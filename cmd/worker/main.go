@@ -2,54 +2,99 @@ package main
 
 import (
 	"context"
+	"fmt"
 	"log"
+	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
-	"time"
 
 	"golang-arch/internal/bootstrap"
 )
 
+// main exits only after run returns, so the deferred cleanup runs on
+// failures too
 func main() {
+	if err := run(); err != nil {
+		log.Print(err)
+		os.Exit(1)
+	}
+}
+
+// run runs the worker until SIGINT or SIGTERM
+func run() error {
 	// Load configuration
 	config, err := bootstrap.LoadConfig()
 	if err != nil {
-		log.Fatalf("Failed to load config: %v", err)
+		return fmt.Errorf("failed to load config: %w", err)
 	}
 
 	// Initialize dependency injection container
 	container, err := bootstrap.NewContainer(config)
 	if err != nil {
-		log.Fatalf("Failed to create container: %v", err)
+		return fmt.Errorf("failed to create container: %w", err)
+	}
+	defer container.Close()
+
+	// Create the admin server first so a bad admin config fails before the jobs start
+	adminServer, err := bootstrap.NewAdminServer(container)
+	if err != nil {
+		return fmt.Errorf("failed to create admin server: %w", err)
 	}
 
 	// Start the worker
 	worker := bootstrap.NewWorker(container)
 
 	// Start worker in a goroutine
+	startErr := make(chan error, 1)
 	go func() {
 		log.Println("Starting background worker...")
 		if err := worker.Start(); err != nil {
-			log.Fatalf("Failed to start worker: %v", err)
+			startErr <- err
 		}
 	}()
 
+	// Start the diagnostics admin server when enabled
+	if adminServer != nil {
+		go func() {
+			log.Printf("Starting admin server on %s", adminServer.Addr)
+			if err := adminServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+				log.Printf("Admin server stopped: %v", err)
+			}
+		}()
+	}
+
+	// Dump goroutine and heap profiles on SIGUSR1
+	stopDumpSignal := bootstrap.WatchDumpSignal(container)
+	defer stopDumpSignal()
+
 	// Wait for interrupt signal to gracefully shutdown the worker
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
-	<-quit
+	select {
+	case <-quit:
+	case err := <-startErr:
+		return fmt.Errorf("failed to start worker: %w", err)
+	}
 
 	log.Println("Shutting down worker...")
 
 	// Create a deadline for worker shutdown
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	ctx, cancel := context.WithTimeout(context.Background(), config.Shutdown.Timeout)
 	defer cancel()
 
 	// Attempt graceful shutdown
-	if err := worker.Shutdown(ctx); err != nil {
-		log.Fatalf("Worker forced to shutdown: %v", err)
+	shutdownErr := worker.Shutdown(ctx)
+
+	if adminServer != nil {
+		if err := adminServer.Shutdown(ctx); err != nil {
+			log.Printf("Admin server forced to shutdown: %v", err)
+		}
+	}
+	if shutdownErr != nil {
+		return fmt.Errorf("worker forced to shutdown: %w", shutdownErr)
 	}
 
 	log.Println("Worker exited")
+	return nil
 }
@@ -0,0 +1,148 @@
+package bootstrap
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"expvar"
+	"fmt"
+	"net/http"
+	"net/http/pprof"
+	"os"
+	"path/filepath"
+	"runtime"
+	runtimepprof "runtime/pprof"
+	"strings"
+	"time"
+
+	"golang-arch/internal/shared/api"
+	"golang-arch/internal/shared/config"
+
+	"go.uber.org/zap"
+)
+
+// NewAdminServer creates the diagnostics HTTP server exposing pprof, expvar
+// and, with the Prometheus exporter, metrics endpoints on a dedicated port.
+// It returns nil when the admin server is disabled.
+func NewAdminServer(container *Container) (*http.Server, error) {
+	adminConfig := container.Config.Admin
+	if !adminConfig.Enabled {
+		return nil, nil
+	}
+
+	if adminConfig.Token == "" {
+		return nil, fmt.Errorf("admin server requires admin.token to be set")
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+	mux.Handle("/debug/vars", expvar.Handler())
+	if handler := container.Metrics.Handler(); handler != nil {
+		// The worker has no other HTTP port to be scraped on
+		mux.Handle(container.Config.Metrics.Path, handler)
+	}
+
+	return &http.Server{
+		Addr:              fmt.Sprintf("%s:%d", adminConfig.Host, adminConfig.Port),
+		Handler:           adminAuth(adminConfig.Token, mux),
+		ReadHeaderTimeout: 10 * time.Second,
+	}, nil
+}
+
+// adminAuth guards the admin endpoints with a static bearer token. Requests
+// without a bearer token get 401 and those with another token 403, both in
+// the API error envelope.
+func adminAuth(token string, next http.Handler) http.Handler {
+	expected := []byte(token)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		provided, found := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !found || provided == "" {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			writeAdminError(w, http.StatusUnauthorized, api.NewUnauthorizedError("a bearer token is required"))
+			return
+		}
+		if subtle.ConstantTimeCompare([]byte(provided), expected) != 1 {
+			writeAdminError(w, http.StatusForbidden, api.NewForbiddenError("invalid admin token"))
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// writeAdminError writes apiErr in the API error envelope
+func writeAdminError(w http.ResponseWriter, status int, apiErr *api.APIError) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(api.Response{
+		Success: false,
+		Message: http.StatusText(status),
+		Error:   apiErr.Message,
+		Code:    apiErr.Code,
+	})
+}
+
+// WriteDiagnosticsDump writes goroutine and heap profiles into the configured dump
+// directory and returns the paths of the written files.
+func WriteDiagnosticsDump(adminConfig config.AdminConfig) ([]string, error) {
+	dir := adminConfig.DumpDir
+	if dir == "" {
+		dir = os.TempDir()
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create dump directory: %w", err)
+	}
+
+	stamp := time.Now().UTC().Format("20060102T150405Z")
+	pid := os.Getpid()
+
+	var written []string
+	for _, profile := range []string{"goroutine", "heap"} {
+		path := filepath.Join(dir, fmt.Sprintf("%s-%d-%s.pprof", profile, pid, stamp))
+		if err := writeProfile(profile, path); err != nil {
+			return written, err
+		}
+		written = append(written, path)
+	}
+
+	return written, nil
+}
+
+// writeProfile writes a single named runtime profile to path
+func writeProfile(name, path string) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create %s dump: %w", name, err)
+	}
+	defer file.Close()
+
+	if name == "heap" {
+		runtime.GC()
+	}
+
+	profile := runtimepprof.Lookup(name)
+	if profile == nil {
+		return fmt.Errorf("unknown profile: %s", name)
+	}
+
+	if err := profile.WriteTo(file, 0); err != nil {
+		return fmt.Errorf("failed to write %s dump: %w", name, err)
+	}
+
+	return nil
+}
+
+// logDiagnosticsDump writes a dump and reports the outcome through the logger
+func logDiagnosticsDump(logger *zap.Logger, adminConfig config.AdminConfig) {
+	paths, err := WriteDiagnosticsDump(adminConfig)
+	if err != nil {
+		logger.Error("Failed to write diagnostics dump", zap.Error(err))
+		return
+	}
+
+	logger.Info("Diagnostics dump written", zap.String("files", strings.Join(paths, ",")))
+}
@@ -0,0 +1,8 @@
+//go:build !unix
+
+package bootstrap
+
+// WatchDumpSignal is a no-op on platforms without SIGUSR1.
+func WatchDumpSignal(container *Container) func() {
+	return func() {}
+}
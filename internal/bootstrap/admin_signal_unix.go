@@ -0,0 +1,33 @@
+//go:build unix
+
+package bootstrap
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// WatchDumpSignal writes goroutine and heap dumps whenever the process receives
+// SIGUSR1. The returned function stops watching for the signal.
+func WatchDumpSignal(container *Container) func() {
+	signals := make(chan os.Signal, 1)
+	done := make(chan struct{})
+	signal.Notify(signals, syscall.SIGUSR1)
+
+	go func() {
+		for {
+			select {
+			case <-signals:
+				logDiagnosticsDump(container.Logger, container.Config.Admin)
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	return func() {
+		signal.Stop(signals)
+		close(done)
+	}
+}
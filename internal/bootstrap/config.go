@@ -5,6 +5,7 @@ import (
 	"os"
 
 	"golang-arch/internal/shared/config"
+	"golang-arch/internal/shared/secheaders"
 
 	"github.com/spf13/viper"
 )
@@ -20,16 +21,145 @@ func LoadConfig() (*config.AppConfig, error) {
 	// Set default values
 	viper.SetDefault("server.port", 8080)
 	viper.SetDefault("server.host", "0.0.0.0")
+	viper.SetDefault("server.trusted_proxies", []string{})
+	viper.SetDefault("server.remote_ip_headers", []string{"X-Forwarded-For", "X-Real-IP"})
+	viper.SetDefault("server.h2c", false)
+	viper.SetDefault("server.grpc", false)
+	viper.SetDefault("server.systemd_activation", true)
+	viper.SetDefault("server.reuse_port", false)
 	viper.SetDefault("database.host", "localhost")
 	viper.SetDefault("database.port", 5432)
 	viper.SetDefault("database.name", "golang_arch")
 	viper.SetDefault("database.user", "postgres")
 	viper.SetDefault("database.ssl_mode", "disable")
+	viper.SetDefault("database.query_timeout", "10s")
+	viper.SetDefault("database.statement_timeout", "30s")
+	viper.SetDefault("database.timezone", "UTC")
+	viper.SetDefault("database.locale", "C")
 	viper.SetDefault("redis.host", "localhost")
 	viper.SetDefault("redis.port", 6379)
 	viper.SetDefault("redis.db", 0)
 	viper.SetDefault("log.level", "info")
 	viper.SetDefault("log.format", "json")
+	viper.SetDefault("admin.enabled", false)
+	viper.SetDefault("admin.host", "127.0.0.1")
+	viper.SetDefault("admin.port", 6060)
+	viper.SetDefault("admin.dump_dir", os.TempDir())
+	viper.SetDefault("metrics.exporter", "prometheus")
+	viper.SetDefault("metrics.path", "/metrics")
+	viper.SetDefault("metrics.export_interval", "30s")
+	viper.SetDefault("metrics.service", "golang-arch")
+	viper.SetDefault("metrics.environment", "production")
+	viper.SetDefault("rates.provider", "static")
+	viper.SetDefault("rates.base", "USD")
+	viper.SetDefault("rates.refresh_schedule", "@hourly")
+	viper.SetDefault("rates.cache_ttl", "2h")
+	viper.SetDefault("rates.max_age", "26h")
+	viper.SetDefault("rates.ecb_url", "https://www.ecb.europa.eu/stats/eurofxref/eurofxref-daily.xml")
+	viper.SetDefault("rates.oxr_url", "https://openexchangerates.org/api")
+	viper.SetDefault("rates.fetch_timeout", "10s")
+	viper.SetDefault("regions.provider", "none")
+	viper.SetDefault("regions.geonames_url", "https://secure.geonames.org")
+	viper.SetDefault("regions.cache_ttl", "24h")
+	viper.SetDefault("regions.min_interval", "4s")
+	viper.SetDefault("phone_verify.provider", "static")
+	viper.SetDefault("phone_verify.url", "https://www.hlr-lookups.com/api/v2")
+	viper.SetDefault("phone_verify.cache_ttl", "24h")
+	viper.SetDefault("phone_verify.retry_ttl", "1h")
+	viper.SetDefault("otp.code_length", 6)
+	viper.SetDefault("otp.ttl", "5m")
+	viper.SetDefault("otp.max_attempts", 5)
+	viper.SetDefault("otp.cooldown", "1m")
+	viper.SetDefault("otp.send_limit", 5)
+	viper.SetDefault("otp.send_window", "1h")
+	viper.SetDefault("otp.sender", "log")
+	viper.SetDefault("otp.delivery_schedule", "@every 2s")
+	viper.SetDefault("encryption.provider", "none")
+	viper.SetDefault("encryption.reload_interval", "5m")
+	viper.SetDefault("encryption.vault.mount", "secret")
+	viper.SetDefault("encryption.vault.path", "golang-arch/encryption")
+	viper.SetDefault("encryption.vault.timeout", "10s")
+	viper.SetDefault("erasure.strategy", "scrub")
+	viper.SetDefault("security.headers.enabled", true)
+	viper.SetDefault("security.headers.hsts_max_age", "8760h")
+	viper.SetDefault("security.headers.hsts_include_subdomains", true)
+	viper.SetDefault("security.headers.content_type_nosniff", true)
+	viper.SetDefault("security.headers.frame_options", "DENY")
+	viper.SetDefault("security.headers.referrer_policy", "strict-origin-when-cross-origin")
+	viper.SetDefault("security.headers.content_security_policy", secheaders.DefaultContentSecurityPolicy)
+	viper.SetDefault("security.headers.cross_origin_opener_policy", "same-origin")
+	viper.SetDefault("security.headers.cross_origin_resource_policy", "same-origin")
+	viper.SetDefault("metering.enabled", false)
+	viper.SetDefault("metering.header", "X-API-Key")
+	viper.SetDefault("metering.flush_schedule", "@every 1m")
+	viper.SetDefault("worker.breaker_threshold", 5)
+	viper.SetDefault("worker.breaker_cooldown", "5m")
+	viper.SetDefault("startup.wait_timeout", "60s")
+	viper.SetDefault("startup.retry_initial", "500ms")
+	viper.SetDefault("startup.retry_max", "5s")
+	viper.SetDefault("startup.degraded", false)
+	viper.SetDefault("shutdown.drain_delay", "5s")
+	viper.SetDefault("shutdown.timeout", "30s")
+	viper.SetDefault("storage.provider", "local")
+	viper.SetDefault("storage.region", "us-east-1")
+	viper.SetDefault("storage.local_dir", "./data/blobs")
+	viper.SetDefault("storage.public_url", "http://localhost:8080")
+	viper.SetDefault("storage.max_upload_size", 100<<20)
+	viper.SetDefault("documents.pdf_provider", "none")
+	viper.SetDefault("documents.timeout", "30s")
+	viper.SetDefault("rbac.roles", map[string][]string{
+		"i18n-admin":    {"i18n:read", "i18n:write"},
+		"i18n-viewer":   {"i18n:read"},
+		"privacy-admin": {"consent:admin", "notify:admin", "push:admin"},
+	})
+	viper.SetDefault("refdata.channel", "refdata_changed")
+	viper.SetDefault("i18n.default_locale", "en-US")
+	viper.SetDefault("i18n.default_timezone", "UTC")
+	viper.SetDefault("i18n.default_currency", "USD")
+	viper.SetDefault("i18n.supported_locales", []string{})
+	viper.SetDefault("i18n.strict_money_json", false)
+	viper.SetDefault("i18n.non_negative_money", false)
+	viper.SetDefault("i18n.allowed_currencies", []string{})
+	viper.SetDefault("i18n_api.convert_max_age", "60s")
+	viper.SetDefault("i18n_api.convert_rate_limit", 120)
+	viper.SetDefault("i18n_api.convert_rate_window", "1m")
+	viper.SetDefault("i18n_api.catalog_cache_ttl", "1m")
+	viper.SetDefault("i18n_api.catalog_cache_stale", "5m")
+	viper.SetDefault("i18n_api.catalog_cache_size", 10000)
+	viper.SetDefault("health.check_schedule", "@every 1m")
+	viper.SetDefault("health.timeout", "5s")
+	viper.SetDefault("health.history_size", 1440)
+	viper.SetDefault("jobs.ttl", "24h")
+	viper.SetDefault("jobs.max_wait", "30s")
+	viper.SetDefault("exports.concurrency", 4)
+	viper.SetDefault("exports.url_ttl", "24h")
+	viper.SetDefault("exports.progress_interval", 1000)
+	viper.SetDefault("notifications.default_channels", []string{"push", "email", "sms"})
+	viper.SetDefault("notifications.webhook_timeout", "10s")
+	viper.SetDefault("push.fcm.url", "https://fcm.googleapis.com")
+	viper.SetDefault("push.max_attempts", 5)
+	viper.SetDefault("push.retry_backoff", "30s")
+	viper.SetDefault("push.delivery_schedule", "@every 5s")
+	viper.SetDefault("sagas.max_attempts", 3)
+	viper.SetDefault("sagas.retry_backoff", "10s")
+	viper.SetDefault("sagas.lease", "1m")
+	viper.SetDefault("sagas.resume_schedule", "@every 10s")
+	viper.SetDefault("events.processed_retention", "168h")
+	viper.SetDefault("events.prune_schedule", "@every 1h")
+	viper.SetDefault("projections.schedule", "@every 5s")
+	viper.SetDefault("projections.batch_size", 500)
+	viper.SetDefault("retention.schedule", "@every 1h")
+	viper.SetDefault("retention.batch_size", 1000)
+	viper.SetDefault("retention.archive_prefix", "retention")
+	viper.SetDefault("chaos.enabled", false)
+	viper.SetDefault("chaos.skip_paths", []string{"/health", "/ready", "/metrics"})
+	viper.SetDefault("load_shedding.enabled", false)
+	viper.SetDefault("load_shedding.max_in_flight", 256)
+	viper.SetDefault("load_shedding.min_in_flight", 16)
+	viper.SetDefault("load_shedding.max_queue", 128)
+	viper.SetDefault("load_shedding.queue_timeout", "100ms")
+	viper.SetDefault("load_shedding.retry_after", "1s")
+	viper.SetDefault("load_shedding.priority_paths", []string{"/health", "/ready", "/status", "/metrics"})
 
 	// Read environment variables
 	viper.AutomaticEnv()
@@ -48,12 +178,85 @@ func LoadConfig() (*config.AppConfig, error) {
 	overrideFromEnv("DATABASE_USER", "database.user")
 	overrideFromEnv("DATABASE_PASSWORD", "database.password")
 	overrideFromEnv("DATABASE_SSL_MODE", "database.ssl_mode")
+	overrideFromEnv("DATABASE_QUERY_TIMEOUT", "database.query_timeout")
+	overrideFromEnv("DATABASE_STATEMENT_TIMEOUT", "database.statement_timeout")
+	overrideFromEnv("DATABASE_TIMEZONE", "database.timezone")
+	overrideFromEnv("DATABASE_LOCALE", "database.locale")
 	overrideFromEnv("REDIS_HOST", "redis.host")
 	overrideFromEnv("REDIS_PORT", "redis.port")
 	overrideFromEnv("REDIS_PASSWORD", "redis.password")
 	overrideFromEnv("REDIS_DB", "redis.db")
 	overrideFromEnv("SERVER_PORT", "server.port")
+	overrideFromEnv("SERVER_TRUSTED_PROXIES", "server.trusted_proxies")
+	overrideFromEnv("SERVER_TRUSTED_PLATFORM", "server.trusted_platform")
+	overrideFromEnv("SERVER_H2C", "server.h2c")
+	overrideFromEnv("SERVER_GRPC", "server.grpc")
+	overrideFromEnv("SERVER_SOCKET", "server.socket")
 	overrideFromEnv("LOG_LEVEL", "log.level")
+	overrideFromEnv("LOG_LEVELS", "log.levels")
+	overrideFromEnv("ADMIN_ENABLED", "admin.enabled")
+	overrideFromEnv("ADMIN_PORT", "admin.port")
+	overrideFromEnv("ADMIN_TOKEN", "admin.token")
+	overrideFromEnv("ADMIN_DUMP_DIR", "admin.dump_dir")
+	overrideFromEnv("METRICS_EXPORTER", "metrics.exporter")
+	overrideFromEnv("METRICS_OTLP_ENDPOINT", "metrics.otlp_endpoint")
+	overrideFromEnv("METRICS_ENVIRONMENT", "metrics.environment")
+	overrideFromEnv("METRICS_VERSION", "metrics.version")
+	overrideFromEnv("RATES_PROVIDER", "rates.provider")
+	overrideFromEnv("RATES_REFRESH_SCHEDULE", "rates.refresh_schedule")
+	overrideFromEnv("OXR_APP_ID", "rates.oxr_app_id")
+	overrideFromEnv("GEOIP_DATABASE_PATH", "geo.database_path")
+	overrideFromEnv("REGIONS_PROVIDER", "regions.provider")
+	overrideFromEnv("GEONAMES_USERNAME", "regions.geonames_username")
+	overrideFromEnv("PHONE_VERIFY_PROVIDER", "phone_verify.provider")
+	overrideFromEnv("PHONE_VERIFY_API_KEY", "phone_verify.api_key")
+	overrideFromEnv("PHONE_VERIFY_API_SECRET", "phone_verify.api_secret")
+	overrideFromEnv("PHONE_VERIFY_SECRET", "phone_verify.secret")
+	overrideFromEnv("OTP_SECRET", "otp.secret")
+	overrideFromEnv("ENCRYPTION_PROVIDER", "encryption.provider")
+	overrideFromEnv("ENCRYPTION_KEYS", "encryption.keys")
+	overrideFromEnv("ENCRYPTION_PRIMARY_KEY", "encryption.primary_key")
+	overrideFromEnv("VAULT_ADDR", "encryption.vault.address")
+	overrideFromEnv("VAULT_TOKEN", "encryption.vault.token")
+	overrideFromEnv("ERASURE_SECRET", "erasure.secret")
+	overrideFromEnv("SECURITY_HEADERS_ENABLED", "security.headers.enabled")
+	overrideFromEnv("SECURITY_CSP", "security.headers.content_security_policy")
+	overrideFromEnv("METERING_ENABLED", "metering.enabled")
+	overrideFromEnv("METERING_DEFAULT_PLAN", "metering.default_plan")
+	overrideFromEnv("METERING_ANONYMOUS_PLAN", "metering.anonymous_plan")
+	overrideFromEnv("STARTUP_WAIT_TIMEOUT", "startup.wait_timeout")
+	overrideFromEnv("STARTUP_DEGRADED", "startup.degraded")
+	overrideFromEnv("SHUTDOWN_DRAIN_DELAY", "shutdown.drain_delay")
+	overrideFromEnv("SHUTDOWN_TIMEOUT", "shutdown.timeout")
+	overrideFromEnv("STORAGE_PROVIDER", "storage.provider")
+	overrideFromEnv("STORAGE_BUCKET", "storage.bucket")
+	overrideFromEnv("STORAGE_REGION", "storage.region")
+	overrideFromEnv("STORAGE_ENDPOINT", "storage.endpoint")
+	overrideFromEnv("STORAGE_ACCESS_KEY", "storage.access_key")
+	overrideFromEnv("STORAGE_SECRET_KEY", "storage.secret_key")
+	overrideFromEnv("STORAGE_PUBLIC_URL", "storage.public_url")
+	overrideFromEnv("STORAGE_URL_SECRET", "storage.url_secret")
+	overrideFromEnv("STORAGE_MAX_UPLOAD_SIZE", "storage.max_upload_size")
+	overrideFromEnv("DOCUMENTS_PDF_PROVIDER", "documents.pdf_provider")
+	overrideFromEnv("DOCUMENTS_GOTENBERG_URL", "documents.gotenberg_url")
+	overrideFromEnv("TEMPLATES_DIR", "templates.dir")
+	overrideFromEnv("REFDATA_CHANNEL", "refdata.channel")
+	overrideFromEnv("I18N_DEFAULT_LOCALE", "i18n.default_locale")
+	overrideFromEnv("I18N_DEFAULT_TIMEZONE", "i18n.default_timezone")
+	overrideFromEnv("I18N_DEFAULT_CURRENCY", "i18n.default_currency")
+	overrideFromEnv("I18N_STRICT_MONEY_JSON", "i18n.strict_money_json")
+	overrideFromEnv("I18N_NON_NEGATIVE_MONEY", "i18n.non_negative_money")
+	overrideFromEnv("I18N_API_CONVERT_RATE_LIMIT", "i18n_api.convert_rate_limit")
+	overrideFromEnv("I18N_API_CATALOG_CACHE_TTL", "i18n_api.catalog_cache_ttl")
+	overrideFromEnv("HEALTH_CHECK_SCHEDULE", "health.check_schedule")
+	overrideFromEnv("CHAOS_ENABLED", "chaos.enabled")
+	overrideFromEnv("LOAD_SHEDDING_ENABLED", "load_shedding.enabled")
+	overrideFromEnv("LOAD_SHEDDING_MAX_IN_FLIGHT", "load_shedding.max_in_flight")
+
+	// Resolve ${ENV_VAR} and ${section.key} placeholders
+	if err := expandConfig(viper.GetViper()); err != nil {
+		return nil, fmt.Errorf("failed to expand config: %w", err)
+	}
 
 	var config config.AppConfig
 	if err := viper.Unmarshal(&config); err != nil {
@@ -0,0 +1,288 @@
+//go:build dev
+
+package bootstrap
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io"
+	"log"
+
+	"golang-arch/internal/shared/chaos"
+	"golang-arch/internal/shared/config"
+	"golang-arch/internal/shared/consent"
+	"golang-arch/internal/shared/erasure"
+	"golang-arch/internal/shared/events"
+	"golang-arch/internal/shared/eventsource"
+	"golang-arch/internal/shared/geo"
+	"golang-arch/internal/shared/jobs"
+	"golang-arch/internal/shared/metering"
+	"golang-arch/internal/shared/notify"
+	"golang-arch/internal/shared/projection"
+	"golang-arch/internal/shared/push"
+	"golang-arch/internal/shared/rbac"
+	"golang-arch/internal/shared/refdata"
+	"golang-arch/internal/shared/repository"
+	"golang-arch/internal/shared/retention"
+	"golang-arch/internal/shared/saga"
+	"golang-arch/internal/shared/storage"
+	"golang-arch/pkg/clock"
+	"golang-arch/pkg/logger"
+	"golang-arch/pkg/metrics"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	_ "modernc.org/sqlite"
+)
+
+// DevModeAvailable reports whether this binary was built with the dev profile
+const DevModeAvailable = true
+
+// devDatabaseDSN is a shared in-memory SQLite database that lives as long as
+// at least one connection is open
+const devDatabaseDSN = "file:golang_arch_dev?mode=memory&cache=shared&_pragma=foreign_keys(1)"
+
+// NewDevContainer creates a container with zero external infrastructure: an
+// in-memory SQLite database and an embedded miniredis server. Only available
+// in binaries built with `-tags dev`.
+func NewDevContainer(config *config.AppConfig) (*Container, error) {
+	loggers, err := logger.NewFactory(config.Log.Level, config.Log.Format, config.Log.Levels)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize logger: %w", err)
+	}
+
+	metricsProvider, err := metrics.NewProvider(metrics.Options{
+		Exporter:       config.Metrics.Exporter,
+		OTLPEndpoint:   config.Metrics.OTLPEndpoint,
+		OTLPInsecure:   config.Metrics.OTLPInsecure,
+		ExportInterval: config.Metrics.ExportInterval,
+		Identity:       metrics.Identity{Service: config.Metrics.Service, Env: "dev", Version: metrics.BuildVersion()},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize metrics: %w", err)
+	}
+	configureMoneyJSON(config.I18n, metricsProvider.Instruments)
+	if err := configureConstraints(config.I18n); err != nil {
+		return nil, err
+	}
+	faults, err := newChaosInjector(config.Chaos, "dev", metricsProvider.Instruments, loggers)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize chaos: %w", err)
+	}
+	shedder, err := newLoadShedder(config.LoadShed, metricsProvider.Instruments, loggers)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize load shedding: %w", err)
+	}
+	sunsets, err := newDeprecations(config.Deprecations, metricsProvider.Instruments)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize deprecations: %w", err)
+	}
+
+	db, err := openDevDatabase(faults)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open dev database: %w", err)
+	}
+	// Keep one connection open so the in-memory database is not discarded
+	db.SetMaxIdleConns(1)
+	db.SetConnMaxLifetime(0)
+	if err := db.PingContext(context.Background()); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to ping dev database: %w", err)
+	}
+
+	redisServer, err := miniredis.Run()
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to start miniredis: %w", err)
+	}
+
+	log.Printf("Dev mode: using in-memory SQLite and miniredis at %s", redisServer.Addr())
+
+	redisClient := redis.NewClient(&redis.Options{Addr: redisServer.Addr()})
+	faults.Instrument(redisClient)
+
+	clk := clock.New()
+	authz, err := rbac.NewAuthorizer(config.RBAC)
+	if err != nil {
+		redisClient.Close()
+		redisServer.Close()
+		db.Close()
+		return nil, fmt.Errorf("failed to initialize rbac: %w", err)
+	}
+
+	refData, err := newRefDataService(refdata.NewMemoryStore(), refdata.NewLocalNotifier(), clk, loggers)
+	if err != nil {
+		redisClient.Close()
+		redisServer.Close()
+		db.Close()
+		return nil, fmt.Errorf("failed to initialize reference data: %w", err)
+	}
+
+	ratesService, err := newRatesService(config.Rates, db, redisClient, refData, clk, loggers)
+	if err != nil {
+		redisClient.Close()
+		redisServer.Close()
+		db.Close()
+		return nil, fmt.Errorf("failed to initialize rates: %w", err)
+	}
+
+	geoResolver, err := geo.NewResolver(config.Geo)
+	if err != nil {
+		redisClient.Close()
+		redisServer.Close()
+		db.Close()
+		return nil, fmt.Errorf("failed to initialize geoip: %w", err)
+	}
+
+	regionsService, err := newRegionsService(config.Regions, clk, loggers)
+	if err != nil {
+		redisClient.Close()
+		redisServer.Close()
+		db.Close()
+		return nil, fmt.Errorf("failed to initialize regions: %w", err)
+	}
+
+	phoneVerifier, err := newPhoneVerifier(config.PhoneVerify, redisClient, clk, loggers)
+	if err != nil {
+		redisClient.Close()
+		redisServer.Close()
+		db.Close()
+		return nil, fmt.Errorf("failed to initialize phone verification: %w", err)
+	}
+
+	encryptor, err := newEncryptor(config.Encryption)
+	if err != nil {
+		redisClient.Close()
+		redisServer.Close()
+		db.Close()
+		return nil, fmt.Errorf("failed to initialize encryption: %w", err)
+	}
+
+	locales, err := newLocaleDefaults(config.I18n)
+	if err != nil {
+		redisClient.Close()
+		redisServer.Close()
+		db.Close()
+		return nil, fmt.Errorf("failed to initialize i18n defaults: %w", err)
+	}
+
+	otpService, err := newOTPService(config.OTP, redisClient, phoneVerifier, encryptor, locales, clk, loggers)
+	if err != nil {
+		redisClient.Close()
+		redisServer.Close()
+		db.Close()
+		return nil, fmt.Errorf("failed to initialize otp: %w", err)
+	}
+
+	erasureService, err := newErasureService(config.Erasure, erasure.NewPostgresAuditStore(db), clk, loggers)
+	if err != nil {
+		redisClient.Close()
+		redisServer.Close()
+		db.Close()
+		return nil, fmt.Errorf("failed to initialize erasure: %w", err)
+	}
+
+	meter, err := newMeter(config.Metering, redisClient, metering.NewMemoryStore(), clk, loggers)
+	if err != nil {
+		redisClient.Close()
+		redisServer.Close()
+		db.Close()
+		return nil, fmt.Errorf("failed to initialize metering: %w", err)
+	}
+
+	blobs := storage.NewMemoryStore(clk)
+	docs, err := newDocumentService(config.Documents, blobs)
+	if err != nil {
+		redisClient.Close()
+		redisServer.Close()
+		db.Close()
+		return nil, fmt.Errorf("failed to initialize documents: %w", err)
+	}
+
+	views, err := newViews(config, locales, refData, loggers)
+	if err != nil {
+		redisClient.Close()
+		redisServer.Close()
+		db.Close()
+		return nil, fmt.Errorf("failed to initialize templates: %w", err)
+	}
+
+	schemas := events.NewRegistry()
+	container := &Container{
+		Config:   config,
+		DB:       db,
+		Redis:    redisClient,
+		Logger:   loggers.Root(),
+		Loggers:  loggers,
+		Metrics:  metricsProvider,
+		Clock:    clk,
+		I18n:     locales,
+		Events:   events.NewBus(loggers.Named(logger.NameEvents)),
+		Ledger:   events.NewMemoryLedger(clk),
+		Broker:   schemas.Publisher(events.NewRedisBroker(redisClient, eventChannelPrefix)),
+		Schemas:  schemas,
+		Journal:  eventsource.NewMemoryStore(clk),
+		Audit:    repository.NewMemoryAuditLog(),
+		Rates:    ratesService,
+		Convert:  ratesService.Converter(context.Background()),
+		Geo:      geoResolver,
+		Regions:  regionsService,
+		Phones:   phoneVerifier,
+		OTP:      otpService,
+		Crypto:   encryptor,
+		Erasure:  erasureService,
+		Consent:  newConsentService(consent.NewMemoryStore(), locales, clk, loggers),
+		Metering: meter,
+		Blobs:    blobs,
+		Docs:     docs,
+		Views:    views,
+		Authz:    authz,
+		RefData:  refData,
+		Health:   newHealthMonitor(config.Health, db, redisClient, clk, loggers),
+		Jobs:     jobs.NewStore(redisClient, jobs.WithClock(clk), jobs.WithTTL(config.Jobs.TTL)),
+		closers: []func() error{
+			watchKeys(config.Encryption, encryptor, loggers),
+			func() error {
+				redisServer.Close()
+				return nil
+			},
+		},
+	}
+	container.Exports = newExportService(config.Exports, container.Jobs, blobs, container.Events, clk, loggers)
+	container.Sagas = newSagaOrchestrator(config.Sagas, saga.NewMemoryStore(), container.Events, clk, loggers)
+	container.ReadSide = newProjectionRunner(config.Projections, container, projection.NewMemoryCheckpoints(clk))
+	container.Retainer = newRetentionService(config.Retention, container, retention.NewMemoryStore())
+	container.Chaos = faults
+	container.Shedder = shedder
+	container.Sunsets = sunsets
+	container.Push, err = newPushService(config.Push, push.NewMemoryStore(), container.Redis, container.Views, clk, loggers)
+	if err != nil {
+		container.Close()
+		return nil, fmt.Errorf("failed to initialize push notifications: %w", err)
+	}
+	container.Notify, err = newNotifyRouter(config.Notify, notify.NewMemoryStore(), container.Consent, container.Push, locales, clk, loggers)
+	if err != nil {
+		container.Close()
+		return nil, fmt.Errorf("failed to initialize notifications: %w", err)
+	}
+	if closer, ok := geoResolver.(io.Closer); ok {
+		container.closers = append(container.closers, closer.Close)
+	}
+	registerErasers(container)
+
+	return container, nil
+}
+
+// openDevDatabase opens the in-memory SQLite database, injecting faults when
+// faults is not nil
+func openDevDatabase(faults *chaos.Injector) (*sql.DB, error) {
+	db, err := sql.Open("sqlite", devDatabaseDSN)
+	if err != nil || faults == nil {
+		return db, err
+	}
+	// sql.Open does not connect, so the pool is only needed for its driver
+	drv := db.Driver()
+	db.Close()
+	return sql.OpenDB(faults.Connector(chaos.DriverConnector(drv, devDatabaseDSN))), nil
+}
@@ -0,0 +1,20 @@
+//go:build !dev
+
+package bootstrap
+
+import (
+	"errors"
+
+	"golang-arch/internal/shared/config"
+)
+
+// DevModeAvailable reports whether this binary was built with the dev profile
+const DevModeAvailable = false
+
+// ErrDevModeUnavailable is returned by NewDevContainer in production builds
+var ErrDevModeUnavailable = errors.New("dev mode is not available in this build (rebuild with -tags dev)")
+
+// NewDevContainer is only implemented in binaries built with `-tags dev`
+func NewDevContainer(config *config.AppConfig) (*Container, error) {
+	return nil, ErrDevModeUnavailable
+}
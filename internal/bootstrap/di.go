@@ -1,75 +1,738 @@
 package bootstrap
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
+	"io"
 	"log"
+	"os"
+	"slices"
+	"strings"
+	"time"
 
+	"golang-arch/internal/shared/api"
+	"golang-arch/internal/shared/cache"
+	"golang-arch/internal/shared/chaos"
 	"golang-arch/internal/shared/config"
+	"golang-arch/internal/shared/consent"
+	"golang-arch/internal/shared/documents"
+	i18n "golang-arch/internal/shared/domain/internationalization"
+	"golang-arch/internal/shared/erasure"
+	"golang-arch/internal/shared/events"
+	"golang-arch/internal/shared/eventsource"
+	"golang-arch/internal/shared/export"
+	"golang-arch/internal/shared/fieldcrypt"
+	"golang-arch/internal/shared/geo"
+	"golang-arch/internal/shared/health"
+	"golang-arch/internal/shared/jobs"
+	"golang-arch/internal/shared/loadshed"
+	"golang-arch/internal/shared/metering"
+	"golang-arch/internal/shared/notify"
+	"golang-arch/internal/shared/otp"
+	"golang-arch/internal/shared/phoneverify"
+	"golang-arch/internal/shared/projection"
+	"golang-arch/internal/shared/push"
+	"golang-arch/internal/shared/rates"
+	"golang-arch/internal/shared/rbac"
+	"golang-arch/internal/shared/refdata"
+	"golang-arch/internal/shared/regions"
+	"golang-arch/internal/shared/repository"
+	"golang-arch/internal/shared/retention"
+	"golang-arch/internal/shared/saga"
+	"golang-arch/internal/shared/storage"
+	"golang-arch/internal/shared/templates"
+	"golang-arch/pkg/clock"
 	"golang-arch/pkg/logger"
+	"golang-arch/pkg/metrics"
+	"golang-arch/pkg/schedule"
 
+	"github.com/redis/go-redis/v9"
 	"go.uber.org/zap"
 )
 
 // Container holds all application dependencies
 type Container struct {
-	Config *config.AppConfig
-	DB     *sql.DB
-	Logger *zap.Logger
+	Config   *config.AppConfig
+	DB       *sql.DB
+	Redis    *redis.Client
+	Logger   *zap.Logger
+	Loggers  *logger.Factory
+	Metrics  *metrics.Provider
+	Clock    clock.Clock
+	I18n     *i18n.LocaleDefaults      // Fallback locale, timezone and currency, and the supported locales
+	Events   *events.Bus               // In-process domain event bus
+	Ledger   events.Ledger             // Messages processed by idempotent consumers
+	Broker   events.Publisher          // Delivers forwarded events outside the process, sealed by Schemas
+	Schemas  *events.Registry          // Versions of the events exchanged with other processes
+	Journal  eventsource.Store         // Events and snapshots of event-sourced aggregates
+	Audit    repository.AuditLog       // Audit trail of entity writes made through repository hooks
+	Rates    *rates.Service            // Current and historical exchange rates
+	Convert  i18n.CurrencyConverter    // Current rates for Money.ConvertTo; use Rates.Converter(ctx) within a request
+	Geo      geo.Resolver              // Client IP geolocation
+	Regions  *regions.Service          // ISO 3166-2 subdivision lookups
+	Phones   phoneverify.PhoneVerifier // Phone number reachability lookups
+	OTP      *otp.Service              // One-time verification codes
+	Crypto   *fieldcrypt.Encryptor     // Field-level encryption at rest; nil when disabled
+	Erasure  *erasure.Service          // Right-to-be-forgotten requests across repositories
+	Consent  *consent.Service          // Consent to data-processing purposes per contact channel
+	Metering *metering.Meter           // Per-consumer request quotas; nil when disabled
+	Blobs    storage.BlobStore         // Object storage for exports and generated documents
+	Docs     *documents.Service        // Invoice and report rendering to HTML and PDF
+	Views    *templates.Engine         // Server-side HTML pages and emails
+	Authz    *rbac.Authorizer          // Bearer-token principals and their role permissions
+	RefData  *refdata.Service          // Admin-managed currencies, rate overrides, translations and holidays
+	Health   *health.Monitor           // Database and Redis probes and their recorded history
+	Jobs     *jobs.Store               // State and results of asynchronous jobs
+	Exports  *export.Service           // Query results streamed to CSV, XLSX or JSON files as jobs
+	Notify   *notify.Router            // Picks each recipient's channel for notifications and delivers them
+	Push     *push.Service             // Registered devices and the push notification outbox
+	Sagas    *saga.Orchestrator        // Runs multi-step workflows with compensations; definitions register on it
+	ReadSide *projection.Runner        // Feeds stored events to read models; projections register on it
+	Retainer *retention.Service        // Archives and deletes expired rows; tables register policies on it
+	Chaos    *chaos.Injector           // Fault injection for resilience testing; nil when disabled
+	Shedder  *loadshed.Limiter         // Caps the requests handled at once; nil when disabled
+	Sunsets  *api.Deprecations         // Deprecated routes; services mark theirs with Route
 	// Add more dependencies as needed
 	// Services map[string]interface{}
+
+	// closers release resources that are not container fields, such as the
+	// embedded servers of the dev profile
+	closers []func() error
 }
 
 // NewContainer creates and initializes the dependency injection container
 func NewContainer(config *config.AppConfig) (*Container, error) {
-	// Initialize logger
-	logger, err := logger.NewLogger(config.Log.Level, config.Log.Format)
+	// Initialize logger factory for named per-module loggers
+	loggers, err := logger.NewFactory(config.Log.Level, config.Log.Format, config.Log.Levels)
 	if err != nil {
 		return nil, fmt.Errorf("failed to initialize logger: %w", err)
 	}
 
+	// Initialize metrics provider
+	metricsProvider, err := metrics.NewProvider(metrics.Options{
+		Exporter:       config.Metrics.Exporter,
+		OTLPEndpoint:   config.Metrics.OTLPEndpoint,
+		OTLPInsecure:   config.Metrics.OTLPInsecure,
+		ExportInterval: config.Metrics.ExportInterval,
+		Identity:       metricsIdentity(config.Metrics),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize metrics: %w", err)
+	}
+	configureMoneyJSON(config.I18n, metricsProvider.Instruments)
+	if err := configureConstraints(config.I18n); err != nil {
+		return nil, err
+	}
+	faults, err := newChaosInjector(config.Chaos, config.Metrics.Environment, metricsProvider.Instruments, loggers)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize chaos: %w", err)
+	}
+	shedder, err := newLoadShedder(config.LoadShed, metricsProvider.Instruments, loggers)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize load shedding: %w", err)
+	}
+	sunsets, err := newDeprecations(config.Deprecations, metricsProvider.Instruments)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize deprecations: %w", err)
+	}
+
 	// Initialize database connection
-	db, err := initDatabase(config.Database)
+	db, err := initDatabase(config.Database, config.Startup, faults)
 	if err != nil {
 		return nil, fmt.Errorf("failed to initialize database: %w", err)
 	}
 
+	// Initialize Redis connection
+	redisClient, err := initRedis(config.Redis, config.Startup, faults)
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to initialize redis: %w", err)
+	}
+
+	clk := clock.New()
+	authz, err := rbac.NewAuthorizer(config.RBAC)
+	if err != nil {
+		db.Close()
+		redisClient.Close()
+		return nil, fmt.Errorf("failed to initialize rbac: %w", err)
+	}
+
+	notifier := refdata.NewPostgresNotifier(db, postgresDSN(config.Database), config.RefData.Channel, loggers.Named(logger.NameRefData))
+	refData, err := newRefDataService(refdata.NewPostgresStore(db), notifier, clk, loggers)
+	if err != nil {
+		db.Close()
+		redisClient.Close()
+		notifier.Close()
+		return nil, fmt.Errorf("failed to initialize reference data: %w", err)
+	}
+
+	ratesService, err := newRatesService(config.Rates, db, redisClient, refData, clk, loggers)
+	if err != nil {
+		db.Close()
+		redisClient.Close()
+		notifier.Close()
+		return nil, fmt.Errorf("failed to initialize rates: %w", err)
+	}
+
+	geoResolver, err := geo.NewResolver(config.Geo)
+	if err != nil {
+		db.Close()
+		redisClient.Close()
+		notifier.Close()
+		return nil, fmt.Errorf("failed to initialize geoip: %w", err)
+	}
+
+	regionsService, err := newRegionsService(config.Regions, clk, loggers)
+	if err != nil {
+		db.Close()
+		redisClient.Close()
+		notifier.Close()
+		return nil, fmt.Errorf("failed to initialize regions: %w", err)
+	}
+
+	phoneVerifier, err := newPhoneVerifier(config.PhoneVerify, redisClient, clk, loggers)
+	if err != nil {
+		db.Close()
+		redisClient.Close()
+		notifier.Close()
+		return nil, fmt.Errorf("failed to initialize phone verification: %w", err)
+	}
+
+	encryptor, err := newEncryptor(config.Encryption)
+	if err != nil {
+		db.Close()
+		redisClient.Close()
+		notifier.Close()
+		return nil, fmt.Errorf("failed to initialize encryption: %w", err)
+	}
+
+	locales, err := newLocaleDefaults(config.I18n)
+	if err != nil {
+		db.Close()
+		redisClient.Close()
+		notifier.Close()
+		return nil, fmt.Errorf("failed to initialize i18n defaults: %w", err)
+	}
+
+	otpService, err := newOTPService(config.OTP, redisClient, phoneVerifier, encryptor, locales, clk, loggers)
+	if err != nil {
+		db.Close()
+		redisClient.Close()
+		notifier.Close()
+		return nil, fmt.Errorf("failed to initialize otp: %w", err)
+	}
+
+	erasureService, err := newErasureService(config.Erasure, erasure.NewPostgresAuditStore(db), clk, loggers)
+	if err != nil {
+		db.Close()
+		redisClient.Close()
+		notifier.Close()
+		return nil, fmt.Errorf("failed to initialize erasure: %w", err)
+	}
+
+	meter, err := newMeter(config.Metering, redisClient, metering.NewPostgresStore(db), clk, loggers)
+	if err != nil {
+		db.Close()
+		redisClient.Close()
+		notifier.Close()
+		return nil, fmt.Errorf("failed to initialize metering: %w", err)
+	}
+
+	blobs, err := storage.NewBlobStore(config.Storage, clk)
+	if err != nil {
+		db.Close()
+		redisClient.Close()
+		notifier.Close()
+		return nil, fmt.Errorf("failed to initialize storage: %w", err)
+	}
+
+	docs, err := newDocumentService(config.Documents, blobs)
+	if err != nil {
+		db.Close()
+		redisClient.Close()
+		notifier.Close()
+		return nil, fmt.Errorf("failed to initialize documents: %w", err)
+	}
+
+	views, err := newViews(config, locales, refData, loggers)
+	if err != nil {
+		db.Close()
+		redisClient.Close()
+		notifier.Close()
+		return nil, fmt.Errorf("failed to initialize templates: %w", err)
+	}
+
+	schemas := events.NewRegistry()
 	container := &Container{
-		Config: config,
-		DB:     db,
-		Logger: logger,
+		Config:   config,
+		DB:       db,
+		Redis:    redisClient,
+		Logger:   loggers.Root(),
+		Loggers:  loggers,
+		Metrics:  metricsProvider,
+		Clock:    clk,
+		I18n:     locales,
+		Events:   events.NewBus(loggers.Named(logger.NameEvents)),
+		Ledger:   events.NewPostgresLedger(db),
+		Broker:   schemas.Publisher(events.NewRedisBroker(redisClient, eventChannelPrefix)),
+		Schemas:  schemas,
+		Journal:  eventsource.NewPostgresStore(db),
+		Audit:    repository.NewPostgresAuditLog(),
+		Rates:    ratesService,
+		Convert:  ratesService.Converter(context.Background()),
+		Geo:      geoResolver,
+		Regions:  regionsService,
+		Phones:   phoneVerifier,
+		OTP:      otpService,
+		Crypto:   encryptor,
+		Erasure:  erasureService,
+		Consent:  newConsentService(consent.NewPostgresStore(db), locales, clk, loggers),
+		Metering: meter,
+		Blobs:    blobs,
+		Docs:     docs,
+		Views:    views,
+		Authz:    authz,
+		RefData:  refData,
+		Health:   newHealthMonitor(config.Health, db, redisClient, clk, loggers),
+		Jobs:     jobs.NewStore(redisClient, jobs.WithClock(clk), jobs.WithTTL(config.Jobs.TTL)),
+		closers:  []func() error{notifier.Close, watchKeys(config.Encryption, encryptor, loggers)},
 	}
+	container.Exports = newExportService(config.Exports, container.Jobs, blobs, container.Events, clk, loggers)
+	container.Sagas = newSagaOrchestrator(config.Sagas, saga.NewPostgresStore(db), container.Events, clk, loggers)
+	container.ReadSide = newProjectionRunner(config.Projections, container, projection.NewPostgresCheckpoints(db))
+	container.Retainer = newRetentionService(config.Retention, container, retention.NewPostgresStore(db))
+	container.Chaos = faults
+	container.Shedder = shedder
+	container.Sunsets = sunsets
+	container.Push, err = newPushService(config.Push, push.NewPostgresStore(db, push.WithEncryption(encryptor)), container.Redis, container.Views, clk, loggers)
+	if err != nil {
+		container.Close()
+		return nil, fmt.Errorf("failed to initialize push notifications: %w", err)
+	}
+	container.Notify, err = newNotifyRouter(config.Notify, notify.NewPostgresStore(db, notify.WithEncryption(encryptor)), container.Consent, container.Push, locales, clk, loggers)
+	if err != nil {
+		container.Close()
+		return nil, fmt.Errorf("failed to initialize notifications: %w", err)
+	}
+	if closer, ok := geoResolver.(io.Closer); ok {
+		container.closers = append(container.closers, closer.Close)
+	}
+	registerErasers(container)
 
 	return container, nil
 }
 
-// initDatabase initializes the database connection
-func initDatabase(dbConfig config.DatabaseConfig) (*sql.DB, error) {
-	dsn := fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
-		dbConfig.Host, dbConfig.Port, dbConfig.User, dbConfig.Password, dbConfig.Name, dbConfig.SSLMode)
-
-	db, err := sql.Open("postgres", dsn)
+// initDatabase initializes the database connection, waiting for the
+// database to come up as configured in startupConfig
+func initDatabase(dbConfig config.DatabaseConfig, startupConfig config.StartupConfig, faults *chaos.Injector) (*sql.DB, error) {
+	db, err := openPostgres(dbConfig, faults)
 	if err != nil {
 		return nil, fmt.Errorf("failed to open database connection: %w", err)
 	}
 
 	// Test the connection
-	if err := db.Ping(); err != nil {
+	reachable, err := waitOrDegrade("database", db.PingContext, startupConfig)
+	if err != nil {
+		db.Close()
 		return nil, fmt.Errorf("failed to ping database: %w", err)
 	}
+	if !reachable {
+		return db, nil
+	}
 
 	log.Printf("Successfully connected to database: %s:%d/%s", dbConfig.Host, dbConfig.Port, dbConfig.Name)
 	return db, nil
 }
 
+// initRedis initializes the Redis client, waiting for Redis to come up as
+// configured in startupConfig
+func initRedis(redisConfig config.RedisConfig, startupConfig config.StartupConfig, faults *chaos.Injector) (*redis.Client, error) {
+	client := redis.NewClient(&redis.Options{
+		Addr:     fmt.Sprintf("%s:%d", redisConfig.Host, redisConfig.Port),
+		Password: redisConfig.Password,
+		DB:       redisConfig.DB,
+	})
+	faults.Instrument(client)
+
+	// Test the connection
+	ping := func(ctx context.Context) error { return client.Ping(ctx).Err() }
+	reachable, err := waitOrDegrade("redis", ping, startupConfig)
+	if err != nil {
+		client.Close()
+		return nil, fmt.Errorf("failed to ping redis: %w", err)
+	}
+	if !reachable {
+		return client, nil
+	}
+
+	log.Printf("Successfully connected to redis: %s:%d/%d", redisConfig.Host, redisConfig.Port, redisConfig.DB)
+	return client, nil
+}
+
+// newRatesService builds the exchange-rate service from configuration. The
+// refresh schedule is parsed here so a bad expression fails at startup.
+func newRatesService(cfg config.RatesConfig, db *sql.DB, redisClient *redis.Client, overrides rates.OverrideSource, clk clock.Clock, loggers *logger.Factory) (*rates.Service, error) {
+	if cfg.RefreshSchedule != "" {
+		if _, err := schedule.Parse(cfg.RefreshSchedule); err != nil {
+			return nil, err
+		}
+	}
+
+	provider, err := rates.NewProvider(cfg, clk)
+	if err != nil {
+		return nil, err
+	}
+
+	return rates.NewService(provider,
+		rates.NewPostgresStore(db),
+		cache.NewRedisCache(redisClient, cache.WithPrefix(ratesCachePrefix)),
+		rates.WithClock(clk),
+		rates.WithLogger(loggers.Named(logger.NameRates)),
+		rates.WithCacheTTL(cfg.CacheTTL),
+		rates.WithMaxAge(cfg.MaxAge),
+		rates.WithOverrides(overrides),
+	), nil
+}
+
+// newRegionsService builds the region metadata service with the configured
+// remote provider, if any
+func newRegionsService(cfg config.RegionsConfig, clk clock.Clock, loggers *logger.Factory) (*regions.Service, error) {
+	provider, err := regions.NewProvider(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return regions.NewService(provider,
+		regions.WithClock(clk),
+		regions.WithLogger(loggers.Named(logger.NameRegions)),
+		regions.WithCacheTTL(cfg.CacheTTL),
+		regions.WithMinInterval(cfg.MinInterval),
+	), nil
+}
+
+// newPhoneVerifier builds the configured phone verifier; results of remote
+// providers are cached in Redis
+func newPhoneVerifier(cfg config.PhoneVerifyConfig, redisClient *redis.Client, clk clock.Clock, loggers *logger.Factory) (phoneverify.PhoneVerifier, error) {
+	verifier, err := phoneverify.NewVerifier(cfg, clk)
+	if err != nil {
+		return nil, err
+	}
+	if _, static := verifier.(*phoneverify.StaticVerifier); static {
+		return verifier, nil
+	}
+
+	return phoneverify.NewCachingVerifier(verifier,
+		cache.NewRedisCache(redisClient, cache.WithPrefix(phoneVerifyCachePrefix)),
+		phoneverify.WithLogger(loggers.Named(logger.NamePhoneVerify)),
+		phoneverify.WithSecret(cfg.Secret),
+		phoneverify.WithTTL(cfg.CacheTTL),
+		phoneverify.WithRetryTTL(cfg.RetryTTL),
+	), nil
+}
+
+// newEncryptor loads the field encryption keys; it returns nil when
+// encryption is disabled
+func newEncryptor(cfg config.EncryptionConfig) (*fieldcrypt.Encryptor, error) {
+	provider, err := fieldcrypt.NewKeyProvider(cfg)
+	if err != nil || provider == nil {
+		return nil, err
+	}
+	return fieldcrypt.NewEncryptor(context.Background(), provider)
+}
+
+// watchKeys reloads the encryption keys every cfg.ReloadInterval so
+// rotated keys are used without a restart. It returns the closer that stops
+// watching; it is a no-op when encryption is disabled or reloading is off.
+func watchKeys(cfg config.EncryptionConfig, encryptor *fieldcrypt.Encryptor, loggers *logger.Factory) func() error {
+	if encryptor == nil || cfg.ReloadInterval <= 0 {
+		return func() error { return nil }
+	}
+	cryptLogger := loggers.Named(logger.NameFieldCrypt)
+	ctx, cancel := context.WithCancel(context.Background())
+	go encryptor.Watch(ctx, cfg.ReloadInterval, func(err error) {
+		cryptLogger.Warn("Failed to reload encryption keys, keeping the current ones", zap.Error(err))
+	})
+	return func() error {
+		cancel()
+		return nil
+	}
+}
+
+// newErasureService builds the erasure service; repositories register
+// their erasers on it
+func newErasureService(cfg config.ErasureConfig, audit erasure.AuditStore, clk clock.Clock, loggers *logger.Factory) (*erasure.Service, error) {
+	strategy, err := erasure.ParseStrategy(cfg.Strategy)
+	if err != nil {
+		return nil, err
+	}
+	return erasure.NewService(audit,
+		erasure.WithClock(clk),
+		erasure.WithLogger(loggers.Named(logger.NameErasure)),
+		erasure.WithSecret(cfg.Secret),
+		erasure.WithStrategy(strategy),
+	), nil
+}
+
+// registerErasers registers the erasers of the container's stores that
+// hold subject data, so an erasure request reaches all of them
+func registerErasers(c *Container) {
+	c.Erasure.Register("consents", erasure.EraserFunc(c.Consent.Erase))
+	c.Erasure.Register("notification_preferences", erasure.EraserFunc(c.Notify.Erase))
+	c.Erasure.Register("push_devices", erasure.EraserFunc(c.Push.Erase))
+	c.Erasure.Register("otp", erasure.EraserFunc(c.OTP.Erase))
+	c.Erasure.Register("audit_log", erasure.EraserFunc(func(ctx context.Context, subject erasure.Subject, anonymizer *erasure.Anonymizer) (int, error) {
+		return c.Audit.Erase(ctx, c.DB, subject, anonymizer)
+	}))
+	// Only remote providers cache their results
+	if cached, ok := c.Phones.(*phoneverify.CachingVerifier); ok {
+		c.Erasure.Register("phone_verifications", erasure.EraserFunc(cached.Erase))
+	}
+}
+
+// newConsentService builds the consent service on store
+func newConsentService(store consent.Store, locales *i18n.LocaleDefaults, clk clock.Clock, loggers *logger.Factory) *consent.Service {
+	return consent.NewService(store,
+		consent.WithLocaleDefaults(*locales),
+		consent.WithClock(clk),
+		consent.WithLogger(loggers.Named(logger.NameConsent)),
+	)
+}
+
+// newRefDataService builds the reference data service on store; changes are
+// announced to the other instances through notifier. The enabled currencies
+// are registered right away; when the store cannot be read they follow with
+// the first change or listener reconnect.
+func newRefDataService(store refdata.Store, notifier refdata.Notifier, clk clock.Clock, loggers *logger.Factory) (*refdata.Service, error) {
+	service, err := refdata.NewService(store, notifier,
+		refdata.WithClock(clk),
+		refdata.WithLogger(loggers.Named(logger.NameRefData)),
+		refdata.WithCurrencyRegistry(i18n.Currencies()),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), pingTimeout)
+	defer cancel()
+	if err := service.SyncCurrencies(ctx); err != nil {
+		loggers.Named(logger.NameRefData).Warn("Starting without the stored currencies", zap.Error(err))
+	}
+	return service, nil
+}
+
+// newMeter builds the usage meter with the configured plans; it returns nil
+// when metering is disabled. The flush schedule is parsed here so a bad
+// expression fails at startup.
+func newMeter(cfg config.MeteringConfig, redisClient *redis.Client, store metering.Store, clk clock.Clock, loggers *logger.Factory) (*metering.Meter, error) {
+	if !cfg.Enabled {
+		return nil, nil
+	}
+	if cfg.FlushSchedule != "" {
+		if _, err := schedule.Parse(cfg.FlushSchedule); err != nil {
+			return nil, err
+		}
+	}
+	plans, err := metering.NewStaticPlans(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return metering.NewMeter(redisClient, plans, store,
+		metering.WithClock(clk),
+		metering.WithLogger(loggers.Named(logger.NameMetering)),
+	), nil
+}
+
+// newOTPService builds the verification code service; SMS recipients are
+// checked with the phone verifier before codes are sent, and queued
+// deliveries are sealed with encryptor when encryption is enabled
+func newOTPService(cfg config.OTPConfig, redisClient *redis.Client, phones phoneverify.PhoneVerifier, encryptor *fieldcrypt.Encryptor, locales *i18n.LocaleDefaults, clk clock.Clock, loggers *logger.Factory) (*otp.Service, error) {
+	if cfg.DeliverySchedule != "" {
+		if _, err := schedule.Parse(cfg.DeliverySchedule); err != nil {
+			return nil, err
+		}
+	}
+	otpLogger := loggers.Named(logger.NameOTP)
+	senders, err := otp.NewSenders(cfg.Sender, otpLogger)
+	if err != nil {
+		return nil, err
+	}
+
+	return otp.NewService(redisClient, senders,
+		otp.WithClock(clk),
+		otp.WithLogger(otpLogger),
+		otp.WithSecret(cfg.Secret),
+		otp.WithCodeLength(cfg.CodeLength),
+		otp.WithTTL(cfg.TTL),
+		otp.WithMaxAttempts(cfg.MaxAttempts),
+		otp.WithCooldown(cfg.Cooldown),
+		otp.WithSendLimit(cfg.SendLimit, cfg.SendWindow),
+		otp.WithPhoneVerifier(phones),
+		otp.WithEncryption(encryptor),
+		otp.WithLocaleDefaults(*locales),
+	), nil
+}
+
+// newHealthMonitor builds the probes of the database and Redis behind
+// /ready and /status
+func newHealthMonitor(cfg config.HealthConfig, db *sql.DB, redisClient *redis.Client, clk clock.Clock, loggers *logger.Factory) *health.Monitor {
+	checks := []health.Check{
+		{Name: "database", Probe: db.PingContext},
+		{Name: "redis", Probe: func(ctx context.Context) error { return redisClient.Ping(ctx).Err() }},
+	}
+	return health.NewMonitor(redisClient, checks,
+		health.WithClock(clk),
+		health.WithLogger(loggers.Named(logger.NameHealth)),
+		health.WithTimeout(cfg.Timeout),
+		health.WithHistorySize(cfg.HistorySize),
+	)
+}
+
+// newExportService builds the export runner; outcomes are announced on bus
+func newExportService(cfg config.ExportsConfig, store *jobs.Store, blobs storage.BlobStore, bus *events.Bus, clk clock.Clock, loggers *logger.Factory) *export.Service {
+	return export.NewService(store, blobs,
+		export.WithClock(clk),
+		export.WithLogger(loggers.Named(logger.NameExport)),
+		export.WithEvents(bus),
+		export.WithConcurrency(cfg.Concurrency),
+		export.WithURLTTL(cfg.URLTTL),
+		export.WithProgressInterval(cfg.ProgressInterval),
+	)
+}
+
+// newSagaOrchestrator builds the saga orchestrator; its outcomes are
+// published on bus
+func newSagaOrchestrator(cfg config.SagaConfig, store saga.Store, bus *events.Bus, clk clock.Clock, loggers *logger.Factory) *saga.Orchestrator {
+	return saga.NewOrchestrator(store,
+		saga.WithClock(clk),
+		saga.WithLogger(loggers.Named(logger.NameSaga)),
+		saga.WithEvents(bus),
+		saga.WithMaxAttempts(cfg.MaxAttempts),
+		saga.WithRetryBackoff(cfg.RetryBackoff),
+		saga.WithLease(cfg.Lease),
+	)
+}
+
+// newProjectionRunner builds the projection runner over the container's
+// journal
+func newProjectionRunner(cfg config.ProjectionsConfig, c *Container, checkpoints projection.Checkpoints) *projection.Runner {
+	return projection.NewRunner(c.Journal, c.Schemas, checkpoints,
+		projection.WithLogger(c.Loggers.Named(logger.NameProjection)),
+		projection.WithLagGauge(c.Metrics.Instruments.ProjectionLag),
+		projection.WithBatchSize(cfg.BatchSize),
+	)
+}
+
+// newRetentionService builds the retention service with the built-in
+// policies; archives go to the container's blob store
+func newRetentionService(cfg config.RetentionConfig, c *Container, store retention.Store) *retention.Service {
+	service := retention.NewService(store, c.Blobs,
+		retention.WithClock(c.Clock),
+		retention.WithLogger(c.Loggers.Named(logger.NameRetention)),
+		retention.WithRowsCounter(c.Metrics.Instruments.RetentionRows),
+		retention.WithBatchSize(cfg.BatchSize),
+		retention.WithPrefix(cfg.ArchivePrefix),
+		retention.WithMaxAges(cfg.Policies),
+	)
+	service.Register(retention.Policy{Name: "audit_log", Table: "audit_log", TimeColumn: "changed_at", MaxAge: 180 * 24 * time.Hour, Archive: true})
+	service.Register(retention.Policy{Name: "event_outbox", Table: "event_outbox", TimeColumn: "published_at", MaxAge: 30 * 24 * time.Hour})
+	return service
+}
+
+// newPushService builds the push service with the configured providers
+func newPushService(cfg config.PushConfig, devices push.DeviceStore, redisClient *redis.Client, views *templates.Engine, clk clock.Clock, loggers *logger.Factory) (*push.Service, error) {
+	pushLogger := loggers.Named(logger.NamePush)
+	senders, err := push.NewSenders(cfg, clk, pushLogger)
+	if err != nil {
+		return nil, err
+	}
+	return push.NewService(devices, redisClient, senders,
+		push.WithClock(clk),
+		push.WithLogger(pushLogger),
+		push.WithViews(views),
+		push.WithMaxAttempts(cfg.MaxAttempts),
+		push.WithRetryBackoff(cfg.RetryBackoff),
+	), nil
+}
+
+// newNotifyRouter builds the notification router. No email or SMS provider
+// is integrated yet, so those channels log their messages.
+func newNotifyRouter(cfg config.NotifyConfig, store notify.Store, consents *consent.Service, pushSender notify.Sender, locales *i18n.LocaleDefaults, clk clock.Clock, loggers *logger.Factory) (*notify.Router, error) {
+	channels, err := notify.ParseChannels(cfg.DefaultChannels)
+	if err != nil {
+		return nil, err
+	}
+	notifyLogger := loggers.Named(logger.NameNotify)
+	logSender := notify.NewLogSender(notifyLogger)
+	return notify.NewRouter(store,
+		notify.WithClock(clk),
+		notify.WithLogger(notifyLogger),
+		notify.WithConsent(consents),
+		notify.WithDefaultChannels(channels...),
+		notify.WithLocaleDefaults(*locales),
+		notify.WithSender(notify.ChannelEmail, logSender),
+		notify.WithSender(notify.ChannelSMS, logSender),
+		notify.WithSender(notify.ChannelPush, pushSender),
+		notify.WithSender(notify.ChannelWebhook, notify.NewWebhookSender(cfg.WebhookSecret, cfg.WebhookTimeout, clk)),
+	), nil
+}
+
+// phoneVerifyCachePrefix namespaces the phone verification results in Redis
+const phoneVerifyCachePrefix = "phoneverify:"
+
+// ratesCachePrefix namespaces the exchange-rate keys in Redis
+const ratesCachePrefix = "rates:"
+
+// eventChannelPrefix prefixes the Redis pub/sub channels events are forwarded to
+const eventChannelPrefix = "events."
+
 // Close gracefully closes all container resources
 func (c *Container) Close() error {
+	// Stop running exports first, their outcomes are published as events
+	if c.Exports != nil {
+		if err := c.Exports.Close(); err != nil {
+			return fmt.Errorf("failed to close exports: %w", err)
+		}
+	}
+
+	// Drop notifications waiting for quiet hours to end
+	if c.Notify != nil {
+		if err := c.Notify.Close(); err != nil {
+			return fmt.Errorf("failed to close notifications: %w", err)
+		}
+	}
+
+	// Drain asynchronous event handlers while their dependencies are still open
+	if c.Events != nil {
+		if err := c.Events.Close(); err != nil {
+			return fmt.Errorf("failed to close event bus: %w", err)
+		}
+	}
+
 	if c.DB != nil {
 		if err := c.DB.Close(); err != nil {
 			return fmt.Errorf("failed to close database connection: %w", err)
 		}
 	}
 
+	if c.Redis != nil {
+		if err := c.Redis.Close(); err != nil {
+			return fmt.Errorf("failed to close redis connection: %w", err)
+		}
+	}
+
+	if c.Metrics != nil {
+		if err := c.Metrics.Shutdown(context.Background()); err != nil {
+			return fmt.Errorf("failed to shutdown metrics: %w", err)
+		}
+	}
+
+	for _, closer := range c.closers {
+		if err := closer(); err != nil {
+			return err
+		}
+	}
+
 	if c.Logger != nil {
 		if err := c.Logger.Sync(); err != nil {
 			return fmt.Errorf("failed to sync logger: %w", err)
@@ -78,3 +741,192 @@ func (c *Container) Close() error {
 
 	return nil
 }
+
+// newDocumentService builds the document renderer with the configured PDF
+// converter, storing generated documents in blobs
+func newDocumentService(cfg config.DocumentsConfig, blobs storage.BlobStore) (*documents.Service, error) {
+	converter, err := documents.NewConverter(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return documents.NewService(converter, blobs)
+}
+
+// chaosEnvironments are the metrics.environment values faults may be
+// injected in. Any other, production or a name not listed, is refused.
+var chaosEnvironments = []string{"dev", "development", "local", "test", "staging"}
+
+// newChaosInjector builds the fault injector of chaos, or returns nil when
+// it is disabled. It refuses to inject faults outside chaosEnvironments.
+func newChaosInjector(cfg config.ChaosConfig, environment string, instruments *metrics.Instruments, loggers *logger.Factory) (*chaos.Injector, error) {
+	if !cfg.Enabled {
+		return nil, nil
+	}
+	if !slices.Contains(chaosEnvironments, strings.ToLower(environment)) {
+		return nil, fmt.Errorf("chaos.enabled is only allowed when metrics.environment is one of %s, not %q",
+			strings.Join(chaosEnvironments, ", "), environment)
+	}
+	chaosLogger := loggers.Named(logger.NameChaos)
+	injector, err := chaos.NewInjector(map[string]chaos.Fault{
+		chaos.TargetHTTP:     chaos.Fault(cfg.HTTP),
+		chaos.TargetDatabase: chaos.Fault(cfg.Database),
+		chaos.TargetRedis:    chaos.Fault(cfg.Redis),
+	}, chaos.WithFaultsCounter(instruments.ChaosFaults), chaos.WithLogger(chaosLogger))
+	if err != nil {
+		return nil, err
+	}
+	chaosLogger.Warn("Injecting faults for resilience testing", zap.String("environment", environment),
+		zap.Any("http", cfg.HTTP), zap.Any("database", cfg.Database), zap.Any("redis", cfg.Redis))
+	return injector, nil
+}
+
+// newLoadShedder builds the request limiter of load_shedding, or returns nil
+// when it is disabled
+func newLoadShedder(cfg config.LoadShedConfig, instruments *metrics.Instruments, loggers *logger.Factory) (*loadshed.Limiter, error) {
+	if !cfg.Enabled {
+		return nil, nil
+	}
+	return loadshed.NewLimiter(loadshed.Options{
+		MaxInFlight:   cfg.MaxInFlight,
+		MinInFlight:   cfg.MinInFlight,
+		MaxQueue:      cfg.MaxQueue,
+		QueueTimeout:  cfg.QueueTimeout,
+		TargetLatency: cfg.TargetLatency,
+	},
+		loadshed.WithMetrics(instruments.HTTPShed, instruments.HTTPInFlight, instruments.HTTPQueued, instruments.HTTPConcurrency),
+		loadshed.WithLogger(loggers.Named(logger.NameLoadShed)))
+}
+
+// newDeprecations marks the routes deprecated in the configuration
+func newDeprecations(cfg []config.DeprecationConfig, instruments *metrics.Instruments) (*api.Deprecations, error) {
+	deprecations := api.NewDeprecations(api.WithUsageCounter(instruments.HTTPDeprecated))
+	for _, route := range cfg {
+		var deprecation api.Deprecation
+		var err error
+		if deprecation.Since, err = time.Parse(time.DateOnly, route.Since); err != nil {
+			return nil, fmt.Errorf("invalid deprecation date of %s %s: %w", route.Method, route.Path, err)
+		}
+		if route.Sunset != "" {
+			if deprecation.Sunset, err = time.Parse(time.DateOnly, route.Sunset); err != nil {
+				return nil, fmt.Errorf("invalid sunset of %s %s: %w", route.Method, route.Path, err)
+			}
+		}
+		deprecation.Link, deprecation.Message = route.Link, route.Message
+		if err := deprecations.Mark(strings.ToUpper(route.Method), route.Path, deprecation); err != nil {
+			return nil, err
+		}
+	}
+	return deprecations, nil
+}
+
+// metricsIdentity labels every series with the configured service and
+// environment and the release, so the server and the worker share dashboards
+func metricsIdentity(cfg config.MetricsConfig) metrics.Identity {
+	version := cfg.Version
+	if version == "" {
+		version = metrics.BuildVersion()
+	}
+	return metrics.Identity{Service: cfg.Service, Env: cfg.Environment, Version: version}
+}
+
+// configureMoneyJSON applies i18n.strict_money_json and counts the Money
+// payloads still decoded through a lenient legacy path
+func configureMoneyJSON(cfg config.I18nConfig, instruments *metrics.Instruments) {
+	i18n.SetStrictMoneyJSON(cfg.StrictMoneyJSON)
+	i18n.SetLegacyMoneyJSONHook(func(reason string) {
+		instruments.MoneyJSONLegacy.Inc(metrics.Labels{"reason": reason})
+	})
+}
+
+// configureConstraints makes the i18n constructors enforce the configured
+// value object constraints
+func configureConstraints(cfg config.I18nConfig) error {
+	constraints, err := newConstraints(cfg)
+	if err != nil {
+		return err
+	}
+	i18n.SetConstraints(constraints)
+	return nil
+}
+
+// newConstraints builds the value object constraints of
+// i18n.non_negative_money and i18n.allowed_currencies
+func newConstraints(cfg config.I18nConfig) (i18n.Constraints, error) {
+	var constraints i18n.Constraints
+	if cfg.NonNegativeMoney {
+		constraints.Money = append(constraints.Money, i18n.NonNegativeAmount())
+	}
+	if len(cfg.AllowedCurrencies) > 0 {
+		allowed, err := i18n.AllowedCurrencies(cfg.AllowedCurrencies...)
+		if err != nil {
+			return i18n.Constraints{}, fmt.Errorf("invalid i18n.allowed_currencies: %w", err)
+		}
+		constraints.Money = append(constraints.Money, allowed)
+	}
+	return constraints, nil
+}
+
+// newLocaleDefaults builds the application's locale defaults, and those by
+// locale and tenant, from the i18n section
+func newLocaleDefaults(cfg config.I18nConfig) (*i18n.LocaleDefaults, error) {
+	defaults, err := i18n.NewLocaleDefaults(cfg.DefaultLocale, cfg.DefaultTimezone, cfg.DefaultCurrency, cfg.SupportedLocales)
+	if err != nil {
+		return nil, err
+	}
+	for _, locale := range cfg.Locales {
+		if err := defaults.AddLocale(locale.Locale, i18n.PartialLocalePreferences{
+			Timezone: locale.Timezone, Currency: locale.Currency,
+		}); err != nil {
+			return nil, err
+		}
+	}
+	for _, tenant := range cfg.Tenants {
+		if err := defaults.AddTenant(tenant.ID, i18n.PartialLocalePreferences{
+			Locale: tenant.Locale, Timezone: tenant.Timezone, Currency: tenant.Currency,
+		}); err != nil {
+			return nil, err
+		}
+	}
+	return defaults, nil
+}
+
+// newViews builds the template engine from the built-in templates and those
+// under templates.dir; readers nothing is known about get the locale
+// defaults, and every reader a supported locale. The translations managed
+// through refData, when set, replace the file labels and are reloaded when
+// they change.
+func newViews(cfg *config.AppConfig, locales *i18n.LocaleDefaults, refData *refdata.Service, loggers *logger.Factory) (*templates.Engine, error) {
+	options := []templates.Option{templates.WithLocaleDefaults(*locales)}
+	if cfg.Templates.Dir != "" {
+		options = append(options, templates.WithFS(os.DirFS(cfg.Templates.Dir)))
+	}
+	if refData == nil {
+		return templates.NewEngine(options...)
+	}
+
+	refLogger := loggers.Named(logger.NameRefData)
+	ctx, cancel := context.WithTimeout(context.Background(), pingTimeout)
+	defer cancel()
+	if catalog, err := refData.Catalog(ctx); err != nil {
+		refLogger.Warn("Starting without the stored translations", zap.Error(err))
+	} else {
+		options = append(options, templates.WithCatalog(catalog))
+	}
+
+	views, err := templates.NewEngine(options...)
+	if err != nil {
+		return nil, err
+	}
+	refData.OnChange(func(change refdata.Change) {
+		if change.Kind != refdata.KindTranslations && change.Kind != "" {
+			return
+		}
+		catalog, err := refData.Catalog(context.Background())
+		if err != nil {
+			refLogger.Error("Failed to reload the stored translations", zap.Error(err))
+			return
+		}
+		views.UseCatalog(catalog)
+	})
+	return views, nil
+}
@@ -0,0 +1,414 @@
+package bootstrap
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"time"
+
+	"golang-arch/internal/shared/chaos"
+	"golang-arch/internal/shared/config"
+	"golang-arch/internal/shared/database"
+	"golang-arch/internal/shared/documents"
+	i18n "golang-arch/internal/shared/domain/internationalization"
+	"golang-arch/internal/shared/erasure"
+	"golang-arch/internal/shared/fieldcrypt"
+	"golang-arch/internal/shared/metering"
+	"golang-arch/internal/shared/notify"
+	"golang-arch/internal/shared/otp"
+	"golang-arch/internal/shared/phoneverify"
+	"golang-arch/internal/shared/push"
+	"golang-arch/internal/shared/rates"
+	"golang-arch/internal/shared/rbac"
+	"golang-arch/internal/shared/regions"
+	"golang-arch/internal/shared/storage"
+	"golang-arch/pkg/clock"
+	"golang-arch/pkg/logger"
+	"golang-arch/pkg/schedule"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lib/pq"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+// CheckStatus is the outcome of a doctor check
+type CheckStatus int
+
+const (
+	CheckOK CheckStatus = iota
+	CheckWarn
+	CheckFail
+	CheckSkipped
+)
+
+// String returns the label printed in the report
+func (s CheckStatus) String() string {
+	switch s {
+	case CheckOK:
+		return "OK"
+	case CheckWarn:
+		return "WARN"
+	case CheckFail:
+		return "FAIL"
+	}
+	return "SKIP"
+}
+
+// CheckResult is one line of the doctor report
+type CheckResult struct {
+	Name     string
+	Status   CheckStatus
+	Detail   string
+	Problems []string // Individual findings, printed below the line
+	Duration time.Duration
+}
+
+// DoctorOptions configures RunDoctor
+type DoctorOptions struct {
+	MigrationsDir string        // Defaults to src/migrations
+	Timeout       time.Duration // Per connection check; defaults to 5s
+}
+
+// doctorTimezones must load for LocalizedDateTime to work; the configured
+// default timezone is checked as well
+var doctorTimezones = []string{"UTC", "America/New_York", "Europe/London", "Asia/Jakarta", "Australia/Sydney"}
+
+// RunDoctor checks that cfg is usable and that the database, Redis,
+// migrations and timezone database are ready, without starting anything.
+// Migrations are skipped when the database is unreachable.
+func RunDoctor(ctx context.Context, cfg *config.AppConfig, opts DoctorOptions) []CheckResult {
+	if opts.MigrationsDir == "" {
+		opts.MigrationsDir = filepath.Join("src", "migrations")
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = 5 * time.Second
+	}
+
+	results := []CheckResult{CheckConfig(cfg)}
+
+	db, err := openPostgres(cfg.Database, nil)
+	if err != nil {
+		results = append(results,
+			CheckResult{Name: "database", Status: CheckFail, Detail: err.Error()},
+			CheckResult{Name: "migrations", Status: CheckSkipped, Detail: "database unreachable"})
+	} else {
+		defer db.Close()
+		dbCtx, cancel := context.WithTimeout(ctx, opts.Timeout)
+		database := CheckDatabase(dbCtx, db)
+		database.Detail = fmt.Sprintf("%s:%d/%s %s", cfg.Database.Host, cfg.Database.Port, cfg.Database.Name, database.Detail)
+		results = append(results, database)
+		if database.Status == CheckFail {
+			results = append(results, CheckResult{Name: "migrations", Status: CheckSkipped, Detail: "database unreachable"})
+		} else {
+			results = append(results, CheckMigrations(dbCtx, db, opts.MigrationsDir))
+		}
+		cancel()
+	}
+
+	client := redis.NewClient(&redis.Options{
+		Addr:     fmt.Sprintf("%s:%d", cfg.Redis.Host, cfg.Redis.Port),
+		Password: cfg.Redis.Password,
+		DB:       cfg.Redis.DB,
+	})
+	defer client.Close()
+	redisCtx, cancel := context.WithTimeout(ctx, opts.Timeout)
+	defer cancel()
+	results = append(results, CheckRedis(redisCtx, client))
+
+	return append(results, CheckTimezones(append(doctorTimezones, cfg.I18n.DefaultTimezone, cfg.Geo.DefaultTimezone)...))
+}
+
+// CheckConfig builds every configured component that can be built without
+// connecting anywhere, so invalid values fail here instead of at startup.
+// Insecure but working settings are warnings.
+func CheckConfig(cfg *config.AppConfig) CheckResult {
+	started := time.Now()
+	result := CheckResult{Name: "config"}
+	fail := func(section string, err error) {
+		if err != nil {
+			result.Status = CheckFail
+			result.Problems = append(result.Problems, fmt.Sprintf("%s: %v", section, err))
+		}
+	}
+	warn := func(message string) {
+		result.Status = max(result.Status, CheckWarn)
+		result.Problems = append(result.Problems, message)
+	}
+
+	_, err := logger.NewFactory(cfg.Log.Level, cfg.Log.Format, cfg.Log.Levels)
+	fail("log", err)
+	for section, expr := range map[string]string{
+		"rates.refresh_schedule":  cfg.Rates.RefreshSchedule,
+		"otp.delivery_schedule":   cfg.OTP.DeliverySchedule,
+		"push.delivery_schedule":  cfg.Push.DeliverySchedule,
+		"sagas.resume_schedule":   cfg.Sagas.ResumeSchedule,
+		"events.prune_schedule":   cfg.Events.PruneSchedule,
+		"projections.schedule":    cfg.Projections.Schedule,
+		"retention.schedule":      cfg.Retention.Schedule,
+		"metering.flush_schedule": cfg.Metering.FlushSchedule,
+		"health.check_schedule":   cfg.Health.CheckSchedule,
+	} {
+		if expr != "" {
+			_, err := schedule.Parse(expr)
+			fail(section, err)
+		}
+	}
+	_, err = rates.NewProvider(cfg.Rates, clock.New())
+	fail("rates", err)
+	_, err = regions.NewProvider(cfg.Regions)
+	fail("regions", err)
+	_, err = phoneverify.NewVerifier(cfg.PhoneVerify, clock.New())
+	fail("phone_verify", err)
+	_, err = otp.NewSenders(cfg.OTP.Sender, zap.NewNop())
+	fail("otp", err)
+	_, err = fieldcrypt.NewKeyProvider(cfg.Encryption)
+	fail("encryption", err)
+	_, err = erasure.ParseStrategy(cfg.Erasure.Strategy)
+	fail("erasure", err)
+	_, err = storage.NewBlobStore(cfg.Storage, clock.New())
+	fail("storage", err)
+	_, err = documents.NewConverter(cfg.Documents)
+	fail("documents", err)
+	locales, err := newLocaleDefaults(cfg.I18n)
+	fail("i18n", err)
+	_, err = newConstraints(cfg.I18n)
+	fail("i18n.constraints", err)
+	if locales != nil {
+		_, err = newViews(cfg, locales, nil, nil)
+		fail("templates", err)
+	}
+	_, err = rbac.NewAuthorizer(cfg.RBAC)
+	fail("rbac", err)
+	_, err = notify.ParseChannels(cfg.Notify.DefaultChannels)
+	fail("notifications", err)
+	_, err = push.NewSenders(cfg.Push, clock.New(), zap.NewNop())
+	fail("push", err)
+	if cfg.Metering.Enabled {
+		_, err = metering.NewStaticPlans(cfg.Metering)
+		fail("metering", err)
+	}
+	if cfg.Geo.DefaultTimezone != "" {
+		_, err = i18n.NewTimezoneFromID(cfg.Geo.DefaultTimezone)
+		fail("geo.default_timezone", err)
+	}
+	// A bare engine, as gin.New prints its debug-mode banner
+	fail("server", configureClientIP(new(gin.Engine), cfg.Server))
+	if cfg.I18nAPI.ConvertRateLimit > 0 && cfg.I18nAPI.ConvertRateWindow <= 0 {
+		fail("i18n_api", errors.New("convert_rate_limit requires a positive convert_rate_window"))
+	}
+	if cfg.Health.HistorySize < 0 {
+		fail("health", errors.New("history_size must not be negative"))
+	}
+	if cfg.Admin.Enabled && cfg.Admin.Token == "" {
+		fail("admin", errors.New("admin server requires admin.token to be set"))
+	}
+
+	if cfg.OTP.Secret == "" {
+		warn("otp: no secret, codes only verify on the instance that issued them")
+	}
+	if cfg.PhoneVerify.Secret == "" && cfg.PhoneVerify.Provider != "" && cfg.PhoneVerify.Provider != "static" {
+		warn("phone_verify: no secret, cached lookups are only found by the instance that made them")
+	}
+	if cfg.Erasure.Secret == "" && cfg.Erasure.Strategy == string(erasure.StrategyPseudonymize) {
+		warn("erasure: pseudonymize without a secret derives replacements from a random key")
+	}
+	if cfg.Encryption.Provider == "" || cfg.Encryption.Provider == "none" {
+		warn("encryption: field encryption is disabled")
+	}
+
+	result.Duration = time.Since(started)
+	switch result.Status {
+	case CheckOK:
+		result.Detail = "valid"
+	case CheckWarn:
+		result.Detail = fmt.Sprintf("valid with %d warning(s)", len(result.Problems))
+	default:
+		result.Detail = "invalid"
+	}
+	return result
+}
+
+// CheckDatabase pings db
+func CheckDatabase(ctx context.Context, db *sql.DB) CheckResult {
+	return checkPing("database", func() error { return db.PingContext(ctx) })
+}
+
+// CheckRedis pings client
+func CheckRedis(ctx context.Context, client *redis.Client) CheckResult {
+	result := checkPing("redis", func() error { return client.Ping(ctx).Err() })
+	result.Detail = client.Options().Addr + " " + result.Detail
+	return result
+}
+
+func checkPing(name string, ping func() error) CheckResult {
+	started := time.Now()
+	err := ping()
+	result := CheckResult{Name: name, Duration: time.Since(started)}
+	if err != nil {
+		result.Status, result.Detail = CheckFail, err.Error()
+		return result
+	}
+	result.Detail = fmt.Sprintf("reachable in %s", result.Duration.Round(time.Millisecond))
+	return result
+}
+
+// migrationFile matches golang-migrate file names such as
+// 000003_create_erasure_audit.up.sql
+var migrationFile = regexp.MustCompile(`^(\d+)_.+\.up\.sql$`)
+
+// CheckMigrations compares the version recorded by golang-migrate in
+// schema_migrations with the newest migration in dir
+func CheckMigrations(ctx context.Context, db *sql.DB, dir string) (result CheckResult) {
+	started := time.Now()
+	result.Name = "migrations"
+	defer func() { result.Duration = time.Since(started) }()
+
+	latest, err := latestMigration(dir)
+	if err != nil {
+		result.Status, result.Detail = CheckFail, err.Error()
+		return result
+	}
+
+	var (
+		version int64
+		dirty   bool
+	)
+	err = db.QueryRowContext(ctx, `SELECT version, dirty FROM schema_migrations LIMIT 1`).Scan(&version, &dirty)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		version = 0
+	case err != nil:
+		result.Status, result.Detail = CheckFail, fmt.Sprintf("cannot read schema_migrations (run make migrate-up): %v", err)
+		return result
+	}
+
+	switch {
+	case dirty:
+		result.Status, result.Detail = CheckFail, fmt.Sprintf("version %d is dirty, a migration failed halfway", version)
+	case version < latest:
+		result.Status, result.Detail = CheckFail, fmt.Sprintf("at version %d, %d pending (latest %d)", version, latest-version, latest)
+	case version > latest:
+		result.Status, result.Detail = CheckWarn, fmt.Sprintf("at version %d, newer than the latest migration %d in %s", version, latest, dir)
+	default:
+		result.Detail = fmt.Sprintf("current at version %d", version)
+	}
+	return result
+}
+
+// latestMigration returns the highest migration version in dir
+func latestMigration(dir string) (int64, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return 0, fmt.Errorf("cannot read migrations: %w", err)
+	}
+	var latest int64
+	for _, entry := range entries {
+		match := migrationFile.FindStringSubmatch(entry.Name())
+		if match == nil {
+			continue
+		}
+		version, err := strconv.ParseInt(match[1], 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid migration %s: %w", entry.Name(), err)
+		}
+		latest = max(latest, version)
+	}
+	if latest == 0 {
+		return 0, fmt.Errorf("no migrations found in %s", dir)
+	}
+	return latest, nil
+}
+
+// CheckTimezones loads each zone from the timezone database; empty IDs
+// are ignored
+func CheckTimezones(ids ...string) CheckResult {
+	started := time.Now()
+	result := CheckResult{Name: "timezones"}
+	loaded := 0
+	for _, id := range ids {
+		if id == "" {
+			continue
+		}
+		if _, err := time.LoadLocation(id); err != nil {
+			result.Status = CheckFail
+			result.Problems = append(result.Problems, err.Error())
+			continue
+		}
+		loaded++
+	}
+	result.Duration = time.Since(started)
+
+	source := "system zoneinfo"
+	if zoneinfo := os.Getenv("ZONEINFO"); zoneinfo != "" {
+		source = "ZONEINFO=" + zoneinfo
+	}
+	if result.Status == CheckFail {
+		result.Detail = fmt.Sprintf("%d of %d zones failed to load from %s; install tzdata or import time/tzdata",
+			len(result.Problems), len(result.Problems)+loaded, source)
+	} else {
+		result.Detail = fmt.Sprintf("%d zones loaded from %s", loaded, source)
+	}
+	return result
+}
+
+// ANSI colors of the report statuses
+var statusColors = map[CheckStatus]string{
+	CheckOK:      "\033[32m",
+	CheckWarn:    "\033[33m",
+	CheckFail:    "\033[31m",
+	CheckSkipped: "\033[90m",
+}
+
+// WriteDoctorReport prints one line per result, colored when color is set,
+// and reports whether any check failed
+func WriteDoctorReport(w io.Writer, results []CheckResult, color bool) bool {
+	failed := false
+	for _, result := range results {
+		label := fmt.Sprintf("%-4s", result.Status)
+		if color {
+			label = statusColors[result.Status] + label + "\033[0m"
+		}
+		fmt.Fprintf(w, "[%s] %-10s %s\n", label, result.Name, result.Detail)
+		for _, problem := range result.Problems {
+			fmt.Fprintf(w, "       - %s\n", problem)
+		}
+		failed = failed || result.Status == CheckFail
+	}
+
+	summary := "All checks passed"
+	if failed {
+		summary = "Some checks failed"
+	}
+	fmt.Fprintf(w, "\n%s\n", summary)
+	return failed
+}
+
+// openPostgres opens the connection pool of dbConfig, applying its session
+// settings to every connection and injecting faults when faults is not nil
+func openPostgres(dbConfig config.DatabaseConfig, faults *chaos.Injector) (*sql.DB, error) {
+	connector, err := pq.NewConnector(postgresDSN(dbConfig))
+	if err != nil {
+		return nil, err
+	}
+	return sql.OpenDB(faults.Connector(database.NewConnector(connector,
+		database.WithTimeZone(dbConfig.TimeZone),
+		database.WithLocale(dbConfig.Locale),
+	))), nil
+}
+
+// postgresDSN builds the key/value connection string of dbConfig
+func postgresDSN(dbConfig config.DatabaseConfig) string {
+	dsn := fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
+		dbConfig.Host, dbConfig.Port, dbConfig.User, dbConfig.Password, dbConfig.Name, dbConfig.SSLMode)
+	if option := database.StatementTimeout(dbConfig.StatementTimeout); option != "" {
+		dsn += " " + option
+	}
+	return dsn
+}
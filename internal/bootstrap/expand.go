@@ -0,0 +1,184 @@
+package bootstrap
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// expandConfig resolves placeholders in every string setting of v:
+//
+//	${NAME}          the environment variable NAME
+//	${NAME:-default} NAME, or default when it is unset or empty
+//	${section.key}   another setting, itself expanded first
+//	$${              a literal "${"
+//
+// Names containing a dot refer to settings, others to the environment.
+// Placeholders that resolve to nothing are errors, reported together.
+func expandConfig(v *viper.Viper) error {
+	e := &expander{v: v, resolved: map[string]string{}, resolving: map[string]bool{}}
+
+	keys := v.AllKeys()
+	sort.Strings(keys)
+	var errs []error
+	for _, key := range keys {
+		value, changed, err := e.expandValue(key, v.Get(key))
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if changed {
+			v.Set(key, value)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// expander memoizes expanded settings and detects reference cycles
+type expander struct {
+	v         *viper.Viper
+	resolved  map[string]string
+	resolving map[string]bool
+}
+
+// expandValue expands strings, including those nested in lists and maps
+// such as metering.consumers
+func (e *expander) expandValue(key string, value any) (any, bool, error) {
+	switch value := value.(type) {
+	case string:
+		if !strings.Contains(value, "${") {
+			return value, false, nil
+		}
+		expanded, err := e.expandString(key, value)
+		return expanded, err == nil, err
+	case []string:
+		out := make([]string, len(value))
+		changed := false
+		for i, item := range value {
+			expanded, itemChanged, err := e.expandValue(key, item)
+			if err != nil {
+				return nil, false, err
+			}
+			out[i], changed = expanded.(string), changed || itemChanged
+		}
+		return out, changed, nil
+	case []any:
+		out := make([]any, len(value))
+		changed := false
+		for i, item := range value {
+			expanded, itemChanged, err := e.expandValue(key, item)
+			if err != nil {
+				return nil, false, err
+			}
+			out[i], changed = expanded, changed || itemChanged
+		}
+		return out, changed, nil
+	case map[string]any:
+		out := make(map[string]any, len(value))
+		changed := false
+		for name, item := range value {
+			expanded, itemChanged, err := e.expandValue(key, item)
+			if err != nil {
+				return nil, false, err
+			}
+			out[name], changed = expanded, changed || itemChanged
+		}
+		return out, changed, nil
+	default:
+		return value, false, nil
+	}
+}
+
+// expandString replaces the placeholders of one string setting
+func (e *expander) expandString(key, value string) (string, error) {
+	var out strings.Builder
+	for {
+		start := strings.Index(value, "${")
+		if start < 0 {
+			out.WriteString(value)
+			return out.String(), nil
+		}
+		if start > 0 && value[start-1] == '$' {
+			out.WriteString(value[:start-1] + "${")
+			value = value[start+2:]
+			continue
+		}
+		end := strings.IndexByte(value[start:], '}')
+		if end < 0 {
+			return "", fmt.Errorf("config %s: unterminated placeholder in %q", key, value)
+		}
+		out.WriteString(value[:start])
+
+		resolved, err := e.resolve(key, value[start+2:start+end])
+		if err != nil {
+			return "", err
+		}
+		out.WriteString(resolved)
+		value = value[start+end+1:]
+	}
+}
+
+// resolve looks up the expression of one placeholder
+func (e *expander) resolve(key, expr string) (string, error) {
+	name, fallback, hasFallback := strings.Cut(expr, ":-")
+	name = strings.TrimSpace(name)
+
+	var value string
+	switch {
+	case name == "":
+		return "", fmt.Errorf("config %s: empty placeholder ${%s}", key, expr)
+	case strings.Contains(name, "."):
+		ref, err := e.setting(strings.ToLower(name))
+		if err != nil {
+			return "", fmt.Errorf("config %s: %w", key, err)
+		}
+		value = ref
+	default:
+		value = os.Getenv(name)
+	}
+
+	if value == "" {
+		if !hasFallback {
+			return "", fmt.Errorf("config %s: unresolved placeholder ${%s}", key, expr)
+		}
+		return fallback, nil
+	}
+	return value, nil
+}
+
+// setting returns the expanded value of another setting
+func (e *expander) setting(key string) (string, error) {
+	if value, ok := e.resolved[key]; ok {
+		return value, nil
+	}
+	if e.resolving[key] {
+		return "", fmt.Errorf("reference cycle through %s", key)
+	}
+	if !e.v.IsSet(key) {
+		return "", nil
+	}
+
+	e.resolving[key] = true
+	defer delete(e.resolving, key)
+
+	raw := e.v.Get(key)
+	var value string
+	switch raw := raw.(type) {
+	case string:
+		expanded, err := e.expandString(key, raw)
+		if err != nil {
+			return "", err
+		}
+		value = expanded
+	case map[string]any, []any:
+		return "", fmt.Errorf("%s is not a scalar setting", key)
+	default:
+		value = fmt.Sprint(raw)
+	}
+	e.resolved[key] = value
+	return value, nil
+}
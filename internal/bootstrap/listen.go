@@ -0,0 +1,225 @@
+package bootstrap
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	"golang-arch/internal/shared/config"
+
+	"github.com/soheilhy/cmux"
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
+)
+
+// GRPCServer is the part of *grpc.Server needed to share the HTTP port
+type GRPCServer interface {
+	Serve(listener net.Listener) error
+	GracefulStop()
+	Stop()
+}
+
+// RegisterGRPC serves grpcServer on the HTTP port when server.grpc is
+// enabled; call it before Serve
+func (s *Server) RegisterGRPC(grpcServer GRPCServer) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.grpc = grpcServer
+}
+
+// Handler returns the router, accepting HTTP/2 without TLS (h2c) when
+// server.h2c is enabled
+func (s *Server) Handler() http.Handler {
+	if s.container.Config.Server.H2C {
+		return h2c.NewHandler(s.router, &http2.Server{})
+	}
+	return s.router
+}
+
+// Listen opens the listener configured in cfg: the socket systemd passed
+// when server.systemd_activation is set and the process was socket
+// activated, else a unix socket at server.socket, else TCP on server.port,
+// shared with other processes when server.reuse_port is set
+func Listen(cfg config.ServerConfig) (net.Listener, error) {
+	if cfg.SystemdActivation {
+		listener, err := systemdListener(cfg.SystemdName)
+		if err != nil || listener != nil {
+			return listener, err
+		}
+	}
+	if cfg.Socket != "" {
+		return listenUnix(cfg.Socket, cfg.SocketMode)
+	}
+	var listenConfig net.ListenConfig
+	if cfg.ReusePort {
+		listenConfig.Control = reusePort
+	}
+	return listenConfig.Listen(context.Background(), "tcp", fmt.Sprintf(":%d", cfg.Port))
+}
+
+// listenUnix listens on the unix socket path, replacing a stale socket left
+// by an earlier run. mode is octal, e.g. "0660"; empty keeps the umask
+// default. The socket file is removed when the listener is closed.
+func listenUnix(path, mode string) (net.Listener, error) {
+	var perm fs.FileMode
+	if mode != "" {
+		parsed, err := strconv.ParseUint(mode, 8, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid server.socket_mode %q: %w", mode, err)
+		}
+		perm = fs.FileMode(parsed)
+	}
+
+	if info, err := os.Lstat(path); err == nil {
+		if info.Mode()&fs.ModeSocket == 0 {
+			return nil, fmt.Errorf("%s exists and is not a socket", path)
+		}
+		if err := os.Remove(path); err != nil {
+			return nil, fmt.Errorf("failed to remove stale socket: %w", err)
+		}
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+
+	listener, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, err
+	}
+	if mode != "" {
+		if err := os.Chmod(path, perm); err != nil {
+			listener.Close()
+			return nil, fmt.Errorf("failed to set socket mode: %w", err)
+		}
+	}
+	return listener, nil
+}
+
+// ListenAndServe listens on the TCP address addr and calls Serve
+func (s *Server) ListenAndServe(addr string) error {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	return s.Serve(listener)
+}
+
+// Serve accepts connections on listener until Shutdown, after which it
+// returns http.ErrServerClosed. With server.grpc enabled and a gRPC server
+// registered, connections are split by protocol: HTTP/2 requests with a
+// gRPC content type go to the gRPC server and everything else to the
+// router, so both share one port behind an L4 load balancer.
+func (s *Server) Serve(listener net.Listener) error {
+	s.mu.Lock()
+	httpServer, grpcServer := s.httpServer, s.grpc
+	s.mu.Unlock()
+
+	if grpcServer == nil || !s.container.Config.Server.GRPC {
+		return httpServer.Serve(listener)
+	}
+
+	mux := cmux.New(listener)
+	// gRPC clients wait for the server's SETTINGS frame before sending
+	// headers, so the matcher has to send it while sniffing
+	grpcListener := mux.MatchWithWriters(cmux.HTTP2MatchHeaderFieldSendSettings("content-type", "application/grpc"))
+	httpListener := mux.Match(cmux.Any())
+
+	// Shutdown sets shuttingDown before taking the lock, so either it sees
+	// the mux and closes it or Serve sees the flag and does not start
+	s.mu.Lock()
+	if s.shuttingDown.Load() {
+		s.mu.Unlock()
+		listener.Close()
+		return http.ErrServerClosed
+	}
+	s.mux, s.listener = mux, listener
+	s.mu.Unlock()
+
+	errs := make(chan error, 3)
+	go func() { errs <- grpcServer.Serve(grpcListener) }()
+	go func() { errs <- httpServer.Serve(httpListener) }()
+	go func() { errs <- mux.Serve() }()
+
+	err := <-errs
+	if s.shuttingDown.Load() {
+		return http.ErrServerClosed
+	}
+	// One side failed on its own; take the others down with it
+	mux.Close()
+	listener.Close()
+	grpcServer.Stop()
+	httpServer.Close()
+	return err
+}
+
+// Drain takes the server out of rotation ahead of Shutdown, for rolling
+// deploys: /ready fails and keep-alive connections are closed after their
+// next response, so load balancers move traffic to other instances while
+// requests are still served. It returns after delay or once ctx ends.
+func (s *Server) Drain(ctx context.Context, delay time.Duration) {
+	s.draining.Store(true)
+	s.httpServer.SetKeepAlivesEnabled(false)
+
+	select {
+	case <-s.container.Clock.After(delay):
+	case <-ctx.Done():
+	}
+}
+
+// Draining reports whether Drain was called
+func (s *Server) Draining() bool {
+	return s.draining.Load()
+}
+
+// Shutdown stops accepting connections and waits for in-flight HTTP
+// requests and gRPC calls to finish, then for the exports they started, or
+// for ctx to expire, in which case remaining gRPC calls are cancelled and
+// exports are left for Container.Close to cancel
+func (s *Server) Shutdown(ctx context.Context) error {
+	s.shuttingDown.Store(true)
+	s.mu.Lock()
+	httpServer, grpcServer, mux, listener := s.httpServer, s.grpc, s.mux, s.listener
+	s.mu.Unlock()
+	if !s.container.Config.Server.GRPC {
+		grpcServer = nil
+	}
+
+	// Stop accepting on the shared port. The gRPC and HTTP listeners of the
+	// mux wrap it, so closing them again below reports net.ErrClosed
+	if mux != nil {
+		mux.Close()
+		listener.Close()
+	}
+
+	stopped := make(chan struct{})
+	if grpcServer != nil {
+		go func() {
+			grpcServer.GracefulStop()
+			close(stopped)
+		}()
+	}
+
+	err := httpServer.Shutdown(ctx)
+	if mux != nil && errors.Is(err, net.ErrClosed) {
+		err = nil
+	}
+	if grpcServer != nil {
+		select {
+		case <-stopped:
+		case <-ctx.Done():
+			grpcServer.Stop()
+			if err == nil {
+				err = ctx.Err()
+			}
+		}
+	}
+	if err == nil && s.container.Exports != nil {
+		err = s.container.Exports.Wait(ctx)
+	}
+	return err
+}
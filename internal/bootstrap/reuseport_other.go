@@ -0,0 +1,13 @@
+//go:build !unix || solaris
+
+package bootstrap
+
+import (
+	"errors"
+	"syscall"
+)
+
+// reusePort fails: SO_REUSEPORT is not available on this platform.
+func reusePort(string, string, syscall.RawConn) error {
+	return errors.New("server.reuse_port is not supported on this platform")
+}
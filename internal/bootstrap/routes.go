@@ -0,0 +1,149 @@
+package bootstrap
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"regexp"
+	"runtime"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"golang-arch/internal/shared/config"
+	"golang-arch/internal/shared/storage"
+	"golang-arch/pkg/metrics"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RouteInfo describes a registered route
+type RouteInfo struct {
+	Method     string   `json:"method"`
+	Path       string   `json:"path"`
+	Handler    string   `json:"handler"`
+	Middleware []string `json:"middleware"`     // Chain run before the handler, outermost first
+	Auth       string   `json:"auth,omitempty"` // Credentials the route requires; empty when public
+}
+
+// handle runs register on group and records the routes it adds. Gin
+// panics on a duplicate or conflicting route; RegisterAPI and ListRoutes
+// turn that into an error.
+func (s *Server) handle(group *gin.RouterGroup, auth string, register func(*gin.RouterGroup)) {
+	existing := make(map[string]bool)
+	for _, route := range s.router.Routes() {
+		existing[route.Method+" "+route.Path] = true
+	}
+
+	register(group)
+
+	middleware := make([]string, len(group.Handlers))
+	for i, handler := range group.Handlers {
+		middleware[i] = funcName(handler)
+	}
+	for _, route := range s.router.Routes() {
+		if existing[route.Method+" "+route.Path] {
+			continue
+		}
+		s.routes = append(s.routes, RouteInfo{
+			Method:     route.Method,
+			Path:       route.Path,
+			Handler:    shortFuncName(route.Handler),
+			Middleware: middleware,
+			Auth:       auth,
+		})
+	}
+}
+
+// RegisterAPI adds routes under /api/v1, behind the same middleware as the
+// built-in API routes. auth describes the credentials they require, for the
+// routes listing. A duplicate or conflicting route is returned as an error.
+func (s *Server) RegisterAPI(auth string, register func(*gin.RouterGroup)) (err error) {
+	defer recoverConflict(&err)
+	s.handle(s.api, auth, register)
+	return nil
+}
+
+// Routes returns the registered routes sorted by path and method
+func (s *Server) Routes() []RouteInfo {
+	routes := append([]RouteInfo(nil), s.routes...)
+	sort.Slice(routes, func(i, j int) bool {
+		if routes[i].Path != routes[j].Path {
+			return routes[i].Path < routes[j].Path
+		}
+		return routes[i].Method < routes[j].Method
+	})
+	return routes
+}
+
+// ListRoutes builds the server of container and returns its routes.
+// Invalid server settings are returned as an error, and so is a duplicate or
+// conflicting registration instead of panicking.
+func ListRoutes(container *Container) (routes []RouteInfo, err error) {
+	defer recoverConflict(&err)
+	server, err := NewServer(container)
+	if err != nil {
+		return nil, err
+	}
+	return server.Routes(), nil
+}
+
+// RoutesFromConfig lists the routes of a server built from cfg. Routes
+// depend only on configuration, so the server is built on an in-memory
+// container and neither the database nor Redis is contacted.
+func RoutesFromConfig(cfg *config.AppConfig) ([]RouteInfo, error) {
+	tc, err := NewTestContainer(WithTestConfig(cfg))
+	if err != nil {
+		return nil, err
+	}
+	defer tc.Close()
+	if tc.Blobs, err = storage.NewBlobStore(cfg.Storage, tc.Clock); err != nil {
+		return nil, err
+	}
+	if cfg.Metrics.Exporter == metrics.ExporterPrometheus {
+		// Only the scrape handler is needed, not a configured exporter
+		if tc.Metrics, err = metrics.NewProvider(metrics.Options{Exporter: metrics.ExporterPrometheus}); err != nil {
+			return nil, err
+		}
+	}
+	return ListRoutes(tc.Container)
+}
+
+// recoverConflict turns gin's route registration panic into *err
+func recoverConflict(err *error) {
+	if r := recover(); r != nil {
+		*err = fmt.Errorf("conflicting route registration: %v", r)
+	}
+}
+
+// WriteRoutes prints routes as a table
+func WriteRoutes(w io.Writer, routes []RouteInfo) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "METHOD\tPATH\tHANDLER\tAUTH\tMIDDLEWARE")
+	for _, route := range routes {
+		auth := route.Auth
+		if auth == "" {
+			auth = "-"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n",
+			route.Method, route.Path, route.Handler, auth, strings.Join(route.Middleware, ", "))
+	}
+	return tw.Flush()
+}
+
+// funcName returns the short name of a handler function
+func funcName(handler gin.HandlerFunc) string {
+	return shortFuncName(runtime.FuncForPC(reflect.ValueOf(handler).Pointer()).Name())
+}
+
+// closureSuffix matches the compiler's names of closures and method values
+var closureSuffix = regexp.MustCompile(`(\.func\d+|\.\d+)+$|-fm$`)
+
+// shortFuncName trims "golang-arch/internal/bootstrap.loggerMiddleware.func1"
+// to "bootstrap.loggerMiddleware"
+func shortFuncName(name string) string {
+	if i := strings.LastIndexByte(name, '/'); i >= 0 {
+		name = name[i+1:]
+	}
+	return closureSuffix.ReplaceAllString(name, "")
+}
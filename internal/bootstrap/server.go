@@ -1,9 +1,36 @@
 package bootstrap
 
 import (
+	"fmt"
+	"net"
 	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"golang-arch/internal/shared/api"
+	"golang-arch/internal/shared/config"
+	"golang-arch/internal/shared/consent"
+	"golang-arch/internal/shared/database"
+	"golang-arch/internal/shared/geo"
+	"golang-arch/internal/shared/health"
+	"golang-arch/internal/shared/i18napi"
+	"golang-arch/internal/shared/jobs"
+	"golang-arch/internal/shared/metering"
+	"golang-arch/internal/shared/notify"
+	"golang-arch/internal/shared/otp"
+	"golang-arch/internal/shared/push"
+	"golang-arch/internal/shared/ratelimit"
+	"golang-arch/internal/shared/refdata"
+	"golang-arch/internal/shared/respcache"
+	"golang-arch/internal/shared/secheaders"
+	"golang-arch/internal/shared/storage"
+	"golang-arch/pkg/logger"
+	"golang-arch/pkg/metrics"
 
 	"github.com/gin-gonic/gin"
+	"github.com/soheilhy/cmux"
 	"go.uber.org/zap"
 )
 
@@ -11,18 +38,50 @@ import (
 type Server struct {
 	router    *gin.Engine
 	container *Container
+	api       *gin.RouterGroup // /api/v1
+	routes    []RouteInfo      // Registered routes, see routes.go
+
+	// Serving state, see listen.go
+	mu           sync.Mutex
+	httpServer   *http.Server // Created with the server, so Shutdown can run before Serve
+	grpc         GRPCServer
+	mux          cmux.CMux    // Splits listener between gRPC and HTTP while serving both
+	listener     net.Listener // Root listener of mux
+	shuttingDown atomic.Bool
+	draining     atomic.Bool
 }
 
-// NewServer creates a new HTTP server instance
-func NewServer(container *Container) *Server {
+// NewServer creates a new HTTP server instance. Invalid client IP settings
+// are an error, so a misconfigured proxy list fails at startup.
+func NewServer(container *Container) (*Server, error) {
 	// Set Gin mode based on environment
 	gin.SetMode(gin.ReleaseMode)
 
 	router := gin.New()
+	if err := configureClientIP(router, container.Config.Server); err != nil {
+		return nil, err
+	}
 
 	// Add middleware
 	router.Use(gin.Recovery())
-	router.Use(loggerMiddleware(container.Logger))
+	if headers := container.Config.Security.Headers; headers.Enabled {
+		policy, routes := secheaders.PolicyFromConfig(headers)
+		router.Use(secheaders.Middleware(policy, routes...))
+	}
+	router.Use(loggerMiddleware(container.Loggers.Named(logger.NameHTTP)))
+	router.Use(metricsMiddleware(container.Metrics.Instruments))
+	if shed := container.Config.LoadShed; container.Shedder != nil {
+		// Before chaos, so injected latency counts against the limit as real latency would
+		router.Use(container.Shedder.Middleware(shed.RetryAfter, shed.PriorityPaths...))
+	}
+	if container.Chaos != nil {
+		router.Use(container.Chaos.Middleware(container.Config.Chaos.SkipPaths...))
+	}
+	router.Use(api.ErrorHandler())
+	if container.Sunsets != nil && container.Sunsets.Len() > 0 {
+		router.Use(container.Sunsets.Middleware())
+	}
+	router.Use(geo.Middleware(container.Geo, geo.Defaults(container.Config.Geo, container.I18n.Preferences)))
 
 	server := &Server{
 		router:    router,
@@ -31,21 +90,149 @@ func NewServer(container *Container) *Server {
 
 	// Setup routes
 	server.setupRoutes()
+	server.httpServer = &http.Server{Handler: server.Handler()}
 
-	return server
+	return server, nil
 }
 
-// setupRoutes configures all application routes
+// setupRoutes configures all application routes. Routes are registered
+// through s.handle so the routes command can list them with their
+// middleware and auth requirement.
 func (s *Server) setupRoutes() {
-	// Health check endpoint
-	s.router.GET("/health", s.healthCheck)
+	root := &s.router.RouterGroup
+
+	// Health check endpoints
+	s.handle(root, "", func(group *gin.RouterGroup) {
+		group.GET("/health", s.healthCheck)
+		group.GET("/ready", s.readinessCheck)
+	})
+	s.handle(root, "", health.NewHandler(s.container.Health).Register)
+
+	// Presigned blob URLs of the local storage provider
+	if local, ok := s.container.Blobs.(*storage.LocalStore); ok {
+		s.handle(root, "signed url", local.Register)
+	}
+
+	// Static assets of server-rendered pages
+	s.handle(root, "", s.container.Views.Register)
+
+	// Prometheus scrape endpoint (only when the pull exporter is selected)
+	if handler := s.container.Metrics.Handler(); handler != nil {
+		s.handle(root, "", func(group *gin.RouterGroup) {
+			group.GET(s.container.Config.Metrics.Path, gin.WrapH(handler))
+		})
+	}
 
 	// API routes
-	_ = s.router.Group("/api/v1")
+	v1 := s.router.Group("/api/v1")
+	s.api = v1
+	// Bounds the queries of API requests; the job event stream is long-lived
+	v1.Use(database.Middleware(s.container.Config.Database.QueryTimeout, "/api/v1/jobs/:id/events"))
+	if s.container.Metering != nil {
+		// Installed first so quotas apply to every route registered below
+		v1.Use(s.container.Metering.Middleware(s.container.Config.Metering.Header))
+	}
 	{
+		if s.container.Metering != nil {
+			s.handle(v1, "api key ("+s.container.Config.Metering.Header+")",
+				metering.NewHandler(s.container.Metering, s.container.Config.Metering.Header).Register)
+		}
+		if s.container.OTP != nil {
+			s.handle(v1, "", otp.NewHandler(s.container.OTP).Register)
+		}
+		if s.container.Consent != nil {
+			s.handle(v1, "bearer token (rbac)", consent.NewHandler(s.container.Consent, s.container.Authz).Register)
+		}
+		if s.container.Notify != nil {
+			s.handle(v1, "bearer token (rbac)", notify.NewHandler(s.container.Notify, s.container.Authz).Register)
+		}
+		if s.container.Push != nil {
+			s.handle(v1, "bearer token (rbac)", push.NewHandler(s.container.Push, s.container.Authz).Register)
+		}
+		s.handle(v1, "", s.i18nHandler().Register)
+		s.handle(v1, "job id", jobs.NewHandler(s.container.Jobs, s.container.Views, s.container.Config.Jobs.MaxWait).Register)
+		if s.container.RefData != nil {
+			s.handle(v1, "bearer token (rbac)", refdata.NewHandler(s.container.RefData, s.container.Authz).Register)
+		}
+
 		// Add service routes here
-		// Example: userService.SetupRoutes(api)
+		// Example: s.handle(v1, "", userService.SetupRoutes)
+	}
+}
+
+// i18nHandler builds the public i18n endpoints; conversions are limited per
+// API key, or per client IP for anonymous callers
+func (s *Server) i18nHandler() *i18napi.Handler {
+	cfg := s.container.Config.I18nAPI
+	options := []i18napi.Option{
+		i18napi.WithConverter(s.container.Rates, cfg.ConvertMaxAge),
+		i18napi.WithDefaults(s.container.I18n.Preferences),
+	}
+	header := s.container.Config.Metering.Header
+	if header == "" {
+		header = metering.DefaultHeader
+	}
+	if cfg.ConvertRateLimit > 0 {
+		limiter := ratelimit.NewLimiter(s.container.Redis, "i18n_convert", cfg.ConvertRateLimit, cfg.ConvertRateWindow,
+			ratelimit.WithLogger(s.container.Loggers.Named(logger.NameHTTP)))
+		options = append(options, i18napi.WithConvertLimit(limiter.Middleware(ratelimit.ByConsumer(header))))
+	}
+	if cfg.CatalogCacheTTL > 0 {
+		// Stale entries are refreshed by replaying their request through the
+		// router, so the refresh sees the same middleware as the original,
+		// without the caller's credentials
+		catalog := respcache.New(cfg.CatalogCacheTTL, cfg.CatalogCacheStale,
+			respcache.WithMaxEntries(cfg.CatalogCacheSize),
+			respcache.WithReplay(s.router),
+			respcache.WithStripHeaders(header),
+			respcache.WithLookupsCounter(s.container.Metrics.Instruments.HTTPResponseCache),
+			respcache.WithLogger(s.container.Loggers.Named(logger.NameHTTP)))
+		options = append(options, i18napi.WithCatalogCache(catalog))
 	}
+	if s.container.RefData != nil {
+		options = append(options, i18napi.WithCalendars(s.container.RefData))
+	}
+
+	handler := i18napi.NewHandler(options...)
+	if s.container.RefData != nil {
+		// Currencies may have been registered or removed
+		s.container.RefData.OnChange(func(refdata.Change) { handler.Refresh() })
+	}
+	return handler
+}
+
+// trustedPlatforms maps the configured platform names to the header the
+// platform sets to the client IP
+var trustedPlatforms = map[string]string{
+	"cloudflare":        gin.PlatformCloudflare,
+	"google_app_engine": gin.PlatformGoogleAppEngine,
+	"fly_io":            gin.PlatformFlyIO,
+}
+
+// configureClientIP decides where c.ClientIP comes from. Forwarding headers
+// are only believed from the configured proxies, so neither clients nor an
+// untrusted hop can spoof the IP that logging, geolocation and per-IP limits
+// see; with no proxies configured it is the peer address.
+func configureClientIP(router *gin.Engine, cfg config.ServerConfig) error {
+	proxies := make([]string, 0, len(cfg.TrustedProxies))
+	for _, proxy := range cfg.TrustedProxies {
+		if proxy = strings.TrimSpace(proxy); proxy != "" {
+			proxies = append(proxies, proxy)
+		}
+	}
+	if err := router.SetTrustedProxies(proxies); err != nil {
+		return fmt.Errorf("invalid server.trusted_proxies: %w", err)
+	}
+	if len(cfg.RemoteIPHeaders) > 0 {
+		router.RemoteIPHeaders = cfg.RemoteIPHeaders
+	}
+
+	platform := strings.TrimSpace(cfg.TrustedPlatform)
+	if header, ok := trustedPlatforms[strings.ToLower(platform)]; ok {
+		platform = header
+	}
+	router.TrustedPlatform = platform
+	return nil
 }
 
 // healthCheck handles the health check endpoint
@@ -56,6 +243,28 @@ func (s *Server) healthCheck(c *gin.Context) {
 	})
 }
 
+// readinessCheck reports whether the database and Redis answer, so a
+// degraded start (startup.degraded) is kept out of rotation until they do,
+// and fails once the server is draining for shutdown
+func (s *Server) readinessCheck(c *gin.Context) {
+	if s.draining.Load() {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"status": "draining"})
+		return
+	}
+	status, code := "ready", http.StatusOK
+	results := s.container.Health.Probe(c.Request.Context())
+	checks := make(gin.H, len(results))
+	for name, result := range results {
+		if !result.Up {
+			status, code = "unavailable", http.StatusServiceUnavailable
+			checks[name] = result.Error
+			continue
+		}
+		checks[name] = "ok"
+	}
+	c.JSON(code, gin.H{"status": status, "checks": checks})
+}
+
 // ServeHTTP implements the http.Handler interface
 func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	s.router.ServeHTTP(w, r)
@@ -74,3 +283,21 @@ func loggerMiddleware(logger *zap.Logger) gin.HandlerFunc {
 		return ""
 	})
 }
+
+// metricsMiddleware records request counts and durations on the shared instruments
+func metricsMiddleware(instruments *metrics.Instruments) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+
+		labels := metrics.HTTPLabels(c.Request.Method, route, c.Writer.Status())
+
+		instruments.HTTPRequests.Inc(labels)
+		instruments.HTTPRequestDuration.ObserveDuration(start, labels)
+	}
+}
@@ -0,0 +1,75 @@
+package bootstrap
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"golang-arch/internal/shared/config"
+)
+
+// pingTimeout bounds a single connection attempt
+const pingTimeout = 5 * time.Second
+
+// ErrDependencyUnavailable marks a dependency still unreachable when the
+// startup wait is over
+var ErrDependencyUnavailable = errors.New("dependency unavailable")
+
+// WaitForDependency pings a dependency until it answers, retrying with
+// exponential backoff between cfg.RetryInitial and cfg.RetryMax for up to
+// cfg.WaitTimeout. With no wait timeout only one attempt is made. The error
+// wraps ErrDependencyUnavailable and the last ping error.
+func WaitForDependency(ctx context.Context, name string, ping func(context.Context) error, cfg config.StartupConfig) error {
+	ctx, cancel := context.WithTimeout(ctx, cfg.WaitTimeout)
+	defer cancel()
+
+	delay := cfg.RetryInitial
+	for attempt := 1; ; attempt++ {
+		err := pingOnce(ctx, ping)
+		if err == nil {
+			if attempt > 1 {
+				log.Printf("%s reachable after %d attempts", name, attempt)
+			}
+			return nil
+		}
+		if cfg.WaitTimeout <= 0 || ctx.Err() != nil {
+			return errors.Join(ErrDependencyUnavailable, err)
+		}
+
+		log.Printf("Waiting for %s (attempt %d): %v", name, attempt, err)
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return errors.Join(ErrDependencyUnavailable, err)
+		}
+		delay = min(2*delay, cfg.RetryMax)
+		if delay <= 0 {
+			delay = cfg.RetryMax
+		}
+	}
+}
+
+// pingOnce makes one attempt, bounded by pingTimeout and ctx. Attempts are
+// not cut short by an expired wait so the first one always gets a chance.
+func pingOnce(ctx context.Context, ping func(context.Context) error) error {
+	attemptCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), pingTimeout)
+	defer cancel()
+	return ping(attemptCtx)
+}
+
+// waitOrDegrade waits for a dependency and decides what a failed wait means:
+// fatal by default, or reachable=false when cfg.Degraded lets startup go on
+// and leaves /ready to report the outage until the client reconnects
+func waitOrDegrade(name string, ping func(context.Context) error, cfg config.StartupConfig) (reachable bool, err error) {
+	err = WaitForDependency(context.Background(), name, ping, cfg)
+	switch {
+	case err == nil:
+		return true, nil
+	case cfg.Degraded:
+		log.Printf("Starting degraded, %s is unavailable: %v", name, err)
+		return false, nil
+	default:
+		return false, err
+	}
+}
@@ -0,0 +1,10 @@
+//go:build !unix
+
+package bootstrap
+
+import "net"
+
+// systemdListener returns nil: socket activation only exists on unix.
+func systemdListener(string) (net.Listener, error) {
+	return nil, nil
+}
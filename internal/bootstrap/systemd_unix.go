@@ -0,0 +1,60 @@
+//go:build unix
+
+package bootstrap
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+// listenFDsStart is the first file descriptor systemd passes
+const listenFDsStart = 3
+
+// systemdListener returns the socket passed by systemd socket activation
+// (LISTEN_PID, LISTEN_FDS, LISTEN_FDNAMES), or nil when the process was not
+// socket activated. With several sockets, name selects one by its
+// FileDescriptorName; empty takes the first. The variables are cleared so
+// child processes do not inherit them.
+func systemdListener(name string) (net.Listener, error) {
+	pid, fds := os.Getenv("LISTEN_PID"), os.Getenv("LISTEN_FDS")
+	if fds == "" || pid != strconv.Itoa(os.Getpid()) {
+		return nil, nil
+	}
+	names := strings.Split(os.Getenv("LISTEN_FDNAMES"), ":")
+	os.Unsetenv("LISTEN_PID")
+	os.Unsetenv("LISTEN_FDS")
+	os.Unsetenv("LISTEN_FDNAMES")
+
+	count, err := strconv.Atoi(fds)
+	if err != nil || count < 1 {
+		return nil, fmt.Errorf("invalid LISTEN_FDS %q", fds)
+	}
+	index := 0
+	if name != "" {
+		index = -1
+		for i, candidate := range names {
+			if candidate == name && i < count {
+				index = i
+				break
+			}
+		}
+		if index < 0 {
+			return nil, fmt.Errorf("systemd passed no socket named %q", name)
+		}
+	}
+
+	for fd := listenFDsStart; fd < listenFDsStart+count; fd++ {
+		syscall.CloseOnExec(fd)
+	}
+	file := os.NewFile(uintptr(listenFDsStart+index), "systemd-socket")
+	defer file.Close()
+	listener, err := net.FileListener(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to use systemd socket: %w", err)
+	}
+	return listener, nil
+}
@@ -0,0 +1,345 @@
+package bootstrap
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/base64"
+	"fmt"
+	"time"
+
+	"golang-arch/internal/shared/config"
+	"golang-arch/internal/shared/consent"
+	"golang-arch/internal/shared/erasure"
+	"golang-arch/internal/shared/events"
+	"golang-arch/internal/shared/eventsource"
+	"golang-arch/internal/shared/fieldcrypt"
+	"golang-arch/internal/shared/geo"
+	"golang-arch/internal/shared/jobs"
+	"golang-arch/internal/shared/metering"
+	"golang-arch/internal/shared/notify"
+	"golang-arch/internal/shared/phoneverify"
+	"golang-arch/internal/shared/projection"
+	"golang-arch/internal/shared/push"
+	"golang-arch/internal/shared/rates"
+	"golang-arch/internal/shared/rbac"
+	"golang-arch/internal/shared/refdata"
+	"golang-arch/internal/shared/regions"
+	"golang-arch/internal/shared/repository"
+	"golang-arch/internal/shared/retention"
+	"golang-arch/internal/shared/saga"
+	"golang-arch/internal/shared/storage"
+	"golang-arch/pkg/clock"
+	"golang-arch/pkg/logger"
+	"golang-arch/pkg/metrics"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+// TestContainer is a Container wired with in-memory fakes so handlers and jobs
+// can be unit tested without Postgres or Redis
+type TestContainer struct {
+	*Container
+
+	SQLMock   sqlmock.Sqlmock      // Expectations for the mocked DB (nil when WithTestDB is used)
+	Miniredis *miniredis.Miniredis // In-memory Redis server backing Container.Redis
+	FakeClock *clock.Fake          // Clock installed as Container.Clock
+
+	FakeBroker *events.MemoryBroker // Broker behind Container.Broker
+}
+
+// TestOption customizes a TestContainer
+type TestOption func(*testContainerOptions)
+
+type testContainerOptions struct {
+	config    *config.AppConfig
+	db        *sql.DB
+	startTime time.Time
+	loggers   *logger.Factory
+}
+
+// WithTestConfig replaces the default test configuration
+func WithTestConfig(cfg *config.AppConfig) TestOption {
+	return func(opts *testContainerOptions) {
+		opts.config = cfg
+	}
+}
+
+// WithTestDB uses the given database (e.g. an SQLite handle) instead of sqlmock
+func WithTestDB(db *sql.DB) TestOption {
+	return func(opts *testContainerOptions) {
+		opts.db = db
+	}
+}
+
+// WithTestStartTime sets the instant the fake clock starts at
+func WithTestStartTime(startTime time.Time) TestOption {
+	return func(opts *testContainerOptions) {
+		opts.startTime = startTime
+	}
+}
+
+// WithTestLoggers replaces the default no-op logger factory, e.g. to see logs
+// while debugging a test
+func WithTestLoggers(loggers *logger.Factory) TestOption {
+	return func(opts *testContainerOptions) {
+		opts.loggers = loggers
+	}
+}
+
+// NewTestContainer creates a container backed by sqlmock, miniredis, a no-op
+// logger, no-op metrics, a fake clock and an in-memory broker. Call Close when the test finishes.
+func NewTestContainer(options ...TestOption) (*TestContainer, error) {
+	opts := &testContainerOptions{
+		config:    DefaultTestConfig(),
+		startTime: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		loggers:   logger.NewNopFactory(),
+	}
+	for _, option := range options {
+		option(opts)
+	}
+
+	testContainer := &TestContainer{
+		FakeClock:  clock.NewFake(opts.startTime),
+		FakeBroker: events.NewMemoryBroker(),
+	}
+
+	db := opts.db
+	if db == nil {
+		mockDB, mock, err := sqlmock.New()
+		if err != nil {
+			return nil, fmt.Errorf("failed to create sqlmock: %w", err)
+		}
+		db = mockDB
+		testContainer.SQLMock = mock
+	}
+
+	redisServer, err := miniredis.Run()
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to start miniredis: %w", err)
+	}
+	testContainer.Miniredis = redisServer
+
+	redisClient := redis.NewClient(&redis.Options{Addr: redisServer.Addr()})
+	authz, err := rbac.NewAuthorizer(opts.config.RBAC)
+	if err != nil {
+		redisClient.Close()
+		redisServer.Close()
+		db.Close()
+		return nil, fmt.Errorf("failed to initialize rbac: %w", err)
+	}
+
+	refData, err := newRefDataService(refdata.NewMemoryStore(), refdata.NewLocalNotifier(), testContainer.FakeClock, opts.loggers)
+	if err != nil {
+		redisClient.Close()
+		redisServer.Close()
+		db.Close()
+		return nil, fmt.Errorf("failed to initialize reference data: %w", err)
+	}
+
+	ratesService, err := newRatesService(opts.config.Rates, db, redisClient, refData, testContainer.FakeClock, opts.loggers)
+	if err != nil {
+		redisClient.Close()
+		redisServer.Close()
+		db.Close()
+		return nil, fmt.Errorf("failed to initialize rates: %w", err)
+	}
+	// Conversions use the configured rates directly, so tests need no refresh
+	staticRates, err := rates.NewStaticProvider(opts.config.Rates.Base, opts.config.Rates.Static, testContainer.FakeClock)
+	if err != nil {
+		redisClient.Close()
+		redisServer.Close()
+		db.Close()
+		return nil, fmt.Errorf("failed to initialize rates: %w", err)
+	}
+	fixedRates, err := staticRates.FetchRates(context.Background())
+	if err != nil {
+		redisClient.Close()
+		redisServer.Close()
+		db.Close()
+		return nil, fmt.Errorf("failed to load static rates: %w", err)
+	}
+
+	phoneVerifier, err := phoneverify.NewStaticVerifier(opts.config.PhoneVerify.Static, testContainer.FakeClock)
+	if err != nil {
+		redisClient.Close()
+		redisServer.Close()
+		db.Close()
+		return nil, fmt.Errorf("failed to initialize phone verification: %w", err)
+	}
+
+	encryptor, err := newTestEncryptor()
+	if err != nil {
+		redisClient.Close()
+		redisServer.Close()
+		db.Close()
+		return nil, fmt.Errorf("failed to initialize encryption: %w", err)
+	}
+
+	locales, err := newLocaleDefaults(opts.config.I18n)
+	if err != nil {
+		redisClient.Close()
+		redisServer.Close()
+		db.Close()
+		return nil, fmt.Errorf("failed to initialize i18n defaults: %w", err)
+	}
+
+	otpService, err := newOTPService(opts.config.OTP, redisClient, phoneVerifier, encryptor, locales, testContainer.FakeClock, opts.loggers)
+	if err != nil {
+		redisClient.Close()
+		redisServer.Close()
+		db.Close()
+		return nil, fmt.Errorf("failed to initialize otp: %w", err)
+	}
+
+	erasureService, err := newErasureService(opts.config.Erasure, erasure.NewMemoryAuditStore(), testContainer.FakeClock, opts.loggers)
+	if err != nil {
+		redisClient.Close()
+		redisServer.Close()
+		db.Close()
+		return nil, fmt.Errorf("failed to initialize erasure: %w", err)
+	}
+
+	meter, err := newMeter(opts.config.Metering, redisClient, metering.NewMemoryStore(), testContainer.FakeClock, opts.loggers)
+	if err != nil {
+		redisClient.Close()
+		redisServer.Close()
+		db.Close()
+		return nil, fmt.Errorf("failed to initialize metering: %w", err)
+	}
+
+	blobs := storage.NewMemoryStore(testContainer.FakeClock)
+	docs, err := newDocumentService(opts.config.Documents, blobs)
+	if err != nil {
+		redisClient.Close()
+		redisServer.Close()
+		db.Close()
+		return nil, fmt.Errorf("failed to initialize documents: %w", err)
+	}
+
+	views, err := newViews(opts.config, locales, refData, opts.loggers)
+	if err != nil {
+		redisClient.Close()
+		redisServer.Close()
+		db.Close()
+		return nil, fmt.Errorf("failed to initialize templates: %w", err)
+	}
+
+	schemas := events.NewRegistry()
+	testContainer.Container = &Container{
+		Config:   opts.config,
+		DB:       db,
+		Redis:    redisClient,
+		Logger:   opts.loggers.Root(),
+		Loggers:  opts.loggers,
+		Metrics:  metrics.NewNoopProvider(),
+		Clock:    testContainer.FakeClock,
+		I18n:     locales,
+		Events:   events.NewBus(opts.loggers.Named(logger.NameEvents)),
+		Ledger:   events.NewMemoryLedger(testContainer.FakeClock),
+		Broker:   schemas.Publisher(testContainer.FakeBroker),
+		Schemas:  schemas,
+		Journal:  eventsource.NewMemoryStore(testContainer.FakeClock),
+		Audit:    repository.NewMemoryAuditLog(),
+		Rates:    ratesService,
+		Convert:  rates.NewStaticConverter(fixedRates...),
+		Geo:      geo.NopResolver{},
+		Regions:  regions.NewService(nil, regions.WithClock(testContainer.FakeClock)),
+		Phones:   phoneVerifier,
+		OTP:      otpService,
+		Crypto:   encryptor,
+		Erasure:  erasureService,
+		Consent:  newConsentService(consent.NewMemoryStore(), locales, testContainer.FakeClock, opts.loggers),
+		Metering: meter,
+		Blobs:    blobs,
+		Docs:     docs,
+		Views:    views,
+		Authz:    authz,
+		RefData:  refData,
+		Health:   newHealthMonitor(opts.config.Health, db, redisClient, testContainer.FakeClock, opts.loggers),
+		Jobs:     jobs.NewStore(redisClient, jobs.WithClock(testContainer.FakeClock), jobs.WithTTL(opts.config.Jobs.TTL)),
+	}
+	testContainer.Exports = newExportService(opts.config.Exports, testContainer.Jobs, blobs, testContainer.Events,
+		testContainer.FakeClock, opts.loggers)
+	testContainer.Sagas = newSagaOrchestrator(opts.config.Sagas, saga.NewMemoryStore(), testContainer.Events,
+		testContainer.FakeClock, opts.loggers)
+	testContainer.ReadSide = newProjectionRunner(opts.config.Projections, testContainer.Container,
+		projection.NewMemoryCheckpoints(testContainer.FakeClock))
+	testContainer.Retainer = newRetentionService(opts.config.Retention, testContainer.Container, retention.NewMemoryStore())
+	testContainer.Sunsets, err = newDeprecations(opts.config.Deprecations, testContainer.Metrics.Instruments)
+	if err != nil {
+		testContainer.Container.Close()
+		return nil, fmt.Errorf("failed to initialize deprecations: %w", err)
+	}
+	testContainer.Push, err = newPushService(opts.config.Push, push.NewMemoryStore(), testContainer.Redis, testContainer.Views,
+		testContainer.FakeClock, opts.loggers)
+	if err != nil {
+		testContainer.Container.Close()
+		return nil, fmt.Errorf("failed to initialize push notifications: %w", err)
+	}
+	testContainer.Notify, err = newNotifyRouter(opts.config.Notify, notify.NewMemoryStore(), testContainer.Consent,
+		testContainer.Push, locales, testContainer.FakeClock, opts.loggers)
+	if err != nil {
+		testContainer.Container.Close()
+		return nil, fmt.Errorf("failed to initialize notifications: %w", err)
+	}
+	registerErasers(testContainer.Container)
+
+	return testContainer, nil
+}
+
+// newTestEncryptor encrypts with a random key, so repositories that store
+// encrypted fields work without configuration
+func newTestEncryptor() (*fieldcrypt.Encryptor, error) {
+	key := make([]byte, fieldcrypt.KeySize)
+	if _, err := rand.Read(key); err != nil {
+		return nil, err
+	}
+	provider, err := fieldcrypt.NewStaticKeyProvider("test="+base64.StdEncoding.EncodeToString(key), "")
+	if err != nil {
+		return nil, err
+	}
+	return fieldcrypt.NewEncryptor(context.Background(), provider)
+}
+
+// Close releases the fakes. Unmet sqlmock expectations are not reported here;
+// assert them with SQLMock.ExpectationsWereMet.
+func (tc *TestContainer) Close() error {
+	if tc.SQLMock != nil {
+		tc.SQLMock.ExpectClose()
+	}
+
+	err := tc.Container.Close()
+	tc.Miniredis.Close()
+	return err
+}
+
+// DefaultTestConfig returns a configuration suitable for tests
+func DefaultTestConfig() *config.AppConfig {
+	return &config.AppConfig{
+		Server:   config.ServerConfig{Port: 0, Host: "127.0.0.1"},
+		Database: config.DatabaseConfig{Host: "localhost", Port: 5432, Name: "golang_arch_test", SSLMode: "disable"},
+		Redis:    config.RedisConfig{Host: "localhost", Port: 6379},
+		Log:      config.LogConfig{Level: "debug", Format: "console"},
+		Metrics:  config.MetricsConfig{Exporter: metrics.ExporterNone, Path: "/metrics"},
+		Rates: config.RatesConfig{
+			Provider: "static",
+			Base:     "USD",
+			Static:   map[string]string{"EUR": "0.9221", "GBP": "0.7918", "JPY": "142.35"},
+		},
+		OTP: config.OTPConfig{
+			Secret:      "test-otp-secret",
+			CodeLength:  6,
+			TTL:         5 * time.Minute,
+			MaxAttempts: 5,
+			Cooldown:    time.Minute,
+			SendLimit:   5,
+			SendWindow:  time.Hour,
+			Sender:      "log",
+		},
+		Erasure: config.ErasureConfig{Strategy: "scrub", Secret: "test-erasure-secret"},
+	}
+}
@@ -2,21 +2,175 @@ package bootstrap
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"log"
+	"runtime/debug"
+	"sync"
 	"time"
+
+	"go.uber.org/zap"
+
+	"golang-arch/pkg/logger"
+	"golang-arch/pkg/metrics"
+	"golang-arch/pkg/schedule"
 )
 
+// Job is a background task run by the worker on a schedule
+type Job struct {
+	Name     string
+	Schedule schedule.Schedule
+	Run      func(ctx context.Context) error
+}
+
+// PanicError is the error of a job run that panicked
+type PanicError struct {
+	Job   string
+	Value any
+	Stack []byte
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("job %s panicked: %v", e.Job, e.Value)
+}
+
+// ErrorReporter forwards job failures to an error tracker such as Sentry
+type ErrorReporter interface {
+	Report(ctx context.Context, err error, tags map[string]string)
+}
+
+// WorkerOption configures a Worker
+type WorkerOption func(*Worker)
+
+// WithErrorReporter reports failed and panicked job runs to reporter
+func WithErrorReporter(reporter ErrorReporter) WorkerOption {
+	return func(w *Worker) {
+		w.reporter = reporter
+	}
+}
+
 // Worker represents the background job worker
 type Worker struct {
 	container *Container
 	stopChan  chan struct{}
+	reporter  ErrorReporter
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	jobs   []Job
+	wg     sync.WaitGroup
+
+	breakersMu sync.Mutex
+	breakers   map[string]*jobBreaker
+}
+
+// jobBreaker counts a job's consecutive failures; once they reach the
+// threshold the job is paused until openUntil
+type jobBreaker struct {
+	failures  int
+	openUntil time.Time
 }
 
-// NewWorker creates a new worker instance
-func NewWorker(container *Container) *Worker {
-	return &Worker{
+// NewWorker creates a new worker instance with the built-in jobs registered
+func NewWorker(container *Container, options ...WorkerOption) *Worker {
+	ctx, cancel := context.WithCancel(context.Background())
+	w := &Worker{
 		container: container,
 		stopChan:  make(chan struct{}),
+		ctx:       ctx,
+		cancel:    cancel,
+		breakers:  make(map[string]*jobBreaker),
+	}
+	for _, option := range options {
+		option(w)
+	}
+	w.registerBuiltinJobs()
+	return w
+}
+
+// Register adds a scheduled job; call it before Start
+func (w *Worker) Register(job Job) {
+	w.jobs = append(w.jobs, job)
+}
+
+// Jobs returns the registered scheduled jobs
+func (w *Worker) Jobs() []Job {
+	return w.jobs
+}
+
+// registerBuiltinJobs registers the jobs backed by container services
+func (w *Worker) registerBuiltinJobs() {
+	if w.container.Rates != nil && w.container.Config.Rates.RefreshSchedule != "" {
+		refreshSchedule, err := schedule.Parse(w.container.Config.Rates.RefreshSchedule)
+		if err != nil {
+			w.container.Logger.Error("Exchange-rate refresh job disabled", zap.Error(err))
+		} else {
+			w.Register(Job{Name: "rates_refresh", Schedule: refreshSchedule, Run: w.container.Rates.Refresh})
+		}
+	}
+	if w.container.OTP != nil && w.container.Config.OTP.DeliverySchedule != "" {
+		deliverySchedule, err := schedule.Parse(w.container.Config.OTP.DeliverySchedule)
+		if err != nil {
+			w.container.Logger.Error("Verification code delivery job disabled", zap.Error(err))
+		} else {
+			w.Register(Job{Name: "otp_delivery", Schedule: deliverySchedule, Run: w.container.OTP.Deliver})
+		}
+	}
+	if w.container.Push != nil && w.container.Config.Push.DeliverySchedule != "" {
+		deliverySchedule, err := schedule.Parse(w.container.Config.Push.DeliverySchedule)
+		if err != nil {
+			w.container.Logger.Error("Push delivery job disabled", zap.Error(err))
+		} else {
+			w.Register(Job{Name: "push_delivery", Schedule: deliverySchedule, Run: w.container.Push.Deliver})
+		}
+	}
+	if w.container.Sagas != nil && w.container.Config.Sagas.ResumeSchedule != "" {
+		resumeSchedule, err := schedule.Parse(w.container.Config.Sagas.ResumeSchedule)
+		if err != nil {
+			w.container.Logger.Error("Saga resume job disabled", zap.Error(err))
+		} else {
+			w.Register(Job{Name: "saga_resume", Schedule: resumeSchedule, Run: w.container.Sagas.Resume})
+		}
+	}
+	if w.container.ReadSide != nil && w.container.Config.Projections.Schedule != "" {
+		runSchedule, err := schedule.Parse(w.container.Config.Projections.Schedule)
+		if err != nil {
+			w.container.Logger.Error("Projection job disabled", zap.Error(err))
+		} else {
+			w.Register(Job{Name: "projections", Schedule: runSchedule, Run: w.container.ReadSide.Run})
+		}
+	}
+	if w.container.Retainer != nil && w.container.Config.Retention.Schedule != "" {
+		purgeSchedule, err := schedule.Parse(w.container.Config.Retention.Schedule)
+		if err != nil {
+			w.container.Logger.Error("Retention job disabled", zap.Error(err))
+		} else {
+			w.Register(Job{Name: "retention", Schedule: purgeSchedule, Run: w.container.Retainer.Run})
+		}
+	}
+	if w.container.Ledger != nil && w.container.Config.Events.PruneSchedule != "" {
+		pruneSchedule, err := schedule.Parse(w.container.Config.Events.PruneSchedule)
+		if err != nil {
+			w.container.Logger.Error("Processed message pruning job disabled", zap.Error(err))
+		} else {
+			w.Register(Job{Name: "processed_messages_prune", Schedule: pruneSchedule, Run: w.pruneProcessedMessages})
+		}
+	}
+	if w.container.Health != nil && w.container.Config.Health.CheckSchedule != "" {
+		checkSchedule, err := schedule.Parse(w.container.Config.Health.CheckSchedule)
+		if err != nil {
+			w.container.Logger.Error("Health check recording job disabled", zap.Error(err))
+		} else {
+			w.Register(Job{Name: "health_check", Schedule: checkSchedule, Run: w.container.Health.Record})
+		}
+	}
+	if w.container.Metering != nil && w.container.Config.Metering.FlushSchedule != "" {
+		flushSchedule, err := schedule.Parse(w.container.Config.Metering.FlushSchedule)
+		if err != nil {
+			w.container.Logger.Error("Usage flush job disabled", zap.Error(err))
+		} else {
+			w.Register(Job{Name: "metering_flush", Schedule: flushSchedule, Run: w.container.Metering.Flush})
+		}
 	}
 }
 
@@ -26,6 +180,10 @@ func (w *Worker) Start() error {
 
 	// Start background jobs
 	go w.runBackgroundJobs()
+	for _, job := range w.jobs {
+		w.wg.Add(1)
+		go w.runScheduled(job)
+	}
 
 	// Keep the worker running
 	<-w.stopChan
@@ -36,11 +194,20 @@ func (w *Worker) Start() error {
 func (w *Worker) Shutdown(ctx context.Context) error {
 	w.container.Logger.Info("Shutting down background worker")
 
-	// Signal stop
+	// Signal stop and cancel running jobs
 	close(w.stopChan)
+	w.cancel()
 
-	// Wait for context cancellation or timeout
-	<-ctx.Done()
+	// Wait for scheduled jobs to return, or for the deadline
+	done := make(chan struct{})
+	go func() {
+		w.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-ctx.Done():
+	}
 
 	w.container.Logger.Info("Background worker stopped")
 	return nil
@@ -54,16 +221,158 @@ func (w *Worker) runBackgroundJobs() {
 	for {
 		select {
 		case <-ticker.C:
-			w.processJobs()
+			w.RunJob(Job{Name: "process_jobs", Run: w.processJobs})
+		case <-w.stopChan:
+			return
+		}
+	}
+}
+
+// runScheduled runs job at every activation of its schedule until shutdown.
+// Waiting goes through the container clock so tests can drive it with a fake.
+func (w *Worker) runScheduled(job Job) {
+	defer w.wg.Done()
+
+	clk := w.container.Clock
+	for {
+		now := clk.Now()
+		next := job.Schedule.Next(now)
+		if next.IsZero() {
+			w.container.Loggers.Named(logger.NameWorkerJobs).Warn("Job schedule never fires",
+				zap.String("job", job.Name))
+			return
+		}
+
+		select {
+		case <-clk.After(next.Sub(now)):
+			w.RunJob(job)
 		case <-w.stopChan:
 			return
 		}
 	}
 }
 
+// EnqueueAt runs job once at the given time, e.g. a notification deferred to
+// the recipient's contact window. Past times run immediately; jobs still
+// waiting at shutdown are dropped. job.Schedule is ignored.
+func (w *Worker) EnqueueAt(job Job, at time.Time) {
+	due := w.container.Clock.After(at.Sub(w.container.Clock.Now()))
+	w.wg.Add(1)
+	go func() {
+		defer w.wg.Done()
+
+		select {
+		case <-due:
+			w.RunJob(job)
+		case <-w.stopChan:
+			w.container.Loggers.Named(logger.NameWorkerJobs).Warn("Dropping delayed job at shutdown",
+				zap.String("job", job.Name), zap.Time("run_at", at))
+		}
+	}()
+}
+
+// RunJob runs a job once, recording its duration and outcome. A panic is
+// recovered and treated as a failure, so one job cannot take the worker
+// down. Failures go to the error reporter, and a job failing
+// worker.breaker_threshold times in a row is paused for
+// worker.breaker_cooldown; runs while paused are skipped.
+func (w *Worker) RunJob(job Job) {
+	start := w.container.Clock.Now()
+	jobLogger := w.container.Loggers.Named(logger.NameWorkerJobs).With(zap.String("job", job.Name))
+
+	if until, paused := w.Paused(job.Name); paused {
+		jobLogger.Debug("Skipping paused job", zap.Time("paused_until", until))
+		w.container.Metrics.Instruments.JobsProcessed.Inc(metrics.JobLabels(job.Name, "skipped"))
+		return
+	}
+	jobLogger.Debug("Running scheduled job")
+
+	status := "ok"
+	err := runRecovered(w.ctx, job)
+	var panicked *PanicError
+	switch {
+	case errors.As(err, &panicked):
+		status = "panic"
+		jobLogger.Error("Scheduled job panicked", zap.Any("panic", panicked.Value), zap.ByteString("stack", panicked.Stack))
+	case err != nil:
+		status = "error"
+		jobLogger.Error("Scheduled job failed", zap.Error(err))
+	}
+	if err != nil && w.reporter != nil {
+		w.reporter.Report(w.ctx, err, map[string]string{"job": job.Name, "status": status})
+	}
+	w.recordOutcome(job.Name, err, jobLogger)
+
+	labels := metrics.JobLabels(job.Name, status)
+	w.container.Metrics.Instruments.JobsProcessed.Inc(labels)
+	w.container.Metrics.Instruments.JobDuration.Observe(w.container.Clock.Since(start).Seconds(), labels)
+}
+
+// runRecovered runs job, turning a panic into a *PanicError
+func runRecovered(ctx context.Context, job Job) (err error) {
+	defer func() {
+		if value := recover(); value != nil {
+			err = &PanicError{Job: job.Name, Value: value, Stack: debug.Stack()}
+		}
+	}()
+	return job.Run(ctx)
+}
+
+// Paused reports whether the job's circuit breaker is open, and until when
+func (w *Worker) Paused(name string) (time.Time, bool) {
+	w.breakersMu.Lock()
+	defer w.breakersMu.Unlock()
+	breaker, ok := w.breakers[name]
+	if !ok || !w.container.Clock.Now().Before(breaker.openUntil) {
+		return time.Time{}, false
+	}
+	return breaker.openUntil, true
+}
+
+// Resume closes the job's circuit breaker, e.g. after fixing the cause
+func (w *Worker) Resume(name string) {
+	w.breakersMu.Lock()
+	defer w.breakersMu.Unlock()
+	delete(w.breakers, name)
+	w.container.Metrics.Instruments.JobsPaused.Set(0, metrics.JobLabels(name, ""))
+}
+
+// recordOutcome updates the job's circuit breaker. After a pause the next
+// run is a trial: failing it pauses the job again, succeeding resets it.
+func (w *Worker) recordOutcome(name string, err error, jobLogger *zap.Logger) {
+	cfg := w.container.Config.Worker
+	w.breakersMu.Lock()
+	defer w.breakersMu.Unlock()
+
+	labels := metrics.JobLabels(name, "")
+	if err == nil {
+		if _, ok := w.breakers[name]; ok {
+			delete(w.breakers, name)
+			w.container.Metrics.Instruments.JobsPaused.Set(0, labels)
+		}
+		return
+	}
+	if cfg.BreakerThreshold <= 0 {
+		return
+	}
+
+	breaker, ok := w.breakers[name]
+	if !ok {
+		breaker = &jobBreaker{}
+		w.breakers[name] = breaker
+	}
+	breaker.failures++
+	if breaker.failures >= cfg.BreakerThreshold {
+		breaker.openUntil = w.container.Clock.Now().Add(cfg.BreakerCooldown)
+		w.container.Metrics.Instruments.JobsPaused.Set(1, labels)
+		jobLogger.Error("Pausing job after repeated failures",
+			zap.Int("failures", breaker.failures), zap.Time("paused_until", breaker.openUntil))
+	}
+}
+
 // processJobs processes pending background jobs
-func (w *Worker) processJobs() {
-	w.container.Logger.Debug("Processing background jobs")
+func (w *Worker) processJobs(context.Context) error {
+	w.container.Loggers.Named(logger.NameWorkerJobs).Debug("Processing background jobs")
 
 	// TODO: Implement actual job processing logic
 	// This could include:
@@ -73,4 +382,19 @@ func (w *Worker) processJobs() {
 	// - Syncing with external services
 
 	log.Println("Background jobs processed")
+	return nil
+}
+
+// pruneProcessedMessages forgets the messages idempotent consumers processed
+// longer than events.processed_retention ago
+func (w *Worker) pruneProcessedMessages(ctx context.Context) error {
+	before := w.container.Clock.Now().Add(-w.container.Config.Events.ProcessedRetention)
+	pruned, err := w.container.Ledger.Prune(ctx, before)
+	if err != nil {
+		return err
+	}
+	if pruned > 0 {
+		w.container.Logger.Info("Pruned processed messages", zap.Int64("count", pruned))
+	}
+	return nil
 }
@@ -0,0 +1,45 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"golang-arch/internal/shared/csvmap"
+)
+
+// ContentTypeCSV is the media type of CSV exports
+const ContentTypeCSV = "text/csv; charset=utf-8"
+
+// CSVOptions returns csvmap options for the request's Accept-Language, so
+// exports open correctly in the user's spreadsheet software
+func CSVOptions(c *gin.Context) csvmap.Options {
+	return csvmap.LocaleOptions(c.GetHeader("Accept-Language"))
+}
+
+// CSV sends rows, a slice of structs, as a CSV attachment
+func CSV(c *gin.Context, filename string, rows any, opts csvmap.Options) {
+	data, err := csvmap.Marshal(rows, opts)
+	if err != nil {
+		RespondError(c, err)
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	c.Data(http.StatusOK, ContentTypeCSV, data)
+}
+
+// BindCSV decodes a CSV request body into the slice pointed to by rows. On
+// failure it writes a ValidationFailed response listing every invalid cell,
+// aborts the request and returns false.
+func BindCSV(c *gin.Context, rows any, opts csvmap.Options) bool {
+	err := csvmap.Read(c.Request.Body, rows, opts)
+	if err == nil {
+		return true
+	}
+
+	ValidationFailed(c, "invalid CSV", csvmap.FieldErrors(err))
+	c.Abort()
+	return false
+}
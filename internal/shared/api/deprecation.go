@@ -0,0 +1,161 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"golang-arch/pkg/metrics"
+)
+
+// WarnDeprecated is the code of the warning added to deprecated routes'
+// responses
+const WarnDeprecated = "DEPRECATED_ENDPOINT"
+
+// Warning is a notice about a successful or failed request that clients
+// should act on, e.g. moving off a deprecated route
+type Warning struct {
+	Code         string `json:"code"`
+	Message      string `json:"message"`
+	SunsetDate   string `json:"sunset_date,omitempty"`   // Day the route stops working, e.g. "2025-06-30"
+	MigrationURL string `json:"migration_url,omitempty"` // Where to read how to migrate
+}
+
+// warningsKey holds the warnings of a request in the gin context
+const warningsKey = "api.warnings"
+
+// AddWarning adds a warning to the envelope of the request's response
+func AddWarning(c *gin.Context, warning Warning) {
+	warnings, _ := c.Get(warningsKey)
+	list, _ := warnings.([]Warning)
+	c.Set(warningsKey, append(list, warning))
+}
+
+// warnings returns the warnings added to the request
+func warnings(c *gin.Context) []Warning {
+	value, _ := c.Get(warningsKey)
+	list, _ := value.([]Warning)
+	return list
+}
+
+// Deprecation describes a route on its way out
+type Deprecation struct {
+	Since   time.Time // When the route was deprecated
+	Sunset  time.Time // When it stops working; zero when not scheduled
+	Link    string    // Migration guide, sent as a Link header and in the warning
+	Message string    // Warning text; a generic notice when empty
+}
+
+// Validate checks the deprecation has a date and a sunset after it
+func (d Deprecation) Validate() error {
+	if d.Since.IsZero() {
+		return fmt.Errorf("deprecation date is required")
+	}
+	if !d.Sunset.IsZero() && d.Sunset.Before(d.Since) {
+		return fmt.Errorf("sunset %s is before the deprecation date %s",
+			d.Sunset.Format(time.DateOnly), d.Since.Format(time.DateOnly))
+	}
+	return nil
+}
+
+// warning returns the envelope warning of the deprecation
+func (d Deprecation) warning() Warning {
+	warning := Warning{Code: WarnDeprecated, Message: d.Message, MigrationURL: d.Link}
+	if warning.Message == "" {
+		warning.Message = "This endpoint is deprecated"
+		if !d.Sunset.IsZero() {
+			warning.Message += " and will be removed on " + d.Sunset.UTC().Format(time.DateOnly)
+		}
+	}
+	if !d.Sunset.IsZero() {
+		warning.SunsetDate = d.Sunset.UTC().Format(time.DateOnly)
+	}
+	return warning
+}
+
+// Deprecations marks routes deprecated: their responses carry the
+// Deprecation (RFC 9745), Sunset (RFC 8594) and Link headers and a warning in
+// the envelope, and their use is counted so callers can be chased before the
+// sunset.
+type Deprecations struct {
+	routes  map[string]Deprecation // By method and route pattern
+	counter *metrics.Counter
+}
+
+// DeprecationOption customizes Deprecations
+type DeprecationOption func(*Deprecations)
+
+// WithUsageCounter counts requests to deprecated routes by method and route
+func WithUsageCounter(counter *metrics.Counter) DeprecationOption {
+	return func(d *Deprecations) {
+		d.counter = counter
+	}
+}
+
+// NewDeprecations creates an empty set of deprecated routes
+func NewDeprecations(options ...DeprecationOption) *Deprecations {
+	d := &Deprecations{routes: make(map[string]Deprecation)}
+	for _, option := range options {
+		option(d)
+	}
+	return d
+}
+
+// Mark deprecates the route with method and pattern, e.g. "GET" and
+// "/api/v1/users/:id", for Middleware. Routes registered in code use Route.
+func (d *Deprecations) Mark(method, pattern string, deprecation Deprecation) error {
+	if err := deprecation.Validate(); err != nil {
+		return fmt.Errorf("invalid deprecation of %s %s: %w", method, pattern, err)
+	}
+	d.routes[method+" "+pattern] = deprecation
+	return nil
+}
+
+// Len returns the number of marked routes
+func (d *Deprecations) Len() int {
+	return len(d.routes)
+}
+
+// Middleware applies the deprecations of marked routes. Marking routes
+// after the server starts is not safe.
+func (d *Deprecations) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if deprecation, ok := d.routes[c.Request.Method+" "+c.FullPath()]; ok {
+			d.apply(c, deprecation)
+		}
+		c.Next()
+	}
+}
+
+// Route deprecates the routes it guards, e.g.
+//
+//	group.GET("/users/:id", deprecations.Route(api.Deprecation{Since: since}), h.getUser)
+//
+// It panics on an invalid deprecation, like gin does on an invalid route.
+func (d *Deprecations) Route(deprecation Deprecation) gin.HandlerFunc {
+	if err := deprecation.Validate(); err != nil {
+		panic("api: " + err.Error())
+	}
+	return func(c *gin.Context) {
+		d.apply(c, deprecation)
+		c.Next()
+	}
+}
+
+// apply sets the headers and warning of deprecation and counts the request
+func (d *Deprecations) apply(c *gin.Context, deprecation Deprecation) {
+	c.Header("Deprecation", "@"+strconv.FormatInt(deprecation.Since.Unix(), 10))
+	if !deprecation.Sunset.IsZero() {
+		c.Header("Sunset", deprecation.Sunset.UTC().Format(http.TimeFormat))
+	}
+	if deprecation.Link != "" {
+		c.Writer.Header().Add("Link", "<"+deprecation.Link+`>; rel="deprecation"; type="text/html"`)
+	}
+	AddWarning(c, deprecation.warning())
+	if d.counter != nil {
+		d.counter.Inc(metrics.Labels{metrics.LabelMethod: c.Request.Method, metrics.LabelRoute: c.FullPath()})
+	}
+}
@@ -0,0 +1,124 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"golang-arch/internal/shared/domain/domainerror"
+	"golang-arch/internal/shared/domain/validation"
+)
+
+// kindStatus maps domain error kinds to HTTP status codes and API error codes
+var kindStatus = map[domainerror.Kind]struct {
+	status int
+	code   string
+}{
+	domainerror.NotFound:    {http.StatusNotFound, ErrCodeNotFound},
+	domainerror.Conflict:    {http.StatusConflict, ErrCodeConflict},
+	domainerror.Invalid:     {http.StatusBadRequest, ErrCodeInvalidInput},
+	domainerror.Forbidden:   {http.StatusForbidden, ErrCodeForbidden},
+	domainerror.Unavailable: {http.StatusServiceUnavailable, ErrCodeUnavailable},
+}
+
+// codeStatus maps API error codes to HTTP status codes
+var codeStatus = map[string]int{
+	ErrCodeInvalidInput:     http.StatusBadRequest,
+	ErrCodeValidationFailed: http.StatusBadRequest,
+	ErrCodeNotFound:         http.StatusNotFound,
+	ErrCodeUnauthorized:     http.StatusUnauthorized,
+	ErrCodeForbidden:        http.StatusForbidden,
+	ErrCodeConflict:         http.StatusConflict,
+	ErrCodeUnavailable:      http.StatusServiceUnavailable,
+	ErrCodeTooManyRequests:  http.StatusTooManyRequests,
+	ErrCodeQuotaExceeded:    http.StatusTooManyRequests,
+	ErrCodePayloadTooLarge:  http.StatusRequestEntityTooLarge,
+	ErrCodeDatabaseError:    http.StatusInternalServerError,
+	ErrCodeInternalServer:   http.StatusInternalServerError,
+}
+
+// sentinelKinds maps the package's sentinel errors onto domain kinds
+var sentinelKinds = []struct {
+	err  error
+	kind domainerror.Kind
+}{
+	{ErrNotFound, domainerror.NotFound},
+	{ErrInvalidInput, domainerror.Invalid},
+	{ErrValidationFailed, domainerror.Invalid},
+	{ErrForbidden, domainerror.Forbidden},
+	{context.DeadlineExceeded, domainerror.Unavailable},
+}
+
+// MapError returns the HTTP status and APIError for err. Domain error kinds,
+// validation errors, *APIError values and the package sentinels are
+// recognized; anything else is an internal server error whose message is not
+// exposed to clients.
+func MapError(err error) (int, *APIError) {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		status, ok := codeStatus[apiErr.Code]
+		if !ok {
+			status = http.StatusInternalServerError
+		}
+		return status, apiErr
+	}
+
+	var validationErrs validation.ValidationErrors
+	if errors.As(err, &validationErrs) {
+		return http.StatusBadRequest, NewValidationError(err.Error())
+	}
+
+	kind := domainerror.KindOf(err)
+	if kind == domainerror.Unknown {
+		for _, sentinel := range sentinelKinds {
+			if errors.Is(err, sentinel.err) {
+				kind = sentinel.kind
+				break
+			}
+		}
+	}
+
+	if mapping, ok := kindStatus[kind]; ok {
+		return mapping.status, NewAPIError(mapping.code, err.Error())
+	}
+	return http.StatusInternalServerError, NewInternalServerError(ErrInternalServer.Error())
+}
+
+// RespondError writes the error response for err using MapError
+func RespondError(c *gin.Context, err error) {
+	status, apiErr := MapError(err)
+
+	response := Response{
+		Success: false,
+		Message: http.StatusText(status),
+		Error:   apiErr.Message,
+		Code:    apiErr.Code,
+	}
+	if status == http.StatusBadRequest {
+		response.Errors = validation.FromError(err)
+	}
+
+	Render(c, status, response)
+}
+
+// AbortWithError records err on the context and stops the handler chain;
+// ErrorHandler renders it
+func AbortWithError(c *gin.Context, err error) {
+	_ = c.Error(err)
+	c.Abort()
+}
+
+// ErrorHandler renders the last error recorded with c.Error when the handler
+// chain did not write a response itself
+func ErrorHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		if len(c.Errors) == 0 || c.Writer.Written() {
+			return
+		}
+		RespondError(c, c.Errors.Last().Err)
+	}
+}
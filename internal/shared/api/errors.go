@@ -51,6 +51,11 @@ const (
 	ErrCodeInternalServer   = "INTERNAL_SERVER_ERROR"
 	ErrCodeDatabaseError    = "DATABASE_ERROR"
 	ErrCodeValidationFailed = "VALIDATION_FAILED"
+	ErrCodeConflict         = "CONFLICT"
+	ErrCodeUnavailable      = "SERVICE_UNAVAILABLE"
+	ErrCodeTooManyRequests  = "TOO_MANY_REQUESTS"
+	ErrCodeQuotaExceeded    = "QUOTA_EXCEEDED"
+	ErrCodePayloadTooLarge  = "PAYLOAD_TOO_LARGE"
 )
 
 // Common error constructors
@@ -70,6 +75,14 @@ func NewForbiddenError(message string) *APIError {
 	return NewAPIError(ErrCodeForbidden, message)
 }
 
+func NewTooManyRequestsError(message string) *APIError {
+	return NewAPIError(ErrCodeTooManyRequests, message)
+}
+
+func NewQuotaExceededError(message string) *APIError {
+	return NewAPIError(ErrCodeQuotaExceeded, message)
+}
+
 func NewInternalServerError(message string) *APIError {
 	return NewAPIError(ErrCodeInternalServer, message)
 }
@@ -81,3 +94,11 @@ func NewDatabaseError(message string) *APIError {
 func NewValidationError(message string) *APIError {
 	return NewAPIError(ErrCodeValidationFailed, message)
 }
+
+func NewConflictError(message string) *APIError {
+	return NewAPIError(ErrCodeConflict, message)
+}
+
+func NewUnavailableError(message string) *APIError {
+	return NewAPIError(ErrCodeUnavailable, message)
+}
@@ -0,0 +1,37 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"golang-arch/internal/shared/codec"
+)
+
+// Render writes the response envelope in the format the client accepts: JSON
+// by default, MessagePack or CBOR when the Accept header asks for them
+func Render(c *gin.Context, statusCode int, response Response) {
+	c.Header("Vary", "Accept")
+	if response.Warnings == nil {
+		response.Warnings = warnings(c)
+	}
+
+	responseCodec := codec.ForAccept(c.GetHeader("Accept"))
+	if responseCodec == codec.JSON {
+		c.JSON(statusCode, response)
+		return
+	}
+
+	data, err := responseCodec.Marshal(response)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, Response{
+			Success: false,
+			Message: "failed to encode response",
+			Error:   err.Error(),
+			Code:    ErrCodeInternalServer,
+		})
+		return
+	}
+
+	c.Data(statusCode, responseCodec.ContentType(), data)
+}
@@ -4,6 +4,8 @@ import (
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+
+	"golang-arch/internal/shared/domain/validation"
 )
 
 // Response represents a standard API response
@@ -12,11 +14,16 @@ type Response struct {
 	Message string      `json:"message,omitempty"`
 	Data    interface{} `json:"data,omitempty"`
 	Error   string      `json:"error,omitempty"`
+
+	Code   string                      `json:"code,omitempty"`
+	Errors validation.ValidationErrors `json:"errors,omitempty"`
+
+	Warnings []Warning `json:"warnings,omitempty"` // Added with AddWarning, e.g. for deprecated routes
 }
 
 // Success sends a successful response
 func Success(c *gin.Context, data interface{}, message string) {
-	c.JSON(http.StatusOK, Response{
+	Render(c, http.StatusOK, Response{
 		Success: true,
 		Message: message,
 		Data:    data,
@@ -25,7 +32,7 @@ func Success(c *gin.Context, data interface{}, message string) {
 
 // Created sends a 201 Created response
 func Created(c *gin.Context, data interface{}, message string) {
-	c.JSON(http.StatusCreated, Response{
+	Render(c, http.StatusCreated, Response{
 		Success: true,
 		Message: message,
 		Data:    data,
@@ -39,7 +46,7 @@ func Error(c *gin.Context, statusCode int, message string, err error) {
 		errorMsg = err.Error()
 	}
 
-	c.JSON(statusCode, Response{
+	Render(c, statusCode, Response{
 		Success: false,
 		Message: message,
 		Error:   errorMsg,
@@ -0,0 +1,70 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	playground "github.com/go-playground/validator/v10"
+
+	"golang-arch/internal/shared/domain/validation"
+)
+
+// ValidationFailed sends a 400 Bad Request response listing every field error
+// found in err
+func ValidationFailed(c *gin.Context, message string, err error) {
+	errs := BindingErrors(err)
+
+	Render(c, http.StatusBadRequest, Response{
+		Success: false,
+		Message: message,
+		Error:   errs.Error(),
+		Code:    ErrCodeValidationFailed,
+		Errors:  errs,
+	})
+}
+
+// BindJSON binds the request body into obj and, when obj implements
+// validation.Validator, validates it. On failure it writes a ValidationFailed
+// response, aborts the request and returns false.
+func BindJSON(c *gin.Context, obj any) bool {
+	err := c.ShouldBindJSON(obj)
+	if err == nil {
+		if v, ok := obj.(validation.Validator); ok {
+			err = v.Validate()
+		}
+	}
+	if err == nil {
+		return true
+	}
+
+	ValidationFailed(c, ErrValidationFailed.Error(), err)
+	c.Abort()
+	return false
+}
+
+// BindingErrors converts gin binding errors and domain validation errors into
+// ValidationErrors so both render the same way
+func BindingErrors(err error) validation.ValidationErrors {
+	var fieldErrs playground.ValidationErrors
+	if !errors.As(err, &fieldErrs) {
+		return validation.FromError(err)
+	}
+
+	errs := make(validation.ValidationErrors, 0, len(fieldErrs))
+	for _, fe := range fieldErrs {
+		code := fe.Tag()
+		if code == "required" {
+			code = validation.CodeRequired
+		}
+
+		var params map[string]any
+		if fe.Param() != "" {
+			params = map[string]any{"param": fe.Param()}
+		}
+
+		errs.Add(fe.Field(), code, fmt.Sprintf("%s failed on the '%s' rule", fe.Field(), fe.Tag()), params)
+	}
+	return errs
+}
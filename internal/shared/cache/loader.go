@@ -0,0 +1,50 @@
+package cache
+
+import (
+	"context"
+	"time"
+
+	"golang-arch/pkg/singleflight"
+)
+
+// Loader reads values of type T through a RedisCache, loading misses with
+// a function. Concurrent misses of the same key are collapsed into one load
+// and one Set. Cache errors are not fatal: a failing read is treated as a
+// miss and a failing write only costs a later reload.
+type Loader[T any] struct {
+	cache  *RedisCache
+	ttl    time.Duration
+	flight singleflight.Group[string, T]
+}
+
+// NewLoader creates a loader storing loaded values for ttl; zero keeps them
+// until deleted
+func NewLoader[T any](rc *RedisCache, ttl time.Duration) *Loader[T] {
+	return &Loader[T]{cache: rc, ttl: ttl}
+}
+
+// Get returns the cached value of key, calling load on a miss. Values shared
+// between concurrent callers must not be modified.
+func (l *Loader[T]) Get(ctx context.Context, key string, load func(context.Context) (T, error)) (T, error) {
+	value, _, err := l.flight.Do(ctx, key, func(ctx context.Context) (T, error) {
+		var cached T
+		if found, err := l.cache.Get(ctx, key, &cached); found && err == nil {
+			return cached, nil
+		}
+
+		value, err := load(ctx)
+		if err != nil {
+			return value, err
+		}
+		_ = l.cache.Set(ctx, key, value, l.ttl)
+		return value, nil
+	})
+	return value, err
+}
+
+// Invalidate deletes key from the cache and detaches a load in flight, so
+// the next Get loads a fresh value
+func (l *Loader[T]) Invalidate(ctx context.Context, key string) error {
+	l.flight.Forget(key)
+	return l.cache.Delete(ctx, key)
+}
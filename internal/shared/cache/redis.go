@@ -0,0 +1,93 @@
+// Package cache provides typed caching on top of the container's Redis client.
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"golang-arch/internal/shared/codec"
+)
+
+// RedisCache stores values in Redis encoded with a codec. MessagePack is the
+// default: the i18n value objects encode to compact integer forms there.
+type RedisCache struct {
+	client *redis.Client
+	codec  codec.Codec
+	prefix string
+}
+
+// Option customizes a RedisCache
+type Option func(*RedisCache)
+
+// WithCodec replaces the MessagePack codec
+func WithCodec(c codec.Codec) Option {
+	return func(rc *RedisCache) {
+		rc.codec = c
+	}
+}
+
+// WithPrefix namespaces every key, e.g. "rates:"
+func WithPrefix(prefix string) Option {
+	return func(rc *RedisCache) {
+		rc.prefix = prefix
+	}
+}
+
+// NewRedisCache creates a cache backed by client
+func NewRedisCache(client *redis.Client, options ...Option) *RedisCache {
+	rc := &RedisCache{client: client, codec: codec.MsgPack}
+	for _, option := range options {
+		option(rc)
+	}
+	return rc
+}
+
+// Get decodes the value stored under key into dst and reports whether it was
+// found
+func (rc *RedisCache) Get(ctx context.Context, key string, dst any) (bool, error) {
+	data, err := rc.client.Get(ctx, rc.prefix+key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("cache get %s: %w", key, err)
+	}
+
+	if err := rc.codec.Unmarshal(data, dst); err != nil {
+		return false, fmt.Errorf("cache decode %s: %w", key, err)
+	}
+	return true, nil
+}
+
+// Set stores value under key; a zero ttl keeps it until deleted
+func (rc *RedisCache) Set(ctx context.Context, key string, value any, ttl time.Duration) error {
+	data, err := rc.codec.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("cache encode %s: %w", key, err)
+	}
+
+	if err := rc.client.Set(ctx, rc.prefix+key, data, ttl).Err(); err != nil {
+		return fmt.Errorf("cache set %s: %w", key, err)
+	}
+	return nil
+}
+
+// Delete removes the keys
+func (rc *RedisCache) Delete(ctx context.Context, keys ...string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+
+	prefixed := make([]string, len(keys))
+	for i, key := range keys {
+		prefixed[i] = rc.prefix + key
+	}
+	if err := rc.client.Del(ctx, prefixed...).Err(); err != nil {
+		return fmt.Errorf("cache delete: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,169 @@
+// Package chaos injects latency and errors into HTTP handling, database calls
+// and Redis commands, to exercise timeouts, retries and circuit breakers end
+// to end. Each target has its own fault profile: a share of calls is delayed
+// and a share fails with ErrInjected.
+//
+// It is meant for the dev profile, staging and load tests only: the
+// container refuses to start with chaos enabled in production.
+package chaos
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"time"
+
+	"go.uber.org/zap"
+
+	"golang-arch/pkg/clock"
+	"golang-arch/pkg/metrics"
+)
+
+// ErrInjected is the error of every injected failure
+var ErrInjected = errors.New("chaos: injected fault")
+
+// Targets faults can be injected into
+const (
+	TargetHTTP     = "http"
+	TargetDatabase = "database"
+	TargetRedis    = "redis"
+)
+
+// Fault is the fault profile of a target
+type Fault struct {
+	LatencyRate float64       // Share of calls delayed, from 0 to 1
+	Latency     time.Duration // Delay of a delayed call
+	Jitter      time.Duration // Random extra delay, up to this much
+	ErrorRate   float64       // Share of calls failed, from 0 to 1
+}
+
+// Validate checks the rates are shares and the delays are not negative
+func (f Fault) Validate() error {
+	if f.LatencyRate < 0 || f.LatencyRate > 1 || f.ErrorRate < 0 || f.ErrorRate > 1 {
+		return fmt.Errorf("latency and error rates must be between 0 and 1, got %g and %g", f.LatencyRate, f.ErrorRate)
+	}
+	if f.Latency < 0 || f.Jitter < 0 {
+		return fmt.Errorf("latency and jitter must not be negative, got %s and %s", f.Latency, f.Jitter)
+	}
+	return nil
+}
+
+// active reports whether the profile injects anything
+func (f Fault) active() bool {
+	return f.ErrorRate > 0 || (f.LatencyRate > 0 && f.Latency+f.Jitter > 0)
+}
+
+// Injector decides, call by call, whether to delay or fail it. A nil
+// Injector injects nothing, so callers need not check whether chaos is on.
+type Injector struct {
+	faults  map[string]Fault
+	clock   clock.Clock
+	random  func() float64
+	counter *metrics.Counter
+	logger  *zap.Logger
+}
+
+// Option customizes an Injector
+type Option func(*Injector)
+
+// WithClock sets the clock delays are measured with
+func WithClock(c clock.Clock) Option {
+	return func(i *Injector) {
+		i.clock = c
+	}
+}
+
+// WithRandom sets the source of the uniform values in [0, 1) the rates are
+// compared against, e.g. a fixed value in tests
+func WithRandom(random func() float64) Option {
+	return func(i *Injector) {
+		i.random = random
+	}
+}
+
+// WithFaultsCounter counts injected faults by target and kind
+func WithFaultsCounter(counter *metrics.Counter) Option {
+	return func(i *Injector) {
+		i.counter = counter
+	}
+}
+
+// WithLogger sets the logger injected faults are logged to at debug level
+func WithLogger(logger *zap.Logger) Option {
+	return func(i *Injector) {
+		i.logger = logger
+	}
+}
+
+// NewInjector creates an injector with a fault profile per target. Targets
+// without a profile, or with an empty one, are left alone.
+func NewInjector(faults map[string]Fault, options ...Option) (*Injector, error) {
+	i := &Injector{
+		faults: make(map[string]Fault, len(faults)),
+		clock:  clock.New(),
+		random: rand.Float64,
+		logger: zap.NewNop(),
+	}
+	for target, fault := range faults {
+		if err := fault.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid %s fault: %w", target, err)
+		}
+		if fault.active() {
+			i.faults[target] = fault
+		}
+	}
+	for _, option := range options {
+		option(i)
+	}
+	return i, nil
+}
+
+// Fault returns the profile of target
+func (i *Injector) Fault(target string) Fault {
+	if i == nil {
+		return Fault{}
+	}
+	return i.faults[target]
+}
+
+// Inject delays and fails a call as the target's profile says. op names the
+// call in errors and logs, e.g. "query". It returns an error wrapping
+// ErrInjected when the call is to fail, or the context's error when it ends
+// during the delay.
+func (i *Injector) Inject(ctx context.Context, target, op string) error {
+	if i == nil {
+		return nil
+	}
+	fault, ok := i.faults[target]
+	if !ok {
+		return nil
+	}
+
+	if fault.LatencyRate > 0 && i.random() < fault.LatencyRate {
+		delay := fault.Latency
+		if fault.Jitter > 0 {
+			delay += time.Duration(i.random() * float64(fault.Jitter))
+		}
+		i.record(target, "latency", op)
+		select {
+		case <-i.clock.After(delay):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	if fault.ErrorRate > 0 && i.random() < fault.ErrorRate {
+		i.record(target, "error", op)
+		return fmt.Errorf("%w: %s %s", ErrInjected, target, op)
+	}
+	return nil
+}
+
+// record counts and logs an injected fault
+func (i *Injector) record(target, kind, op string) {
+	if i.counter != nil {
+		i.counter.Inc(metrics.Labels{"target": target, "kind": kind})
+	}
+	i.logger.Debug("Injecting fault", zap.String("target", target), zap.String("kind", kind), zap.String("op", op))
+}
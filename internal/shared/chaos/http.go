@@ -0,0 +1,36 @@
+package chaos
+
+import (
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"golang-arch/internal/shared/api"
+)
+
+// Middleware delays and fails requests as the http profile says. Failed
+// requests are answered 503 with code UNAVAILABLE, the response clients are
+// expected to retry. Paths starting with one of skip, e.g. health checks,
+// are left alone.
+func (i *Injector) Middleware(skip ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		path := c.Request.URL.Path
+		for _, prefix := range skip {
+			if strings.HasPrefix(path, prefix) {
+				c.Next()
+				return
+			}
+		}
+
+		op := c.FullPath()
+		if op == "" {
+			op = "unmatched"
+		}
+		if err := i.Inject(c.Request.Context(), TargetHTTP, c.Request.Method+" "+op); err != nil {
+			api.RespondError(c, api.NewUnavailableError(err.Error()))
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,50 @@
+package chaos
+
+import (
+	"context"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Instrument adds a hook to client that delays and fails commands as the
+// redis profile says. A pipeline or transaction counts as one call. PING is
+// left alone, as database pings are. A nil Injector leaves the client alone.
+func (i *Injector) Instrument(client *redis.Client) {
+	if i == nil {
+		return
+	}
+	client.AddHook(redisHook{injector: i})
+}
+
+type redisHook struct {
+	injector *Injector
+}
+
+func (h redisHook) DialHook(next redis.DialHook) redis.DialHook {
+	return next
+}
+
+func (h redisHook) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		if cmd.Name() == "ping" {
+			return next(ctx, cmd)
+		}
+		if err := h.injector.Inject(ctx, TargetRedis, cmd.Name()); err != nil {
+			cmd.SetErr(err)
+			return err
+		}
+		return next(ctx, cmd)
+	}
+}
+
+func (h redisHook) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		if err := h.injector.Inject(ctx, TargetRedis, "pipeline"); err != nil {
+			for _, cmd := range cmds {
+				cmd.SetErr(err)
+			}
+			return err
+		}
+		return next(ctx, cmds)
+	}
+}
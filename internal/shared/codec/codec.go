@@ -0,0 +1,144 @@
+// Package codec provides the wire encodings shared by HTTP content
+// negotiation and cache serialization: JSON, MessagePack and CBOR.
+//
+// The i18n value objects implement compact MessagePack and CBOR forms, so
+// choosing MsgPack or CBOR for mobile clients or Redis values cuts payload
+// sizes without changing handler or repository code.
+package codec
+
+import (
+	"bytes"
+	"encoding/json"
+	"mime"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+// Content types
+const (
+	ContentTypeJSON    = "application/json"
+	ContentTypeMsgPack = "application/msgpack"
+	ContentTypeCBOR    = "application/cbor"
+)
+
+// Codec marshals values to and from one wire format
+type Codec interface {
+	Name() string
+	ContentType() string
+	Marshal(v any) ([]byte, error)
+	Unmarshal(data []byte, v any) error
+}
+
+type jsonCodec struct{}
+
+func (jsonCodec) Name() string                       { return "json" }
+func (jsonCodec) ContentType() string                { return ContentTypeJSON }
+func (jsonCodec) Marshal(v any) ([]byte, error)      { return json.Marshal(v) }
+func (jsonCodec) Unmarshal(data []byte, v any) error { return json.Unmarshal(data, v) }
+
+type msgpackCodec struct{}
+
+func (msgpackCodec) Name() string        { return "msgpack" }
+func (msgpackCodec) ContentType() string { return ContentTypeMsgPack }
+
+// Marshal encodes v using json tags for field names, so payloads have the same
+// keys as their JSON form, and the smallest integer representation
+func (msgpackCodec) Marshal(v any) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := msgpack.NewEncoder(&buf)
+	enc.SetCustomStructTag("json")
+	enc.UseCompactInts(true)
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Unmarshal decodes data using json tags for field names
+func (msgpackCodec) Unmarshal(data []byte, v any) error {
+	dec := msgpack.NewDecoder(bytes.NewReader(data))
+	dec.SetCustomStructTag("json")
+	return dec.Decode(v)
+}
+
+type cborCodec struct{}
+
+func (cborCodec) Name() string                       { return "cbor" }
+func (cborCodec) ContentType() string                { return ContentTypeCBOR }
+func (cborCodec) Marshal(v any) ([]byte, error)      { return cbor.Marshal(v) }
+func (cborCodec) Unmarshal(data []byte, v any) error { return cbor.Unmarshal(data, v) }
+
+// Built-in codecs
+var (
+	JSON    Codec = jsonCodec{}
+	MsgPack Codec = msgpackCodec{}
+	CBOR    Codec = cborCodec{}
+)
+
+// byMediaType maps accepted media types, including legacy aliases, to codecs
+var byMediaType = map[string]Codec{
+	ContentTypeJSON:           JSON,
+	ContentTypeMsgPack:        MsgPack,
+	"application/x-msgpack":   MsgPack,
+	"application/vnd.msgpack": MsgPack,
+	ContentTypeCBOR:           CBOR,
+}
+
+// ByName returns the codec with the given name ("json", "msgpack", "cbor")
+func ByName(name string) (Codec, bool) {
+	for _, c := range []Codec{JSON, MsgPack, CBOR} {
+		if c.Name() == strings.ToLower(name) {
+			return c, true
+		}
+	}
+	return nil, false
+}
+
+// ForContentType returns the codec for a Content-Type header value
+func ForContentType(contentType string) (Codec, bool) {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return nil, false
+	}
+	c, ok := byMediaType[mediaType]
+	return c, ok
+}
+
+// ForAccept picks the codec for an Accept header, honoring q-values. JSON is
+// returned when the header is empty or names no supported type.
+func ForAccept(accept string) Codec {
+	type candidate struct {
+		codec Codec
+		q     float64
+	}
+
+	var candidates []candidate
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+
+		q := 1.0
+		if value, ok := params["q"]; ok {
+			if parsed, err := strconv.ParseFloat(value, 64); err == nil {
+				q = parsed
+			}
+		}
+		if c, ok := byMediaType[mediaType]; ok && q > 0 {
+			candidates = append(candidates, candidate{codec: c, q: q})
+		}
+	}
+
+	if len(candidates) == 0 {
+		return JSON
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].q > candidates[j].q
+	})
+	return candidates[0].codec
+}
@@ -1,27 +1,77 @@
 package config
 
+import "time"
+
 // AppConfig represents the main application configuration
 type AppConfig struct {
-	Server   ServerConfig   `mapstructure:"server"`
-	Database DatabaseConfig `mapstructure:"database"`
-	Redis    RedisConfig    `mapstructure:"redis"`
-	Log      LogConfig      `mapstructure:"log"`
+	Server      ServerConfig      `mapstructure:"server"`
+	Database    DatabaseConfig    `mapstructure:"database"`
+	Redis       RedisConfig       `mapstructure:"redis"`
+	Log         LogConfig         `mapstructure:"log"`
+	Admin       AdminConfig       `mapstructure:"admin"`
+	Metrics     MetricsConfig     `mapstructure:"metrics"`
+	Rates       RatesConfig       `mapstructure:"rates"`
+	Geo         GeoConfig         `mapstructure:"geo"`
+	Regions     RegionsConfig     `mapstructure:"regions"`
+	PhoneVerify PhoneVerifyConfig `mapstructure:"phone_verify"`
+	OTP         OTPConfig         `mapstructure:"otp"`
+	Encryption  EncryptionConfig  `mapstructure:"encryption"`
+	Erasure     ErasureConfig     `mapstructure:"erasure"`
+	Security    SecurityConfig    `mapstructure:"security"`
+	Metering    MeteringConfig    `mapstructure:"metering"`
+	Worker      WorkerConfig      `mapstructure:"worker"`
+	Startup     StartupConfig     `mapstructure:"startup"`
+	Shutdown    ShutdownConfig    `mapstructure:"shutdown"`
+	Storage     StorageConfig     `mapstructure:"storage"`
+	Documents   DocumentsConfig   `mapstructure:"documents"`
+	Templates   TemplatesConfig   `mapstructure:"templates"`
+	RBAC        RBACConfig        `mapstructure:"rbac"`
+	RefData     RefDataConfig     `mapstructure:"refdata"`
+	I18n        I18nConfig        `mapstructure:"i18n"`
+	I18nAPI     I18nAPIConfig     `mapstructure:"i18n_api"`
+	Health      HealthConfig      `mapstructure:"health"`
+	Jobs        JobsConfig        `mapstructure:"jobs"`
+	Exports     ExportsConfig     `mapstructure:"exports"`
+	Notify      NotifyConfig      `mapstructure:"notifications"`
+	Push        PushConfig        `mapstructure:"push"`
+	Sagas       SagaConfig        `mapstructure:"sagas"`
+	Events      EventsConfig      `mapstructure:"events"`
+	Projections ProjectionsConfig `mapstructure:"projections"`
+	Retention   RetentionConfig   `mapstructure:"retention"`
+	Chaos       ChaosConfig       `mapstructure:"chaos"`
+	LoadShed    LoadShedConfig    `mapstructure:"load_shedding"`
+
+	Deprecations []DeprecationConfig `mapstructure:"deprecations"`
 }
 
 // ServerConfig holds server-related configuration
 type ServerConfig struct {
-	Port int    `mapstructure:"port"`
-	Host string `mapstructure:"host"`
+	Port              int      `mapstructure:"port"`
+	Host              string   `mapstructure:"host"`
+	TrustedProxies    []string `mapstructure:"trusted_proxies"`    // IPs or CIDRs whose forwarding headers are believed; empty trusts none
+	RemoteIPHeaders   []string `mapstructure:"remote_ip_headers"`  // Headers carrying the client IP, read from trusted proxies in order
+	TrustedPlatform   string   `mapstructure:"trusted_platform"`   // cloudflare, google_app_engine, fly_io or a header set by the platform
+	H2C               bool     `mapstructure:"h2c"`                // Accept HTTP/2 without TLS, e.g. behind an L4 load balancer
+	GRPC              bool     `mapstructure:"grpc"`               // Serve the registered gRPC server on the HTTP port
+	Socket            string   `mapstructure:"socket"`             // Unix socket path to listen on instead of the TCP port
+	SocketMode        string   `mapstructure:"socket_mode"`        // Octal permissions of the socket file, e.g. "0660"
+	SystemdActivation bool     `mapstructure:"systemd_activation"` // Use the socket passed by systemd (LISTEN_FDS) when present
+	SystemdName       string   `mapstructure:"systemd_name"`       // FileDescriptorName of the socket to use when systemd passes several
+	ReusePort         bool     `mapstructure:"reuse_port"`         // Set SO_REUSEPORT so the next release can bind the port while this one drains
 }
 
 // DatabaseConfig holds database connection configuration
 type DatabaseConfig struct {
-	Host     string `mapstructure:"host"`
-	Port     int    `mapstructure:"port"`
-	Name     string `mapstructure:"name"`
-	User     string `mapstructure:"user"`
-	Password string `mapstructure:"password"`
-	SSLMode  string `mapstructure:"ssl_mode"`
+	Host             string        `mapstructure:"host"`
+	Port             int           `mapstructure:"port"`
+	Name             string        `mapstructure:"name"`
+	User             string        `mapstructure:"user"`
+	Password         string        `mapstructure:"password"`
+	SSLMode          string        `mapstructure:"ssl_mode"`
+	QueryTimeout     time.Duration `mapstructure:"query_timeout"`     // Deadline of each API request's queries; 0 disables
+	StatementTimeout time.Duration `mapstructure:"statement_timeout"` // Postgres statement_timeout of every connection; 0 disables
+	TimeZone         string        `mapstructure:"timezone"`          // Session TimeZone of every connection; empty keeps the server's
+	Locale           string        `mapstructure:"locale"`            // lc_monetary, lc_numeric and lc_time of every connection; empty keeps the server's
 }
 
 // RedisConfig holds Redis connection configuration
@@ -36,4 +86,402 @@ type RedisConfig struct {
 type LogConfig struct {
 	Level  string `mapstructure:"level"`
 	Format string `mapstructure:"format"`
+	// Levels overrides the level of named loggers, e.g. "worker.jobs=debug,i18n=warn"
+	Levels string `mapstructure:"levels"`
+}
+
+// AdminConfig holds the diagnostics (pprof/expvar) admin server configuration
+type AdminConfig struct {
+	Enabled bool   `mapstructure:"enabled"`
+	Host    string `mapstructure:"host"`
+	Port    int    `mapstructure:"port"`
+	Token   string `mapstructure:"token"`
+	DumpDir string `mapstructure:"dump_dir"`
+}
+
+// MetricsConfig holds metrics export configuration
+type MetricsConfig struct {
+	Exporter       string        `mapstructure:"exporter"` // prometheus, otlp or none
+	Path           string        `mapstructure:"path"`     // Prometheus scrape path
+	OTLPEndpoint   string        `mapstructure:"otlp_endpoint"`
+	OTLPInsecure   bool          `mapstructure:"otlp_insecure"`
+	ExportInterval time.Duration `mapstructure:"export_interval"`
+	Service        string        `mapstructure:"service"`     // "service" label of every series
+	Environment    string        `mapstructure:"environment"` // "env" label, e.g. production or staging
+	Version        string        `mapstructure:"version"`     // "version" label; empty uses the build's VCS revision
+}
+
+// RatesConfig holds exchange-rate refresh configuration
+type RatesConfig struct {
+	Provider        string            `mapstructure:"provider"`         // Rate provider: static, ecb or openexchangerates
+	Base            string            `mapstructure:"base"`             // Currency the provider quotes against
+	RefreshSchedule string            `mapstructure:"refresh_schedule"` // Cron expression; empty disables the refresh job
+	CacheTTL        time.Duration     `mapstructure:"cache_ttl"`        // Lifetime of the current rates in Redis
+	MaxAge          time.Duration     `mapstructure:"max_age"`          // Rates older than this are reported as stale
+	Static          map[string]string `mapstructure:"static"`           // Quote currency to decimal rate, for the static provider
+	ECBURL          string            `mapstructure:"ecb_url"`          // Daily reference rates feed, for the ecb provider
+	OXRURL          string            `mapstructure:"oxr_url"`          // API root, for the openexchangerates provider
+	OXRAppID        string            `mapstructure:"oxr_app_id"`       // App ID, for the openexchangerates provider
+	FetchTimeout    time.Duration     `mapstructure:"fetch_timeout"`    // Bound on each request of the ecb and openexchangerates providers
+}
+
+// GeoConfig holds client IP geolocation configuration
+type GeoConfig struct {
+	DatabasePath    string `mapstructure:"database_path"`    // MaxMind .mmdb file; empty disables lookups
+	DefaultCountry  string `mapstructure:"default_country"`  // Used when the client IP cannot be resolved
+	DefaultTimezone string `mapstructure:"default_timezone"` // IANA identifier used when none can be derived; empty uses i18n.default_timezone
+	DefaultLocale   string `mapstructure:"default_locale"`   // BCP 47 tag used when none can be derived; empty uses i18n.default_locale
+}
+
+// RegionsConfig holds region (ISO 3166-2 subdivision) metadata configuration
+type RegionsConfig struct {
+	Provider         string        `mapstructure:"provider"`          // Remote source for countries without embedded data: none or geonames
+	GeoNamesURL      string        `mapstructure:"geonames_url"`      // GeoNames web service base URL
+	GeoNamesUsername string        `mapstructure:"geonames_username"` // GeoNames account name
+	CacheTTL         time.Duration `mapstructure:"cache_ttl"`         // Lifetime of remotely fetched subdivisions
+	MinInterval      time.Duration `mapstructure:"min_interval"`      // Minimum time between remote requests
+}
+
+// PhoneVerifyConfig holds phone number reachability lookup configuration
+type PhoneVerifyConfig struct {
+	Provider  string            `mapstructure:"provider"`   // Lookup provider: static or hlrlookups
+	URL       string            `mapstructure:"url"`        // Provider API base URL
+	APIKey    string            `mapstructure:"api_key"`    // Provider credentials
+	APISecret string            `mapstructure:"api_secret"` // Provider credentials
+	Secret    string            `mapstructure:"secret"`     // HMAC key of the cached numbers, shared by all instances
+	CacheTTL  time.Duration     `mapstructure:"cache_ttl"`  // Lifetime of reachable and disconnected results
+	RetryTTL  time.Duration     `mapstructure:"retry_ttl"`  // Lifetime of absent and unknown results
+	Static    map[string]string `mapstructure:"static"`     // E.164 number to status, for the static provider
+}
+
+// OTPConfig holds one-time verification code configuration
+type OTPConfig struct {
+	Secret           string        `mapstructure:"secret"`            // HMAC key for stored codes, shared by all instances
+	CodeLength       int           `mapstructure:"code_length"`       // Digits per code
+	TTL              time.Duration `mapstructure:"ttl"`               // How long a code is accepted
+	MaxAttempts      int           `mapstructure:"max_attempts"`      // Wrong guesses before a code is invalidated
+	Cooldown         time.Duration `mapstructure:"cooldown"`          // Minimum time between codes per recipient
+	SendLimit        int           `mapstructure:"send_limit"`        // Codes per recipient within send_window
+	SendWindow       time.Duration `mapstructure:"send_window"`       // Rate-limit window
+	Sender           string        `mapstructure:"sender"`            // Delivery backend: log
+	DeliverySchedule string        `mapstructure:"delivery_schedule"` // Worker schedule draining the outbox
+}
+
+// EncryptionConfig holds field-level encryption keys
+type EncryptionConfig struct {
+	Provider       string        `mapstructure:"provider"`        // none, static or vault
+	Keys           string        `mapstructure:"keys"`            // Comma-separated "id=base64key" list of 32-byte keys, for the static provider
+	PrimaryKey     string        `mapstructure:"primary_key"`     // Key that seals new values; defaults to the last ID in sort order
+	ReloadInterval time.Duration `mapstructure:"reload_interval"` // How often keys are fetched again to pick up rotations; 0 loads them once
+	Vault          VaultConfig   `mapstructure:"vault"`
+}
+
+// VaultConfig locates the encryption keys in a HashiCorp Vault KV v2 secret
+type VaultConfig struct {
+	Address string        `mapstructure:"address"` // e.g. https://vault.internal:8200
+	Token   string        `mapstructure:"token"`   // Token with read access to the secret
+	Mount   string        `mapstructure:"mount"`   // KV v2 secrets engine mount
+	Path    string        `mapstructure:"path"`    // Secret holding "keys" and optionally "primary_key"
+	Timeout time.Duration `mapstructure:"timeout"` // Bound on each read
+}
+
+// ErasureConfig holds right-to-be-forgotten configuration
+type ErasureConfig struct {
+	Strategy string `mapstructure:"strategy"` // scrub or pseudonymize
+	Secret   string `mapstructure:"secret"`   // Key of pseudonyms, shared by all instances
+}
+
+// SecurityConfig holds HTTP security configuration
+type SecurityConfig struct {
+	Headers SecurityHeadersConfig `mapstructure:"headers"`
+}
+
+// SecurityHeadersConfig holds the security headers sent with every response
+type SecurityHeadersConfig struct {
+	Enabled                   bool                   `mapstructure:"enabled"`
+	HSTSMaxAge                time.Duration          `mapstructure:"hsts_max_age"` // 0 disables HSTS; only sent over HTTPS
+	HSTSIncludeSubdomains     bool                   `mapstructure:"hsts_include_subdomains"`
+	HSTSPreload               bool                   `mapstructure:"hsts_preload"`
+	ContentTypeNosniff        bool                   `mapstructure:"content_type_nosniff"`
+	FrameOptions              string                 `mapstructure:"frame_options"` // DENY or SAMEORIGIN; empty omits the header
+	ReferrerPolicy            string                 `mapstructure:"referrer_policy"`
+	ContentSecurityPolicy     string                 `mapstructure:"content_security_policy"`
+	CSPReportOnly             bool                   `mapstructure:"csp_report_only"` // Report violations without enforcing
+	CrossOriginOpenerPolicy   string                 `mapstructure:"cross_origin_opener_policy"`
+	CrossOriginResourcePolicy string                 `mapstructure:"cross_origin_resource_policy"`
+	PermissionsPolicy         string                 `mapstructure:"permissions_policy"`
+	Routes                    []SecurityHeadersRoute `mapstructure:"routes"` // Per-path overrides, longest prefix wins
+}
+
+// SecurityHeadersRoute overrides headers for paths starting with PathPrefix;
+// unset fields keep the global value and empty strings omit the header
+type SecurityHeadersRoute struct {
+	PathPrefix                string  `mapstructure:"path_prefix"`
+	FrameOptions              *string `mapstructure:"frame_options"`
+	ReferrerPolicy            *string `mapstructure:"referrer_policy"`
+	ContentSecurityPolicy     *string `mapstructure:"content_security_policy"`
+	CSPReportOnly             *bool   `mapstructure:"csp_report_only"`
+	CrossOriginResourcePolicy *string `mapstructure:"cross_origin_resource_policy"`
+}
+
+// StorageConfig holds object storage configuration for exports and
+// generated documents
+type StorageConfig struct {
+	Provider      string `mapstructure:"provider"`        // local, s3 or gcs
+	Bucket        string `mapstructure:"bucket"`          // Bucket of the s3 and gcs providers
+	Region        string `mapstructure:"region"`          // S3 signing region
+	Endpoint      string `mapstructure:"endpoint"`        // S3-compatible endpoint (MinIO, R2); empty for AWS
+	PathStyle     bool   `mapstructure:"path_style"`      // Address buckets as endpoint/bucket, as MinIO expects
+	AccessKey     string `mapstructure:"access_key"`      // AWS access key ID or GCS HMAC key ID
+	SecretKey     string `mapstructure:"secret_key"`      // AWS secret access key or GCS HMAC secret
+	LocalDir      string `mapstructure:"local_dir"`       // Root directory of the local provider
+	PublicURL     string `mapstructure:"public_url"`      // Base URL of this API, for the local provider's presigned URLs
+	URLSecret     string `mapstructure:"url_secret"`      // HMAC key of the local provider's presigned URLs
+	MaxUploadSize int64  `mapstructure:"max_upload_size"` // Largest body in bytes of a presigned PUT to the local provider
+}
+
+// DocumentsConfig holds how documents such as invoices are converted to PDF
+type DocumentsConfig struct {
+	PDFProvider  string        `mapstructure:"pdf_provider"`  // none, gotenberg or command
+	GotenbergURL string        `mapstructure:"gotenberg_url"` // Base URL of a Gotenberg service, e.g. http://gotenberg:3000
+	Command      []string      `mapstructure:"command"`       // Reads HTML on stdin and writes PDF to stdout, e.g. [wkhtmltopdf, --quiet, -, -]
+	Timeout      time.Duration `mapstructure:"timeout"`       // Limit of one conversion
+}
+
+// TemplatesConfig holds where server-rendered pages and emails are loaded from
+type TemplatesConfig struct {
+	Dir string `mapstructure:"dir"` // Layouts, pages, emails, locales and assets on top of the built-in ones; empty uses only those
+}
+
+// RBACConfig holds the bearer tokens of API principals and the permissions
+// of their roles
+type RBACConfig struct {
+	Principals []PrincipalConfig   `mapstructure:"principals"`
+	Roles      map[string][]string `mapstructure:"roles"` // Permissions granted by each role, e.g. "i18n:write"
+}
+
+// PrincipalConfig is one token holder
+type PrincipalConfig struct {
+	Name  string   `mapstructure:"name"`  // Recorded as the author of changes
+	Token string   `mapstructure:"token"` // Sent as "Authorization: Bearer <token>"
+	Roles []string `mapstructure:"roles"`
+}
+
+// RefDataConfig holds the i18n reference data administration settings
+type RefDataConfig struct {
+	Channel string `mapstructure:"channel"` // Postgres NOTIFY channel that invalidates the caches of every instance
+}
+
+// I18nConfig holds the application-wide locale defaults, the fallbacks of
+// middleware and formatters when nothing is known about the reader
+type I18nConfig struct {
+	DefaultLocale     string   `mapstructure:"default_locale"`     // BCP 47 tag
+	DefaultTimezone   string   `mapstructure:"default_timezone"`   // IANA identifier
+	DefaultCurrency   string   `mapstructure:"default_currency"`   // ISO 4217 code
+	SupportedLocales  []string `mapstructure:"supported_locales"`  // Locales pages and emails are negotiated to; empty accepts any
+	StrictMoneyJSON   bool     `mapstructure:"strict_money_json"`  // Reject Money payloads whose amount is not in minor units
+	NonNegativeMoney  bool     `mapstructure:"non_negative_money"` // Reject negative Money amounts in every constructor
+	AllowedCurrencies []string `mapstructure:"allowed_currencies"` // Currencies Money may be in; empty allows every supported one
+
+	Locales []I18nLocaleDefaults `mapstructure:"locales"` // Timezone and currency by locale
+	Tenants []I18nTenantDefaults `mapstructure:"tenants"` // Defaults by tenant, ahead of those by locale
+}
+
+// I18nLocaleDefaults holds the defaults of readers whose locale is known but
+// not their timezone or currency
+type I18nLocaleDefaults struct {
+	Locale   string `mapstructure:"locale"` // BCP 47 tag, or a language for all its regions
+	Timezone string `mapstructure:"timezone"`
+	Currency string `mapstructure:"currency"`
+}
+
+// I18nTenantDefaults holds the defaults of a tenant's readers; empty values
+// follow the locale and application defaults
+type I18nTenantDefaults struct {
+	ID       string `mapstructure:"id"` // As set with metering.SetConsumer
+	Locale   string `mapstructure:"locale"`
+	Timezone string `mapstructure:"timezone"`
+	Currency string `mapstructure:"currency"`
+}
+
+// I18nAPIConfig holds the settings of the public /api/v1/i18n endpoints
+type I18nAPIConfig struct {
+	ConvertMaxAge     time.Duration `mapstructure:"convert_max_age"`     // Cache-Control max-age of conversions; 0 sends none
+	ConvertRateLimit  int           `mapstructure:"convert_rate_limit"`  // Conversions per API key, or client IP, per window; 0 is unlimited
+	ConvertRateWindow time.Duration `mapstructure:"convert_rate_window"` // Window of convert_rate_limit
+	CatalogCacheTTL   time.Duration `mapstructure:"catalog_cache_ttl"`   // How long timezone and currency searches are served from memory; 0 disables
+	CatalogCacheStale time.Duration `mapstructure:"catalog_cache_stale"` // How long past the TTL they are served while refreshed in the background
+	CatalogCacheSize  int           `mapstructure:"catalog_cache_size"`  // Cached responses kept, least recently used evicted first
+}
+
+// HealthConfig holds the dependency checks behind /ready and /status
+type HealthConfig struct {
+	CheckSchedule string        `mapstructure:"check_schedule"` // Worker schedule recording the checks for /status; empty records nothing
+	Timeout       time.Duration `mapstructure:"timeout"`        // Bound on each probe
+	HistorySize   int           `mapstructure:"history_size"`   // Results kept per check
+}
+
+// JobsConfig holds the asynchronous job records behind /api/v1/jobs
+type JobsConfig struct {
+	TTL     time.Duration `mapstructure:"ttl"`      // How long records are kept after their last update
+	MaxWait time.Duration `mapstructure:"max_wait"` // Longest long-poll, and longest silence in an event stream
+}
+
+// ExportsConfig holds how query results are exported to files
+type ExportsConfig struct {
+	Concurrency      int           `mapstructure:"concurrency"`       // Exports running at once per instance; later ones wait
+	URLTTL           time.Duration `mapstructure:"url_ttl"`           // Lifetime of the download URL in a finished export's job
+	ProgressInterval int64         `mapstructure:"progress_interval"` // Rows between two progress updates of the job
+}
+
+// NotifyConfig holds how notifications are routed to recipients
+type NotifyConfig struct {
+	DefaultChannels []string      `mapstructure:"default_channels"` // Tried in order for recipients without preferences
+	WebhookSecret   string        `mapstructure:"webhook_secret"`   // HMAC key of the webhook signature header; empty sends none
+	WebhookTimeout  time.Duration `mapstructure:"webhook_timeout"`  // Bound on one webhook request
+}
+
+// PushConfig holds push notification provider configuration
+type PushConfig struct {
+	FCM              FCMConfig     `mapstructure:"fcm"`
+	APNs             APNsConfig    `mapstructure:"apns"`
+	MaxAttempts      int           `mapstructure:"max_attempts"`      // Tries per delivery before it is dropped
+	RetryBackoff     time.Duration `mapstructure:"retry_backoff"`     // Wait before the first retry, doubled for each further one
+	DeliverySchedule string        `mapstructure:"delivery_schedule"` // Worker schedule draining the outbox
+}
+
+// FCMConfig holds Firebase Cloud Messaging configuration, used for Android
+// and web devices
+type FCMConfig struct {
+	CredentialsFile string `mapstructure:"credentials_file"` // Service account JSON key; empty logs pushes instead
+	URL             string `mapstructure:"url"`              // API base URL
+}
+
+// APNsConfig holds Apple Push Notification service configuration, used for
+// iOS devices
+type APNsConfig struct {
+	KeyFile string `mapstructure:"key_file"` // .p8 signing key; empty logs pushes instead
+	KeyID   string `mapstructure:"key_id"`   // ID of the signing key
+	TeamID  string `mapstructure:"team_id"`  // Apple developer team ID
+	Topic   string `mapstructure:"topic"`    // The app's bundle ID
+	Sandbox bool   `mapstructure:"sandbox"`  // Use the development environment
+}
+
+// SagaConfig holds saga orchestration configuration
+type SagaConfig struct {
+	MaxAttempts    int           `mapstructure:"max_attempts"`    // Tries per step or compensation
+	RetryBackoff   time.Duration `mapstructure:"retry_backoff"`   // Wait before the first retry, doubled for each further one
+	Lease          time.Duration `mapstructure:"lease"`           // How long a runner owns an instance before others resume it
+	ResumeSchedule string        `mapstructure:"resume_schedule"` // Worker schedule resuming due and abandoned instances
+}
+
+// EventsConfig holds event consumer configuration
+type EventsConfig struct {
+	ProcessedRetention time.Duration `mapstructure:"processed_retention"` // How long processed message IDs are kept
+	PruneSchedule      string        `mapstructure:"prune_schedule"`      // Worker schedule pruning the processed-message ledger
+}
+
+// ProjectionsConfig holds read-model projection configuration
+type ProjectionsConfig struct {
+	Schedule  string `mapstructure:"schedule"`   // Worker schedule feeding new events to projections
+	BatchSize int    `mapstructure:"batch_size"` // Events read and checkpointed at a time
+}
+
+// RetentionConfig holds data retention configuration
+type RetentionConfig struct {
+	Schedule      string                   `mapstructure:"schedule"`       // Worker schedule purging expired rows
+	BatchSize     int                      `mapstructure:"batch_size"`     // Rows archived and deleted at a time
+	ArchivePrefix string                   `mapstructure:"archive_prefix"` // Blob key prefix of archived rows
+	Policies      map[string]time.Duration `mapstructure:"policies"`       // Max age by policy name, replacing its default; 0 keeps rows forever
+}
+
+// ChaosConfig holds fault injection for resilience testing. It is refused
+// unless metrics.environment is dev, development, local, test or staging.
+type ChaosConfig struct {
+	Enabled   bool        `mapstructure:"enabled"`
+	SkipPaths []string    `mapstructure:"skip_paths"` // HTTP path prefixes left alone, e.g. health checks
+	HTTP      FaultConfig `mapstructure:"http"`       // Requests; failures answer 503
+	Database  FaultConfig `mapstructure:"database"`   // Queries, statements and transactions; pings are left alone
+	Redis     FaultConfig `mapstructure:"redis"`      // Commands and pipelines
+}
+
+// FaultConfig is the fault profile of one target
+type FaultConfig struct {
+	LatencyRate float64       `mapstructure:"latency_rate"` // Share of calls delayed, from 0 to 1
+	Latency     time.Duration `mapstructure:"latency"`      // Delay of a delayed call
+	Jitter      time.Duration `mapstructure:"jitter"`       // Random extra delay, up to this much
+	ErrorRate   float64       `mapstructure:"error_rate"`   // Share of calls failed, from 0 to 1
+}
+
+// LoadShedConfig holds the cap on requests handled at once, past which
+// requests queue briefly and are then answered 503
+type LoadShedConfig struct {
+	Enabled       bool          `mapstructure:"enabled"`
+	MaxInFlight   int           `mapstructure:"max_in_flight"`  // Requests handled at once; the adaptive limit never exceeds it
+	MinInFlight   int           `mapstructure:"min_in_flight"`  // Floor of the adaptive limit
+	MaxQueue      int           `mapstructure:"max_queue"`      // Requests waiting for a slot; 0 sheds as soon as the limit is reached
+	QueueTimeout  time.Duration `mapstructure:"queue_timeout"`  // Longest wait for a slot
+	TargetLatency time.Duration `mapstructure:"target_latency"` // Latency the limit adapts to; 0 keeps it at max_in_flight
+	RetryAfter    time.Duration `mapstructure:"retry_after"`    // Retry-After of shed requests
+	PriorityPaths []string      `mapstructure:"priority_paths"` // HTTP path prefixes that bypass the limit, e.g. health checks
+}
+
+// DeprecationConfig marks one route deprecated without a code change
+type DeprecationConfig struct {
+	Method  string `mapstructure:"method"`
+	Path    string `mapstructure:"path"`    // Route pattern, e.g. "/api/v1/users/:id"
+	Since   string `mapstructure:"since"`   // Date of the deprecation, e.g. "2025-01-31"
+	Sunset  string `mapstructure:"sunset"`  // Date of the removal; empty when not scheduled
+	Link    string `mapstructure:"link"`    // Migration guide
+	Message string `mapstructure:"message"` // Warning text; a generic notice when empty
+}
+
+// StartupConfig holds how startup waits for the database and Redis
+type StartupConfig struct {
+	WaitTimeout  time.Duration `mapstructure:"wait_timeout"`  // How long to retry an unreachable dependency; 0 fails on the first error
+	RetryInitial time.Duration `mapstructure:"retry_initial"` // First delay between attempts, doubled after each failure
+	RetryMax     time.Duration `mapstructure:"retry_max"`     // Cap on the delay between attempts
+	Degraded     bool          `mapstructure:"degraded"`      // Start anyway once the wait is over; /ready reports the outage
+}
+
+// ShutdownConfig holds how processes stop during a rolling deploy
+type ShutdownConfig struct {
+	DrainDelay time.Duration `mapstructure:"drain_delay"` // How long /ready fails before the listener closes, for load balancers to stop routing
+	Timeout    time.Duration `mapstructure:"timeout"`     // Wait for in-flight requests and jobs before cancelling them
+}
+
+// WorkerConfig holds background worker configuration
+type WorkerConfig struct {
+	BreakerThreshold int           `mapstructure:"breaker_threshold"` // Consecutive failures that pause a job; 0 never pauses
+	BreakerCooldown  time.Duration `mapstructure:"breaker_cooldown"`  // How long a tripped job stays paused before a trial run
+}
+
+// MeteringConfig holds API usage metering and plan quota configuration
+type MeteringConfig struct {
+	Enabled       bool                    `mapstructure:"enabled"`
+	Header        string                  `mapstructure:"header"`         // Request header carrying the API key
+	FlushSchedule string                  `mapstructure:"flush_schedule"` // Worker schedule copying Redis counters to Postgres
+	DefaultPlan   string                  `mapstructure:"default_plan"`   // Plan of SetConsumer consumers not listed below; API keys must be listed
+	AnonymousPlan string                  `mapstructure:"anonymous_plan"` // Plan shared by all requests without an API key
+	Plans         map[string]MeteringPlan `mapstructure:"plans"`
+	Consumers     []MeteringConsumerPlan  `mapstructure:"consumers"`
+}
+
+// MeteringPlan holds the quotas of one plan
+type MeteringPlan struct {
+	Period    string                  `mapstructure:"period"`    // day or month (UTC)
+	Requests  int64                   `mapstructure:"requests"`  // Per period across all endpoints; 0 is unlimited
+	Endpoints []MeteringEndpointQuota `mapstructure:"endpoints"` // Per-endpoint quotas
+}
+
+// MeteringEndpointQuota limits one route per period
+type MeteringEndpointQuota struct {
+	Route    string `mapstructure:"route"` // "METHOD /path" with gin parameters, e.g. "POST /api/v1/otp/request"
+	Requests int64  `mapstructure:"requests"`
+}
+
+// MeteringConsumerPlan assigns a plan to a consumer ID
+type MeteringConsumerPlan struct {
+	ID   string `mapstructure:"id"`
+	Plan string `mapstructure:"plan"`
 }
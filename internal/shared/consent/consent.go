@@ -0,0 +1,74 @@
+// Package consent records which data-processing purposes each subject has
+// agreed to, per contact channel, and lets jobs and handlers check that
+// consent before contacting someone.
+package consent
+
+import (
+	"fmt"
+	"regexp"
+
+	i18n "golang-arch/internal/shared/domain/internationalization"
+	"golang-arch/internal/shared/domain/validation"
+)
+
+// Purpose is what personal data is processed for, e.g. "marketing"
+type Purpose string
+
+// Common purposes; applications may define their own
+const (
+	PurposeMarketing      Purpose = "marketing"
+	PurposeProductUpdates Purpose = "product_updates"
+	PurposeAnalytics      Purpose = "analytics"
+)
+
+// purposePattern keeps purposes usable as path segments and keys
+var purposePattern = regexp.MustCompile(`^[a-z0-9_]{1,64}$`)
+
+// Validate checks that the purpose is a lower-case identifier
+func (p Purpose) Validate() error {
+	if !purposePattern.MatchString(string(p)) {
+		var errs validation.ValidationErrors
+		errs.Add("purpose", validation.CodeInvalidFormat, "purpose must be 1-64 lower-case letters, digits or underscores", nil)
+		return errs.Err()
+	}
+	return nil
+}
+
+// Channel is how a subject may be contacted for a purpose
+type Channel string
+
+// Contact channels
+const (
+	ChannelEmail Channel = "email"
+	ChannelSMS   Channel = "sms"
+	ChannelPush  Channel = "push"
+)
+
+// Validate checks that the channel is known
+func (c Channel) Validate() error {
+	switch c {
+	case ChannelEmail, ChannelSMS, ChannelPush:
+		return nil
+	default:
+		var errs validation.ValidationErrors
+		errs.Add("channel", validation.CodeUnsupported, fmt.Sprintf("unsupported channel %q (expected email, sms or push)", c), nil)
+		return errs.Err()
+	}
+}
+
+// Consent is a subject's decision about one purpose on one channel. The
+// timestamps keep the subject's timezone, as consent records are shown back
+// to them and to auditors in local time.
+type Consent struct {
+	SubjectID string                  `json:"subject_id"`
+	Purpose   Purpose                 `json:"purpose"`
+	Channel   Channel                 `json:"channel"`
+	GrantedAt *i18n.LocalizedDateTime `json:"granted_at,omitempty"` // Latest grant
+	RevokedAt *i18n.LocalizedDateTime `json:"revoked_at,omitempty"` // Set while revoked
+	Source    string                  `json:"source,omitempty"`     // Where the latest decision was made, e.g. "signup_form"
+}
+
+// Granted reports whether the subject currently consents
+func (c Consent) Granted() bool {
+	return c.GrantedAt != nil && c.RevokedAt == nil
+}
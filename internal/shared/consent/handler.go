@@ -0,0 +1,151 @@
+package consent
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"golang-arch/internal/shared/api"
+	"golang-arch/internal/shared/domain/validation"
+	"golang-arch/internal/shared/geo"
+	"golang-arch/internal/shared/metering"
+	"golang-arch/internal/shared/rbac"
+)
+
+// PermissionAdmin lets an operator list consenting subjects and read or
+// record any subject's consent
+const PermissionAdmin rbac.Permission = "consent:admin"
+
+// Handler exposes consent records over HTTP
+type Handler struct {
+	service *Service
+	authz   *rbac.Authorizer
+}
+
+// NewHandler creates the consent HTTP handler; authz guards every route
+func NewHandler(service *Service, authz *rbac.Authorizer) *Handler {
+	return &Handler{service: service, authz: authz}
+}
+
+// Register adds the consent routes to group. The subject's own principal
+// may use the routes about it; everything else needs consent:admin:
+//
+//	GET /consents?purpose=&channel=&after=&limit=   consenting subject IDs (admin)
+//	GET /consents/:subject                          a subject's decisions
+//	GET /consents/:subject/:purpose/:channel        one decision
+//	PUT /consents/:subject/:purpose/:channel        grant or revoke
+func (h *Handler) Register(group *gin.RouterGroup) {
+	owner := h.authz.RequireOwner("subject", PermissionAdmin)
+
+	group.GET("/consents", h.authz.Require(PermissionAdmin), h.subjects)
+	group.GET("/consents/:subject", owner, h.list)
+	group.GET("/consents/:subject/:purpose/:channel", owner, h.get)
+	group.PUT("/consents/:subject/:purpose/:channel", owner, h.decide)
+}
+
+// DecisionBody grants or revokes a consent
+type DecisionBody struct {
+	Granted  *bool  `json:"granted" binding:"required"`
+	Source   string `json:"source"`
+	Timezone string `json:"timezone"` // Defaults to the request's detected timezone, else the tenant's or locale's
+}
+
+// subjectsResponse is a page of consenting subjects
+type subjectsResponse struct {
+	Subjects []string `json:"subjects"`
+	Next     string   `json:"next,omitempty"` // Pass as after for the next page
+}
+
+func (h *Handler) subjects(c *gin.Context) {
+	limit := maxPageSize
+	if value := c.Query("limit"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed <= 0 {
+			var errs validation.ValidationErrors
+			errs.Add("limit", validation.CodeInvalid, "limit must be a positive integer", nil)
+			api.ValidationFailed(c, api.ErrValidationFailed.Error(), errs.Err())
+			return
+		}
+		limit = parsed
+	}
+
+	subjects, err := h.service.Subjects(c.Request.Context(), Purpose(c.Query("purpose")), Channel(c.Query("channel")), c.Query("after"), limit)
+	if err != nil {
+		api.RespondError(c, err)
+		return
+	}
+	response := subjectsResponse{Subjects: subjects}
+	if len(subjects) > 0 && len(subjects) == min(limit, maxPageSize) {
+		response.Next = subjects[len(subjects)-1]
+	}
+	if response.Subjects == nil {
+		response.Subjects = []string{}
+	}
+	api.Success(c, response, "consenting subjects")
+}
+
+func (h *Handler) list(c *gin.Context) {
+	consents, err := h.service.List(c.Request.Context(), c.Param("subject"))
+	if err != nil {
+		api.RespondError(c, err)
+		return
+	}
+	if consents == nil {
+		consents = []Consent{}
+	}
+	api.Success(c, consents, "consents")
+}
+
+func (h *Handler) get(c *gin.Context) {
+	purpose, channel := Purpose(c.Param("purpose")), Channel(c.Param("channel"))
+	if !validPath(c, purpose, channel) {
+		return
+	}
+	consent, err := h.service.Get(c.Request.Context(), c.Param("subject"), purpose, channel)
+	if err != nil {
+		api.RespondError(c, err)
+		return
+	}
+	api.Success(c, consent, "consent")
+}
+
+func (h *Handler) decide(c *gin.Context) {
+	var body DecisionBody
+	if !api.BindJSON(c, &body) {
+		return
+	}
+
+	decision := Decision{
+		SubjectID: c.Param("subject"),
+		Purpose:   Purpose(c.Param("purpose")),
+		Channel:   Channel(c.Param("channel")),
+		Source:    body.Source,
+		Timezone:  body.Timezone,
+	}
+	if decision.Timezone == "" {
+		decision.Timezone = geo.Preferences(c, h.service.locales.ForTenant(metering.Tenant(c))).Timezone.ID
+	}
+
+	decide := h.service.Revoke
+	if *body.Granted {
+		decide = h.service.Grant
+	}
+	consent, err := decide(c.Request.Context(), decision)
+	if err != nil {
+		api.RespondError(c, err)
+		return
+	}
+	api.Success(c, consent, "consent recorded")
+}
+
+// validPath rejects malformed purpose and channel path segments
+func validPath(c *gin.Context, purpose Purpose, channel Channel) bool {
+	var errs validation.ValidationErrors
+	errs.Merge("", "", purpose.Validate())
+	errs.Merge("", "", channel.Validate())
+	if err := errs.Err(); err != nil {
+		api.ValidationFailed(c, api.ErrValidationFailed.Error(), err)
+		return false
+	}
+	return true
+}
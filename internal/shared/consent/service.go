@@ -0,0 +1,229 @@
+package consent
+
+import (
+	"context"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"golang-arch/internal/shared/domain/domainerror"
+	i18n "golang-arch/internal/shared/domain/internationalization"
+	"golang-arch/internal/shared/domain/validation"
+	"golang-arch/internal/shared/erasure"
+	"golang-arch/pkg/clock"
+)
+
+// maxPageSize bounds Subjects pages
+const maxPageSize = 1000
+
+// Decision is a grant or revocation made by a subject
+type Decision struct {
+	SubjectID string
+	Purpose   Purpose
+	Channel   Channel
+	Source    string // e.g. "signup_form", "preference_center"
+	Timezone  string // IANA zone of the subject; defaults to the service's
+}
+
+// Validate checks the identifiers and timezone
+func (d Decision) Validate() error {
+	var errs validation.ValidationErrors
+	if strings.TrimSpace(d.SubjectID) == "" {
+		errs.Add("subject_id", validation.CodeRequired, "subject id is required", nil)
+	}
+	errs.Merge("", "", d.Purpose.Validate())
+	errs.Merge("", "", d.Channel.Validate())
+	if d.Timezone != "" {
+		_, err := i18n.NewTimezoneFromID(d.Timezone)
+		errs.Merge("timezone", "", err)
+	}
+	return errs.Err()
+}
+
+// Service records consent decisions and answers whether a subject may be
+// contacted
+type Service struct {
+	store   Store
+	clock   clock.Clock
+	logger  *zap.Logger
+	locales i18n.LocaleDefaults
+}
+
+// Option configures a Service
+type Option func(*Service)
+
+// WithClock sets the clock used to timestamp decisions
+func WithClock(c clock.Clock) Option {
+	return func(s *Service) {
+		s.clock = c
+	}
+}
+
+// WithLogger sets the logger
+func WithLogger(logger *zap.Logger) Option {
+	return func(s *Service) {
+		s.logger = logger
+	}
+}
+
+// WithLocaleDefaults sets the timezone of decisions without one, and the
+// defaults the handler resolves a request's timezone against
+func WithLocaleDefaults(defaults i18n.LocaleDefaults) Option {
+	return func(s *Service) {
+		s.locales = defaults
+	}
+}
+
+// NewService creates a consent service on store
+func NewService(store Store, options ...Option) *Service {
+	s := &Service{
+		store:   store,
+		clock:   clock.New(),
+		logger:  zap.NewNop(),
+		locales: i18n.BuiltInLocaleDefaults(),
+	}
+	for _, option := range options {
+		option(s)
+	}
+	return s
+}
+
+// Grant records that the subject consents, clearing an earlier revocation
+func (s *Service) Grant(ctx context.Context, decision Decision) (Consent, error) {
+	return s.decide(ctx, decision, true)
+}
+
+// Revoke records that the subject withdrew consent. Revoking without an
+// earlier grant is recorded too, so an explicit refusal is kept.
+func (s *Service) Revoke(ctx context.Context, decision Decision) (Consent, error) {
+	return s.decide(ctx, decision, false)
+}
+
+func (s *Service) decide(ctx context.Context, decision Decision, grant bool) (Consent, error) {
+	if err := decision.Validate(); err != nil {
+		return Consent{}, err
+	}
+	now, err := s.now(decision.Timezone)
+	if err != nil {
+		return Consent{}, err
+	}
+
+	consent, _, err := s.store.Get(ctx, decision.SubjectID, decision.Purpose, decision.Channel)
+	if err != nil {
+		return Consent{}, err
+	}
+	consent.SubjectID, consent.Purpose, consent.Channel = decision.SubjectID, decision.Purpose, decision.Channel
+	consent.Source = decision.Source
+	if grant {
+		consent.GrantedAt, consent.RevokedAt = now, nil
+	} else {
+		consent.RevokedAt = now
+	}
+
+	if err := s.store.Save(ctx, consent); err != nil {
+		return Consent{}, err
+	}
+	s.logger.Info("Consent recorded",
+		zap.String("subject_id", consent.SubjectID),
+		zap.String("purpose", string(consent.Purpose)),
+		zap.String("channel", string(consent.Channel)),
+		zap.Bool("granted", consent.Granted()))
+	return consent, nil
+}
+
+// Get returns the subject's consent for purpose on channel. Subjects that
+// never decided get a consent that is not granted.
+func (s *Service) Get(ctx context.Context, subjectID string, purpose Purpose, channel Channel) (Consent, error) {
+	consent, found, err := s.store.Get(ctx, subjectID, purpose, channel)
+	if err != nil {
+		return Consent{}, err
+	}
+	if !found {
+		return Consent{SubjectID: subjectID, Purpose: purpose, Channel: channel}, nil
+	}
+	return consent, nil
+}
+
+// List returns every decision of a subject
+func (s *Service) List(ctx context.Context, subjectID string) ([]Consent, error) {
+	return s.store.List(ctx, subjectID)
+}
+
+// Erase deletes the subject's consents; it is the consent repository's
+// erasure.Eraser. Without a record the subject counts as not consenting, so
+// nothing is kept.
+func (s *Service) Erase(ctx context.Context, subject erasure.Subject, _ *erasure.Anonymizer) (int, error) {
+	return s.store.Delete(ctx, subject.ID)
+}
+
+// Subjects pages through the subjects that consent to purpose on channel,
+// e.g. to address a campaign. Pass the last ID of a page as after to get
+// the next one.
+func (s *Service) Subjects(ctx context.Context, purpose Purpose, channel Channel, after string, limit int) ([]string, error) {
+	var errs validation.ValidationErrors
+	errs.Merge("", "", purpose.Validate())
+	errs.Merge("", "", channel.Validate())
+	if err := errs.Err(); err != nil {
+		return nil, err
+	}
+	if limit <= 0 || limit > maxPageSize {
+		limit = maxPageSize
+	}
+	return s.store.Subjects(ctx, purpose, channel, after, limit)
+}
+
+// Allowed reports whether the subject currently consents to purpose on
+// channel
+func (s *Service) Allowed(ctx context.Context, subjectID string, purpose Purpose, channel Channel) (bool, error) {
+	consent, err := s.Get(ctx, subjectID, purpose, channel)
+	if err != nil {
+		return false, err
+	}
+	return consent.Granted(), nil
+}
+
+// Require fails with a Forbidden error unless the subject consents
+func (s *Service) Require(ctx context.Context, subjectID string, purpose Purpose, channel Channel) error {
+	allowed, err := s.Allowed(ctx, subjectID, purpose, channel)
+	if err != nil {
+		return err
+	}
+	if !allowed {
+		return domainerror.Forbiddenf("subject %s has not consented to %s by %s", subjectID, purpose, channel)
+	}
+	return nil
+}
+
+// Guard wraps the Run function of a job that contacts one subject. Consent
+// is checked when the job runs rather than when it is scheduled, so a
+// revocation in between still stops the message; blocked runs are skipped
+// without an error.
+//
+//	worker.EnqueueAt(bootstrap.Job{
+//		Name: "newsletter",
+//		Run:  container.Consent.Guard(userID, consent.PurposeMarketing, consent.ChannelEmail, send),
+//	}, at)
+func (s *Service) Guard(subjectID string, purpose Purpose, channel Channel, run func(ctx context.Context) error) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		allowed, err := s.Allowed(ctx, subjectID, purpose, channel)
+		if err != nil {
+			return err
+		}
+		if !allowed {
+			s.logger.Info("Skipping job without consent",
+				zap.String("subject_id", subjectID),
+				zap.String("purpose", string(purpose)),
+				zap.String("channel", string(channel)))
+			return nil
+		}
+		return run(ctx)
+	}
+}
+
+// now is the current time in the given timezone, or the default one
+func (s *Service) now(timezone string) (*i18n.LocalizedDateTime, error) {
+	if timezone == "" {
+		timezone = s.locales.Preferences.Timezone.ID
+	}
+	return i18n.NewLocalizedDateTimeFromPrimitive(s.clock.Now().Unix(), timezone)
+}
@@ -0,0 +1,283 @@
+package consent
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"golang-arch/internal/shared/database"
+	i18n "golang-arch/internal/shared/domain/internationalization"
+)
+
+// Store persists the current consent of every subject, purpose and channel
+type Store interface {
+	// Get returns the consent; found is false when the subject never decided
+	Get(ctx context.Context, subjectID string, purpose Purpose, channel Channel) (Consent, bool, error)
+	// Save inserts or replaces the consent
+	Save(ctx context.Context, consent Consent) error
+	// List returns a subject's consents ordered by purpose and channel
+	List(ctx context.Context, subjectID string) ([]Consent, error)
+	// Subjects returns up to limit subjects that currently consent to purpose
+	// on channel, ordered by ID and starting after the given ID
+	Subjects(ctx context.Context, purpose Purpose, channel Channel, after string, limit int) ([]string, error)
+	// Delete removes every consent of a subject and returns how many it
+	// removed
+	Delete(ctx context.Context, subjectID string) (int, error)
+}
+
+// PostgresStore keeps consents in the consents table
+type PostgresStore struct {
+	db *sql.DB
+}
+
+// NewPostgresStore creates a store on db
+func NewPostgresStore(db *sql.DB) *PostgresStore {
+	return &PostgresStore{db: db}
+}
+
+const consentColumns = `subject_id, purpose, channel, granted_at, granted_timezone, revoked_at, revoked_timezone, source`
+
+// Columns of the consents table
+var (
+	subjectColumn   = database.NewColumn[string]("subject_id")
+	purposeColumn   = database.NewColumn[string]("purpose")
+	channelColumn   = database.NewColumn[string]("channel")
+	grantedAtColumn = database.NewColumn[int64]("granted_at")
+	revokedAtColumn = database.NewColumn[int64]("revoked_at")
+)
+
+// Get selects one consent
+func (s *PostgresStore) Get(ctx context.Context, subjectID string, purpose Purpose, channel Channel) (Consent, bool, error) {
+	row := s.db.QueryRowContext(ctx,
+		`SELECT `+consentColumns+` FROM consents WHERE subject_id = $1 AND purpose = $2 AND channel = $3`,
+		subjectID, string(purpose), string(channel))
+	consent, err := scanConsent(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return Consent{}, false, nil
+	}
+	if err != nil {
+		return Consent{}, false, fmt.Errorf("failed to read consent: %w", err)
+	}
+	return consent, true, nil
+}
+
+// Save upserts the consent
+func (s *PostgresStore) Save(ctx context.Context, consent Consent) error {
+	grantedAt, grantedTZ := nullableTime(consent.GrantedAt)
+	revokedAt, revokedTZ := nullableTime(consent.RevokedAt)
+	_, err := s.db.ExecContext(ctx,
+		`INSERT INTO consents (`+consentColumns+`)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		ON CONFLICT (subject_id, purpose, channel) DO UPDATE SET
+			granted_at = EXCLUDED.granted_at,
+			granted_timezone = EXCLUDED.granted_timezone,
+			revoked_at = EXCLUDED.revoked_at,
+			revoked_timezone = EXCLUDED.revoked_timezone,
+			source = EXCLUDED.source,
+			updated_at = NOW()`,
+		consent.SubjectID, string(consent.Purpose), string(consent.Channel),
+		grantedAt, grantedTZ, revokedAt, revokedTZ, consent.Source,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save consent: %w", err)
+	}
+	return nil
+}
+
+// List selects a subject's consents
+func (s *PostgresStore) List(ctx context.Context, subjectID string) ([]Consent, error) {
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT `+consentColumns+` FROM consents WHERE subject_id = $1 ORDER BY purpose, channel`,
+		subjectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query consents: %w", err)
+	}
+	defer rows.Close()
+
+	var consents []Consent
+	for rows.Next() {
+		consent, err := scanConsent(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan consent: %w", err)
+		}
+		consents = append(consents, consent)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read consents: %w", err)
+	}
+	return consents, nil
+}
+
+// Subjects selects a page of consenting subjects
+func (s *PostgresStore) Subjects(ctx context.Context, purpose Purpose, channel Channel, after string, limit int) ([]string, error) {
+	query, args := database.Select("consents", subjectColumn.Name()).
+		Where(
+			purposeColumn.Eq(string(purpose)),
+			channelColumn.Eq(string(channel)),
+			grantedAtColumn.IsNotNull(),
+			revokedAtColumn.IsNull(),
+			subjectColumn.Gt(after),
+		).
+		OrderBy(subjectColumn.Asc()).
+		Limit(limit).
+		Build()
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query consenting subjects: %w", err)
+	}
+	defer rows.Close()
+
+	var subjects []string
+	for rows.Next() {
+		var subject string
+		if err := rows.Scan(&subject); err != nil {
+			return nil, fmt.Errorf("failed to scan consenting subject: %w", err)
+		}
+		subjects = append(subjects, subject)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read consenting subjects: %w", err)
+	}
+	return subjects, nil
+}
+
+// Delete removes a subject's consents
+func (s *PostgresStore) Delete(ctx context.Context, subjectID string) (int, error) {
+	result, err := s.db.ExecContext(ctx, `DELETE FROM consents WHERE subject_id = $1`, subjectID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete consents: %w", err)
+	}
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete consents: %w", err)
+	}
+	return int(deleted), nil
+}
+
+// rowScanner is satisfied by *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanConsent(row rowScanner) (Consent, error) {
+	var (
+		consent              Consent
+		purpose, channel     string
+		grantedAt, revokedAt sql.NullInt64
+		grantedTZ, revokedTZ sql.NullString
+	)
+	if err := row.Scan(&consent.SubjectID, &purpose, &channel, &grantedAt, &grantedTZ, &revokedAt, &revokedTZ, &consent.Source); err != nil {
+		return Consent{}, err
+	}
+	consent.Purpose, consent.Channel = Purpose(purpose), Channel(channel)
+
+	var err error
+	if consent.GrantedAt, err = localizedTime(grantedAt, grantedTZ); err != nil {
+		return Consent{}, err
+	}
+	if consent.RevokedAt, err = localizedTime(revokedAt, revokedTZ); err != nil {
+		return Consent{}, err
+	}
+	return consent, nil
+}
+
+// nullableTime splits a timestamp into epoch and timezone columns
+func nullableTime(ldt *i18n.LocalizedDateTime) (sql.NullInt64, sql.NullString) {
+	if ldt == nil {
+		return sql.NullInt64{}, sql.NullString{}
+	}
+	epoch, timezone := ldt.ToPrimitive()
+	return sql.NullInt64{Int64: epoch, Valid: true}, sql.NullString{String: timezone, Valid: true}
+}
+
+// localizedTime rebuilds a timestamp from its columns
+func localizedTime(epoch sql.NullInt64, timezone sql.NullString) (*i18n.LocalizedDateTime, error) {
+	if !epoch.Valid {
+		return nil, nil
+	}
+	return i18n.NewLocalizedDateTimeFromPrimitive(epoch.Int64, timezone.String)
+}
+
+// MemoryStore keeps consents in memory, for tests and the dev profile
+type MemoryStore struct {
+	mu       sync.Mutex
+	consents map[string]Consent
+}
+
+// NewMemoryStore creates an empty in-memory store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{consents: make(map[string]Consent)}
+}
+
+func memoryKey(subjectID string, purpose Purpose, channel Channel) string {
+	return subjectID + "\x00" + string(purpose) + "\x00" + string(channel)
+}
+
+// Get returns the stored consent
+func (s *MemoryStore) Get(_ context.Context, subjectID string, purpose Purpose, channel Channel) (Consent, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	consent, ok := s.consents[memoryKey(subjectID, purpose, channel)]
+	return consent, ok, nil
+}
+
+// Save stores the consent
+func (s *MemoryStore) Save(_ context.Context, consent Consent) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.consents[memoryKey(consent.SubjectID, consent.Purpose, consent.Channel)] = consent
+	return nil
+}
+
+// List returns a subject's consents
+func (s *MemoryStore) List(_ context.Context, subjectID string) ([]Consent, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var consents []Consent
+	for _, consent := range s.consents {
+		if consent.SubjectID == subjectID {
+			consents = append(consents, consent)
+		}
+	}
+	sort.Slice(consents, func(i, j int) bool {
+		if consents[i].Purpose != consents[j].Purpose {
+			return consents[i].Purpose < consents[j].Purpose
+		}
+		return consents[i].Channel < consents[j].Channel
+	})
+	return consents, nil
+}
+
+// Subjects returns a page of consenting subjects
+func (s *MemoryStore) Subjects(_ context.Context, purpose Purpose, channel Channel, after string, limit int) ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var subjects []string
+	for _, consent := range s.consents {
+		if consent.Purpose == purpose && consent.Channel == channel && consent.Granted() && consent.SubjectID > after {
+			subjects = append(subjects, consent.SubjectID)
+		}
+	}
+	sort.Strings(subjects)
+	if len(subjects) > limit {
+		subjects = subjects[:limit]
+	}
+	return subjects, nil
+}
+
+// Delete removes a subject's consents
+func (s *MemoryStore) Delete(_ context.Context, subjectID string) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	deleted := 0
+	for key, consent := range s.consents {
+		if consent.SubjectID == subjectID {
+			delete(s.consents, key)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
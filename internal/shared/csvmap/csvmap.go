@@ -0,0 +1,725 @@
+// Package csvmap encodes and decodes slices of structs to and from CSV, with
+// first-class support for the i18n value objects.
+//
+// Columns come from `csv` struct tags (the field name when absent, "-" to
+// skip). Domain values span the columns a spreadsheet user expects:
+//
+//	i18n.Money              <name>_amount, <name>_currency
+//	i18n.LocalizedDateTime  <name>, <name>_tz
+//	i18n.Phone              <name> in international format
+//	i18n.Time               <name>
+//	i18n.Timezone           <name> (IANA ID)
+//	i18n.Currency           <name> (ISO 4217 code)
+//
+// Decoding matches columns by header name, so column order does not matter,
+// and reports every invalid cell of a row as validation errors.
+package csvmap
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	i18n "golang-arch/internal/shared/domain/internationalization"
+	"golang-arch/internal/shared/domain/validation"
+)
+
+// Encoder writes structs as CSV rows, starting with a header row
+type Encoder struct {
+	w      *csv.Writer
+	opts   Options
+	layout *layout
+}
+
+// NewEncoder creates an encoder writing to w
+func NewEncoder(w io.Writer, opts Options) *Encoder {
+	opts = opts.withDefaults()
+	writer := csv.NewWriter(w)
+	writer.Comma = opts.Comma
+	return &Encoder{w: writer, opts: opts}
+}
+
+// Encode writes v, a struct or pointer to struct. The header is written
+// before the first row; every row must have the same type.
+func (e *Encoder) Encode(v any) error {
+	value := reflect.Indirect(reflect.ValueOf(v))
+	l, err := layoutFor(value.Type())
+	if err != nil {
+		return err
+	}
+
+	if e.layout == nil {
+		e.layout = l
+		if err := e.w.Write(l.header()); err != nil {
+			return err
+		}
+	} else if e.layout != l {
+		return fmt.Errorf("csvmap: cannot mix %s rows with %s rows", value.Type(), e.layout.typ)
+	}
+
+	record := make([]string, 0, len(l.header()))
+	for _, f := range l.fields {
+		record = f.kind.encode(record, value.Field(f.index), e.opts)
+	}
+	return e.w.Write(record)
+}
+
+// Column describes one column of a struct's rows
+type Column struct {
+	Name    string
+	Numeric bool // Holds a number, such as an amount or an integer field
+}
+
+// Columns returns the columns of rows of v's type, a struct or pointer to
+// struct, in header order
+func Columns(v any) ([]Column, error) {
+	l, err := layoutFor(derefType(reflect.TypeOf(v)))
+	if err != nil {
+		return nil, err
+	}
+	columns := make([]Column, len(l.columns))
+	for i, name := range l.columns {
+		columns[i] = Column{Name: name, Numeric: l.numeric[i]}
+	}
+	return columns, nil
+}
+
+// Record renders v, a struct or pointer to struct, as the cells of one row
+// in the order of Columns, for writers of other tabular formats
+func Record(v any, opts Options) ([]string, error) {
+	value := reflect.Indirect(reflect.ValueOf(v))
+	l, err := layoutFor(value.Type())
+	if err != nil {
+		return nil, err
+	}
+	opts = opts.withDefaults()
+	record := make([]string, 0, len(l.columns))
+	for _, f := range l.fields {
+		record = f.kind.encode(record, value.Field(f.index), opts)
+	}
+	return record, nil
+}
+
+// Flush writes buffered rows to the underlying writer
+func (e *Encoder) Flush() error {
+	e.w.Flush()
+	return e.w.Error()
+}
+
+// Decoder reads CSV rows into structs
+type Decoder struct {
+	r       *csv.Reader
+	opts    Options
+	columns map[string]int
+}
+
+// NewDecoder creates a decoder reading from r. The first row must be the
+// header.
+func NewDecoder(r io.Reader, opts Options) *Decoder {
+	opts = opts.withDefaults()
+	reader := csv.NewReader(r)
+	reader.Comma = opts.Comma
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	return &Decoder{r: reader, opts: opts}
+}
+
+// Decode reads the next row into v, a pointer to struct. It returns io.EOF
+// when no rows are left. Invalid cells are reported together as a
+// validation.ValidationErrors wrapped with the line number.
+func (d *Decoder) Decode(v any) error {
+	ptr := reflect.ValueOf(v)
+	if ptr.Kind() != reflect.Pointer || ptr.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("csvmap: Decode needs a pointer to struct, got %T", v)
+	}
+	value := ptr.Elem()
+
+	l, err := layoutFor(value.Type())
+	if err != nil {
+		return err
+	}
+
+	if d.columns == nil {
+		if err := d.readHeader(l); err != nil {
+			return err
+		}
+	}
+
+	record, err := d.r.Read()
+	if err != nil {
+		return err
+	}
+	line, _ := d.r.FieldPos(0)
+
+	var errs validation.ValidationErrors
+	for _, f := range l.fields {
+		cells := make([]string, len(f.columns))
+		for i, column := range f.columns {
+			if index := d.columns[column]; index < len(record) {
+				cells[i] = strings.TrimSpace(record[index])
+			}
+		}
+		if err := f.kind.decode(value.Field(f.index), cells, d.opts); err != nil {
+			errs.Merge(f.columns[0], "", err)
+		}
+	}
+
+	if err := errs.Err(); err != nil {
+		return &RowError{Line: line, Err: err}
+	}
+	return nil
+}
+
+// readHeader maps column names to record indexes and checks every field has
+// its columns
+func (d *Decoder) readHeader(l *layout) error {
+	header, err := d.r.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return io.EOF
+		}
+		return fmt.Errorf("csvmap: failed to read header: %w", err)
+	}
+
+	d.columns = make(map[string]int, len(header))
+	for i, name := range header {
+		d.columns[strings.TrimSpace(strings.TrimPrefix(name, "\ufeff"))] = i
+	}
+
+	var missing []string
+	for _, column := range l.header() {
+		if _, ok := d.columns[column]; !ok {
+			missing = append(missing, column)
+		}
+	}
+	if len(missing) > 0 {
+		d.columns = nil
+		return fmt.Errorf("csvmap: missing columns: %s", strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// RowError reports the invalid cells of one CSV row
+type RowError struct {
+	Line int
+	Err  error
+}
+
+// Error implements the error interface
+func (e *RowError) Error() string {
+	return fmt.Sprintf("line %d: %v", e.Line, e.Err)
+}
+
+// Unwrap returns the row's validation errors
+func (e *RowError) Unwrap() error {
+	return e.Err
+}
+
+// FieldErrors flattens the row errors returned by Read or Decode into one
+// ValidationErrors, naming fields "line[N].<column>". Other errors become a
+// single entry.
+func FieldErrors(err error) validation.ValidationErrors {
+	var rowErrs []error
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		rowErrs = joined.Unwrap()
+	} else if err != nil {
+		rowErrs = []error{err}
+	}
+
+	var errs validation.ValidationErrors
+	for _, err := range rowErrs {
+		var rowErr *RowError
+		if !errors.As(err, &rowErr) {
+			errs = append(errs, validation.FromError(err)...)
+			continue
+		}
+
+		for _, fe := range validation.FromError(rowErr.Err) {
+			fe.Field = validation.JoinField(fmt.Sprintf("line[%d]", rowErr.Line), fe.Field)
+			fe.Message = fmt.Sprintf("line %d: %s", rowErr.Line, fe.Message)
+			errs = append(errs, fe)
+		}
+	}
+	return errs
+}
+
+// Marshal encodes a slice of structs as CSV
+func Marshal(rows any, opts Options) ([]byte, error) {
+	var b strings.Builder
+	if err := Write(&b, rows, opts); err != nil {
+		return nil, err
+	}
+	return []byte(b.String()), nil
+}
+
+// Write encodes a slice of structs as CSV to w. An empty slice still writes
+// the header.
+func Write(w io.Writer, rows any, opts Options) error {
+	slice := reflect.ValueOf(rows)
+	if slice.Kind() != reflect.Slice {
+		return fmt.Errorf("csvmap: Write needs a slice, got %T", rows)
+	}
+
+	enc := NewEncoder(w, opts)
+	if slice.Len() == 0 {
+		l, err := layoutFor(derefType(slice.Type().Elem()))
+		if err != nil {
+			return err
+		}
+		if err := enc.w.Write(l.header()); err != nil {
+			return err
+		}
+	}
+	for i := 0; i < slice.Len(); i++ {
+		if err := enc.Encode(slice.Index(i).Interface()); err != nil {
+			return err
+		}
+	}
+	return enc.Flush()
+}
+
+// Unmarshal decodes every row of data into the slice pointed to by rows. All
+// invalid rows are reported, joined, after the valid rows are appended.
+func Unmarshal(data []byte, rows any, opts Options) error {
+	return Read(strings.NewReader(string(data)), rows, opts)
+}
+
+// Read decodes every row from r into the slice pointed to by rows
+func Read(r io.Reader, rows any, opts Options) error {
+	ptr := reflect.ValueOf(rows)
+	if ptr.Kind() != reflect.Pointer || ptr.Elem().Kind() != reflect.Slice {
+		return fmt.Errorf("csvmap: Read needs a pointer to slice, got %T", rows)
+	}
+	slice := ptr.Elem()
+	elemType := slice.Type().Elem()
+	isPointer := elemType.Kind() == reflect.Pointer
+
+	dec := NewDecoder(r, opts)
+	var rowErrs []error
+	for {
+		row := reflect.New(derefType(elemType))
+		err := dec.Decode(row.Interface())
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		var rowErr *RowError
+		if errors.As(err, &rowErr) {
+			rowErrs = append(rowErrs, rowErr)
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		if isPointer {
+			slice.Set(reflect.Append(slice, row))
+		} else {
+			slice.Set(reflect.Append(slice, row.Elem()))
+		}
+	}
+	return errors.Join(rowErrs...)
+}
+
+func derefType(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t
+}
+
+// layout is the cached column mapping of a struct type
+type layout struct {
+	typ     reflect.Type
+	fields  []field
+	columns []string
+	numeric []bool // Per column
+}
+
+type field struct {
+	index   int
+	columns []string
+	kind    kind
+}
+
+func (l *layout) header() []string {
+	return l.columns
+}
+
+var layouts sync.Map // reflect.Type -> *layout
+
+func layoutFor(t reflect.Type) (*layout, error) {
+	if cached, ok := layouts.Load(t); ok {
+		return cached.(*layout), nil
+	}
+	if t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("csvmap: %s is not a struct", t)
+	}
+
+	l := &layout{typ: t}
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		name := sf.Tag.Get("csv")
+		if !sf.IsExported() || name == "-" {
+			continue
+		}
+		if name == "" {
+			name = sf.Name
+		}
+
+		k, ok := kindFor(sf.Type)
+		if !ok {
+			return nil, fmt.Errorf("csvmap: unsupported type %s for field %s.%s", sf.Type, t.Name(), sf.Name)
+		}
+
+		f := field{index: i, columns: k.columns(name), kind: k}
+		l.fields = append(l.fields, f)
+		l.columns = append(l.columns, f.columns...)
+		l.numeric = append(l.numeric, numericColumns(sf.Type, k, len(f.columns))...)
+	}
+
+	actual, _ := layouts.LoadOrStore(t, l)
+	return actual.(*layout), nil
+}
+
+// kind converts one struct field to and from its CSV cells
+type kind interface {
+	columns(name string) []string
+	encode(record []string, v reflect.Value, opts Options) []string
+	decode(v reflect.Value, cells []string, opts Options) error
+}
+
+var (
+	moneyType    = reflect.TypeOf(i18n.Money{})
+	ldtType      = reflect.TypeOf(i18n.LocalizedDateTime{})
+	phoneType    = reflect.TypeOf(i18n.Phone{})
+	timeType     = reflect.TypeOf(i18n.Time{})
+	timezoneType = reflect.TypeOf(i18n.Timezone{})
+	currencyType = reflect.TypeOf(i18n.Currency{})
+)
+
+func kindFor(t reflect.Type) (kind, bool) {
+	switch t {
+	case moneyType:
+		return moneyKind{}, true
+	case ldtType:
+		return localizedDateTimeKind{}, true
+	case phoneType:
+		return phoneKind{}, true
+	case timeType:
+		return timeKind{}, true
+	case timezoneType:
+		return timezoneKind{}, true
+	case currencyType:
+		return currencyKind{}, true
+	}
+
+	switch t.Kind() {
+	case reflect.String, reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return scalarKind{}, true
+	}
+	return nil, false
+}
+
+// numericColumns reports which of a field's columns hold numbers: the
+// amount of Money, and numeric scalars
+func numericColumns(t reflect.Type, k kind, n int) []bool {
+	numeric := make([]bool, n)
+	switch k.(type) {
+	case moneyKind:
+		numeric[0] = true
+	case scalarKind:
+		numeric[0] = t.Kind() != reflect.String && t.Kind() != reflect.Bool
+	}
+	return numeric
+}
+
+type moneyKind struct{}
+
+func (moneyKind) columns(name string) []string {
+	return []string{name + "_amount", name + "_currency"}
+}
+
+func (moneyKind) encode(record []string, v reflect.Value, opts Options) []string {
+	m := v.Interface().(i18n.Money)
+
+	amount := strconv.FormatInt(m.Amount, 10)
+	if opts.AmountFormat == AmountDecimal {
+		amount = m.DecimalString()
+		if opts.DecimalSeparator != '.' {
+			amount = strings.Replace(amount, ".", string(opts.DecimalSeparator), 1)
+		}
+	}
+	return append(record, amount, m.Currency.Code)
+}
+
+func (moneyKind) decode(v reflect.Value, cells []string, opts Options) error {
+	amount, code := cells[0], strings.ToUpper(cells[1])
+	if amount == "" || code == "" {
+		return validation.ValidationErrors{{Code: validation.CodeRequired, Message: "amount and currency are required"}}
+	}
+
+	var money *i18n.Money
+	var err error
+	if opts.AmountFormat == AmountMinorUnits {
+		var minor int64
+		minor, err = strconv.ParseInt(amount, 10, 64)
+		if err == nil {
+			money, err = i18n.NewMoneyFromPrimitive(minor, code)
+		}
+	} else {
+		if opts.DecimalSeparator != '.' {
+			amount = strings.Replace(amount, string(opts.DecimalSeparator), ".", 1)
+		}
+		money, err = i18n.ParseMoney(amount + " " + code)
+	}
+	if err != nil {
+		return err
+	}
+
+	v.Set(reflect.ValueOf(*money))
+	return nil
+}
+
+type localizedDateTimeKind struct{}
+
+func (localizedDateTimeKind) columns(name string) []string {
+	return []string{name, name + "_tz"}
+}
+
+func (localizedDateTimeKind) encode(record []string, v reflect.Value, opts Options) []string {
+	ldt := v.Interface().(i18n.LocalizedDateTime)
+	// Resolve the zone rules for the instant itself; the cached offset on
+	// Timezone reflects the current DST state, not the one at Epoch.
+	t := ldt.ToTime()
+	if loc, err := ldt.Timezone.GetLocation(); err == nil {
+		t = time.Unix(ldt.Time.Epoch, 0).In(loc)
+	}
+	zone := ldt.Timezone.ID
+	if opts.Location != nil {
+		t, zone = time.Unix(ldt.Time.Epoch, 0).In(opts.Location), opts.Location.String()
+	}
+	return append(record, formatTime(t, ldt.Time.Epoch, opts), zone)
+}
+
+func (localizedDateTimeKind) decode(v reflect.Value, cells []string, opts Options) error {
+	tz, err := i18n.MakeTimezone(cells[1])
+	if err != nil {
+		return err
+	}
+
+	loc, err := time.LoadLocation(tz.ID)
+	if err != nil {
+		return err
+	}
+	epoch, err := parseTime(cells[0], loc, opts)
+	if err != nil {
+		return err
+	}
+
+	ldt, err := i18n.MakeLocalizedDateTime(epoch, tz.ID)
+	if err != nil {
+		return err
+	}
+	v.Set(reflect.ValueOf(ldt))
+	return nil
+}
+
+type phoneKind struct{}
+
+func (phoneKind) columns(name string) []string {
+	return []string{name}
+}
+
+func (phoneKind) encode(record []string, v reflect.Value, _ Options) []string {
+	return append(record, v.Interface().(i18n.Phone).ToPrimitive())
+}
+
+func (phoneKind) decode(v reflect.Value, cells []string, _ Options) error {
+	phone, err := i18n.FromPrimitivePhone(cells[0])
+	if err != nil {
+		return err
+	}
+	v.Set(reflect.ValueOf(*phone))
+	return nil
+}
+
+type timeKind struct{}
+
+func (timeKind) columns(name string) []string {
+	return []string{name}
+}
+
+func (timeKind) encode(record []string, v reflect.Value, opts Options) []string {
+	t := v.Interface().(i18n.Time)
+	loc := time.UTC
+	if opts.Location != nil {
+		loc = opts.Location
+	}
+	return append(record, formatTime(t.ToTime().In(loc), t.Epoch, opts))
+}
+
+func (timeKind) decode(v reflect.Value, cells []string, opts Options) error {
+	loc := time.UTC
+	if opts.Location != nil {
+		loc = opts.Location
+	}
+	epoch, err := parseTime(cells[0], loc, opts)
+	if err != nil {
+		return err
+	}
+	t, err := i18n.MakeTime(epoch)
+	if err != nil {
+		return err
+	}
+	v.Set(reflect.ValueOf(t))
+	return nil
+}
+
+type timezoneKind struct{}
+
+func (timezoneKind) columns(name string) []string {
+	return []string{name}
+}
+
+func (timezoneKind) encode(record []string, v reflect.Value, _ Options) []string {
+	return append(record, v.Interface().(i18n.Timezone).ID)
+}
+
+func (timezoneKind) decode(v reflect.Value, cells []string, _ Options) error {
+	tz, err := i18n.MakeTimezone(cells[0])
+	if err != nil {
+		return err
+	}
+	v.Set(reflect.ValueOf(tz))
+	return nil
+}
+
+type currencyKind struct{}
+
+func (currencyKind) columns(name string) []string {
+	return []string{name}
+}
+
+func (currencyKind) encode(record []string, v reflect.Value, _ Options) []string {
+	return append(record, v.Interface().(i18n.Currency).Code)
+}
+
+func (currencyKind) decode(v reflect.Value, cells []string, _ Options) error {
+	currency, err := i18n.NewCurrencyFromCode(strings.ToUpper(cells[0]))
+	if err != nil {
+		return err
+	}
+	v.Set(reflect.ValueOf(*currency))
+	return nil
+}
+
+// scalarKind handles strings, booleans and numbers
+type scalarKind struct{}
+
+func (scalarKind) columns(name string) []string {
+	return []string{name}
+}
+
+func (scalarKind) encode(record []string, v reflect.Value, opts Options) []string {
+	switch v.Kind() {
+	case reflect.String:
+		return append(record, v.String())
+	case reflect.Bool:
+		return append(record, strconv.FormatBool(v.Bool()))
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return append(record, strconv.FormatInt(v.Int(), 10))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return append(record, strconv.FormatUint(v.Uint(), 10))
+	default:
+		s := strconv.FormatFloat(v.Float(), 'f', -1, v.Type().Bits())
+		if opts.DecimalSeparator != '.' {
+			s = strings.Replace(s, ".", string(opts.DecimalSeparator), 1)
+		}
+		return append(record, s)
+	}
+}
+
+func (scalarKind) decode(v reflect.Value, cells []string, opts Options) error {
+	cell := cells[0]
+	if v.Kind() == reflect.String {
+		v.SetString(cell)
+		return nil
+	}
+	if cell == "" {
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.Bool:
+		b, err := strconv.ParseBool(cell)
+		if err != nil {
+			return invalidCell(cell, "boolean")
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(cell, 10, v.Type().Bits())
+		if err != nil {
+			return invalidCell(cell, "integer")
+		}
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(cell, 10, v.Type().Bits())
+		if err != nil {
+			return invalidCell(cell, "unsigned integer")
+		}
+		v.SetUint(n)
+	default:
+		if opts.DecimalSeparator != '.' {
+			cell = strings.Replace(cell, string(opts.DecimalSeparator), ".", 1)
+		}
+		f, err := strconv.ParseFloat(cell, v.Type().Bits())
+		if err != nil {
+			return invalidCell(cell, "number")
+		}
+		v.SetFloat(f)
+	}
+	return nil
+}
+
+func invalidCell(cell, expected string) error {
+	return validation.ValidationErrors{{
+		Code:    validation.CodeInvalidFormat,
+		Message: fmt.Sprintf("%q is not a valid %s", cell, expected),
+		Params:  map[string]any{"value": cell, "expected": expected},
+	}}
+}
+
+// formatTime renders t with the layout option, or the epoch when unset
+func formatTime(t time.Time, epoch int64, opts Options) string {
+	if opts.TimeLayout == "" {
+		return strconv.FormatInt(epoch, 10)
+	}
+	return t.Format(opts.TimeLayout)
+}
+
+// parseTime reads epoch seconds, or a time in loc using the layout option
+func parseTime(cell string, loc *time.Location, opts Options) (int64, error) {
+	if opts.TimeLayout == "" {
+		epoch, err := strconv.ParseInt(cell, 10, 64)
+		if err != nil {
+			return 0, invalidCell(cell, "epoch time")
+		}
+		return epoch, nil
+	}
+
+	t, err := time.ParseInLocation(opts.TimeLayout, cell, loc)
+	if err != nil {
+		return 0, invalidCell(cell, "time in layout "+opts.TimeLayout)
+	}
+	return t.Unix(), nil
+}
@@ -0,0 +1,73 @@
+package csvmap
+
+import (
+	"strings"
+	"time"
+)
+
+// AmountFormat selects how Money amounts are written
+type AmountFormat int
+
+const (
+	// AmountDecimal writes amounts in major units, e.g. "100.50"
+	AmountDecimal AmountFormat = iota
+	// AmountMinorUnits writes the integer minor units, e.g. "10050"
+	AmountMinorUnits
+)
+
+// Options controls the CSV dialect and how domain values are rendered
+type Options struct {
+	// Comma is the field delimiter; ',' when zero
+	Comma rune
+	// DecimalSeparator is used for decimal amounts; '.' when zero
+	DecimalSeparator rune
+	// AmountFormat selects decimal or minor-unit amounts
+	AmountFormat AmountFormat
+	// TimeLayout formats date-times in their own timezone; epoch seconds
+	// when empty
+	TimeLayout string
+	// Location shows date-times and times in this zone, e.g. the reader's,
+	// instead of their own zone or UTC. Times are also read in it.
+	Location *time.Location
+}
+
+// localeOptions holds the spreadsheet conventions of common locales. Locales
+// that use a decimal comma also use ';' as the list separator so the file
+// opens correctly in spreadsheet software.
+var localeOptions = map[string]Options{
+	"en": {Comma: ',', DecimalSeparator: '.'},
+	"ja": {Comma: ',', DecimalSeparator: '.'},
+	"zh": {Comma: ',', DecimalSeparator: '.'},
+	"de": {Comma: ';', DecimalSeparator: ','},
+	"fr": {Comma: ';', DecimalSeparator: ','},
+	"es": {Comma: ';', DecimalSeparator: ','},
+	"it": {Comma: ';', DecimalSeparator: ','},
+	"nl": {Comma: ';', DecimalSeparator: ','},
+	"pt": {Comma: ';', DecimalSeparator: ','},
+	"id": {Comma: ';', DecimalSeparator: ','},
+	"ru": {Comma: ';', DecimalSeparator: ','},
+}
+
+// LocaleOptions returns the delimiter and decimal separator conventions for a
+// BCP 47 locale such as "de-DE"; an Accept-Language header value uses its
+// first entry. Unknown locales get the defaults.
+func LocaleOptions(locale string) Options {
+	if i := strings.IndexAny(locale, ",;"); i >= 0 {
+		locale = locale[:i]
+	}
+	language, _, _ := strings.Cut(strings.ReplaceAll(strings.TrimSpace(locale), "_", "-"), "-")
+	if opts, ok := localeOptions[strings.ToLower(language)]; ok {
+		return opts
+	}
+	return Options{}
+}
+
+func (o Options) withDefaults() Options {
+	if o.Comma == 0 {
+		o.Comma = ','
+	}
+	if o.DecimalSeparator == 0 {
+		o.DecimalSeparator = '.'
+	}
+	return o
+}
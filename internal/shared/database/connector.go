@@ -0,0 +1,94 @@
+package database
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+)
+
+// Setting is a Postgres run-time parameter set on each connection
+type Setting struct {
+	Name  string
+	Value string
+}
+
+// Option configures a Connector
+type Option func(*Connector)
+
+// WithTimeZone sets the session TimeZone, which timestamptz values are read
+// and written in and NOW()::date is taken in
+func WithTimeZone(name string) Option {
+	return func(c *Connector) {
+		if name != "" {
+			c.settings = append(c.settings, Setting{"TimeZone", name})
+		}
+	}
+}
+
+// WithLocale sets lc_monetary, lc_numeric and lc_time, which to_char and
+// the money type format with. lc_messages needs a superuser and collation
+// is fixed per database, so neither is set.
+func WithLocale(locale string) Option {
+	return func(c *Connector) {
+		if locale != "" {
+			c.settings = append(c.settings,
+				Setting{"lc_monetary", locale}, Setting{"lc_numeric", locale}, Setting{"lc_time", locale})
+		}
+	}
+}
+
+// WithSetting sets any other run-time parameter
+func WithSetting(name, value string) Option {
+	return func(c *Connector) {
+		c.settings = append(c.settings, Setting{name, value})
+	}
+}
+
+// Connector applies session settings to every connection its driver
+// connector opens, so a deployment behaves the same whatever the server's
+// or the role's defaults are. Open it with sql.OpenDB.
+type Connector struct {
+	driver.Connector
+	settings []Setting
+}
+
+// NewConnector wraps base, e.g. a pq.Connector
+func NewConnector(base driver.Connector, options ...Option) *Connector {
+	c := &Connector{Connector: base}
+	for _, option := range options {
+		option(c)
+	}
+	return c
+}
+
+// Settings returns the settings applied to each connection
+func (c *Connector) Settings() []Setting {
+	return append([]Setting(nil), c.settings...)
+}
+
+// Connect opens a connection and applies the settings. A setting the server
+// rejects, e.g. an unknown time zone, fails the connection.
+func (c *Connector) Connect(ctx context.Context) (driver.Conn, error) {
+	conn, err := c.Connector.Connect(ctx)
+	if err != nil || len(c.settings) == 0 {
+		return conn, err
+	}
+	execer, ok := conn.(driver.ExecerContext)
+	if !ok {
+		conn.Close()
+		return nil, errors.New("database: driver connection cannot apply session settings")
+	}
+	for _, setting := range c.settings {
+		// set_config takes parameters, unlike SET
+		_, err := execer.ExecContext(ctx, "SELECT set_config($1, $2, false)", []driver.NamedValue{
+			{Ordinal: 1, Value: setting.Name},
+			{Ordinal: 2, Value: setting.Value},
+		})
+		if err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("failed to set %s to %q: %w", setting.Name, setting.Value, err)
+		}
+	}
+	return conn, nil
+}
@@ -0,0 +1,105 @@
+// Package database bounds the time repositories spend in the database.
+//
+// Three limits apply. Middleware gives each HTTP request a deadline, and
+// queries run with the request's context are cancelled when it passes, so a
+// slow query cannot outlive its request. WithTimeout gives background work
+// the same kind of deadline. Statement timeouts, set on every Postgres
+// connection, are the backstop for queries run without one.
+//
+// Repositories take a Querier, which only has the context-taking methods of
+// *sql.DB and *sql.Tx, and run every query with the caller's context.
+//
+// Connector applies the same session settings, such as the time zone, to
+// every connection.
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lib/pq"
+
+	"golang-arch/internal/shared/domain/domainerror"
+)
+
+// queryCanceled is the Postgres error code of a statement cancelled by
+// statement_timeout or a cancel request
+const queryCanceled = "57014"
+
+// Querier is implemented by *sql.DB, *sql.Tx and *sql.Conn. It leaves out
+// the methods that take no context, whose queries cannot be cancelled.
+type Querier interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// WithTimeout returns ctx with a deadline timeout from now, unless ctx
+// already ends sooner or timeout is not positive
+func WithTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		return ctx, func() {}
+	}
+	if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) <= timeout {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, timeout)
+}
+
+// Middleware gives each request's context a deadline timeout from its start;
+// 0 leaves requests without one. Routes in exempt, full patterns such as
+// "/api/v1/jobs/:id/events", are long-lived and get no deadline.
+func Middleware(timeout time.Duration, exempt ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if timeout <= 0 || slices.Contains(exempt, c.FullPath()) {
+			c.Next()
+			return
+		}
+		ctx, cancel := WithTimeout(c.Request.Context(), timeout)
+		defer cancel()
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}
+}
+
+// StatementTimeout returns the connection string parameter that sets
+// Postgres' statement_timeout on every connection; empty for 0
+func StatementTimeout(timeout time.Duration) string {
+	if timeout <= 0 {
+		return ""
+	}
+	return fmt.Sprintf("options='-c statement_timeout=%d'", timeout.Milliseconds())
+}
+
+// SetLocalStatementTimeout changes the statement timeout for the rest of tx,
+// e.g. for a report known to run longer than the connection's timeout
+func SetLocalStatementTimeout(ctx context.Context, tx *sql.Tx, timeout time.Duration) error {
+	// SET does not take parameters
+	_, err := tx.ExecContext(ctx, fmt.Sprintf("SET LOCAL statement_timeout = %d", timeout.Milliseconds()))
+	if err != nil {
+		return fmt.Errorf("failed to set statement timeout: %w", err)
+	}
+	return nil
+}
+
+// MapTimeout returns err, the result of a query run with ctx, as an
+// Unavailable domain error when the query ran out of time by ctx's deadline
+// or the statement timeout, so the API answers 503 instead of 500. Drivers
+// report a cancelled query in their own ways, hence ctx. Other errors are
+// returned unchanged.
+func MapTimeout(ctx context.Context, err error) error {
+	if err == nil {
+		return nil
+	}
+	var pqErr *pq.Error
+	if errors.Is(err, context.DeadlineExceeded) || errors.Is(ctx.Err(), context.DeadlineExceeded) ||
+		(errors.As(err, &pqErr) && pqErr.Code == queryCanceled) {
+		return domainerror.Wrap(domainerror.Unavailable, err, "query timed out")
+	}
+	return err
+}
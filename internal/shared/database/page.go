@@ -0,0 +1,106 @@
+package database
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+
+	"golang-arch/internal/shared/domain/domainerror"
+)
+
+// Page asks for one page of a keyset-paginated list
+type Page struct {
+	// Limit is the number of rows of the page; 0 or less returns every
+	// remaining row
+	Limit int
+	// After is the Next cursor of the previous page; empty for the first
+	After string
+}
+
+// List is one page of rows
+type List[T any] struct {
+	Items []T `json:"items"`
+	// Next is the cursor of the following page; empty on the last page
+	Next string `json:"next,omitempty"`
+}
+
+// Scanner is satisfied by *sql.Row and *sql.Rows
+type Scanner interface {
+	Scan(dest ...any) error
+}
+
+// QueryPage runs query for one page and scans its rows. The query's
+// OrderBy columns are the keyset; key returns their values for a row, which
+// make up the Next cursor. Cursors are opaque to clients and an invalid one
+// is an Invalid error. query itself is left as it was, so it can be reused
+// for the next page.
+func QueryPage[T any](ctx context.Context, db Querier, query *Query, page Page, scan func(Scanner) (T, error), key func(T) []any) (List[T], error) {
+	query = query.clone()
+	if page.After != "" {
+		values, err := decodeCursor(page.After, query.orders)
+		if err != nil {
+			return List[T]{}, err
+		}
+		query.After(values...)
+	}
+	if page.Limit > 0 {
+		// One more row tells whether another page follows
+		query.Limit(page.Limit + 1)
+	}
+
+	statement, args := query.Build()
+	rows, err := db.QueryContext(ctx, statement, args...)
+	if err != nil {
+		return List[T]{}, MapTimeout(ctx, fmt.Errorf("failed to query %s: %w", query.table, err))
+	}
+	defer rows.Close()
+
+	list := List[T]{Items: []T{}}
+	for rows.Next() {
+		item, err := scan(rows)
+		if err != nil {
+			return List[T]{}, fmt.Errorf("failed to scan %s: %w", query.table, err)
+		}
+		list.Items = append(list.Items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return List[T]{}, MapTimeout(ctx, fmt.Errorf("failed to read %s: %w", query.table, err))
+	}
+
+	if page.Limit > 0 && len(list.Items) > page.Limit {
+		list.Items = list.Items[:page.Limit]
+		if list.Next, err = encodeCursor(key(list.Items[page.Limit-1])); err != nil {
+			return List[T]{}, err
+		}
+	}
+	return list, nil
+}
+
+// encodeCursor encodes keyset values as URL-safe base64 JSON
+func encodeCursor(values []any) (string, error) {
+	data, err := json.Marshal(values)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode cursor: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+// decodeCursor decodes a cursor into values of the ordering columns' types
+func decodeCursor(cursor string, orders []Order) ([]any, error) {
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, domainerror.Invalidf("invalid cursor")
+	}
+	var raw []json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil || len(raw) != len(orders) {
+		return nil, domainerror.Invalidf("invalid cursor")
+	}
+	values := make([]any, len(raw))
+	for i, order := range orders {
+		if values[i], err = order.decode(raw[i]); err != nil {
+			return nil, domainerror.Invalidf("invalid cursor")
+		}
+	}
+	return values, nil
+}
@@ -0,0 +1,361 @@
+package database
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// identifier matches the table and column names queries may use, optionally
+// qualified by a table
+var identifier = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*(\.[A-Za-z_][A-Za-z0-9_]*)?$`)
+
+// mustIdentifier panics unless name is a plain identifier. Names are part of
+// the code, not of the input, so a bad one is a programming error.
+func mustIdentifier(name string) string {
+	if !identifier.MatchString(name) {
+		panic(fmt.Sprintf("database: invalid identifier %q", name))
+	}
+	return name
+}
+
+// Column is a table column holding values of type T. Predicates built from
+// it only accept T, and always pass values as parameters.
+type Column[T any] struct {
+	name string
+}
+
+// NewColumn declares a column; it panics on a name that is not a plain
+// identifier
+func NewColumn[T any](name string) Column[T] {
+	return Column[T]{name: mustIdentifier(name)}
+}
+
+// Name returns the column name
+func (c Column[T]) Name() string {
+	return c.name
+}
+
+// Eq matches rows whose column equals value
+func (c Column[T]) Eq(value T) Predicate { return comparison{c.name, "=", value} }
+
+// Ne matches rows whose column differs from value
+func (c Column[T]) Ne(value T) Predicate { return comparison{c.name, "<>", value} }
+
+// Lt matches rows whose column is less than value
+func (c Column[T]) Lt(value T) Predicate { return comparison{c.name, "<", value} }
+
+// Le matches rows whose column is at most value
+func (c Column[T]) Le(value T) Predicate { return comparison{c.name, "<=", value} }
+
+// Gt matches rows whose column is greater than value
+func (c Column[T]) Gt(value T) Predicate { return comparison{c.name, ">", value} }
+
+// Ge matches rows whose column is at least value
+func (c Column[T]) Ge(value T) Predicate { return comparison{c.name, ">=", value} }
+
+// In matches rows whose column is one of values; none matches no row
+func (c Column[T]) In(values ...T) Predicate {
+	list := make([]any, len(values))
+	for i, value := range values {
+		list[i] = value
+	}
+	return in{c.name, list}
+}
+
+// IsNull matches rows whose column is NULL
+func (c Column[T]) IsNull() Predicate { return null{c.name, true} }
+
+// IsNotNull matches rows whose column is not NULL
+func (c Column[T]) IsNotNull() Predicate { return null{c.name, false} }
+
+// Asc orders by the column, smallest first
+func (c Column[T]) Asc() Order { return Order{column: c.name, decode: decodeAs[T]} }
+
+// Desc orders by the column, largest first
+func (c Column[T]) Desc() Order { return Order{column: c.name, desc: true, decode: decodeAs[T]} }
+
+// Like matches rows whose column matches a LIKE pattern; escape user input
+// with EscapeLike
+func Like(column Column[string], pattern string) Predicate {
+	return comparison{column.name, "LIKE", pattern}
+}
+
+// ILike is Like ignoring case
+func ILike(column Column[string], pattern string) Predicate {
+	return comparison{column.name, "ILIKE", pattern}
+}
+
+// EscapeLike escapes the LIKE wildcards in s, for Contains-style patterns
+// such as "%" + EscapeLike(s) + "%"
+func EscapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
+
+// Predicate is a condition on rows. Predicates only come from typed columns
+// and the combinators of this package, so no input reaches the SQL text.
+type Predicate interface {
+	build(args *arguments) string
+}
+
+// And matches rows matching every predicate; nil predicates are ignored and
+// none matches every row
+func And(predicates ...Predicate) Predicate { return junction{"AND", predicates} }
+
+// Or matches rows matching any predicate; nil predicates are ignored and
+// none matches no row
+func Or(predicates ...Predicate) Predicate { return junction{"OR", predicates} }
+
+// Not matches rows predicate does not match. Not(nil) is nil, so a negated
+// optional filter is ignored like the filter itself.
+func Not(predicate Predicate) Predicate {
+	if predicate == nil {
+		return nil
+	}
+	return negation{predicate}
+}
+
+// Order is a column of an ORDER BY clause, built by Column.Asc or
+// Column.Desc
+type Order struct {
+	column string
+	desc   bool
+	decode func(json.RawMessage) (any, error)
+}
+
+// Query is a SELECT on one table
+type Query struct {
+	table   string
+	columns []string
+	where   []Predicate
+	orders  []Order
+	limit   int
+	limited bool // Limit was called; LIMIT 0 is a valid limit
+	after   []any
+}
+
+// Select starts a query of columns from table; names that are not plain
+// identifiers panic
+func Select(table string, columns ...string) *Query {
+	q := &Query{table: mustIdentifier(table)}
+	for _, column := range columns {
+		q.columns = append(q.columns, mustIdentifier(column))
+	}
+	return q
+}
+
+// Where adds predicates that rows must all match; nil predicates are
+// ignored, so optional filters can be left nil
+func (q *Query) Where(predicates ...Predicate) *Query {
+	q.where = append(q.where, predicates...)
+	return q
+}
+
+// OrderBy sets the order of the rows. Keyset pagination needs an order
+// that is unique and on columns without NULLs, e.g. ending with the key.
+func (q *Query) OrderBy(orders ...Order) *Query {
+	q.orders = orders
+	return q
+}
+
+// Limit caps the number of rows, so Limit(0) returns none. Without it all
+// rows are returned.
+func (q *Query) Limit(n int) *Query {
+	q.limit, q.limited = n, true
+	return q
+}
+
+// After restricts the rows to those ordered after the row whose ordering
+// columns hold values, one per OrderBy column. It panics on a count
+// mismatch.
+func (q *Query) After(values ...any) *Query {
+	if len(values) != len(q.orders) {
+		panic(fmt.Sprintf("database: %d keyset values for %d ordering columns", len(values), len(q.orders)))
+	}
+	q.after = values
+	return q
+}
+
+// clone returns a copy of q that can be changed without affecting q
+func (q *Query) clone() *Query {
+	c := *q
+	c.columns = slices.Clone(q.columns)
+	c.where = slices.Clone(q.where)
+	c.orders = slices.Clone(q.orders)
+	c.after = slices.Clone(q.after)
+	return &c
+}
+
+// Build returns the SQL text and its parameters
+func (q *Query) Build() (string, []any) {
+	var args arguments
+	var sql strings.Builder
+	sql.WriteString("SELECT ")
+	if len(q.columns) == 0 {
+		sql.WriteString("*")
+	}
+	sql.WriteString(strings.Join(q.columns, ", "))
+	sql.WriteString(" FROM ")
+	sql.WriteString(q.table)
+
+	var conditions []string
+	for _, predicate := range q.where {
+		if predicate != nil {
+			conditions = append(conditions, predicate.build(&args))
+		}
+	}
+	if q.after != nil {
+		conditions = append(conditions, keyset{q.orders, q.after}.build(&args))
+	}
+	if len(conditions) > 0 {
+		sql.WriteString(" WHERE ")
+		sql.WriteString(strings.Join(conditions, " AND "))
+	}
+
+	if len(q.orders) > 0 {
+		terms := make([]string, len(q.orders))
+		for i, order := range q.orders {
+			terms[i] = order.column
+			if order.desc {
+				terms[i] += " DESC"
+			}
+		}
+		sql.WriteString(" ORDER BY ")
+		sql.WriteString(strings.Join(terms, ", "))
+	}
+	if q.limited {
+		sql.WriteString(" LIMIT ")
+		sql.WriteString(args.add(q.limit))
+	}
+	return sql.String(), args
+}
+
+// arguments collects the parameters of a query
+type arguments []any
+
+// add appends value and returns its placeholder
+func (a *arguments) add(value any) string {
+	*a = append(*a, value)
+	return "$" + strconv.Itoa(len(*a))
+}
+
+type comparison struct {
+	column   string
+	operator string
+	value    any
+}
+
+func (c comparison) build(args *arguments) string {
+	return c.column + " " + c.operator + " " + args.add(c.value)
+}
+
+type in struct {
+	column string
+	values []any
+}
+
+func (i in) build(args *arguments) string {
+	if len(i.values) == 0 {
+		return "FALSE"
+	}
+	placeholders := make([]string, len(i.values))
+	for n, value := range i.values {
+		placeholders[n] = args.add(value)
+	}
+	return i.column + " IN (" + strings.Join(placeholders, ", ") + ")"
+}
+
+type null struct {
+	column string
+	null   bool
+}
+
+func (n null) build(*arguments) string {
+	if n.null {
+		return n.column + " IS NULL"
+	}
+	return n.column + " IS NOT NULL"
+}
+
+type junction struct {
+	operator   string
+	predicates []Predicate
+}
+
+func (j junction) build(args *arguments) string {
+	var terms []string
+	for _, predicate := range j.predicates {
+		if predicate != nil {
+			terms = append(terms, predicate.build(args))
+		}
+	}
+	switch {
+	case len(terms) == 0 && j.operator == "AND":
+		return "TRUE"
+	case len(terms) == 0:
+		return "FALSE"
+	case len(terms) == 1:
+		return terms[0]
+	}
+	return "(" + strings.Join(terms, " "+j.operator+" ") + ")"
+}
+
+type negation struct {
+	predicate Predicate
+}
+
+func (n negation) build(args *arguments) string {
+	return "NOT (" + n.predicate.build(args) + ")"
+}
+
+// keyset matches the rows after a row in the given order: for columns a, b
+// ascending, a > $1 OR (a = $1 AND b > $2)
+type keyset struct {
+	orders []Order
+	values []any
+}
+
+func (k keyset) build(args *arguments) string {
+	placeholders := make([]string, len(k.values))
+	for i, value := range k.values {
+		placeholders[i] = args.add(value)
+	}
+	alternatives := make([]string, len(k.orders))
+	for i, order := range k.orders {
+		terms := make([]string, 0, i+1)
+		for j := range i {
+			terms = append(terms, k.orders[j].column+" = "+placeholders[j])
+		}
+		operator := " > "
+		if order.desc {
+			operator = " < "
+		}
+		terms = append(terms, order.column+operator+placeholders[i])
+		alternatives[i] = strings.Join(terms, " AND ")
+		if len(terms) > 1 {
+			alternatives[i] = "(" + alternatives[i] + ")"
+		}
+	}
+	if len(alternatives) == 1 {
+		return alternatives[0]
+	}
+	return "(" + strings.Join(alternatives, " OR ") + ")"
+}
+
+// decodeAs decodes a JSON value into a T
+func decodeAs[T any](raw json.RawMessage) (any, error) {
+	var value T
+	if err := json.Unmarshal(raw, &value); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+// ValidIdentifier reports whether name is a plain, optionally
+// table-qualified identifier, safe to splice into SQL text
+func ValidIdentifier(name string) bool {
+	return identifier.MatchString(name)
+}
@@ -0,0 +1,78 @@
+package documents
+
+import (
+	"strings"
+
+	"golang.org/x/text/language"
+	"golang.org/x/text/language/display"
+
+	i18n "golang-arch/internal/shared/domain/internationalization"
+)
+
+// Address is a postal address printed on documents
+type Address struct {
+	Name         string       `json:"name"`
+	Organization string       `json:"organization,omitempty"`
+	Street       []string     `json:"street"`
+	City         string       `json:"city"`
+	Region       string       `json:"region,omitempty"` // State, province or prefecture
+	PostalCode   string       `json:"postal_code,omitempty"`
+	Country      i18n.Country `json:"country"`
+}
+
+// localityFormats order city, region and postal code by country; other
+// countries write "{postal} {city}"
+var localityFormats = map[i18n.Country]string{
+	"US": "{city}, {region} {postal}",
+	"CA": "{city} {region} {postal}",
+	"AU": "{city} {region} {postal}",
+	"GB": "{city}\n{postal}",
+	"IE": "{city}\n{region}\n{postal}",
+	"ID": "{city}\n{region} {postal}",
+	"BR": "{city} - {region}\n{postal}",
+	"JP": "{postal}\n{region} {city}",
+	"IN": "{city} {postal}\n{region}",
+}
+
+// Format returns the lines of the address as written in its country, the
+// last one naming the country in locale's language
+func (a Address) Format(locale i18n.Locale) []string {
+	lines := make([]string, 0, len(a.Street)+4)
+	lines = appendLines(lines, a.Name, a.Organization)
+	lines = appendLines(lines, a.Street...)
+
+	format, ok := localityFormats[a.Country]
+	if !ok {
+		format = "{postal} {city}"
+	}
+	locality := strings.NewReplacer("{city}", a.City, "{region}", a.Region, "{postal}", a.PostalCode).Replace(format)
+	for _, line := range strings.Split(locality, "\n") {
+		lines = appendLines(lines, strings.Trim(strings.Join(strings.Fields(line), " "), " ,-"))
+	}
+
+	if a.Country != "" {
+		lines = append(lines, countryName(a.Country, locale))
+	}
+	return lines
+}
+
+// countryName names country in locale's language, falling back to its code
+func countryName(country i18n.Country, locale i18n.Locale) string {
+	region, err := language.ParseRegion(country.String())
+	if err != nil {
+		return country.String()
+	}
+	if name := display.Regions(language.Make(locale.String())).Name(region); name != "" {
+		return name
+	}
+	return country.String()
+}
+
+func appendLines(lines []string, values ...string) []string {
+	for _, value := range values {
+		if value = strings.TrimSpace(value); value != "" {
+			lines = append(lines, value)
+		}
+	}
+	return lines
+}
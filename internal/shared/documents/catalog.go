@@ -0,0 +1,69 @@
+package documents
+
+import (
+	i18n "golang-arch/internal/shared/domain/internationalization"
+	"golang-arch/internal/shared/templates"
+)
+
+// DefaultCatalog returns the labels of the built-in templates
+func DefaultCatalog() templates.Catalog {
+	return templates.Catalog{
+		"invoice.title": mustLocalized(map[string]string{
+			"en": "Invoice", "de": "Rechnung", "fr": "Facture", "es": "Factura",
+			"id": "Faktur", "pt": "Fatura", "ja": "請求書",
+		}),
+		"invoice.number": mustLocalized(map[string]string{
+			"en": "Invoice number", "de": "Rechnungsnummer", "fr": "Numéro de facture", "es": "Número de factura",
+			"id": "Nomor faktur", "pt": "Número da fatura", "ja": "請求書番号",
+		}),
+		"invoice.issued": mustLocalized(map[string]string{
+			"en": "Issued", "de": "Rechnungsdatum", "fr": "Date d'émission", "es": "Fecha de emisión",
+			"id": "Tanggal terbit", "pt": "Data de emissão", "ja": "発行日",
+		}),
+		"invoice.due": mustLocalized(map[string]string{
+			"en": "Due date", "de": "Fällig am", "fr": "Date d'échéance", "es": "Fecha de vencimiento",
+			"id": "Jatuh tempo", "pt": "Vencimento", "ja": "支払期日",
+		}),
+		"invoice.bill_to": mustLocalized(map[string]string{
+			"en": "Bill to", "de": "Rechnungsempfänger", "fr": "Facturé à", "es": "Facturar a",
+			"id": "Ditagihkan kepada", "pt": "Cobrar de", "ja": "請求先",
+		}),
+		"invoice.description": mustLocalized(map[string]string{
+			"en": "Description", "de": "Beschreibung", "fr": "Description", "es": "Descripción",
+			"id": "Deskripsi", "pt": "Descrição", "ja": "内容",
+		}),
+		"invoice.quantity": mustLocalized(map[string]string{
+			"en": "Qty", "de": "Menge", "fr": "Qté", "es": "Cant.",
+			"id": "Jml", "pt": "Qtd.", "ja": "数量",
+		}),
+		"invoice.unit_price": mustLocalized(map[string]string{
+			"en": "Unit price", "de": "Einzelpreis", "fr": "Prix unitaire", "es": "Precio unitario",
+			"id": "Harga satuan", "pt": "Preço unitário", "ja": "単価",
+		}),
+		"invoice.amount": mustLocalized(map[string]string{
+			"en": "Amount", "de": "Betrag", "fr": "Montant", "es": "Importe",
+			"id": "Jumlah", "pt": "Valor", "ja": "金額",
+		}),
+		"invoice.subtotal": mustLocalized(map[string]string{
+			"en": "Subtotal", "de": "Zwischensumme", "fr": "Sous-total", "es": "Subtotal",
+			"id": "Subtotal", "pt": "Subtotal", "ja": "小計",
+		}),
+		"invoice.tax": mustLocalized(map[string]string{
+			"en": "Tax ({rate}%)", "de": "MwSt. ({rate} %)", "fr": "TVA ({rate} %)", "es": "IVA ({rate} %)",
+			"id": "PPN ({rate}%)", "pt": "Impostos ({rate}%)", "ja": "消費税（{rate}%）",
+		}),
+		"invoice.total": mustLocalized(map[string]string{
+			"en": "Total", "de": "Gesamtbetrag", "fr": "Total", "es": "Total",
+			"id": "Total", "pt": "Total", "ja": "合計",
+		}),
+	}
+}
+
+// mustLocalized builds a LocalizedString from built-in labels
+func mustLocalized(values map[string]string) i18n.LocalizedString {
+	text, err := i18n.NewLocalizedString(values)
+	if err != nil {
+		panic(err)
+	}
+	return *text
+}
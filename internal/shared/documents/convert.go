@@ -0,0 +1,139 @@
+package documents
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"os/exec"
+	"strings"
+	"time"
+
+	"golang-arch/internal/shared/config"
+	"golang-arch/internal/shared/domain/domainerror"
+)
+
+// Converter turns an HTML document into a PDF
+type Converter interface {
+	Convert(ctx context.Context, html io.Reader, pdf io.Writer) error
+}
+
+// NewConverter creates the converter selected by cfg.PDFProvider. It does
+// not contact the converter.
+func NewConverter(cfg config.DocumentsConfig) (Converter, error) {
+	switch cfg.PDFProvider {
+	case "", "none":
+		return unavailableConverter{}, nil
+	case "gotenberg":
+		endpoint, err := url.Parse(cfg.GotenbergURL)
+		if err != nil || endpoint.Host == "" {
+			return nil, fmt.Errorf("pdf provider gotenberg requires a valid gotenberg_url, got %q", cfg.GotenbergURL)
+		}
+		return NewGotenbergConverter(cfg.GotenbergURL, cfg.Timeout), nil
+	case "command":
+		if len(cfg.Command) == 0 {
+			return nil, fmt.Errorf("pdf provider command requires a command")
+		}
+		return NewCommandConverter(cfg.Command, cfg.Timeout), nil
+	default:
+		return nil, fmt.Errorf("unknown pdf provider %q", cfg.PDFProvider)
+	}
+}
+
+// unavailableConverter is used when no PDF provider is configured
+type unavailableConverter struct{}
+
+func (unavailableConverter) Convert(context.Context, io.Reader, io.Writer) error {
+	return domainerror.Unavailablef("PDF rendering is not configured (documents.pdf_provider)")
+}
+
+// GotenbergConverter converts with the Chromium route of a Gotenberg
+// service (https://gotenberg.dev)
+type GotenbergConverter struct {
+	endpoint string
+	client   *http.Client
+}
+
+// NewGotenbergConverter creates a converter for the service at baseURL;
+// a zero timeout waits as long as the context allows
+func NewGotenbergConverter(baseURL string, timeout time.Duration) *GotenbergConverter {
+	return &GotenbergConverter{
+		endpoint: strings.TrimSuffix(baseURL, "/") + "/forms/chromium/convert/html",
+		client:   &http.Client{Timeout: timeout},
+	}
+}
+
+// Convert uploads html as index.html and copies the PDF to pdf
+func (c *GotenbergConverter) Convert(ctx context.Context, html io.Reader, pdf io.Writer) error {
+	body, form := io.Pipe()
+	multipartWriter := multipart.NewWriter(form)
+	go func() {
+		part, err := multipartWriter.CreateFormFile("files", "index.html")
+		if err == nil {
+			_, err = io.Copy(part, html)
+		}
+		if err == nil {
+			err = multipartWriter.Close()
+		}
+		form.CloseWithError(err)
+	}()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.endpoint, body)
+	if err != nil {
+		body.Close()
+		return fmt.Errorf("failed to create gotenberg request: %w", err)
+	}
+	req.Header.Set("Content-Type", multipartWriter.FormDataContentType())
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return domainerror.Unavailablef("gotenberg request failed: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("gotenberg returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(detail)))
+	}
+	if _, err := io.Copy(pdf, resp.Body); err != nil {
+		return fmt.Errorf("failed to read gotenberg response: %w", err)
+	}
+	return nil
+}
+
+// CommandConverter runs a program such as wkhtmltopdf that reads HTML on
+// stdin and writes the PDF to stdout
+type CommandConverter struct {
+	args    []string
+	timeout time.Duration
+}
+
+// NewCommandConverter creates a converter running args; a zero timeout
+// waits as long as the context allows
+func NewCommandConverter(args []string, timeout time.Duration) *CommandConverter {
+	return &CommandConverter{args: args, timeout: timeout}
+}
+
+// Convert runs the command; its stderr is part of the error when it fails
+func (c *CommandConverter) Convert(ctx context.Context, html io.Reader, pdf io.Writer) error {
+	if c.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.timeout)
+		defer cancel()
+	}
+
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, c.args[0], c.args[1:]...)
+	cmd.Stdin = html
+	cmd.Stdout = pdf
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() != nil {
+			return fmt.Errorf("%s: %w", c.args[0], ctx.Err())
+		}
+		return fmt.Errorf("%s failed: %w: %s", c.args[0], err, strings.TrimSpace(stderr.String()))
+	}
+	return nil
+}
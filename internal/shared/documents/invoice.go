@@ -0,0 +1,132 @@
+package documents
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"mime"
+	"regexp"
+	"time"
+
+	"golang-arch/internal/shared/domain/domainerror"
+	i18n "golang-arch/internal/shared/domain/internationalization"
+	"golang-arch/internal/shared/storage"
+)
+
+// InvoiceTemplate is the name of the built-in invoice template
+const InvoiceTemplate = "invoice.html"
+
+// Invoice is the data of an invoice document. Amounts are in Currency;
+// IssuedAt is shown in the customer's timezone.
+type Invoice struct {
+	Number   string          `json:"number"`
+	IssuedAt time.Time       `json:"issued_at"`
+	DueDate  i18n.Date       `json:"due_date"`
+	Seller   Address         `json:"seller"`
+	Customer Address         `json:"customer"`
+	Currency i18n.Currency   `json:"currency"`
+	Lines    []InvoiceLine   `json:"lines"`
+	TaxRate  i18n.Percentage `json:"tax_rate"` // Basis points, e.g. 1100 for 11%
+}
+
+// InvoiceLine is one billed item
+type InvoiceLine struct {
+	Description i18n.LocalizedString `json:"description"`
+	Quantity    int64                `json:"quantity"`
+	UnitPrice   i18n.Money           `json:"unit_price"`
+}
+
+// InvoiceTotals are the computed amounts of an invoice
+type InvoiceTotals struct {
+	Lines    []i18n.Money // Quantity × unit price of each line
+	Subtotal i18n.Money
+	Tax      i18n.Money // Rounded half away from zero
+	Total    i18n.Money
+}
+
+// invoiceNumberPattern keeps invoice numbers usable in blob keys
+var invoiceNumberPattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+// Validate checks the number and that every line is in the invoice currency
+func (inv Invoice) Validate() error {
+	if !invoiceNumberPattern.MatchString(inv.Number) {
+		return domainerror.Invalidf("invalid invoice number %q", inv.Number)
+	}
+	for i, line := range inv.Lines {
+		if line.UnitPrice.Currency.Code != inv.Currency.Code {
+			return domainerror.Invalidf("line %d is in %s, the invoice in %s", i+1, line.UnitPrice.Currency.Code, inv.Currency.Code)
+		}
+	}
+	return nil
+}
+
+// Totals computes line amounts, tax and total
+func (inv Invoice) Totals() (InvoiceTotals, error) {
+	if err := inv.Validate(); err != nil {
+		return InvoiceTotals{}, err
+	}
+	totals := InvoiceTotals{
+		Lines:    make([]i18n.Money, len(inv.Lines)),
+		Subtotal: i18n.Money{Currency: inv.Currency},
+	}
+	for i, line := range inv.Lines {
+		amount, err := line.UnitPrice.Multiply(line.Quantity)
+		if err != nil {
+			return InvoiceTotals{}, err
+		}
+		subtotal, err := totals.Subtotal.Add(amount)
+		if err != nil {
+			return InvoiceTotals{}, err
+		}
+		totals.Lines[i], totals.Subtotal = *amount, *subtotal
+	}
+
+	tax, err := totals.Subtotal.ApplyPercentage(inv.TaxRate, i18n.RoundHalfUp)
+	if err != nil {
+		return InvoiceTotals{}, err
+	}
+	totals.Tax = *tax
+	total, err := totals.Subtotal.Add(&totals.Tax)
+	if err != nil {
+		return InvoiceTotals{}, err
+	}
+	totals.Total = *total
+	return totals, nil
+}
+
+// invoiceView is the data of the invoice template
+type invoiceView struct {
+	Invoice
+	Totals     InvoiceTotals
+	TaxPercent float64
+}
+
+// RenderInvoice renders inv as a PDF for the customer described by prefs
+func (s *Service) RenderInvoice(ctx context.Context, w io.Writer, inv Invoice, prefs i18n.LocalePreferences) error {
+	totals, err := inv.Totals()
+	if err != nil {
+		return err
+	}
+	view := invoiceView{Invoice: inv, Totals: totals, TaxPercent: inv.TaxRate.Percent()}
+	return s.RenderPDF(ctx, w, InvoiceTemplate, prefs, view)
+}
+
+// StoreInvoice renders inv as a PDF straight into blob storage under
+// invoices/<number>.pdf. Nothing is stored when rendering fails.
+func (s *Service) StoreInvoice(ctx context.Context, inv Invoice, prefs i18n.LocalePreferences) (storage.Info, error) {
+	if err := inv.Validate(); err != nil {
+		return storage.Info{}, err
+	}
+	filename := inv.Number + ".pdf"
+	writer := storage.NewWriter(ctx, s.blobs, "invoices/"+filename, storage.PutOptions{
+		ContentType:        "application/pdf",
+		ContentDisposition: mime.FormatMediaType("attachment", map[string]string{"filename": filename}),
+	})
+	if err := s.RenderInvoice(ctx, writer, inv, prefs); err != nil {
+		return storage.Info{}, writer.Abort(err)
+	}
+	if err := writer.Close(); err != nil {
+		return storage.Info{}, fmt.Errorf("failed to store invoice %s: %w", inv.Number, err)
+	}
+	return writer.Info(), nil
+}
@@ -0,0 +1,120 @@
+// Package documents renders customer-facing documents such as invoices from
+// HTML templates and converts them to PDF. Templates format amounts, dates,
+// addresses and labels for the reader's locale, timezone and language.
+package documents
+
+import (
+	"bytes"
+	"context"
+	"embed"
+	"fmt"
+	"html/template"
+	"io"
+	"io/fs"
+
+	i18n "golang-arch/internal/shared/domain/internationalization"
+	"golang-arch/internal/shared/storage"
+	"golang-arch/internal/shared/templates"
+)
+
+//go:embed templates/*.html
+var builtinTemplates embed.FS
+
+// Service renders templates to HTML or PDF and stores generated documents
+type Service struct {
+	templates *template.Template
+	catalog   templates.Catalog
+	converter Converter
+	blobs     storage.BlobStore
+	fsys      []templateSource
+}
+
+type templateSource struct {
+	fsys     fs.FS
+	patterns []string
+}
+
+// Option configures a Service
+type Option func(*Service)
+
+// WithTemplates adds the templates matching patterns in fsys; a template
+// named like a built-in one replaces it
+func WithTemplates(fsys fs.FS, patterns ...string) Option {
+	return func(s *Service) {
+		s.fsys = append(s.fsys, templateSource{fsys: fsys, patterns: patterns})
+	}
+}
+
+// WithCatalog adds labels, replacing built-in ones with the same key
+func WithCatalog(catalog templates.Catalog) Option {
+	return func(s *Service) {
+		s.catalog = s.catalog.Merge(catalog)
+	}
+}
+
+// NewService creates a service converting with converter and storing
+// documents in blobs
+func NewService(converter Converter, blobs storage.BlobStore, options ...Option) (*Service, error) {
+	s := &Service{
+		catalog:   DefaultCatalog(),
+		converter: converter,
+		blobs:     blobs,
+		fsys:      []templateSource{{fsys: builtinTemplates, patterns: []string{"templates/*.html"}}},
+	}
+	for _, option := range options {
+		option(s)
+	}
+
+	// Functions are bound to a reader's formatter when executing
+	s.templates = template.New("documents").Funcs(s.funcs(templates.DefaultPreferences()))
+	for _, source := range s.fsys {
+		if _, err := s.templates.ParseFS(source.fsys, source.patterns...); err != nil {
+			return nil, fmt.Errorf("failed to parse document templates: %w", err)
+		}
+	}
+	return s, nil
+}
+
+// Formatter returns the formatter templates use for prefs
+func (s *Service) Formatter(prefs i18n.LocalePreferences) *templates.Formatter {
+	return templates.NewFormatter(prefs, s.catalog)
+}
+
+// funcs adds "address" to the formatter's functions:
+//
+//	{{ range address .Customer }}{{ . }}<br>{{ end }}
+func (s *Service) funcs(prefs i18n.LocalePreferences) template.FuncMap {
+	funcs := s.Formatter(prefs).FuncMap()
+	funcs["address"] = func(address Address) []string {
+		return address.Format(prefs.Locale)
+	}
+	return funcs
+}
+
+// Render executes the template name for the reader described by prefs and
+// writes the HTML to w
+func (s *Service) Render(w io.Writer, name string, prefs i18n.LocalePreferences, data any) error {
+	tmpl, err := s.templates.Clone()
+	if err != nil {
+		return fmt.Errorf("failed to clone document templates: %w", err)
+	}
+	tmpl.Funcs(s.funcs(prefs))
+	if err := tmpl.ExecuteTemplate(w, name, data); err != nil {
+		return fmt.Errorf("failed to render document %s: %w", name, err)
+	}
+	return nil
+}
+
+// RenderPDF renders the template name and converts it to a PDF written to
+// w. The HTML is rendered completely first, so a template error writes
+// nothing.
+func (s *Service) RenderPDF(ctx context.Context, w io.Writer, name string, prefs i18n.LocalePreferences, data any) error {
+	var html bytes.Buffer
+	if err := s.Render(&html, name, prefs, data); err != nil {
+		return err
+	}
+	if err := s.converter.Convert(ctx, &html, w); err != nil {
+		return fmt.Errorf("failed to convert document %s to PDF: %w", name, err)
+	}
+	return nil
+}
@@ -0,0 +1,137 @@
+// Package domainerror provides a small taxonomy of domain error kinds shared by
+// the domain layer and services.
+//
+// Domain code wraps failures with a Kind (NotFound, Conflict, Invalid,
+// Forbidden, Unavailable) and callers test for it with errors.Is:
+//
+//	if errors.Is(err, domainerror.NotFound) { ... }
+//
+// The API error middleware uses KindOf to map errors to HTTP status codes, so
+// handlers no longer translate errors by hand.
+package domainerror
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Kind classifies a domain error. Kinds are errors themselves so they can be
+// used as errors.Is targets.
+type Kind string
+
+// Domain error kinds
+const (
+	Unknown     Kind = ""
+	NotFound    Kind = "not_found"
+	Conflict    Kind = "conflict"
+	Invalid     Kind = "invalid"
+	Forbidden   Kind = "forbidden"
+	Unavailable Kind = "unavailable"
+)
+
+// Kinds lists every known kind, in the order KindOf checks them
+var Kinds = []Kind{NotFound, Conflict, Invalid, Forbidden, Unavailable}
+
+// Error implements the error interface
+func (k Kind) Error() string {
+	if k == Unknown {
+		return "unknown error"
+	}
+	return strings.ReplaceAll(string(k), "_", " ")
+}
+
+// Error is a domain error tagged with a Kind
+type Error struct {
+	Kind    Kind
+	Message string
+	Err     error
+}
+
+// Error implements the error interface
+func (e *Error) Error() string {
+	switch {
+	case e.Err == nil && e.Message == "":
+		return e.Kind.Error()
+	case e.Err == nil:
+		return e.Message
+	case e.Message == "":
+		return e.Err.Error()
+	}
+	return e.Message + ": " + e.Err.Error()
+}
+
+// Unwrap returns the wrapped error
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether target is the error's Kind
+func (e *Error) Is(target error) bool {
+	kind, ok := target.(Kind)
+	return ok && kind == e.Kind
+}
+
+// New creates a domain error of the given kind
+func New(kind Kind, message string) error {
+	return &Error{Kind: kind, Message: message}
+}
+
+// Newf creates a domain error of the given kind using fmt.Errorf semantics, so
+// %w verbs keep the wrapped error reachable
+func Newf(kind Kind, format string, args ...any) error {
+	return &Error{Kind: kind, Err: fmt.Errorf(format, args...)}
+}
+
+// Wrap tags err with a kind and optional context message; nil stays nil
+func Wrap(kind Kind, err error, message string) error {
+	if err == nil {
+		return nil
+	}
+	return &Error{Kind: kind, Message: message, Err: err}
+}
+
+// NotFoundf creates a NotFound error
+func NotFoundf(format string, args ...any) error {
+	return Newf(NotFound, format, args...)
+}
+
+// Conflictf creates a Conflict error
+func Conflictf(format string, args ...any) error {
+	return Newf(Conflict, format, args...)
+}
+
+// Invalidf creates an Invalid error
+func Invalidf(format string, args ...any) error {
+	return Newf(Invalid, format, args...)
+}
+
+// Forbiddenf creates a Forbidden error
+func Forbiddenf(format string, args ...any) error {
+	return Newf(Forbidden, format, args...)
+}
+
+// Unavailablef creates an Unavailable error
+func Unavailablef(format string, args ...any) error {
+	return Newf(Unavailable, format, args...)
+}
+
+// KindOf returns the kind of the outermost domain error in err's chain, or
+// the first kind err matches via errors.Is; Unknown when none applies
+func KindOf(err error) Kind {
+	if err == nil {
+		return Unknown
+	}
+
+	var domainErr *Error
+	if errors.As(err, &domainErr) && domainErr.Kind != Unknown {
+		return domainErr.Kind
+	}
+
+	for _, kind := range Kinds {
+		if errors.Is(err, kind) {
+			return kind
+		}
+	}
+	return Unknown
+}
@@ -0,0 +1,129 @@
+// Package internationalization provides domain types for handling internationalization
+// concerns such as time, currency, timezone, and phone number formatting.
+//
+// This file holds the AppendFormat variants of the formatting methods. They
+// append to a caller-supplied buffer instead of returning a new string, so bulk
+// exports (CSV, reports) can format millions of values into one reused buffer
+// without per-value allocations:
+//
+//	buf := make([]byte, 0, 4096)
+//	for _, row := range rows {
+//	    buf = row.Price.AppendFormat(buf[:0])
+//	    w.Write(buf)
+//	}
+//
+// The string-returning methods are built on the same code and borrow their
+// scratch buffers from a sync.Pool.
+package internationalization
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// AppendFormat appends the amount formatted with the currency symbol, as
+// returned by Format, to dst.
+func (c Currency) AppendFormat(dst []byte, amount int64) []byte {
+	if amount < 0 {
+		dst = append(dst, '-')
+	}
+	dst = append(dst, c.Symbol...)
+	return appendMinorUnits(dst, amount, c.DecimalPlaces)
+}
+
+// AppendFormat appends the money value formatted with its currency symbol, as
+// returned by Format, to dst.
+func (m Money) AppendFormat(dst []byte) []byte {
+	return m.Currency.AppendFormat(dst, m.Amount)
+}
+
+// AppendFormat appends the phone number in international format, as returned
+// by Format, to dst.
+func (p Phone) AppendFormat(dst []byte) []byte {
+	dst = append(dst, '+')
+	dst = append(dst, p.CountryCode...)
+	dst = append(dst, ' ')
+	return append(dst, p.Number...)
+}
+
+// AppendFormat appends the time formatted with layout in the given timezone
+// (UTC when nil), as returned by Format, to dst.
+func (t Time) AppendFormat(dst []byte, layout string, tz *Timezone) []byte {
+	timeValue := t.ToTime()
+	if tz != nil {
+		timeValue = timeValue.In(fixedZone(tz.ID, tz.Offset))
+	} else {
+		timeValue = timeValue.UTC()
+	}
+	return timeValue.AppendFormat(dst, layout)
+}
+
+// AppendFormat appends the datetime formatted with layout in its timezone, as
+// returned by Format, to dst.
+func (ldt LocalizedDateTime) AppendFormat(dst []byte, layout string) []byte {
+	return ldt.Time.AppendFormat(dst, layout, &ldt.Timezone)
+}
+
+// fixedZoneKey identifies a fixed-offset location by name and offset in minutes
+type fixedZoneKey struct {
+	id     string
+	offset int
+}
+
+// maxFixedZones caps the fixedZone cache. Timezone values are not always
+// validated (they can be decoded from any JSON), so the cache holds at most
+// this many ID and offset pairs and builds the others on each call.
+const maxFixedZones = 1024
+
+// fixedZones caches the locations built by fixedZone; fixedZoneCount
+// counts its entries
+var (
+	fixedZones     sync.Map
+	fixedZoneCount atomic.Int64
+)
+
+// fixedZone returns a time.FixedZone for the timezone ID and offset (in
+// minutes), shared with earlier calls while the cache has room. Offsets
+// outside the ±24h Timezone.Validate accepts are never cached.
+func fixedZone(id string, offsetMinutes int) *time.Location {
+	key := fixedZoneKey{id: id, offset: offsetMinutes}
+	if loc, ok := fixedZones.Load(key); ok {
+		return loc.(*time.Location)
+	}
+
+	loc := time.FixedZone(id, offsetMinutes*60) // Convert minutes to seconds
+	if offsetMinutes <= -1440 || offsetMinutes >= 1440 || fixedZoneCount.Load() >= maxFixedZones {
+		return loc
+	}
+	cached, loaded := fixedZones.LoadOrStore(key, loc)
+	if !loaded {
+		fixedZoneCount.Add(1)
+	}
+	return cached.(*time.Location)
+}
+
+// maxPooledBufferSize keeps unusually large buffers out of the pool
+const maxPooledBufferSize = 4 << 10
+
+var formatBufferPool = sync.Pool{
+	New: func() any {
+		buf := make([]byte, 0, 128)
+		return &buf
+	},
+}
+
+// getFormatBuffer borrows an empty scratch buffer from the pool
+func getFormatBuffer() *[]byte {
+	buf := formatBufferPool.Get().(*[]byte)
+	*buf = (*buf)[:0]
+	return buf
+}
+
+// putFormatBuffer returns a scratch buffer to the pool
+func putFormatBuffer(buf *[]byte) {
+	if cap(*buf) > maxPooledBufferSize {
+		return
+	}
+	formatBufferPool.Put(buf)
+}
@@ -0,0 +1,133 @@
+// Package internationalization provides domain types and composite types for handling
+// internationalized data such as time, currency, timezone, and phone numbers.
+// This file contains text direction metadata and bidi helpers for
+// right-to-left languages.
+//
+// TextDirection:
+//   - Language.Direction and Locale.Direction (a script subtag wins, so
+//     "pa-Arab" is right-to-left while "pa" is not)
+//   - DetectDirection from the first strong character of a text
+//
+// Bidi Wrapping:
+//   - WrapBidi surrounds a value with LRM or RLM marks when its direction
+//     differs from the template's, so "+62 812-3456-7890" or "$1,234.50"
+//     keep their order inside Arabic or Hebrew text
+//
+// Usage Examples:
+//
+//	dir := MustParseLocale("ar-AE").Direction() // TextRTL
+//	msg := "رقم الهاتف: " + WrapBidi(phone.Format(), dir)
+package internationalization
+
+import (
+	"golang.org/x/text/unicode/bidi"
+)
+
+// TextDirection is the writing direction of a language or text.
+type TextDirection string
+
+// Writing directions
+const (
+	TextLTR TextDirection = "ltr"
+	TextRTL TextDirection = "rtl"
+)
+
+// Unicode directional marks
+const (
+	LeftToRightMark = "\u200E" // LRM
+	RightToLeftMark = "\u200F" // RLM
+)
+
+// rtlLanguages lists the languages written right to left in their default script
+var rtlLanguages = map[Language]bool{
+	"ar": true, "arc": true, "ckb": true, "dv": true, "fa": true, "he": true,
+	"iw": true, "ji": true, "ks": true, "ps": true, "sd": true, "syr": true,
+	"ug": true, "ur": true, "yi": true,
+}
+
+// rtlScripts lists the ISO 15924 scripts written right to left
+var rtlScripts = map[string]bool{
+	"Adlm": true, "Arab": true, "Hebr": true, "Mand": true, "Nkoo": true,
+	"Rohg": true, "Samr": true, "Syrc": true, "Thaa": true,
+}
+
+// Direction returns the default writing direction of the language.
+func (l Language) Direction() TextDirection {
+	if rtlLanguages[l] {
+		return TextRTL
+	}
+	return TextLTR
+}
+
+// Direction returns the writing direction of the locale, taken from its
+// script subtag when it has one and from its language otherwise.
+func (l Locale) Direction() TextDirection {
+	if script := l.Script(); script != "" {
+		if rtlScripts[script] {
+			return TextRTL
+		}
+		return TextLTR
+	}
+	return l.Language().Direction()
+}
+
+// Mark returns the directional mark of the direction, LRM or RLM.
+func (d TextDirection) Mark() string {
+	if d == TextRTL {
+		return RightToLeftMark
+	}
+	return LeftToRightMark
+}
+
+// IsRTL reports whether the direction is right to left.
+func (d TextDirection) IsRTL() bool {
+	return d == TextRTL
+}
+
+// String returns "ltr" or "rtl", as used by the HTML dir attribute.
+func (d TextDirection) String() string {
+	return string(d)
+}
+
+// DetectDirection returns the direction of the first strongly directional
+// character in text, following rules P2 and P3 of the Unicode bidi
+// algorithm. ok is false when text has no strong character, e.g. a phone
+// number or an amount without a currency name.
+func DetectDirection(text string) (direction TextDirection, ok bool) {
+	for i := 0; i < len(text); {
+		props, size := bidi.LookupString(text[i:])
+		if size == 0 {
+			size = 1
+		}
+		switch props.Class() {
+		case bidi.L:
+			return TextLTR, true
+		case bidi.R, bidi.AL:
+			return TextRTL, true
+		}
+		i += size
+	}
+	return "", false
+}
+
+// WrapBidi prepares value for insertion into a template written in the
+// context direction. A value whose direction differs from the context is
+// surrounded by the mark of its own direction, so neutral characters such
+// as "+", "-" and spaces stay with it and it is laid out as one run. Values
+// without strong characters (phone numbers, amounts) are treated as
+// left to right, as is an empty context. Values already matching the
+// context are returned as is.
+func WrapBidi(value string, context TextDirection) string {
+	if context != TextRTL {
+		context = TextLTR
+	}
+	direction, ok := DetectDirection(value)
+	if !ok {
+		direction = TextLTR
+	}
+	if value == "" || direction == context {
+		return value
+	}
+	mark := direction.Mark()
+	return mark + value + mark
+}
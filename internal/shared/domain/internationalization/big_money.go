@@ -0,0 +1,292 @@
+// Package internationalization provides domain types for handling internationalization
+// concerns such as time, currency, timezone, and phone number formatting.
+//
+// This file holds BigMoney, the arbitrary-precision counterpart of Money. An
+// int64 of minor units tops out around 9.22 ETH in wei (18 decimal places),
+// so crypto balances and their sums need a big.Int amount. BigMoney has the
+// same arithmetic, formatting and JSON surface as Money; convert with ToBig
+// and ToMoney at the boundary to code that takes Money.
+package internationalization
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"golang-arch/internal/shared/domain/domainerror"
+)
+
+// BigMoney is an amount of minor units of any size in a currency. Like Money
+// it is an immutable value: the amount is never modified once set, and
+// Amount returns a copy.
+type BigMoney struct {
+	amount   *big.Int // nil is zero
+	Currency Currency
+}
+
+// NewBigMoney creates a BigMoney value from an amount in minor units, e.g.
+// wei for ETH. The amount is copied.
+func NewBigMoney(amount *big.Int, currency Currency) (*BigMoney, error) {
+	if amount == nil {
+		return nil, domainerror.Invalidf("money amount is required")
+	}
+	if err := currency.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid currency for money: %w", err)
+	}
+	return &BigMoney{amount: new(big.Int).Set(amount), Currency: currency}, nil
+}
+
+// NewBigMoneyFromPrimitive creates a BigMoney value from the decimal string
+// of an amount in minor units, as stored in a NUMERIC column, and an ISO 4217
+// code
+func NewBigMoneyFromPrimitive(amount, currencyCode string) (*BigMoney, error) {
+	currency, err := NewCurrencyFromCode(currencyCode)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create money from primitive: %w", err)
+	}
+	value, ok := new(big.Int).SetString(amount, 10)
+	if !ok {
+		return nil, domainerror.Invalidf("invalid amount: %q", amount)
+	}
+	return &BigMoney{amount: value, Currency: *currency}, nil
+}
+
+// ParseBigMoney parses a decimal amount and an ISO 4217 code, as ParseMoney
+// does, without an upper bound, e.g. "12.5 ETH"
+func ParseBigMoney(value string) (*BigMoney, error) {
+	fields := strings.Fields(value)
+	if len(fields) != 2 {
+		return nil, domainerror.Invalidf("invalid money format: %q (expected \"<amount> <currency>\")", value)
+	}
+
+	amountStr, code := fields[0], fields[1]
+	if isCurrencyCodeLike(amountStr) {
+		amountStr, code = code, amountStr
+	}
+
+	currency, err := NewCurrencyFromCode(code)
+	if err != nil {
+		return nil, fmt.Errorf("invalid money %q: %w", value, err)
+	}
+	amount, err := parseBigMinorUnits(amountStr, currency.DecimalPlaces)
+	if err != nil {
+		return nil, fmt.Errorf("invalid money %q: %w", value, err)
+	}
+	return &BigMoney{amount: amount, Currency: *currency}, nil
+}
+
+// ToBig returns the money value as a BigMoney
+func (m Money) ToBig() BigMoney {
+	return BigMoney{amount: big.NewInt(m.Amount), Currency: m.Currency}
+}
+
+// ToMoney returns the value as Money, failing when the amount does not fit
+// an int64
+func (b BigMoney) ToMoney() (*Money, error) {
+	amount := b.value()
+	if !amount.IsInt64() {
+		return nil, domainerror.Invalidf("money amount %s %s overflows int64 minor units", amount, b.Currency.Code)
+	}
+	return NewMoneyFromInteger(amount.Int64(), b.Currency)
+}
+
+// Amount returns a copy of the amount in minor units
+func (b BigMoney) Amount() *big.Int {
+	return new(big.Int).Set(b.value())
+}
+
+// value returns the amount, which callers must not modify
+func (b BigMoney) value() *big.Int {
+	if b.amount == nil {
+		return new(big.Int)
+	}
+	return b.amount
+}
+
+// ToPrimitive returns the amount as a decimal string of minor units, for a
+// NUMERIC column, and the currency code
+func (b BigMoney) ToPrimitive() (string, string) {
+	return b.value().String(), b.Currency.ToPrimitive()
+}
+
+// Validate ensures the currency is valid
+func (b BigMoney) Validate() error {
+	return Money{Currency: b.Currency}.Validate()
+}
+
+// Add adds another value in the same currency
+func (b BigMoney) Add(other BigMoney) (*BigMoney, error) {
+	if b.Currency.Code != other.Currency.Code {
+		return nil, domainerror.Invalidf("cannot add money with different currencies: %s and %s",
+			b.Currency.Code, other.Currency.Code)
+	}
+	return &BigMoney{amount: new(big.Int).Add(b.value(), other.value()), Currency: b.Currency}, nil
+}
+
+// Subtract subtracts another value in the same currency
+func (b BigMoney) Subtract(other BigMoney) (*BigMoney, error) {
+	if b.Currency.Code != other.Currency.Code {
+		return nil, domainerror.Invalidf("cannot subtract money with different currencies: %s and %s",
+			b.Currency.Code, other.Currency.Code)
+	}
+	return &BigMoney{amount: new(big.Int).Sub(b.value(), other.value()), Currency: b.Currency}, nil
+}
+
+// Multiply multiplies the amount by an integer factor
+func (b BigMoney) Multiply(factor int64) *BigMoney {
+	return &BigMoney{amount: new(big.Int).Mul(b.value(), big.NewInt(factor)), Currency: b.Currency}
+}
+
+// Divide divides the amount by divisor and rounds the exact quotient with
+// mode, as Money.Divide does
+func (b BigMoney) Divide(divisor int64, mode RoundingMode) (*BigMoney, error) {
+	if mode == nil {
+		return nil, domainerror.Invalidf("rounding mode is required")
+	}
+	if divisor == 0 {
+		return nil, domainerror.Invalidf("division by zero")
+	}
+	num, den := b.value(), big.NewInt(divisor)
+	if divisor < 0 {
+		num, den = new(big.Int).Neg(num), den.Neg(den)
+	}
+	return &BigMoney{amount: mode.Round(num, den), Currency: b.Currency}, nil
+}
+
+// Cmp compares the amounts of two values in the same currency, returning
+// -1, 0 or +1
+func (b BigMoney) Cmp(other BigMoney) (int, error) {
+	if b.Currency.Code != other.Currency.Code {
+		return 0, domainerror.Invalidf("cannot compare money with different currencies: %s and %s",
+			b.Currency.Code, other.Currency.Code)
+	}
+	return b.value().Cmp(other.value()), nil
+}
+
+// IsZero returns true if the amount is zero
+func (b BigMoney) IsZero() bool {
+	return b.value().Sign() == 0
+}
+
+// IsPositive returns true if the amount is positive
+func (b BigMoney) IsPositive() bool {
+	return b.value().Sign() > 0
+}
+
+// IsNegative returns true if the amount is negative
+func (b BigMoney) IsNegative() bool {
+	return b.value().Sign() < 0
+}
+
+// Equal returns true if both values have the same amount and currency
+func (b *BigMoney) Equal(other *BigMoney) bool {
+	if b == nil || other == nil {
+		return b == other
+	}
+	return b.Currency.Code == other.Currency.Code && b.value().Cmp(other.value()) == 0
+}
+
+// digits returns the decimal digits of the absolute amount
+func (b BigMoney) digits() []byte {
+	return new(big.Int).Abs(b.value()).Append(nil, 10)
+}
+
+// appendSign appends "-" for negative amounts
+func (b BigMoney) appendSign(dst []byte) []byte {
+	if b.IsNegative() {
+		return append(dst, '-')
+	}
+	return dst
+}
+
+// DecimalString returns the exact decimal amount without symbol or code,
+// e.g. "12.500000000000000000" for 12.5 ETH
+func (b BigMoney) DecimalString() string {
+	return string(appendDigitsWithPoint(b.appendSign(nil), b.digits(), b.Currency.DecimalPlaces))
+}
+
+// Format formats the amount with the currency symbol, as Money.Format does
+func (b BigMoney) Format() string {
+	dst := append(b.appendSign(nil), b.Currency.Symbol...)
+	return string(appendDigitsWithPoint(dst, b.digits(), b.Currency.DecimalPlaces))
+}
+
+// FormatWithCode formats the amount with the currency code, e.g.
+// "12.500000000000000000 ETH"
+func (b BigMoney) FormatWithCode() string {
+	return b.DecimalString() + " " + b.Currency.Code
+}
+
+// FormatLocalized formats the amount for display in locale, as
+// Currency.FormatLocalized does
+func (b BigMoney) FormatLocalized(locale Locale) string {
+	symbols := NumberSymbolsFor(locale)
+	symbol := b.Currency.Symbol
+	if symbol == "" {
+		symbol = b.Currency.Code
+	}
+
+	dst := b.appendSign(nil)
+	if !symbols.SymbolAfter {
+		dst = append(dst, symbol...)
+	}
+	dst = appendGroupedDigits(dst, b.digits(), b.Currency.DecimalPlaces, symbols)
+	if symbols.SymbolAfter {
+		dst = append(dst, noBreakSpace...)
+		dst = append(dst, symbol...)
+	}
+	return string(dst)
+}
+
+// String returns the formatted amount
+func (b BigMoney) String() string {
+	return b.Format()
+}
+
+// ToDecimal returns the amount in major units as a float64, for display
+// only, as it loses precision
+func (b BigMoney) ToDecimal() float64 {
+	f, _ := new(big.Rat).SetFrac(b.value(), bigPow10(b.Currency.DecimalPlaces)).Float64()
+	return f
+}
+
+// MarshalJSON encodes the value in Money's shape, with the amount as an
+// integer of any size: {"amount":12500000000000000000,"decimal":12.5,...}
+func (b BigMoney) MarshalJSON() ([]byte, error) {
+	dst := append(make([]byte, 0, 192), `{"amount":`...)
+	dst = b.value().Append(dst, 10)
+	dst = append(dst, `,"decimal":`...)
+	dst = appendJSONFloat(dst, b.ToDecimal())
+	dst = append(dst, `,"currency":`...)
+	dst = b.Currency.AppendJSON(dst)
+	return append(dst, '}'), nil
+}
+
+// UnmarshalJSON decodes a Money or BigMoney payload. The amount must be an
+// integer of minor units, written as a number or, for clients whose numbers
+// are doubles, as a decimal string; "decimal" is ignored.
+func (b *BigMoney) UnmarshalJSON(data []byte) error {
+	var payload struct {
+		Amount   json.RawMessage `json:"amount"`
+		Currency Currency        `json:"currency"`
+	}
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return domainerror.Invalidf("failed to unmarshal money: %w", err)
+	}
+
+	raw := string(payload.Amount)
+	if unquoted, ok := strings.CutPrefix(raw, `"`); ok {
+		raw = strings.TrimSuffix(unquoted, `"`)
+	}
+	amount, ok := new(big.Int).SetString(raw, 10)
+	if !ok {
+		return domainerror.Invalidf("money amount %s is not an integer number of minor units", payload.Amount)
+	}
+	decoded, err := NewBigMoney(amount, payload.Currency)
+	if err != nil {
+		return err
+	}
+	*b = *decoded
+	return nil
+}
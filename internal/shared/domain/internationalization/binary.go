@@ -0,0 +1,341 @@
+// Package internationalization provides domain types for handling internationalization
+// concerns such as time, currency, timezone, and phone number formatting.
+//
+// This file holds the MessagePack and CBOR encodings of the value objects. Both
+// use the same compact form, storing only what is needed to rebuild the value:
+//
+//	Currency           "USD"
+//	Time               1703520000
+//	Timezone           "America/New_York"
+//	Money              [10050, "USD"]
+//	Phone              ["1", "5551234567"]
+//	LocalizedDateTime  [1703520000, "America/New_York"]
+//	LocalizedPhone     ["1", "5551234567", "United States", "New York", "America/New_York"]
+//
+// Currencies and timezones are rebuilt from their codes on decode, so custom
+// currencies that are not in the supported table cannot be decoded.
+package internationalization
+
+import (
+	"github.com/fxamacker/cbor/v2"
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+// moneyWire is the compact array form of Money
+type moneyWire struct {
+	_msgpack struct{} `msgpack:",as_array"`
+	_        struct{} `cbor:",toarray"`
+	Amount   int64
+	Currency string
+}
+
+// phoneWire is the compact array form of Phone
+type phoneWire struct {
+	_msgpack    struct{} `msgpack:",as_array"`
+	_           struct{} `cbor:",toarray"`
+	CountryCode string
+	Number      string
+}
+
+// localizedDateTimeWire is the compact array form of LocalizedDateTime
+type localizedDateTimeWire struct {
+	_msgpack struct{} `msgpack:",as_array"`
+	_        struct{} `cbor:",toarray"`
+	Epoch    int64
+	Timezone string
+}
+
+// localizedPhoneWire is the compact array form of LocalizedPhone
+type localizedPhoneWire struct {
+	_msgpack    struct{} `msgpack:",as_array"`
+	_           struct{} `cbor:",toarray"`
+	CountryCode string
+	Number      string
+	Country     string
+	Region      string
+	Timezone    string
+}
+
+func (m Money) wire() moneyWire {
+	return moneyWire{Amount: m.Amount, Currency: m.Currency.Code}
+}
+
+func (w moneyWire) money() (Money, error) {
+	return MakeMoney(w.Amount, w.Currency)
+}
+
+func (p Phone) wire() phoneWire {
+	return phoneWire{CountryCode: p.CountryCode, Number: p.Number}
+}
+
+func (w phoneWire) phone() (Phone, error) {
+	return MakePhone(w.CountryCode, w.Number)
+}
+
+func (ldt LocalizedDateTime) wire() localizedDateTimeWire {
+	return localizedDateTimeWire{Epoch: ldt.Time.Epoch, Timezone: ldt.Timezone.ID}
+}
+
+func (w localizedDateTimeWire) localizedDateTime() (LocalizedDateTime, error) {
+	return MakeLocalizedDateTime(w.Epoch, w.Timezone)
+}
+
+func (lp LocalizedPhone) wire() localizedPhoneWire {
+	return localizedPhoneWire{
+		CountryCode: lp.Phone.CountryCode,
+		Number:      lp.Phone.Number,
+		Country:     lp.Country,
+		Region:      lp.Region,
+		Timezone:    lp.Timezone.ID,
+	}
+}
+
+func (w localizedPhoneWire) localizedPhone() (LocalizedPhone, error) {
+	phone, err := MakePhone(w.CountryCode, w.Number)
+	if err != nil {
+		return LocalizedPhone{}, err
+	}
+	tz, err := MakeTimezone(w.Timezone)
+	if err != nil {
+		return LocalizedPhone{}, err
+	}
+
+	lp, err := NewLocalizedPhone(phone, w.Country, w.Region, tz)
+	if err != nil {
+		return LocalizedPhone{}, err
+	}
+	return *lp, nil
+}
+
+// EncodeMsgpack implements msgpack.CustomEncoder.
+func (c Currency) EncodeMsgpack(enc *msgpack.Encoder) error {
+	return enc.EncodeString(c.Code)
+}
+
+// DecodeMsgpack implements msgpack.CustomDecoder.
+func (c *Currency) DecodeMsgpack(dec *msgpack.Decoder) error {
+	code, err := dec.DecodeString()
+	if err != nil {
+		return err
+	}
+	return c.setCode(code)
+}
+
+// MarshalCBOR implements cbor.Marshaler.
+func (c Currency) MarshalCBOR() ([]byte, error) {
+	return cbor.Marshal(c.Code)
+}
+
+// UnmarshalCBOR implements cbor.Unmarshaler.
+func (c *Currency) UnmarshalCBOR(data []byte) error {
+	var code string
+	if err := cbor.Unmarshal(data, &code); err != nil {
+		return err
+	}
+	return c.setCode(code)
+}
+
+func (c *Currency) setCode(code string) error {
+	currency, err := NewCurrencyFromCode(code)
+	if err != nil {
+		return err
+	}
+	*c = *currency
+	return nil
+}
+
+// EncodeMsgpack implements msgpack.CustomEncoder.
+func (t Time) EncodeMsgpack(enc *msgpack.Encoder) error {
+	return enc.EncodeInt(t.Epoch)
+}
+
+// DecodeMsgpack implements msgpack.CustomDecoder.
+func (t *Time) DecodeMsgpack(dec *msgpack.Decoder) error {
+	epoch, err := dec.DecodeInt64()
+	if err != nil {
+		return err
+	}
+	return t.setEpoch(epoch)
+}
+
+// MarshalCBOR implements cbor.Marshaler.
+func (t Time) MarshalCBOR() ([]byte, error) {
+	return cbor.Marshal(t.Epoch)
+}
+
+// UnmarshalCBOR implements cbor.Unmarshaler.
+func (t *Time) UnmarshalCBOR(data []byte) error {
+	var epoch int64
+	if err := cbor.Unmarshal(data, &epoch); err != nil {
+		return err
+	}
+	return t.setEpoch(epoch)
+}
+
+func (t *Time) setEpoch(epoch int64) error {
+	decoded, err := MakeTime(epoch)
+	if err != nil {
+		return err
+	}
+	*t = decoded
+	return nil
+}
+
+// EncodeMsgpack implements msgpack.CustomEncoder.
+func (tz Timezone) EncodeMsgpack(enc *msgpack.Encoder) error {
+	return enc.EncodeString(tz.ID)
+}
+
+// DecodeMsgpack implements msgpack.CustomDecoder.
+func (tz *Timezone) DecodeMsgpack(dec *msgpack.Decoder) error {
+	id, err := dec.DecodeString()
+	if err != nil {
+		return err
+	}
+	return tz.setID(id)
+}
+
+// MarshalCBOR implements cbor.Marshaler.
+func (tz Timezone) MarshalCBOR() ([]byte, error) {
+	return cbor.Marshal(tz.ID)
+}
+
+// UnmarshalCBOR implements cbor.Unmarshaler.
+func (tz *Timezone) UnmarshalCBOR(data []byte) error {
+	var id string
+	if err := cbor.Unmarshal(data, &id); err != nil {
+		return err
+	}
+	return tz.setID(id)
+}
+
+func (tz *Timezone) setID(id string) error {
+	decoded, err := MakeTimezone(id)
+	if err != nil {
+		return err
+	}
+	*tz = decoded
+	return nil
+}
+
+// EncodeMsgpack implements msgpack.CustomEncoder.
+func (m Money) EncodeMsgpack(enc *msgpack.Encoder) error {
+	return enc.Encode(m.wire())
+}
+
+// DecodeMsgpack implements msgpack.CustomDecoder.
+func (m *Money) DecodeMsgpack(dec *msgpack.Decoder) error {
+	var w moneyWire
+	if err := dec.Decode(&w); err != nil {
+		return err
+	}
+	return decodeWire(m, w.money)
+}
+
+// MarshalCBOR implements cbor.Marshaler.
+func (m Money) MarshalCBOR() ([]byte, error) {
+	return cbor.Marshal(m.wire())
+}
+
+// UnmarshalCBOR implements cbor.Unmarshaler.
+func (m *Money) UnmarshalCBOR(data []byte) error {
+	var w moneyWire
+	if err := cbor.Unmarshal(data, &w); err != nil {
+		return err
+	}
+	return decodeWire(m, w.money)
+}
+
+// EncodeMsgpack implements msgpack.CustomEncoder.
+func (p Phone) EncodeMsgpack(enc *msgpack.Encoder) error {
+	return enc.Encode(p.wire())
+}
+
+// DecodeMsgpack implements msgpack.CustomDecoder.
+func (p *Phone) DecodeMsgpack(dec *msgpack.Decoder) error {
+	var w phoneWire
+	if err := dec.Decode(&w); err != nil {
+		return err
+	}
+	return decodeWire(p, w.phone)
+}
+
+// MarshalCBOR implements cbor.Marshaler.
+func (p Phone) MarshalCBOR() ([]byte, error) {
+	return cbor.Marshal(p.wire())
+}
+
+// UnmarshalCBOR implements cbor.Unmarshaler.
+func (p *Phone) UnmarshalCBOR(data []byte) error {
+	var w phoneWire
+	if err := cbor.Unmarshal(data, &w); err != nil {
+		return err
+	}
+	return decodeWire(p, w.phone)
+}
+
+// EncodeMsgpack implements msgpack.CustomEncoder.
+func (ldt LocalizedDateTime) EncodeMsgpack(enc *msgpack.Encoder) error {
+	return enc.Encode(ldt.wire())
+}
+
+// DecodeMsgpack implements msgpack.CustomDecoder.
+func (ldt *LocalizedDateTime) DecodeMsgpack(dec *msgpack.Decoder) error {
+	var w localizedDateTimeWire
+	if err := dec.Decode(&w); err != nil {
+		return err
+	}
+	return decodeWire(ldt, w.localizedDateTime)
+}
+
+// MarshalCBOR implements cbor.Marshaler.
+func (ldt LocalizedDateTime) MarshalCBOR() ([]byte, error) {
+	return cbor.Marshal(ldt.wire())
+}
+
+// UnmarshalCBOR implements cbor.Unmarshaler.
+func (ldt *LocalizedDateTime) UnmarshalCBOR(data []byte) error {
+	var w localizedDateTimeWire
+	if err := cbor.Unmarshal(data, &w); err != nil {
+		return err
+	}
+	return decodeWire(ldt, w.localizedDateTime)
+}
+
+// EncodeMsgpack implements msgpack.CustomEncoder.
+func (lp LocalizedPhone) EncodeMsgpack(enc *msgpack.Encoder) error {
+	return enc.Encode(lp.wire())
+}
+
+// DecodeMsgpack implements msgpack.CustomDecoder.
+func (lp *LocalizedPhone) DecodeMsgpack(dec *msgpack.Decoder) error {
+	var w localizedPhoneWire
+	if err := dec.Decode(&w); err != nil {
+		return err
+	}
+	return decodeWire(lp, w.localizedPhone)
+}
+
+// MarshalCBOR implements cbor.Marshaler.
+func (lp LocalizedPhone) MarshalCBOR() ([]byte, error) {
+	return cbor.Marshal(lp.wire())
+}
+
+// UnmarshalCBOR implements cbor.Unmarshaler.
+func (lp *LocalizedPhone) UnmarshalCBOR(data []byte) error {
+	var w localizedPhoneWire
+	if err := cbor.Unmarshal(data, &w); err != nil {
+		return err
+	}
+	return decodeWire(lp, w.localizedPhone)
+}
+
+// decodeWire rebuilds a value from its wire form and stores it in dst
+func decodeWire[T any](dst *T, build func() (T, error)) error {
+	value, err := build()
+	if err != nil {
+		return err
+	}
+	*dst = value
+	return nil
+}
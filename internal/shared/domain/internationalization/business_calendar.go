@@ -0,0 +1,289 @@
+// Package internationalization provides domain types and composite types for handling
+// internationalized data such as time, currency, timezone, and phone numbers.
+// This file contains the BusinessCalendar type for SLA and due-date
+// calculations.
+//
+// BusinessCalendar Type:
+//   - Working hours on business days, in one timezone (e.g. 09:00–17:00 Berlin)
+//   - Weekends from a WeekDefinition, plus dated holidays
+//   - DueDate adds working time ("respond within 8 business hours")
+//   - WorkingTimeBetween measures elapsed working time for SLA reporting
+//   - DST-aware: hours follow the local clock
+//
+// JSON Format: {"timezone": "Europe/Berlin", "start": "09:00", "end": "17:00",
+// "week": {...}, "holidays": {"2025-12-25": "Christmas Day"}}
+//
+// Usage Examples:
+//
+//	calendar, err := NewBusinessCalendar(*berlin, Country("DE").Week(), "09:00", "17:00")
+//	calendar, err = calendar.WithHoliday("2025-12-25", "Christmas Day")
+//	due, err := calendar.DueDate(ticket.CreatedAt, 8*time.Hour) // *LocalizedDateTime
+package internationalization
+
+import (
+	"encoding/json"
+	"time"
+
+	"golang-arch/internal/shared/domain/domainerror"
+	"golang-arch/internal/shared/domain/validation"
+)
+
+// holidayDateLayout is the layout of holiday keys
+const holidayDateLayout = "2006-01-02"
+
+// maxCalendarDays bounds calendar walks so a calendar with every day off
+// fails instead of looping
+const maxCalendarDays = 3 * 366
+
+// BusinessCalendar describes when a team works: daily working hours on the
+// business days of Week, in Timezone, except on Holidays.
+//
+// Features:
+//   - Holidays keyed by local date ("2006-01-02") with a display name
+//   - Immutable: WithHoliday returns a new calendar
+//
+// Example:
+//
+//	calendar, _ := NewBusinessCalendar(*berlin, DefaultWeek, "09:00", "17:00")
+//	due, _ := calendar.DueDate(fridayAt16, 2*time.Hour) // Monday 10:00 Berlin time
+type BusinessCalendar struct {
+	Timezone Timezone          // Zone working hours are defined in
+	Week     WeekDefinition    // Weekend days
+	Start    int               // Minutes after local midnight work starts
+	End      int               // Minutes after local midnight work ends
+	Holidays map[string]string // Local date ("2006-01-02") -> holiday name
+}
+
+// NewBusinessCalendar creates a BusinessCalendar with "HH:MM" working hours.
+// Returns an error if the hours are invalid or end before they start.
+func NewBusinessCalendar(timezone Timezone, week WeekDefinition, start, end string) (*BusinessCalendar, error) {
+	startMinutes, err := parseClock(start)
+	if err != nil {
+		return nil, err
+	}
+	endMinutes, err := parseClock(end)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &BusinessCalendar{
+		Timezone: timezone,
+		Week:     week,
+		Start:    startMinutes,
+		End:      endMinutes,
+		Holidays: map[string]string{},
+	}
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// WithHoliday returns a copy of the calendar with date ("2006-01-02") as a
+// holiday.
+func (c BusinessCalendar) WithHoliday(date, name string) (*BusinessCalendar, error) {
+	if _, err := time.Parse(holidayDateLayout, date); err != nil {
+		return nil, domainerror.Invalidf("invalid holiday date %q (expected YYYY-MM-DD)", date)
+	}
+
+	holidays := make(map[string]string, len(c.Holidays)+1)
+	for day, holiday := range c.Holidays {
+		holidays[day] = holiday
+	}
+	holidays[date] = name
+
+	next := c
+	next.Holidays = holidays
+	return &next, nil
+}
+
+// Validate ensures the calendar has a valid timezone, week, working hours
+// and holiday dates.
+func (c BusinessCalendar) Validate() error {
+	var errs validation.ValidationErrors
+	errs.Merge("timezone", "invalid timezone in business calendar", c.Timezone.Validate())
+	errs.Merge("week", "", c.Week.Validate())
+	if c.Start < 0 || c.End >= minutesPerDay || c.Start >= c.End {
+		errs.Add("hours", validation.CodeOutOfRange, "working hours must start before they end, between 00:00 and 23:59", nil)
+	}
+	for _, date := range sortedKeys(c.Holidays) {
+		if _, err := time.Parse(holidayDateLayout, date); err != nil {
+			errs.Add("holidays."+date, validation.CodeInvalidFormat, "holiday date must be YYYY-MM-DD", nil)
+		}
+	}
+	return errs.Err()
+}
+
+// Holiday returns the name of the holiday on t's date in the calendar's
+// timezone.
+func (c BusinessCalendar) Holiday(t time.Time) (string, bool, error) {
+	loc, err := c.Timezone.GetLocation()
+	if err != nil {
+		return "", false, err
+	}
+	name, ok := c.Holidays[t.In(loc).Format(holidayDateLayout)]
+	return name, ok, nil
+}
+
+// IsWorkingTime reports whether t falls inside working hours on a business
+// day.
+func (c BusinessCalendar) IsWorkingTime(t time.Time) (bool, error) {
+	loc, err := c.Timezone.GetLocation()
+	if err != nil {
+		return false, err
+	}
+	local := t.In(loc)
+	if !c.isWorkingDay(local) {
+		return false, nil
+	}
+	open, closing := c.hours(local, loc)
+	return !local.Before(open) && local.Before(closing), nil
+}
+
+// AddWorkingTime returns the instant d of working time after t. Time
+// outside working hours does not count, so a start on Saturday counts from
+// the next opening. When d ends exactly at closing time, the result is that
+// closing time.
+func (c BusinessCalendar) AddWorkingTime(t time.Time, d time.Duration) (time.Time, error) {
+	if d < 0 {
+		return time.Time{}, domainerror.Invalidf("working time must not be negative")
+	}
+	if err := c.Validate(); err != nil {
+		return time.Time{}, err
+	}
+	loc, err := c.Timezone.GetLocation()
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	current := t.In(loc)
+	remaining := d
+	for i := 0; i < maxCalendarDays; i++ {
+		open, closing := c.hours(current, loc)
+		if c.isWorkingDay(current) && current.Before(closing) {
+			if current.Before(open) {
+				current = open
+			}
+			available := closing.Sub(current)
+			if remaining <= available {
+				return current.Add(remaining).In(t.Location()), nil
+			}
+			remaining -= available
+		}
+		year, month, day := current.Date()
+		current = time.Date(year, month, day+1, 0, 0, 0, 0, loc)
+	}
+	return time.Time{}, domainerror.Invalidf("business calendar has no working time within %d days", maxCalendarDays)
+}
+
+// WorkingTimeBetween returns the working time elapsed from from to to, or
+// zero when to is not after from.
+func (c BusinessCalendar) WorkingTimeBetween(from, to time.Time) (time.Duration, error) {
+	if err := c.Validate(); err != nil {
+		return 0, err
+	}
+	loc, err := c.Timezone.GetLocation()
+	if err != nil {
+		return 0, err
+	}
+
+	var total time.Duration
+	current := from.In(loc)
+	for current.Before(to) {
+		if c.isWorkingDay(current) {
+			open, closing := c.hours(current, loc)
+			start, end := laterOf(open, current), earlierOf(closing, to)
+			if end.After(start) {
+				total += end.Sub(start)
+			}
+		}
+		year, month, day := current.Date()
+		current = time.Date(year, month, day+1, 0, 0, 0, 0, loc)
+	}
+	return total, nil
+}
+
+// DueDate returns the deadline for an SLA of within working time starting
+// at start, in the calendar's timezone.
+func (c BusinessCalendar) DueDate(start time.Time, within time.Duration) (*LocalizedDateTime, error) {
+	due, err := c.AddWorkingTime(start, within)
+	if err != nil {
+		return nil, err
+	}
+	return NewLocalizedDateTime(*NewTimeFromTime(due), c.Timezone)
+}
+
+// MarshalJSON implements json.Marshaler with a timezone ID and "HH:MM" hours.
+func (c BusinessCalendar) MarshalJSON() ([]byte, error) {
+	return json.Marshal(businessCalendarJSON{
+		Timezone: c.Timezone.ID,
+		Week:     c.Week,
+		Start:    formatClock(c.Start),
+		End:      formatClock(c.End),
+		Holidays: c.Holidays,
+	})
+}
+
+// UnmarshalJSON implements json.Unmarshaler interface.
+func (c *BusinessCalendar) UnmarshalJSON(data []byte) error {
+	raw := businessCalendarJSON{Week: DefaultWeek}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	timezone, err := NewTimezoneFromID(raw.Timezone)
+	if err != nil {
+		return err
+	}
+	calendar, err := NewBusinessCalendar(*timezone, raw.Week, raw.Start, raw.End)
+	if err != nil {
+		return err
+	}
+	for _, date := range sortedKeys(raw.Holidays) {
+		if calendar, err = calendar.WithHoliday(date, raw.Holidays[date]); err != nil {
+			return err
+		}
+	}
+	*c = *calendar
+	return nil
+}
+
+// businessCalendarJSON is the wire format of BusinessCalendar.
+type businessCalendarJSON struct {
+	Timezone string            `json:"timezone"`
+	Week     WeekDefinition    `json:"week"`
+	Start    string            `json:"start"`
+	End      string            `json:"end"`
+	Holidays map[string]string `json:"holidays,omitempty"`
+}
+
+// isWorkingDay reports whether local's date is neither a weekend day nor a holiday.
+func (c BusinessCalendar) isWorkingDay(local time.Time) bool {
+	if c.Week.IsWeekend(local.Weekday()) {
+		return false
+	}
+	_, holiday := c.Holidays[local.Format(holidayDateLayout)]
+	return !holiday
+}
+
+// hours returns the opening and closing instants on local's date.
+func (c BusinessCalendar) hours(local time.Time, loc *time.Location) (time.Time, time.Time) {
+	year, month, day := local.Date()
+	return time.Date(year, month, day, c.Start/60, c.Start%60, 0, 0, loc),
+		time.Date(year, month, day, c.End/60, c.End%60, 0, 0, loc)
+}
+
+// laterOf returns the later of a and b.
+func laterOf(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+	return b
+}
+
+// earlierOf returns the earlier of a and b.
+func earlierOf(a, b time.Time) time.Time {
+	if a.Before(b) {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,40 @@
+// Package internationalization provides domain types for handling internationalization
+// concerns such as time, currency, timezone, and phone number formatting.
+//
+// This file holds the package clock used wherever a domain type needs "now",
+// such as resolving the current UTC offset of a timezone. Tests can replace it
+// with a fake clock to make DST-sensitive behavior deterministic.
+package internationalization
+
+import (
+	"sync"
+
+	"golang-arch/pkg/clock"
+)
+
+var (
+	clockMu      sync.RWMutex
+	packageClock clock.Clock = clock.New()
+)
+
+// SetClock replaces the clock used by the package and returns a function that
+// restores the previous one.
+func SetClock(c clock.Clock) (restore func()) {
+	clockMu.Lock()
+	previous := packageClock
+	packageClock = c
+	clockMu.Unlock()
+
+	return func() {
+		clockMu.Lock()
+		packageClock = previous
+		clockMu.Unlock()
+	}
+}
+
+// currentClock returns the clock currently used by the package
+func currentClock() clock.Clock {
+	clockMu.RLock()
+	defer clockMu.RUnlock()
+	return packageClock
+}
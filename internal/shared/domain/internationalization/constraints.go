@@ -0,0 +1,138 @@
+// Package internationalization provides domain types for handling internationalization
+// concerns such as time, currency, timezone, and phone number formatting.
+//
+// This file holds the constraints registry. Applications narrow what the
+// value objects accept, e.g. "amounts are never negative in this service"
+// or "only these five currencies", by registering constraints once at
+// startup instead of checking in every handler:
+//
+//	allowed, err := AllowedCurrencies("USD", "EUR", "GBP")
+//	...
+//	SetConstraints(Constraints{Money: []Constraint[Money]{NonNegativeAmount(), allowed}})
+//
+// The constructors enforce them: NewMoneyFromInteger, NewMoneyFromDecimal,
+// NewMoneyFromPrimitive, ParseMoney and MakeMoney for Money, NewPhone for
+// Phone and NewTimezoneFromID for Timezone. JSON and database decoding go
+// through those constructors, and so does arithmetic, so a Subtract that
+// would go negative fails under NonNegativeAmount. Currency lookups are not
+// constrained, as rate tables and catalogs list every currency.
+package internationalization
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"sync/atomic"
+
+	"golang-arch/internal/shared/domain/domainerror"
+)
+
+// Constraint is an application invariant on values of type T. Check
+// returns an error for values the application does not accept.
+type Constraint[T any] struct {
+	// Name identifies the constraint in errors, e.g. "non_negative_amount"
+	Name  string
+	Check func(T) error
+}
+
+// Constraints are the invariants the constructors enforce, by type
+type Constraints struct {
+	Money    []Constraint[Money]
+	Phone    []Constraint[Phone]
+	Timezone []Constraint[Timezone]
+}
+
+// packageConstraints is read by every constructor, so it is swapped
+// atomically rather than locked
+var packageConstraints atomic.Pointer[Constraints]
+
+// SetConstraints replaces the constraints enforced by the constructors and
+// returns a function that restores the previous ones.
+func SetConstraints(c Constraints) (restore func()) {
+	previous := packageConstraints.Swap(&c)
+	return func() {
+		packageConstraints.Store(previous)
+	}
+}
+
+// currentConstraints returns the constraints currently enforced
+func currentConstraints() Constraints {
+	if c := packageConstraints.Load(); c != nil {
+		return *c
+	}
+	return Constraints{}
+}
+
+// checkConstraints runs every constraint on value. A failure is an Invalid
+// error naming the constraint.
+func checkConstraints[T any](constraints []Constraint[T], value T) error {
+	for _, constraint := range constraints {
+		if err := constraint.Check(value); err != nil {
+			return domainerror.Wrap(domainerror.Invalid, err, "violates "+constraint.Name)
+		}
+	}
+	return nil
+}
+
+// constrainMoney checks m against the registered Money constraints
+func constrainMoney(m *Money) (*Money, error) {
+	if err := checkConstraints(currentConstraints().Money, *m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// NonNegativeAmount rejects negative amounts
+func NonNegativeAmount() Constraint[Money] {
+	return Constraint[Money]{Name: "non_negative_amount", Check: func(m Money) error {
+		if m.Amount < 0 {
+			return fmt.Errorf("amount %s is negative", m.Format())
+		}
+		return nil
+	}}
+}
+
+// AmountRange accepts amounts from min to max minor units, inclusive, in
+// any currency
+func AmountRange(min, max int64) Constraint[Money] {
+	return Constraint[Money]{Name: "amount_range", Check: func(m Money) error {
+		if m.Amount < min || m.Amount > max {
+			return fmt.Errorf("amount %d is outside %d to %d minor units", m.Amount, min, max)
+		}
+		return nil
+	}}
+}
+
+// AllowedCurrencies accepts Money in the listed currencies only. It fails
+// on codes that are not supported currencies.
+func AllowedCurrencies(codes ...string) (Constraint[Money], error) {
+	allowed := make([]string, 0, len(codes))
+	for _, code := range codes {
+		currency, err := NewCurrencyFromCode(strings.ToUpper(strings.TrimSpace(code)))
+		if err != nil {
+			return Constraint[Money]{}, err
+		}
+		allowed = append(allowed, currency.Code)
+	}
+	return Constraint[Money]{Name: "allowed_currencies", Check: func(m Money) error {
+		if !slices.Contains(allowed, m.Currency.Code) {
+			return fmt.Errorf("currency %s is not one of %s", m.Currency.Code, strings.Join(allowed, ", "))
+		}
+		return nil
+	}}, nil
+}
+
+// AllowedCallingCodes accepts phone numbers with the listed country calling
+// codes only, e.g. "1" and "44"
+func AllowedCallingCodes(codes ...string) Constraint[Phone] {
+	allowed := make([]string, len(codes))
+	for i, code := range codes {
+		allowed[i] = strings.TrimPrefix(strings.TrimSpace(code), "+")
+	}
+	return Constraint[Phone]{Name: "allowed_calling_codes", Check: func(p Phone) error {
+		if !slices.Contains(allowed, p.CountryCode) {
+			return fmt.Errorf("calling code +%s is not one of +%s", p.CountryCode, strings.Join(allowed, ", +"))
+		}
+		return nil
+	}}
+}
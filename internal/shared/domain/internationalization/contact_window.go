@@ -0,0 +1,214 @@
+// Package internationalization provides domain types and composite types for handling
+// internationalized data such as time, currency, timezone, and phone numbers.
+// This file contains the ContactWindow type for recipient-local quiet hours.
+//
+// ContactWindow Type:
+//   - Daily window of recipient-local wall-clock time, e.g. 09:00–21:00
+//   - Overnight windows such as 22:00–06:00
+//   - Optional weekday restriction, evaluated in the recipient's timezone
+//   - Next opening time for deferring SMS and calls to business hours
+//   - DST-aware: windows follow the local clock, not a fixed UTC offset
+//
+// JSON Format: {"start": "09:00", "end": "21:00", "weekdays": [1, 2, 3, 4, 5]}
+//
+// Usage Examples:
+//
+//	ok, err := phone.IsWithinCallingHours(time.Now(), DefaultCallingHours)
+//	window, _ := NewContactWindow("08:00", "18:00", time.Monday, time.Friday)
+//	at, err := phone.NextContactTime(time.Now(), *window) // defer delivery until at
+package internationalization
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"time"
+
+	"golang-arch/internal/shared/domain/domainerror"
+)
+
+const minutesPerDay = 24 * 60
+
+// DefaultCallingHours is the window of 09:00–21:00 local time on every day.
+var DefaultCallingHours = ContactWindow{Start: 9 * 60, End: 21 * 60}
+
+// ContactWindow is a daily window of local wall-clock time during which a
+// recipient may be contacted. Start and End are minutes after local midnight;
+// the window includes Start and excludes End. When End is before Start the
+// window runs overnight into the next day.
+//
+// Features:
+//   - Evaluated in the recipient's timezone, following DST changes
+//   - Weekdays restricts the days a window opens on (empty means every day);
+//     an overnight window belongs to the day it opens
+//
+// Example:
+//
+//	window, _ := NewContactWindow("09:00", "21:00")
+//	window.Contains(now, *newYork) // true between 09:00 and 21:00 in New York
+type ContactWindow struct {
+	Start    int            // Minutes after local midnight the window opens
+	End      int            // Minutes after local midnight the window closes
+	Weekdays []time.Weekday // Days the window opens on; empty means every day
+}
+
+// NewContactWindow creates a ContactWindow from "HH:MM" times, optionally
+// restricted to the given weekdays.
+func NewContactWindow(start, end string, weekdays ...time.Weekday) (*ContactWindow, error) {
+	startMinutes, err := parseClock(start)
+	if err != nil {
+		return nil, err
+	}
+	endMinutes, err := parseClock(end)
+	if err != nil {
+		return nil, err
+	}
+
+	w := &ContactWindow{Start: startMinutes, End: endMinutes, Weekdays: weekdays}
+	if err := w.Validate(); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// Validate ensures the window has valid, distinct bounds and weekdays.
+func (w ContactWindow) Validate() error {
+	if w.Start < 0 || w.Start >= minutesPerDay || w.End < 0 || w.End >= minutesPerDay {
+		return domainerror.Invalidf("contact window bounds must be between 00:00 and 23:59")
+	}
+	if w.Start == w.End {
+		return domainerror.Invalidf("contact window %s is empty", w)
+	}
+	for _, day := range w.Weekdays {
+		if day < time.Sunday || day > time.Saturday {
+			return domainerror.Invalidf("invalid contact window weekday: %d", int(day))
+		}
+	}
+	return nil
+}
+
+// Contains reports whether t falls inside the window in timezone tz.
+func (w ContactWindow) Contains(t time.Time, tz Timezone) (bool, error) {
+	loc, err := tz.GetLocation()
+	if err != nil {
+		return false, err
+	}
+	return w.containsLocal(t.In(loc)), nil
+}
+
+// NextOpen returns t if it falls inside the window, otherwise the next time
+// the window opens in timezone tz.
+func (w ContactWindow) NextOpen(t time.Time, tz Timezone) (time.Time, error) {
+	if err := w.Validate(); err != nil {
+		return time.Time{}, err
+	}
+	loc, err := tz.GetLocation()
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	local := t.In(loc)
+	if w.containsLocal(local) {
+		return t, nil
+	}
+
+	// The window opens at most a week away
+	year, month, day := local.Date()
+	for i := 0; i <= 7; i++ {
+		opening := time.Date(year, month, day+i, w.Start/60, w.Start%60, 0, 0, loc)
+		if opening.After(local) && w.opensOn(opening.Weekday()) {
+			return opening.In(t.Location()), nil
+		}
+	}
+	return time.Time{}, domainerror.Invalidf("contact window %s never opens", w)
+}
+
+// String returns the window as "HH:MM–HH:MM".
+func (w ContactWindow) String() string {
+	return formatClock(w.Start) + "–" + formatClock(w.End)
+}
+
+// MarshalJSON implements json.Marshaler with "HH:MM" bounds.
+func (w ContactWindow) MarshalJSON() ([]byte, error) {
+	return json.Marshal(contactWindowJSON{
+		Start:    formatClock(w.Start),
+		End:      formatClock(w.End),
+		Weekdays: w.Weekdays,
+	})
+}
+
+// UnmarshalJSON implements json.Unmarshaler interface.
+func (w *ContactWindow) UnmarshalJSON(data []byte) error {
+	var raw contactWindowJSON
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	window, err := NewContactWindow(raw.Start, raw.End, raw.Weekdays...)
+	if err != nil {
+		return err
+	}
+	*w = *window
+	return nil
+}
+
+// contactWindowJSON is the wire format of ContactWindow.
+type contactWindowJSON struct {
+	Start    string         `json:"start"`
+	End      string         `json:"end"`
+	Weekdays []time.Weekday `json:"weekdays,omitempty"`
+}
+
+// containsLocal checks a time already converted to the recipient's timezone.
+func (w ContactWindow) containsLocal(local time.Time) bool {
+	minute := local.Hour()*60 + local.Minute()
+	if w.Start < w.End {
+		return minute >= w.Start && minute < w.End && w.opensOn(local.Weekday())
+	}
+
+	// Overnight: the evening part opens today, the morning part opened yesterday
+	if minute >= w.Start {
+		return w.opensOn(local.Weekday())
+	}
+	return minute < w.End && w.opensOn((local.Weekday()+6)%7)
+}
+
+// opensOn reports whether the window opens on day.
+func (w ContactWindow) opensOn(day time.Weekday) bool {
+	return len(w.Weekdays) == 0 || slices.Contains(w.Weekdays, day)
+}
+
+// parseClock parses "HH:MM" into minutes after midnight.
+func parseClock(value string) (int, error) {
+	parsed, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, domainerror.Invalidf("invalid time of day %q (expected HH:MM)", value)
+	}
+	return parsed.Hour()*60 + parsed.Minute(), nil
+}
+
+// formatClock formats minutes after midnight as "HH:MM".
+func formatClock(minutes int) string {
+	return fmt.Sprintf("%02d:%02d", minutes/60, minutes%60)
+}
+
+// IsWithinCallingHours reports whether now falls inside window in the timezone.
+func (tz Timezone) IsWithinCallingHours(now time.Time, window ContactWindow) (bool, error) {
+	return window.Contains(now, tz)
+}
+
+// NextContactTime returns now if it falls inside window in the timezone,
+// otherwise the next time the window opens.
+func (tz Timezone) NextContactTime(now time.Time, window ContactWindow) (time.Time, error) {
+	return window.NextOpen(now, tz)
+}
+
+// IsWithinCallingHours reports whether now falls inside window in the phone's timezone.
+func (lp LocalizedPhone) IsWithinCallingHours(now time.Time, window ContactWindow) (bool, error) {
+	return window.Contains(now, lp.Timezone)
+}
+
+// NextContactTime returns now if it falls inside window in the phone's
+// timezone, otherwise the next time the window opens.
+func (lp LocalizedPhone) NextContactTime(now time.Time, window ContactWindow) (time.Time, error) {
+	return window.NextOpen(now, lp.Timezone)
+}
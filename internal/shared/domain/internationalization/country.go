@@ -0,0 +1,56 @@
+// Package internationalization provides domain types and composite types for handling
+// internationalized data such as time, currency, timezone, and phone numbers.
+// This file contains the Country type for ISO 3166-1 alpha-2 codes.
+//
+// Country Type:
+//   - Two-letter ISO 3166-1 code, upper case ("id" parses as "ID")
+//   - Country-driven conventions such as the week definition
+//
+// Database Storage: Stored as string (CHAR(2))
+//
+// Usage Examples:
+//
+//	country, err := ParseCountry("ae")
+//	country.Week().Weekend() // [Friday Saturday]
+package internationalization
+
+import (
+	"strings"
+
+	"golang-arch/internal/shared/domain/domainerror"
+)
+
+// Country is an ISO 3166-1 alpha-2 country code.
+type Country string
+
+// ParseCountry parses a two-letter country code in any casing.
+func ParseCountry(code string) (Country, error) {
+	country := Country(strings.ToUpper(strings.TrimSpace(code)))
+	if err := country.Validate(); err != nil {
+		return "", err
+	}
+	return country, nil
+}
+
+// Validate ensures the country is a two-letter upper-case code.
+func (c Country) Validate() error {
+	if len(c) != 2 || !isAlpha(string(c)) || strings.ToUpper(string(c)) != string(c) {
+		return domainerror.Invalidf("invalid country code: %q (expected ISO 3166-1 alpha-2)", string(c))
+	}
+	return nil
+}
+
+// String returns the country code.
+func (c Country) String() string {
+	return string(c)
+}
+
+// Country returns the locale's region as a Country, or "" when the locale has
+// no region or a numeric (UN M.49) one.
+func (l Locale) Country() Country {
+	region := l.Region()
+	if len(region) != 2 {
+		return ""
+	}
+	return Country(region)
+}
@@ -12,7 +12,11 @@ package internationalization
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
+
+	"golang-arch/internal/shared/domain/domainerror"
+	"golang-arch/internal/shared/domain/validation"
 )
 
 // Currency represents a currency using ISO 4217 code with decimal precision.
@@ -81,67 +85,57 @@ func NewCurrency(code, symbol, name string, decimalPlaces int) (*Currency, error
 	return currency, nil
 }
 
-// NewCurrencyFromCode creates a Currency instance from ISO 4217 code.
+// NewCurrencyFromCode returns a copy of the registered Currency of a code;
+// see RegisterCurrency for adding currencies. Lookups read a table built
+// once, and changing the result does not affect the registry.
 func NewCurrencyFromCode(code string) (*Currency, error) {
-	code = strings.ToUpper(code)
-
-	// Common currencies with their symbols, names, and decimal places
-	currencies := map[string]struct {
-		symbol        string
-		name          string
-		decimalPlaces int
-	}{
-		"USD": {"$", "US Dollar", 2},
-		"EUR": {"€", "Euro", 2},
-		"GBP": {"£", "British Pound", 2},
-		"JPY": {"¥", "Japanese Yen", 0},
-		"CAD": {"C$", "Canadian Dollar", 2},
-		"AUD": {"A$", "Australian Dollar", 2},
-		"CHF": {"CHF", "Swiss Franc", 2},
-		"CNY": {"¥", "Chinese Yuan", 2},
-		"INR": {"₹", "Indian Rupee", 2},
-		"BRL": {"R$", "Brazilian Real", 2},
-		"KRW": {"₩", "South Korean Won", 0},
-		"MXN": {"$", "Mexican Peso", 2},
-		"SGD": {"S$", "Singapore Dollar", 2},
-		"HKD": {"HK$", "Hong Kong Dollar", 2},
-		"NZD": {"NZ$", "New Zealand Dollar", 2},
-		"SEK": {"kr", "Swedish Krona", 2},
-		"NOK": {"kr", "Norwegian Krone", 2},
-		"DKK": {"kr", "Danish Krone", 2},
-		"PLN": {"zł", "Polish Złoty", 2},
-		"CZK": {"Kč", "Czech Koruna", 2},
-		"HUF": {"Ft", "Hungarian Forint", 0},
-		"RUB": {"₽", "Russian Ruble", 2},
-		"TRY": {"₺", "Turkish Lira", 2},
-		"ZAR": {"R", "South African Rand", 2},
-		"ILS": {"₪", "Israeli Shekel", 2},
-		"SAR": {"", "Saudi Riyal", 2},
-		"AED": {"د.إ", "UAE Dirham", 2},
-		"THB": {"฿", "Thai Baht", 2},
-		"MYR": {"RM", "Malaysian Ringgit", 2},
-		"IDR": {"Rp", "Indonesian Rupiah", 0},
-		"PHP": {"₱", "Philippine Peso", 2},
-		"VND": {"₫", "Vietnamese Dong", 0},
-		"BTC": {"₿", "Bitcoin", 8},
-		"ETH": {"Ξ", "Ethereum", 18},
-	}
-
-	currencyInfo, exists := currencies[code]
-	if !exists {
-		return nil, fmt.Errorf("unsupported currency code: %s", code)
+	if currency, exists := packageCurrencies.Lookup(code); exists {
+		return currency, nil
 	}
+	return nil, domainerror.Invalidf("unsupported currency code: %s", strings.ToUpper(code))
+}
 
-	return &Currency{
-		Code:          code,
-		Symbol:        currencyInfo.symbol,
-		Name:          currencyInfo.name,
-		DecimalPlaces: currencyInfo.decimalPlaces,
-	}, nil
+// supportedCurrencies lists the built-in currencies of the registry with
+// their symbols, names, and decimal places
+var supportedCurrencies = []Currency{
+	{Code: "USD", Symbol: "$", Name: "US Dollar", DecimalPlaces: 2},
+	{Code: "EUR", Symbol: "€", Name: "Euro", DecimalPlaces: 2},
+	{Code: "GBP", Symbol: "£", Name: "British Pound", DecimalPlaces: 2},
+	{Code: "JPY", Symbol: "¥", Name: "Japanese Yen", DecimalPlaces: 0},
+	{Code: "CAD", Symbol: "C$", Name: "Canadian Dollar", DecimalPlaces: 2},
+	{Code: "AUD", Symbol: "A$", Name: "Australian Dollar", DecimalPlaces: 2},
+	{Code: "CHF", Symbol: "CHF", Name: "Swiss Franc", DecimalPlaces: 2},
+	{Code: "CNY", Symbol: "¥", Name: "Chinese Yuan", DecimalPlaces: 2},
+	{Code: "INR", Symbol: "₹", Name: "Indian Rupee", DecimalPlaces: 2},
+	{Code: "BRL", Symbol: "R$", Name: "Brazilian Real", DecimalPlaces: 2},
+	{Code: "KRW", Symbol: "₩", Name: "South Korean Won", DecimalPlaces: 0},
+	{Code: "MXN", Symbol: "$", Name: "Mexican Peso", DecimalPlaces: 2},
+	{Code: "SGD", Symbol: "S$", Name: "Singapore Dollar", DecimalPlaces: 2},
+	{Code: "HKD", Symbol: "HK$", Name: "Hong Kong Dollar", DecimalPlaces: 2},
+	{Code: "NZD", Symbol: "NZ$", Name: "New Zealand Dollar", DecimalPlaces: 2},
+	{Code: "SEK", Symbol: "kr", Name: "Swedish Krona", DecimalPlaces: 2},
+	{Code: "NOK", Symbol: "kr", Name: "Norwegian Krone", DecimalPlaces: 2},
+	{Code: "DKK", Symbol: "kr", Name: "Danish Krone", DecimalPlaces: 2},
+	{Code: "PLN", Symbol: "zł", Name: "Polish Złoty", DecimalPlaces: 2},
+	{Code: "CZK", Symbol: "Kč", Name: "Czech Koruna", DecimalPlaces: 2},
+	{Code: "HUF", Symbol: "Ft", Name: "Hungarian Forint", DecimalPlaces: 0},
+	{Code: "RUB", Symbol: "₽", Name: "Russian Ruble", DecimalPlaces: 2},
+	{Code: "TRY", Symbol: "₺", Name: "Turkish Lira", DecimalPlaces: 2},
+	{Code: "ZAR", Symbol: "R", Name: "South African Rand", DecimalPlaces: 2},
+	{Code: "ILS", Symbol: "₪", Name: "Israeli Shekel", DecimalPlaces: 2},
+	{Code: "SAR", Symbol: "", Name: "Saudi Riyal", DecimalPlaces: 2},
+	{Code: "AED", Symbol: "د.إ", Name: "UAE Dirham", DecimalPlaces: 2},
+	{Code: "THB", Symbol: "฿", Name: "Thai Baht", DecimalPlaces: 2},
+	{Code: "MYR", Symbol: "RM", Name: "Malaysian Ringgit", DecimalPlaces: 2},
+	{Code: "IDR", Symbol: "Rp", Name: "Indonesian Rupiah", DecimalPlaces: 0},
+	{Code: "PHP", Symbol: "₱", Name: "Philippine Peso", DecimalPlaces: 2},
+	{Code: "VND", Symbol: "₫", Name: "Vietnamese Dong", DecimalPlaces: 0},
+	{Code: "BTC", Symbol: "₿", Name: "Bitcoin", DecimalPlaces: 8},
+	{Code: "ETH", Symbol: "Ξ", Name: "Ethereum", DecimalPlaces: 18},
 }
 
 // ToPrimitive returns the primitive value for database storage.
-func (c *Currency) ToPrimitive() string {
+func (c Currency) ToPrimitive() string {
 	return c.Code
 }
 
@@ -151,78 +145,126 @@ func FromPrimitiveCurrency(code string) (*Currency, error) {
 }
 
 // Validate ensures the currency code is valid and follows ISO 4217 standard.
-func (c *Currency) Validate() error {
-	if c.Code == "" {
-		return fmt.Errorf("currency code cannot be empty")
-	}
-
-	if len(c.Code) != 3 {
-		return fmt.Errorf("currency code must be exactly 3 characters, got %d", len(c.Code))
-	}
-
-	// Check if code contains only letters
-	for _, char := range c.Code {
-		if char < 'A' || char > 'Z' {
-			return fmt.Errorf("currency code must contain only uppercase letters, got %c", char)
+func (c Currency) Validate() error {
+	var errs validation.ValidationErrors
+
+	switch {
+	case c.Code == "":
+		errs.Add("code", validation.CodeRequired, "currency code cannot be empty", nil)
+	case len(c.Code) != 3:
+		errs.Add("code", validation.CodeInvalidFormat,
+			fmt.Sprintf("currency code must be exactly 3 characters, got %d", len(c.Code)),
+			map[string]any{"length": 3, "actual": len(c.Code)})
+	default:
+		// Check if code contains only letters
+		for _, char := range c.Code {
+			if char < 'A' || char > 'Z' {
+				errs.Add("code", validation.CodeInvalidFormat,
+					fmt.Sprintf("currency code must contain only uppercase letters, got %c", char),
+					map[string]any{"value": c.Code})
+				break
+			}
 		}
 	}
 
 	if c.Symbol == "" {
-		return fmt.Errorf("currency symbol cannot be empty")
+		errs.Add("symbol", validation.CodeRequired, "currency symbol cannot be empty", nil)
 	}
 
 	if c.Name == "" {
-		return fmt.Errorf("currency name cannot be empty")
+		errs.Add("name", validation.CodeRequired, "currency name cannot be empty", nil)
 	}
 
 	// Validate decimal places
 	if c.DecimalPlaces < 0 || c.DecimalPlaces > 18 {
-		return fmt.Errorf("decimal places must be between 0 and 18, got %d", c.DecimalPlaces)
+		errs.Add("decimal_places", validation.CodeOutOfRange,
+			fmt.Sprintf("decimal places must be between 0 and 18, got %d", c.DecimalPlaces),
+			map[string]any{"min": 0, "max": 18, "actual": c.DecimalPlaces})
 	}
 
-	return nil
+	return errs.Err()
 }
 
 // GetDecimalPlaces returns the number of decimal places for this currency.
-func (c *Currency) GetDecimalPlaces() int {
+func (c Currency) GetDecimalPlaces() int {
 	return c.DecimalPlaces
 }
 
 // Format formats an amount with the currency symbol using integer-based storage.
 // The amount parameter should be the integer value in the smallest currency unit.
-func (c *Currency) Format(amount int64) string {
-	if c.DecimalPlaces == 0 {
-		return fmt.Sprintf("%s%d", c.Symbol, amount)
-	}
-
-	// Convert integer to decimal representation
-	decimal := float64(amount) / float64(pow10(c.DecimalPlaces))
-	return fmt.Sprintf("%s%.*f", c.Symbol, c.DecimalPlaces, decimal)
+// Negative amounts put the sign before the symbol (e.g. "-$100.50").
+func (c Currency) Format(amount int64) string {
+	var buf [formatBufferSize]byte
+	return string(c.AppendFormat(buf[:0], amount))
 }
 
 // FormatWithCode formats an amount with the currency code using integer-based storage.
-func (c *Currency) FormatWithCode(amount int64) string {
-	if c.DecimalPlaces == 0 {
-		return fmt.Sprintf("%d %s", amount, c.Code)
+func (c Currency) FormatWithCode(amount int64) string {
+	return c.formatWithSuffix(amount, c.Code)
+}
+
+// FormatWithName formats an amount with the currency name using integer-based storage.
+func (c Currency) FormatWithName(amount int64) string {
+	return c.formatWithSuffix(amount, c.Name)
+}
+
+// formatBufferSize fits a sign, 19 digits, a decimal point, up to 18 leading
+// zeros and a typical symbol, code or name without growing onto the heap
+const formatBufferSize = 64
+
+// formatWithSuffix renders "<amount> <suffix>", e.g. "-100.50 USD"
+func (c Currency) formatWithSuffix(amount int64, suffix string) string {
+	var buf [formatBufferSize]byte
+	b := buf[:0]
+	if amount < 0 {
+		b = append(b, '-')
 	}
+	b = appendMinorUnits(b, amount, c.DecimalPlaces)
+	b = append(b, ' ')
+	b = append(b, suffix...)
+	return string(b)
+}
 
-	decimal := float64(amount) / float64(pow10(c.DecimalPlaces))
-	return fmt.Sprintf("%.*f %s", c.DecimalPlaces, decimal, c.Code)
+// appendMinorUnits appends the unsigned decimal representation of an integer
+// amount of minor units, e.g. 12345 with 2 decimal places becomes "123.45".
+// Only integer math is used, so 18-decimal amounts render exactly.
+func appendMinorUnits(dst []byte, amount int64, decimalPlaces int) []byte {
+	var digitsBuf [20]byte
+	return appendDigitsWithPoint(dst, strconv.AppendUint(digitsBuf[:0], absUint64(amount), 10), decimalPlaces)
 }
 
-// FormatWithName formats an amount with the currency name using integer-based storage.
-func (c *Currency) FormatWithName(amount int64) string {
-	if c.DecimalPlaces == 0 {
-		return fmt.Sprintf("%d %s", amount, c.Name)
+// appendDigitsWithPoint appends the decimal digits of an unsigned amount of
+// minor units with the decimal point inserted, padding with leading zeros
+func appendDigitsWithPoint(dst, digits []byte, decimalPlaces int) []byte {
+	if decimalPlaces <= 0 {
+		return append(dst, digits...)
+	}
+
+	if len(digits) <= decimalPlaces {
+		dst = append(dst, '0', '.')
+		for i := len(digits); i < decimalPlaces; i++ {
+			dst = append(dst, '0')
+		}
+		return append(dst, digits...)
 	}
 
-	decimal := float64(amount) / float64(pow10(c.DecimalPlaces))
-	return fmt.Sprintf("%.*f %s", c.DecimalPlaces, decimal, c.Name)
+	point := len(digits) - decimalPlaces
+	dst = append(dst, digits[:point]...)
+	dst = append(dst, '.')
+	return append(dst, digits[point:]...)
+}
+
+// absUint64 returns |n| without overflowing for math.MinInt64
+func absUint64(n int64) uint64 {
+	if n < 0 {
+		return uint64(-(n + 1)) + 1
+	}
+	return uint64(n)
 }
 
 // FormatDecimal formats a decimal amount with the currency symbol.
 // This is for backward compatibility and external API usage.
-func (c *Currency) FormatDecimal(amount float64) string {
+func (c Currency) FormatDecimal(amount float64) string {
 	return fmt.Sprintf("%s%.*f", c.Symbol, c.DecimalPlaces, amount)
 }
 
@@ -235,24 +277,20 @@ func (c *Currency) Equal(other *Currency) bool {
 }
 
 // String returns the currency code.
-func (c *Currency) String() string {
+func (c Currency) String() string {
 	return c.Code
 }
 
-// IsSupported returns true if the currency code is supported.
+// IsSupported returns true if the currency code is registered.
 func IsSupported(code string) bool {
-	_, err := NewCurrencyFromCode(code)
-	return err == nil
+	_, exists := packageCurrencies.Lookup(code)
+	return exists
 }
 
-// GetSupportedCurrencies returns a list of supported currency codes.
+// GetSupportedCurrencies returns the registered currency codes, the
+// built-in ones first.
 func GetSupportedCurrencies() []string {
-	return []string{
-		"USD", "EUR", "GBP", "JPY", "CAD", "AUD", "CHF", "CNY", "INR", "BRL",
-		"KRW", "MXN", "SGD", "HKD", "NZD", "SEK", "NOK", "DKK", "PLN", "CZK",
-		"HUF", "RUB", "TRY", "ZAR", "ILS", "SAR", "AED", "THB", "MYR", "IDR",
-		"PHP", "VND", "BTC", "ETH",
-	}
+	return packageCurrencies.Codes()
 }
 
 // pow10 returns 10 raised to the power of n.
@@ -0,0 +1,211 @@
+// Package internationalization provides domain types and composite types for handling
+// internationalized data such as time, currency, timezone, and phone numbers.
+// This file contains the default currency of each country and the
+// CurrencyRules type for per-tenant overrides.
+//
+// Currency Defaults:
+//   - Country.DefaultCurrency: the national currency, when it is supported
+//     by NewCurrencyFromCode
+//   - CurrencyForLocale: the currency of the locale's region
+//   - Currency.Countries: the countries a currency is the default of
+//   - CurrencyRules: overrides by country plus a fallback, stored with a
+//     tenant's settings so checkout can pre-select the right currency
+//
+// JSON Format: {"overrides": {"CH": "EUR"}, "fallback": "USD"}
+//
+// Usage Examples:
+//
+//	eur, ok := Country("DE").DefaultCurrency()             // EUR, true
+//	jpy, ok := CurrencyForLocale(MustParseLocale("ja-JP")) // JPY, true
+//	currency := tenantRules.ForLocale(prefs.Locale)        // override, default or fallback
+package internationalization
+
+import (
+	"encoding/json"
+	"sort"
+
+	"golang-arch/internal/shared/domain/validation"
+)
+
+// countryCurrencies maps countries to their ISO 4217 currency, limited to
+// the built-in currencies
+var countryCurrencies = map[Country]string{
+	// Euro area and countries using the euro
+	"AD": "EUR", "AT": "EUR", "BE": "EUR", "CY": "EUR", "DE": "EUR", "EE": "EUR",
+	"ES": "EUR", "FI": "EUR", "FR": "EUR", "GR": "EUR", "HR": "EUR", "IE": "EUR",
+	"IT": "EUR", "LT": "EUR", "LU": "EUR", "LV": "EUR", "MC": "EUR", "ME": "EUR",
+	"MT": "EUR", "NL": "EUR", "PT": "EUR", "SI": "EUR", "SK": "EUR", "SM": "EUR",
+	"VA": "EUR", "XK": "EUR",
+
+	// US dollar, including dollarized economies
+	"US": "USD", "AS": "USD", "EC": "USD", "FM": "USD", "GU": "USD", "MH": "USD",
+	"MP": "USD", "PR": "USD", "PW": "USD", "SV": "USD", "TL": "USD", "VG": "USD",
+	"VI": "USD",
+
+	"AE": "AED", "AU": "AUD", "BR": "BRL", "CA": "CAD", "CH": "CHF", "CN": "CNY",
+	"CZ": "CZK", "DK": "DKK", "GB": "GBP", "HK": "HKD", "HU": "HUF", "ID": "IDR",
+	"IL": "ILS", "IN": "INR", "JP": "JPY", "KR": "KRW", "LI": "CHF", "MX": "MXN",
+	"MY": "MYR", "NO": "NOK", "NZ": "NZD", "PH": "PHP", "PL": "PLN", "RU": "RUB",
+	"SA": "SAR", "SE": "SEK", "SG": "SGD", "TH": "THB", "TR": "TRY", "VN": "VND",
+	"ZA": "ZAR", "GL": "DKK", "FO": "DKK", "KI": "AUD", "NR": "AUD", "TV": "AUD",
+}
+
+// DefaultCurrency returns the country's currency. ok is false for countries
+// without data or whose currency is not supported.
+func (c Country) DefaultCurrency() (Currency, bool) {
+	code, exists := countryCurrencies[c]
+	if !exists {
+		return Currency{}, false
+	}
+	currency, err := NewCurrencyFromCode(code)
+	if err != nil {
+		return Currency{}, false
+	}
+	return *currency, true
+}
+
+// CurrencyForLocale returns the currency of the locale's country. ok is
+// false when the locale has no country or the country has no default.
+func CurrencyForLocale(locale Locale) (Currency, bool) {
+	country := locale.Country()
+	if country == "" {
+		return Currency{}, false
+	}
+	return country.DefaultCurrency()
+}
+
+// Countries returns the countries whose default currency is c, sorted by
+// code. It is empty for currencies no country uses, e.g. BTC.
+func (c Currency) Countries() []Country {
+	var countries []Country
+	for country, code := range countryCurrencies {
+		if code == c.Code {
+			countries = append(countries, country)
+		}
+	}
+	sort.Slice(countries, func(i, j int) bool { return countries[i] < countries[j] })
+	return countries
+}
+
+// CurrencyRules decides which currency to pre-select for a customer, e.g.
+// for one tenant. Overrides take precedence over country defaults, and
+// Fallback applies when neither matches.
+//
+// Example:
+//
+//	rules, _ := NewCurrencyRules(map[string]string{"CH": "EUR", "LI": "EUR"}, "EUR")
+//	rules.ForCountry("CH") // EUR (override)
+//	rules.ForCountry("GB") // GBP (country default)
+//	rules.ForCountry("AR") // EUR (fallback)
+type CurrencyRules struct {
+	Overrides map[Country]string `json:"overrides,omitempty"` // Country -> ISO 4217 code
+	Fallback  string             `json:"fallback"`            // ISO 4217 code
+}
+
+// NewCurrencyRules creates CurrencyRules from overrides keyed by country
+// code, normalizing codes to upper case.
+// Returns an error if a country or currency code is invalid.
+func NewCurrencyRules(overrides map[string]string, fallback string) (*CurrencyRules, error) {
+	var errs validation.ValidationErrors
+	rules := &CurrencyRules{Overrides: make(map[Country]string, len(overrides)), Fallback: fallback}
+
+	for _, key := range sortedKeys(overrides) {
+		country, err := ParseCountry(key)
+		if err != nil {
+			errs.Merge("overrides."+key, "", err)
+			continue
+		}
+		currency, err := NewCurrencyFromCode(overrides[key])
+		if err != nil {
+			errs.Merge("overrides."+key, "", err)
+			continue
+		}
+		rules.Overrides[country] = currency.Code
+	}
+	if currency, err := NewCurrencyFromCode(fallback); err != nil {
+		errs.Merge("fallback", "", err)
+	} else {
+		rules.Fallback = currency.Code
+	}
+
+	if err := errs.Err(); err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+// Validate ensures every country code is valid and every currency code is
+// a supported, upper-case ISO 4217 code.
+func (r CurrencyRules) Validate() error {
+	var errs validation.ValidationErrors
+	countries := make([]Country, 0, len(r.Overrides))
+	for country := range r.Overrides {
+		countries = append(countries, country)
+	}
+	sort.Slice(countries, func(i, j int) bool { return countries[i] < countries[j] })
+
+	for _, country := range countries {
+		field := "overrides." + string(country)
+		errs.Merge(field, "", country.Validate())
+		if !isSupportedCurrencyCode(r.Overrides[country]) {
+			errs.Add(field, validation.CodeUnsupported, "unsupported currency code: "+r.Overrides[country], nil)
+		}
+	}
+	if !isSupportedCurrencyCode(r.Fallback) {
+		errs.Add("fallback", validation.CodeUnsupported, "unsupported currency code: "+r.Fallback, nil)
+	}
+	return errs.Err()
+}
+
+// isSupportedCurrencyCode reports whether code is a registered code in canonical form
+func isSupportedCurrencyCode(code string) bool {
+	return packageCurrencies.has(code)
+}
+
+// ForCountry returns the currency to pre-select for customers in country.
+func (r CurrencyRules) ForCountry(country Country) Currency {
+	if code, ok := r.Overrides[country]; ok {
+		if currency, err := NewCurrencyFromCode(code); err == nil {
+			return *currency
+		}
+	}
+	if currency, ok := country.DefaultCurrency(); ok {
+		return currency
+	}
+	return r.fallback()
+}
+
+// ForLocale returns the currency to pre-select for the locale's country, or
+// the fallback when the locale has no country.
+func (r CurrencyRules) ForLocale(locale Locale) Currency {
+	if country := locale.Country(); country != "" {
+		return r.ForCountry(country)
+	}
+	return r.fallback()
+}
+
+// fallback returns the fallback currency; invalid rules fall back to USD
+func (r CurrencyRules) fallback() Currency {
+	if currency, err := NewCurrencyFromCode(r.Fallback); err == nil {
+		return *currency
+	}
+	usd, _ := NewCurrencyFromCode("USD")
+	return *usd
+}
+
+// UnmarshalJSON implements json.Unmarshaler interface.
+func (r *CurrencyRules) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		Overrides map[string]string `json:"overrides"`
+		Fallback  string            `json:"fallback"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	rules, err := NewCurrencyRules(raw.Overrides, raw.Fallback)
+	if err != nil {
+		return err
+	}
+	*r = *rules
+	return nil
+}
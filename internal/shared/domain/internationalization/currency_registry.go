@@ -0,0 +1,172 @@
+// Package internationalization provides domain types for handling internationalization
+// concerns such as time, currency, timezone, and phone number formatting.
+//
+// This file holds the currency registry, the currencies NewCurrencyFromCode
+// knows, and so ParseMoney, database and binary decoding, exchange rates and
+// locale preferences. It starts with the built-in ISO 4217 currencies;
+// applications add loyalty points, in-game currencies or newly issued codes
+// at startup instead of forking the package:
+//
+//	err := RegisterCurrency(Currency{Code: "XLP", Symbol: "pts", Name: "Loyalty Points", DecimalPlaces: 0})
+//	points, err := ParseMoney("1500 XLP")
+//
+// Codes follow the ISO 4217 format, three uppercase letters; codes starting
+// with X are the ones ISO leaves to private use.
+package internationalization
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"golang-arch/internal/shared/domain/domainerror"
+)
+
+// CurrencyRegistry is a set of currencies safe for concurrent use. Lookups
+// read an immutable snapshot that every change replaces, so they take no
+// lock; changes are serialized.
+type CurrencyRegistry struct {
+	mu       sync.Mutex // Serializes changes
+	snapshot atomic.Pointer[currencySet]
+	builtIn  map[string]bool // Codes Unregister refuses; set once, never changed
+}
+
+// currencySet is one immutable state of a registry
+type currencySet struct {
+	byCode map[string]*Currency
+	codes  []string // In registration order
+}
+
+// NewCurrencyRegistry creates a registry holding currencies
+func NewCurrencyRegistry(currencies ...Currency) (*CurrencyRegistry, error) {
+	r := &CurrencyRegistry{}
+	r.snapshot.Store(&currencySet{byCode: map[string]*Currency{}})
+	for _, currency := range currencies {
+		if err := r.Register(currency); err != nil {
+			return nil, err
+		}
+	}
+	return r, nil
+}
+
+// Register adds a currency, failing with a Conflict error when its code is
+// already registered. The code is upper-cased and the currency validated.
+// The registry keeps its own copy, so changing c afterwards has no effect.
+func (r *CurrencyRegistry) Register(c Currency) error {
+	c.Code = strings.ToUpper(c.Code)
+	if err := c.Validate(); err != nil {
+		return fmt.Errorf("invalid currency: %w", err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	current := r.snapshot.Load()
+	if _, exists := current.byCode[c.Code]; exists {
+		return domainerror.Conflictf("currency %s is already registered", c.Code)
+	}
+	next := &currencySet{
+		byCode: make(map[string]*Currency, len(current.byCode)+1),
+		codes:  append(slices.Clip(current.codes), c.Code),
+	}
+	for code, currency := range current.byCode {
+		next.byCode[code] = currency
+	}
+	next.byCode[c.Code] = &c
+	r.snapshot.Store(next)
+	return nil
+}
+
+// Unregister removes a currency, failing with a NotFound error when its code
+// is not registered and a Forbidden error for a built-in ISO 4217 currency
+// of the package registry. Values already holding the currency keep it, but
+// new lookups, and so decoding stored amounts, fail.
+func (r *CurrencyRegistry) Unregister(code string) error {
+	code = strings.ToUpper(code)
+	if r.builtIn[code] {
+		return domainerror.Forbiddenf("currency %s is built in and cannot be unregistered", code)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	current := r.snapshot.Load()
+	if _, exists := current.byCode[code]; !exists {
+		return domainerror.NotFoundf("currency %s is not registered", code)
+	}
+	next := &currencySet{
+		byCode: make(map[string]*Currency, len(current.byCode)-1),
+		codes:  slices.DeleteFunc(slices.Clone(current.codes), func(c string) bool { return c == code }),
+	}
+	for c, currency := range current.byCode {
+		if c != code {
+			next.byCode[c] = currency
+		}
+	}
+	r.snapshot.Store(next)
+	return nil
+}
+
+// Lookup returns a copy of the currency registered under code, in any
+// letter case, so callers may change it without affecting the registry
+func (r *CurrencyRegistry) Lookup(code string) (*Currency, bool) {
+	byCode := r.snapshot.Load().byCode
+	currency, exists := byCode[code]
+	if !exists {
+		// strings.ToUpper only allocates when the code contains lowercase letters
+		currency, exists = byCode[strings.ToUpper(code)]
+	}
+	if !exists {
+		return nil, false
+	}
+	copied := *currency
+	return &copied, true
+}
+
+// Codes returns the registered codes in registration order
+func (r *CurrencyRegistry) Codes() []string {
+	return slices.Clone(r.snapshot.Load().codes)
+}
+
+// has reports whether code is registered in canonical form
+func (r *CurrencyRegistry) has(code string) bool {
+	_, exists := r.snapshot.Load().byCode[code]
+	return exists
+}
+
+// packageCurrencies is the registry behind NewCurrencyFromCode, starting
+// with copies of the built-in currencies, which stay registered. Those are
+// indexed without the validation Register applies, as SAR has no symbol.
+var packageCurrencies = func() *CurrencyRegistry {
+	builtIn := &currencySet{byCode: make(map[string]*Currency, len(supportedCurrencies))}
+	r := &CurrencyRegistry{builtIn: make(map[string]bool, len(supportedCurrencies))}
+	for _, currency := range supportedCurrencies {
+		builtIn.byCode[currency.Code] = &currency
+		builtIn.codes = append(builtIn.codes, currency.Code)
+		r.builtIn[currency.Code] = true
+	}
+	r.snapshot.Store(builtIn)
+	return r
+}()
+
+// Currencies returns the registry behind NewCurrencyFromCode
+func Currencies() *CurrencyRegistry {
+	return packageCurrencies
+}
+
+// RegisterCurrency adds a currency to the registry behind
+// NewCurrencyFromCode; see CurrencyRegistry.Register
+func RegisterCurrency(c Currency) error {
+	return packageCurrencies.Register(c)
+}
+
+// UnregisterCurrency removes a currency from the registry behind
+// NewCurrencyFromCode; see CurrencyRegistry.Unregister
+func UnregisterCurrency(code string) error {
+	return packageCurrencies.Unregister(code)
+}
+
+// LookupCurrency returns a copy of the registered currency of code
+func LookupCurrency(code string) (*Currency, bool) {
+	return packageCurrencies.Lookup(code)
+}
@@ -0,0 +1,279 @@
+// Package internationalization provides domain types and composite types for handling
+// internationalized data such as time, currency, timezone, and phone numbers.
+// This file contains the Date type for calendar dates and the Period type
+// for calendar-correct date differences.
+//
+// Date Type:
+//   - A calendar date without time of day or timezone, e.g. a birth date
+//   - Strict validation: 2023-02-29 is rejected instead of rolling over
+//   - The date of an instant depends on the timezone; see LocalizedDateTime.Date
+//
+// Period Type:
+//   - Difference in years, months and days ("17 years, 11 months, 30 days")
+//   - Month lengths and leap years are taken into account
+//
+// Database Storage: Stored as string (DATE, "YYYY-MM-DD")
+// JSON Format: "1990-05-17"
+//
+// Usage Examples:
+//
+//	birth, err := ParseDate("2006-02-28")
+//	age, err := Age(birth, *now)                // whole years in now's timezone
+//	ok, err := MeetsMinimumAge(birth, 18, *now) // KYC check
+//	period := DateDifference(start, end)        // {Years: 1, Months: 2, Days: 3}
+package internationalization
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"golang-arch/internal/shared/domain/domainerror"
+)
+
+// dateLayout is the ISO 8601 calendar date layout
+const dateLayout = "2006-01-02"
+
+// Date is a calendar date in the proleptic Gregorian calendar.
+//
+// Features:
+//   - Comparable with == and usable as a map key
+//   - Day arithmetic without DST or timezone effects
+//
+// Example:
+//
+//	d, _ := NewDate(2024, time.February, 29)
+//	d.AddYears(1) // 2025-02-28
+type Date struct {
+	Year  int
+	Month time.Month
+	Day   int
+}
+
+// NewDate creates a Date, rejecting days that do not exist in the month.
+func NewDate(year int, month time.Month, day int) (Date, error) {
+	d := Date{Year: year, Month: month, Day: day}
+	if err := d.Validate(); err != nil {
+		return Date{}, err
+	}
+	return d, nil
+}
+
+// ParseDate parses a "YYYY-MM-DD" date.
+func ParseDate(value string) (Date, error) {
+	parsed, err := time.Parse(dateLayout, value)
+	if err != nil {
+		return Date{}, domainerror.Invalidf("invalid date %q (expected YYYY-MM-DD)", value)
+	}
+	return DateOf(parsed), nil
+}
+
+// DateOf returns the date of t in t's location.
+func DateOf(t time.Time) Date {
+	year, month, day := t.Date()
+	return Date{Year: year, Month: month, Day: day}
+}
+
+// ToPrimitive returns the "YYYY-MM-DD" form for storage.
+func (d Date) ToPrimitive() string {
+	return d.String()
+}
+
+// Validate ensures the date exists, e.g. rejects February 29 outside leap years.
+func (d Date) Validate() error {
+	if d.Year < 1 || d.Year > 9999 {
+		return domainerror.Invalidf("invalid date %s: year must be between 1 and 9999", d)
+	}
+	if d.Month < time.January || d.Month > time.December {
+		return domainerror.Invalidf("invalid date %s: month must be between 1 and 12", d)
+	}
+	if d.Day < 1 || d.Day > daysIn(d.Year, d.Month) {
+		return domainerror.Invalidf("invalid date %s: %s %d has %d days", d, d.Month, d.Year, daysIn(d.Year, d.Month))
+	}
+	return nil
+}
+
+// IsZero reports whether d is the zero Date.
+func (d Date) IsZero() bool {
+	return d == Date{}
+}
+
+// In returns midnight at the start of d in loc. On days where a DST change
+// skips midnight, the result is the first instant of the day.
+func (d Date) In(loc *time.Location) time.Time {
+	return time.Date(d.Year, d.Month, d.Day, 0, 0, 0, 0, loc)
+}
+
+// AddDays returns the date n days later; negative n moves backwards.
+func (d Date) AddDays(n int) Date {
+	return DateOf(time.Date(d.Year, d.Month, d.Day+n, 12, 0, 0, 0, time.UTC))
+}
+
+// AddMonths returns the date n months later, clamping the day to the end of
+// the target month, so January 31 plus one month is February 28 (or 29).
+func (d Date) AddMonths(n int) Date {
+	total := d.Year*12 + int(d.Month-1) + n
+	year, month := total/12, time.Month(total%12+1)
+	return Date{Year: year, Month: month, Day: min(d.Day, daysIn(year, month))}
+}
+
+// AddYears returns the date n years later, clamping February 29 to
+// February 28 in non-leap years.
+func (d Date) AddYears(n int) Date {
+	return d.AddMonths(12 * n)
+}
+
+// Compare returns -1, 0 or +1 when d is before, equal to or after other.
+func (d Date) Compare(other Date) int {
+	switch {
+	case d.Year != other.Year:
+		return sign(d.Year - other.Year)
+	case d.Month != other.Month:
+		return sign(int(d.Month - other.Month))
+	default:
+		return sign(d.Day - other.Day)
+	}
+}
+
+// Before reports whether d is before other.
+func (d Date) Before(other Date) bool {
+	return d.Compare(other) < 0
+}
+
+// After reports whether d is after other.
+func (d Date) After(other Date) bool {
+	return d.Compare(other) > 0
+}
+
+// DaysUntil returns the number of days from d to other, negative when other
+// is before d.
+func (d Date) DaysUntil(other Date) int {
+	return int(other.In(time.UTC).Sub(d.In(time.UTC)).Hours() / 24)
+}
+
+// String returns the date as "YYYY-MM-DD".
+func (d Date) String() string {
+	return fmt.Sprintf("%04d-%02d-%02d", d.Year, int(d.Month), d.Day)
+}
+
+// MarshalJSON implements json.Marshaler as a "YYYY-MM-DD" string.
+func (d Date) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.String())
+}
+
+// UnmarshalJSON implements json.Unmarshaler interface.
+func (d *Date) UnmarshalJSON(data []byte) error {
+	var value string
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	parsed, err := ParseDate(value)
+	if err != nil {
+		return err
+	}
+	*d = parsed
+	return nil
+}
+
+// Date returns the calendar date of the localized datetime in its timezone.
+func (ldt LocalizedDateTime) Date() (Date, error) {
+	loc, err := ldt.Timezone.GetLocation()
+	if err != nil {
+		return Date{}, err
+	}
+	return DateOf(ldt.Time.ToTime().In(loc)), nil
+}
+
+// Period is a calendar difference between two dates. All fields share the
+// sign of the difference.
+type Period struct {
+	Years  int `json:"years"`
+	Months int `json:"months"`
+	Days   int `json:"days"`
+}
+
+// DateDifference returns the period from from to to: whole years, then whole
+// months, then the remaining days. A month is counted once the day of month
+// is reached, clamped to the month's length for the remaining days, so
+// 2024-01-31 to 2024-03-01 is 1 month and 1 day.
+func DateDifference(from, to Date) Period {
+	if to.Before(from) {
+		p := DateDifference(to, from)
+		return Period{Years: -p.Years, Months: -p.Months, Days: -p.Days}
+	}
+
+	months := (to.Year-from.Year)*12 + int(to.Month-from.Month)
+	if to.Day < from.Day {
+		months--
+	}
+	days := from.AddMonths(months).DaysUntil(to)
+	return Period{Years: months / 12, Months: months % 12, Days: days}
+}
+
+// IsZero reports whether the period is empty.
+func (p Period) IsZero() bool {
+	return p == Period{}
+}
+
+// String returns the period in ISO 8601 form, e.g. "P1Y2M3D".
+func (p Period) String() string {
+	if p.IsZero() {
+		return "P0D"
+	}
+	s := "P"
+	if p.Years != 0 {
+		s += fmt.Sprintf("%dY", p.Years)
+	}
+	if p.Months != 0 {
+		s += fmt.Sprintf("%dM", p.Months)
+	}
+	if p.Days != 0 {
+		s += fmt.Sprintf("%dD", p.Days)
+	}
+	return s
+}
+
+// Age returns the age in whole years of someone born on birthDate, on the
+// date asOf falls on in its own timezone. Someone born on February 29 turns
+// a year older on March 1 in non-leap years.
+// Returns an error if birthDate is invalid or after that date.
+func Age(birthDate Date, asOf LocalizedDateTime) (int, error) {
+	if err := birthDate.Validate(); err != nil {
+		return 0, err
+	}
+	today, err := asOf.Date()
+	if err != nil {
+		return 0, err
+	}
+	if birthDate.After(today) {
+		return 0, domainerror.Invalidf("birth date %s is after %s", birthDate, today)
+	}
+	return DateDifference(birthDate, today).Years, nil
+}
+
+// MeetsMinimumAge reports whether someone born on birthDate is at least
+// minimum years old as of asOf, e.g. for KYC checks.
+func MeetsMinimumAge(birthDate Date, minimum int, asOf LocalizedDateTime) (bool, error) {
+	age, err := Age(birthDate, asOf)
+	if err != nil {
+		return false, err
+	}
+	return age >= minimum, nil
+}
+
+// daysIn returns the number of days in month of year.
+func daysIn(year int, month time.Month) int {
+	return time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
+
+// sign returns -1, 0 or +1 following the sign of n.
+func sign(n int) int {
+	switch {
+	case n < 0:
+		return -1
+	case n > 0:
+		return 1
+	default:
+		return 0
+	}
+}
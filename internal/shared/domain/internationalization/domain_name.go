@@ -0,0 +1,446 @@
+// Package internationalization provides domain types and composite types for handling
+// internationalized data such as time, currency, timezone, and phone numbers.
+// This file contains the DomainName and URL types for internationalized
+// domain names (IDN).
+//
+// DomainName Type:
+//   - IDNA2008 (UTS #46, non-transitional) normalization and validation
+//   - ASCII (punycode A-label) form for storage and DNS, Unicode form for display
+//   - Homograph warnings for mixed-script and whole-script confusable labels
+//
+// URL Type:
+//   - Absolute http(s) URL whose host is a DomainName
+//   - Stored with the ASCII host, displayed with the Unicode host
+//
+// Database Storage: Stored as string (ASCII form)
+// JSON Format: {"ascii": "xn--mnchen-3ya.de", "unicode": "münchen.de"}
+//
+// Usage Examples:
+//
+//	domain, err := NewDomainName("Bücher.Example")
+//	domain.ToASCII()   // "xn--bcher-kva.example"
+//	domain.ToUnicode() // "bücher.example"
+//	for _, w := range domain.HomographWarnings() { ... }
+//	site, err := NewURL("https://münchen.de/shop?q=1")
+package internationalization
+
+import (
+	"encoding/json"
+	"net/url"
+	"strings"
+	"unicode"
+
+	"golang.org/x/net/idna"
+
+	"golang-arch/internal/shared/domain/domainerror"
+)
+
+// idnaProfile maps and validates names the way browsers look them up, and
+// rejects names that are not valid host names once converted.
+var idnaProfile = idna.New(
+	idna.MapForLookup(),
+	idna.BidiRule(),
+	idna.Transitional(false),
+	idna.VerifyDNSLength(true),
+)
+
+// DomainName is a fully qualified domain name kept in both its ASCII and
+// Unicode forms. The ASCII form is canonical: two names are equal when
+// their ASCII forms are.
+//
+// Features:
+//   - Case folding and Unicode normalization (MÜNCHEN.de == münchen.de)
+//   - Punycode round trip (xn--mnchen-3ya.de <-> münchen.de)
+//   - At least two labels and an alphabetic top-level domain
+//
+// Example:
+//
+//	domain, _ := NewDomainName("xn--mnchen-3ya.de")
+//	domain.String() // "münchen.de"
+type DomainName struct {
+	ASCII   string `json:"ascii"`   // A-label form, e.g. "xn--mnchen-3ya.de"
+	Unicode string `json:"unicode"` // U-label form, e.g. "münchen.de"
+}
+
+// NewDomainName creates a DomainName from its Unicode or ASCII form. A
+// trailing dot is ignored.
+func NewDomainName(name string) (*DomainName, error) {
+	trimmed := strings.TrimSuffix(strings.TrimSpace(name), ".")
+	if trimmed == "" {
+		return nil, domainerror.Invalidf("domain name cannot be empty")
+	}
+
+	ascii, err := idnaProfile.ToASCII(trimmed)
+	if err != nil {
+		return nil, domainerror.Invalidf("invalid domain name %q: %v", name, err)
+	}
+	unicodeName, err := idnaProfile.ToUnicode(ascii)
+	if err != nil {
+		return nil, domainerror.Invalidf("invalid domain name %q: %v", name, err)
+	}
+
+	d := &DomainName{ASCII: ascii, Unicode: unicodeName}
+	if err := d.validateStructure(); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// NewDomainNameFromPrimitive creates a DomainName from its stored ASCII form.
+func NewDomainNameFromPrimitive(ascii string) (*DomainName, error) {
+	return NewDomainName(ascii)
+}
+
+// ToPrimitive returns the ASCII form for storage.
+func (d DomainName) ToPrimitive() string {
+	return d.ASCII
+}
+
+// ToASCII returns the punycode (A-label) form, e.g. "xn--mnchen-3ya.de".
+func (d DomainName) ToASCII() string {
+	return d.ASCII
+}
+
+// ToUnicode returns the Unicode (U-label) form, e.g. "münchen.de".
+func (d DomainName) ToUnicode() string {
+	return d.Unicode
+}
+
+// IsInternationalized reports whether any label uses non-ASCII characters.
+func (d DomainName) IsInternationalized() bool {
+	return d.ASCII != d.Unicode
+}
+
+// Validate ensures both forms are consistent, normalized and well formed.
+func (d DomainName) Validate() error {
+	canonical, err := NewDomainName(d.ASCII)
+	if err != nil {
+		return err
+	}
+	if canonical.ASCII != d.ASCII || canonical.Unicode != d.Unicode {
+		return domainerror.Invalidf("domain name %q is not normalized", d.ASCII)
+	}
+	return nil
+}
+
+// validateStructure applies the host name rules IDNA does not cover.
+func (d DomainName) validateStructure() error {
+	labels := strings.Split(d.ASCII, ".")
+	if len(labels) < 2 {
+		return domainerror.Invalidf("domain name %q must have at least two labels", d.Unicode)
+	}
+	if tld := labels[len(labels)-1]; !isAlpha(tld) && !strings.HasPrefix(tld, "xn--") {
+		return domainerror.Invalidf("domain name %q has an invalid top-level domain", d.Unicode)
+	}
+	return nil
+}
+
+// Equal reports whether both names have the same canonical form.
+func (d *DomainName) Equal(other *DomainName) bool {
+	if d == nil || other == nil {
+		return d == other
+	}
+	return d.ASCII == other.ASCII
+}
+
+// String returns the Unicode form for display.
+func (d DomainName) String() string {
+	return d.Unicode
+}
+
+// UnmarshalJSON implements json.Unmarshaler interface. Both the object form
+// and a plain string are accepted; the name is re-normalized either way.
+func (d *DomainName) UnmarshalJSON(data []byte) error {
+	var name string
+	if err := json.Unmarshal(data, &name); err != nil {
+		var object struct {
+			ASCII   string `json:"ascii"`
+			Unicode string `json:"unicode"`
+		}
+		if err := json.Unmarshal(data, &object); err != nil {
+			return err
+		}
+		name = object.ASCII
+		if name == "" {
+			name = object.Unicode
+		}
+	}
+
+	domain, err := NewDomainName(name)
+	if err != nil {
+		return err
+	}
+	*d = *domain
+	return nil
+}
+
+// Homograph warning codes
+const (
+	HomographMixedScript           = "mixed_script"
+	HomographWholeScriptConfusable = "whole_script_confusable"
+)
+
+// HomographWarning flags a label that could be mistaken for a different
+// name, such as "раypal" spelled with Cyrillic letters. Warnings do not make
+// a name invalid; callers decide whether to reject, review or display the
+// ASCII form instead.
+type HomographWarning struct {
+	Label   string `json:"label"`   // Unicode label the warning is about
+	Code    string `json:"code"`    // mixed_script or whole_script_confusable
+	Message string `json:"message"` // Human-readable explanation
+}
+
+// HomographWarnings inspects every internationalized label, following the
+// "highly restrictive" profile of UTS #39: a label may use one script, or
+// Latin combined with the scripts of Chinese, Japanese or Korean.
+func (d DomainName) HomographWarnings() []HomographWarning {
+	var warnings []HomographWarning
+	for _, label := range strings.Split(d.Unicode, ".") {
+		if isASCII(label) {
+			continue
+		}
+
+		scripts := labelScripts(label)
+		switch {
+		case !allowedScriptMix(scripts):
+			warnings = append(warnings, HomographWarning{
+				Label:   label,
+				Code:    HomographMixedScript,
+				Message: "label mixes " + strings.Join(scriptNames(scripts), ", ") + " characters",
+			})
+		case isLatinConfusable(label):
+			warnings = append(warnings, HomographWarning{
+				Label:   label,
+				Code:    HomographWholeScriptConfusable,
+				Message: "label consists of " + strings.Join(scriptNames(scripts), ", ") + " characters that look like Latin letters",
+			})
+		}
+	}
+	return warnings
+}
+
+// scriptTables lists the scripts distinguished by the homograph checks, in
+// reporting order
+var scriptTables = []struct {
+	name  string
+	table *unicode.RangeTable
+}{
+	{"Latin", unicode.Latin},
+	{"Cyrillic", unicode.Cyrillic},
+	{"Greek", unicode.Greek},
+	{"Armenian", unicode.Armenian},
+	{"Hebrew", unicode.Hebrew},
+	{"Arabic", unicode.Arabic},
+	{"Han", unicode.Han},
+	{"Hiragana", unicode.Hiragana},
+	{"Katakana", unicode.Katakana},
+	{"Hangul", unicode.Hangul},
+	{"Bopomofo", unicode.Bopomofo},
+	{"Thai", unicode.Thai},
+	{"Devanagari", unicode.Devanagari},
+}
+
+// labelScripts returns the set of scripts used by the letters of label;
+// digits, hyphens and combining marks are script-neutral.
+func labelScripts(label string) map[string]bool {
+	scripts := make(map[string]bool)
+	for _, r := range label {
+		if !unicode.IsLetter(r) {
+			continue
+		}
+		name := "Other"
+		for _, script := range scriptTables {
+			if unicode.Is(script.table, r) {
+				name = script.name
+				break
+			}
+		}
+		scripts[name] = true
+	}
+	return scripts
+}
+
+// allowedScriptMix reports whether the scripts form a single script or one
+// of the CJK combinations UTS #39 allows
+func allowedScriptMix(scripts map[string]bool) bool {
+	if len(scripts) <= 1 {
+		return true
+	}
+	cjk := map[string]bool{"Latin": true, "Han": true, "Hiragana": true, "Katakana": true, "Hangul": true, "Bopomofo": true}
+	for script := range scripts {
+		if !cjk[script] {
+			return false
+		}
+	}
+	// Japanese, Chinese and Korean mixes must not combine Hangul with kana or Bopomofo
+	return !(scripts["Hangul"] && (scripts["Hiragana"] || scripts["Katakana"] || scripts["Bopomofo"])) &&
+		!(scripts["Bopomofo"] && (scripts["Hiragana"] || scripts["Katakana"]))
+}
+
+// latinLookalikes are Cyrillic and Greek letters that render like Latin ones
+const latinLookalikes = "аеорсуухіјѕԁһӏԛԝвмнктьАВЕКМНОРСТУХІЈЅαβεικνορτυχΑΒΕΖΗΙΚΜΝΟΡΤΥΧ"
+
+// isLatinConfusable reports whether every letter of a Cyrillic or Greek label
+// has a Latin lookalike
+func isLatinConfusable(label string) bool {
+	letters := 0
+	for _, r := range label {
+		if !unicode.IsLetter(r) {
+			continue
+		}
+		if !unicode.Is(unicode.Cyrillic, r) && !unicode.Is(unicode.Greek, r) {
+			return false
+		}
+		if !strings.ContainsRune(latinLookalikes, r) {
+			return false
+		}
+		letters++
+	}
+	return letters > 0
+}
+
+// scriptNames returns the script names in reporting order
+func scriptNames(scripts map[string]bool) []string {
+	var names []string
+	for _, script := range scriptTables {
+		if scripts[script.name] {
+			names = append(names, script.name)
+		}
+	}
+	if scripts["Other"] {
+		names = append(names, "Other")
+	}
+	return names
+}
+
+// isASCII reports whether s contains only ASCII characters.
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= 0x80 {
+			return false
+		}
+	}
+	return true
+}
+
+// URL is an absolute http or https URL whose host is an internationalized
+// domain name. The ASCII form is canonical and is what gets stored.
+//
+// Example:
+//
+//	site, _ := NewURL("HTTPS://Bücher.example/Katalog?q=1")
+//	site.ToASCII()   // "https://xn--bcher-kva.example/Katalog?q=1"
+//	site.ToUnicode() // "https://bücher.example/Katalog?q=1"
+type URL struct {
+	ASCII   string     `json:"ascii"`   // URL with the punycode host
+	Unicode string     `json:"unicode"` // URL with the Unicode host, for display
+	Domain  DomainName `json:"-"`       // Parsed host
+}
+
+// NewURL parses and normalizes an absolute http(s) URL. User info is
+// rejected because it is commonly used to disguise the real host.
+func NewURL(raw string) (*URL, error) {
+	parsed, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil {
+		return nil, domainerror.Invalidf("invalid URL %q: %v", raw, err)
+	}
+
+	scheme := strings.ToLower(parsed.Scheme)
+	if scheme != "http" && scheme != "https" {
+		return nil, domainerror.Invalidf("invalid URL %q: scheme must be http or https", raw)
+	}
+	if parsed.User != nil {
+		return nil, domainerror.Invalidf("invalid URL %q: user info is not allowed", raw)
+	}
+
+	domain, err := NewDomainName(parsed.Hostname())
+	if err != nil {
+		return nil, err
+	}
+
+	host := func(name string) string {
+		if port := parsed.Port(); port != "" {
+			return name + ":" + port
+		}
+		return name
+	}
+
+	parsed.Scheme = scheme
+	parsed.Host = host(domain.ASCII)
+	ascii := parsed.String()
+
+	// url.URL escapes non-ASCII hosts, so the display form is assembled by
+	// replacing the host in the ASCII form
+	prefix := scheme + "://" + parsed.Host
+	unicodeURL := scheme + "://" + host(domain.Unicode) + strings.TrimPrefix(ascii, prefix)
+
+	return &URL{ASCII: ascii, Unicode: unicodeURL, Domain: *domain}, nil
+}
+
+// NewURLFromPrimitive creates a URL from its stored ASCII form.
+func NewURLFromPrimitive(ascii string) (*URL, error) {
+	return NewURL(ascii)
+}
+
+// ToPrimitive returns the ASCII form for storage.
+func (u URL) ToPrimitive() string {
+	return u.ASCII
+}
+
+// ToASCII returns the URL with the punycode host.
+func (u URL) ToASCII() string {
+	return u.ASCII
+}
+
+// ToUnicode returns the URL with the Unicode host, for display.
+func (u URL) ToUnicode() string {
+	return u.Unicode
+}
+
+// HomographWarnings returns the homograph warnings of the URL's host.
+func (u URL) HomographWarnings() []HomographWarning {
+	return u.Domain.HomographWarnings()
+}
+
+// Validate ensures the URL is normalized.
+func (u URL) Validate() error {
+	canonical, err := NewURL(u.ASCII)
+	if err != nil {
+		return err
+	}
+	if canonical.ASCII != u.ASCII {
+		return domainerror.Invalidf("URL %q is not normalized", u.ASCII)
+	}
+	return nil
+}
+
+// String returns the display form.
+func (u URL) String() string {
+	return u.Unicode
+}
+
+// UnmarshalJSON implements json.Unmarshaler interface. Both the object form
+// and a plain string are accepted; the URL is re-normalized either way.
+func (u *URL) UnmarshalJSON(data []byte) error {
+	var raw string
+	if err := json.Unmarshal(data, &raw); err != nil {
+		var object struct {
+			ASCII   string `json:"ascii"`
+			Unicode string `json:"unicode"`
+		}
+		if err := json.Unmarshal(data, &object); err != nil {
+			return err
+		}
+		raw = object.ASCII
+		if raw == "" {
+			raw = object.Unicode
+		}
+	}
+
+	parsed, err := NewURL(raw)
+	if err != nil {
+		return err
+	}
+	*u = *parsed
+	return nil
+}
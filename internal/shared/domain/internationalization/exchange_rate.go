@@ -0,0 +1,328 @@
+// Package internationalization provides domain types and composite types for handling
+// internationalized data such as time, currency, timezone, and phone numbers.
+// This file contains the ExchangeRate composite type for currency conversion.
+//
+// ExchangeRate Composite Type:
+//   - Price of one unit of a base currency in a quote currency
+//   - Rate stored as a scaled integer (Rate / 10^Scale), never as float64
+//   - Inversion and cross-rate composition (USD→EUR→GBP)
+//   - Staleness checks against the package clock
+//   - Money conversion with an explicit rounding mode
+//   - Money.ConvertTo through a pluggable CurrencyConverter
+//
+// Database Storage: (base_code string, quote_code string, rate int64, scale int, timestamp int64)
+// JSON Format: {"base": {...}, "quote": {...}, "rate": 10845, "scale": 4, "timestamp": {"epoch": 1703520000}}
+//
+// Usage Examples:
+//
+//	usdEur, err := NewExchangeRateFromDecimal(usd, eur, "0.9221", *NewTimeFromTime(time.Now()))
+//	eurUsd, err := usdEur.Invert()                // 1 EUR = 1.08448107581 USD
+//	usdGbp, err := usdEur.Cross(eurGbp)           // USD→EUR→GBP
+//	euros, err := usdEur.Convert(price, RoundHalfEven)
+//	if usdEur.IsStale(time.Hour) { ... }
+//	euros, err := price.ConvertTo(eur, converter) // rates from any CurrencyConverter
+package internationalization
+
+import (
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang-arch/internal/shared/domain/domainerror"
+	"golang-arch/internal/shared/domain/validation"
+)
+
+const (
+	// RatePrecision is the number of significant digits kept when a rate is
+	// derived by Invert or Cross.
+	RatePrecision = 12
+	// MaxRateScale is the largest number of decimal places a rate may have.
+	MaxRateScale = 30
+)
+
+// ExchangeRate represents the price of one unit of Base expressed in Quote,
+// stored as the scaled integer Rate / 10^Scale. The rate is always positive
+// and normalized so that Rate has no trailing zeros in its decimal places.
+//
+// Features:
+//   - Exact decimal storage without floating-point error
+//   - Inversion and cross-rate composition with bounded precision
+//   - Staleness checks for cached or provider rates
+//   - Conversion of Money between minor units of both currencies
+//
+// Example:
+//
+//	rate, _ := NewExchangeRate(usd, eur, 9221, 4, *timestamp) // 1 USD = 0.9221 EUR
+//	rate.DecimalString() // "0.9221"
+type ExchangeRate struct {
+	Base      Currency `json:"base"`      // Currency being priced
+	Quote     Currency `json:"quote"`     // Currency the price is expressed in
+	Rate      int64    `json:"rate"`      // Units of Quote per Base, scaled by 10^Scale
+	Scale     int      `json:"scale"`     // Number of decimal places in Rate
+	Timestamp Time     `json:"timestamp"` // When the rate was observed
+}
+
+// NewExchangeRate creates a new ExchangeRate composite type from a scaled
+// integer rate. Returns an error if any component is invalid.
+func NewExchangeRate(base, quote Currency, rate int64, scale int, timestamp Time) (*ExchangeRate, error) {
+	r := &ExchangeRate{Base: base, Quote: quote, Rate: rate, Scale: scale, Timestamp: timestamp}
+	if err := r.Validate(); err != nil {
+		return nil, err
+	}
+	r.Rate, r.Scale = normalizeRate(r.Rate, r.Scale)
+	return r, nil
+}
+
+// NewExchangeRateFromDecimal creates an ExchangeRate from a decimal string
+// such as "0.9221". The value is parsed exactly, without going through float64.
+func NewExchangeRateFromDecimal(base, quote Currency, rate string, timestamp Time) (*ExchangeRate, error) {
+	integerPart, fractionPart, _ := strings.Cut(rate, ".")
+	if len(fractionPart) > MaxRateScale {
+		return nil, domainerror.Invalidf("exchange rate %q has more than %d decimal places", rate, MaxRateScale)
+	}
+
+	scaled, err := strconv.ParseInt(integerPart+fractionPart, 10, 64)
+	if err != nil || integerPart == "" || strings.ContainsAny(rate, "+-") {
+		return nil, domainerror.Invalidf("invalid exchange rate: %q", rate)
+	}
+
+	return NewExchangeRate(base, quote, scaled, len(fractionPart), timestamp)
+}
+
+// NewExchangeRateFromPrimitive creates an ExchangeRate from primitive database values.
+func NewExchangeRateFromPrimitive(baseCode, quoteCode string, rate int64, scale int, epoch int64) (*ExchangeRate, error) {
+	base, err := NewCurrencyFromCode(baseCode)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create exchange rate from primitive: %w", err)
+	}
+
+	quote, err := NewCurrencyFromCode(quoteCode)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create exchange rate from primitive: %w", err)
+	}
+
+	timestamp, err := FromPrimitive(epoch)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create exchange rate from primitive: %w", err)
+	}
+
+	return NewExchangeRate(*base, *quote, rate, scale, *timestamp)
+}
+
+// ToPrimitive converts the ExchangeRate to primitive database values.
+// Returns the base and quote codes, the scaled rate, its scale and the epoch.
+func (r ExchangeRate) ToPrimitive() (string, string, int64, int, int64) {
+	return r.Base.ToPrimitive(), r.Quote.ToPrimitive(), r.Rate, r.Scale, r.Timestamp.ToPrimitive()
+}
+
+// Validate ensures the ExchangeRate composite type is valid.
+func (r ExchangeRate) Validate() error {
+	var errs validation.ValidationErrors
+	errs.Merge("base", "invalid base currency in exchange rate", r.Base.Validate())
+	errs.Merge("quote", "invalid quote currency in exchange rate", r.Quote.Validate())
+	if r.Base.Code != "" && r.Base.Code == r.Quote.Code {
+		errs.Add("quote", validation.CodeInvalid,
+			fmt.Sprintf("exchange rate base and quote must differ: %s", r.Base.Code), nil)
+	}
+	if r.Rate <= 0 {
+		errs.Add("rate", validation.CodeOutOfRange, "exchange rate must be positive",
+			map[string]any{"actual": r.Rate})
+	}
+	if r.Scale < 0 || r.Scale > MaxRateScale {
+		errs.Add("scale", validation.CodeOutOfRange,
+			fmt.Sprintf("exchange rate scale must be between 0 and %d", MaxRateScale),
+			map[string]any{"min": 0, "max": MaxRateScale, "actual": r.Scale})
+	}
+	errs.Merge("timestamp", "invalid timestamp in exchange rate", r.Timestamp.Validate())
+	return errs.Err()
+}
+
+// DecimalString returns the exact rate as a decimal string (e.g. "0.9221").
+func (r ExchangeRate) DecimalString() string {
+	digits := strconv.FormatInt(r.Rate, 10)
+	if r.Scale <= 0 {
+		return digits
+	}
+	if len(digits) <= r.Scale {
+		digits = strings.Repeat("0", r.Scale-len(digits)+1) + digits
+	}
+	return digits[:len(digits)-r.Scale] + "." + digits[len(digits)-r.Scale:]
+}
+
+// Float64 returns an approximation of the rate for display and charts only.
+func (r ExchangeRate) Float64() float64 {
+	f, _ := new(big.Rat).SetFrac(big.NewInt(r.Rate), bigPow10(r.Scale)).Float64()
+	return f
+}
+
+// String returns a string representation such as "1 USD = 0.9221 EUR".
+func (r ExchangeRate) String() string {
+	return fmt.Sprintf("1 %s = %s %s", r.Base.Code, r.DecimalString(), r.Quote.Code)
+}
+
+// Invert returns the rate from Quote to Base, rounded half-even to
+// RatePrecision significant digits.
+func (r ExchangeRate) Invert() (*ExchangeRate, error) {
+	rate, scale, err := rateFromRational(bigPow10(r.Scale), big.NewInt(r.Rate))
+	if err != nil {
+		return nil, err
+	}
+	return NewExchangeRate(r.Quote, r.Base, rate, scale, r.Timestamp)
+}
+
+// Cross composes this rate with next, whose base must be this rate's quote,
+// giving the rate from this base to next's quote (USD→EUR and EUR→GBP give
+// USD→GBP). The result is rounded half-even to RatePrecision significant
+// digits and carries the older of the two timestamps.
+func (r ExchangeRate) Cross(next *ExchangeRate) (*ExchangeRate, error) {
+	if next == nil {
+		return nil, domainerror.Invalidf("cross rate requires a second exchange rate")
+	}
+	if r.Quote.Code != next.Base.Code {
+		return nil, domainerror.Invalidf("cannot cross %s/%s with %s/%s: %s does not match %s",
+			r.Base.Code, r.Quote.Code, next.Base.Code, next.Quote.Code, r.Quote.Code, next.Base.Code)
+	}
+
+	num := new(big.Int).Mul(big.NewInt(r.Rate), big.NewInt(next.Rate))
+	rate, scale, err := rateFromRational(num, bigPow10(r.Scale+next.Scale))
+	if err != nil {
+		return nil, err
+	}
+
+	timestamp := r.Timestamp
+	if next.Timestamp.Epoch < timestamp.Epoch {
+		timestamp = next.Timestamp
+	}
+	return NewExchangeRate(r.Base, next.Quote, rate, scale, timestamp)
+}
+
+// Convert converts an amount in Base into Quote, rounding the result to the
+// quote currency's minor units with mode.
+func (r ExchangeRate) Convert(amount Money, mode RoundingMode) (*Money, error) {
+	if amount.Currency.Code != r.Base.Code {
+		return nil, domainerror.Invalidf("cannot convert %s with a %s/%s rate",
+			amount.Currency.Code, r.Base.Code, r.Quote.Code)
+	}
+
+	num := new(big.Int).Mul(big.NewInt(amount.Amount), big.NewInt(r.Rate))
+	num.Mul(num, bigPow10(r.Quote.DecimalPlaces))
+	den := bigPow10(r.Scale + amount.Currency.DecimalPlaces)
+
+	converted, err := roundQuotient(num, den, mode)
+	if err != nil {
+		return nil, err
+	}
+	return NewMoneyFromInteger(converted, r.Quote)
+}
+
+// CurrencyConverter supplies the exchange rates Money.ConvertTo converts at.
+// The rates package implements it with the current provider rates and with a
+// fixed in-memory table.
+type CurrencyConverter interface {
+	// ExchangeRate returns the rate from base to quote.
+	ExchangeRate(base, quote Currency) (*ExchangeRate, error)
+}
+
+// ConvertTo converts the money into target at the rate converter supplies,
+// rounding half-even to the target's minor units. Money already in target is
+// returned as is, without asking the converter.
+func (m Money) ConvertTo(target Currency, converter CurrencyConverter) (*Money, error) {
+	if m.Currency.Code == target.Code {
+		return NewMoneyFromInteger(m.Amount, m.Currency)
+	}
+	if converter == nil {
+		return nil, domainerror.Invalidf("cannot convert %s to %s without a currency converter",
+			m.Currency.Code, target.Code)
+	}
+
+	rate, err := converter.ExchangeRate(m.Currency, target)
+	if err != nil {
+		return nil, err
+	}
+	if rate.Base.Code != m.Currency.Code || rate.Quote.Code != target.Code {
+		return nil, domainerror.Invalidf("converter returned a %s/%s rate for %s to %s",
+			rate.Base.Code, rate.Quote.Code, m.Currency.Code, target.Code)
+	}
+	return rate.Convert(m, RoundHalfEven)
+}
+
+// AddConverting adds other, converted into the money's currency at the rate
+// converter supplies, for aggregating amounts across currencies, e.g. in
+// reports. Unlike Add, a different currency is not an error; the result is in
+// the money's currency.
+func (m Money) AddConverting(other *Money, converter CurrencyConverter) (*Money, error) {
+	converted, err := other.ConvertTo(m.Currency, converter)
+	if err != nil {
+		return nil, err
+	}
+	return m.Add(converted)
+}
+
+// SubtractConverting subtracts other, converted into the money's currency at
+// the rate converter supplies. The result is in the money's currency.
+func (m Money) SubtractConverting(other *Money, converter CurrencyConverter) (*Money, error) {
+	converted, err := other.ConvertTo(m.Currency, converter)
+	if err != nil {
+		return nil, err
+	}
+	return m.Subtract(converted)
+}
+
+// Age returns how long ago the rate was observed, according to the package clock.
+func (r ExchangeRate) Age() time.Duration {
+	return currentClock().Now().Sub(r.Timestamp.ToTime())
+}
+
+// IsStale reports whether the rate is older than maxAge.
+func (r ExchangeRate) IsStale(maxAge time.Duration) bool {
+	return r.Age() > maxAge
+}
+
+// Equal returns true if both rates quote the same pair at the same value and time.
+func (r *ExchangeRate) Equal(other *ExchangeRate) bool {
+	if r == nil || other == nil {
+		return r == other
+	}
+	return r.Base.Code == other.Base.Code && r.Quote.Code == other.Quote.Code &&
+		r.Timestamp.Epoch == other.Timestamp.Epoch && r.compare(other) == 0
+}
+
+// compare compares the rate values, ignoring currencies and timestamps.
+func (r ExchangeRate) compare(other *ExchangeRate) int {
+	left := new(big.Int).Mul(big.NewInt(r.Rate), bigPow10(other.Scale))
+	right := new(big.Int).Mul(big.NewInt(other.Rate), bigPow10(r.Scale))
+	return left.Cmp(right)
+}
+
+// normalizeRate strips trailing zeros from the decimal places of a rate.
+func normalizeRate(rate int64, scale int) (int64, int) {
+	for scale > 0 && rate%10 == 0 {
+		rate /= 10
+		scale--
+	}
+	return rate, scale
+}
+
+// rateFromRational rounds num/den half-even to a scaled integer with at least
+// RatePrecision significant digits, or MaxRateScale decimal places.
+func rateFromRational(num, den *big.Int) (int64, int, error) {
+	integerDigits := len(num.String()) - len(den.String())
+	scale := min(max(RatePrecision-integerDigits, 0), MaxRateScale)
+
+	rate, err := roundQuotient(new(big.Int).Mul(num, bigPow10(scale)), den, RoundHalfEven)
+	if err != nil {
+		return 0, 0, domainerror.Invalidf("exchange rate overflows the scaled integer range")
+	}
+	if rate == 0 {
+		return 0, 0, domainerror.Invalidf("exchange rate is below the smallest representable rate")
+	}
+	rate, scale = normalizeRate(rate, scale)
+	return rate, scale, nil
+}
+
+// bigPow10 returns 10^n as a big.Int.
+func bigPow10(n int) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(n)), nil)
+}
@@ -0,0 +1,134 @@
+// Package internationalization provides domain types for handling internationalization
+// concerns such as time, currency, timezone, and phone number formatting.
+//
+// This file holds the identity of the value objects. Their structs carry
+// display fields, such as a currency's symbol and name or a timezone's name
+// and offset, that can differ between two values of the same thing, e.g. a
+// Currency decoded from an older payload. Comparing the structs with == or
+// using them as map keys then splits one currency in two. Key returns a
+// comparable key of the normalized identifying fields only, and Hash a
+// 64-bit FNV-1a hash of the same fields that is stable across processes and
+// releases, for dedup sets, sharding and cache keys:
+//
+//	totals := make(map[CurrencyKey]int64)
+//	for _, m := range payments {
+//	    totals[m.Currency.Key()] += m.Amount
+//	}
+//
+// Normalization:
+//
+//   - Currency: the code, trimmed and upper-cased; symbol, name and decimal
+//     places follow from it and are ignored
+//   - Money: the amount and the currency key
+//   - Phone: the digits of the country code and of the number, so "+44" and
+//     "44", or "20 7946 0958" and "2079460958", are the same
+//   - Timezone: the IANA ID; name and offset are ignored, as the offset
+//     depends on the date
+package internationalization
+
+import "strings"
+
+// CurrencyKey is the comparable identity of a Currency, its ISO 4217 code
+type CurrencyKey string
+
+// MoneyKey is the comparable identity of a Money value
+type MoneyKey struct {
+	Amount   int64
+	Currency CurrencyKey
+}
+
+// PhoneKey is the comparable identity of a Phone, its digits only
+type PhoneKey struct {
+	CountryCode string
+	Number      string
+}
+
+// TimezoneKey is the comparable identity of a Timezone, its IANA ID
+type TimezoneKey string
+
+// Key returns the currency's comparable identity
+func (c Currency) Key() CurrencyKey {
+	return CurrencyKey(strings.ToUpper(strings.TrimSpace(c.Code)))
+}
+
+// Hash returns a stable hash of the currency's identity
+func (c Currency) Hash() uint64 {
+	return newHash().string(string(c.Key())).sum()
+}
+
+// Key returns the money value's comparable identity
+func (m Money) Key() MoneyKey {
+	return MoneyKey{Amount: m.Amount, Currency: m.Currency.Key()}
+}
+
+// Hash returns a stable hash of the money value's identity
+func (m Money) Hash() uint64 {
+	return newHash().int64(m.Amount).string(string(m.Currency.Key())).sum()
+}
+
+// Key returns the phone number's comparable identity
+func (p Phone) Key() PhoneKey {
+	return PhoneKey{CountryCode: digitsOnly(p.CountryCode), Number: digitsOnly(p.Number)}
+}
+
+// Hash returns a stable hash of the phone number's identity
+func (p Phone) Hash() uint64 {
+	key := p.Key()
+	return newHash().string(key.CountryCode).string(key.Number).sum()
+}
+
+// Key returns the timezone's comparable identity
+func (tz Timezone) Key() TimezoneKey {
+	return TimezoneKey(strings.TrimSpace(tz.ID))
+}
+
+// Hash returns a stable hash of the timezone's identity
+func (tz Timezone) Hash() uint64 {
+	return newHash().string(string(tz.Key())).sum()
+}
+
+// digitsOnly drops everything but ASCII digits
+func digitsOnly(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= '0' && r <= '9' {
+			return r
+		}
+		return -1
+	}, s)
+}
+
+// FNV-1a, 64-bit
+const (
+	fnvOffset64 = 14695981039346656037
+	fnvPrime64  = 1099511628211
+)
+
+// fnvHash is an allocation-free FNV-1a hash. Each field is followed by a
+// terminator, so ("ab", "c") and ("a", "bc") hash differently.
+type fnvHash uint64
+
+func newHash() fnvHash {
+	return fnvOffset64
+}
+
+func (h fnvHash) byte(b byte) fnvHash {
+	return (h ^ fnvHash(b)) * fnvPrime64
+}
+
+func (h fnvHash) string(s string) fnvHash {
+	for i := 0; i < len(s); i++ {
+		h = h.byte(s[i])
+	}
+	return h.byte(0xff) // Never part of UTF-8 text
+}
+
+func (h fnvHash) int64(n int64) fnvHash {
+	for shift := 0; shift < 64; shift += 8 {
+		h = h.byte(byte(uint64(n) >> shift))
+	}
+	return h
+}
+
+func (h fnvHash) sum() uint64 {
+	return uint64(h)
+}
@@ -0,0 +1,197 @@
+// Package invariants provides reusable property checks for the Money composite
+// type. Each check returns a descriptive error when the property is violated
+// and nil when it holds (or when the inputs are outside the property's domain,
+// such as operations that legitimately fail with overflow).
+//
+// The checks are public so that packages extending Money arithmetic — custom
+// rounding modes, allocation strategies — can run the same properties from
+// their own fuzz and property tests:
+//
+//	func FuzzMyAllocator(f *testing.F) {
+//	    f.Fuzz(func(t *testing.T, amount int64, a, b, c int64) {
+//	        m := &Money{Amount: amount, Currency: usd}
+//	        if err := invariants.CheckAllocationConserves(m, []int64{a, b, c}, myAllocator); err != nil {
+//	            t.Fatal(err)
+//	        }
+//	    })
+//	}
+package invariants
+
+import (
+	"fmt"
+	"math"
+
+	i18n "golang-arch/internal/shared/domain/internationalization"
+)
+
+// Allocator splits money by ratios; (*Money).Allocate is the default implementation
+type Allocator func(m *i18n.Money, ratios []int64) ([]*i18n.Money, error)
+
+// DefaultAllocator adapts (*Money).Allocate to the Allocator signature
+func DefaultAllocator(m *i18n.Money, ratios []int64) ([]*i18n.Money, error) {
+	return m.Allocate(ratios...)
+}
+
+// StrategyAllocator adapts a registered AllocationStrategy to the Allocator signature
+func StrategyAllocator(strategy i18n.AllocationStrategy) Allocator {
+	return func(m *i18n.Money, ratios []int64) ([]*i18n.Money, error) {
+		return m.AllocateWith(strategy, ratios...)
+	}
+}
+
+// maxExactFloat bounds the amounts for which a float64 decimal round trip is
+// exact: the scale-down and scale-up each add up to 2^-53 relative error, so
+// amounts below 2^50 always round back to the same integer.
+const maxExactFloat = 1 << 50
+
+// CheckAddCommutative verifies a+b == b+a
+func CheckAddCommutative(a, b *i18n.Money) error {
+	left, errLeft := a.Add(b)
+	right, errRight := b.Add(a)
+	if (errLeft == nil) != (errRight == nil) {
+		return fmt.Errorf("add commutativity: a+b error %v, b+a error %v", errLeft, errRight)
+	}
+	if errLeft != nil {
+		return nil
+	}
+
+	if !left.Equal(right) {
+		return fmt.Errorf("add commutativity: %d+%d=%d but %d+%d=%d",
+			a.Amount, b.Amount, left.Amount, b.Amount, a.Amount, right.Amount)
+	}
+	return nil
+}
+
+// CheckAddAssociative verifies (a+b)+c == a+(b+c) whenever both sides are computable
+func CheckAddAssociative(a, b, c *i18n.Money) error {
+	ab, err := a.Add(b)
+	if err != nil {
+		return nil
+	}
+	left, err := ab.Add(c)
+	if err != nil {
+		return nil
+	}
+
+	bc, err := b.Add(c)
+	if err != nil {
+		return nil
+	}
+	right, err := a.Add(bc)
+	if err != nil {
+		return nil
+	}
+
+	if !left.Equal(right) {
+		return fmt.Errorf("add associativity: (%d+%d)+%d=%d but %d+(%d+%d)=%d",
+			a.Amount, b.Amount, c.Amount, left.Amount, a.Amount, b.Amount, c.Amount, right.Amount)
+	}
+	return nil
+}
+
+// CheckSubtractInverse verifies (a+b)-b == a
+func CheckSubtractInverse(a, b *i18n.Money) error {
+	sum, err := a.Add(b)
+	if err != nil {
+		return nil
+	}
+
+	back, err := sum.Subtract(b)
+	if err != nil {
+		return fmt.Errorf("subtract inverse: (%d+%d)-%d failed: %w", a.Amount, b.Amount, b.Amount, err)
+	}
+
+	if !back.Equal(a) {
+		return fmt.Errorf("subtract inverse: (%d+%d)-%d=%d, want %d",
+			a.Amount, b.Amount, b.Amount, back.Amount, a.Amount)
+	}
+	return nil
+}
+
+// CheckAddRejectsCurrencyMismatch verifies that adding different currencies fails
+func CheckAddRejectsCurrencyMismatch(a, b *i18n.Money) error {
+	if a.Currency.Code == b.Currency.Code {
+		return nil
+	}
+
+	if _, err := a.Add(b); err == nil {
+		return fmt.Errorf("currency mismatch: adding %s to %s succeeded", b.Currency.Code, a.Currency.Code)
+	}
+	return nil
+}
+
+// CheckAllocationConserves verifies that the allocated parts sum exactly to the
+// original amount, keep its currency, and that no part deviates from its exact
+// proportional share by one minor unit or more.
+func CheckAllocationConserves(m *i18n.Money, ratios []int64, allocate Allocator) error {
+	if allocate == nil {
+		allocate = DefaultAllocator
+	}
+
+	parts, err := allocate(m, ratios)
+	if err != nil {
+		return nil
+	}
+
+	if len(parts) != len(ratios) {
+		return fmt.Errorf("allocation: got %d parts for %d ratios", len(parts), len(ratios))
+	}
+
+	var ratioSum float64
+	var sum int64
+	for _, ratio := range ratios {
+		ratioSum += float64(ratio)
+	}
+
+	for i, part := range parts {
+		if part.Currency.Code != m.Currency.Code {
+			return fmt.Errorf("allocation: part %d has currency %s, want %s", i, part.Currency.Code, m.Currency.Code)
+		}
+		if (part.Amount > 0 && sum > math.MaxInt64-part.Amount) || (part.Amount < 0 && sum < math.MinInt64-part.Amount) {
+			return fmt.Errorf("allocation: parts overflow when summed")
+		}
+		sum += part.Amount
+
+		exact := float64(m.Amount) * float64(ratios[i]) / ratioSum
+		if math.Abs(float64(part.Amount)-exact) > 1+math.Abs(exact)*1e-12 {
+			return fmt.Errorf("allocation: part %d is %d, exact share is %.4f", i, part.Amount, exact)
+		}
+	}
+
+	if sum != m.Amount {
+		return fmt.Errorf("allocation: parts sum to %d, want %d", sum, m.Amount)
+	}
+	return nil
+}
+
+// CheckDecimalRoundTrip verifies NewMoneyFromDecimal(m.ToDecimal()) == m for
+// amounts that float64 can represent exactly
+func CheckDecimalRoundTrip(m *i18n.Money) error {
+	if m.Amount > maxExactFloat || m.Amount < -maxExactFloat {
+		return nil
+	}
+
+	back, err := i18n.NewMoneyFromDecimal(m.ToDecimal(), m.Currency)
+	if err != nil {
+		return fmt.Errorf("decimal round trip: %d %s failed: %w", m.Amount, m.Currency.Code, err)
+	}
+
+	if !back.Equal(m) {
+		return fmt.Errorf("decimal round trip: %d %s came back as %d", m.Amount, m.Currency.Code, back.Amount)
+	}
+	return nil
+}
+
+// CheckMultiplyIdentity verifies m*1 == m and m*0 is zero
+func CheckMultiplyIdentity(m *i18n.Money) error {
+	one, err := m.Multiply(1)
+	if err != nil || !one.Equal(m) {
+		return fmt.Errorf("multiply identity: %d*1 gave %v (err %v)", m.Amount, one, err)
+	}
+
+	zero, err := m.Multiply(0)
+	if err != nil || !zero.IsZero() {
+		return fmt.Errorf("multiply zero: %d*0 gave %v (err %v)", m.Amount, zero, err)
+	}
+	return nil
+}
@@ -0,0 +1,174 @@
+// Package internationalization provides domain types for handling internationalization
+// concerns such as time, currency, timezone, and phone number formatting.
+//
+// This file holds the hand-written JSON encoders for the types that dominate API
+// serialization. Each type exposes AppendJSON(dst []byte) []byte, which appends
+// its encoding without reflection, and MarshalJSON is implemented on top of it.
+// The output is byte-for-byte what encoding/json produced for these types
+// before, including its string escaping and float formatting rules.
+package internationalization
+
+import (
+	"math"
+	"strconv"
+	"unicode/utf8"
+)
+
+// AppendJSON appends the JSON encoding of the currency to dst.
+func (c Currency) AppendJSON(dst []byte) []byte {
+	dst = append(dst, `{"code":`...)
+	dst = appendJSONString(dst, c.Code)
+	dst = append(dst, `,"symbol":`...)
+	dst = appendJSONString(dst, c.Symbol)
+	dst = append(dst, `,"name":`...)
+	dst = appendJSONString(dst, c.Name)
+	dst = append(dst, `,"decimal_places":`...)
+	dst = strconv.AppendInt(dst, int64(c.DecimalPlaces), 10)
+	return append(dst, '}')
+}
+
+// MarshalJSON implements json.Marshaler interface.
+func (c Currency) MarshalJSON() ([]byte, error) {
+	return c.AppendJSON(make([]byte, 0, 96)), nil
+}
+
+// AppendJSON appends the JSON encoding of the timezone to dst.
+func (tz Timezone) AppendJSON(dst []byte) []byte {
+	dst = append(dst, `{"id":`...)
+	dst = appendJSONString(dst, tz.ID)
+	dst = append(dst, `,"name":`...)
+	dst = appendJSONString(dst, tz.Name)
+	dst = append(dst, `,"offset":`...)
+	dst = strconv.AppendInt(dst, int64(tz.Offset), 10)
+	return append(dst, '}')
+}
+
+// MarshalJSON implements json.Marshaler interface.
+func (tz Timezone) MarshalJSON() ([]byte, error) {
+	return tz.AppendJSON(make([]byte, 0, 80)), nil
+}
+
+// AppendJSON appends the epoch seconds of the time to dst.
+func (t Time) AppendJSON(dst []byte) []byte {
+	return strconv.AppendInt(dst, t.Epoch, 10)
+}
+
+// MarshalJSON implements json.Marshaler interface.
+func (t Time) MarshalJSON() ([]byte, error) {
+	return t.AppendJSON(make([]byte, 0, 20)), nil
+}
+
+// AppendJSON appends the JSON encoding of the money value, with both the
+// integer amount and its decimal representation, to dst.
+func (m Money) AppendJSON(dst []byte) []byte {
+	dst = append(dst, `{"amount":`...)
+	dst = strconv.AppendInt(dst, m.Amount, 10)
+	dst = append(dst, `,"decimal":`...)
+	dst = appendJSONFloat(dst, m.ToDecimal())
+	dst = append(dst, `,"currency":`...)
+	dst = m.Currency.AppendJSON(dst)
+	return append(dst, '}')
+}
+
+// MarshalJSON implements json.Marshaler interface.
+func (m Money) MarshalJSON() ([]byte, error) {
+	return m.AppendJSON(make([]byte, 0, 160)), nil
+}
+
+// AppendJSON appends the JSON encoding of the localized datetime to dst.
+func (ldt LocalizedDateTime) AppendJSON(dst []byte) []byte {
+	dst = append(dst, `{"time":`...)
+	dst = ldt.Time.AppendJSON(dst)
+	dst = append(dst, `,"timezone":`...)
+	dst = ldt.Timezone.AppendJSON(dst)
+	return append(dst, '}')
+}
+
+// MarshalJSON implements json.Marshaler interface.
+func (ldt LocalizedDateTime) MarshalJSON() ([]byte, error) {
+	return ldt.AppendJSON(make([]byte, 0, 112)), nil
+}
+
+// appendJSONFloat formats f the way encoding/json does: shortest
+// representation, switching to exponent form for very small or large values.
+// NaN and infinities, which encoding/json rejects, are encoded as 0 because
+// ToDecimal cannot produce them.
+func appendJSONFloat(dst []byte, f float64) []byte {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return append(dst, '0')
+	}
+
+	format := byte('f')
+	if abs := math.Abs(f); abs != 0 && (abs < 1e-6 || abs >= 1e21) {
+		format = 'e'
+	}
+
+	dst = strconv.AppendFloat(dst, f, format, -1, 64)
+	if format == 'e' {
+		// Clean up e-09 to e-9
+		n := len(dst)
+		if n >= 4 && dst[n-4] == 'e' && dst[n-3] == '-' && dst[n-2] == '0' {
+			dst[n-2] = dst[n-1]
+			dst = dst[:n-1]
+		}
+	}
+	return dst
+}
+
+const jsonHex = "0123456789abcdef"
+
+// appendJSONString appends s as a JSON string using encoding/json's default
+// escaping, including HTML-sensitive characters and U+2028/U+2029; invalid
+// UTF-8 is replaced with U+FFFD.
+func appendJSONString(dst []byte, s string) []byte {
+	dst = append(dst, '"')
+	start := 0
+	for i := 0; i < len(s); {
+		if b := s[i]; b < utf8.RuneSelf {
+			if b >= 0x20 && b != '"' && b != '\\' && b != '<' && b != '>' && b != '&' {
+				i++
+				continue
+			}
+
+			dst = append(dst, s[start:i]...)
+			switch b {
+			case '\\', '"':
+				dst = append(dst, '\\', b)
+			case '\b':
+				dst = append(dst, '\\', 'b')
+			case '\f':
+				dst = append(dst, '\\', 'f')
+			case '\n':
+				dst = append(dst, '\\', 'n')
+			case '\r':
+				dst = append(dst, '\\', 'r')
+			case '\t':
+				dst = append(dst, '\\', 't')
+			default:
+				dst = append(dst, '\\', 'u', '0', '0', jsonHex[b>>4], jsonHex[b&0xF])
+			}
+			i++
+			start = i
+			continue
+		}
+
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if r == utf8.RuneError && size == 1 {
+			dst = append(dst, s[start:i]...)
+			dst = append(dst, "\ufffd"...)
+			i += size
+			start = i
+			continue
+		}
+		if r == '\u2028' || r == '\u2029' {
+			dst = append(dst, s[start:i]...)
+			dst = append(dst, '\\', 'u', '2', '0', '2', jsonHex[r&0xF])
+			i += size
+			start = i
+			continue
+		}
+		i += size
+	}
+	dst = append(dst, s[start:]...)
+	return append(dst, '"')
+}
@@ -0,0 +1,267 @@
+// Package internationalization provides domain types and composite types for handling
+// internationalized data such as time, currency, timezone, and phone numbers.
+// This file contains the LedgerEntry composite type for double-entry bookkeeping.
+//
+// LedgerEntry Composite Type:
+//   - Combines a non-negative Money amount with a debit/credit direction
+//   - Records when the entry was posted as a LocalizedDateTime
+//   - Groups entries by transaction; every transaction must balance to zero
+//   - Running balance helpers using the debit-positive convention
+//   - Database storage as primitive values
+//
+// Database Storage: (transaction_id, account, direction, amount int64,
+// currency_code, posted_at int64, posted_at_tz, reference)
+//
+// Usage Examples:
+//
+//	usd, _ := MakeMoney(10050, "USD")
+//	postedAt, _ := MakeLocalizedDateTime(1703520000, "America/New_York")
+//	debit, _ := NewLedgerEntry("tx-1", "cash", Debit, usd, postedAt, "INV-1")
+//	credit, _ := NewLedgerEntry("tx-1", "revenue", Credit, usd, postedAt, "INV-1")
+//	err := ValidateBalanced([]LedgerEntry{*debit, *credit}) // nil
+package internationalization
+
+import (
+	"fmt"
+	"math/big"
+	"sort"
+
+	"golang-arch/internal/shared/domain/domainerror"
+	"golang-arch/internal/shared/domain/validation"
+)
+
+// Direction is the side of the ledger an entry is posted to.
+type Direction string
+
+// Ledger directions
+const (
+	Debit  Direction = "debit"
+	Credit Direction = "credit"
+)
+
+// ParseDirection parses "debit" or "credit".
+func ParseDirection(value string) (Direction, error) {
+	direction := Direction(value)
+	if err := direction.Validate(); err != nil {
+		return "", err
+	}
+	return direction, nil
+}
+
+// Validate ensures the direction is debit or credit.
+func (d Direction) Validate() error {
+	if d != Debit && d != Credit {
+		return domainerror.Invalidf("invalid ledger direction: %q (expected debit or credit)", string(d))
+	}
+	return nil
+}
+
+// Sign returns 1 for debits and -1 for credits.
+func (d Direction) Sign() int64 {
+	if d == Credit {
+		return -1
+	}
+	return 1
+}
+
+// Opposite returns the other side of the ledger, used to build reversals.
+func (d Direction) Opposite() Direction {
+	if d == Credit {
+		return Debit
+	}
+	return Credit
+}
+
+// LedgerEntry represents one side of a double-entry transaction: a
+// non-negative amount posted to an account as a debit or a credit.
+//
+// Entries sharing a TransactionID form one transaction, whose debits and
+// credits must cancel out per currency (see ValidateBalanced).
+//
+// Database Storage: see LedgerEntryPrimitive
+// JSON Format: {"transaction_id": "tx-1", "account": "cash", "direction": "debit",
+// "amount": {...}, "posted_at": {...}, "reference": "INV-1"}
+type LedgerEntry struct {
+	TransactionID string            `json:"transaction_id"`      // Groups the entries of one transaction
+	Account       string            `json:"account"`             // Account the entry is posted to
+	Direction     Direction         `json:"direction"`           // Debit or credit
+	Amount        Money             `json:"amount"`              // Non-negative amount
+	PostedAt      LocalizedDateTime `json:"posted_at"`           // When the entry was posted
+	Reference     string            `json:"reference,omitempty"` // External reference (invoice, payment ID)
+}
+
+// LedgerEntryPrimitive is the flat database representation of a LedgerEntry.
+type LedgerEntryPrimitive struct {
+	TransactionID string `json:"transaction_id"`
+	Account       string `json:"account"`
+	Direction     string `json:"direction"`
+	Amount        int64  `json:"amount"`
+	CurrencyCode  string `json:"currency_code"`
+	PostedAt      int64  `json:"posted_at"`
+	PostedAtTZ    string `json:"posted_at_tz"`
+	Reference     string `json:"reference"`
+}
+
+// NewLedgerEntry creates a new LedgerEntry composite type.
+// Returns an error if any component is invalid or the amount is negative.
+func NewLedgerEntry(transactionID, account string, direction Direction, amount Money, postedAt LocalizedDateTime, reference string) (*LedgerEntry, error) {
+	entry := &LedgerEntry{
+		TransactionID: transactionID,
+		Account:       account,
+		Direction:     direction,
+		Amount:        amount,
+		PostedAt:      postedAt,
+		Reference:     reference,
+	}
+	if err := entry.Validate(); err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+// NewLedgerEntryFromPrimitive creates a LedgerEntry from primitive database values.
+func NewLedgerEntryFromPrimitive(p LedgerEntryPrimitive) (*LedgerEntry, error) {
+	direction, err := ParseDirection(p.Direction)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create ledger entry from primitive: %w", err)
+	}
+
+	amount, err := NewMoneyFromPrimitive(p.Amount, p.CurrencyCode)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create ledger entry from primitive: %w", err)
+	}
+
+	postedAt, err := NewLocalizedDateTimeFromPrimitive(p.PostedAt, p.PostedAtTZ)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create ledger entry from primitive: %w", err)
+	}
+
+	return NewLedgerEntry(p.TransactionID, p.Account, direction, *amount, *postedAt, p.Reference)
+}
+
+// ToPrimitive converts the LedgerEntry to primitive database values.
+func (e LedgerEntry) ToPrimitive() LedgerEntryPrimitive {
+	amount, currencyCode := e.Amount.ToPrimitive()
+	postedAt, postedAtTZ := e.PostedAt.ToPrimitive()
+	return LedgerEntryPrimitive{
+		TransactionID: e.TransactionID,
+		Account:       e.Account,
+		Direction:     string(e.Direction),
+		Amount:        amount,
+		CurrencyCode:  currencyCode,
+		PostedAt:      postedAt,
+		PostedAtTZ:    postedAtTZ,
+		Reference:     e.Reference,
+	}
+}
+
+// Validate ensures the LedgerEntry composite type is valid.
+func (e LedgerEntry) Validate() error {
+	var errs validation.ValidationErrors
+	if e.TransactionID == "" {
+		errs.Add("transaction_id", validation.CodeRequired, "transaction ID cannot be empty", nil)
+	}
+	if e.Account == "" {
+		errs.Add("account", validation.CodeRequired, "account cannot be empty", nil)
+	}
+	errs.Merge("direction", "", e.Direction.Validate())
+	errs.Merge("amount", "invalid amount in ledger entry", e.Amount.Validate())
+	if e.Amount.IsNegative() {
+		errs.Add("amount", validation.CodeOutOfRange, "ledger amounts cannot be negative; use the direction instead",
+			map[string]any{"amount": e.Amount.Amount})
+	}
+	errs.Merge("posted_at", "invalid posted_at in ledger entry", e.PostedAt.Validate())
+	return errs.Err()
+}
+
+// SignedAmount returns the amount as a signed number of minor units:
+// positive for debits and negative for credits.
+func (e LedgerEntry) SignedAmount() int64 {
+	return e.Direction.Sign() * e.Amount.Amount
+}
+
+// Reverse returns an entry that cancels this one: same amount and account,
+// opposite direction, posted under the given transaction and time.
+func (e LedgerEntry) Reverse(transactionID string, postedAt LocalizedDateTime) (*LedgerEntry, error) {
+	return NewLedgerEntry(transactionID, e.Account, e.Direction.Opposite(), e.Amount, postedAt, e.Reference)
+}
+
+// Balance returns the net debit-positive balance of the entries, which must
+// all be in currency. An empty ledger has a zero balance.
+func Balance(currency Currency, entries ...LedgerEntry) (*Money, error) {
+	balances, err := RunningBalances(currency, entries...)
+	if err != nil {
+		return nil, err
+	}
+	if len(balances) == 0 {
+		return NewMoneyFromInteger(0, currency)
+	}
+	return &balances[len(balances)-1], nil
+}
+
+// RunningBalances returns the debit-positive balance after each entry, in the
+// order given. Every entry must be in currency; sort entries by PostedAt
+// first for a chronological statement.
+func RunningBalances(currency Currency, entries ...LedgerEntry) ([]Money, error) {
+	balances := make([]Money, len(entries))
+	running := Money{Currency: currency}
+	for i, entry := range entries {
+		if entry.Amount.Currency.Code != currency.Code {
+			return nil, domainerror.Invalidf("ledger entry %d is in %s, expected %s",
+				i, entry.Amount.Currency.Code, currency.Code)
+		}
+
+		next, err := running.Add(&Money{Amount: entry.SignedAmount(), Currency: currency})
+		if err != nil {
+			return nil, fmt.Errorf("ledger entry %d: %w", i, err)
+		}
+		running = *next
+		balances[i] = running
+	}
+	return balances, nil
+}
+
+// ValidateBalanced verifies the double-entry invariant: within every
+// transaction, debits equal credits for each currency. Unbalanced
+// transactions are reported as field errors keyed by transaction ID.
+func ValidateBalanced(entries []LedgerEntry) error {
+	type key struct {
+		transactionID string
+		currency      string
+	}
+
+	totals := make(map[key]*big.Int)
+	var order []key
+	for _, entry := range entries {
+		k := key{entry.TransactionID, entry.Amount.Currency.Code}
+		total, ok := totals[k]
+		if !ok {
+			total = new(big.Int)
+			totals[k] = total
+			order = append(order, k)
+		}
+		if entry.Direction == Credit {
+			total.Sub(total, big.NewInt(entry.Amount.Amount))
+		} else {
+			total.Add(total, big.NewInt(entry.Amount.Amount))
+		}
+	}
+
+	sort.SliceStable(order, func(i, j int) bool {
+		if order[i].transactionID != order[j].transactionID {
+			return order[i].transactionID < order[j].transactionID
+		}
+		return order[i].currency < order[j].currency
+	})
+
+	var errs validation.ValidationErrors
+	for _, k := range order {
+		if total := totals[k]; total.Sign() != 0 {
+			errs.Add(fmt.Sprintf("transaction[%s]", k.transactionID), validation.CodeInvalid,
+				fmt.Sprintf("transaction %s does not balance in %s: debits exceed credits by %s minor units",
+					k.transactionID, k.currency, total),
+				map[string]any{"currency": k.currency, "difference": total.String()})
+		}
+	}
+	return errs.Err()
+}
@@ -0,0 +1,190 @@
+// Package internationalization provides domain types and composite types for handling
+// internationalized data such as time, currency, timezone, and phone numbers.
+// This file contains the Locale type for BCP 47 language tags.
+//
+// Locale Type:
+//   - Language, optional script and optional region, e.g. "en-US", "zh-Hant-TW"
+//   - Canonical casing ("EN_us" parses as "en-US")
+//   - Region-driven conventions such as the measurement system
+//
+// Database Storage: Stored as string (canonical BCP 47 tag)
+//
+// Usage Examples:
+//
+//	locale, err := ParseLocale("pt_br") // "pt-BR"
+//	locale.Language()                   // "pt"
+//	locale.Region()                     // "BR"
+package internationalization
+
+import (
+	"strings"
+
+	"golang-arch/internal/shared/domain/domainerror"
+)
+
+// Locale is a canonical BCP 47 language tag limited to language, script and
+// region subtags.
+type Locale string
+
+// ParseLocale parses a language tag, accepting "_" as a separator and any
+// casing, and returns it in canonical form.
+func ParseLocale(tag string) (Locale, error) {
+	subtags := strings.Split(strings.ReplaceAll(strings.TrimSpace(tag), "_", "-"), "-")
+	if !isAlpha(subtags[0]) || len(subtags[0]) < 2 || len(subtags[0]) > 3 {
+		return "", domainerror.Invalidf("invalid locale: %q", tag)
+	}
+	subtags[0] = strings.ToLower(subtags[0])
+
+	// Optional script (4 letters) followed by an optional region (2 letters or 3 digits)
+	rest := subtags[1:]
+	if len(rest) > 0 && len(rest[0]) == 4 && isAlpha(rest[0]) {
+		rest[0] = strings.ToUpper(rest[0][:1]) + strings.ToLower(rest[0][1:])
+		rest = rest[1:]
+	}
+	if len(rest) > 0 && ((len(rest[0]) == 2 && isAlpha(rest[0])) || (len(rest[0]) == 3 && isDigits(rest[0]))) {
+		rest[0] = strings.ToUpper(rest[0])
+		rest = rest[1:]
+	}
+	if len(rest) > 0 {
+		return "", domainerror.Invalidf("invalid locale: %q", tag)
+	}
+	return Locale(strings.Join(subtags, "-")), nil
+}
+
+// MustParseLocale is like ParseLocale but panics on error.
+func MustParseLocale(tag string) Locale {
+	locale, err := ParseLocale(tag)
+	if err != nil {
+		panic(err)
+	}
+	return locale
+}
+
+// Validate ensures the locale is a canonical language tag.
+func (l Locale) Validate() error {
+	canonical, err := ParseLocale(string(l))
+	if err != nil {
+		return err
+	}
+	if canonical != l {
+		return domainerror.Invalidf("locale %q is not canonical, expected %q", string(l), string(canonical))
+	}
+	return nil
+}
+
+// Language returns the language subtag, e.g. "en".
+func (l Locale) Language() Language {
+	language, _, _ := strings.Cut(string(l), "-")
+	return Language(language)
+}
+
+// Script returns the script subtag, e.g. "Hant", or "" when the locale has none.
+func (l Locale) Script() string {
+	for _, subtag := range strings.Split(string(l), "-")[1:] {
+		if len(subtag) == 4 {
+			return subtag
+		}
+	}
+	return ""
+}
+
+// Region returns the region subtag, e.g. "US", or "" when the locale has none.
+func (l Locale) Region() string {
+	subtags := strings.Split(string(l), "-")
+	last := subtags[len(subtags)-1]
+	if len(subtags) > 1 && (len(last) == 2 || len(last) == 3) {
+		return last
+	}
+	return ""
+}
+
+// WithRegion returns the locale with its region replaced by region.
+func (l Locale) WithRegion(region string) (Locale, error) {
+	tag := string(l)
+	if current := l.Region(); current != "" {
+		tag = strings.TrimSuffix(tag, "-"+current)
+	}
+	return ParseLocale(tag + "-" + region)
+}
+
+// MeasurementSystem returns the measurement system customary in the
+// locale's region; locales without a region use the metric system.
+func (l Locale) MeasurementSystem() MeasurementSystem {
+	switch l.Region() {
+	case "US", "LR", "MM":
+		return MeasurementUS
+	case "GB":
+		return MeasurementUK
+	default:
+		return MeasurementMetric
+	}
+}
+
+// String returns the language tag.
+func (l Locale) String() string {
+	return string(l)
+}
+
+// Language is a lower-case ISO 639 language code, the first subtag of a Locale.
+type Language string
+
+// String returns the language code.
+func (l Language) String() string {
+	return string(l)
+}
+
+// MeasurementSystem is the system of units a user prefers.
+type MeasurementSystem string
+
+// Measurement systems, following CLDR
+const (
+	MeasurementMetric MeasurementSystem = "metric"
+	MeasurementUS     MeasurementSystem = "us" // US customary units
+	MeasurementUK     MeasurementSystem = "uk" // Metric with miles, pints and stones
+)
+
+// ParseMeasurementSystem parses "metric", "us" or "uk".
+func ParseMeasurementSystem(value string) (MeasurementSystem, error) {
+	system := MeasurementSystem(strings.ToLower(strings.TrimSpace(value)))
+	if err := system.Validate(); err != nil {
+		return "", err
+	}
+	return system, nil
+}
+
+// Validate ensures the measurement system is known.
+func (m MeasurementSystem) Validate() error {
+	switch m {
+	case MeasurementMetric, MeasurementUS, MeasurementUK:
+		return nil
+	default:
+		return domainerror.Invalidf("invalid measurement system: %q (expected metric, us or uk)", string(m))
+	}
+}
+
+// isAlpha reports whether s is non-empty and consists of ASCII letters.
+func isAlpha(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		c := s[i] | 0x20
+		if c < 'a' || c > 'z' {
+			return false
+		}
+	}
+	return true
+}
+
+// isDigits reports whether s is non-empty and consists of ASCII digits.
+func isDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
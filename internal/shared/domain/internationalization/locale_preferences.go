@@ -0,0 +1,374 @@
+// Package internationalization provides domain types and composite types for handling
+// internationalized data such as time, currency, timezone, and phone numbers.
+// This file contains the LocalePreferences composite type for user profiles.
+//
+// LocalePreferences Composite Type:
+//   - Locale, Timezone and Currency used to format values for a user
+//   - Measurement system and first day of week for calendars and units
+//   - Database storage as primitive values
+//   - Resolution across sources: stored user preferences, request
+//     negotiation (Accept-Language, GeoIP) and application defaults
+//
+// Database Storage: (locale string, timezone_id string, currency_code string,
+// measurement_system string, first_day_of_week int)
+// JSON Format: {"locale": "en-US", "timezone": {...}, "currency": {...}, "measurement_system": "us", "first_day_of_week": 0}
+//
+// Usage Examples:
+//
+//	stored, _ := NewLocalePreferencesFromPrimitive("de-DE", "Europe/Berlin", "EUR", "metric", 1)
+//	prefs := ResolveLocalePreferences(defaults, stored.Partial(), requestPrefs)
+//	prefs.Timezone // user's stored zone, whatever the request says
+//
+//	defaults, _ := NewLocaleDefaults("en-US", "UTC", "USD", []string{"en", "de"})
+//	prefs = defaults.Resolve(stored.Partial(), requestPrefs) // locale narrowed to en or de
+//	prefs = defaults.ForTenant("tenant-42").Resolve(requestPrefs) // the tenant's defaults first
+package internationalization
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"time"
+
+	"golang-arch/internal/shared/domain/validation"
+)
+
+// LocalePreferences holds how values should be presented to one user.
+//
+// Features:
+//   - Complete and validated: every field is set
+//   - Built from partial sources with ResolveLocalePreferences
+//
+// Example:
+//
+//	prefs, err := NewLocalePreferences("en-GB", *london, *gbp, MeasurementUK, time.Monday)
+type LocalePreferences struct {
+	Locale            Locale            `json:"locale"`             // Language tag used for messages and formatting
+	Timezone          Timezone          `json:"timezone"`           // Zone dates and times are shown in
+	Currency          Currency          `json:"currency"`           // Preferred display currency
+	MeasurementSystem MeasurementSystem `json:"measurement_system"` // metric, us or uk
+	FirstDayOfWeek    time.Weekday      `json:"first_day_of_week"`  // 0 (Sunday) to 6 (Saturday)
+}
+
+// NewLocalePreferences creates a new LocalePreferences composite type.
+// Returns an error if any component is invalid.
+func NewLocalePreferences(locale Locale, timezone Timezone, currency Currency, measurement MeasurementSystem, firstDayOfWeek time.Weekday) (*LocalePreferences, error) {
+	p := &LocalePreferences{
+		Locale:            locale,
+		Timezone:          timezone,
+		Currency:          currency,
+		MeasurementSystem: measurement,
+		FirstDayOfWeek:    firstDayOfWeek,
+	}
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// NewLocalePreferencesFromPrimitive creates LocalePreferences from primitive database values.
+func NewLocalePreferencesFromPrimitive(locale, timezoneID, currencyCode, measurement string, firstDayOfWeek int) (*LocalePreferences, error) {
+	var errs validation.ValidationErrors
+
+	parsedLocale, err := ParseLocale(locale)
+	errs.Merge("locale", "", err)
+	timezone, err := NewTimezoneFromID(timezoneID)
+	errs.Merge("timezone", "", err)
+	currency, err := NewCurrencyFromCode(currencyCode)
+	errs.Merge("currency", "", err)
+	system, err := ParseMeasurementSystem(measurement)
+	errs.Merge("measurement_system", "", err)
+	if err := errs.Err(); err != nil {
+		return nil, fmt.Errorf("failed to create locale preferences from primitive: %w", err)
+	}
+
+	return NewLocalePreferences(parsedLocale, *timezone, *currency, system, time.Weekday(firstDayOfWeek))
+}
+
+// ToPrimitive converts the LocalePreferences to primitive database values.
+func (p LocalePreferences) ToPrimitive() (string, string, string, string, int) {
+	return string(p.Locale), p.Timezone.ToPrimitive(), p.Currency.Code, string(p.MeasurementSystem), int(p.FirstDayOfWeek)
+}
+
+// Validate ensures the LocalePreferences composite type is valid.
+func (p LocalePreferences) Validate() error {
+	var errs validation.ValidationErrors
+	errs.Merge("locale", "", p.Locale.Validate())
+	errs.Merge("timezone", "invalid timezone in locale preferences", p.Timezone.Validate())
+	errs.Merge("currency", "invalid currency in locale preferences", p.Currency.Validate())
+	errs.Merge("measurement_system", "", p.MeasurementSystem.Validate())
+	if p.FirstDayOfWeek < time.Sunday || p.FirstDayOfWeek > time.Saturday {
+		errs.Add("first_day_of_week", validation.CodeOutOfRange, "first day of week must be between 0 (Sunday) and 6 (Saturday)",
+			map[string]any{"min": 0, "max": 6})
+	}
+	return errs.Err()
+}
+
+// Partial returns the preferences as a fully set PartialLocalePreferences,
+// for use as a source in ResolveLocalePreferences.
+func (p LocalePreferences) Partial() PartialLocalePreferences {
+	firstDay := p.FirstDayOfWeek
+	return PartialLocalePreferences{
+		Locale:            string(p.Locale),
+		Timezone:          p.Timezone.ID,
+		Currency:          p.Currency.Code,
+		MeasurementSystem: string(p.MeasurementSystem),
+		FirstDayOfWeek:    &firstDay,
+	}
+}
+
+// UnmarshalJSON implements json.Unmarshaler interface.
+func (p *LocalePreferences) UnmarshalJSON(data []byte) error {
+	type plain LocalePreferences
+	var decoded plain
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+
+	prefs := LocalePreferences(decoded)
+	if err := prefs.Validate(); err != nil {
+		return err
+	}
+	*p = prefs
+	return nil
+}
+
+// PartialLocalePreferences holds the preferences one source knows about, such
+// as a user's saved profile or what a request negotiated. Empty fields are
+// unset.
+type PartialLocalePreferences struct {
+	Locale            string        `json:"locale,omitempty"`
+	Timezone          string        `json:"timezone,omitempty"`
+	Currency          string        `json:"currency,omitempty"`
+	MeasurementSystem string        `json:"measurement_system,omitempty"`
+	FirstDayOfWeek    *time.Weekday `json:"first_day_of_week,omitempty"`
+}
+
+// ResolveLocalePreferences merges sources field by field, in order of
+// precedence: the first source with a valid value for a field wins, and
+// defaults fill the rest. Invalid values, e.g. from untrusted request
+// headers, are skipped. When no source sets the currency, the measurement
+// system or the first day of week, they follow the region of the resolved
+// locale, if a source set one.
+func ResolveLocalePreferences(defaults LocalePreferences, sources ...PartialLocalePreferences) LocalePreferences {
+	resolved := defaults
+	var localeSet, timezoneSet, currencySet, measurementSet, firstDaySet bool
+
+	for _, source := range sources {
+		if !localeSet && source.Locale != "" {
+			if locale, err := ParseLocale(source.Locale); err == nil {
+				resolved.Locale, localeSet = locale, true
+			}
+		}
+		if !timezoneSet && source.Timezone != "" {
+			if timezone, err := NewTimezoneFromID(source.Timezone); err == nil {
+				resolved.Timezone, timezoneSet = *timezone, true
+			}
+		}
+		if !currencySet && source.Currency != "" {
+			if currency, err := NewCurrencyFromCode(source.Currency); err == nil {
+				resolved.Currency, currencySet = *currency, true
+			}
+		}
+		if !measurementSet && source.MeasurementSystem != "" {
+			if system, err := ParseMeasurementSystem(source.MeasurementSystem); err == nil {
+				resolved.MeasurementSystem, measurementSet = system, true
+			}
+		}
+		if !firstDaySet && source.FirstDayOfWeek != nil {
+			if day := *source.FirstDayOfWeek; day >= time.Sunday && day <= time.Saturday {
+				resolved.FirstDayOfWeek, firstDaySet = day, true
+			}
+		}
+	}
+
+	if localeSet && resolved.Locale.Region() != "" {
+		if !currencySet {
+			if currency, ok := CurrencyForLocale(resolved.Locale); ok {
+				resolved.Currency = currency
+			}
+		}
+		if !measurementSet {
+			resolved.MeasurementSystem = resolved.Locale.MeasurementSystem()
+		}
+		if !firstDaySet {
+			resolved.FirstDayOfWeek = resolved.Locale.Week().FirstDay
+		}
+	}
+	return resolved
+}
+
+// Built-in application defaults, used for what configuration leaves empty
+const (
+	DefaultLocale   = "en-US"
+	DefaultTimezone = "UTC"
+	DefaultCurrency = "USD"
+)
+
+// LocaleDefaults are the application's fallback preferences, for readers
+// nothing is known about, and the locales it is translated into. Defaults
+// by locale fill in the timezone and currency of readers whose locale is
+// known, and defaults by tenant take precedence over both.
+//
+// Example:
+//
+//	defaults, err := NewLocaleDefaults("de-DE", "Europe/Berlin", "EUR", []string{"de", "en"})
+//	defaults.Negotiate("en-GB") // "en"
+//	defaults.Negotiate("fr-FR") // "de-DE"
+//
+//	err = defaults.AddLocale("de-CH", PartialLocalePreferences{Timezone: "Europe/Zurich", Currency: "CHF"})
+//	defaults.Resolve(PartialLocalePreferences{Locale: "de-CH"}).Currency // CHF
+type LocaleDefaults struct {
+	Preferences LocalePreferences                   // Measurement system and first day of week follow the locale's region
+	Supported   []Locale                            // Empty supports every locale
+	Locales     map[Locale]PartialLocalePreferences // Timezone and currency by locale tag or language
+	Tenants     map[string]PartialLocalePreferences // Locale, timezone and currency by tenant
+
+	tenant PartialLocalePreferences // Defaults of the tenant chosen with ForTenant
+}
+
+// NewLocaleDefaults creates LocaleDefaults from configuration values. Empty
+// values take DefaultLocale, DefaultTimezone and DefaultCurrency.
+func NewLocaleDefaults(locale, timezoneID, currencyCode string, supported []string) (*LocaleDefaults, error) {
+	var errs validation.ValidationErrors
+
+	parsedLocale, err := ParseLocale(cmp.Or(locale, DefaultLocale))
+	errs.Merge("default_locale", "", err)
+	timezone, err := NewTimezoneFromID(cmp.Or(timezoneID, DefaultTimezone))
+	errs.Merge("default_timezone", "", err)
+	currency, err := NewCurrencyFromCode(cmp.Or(currencyCode, DefaultCurrency))
+	errs.Merge("default_currency", "", err)
+	locales := make([]Locale, len(supported))
+	for i, tag := range supported {
+		locales[i], err = ParseLocale(tag)
+		errs.Merge(fmt.Sprintf("supported_locales[%d]", i), "", err)
+	}
+	if err := errs.Err(); err != nil {
+		return nil, fmt.Errorf("invalid locale defaults: %w", err)
+	}
+
+	prefs, err := NewLocalePreferences(parsedLocale, *timezone, *currency,
+		parsedLocale.MeasurementSystem(), parsedLocale.Week().FirstDay)
+	if err != nil {
+		return nil, err
+	}
+	return &LocaleDefaults{Preferences: *prefs, Supported: locales}, nil
+}
+
+// BuiltInLocaleDefaults are DefaultLocale, DefaultTimezone and
+// DefaultCurrency, supporting every locale; the defaults of services that
+// are given none
+func BuiltInLocaleDefaults() LocaleDefaults {
+	defaults, err := NewLocaleDefaults("", "", "", nil)
+	if err != nil {
+		panic(err)
+	}
+	return *defaults
+}
+
+// Negotiate returns the supported locale closest to locale: the same tag,
+// else the first with the same language, else the default locale. Without
+// supported locales every locale is returned as is.
+func (d LocaleDefaults) Negotiate(locale Locale) Locale {
+	if len(d.Supported) == 0 {
+		return locale
+	}
+	for _, supported := range d.Supported {
+		if supported == locale {
+			return supported
+		}
+	}
+	for _, supported := range d.Supported {
+		if supported.Language() == locale.Language() {
+			return supported
+		}
+	}
+	return d.Preferences.Locale
+}
+
+// AddLocale sets the timezone and currency of readers whose locale is tag,
+// or whose language is tag when it has no region, and who set neither
+func (d *LocaleDefaults) AddLocale(tag string, prefs PartialLocalePreferences) error {
+	locale, err := ParseLocale(tag)
+	if err != nil {
+		return fmt.Errorf("invalid locale defaults %q: %w", tag, err)
+	}
+	prefs.Locale = ""
+	if err := validatePartial(prefs); err != nil {
+		return fmt.Errorf("invalid locale defaults %q: %w", tag, err)
+	}
+	if d.Locales == nil {
+		d.Locales = make(map[Locale]PartialLocalePreferences)
+	}
+	d.Locales[locale] = prefs
+	return nil
+}
+
+// AddTenant sets the defaults of a tenant, ahead of the defaults by locale
+// and the application's
+func (d *LocaleDefaults) AddTenant(id string, prefs PartialLocalePreferences) error {
+	if id == "" {
+		return fmt.Errorf("invalid tenant defaults: tenant id is required")
+	}
+	if err := validatePartial(prefs); err != nil {
+		return fmt.Errorf("invalid defaults of tenant %q: %w", id, err)
+	}
+	if d.Tenants == nil {
+		d.Tenants = make(map[string]PartialLocalePreferences)
+	}
+	d.Tenants[id] = prefs
+	return nil
+}
+
+// validatePartial checks the values a partial source sets
+func validatePartial(prefs PartialLocalePreferences) error {
+	var errs validation.ValidationErrors
+	if prefs.Locale != "" {
+		_, err := ParseLocale(prefs.Locale)
+		errs.Merge("locale", "", err)
+	}
+	if prefs.Timezone != "" {
+		_, err := NewTimezoneFromID(prefs.Timezone)
+		errs.Merge("timezone", "", err)
+	}
+	if prefs.Currency != "" {
+		_, err := NewCurrencyFromCode(prefs.Currency)
+		errs.Merge("currency", "", err)
+	}
+	if prefs.MeasurementSystem != "" {
+		_, err := ParseMeasurementSystem(prefs.MeasurementSystem)
+		errs.Merge("measurement_system", "", err)
+	}
+	return errs.Err()
+}
+
+// ForLocale returns the defaults added for locale: those of its tag, else
+// those of its language, else none
+func (d LocaleDefaults) ForLocale(locale Locale) PartialLocalePreferences {
+	if prefs, ok := d.Locales[locale]; ok {
+		return prefs
+	}
+	return d.Locales[Locale(locale.Language())]
+}
+
+// ForTenant returns the defaults of readers of a tenant; unknown tenants get
+// the application's
+func (d LocaleDefaults) ForTenant(id string) LocaleDefaults {
+	d.tenant = d.Tenants[id]
+	return d
+}
+
+// Resolve is ResolveLocalePreferences with d.Preferences as the defaults:
+// sources come first, then the tenant's defaults, then the defaults of the
+// locale they resolve to. The locale is narrowed to a supported one.
+func (d LocaleDefaults) Resolve(sources ...PartialLocalePreferences) LocalePreferences {
+	sources = append(slices.Clone(sources), d.tenant)
+	if len(d.Locales) > 0 {
+		locale := ResolveLocalePreferences(d.Preferences, sources...).Locale
+		sources = append(sources, d.ForLocale(locale))
+	}
+	resolved := ResolveLocalePreferences(d.Preferences, sources...)
+	resolved.Locale = d.Negotiate(resolved.Locale)
+	return resolved
+}
@@ -22,6 +22,8 @@ package internationalization
 import (
 	"fmt"
 	"time"
+
+	"golang-arch/internal/shared/domain/validation"
 )
 
 // LocalizedDateTime represents a date and time with an associated timezone.
@@ -86,43 +88,37 @@ func NewLocalizedDateTimeFromPrimitive(epoch int64, timezoneID string) (*Localiz
 
 // ToPrimitive converts the LocalizedDateTime to primitive database values.
 // Returns the time as epoch int64 and timezone as string ID.
-func (ldt *LocalizedDateTime) ToPrimitive() (int64, string) {
+func (ldt LocalizedDateTime) ToPrimitive() (int64, string) {
 	return ldt.Time.ToPrimitive(), ldt.Timezone.ToPrimitive()
 }
 
 // Validate ensures the LocalizedDateTime composite type is valid.
-func (ldt *LocalizedDateTime) Validate() error {
-	if err := ldt.Time.Validate(); err != nil {
-		return fmt.Errorf("invalid time in localized datetime: %w", err)
-	}
-
-	if err := ldt.Timezone.Validate(); err != nil {
-		return fmt.Errorf("invalid timezone in localized datetime: %w", err)
-	}
-
-	return nil
+func (ldt LocalizedDateTime) Validate() error {
+	var errs validation.ValidationErrors
+	errs.Merge("time", "invalid time in localized datetime", ldt.Time.Validate())
+	errs.Merge("timezone", "invalid timezone in localized datetime", ldt.Timezone.Validate())
+	return errs.Err()
 }
 
 // Format returns a formatted string representation of the localized datetime.
 // Uses the provided layout string and the associated timezone.
-func (ldt *LocalizedDateTime) Format(layout string) string {
+func (ldt LocalizedDateTime) Format(layout string) string {
 	return ldt.Time.Format(layout, &ldt.Timezone)
 }
 
 // ToTime returns the time.Time representation in the associated timezone.
-func (ldt *LocalizedDateTime) ToTime() time.Time {
-	// Create a fixed timezone using the offset
-	loc := time.FixedZone(ldt.Timezone.ID, ldt.Timezone.Offset*60) // Convert minutes to seconds
-	return ldt.Time.ToTime().In(loc)
+func (ldt LocalizedDateTime) ToTime() time.Time {
+	// Use a fixed timezone built from the offset
+	return ldt.Time.ToTime().In(fixedZone(ldt.Timezone.ID, ldt.Timezone.Offset))
 }
 
 // ToUTC returns the time.Time representation in UTC.
-func (ldt *LocalizedDateTime) ToUTC() time.Time {
+func (ldt LocalizedDateTime) ToUTC() time.Time {
 	return ldt.Time.ToTime().UTC()
 }
 
 // Add adds a duration to the localized datetime and returns a new LocalizedDateTime.
-func (ldt *LocalizedDateTime) Add(duration time.Duration) *LocalizedDateTime {
+func (ldt LocalizedDateTime) Add(duration time.Duration) *LocalizedDateTime {
 	newTime := ldt.Time.ToTime().Add(duration)
 	newTimeValue := NewTimeFromTime(newTime)
 
@@ -133,17 +129,17 @@ func (ldt *LocalizedDateTime) Add(duration time.Duration) *LocalizedDateTime {
 }
 
 // Subtract subtracts a duration from the localized datetime and returns a new LocalizedDateTime.
-func (ldt *LocalizedDateTime) Subtract(duration time.Duration) *LocalizedDateTime {
+func (ldt LocalizedDateTime) Subtract(duration time.Duration) *LocalizedDateTime {
 	return ldt.Add(-duration)
 }
 
 // IsBefore returns true if this localized datetime is before the other.
-func (ldt *LocalizedDateTime) IsBefore(other *LocalizedDateTime) bool {
+func (ldt LocalizedDateTime) IsBefore(other *LocalizedDateTime) bool {
 	return ldt.Time.ToTime().Before(other.Time.ToTime())
 }
 
 // IsAfter returns true if this localized datetime is after the other.
-func (ldt *LocalizedDateTime) IsAfter(other *LocalizedDateTime) bool {
+func (ldt LocalizedDateTime) IsAfter(other *LocalizedDateTime) bool {
 	return ldt.Time.ToTime().After(other.Time.ToTime())
 }
 
@@ -156,6 +152,6 @@ func (ldt *LocalizedDateTime) IsEqual(other *LocalizedDateTime) bool {
 }
 
 // Duration returns the duration between this localized datetime and another.
-func (ldt *LocalizedDateTime) Duration(other *LocalizedDateTime) time.Duration {
+func (ldt LocalizedDateTime) Duration(other *LocalizedDateTime) time.Duration {
 	return ldt.Time.ToTime().Sub(other.Time.ToTime())
 }
@@ -23,6 +23,9 @@ package internationalization
 
 import (
 	"fmt"
+
+	"golang-arch/internal/shared/domain/domainerror"
+	"golang-arch/internal/shared/domain/validation"
 )
 
 // LocalizedPhone represents a phone number with country information.
@@ -65,7 +68,7 @@ func NewLocalizedPhone(phone Phone, country string, region string, timezone Time
 	}
 
 	if country == "" {
-		return nil, fmt.Errorf("country cannot be empty")
+		return nil, domainerror.Invalidf("country cannot be empty")
 	}
 
 	return &LocalizedPhone{
@@ -92,34 +95,30 @@ func NewLocalizedPhoneFromPrimitive(phoneStr, country, region, timezoneID string
 }
 
 // ToPrimitive converts the LocalizedPhone to primitive database values.
-func (lp *LocalizedPhone) ToPrimitive() (string, string, string, string) {
+func (lp LocalizedPhone) ToPrimitive() (string, string, string, string) {
 	return lp.Phone.ToPrimitive(), lp.Country, lp.Region, lp.Timezone.ToPrimitive()
 }
 
 // Validate ensures the LocalizedPhone composite type is valid.
-func (lp *LocalizedPhone) Validate() error {
-	if err := lp.Phone.Validate(); err != nil {
-		return fmt.Errorf("invalid phone in localized phone: %w", err)
-	}
-
-	if err := lp.Timezone.Validate(); err != nil {
-		return fmt.Errorf("invalid timezone in localized phone: %w", err)
-	}
+func (lp LocalizedPhone) Validate() error {
+	var errs validation.ValidationErrors
+	errs.Merge("phone", "invalid phone in localized phone", lp.Phone.Validate())
+	errs.Merge("timezone", "invalid timezone in localized phone", lp.Timezone.Validate())
 
 	if lp.Country == "" {
-		return fmt.Errorf("country cannot be empty")
+		errs.Add("country", validation.CodeRequired, "country cannot be empty", nil)
 	}
 
-	return nil
+	return errs.Err()
 }
 
 // Format returns a formatted string representation of the localized phone.
-func (lp *LocalizedPhone) Format() string {
+func (lp LocalizedPhone) Format() string {
 	return lp.Phone.Format()
 }
 
 // GetFullLocation returns the full location string (Country, Region).
-func (lp *LocalizedPhone) GetFullLocation() string {
+func (lp LocalizedPhone) GetFullLocation() string {
 	if lp.Region != "" {
 		return fmt.Sprintf("%s, %s", lp.Region, lp.Country)
 	}
@@ -0,0 +1,190 @@
+// Package internationalization provides domain types and composite types for handling
+// internationalized data such as time, currency, timezone, and phone numbers.
+// This file contains the LocalizedString composite type for translated text.
+//
+// LocalizedString Composite Type:
+//   - One text per locale, e.g. a product name in English and German
+//   - Lookup by locale preference with language fallback (de-AT -> de -> de-DE)
+//   - Immutable: With returns a new value
+//   - SEO-friendly slugs per locale, see Slugify
+//
+// Database Storage: Stored as JSON object (JSONB)
+// JSON Format: {"de-DE": "Schöne Grüße", "en": "Kind regards"}
+//
+// Usage Examples:
+//
+//	name, err := NewLocalizedString(map[string]string{"en": "Blue Shirt", "de-DE": "Blaues Hemd"})
+//	text, ok := name.Get("de-AT", "en") // "Blaues Hemd", true
+//	slugs := name.Slugs()               // {"de-DE": "blaues-hemd", "en": "blue-shirt"}
+package internationalization
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+
+	"golang-arch/internal/shared/domain/validation"
+)
+
+// LocalizedString holds translations of one piece of text keyed by locale.
+// The zero value has no translations.
+//
+// Features:
+//   - Canonical locale keys ("pt_br" is stored as "pt-BR")
+//   - Deterministic iteration ordered by locale
+//
+// Example:
+//
+//	title, _ := NewLocalizedString(map[string]string{"en-US": "Color", "en-GB": "Colour"})
+//	title.Get("en-AU") // "Colour", true (closest en-* translation, by locale order)
+type LocalizedString struct {
+	values map[Locale]string
+}
+
+// NewLocalizedString creates a LocalizedString from texts keyed by language tag.
+// Returns an error if a tag is invalid, two tags have the same canonical
+// form, or a text is blank.
+func NewLocalizedString(texts map[string]string) (*LocalizedString, error) {
+	var errs validation.ValidationErrors
+	values := make(map[Locale]string, len(texts))
+
+	tags := make([]string, 0, len(texts))
+	for tag := range texts {
+		tags = append(tags, tag)
+	}
+	sort.Strings(tags)
+
+	for _, tag := range tags {
+		locale, err := ParseLocale(tag)
+		if err != nil {
+			errs.Merge(tag, "", err)
+			continue
+		}
+		if _, exists := values[locale]; exists {
+			errs.Add(tag, validation.CodeInvalid, "duplicate translation for locale "+string(locale), nil)
+			continue
+		}
+		if strings.TrimSpace(texts[tag]) == "" {
+			errs.Add(tag, validation.CodeRequired, "translation cannot be empty", nil)
+			continue
+		}
+		values[locale] = texts[tag]
+	}
+	if err := errs.Err(); err != nil {
+		return nil, err
+	}
+	return &LocalizedString{values: values}, nil
+}
+
+// NewLocalizedStringFromPrimitive creates a LocalizedString from its stored JSON object.
+func NewLocalizedStringFromPrimitive(data []byte) (*LocalizedString, error) {
+	var s LocalizedString
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// ToPrimitive converts the LocalizedString to its JSON object for storage.
+func (s LocalizedString) ToPrimitive() ([]byte, error) {
+	return json.Marshal(s)
+}
+
+// With returns a copy with text set for locale.
+func (s LocalizedString) With(locale Locale, text string) (*LocalizedString, error) {
+	texts := s.Texts()
+	texts[string(locale)] = text
+	return NewLocalizedString(texts)
+}
+
+// Get returns the text for the first preferred locale that has one. Each
+// preference matches, in order, its exact locale, the bare language ("de"
+// for "de-AT") and then any other region of the same language.
+func (s LocalizedString) Get(preferences ...Locale) (string, bool) {
+	locale, ok := s.Match(preferences...)
+	if !ok {
+		return "", false
+	}
+	return s.values[locale], true
+}
+
+// Match returns the stored locale Get would use for the preferences.
+func (s LocalizedString) Match(preferences ...Locale) (Locale, bool) {
+	for _, preference := range preferences {
+		if _, ok := s.values[preference]; ok {
+			return preference, true
+		}
+		language := Locale(preference.Language())
+		if _, ok := s.values[language]; ok {
+			return language, true
+		}
+		for _, locale := range s.Locales() {
+			if locale.Language() == preference.Language() {
+				return locale, true
+			}
+		}
+	}
+	return "", false
+}
+
+// Locales returns the locales with a translation, sorted.
+func (s LocalizedString) Locales() []Locale {
+	locales := make([]Locale, 0, len(s.values))
+	for locale := range s.values {
+		locales = append(locales, locale)
+	}
+	sort.Slice(locales, func(i, j int) bool { return locales[i] < locales[j] })
+	return locales
+}
+
+// Texts returns a copy of the translations keyed by language tag.
+func (s LocalizedString) Texts() map[string]string {
+	texts := make(map[string]string, len(s.values)+1)
+	for locale, text := range s.values {
+		texts[string(locale)] = text
+	}
+	return texts
+}
+
+// Len returns the number of translations.
+func (s LocalizedString) Len() int {
+	return len(s.values)
+}
+
+// Slug returns the slug of the text Get selects for the preferences.
+func (s LocalizedString) Slug(preferences ...Locale) (string, bool) {
+	locale, ok := s.Match(preferences...)
+	if !ok {
+		return "", false
+	}
+	return Slugify(s.values[locale], locale), true
+}
+
+// Slugs returns the slug of every translation, transliterated with the
+// rules of its own locale.
+func (s LocalizedString) Slugs() map[Locale]string {
+	slugs := make(map[Locale]string, len(s.values))
+	for locale, text := range s.values {
+		slugs[locale] = Slugify(text, locale)
+	}
+	return slugs
+}
+
+// MarshalJSON implements json.Marshaler as an object keyed by language tag.
+func (s LocalizedString) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.Texts())
+}
+
+// UnmarshalJSON implements json.Unmarshaler interface.
+func (s *LocalizedString) UnmarshalJSON(data []byte) error {
+	var texts map[string]string
+	if err := json.Unmarshal(data, &texts); err != nil {
+		return err
+	}
+	decoded, err := NewLocalizedString(texts)
+	if err != nil {
+		return err
+	}
+	*s = *decoded
+	return nil
+}
@@ -17,12 +17,20 @@
 //	money, err := NewMoneyFromDecimal(100.50, *usd)
 //	formatted := money.Format() // "$100.50"
 //	integer := money.ToInteger() // 10050 (cents)
+//	parsed, err := ParseMoney("100.50 USD")
 package internationalization
 
 import (
 	"encoding/json"
 	"fmt"
 	"math"
+	"math/big"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"golang-arch/internal/shared/domain/domainerror"
+	"golang-arch/internal/shared/domain/validation"
 )
 
 // Money represents a monetary value with an associated currency.
@@ -68,14 +76,14 @@ func NewMoneyFromDecimal(decimal float64, currency Currency) (*Money, error) {
 		// Verify the conversion is accurate
 		convertedBack := float64(integerAmount) / multiplier
 		if math.Abs(convertedBack-decimal) > 0.000001 {
-			return nil, fmt.Errorf("decimal conversion would lose precision: %f", decimal)
+			return nil, domainerror.Invalidf("decimal conversion would lose precision: %f", decimal)
 		}
 	}
 
-	return &Money{
+	return constrainMoney(&Money{
 		Amount:   integerAmount,
 		Currency: currency,
-	}, nil
+	})
 }
 
 // NewMoneyFromInteger creates a new Money composite type from an integer amount.
@@ -85,10 +93,10 @@ func NewMoneyFromInteger(amount int64, currency Currency) (*Money, error) {
 		return nil, fmt.Errorf("invalid currency for money: %w", err)
 	}
 
-	return &Money{
+	return constrainMoney(&Money{
 		Amount:   amount,
 		Currency: currency,
-	}, nil
+	})
 }
 
 // NewMoneyFromPrimitive creates a Money composite type from primitive database values.
@@ -99,21 +107,125 @@ func NewMoneyFromPrimitive(amount int64, currencyCode string) (*Money, error) {
 		return nil, fmt.Errorf("failed to create money from primitive: %w", err)
 	}
 
-	return &Money{
+	return constrainMoney(&Money{
 		Amount:   amount,
 		Currency: *currency,
-	}, nil
+	})
+}
+
+// ParseMoney parses a decimal amount and an ISO 4217 code separated by
+// whitespace, in either order (e.g. "100.50 USD", "USD 100.50", "-3 JPY").
+// The amount is converted exactly, without going through float64, and may not
+// have more fractional digits than the currency allows.
+func ParseMoney(value string) (*Money, error) {
+	fields := strings.Fields(value)
+	if len(fields) != 2 {
+		return nil, domainerror.Invalidf("invalid money format: %q (expected \"<amount> <currency>\")", value)
+	}
+
+	amountStr, code := fields[0], fields[1]
+	if isCurrencyCodeLike(amountStr) {
+		amountStr, code = code, amountStr
+	}
+
+	currency, err := NewCurrencyFromCode(code)
+	if err != nil {
+		return nil, fmt.Errorf("invalid money %q: %w", value, err)
+	}
+
+	amount, err := parseMinorUnits(amountStr, currency.DecimalPlaces)
+	if err != nil {
+		return nil, fmt.Errorf("invalid money %q: %w", value, err)
+	}
+
+	return constrainMoney(&Money{
+		Amount:   amount,
+		Currency: *currency,
+	})
+}
+
+// isCurrencyCodeLike reports whether s consists only of ASCII letters
+func isCurrencyCodeLike(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if (s[i] < 'A' || s[i] > 'Z') && (s[i] < 'a' || s[i] > 'z') {
+			return false
+		}
+	}
+	return s != ""
+}
+
+// parseMinorUnits converts a decimal string such as "-12.3" into an integer
+// amount of minor units for the given number of decimal places.
+func parseMinorUnits(s string, decimalPlaces int) (int64, error) {
+	amount, err := parseBigMinorUnits(s, decimalPlaces)
+	if err != nil {
+		return 0, err
+	}
+	if !amount.IsInt64() {
+		return 0, domainerror.Invalidf("amount %q overflows int64 minor units", s)
+	}
+	return amount.Int64(), nil
+}
+
+// parseBigMinorUnits converts a decimal string into an arbitrary-precision
+// amount of minor units
+func parseBigMinorUnits(s string, decimalPlaces int) (*big.Int, error) {
+	negative := false
+	switch {
+	case strings.HasPrefix(s, "-"):
+		negative = true
+		s = s[1:]
+	case strings.HasPrefix(s, "+"):
+		s = s[1:]
+	}
+
+	integerPart, fractionPart, hasPoint := strings.Cut(s, ".")
+	if integerPart == "" || (hasPoint && fractionPart == "") {
+		return nil, domainerror.Invalidf("invalid amount: %q", s)
+	}
+	for _, part := range []string{integerPart, fractionPart} {
+		for i := 0; i < len(part); i++ {
+			if part[i] < '0' || part[i] > '9' {
+				return nil, domainerror.Invalidf("invalid amount: %q", s)
+			}
+		}
+	}
+
+	if len(fractionPart) > decimalPlaces {
+		return nil, domainerror.Invalidf("amount %q has more than %d decimal places", s, decimalPlaces)
+	}
+
+	digits := integerPart + fractionPart + strings.Repeat("0", decimalPlaces-len(fractionPart))
+	amount, ok := new(big.Int).SetString(digits, 10)
+	if !ok {
+		return nil, domainerror.Invalidf("invalid amount: %q", s)
+	}
+	if negative {
+		amount.Neg(amount)
+	}
+	return amount, nil
+}
+
+// DecimalString returns the exact decimal amount without symbol or code
+// (e.g. "-100.50" for -10050 cents), computed with integer math only.
+func (m Money) DecimalString() string {
+	var buf [formatBufferSize]byte
+	b := buf[:0]
+	if m.Amount < 0 {
+		b = append(b, '-')
+	}
+	return string(appendMinorUnits(b, m.Amount, m.Currency.DecimalPlaces))
 }
 
 // ToPrimitive converts the Money composite type to primitive database values.
 // Returns the amount as int64 and currency code as string.
-func (m *Money) ToPrimitive() (int64, string) {
+func (m Money) ToPrimitive() (int64, string) {
 	return m.Amount, m.Currency.ToPrimitive()
 }
 
 // ToDecimal converts the integer amount to a decimal representation.
 // This is useful for API responses and display purposes.
-func (m *Money) ToDecimal() float64 {
+func (m Money) ToDecimal() float64 {
 	if m.Currency.DecimalPlaces == 0 {
 		return float64(m.Amount)
 	}
@@ -121,42 +233,40 @@ func (m *Money) ToDecimal() float64 {
 }
 
 // ToInteger returns the integer amount in the smallest currency unit.
-func (m *Money) ToInteger() int64 {
+func (m Money) ToInteger() int64 {
 	return m.Amount
 }
 
 // Validate ensures the Money composite type is valid.
-func (m *Money) Validate() error {
-	if err := m.Currency.Validate(); err != nil {
-		return fmt.Errorf("invalid currency in money: %w", err)
-	}
-
-	return nil
+func (m Money) Validate() error {
+	var errs validation.ValidationErrors
+	errs.Merge("currency", "invalid currency in money", m.Currency.Validate())
+	return errs.Err()
 }
 
 // Format returns a formatted string representation of the money value.
-func (m *Money) Format() string {
+func (m Money) Format() string {
 	return m.Currency.Format(m.Amount)
 }
 
 // FormatDecimal returns a formatted string with decimal representation.
 // This is useful for API responses and backward compatibility.
-func (m *Money) FormatDecimal() string {
+func (m Money) FormatDecimal() string {
 	return m.Currency.FormatDecimal(m.ToDecimal())
 }
 
 // Add adds another Money value and returns a new Money value.
 // Both values must have the same currency.
-func (m *Money) Add(other *Money) (*Money, error) {
+func (m Money) Add(other *Money) (*Money, error) {
 	if m.Currency.Code != other.Currency.Code {
-		return nil, fmt.Errorf("cannot add money with different currencies: %s and %s",
+		return nil, domainerror.Invalidf("cannot add money with different currencies: %s and %s",
 			m.Currency.Code, other.Currency.Code)
 	}
 
 	// Check for integer overflow
 	if (other.Amount > 0 && m.Amount > math.MaxInt64-other.Amount) ||
 		(other.Amount < 0 && m.Amount < math.MinInt64-other.Amount) {
-		return nil, fmt.Errorf("integer overflow in money addition")
+		return nil, domainerror.Invalidf("integer overflow in money addition")
 	}
 
 	return NewMoneyFromInteger(m.Amount+other.Amount, m.Currency)
@@ -164,16 +274,16 @@ func (m *Money) Add(other *Money) (*Money, error) {
 
 // Subtract subtracts another Money value and returns a new Money value.
 // Both values must have the same currency.
-func (m *Money) Subtract(other *Money) (*Money, error) {
+func (m Money) Subtract(other *Money) (*Money, error) {
 	if m.Currency.Code != other.Currency.Code {
-		return nil, fmt.Errorf("cannot subtract money with different currencies: %s and %s",
+		return nil, domainerror.Invalidf("cannot subtract money with different currencies: %s and %s",
 			m.Currency.Code, other.Currency.Code)
 	}
 
 	// Check for integer overflow
 	if (other.Amount > 0 && m.Amount < math.MinInt64+other.Amount) ||
 		(other.Amount < 0 && m.Amount > math.MaxInt64+other.Amount) {
-		return nil, fmt.Errorf("integer overflow in money subtraction")
+		return nil, domainerror.Invalidf("integer overflow in money subtraction")
 	}
 
 	return NewMoneyFromInteger(m.Amount-other.Amount, m.Currency)
@@ -181,10 +291,10 @@ func (m *Money) Subtract(other *Money) (*Money, error) {
 
 // Multiply multiplies the money amount by a factor and returns a new Money value.
 // The factor should be an integer to maintain precision.
-func (m *Money) Multiply(factor int64) (*Money, error) {
+func (m Money) Multiply(factor int64) (*Money, error) {
 	// Check for integer overflow
 	if factor != 0 && (m.Amount > math.MaxInt64/factor || m.Amount < math.MinInt64/factor) {
-		return nil, fmt.Errorf("integer overflow in money multiplication")
+		return nil, domainerror.Invalidf("integer overflow in money multiplication")
 	}
 
 	return NewMoneyFromInteger(m.Amount*factor, m.Currency)
@@ -192,24 +302,32 @@ func (m *Money) Multiply(factor int64) (*Money, error) {
 
 // MultiplyDecimal multiplies the money amount by a decimal factor.
 // This method converts to decimal, multiplies, then converts back to integer.
-func (m *Money) MultiplyDecimal(factor float64) (*Money, error) {
+func (m Money) MultiplyDecimal(factor float64) (*Money, error) {
 	decimal := m.ToDecimal()
 	result := decimal * factor
 	return NewMoneyFromDecimal(result, m.Currency)
 }
 
+// Allocate splits the money into parts proportional to the given ratios without
+// losing or creating any minor units. Remainders left by integer division are
+// handed out one unit at a time to the first parts, so the parts always sum to
+// the original amount. Use AllocateWith to choose a different strategy.
+func (m Money) Allocate(ratios ...int64) ([]*Money, error) {
+	return m.AllocateWith(AllocateRoundRobin, ratios...)
+}
+
 // IsZero returns true if the money amount is zero.
-func (m *Money) IsZero() bool {
+func (m Money) IsZero() bool {
 	return m.Amount == 0
 }
 
 // IsPositive returns true if the money amount is positive.
-func (m *Money) IsPositive() bool {
+func (m Money) IsPositive() bool {
 	return m.Amount > 0
 }
 
 // IsNegative returns true if the money amount is negative.
-func (m *Money) IsNegative() bool {
+func (m Money) IsNegative() bool {
 	return m.Amount < 0
 }
 
@@ -222,45 +340,115 @@ func (m *Money) Equal(other *Money) bool {
 }
 
 // String returns a string representation of the money value.
-func (m *Money) String() string {
+func (m Money) String() string {
 	return m.Format()
 }
 
-// MarshalJSON implements json.Marshaler interface.
-func (m *Money) MarshalJSON() ([]byte, error) {
-	// For JSON serialization, we can include both integer and decimal representations
-	type MoneyJSON struct {
-		Amount   int64    `json:"amount"`   // Integer amount
-		Decimal  float64  `json:"decimal"`  // Decimal representation
-		Currency Currency `json:"currency"` // Currency information
+// Reasons passed to the hook of SetLegacyMoneyJSONHook
+const (
+	// LegacyDecimalAmount is an "amount" written as a float, read as a
+	// decimal in major units
+	LegacyDecimalAmount = "decimal_amount"
+)
+
+var (
+	strictMoneyJSON atomic.Bool
+
+	legacyMoneyHookMu sync.RWMutex
+	legacyMoneyHook   func(reason string)
+)
+
+// SetStrictMoneyJSON makes every Money decode strictly, as StrictMoney does,
+// and returns a function that restores the previous mode.
+func SetStrictMoneyJSON(strict bool) (restore func()) {
+	previous := strictMoneyJSON.Swap(strict)
+	return func() {
+		strictMoneyJSON.Store(previous)
 	}
+}
 
-	moneyJSON := MoneyJSON{
-		Amount:   m.Amount,
-		Decimal:  m.ToDecimal(),
-		Currency: m.Currency,
+// SetLegacyMoneyJSONHook sets a function called, with a reason such as
+// LegacyDecimalAmount, whenever a payload is decoded through a lenient
+// legacy path, e.g. to count them before strict mode is turned on. It
+// returns a function that restores the previous hook.
+func SetLegacyMoneyJSONHook(hook func(reason string)) (restore func()) {
+	legacyMoneyHookMu.Lock()
+	previous := legacyMoneyHook
+	legacyMoneyHook = hook
+	legacyMoneyHookMu.Unlock()
+
+	return func() {
+		legacyMoneyHookMu.Lock()
+		legacyMoneyHook = previous
+		legacyMoneyHookMu.Unlock()
 	}
+}
 
-	return json.Marshal(moneyJSON)
+// observeLegacyMoneyJSON reports a lenient decode to the hook, if any
+func observeLegacyMoneyJSON(reason string) {
+	legacyMoneyHookMu.RLock()
+	hook := legacyMoneyHook
+	legacyMoneyHookMu.RUnlock()
+	if hook != nil {
+		hook(reason)
+	}
+}
+
+// StrictMoney is Money that always decodes strictly, whatever
+// SetStrictMoneyJSON says, for request bodies that opt in ahead of the
+// global switch. It encodes like Money.
+type StrictMoney struct {
+	Money
 }
 
 // UnmarshalJSON implements json.Unmarshaler interface.
+func (m *StrictMoney) UnmarshalJSON(data []byte) error {
+	decoded, err := UnmarshalMoneyJSON(data, true)
+	if err != nil {
+		return err
+	}
+	m.Money = *decoded
+	return nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler interface. It is lenient unless
+// SetStrictMoneyJSON turned strict mode on; see UnmarshalMoneyJSON.
 func (m *Money) UnmarshalJSON(data []byte) error {
+	decoded, err := UnmarshalMoneyJSON(data, strictMoneyJSON.Load())
+	if err != nil {
+		return err
+	}
+	*m = *decoded
+	return nil
+}
+
+// UnmarshalMoneyJSON decodes a Money payload. "amount" is a whole number of
+// minor units, e.g. 10050 for 100.50 USD. Leniently, an amount written as a
+// float, such as 100.5 or 10050.0, is read as a decimal in major units
+// instead, which double-scales clients sending cents as a float. Strictly,
+// that payload is rejected, as is a "decimal" that disagrees with "amount".
+func UnmarshalMoneyJSON(data []byte, strict bool) (*Money, error) {
 	// Try the custom format first (with both amount and decimal fields)
 	var customMoney struct {
 		Amount   int64    `json:"amount"`
-		Decimal  float64  `json:"decimal"`
+		Decimal  *float64 `json:"decimal"`
 		Currency Currency `json:"currency"`
 	}
 
 	if err := json.Unmarshal(data, &customMoney); err == nil {
 		// Use the integer amount directly
-		newMoney, err := NewMoneyFromInteger(customMoney.Amount, customMoney.Currency)
+		money, err := NewMoneyFromInteger(customMoney.Amount, customMoney.Currency)
 		if err != nil {
-			return err
+			return nil, err
+		}
+		if strict && customMoney.Decimal != nil {
+			stated, err := NewMoneyFromDecimal(*customMoney.Decimal, money.Currency)
+			if err != nil || stated.Amount != money.Amount {
+				return nil, domainerror.Invalidf("money decimal %v does not match amount %d %s",
+					*customMoney.Decimal, money.Amount, money.Currency.Code)
+			}
 		}
-		*m = *newMoney
-		return nil
+		return money, nil
 	}
 
 	// For backward compatibility, try to unmarshal as decimal first
@@ -270,13 +458,13 @@ func (m *Money) UnmarshalJSON(data []byte) error {
 	}
 
 	if err := json.Unmarshal(data, &decimalMoney); err == nil {
-		// Convert from decimal format
-		newMoney, err := NewMoneyFromDecimal(decimalMoney.Amount, decimalMoney.Currency)
-		if err != nil {
-			return err
+		if strict {
+			return nil, domainerror.Invalidf("money amount must be an integer number of %s minor units, "+
+				"e.g. 10050 for 100.50", decimalMoney.Currency.Code)
 		}
-		*m = *newMoney
-		return nil
+		// Convert from decimal format
+		observeLegacyMoneyJSON(LegacyDecimalAmount)
+		return NewMoneyFromDecimal(decimalMoney.Amount, decimalMoney.Currency)
 	}
 
 	// Try integer format
@@ -286,13 +474,8 @@ func (m *Money) UnmarshalJSON(data []byte) error {
 	}
 
 	if err := json.Unmarshal(data, &integerMoney); err != nil {
-		return fmt.Errorf("failed to unmarshal money: %w", err)
+		return nil, domainerror.Invalidf("failed to unmarshal money: %w", err)
 	}
 
-	newMoney, err := NewMoneyFromInteger(integerMoney.Amount, integerMoney.Currency)
-	if err != nil {
-		return err
-	}
-	*m = *newMoney
-	return nil
+	return NewMoneyFromInteger(integerMoney.Amount, integerMoney.Currency)
 }
@@ -0,0 +1,173 @@
+// Package internationalization provides domain types and composite types for handling
+// internationalized data such as time, currency, timezone, and phone numbers.
+// This file contains the MoneyBag composite type for multi-currency totals.
+//
+// MoneyBag Composite Type:
+//   - Holds at most one Money amount per currency
+//   - Adding Money of a new currency opens a new slot instead of failing
+//   - Immutable: Add and Merge return a new bag
+//   - Deterministic iteration ordered by currency code
+//
+// JSON Format: [{"amount": 2000, "decimal": 20, "currency": {...}}, {"amount": 10050, "decimal": 100.5, "currency": {...}}]
+//
+// Usage Examples:
+//
+//	bag, err := NewMoneyBag(usdPrice, eurPrice)
+//	bag, err = bag.Add(anotherUSD)      // USD slot grows, EUR unchanged
+//	usd, ok := bag.Get("USD")
+//	for _, m := range bag.Amounts() { ... } // EUR, USD
+package internationalization
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// MoneyBag is a multi-currency collection of Money that keeps one running
+// total per currency. The zero value is an empty bag.
+//
+// Features:
+//   - Accumulates mixed-currency amounts without conversion
+//   - Overflow-checked addition per currency
+//   - Stable, code-ordered iteration for display and valuation
+//
+// Example:
+//
+//	bag, _ := NewMoneyBag(*usd100, *eur50, *usd25)
+//	bag.Len()        // 2
+//	bag.Get("USD")   // $125.00, true
+type MoneyBag struct {
+	amounts map[string]Money
+}
+
+// NewMoneyBag creates a MoneyBag holding the sum of monies per currency.
+// Returns an error if any amount is invalid or a currency total overflows.
+func NewMoneyBag(monies ...Money) (*MoneyBag, error) {
+	bag := &MoneyBag{}
+	for _, m := range monies {
+		next, err := bag.Add(m)
+		if err != nil {
+			return nil, err
+		}
+		bag = next
+	}
+	return bag, nil
+}
+
+// Add returns a new bag with m added to the total of its currency.
+func (b MoneyBag) Add(m Money) (*MoneyBag, error) {
+	if err := m.Validate(); err != nil {
+		return nil, err
+	}
+
+	total := m
+	if existing, ok := b.amounts[m.Currency.Code]; ok {
+		sum, err := existing.Add(&m)
+		if err != nil {
+			return nil, fmt.Errorf("money bag %s total: %w", m.Currency.Code, err)
+		}
+		total = *sum
+	}
+
+	next := b.clone()
+	next.amounts[m.Currency.Code] = total
+	return next, nil
+}
+
+// Merge returns a new bag holding the per-currency sums of both bags.
+func (b MoneyBag) Merge(other *MoneyBag) (*MoneyBag, error) {
+	result := b.clone()
+	if other == nil {
+		return result, nil
+	}
+	for _, m := range other.Amounts() {
+		next, err := result.Add(m)
+		if err != nil {
+			return nil, err
+		}
+		result = next
+	}
+	return result, nil
+}
+
+// Get returns the total held in the currency with the given code.
+func (b MoneyBag) Get(code string) (Money, bool) {
+	m, ok := b.amounts[code]
+	return m, ok
+}
+
+// Currencies returns the codes of the currencies in the bag, sorted.
+func (b MoneyBag) Currencies() []string {
+	codes := make([]string, 0, len(b.amounts))
+	for code := range b.amounts {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	return codes
+}
+
+// Amounts returns the per-currency totals ordered by currency code.
+func (b MoneyBag) Amounts() []Money {
+	amounts := make([]Money, 0, len(b.amounts))
+	for _, code := range b.Currencies() {
+		amounts = append(amounts, b.amounts[code])
+	}
+	return amounts
+}
+
+// Len returns the number of currencies in the bag.
+func (b MoneyBag) Len() int {
+	return len(b.amounts)
+}
+
+// IsZero reports whether every currency total in the bag is zero.
+func (b MoneyBag) IsZero() bool {
+	for _, m := range b.amounts {
+		if !m.IsZero() {
+			return false
+		}
+	}
+	return true
+}
+
+// String returns the totals joined by " + ", e.g. "€50.00 + $125.00".
+func (b MoneyBag) String() string {
+	if len(b.amounts) == 0 {
+		return "0"
+	}
+	parts := make([]string, 0, len(b.amounts))
+	for _, m := range b.Amounts() {
+		parts = append(parts, m.Format())
+	}
+	return strings.Join(parts, " + ")
+}
+
+// MarshalJSON implements json.Marshaler as an array of Money ordered by currency code.
+func (b MoneyBag) MarshalJSON() ([]byte, error) {
+	return json.Marshal(b.Amounts())
+}
+
+// UnmarshalJSON implements json.Unmarshaler, summing amounts of repeated currencies.
+func (b *MoneyBag) UnmarshalJSON(data []byte) error {
+	var amounts []Money
+	if err := json.Unmarshal(data, &amounts); err != nil {
+		return err
+	}
+	bag, err := NewMoneyBag(amounts...)
+	if err != nil {
+		return err
+	}
+	*b = *bag
+	return nil
+}
+
+// clone returns a copy of the bag with its own map.
+func (b MoneyBag) clone() *MoneyBag {
+	amounts := make(map[string]Money, len(b.amounts)+1)
+	for code, m := range b.amounts {
+		amounts[code] = m
+	}
+	return &MoneyBag{amounts: amounts}
+}
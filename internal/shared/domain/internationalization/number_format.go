@@ -0,0 +1,160 @@
+package internationalization
+
+import "strconv"
+
+// NumberSymbols are the separators and currency placement of a locale,
+// following CLDR. Digits are always Latin.
+type NumberSymbols struct {
+	// Decimal separates the fraction, e.g. "," in de-DE
+	Decimal string
+	// Group separates thousands, e.g. "." in de-DE
+	Group string
+	// SecondaryGrouping is the size of the groups above the first three
+	// digits; 2 in en-IN, where 12345678 reads "1,23,45,678", and 3 elsewhere
+	SecondaryGrouping int
+	// SymbolAfter writes the currency symbol after the amount, separated by a
+	// no-break space, e.g. "1.234,56 €"
+	SymbolAfter bool
+}
+
+const noBreakSpace = "\u00a0"
+
+var (
+	pointSymbols      = NumberSymbols{Decimal: ".", Group: ",", SecondaryGrouping: 3}
+	commaSymbols      = NumberSymbols{Decimal: ",", Group: ".", SecondaryGrouping: 3, SymbolAfter: true}
+	spaceSymbols      = NumberSymbols{Decimal: ",", Group: noBreakSpace, SecondaryGrouping: 3, SymbolAfter: true}
+	apostropheSymbols = NumberSymbols{Decimal: ".", Group: "’", SecondaryGrouping: 3}
+	indianSymbols     = NumberSymbols{Decimal: ".", Group: ",", SecondaryGrouping: 2}
+)
+
+// languageNumberSymbols are the symbols by language; languages not listed
+// use pointSymbols
+var languageNumberSymbols = map[Language]NumberSymbols{
+	"de": commaSymbols, "es": commaSymbols, "it": commaSymbols, "da": commaSymbols,
+	"el": commaSymbols, "ro": commaSymbols, "hr": commaSymbols, "sl": commaSymbols,
+	"sr": commaSymbols, "vi": commaSymbols,
+	"fr": spaceSymbols, "pl": spaceSymbols, "ru": spaceSymbols, "uk": spaceSymbols,
+	"cs": spaceSymbols, "sk": spaceSymbols, "sv": spaceSymbols, "nb": spaceSymbols,
+	"fi": spaceSymbols, "hu": spaceSymbols, "bg": spaceSymbols, "lt": spaceSymbols,
+	"lv": spaceSymbols, "et": spaceSymbols,
+	"hi": indianSymbols,
+	// The symbol leads in these languages despite their comma decimals
+	"nl": {Decimal: ",", Group: ".", SecondaryGrouping: 3},
+	"pt": {Decimal: ",", Group: ".", SecondaryGrouping: 3},
+	"tr": {Decimal: ",", Group: ".", SecondaryGrouping: 3},
+	"id": {Decimal: ",", Group: ".", SecondaryGrouping: 3},
+}
+
+// localeNumberSymbols override the language for regional variants
+var localeNumberSymbols = map[Locale]NumberSymbols{
+	"de-AT": spaceSymbols,
+	"de-CH": apostropheSymbols,
+	"de-LI": apostropheSymbols,
+	"it-CH": apostropheSymbols,
+	"fr-CH": spaceSymbols,
+	"es-MX": pointSymbols,
+	"es-US": pointSymbols,
+	"en-IN": indianSymbols,
+	"pt-PT": spaceSymbols,
+}
+
+// NumberSymbolsFor returns the separators and currency placement of locale,
+// falling back from the region to the language and then to en-US style
+func NumberSymbolsFor(locale Locale) NumberSymbols {
+	if symbols, ok := localeNumberSymbols[locale]; ok {
+		return symbols
+	}
+	if symbols, ok := languageNumberSymbols[locale.Language()]; ok {
+		return symbols
+	}
+	return pointSymbols
+}
+
+// FormatLocalized formats an amount of minor units for display in locale,
+// with its grouping, decimal separator and symbol position, e.g.
+// "$1,234.56" in en-US and "1.234,56 €" in de-DE. Only integer math is used,
+// so large amounts render exactly. Currencies without a symbol show their
+// code.
+func (c Currency) FormatLocalized(amount int64, locale Locale) string {
+	buf := getFormatBuffer()
+	defer putFormatBuffer(buf)
+
+	*buf = c.AppendFormatLocalized(*buf, amount, locale)
+	return string(*buf)
+}
+
+// FormatLocalized formats the money value for display in locale, as
+// Currency.FormatLocalized does
+func (m Money) FormatLocalized(locale Locale) string {
+	return m.Currency.FormatLocalized(m.Amount, locale)
+}
+
+// AppendFormatLocalized appends the amount formatted for locale, as returned
+// by FormatLocalized, to dst.
+func (c Currency) AppendFormatLocalized(dst []byte, amount int64, locale Locale) []byte {
+	symbols := NumberSymbolsFor(locale)
+	symbol := c.Symbol
+	if symbol == "" {
+		symbol = c.Code
+	}
+
+	if amount < 0 {
+		dst = append(dst, '-')
+	}
+	if !symbols.SymbolAfter {
+		dst = append(dst, symbol...)
+	}
+	dst = appendGroupedMinorUnits(dst, amount, c.DecimalPlaces, symbols)
+	if symbols.SymbolAfter {
+		dst = append(dst, noBreakSpace...)
+		dst = append(dst, symbol...)
+	}
+	return dst
+}
+
+// appendGroupedMinorUnits appends the unsigned amount of minor units with
+// the locale's group and decimal separators, e.g. 123456 with 2 decimal
+// places becomes "1.234,56" in de-DE
+func appendGroupedMinorUnits(dst []byte, amount int64, decimalPlaces int, symbols NumberSymbols) []byte {
+	var digitsBuf [20]byte
+	return appendGroupedDigits(dst, strconv.AppendUint(digitsBuf[:0], absUint64(amount), 10), decimalPlaces, symbols)
+}
+
+// appendGroupedDigits appends the decimal digits of an unsigned amount of
+// minor units with the locale's separators
+func appendGroupedDigits(dst, digits []byte, decimalPlaces int, symbols NumberSymbols) []byte {
+	var integer, fraction []byte
+	switch {
+	case decimalPlaces <= 0:
+		integer = digits
+	case len(digits) <= decimalPlaces:
+		integer = []byte{'0'}
+		fraction = digits
+	default:
+		point := len(digits) - decimalPlaces
+		integer, fraction = digits[:point], digits[point:]
+	}
+
+	secondary := symbols.SecondaryGrouping
+	if secondary <= 0 {
+		secondary = 3
+	}
+	for i, digit := range integer {
+		if i > 0 {
+			// left digits remain, this one included
+			if left := len(integer) - i; left == 3 || (left > 3 && (left-3)%secondary == 0) {
+				dst = append(dst, symbols.Group...)
+			}
+		}
+		dst = append(dst, digit)
+	}
+
+	if decimalPlaces > 0 {
+		dst = append(dst, symbols.Decimal...)
+		for i := len(fraction); i < decimalPlaces; i++ {
+			dst = append(dst, '0')
+		}
+		dst = append(dst, fraction...)
+	}
+	return dst
+}
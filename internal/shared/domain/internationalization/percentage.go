@@ -0,0 +1,121 @@
+// Package internationalization provides domain types for handling internationalization
+// concerns such as time, currency, timezone, and phone number formatting.
+//
+// This file holds Percentage, the rate of taxes, fees and discounts. It is an
+// integer number of basis points (hundredths of a percent), so 11% is 1100
+// and 2.5% is 250, and applying it to Money is exact integer math with an
+// explicit rounding mode instead of float multiplication:
+//
+//	vat, _ := ParsePercentage("20%")
+//	tax, err := subtotal.ApplyPercentage(vat, RoundHalfUp)
+//
+// Database Storage: Stored as integer (basis points)
+// JSON: the number of basis points, e.g. 1100 for 11%
+package internationalization
+
+import (
+	"math/big"
+	"strconv"
+	"strings"
+
+	"golang-arch/internal/shared/domain/domainerror"
+)
+
+// Percentage is a rate in basis points: 100 is 1%, 10000 is 100%
+type Percentage int64
+
+// OneHundredPercent is 100%, the whole amount
+const OneHundredPercent Percentage = 10000
+
+// basisPointDecimals is the number of decimal places of a percentage in
+// basis points
+const basisPointDecimals = 2
+
+// NewPercentageFromBasisPoints creates a percentage from basis points, e.g.
+// 1100 for 11%
+func NewPercentageFromBasisPoints(basisPoints int64) Percentage {
+	return Percentage(basisPoints)
+}
+
+// ParsePercentage parses a percentage with at most two decimal places and an
+// optional percent sign, e.g. "11%", "12.5" or "-0.25 %"
+func ParsePercentage(value string) (Percentage, error) {
+	s := strings.TrimSpace(value)
+	s = strings.TrimSpace(strings.TrimSuffix(s, "%"))
+	basisPoints, err := parseMinorUnits(s, basisPointDecimals)
+	if err != nil {
+		return 0, domainerror.Invalidf("invalid percentage %q: must be a number with at most %d decimal places", value, basisPointDecimals)
+	}
+	return Percentage(basisPoints), nil
+}
+
+// BasisPoints returns the percentage in basis points
+func (p Percentage) BasisPoints() int64 {
+	return int64(p)
+}
+
+// ToPrimitive returns the percentage in basis points for database storage
+func (p Percentage) ToPrimitive() int64 {
+	return int64(p)
+}
+
+// Percent returns the percentage as a float64, e.g. 12.5 for 1250 basis
+// points, for display only
+func (p Percentage) Percent() float64 {
+	return float64(p) / 100
+}
+
+// IsZero returns true if the percentage is 0%
+func (p Percentage) IsZero() bool {
+	return p == 0
+}
+
+// IsNegative returns true if the percentage is below 0%, as for discounts
+// expressed as negative rates
+func (p Percentage) IsNegative() bool {
+	return p < 0
+}
+
+// String returns the percentage without trailing zeros, e.g. "12.5%"
+func (p Percentage) String() string {
+	var digits [20]byte
+	s := string(appendDigitsWithPoint(nil, strconv.AppendUint(digits[:0], absUint64(int64(p)), 10), basisPointDecimals))
+	s = strings.TrimSuffix(strings.TrimRight(s, "0"), ".")
+	if p < 0 {
+		return "-" + s + "%"
+	}
+	return s + "%"
+}
+
+// ApplyPercentage returns p of the amount, rounded with mode, e.g. the tax
+// on a subtotal or the fee on a payment
+func (m Money) ApplyPercentage(p Percentage, mode RoundingMode) (*Money, error) {
+	num := new(big.Int).Mul(big.NewInt(m.Amount), big.NewInt(int64(p)))
+	amount, err := roundQuotient(num, big.NewInt(int64(OneHundredPercent)), mode)
+	if err != nil {
+		return nil, err
+	}
+	return NewMoneyFromInteger(amount, m.Currency)
+}
+
+// PercentageOf returns the amount as a percentage of other, in the same
+// currency, rounded half up to a basis point, e.g. 25.00 of 200.00 is 12.5%
+func (m Money) PercentageOf(other *Money) (Percentage, error) {
+	if other == nil {
+		return 0, domainerror.Invalidf("money to compare with is required")
+	}
+	if m.Currency.Code != other.Currency.Code {
+		return 0, domainerror.Invalidf("cannot compare money with different currencies: %s and %s",
+			m.Currency.Code, other.Currency.Code)
+	}
+	if other.Amount == 0 {
+		return 0, domainerror.Invalidf("percentage of zero is undefined")
+	}
+
+	num := new(big.Int).Mul(big.NewInt(m.Amount), big.NewInt(int64(OneHundredPercent)))
+	basisPoints, err := roundQuotient(num, big.NewInt(other.Amount), RoundHalfUp)
+	if err != nil {
+		return 0, err
+	}
+	return Percentage(basisPoints), nil
+}
@@ -13,6 +13,9 @@ import (
 	"fmt"
 	"regexp"
 	"strings"
+
+	"golang-arch/internal/shared/domain/domainerror"
+	"golang-arch/internal/shared/domain/validation"
 )
 
 // Phone represents a phone number with country code.
@@ -32,6 +35,9 @@ func NewPhone(countryCode, number string) (*Phone, error) {
 	if err := phone.Validate(); err != nil {
 		return nil, fmt.Errorf("invalid phone number: %w", err)
 	}
+	if err := checkConstraints(currentConstraints().Phone, *phone); err != nil {
+		return nil, err
+	}
 
 	return phone, nil
 }
@@ -55,7 +61,7 @@ func NewPhoneFromString(phoneStr string) (*Phone, error) {
 }
 
 // ToPrimitive returns the primitive value for database storage.
-func (p *Phone) ToPrimitive() string {
+func (p Phone) ToPrimitive() string {
 	return p.Format()
 }
 
@@ -65,42 +71,51 @@ func FromPrimitivePhone(phoneStr string) (*Phone, error) {
 }
 
 // Validate ensures the phone number has valid country code and number format.
-func (p *Phone) Validate() error {
+func (p Phone) Validate() error {
+	var errs validation.ValidationErrors
+
 	if p.CountryCode == "" {
-		return fmt.Errorf("country code cannot be empty")
+		errs.Add("country_code", validation.CodeRequired, "country code cannot be empty", nil)
+	} else if !phoneCountryCodeRegex.MatchString(p.CountryCode) {
+		// Validate country code (1-3 digits)
+		errs.Add("country_code", validation.CodeInvalidFormat,
+			fmt.Sprintf("invalid country code: %s", p.CountryCode),
+			map[string]any{"value": p.CountryCode})
 	}
 
 	if p.Number == "" {
-		return fmt.Errorf("phone number cannot be empty")
-	}
-
-	// Validate country code (1-3 digits)
-	countryCodeRegex := regexp.MustCompile(`^[1-9]\d{0,2}$`)
-	if !countryCodeRegex.MatchString(p.CountryCode) {
-		return fmt.Errorf("invalid country code: %s", p.CountryCode)
+		errs.Add("number", validation.CodeRequired, "phone number cannot be empty", nil)
+	} else if !phoneNumberRegex.MatchString(p.Number) {
+		// Validate phone number (7-15 digits)
+		errs.Add("number", validation.CodeInvalidFormat,
+			fmt.Sprintf("invalid phone number format: %s", p.Number),
+			map[string]any{"value": p.Number, "min_digits": 7, "max_digits": 15})
 	}
 
-	// Validate phone number (7-15 digits)
-	numberRegex := regexp.MustCompile(`^\d{7,15}$`)
-	if !numberRegex.MatchString(p.Number) {
-		return fmt.Errorf("invalid phone number format: %s", p.Number)
-	}
-
-	return nil
+	return errs.Err()
 }
 
+var (
+	phoneCountryCodeRegex = regexp.MustCompile(`^[1-9]\d{0,2}$`)
+	phoneNumberRegex      = regexp.MustCompile(`^\d{7,15}$`)
+)
+
 // Format returns the phone number in international format.
-func (p *Phone) Format() string {
-	return fmt.Sprintf("+%s %s", p.CountryCode, p.Number)
+func (p Phone) Format() string {
+	buf := getFormatBuffer()
+	defer putFormatBuffer(buf)
+
+	*buf = p.AppendFormat(*buf)
+	return string(*buf)
 }
 
 // FormatCompact returns the phone number in compact international format.
-func (p *Phone) FormatCompact() string {
+func (p Phone) FormatCompact() string {
 	return fmt.Sprintf("+%s%s", p.CountryCode, p.Number)
 }
 
 // FormatLocal returns the phone number in local format (with dashes for US numbers).
-func (p *Phone) FormatLocal() string {
+func (p Phone) FormatLocal() string {
 	// For US numbers (country code 1), format with dashes
 	if p.CountryCode == "1" && len(p.Number) == 10 {
 		return fmt.Sprintf("%s-%s-%s", p.Number[:3], p.Number[3:6], p.Number[6:])
@@ -119,17 +134,17 @@ func (p *Phone) Equal(other *Phone) bool {
 }
 
 // String returns the phone number in international format.
-func (p *Phone) String() string {
+func (p Phone) String() string {
 	return p.Format()
 }
 
 // GetCountryCode returns the country code.
-func (p *Phone) GetCountryCode() string {
+func (p Phone) GetCountryCode() string {
 	return p.CountryCode
 }
 
 // GetNumber returns the phone number without country code.
-func (p *Phone) GetNumber() string {
+func (p Phone) GetNumber() string {
 	return p.Number
 }
 
@@ -151,7 +166,7 @@ func parseInternationalFormat(phoneStr string) (*Phone, error) {
 		}
 	}
 
-	return nil, fmt.Errorf("invalid international phone format: %s", phoneStr)
+	return nil, domainerror.Invalidf("invalid international phone format: %s", phoneStr)
 }
 
 // parseLocalFormat attempts to parse local format and detect country code.
@@ -160,7 +175,7 @@ func parseLocalFormat(phoneStr string) (*Phone, error) {
 	digits := regexp.MustCompile(`\D`).ReplaceAllString(phoneStr, "")
 
 	if len(digits) < 7 || len(digits) > 15 {
-		return nil, fmt.Errorf("invalid phone number length: %d", len(digits))
+		return nil, domainerror.Invalidf("invalid phone number length: %d", len(digits))
 	}
 
 	// For 10-digit numbers, assume US (+1) as they are typically US numbers
@@ -185,7 +200,7 @@ func parseLocalFormat(phoneStr string) (*Phone, error) {
 		return NewPhone("1", digits)
 	}
 
-	return nil, fmt.Errorf("unable to parse phone number: %s", phoneStr)
+	return nil, domainerror.Invalidf("unable to parse phone number: %s", phoneStr)
 }
 
 // IsValidPhoneNumber checks if a string is a valid phone number.
@@ -0,0 +1,259 @@
+// Package internationalization provides domain types and composite types for handling
+// internationalized data such as time, currency, timezone, and phone numbers.
+// This file contains ParsePhone, which normalizes free-form phone input using
+// the numbering plans of the supported countries.
+//
+// Parsing:
+//   - Accepts spaces, dashes, dots, slashes and parentheses as separators
+//   - "+", "00" (and "011" within NANP) start an international number
+//   - National numbers need a default region; its trunk prefix is dropped
+//   - The country is derived from the calling code, using the area code for
+//     +1 (US or CA) and the first digit for +7 (RU or KZ)
+//
+// Classification is by prefix, so ported numbers keep their original type.
+// Countries outside phonePlans are rejected rather than guessed.
+//
+// Usage Examples:
+//
+//	parsed, err := ParsePhone("+49 (0)176 1234-5678", "")
+//	parsed.E164()    // "+4917612345678"
+//	parsed.Type      // PhoneMobile
+//	parsed, err = ParsePhone("020 7946 0958", "GB")
+//	parsed.Timezones // ["Europe/London"]
+package internationalization
+
+import (
+	"regexp"
+	"strings"
+
+	"golang-arch/internal/shared/domain/domainerror"
+)
+
+// PhoneType classifies a number by its numbering plan prefix.
+type PhoneType string
+
+// Phone types.
+const (
+	PhoneMobile            PhoneType = "mobile"
+	PhoneFixedLine         PhoneType = "fixed_line"
+	PhoneFixedLineOrMobile PhoneType = "fixed_line_or_mobile" // Plans that do not tell them apart, e.g. NANP
+	PhoneTollFree          PhoneType = "toll_free"
+)
+
+// ParsedPhone is a normalized phone number with what its numbering plan
+// tells about it.
+type ParsedPhone struct {
+	Phone     Phone     `json:"phone"`
+	Country   Country   `json:"country"`
+	Type      PhoneType `json:"type"`
+	Timezones []string  `json:"timezones"` // IANA zones of the country, the likely ones of the subscriber
+}
+
+// E164 returns the number in E.164 form, e.g. "+4917612345678".
+func (p ParsedPhone) E164() string {
+	return p.Phone.FormatCompact()
+}
+
+// phonePlan is the numbering plan of a country. Patterns match the national
+// number without trunk prefix.
+type phonePlan struct {
+	code     string         // Calling code
+	trunk    string         // National (trunk) prefix, e.g. "0"
+	min, max int            // Digits of the national number
+	mobile   *regexp.Regexp // nil when mobiles cannot be told from fixed lines
+	tollFree *regexp.Regexp
+}
+
+func plan(code, trunk string, min, max int, mobile, tollFree string) phonePlan {
+	p := phonePlan{code: code, trunk: trunk, min: min, max: max}
+	if mobile != "" {
+		p.mobile = regexp.MustCompile(`^(?:` + mobile + `)$`)
+	}
+	if tollFree != "" {
+		p.tollFree = regexp.MustCompile(`^(?:` + tollFree + `)$`)
+	}
+	return p
+}
+
+// phonePlans holds the numbering plans of the supported countries.
+var phonePlans = map[Country]phonePlan{
+	"US": plan("1", "1", 10, 10, "", `8(00|33|44|55|66|77|88)\d{7}`),
+	"CA": plan("1", "1", 10, 10, "", `8(00|33|44|55|66|77|88)\d{7}`),
+	"GB": plan("44", "0", 9, 10, `7[1-57-9]\d{8}`, `80[08]\d{6,7}`),
+	"FR": plan("33", "0", 9, 9, `[67]\d{8}`, `80\d{7}`),
+	"DE": plan("49", "0", 7, 13, `1[5-7]\d{8,9}`, `800\d{7,9}`),
+	"AT": plan("43", "0", 7, 13, `6[5-9]\d{6,11}`, `800\d{6,9}`),
+	"CH": plan("41", "0", 9, 9, `7[5-9]\d{7}`, `800\d{6}`),
+	"IT": plan("39", "", 7, 11, `3\d{8,9}`, `80[03]\d{3,6}`),
+	"ES": plan("34", "", 9, 9, `[67]\d{8}`, `90[08]\d{6}`),
+	"PT": plan("351", "", 9, 9, `9[1236]\d{7}`, `80[08]\d{6}`),
+	"NL": plan("31", "0", 9, 9, `6[1-58]\d{7}`, `800\d{4,7}`),
+	"BE": plan("32", "0", 8, 9, `4[5-9]\d{7}`, `800\d{5}`),
+	"IE": plan("353", "0", 7, 9, `8[35-9]\d{7}`, `1800\d{6}`),
+	"SE": plan("46", "0", 7, 10, `7[02369]\d{7}`, `20\d{5,7}`),
+	"NO": plan("47", "", 8, 8, `[49]\d{7}`, `80[01]\d{5}`),
+	"DK": plan("45", "", 8, 8, "", `80\d{6}`),
+	"PL": plan("48", "", 9, 9, `(45|5[0137]|6[069]|7[2389]|88)\d{7}`, `800\d{6}`),
+	"CZ": plan("420", "", 9, 9, `[67]\d{8}`, `800\d{6}`),
+	"HU": plan("36", "06", 8, 9, `(20|30|31|50|70)\d{7}`, `80\d{6}`),
+	"RU": plan("7", "8", 10, 10, `9\d{9}`, `80[04]\d{7}`),
+	"KZ": plan("7", "8", 10, 10, `7[0-8]\d{8}`, `800\d{7}`),
+	"TR": plan("90", "0", 10, 10, `5\d{9}`, `800\d{7}`),
+	"IL": plan("972", "0", 8, 9, `5\d{8}`, `1800\d{6}`),
+	"SA": plan("966", "0", 8, 9, `5\d{8}`, `800\d{7}`),
+	"AE": plan("971", "0", 8, 9, `5[024568]\d{7}`, `800\d{2,9}`),
+	"ZA": plan("27", "0", 9, 9, `[6-8]\d{8}`, `80\d{7}`),
+	"IN": plan("91", "0", 10, 10, `[6-9]\d{9}`, `1800\d{6,7}`),
+	"CN": plan("86", "0", 9, 11, `1[3-9]\d{9}`, `800\d{7}`),
+	"HK": plan("852", "", 8, 8, `[4-79]\d{7}`, `800\d{6}`),
+	"JP": plan("81", "0", 9, 10, `[7-9]0\d{8}`, `120\d{6}|800\d{7}`),
+	"KR": plan("82", "0", 8, 10, `1[016-9]\d{7,8}`, `80\d{7}`),
+	"SG": plan("65", "", 8, 11, `[89]\d{7}`, `1800\d{7}`),
+	"MY": plan("60", "0", 8, 10, `1\d{8,9}`, `1[38]00\d{6}`),
+	"TH": plan("66", "0", 8, 9, `[689]\d{8}`, `1800\d{6}`),
+	"VN": plan("84", "0", 9, 10, `[35789]\d{8}`, `1800\d{4,6}`),
+	"ID": plan("62", "0", 8, 12, `8\d{8,11}`, `800\d{6,8}`),
+	"PH": plan("63", "0", 8, 10, `9\d{9}`, `1800\d{6,7}`),
+	"AU": plan("61", "0", 9, 10, `4\d{8}`, `180[02]\d{6}`),
+	"NZ": plan("64", "0", 8, 10, `2\d{7,9}`, `80[08]\d{6,7}`),
+	"BR": plan("55", "0", 10, 11, `\d{2}9\d{8}`, `800\d{6,7}`),
+	"MX": plan("52", "", 10, 10, "", `800\d{7}`),
+}
+
+// canadianAreaCodes tells Canadian +1 numbers from US ones.
+var canadianAreaCodes = map[string]bool{
+	"204": true, "226": true, "236": true, "249": true, "250": true, "263": true, "289": true,
+	"306": true, "343": true, "354": true, "365": true, "367": true, "368": true, "382": true,
+	"403": true, "416": true, "418": true, "428": true, "431": true, "437": true, "438": true,
+	"450": true, "468": true, "474": true, "506": true, "514": true, "519": true, "548": true,
+	"579": true, "581": true, "584": true, "587": true, "604": true, "613": true, "639": true,
+	"647": true, "672": true, "683": true, "705": true, "709": true, "742": true, "753": true,
+	"778": true, "780": true, "782": true, "807": true, "819": true, "825": true, "867": true,
+	"873": true, "879": true, "902": true, "905": true,
+}
+
+// phoneSeparators are removed before parsing.
+var phoneSeparators = strings.NewReplacer(" ", "", "\u00a0", "", "-", "", ".", "", "/", "", "(", "", ")", "")
+
+// ParsePhone normalizes raw, an international number or a national number of
+// region, and classifies it by its country's numbering plan. region may be
+// empty for international numbers.
+func ParsePhone(raw string, region Country) (*ParsedPhone, error) {
+	digits := phoneSeparators.Replace(strings.TrimSpace(raw))
+	international := false
+	switch {
+	case strings.HasPrefix(digits, "+"):
+		digits, international = digits[1:], true
+	case strings.HasPrefix(digits, "00"):
+		digits, international = digits[2:], true
+	case strings.HasPrefix(digits, "011") && phonePlans[region].code == "1":
+		digits, international = digits[3:], true
+	}
+	if digits == "" || !isDigits(digits) {
+		return nil, domainerror.Invalidf("phone number may only contain digits and separators: %q", raw)
+	}
+
+	var code, national string
+	if international {
+		code, national = splitCallingCode(digits)
+		if code == "" {
+			return nil, domainerror.Invalidf("unsupported calling code in %q", raw)
+		}
+	} else {
+		if region == "" {
+			return nil, domainerror.Invalidf("national number %q needs a default region", raw)
+		}
+		p, ok := phonePlans[region]
+		if !ok {
+			return nil, domainerror.Invalidf("no numbering plan for region %s", region)
+		}
+		code, national = p.code, digits
+	}
+
+	country := phoneCountry(code, national, region)
+	p := phonePlans[country]
+	// The trunk prefix is dialed nationally only, but is often written
+	// internationally too, e.g. "+44 (0)20 ...". Only the 8 of RU and KZ
+	// may also start a national number, there the length decides.
+	if p.trunk != "" && strings.HasPrefix(national, p.trunk) && p.valid(national[len(p.trunk):]) &&
+		(p.trunk != "8" || !p.valid(national)) {
+		national = national[len(p.trunk):]
+		country = phoneCountry(code, national, region)
+		p = phonePlans[country]
+	}
+	if !p.valid(national) {
+		return nil, domainerror.Invalidf("%s numbers have %d to %d digits after +%s, got %d", country, p.min, p.max, code, len(national))
+	}
+
+	phone, err := NewPhone(code, national)
+	if err != nil {
+		return nil, err
+	}
+	return &ParsedPhone{Phone: *phone, Country: country, Type: p.classify(national), Timezones: country.Timezones()}, nil
+}
+
+// splitCallingCode splits digits into a supported calling code and the
+// national number; calling codes are prefix-free, so at most one matches.
+func splitCallingCode(digits string) (string, string) {
+	for n := 1; n <= 3 && n < len(digits); n++ {
+		for _, p := range phonePlans {
+			if p.code == digits[:n] {
+				return digits[:n], digits[n:]
+			}
+		}
+	}
+	return "", ""
+}
+
+// phoneCountry picks the country of a number among those sharing its
+// calling code, preferring region.
+func phoneCountry(code, national string, region Country) Country {
+	switch code {
+	case "1":
+		if len(national) >= 3 && canadianAreaCodes[national[:3]] {
+			return "CA"
+		}
+		return "US"
+	case "7":
+		if strings.HasPrefix(national, "6") || strings.HasPrefix(national, "7") {
+			return "KZ"
+		}
+		return "RU"
+	}
+	if phonePlans[region].code == code {
+		return region
+	}
+	for country, p := range phonePlans {
+		if p.code == code {
+			return country
+		}
+	}
+	return ""
+}
+
+func (p phonePlan) valid(national string) bool {
+	return len(national) >= p.min && len(national) <= p.max
+}
+
+func (p phonePlan) classify(national string) PhoneType {
+	switch {
+	case p.tollFree != nil && p.tollFree.MatchString(national):
+		return PhoneTollFree
+	case p.mobile == nil:
+		return PhoneFixedLineOrMobile
+	case p.mobile.MatchString(national):
+		return PhoneMobile
+	}
+	return PhoneFixedLine
+}
+
+// Timezones returns the IANA zones of the country, in catalog order.
+func (c Country) Timezones() []string {
+	var zones []string
+	for _, zone := range timezoneCatalog {
+		if zone.Country == c {
+			zones = append(zones, zone.ID)
+		}
+	}
+	return zones
+}
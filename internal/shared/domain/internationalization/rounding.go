@@ -0,0 +1,434 @@
+// Package internationalization provides domain types for handling internationalization
+// concerns such as time, currency, timezone, and phone number formatting.
+//
+// This file defines the pluggable rounding and allocation strategies used by
+// Money. Both are interfaces with a name-keyed registry, so jurisdiction-specific
+// rules can be added without forking Money:
+//
+//	// Swiss cash payments round to 0.05 CHF
+//	chfCash := NewIncrementRounding("chf_cash", 5, RoundHalfUp)
+//	_ = RegisterRoundingMode(chfCash)
+//	price.Round(chfCash) // CHF 12.33 -> CHF 12.35
+//
+//	// A third of EUR 10.00, ties to even
+//	share, err := total.Divide(3, RoundHalfEven) // EUR 3.33
+//
+//	// Hamilton method instead of handing remainders to the first parts
+//	parts, err := total.AllocateWith(AllocateLargestRemainder, 1, 1, 1)
+//
+// Modes and strategies can then be looked up from configuration by name with
+// RoundingModeByName and AllocationStrategyByName.
+package internationalization
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"sort"
+	"sync"
+
+	"golang-arch/internal/shared/domain/domainerror"
+)
+
+// RoundingMode rounds an exact quotient to an integer number of minor units.
+type RoundingMode interface {
+	// Name identifies the mode in the registry and in configuration.
+	Name() string
+	// Round returns num/den rounded to an integer. den is always positive and
+	// neither argument may be modified.
+	Round(num, den *big.Int) *big.Int
+}
+
+// roundingMode implements the standard modes on top of truncated division.
+// away reports whether a truncated quotient should move one unit away from
+// zero, given the sign of the quotient, how twice the remainder compares with
+// the divisor (-1, 0, 1) and whether the truncated quotient is odd.
+type roundingMode struct {
+	name string
+	away func(sign, half int, odd bool) bool
+}
+
+func (r roundingMode) Name() string { return r.name }
+
+func (r roundingMode) Round(num, den *big.Int) *big.Int {
+	q, rem := new(big.Int).QuoRem(num, den, new(big.Int))
+	if rem.Sign() == 0 {
+		return q
+	}
+
+	sign := num.Sign()
+	half := new(big.Int).Abs(rem)
+	half.Lsh(half, 1)
+	if r.away(sign, half.Cmp(den), q.Bit(0) == 1) {
+		q.Add(q, big.NewInt(int64(sign)))
+	}
+	return q
+}
+
+// Built-in rounding modes
+var (
+	// RoundHalfUp rounds to the nearest unit, ties away from zero (commercial rounding).
+	RoundHalfUp RoundingMode = roundingMode{"half_up", func(_, half int, _ bool) bool { return half >= 0 }}
+	// RoundHalfDown rounds to the nearest unit, ties toward zero.
+	RoundHalfDown RoundingMode = roundingMode{"half_down", func(_, half int, _ bool) bool { return half > 0 }}
+	// RoundHalfEven rounds to the nearest unit, ties to the even unit (banker's rounding).
+	RoundHalfEven RoundingMode = roundingMode{"half_even", func(_, half int, odd bool) bool { return half > 0 || (half == 0 && odd) }}
+	// RoundUp rounds away from zero.
+	RoundUp RoundingMode = roundingMode{"up", func(int, int, bool) bool { return true }}
+	// RoundDown rounds toward zero (truncation).
+	RoundDown RoundingMode = roundingMode{"down", func(int, int, bool) bool { return false }}
+	// RoundCeiling rounds toward positive infinity.
+	RoundCeiling RoundingMode = roundingMode{"ceiling", func(sign, _ int, _ bool) bool { return sign > 0 }}
+	// RoundFloor rounds toward negative infinity.
+	RoundFloor RoundingMode = roundingMode{"floor", func(sign, _ int, _ bool) bool { return sign < 0 }}
+)
+
+// roundingFunc adapts a plain function to RoundingMode.
+type roundingFunc struct {
+	name string
+	fn   func(num, den *big.Int) *big.Int
+}
+
+func (r roundingFunc) Name() string                     { return r.name }
+func (r roundingFunc) Round(num, den *big.Int) *big.Int { return r.fn(num, den) }
+
+// NewRoundingMode returns a RoundingMode backed by fn, for rules that do not
+// fit the built-in modes (e.g. rounding tables that differ by amount band).
+func NewRoundingMode(name string, fn func(num, den *big.Int) *big.Int) RoundingMode {
+	return roundingFunc{name: name, fn: fn}
+}
+
+// incrementRounding rounds to a multiple of a fixed number of minor units.
+type incrementRounding struct {
+	name      string
+	increment *big.Int
+	mode      RoundingMode
+}
+
+func (r incrementRounding) Name() string { return r.name }
+
+func (r incrementRounding) Round(num, den *big.Int) *big.Int {
+	scaled := r.mode.Round(num, new(big.Int).Mul(den, r.increment))
+	return scaled.Mul(scaled, r.increment)
+}
+
+// NewIncrementRounding returns a mode that rounds to multiples of increment
+// minor units using mode for the final step. It models cash rounding, such as
+// 0.05 CHF (increment 5) or whole-krona SEK cash payments (increment 100).
+// It panics if increment is not positive.
+func NewIncrementRounding(name string, increment int64, mode RoundingMode) RoundingMode {
+	if increment <= 0 {
+		panic(fmt.Sprintf("internationalization: rounding increment must be positive, got %d", increment))
+	}
+	return incrementRounding{name: name, increment: big.NewInt(increment), mode: mode}
+}
+
+// roundQuotient returns num/den rounded with mode as an int64 amount.
+func roundQuotient(num, den *big.Int, mode RoundingMode) (int64, error) {
+	if mode == nil {
+		return 0, domainerror.Invalidf("rounding mode is required")
+	}
+	if den.Sign() == 0 {
+		return 0, domainerror.Invalidf("division by zero")
+	}
+	if den.Sign() < 0 {
+		num = new(big.Int).Neg(num)
+		den = new(big.Int).Neg(den)
+	}
+
+	result := mode.Round(num, den)
+	if !result.IsInt64() {
+		return 0, domainerror.Invalidf("integer overflow in money rounding")
+	}
+	return result.Int64(), nil
+}
+
+// Round applies mode to the amount and returns a new Money value. Plain modes
+// leave whole minor units unchanged; increment modes perform cash rounding.
+func (m Money) Round(mode RoundingMode) (*Money, error) {
+	amount, err := roundQuotient(big.NewInt(m.Amount), big.NewInt(1), mode)
+	if err != nil {
+		return nil, err
+	}
+	return NewMoneyFromInteger(amount, m.Currency)
+}
+
+// Divide divides the amount by divisor and rounds the exact quotient with
+// mode, e.g. RoundHalfEven where banker's rounding is required. Use Allocate
+// instead when the parts must add back up to the amount.
+func (m Money) Divide(divisor int64, mode RoundingMode) (*Money, error) {
+	amount, err := roundQuotient(big.NewInt(m.Amount), big.NewInt(divisor), mode)
+	if err != nil {
+		return nil, err
+	}
+	return NewMoneyFromInteger(amount, m.Currency)
+}
+
+// AllocationStrategy decides how an amount is split by ratios.
+type AllocationStrategy interface {
+	// Name identifies the strategy in the registry and in configuration.
+	Name() string
+	// Allocate splits amount into len(ratios) parts. Ratios are already
+	// validated: none is negative and they sum to total, which is positive.
+	// The parts must sum to amount.
+	Allocate(amount int64, ratios []int64, total int64) ([]int64, error)
+}
+
+// truncatedShares returns amount*ratio/total truncated toward zero for every
+// ratio, along with the exact remainders of each division.
+func truncatedShares(amount int64, ratios []int64, total int64) ([]int64, []*big.Int, error) {
+	shares := make([]int64, len(ratios))
+	remainders := make([]*big.Int, len(ratios))
+	bigTotal := big.NewInt(total)
+	for i, ratio := range ratios {
+		product := new(big.Int).Mul(big.NewInt(amount), big.NewInt(ratio))
+		q, r := product.QuoRem(product, bigTotal, new(big.Int))
+		if !q.IsInt64() {
+			return nil, nil, domainerror.Invalidf("integer overflow in money allocation")
+		}
+		shares[i] = q.Int64()
+		remainders[i] = r.Abs(r)
+	}
+	return shares, remainders, nil
+}
+
+// leftover returns the minor units not yet distributed and the unit (1 or -1)
+// used to hand them out, keeping the sign of the original amount.
+func leftover(amount int64, shares []int64) (int64, int64) {
+	remainder := amount
+	for _, share := range shares {
+		remainder -= share
+	}
+	if remainder < 0 {
+		return remainder, -1
+	}
+	return remainder, 1
+}
+
+type roundRobinAllocation struct{}
+
+func (roundRobinAllocation) Name() string { return "round_robin" }
+
+func (roundRobinAllocation) Allocate(amount int64, ratios []int64, total int64) ([]int64, error) {
+	shares, _, err := truncatedShares(amount, ratios, total)
+	if err != nil {
+		return nil, err
+	}
+
+	remainder, unit := leftover(amount, shares)
+	for i := 0; remainder != 0; i = (i + 1) % len(shares) {
+		if ratios[i] == 0 {
+			continue
+		}
+		shares[i] += unit
+		remainder -= unit
+	}
+	return shares, nil
+}
+
+type largestRemainderAllocation struct{}
+
+func (largestRemainderAllocation) Name() string { return "largest_remainder" }
+
+func (largestRemainderAllocation) Allocate(amount int64, ratios []int64, total int64) ([]int64, error) {
+	shares, remainders, err := truncatedShares(amount, ratios, total)
+	if err != nil {
+		return nil, err
+	}
+
+	order := make([]int, len(shares))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return remainders[order[a]].Cmp(remainders[order[b]]) > 0
+	})
+
+	// Each leftover unit comes from a non-zero remainder, so there are never
+	// more leftover units than parts with a fractional share
+	remainder, unit := leftover(amount, shares)
+	for _, i := range order {
+		if remainder == 0 {
+			break
+		}
+		shares[i] += unit
+		remainder -= unit
+	}
+	return shares, nil
+}
+
+type lastPartAllocation struct{}
+
+func (lastPartAllocation) Name() string { return "last" }
+
+func (lastPartAllocation) Allocate(amount int64, ratios []int64, total int64) ([]int64, error) {
+	shares, _, err := truncatedShares(amount, ratios, total)
+	if err != nil {
+		return nil, err
+	}
+
+	remainder, _ := leftover(amount, shares)
+	for i := len(ratios) - 1; i >= 0; i-- {
+		if ratios[i] != 0 {
+			shares[i] += remainder
+			break
+		}
+	}
+	return shares, nil
+}
+
+// Built-in allocation strategies
+var (
+	// AllocateRoundRobin hands leftover units one at a time to the parts in
+	// order; it is the strategy used by Money.Allocate.
+	AllocateRoundRobin AllocationStrategy = roundRobinAllocation{}
+	// AllocateLargestRemainder hands leftover units to the parts with the
+	// largest fractional shares (Hamilton method), ties going to earlier parts.
+	AllocateLargestRemainder AllocationStrategy = largestRemainderAllocation{}
+	// AllocateToLast adds all leftover units to the last part with a non-zero ratio.
+	AllocateToLast AllocationStrategy = lastPartAllocation{}
+)
+
+// AllocateWith splits the money into parts proportional to ratios using the
+// given strategy. Ratios are validated here, and the result is checked to
+// conserve the original amount, so custom strategies cannot create or lose
+// minor units.
+func (m Money) AllocateWith(strategy AllocationStrategy, ratios ...int64) ([]*Money, error) {
+	if strategy == nil {
+		return nil, domainerror.Invalidf("allocation strategy is required")
+	}
+	if len(ratios) == 0 {
+		return nil, domainerror.Invalidf("at least one ratio is required for allocation")
+	}
+
+	var total int64
+	for _, ratio := range ratios {
+		if ratio < 0 {
+			return nil, domainerror.Invalidf("allocation ratios cannot be negative: %d", ratio)
+		}
+		if total > math.MaxInt64-ratio {
+			return nil, domainerror.Invalidf("integer overflow in allocation ratios")
+		}
+		total += ratio
+	}
+	if total == 0 {
+		return nil, domainerror.Invalidf("allocation ratios cannot all be zero")
+	}
+
+	shares, err := strategy.Allocate(m.Amount, ratios, total)
+	if err != nil {
+		return nil, err
+	}
+	if len(shares) != len(ratios) {
+		return nil, fmt.Errorf("allocation strategy %q returned %d parts for %d ratios",
+			strategy.Name(), len(shares), len(ratios))
+	}
+
+	sum := new(big.Int)
+	parts := make([]*Money, len(shares))
+	for i, share := range shares {
+		sum.Add(sum, big.NewInt(share))
+		parts[i] = &Money{Amount: share, Currency: m.Currency}
+	}
+	if !sum.IsInt64() || sum.Int64() != m.Amount {
+		return nil, fmt.Errorf("allocation strategy %q does not conserve %d: parts sum to %s",
+			strategy.Name(), m.Amount, sum)
+	}
+
+	return parts, nil
+}
+
+// strategies holds the registered rounding modes and allocation strategies.
+var strategies = struct {
+	sync.RWMutex
+	rounding   map[string]RoundingMode
+	allocation map[string]AllocationStrategy
+}{
+	rounding:   map[string]RoundingMode{},
+	allocation: map[string]AllocationStrategy{},
+}
+
+func init() {
+	for _, mode := range []RoundingMode{
+		RoundHalfUp, RoundHalfDown, RoundHalfEven, RoundUp, RoundDown, RoundCeiling, RoundFloor,
+	} {
+		strategies.rounding[mode.Name()] = mode
+	}
+	for _, strategy := range []AllocationStrategy{
+		AllocateRoundRobin, AllocateLargestRemainder, AllocateToLast,
+	} {
+		strategies.allocation[strategy.Name()] = strategy
+	}
+}
+
+// RegisterRoundingMode makes mode available through RoundingModeByName.
+// Names must be unique, including against the built-in modes.
+func RegisterRoundingMode(mode RoundingMode) error {
+	if mode == nil || mode.Name() == "" {
+		return domainerror.Invalidf("rounding mode must have a name")
+	}
+
+	strategies.Lock()
+	defer strategies.Unlock()
+	if _, exists := strategies.rounding[mode.Name()]; exists {
+		return domainerror.Conflictf("rounding mode %q is already registered", mode.Name())
+	}
+	strategies.rounding[mode.Name()] = mode
+	return nil
+}
+
+// RoundingModeByName returns the registered rounding mode with the given name.
+func RoundingModeByName(name string) (RoundingMode, bool) {
+	strategies.RLock()
+	defer strategies.RUnlock()
+	mode, ok := strategies.rounding[name]
+	return mode, ok
+}
+
+// RoundingModes returns the names of all registered rounding modes, sorted.
+func RoundingModes() []string {
+	strategies.RLock()
+	defer strategies.RUnlock()
+	return sortedKeys(strategies.rounding)
+}
+
+// RegisterAllocationStrategy makes strategy available through
+// AllocationStrategyByName. Names must be unique, including against the
+// built-in strategies.
+func RegisterAllocationStrategy(strategy AllocationStrategy) error {
+	if strategy == nil || strategy.Name() == "" {
+		return domainerror.Invalidf("allocation strategy must have a name")
+	}
+
+	strategies.Lock()
+	defer strategies.Unlock()
+	if _, exists := strategies.allocation[strategy.Name()]; exists {
+		return domainerror.Conflictf("allocation strategy %q is already registered", strategy.Name())
+	}
+	strategies.allocation[strategy.Name()] = strategy
+	return nil
+}
+
+// AllocationStrategyByName returns the registered allocation strategy with the given name.
+func AllocationStrategyByName(name string) (AllocationStrategy, bool) {
+	strategies.RLock()
+	defer strategies.RUnlock()
+	strategy, ok := strategies.allocation[name]
+	return strategy, ok
+}
+
+// AllocationStrategies returns the names of all registered allocation strategies, sorted.
+func AllocationStrategies() []string {
+	strategies.RLock()
+	defer strategies.RUnlock()
+	return sortedKeys(strategies.allocation)
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,83 @@
+// Package internationalization provides domain types for handling internationalization
+// concerns such as time, currency, timezone, and phone number formatting.
+//
+// This file refines the JSON Schema generated for the value objects so the
+// published schemas carry the same constraints as Validate. Run
+// `golang-arch schema` to regenerate the documents after changing them.
+package internationalization
+
+import (
+	"time"
+
+	"golang-arch/internal/shared/schema"
+)
+
+// SchemaTypes lists the value objects published as JSON Schema documents and
+// GraphQL scalars
+var SchemaTypes = []any{Money{}, Phone{}, Timezone{}, LocalizedDateTime{}}
+
+// JSONSchemaExtend implements schema.Extender.
+func (c Currency) JSONSchemaExtend(s *schema.Schema) {
+	s.Description = "ISO 4217 currency with display metadata."
+	s.Properties["code"].Description = "ISO 4217 alphabetic code."
+	s.Properties["code"].Pattern = "^[A-Z]{3}$"
+	s.Properties["code"].Examples = []any{"USD"}
+	s.Properties["symbol"].MinLength = schema.Int(1)
+	s.Properties["name"].MinLength = schema.Int(1)
+	s.Properties["decimal_places"].Description = "Number of minor unit digits."
+	s.Properties["decimal_places"].Minimum = schema.Number(0)
+	s.Properties["decimal_places"].Maximum = schema.Number(18)
+}
+
+// JSONSchemaExtend implements schema.Extender.
+func (m Money) JSONSchemaExtend(s *schema.Schema) {
+	s.Description = "Monetary amount stored in the currency's minor units."
+	s.Properties["amount"].Description = "Amount in minor units, e.g. cents."
+	s.Properties["amount"].Examples = []any{10050}
+	s.Properties["decimal"] = &schema.Schema{
+		Type:        "number",
+		Description: "Amount in major units; informational, amount is authoritative.",
+		ReadOnly:    true,
+		Examples:    []any{100.5},
+	}
+}
+
+// JSONSchemaExtend implements schema.Extender.
+func (t Time) JSONSchemaExtend(s *schema.Schema) {
+	// Time is encoded as a bare epoch number rather than an object
+	*s = schema.Schema{
+		Type:        "integer",
+		Description: "Unix time in seconds.",
+		Minimum:     schema.Number(float64(time.Date(1970, 1, 1, 0, 0, 0, 0, time.UTC).Unix())),
+		Maximum:     schema.Number(float64(time.Date(2100, 12, 31, 23, 59, 59, 0, time.UTC).Unix())),
+		Examples:    []any{1703520000},
+	}
+}
+
+// JSONSchemaExtend implements schema.Extender.
+func (tz Timezone) JSONSchemaExtend(s *schema.Schema) {
+	s.Description = "IANA timezone with its display name and current UTC offset."
+	s.Properties["id"].Description = "IANA identifier."
+	s.Properties["id"].MinLength = schema.Int(1)
+	s.Properties["id"].Examples = []any{"America/New_York"}
+	s.Properties["name"].MinLength = schema.Int(1)
+	s.Properties["offset"].Description = "UTC offset in minutes."
+	s.Properties["offset"].Minimum = schema.Number(-1439)
+	s.Properties["offset"].Maximum = schema.Number(1439)
+}
+
+// JSONSchemaExtend implements schema.Extender.
+func (p Phone) JSONSchemaExtend(s *schema.Schema) {
+	s.Description = "Phone number split into country calling code and national number."
+	s.Properties["country_code"].Description = "Country calling code without the leading +."
+	s.Properties["country_code"].Pattern = `^[1-9]\d{0,2}$`
+	s.Properties["country_code"].Examples = []any{"1"}
+	s.Properties["number"].Description = "National number, digits only."
+	s.Properties["number"].Pattern = `^\d{7,15}$`
+	s.Properties["number"].Examples = []any{"5551234567"}
+}
+
+// JSONSchemaExtend implements schema.Extender.
+func (ldt LocalizedDateTime) JSONSchemaExtend(s *schema.Schema) {
+	s.Description = "Point in time together with the timezone it should be displayed in."
+}
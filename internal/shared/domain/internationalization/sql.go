@@ -0,0 +1,150 @@
+// Package internationalization provides domain types for handling internationalization
+// concerns such as time, currency, timezone, and phone number formatting.
+//
+// This file implements sql.Scanner and driver.Valuer, so the types are
+// written and read by database/sql and sqlx without ToPrimitive and
+// FromPrimitive calls. Each type is stored as its primitive:
+//
+//   - Currency: the ISO 4217 code, e.g. VARCHAR(3)
+//   - Phone: the international format, e.g. "+44 2079460958"
+//   - Timezone: the IANA ID
+//   - Time: the Unix epoch, BIGINT; TIMESTAMP columns are scanned as well
+//   - Money: the JSON object, for a JSON or JSONB column
+//
+// Money in the usual amount and currency column pair needs no adapter, as
+// its fields scan and bind directly:
+//
+//	row.Scan(&price.Amount, &price.Currency)
+//	db.Exec("INSERT INTO prices (amount, currency_code) VALUES ($1, $2)", price.Amount, price.Currency)
+//
+// Scanned values are validated as by the constructors. NULL is rejected;
+// nullable columns scan into sql.Null[Currency] and the like.
+package internationalization
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"strconv"
+	"time"
+
+	"golang-arch/internal/shared/domain/domainerror"
+)
+
+// Value stores the currency as its code
+func (c Currency) Value() (driver.Value, error) {
+	return c.Code, nil
+}
+
+// Scan reads a currency code
+func (c *Currency) Scan(src any) error {
+	code, err := scanString("currency", src)
+	if err != nil {
+		return err
+	}
+	currency, err := NewCurrencyFromCode(code)
+	if err != nil {
+		return err
+	}
+	*c = *currency
+	return nil
+}
+
+// Value stores the phone number in international format
+func (p Phone) Value() (driver.Value, error) {
+	return p.Format(), nil
+}
+
+// Scan reads a phone number in any format NewPhoneFromString accepts
+func (p *Phone) Scan(src any) error {
+	s, err := scanString("phone", src)
+	if err != nil {
+		return err
+	}
+	phone, err := NewPhoneFromString(s)
+	if err != nil {
+		return err
+	}
+	*p = *phone
+	return nil
+}
+
+// Value stores the timezone as its IANA ID
+func (tz Timezone) Value() (driver.Value, error) {
+	return tz.ID, nil
+}
+
+// Scan reads an IANA timezone ID
+func (tz *Timezone) Scan(src any) error {
+	id, err := scanString("timezone", src)
+	if err != nil {
+		return err
+	}
+	timezone, err := NewTimezoneFromID(id)
+	if err != nil {
+		return err
+	}
+	*tz = *timezone
+	return nil
+}
+
+// Value stores the time as its Unix epoch
+func (t Time) Value() (driver.Value, error) {
+	return t.Epoch, nil
+}
+
+// Scan reads a Unix epoch, or a timestamp, dropping sub-second precision
+func (t *Time) Scan(src any) error {
+	var epoch int64
+	switch v := src.(type) {
+	case int64:
+		epoch = v
+	case time.Time:
+		epoch = v.Unix()
+	default:
+		s, err := scanString("time", src)
+		if err != nil {
+			return err
+		}
+		if epoch, err = strconv.ParseInt(s, 10, 64); err != nil {
+			return domainerror.Invalidf("cannot scan %q as a Unix epoch", s)
+		}
+	}
+	parsed, err := NewTime(epoch)
+	if err != nil {
+		return err
+	}
+	*t = *parsed
+	return nil
+}
+
+// Value stores the money value as its JSON object. It is a string, as
+// drivers send bytes as binary rather than JSON.
+func (m Money) Value() (driver.Value, error) {
+	return string(m.AppendJSON(nil)), nil
+}
+
+// Scan reads a money JSON object, decoded as UnmarshalJSON does
+func (m *Money) Scan(src any) error {
+	s, err := scanString("money", src)
+	if err != nil {
+		return err
+	}
+	if !json.Valid([]byte(s)) {
+		return domainerror.Invalidf("cannot scan %q as money JSON", s)
+	}
+	return m.UnmarshalJSON([]byte(s))
+}
+
+// scanString accepts the text column values drivers return
+func scanString(kind string, src any) (string, error) {
+	switch v := src.(type) {
+	case string:
+		return v, nil
+	case []byte:
+		return string(v), nil
+	case nil:
+		return "", domainerror.Invalidf("cannot scan NULL into a %s", kind)
+	default:
+		return "", domainerror.Invalidf("cannot scan %T into a %s", src, kind)
+	}
+}
@@ -0,0 +1,253 @@
+// Package internationalization provides domain types and composite types for handling
+// internationalized data such as time, currency, timezone, and phone numbers.
+// This file contains the Subdivision type for ISO 3166-2 regions (states,
+// provinces, territories) and lookups over the embedded region metadata.
+//
+// Subdivision Type:
+//   - ISO 3166-2 code ("US-NY"), subdivision type and ISO name
+//   - Localized names by language ("Bayern" is "Bavaria" in English)
+//   - Lookups by full code, local code ("NY") or any name, ignoring case
+//     and accents ("quebec" finds "CA-QC")
+//   - Embedded data for a set of countries; see subdivision_data.go
+//
+// Database Storage: Stored as string (VARCHAR(6), the ISO 3166-2 code)
+// JSON Format: {"code": "CA-QC", "country": "CA", "type": "province", "name": "Quebec", "names": {"fr": "Québec"}}
+//
+// Usage Examples:
+//
+//	ny, ok := LookupSubdivision("US-NY")
+//	bavaria, ok := Country("DE").FindSubdivision("Bavaria")  // DE-BY
+//	bavaria.LocalizedName(MustParseLocale("en-GB"))         // "Bavaria"
+//	country, ok := FindCountry("United States")             // "US"
+//	err := phone.ValidateRegion()                           // "New York" is a US state
+package internationalization
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"golang-arch/internal/shared/domain/domainerror"
+	"golang-arch/internal/shared/domain/validation"
+)
+
+// Subdivision is a principal subdivision of a country as listed in ISO 3166-2.
+//
+// Example:
+//
+//	quebec, _ := LookupSubdivision("CA-QC")
+//	quebec.LocalizedName(MustParseLocale("fr-CA")) // "Québec"
+type Subdivision struct {
+	Code    string              `json:"code"`            // ISO 3166-2 code, e.g. "US-NY"
+	Country Country             `json:"country"`         // ISO 3166-1 alpha-2 code
+	Type    string              `json:"type"`            // ISO subdivision category, e.g. "state"
+	Name    string              `json:"name"`            // ISO 3166-2 name
+	Names   map[Language]string `json:"names,omitempty"` // Names in other languages
+}
+
+// LocalCode returns the part of the code after the country, e.g. "NY".
+func (s Subdivision) LocalCode() string {
+	return strings.TrimPrefix(s.Code, string(s.Country)+"-")
+}
+
+// LocalizedName returns the name in the locale's language, or the ISO name
+// when there is no translation.
+func (s Subdivision) LocalizedName(locale Locale) string {
+	if name, ok := s.Names[locale.Language()]; ok {
+		return name
+	}
+	return s.Name
+}
+
+// Matches reports whether value is the subdivision's code, local code or one
+// of its names, ignoring case, accents and extra whitespace.
+func (s Subdivision) Matches(value string) bool {
+	key := SearchKey(value)
+	if key == "" {
+		return false
+	}
+	if key == SearchKey(s.Code) || key == SearchKey(s.LocalCode()) || key == SearchKey(s.Name) {
+		return true
+	}
+	for _, name := range s.Names {
+		if key == SearchKey(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// Validate ensures the subdivision has a well-formed code for its country and
+// a name.
+func (s Subdivision) Validate() error {
+	var errs validation.ValidationErrors
+	errs.Merge("country", "invalid country in subdivision", s.Country.Validate())
+	local := s.LocalCode()
+	if !strings.HasPrefix(s.Code, string(s.Country)+"-") || local == "" || len(local) > 3 {
+		errs.Add("code", validation.CodeInvalidFormat,
+			fmt.Sprintf("invalid subdivision code %q (expected ISO 3166-2, e.g. %s-NY)", s.Code, s.Country), nil)
+	}
+	if s.Name == "" {
+		errs.Add("name", validation.CodeRequired, "subdivision name cannot be empty", nil)
+	}
+	return errs.Err()
+}
+
+// String returns the ISO 3166-2 code.
+func (s Subdivision) String() string {
+	return s.Code
+}
+
+// MatchSubdivision returns the first subdivision in subdivisions that matches
+// value; see Subdivision.Matches.
+func MatchSubdivision(subdivisions []Subdivision, value string) (Subdivision, bool) {
+	for _, subdivision := range subdivisions {
+		if subdivision.Matches(value) {
+			return subdivision, true
+		}
+	}
+	return Subdivision{}, false
+}
+
+// LookupSubdivision returns the embedded subdivision with the given ISO
+// 3166-2 code, in any casing.
+func LookupSubdivision(code string) (Subdivision, bool) {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	country, _, found := strings.Cut(code, "-")
+	if !found {
+		return Subdivision{}, false
+	}
+	for _, subdivision := range Country(country).Subdivisions() {
+		if subdivision.Code == code {
+			return subdivision, true
+		}
+	}
+	return Subdivision{}, false
+}
+
+// Subdivisions returns the embedded subdivisions of the country ordered by
+// code, or nil when the country has no embedded data.
+func (c Country) Subdivisions() []Subdivision {
+	subdivisions := loadSubdivisions()[c]
+	if len(subdivisions) == 0 {
+		return nil
+	}
+	return append([]Subdivision(nil), subdivisions...)
+}
+
+// HasSubdivisions reports whether the country has embedded subdivision data,
+// i.e. whether its regions can be validated offline.
+func (c Country) HasSubdivisions() bool {
+	return len(loadSubdivisions()[c]) > 0
+}
+
+// FindSubdivision returns the country's embedded subdivision matching a code
+// or name, e.g. "NY", "US-NY" or "new york".
+func (c Country) FindSubdivision(value string) (Subdivision, bool) {
+	return MatchSubdivision(loadSubdivisions()[c], value)
+}
+
+// FindCountry resolves a country code or a country name known to the
+// embedded data ("United States", "Deutschland"), ignoring case and accents.
+// Names win over codes, so "UK" resolves to GB.
+func FindCountry(value string) (Country, bool) {
+	key := SearchKey(value)
+	for _, country := range sortedCountries(countryNames) {
+		for _, name := range countryNames[country] {
+			if key == SearchKey(name) {
+				return country, true
+			}
+		}
+	}
+	if country, err := ParseCountry(value); err == nil {
+		return country, true
+	}
+	return "", false
+}
+
+// ValidateRegion checks Region against the embedded subdivisions of Country,
+// which may be a code or a name. Regions of countries without embedded data
+// cannot be checked and are accepted, as is an empty Region.
+func (lp LocalizedPhone) ValidateRegion() error {
+	if lp.Region == "" {
+		return nil
+	}
+	country, ok := FindCountry(lp.Country)
+	if !ok || !country.HasSubdivisions() {
+		return nil
+	}
+	if _, ok := country.FindSubdivision(lp.Region); ok {
+		return nil
+	}
+
+	var errs validation.ValidationErrors
+	errs.Add("region", validation.CodeInvalid,
+		fmt.Sprintf("%q is not a known region of %s", lp.Region, country), map[string]any{"country": string(country)})
+	return errs.Err()
+}
+
+// NewSubdivisionFromPrimitive returns the embedded subdivision stored as its
+// ISO 3166-2 code.
+func NewSubdivisionFromPrimitive(code string) (*Subdivision, error) {
+	subdivision, ok := LookupSubdivision(code)
+	if !ok {
+		return nil, domainerror.NotFoundf("unknown subdivision code %q", code)
+	}
+	return &subdivision, nil
+}
+
+// ToPrimitive returns the ISO 3166-2 code for storage.
+func (s Subdivision) ToPrimitive() string {
+	return s.Code
+}
+
+var (
+	subdivisionsOnce sync.Once
+	subdivisionIndex map[Country][]Subdivision
+)
+
+// loadSubdivisions expands subdivisionData into Subdivision values once.
+func loadSubdivisions() map[Country][]Subdivision {
+	subdivisionsOnce.Do(func() {
+		subdivisionIndex = make(map[Country][]Subdivision, len(subdivisionData))
+		for country, rows := range subdivisionData {
+			subdivisions := make([]Subdivision, 0, len(rows))
+			for _, row := range rows {
+				subdivisions = append(subdivisions, row.subdivision(country))
+			}
+			sort.Slice(subdivisions, func(i, j int) bool {
+				return subdivisions[i].Code < subdivisions[j].Code
+			})
+			subdivisionIndex[country] = subdivisions
+		}
+	})
+	return subdivisionIndex
+}
+
+// subdivision builds the Subdivision of a data row.
+func (r subdivisionRow) subdivision(country Country) Subdivision {
+	s := Subdivision{
+		Code:    string(country) + "-" + r.code,
+		Country: country,
+		Type:    r.kind,
+		Name:    r.name,
+	}
+	if len(r.names) > 0 {
+		s.Names = make(map[Language]string, len(r.names)/2)
+		for i := 0; i+1 < len(r.names); i += 2 {
+			s.Names[Language(r.names[i])] = r.names[i+1]
+		}
+	}
+	return s
+}
+
+// sortedCountries returns the keys of m in order, for deterministic lookups.
+func sortedCountries[V any](m map[Country]V) []Country {
+	countries := make([]Country, 0, len(m))
+	for country := range m {
+		countries = append(countries, country)
+	}
+	sort.Slice(countries, func(i, j int) bool { return countries[i] < countries[j] })
+	return countries
+}
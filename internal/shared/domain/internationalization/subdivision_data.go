@@ -0,0 +1,211 @@
+// Package internationalization provides domain types and composite types for handling
+// internationalized data such as time, currency, timezone, and phone numbers.
+// This file contains the embedded ISO 3166-2 region metadata.
+//
+// Embedded Data:
+//   - Principal subdivisions (first level) only; GB lists its four countries
+//     rather than its ~200 council areas
+//   - Names as published in ISO 3166-2, with English and local-language
+//     alternatives where they differ
+//   - Countries without data can be served remotely by the regions service
+package internationalization
+
+// subdivisionRow is one embedded subdivision: the local part of its code,
+// its ISO category and name, and alternating language/name pairs
+type subdivisionRow struct {
+	code  string
+	kind  string
+	name  string
+	names []string
+}
+
+// countryNames lists the names FindCountry accepts, English first
+var countryNames = map[Country][]string{
+	"AU": {"Australia"},
+	"BR": {"Brazil", "Brasil"},
+	"CA": {"Canada"},
+	"DE": {"Germany", "Deutschland"},
+	"GB": {"United Kingdom", "Great Britain", "UK"},
+	"ID": {"Indonesia"},
+	"US": {"United States", "United States of America", "USA"},
+}
+
+// subdivisionData holds the embedded subdivisions by country
+var subdivisionData = map[Country][]subdivisionRow{
+	"AU": {
+		{"ACT", "territory", "Australian Capital Territory", nil},
+		{"NSW", "state", "New South Wales", nil},
+		{"NT", "territory", "Northern Territory", nil},
+		{"QLD", "state", "Queensland", nil},
+		{"SA", "state", "South Australia", nil},
+		{"TAS", "state", "Tasmania", nil},
+		{"VIC", "state", "Victoria", nil},
+		{"WA", "state", "Western Australia", nil},
+	},
+	"BR": {
+		{"AC", "state", "Acre", nil},
+		{"AL", "state", "Alagoas", nil},
+		{"AM", "state", "Amazonas", nil},
+		{"AP", "state", "Amapá", nil},
+		{"BA", "state", "Bahia", nil},
+		{"CE", "state", "Ceará", nil},
+		{"DF", "federal district", "Distrito Federal", []string{"en", "Federal District"}},
+		{"ES", "state", "Espírito Santo", nil},
+		{"GO", "state", "Goiás", nil},
+		{"MA", "state", "Maranhão", nil},
+		{"MG", "state", "Minas Gerais", nil},
+		{"MS", "state", "Mato Grosso do Sul", nil},
+		{"MT", "state", "Mato Grosso", nil},
+		{"PA", "state", "Pará", nil},
+		{"PB", "state", "Paraíba", nil},
+		{"PE", "state", "Pernambuco", nil},
+		{"PI", "state", "Piauí", nil},
+		{"PR", "state", "Paraná", nil},
+		{"RJ", "state", "Rio de Janeiro", nil},
+		{"RN", "state", "Rio Grande do Norte", nil},
+		{"RO", "state", "Rondônia", nil},
+		{"RR", "state", "Roraima", nil},
+		{"RS", "state", "Rio Grande do Sul", nil},
+		{"SC", "state", "Santa Catarina", nil},
+		{"SE", "state", "Sergipe", nil},
+		{"SP", "state", "São Paulo", nil},
+		{"TO", "state", "Tocantins", nil},
+	},
+	"CA": {
+		{"AB", "province", "Alberta", nil},
+		{"BC", "province", "British Columbia", []string{"fr", "Colombie-Britannique"}},
+		{"MB", "province", "Manitoba", nil},
+		{"NB", "province", "New Brunswick", []string{"fr", "Nouveau-Brunswick"}},
+		{"NL", "province", "Newfoundland and Labrador", []string{"fr", "Terre-Neuve-et-Labrador"}},
+		{"NS", "province", "Nova Scotia", []string{"fr", "Nouvelle-Écosse"}},
+		{"NT", "territory", "Northwest Territories", []string{"fr", "Territoires du Nord-Ouest"}},
+		{"NU", "territory", "Nunavut", nil},
+		{"ON", "province", "Ontario", nil},
+		{"PE", "province", "Prince Edward Island", []string{"fr", "Île-du-Prince-Édouard"}},
+		{"QC", "province", "Quebec", []string{"fr", "Québec"}},
+		{"SK", "province", "Saskatchewan", nil},
+		{"YT", "territory", "Yukon", nil},
+	},
+	"DE": {
+		{"BB", "state", "Brandenburg", nil},
+		{"BE", "state", "Berlin", nil},
+		{"BW", "state", "Baden-Württemberg", nil},
+		{"BY", "state", "Bayern", []string{"en", "Bavaria"}},
+		{"HB", "state", "Bremen", nil},
+		{"HE", "state", "Hessen", []string{"en", "Hesse"}},
+		{"HH", "state", "Hamburg", nil},
+		{"MV", "state", "Mecklenburg-Vorpommern", []string{"en", "Mecklenburg-Western Pomerania"}},
+		{"NI", "state", "Niedersachsen", []string{"en", "Lower Saxony"}},
+		{"NW", "state", "Nordrhein-Westfalen", []string{"en", "North Rhine-Westphalia"}},
+		{"RP", "state", "Rheinland-Pfalz", []string{"en", "Rhineland-Palatinate"}},
+		{"SH", "state", "Schleswig-Holstein", nil},
+		{"SL", "state", "Saarland", nil},
+		{"SN", "state", "Sachsen", []string{"en", "Saxony"}},
+		{"ST", "state", "Sachsen-Anhalt", []string{"en", "Saxony-Anhalt"}},
+		{"TH", "state", "Thüringen", []string{"en", "Thuringia"}},
+	},
+	"GB": {
+		{"ENG", "country", "England", nil},
+		{"NIR", "province", "Northern Ireland", nil},
+		{"SCT", "country", "Scotland", []string{"gd", "Alba"}},
+		{"WLS", "country", "Wales", []string{"cy", "Cymru"}},
+	},
+	"ID": {
+		{"AC", "province", "Aceh", nil},
+		{"BA", "province", "Bali", nil},
+		{"BB", "province", "Kepulauan Bangka Belitung", []string{"en", "Bangka Belitung Islands"}},
+		{"BE", "province", "Bengkulu", nil},
+		{"BT", "province", "Banten", nil},
+		{"GO", "province", "Gorontalo", nil},
+		{"JA", "province", "Jambi", nil},
+		{"JB", "province", "Jawa Barat", []string{"en", "West Java"}},
+		{"JI", "province", "Jawa Timur", []string{"en", "East Java"}},
+		{"JK", "capital district", "Jakarta Raya", []string{"en", "Jakarta", "id", "DKI Jakarta"}},
+		{"JT", "province", "Jawa Tengah", []string{"en", "Central Java"}},
+		{"KB", "province", "Kalimantan Barat", []string{"en", "West Kalimantan"}},
+		{"KI", "province", "Kalimantan Timur", []string{"en", "East Kalimantan"}},
+		{"KR", "province", "Kepulauan Riau", []string{"en", "Riau Islands"}},
+		{"KS", "province", "Kalimantan Selatan", []string{"en", "South Kalimantan"}},
+		{"KT", "province", "Kalimantan Tengah", []string{"en", "Central Kalimantan"}},
+		{"KU", "province", "Kalimantan Utara", []string{"en", "North Kalimantan"}},
+		{"LA", "province", "Lampung", nil},
+		{"MA", "province", "Maluku", nil},
+		{"MU", "province", "Maluku Utara", []string{"en", "North Maluku"}},
+		{"NB", "province", "Nusa Tenggara Barat", []string{"en", "West Nusa Tenggara"}},
+		{"NT", "province", "Nusa Tenggara Timur", []string{"en", "East Nusa Tenggara"}},
+		{"PA", "province", "Papua", nil},
+		{"PB", "province", "Papua Barat", []string{"en", "West Papua"}},
+		{"PD", "province", "Papua Barat Daya", []string{"en", "Southwest Papua"}},
+		{"PE", "province", "Papua Pegunungan", []string{"en", "Highland Papua"}},
+		{"PS", "province", "Papua Selatan", []string{"en", "South Papua"}},
+		{"PT", "province", "Papua Tengah", []string{"en", "Central Papua"}},
+		{"RI", "province", "Riau", nil},
+		{"SA", "province", "Sulawesi Utara", []string{"en", "North Sulawesi"}},
+		{"SB", "province", "Sumatera Barat", []string{"en", "West Sumatra"}},
+		{"SG", "province", "Sulawesi Tenggara", []string{"en", "Southeast Sulawesi"}},
+		{"SN", "province", "Sulawesi Selatan", []string{"en", "South Sulawesi"}},
+		{"SR", "province", "Sulawesi Barat", []string{"en", "West Sulawesi"}},
+		{"SS", "province", "Sumatera Selatan", []string{"en", "South Sumatra"}},
+		{"ST", "province", "Sulawesi Tengah", []string{"en", "Central Sulawesi"}},
+		{"SU", "province", "Sumatera Utara", []string{"en", "North Sumatra"}},
+		{"YO", "special region", "Daerah Istimewa Yogyakarta", []string{"en", "Yogyakarta"}},
+	},
+	"US": {
+		{"AK", "state", "Alaska", nil},
+		{"AL", "state", "Alabama", nil},
+		{"AR", "state", "Arkansas", nil},
+		{"AS", "outlying area", "American Samoa", nil},
+		{"AZ", "state", "Arizona", nil},
+		{"CA", "state", "California", nil},
+		{"CO", "state", "Colorado", nil},
+		{"CT", "state", "Connecticut", nil},
+		{"DC", "district", "District of Columbia", []string{"en", "Washington, D.C."}},
+		{"DE", "state", "Delaware", nil},
+		{"FL", "state", "Florida", nil},
+		{"GA", "state", "Georgia", nil},
+		{"GU", "outlying area", "Guam", nil},
+		{"HI", "state", "Hawaii", nil},
+		{"IA", "state", "Iowa", nil},
+		{"ID", "state", "Idaho", nil},
+		{"IL", "state", "Illinois", nil},
+		{"IN", "state", "Indiana", nil},
+		{"KS", "state", "Kansas", nil},
+		{"KY", "state", "Kentucky", nil},
+		{"LA", "state", "Louisiana", nil},
+		{"MA", "state", "Massachusetts", nil},
+		{"MD", "state", "Maryland", nil},
+		{"ME", "state", "Maine", nil},
+		{"MI", "state", "Michigan", nil},
+		{"MN", "state", "Minnesota", nil},
+		{"MO", "state", "Missouri", nil},
+		{"MP", "outlying area", "Northern Mariana Islands", nil},
+		{"MS", "state", "Mississippi", nil},
+		{"MT", "state", "Montana", nil},
+		{"NC", "state", "North Carolina", nil},
+		{"ND", "state", "North Dakota", nil},
+		{"NE", "state", "Nebraska", nil},
+		{"NH", "state", "New Hampshire", nil},
+		{"NJ", "state", "New Jersey", nil},
+		{"NM", "state", "New Mexico", nil},
+		{"NV", "state", "Nevada", nil},
+		{"NY", "state", "New York", nil},
+		{"OH", "state", "Ohio", nil},
+		{"OK", "state", "Oklahoma", nil},
+		{"OR", "state", "Oregon", nil},
+		{"PA", "state", "Pennsylvania", nil},
+		{"PR", "outlying area", "Puerto Rico", nil},
+		{"RI", "state", "Rhode Island", nil},
+		{"SC", "state", "South Carolina", nil},
+		{"SD", "state", "South Dakota", nil},
+		{"TN", "state", "Tennessee", nil},
+		{"TX", "state", "Texas", nil},
+		{"UT", "state", "Utah", nil},
+		{"VA", "state", "Virginia", nil},
+		{"VI", "outlying area", "Virgin Islands, U.S.", []string{"en", "U.S. Virgin Islands"}},
+		{"VT", "state", "Vermont", nil},
+		{"WA", "state", "Washington", nil},
+		{"WI", "state", "Wisconsin", nil},
+		{"WV", "state", "West Virginia", nil},
+		{"WY", "state", "Wyoming", nil},
+	},
+}
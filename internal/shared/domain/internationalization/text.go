@@ -0,0 +1,111 @@
+// Package internationalization provides domain types and composite types for handling
+// internationalized data such as time, currency, timezone, and phone numbers.
+// This file contains Unicode text normalization and comparison helpers.
+//
+// Normalization:
+//   - NFC for storage: "é" typed as e + combining accent equals precomposed "é"
+//   - NFKC for identifiers: compatibility forms fold ("ﬁ" -> "fi", full-width
+//     "ＡＢＣ" -> "ABC")
+//   - Full Unicode case folding ("Straße" and "STRASSE" fold alike)
+//   - Accent removal that keeps the script ("José" -> "Jose", "Ёлка" -> "Елка")
+//
+// Comparison:
+//   - EqualFold: case-insensitive, accent-sensitive
+//   - EqualIgnoringAccents and SearchKey: case- and accent-insensitive, for
+//     search and deduplication
+//
+// Usage Examples:
+//
+//	EqualIgnoringAccents("José", "JOSE")             // true
+//	SearchKey("  Crème   Brûlée ")                   // "creme brulee"
+//	ContainsIgnoringAccents("Café de Flore", "cafe") // true
+package internationalization
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/cases"
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+// accentFolds maps Latin letters whose accent-like marks are not combining
+// characters, so NFD cannot strip them
+var accentFolds = map[rune]string{
+	'ł': "l", 'Ł': "L", 'ø': "o", 'Ø': "O", 'đ': "d", 'Đ': "D", 'ħ': "h", 'Ħ': "H", 'ı': "i",
+}
+
+// NormalizeNFC returns s in Unicode Normalization Form C, the form text
+// should be stored in.
+func NormalizeNFC(s string) string {
+	return norm.NFC.String(s)
+}
+
+// NormalizeNFKC returns s in Unicode Normalization Form KC, which also folds
+// compatibility characters such as ligatures and full-width letters. Use it
+// for identifiers and search, not for text shown back to users.
+func NormalizeNFKC(s string) string {
+	return norm.NFKC.String(s)
+}
+
+// FoldCase returns the Unicode case folding of s, for caseless comparison.
+// Unlike strings.ToLower it folds "ß" to "ss".
+func FoldCase(s string) string {
+	// Casers keep state and must not be shared between goroutines
+	return cases.Fold().String(s)
+}
+
+// RemoveAccents strips combining marks and folds Latin letters with
+// non-combining marks ("ł", "ø"), returning the result in NFC. Letters of
+// other scripts are kept, so "Ёлка" becomes "Елка", not "Elka".
+func RemoveAccents(s string) string {
+	t := transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC)
+	stripped, _, err := transform.String(t, s)
+	if err != nil {
+		// Not reachable for valid transformers; keep the input unchanged
+		return s
+	}
+
+	if strings.IndexFunc(stripped, func(r rune) bool { _, ok := accentFolds[r]; return ok }) < 0 {
+		return stripped
+	}
+	var b strings.Builder
+	b.Grow(len(stripped))
+	for _, r := range stripped {
+		if folded, ok := accentFolds[r]; ok {
+			b.WriteString(folded)
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// SearchKey returns the case- and accent-insensitive comparison key of s:
+// NFKC-normalized, accents removed, case folded, with runs of white space
+// collapsed to one space and the ends trimmed. Store it next to the original
+// text to index it for search and to detect duplicates.
+func SearchKey(s string) string {
+	key := FoldCase(RemoveAccents(NormalizeNFKC(s)))
+	return strings.Join(strings.Fields(key), " ")
+}
+
+// EqualFold reports whether a and b are equal under NFKC normalization and
+// Unicode case folding. Accents still matter: "José" does not equal "Jose".
+func EqualFold(a, b string) bool {
+	return FoldCase(NormalizeNFKC(a)) == FoldCase(NormalizeNFKC(b))
+}
+
+// EqualIgnoringAccents reports whether a and b have the same SearchKey, so
+// "José" equals "JOSE" and "Straße" equals "strasse".
+func EqualIgnoringAccents(a, b string) bool {
+	return SearchKey(a) == SearchKey(b)
+}
+
+// ContainsIgnoringAccents reports whether the SearchKey of needle occurs in
+// the SearchKey of haystack.
+func ContainsIgnoringAccents(haystack, needle string) bool {
+	return strings.Contains(SearchKey(haystack), SearchKey(needle))
+}
@@ -8,6 +8,9 @@ package internationalization
 import (
 	"fmt"
 	"time"
+
+	"golang-arch/internal/shared/domain/domainerror"
+	"golang-arch/internal/shared/domain/validation"
 )
 
 // Time represents a point in time using epoch time (Unix timestamp in seconds).
@@ -30,18 +33,19 @@ func NewTime(epoch int64) (*Time, error) {
 	return t, nil
 }
 
-// NewTimeFromTime creates a new Time instance from time.Time.
+// NewTimeFromTime creates a new Time instance from time.Time, dropping any
+// sub-second precision. Use TimePolicy.FromTime to detect or round it.
 func NewTimeFromTime(t time.Time) *Time {
 	return &Time{Epoch: t.Unix()}
 }
 
 // ToTime converts Time to time.Time.
-func (t *Time) ToTime() time.Time {
+func (t Time) ToTime() time.Time {
 	return time.Unix(t.Epoch, 0)
 }
 
 // ToPrimitive returns the primitive value for database storage.
-func (t *Time) ToPrimitive() int64 {
+func (t Time) ToPrimitive() int64 {
 	return t.Epoch
 }
 
@@ -51,50 +55,48 @@ func FromPrimitive(epoch int64) (*Time, error) {
 }
 
 // Validate ensures the epoch time is within a reasonable range.
-func (t *Time) Validate() error {
+func (t Time) Validate() error {
 	// Check if epoch is within reasonable range (1970-2100)
 	minEpoch := time.Date(1970, 1, 1, 0, 0, 0, 0, time.UTC).Unix()
 	maxEpoch := time.Date(2100, 12, 31, 23, 59, 59, 0, time.UTC).Unix()
 
+	var errs validation.ValidationErrors
+	params := map[string]any{"min": minEpoch, "max": maxEpoch, "actual": t.Epoch}
+
 	if t.Epoch < minEpoch {
-		return fmt.Errorf("epoch time too early: %d (minimum: %d)", t.Epoch, minEpoch)
+		errs.Add("epoch", validation.CodeOutOfRange,
+			fmt.Sprintf("epoch time too early: %d (minimum: %d)", t.Epoch, minEpoch), params)
 	}
 	if t.Epoch > maxEpoch {
-		return fmt.Errorf("epoch time too late: %d (maximum: %d)", t.Epoch, maxEpoch)
+		errs.Add("epoch", validation.CodeOutOfRange,
+			fmt.Sprintf("epoch time too late: %d (maximum: %d)", t.Epoch, maxEpoch), params)
 	}
 
-	return nil
+	return errs.Err()
 }
 
 // Format formats the time according to the specified layout and timezone.
 // If timezone is nil, UTC is used.
-func (t *Time) Format(layout string, tz *Timezone) string {
-	timeValue := t.ToTime()
-
-	if tz != nil {
-		// Create a fixed timezone using the offset
-		loc := time.FixedZone(tz.ID, tz.Offset*60) // Convert minutes to seconds
-		timeValue = timeValue.In(loc)
-	} else {
-		// Use UTC when timezone is nil
-		timeValue = timeValue.UTC()
-	}
+func (t Time) Format(layout string, tz *Timezone) string {
+	buf := getFormatBuffer()
+	defer putFormatBuffer(buf)
 
-	return timeValue.Format(layout)
+	*buf = t.AppendFormat(*buf, layout, tz)
+	return string(*buf)
 }
 
 // FormatUTC formats the time in UTC timezone.
-func (t *Time) FormatUTC(layout string) string {
+func (t Time) FormatUTC(layout string) string {
 	return t.ToTime().UTC().Format(layout)
 }
 
 // FormatLocal formats the time in local timezone.
-func (t *Time) FormatLocal(layout string) string {
+func (t Time) FormatLocal(layout string) string {
 	return t.ToTime().Local().Format(layout)
 }
 
 // IsZero returns true if the time is zero (epoch 0).
-func (t *Time) IsZero() bool {
+func (t Time) IsZero() bool {
 	return t.Epoch == 0
 }
 
@@ -107,36 +109,31 @@ func (t *Time) Equal(other *Time) bool {
 }
 
 // Before returns true if this time is before the other time.
-func (t *Time) Before(other *Time) bool {
+func (t Time) Before(other *Time) bool {
 	return t.Epoch < other.Epoch
 }
 
 // After returns true if this time is after the other time.
-func (t *Time) After(other *Time) bool {
+func (t Time) After(other *Time) bool {
 	return t.Epoch > other.Epoch
 }
 
 // Add adds a duration to the time.
-func (t *Time) Add(d time.Duration) *Time {
+func (t Time) Add(d time.Duration) *Time {
 	newTime := t.ToTime().Add(d)
 	return NewTimeFromTime(newTime)
 }
 
 // Sub returns the duration between this time and another time.
-func (t *Time) Sub(other *Time) time.Duration {
+func (t Time) Sub(other *Time) time.Duration {
 	return t.ToTime().Sub(other.ToTime())
 }
 
 // String returns the time formatted as RFC3339 string.
-func (t *Time) String() string {
+func (t Time) String() string {
 	return t.Format(time.RFC3339, nil)
 }
 
-// MarshalJSON implements json.Marshaler interface.
-func (t *Time) MarshalJSON() ([]byte, error) {
-	return []byte(fmt.Sprintf("%d", t.Epoch)), nil
-}
-
 // UnmarshalJSON implements json.Unmarshaler interface.
 func (t *Time) UnmarshalJSON(data []byte) error {
 	// Remove quotes if present
@@ -148,7 +145,7 @@ func (t *Time) UnmarshalJSON(data []byte) error {
 	var epoch int64
 	_, err := fmt.Sscanf(epochStr, "%d", &epoch)
 	if err != nil {
-		return fmt.Errorf("invalid epoch time: %s", string(data))
+		return domainerror.Invalidf("invalid epoch time: %s", string(data))
 	}
 
 	t.Epoch = epoch
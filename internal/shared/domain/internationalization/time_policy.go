@@ -0,0 +1,160 @@
+// Package internationalization provides domain types and composite types for handling
+// internationalized data such as time, currency, timezone, and phone numbers.
+// This file contains the TimePolicy type, which makes explicit how instants
+// with sub-second precision or leap seconds become a Time.
+//
+// Time stores whole seconds of Unix time, which has no leap seconds. Converting
+// into it can therefore lose information in two ways:
+//   - Sub-second precision: "12:00:00.750Z" has no exact Time
+//   - Leap seconds: "23:59:60Z" is not representable in Unix time (Go's
+//     time.Parse rejects it)
+//
+// TimePolicy decides, per caller, whether those cases fail or are adjusted.
+// DefaultTimePolicy rejects both, so no precision is ever lost silently.
+// Clocks that smear leap seconds (Google, AWS and other NTP smearing) never
+// produce ":60"; their timestamps are already off by up to a second during
+// the smear window, which no policy can detect.
+//
+// JSON Format: {"sub_second": "reject", "leap_second": "reject"}
+//
+// Usage Examples:
+//
+//	t, err := DefaultTimePolicy.ParseRFC3339Nano("2024-05-17T09:30:00.250Z")  // error
+//	audit := TimePolicy{SubSecond: SubSecondTruncate, LeapSecond: LeapSecondClamp}
+//	t, err = audit.ParseRFC3339Nano("2016-12-31T23:59:60.5Z")                 // 23:59:59Z
+//	t.FormatRFC3339Nano()                                                     // "2016-12-31T23:59:59Z"
+package internationalization
+
+import (
+	"fmt"
+	"regexp"
+	"time"
+
+	"golang-arch/internal/shared/domain/domainerror"
+	"golang-arch/internal/shared/domain/validation"
+)
+
+// SubSecondHandling decides what happens to fractional seconds.
+type SubSecondHandling string
+
+// Sub-second handling modes
+const (
+	SubSecondReject   SubSecondHandling = "reject"   // Fail unless the fraction is zero
+	SubSecondTruncate SubSecondHandling = "truncate" // Drop the fraction (towards the past)
+	SubSecondRound    SubSecondHandling = "round"    // Round to the nearest second, halves up
+)
+
+// LeapSecondHandling decides what happens to a leap second (":60").
+type LeapSecondHandling string
+
+// Leap second handling modes
+const (
+	LeapSecondReject LeapSecondHandling = "reject" // Fail on ":60"
+	LeapSecondClamp  LeapSecondHandling = "clamp"  // Map 23:59:60 to 23:59:59
+	LeapSecondRoll   LeapSecondHandling = "roll"   // Map 23:59:60 to 00:00:00 of the next day
+)
+
+// TimePolicy is an explicit conversion policy into Time.
+//
+// Example:
+//
+//	policy := TimePolicy{SubSecond: SubSecondRound, LeapSecond: LeapSecondReject}
+//	t, err := policy.FromTime(event.OccurredAt)
+type TimePolicy struct {
+	SubSecond  SubSecondHandling  `json:"sub_second"`
+	LeapSecond LeapSecondHandling `json:"leap_second"`
+}
+
+// DefaultTimePolicy rejects anything Time cannot represent exactly.
+var DefaultTimePolicy = TimePolicy{SubSecond: SubSecondReject, LeapSecond: LeapSecondReject}
+
+// leapSecondPattern matches the seconds field of an RFC 3339 leap second
+var leapSecondPattern = regexp.MustCompile(`^(\d{4}-\d{2}-\d{2}[Tt]\d{2}:\d{2}:)60([.,]\d+)?([Zz]|[+-]\d{2}:\d{2})$`)
+
+// Validate ensures both handling modes are known.
+func (p TimePolicy) Validate() error {
+	var errs validation.ValidationErrors
+	switch p.SubSecond {
+	case SubSecondReject, SubSecondTruncate, SubSecondRound:
+	default:
+		errs.Add("sub_second", validation.CodeUnsupported,
+			fmt.Sprintf("unknown sub-second handling %q (expected reject, truncate or round)", p.SubSecond), nil)
+	}
+	switch p.LeapSecond {
+	case LeapSecondReject, LeapSecondClamp, LeapSecondRoll:
+	default:
+		errs.Add("leap_second", validation.CodeUnsupported,
+			fmt.Sprintf("unknown leap second handling %q (expected reject, clamp or roll)", p.LeapSecond), nil)
+	}
+	return errs.Err()
+}
+
+// FromTime converts t into a Time, applying the sub-second handling.
+func (p TimePolicy) FromTime(t time.Time) (*Time, error) {
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+
+	if t.Nanosecond() != 0 {
+		switch p.SubSecond {
+		case SubSecondReject:
+			return nil, domainerror.Invalidf("time %s has sub-second precision that would be lost",
+				t.UTC().Format(time.RFC3339Nano))
+		case SubSecondTruncate:
+			t = t.Truncate(time.Second)
+		case SubSecondRound:
+			t = t.Round(time.Second)
+		}
+	}
+	return NewTime(t.Unix())
+}
+
+// ParseRFC3339Nano parses an RFC 3339 timestamp with optional fractional
+// seconds, applying the leap second handling and then the sub-second
+// handling. A ":60" second is only accepted where a leap second can occur:
+// 23:59:60 UTC on June 30 or December 31.
+func (p TimePolicy) ParseRFC3339Nano(value string) (*Time, error) {
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+
+	match := leapSecondPattern.FindStringSubmatch(value)
+	if match == nil {
+		parsed, err := time.Parse(time.RFC3339Nano, value)
+		if err != nil {
+			return nil, domainerror.Invalidf("invalid RFC 3339 time %q", value)
+		}
+		return p.FromTime(parsed)
+	}
+
+	// Parse the leap second as the second before it, keeping the fraction
+	parsed, err := time.Parse(time.RFC3339Nano, match[1]+"59"+match[2]+match[3])
+	if err != nil {
+		return nil, domainerror.Invalidf("invalid RFC 3339 time %q", value)
+	}
+	utc := parsed.UTC()
+	_, month, day := utc.Date()
+	endOfHalfYear := (month == time.June && day == 30) || (month == time.December && day == 31)
+	if !endOfHalfYear || utc.Hour() != 23 || utc.Minute() != 59 {
+		return nil, domainerror.Invalidf("invalid leap second %q: leap seconds occur at 23:59:60 UTC on June 30 or December 31", value)
+	}
+
+	switch p.LeapSecond {
+	case LeapSecondReject:
+		return nil, domainerror.Invalidf("leap second %q cannot be represented in Unix time", value)
+	case LeapSecondRoll:
+		parsed = parsed.Add(time.Second)
+	}
+	return p.FromTime(parsed)
+}
+
+// FormatRFC3339Nano formats the time in UTC as RFC 3339. Time has no
+// fraction, so the result is exact and round-trips through any policy.
+func (t Time) FormatRFC3339Nano() string {
+	return t.ToTime().UTC().Format(time.RFC3339Nano)
+}
+
+// String describes the policy, e.g. "sub_second=reject leap_second=clamp".
+func (p TimePolicy) String() string {
+	return fmt.Sprintf("sub_second=%s leap_second=%s", p.SubSecond, p.LeapSecond)
+}
@@ -0,0 +1,120 @@
+// Package internationalization provides domain types and composite types for handling
+// internationalized data such as time, currency, timezone, and phone numbers.
+// This file contains the TimeRange type for half-open spans of time.
+//
+// TimeRange Type:
+//   - Half-open [Start, End): a booking ending at 10:00 does not overlap one
+//     starting at 10:00
+//   - Overlap, containment and intersection checks
+//   - Indexed by TimeRangeTree for fast conflict queries
+//
+// Database Storage: (start_epoch int64, end_epoch int64)
+// JSON Format: {"start": {"epoch": 1715936400}, "end": {"epoch": 1715940000}}
+//
+// Usage Examples:
+//
+//	booking, err := NewTimeRange(*start, *end)
+//	booking.Overlaps(other)      // true if they share any second
+//	booking.Contains(*checkIn)   // Start <= checkIn < End
+package internationalization
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"golang-arch/internal/shared/domain/domainerror"
+	"golang-arch/internal/shared/domain/validation"
+)
+
+// TimeRange is the half-open span of time [Start, End).
+type TimeRange struct {
+	Start Time `json:"start"` // First second included
+	End   Time `json:"end"`   // First second after the range
+}
+
+// NewTimeRange creates a TimeRange.
+// Returns an error if either bound is invalid or End is not after Start.
+func NewTimeRange(start, end Time) (*TimeRange, error) {
+	r := &TimeRange{Start: start, End: end}
+	if err := r.Validate(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// NewTimeRangeFromPrimitive creates a TimeRange from primitive database values.
+func NewTimeRangeFromPrimitive(startEpoch, endEpoch int64) (*TimeRange, error) {
+	r, err := NewTimeRange(Time{Epoch: startEpoch}, Time{Epoch: endEpoch})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create time range from primitive: %w", err)
+	}
+	return r, nil
+}
+
+// ToPrimitive converts the TimeRange to primitive database values.
+func (r TimeRange) ToPrimitive() (int64, int64) {
+	return r.Start.Epoch, r.End.Epoch
+}
+
+// Validate ensures both bounds are valid and End is after Start.
+func (r TimeRange) Validate() error {
+	var errs validation.ValidationErrors
+	errs.Merge("start", "invalid start in time range", r.Start.Validate())
+	errs.Merge("end", "invalid end in time range", r.End.Validate())
+	if err := errs.Err(); err != nil {
+		return err
+	}
+	if r.End.Epoch <= r.Start.Epoch {
+		return domainerror.Invalidf("time range end %s must be after start %s", r.End, r.Start)
+	}
+	return nil
+}
+
+// Duration returns the length of the range.
+func (r TimeRange) Duration() time.Duration {
+	return time.Duration(r.End.Epoch-r.Start.Epoch) * time.Second
+}
+
+// Contains reports whether t falls inside the range.
+func (r TimeRange) Contains(t Time) bool {
+	return t.Epoch >= r.Start.Epoch && t.Epoch < r.End.Epoch
+}
+
+// Overlaps reports whether the ranges share any time. Adjacent ranges do not
+// overlap.
+func (r TimeRange) Overlaps(other TimeRange) bool {
+	return r.Start.Epoch < other.End.Epoch && other.Start.Epoch < r.End.Epoch
+}
+
+// Intersect returns the overlap of the ranges, or false when they do not overlap.
+func (r TimeRange) Intersect(other TimeRange) (TimeRange, bool) {
+	if !r.Overlaps(other) {
+		return TimeRange{}, false
+	}
+	return TimeRange{
+		Start: Time{Epoch: max(r.Start.Epoch, other.Start.Epoch)},
+		End:   Time{Epoch: min(r.End.Epoch, other.End.Epoch)},
+	}, true
+}
+
+// String returns the range as "start/end" in RFC 3339 (ISO 8601 interval form).
+func (r TimeRange) String() string {
+	return r.Start.String() + "/" + r.End.String()
+}
+
+// UnmarshalJSON implements json.Unmarshaler interface.
+func (r *TimeRange) UnmarshalJSON(data []byte) error {
+	type plain TimeRange
+	var decoded plain
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+
+	tr := TimeRange(decoded)
+	if err := tr.Validate(); err != nil {
+		return err
+	}
+	*r = tr
+	return nil
+}
@@ -0,0 +1,233 @@
+// Package internationalization provides domain types and composite types for handling
+// internationalized data such as time, currency, timezone, and phone numbers.
+// This file contains the TimeRangeTree interval index over TimeRange values.
+//
+// TimeRangeTree Type:
+//   - Interval tree keyed by caller IDs (e.g. booking IDs)
+//   - Insert, Remove and QueryOverlapping in O(log n) expected time, plus
+//     O(k) for k results
+//   - Results ordered by start, then end, then insertion
+//   - Not safe for concurrent use; guard it with a mutex when shared
+//
+// Usage Examples:
+//
+//	tree := NewTimeRangeTree[string]()
+//	tree.Insert("booking-1", *morning)
+//	conflicts := tree.QueryOverlapping(*requested) // []TimeRangeEntry[string]
+//	if tree.HasOverlap(*requested) { ... }
+package internationalization
+
+// TimeRangeEntry is a range stored in a TimeRangeTree with its key.
+type TimeRangeEntry[K comparable] struct {
+	Key   K
+	Range TimeRange
+}
+
+// TimeRangeTree indexes TimeRange values by key for overlap queries. It is
+// a treap ordered by range start and augmented with the latest end in each
+// subtree, so subtrees that end before a query starts are skipped.
+//
+// Example:
+//
+//	tree := NewTimeRangeTree[int64]()
+//	tree.Insert(booking.ID, booking.Period)
+//	for _, entry := range tree.QueryOverlapping(request) { ... }
+type TimeRangeTree[K comparable] struct {
+	root    *timeRangeNode[K]
+	nodes   map[K]*timeRangeNode[K]
+	counter uint64
+}
+
+// timeRangeNode is a treap node; seq makes ordering total and prio keeps the
+// tree balanced in expectation
+type timeRangeNode[K comparable] struct {
+	entry       TimeRangeEntry[K]
+	seq         uint64
+	prio        uint64
+	maxEnd      int64
+	left, right *timeRangeNode[K]
+}
+
+// NewTimeRangeTree creates an empty TimeRangeTree.
+func NewTimeRangeTree[K comparable]() *TimeRangeTree[K] {
+	return &TimeRangeTree[K]{nodes: make(map[K]*timeRangeNode[K])}
+}
+
+// Insert adds r under key, replacing the range previously stored under key.
+// Returns an error if r is invalid.
+func (t *TimeRangeTree[K]) Insert(key K, r TimeRange) error {
+	if err := r.Validate(); err != nil {
+		return err
+	}
+	t.Remove(key)
+
+	t.counter++
+	node := &timeRangeNode[K]{
+		entry:  TimeRangeEntry[K]{Key: key, Range: r},
+		seq:    t.counter,
+		prio:   splitmix64(t.counter),
+		maxEnd: r.End.Epoch,
+	}
+	left, right := splitTimeRange(t.root, node, false)
+	t.root = mergeTimeRange(mergeTimeRange(left, node), right)
+	t.nodes[key] = node
+	return nil
+}
+
+// Remove deletes the range stored under key and reports whether there was one.
+func (t *TimeRangeTree[K]) Remove(key K) bool {
+	node, ok := t.nodes[key]
+	if !ok {
+		return false
+	}
+	left, rest := splitTimeRange(t.root, node, false)
+	_, right := splitTimeRange(rest, node, true)
+	t.root = mergeTimeRange(left, right)
+	delete(t.nodes, key)
+	return true
+}
+
+// Get returns the range stored under key.
+func (t *TimeRangeTree[K]) Get(key K) (TimeRange, bool) {
+	node, ok := t.nodes[key]
+	if !ok {
+		return TimeRange{}, false
+	}
+	return node.entry.Range, true
+}
+
+// Len returns the number of stored ranges.
+func (t *TimeRangeTree[K]) Len() int {
+	return len(t.nodes)
+}
+
+// QueryOverlapping returns the entries whose ranges overlap q, ordered by
+// start. Ranges that only touch q at a bound do not overlap it.
+func (t *TimeRangeTree[K]) QueryOverlapping(q TimeRange) []TimeRangeEntry[K] {
+	var result []TimeRangeEntry[K]
+	t.visitOverlapping(t.root, q, func(entry TimeRangeEntry[K]) bool {
+		result = append(result, entry)
+		return true
+	})
+	return result
+}
+
+// QueryPoint returns the entries whose ranges contain at, ordered by start.
+func (t *TimeRangeTree[K]) QueryPoint(at Time) []TimeRangeEntry[K] {
+	return t.QueryOverlapping(TimeRange{Start: at, End: Time{Epoch: at.Epoch + 1}})
+}
+
+// HasOverlap reports whether any stored range overlaps q, stopping at the
+// first match.
+func (t *TimeRangeTree[K]) HasOverlap(q TimeRange) bool {
+	found := false
+	t.visitOverlapping(t.root, q, func(TimeRangeEntry[K]) bool {
+		found = true
+		return false
+	})
+	return found
+}
+
+// Entries returns all entries ordered by start.
+func (t *TimeRangeTree[K]) Entries() []TimeRangeEntry[K] {
+	result := make([]TimeRangeEntry[K], 0, len(t.nodes))
+	var walk func(n *timeRangeNode[K])
+	walk = func(n *timeRangeNode[K]) {
+		if n == nil {
+			return
+		}
+		walk(n.left)
+		result = append(result, n.entry)
+		walk(n.right)
+	}
+	walk(t.root)
+	return result
+}
+
+// visitOverlapping calls visit for each overlapping entry in order until it
+// returns false, and reports whether the walk should continue.
+func (t *TimeRangeTree[K]) visitOverlapping(n *timeRangeNode[K], q TimeRange, visit func(TimeRangeEntry[K]) bool) bool {
+	// Nothing in this subtree ends after the query starts
+	if n == nil || n.maxEnd <= q.Start.Epoch {
+		return true
+	}
+	if !t.visitOverlapping(n.left, q, visit) {
+		return false
+	}
+	// This node and everything to its right start at or after the query ends
+	if n.entry.Range.Start.Epoch >= q.End.Epoch {
+		return true
+	}
+	if n.entry.Range.Overlaps(q) && !visit(n.entry) {
+		return false
+	}
+	return t.visitOverlapping(n.right, q, visit)
+}
+
+// lessTimeRange orders nodes by start, end and insertion sequence.
+func lessTimeRange[K comparable](a, b *timeRangeNode[K]) bool {
+	ar, br := a.entry.Range, b.entry.Range
+	switch {
+	case ar.Start.Epoch != br.Start.Epoch:
+		return ar.Start.Epoch < br.Start.Epoch
+	case ar.End.Epoch != br.End.Epoch:
+		return ar.End.Epoch < br.End.Epoch
+	default:
+		return a.seq < b.seq
+	}
+}
+
+// splitTimeRange splits n into nodes ordered before pivot and the rest;
+// with inclusive, pivot itself goes to the left part.
+func splitTimeRange[K comparable](n, pivot *timeRangeNode[K], inclusive bool) (*timeRangeNode[K], *timeRangeNode[K]) {
+	if n == nil {
+		return nil, nil
+	}
+	if lessTimeRange(n, pivot) || (inclusive && n == pivot) {
+		left, right := splitTimeRange(n.right, pivot, inclusive)
+		n.right = left
+		n.update()
+		return n, right
+	}
+	left, right := splitTimeRange(n.left, pivot, inclusive)
+	n.left = right
+	n.update()
+	return left, n
+}
+
+// mergeTimeRange joins two treaps where every node of a orders before b.
+func mergeTimeRange[K comparable](a, b *timeRangeNode[K]) *timeRangeNode[K] {
+	switch {
+	case a == nil:
+		return b
+	case b == nil:
+		return a
+	case a.prio > b.prio:
+		a.right = mergeTimeRange(a.right, b)
+		a.update()
+		return a
+	default:
+		b.left = mergeTimeRange(a, b.left)
+		b.update()
+		return b
+	}
+}
+
+// update recomputes the latest end of the subtree rooted at n.
+func (n *timeRangeNode[K]) update() {
+	n.maxEnd = n.entry.Range.End.Epoch
+	if n.left != nil && n.left.maxEnd > n.maxEnd {
+		n.maxEnd = n.left.maxEnd
+	}
+	if n.right != nil && n.right.maxEnd > n.maxEnd {
+		n.maxEnd = n.right.maxEnd
+	}
+}
+
+// splitmix64 scrambles a counter into a well-distributed treap priority.
+func splitmix64(x uint64) uint64 {
+	x += 0x9e3779b97f4a7c15
+	x = (x ^ (x >> 30)) * 0xbf58476d1ce4e5b9
+	x = (x ^ (x >> 27)) * 0x94d049bb133111eb
+	return x ^ (x >> 31)
+}
@@ -11,8 +11,10 @@ package internationalization
 
 import (
 	"fmt"
-	"strings"
 	"time"
+
+	"golang-arch/internal/shared/domain/domainerror"
+	"golang-arch/internal/shared/domain/validation"
 )
 
 // Timezone represents a timezone using IANA identifier.
@@ -41,28 +43,31 @@ func NewTimezone(id, name string, offset int) (*Timezone, error) {
 // NewTimezoneFromID creates a Timezone instance from IANA identifier.
 func NewTimezoneFromID(id string) (*Timezone, error) {
 	if id == "" {
-		return nil, fmt.Errorf("timezone ID cannot be empty")
+		return nil, domainerror.Invalidf("timezone ID cannot be empty")
 	}
 
-	// Try to load the location to validate it
-	loc, err := time.LoadLocation(id)
+	// Load (or reuse) the location to validate it
+	loc, err := loadLocation(id)
 	if err != nil {
-		return nil, fmt.Errorf("unsupported timezone ID: %s", id)
+		return nil, domainerror.Invalidf("unsupported timezone ID: %s", id)
 	}
 
 	// Get the offset for the current time
-	now := time.Now()
-	_, offset := now.In(loc).Zone()
+	offset := loc.offsetAt(currentClock().Now())
 
-	return &Timezone{
+	tz := &Timezone{
 		ID:     id,
-		Name:   getTimezoneDisplayName(id),
+		Name:   loc.displayName,
 		Offset: offset / 60, // Convert seconds to minutes
-	}, nil
+	}
+	if err := checkConstraints(currentConstraints().Timezone, *tz); err != nil {
+		return nil, err
+	}
+	return tz, nil
 }
 
 // ToPrimitive returns the primitive value for database storage.
-func (tz *Timezone) ToPrimitive() string {
+func (tz Timezone) ToPrimitive() string {
 	return tz.ID
 }
 
@@ -72,41 +77,48 @@ func FromPrimitiveTimezone(id string) (*Timezone, error) {
 }
 
 // Validate ensures the timezone ID is valid and offset is reasonable.
-func (tz *Timezone) Validate() error {
+func (tz Timezone) Validate() error {
+	var errs validation.ValidationErrors
+
 	if tz.ID == "" {
-		return fmt.Errorf("timezone ID cannot be empty")
+		errs.Add("id", validation.CodeRequired, "timezone ID cannot be empty", nil)
+	} else if _, err := loadLocation(tz.ID); err != nil {
+		// Try to load the location to validate it
+		errs.Add("id", validation.CodeUnsupported,
+			fmt.Sprintf("unsupported timezone ID: %s", tz.ID),
+			map[string]any{"value": tz.ID})
 	}
 
 	if tz.Name == "" {
-		return fmt.Errorf("timezone name cannot be empty")
-	}
-
-	// Try to load the location to validate it
-	_, err := time.LoadLocation(tz.ID)
-	if err != nil {
-		return fmt.Errorf("unsupported timezone ID: %s", tz.ID)
+		errs.Add("name", validation.CodeRequired, "timezone name cannot be empty", nil)
 	}
 
 	// Validate offset range (-1440 to 1440 minutes = -24 to +24 hours, excluding the extremes)
 	if tz.Offset <= -1440 || tz.Offset >= 1440 {
-		return fmt.Errorf("timezone offset must be between -1440 and 1440 minutes, got %d", tz.Offset)
+		errs.Add("offset", validation.CodeOutOfRange,
+			fmt.Sprintf("timezone offset must be between -1440 and 1440 minutes, got %d", tz.Offset),
+			map[string]any{"min": -1440, "max": 1440, "actual": tz.Offset})
 	}
 
-	return nil
+	return errs.Err()
 }
 
 // GetOffset returns the timezone offset as time.Duration.
-func (tz *Timezone) GetOffset() time.Duration {
+func (tz Timezone) GetOffset() time.Duration {
 	return time.Duration(tz.Offset) * time.Minute
 }
 
 // GetLocation returns the time.Location for this timezone.
-func (tz *Timezone) GetLocation() (*time.Location, error) {
-	return time.LoadLocation(tz.ID)
+func (tz Timezone) GetLocation() (*time.Location, error) {
+	loc, err := loadLocation(tz.ID)
+	if err != nil {
+		return nil, err
+	}
+	return loc.location, nil
 }
 
 // FormatOffset returns the offset formatted as "+/-HH:MM".
-func (tz *Timezone) FormatOffset() string {
+func (tz Timezone) FormatOffset() string {
 	hours := tz.Offset / 60
 	minutes := tz.Offset % 60
 
@@ -129,35 +141,30 @@ func (tz *Timezone) Equal(other *Timezone) bool {
 }
 
 // String returns a formatted string representation of the timezone.
-func (tz *Timezone) String() string {
+func (tz Timezone) String() string {
 	return fmt.Sprintf("%s (%s) %s", tz.ID, tz.Name, tz.FormatOffset())
 }
 
 // getTimezoneDisplayName returns a human-readable name for the timezone.
+// The established names of common timezones come first, then the CLDR
+// English name; see DisplayName for other languages.
 func getTimezoneDisplayName(id string) string {
-	// Common timezone display names
-	displayNames := map[string]string{
-		"UTC":                 "Coordinated Universal Time",
-		"America/New_York":    "Eastern Time",
-		"America/Chicago":     "Central Time",
-		"America/Denver":      "Mountain Time",
-		"America/Los_Angeles": "Pacific Time",
-		"Europe/London":       "Greenwich Mean Time",
-		"Europe/Paris":        "Central European Time",
-		"Asia/Tokyo":          "Japan Standard Time",
-		"Asia/Shanghai":       "China Standard Time",
-		"Australia/Sydney":    "Australian Eastern Time",
-	}
-
-	if name, exists := displayNames[id]; exists {
+	if name, exists := timezoneDisplayNames[id]; exists {
 		return name
 	}
+	return timezoneNames().displayName(id, "en")
+}
 
-	// Fallback: convert ID to display name
-	parts := strings.Split(id, "/")
-	if len(parts) > 1 {
-		return strings.ReplaceAll(parts[len(parts)-1], "_", " ")
-	}
-
-	return id
+// timezoneDisplayNames holds display names for common timezones
+var timezoneDisplayNames = map[string]string{
+	"UTC":                 "Coordinated Universal Time",
+	"America/New_York":    "Eastern Time",
+	"America/Chicago":     "Central Time",
+	"America/Denver":      "Mountain Time",
+	"America/Los_Angeles": "Pacific Time",
+	"Europe/London":       "Greenwich Mean Time",
+	"Europe/Paris":        "Central European Time",
+	"Asia/Tokyo":          "Japan Standard Time",
+	"Asia/Shanghai":       "China Standard Time",
+	"Australia/Sydney":    "Australian Eastern Time",
 }
@@ -0,0 +1,73 @@
+// Package internationalization provides domain types for handling internationalization
+// concerns such as time, currency, timezone, and phone number formatting.
+//
+// This file caches timezone lookups. NewTimezoneFromID runs for every row that
+// hydrates a LocalizedDateTime, and time.LoadLocation re-reads and parses the
+// zoneinfo data on each call. Loaded locations and their display names are kept
+// for the life of the process; the set is bounded by the IANA database because
+// only IDs that load successfully are cached. The UTC offset is cached per
+// location together with the bounds of the zone period it belongs to, so it is
+// recomputed only when the clock crosses a DST transition.
+package internationalization
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// locationCache maps IANA IDs to *cachedLocation
+var locationCache sync.Map
+
+// cachedLocation is a loaded location with its display name and the zone
+// period most recently resolved for it
+type cachedLocation struct {
+	location    *time.Location
+	displayName string
+	zone        atomic.Pointer[zonePeriod]
+}
+
+// zonePeriod is an interval during which a location has a constant UTC offset.
+// A zero start or end means the period is unbounded on that side.
+type zonePeriod struct {
+	start  time.Time
+	end    time.Time
+	offset int // seconds east of UTC
+}
+
+// loadLocation returns the cached location for id, loading it on first use
+func loadLocation(id string) (*cachedLocation, error) {
+	if cached, ok := locationCache.Load(id); ok {
+		return cached.(*cachedLocation), nil
+	}
+
+	location, err := time.LoadLocation(id)
+	if err != nil {
+		return nil, err
+	}
+
+	cached, _ := locationCache.LoadOrStore(id, &cachedLocation{
+		location:    location,
+		displayName: getTimezoneDisplayName(id),
+	})
+	return cached.(*cachedLocation), nil
+}
+
+// offsetAt returns the UTC offset in seconds of the location at t
+func (c *cachedLocation) offsetAt(t time.Time) int {
+	if period := c.zone.Load(); period != nil && period.contains(t) {
+		return period.offset
+	}
+
+	local := t.In(c.location)
+	_, offset := local.Zone()
+	start, end := local.ZoneBounds()
+	c.zone.Store(&zonePeriod{start: start, end: end, offset: offset})
+
+	return offset
+}
+
+// contains reports whether t falls inside the period
+func (p *zonePeriod) contains(t time.Time) bool {
+	return (p.start.IsZero() || !t.Before(p.start)) && (p.end.IsZero() || t.Before(p.end))
+}
@@ -0,0 +1,446 @@
+// Package internationalization provides domain types and composite types for handling
+// internationalized data such as time, currency, timezone, and phone numbers.
+// This file contains the embedded IANA timezone catalog.
+//
+// Embedded Data:
+//   - One row per country and zone, from the tz database's zone.tab
+//     (version 2025b), plus UTC
+//   - Comments distinguish zones of countries with several, e.g.
+//     "Mountain (most areas)"
+//   - Zones shared by several countries appear once per country
+package internationalization
+
+// TimezoneEntry is one zone of the catalog, as offered in timezone pickers
+type TimezoneEntry struct {
+	ID      string  `json:"id"`                // IANA identifier, e.g. "America/New_York"
+	Country Country `json:"country,omitempty"` // Empty for UTC
+	Comment string  `json:"comment,omitempty"` // Part of the country the zone covers
+}
+
+// Timezones returns the catalog ordered by ID, then country. The slice is
+// shared and must not be modified.
+func Timezones() []TimezoneEntry {
+	return timezoneCatalog
+}
+
+var timezoneCatalog = []TimezoneEntry{
+	{"UTC", "", ""},
+	{"Africa/Abidjan", "CI", ""},
+	{"Africa/Accra", "GH", ""},
+	{"Africa/Addis_Ababa", "ET", ""},
+	{"Africa/Algiers", "DZ", ""},
+	{"Africa/Asmara", "ER", ""},
+	{"Africa/Bamako", "ML", ""},
+	{"Africa/Bangui", "CF", ""},
+	{"Africa/Banjul", "GM", ""},
+	{"Africa/Bissau", "GW", ""},
+	{"Africa/Blantyre", "MW", ""},
+	{"Africa/Brazzaville", "CG", ""},
+	{"Africa/Bujumbura", "BI", ""},
+	{"Africa/Cairo", "EG", ""},
+	{"Africa/Casablanca", "MA", ""},
+	{"Africa/Ceuta", "ES", "Ceuta, Melilla"},
+	{"Africa/Conakry", "GN", ""},
+	{"Africa/Dakar", "SN", ""},
+	{"Africa/Dar_es_Salaam", "TZ", ""},
+	{"Africa/Djibouti", "DJ", ""},
+	{"Africa/Douala", "CM", ""},
+	{"Africa/El_Aaiun", "EH", ""},
+	{"Africa/Freetown", "SL", ""},
+	{"Africa/Gaborone", "BW", ""},
+	{"Africa/Harare", "ZW", ""},
+	{"Africa/Johannesburg", "ZA", ""},
+	{"Africa/Juba", "SS", ""},
+	{"Africa/Kampala", "UG", ""},
+	{"Africa/Khartoum", "SD", ""},
+	{"Africa/Kigali", "RW", ""},
+	{"Africa/Kinshasa", "CD", "Dem. Rep. of Congo (west)"},
+	{"Africa/Lagos", "NG", ""},
+	{"Africa/Libreville", "GA", ""},
+	{"Africa/Lome", "TG", ""},
+	{"Africa/Luanda", "AO", ""},
+	{"Africa/Lubumbashi", "CD", "Dem. Rep. of Congo (east)"},
+	{"Africa/Lusaka", "ZM", ""},
+	{"Africa/Malabo", "GQ", ""},
+	{"Africa/Maputo", "MZ", ""},
+	{"Africa/Maseru", "LS", ""},
+	{"Africa/Mbabane", "SZ", ""},
+	{"Africa/Mogadishu", "SO", ""},
+	{"Africa/Monrovia", "LR", ""},
+	{"Africa/Nairobi", "KE", ""},
+	{"Africa/Ndjamena", "TD", ""},
+	{"Africa/Niamey", "NE", ""},
+	{"Africa/Nouakchott", "MR", ""},
+	{"Africa/Ouagadougou", "BF", ""},
+	{"Africa/Porto-Novo", "BJ", ""},
+	{"Africa/Sao_Tome", "ST", ""},
+	{"Africa/Tripoli", "LY", ""},
+	{"Africa/Tunis", "TN", ""},
+	{"Africa/Windhoek", "NA", ""},
+	{"America/Adak", "US", "Alaska - western Aleutians"},
+	{"America/Anchorage", "US", "Alaska (most areas)"},
+	{"America/Anguilla", "AI", ""},
+	{"America/Antigua", "AG", ""},
+	{"America/Araguaina", "BR", "Tocantins"},
+	{"America/Argentina/Buenos_Aires", "AR", "Buenos Aires (BA, CF)"},
+	{"America/Argentina/Catamarca", "AR", "Catamarca (CT), Chubut (CH)"},
+	{"America/Argentina/Cordoba", "AR", "Argentina (most areas: CB, CC, CN, ER, FM, MN, SE, SF)"},
+	{"America/Argentina/Jujuy", "AR", "Jujuy (JY)"},
+	{"America/Argentina/La_Rioja", "AR", "La Rioja (LR)"},
+	{"America/Argentina/Mendoza", "AR", "Mendoza (MZ)"},
+	{"America/Argentina/Rio_Gallegos", "AR", "Santa Cruz (SC)"},
+	{"America/Argentina/Salta", "AR", "Salta (SA, LP, NQ, RN)"},
+	{"America/Argentina/San_Juan", "AR", "San Juan (SJ)"},
+	{"America/Argentina/San_Luis", "AR", "San Luis (SL)"},
+	{"America/Argentina/Tucuman", "AR", "Tucuman (TM)"},
+	{"America/Argentina/Ushuaia", "AR", "Tierra del Fuego (TF)"},
+	{"America/Aruba", "AW", ""},
+	{"America/Asuncion", "PY", ""},
+	{"America/Atikokan", "CA", "EST - ON (Atikokan), NU (Coral H)"},
+	{"America/Bahia", "BR", "Bahia"},
+	{"America/Bahia_Banderas", "MX", "Bahia de Banderas"},
+	{"America/Barbados", "BB", ""},
+	{"America/Belem", "BR", "Para (east), Amapa"},
+	{"America/Belize", "BZ", ""},
+	{"America/Blanc-Sablon", "CA", "AST - QC (Lower North Shore)"},
+	{"America/Boa_Vista", "BR", "Roraima"},
+	{"America/Bogota", "CO", ""},
+	{"America/Boise", "US", "Mountain - ID (south), OR (east)"},
+	{"America/Cambridge_Bay", "CA", "Mountain - NU (west)"},
+	{"America/Campo_Grande", "BR", "Mato Grosso do Sul"},
+	{"America/Cancun", "MX", "Quintana Roo"},
+	{"America/Caracas", "VE", ""},
+	{"America/Cayenne", "GF", ""},
+	{"America/Cayman", "KY", ""},
+	{"America/Chicago", "US", "Central (most areas)"},
+	{"America/Chihuahua", "MX", "Chihuahua (most areas)"},
+	{"America/Ciudad_Juarez", "MX", "Chihuahua (US border - west)"},
+	{"America/Costa_Rica", "CR", ""},
+	{"America/Coyhaique", "CL", "Aysen Region"},
+	{"America/Creston", "CA", "MST - BC (Creston)"},
+	{"America/Cuiaba", "BR", "Mato Grosso"},
+	{"America/Curacao", "CW", ""},
+	{"America/Danmarkshavn", "GL", "National Park (east coast)"},
+	{"America/Dawson", "CA", "MST - Yukon (west)"},
+	{"America/Dawson_Creek", "CA", "MST - BC (Dawson Cr, Ft St John)"},
+	{"America/Denver", "US", "Mountain (most areas)"},
+	{"America/Detroit", "US", "Eastern - MI (most areas)"},
+	{"America/Dominica", "DM", ""},
+	{"America/Edmonton", "CA", "Mountain - AB, BC(E), NT(E), SK(W)"},
+	{"America/Eirunepe", "BR", "Amazonas (west)"},
+	{"America/El_Salvador", "SV", ""},
+	{"America/Fort_Nelson", "CA", "MST - BC (Ft Nelson)"},
+	{"America/Fortaleza", "BR", "Brazil (northeast: MA, PI, CE, RN, PB)"},
+	{"America/Glace_Bay", "CA", "Atlantic - NS (Cape Breton)"},
+	{"America/Goose_Bay", "CA", "Atlantic - Labrador (most areas)"},
+	{"America/Grand_Turk", "TC", ""},
+	{"America/Grenada", "GD", ""},
+	{"America/Guadeloupe", "GP", ""},
+	{"America/Guatemala", "GT", ""},
+	{"America/Guayaquil", "EC", "Ecuador (mainland)"},
+	{"America/Guyana", "GY", ""},
+	{"America/Halifax", "CA", "Atlantic - NS (most areas), PE"},
+	{"America/Havana", "CU", ""},
+	{"America/Hermosillo", "MX", "Sonora"},
+	{"America/Indiana/Indianapolis", "US", "Eastern - IN (most areas)"},
+	{"America/Indiana/Knox", "US", "Central - IN (Starke)"},
+	{"America/Indiana/Marengo", "US", "Eastern - IN (Crawford)"},
+	{"America/Indiana/Petersburg", "US", "Eastern - IN (Pike)"},
+	{"America/Indiana/Tell_City", "US", "Central - IN (Perry)"},
+	{"America/Indiana/Vevay", "US", "Eastern - IN (Switzerland)"},
+	{"America/Indiana/Vincennes", "US", "Eastern - IN (Da, Du, K, Mn)"},
+	{"America/Indiana/Winamac", "US", "Eastern - IN (Pulaski)"},
+	{"America/Inuvik", "CA", "Mountain - NT (west)"},
+	{"America/Iqaluit", "CA", "Eastern - NU (most areas)"},
+	{"America/Jamaica", "JM", ""},
+	{"America/Juneau", "US", "Alaska - Juneau area"},
+	{"America/Kentucky/Louisville", "US", "Eastern - KY (Louisville area)"},
+	{"America/Kentucky/Monticello", "US", "Eastern - KY (Wayne)"},
+	{"America/Kralendijk", "BQ", ""},
+	{"America/La_Paz", "BO", ""},
+	{"America/Lima", "PE", ""},
+	{"America/Los_Angeles", "US", "Pacific"},
+	{"America/Lower_Princes", "SX", ""},
+	{"America/Maceio", "BR", "Alagoas, Sergipe"},
+	{"America/Managua", "NI", ""},
+	{"America/Manaus", "BR", "Amazonas (east)"},
+	{"America/Marigot", "MF", ""},
+	{"America/Martinique", "MQ", ""},
+	{"America/Matamoros", "MX", "Coahuila, Nuevo Leon, Tamaulipas (US border)"},
+	{"America/Mazatlan", "MX", "Baja California Sur, Nayarit (most areas), Sinaloa"},
+	{"America/Menominee", "US", "Central - MI (Wisconsin border)"},
+	{"America/Merida", "MX", "Campeche, Yucatan"},
+	{"America/Metlakatla", "US", "Alaska - Annette Island"},
+	{"America/Mexico_City", "MX", "Central Mexico"},
+	{"America/Miquelon", "PM", ""},
+	{"America/Moncton", "CA", "Atlantic - New Brunswick"},
+	{"America/Monterrey", "MX", "Durango; Coahuila, Nuevo Leon, Tamaulipas (most areas)"},
+	{"America/Montevideo", "UY", ""},
+	{"America/Montserrat", "MS", ""},
+	{"America/Nassau", "BS", ""},
+	{"America/New_York", "US", "Eastern (most areas)"},
+	{"America/Nome", "US", "Alaska (west)"},
+	{"America/Noronha", "BR", "Atlantic islands"},
+	{"America/North_Dakota/Beulah", "US", "Central - ND (Mercer)"},
+	{"America/North_Dakota/Center", "US", "Central - ND (Oliver)"},
+	{"America/North_Dakota/New_Salem", "US", "Central - ND (Morton rural)"},
+	{"America/Nuuk", "GL", "most of Greenland"},
+	{"America/Ojinaga", "MX", "Chihuahua (US border - east)"},
+	{"America/Panama", "PA", ""},
+	{"America/Paramaribo", "SR", ""},
+	{"America/Phoenix", "US", "MST - AZ (except Navajo)"},
+	{"America/Port-au-Prince", "HT", ""},
+	{"America/Port_of_Spain", "TT", ""},
+	{"America/Porto_Velho", "BR", "Rondonia"},
+	{"America/Puerto_Rico", "PR", ""},
+	{"America/Punta_Arenas", "CL", "Magallanes Region"},
+	{"America/Rankin_Inlet", "CA", "Central - NU (central)"},
+	{"America/Recife", "BR", "Pernambuco"},
+	{"America/Regina", "CA", "CST - SK (most areas)"},
+	{"America/Resolute", "CA", "Central - NU (Resolute)"},
+	{"America/Rio_Branco", "BR", "Acre"},
+	{"America/Santarem", "BR", "Para (west)"},
+	{"America/Santiago", "CL", "most of Chile"},
+	{"America/Santo_Domingo", "DO", ""},
+	{"America/Sao_Paulo", "BR", "Brazil (southeast: GO, DF, MG, ES, RJ, SP, PR, SC, RS)"},
+	{"America/Scoresbysund", "GL", "Scoresbysund/Ittoqqortoormiit"},
+	{"America/Sitka", "US", "Alaska - Sitka area"},
+	{"America/St_Barthelemy", "BL", ""},
+	{"America/St_Johns", "CA", "Newfoundland, Labrador (SE)"},
+	{"America/St_Kitts", "KN", ""},
+	{"America/St_Lucia", "LC", ""},
+	{"America/St_Thomas", "VI", ""},
+	{"America/St_Vincent", "VC", ""},
+	{"America/Swift_Current", "CA", "CST - SK (midwest)"},
+	{"America/Tegucigalpa", "HN", ""},
+	{"America/Thule", "GL", "Thule/Pituffik"},
+	{"America/Tijuana", "MX", "Baja California"},
+	{"America/Toronto", "CA", "Eastern - ON & QC (most areas)"},
+	{"America/Tortola", "VG", ""},
+	{"America/Vancouver", "CA", "Pacific - BC (most areas)"},
+	{"America/Whitehorse", "CA", "MST - Yukon (east)"},
+	{"America/Winnipeg", "CA", "Central - ON (west), Manitoba"},
+	{"America/Yakutat", "US", "Alaska - Yakutat"},
+	{"Antarctica/Casey", "AQ", "Casey"},
+	{"Antarctica/Davis", "AQ", "Davis"},
+	{"Antarctica/DumontDUrville", "AQ", "Dumont-d'Urville"},
+	{"Antarctica/Macquarie", "AU", "Macquarie Island"},
+	{"Antarctica/Mawson", "AQ", "Mawson"},
+	{"Antarctica/McMurdo", "AQ", "New Zealand time - McMurdo, South Pole"},
+	{"Antarctica/Palmer", "AQ", "Palmer"},
+	{"Antarctica/Rothera", "AQ", "Rothera"},
+	{"Antarctica/Syowa", "AQ", "Syowa"},
+	{"Antarctica/Troll", "AQ", "Troll"},
+	{"Antarctica/Vostok", "AQ", "Vostok"},
+	{"Arctic/Longyearbyen", "SJ", ""},
+	{"Asia/Aden", "YE", ""},
+	{"Asia/Almaty", "KZ", "most of Kazakhstan"},
+	{"Asia/Amman", "JO", ""},
+	{"Asia/Anadyr", "RU", "MSK+09 - Bering Sea"},
+	{"Asia/Aqtau", "KZ", "Mangghystau/Mankistau"},
+	{"Asia/Aqtobe", "KZ", "Aqtobe/Aktobe"},
+	{"Asia/Ashgabat", "TM", ""},
+	{"Asia/Atyrau", "KZ", "Atyrau/Atirau/Gur'yev"},
+	{"Asia/Baghdad", "IQ", ""},
+	{"Asia/Bahrain", "BH", ""},
+	{"Asia/Baku", "AZ", ""},
+	{"Asia/Bangkok", "TH", ""},
+	{"Asia/Barnaul", "RU", "MSK+04 - Altai"},
+	{"Asia/Beirut", "LB", ""},
+	{"Asia/Bishkek", "KG", ""},
+	{"Asia/Brunei", "BN", ""},
+	{"Asia/Chita", "RU", "MSK+06 - Zabaykalsky"},
+	{"Asia/Colombo", "LK", ""},
+	{"Asia/Damascus", "SY", ""},
+	{"Asia/Dhaka", "BD", ""},
+	{"Asia/Dili", "TL", ""},
+	{"Asia/Dubai", "AE", ""},
+	{"Asia/Dushanbe", "TJ", ""},
+	{"Asia/Famagusta", "CY", "Northern Cyprus"},
+	{"Asia/Gaza", "PS", "Gaza Strip"},
+	{"Asia/Hebron", "PS", "West Bank"},
+	{"Asia/Ho_Chi_Minh", "VN", ""},
+	{"Asia/Hong_Kong", "HK", ""},
+	{"Asia/Hovd", "MN", "Bayan-Olgii, Hovd, Uvs"},
+	{"Asia/Irkutsk", "RU", "MSK+05 - Irkutsk, Buryatia"},
+	{"Asia/Jakarta", "ID", "Java, Sumatra"},
+	{"Asia/Jayapura", "ID", "New Guinea (West Papua / Irian Jaya), Malukus/Moluccas"},
+	{"Asia/Jerusalem", "IL", ""},
+	{"Asia/Kabul", "AF", ""},
+	{"Asia/Kamchatka", "RU", "MSK+09 - Kamchatka"},
+	{"Asia/Karachi", "PK", ""},
+	{"Asia/Kathmandu", "NP", ""},
+	{"Asia/Khandyga", "RU", "MSK+06 - Tomponsky, Ust-Maysky"},
+	{"Asia/Kolkata", "IN", ""},
+	{"Asia/Krasnoyarsk", "RU", "MSK+04 - Krasnoyarsk area"},
+	{"Asia/Kuala_Lumpur", "MY", "Malaysia (peninsula)"},
+	{"Asia/Kuching", "MY", "Sabah, Sarawak"},
+	{"Asia/Kuwait", "KW", ""},
+	{"Asia/Macau", "MO", ""},
+	{"Asia/Magadan", "RU", "MSK+08 - Magadan"},
+	{"Asia/Makassar", "ID", "Borneo (east, south), Sulawesi/Celebes, Bali, Nusa Tengarra, Timor (west)"},
+	{"Asia/Manila", "PH", ""},
+	{"Asia/Muscat", "OM", ""},
+	{"Asia/Nicosia", "CY", "most of Cyprus"},
+	{"Asia/Novokuznetsk", "RU", "MSK+04 - Kemerovo"},
+	{"Asia/Novosibirsk", "RU", "MSK+04 - Novosibirsk"},
+	{"Asia/Omsk", "RU", "MSK+03 - Omsk"},
+	{"Asia/Oral", "KZ", "West Kazakhstan"},
+	{"Asia/Phnom_Penh", "KH", ""},
+	{"Asia/Pontianak", "ID", "Borneo (west, central)"},
+	{"Asia/Pyongyang", "KP", ""},
+	{"Asia/Qatar", "QA", ""},
+	{"Asia/Qostanay", "KZ", "Qostanay/Kostanay/Kustanay"},
+	{"Asia/Qyzylorda", "KZ", "Qyzylorda/Kyzylorda/Kzyl-Orda"},
+	{"Asia/Riyadh", "SA", ""},
+	{"Asia/Sakhalin", "RU", "MSK+08 - Sakhalin Island"},
+	{"Asia/Samarkand", "UZ", "Uzbekistan (west)"},
+	{"Asia/Seoul", "KR", ""},
+	{"Asia/Shanghai", "CN", "Beijing Time"},
+	{"Asia/Singapore", "SG", ""},
+	{"Asia/Srednekolymsk", "RU", "MSK+08 - Sakha (E), N Kuril Is"},
+	{"Asia/Taipei", "TW", ""},
+	{"Asia/Tashkent", "UZ", "Uzbekistan (east)"},
+	{"Asia/Tbilisi", "GE", ""},
+	{"Asia/Tehran", "IR", ""},
+	{"Asia/Thimphu", "BT", ""},
+	{"Asia/Tokyo", "JP", ""},
+	{"Asia/Tomsk", "RU", "MSK+04 - Tomsk"},
+	{"Asia/Ulaanbaatar", "MN", "most of Mongolia"},
+	{"Asia/Urumqi", "CN", "Xinjiang Time"},
+	{"Asia/Ust-Nera", "RU", "MSK+07 - Oymyakonsky"},
+	{"Asia/Vientiane", "LA", ""},
+	{"Asia/Vladivostok", "RU", "MSK+07 - Amur River"},
+	{"Asia/Yakutsk", "RU", "MSK+06 - Lena River"},
+	{"Asia/Yangon", "MM", ""},
+	{"Asia/Yekaterinburg", "RU", "MSK+02 - Urals"},
+	{"Asia/Yerevan", "AM", ""},
+	{"Atlantic/Azores", "PT", "Azores"},
+	{"Atlantic/Bermuda", "BM", ""},
+	{"Atlantic/Canary", "ES", "Canary Islands"},
+	{"Atlantic/Cape_Verde", "CV", ""},
+	{"Atlantic/Faroe", "FO", ""},
+	{"Atlantic/Madeira", "PT", "Madeira Islands"},
+	{"Atlantic/Reykjavik", "IS", ""},
+	{"Atlantic/South_Georgia", "GS", ""},
+	{"Atlantic/St_Helena", "SH", ""},
+	{"Atlantic/Stanley", "FK", ""},
+	{"Australia/Adelaide", "AU", "South Australia"},
+	{"Australia/Brisbane", "AU", "Queensland (most areas)"},
+	{"Australia/Broken_Hill", "AU", "New South Wales (Yancowinna)"},
+	{"Australia/Darwin", "AU", "Northern Territory"},
+	{"Australia/Eucla", "AU", "Western Australia (Eucla)"},
+	{"Australia/Hobart", "AU", "Tasmania"},
+	{"Australia/Lindeman", "AU", "Queensland (Whitsunday Islands)"},
+	{"Australia/Lord_Howe", "AU", "Lord Howe Island"},
+	{"Australia/Melbourne", "AU", "Victoria"},
+	{"Australia/Perth", "AU", "Western Australia (most areas)"},
+	{"Australia/Sydney", "AU", "New South Wales (most areas)"},
+	{"Europe/Amsterdam", "NL", ""},
+	{"Europe/Andorra", "AD", ""},
+	{"Europe/Astrakhan", "RU", "MSK+01 - Astrakhan"},
+	{"Europe/Athens", "GR", ""},
+	{"Europe/Belgrade", "RS", ""},
+	{"Europe/Berlin", "DE", "most of Germany"},
+	{"Europe/Bratislava", "SK", ""},
+	{"Europe/Brussels", "BE", ""},
+	{"Europe/Bucharest", "RO", ""},
+	{"Europe/Budapest", "HU", ""},
+	{"Europe/Busingen", "DE", "Busingen"},
+	{"Europe/Chisinau", "MD", ""},
+	{"Europe/Copenhagen", "DK", ""},
+	{"Europe/Dublin", "IE", ""},
+	{"Europe/Gibraltar", "GI", ""},
+	{"Europe/Guernsey", "GG", ""},
+	{"Europe/Helsinki", "FI", ""},
+	{"Europe/Isle_of_Man", "IM", ""},
+	{"Europe/Istanbul", "TR", ""},
+	{"Europe/Jersey", "JE", ""},
+	{"Europe/Kaliningrad", "RU", "MSK-01 - Kaliningrad"},
+	{"Europe/Kirov", "RU", "MSK+00 - Kirov"},
+	{"Europe/Kyiv", "UA", "most of Ukraine"},
+	{"Europe/Lisbon", "PT", "Portugal (mainland)"},
+	{"Europe/Ljubljana", "SI", ""},
+	{"Europe/London", "GB", ""},
+	{"Europe/Luxembourg", "LU", ""},
+	{"Europe/Madrid", "ES", "Spain (mainland)"},
+	{"Europe/Malta", "MT", ""},
+	{"Europe/Mariehamn", "AX", ""},
+	{"Europe/Minsk", "BY", ""},
+	{"Europe/Monaco", "MC", ""},
+	{"Europe/Moscow", "RU", "MSK+00 - Moscow area"},
+	{"Europe/Oslo", "NO", ""},
+	{"Europe/Paris", "FR", ""},
+	{"Europe/Podgorica", "ME", ""},
+	{"Europe/Prague", "CZ", ""},
+	{"Europe/Riga", "LV", ""},
+	{"Europe/Rome", "IT", ""},
+	{"Europe/Samara", "RU", "MSK+01 - Samara, Udmurtia"},
+	{"Europe/San_Marino", "SM", ""},
+	{"Europe/Sarajevo", "BA", ""},
+	{"Europe/Saratov", "RU", "MSK+01 - Saratov"},
+	{"Europe/Simferopol", "UA", "Crimea"},
+	{"Europe/Skopje", "MK", ""},
+	{"Europe/Sofia", "BG", ""},
+	{"Europe/Stockholm", "SE", ""},
+	{"Europe/Tallinn", "EE", ""},
+	{"Europe/Tirane", "AL", ""},
+	{"Europe/Ulyanovsk", "RU", "MSK+01 - Ulyanovsk"},
+	{"Europe/Vaduz", "LI", ""},
+	{"Europe/Vatican", "VA", ""},
+	{"Europe/Vienna", "AT", ""},
+	{"Europe/Vilnius", "LT", ""},
+	{"Europe/Volgograd", "RU", "MSK+00 - Volgograd"},
+	{"Europe/Warsaw", "PL", ""},
+	{"Europe/Zagreb", "HR", ""},
+	{"Europe/Zurich", "CH", ""},
+	{"Indian/Antananarivo", "MG", ""},
+	{"Indian/Chagos", "IO", ""},
+	{"Indian/Christmas", "CX", ""},
+	{"Indian/Cocos", "CC", ""},
+	{"Indian/Comoro", "KM", ""},
+	{"Indian/Kerguelen", "TF", ""},
+	{"Indian/Mahe", "SC", ""},
+	{"Indian/Maldives", "MV", ""},
+	{"Indian/Mauritius", "MU", ""},
+	{"Indian/Mayotte", "YT", ""},
+	{"Indian/Reunion", "RE", ""},
+	{"Pacific/Apia", "WS", ""},
+	{"Pacific/Auckland", "NZ", "most of New Zealand"},
+	{"Pacific/Bougainville", "PG", "Bougainville"},
+	{"Pacific/Chatham", "NZ", "Chatham Islands"},
+	{"Pacific/Chuuk", "FM", "Chuuk/Truk, Yap"},
+	{"Pacific/Easter", "CL", "Easter Island"},
+	{"Pacific/Efate", "VU", ""},
+	{"Pacific/Fakaofo", "TK", ""},
+	{"Pacific/Fiji", "FJ", ""},
+	{"Pacific/Funafuti", "TV", ""},
+	{"Pacific/Galapagos", "EC", "Galapagos Islands"},
+	{"Pacific/Gambier", "PF", "Gambier Islands"},
+	{"Pacific/Guadalcanal", "SB", ""},
+	{"Pacific/Guam", "GU", ""},
+	{"Pacific/Honolulu", "US", "Hawaii"},
+	{"Pacific/Kanton", "KI", "Phoenix Islands"},
+	{"Pacific/Kiritimati", "KI", "Line Islands"},
+	{"Pacific/Kosrae", "FM", "Kosrae"},
+	{"Pacific/Kwajalein", "MH", "Kwajalein"},
+	{"Pacific/Majuro", "MH", "most of Marshall Islands"},
+	{"Pacific/Marquesas", "PF", "Marquesas Islands"},
+	{"Pacific/Midway", "UM", "Midway Islands"},
+	{"Pacific/Nauru", "NR", ""},
+	{"Pacific/Niue", "NU", ""},
+	{"Pacific/Norfolk", "NF", ""},
+	{"Pacific/Noumea", "NC", ""},
+	{"Pacific/Pago_Pago", "AS", ""},
+	{"Pacific/Palau", "PW", ""},
+	{"Pacific/Pitcairn", "PN", ""},
+	{"Pacific/Pohnpei", "FM", "Pohnpei/Ponape"},
+	{"Pacific/Port_Moresby", "PG", "most of Papua New Guinea"},
+	{"Pacific/Rarotonga", "CK", ""},
+	{"Pacific/Saipan", "MP", ""},
+	{"Pacific/Tahiti", "PF", "Society Islands"},
+	{"Pacific/Tarawa", "KI", "Gilbert Islands"},
+	{"Pacific/Tongatapu", "TO", ""},
+	{"Pacific/Wake", "UM", "Wake Island"},
+	{"Pacific/Wallis", "WF", ""},
+}
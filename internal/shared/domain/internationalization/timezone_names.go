@@ -0,0 +1,130 @@
+// Package internationalization provides domain types for handling internationalization
+// concerns such as time, currency, timezone, and phone number formatting.
+//
+// This file holds localized timezone display names. The embedded
+// timezone_names.json is a subset of CLDR 46: the metazone of each zone that
+// has one, e.g. Europe/Berlin is "Europe_Central", the generic long names of
+// those metazones in en, de, fr, es, ja and zh, and each language's region
+// format for the zones without a name. Names are resolved as CLDR does:
+//
+//   - UTC has its own name, e.g. "Koordinierte Weltzeit" in de
+//   - Zones with a metazone named in the language use that name, e.g.
+//     "Mitteleuropäische Zeit" for Europe/Berlin in de
+//   - Other zones use the region format with the city of the ID, e.g.
+//     "Casablanca (Ortszeit)" in de or "Casablanca Time" in en
+//
+// Languages without data use English. Resolved names are memoized per zone
+// and language for the life of the process.
+package internationalization
+
+import (
+	_ "embed"
+	"encoding/json"
+	"strings"
+	"sync"
+)
+
+//go:embed timezone_names.json
+var timezoneNamesJSON []byte
+
+// timezoneNameData is the decoded timezone_names.json
+type timezoneNameData struct {
+	// UTC is the name of UTC by language
+	UTC map[Language]string `json:"utc"`
+	// RegionFormat is the pattern for zones without a name, by language; {0}
+	// is the city
+	RegionFormat map[Language]string `json:"region_format"`
+	// Metazones maps IANA IDs to CLDR metazones
+	Metazones map[string]string `json:"metazones"`
+	// Names are the generic long metazone names by language
+	Names map[Language]map[string]string `json:"names"`
+}
+
+// timezoneNames decodes the embedded data on first use
+var timezoneNames = sync.OnceValue(func() *timezoneNameData {
+	var data timezoneNameData
+	if err := json.Unmarshal(timezoneNamesJSON, &data); err != nil {
+		panic("internationalization: invalid embedded timezone names: " + err.Error())
+	}
+	return &data
+})
+
+// displayNameKey identifies a memoized display name. The language is one
+// with data, so the cache is bounded by the zones times the languages.
+type displayNameKey struct {
+	id       string
+	language Language
+}
+
+// displayNameCache maps displayNameKey to the resolved name
+var displayNameCache sync.Map
+
+// DisplayName returns the timezone's generic name in locale, e.g. "Central
+// European Time" in en and "heure d’Europe centrale" in fr for Europe/Paris.
+// Locales without data use English.
+func (tz Timezone) DisplayName(locale Locale) string {
+	return timezoneDisplayName(tz.ID, locale)
+}
+
+// timezoneDisplayName resolves and memoizes the display name of id in locale.
+// Only IDs that load are memoized, so arbitrary input cannot grow the cache.
+func timezoneDisplayName(id string, locale Locale) string {
+	data := timezoneNames()
+	key := displayNameKey{id: id, language: data.language(locale)}
+	if name, ok := displayNameCache.Load(key); ok {
+		return name.(string)
+	}
+
+	name := data.displayName(key.id, key.language)
+	if _, err := loadLocation(id); err == nil {
+		displayNameCache.Store(key, name)
+	}
+	return name
+}
+
+// language returns the language of locale the data covers, or "en"
+func (d *timezoneNameData) language(locale Locale) Language {
+	language := locale.Language()
+	if language == "zh" && isTraditionalChinese(locale) {
+		// The Chinese names are Simplified
+		return "en"
+	}
+	if _, ok := d.RegionFormat[language]; !ok {
+		return "en"
+	}
+	return language
+}
+
+// isTraditionalChinese reports whether a zh locale is written in Traditional
+// characters
+func isTraditionalChinese(locale Locale) bool {
+	switch locale.Script() {
+	case "Hant":
+		return true
+	case "Hans":
+		return false
+	}
+	switch locale.Region() {
+	case "TW", "HK", "MO":
+		return true
+	}
+	return false
+}
+
+// displayName resolves the name of id in a language with data
+func (d *timezoneNameData) displayName(id string, language Language) string {
+	if id == "UTC" || id == "Etc/UTC" {
+		return d.UTC[language]
+	}
+	if name, ok := d.Names[language][d.Metazones[id]]; ok {
+		return name
+	}
+	return strings.Replace(d.RegionFormat[language], "{0}", exemplarCity(id), 1)
+}
+
+// exemplarCity returns the city of an IANA ID, e.g. "Sao Paulo" for
+// America/Sao_Paulo
+func exemplarCity(id string) string {
+	city := id[strings.LastIndexByte(id, '/')+1:]
+	return strings.ReplaceAll(city, "_", " ")
+}
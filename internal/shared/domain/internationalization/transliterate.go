@@ -0,0 +1,147 @@
+// Package internationalization provides domain types and composite types for handling
+// internationalized data such as time, currency, timezone, and phone numbers.
+// This file contains transliteration to ASCII and slug generation.
+//
+// Transliteration:
+//   - Accents are stripped after NFKD decomposition ("é" -> "e", "ﬁ" -> "fi")
+//   - Letters without a decomposition use a shared table ("ß" -> "ss", "ł" -> "l")
+//   - Cyrillic and Greek are romanized
+//   - Language rules take precedence, e.g. German "ö" -> "oe", Danish "å" -> "aa"
+//   - Characters without a romanization (e.g. Han) are dropped
+//
+// Usage Examples:
+//
+//	Transliterate("Grüße aus Köln", MustParseLocale("de")) // "Gruesse aus Koeln"
+//	Transliterate("Grüße aus Köln", MustParseLocale("en")) // "Grusse aus Koln"
+//	Slugify("Crème Brûlée & Co.", "fr-FR")                 // "creme-brulee-co"
+package internationalization
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// languageTransliterations holds per-language rules applied before the
+// generic ones
+var languageTransliterations = map[Language]map[rune]string{
+	"de": {'ä': "ae", 'ö': "oe", 'ü': "ue", 'Ä': "Ae", 'Ö': "Oe", 'Ü': "Ue"},
+	"da": {'æ': "ae", 'ø': "oe", 'å': "aa", 'Æ': "Ae", 'Ø': "Oe", 'Å': "Aa"},
+	"nb": {'æ': "ae", 'ø': "oe", 'å': "aa", 'Æ': "Ae", 'Ø': "Oe", 'Å': "Aa"},
+	"nn": {'æ': "ae", 'ø': "oe", 'å': "aa", 'Æ': "Ae", 'Ø': "Oe", 'Å': "Aa"},
+	"no": {'æ': "ae", 'ø': "oe", 'å': "aa", 'Æ': "Ae", 'Ø': "Oe", 'Å': "Aa"},
+	"sv": {'ä': "ae", 'ö': "oe", 'Ä': "Ae", 'Ö': "Oe"},
+	"uk": {'г': "h", 'Г': "H", 'и': "y", 'И': "Y", 'і': "i", 'І': "I", 'ї': "i", 'Ї': "Yi", 'є': "ie", 'Є': "Ye"},
+}
+
+// transliterations romanizes letters NFKD does not decompose to ASCII
+var transliterations = map[rune]string{
+	// Latin
+	'ß': "ss", 'ẞ': "SS", 'æ': "ae", 'Æ': "AE", 'œ': "oe", 'Œ': "OE", 'ø': "o", 'Ø': "O",
+	'đ': "d", 'Đ': "D", 'ð': "d", 'Ð': "D", 'þ': "th", 'Þ': "Th", 'ł': "l", 'Ł': "L",
+	'ı': "i", 'ħ': "h", 'Ħ': "H", 'ŋ': "ng", 'Ŋ': "Ng",
+
+	// Cyrillic (Russian scientific-style, simplified)
+	'а': "a", 'б': "b", 'в': "v", 'г': "g", 'д': "d", 'е': "e", 'ё': "yo", 'ж': "zh",
+	'з': "z", 'и': "i", 'й': "y", 'к': "k", 'л': "l", 'м': "m", 'н': "n", 'о': "o",
+	'п': "p", 'р': "r", 'с': "s", 'т': "t", 'у': "u", 'ф': "f", 'х': "kh", 'ц': "ts",
+	'ч': "ch", 'ш': "sh", 'щ': "shch", 'ъ': "", 'ы': "y", 'ь': "", 'э': "e", 'ю': "yu",
+	'я': "ya", 'і': "i", 'ї': "yi", 'є': "ye", 'ґ': "g",
+	'А': "A", 'Б': "B", 'В': "V", 'Г': "G", 'Д': "D", 'Е': "E", 'Ё': "Yo", 'Ж': "Zh",
+	'З': "Z", 'И': "I", 'Й': "Y", 'К': "K", 'Л': "L", 'М': "M", 'Н': "N", 'О': "O",
+	'П': "P", 'Р': "R", 'С': "S", 'Т': "T", 'У': "U", 'Ф': "F", 'Х': "Kh", 'Ц': "Ts",
+	'Ч': "Ch", 'Ш': "Sh", 'Щ': "Shch", 'Ъ': "", 'Ы': "Y", 'Ь': "", 'Э': "E", 'Ю': "Yu",
+	'Я': "Ya", 'І': "I", 'Ї': "Yi", 'Є': "Ye", 'Ґ': "G",
+
+	// Greek (ELOT 743, simplified)
+	'α': "a", 'β': "v", 'γ': "g", 'δ': "d", 'ε': "e", 'ζ': "z", 'η': "i", 'θ': "th",
+	'ι': "i", 'κ': "k", 'λ': "l", 'μ': "m", 'ν': "n", 'ξ': "x", 'ο': "o", 'π': "p",
+	'ρ': "r", 'σ': "s", 'ς': "s", 'τ': "t", 'υ': "y", 'φ': "f", 'χ': "ch", 'ψ': "ps",
+	'ω': "o",
+	'Α': "A", 'Β': "V", 'Γ': "G", 'Δ': "D", 'Ε': "E", 'Ζ': "Z", 'Η': "I", 'Θ': "Th",
+	'Ι': "I", 'Κ': "K", 'Λ': "L", 'Μ': "M", 'Ν': "N", 'Ξ': "X", 'Ο': "O", 'Π': "P",
+	'Ρ': "R", 'Σ': "S", 'Τ': "T", 'Υ': "Y", 'Φ': "F", 'Χ': "Ch", 'Ψ': "Ps", 'Ω': "O",
+
+	// Punctuation
+	'‘': "'", '’': "'", '“': "\"", '”': "\"", '–': "-", '—': "-", '…': "...", '€': "EUR",
+}
+
+// Transliterate converts text to ASCII using the rules of locale's language.
+// Language rules apply to the precomposed text, so a German "ü" becomes "ue"
+// while other languages get "u". Characters without a romanization are
+// dropped.
+func Transliterate(text string, locale Locale) string {
+	rules := languageTransliterations[locale.Language()]
+
+	var b strings.Builder
+	b.Grow(len(text))
+	for _, r := range norm.NFC.String(text) {
+		if r <= unicode.MaxASCII {
+			b.WriteRune(r)
+			continue
+		}
+		if replacement, ok := rules[r]; ok {
+			b.WriteString(replacement)
+			continue
+		}
+		writeTransliterated(&b, r)
+	}
+	return b.String()
+}
+
+// writeTransliterated writes the ASCII form of a single non-ASCII rune
+func writeTransliterated(b *strings.Builder, r rune) {
+	if replacement, ok := transliterations[r]; ok {
+		b.WriteString(replacement)
+		return
+	}
+	if unicode.IsSpace(r) {
+		b.WriteByte(' ')
+		return
+	}
+	for _, d := range norm.NFKD.String(string(r)) {
+		switch {
+		case d <= unicode.MaxASCII:
+			b.WriteRune(d)
+		case unicode.Is(unicode.Mn, d):
+			// Combining accent stripped by the decomposition
+		default:
+			if replacement, ok := transliterations[d]; ok {
+				b.WriteString(replacement)
+			}
+		}
+	}
+}
+
+// Slugify returns a lower-case, hyphen-separated ASCII slug for text,
+// transliterated with the rules of locale's language. Runs of anything other
+// than letters and digits become a single hyphen; apostrophes are removed
+// so "L'Été" becomes "lete". The result is empty when nothing can be
+// romanized.
+func Slugify(text string, locale Locale) string {
+	ascii := Transliterate(text, locale)
+
+	var b strings.Builder
+	b.Grow(len(ascii))
+	pendingHyphen := false
+	for i := 0; i < len(ascii); i++ {
+		c := ascii[i]
+		switch {
+		case c >= 'A' && c <= 'Z':
+			c += 'a' - 'A'
+			fallthrough
+		case c >= 'a' && c <= 'z', c >= '0' && c <= '9':
+			if pendingHyphen && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			pendingHyphen = false
+			b.WriteByte(c)
+		case c == '\'':
+			// Elisions and possessives are joined: "lete", "joes"
+		default:
+			pendingHyphen = true
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,83 @@
+// Package internationalization provides domain types for handling internationalization
+// concerns such as time, currency, timezone, and phone number formatting.
+//
+// Value semantics:
+//
+// Money, Currency, Time, Timezone, Phone, LocalizedDateTime and LocalizedPhone
+// are immutable values. Every read-only method has a value receiver and every
+// operation returns a new instance instead of modifying its receiver, so the
+// types can be stored directly in slices, maps and structs:
+//
+//	grid := make([]Money, 0, len(rows)) // one allocation for the whole grid
+//	for _, row := range rows {
+//	    price, err := MakeMoney(row.Amount, row.Currency)
+//	    ...
+//	    grid = append(grid, price)
+//	}
+//
+// The New* constructors return pointers for compatibility; the Make*
+// constructors in this file return values and avoid a heap allocation per
+// element. Only UnmarshalJSON, Scan and the nil-tolerant comparisons (Equal,
+// IsEqual, IsSameCountry, IsSameRegion) keep pointer receivers; they work on
+// any addressable value, such as a slice element or local variable.
+package internationalization
+
+// MakeMoney returns a Money value from an amount in minor units and an ISO
+// 4217 code; it is the value counterpart of NewMoneyFromPrimitive.
+func MakeMoney(amount int64, currencyCode string) (Money, error) {
+	currency, err := NewCurrencyFromCode(currencyCode)
+	if err != nil {
+		return Money{}, err
+	}
+
+	m, err := constrainMoney(&Money{Amount: amount, Currency: *currency})
+	if err != nil {
+		return Money{}, err
+	}
+	return *m, nil
+}
+
+// MakeTime returns a validated Time value; it is the value counterpart of NewTime.
+func MakeTime(epoch int64) (Time, error) {
+	t, err := NewTime(epoch)
+	if err != nil {
+		return Time{}, err
+	}
+	return *t, nil
+}
+
+// MakeTimezone returns a Timezone value for an IANA identifier; it is the
+// value counterpart of NewTimezoneFromID.
+func MakeTimezone(id string) (Timezone, error) {
+	tz, err := NewTimezoneFromID(id)
+	if err != nil {
+		return Timezone{}, err
+	}
+	return *tz, nil
+}
+
+// MakePhone returns a validated Phone value; it is the value counterpart of NewPhone.
+func MakePhone(countryCode, number string) (Phone, error) {
+	phone, err := NewPhone(countryCode, number)
+	if err != nil {
+		return Phone{}, err
+	}
+	return *phone, nil
+}
+
+// MakeLocalizedDateTime returns a LocalizedDateTime value from primitive
+// database values; it is the value counterpart of
+// NewLocalizedDateTimeFromPrimitive.
+func MakeLocalizedDateTime(epoch int64, timezoneID string) (LocalizedDateTime, error) {
+	t, err := MakeTime(epoch)
+	if err != nil {
+		return LocalizedDateTime{}, err
+	}
+
+	tz, err := MakeTimezone(timezoneID)
+	if err != nil {
+		return LocalizedDateTime{}, err
+	}
+
+	return LocalizedDateTime{Time: t, Timezone: tz}, nil
+}
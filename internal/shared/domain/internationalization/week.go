@@ -0,0 +1,234 @@
+// Package internationalization provides domain types and composite types for handling
+// internationalized data such as time, currency, timezone, and phone numbers.
+// This file contains the WeekDefinition type for first-day-of-week and
+// weekend conventions.
+//
+// WeekDefinition Type:
+//   - First day of the week (Monday in most of the world, Sunday in the US,
+//     Saturday in much of the Middle East)
+//   - Weekend days as a range (Sat–Sun, Fri–Sat, or a single day)
+//   - Per-country data following CLDR, exposed on Country and Locale
+//   - StartOfWeek and business-day arithmetic on the local calendar
+//
+// JSON Format: {"first_day": 1, "weekend_start": 6, "weekend_end": 0}
+//
+// Usage Examples:
+//
+//	week := MustParseLocale("ar-AE").Week()
+//	week.IsWeekend(time.Friday)              // true
+//	due, err := week.AddBusinessDays(now, 3) // skips Fridays and Saturdays
+//	start, err := ldt.StartOfWeek(Country("US").Week()) // Sunday 00:00 local time
+package internationalization
+
+import (
+	"time"
+
+	"golang-arch/internal/shared/domain/domainerror"
+	"golang-arch/internal/shared/domain/validation"
+)
+
+// WeekDefinition describes which day a week starts on and which days form
+// the weekend. The weekend runs from WeekendStart to WeekendEnd inclusive,
+// wrapping past Saturday, so Saturday–Sunday is {Saturday, Sunday}.
+type WeekDefinition struct {
+	FirstDay     time.Weekday `json:"first_day"`     // Day a calendar week starts on
+	WeekendStart time.Weekday `json:"weekend_start"` // First weekend day
+	WeekendEnd   time.Weekday `json:"weekend_end"`   // Last weekend day
+}
+
+// DefaultWeek is the ISO 8601 week: Monday first, Saturday–Sunday weekend.
+var DefaultWeek = WeekDefinition{FirstDay: time.Monday, WeekendStart: time.Saturday, WeekendEnd: time.Sunday}
+
+// countryFirstDays lists the countries whose week does not start on Monday
+var countryFirstDays = map[Country]time.Weekday{
+	"MV": time.Friday,
+
+	"AE": time.Saturday, "AF": time.Saturday, "BH": time.Saturday, "DJ": time.Saturday,
+	"DZ": time.Saturday, "EG": time.Saturday, "IQ": time.Saturday, "IR": time.Saturday,
+	"JO": time.Saturday, "KW": time.Saturday, "LY": time.Saturday, "OM": time.Saturday,
+	"QA": time.Saturday, "SD": time.Saturday, "SY": time.Saturday,
+
+	"AG": time.Sunday, "AS": time.Sunday, "BD": time.Sunday, "BR": time.Sunday, "BS": time.Sunday,
+	"BT": time.Sunday, "BW": time.Sunday, "BZ": time.Sunday, "CA": time.Sunday, "CN": time.Sunday,
+	"CO": time.Sunday, "DM": time.Sunday, "DO": time.Sunday, "ET": time.Sunday, "GT": time.Sunday,
+	"GU": time.Sunday, "HK": time.Sunday, "HN": time.Sunday, "ID": time.Sunday, "IL": time.Sunday,
+	"IN": time.Sunday, "JM": time.Sunday, "JP": time.Sunday, "KE": time.Sunday, "KH": time.Sunday,
+	"KR": time.Sunday, "LA": time.Sunday, "MH": time.Sunday, "MM": time.Sunday, "MO": time.Sunday,
+	"MT": time.Sunday, "MX": time.Sunday, "MZ": time.Sunday, "NI": time.Sunday, "NP": time.Sunday,
+	"PA": time.Sunday, "PE": time.Sunday, "PH": time.Sunday, "PK": time.Sunday, "PR": time.Sunday,
+	"PT": time.Sunday, "PY": time.Sunday, "SA": time.Sunday, "SG": time.Sunday, "SV": time.Sunday,
+	"TH": time.Sunday, "TT": time.Sunday, "TW": time.Sunday, "UM": time.Sunday, "US": time.Sunday,
+	"VE": time.Sunday, "VI": time.Sunday, "WS": time.Sunday, "YE": time.Sunday, "ZA": time.Sunday,
+	"ZW": time.Sunday,
+}
+
+// countryWeekends lists the countries whose weekend is not Saturday–Sunday
+var countryWeekends = map[Country][2]time.Weekday{
+	"AE": {time.Friday, time.Saturday}, "BH": {time.Friday, time.Saturday},
+	"DZ": {time.Friday, time.Saturday}, "EG": {time.Friday, time.Saturday},
+	"IL": {time.Friday, time.Saturday}, "IQ": {time.Friday, time.Saturday},
+	"JO": {time.Friday, time.Saturday}, "KW": {time.Friday, time.Saturday},
+	"LY": {time.Friday, time.Saturday}, "OM": {time.Friday, time.Saturday},
+	"QA": {time.Friday, time.Saturday}, "SA": {time.Friday, time.Saturday},
+	"SD": {time.Friday, time.Saturday}, "SY": {time.Friday, time.Saturday},
+	"YE": {time.Friday, time.Saturday},
+
+	"AF": {time.Thursday, time.Friday},
+	"IR": {time.Friday, time.Friday},
+	"IN": {time.Sunday, time.Sunday},
+	"UG": {time.Sunday, time.Sunday},
+}
+
+// Week returns the country's week definition; countries without specific
+// data use DefaultWeek.
+func (c Country) Week() WeekDefinition {
+	week := DefaultWeek
+	if firstDay, ok := countryFirstDays[c]; ok {
+		week.FirstDay = firstDay
+	}
+	if weekend, ok := countryWeekends[c]; ok {
+		week.WeekendStart, week.WeekendEnd = weekend[0], weekend[1]
+	}
+	return week
+}
+
+// Week returns the week definition of the locale's country, or DefaultWeek
+// when the locale has no country.
+func (l Locale) Week() WeekDefinition {
+	if country := l.Country(); country != "" {
+		return country.Week()
+	}
+	return DefaultWeek
+}
+
+// Validate ensures every day of the definition is a valid weekday.
+func (w WeekDefinition) Validate() error {
+	var errs validation.ValidationErrors
+	days := []struct {
+		field string
+		day   time.Weekday
+	}{
+		{"first_day", w.FirstDay},
+		{"weekend_start", w.WeekendStart},
+		{"weekend_end", w.WeekendEnd},
+	}
+	for _, d := range days {
+		if d.day < time.Sunday || d.day > time.Saturday {
+			errs.Add(d.field, validation.CodeOutOfRange, "day must be between 0 (Sunday) and 6 (Saturday)",
+				map[string]any{"min": 0, "max": 6})
+		}
+	}
+	return errs.Err()
+}
+
+// Weekend returns the weekend days in order.
+func (w WeekDefinition) Weekend() []time.Weekday {
+	days := []time.Weekday{w.WeekendStart}
+	for day := w.WeekendStart; day != w.WeekendEnd && len(days) < 7; {
+		day = (day + 1) % 7
+		days = append(days, day)
+	}
+	return days
+}
+
+// IsWeekend reports whether day is a weekend day.
+func (w WeekDefinition) IsWeekend(day time.Weekday) bool {
+	return (day-w.WeekendStart+7)%7 <= (w.WeekendEnd-w.WeekendStart+7)%7
+}
+
+// IsBusinessDay reports whether t falls on a weekday that is not part of the
+// weekend, in t's location.
+func (w WeekDefinition) IsBusinessDay(t time.Time) bool {
+	return !w.IsWeekend(t.Weekday())
+}
+
+// StartOfWeek returns midnight of the first day of the week containing t, in
+// t's location.
+func (w WeekDefinition) StartOfWeek(t time.Time) time.Time {
+	back := int(t.Weekday()-w.FirstDay+7) % 7
+	year, month, day := t.Date()
+	return time.Date(year, month, day-back, 0, 0, 0, 0, t.Location())
+}
+
+// AddBusinessDays moves t by n business days, skipping weekend days and
+// keeping the wall-clock time; negative n moves backwards. A start on a
+// weekend counts from that weekend, so Saturday plus one business day is
+// Monday in a Saturday–Sunday week.
+func (w WeekDefinition) AddBusinessDays(t time.Time, n int) (time.Time, error) {
+	if err := w.Validate(); err != nil {
+		return time.Time{}, err
+	}
+	if len(w.Weekend()) == 7 {
+		return time.Time{}, domainerror.Invalidf("week definition has no business days")
+	}
+
+	step := 1
+	if n < 0 {
+		step, n = -1, -n
+	}
+	year, month, day := t.Date()
+	hour, minute, second := t.Clock()
+	for n > 0 {
+		day += step
+		if !w.IsWeekend(time.Date(year, month, day, 12, 0, 0, 0, t.Location()).Weekday()) {
+			n--
+		}
+	}
+	return time.Date(year, month, day, hour, minute, second, t.Nanosecond(), t.Location()), nil
+}
+
+// BusinessDaysBetween counts the business days from the calendar date of
+// from up to, but not including, the calendar date of to, in from's
+// location. It is negative when to is before from.
+func (w WeekDefinition) BusinessDaysBetween(from, to time.Time) int {
+	to = to.In(from.Location())
+	start := dateOnly(from)
+	end := dateOnly(to)
+
+	sign := 1
+	if end.Before(start) {
+		start, end, sign = end, start, -1
+	}
+
+	// Whole weeks contribute a fixed count; walk only the remainder
+	days := int(end.Sub(start).Hours()/24 + 0.5)
+	count := (days / 7) * (7 - len(w.Weekend()))
+	for i := 0; i < days%7; i++ {
+		if !w.IsWeekend((start.Weekday() + time.Weekday(i)) % 7) {
+			count++
+		}
+	}
+	return sign * count
+}
+
+// dateOnly returns midnight UTC of t's calendar date, so day differences are
+// not skewed by DST.
+func dateOnly(t time.Time) time.Time {
+	year, month, day := t.Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+// StartOfWeek returns midnight local time of the first day of the week
+// containing the localized datetime.
+func (ldt LocalizedDateTime) StartOfWeek(week WeekDefinition) (*LocalizedDateTime, error) {
+	loc, err := ldt.Timezone.GetLocation()
+	if err != nil {
+		return nil, err
+	}
+	start := week.StartOfWeek(ldt.Time.ToTime().In(loc))
+	return NewLocalizedDateTime(*NewTimeFromTime(start), ldt.Timezone)
+}
+
+// AddBusinessDays moves the localized datetime by n business days of week,
+// keeping its local wall-clock time.
+func (ldt LocalizedDateTime) AddBusinessDays(week WeekDefinition, n int) (*LocalizedDateTime, error) {
+	loc, err := ldt.Timezone.GetLocation()
+	if err != nil {
+		return nil, err
+	}
+	moved, err := week.AddBusinessDays(ldt.Time.ToTime().In(loc), n)
+	if err != nil {
+		return nil, err
+	}
+	return NewLocalizedDateTime(*NewTimeFromTime(moved), ldt.Timezone)
+}
@@ -0,0 +1,177 @@
+// Package validation provides the shared Validator contract and an aggregated
+// ValidationErrors type used by domain value objects and the HTTP layer.
+//
+// A ValidationErrors value collects every field-level problem instead of
+// stopping at the first one. Each FieldError carries a stable machine-readable
+// Code and Params so API clients and translators can render the message in the
+// user's language, while Message keeps a default English text.
+package validation
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	"golang-arch/internal/shared/domain/domainerror"
+)
+
+// Validator is implemented by any type that can check its own invariants
+type Validator interface {
+	Validate() error
+}
+
+// Common validation codes
+const (
+	CodeRequired      = "required"
+	CodeInvalidFormat = "invalid_format"
+	CodeOutOfRange    = "out_of_range"
+	CodeUnsupported   = "unsupported"
+	CodeInvalid       = "invalid"
+)
+
+// FieldError describes a single problem with a field
+type FieldError struct {
+	Field   string         `json:"field"`
+	Code    string         `json:"code"`
+	Message string         `json:"message"`
+	Params  map[string]any `json:"params,omitempty"`
+}
+
+// Error implements the error interface
+func (fe FieldError) Error() string {
+	return fe.Message
+}
+
+// ValidationErrors is an ordered collection of field errors
+type ValidationErrors []FieldError
+
+// Add appends a field error
+func (ve *ValidationErrors) Add(field, code, message string, params map[string]any) {
+	*ve = append(*ve, FieldError{Field: field, Code: code, Message: message, Params: params})
+}
+
+// Merge appends the errors reported for a nested field. Field names of nested
+// ValidationErrors are prefixed with field and their messages with context;
+// any other error is recorded as a single CodeInvalid entry.
+func (ve *ValidationErrors) Merge(field, context string, err error) {
+	if err == nil {
+		return
+	}
+
+	var nested ValidationErrors
+	if !errors.As(err, &nested) {
+		ve.Add(field, CodeInvalid, withContext(context, err.Error()), nil)
+		return
+	}
+
+	for _, fe := range nested {
+		fe.Field = JoinField(field, fe.Field)
+		fe.Message = withContext(context, fe.Message)
+		*ve = append(*ve, fe)
+	}
+}
+
+// Err returns nil when no errors were collected, otherwise the collection
+func (ve ValidationErrors) Err() error {
+	if len(ve) == 0 {
+		return nil
+	}
+	return ve
+}
+
+// Error implements the error interface
+func (ve ValidationErrors) Error() string {
+	switch len(ve) {
+	case 0:
+		return "validation failed"
+	case 1:
+		return ve[0].Message
+	}
+
+	var b strings.Builder
+	b.WriteString(strconv.Itoa(len(ve)))
+	b.WriteString(" validation errors: ")
+	for i, fe := range ve {
+		if i > 0 {
+			b.WriteString("; ")
+		}
+		b.WriteString(fe.Message)
+	}
+	return b.String()
+}
+
+// Unwrap exposes the individual field errors to errors.Is and errors.As
+func (ve ValidationErrors) Unwrap() []error {
+	errs := make([]error, len(ve))
+	for i, fe := range ve {
+		errs[i] = fe
+	}
+	return errs
+}
+
+// Is reports validation failures as domainerror.Invalid
+func (ve ValidationErrors) Is(target error) bool {
+	return target == domainerror.Invalid
+}
+
+// ByField groups the errors by field name
+func (ve ValidationErrors) ByField() map[string][]FieldError {
+	fields := make(map[string][]FieldError, len(ve))
+	for _, fe := range ve {
+		fields[fe.Field] = append(fields[fe.Field], fe)
+	}
+	return fields
+}
+
+// Translator renders a localized message for a field error
+type Translator func(fe FieldError) string
+
+// Localize returns a copy of the errors with messages rendered by translate.
+// An empty translation keeps the default message.
+func (ve ValidationErrors) Localize(translate Translator) ValidationErrors {
+	localized := make(ValidationErrors, len(ve))
+	for i, fe := range ve {
+		if msg := translate(fe); msg != "" {
+			fe.Message = msg
+		}
+		localized[i] = fe
+	}
+	return localized
+}
+
+// FromError extracts ValidationErrors from err. Errors that are not
+// ValidationErrors are returned as a single CodeInvalid entry without a field.
+func FromError(err error) ValidationErrors {
+	if err == nil {
+		return nil
+	}
+
+	var ve ValidationErrors
+	if errors.As(err, &ve) {
+		return ve
+	}
+	return ValidationErrors{{Code: CodeInvalid, Message: err.Error()}}
+}
+
+// Validate runs v.Validate and returns the aggregated errors, or nil
+func Validate(v Validator) ValidationErrors {
+	return FromError(v.Validate())
+}
+
+// JoinField joins nested field names with a dot, skipping empty parts
+func JoinField(parent, child string) string {
+	switch {
+	case parent == "":
+		return child
+	case child == "":
+		return parent
+	}
+	return parent + "." + child
+}
+
+func withContext(context, message string) string {
+	if context == "" {
+		return message
+	}
+	return context + ": " + message
+}
@@ -0,0 +1,142 @@
+// Package erasure implements right-to-be-forgotten requests. Repositories
+// that hold personal data register an Eraser; Service.Erase runs them all
+// for one subject and records an audit trail of what was erased, without
+// the erased data itself.
+//
+// Erasers replace personal fields with values from an Anonymizer rather
+// than deleting rows, so foreign keys, NOT NULL and unique constraints and
+// aggregate statistics survive the erasure.
+package erasure
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	i18n "golang-arch/internal/shared/domain/internationalization"
+)
+
+// Strategy decides how replacement values are derived
+type Strategy string
+
+// Erasure strategies
+const (
+	// StrategyScrub uses random replacements that cannot be linked to each
+	// other or to the original values
+	StrategyScrub Strategy = "scrub"
+	// StrategyPseudonymize derives replacements from the original values
+	// with a keyed hash, so the same email erased in two repositories maps
+	// to the same pseudonym and records can still be joined
+	StrategyPseudonymize Strategy = "pseudonymize"
+)
+
+// ParseStrategy validates a strategy name
+func ParseStrategy(value string) (Strategy, error) {
+	switch strategy := Strategy(strings.ToLower(strings.TrimSpace(value))); strategy {
+	case StrategyScrub, StrategyPseudonymize:
+		return strategy, nil
+	default:
+		return "", fmt.Errorf("unknown erasure strategy %q (expected scrub or pseudonymize)", value)
+	}
+}
+
+// ErasedText replaces free text, such as address lines and notes
+const ErasedText = "[erased]"
+
+// erasedEmailDomain is reserved (RFC 2606), so replacement addresses never
+// receive mail
+const erasedEmailDomain = "erased.invalid"
+
+// Anonymizer produces replacement values for personal data. Replacements
+// have the shape of the original (a valid email address, a phone number of
+// the same country and length) so they pass the same validation.
+type Anonymizer struct {
+	strategy Strategy
+	secret   []byte
+}
+
+// NewAnonymizer creates an anonymizer; secret keys pseudonyms and is unused
+// when scrubbing
+func NewAnonymizer(strategy Strategy, secret []byte) *Anonymizer {
+	return &Anonymizer{strategy: strategy, secret: secret}
+}
+
+// Strategy returns how replacements are derived
+func (a *Anonymizer) Strategy() Strategy {
+	return a.strategy
+}
+
+// Token returns a 16-character hex replacement for value. kind separates
+// namespaces, so a name and an email with the same text get different
+// pseudonyms.
+func (a *Anonymizer) Token(kind, value string) string {
+	return hex.EncodeToString(a.digest(kind, value)[:8])
+}
+
+// Name replaces a person's name
+func (a *Anonymizer) Name(value string) string {
+	if value == "" {
+		return ""
+	}
+	return "Erased " + a.Token("name", strings.ToLower(strings.TrimSpace(value)))[:8]
+}
+
+// Email replaces an email address with a unique address that cannot receive
+// mail
+func (a *Anonymizer) Email(value string) string {
+	if value == "" {
+		return ""
+	}
+	return a.Token("email", strings.ToLower(strings.TrimSpace(value))) + "@" + erasedEmailDomain
+}
+
+// Phone replaces the subscriber number, keeping the country code and length
+// so country statistics survive. The replacement may be someone else's real
+// number: never send messages to erased records.
+func (a *Anonymizer) Phone(phone i18n.Phone) i18n.Phone {
+	if phone.Number == "" {
+		return phone
+	}
+	digest := a.digest("phone", phone.FormatCompact())
+	number := make([]byte, len(phone.Number))
+	for i := range number {
+		number[i] = '0' + digest[i%len(digest)]%10
+	}
+	if number[0] == '0' {
+		number[0] = '1'
+	}
+	return i18n.Phone{CountryCode: phone.CountryCode, Number: string(number)}
+}
+
+// LocalizedPhone replaces the number and region, keeping country and
+// timezone
+func (a *Anonymizer) LocalizedPhone(phone i18n.LocalizedPhone) i18n.LocalizedPhone {
+	phone.Phone = a.Phone(phone.Phone)
+	phone.Region = ""
+	return phone
+}
+
+// Text replaces free text such as address lines; it keeps empty values
+// empty
+func (a *Anonymizer) Text(value string) string {
+	if value == "" {
+		return ""
+	}
+	return ErasedText
+}
+
+// digest is an HMAC of value when pseudonymizing and random bytes when
+// scrubbing
+func (a *Anonymizer) digest(kind, value string) []byte {
+	if a.strategy != StrategyPseudonymize {
+		random := make([]byte, sha256.Size)
+		_, _ = rand.Read(random)
+		return random
+	}
+	mac := hmac.New(sha256.New, a.secret)
+	mac.Write([]byte(kind + "\x00" + value))
+	return mac.Sum(nil)
+}
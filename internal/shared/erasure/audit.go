@@ -0,0 +1,161 @@
+package erasure
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Status summarizes the outcome of an erasure
+type Status string
+
+// Erasure outcomes
+const (
+	StatusCompleted Status = "completed"
+	StatusPartial   Status = "partial" // Some repositories failed; erase again
+)
+
+// Result is the outcome of one repository
+type Result struct {
+	Repository string `json:"repository"`
+	Records    int    `json:"records"`
+	Error      string `json:"error,omitempty"`
+}
+
+// Record is the audit trail of an erasure. It proves what was erased, when
+// and why, and holds no erased data.
+type Record struct {
+	ID          string    `json:"id"`
+	SubjectID   string    `json:"subject_id"`
+	Strategy    Strategy  `json:"strategy"`
+	Reason      string    `json:"reason"`
+	RequestedBy string    `json:"requested_by"`
+	RequestedAt time.Time `json:"requested_at"`
+	CompletedAt time.Time `json:"completed_at"`
+	Results     []Result  `json:"results"`
+}
+
+// Status reports whether every repository was erased
+func (r Record) Status() Status {
+	for _, result := range r.Results {
+		if result.Error != "" {
+			return StatusPartial
+		}
+	}
+	return StatusCompleted
+}
+
+// Records returns the number of records changed across repositories
+func (r Record) Records() int {
+	total := 0
+	for _, result := range r.Results {
+		total += result.Records
+	}
+	return total
+}
+
+// AuditStore keeps erasure records. Records are append-only and must be
+// retained for as long as the law requires proof of erasure.
+type AuditStore interface {
+	Save(ctx context.Context, record Record) error
+	ForSubject(ctx context.Context, subjectID string) ([]Record, error)
+}
+
+// PostgresAuditStore keeps records in the erasure_audit table
+type PostgresAuditStore struct {
+	db *sql.DB
+}
+
+// NewPostgresAuditStore creates a store on db
+func NewPostgresAuditStore(db *sql.DB) *PostgresAuditStore {
+	return &PostgresAuditStore{db: db}
+}
+
+// Save inserts record
+func (s *PostgresAuditStore) Save(ctx context.Context, record Record) error {
+	results, err := json.Marshal(record.Results)
+	if err != nil {
+		return fmt.Errorf("failed to encode erasure results: %w", err)
+	}
+	_, err = s.db.ExecContext(ctx,
+		`INSERT INTO erasure_audit (id, subject_id, strategy, reason, requested_by, status, requested_at, completed_at, results)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`,
+		record.ID, record.SubjectID, string(record.Strategy), record.Reason, record.RequestedBy,
+		string(record.Status()), record.RequestedAt, record.CompletedAt, results,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save erasure audit record: %w", err)
+	}
+	return nil
+}
+
+// ForSubject returns the records of a subject, oldest first
+func (s *PostgresAuditStore) ForSubject(ctx context.Context, subjectID string) ([]Record, error) {
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT id, subject_id, strategy, reason, requested_by, requested_at, completed_at, results
+		FROM erasure_audit
+		WHERE subject_id = $1
+		ORDER BY requested_at`,
+		subjectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query erasure audit: %w", err)
+	}
+	defer rows.Close()
+
+	var records []Record
+	for rows.Next() {
+		var (
+			record   Record
+			strategy string
+			results  []byte
+		)
+		if err := rows.Scan(&record.ID, &record.SubjectID, &strategy, &record.Reason, &record.RequestedBy,
+			&record.RequestedAt, &record.CompletedAt, &results); err != nil {
+			return nil, fmt.Errorf("failed to scan erasure audit record: %w", err)
+		}
+		record.Strategy = Strategy(strategy)
+		if err := json.Unmarshal(results, &record.Results); err != nil {
+			return nil, fmt.Errorf("failed to decode erasure results: %w", err)
+		}
+		records = append(records, record)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read erasure audit: %w", err)
+	}
+	return records, nil
+}
+
+// MemoryAuditStore keeps records in memory, for tests
+type MemoryAuditStore struct {
+	mu      sync.Mutex
+	records []Record
+}
+
+// NewMemoryAuditStore creates an empty in-memory store
+func NewMemoryAuditStore() *MemoryAuditStore {
+	return &MemoryAuditStore{}
+}
+
+// Save appends record
+func (s *MemoryAuditStore) Save(_ context.Context, record Record) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.records = append(s.records, record)
+	return nil
+}
+
+// ForSubject returns the records of a subject in the order they were saved
+func (s *MemoryAuditStore) ForSubject(_ context.Context, subjectID string) ([]Record, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var records []Record
+	for _, record := range s.records {
+		if record.SubjectID == subjectID {
+			records = append(records, record)
+		}
+	}
+	return records, nil
+}
@@ -0,0 +1,205 @@
+package erasure
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"golang-arch/internal/shared/domain/domainerror"
+	i18n "golang-arch/internal/shared/domain/internationalization"
+	"golang-arch/pkg/clock"
+)
+
+// Subject identifies whose data to erase. Repositories find records by the
+// subject's ID or by any of the known contact details.
+type Subject struct {
+	ID     string       // Internal identifier, e.g. a user ID; kept in the audit record
+	Emails []string     // Addresses the subject used
+	Phones []i18n.Phone // Numbers the subject used
+}
+
+// Eraser anonymizes one repository's records of a subject and returns how
+// many records it changed. Erasers must be idempotent: a failed request is
+// retried by erasing again.
+type Eraser interface {
+	Erase(ctx context.Context, subject Subject, anonymizer *Anonymizer) (int, error)
+}
+
+// EraserFunc adapts a function to Eraser
+type EraserFunc func(ctx context.Context, subject Subject, anonymizer *Anonymizer) (int, error)
+
+// Erase calls f
+func (f EraserFunc) Erase(ctx context.Context, subject Subject, anonymizer *Anonymizer) (int, error) {
+	return f(ctx, subject, anonymizer)
+}
+
+// Request is a right-to-be-forgotten request
+type Request struct {
+	Subject     Subject
+	Reason      string   // e.g. "gdpr_art17", "account_closed"
+	RequestedBy string   // Operator or system that filed the request
+	Strategy    Strategy // Defaults to the service's strategy
+}
+
+// Service runs the registered erasers for a subject
+type Service struct {
+	audit    AuditStore
+	clock    clock.Clock
+	logger   *zap.Logger
+	secret   []byte
+	strategy Strategy
+
+	mu      sync.RWMutex
+	names   []string
+	erasers map[string]Eraser
+}
+
+// Option configures a Service
+type Option func(*Service)
+
+// WithClock sets the clock used for audit timestamps
+func WithClock(c clock.Clock) Option {
+	return func(s *Service) {
+		s.clock = c
+	}
+}
+
+// WithLogger sets the logger
+func WithLogger(logger *zap.Logger) Option {
+	return func(s *Service) {
+		s.logger = logger
+	}
+}
+
+// WithSecret sets the key of pseudonyms. It must stay the same across
+// instances and restarts for pseudonyms to stay consistent.
+func WithSecret(secret string) Option {
+	return func(s *Service) {
+		if secret != "" {
+			s.secret = []byte(secret)
+		}
+	}
+}
+
+// WithStrategy sets the default strategy
+func WithStrategy(strategy Strategy) Option {
+	return func(s *Service) {
+		s.strategy = strategy
+	}
+}
+
+// NewService creates an erasure service recording to audit. Without
+// WithSecret pseudonyms are keyed with a random secret and only consistent
+// until restart.
+func NewService(audit AuditStore, options ...Option) *Service {
+	s := &Service{
+		audit:    audit,
+		clock:    clock.New(),
+		logger:   zap.NewNop(),
+		strategy: StrategyScrub,
+		erasers:  make(map[string]Eraser),
+	}
+	for _, option := range options {
+		option(s)
+	}
+	if len(s.secret) == 0 {
+		s.secret = make([]byte, 32)
+		_, _ = rand.Read(s.secret)
+		if s.strategy == StrategyPseudonymize {
+			s.logger.Warn("No erasure secret configured, pseudonyms are only consistent until restart")
+		}
+	}
+	return s
+}
+
+// Register adds the eraser of a repository. Erasers run in registration
+// order. Registering a name twice panics.
+func (s *Service) Register(name string, eraser Eraser) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, exists := s.erasers[name]; exists {
+		panic(fmt.Sprintf("erasure: eraser %q registered twice", name))
+	}
+	s.names = append(s.names, name)
+	s.erasers[name] = eraser
+}
+
+// Repositories returns the names of the registered erasers
+func (s *Service) Repositories() []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return append([]string(nil), s.names...)
+}
+
+// Erase runs every eraser for the request's subject and saves an audit
+// record. A failing eraser does not stop the others; the record lists each
+// repository's outcome and the error joins the failures, so the request can
+// be retried.
+func (s *Service) Erase(ctx context.Context, request Request) (*Record, error) {
+	if strings.TrimSpace(request.Subject.ID) == "" {
+		return nil, domainerror.Invalidf("erasure subject id is required")
+	}
+	strategy := request.Strategy
+	if strategy == "" {
+		strategy = s.strategy
+	}
+	if _, err := ParseStrategy(string(strategy)); err != nil {
+		return nil, domainerror.Invalidf("%s", err)
+	}
+
+	record := &Record{
+		ID:          uuid.NewString(),
+		SubjectID:   request.Subject.ID,
+		Strategy:    strategy,
+		Reason:      request.Reason,
+		RequestedBy: request.RequestedBy,
+		RequestedAt: s.clock.Now(),
+	}
+	anonymizer := NewAnonymizer(strategy, s.secret)
+
+	s.mu.RLock()
+	names := append([]string(nil), s.names...)
+	erasers := make([]Eraser, len(names))
+	for i, name := range names {
+		erasers[i] = s.erasers[name]
+	}
+	s.mu.RUnlock()
+
+	var errs []error
+	for i, name := range names {
+		count, err := erasers[i].Erase(ctx, request.Subject, anonymizer)
+		result := Result{Repository: name, Records: count}
+		if err != nil {
+			result.Error = err.Error()
+			errs = append(errs, fmt.Errorf("%s: %w", name, err))
+			s.logger.Error("Erasure failed",
+				zap.String("erasure_id", record.ID),
+				zap.String("repository", name),
+				zap.Error(err))
+		}
+		record.Results = append(record.Results, result)
+	}
+	record.CompletedAt = s.clock.Now()
+
+	if err := s.audit.Save(ctx, *record); err != nil {
+		errs = append(errs, err)
+	}
+	s.logger.Info("Erasure completed",
+		zap.String("erasure_id", record.ID),
+		zap.String("subject_id", record.SubjectID),
+		zap.String("status", string(record.Status())),
+		zap.Int("records", record.Records()))
+
+	return record, errors.Join(errs...)
+}
+
+// History returns the audit records of a subject, oldest first
+func (s *Service) History(ctx context.Context, subjectID string) ([]Record, error) {
+	return s.audit.ForSubject(ctx, subjectID)
+}
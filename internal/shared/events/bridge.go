@@ -0,0 +1,166 @@
+package events
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Envelope is the serialized form of an event handed to outboxes and brokers
+type Envelope struct {
+	ID            string          `json:"id"`
+	Name          string          `json:"name"`
+	Version       int             `json:"version,omitempty"` // Schema version of the payload; 0 is read as 1
+	AggregateType string          `json:"aggregate_type"`
+	AggregateID   string          `json:"aggregate_id"`
+	OccurredAt    int64           `json:"occurred_at"`
+	Timezone      string          `json:"timezone"`
+	Payload       json.RawMessage `json:"payload"`
+}
+
+// NewEnvelope serializes event into an Envelope; the payload is the JSON
+// encoding of the event itself
+func NewEnvelope(event Event) (Envelope, error) {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return Envelope{}, fmt.Errorf("failed to encode event %s: %w", event.EventName(), err)
+	}
+
+	meta := event.EventMetadata()
+	return Envelope{
+		ID:            meta.ID,
+		Name:          event.EventName(),
+		Version:       1,
+		AggregateType: meta.AggregateType,
+		AggregateID:   meta.AggregateID,
+		OccurredAt:    meta.OccurredAt.Time.Epoch,
+		Timezone:      meta.OccurredAt.Timezone.ID,
+		Payload:       payload,
+	}, nil
+}
+
+// Publisher delivers envelopes outside the process
+type Publisher interface {
+	Publish(ctx context.Context, envelope Envelope) error
+}
+
+// Forward returns a handler that sends every event it receives to publisher.
+// Subscribe it for specific names, or for AllEvents.
+func Forward(publisher Publisher) Handler {
+	return func(ctx context.Context, event Event) error {
+		envelope, err := NewEnvelope(event)
+		if err != nil {
+			return err
+		}
+		return publisher.Publish(ctx, envelope)
+	}
+}
+
+// Execer is satisfied by *sql.DB, *sql.Tx and *sql.Conn
+type Execer interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+}
+
+// Outbox writes envelopes to the event_outbox table. Build it on the
+// transaction that changes the aggregate so the event is stored atomically
+// with the change; a relay later moves rows to the broker.
+type Outbox struct {
+	exec Execer
+}
+
+// NewOutbox creates an outbox writing through exec
+func NewOutbox(exec Execer) *Outbox {
+	return &Outbox{exec: exec}
+}
+
+// Publish inserts the envelope into the outbox table
+func (o *Outbox) Publish(ctx context.Context, envelope Envelope) error {
+	_, err := o.exec.ExecContext(ctx,
+		`INSERT INTO event_outbox (id, name, version, aggregate_type, aggregate_id, occurred_at, timezone, payload)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`,
+		envelope.ID, envelope.Name, max(envelope.Version, 1), envelope.AggregateType, envelope.AggregateID,
+		envelope.OccurredAt, envelope.Timezone, []byte(envelope.Payload),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to write event %s to outbox: %w", envelope.ID, err)
+	}
+	return nil
+}
+
+// RedisBroker publishes envelopes on Redis pub/sub channels named
+// <prefix><event name>
+type RedisBroker struct {
+	client *redis.Client
+	prefix string
+}
+
+// NewRedisBroker creates a broker publishing through client
+func NewRedisBroker(client *redis.Client, prefix string) *RedisBroker {
+	return &RedisBroker{client: client, prefix: prefix}
+}
+
+// Publish sends the JSON-encoded envelope to the event's channel
+func (b *RedisBroker) Publish(ctx context.Context, envelope Envelope) error {
+	data, err := json.Marshal(envelope)
+	if err != nil {
+		return fmt.Errorf("failed to encode envelope %s: %w", envelope.ID, err)
+	}
+
+	if err := b.client.Publish(ctx, b.prefix+envelope.Name, data).Err(); err != nil {
+		return fmt.Errorf("failed to publish event %s: %w", envelope.ID, err)
+	}
+	return nil
+}
+
+// MemoryBroker records published envelopes; it is the fake broker used in
+// tests and the dev profile
+type MemoryBroker struct {
+	mu        sync.Mutex
+	published []Envelope
+	err       error
+}
+
+// NewMemoryBroker creates an empty in-memory broker
+func NewMemoryBroker() *MemoryBroker {
+	return &MemoryBroker{}
+}
+
+// Publish records the envelope, or returns the error set with FailWith
+func (b *MemoryBroker) Publish(_ context.Context, envelope Envelope) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.err != nil {
+		return b.err
+	}
+	b.published = append(b.published, envelope)
+	return nil
+}
+
+// FailWith makes subsequent Publish calls return err; nil restores success
+func (b *MemoryBroker) FailWith(err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.err = err
+}
+
+// Published returns a copy of the envelopes recorded so far
+func (b *MemoryBroker) Published() []Envelope {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return append([]Envelope(nil), b.published...)
+}
+
+// Reset forgets the recorded envelopes
+func (b *MemoryBroker) Reset() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.published = nil
+}
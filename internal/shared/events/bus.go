@@ -0,0 +1,183 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"go.uber.org/zap"
+)
+
+// AllEvents subscribes a handler to every event name
+const AllEvents = "*"
+
+// ErrBusClosed is returned when publishing asynchronously on a closed bus
+var ErrBusClosed = errors.New("event bus is closed")
+
+// Handler reacts to an event
+type Handler func(ctx context.Context, event Event) error
+
+// BusOption customizes a Bus
+type BusOption func(*Bus)
+
+// WithWorkers sets the number of goroutines that run asynchronous handlers
+func WithWorkers(workers int) BusOption {
+	return func(b *Bus) {
+		if workers > 0 {
+			b.workers = workers
+		}
+	}
+}
+
+// WithQueueSize sets how many asynchronous events may wait for a worker
+// before PublishAsync blocks
+func WithQueueSize(size int) BusOption {
+	return func(b *Bus) {
+		if size >= 0 {
+			b.queueSize = size
+		}
+	}
+}
+
+// Bus dispatches events to subscribed handlers
+type Bus struct {
+	logger    *zap.Logger
+	workers   int
+	queueSize int
+
+	mu       sync.RWMutex
+	handlers map[string][]Handler
+
+	closeMu sync.RWMutex
+	closed  bool
+	queue   chan delivery
+	wg      sync.WaitGroup
+}
+
+type delivery struct {
+	ctx   context.Context
+	event Event
+}
+
+// NewBus creates an event bus and starts its asynchronous workers
+func NewBus(logger *zap.Logger, options ...BusOption) *Bus {
+	bus := &Bus{
+		logger:    logger,
+		workers:   4,
+		queueSize: 256,
+		handlers:  make(map[string][]Handler),
+	}
+	for _, option := range options {
+		option(bus)
+	}
+
+	bus.queue = make(chan delivery, bus.queueSize)
+	for i := 0; i < bus.workers; i++ {
+		bus.wg.Add(1)
+		go bus.work()
+	}
+
+	return bus
+}
+
+// Subscribe registers handler for events with the given name, or for every
+// event when name is AllEvents
+func (b *Bus) Subscribe(name string, handler Handler) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.handlers[name] = append(b.handlers[name], handler)
+}
+
+// Publish runs the handlers of each event in the caller's goroutine, in
+// subscription order. Every handler runs; their errors are joined.
+func (b *Bus) Publish(ctx context.Context, events ...Event) error {
+	var errs []error
+	for _, event := range events {
+		for _, handler := range b.handlersFor(event.EventName()) {
+			if err := b.invoke(ctx, handler, event); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// PublishAsync queues the events for the worker pool and returns once they
+// are queued. Handlers run with a context detached from ctx's cancellation;
+// their errors are logged.
+func (b *Bus) PublishAsync(ctx context.Context, events ...Event) error {
+	b.closeMu.RLock()
+	defer b.closeMu.RUnlock()
+
+	if b.closed {
+		return ErrBusClosed
+	}
+
+	detached := context.WithoutCancel(ctx)
+	for _, event := range events {
+		select {
+		case b.queue <- delivery{ctx: detached, event: event}:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return nil
+}
+
+// Close stops accepting asynchronous events and waits until queued events
+// have been handled
+func (b *Bus) Close() error {
+	b.closeMu.Lock()
+	if b.closed {
+		b.closeMu.Unlock()
+		return nil
+	}
+	b.closed = true
+	close(b.queue)
+	b.closeMu.Unlock()
+
+	b.wg.Wait()
+	return nil
+}
+
+// work runs asynchronous deliveries until the queue is closed
+func (b *Bus) work() {
+	defer b.wg.Done()
+
+	for d := range b.queue {
+		if err := b.Publish(d.ctx, d.event); err != nil {
+			b.logger.Error("Async event handler failed",
+				zap.String("event", d.event.EventName()),
+				zap.String("event_id", d.event.EventMetadata().ID),
+				zap.Error(err),
+			)
+		}
+	}
+}
+
+// handlersFor returns the handlers for name followed by the AllEvents handlers
+func (b *Bus) handlersFor(name string) []Handler {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	named, all := b.handlers[name], b.handlers[AllEvents]
+	handlers := make([]Handler, 0, len(named)+len(all))
+	handlers = append(handlers, named...)
+	return append(handlers, all...)
+}
+
+// invoke runs a handler, turning panics into errors
+func (b *Bus) invoke(ctx context.Context, handler Handler, event Event) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("event handler for %s panicked: %v", event.EventName(), r)
+		}
+	}()
+
+	if err := handler(ctx, event); err != nil {
+		return fmt.Errorf("event handler for %s: %w", event.EventName(), err)
+	}
+	return nil
+}
@@ -0,0 +1,55 @@
+// Package events provides lightweight domain events and an in-process event
+// bus.
+//
+// Modules publish events such as "MoneyConverted" or "UserLocaleChanged" on
+// the Bus instead of calling each other directly. Handlers run synchronously
+// (Publish) or on the bus worker pool (PublishAsync), and Forward bridges
+// events to a Publisher such as the transactional Outbox or a message broker.
+package events
+
+import (
+	"github.com/google/uuid"
+
+	i18n "golang-arch/internal/shared/domain/internationalization"
+	"golang-arch/pkg/clock"
+)
+
+// Event is a domain event
+type Event interface {
+	// EventName identifies the event type, e.g. "money.converted"
+	EventName() string
+	// EventMetadata returns the envelope data common to every event
+	EventMetadata() Metadata
+}
+
+// Metadata describes when and where an event happened
+type Metadata struct {
+	ID            string                 `json:"id"`
+	OccurredAt    i18n.LocalizedDateTime `json:"occurred_at"`
+	AggregateType string                 `json:"aggregate_type"`
+	AggregateID   string                 `json:"aggregate_id"`
+}
+
+// NewMetadata creates metadata with a fresh ID, stamped with the current
+// time of clk in UTC
+func NewMetadata(clk clock.Clock, aggregateType, aggregateID string) Metadata {
+	return Metadata{
+		ID: uuid.NewString(),
+		OccurredAt: i18n.LocalizedDateTime{
+			Time:     i18n.Time{Epoch: clk.Now().Unix()},
+			Timezone: i18n.Timezone{ID: "UTC", Name: "Coordinated Universal Time"},
+		},
+		AggregateType: aggregateType,
+		AggregateID:   aggregateID,
+	}
+}
+
+// Base implements EventMetadata for events that embed it
+type Base struct {
+	Metadata Metadata `json:"metadata"`
+}
+
+// EventMetadata returns the event metadata
+func (b Base) EventMetadata() Metadata {
+	return b.Metadata
+}
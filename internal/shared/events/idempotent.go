@@ -0,0 +1,169 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"golang-arch/pkg/clock"
+)
+
+// EnvelopeHandler consumes an envelope received from a broker
+type EnvelopeHandler func(ctx context.Context, envelope Envelope) error
+
+// Ledger records which handler processed which message, so a redelivered
+// message is not handled twice. Brokers and the outbox relay deliver at least
+// once; the ledger turns that into effectively once per handler.
+type Ledger interface {
+	// Claim records that handler processes messageID; claimed is false when
+	// it already did
+	Claim(ctx context.Context, messageID, handler string) (claimed bool, err error)
+	// Release forgets a claim whose handling failed, so a redelivery is
+	// handled again
+	Release(ctx context.Context, messageID, handler string) error
+	// Prune forgets the messages processed before before and returns how
+	// many it forgot; redeliveries older than that are handled again
+	Prune(ctx context.Context, before time.Time) (int64, error)
+}
+
+// Once runs fn unless handler already processed messageID. A failed fn
+// releases the claim so a redelivery retries it.
+func Once(ctx context.Context, ledger Ledger, messageID, handler string, fn func(ctx context.Context) error) error {
+	claimed, err := ledger.Claim(ctx, messageID, handler)
+	if err != nil {
+		return err
+	}
+	if !claimed {
+		return nil
+	}
+	if err := fn(ctx); err != nil {
+		if releaseErr := ledger.Release(ctx, messageID, handler); releaseErr != nil {
+			return errors.Join(err, releaseErr)
+		}
+		return err
+	}
+	return nil
+}
+
+// Idempotent wraps a bus handler so it skips events it processed before,
+// keyed by the event ID. name identifies the handler in the ledger and must
+// stay the same across deployments.
+func Idempotent(ledger Ledger, name string, handler Handler) Handler {
+	return func(ctx context.Context, event Event) error {
+		return Once(ctx, ledger, event.EventMetadata().ID, name, func(ctx context.Context) error {
+			return handler(ctx, event)
+		})
+	}
+}
+
+// IdempotentEnvelope wraps a broker consumer so it skips envelopes it
+// processed before, keyed by the envelope ID
+func IdempotentEnvelope(ledger Ledger, name string, handler EnvelopeHandler) EnvelopeHandler {
+	return func(ctx context.Context, envelope Envelope) error {
+		return Once(ctx, ledger, envelope.ID, name, func(ctx context.Context) error {
+			return handler(ctx, envelope)
+		})
+	}
+}
+
+// PostgresLedger keeps processed messages in the processed_messages table.
+// Build it on the transaction the handler writes through: the claim then
+// commits with the handler's changes, and a concurrent redelivery waits on
+// the claim and skips. Built on the *sql.DB, a crash while handling leaves
+// the message claimed but unhandled.
+type PostgresLedger struct {
+	exec Execer
+}
+
+// NewPostgresLedger creates a ledger writing through exec
+func NewPostgresLedger(exec Execer) *PostgresLedger {
+	return &PostgresLedger{exec: exec}
+}
+
+// Claim inserts the message, skipping it when the pair is already recorded
+func (l *PostgresLedger) Claim(ctx context.Context, messageID, handler string) (bool, error) {
+	result, err := l.exec.ExecContext(ctx,
+		`INSERT INTO processed_messages (message_id, handler) VALUES ($1, $2)
+		ON CONFLICT (message_id, handler) DO NOTHING`,
+		messageID, handler)
+	if err != nil {
+		return false, fmt.Errorf("failed to claim message %s for %s: %w", messageID, handler, err)
+	}
+	inserted, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to claim message %s for %s: %w", messageID, handler, err)
+	}
+	return inserted == 1, nil
+}
+
+// Release deletes the claim
+func (l *PostgresLedger) Release(ctx context.Context, messageID, handler string) error {
+	_, err := l.exec.ExecContext(ctx,
+		`DELETE FROM processed_messages WHERE message_id = $1 AND handler = $2`, messageID, handler)
+	if err != nil {
+		return fmt.Errorf("failed to release message %s for %s: %w", messageID, handler, err)
+	}
+	return nil
+}
+
+// Prune deletes the messages processed before before
+func (l *PostgresLedger) Prune(ctx context.Context, before time.Time) (int64, error) {
+	result, err := l.exec.ExecContext(ctx, `DELETE FROM processed_messages WHERE processed_at < $1`, before)
+	if err != nil {
+		return 0, fmt.Errorf("failed to prune processed messages: %w", err)
+	}
+	return result.RowsAffected()
+}
+
+// MemoryLedger keeps processed messages in memory; it is the ledger used in
+// tests and the dev profile
+type MemoryLedger struct {
+	clock clock.Clock
+
+	mu        sync.Mutex
+	processed map[[2]string]time.Time
+}
+
+// NewMemoryLedger creates an empty in-memory ledger stamping claims with clk
+func NewMemoryLedger(clk clock.Clock) *MemoryLedger {
+	return &MemoryLedger{clock: clk, processed: make(map[[2]string]time.Time)}
+}
+
+// Claim records the message unless it is recorded
+func (l *MemoryLedger) Claim(_ context.Context, messageID, handler string) (bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	key := [2]string{messageID, handler}
+	if _, ok := l.processed[key]; ok {
+		return false, nil
+	}
+	l.processed[key] = l.clock.Now()
+	return true, nil
+}
+
+// Release forgets the claim
+func (l *MemoryLedger) Release(_ context.Context, messageID, handler string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	delete(l.processed, [2]string{messageID, handler})
+	return nil
+}
+
+// Prune forgets the messages processed before before
+func (l *MemoryLedger) Prune(_ context.Context, before time.Time) (int64, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	var pruned int64
+	for key, processedAt := range l.processed {
+		if processedAt.Before(before) {
+			delete(l.processed, key)
+			pruned++
+		}
+	}
+	return pruned, nil
+}
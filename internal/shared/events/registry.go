@@ -0,0 +1,188 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// ErrUnknownVersion is returned for an envelope newer than the registered
+// schema, e.g. when a consumer runs an older release than the producer
+var ErrUnknownVersion = errors.New("unknown event version")
+
+// ErrInvalidPayload is returned for a payload that does not match its schema
+var ErrInvalidPayload = errors.New("invalid event payload")
+
+// Upcaster transforms a payload of one version into the next version
+type Upcaster func(payload json.RawMessage) (json.RawMessage, error)
+
+// Schema describes the versions of one event
+type Schema struct {
+	Name string
+	// Version is the current version, the one Type encodes; 1 for an event
+	// that never changed
+	Version int
+	// Type is a value of the event type. Payloads must decode into it, and
+	// are checked by its Validate() error method when it has one.
+	Type Event
+	// Upcasters[n] turns a version n payload into version n+1; every version
+	// below Version needs one
+	Upcasters map[int]Upcaster
+}
+
+// Registry holds the schemas of the events exchanged with other processes.
+// Its Publisher stamps and validates outgoing envelopes; its Consumer
+// upcasts and validates incoming ones. Events without a schema pass through
+// as version 1.
+type Registry struct {
+	mu      sync.RWMutex
+	schemas map[string]Schema
+}
+
+// NewRegistry creates an empty registry
+func NewRegistry() *Registry {
+	return &Registry{schemas: make(map[string]Schema)}
+}
+
+// Register adds the schema of an event
+func (r *Registry) Register(schema Schema) error {
+	if schema.Name == "" || schema.Type == nil {
+		return errors.New("event schema needs a name and a type")
+	}
+	if schema.Type.EventName() != schema.Name {
+		return fmt.Errorf("event schema %s has a type named %s", schema.Name, schema.Type.EventName())
+	}
+	if schema.Version < 1 {
+		return fmt.Errorf("event schema %s has version %d; versions start at 1", schema.Name, schema.Version)
+	}
+	for version := 1; version < schema.Version; version++ {
+		if schema.Upcasters[version] == nil {
+			return fmt.Errorf("event schema %s has no upcaster from version %d", schema.Name, version)
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.schemas[schema.Name]; ok {
+		return fmt.Errorf("event schema %s registered twice", schema.Name)
+	}
+	r.schemas[schema.Name] = schema
+	return nil
+}
+
+// Schema returns the schema registered for name
+func (r *Registry) Schema(name string) (Schema, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	schema, ok := r.schemas[name]
+	return schema, ok
+}
+
+// Seal prepares an outgoing envelope: it is stamped with the current version
+// of its schema and its payload is validated
+func (r *Registry) Seal(envelope Envelope) (Envelope, error) {
+	schema, ok := r.Schema(envelope.Name)
+	if !ok {
+		envelope.Version = max(envelope.Version, 1)
+		return envelope, nil
+	}
+
+	envelope.Version = schema.Version
+	if _, err := schema.decode(envelope.Payload); err != nil {
+		return Envelope{}, fmt.Errorf("event %s: %w", envelope.ID, err)
+	}
+	return envelope, nil
+}
+
+// Open prepares an incoming envelope: its payload is upcast to the current
+// version of its schema and validated
+func (r *Registry) Open(envelope Envelope) (Envelope, error) {
+	envelope.Version = max(envelope.Version, 1)
+	schema, ok := r.Schema(envelope.Name)
+	if !ok {
+		return envelope, nil
+	}
+	if envelope.Version > schema.Version {
+		return Envelope{}, fmt.Errorf("%w: event %s is %s v%d, at most v%d is known",
+			ErrUnknownVersion, envelope.ID, envelope.Name, envelope.Version, schema.Version)
+	}
+
+	for envelope.Version < schema.Version {
+		payload, err := schema.Upcasters[envelope.Version](envelope.Payload)
+		if err != nil {
+			return Envelope{}, fmt.Errorf("failed to upcast event %s from v%d: %w", envelope.ID, envelope.Version, err)
+		}
+		envelope.Payload = payload
+		envelope.Version++
+	}
+	if _, err := schema.decode(envelope.Payload); err != nil {
+		return Envelope{}, fmt.Errorf("event %s: %w", envelope.ID, err)
+	}
+	return envelope, nil
+}
+
+// Decode opens the envelope and decodes its payload into a value of the
+// schema's type
+func (r *Registry) Decode(envelope Envelope) (Event, error) {
+	schema, ok := r.Schema(envelope.Name)
+	if !ok {
+		return nil, fmt.Errorf("no schema registered for event %s", envelope.Name)
+	}
+	envelope, err := r.Open(envelope)
+	if err != nil {
+		return nil, err
+	}
+	return schema.decode(envelope.Payload)
+}
+
+// Publisher wraps publisher so envelopes are sealed before they are sent
+func (r *Registry) Publisher(publisher Publisher) Publisher {
+	return &sealingPublisher{registry: r, next: publisher}
+}
+
+// Consumer wraps handler so envelopes are opened before they are handled;
+// envelopes that cannot be opened are returned as errors without calling it
+func (r *Registry) Consumer(handler EnvelopeHandler) EnvelopeHandler {
+	return func(ctx context.Context, envelope Envelope) error {
+		opened, err := r.Open(envelope)
+		if err != nil {
+			return err
+		}
+		return handler(ctx, opened)
+	}
+}
+
+type sealingPublisher struct {
+	registry *Registry
+	next     Publisher
+}
+
+func (p *sealingPublisher) Publish(ctx context.Context, envelope Envelope) error {
+	sealed, err := p.registry.Seal(envelope)
+	if err != nil {
+		return err
+	}
+	return p.next.Publish(ctx, sealed)
+}
+
+// decode unmarshals payload into a new value of the schema's type and
+// validates it
+func (s Schema) decode(payload json.RawMessage) (Event, error) {
+	typ := reflect.TypeOf(s.Type)
+	value := reflect.New(typ)
+	if err := json.Unmarshal(payload, value.Interface()); err != nil {
+		return nil, fmt.Errorf("%w: %s v%d: %v", ErrInvalidPayload, s.Name, s.Version, err)
+	}
+	event := value.Elem().Interface().(Event)
+	if validator, ok := event.(interface{ Validate() error }); ok {
+		if err := validator.Validate(); err != nil {
+			return nil, fmt.Errorf("%w: %s v%d: %w", ErrInvalidPayload, s.Name, s.Version, err)
+		}
+	}
+	return event, nil
+}
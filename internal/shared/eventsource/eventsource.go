@@ -0,0 +1,67 @@
+// Package eventsource is an optional persistence mode for aggregates whose
+// full history matters, such as ledgers. Instead of their current state, the
+// domain events that changed them are stored in an append-only table; the
+// state is rebuilt by replaying them, starting from the latest snapshot.
+//
+// Events are stored as events.Envelope values, so the schema registry
+// upcasts old events when they are replayed, and the stored events can feed
+// read-model projections in the order they were appended.
+package eventsource
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"golang-arch/internal/shared/events"
+)
+
+// Aggregate is the state of an event-sourced aggregate, implemented by a
+// pointer type. Apply changes the state by one event; it is called for new
+// events as well as for replayed ones, so it must not have side effects. The
+// state is stored in snapshots as JSON.
+type Aggregate interface {
+	Apply(event events.Event) error
+}
+
+// Record is a stored event
+type Record struct {
+	// Position orders all events of the store; projections resume from it
+	Position int64
+	// Version is the event's number within its aggregate, from 1
+	Version    int
+	Envelope   events.Envelope
+	RecordedAt time.Time
+}
+
+// Snapshot is an aggregate's state after Version events
+type Snapshot struct {
+	Version int
+	State   json.RawMessage
+	TakenAt time.Time
+}
+
+// Stream is an aggregate loaded from the store, with the events recorded on
+// it since
+type Stream[A Aggregate] struct {
+	ID string
+	// Version is the number of stored events the state reflects
+	Version int
+	State   A
+
+	pending []events.Event
+}
+
+// Record applies event to the state and queues it for the next Save
+func (s *Stream[A]) Record(event events.Event) error {
+	if err := s.State.Apply(event); err != nil {
+		return fmt.Errorf("failed to apply %s: %w", event.EventName(), err)
+	}
+	s.pending = append(s.pending, event)
+	return nil
+}
+
+// Pending returns the events recorded since the stream was loaded or saved
+func (s *Stream[A]) Pending() []events.Event {
+	return append([]events.Event(nil), s.pending...)
+}
@@ -0,0 +1,222 @@
+package eventsource
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"go.uber.org/zap"
+
+	"golang-arch/internal/shared/domain/domainerror"
+	"golang-arch/internal/shared/events"
+	"golang-arch/pkg/clock"
+)
+
+// DefaultSnapshotEvery is the number of events between snapshots
+const DefaultSnapshotEvery = 100
+
+// Option configures a Repository
+type Option func(*options)
+
+type options struct {
+	clock         clock.Clock
+	logger        *zap.Logger
+	bus           *events.Bus
+	snapshotEvery int
+}
+
+// WithClock sets the clock snapshots are stamped with
+func WithClock(c clock.Clock) Option {
+	return func(o *options) {
+		o.clock = c
+	}
+}
+
+// WithLogger sets the logger
+func WithLogger(logger *zap.Logger) Option {
+	return func(o *options) {
+		o.logger = logger
+	}
+}
+
+// WithEvents publishes saved events on bus, asynchronously, once they are
+// stored
+func WithEvents(bus *events.Bus) Option {
+	return func(o *options) {
+		o.bus = bus
+	}
+}
+
+// WithSnapshotEvery sets how many events are stored between snapshots; a
+// negative value disables snapshots
+func WithSnapshotEvery(n int) Option {
+	return func(o *options) {
+		if n != 0 {
+			o.snapshotEvery = n
+		}
+	}
+}
+
+// Repository loads and saves event-sourced aggregates of one type
+type Repository[A Aggregate] struct {
+	store         Store
+	schemas       *events.Registry
+	aggregateType string
+	newState      func() A
+	options
+}
+
+// NewRepository creates a repository for aggregateType. Its events are
+// decoded through schemas, where each of them must be registered; newState
+// returns the state of an aggregate without events.
+func NewRepository[A Aggregate](store Store, schemas *events.Registry, aggregateType string, newState func() A, opts ...Option) *Repository[A] {
+	r := &Repository[A]{
+		store:         store,
+		schemas:       schemas,
+		aggregateType: aggregateType,
+		newState:      newState,
+		options: options{
+			clock:         clock.New(),
+			logger:        zap.NewNop(),
+			snapshotEvery: DefaultSnapshotEvery,
+		},
+	}
+	for _, opt := range opts {
+		opt(&r.options)
+	}
+	return r
+}
+
+// New returns a stream for an aggregate without events
+func (r *Repository[A]) New(id string) *Stream[A] {
+	return &Stream[A]{ID: id, State: r.newState()}
+}
+
+// Load rebuilds an aggregate from its latest snapshot and the events stored
+// after it. It returns a NotFound error for an aggregate without events.
+func (r *Repository[A]) Load(ctx context.Context, id string) (*Stream[A], error) {
+	stream := r.New(id)
+	snapshot, found, err := r.store.LoadSnapshot(ctx, r.aggregateType, id)
+	if err != nil {
+		return nil, err
+	}
+	if found {
+		if err := json.Unmarshal(snapshot.State, stream.State); err != nil {
+			// An unreadable snapshot, e.g. of an older state layout, is
+			// skipped in favour of a full replay
+			r.logger.Warn("Ignoring unreadable snapshot", zap.String("aggregate_type", r.aggregateType),
+				zap.String("aggregate_id", id), zap.Error(err))
+			stream.State = r.newState()
+		} else {
+			stream.Version = snapshot.Version
+		}
+	}
+
+	if err := r.replay(ctx, stream, 0); err != nil {
+		return nil, err
+	}
+	if stream.Version == 0 {
+		return nil, domainerror.NotFoundf("%s %s not found", r.aggregateType, id)
+	}
+	return stream, nil
+}
+
+// Replay rebuilds an aggregate from all of its events, ignoring snapshots,
+// up to version upTo; 0 replays every event. It shows the aggregate as it
+// was after that event.
+func (r *Repository[A]) Replay(ctx context.Context, id string, upTo int) (*Stream[A], error) {
+	stream := r.New(id)
+	if err := r.replay(ctx, stream, upTo); err != nil {
+		return nil, err
+	}
+	if stream.Version == 0 {
+		return nil, domainerror.NotFoundf("%s %s not found", r.aggregateType, id)
+	}
+	return stream, nil
+}
+
+// History returns the stored events of an aggregate
+func (r *Repository[A]) History(ctx context.Context, id string) ([]Record, error) {
+	return r.store.Load(ctx, r.aggregateType, id, 0)
+}
+
+// replay applies the events after stream.Version, up to upTo unless it is 0
+func (r *Repository[A]) replay(ctx context.Context, stream *Stream[A], upTo int) error {
+	records, err := r.store.Load(ctx, r.aggregateType, stream.ID, stream.Version)
+	if err != nil {
+		return err
+	}
+	for _, record := range records {
+		if upTo > 0 && record.Version > upTo {
+			break
+		}
+		event, err := r.schemas.Decode(record.Envelope)
+		if err != nil {
+			return fmt.Errorf("%s %s version %d: %w", r.aggregateType, stream.ID, record.Version, err)
+		}
+		if err := stream.State.Apply(event); err != nil {
+			return fmt.Errorf("%s %s version %d: %w", r.aggregateType, stream.ID, record.Version, err)
+		}
+		stream.Version = record.Version
+	}
+	return nil
+}
+
+// Save appends the stream's pending events. It fails with a Conflict error
+// when the aggregate changed since it was loaded; load it again and retry
+// the command. A snapshot is stored when the events cross a multiple of the
+// snapshot interval.
+func (r *Repository[A]) Save(ctx context.Context, stream *Stream[A]) error {
+	if len(stream.pending) == 0 {
+		return nil
+	}
+
+	envelopes := make([]events.Envelope, 0, len(stream.pending))
+	for _, event := range stream.pending {
+		envelope, err := events.NewEnvelope(event)
+		if err != nil {
+			return err
+		}
+		envelope.AggregateType, envelope.AggregateID = r.aggregateType, stream.ID
+		if envelope, err = r.schemas.Seal(envelope); err != nil {
+			return err
+		}
+		envelopes = append(envelopes, envelope)
+	}
+	if err := r.store.Append(ctx, r.aggregateType, stream.ID, stream.Version, envelopes); err != nil {
+		return err
+	}
+
+	previous := stream.Version
+	stream.Version += len(envelopes)
+	saved := stream.pending
+	stream.pending = nil
+
+	if r.snapshotEvery > 0 && stream.Version/r.snapshotEvery > previous/r.snapshotEvery {
+		r.snapshot(ctx, stream)
+	}
+	if r.bus != nil {
+		if err := r.bus.PublishAsync(ctx, saved...); err != nil {
+			r.logger.Warn("Failed to publish saved events", zap.String("aggregate_type", r.aggregateType),
+				zap.String("aggregate_id", stream.ID), zap.Error(err))
+		}
+	}
+	return nil
+}
+
+// snapshot stores the stream's state; failures are only logged since the
+// events are already stored
+func (r *Repository[A]) snapshot(ctx context.Context, stream *Stream[A]) {
+	state, err := json.Marshal(stream.State)
+	if err == nil {
+		err = r.store.SaveSnapshot(ctx, r.aggregateType, stream.ID, Snapshot{
+			Version: stream.Version,
+			State:   state,
+			TakenAt: r.clock.Now().UTC(),
+		})
+	}
+	if err != nil {
+		r.logger.Warn("Failed to store snapshot", zap.String("aggregate_type", r.aggregateType),
+			zap.String("aggregate_id", stream.ID), zap.Error(err))
+	}
+}
@@ -0,0 +1,272 @@
+package eventsource
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/lib/pq"
+
+	"golang-arch/internal/shared/domain/domainerror"
+	"golang-arch/internal/shared/events"
+	"golang-arch/pkg/clock"
+)
+
+// Store keeps the events and snapshots of event-sourced aggregates
+type Store interface {
+	// Append adds envelopes to an aggregate that has expectedVersion events.
+	// It fails with a Conflict error when another writer appended first.
+	Append(ctx context.Context, aggregateType, aggregateID string, expectedVersion int, envelopes []events.Envelope) error
+	// Load returns an aggregate's events after version afterVersion
+	Load(ctx context.Context, aggregateType, aggregateID string, afterVersion int) ([]Record, error)
+	// ReadAll returns up to limit events of every aggregate after position
+	// afterPosition, in append order
+	ReadAll(ctx context.Context, afterPosition int64, limit int) ([]Record, error)
+	// Head returns the position of the latest event; 0 when there are none
+	Head(ctx context.Context) (int64, error)
+	// SaveSnapshot replaces an aggregate's snapshot
+	SaveSnapshot(ctx context.Context, aggregateType, aggregateID string, snapshot Snapshot) error
+	// LoadSnapshot returns an aggregate's snapshot; found is false when it
+	// has none
+	LoadSnapshot(ctx context.Context, aggregateType, aggregateID string) (snapshot Snapshot, found bool, err error)
+}
+
+// conflict reports that an aggregate changed since it was loaded
+func conflict(aggregateType, aggregateID string, expectedVersion int) error {
+	return domainerror.Conflictf("%s %s changed since version %d", aggregateType, aggregateID, expectedVersion)
+}
+
+// PostgresStore keeps events in the aggregate_events table and snapshots in
+// aggregate_snapshots
+type PostgresStore struct {
+	db *sql.DB
+}
+
+// NewPostgresStore creates a store on db
+func NewPostgresStore(db *sql.DB) *PostgresStore {
+	return &PostgresStore{db: db}
+}
+
+const recordColumns = `position, version, id, name, schema_version, aggregate_type, aggregate_id, occurred_at, timezone, payload, recorded_at`
+
+// Append inserts the envelopes in one transaction; the primary key on
+// (aggregate_type, aggregate_id, version) rejects concurrent appends
+func (s *PostgresStore) Append(ctx context.Context, aggregateType, aggregateID string, expectedVersion int, envelopes []events.Envelope) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to append events: %w", err)
+	}
+	defer tx.Rollback()
+
+	for i, envelope := range envelopes {
+		_, err := tx.ExecContext(ctx,
+			`INSERT INTO aggregate_events
+				(aggregate_type, aggregate_id, version, id, name, schema_version, occurred_at, timezone, payload)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`,
+			aggregateType, aggregateID, expectedVersion+i+1, envelope.ID, envelope.Name, max(envelope.Version, 1),
+			envelope.OccurredAt, envelope.Timezone, []byte(envelope.Payload))
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+			return conflict(aggregateType, aggregateID, expectedVersion)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to append event %s: %w", envelope.ID, err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to append events: %w", err)
+	}
+	return nil
+}
+
+// Load selects an aggregate's events by version
+func (s *PostgresStore) Load(ctx context.Context, aggregateType, aggregateID string, afterVersion int) ([]Record, error) {
+	return s.query(ctx,
+		`SELECT `+recordColumns+` FROM aggregate_events
+		WHERE aggregate_type = $1 AND aggregate_id = $2 AND version > $3
+		ORDER BY version`,
+		aggregateType, aggregateID, afterVersion)
+}
+
+// ReadAll selects events by position
+func (s *PostgresStore) ReadAll(ctx context.Context, afterPosition int64, limit int) ([]Record, error) {
+	return s.query(ctx,
+		`SELECT `+recordColumns+` FROM aggregate_events WHERE position > $1 ORDER BY position LIMIT $2`,
+		afterPosition, limit)
+}
+
+// Head selects the highest position
+func (s *PostgresStore) Head(ctx context.Context) (int64, error) {
+	var head int64
+	if err := s.db.QueryRowContext(ctx, `SELECT COALESCE(MAX(position), 0) FROM aggregate_events`).Scan(&head); err != nil {
+		return 0, fmt.Errorf("failed to read the latest event position: %w", err)
+	}
+	return head, nil
+}
+
+func (s *PostgresStore) query(ctx context.Context, query string, args ...any) ([]Record, error) {
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read events: %w", err)
+	}
+	defer rows.Close()
+
+	var records []Record
+	for rows.Next() {
+		var record Record
+		var payload []byte
+		envelope := &record.Envelope
+		if err := rows.Scan(&record.Position, &record.Version, &envelope.ID, &envelope.Name, &envelope.Version,
+			&envelope.AggregateType, &envelope.AggregateID, &envelope.OccurredAt, &envelope.Timezone, &payload,
+			&record.RecordedAt); err != nil {
+			return nil, fmt.Errorf("failed to read event: %w", err)
+		}
+		envelope.Payload = payload
+		records = append(records, record)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read events: %w", err)
+	}
+	return records, nil
+}
+
+// SaveSnapshot upserts the snapshot
+func (s *PostgresStore) SaveSnapshot(ctx context.Context, aggregateType, aggregateID string, snapshot Snapshot) error {
+	_, err := s.db.ExecContext(ctx,
+		`INSERT INTO aggregate_snapshots (aggregate_type, aggregate_id, version, state, taken_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (aggregate_type, aggregate_id) DO UPDATE
+		SET version = EXCLUDED.version, state = EXCLUDED.state, taken_at = EXCLUDED.taken_at
+		WHERE aggregate_snapshots.version < EXCLUDED.version`,
+		aggregateType, aggregateID, snapshot.Version, []byte(snapshot.State), snapshot.TakenAt)
+	if err != nil {
+		return fmt.Errorf("failed to save snapshot of %s %s: %w", aggregateType, aggregateID, err)
+	}
+	return nil
+}
+
+// LoadSnapshot selects the snapshot
+func (s *PostgresStore) LoadSnapshot(ctx context.Context, aggregateType, aggregateID string) (Snapshot, bool, error) {
+	var snapshot Snapshot
+	var state []byte
+	err := s.db.QueryRowContext(ctx,
+		`SELECT version, state, taken_at FROM aggregate_snapshots WHERE aggregate_type = $1 AND aggregate_id = $2`,
+		aggregateType, aggregateID).Scan(&snapshot.Version, &state, &snapshot.TakenAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return Snapshot{}, false, nil
+	}
+	if err != nil {
+		return Snapshot{}, false, fmt.Errorf("failed to load snapshot of %s %s: %w", aggregateType, aggregateID, err)
+	}
+	snapshot.State = state
+	return snapshot, true, nil
+}
+
+// MemoryStore keeps events and snapshots in memory, for tests and the dev
+// profile
+type MemoryStore struct {
+	clock clock.Clock
+
+	mu        sync.Mutex
+	records   []Record
+	versions  map[[2]string]int
+	snapshots map[[2]string]Snapshot
+}
+
+// NewMemoryStore creates an empty in-memory store stamping events with clk
+func NewMemoryStore(clk clock.Clock) *MemoryStore {
+	return &MemoryStore{
+		clock:     clk,
+		versions:  make(map[[2]string]int),
+		snapshots: make(map[[2]string]Snapshot),
+	}
+}
+
+// Append adds the envelopes unless the aggregate moved past expectedVersion
+func (s *MemoryStore) Append(_ context.Context, aggregateType, aggregateID string, expectedVersion int, envelopes []events.Envelope) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := [2]string{aggregateType, aggregateID}
+	if s.versions[key] != expectedVersion {
+		return conflict(aggregateType, aggregateID, expectedVersion)
+	}
+	now := s.clock.Now()
+	for i, envelope := range envelopes {
+		envelope.Version = max(envelope.Version, 1)
+		envelope.AggregateType, envelope.AggregateID = aggregateType, aggregateID
+		envelope.Payload = append(json.RawMessage(nil), envelope.Payload...)
+		s.records = append(s.records, Record{
+			Position:   int64(len(s.records) + 1),
+			Version:    expectedVersion + i + 1,
+			Envelope:   envelope,
+			RecordedAt: now,
+		})
+	}
+	s.versions[key] = expectedVersion + len(envelopes)
+	return nil
+}
+
+// Load returns an aggregate's events after afterVersion
+func (s *MemoryStore) Load(_ context.Context, aggregateType, aggregateID string, afterVersion int) ([]Record, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var records []Record
+	for _, record := range s.records {
+		if record.Envelope.AggregateType == aggregateType && record.Envelope.AggregateID == aggregateID &&
+			record.Version > afterVersion {
+			records = append(records, record)
+		}
+	}
+	return records, nil
+}
+
+// ReadAll returns events after afterPosition
+func (s *MemoryStore) ReadAll(_ context.Context, afterPosition int64, limit int) ([]Record, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if afterPosition >= int64(len(s.records)) {
+		return nil, nil
+	}
+	records := s.records[max(afterPosition, 0):]
+	if len(records) > limit {
+		records = records[:limit]
+	}
+	return append([]Record(nil), records...), nil
+}
+
+// Head returns the number of stored events
+func (s *MemoryStore) Head(_ context.Context) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return int64(len(s.records)), nil
+}
+
+// SaveSnapshot replaces the snapshot unless a newer one is stored
+func (s *MemoryStore) SaveSnapshot(_ context.Context, aggregateType, aggregateID string, snapshot Snapshot) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := [2]string{aggregateType, aggregateID}
+	if current, ok := s.snapshots[key]; ok && current.Version >= snapshot.Version {
+		return nil
+	}
+	snapshot.State = append(json.RawMessage(nil), snapshot.State...)
+	s.snapshots[key] = snapshot
+	return nil
+}
+
+// LoadSnapshot returns the snapshot
+func (s *MemoryStore) LoadSnapshot(_ context.Context, aggregateType, aggregateID string) (Snapshot, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	snapshot, ok := s.snapshots[[2]string{aggregateType, aggregateID}]
+	return snapshot, ok, nil
+}
@@ -0,0 +1,77 @@
+package export
+
+import (
+	"mime"
+	"strings"
+
+	"golang-arch/internal/shared/domain/domainerror"
+)
+
+// Format is the file format of an export
+type Format string
+
+// Export formats
+const (
+	FormatCSV  Format = "csv"
+	FormatXLSX Format = "xlsx"
+	FormatJSON Format = "json"
+)
+
+// Media types of the formats
+const (
+	ContentTypeCSV  = "text/csv; charset=utf-8"
+	ContentTypeXLSX = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	ContentTypeJSON = "application/json"
+)
+
+// formats lists the formats in the order Negotiate prefers them
+var formats = []Format{FormatCSV, FormatXLSX, FormatJSON}
+
+// ParseFormat reads a format name such as "xlsx", in any case
+func ParseFormat(name string) (Format, error) {
+	format := Format(strings.ToLower(strings.TrimSpace(name)))
+	for _, known := range formats {
+		if format == known {
+			return format, nil
+		}
+	}
+	return "", domainerror.Invalidf("unsupported export format %q, use csv, xlsx or json", name)
+}
+
+// ContentType returns the media type of files in the format
+func (f Format) ContentType() string {
+	switch f {
+	case FormatXLSX:
+		return ContentTypeXLSX
+	case FormatJSON:
+		return ContentTypeJSON
+	default:
+		return ContentTypeCSV
+	}
+}
+
+// Extension returns the file name extension of the format, without the dot
+func (f Format) Extension() string {
+	return string(f)
+}
+
+// Negotiate picks the format of an export request: the format parameter
+// when given, otherwise the first format the Accept header names, and CSV
+// when it names none of them (e.g. "*/*").
+func Negotiate(format, accept string) (Format, error) {
+	if format != "" {
+		return ParseFormat(format)
+	}
+	for _, entry := range strings.Split(accept, ",") {
+		mediaType, _, err := mime.ParseMediaType(strings.TrimSpace(entry))
+		if err != nil {
+			continue
+		}
+		for _, known := range formats {
+			if base, _, _ := mime.ParseMediaType(known.ContentType()); base == mediaType {
+				return known, nil
+			}
+		}
+	}
+	return FormatCSV, nil
+}
@@ -0,0 +1,73 @@
+package export
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"golang-arch/internal/shared/api"
+	"golang-arch/internal/shared/domain/validation"
+	"golang-arch/internal/shared/rbac"
+	"golang-arch/internal/shared/templates"
+)
+
+// Handler starts exports from HTTP requests. Service routes call Start
+// with their query, e.g.
+//
+//	group.GET("/orders/export", func(c *gin.Context) {
+//		exports.Start(c, "orders", orderService.ExportQuery(filter))
+//	})
+type Handler struct {
+	service  *Service
+	views    *templates.Engine
+	jobsPath string
+}
+
+// NewHandler creates the export handler; files are formatted for the
+// locale and timezone views resolves for the request. jobsPath is where the
+// job routes are mounted, e.g. "/api/v1/jobs".
+func NewHandler(service *Service, views *templates.Engine, jobsPath string) *Handler {
+	return &Handler{service: service, views: views, jobsPath: strings.TrimSuffix(jobsPath, "/")}
+}
+
+// Start negotiates the format from ?format= or the Accept header, starts
+// the export for the request's reader and answers 202 Accepted with the job
+// record. Location points to the job, which holds the download URL once it
+// succeeded. ?timezone= overrides the reader's timezone, and an
+// authenticated principal is named as the requester.
+func (h *Handler) Start(c *gin.Context, name string, query Query) {
+	var errs validation.ValidationErrors
+	format, err := Negotiate(c.Query("format"), c.GetHeader("Accept"))
+	if err != nil {
+		errs.Add("format", validation.CodeInvalidFormat, err.Error(), map[string]any{"allowed": formats})
+	}
+	prefs := h.views.Preferences(c)
+	opts := Options{Locale: prefs.Locale, Timezone: prefs.Timezone.ID}
+	if timezone := c.Query("timezone"); timezone != "" {
+		opts.Timezone = timezone
+		if _, err := opts.csvOptions(); err != nil {
+			errs.Add("timezone", validation.CodeInvalidFormat, "timezone must be an IANA zone such as Europe/Berlin", nil)
+		}
+	}
+	if err := errs.Err(); err != nil {
+		api.ValidationFailed(c, api.ErrValidationFailed.Error(), err)
+		return
+	}
+
+	req := Request{Name: name, Format: format, Options: opts, Query: query}
+	if principal, ok := rbac.FromContext(c.Request.Context()); ok {
+		req.Requester = principal.Name
+	}
+	record, err := h.service.Start(c.Request.Context(), req)
+	if err != nil {
+		api.RespondError(c, err)
+		return
+	}
+	c.Header("Location", h.jobsPath+"/"+record.ID)
+	api.Render(c, http.StatusAccepted, api.Response{
+		Success: true,
+		Message: "export started",
+		Data:    record,
+	})
+}
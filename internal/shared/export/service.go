@@ -0,0 +1,349 @@
+// Package export streams large query results into CSV, XLSX or JSON files
+// in the blob store. An export runs as an asynchronous job: the client gets
+// the job ID at once, follows its progress at /api/v1/jobs/:id and finds a
+// presigned download URL in the job's result.
+//
+// Rows are written to storage as they are produced, so an export holds one
+// row in memory whatever its size. Completion is announced on the event bus
+// as "export.completed" or "export.failed", for subscribers that notify
+// the requester by email or push.
+package export
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"path"
+	"strconv"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"golang-arch/internal/shared/domain/domainerror"
+	"golang-arch/internal/shared/events"
+	"golang-arch/internal/shared/jobs"
+	"golang-arch/internal/shared/storage"
+	"golang-arch/pkg/clock"
+)
+
+// JobKind is the kind of export job records
+const JobKind = "export"
+
+// Event names
+const (
+	EventCompleted = "export.completed"
+	EventFailed    = "export.failed"
+)
+
+// Defaults of a Service
+const (
+	DefaultConcurrency      = 4
+	DefaultURLTTL           = 24 * time.Hour
+	DefaultProgressInterval = 1000
+)
+
+// keyPrefix prefixes the blobs of exports as
+// "exports/<yyyy>/<mm>/<dd>/<job id>/<name>.<ext>"
+const keyPrefix = "exports/"
+
+// Query produces the rows of an export, calling emit once per row in file
+// order. Rows are structs in the csvmap layout, which also sets the column
+// names of CSV and XLSX files. An error from emit must be returned; it
+// means the export failed or was cancelled.
+type Query func(ctx context.Context, emit func(row any) error) error
+
+// Request describes an export
+type Request struct {
+	Name      string // Names the file, e.g. "orders" gives orders.csv
+	Format    Format
+	Options   Options
+	Requester string // Who to notify on completion, e.g. a user ID; passed on in the events
+	Query     Query
+}
+
+// Result is the outcome of a successful export, stored as the job result
+type Result struct {
+	Filename    string    `json:"filename"`
+	Format      Format    `json:"format"`
+	ContentType string    `json:"content_type"`
+	Rows        int64     `json:"rows"`
+	Size        int64     `json:"size"`
+	Key         string    `json:"key"`
+	URL         string    `json:"url"`        // Presigned download URL
+	ExpiresAt   time.Time `json:"expires_at"` // When URL stops working
+}
+
+// Completed is published when an export's file is ready
+type Completed struct {
+	events.Base
+	Requester string `json:"requester,omitempty"`
+	JobID     string `json:"job_id"`
+	Result    Result `json:"result"`
+}
+
+// EventName implements events.Event
+func (Completed) EventName() string { return EventCompleted }
+
+// Failed is published when an export failed
+type Failed struct {
+	events.Base
+	Requester string `json:"requester,omitempty"`
+	JobID     string `json:"job_id"`
+	Name      string `json:"name"`
+	Error     string `json:"error"`
+}
+
+// EventName implements events.Event
+func (Failed) EventName() string { return EventFailed }
+
+// Service runs exports in the background
+type Service struct {
+	store    *jobs.Store
+	blobs    storage.BlobStore
+	bus      *events.Bus
+	clock    clock.Clock
+	logger   *zap.Logger
+	urlTTL   time.Duration
+	interval int64
+	slots    chan struct{}
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// Option configures a Service
+type Option func(*Service)
+
+// WithClock sets the clock that dates the blobs and events
+func WithClock(c clock.Clock) Option {
+	return func(s *Service) {
+		s.clock = c
+	}
+}
+
+// WithLogger sets the logger
+func WithLogger(logger *zap.Logger) Option {
+	return func(s *Service) {
+		s.logger = logger
+	}
+}
+
+// WithEvents publishes completion events on bus
+func WithEvents(bus *events.Bus) Option {
+	return func(s *Service) {
+		s.bus = bus
+	}
+}
+
+// WithConcurrency sets how many exports run at once; later ones stay
+// pending until a slot frees up
+func WithConcurrency(n int) Option {
+	return func(s *Service) {
+		if n > 0 {
+			s.slots = make(chan struct{}, n)
+		}
+	}
+}
+
+// WithURLTTL sets how long the download URL of a result works
+func WithURLTTL(ttl time.Duration) Option {
+	return func(s *Service) {
+		if ttl > 0 {
+			s.urlTTL = ttl
+		}
+	}
+}
+
+// WithProgressInterval sets after how many rows the job progress is updated
+func WithProgressInterval(rows int64) Option {
+	return func(s *Service) {
+		if rows > 0 {
+			s.interval = rows
+		}
+	}
+}
+
+// NewService creates an export service that tracks exports in store and
+// writes their files to blobs
+func NewService(store *jobs.Store, blobs storage.BlobStore, options ...Option) *Service {
+	ctx, cancel := context.WithCancel(context.Background())
+	s := &Service{
+		store:    store,
+		blobs:    blobs,
+		clock:    clock.New(),
+		logger:   zap.NewNop(),
+		urlTTL:   DefaultURLTTL,
+		interval: DefaultProgressInterval,
+		slots:    make(chan struct{}, DefaultConcurrency),
+		ctx:      ctx,
+		cancel:   cancel,
+	}
+	for _, option := range options {
+		option(s)
+	}
+	return s
+}
+
+// Start creates the export's job and runs it in the background. The
+// returned record is pending; the export does not depend on ctx once
+// Start returned.
+func (s *Service) Start(ctx context.Context, req Request) (*jobs.Record, error) {
+	if err := s.ctx.Err(); err != nil {
+		return nil, domainerror.Unavailablef("exports are shutting down")
+	}
+	if req.Name == "" || req.Query == nil {
+		return nil, fmt.Errorf("export needs a name and a query")
+	}
+	if _, err := req.Options.csvOptions(); err != nil {
+		return nil, domainerror.Invalidf("%v", err)
+	}
+	if req.Format == "" {
+		req.Format = FormatCSV
+	}
+
+	record, err := s.store.Create(ctx, JobKind)
+	if err != nil {
+		return nil, err
+	}
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		select {
+		case s.slots <- struct{}{}:
+		case <-s.ctx.Done():
+			s.finish(record.ID, req, Result{}, fmt.Errorf("export cancelled at shutdown"))
+			return
+		}
+		defer func() { <-s.slots }()
+
+		result, err := s.Run(s.ctx, record.ID, req)
+		s.finish(record.ID, req, result, err)
+	}()
+	return record, nil
+}
+
+// Run performs the export of job id in the caller's goroutine: it streams
+// the rows into the blob store, reporting progress on the job, and returns
+// the result. It does not complete the job.
+func (s *Service) Run(ctx context.Context, id string, req Request) (Result, error) {
+	if err := s.store.Start(ctx, id); err != nil {
+		return Result{}, err
+	}
+
+	now := s.clock.Now().UTC()
+	filename := req.Name + "." + req.Format.Extension()
+	key := path.Join(keyPrefix, now.Format("2006/01/02"), id, filename)
+	upload := storage.NewWriter(ctx, s.blobs, key, storage.PutOptions{
+		ContentType:        req.Format.ContentType(),
+		ContentDisposition: fmt.Sprintf("attachment; filename=%q", filename),
+	})
+	rows, err := NewRowWriter(upload, req.Format, req.Options)
+	if err != nil {
+		return Result{}, upload.Abort(err)
+	}
+
+	var count int64
+	err = req.Query(ctx, func(row any) error {
+		if err := rows.Write(row); err != nil {
+			return err
+		}
+		count++
+		if count%s.interval == 0 {
+			return s.store.SetProgress(ctx, id, jobs.Progress{
+				Done:    count,
+				Message: "job.export.rows",
+				Args:    map[string]string{"rows": strconv.FormatInt(count, 10)},
+			})
+		}
+		return nil
+	})
+	if err == nil {
+		err = rows.Close()
+	}
+	if err != nil {
+		return Result{}, upload.Abort(err)
+	}
+	if err := upload.Close(); err != nil {
+		return Result{}, err
+	}
+
+	url, err := s.blobs.Presign(ctx, http.MethodGet, key, s.urlTTL)
+	if err != nil {
+		return Result{}, err
+	}
+	return Result{
+		Filename:    filename,
+		Format:      req.Format,
+		ContentType: req.Format.ContentType(),
+		Rows:        count,
+		Size:        upload.Info().Size,
+		Key:         key,
+		URL:         url,
+		ExpiresAt:   s.clock.Now().Add(s.urlTTL),
+	}, nil
+}
+
+// finish records the outcome of an export on its job and announces it
+func (s *Service) finish(id string, req Request, result Result, err error) {
+	ctx := context.WithoutCancel(s.ctx)
+	logger := s.logger.With(zap.String("job", id), zap.String("export", req.Name))
+	metadata := events.NewMetadata(s.clock, JobKind, id)
+
+	var event events.Event
+	if err != nil {
+		logger.Error("Export failed", zap.Error(err))
+		if storeErr := s.store.Fail(ctx, id, exportError(err)); storeErr != nil {
+			logger.Error("Failed to record export failure", zap.Error(storeErr))
+		}
+		event = Failed{Base: events.Base{Metadata: metadata}, Requester: req.Requester, JobID: id, Name: req.Name, Error: exportError(err).Error()}
+	} else {
+		logger.Info("Export completed", zap.Int64("rows", result.Rows), zap.Int64("size", result.Size))
+		if storeErr := s.store.Complete(ctx, id, result); storeErr != nil {
+			logger.Error("Failed to record export result", zap.Error(storeErr))
+		}
+		event = Completed{Base: events.Base{Metadata: metadata}, Requester: req.Requester, JobID: id, Result: result}
+	}
+
+	if s.bus != nil {
+		if err := s.bus.PublishAsync(ctx, event); err != nil {
+			logger.Warn("Failed to announce export outcome", zap.Error(err))
+		}
+	}
+}
+
+// exportError is the failure shown to the user; internal causes are not
+// disclosed
+func exportError(err error) error {
+	var domainErr *domainerror.Error
+	if errors.As(err, &domainErr) {
+		return err
+	}
+	return errors.New("the export could not be completed")
+}
+
+// Wait waits for the running and pending exports to finish, or for ctx to
+// end, e.g. while draining for a deploy before Close cancels the rest
+func (s *Service) Wait(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Close cancels running exports, failing their jobs, and waits for them to
+// return
+func (s *Service) Close() error {
+	s.cancel()
+	s.wg.Wait()
+	return nil
+}
@@ -0,0 +1,122 @@
+package export
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"golang-arch/internal/shared/csvmap"
+	i18n "golang-arch/internal/shared/domain/internationalization"
+)
+
+// DefaultTimeLayout renders timestamps in CSV and XLSX files in a form
+// spreadsheet software recognizes as a date and time
+const DefaultTimeLayout = "2006-01-02 15:04:05"
+
+// Options controls how rows are rendered
+type Options struct {
+	// Locale selects the CSV list and decimal separators, e.g. "de-DE"
+	// writes "1234,50" separated by ';'. XLSX stores numbers as numbers,
+	// which spreadsheet software shows in the reader's locale.
+	Locale i18n.Locale
+	// Timezone is the IANA zone timestamps are shown in; empty keeps each
+	// timestamp in its own zone
+	Timezone string
+	// TimeLayout formats timestamps; DefaultTimeLayout when empty
+	TimeLayout string
+}
+
+// csvOptions returns the csvmap options of a CSV file
+func (o Options) csvOptions() (csvmap.Options, error) {
+	opts := csvmap.LocaleOptions(string(o.Locale))
+	opts.TimeLayout = o.TimeLayout
+	if opts.TimeLayout == "" {
+		opts.TimeLayout = DefaultTimeLayout
+	}
+	if o.Timezone != "" {
+		loc, err := time.LoadLocation(o.Timezone)
+		if err != nil {
+			return opts, fmt.Errorf("unknown timezone %q: %w", o.Timezone, err)
+		}
+		opts.Location = loc
+	}
+	return opts, nil
+}
+
+// RowWriter encodes rows, structs in the csvmap layout, into a file
+type RowWriter interface {
+	// Write appends a row; every row must have the same type
+	Write(row any) error
+	// Close completes the file. It does not close the underlying writer.
+	Close() error
+}
+
+// NewRowWriter creates a writer of files in format to w
+func NewRowWriter(w io.Writer, format Format, opts Options) (RowWriter, error) {
+	csvOpts, err := opts.csvOptions()
+	if err != nil {
+		return nil, err
+	}
+	switch format {
+	case FormatCSV:
+		return &csvWriter{enc: csvmap.NewEncoder(w, csvOpts)}, nil
+	case FormatXLSX:
+		// Numbers are stored in the file's neutral notation
+		csvOpts.DecimalSeparator = '.'
+		return newXLSXWriter(w, csvOpts), nil
+	case FormatJSON:
+		return &jsonWriter{w: bufio.NewWriter(w)}, nil
+	default:
+		return nil, fmt.Errorf("unsupported export format %q", format)
+	}
+}
+
+// csvWriter writes rows with csvmap
+type csvWriter struct {
+	enc *csvmap.Encoder
+}
+
+func (w *csvWriter) Write(row any) error {
+	return w.enc.Encode(row)
+}
+
+func (w *csvWriter) Close() error {
+	return w.enc.Flush()
+}
+
+// jsonWriter writes rows as one JSON array, one element per line, using the
+// rows' own JSON encoding so the file reads like the API's responses
+type jsonWriter struct {
+	w    *bufio.Writer
+	rows int
+}
+
+func (w *jsonWriter) Write(row any) error {
+	data, err := json.Marshal(row)
+	if err != nil {
+		return fmt.Errorf("failed to encode row: %w", err)
+	}
+	separator := ",\n"
+	if w.rows == 0 {
+		separator = "[\n"
+	}
+	w.rows++
+	if _, err := w.w.WriteString(separator); err != nil {
+		return err
+	}
+	_, err = w.w.Write(data)
+	return err
+}
+
+func (w *jsonWriter) Close() error {
+	end := "\n]\n"
+	if w.rows == 0 {
+		end = "[]\n"
+	}
+	if _, err := w.w.WriteString(end); err != nil {
+		return err
+	}
+	return w.w.Flush()
+}
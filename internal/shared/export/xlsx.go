@@ -0,0 +1,137 @@
+package export
+
+import (
+	"archive/zip"
+	"bufio"
+	"encoding/xml"
+	"io"
+
+	"golang-arch/internal/shared/csvmap"
+)
+
+// xlsxParts are the fixed parts of a single-sheet workbook. The sheet itself
+// is streamed last as xl/worksheets/sheet1.xml.
+var xlsxParts = []struct{ name, body string }{
+	{"[Content_Types].xml", xml.Header + `<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+		`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+		`<Default Extension="xml" ContentType="application/xml"/>` +
+		`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>` +
+		`<Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>` +
+		`<Override PartName="/xl/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.styles+xml"/>` +
+		`</Types>`},
+	{"_rels/.rels", xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+		`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+		`</Relationships>`},
+	{"xl/workbook.xml", xml.Header + `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">` +
+		`<sheets><sheet name="Export" sheetId="1" r:id="rId1"/></sheets></workbook>`},
+	{"xl/_rels/workbook.xml.rels", xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+		`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/>` +
+		`<Relationship Id="rId2" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/>` +
+		`</Relationships>`},
+	// Style 1 is the bold header row
+	{"xl/styles.xml", xml.Header + `<styleSheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">` +
+		`<fonts count="2"><font><sz val="11"/><name val="Calibri"/></font><font><b/><sz val="11"/><name val="Calibri"/></font></fonts>` +
+		`<fills count="1"><fill><patternFill patternType="none"/></fill></fills>` +
+		`<borders count="1"><border/></borders>` +
+		`<cellStyleXfs count="1"><xf/></cellStyleXfs>` +
+		`<cellXfs count="2"><xf/><xf fontId="1" applyFont="1"/></cellXfs>` +
+		`</styleSheet>`},
+}
+
+// xlsxWriter streams rows into an Office Open XML workbook with one sheet.
+// Cells are inline strings, except numeric columns, which are stored as
+// numbers so they can be summed and are shown in the reader's locale.
+type xlsxWriter struct {
+	zip     *zip.Writer
+	sheet   *bufio.Writer
+	opts    csvmap.Options
+	columns []csvmap.Column
+	err     error
+}
+
+func newXLSXWriter(w io.Writer, opts csvmap.Options) *xlsxWriter {
+	x := &xlsxWriter{zip: zip.NewWriter(w), opts: opts}
+	for _, part := range xlsxParts {
+		if x.err = x.writePart(part.name, part.body); x.err != nil {
+			return x
+		}
+	}
+	sheet, err := x.zip.Create("xl/worksheets/sheet1.xml")
+	if err != nil {
+		x.err = err
+		return x
+	}
+	x.sheet = bufio.NewWriter(sheet)
+	_, x.err = x.sheet.WriteString(xml.Header +
+		`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`)
+	return x
+}
+
+func (x *xlsxWriter) writePart(name, body string) error {
+	part, err := x.zip.Create(name)
+	if err != nil {
+		return err
+	}
+	_, err = io.WriteString(part, body)
+	return err
+}
+
+func (x *xlsxWriter) Write(row any) error {
+	if x.err != nil {
+		return x.err
+	}
+	if x.columns == nil {
+		if x.columns, x.err = csvmap.Columns(row); x.err != nil {
+			return x.err
+		}
+		header := make([]string, len(x.columns))
+		for i, column := range x.columns {
+			header[i] = column.Name
+		}
+		x.writeRow(header, true)
+	}
+	cells, err := csvmap.Record(row, x.opts)
+	if err != nil {
+		x.err = err
+		return err
+	}
+	x.writeRow(cells, false)
+	return x.err
+}
+
+// writeRow appends a <row>; the header is all bold strings
+func (x *xlsxWriter) writeRow(cells []string, header bool) {
+	x.sheet.WriteString("<row>")
+	for i, cell := range cells {
+		switch {
+		case header:
+			x.sheet.WriteString(`<c t="inlineStr" s="1"><is><t>`)
+			xml.EscapeText(x.sheet, []byte(cell))
+			x.sheet.WriteString("</t></is></c>")
+		case cell == "":
+			x.sheet.WriteString("<c/>")
+		case x.columns[i].Numeric:
+			x.sheet.WriteString("<c><v>")
+			xml.EscapeText(x.sheet, []byte(cell))
+			x.sheet.WriteString("</v></c>")
+		default:
+			x.sheet.WriteString(`<c t="inlineStr"><is><t xml:space="preserve">`)
+			xml.EscapeText(x.sheet, []byte(cell))
+			x.sheet.WriteString("</t></is></c>")
+		}
+	}
+	_, x.err = x.sheet.WriteString("</row>")
+}
+
+func (x *xlsxWriter) Close() error {
+	if x.err != nil {
+		return x.err
+	}
+	if _, err := x.sheet.WriteString("</sheetData></worksheet>"); err != nil {
+		return err
+	}
+	if err := x.sheet.Flush(); err != nil {
+		return err
+	}
+	return x.zip.Close()
+}
@@ -0,0 +1,201 @@
+package fieldcrypt
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"golang-arch/internal/shared/codec"
+)
+
+// envelopeVersion is the first byte of every ciphertext. The envelope is
+//
+//	version(1) | len(keyID)(1) | keyID | nonce(12) | AES-GCM ciphertext and tag
+const envelopeVersion = 1
+
+// ErrMalformed reports a value that is not a fieldcrypt ciphertext
+var ErrMalformed = errors.New("malformed encrypted value")
+
+// Encryptor seals and opens values with the keyring of a KeyProvider. It is
+// safe for concurrent use.
+type Encryptor struct {
+	provider KeyProvider
+
+	mu      sync.RWMutex
+	primary string
+	aeads   map[string]cipher.AEAD
+}
+
+// NewEncryptor loads the keyring from provider
+func NewEncryptor(ctx context.Context, provider KeyProvider) (*Encryptor, error) {
+	e := &Encryptor{provider: provider}
+	if err := e.Reload(ctx); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+// Reload fetches the keyring again, picking up rotated keys
+func (e *Encryptor) Reload(ctx context.Context) error {
+	keyring, err := e.provider.Keyring(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load encryption keys from %s: %w", e.provider.Name(), err)
+	}
+	if err := keyring.Validate(); err != nil {
+		return err
+	}
+
+	aeads := make(map[string]cipher.AEAD, len(keyring.Keys))
+	for id, key := range keyring.Keys {
+		block, err := aes.NewCipher(key)
+		if err != nil {
+			return fmt.Errorf("encryption key %q: %w", id, err)
+		}
+		aead, err := cipher.NewGCM(block)
+		if err != nil {
+			return fmt.Errorf("encryption key %q: %w", id, err)
+		}
+		aeads[id] = aead
+	}
+
+	e.mu.Lock()
+	e.primary, e.aeads = keyring.Primary, aeads
+	e.mu.Unlock()
+	return nil
+}
+
+// Watch reloads the keyring every interval until ctx is done, so a key
+// added or promoted in the provider is used without a restart. A failed
+// reload keeps the current keyring and is reported to onError.
+func (e *Encryptor) Watch(ctx context.Context, interval time.Duration, onError func(error)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := e.Reload(ctx); err != nil && ctx.Err() == nil && onError != nil {
+				onError(err)
+			}
+		}
+	}
+}
+
+// PrimaryKeyID returns the ID of the key that seals new values
+func (e *Encryptor) PrimaryKeyID() string {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.primary
+}
+
+// Encrypt seals plaintext with the primary key. aad, typically the column
+// name, is authenticated but not stored: decrypting with different aad
+// fails, so ciphertexts cannot be moved between columns.
+func (e *Encryptor) Encrypt(plaintext, aad []byte) ([]byte, error) {
+	e.mu.RLock()
+	id, aead := e.primary, e.aeads[e.primary]
+	e.mu.RUnlock()
+
+	out := make([]byte, 0, 2+len(id)+aead.NonceSize()+len(plaintext)+aead.Overhead())
+	out = append(out, envelopeVersion, byte(len(id)))
+	out = append(out, id...)
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	out = append(out, nonce...)
+	return aead.Seal(out, nonce, plaintext, aad), nil
+}
+
+// Decrypt opens a ciphertext sealed with any key in the keyring
+func (e *Encryptor) Decrypt(ciphertext, aad []byte) ([]byte, error) {
+	id, body, err := splitEnvelope(ciphertext)
+	if err != nil {
+		return nil, err
+	}
+
+	e.mu.RLock()
+	aead, ok := e.aeads[id]
+	e.mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("encryption key %q is not available", id)
+	}
+	if len(body) < aead.NonceSize()+aead.Overhead() {
+		return nil, ErrMalformed
+	}
+
+	nonce, sealed := body[:aead.NonceSize()], body[aead.NonceSize():]
+	plaintext, err := aead.Open(nil, nonce, sealed, aad)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt value: %w", err)
+	}
+	return plaintext, nil
+}
+
+// Seal encodes v with its compact MessagePack form and encrypts it
+func (e *Encryptor) Seal(v any, aad []byte) ([]byte, error) {
+	plaintext, err := codec.MsgPack.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode value: %w", err)
+	}
+	return e.Encrypt(plaintext, aad)
+}
+
+// Open decrypts a value sealed by Seal into v
+func (e *Encryptor) Open(ciphertext, aad []byte, v any) error {
+	plaintext, err := e.Decrypt(ciphertext, aad)
+	if err != nil {
+		return err
+	}
+	if err := codec.MsgPack.Unmarshal(plaintext, v); err != nil {
+		return fmt.Errorf("failed to decode value: %w", err)
+	}
+	return nil
+}
+
+// KeyID returns the ID of the key that sealed ciphertext
+func KeyID(ciphertext []byte) (string, error) {
+	id, _, err := splitEnvelope(ciphertext)
+	return id, err
+}
+
+// NeedsRotation reports whether ciphertext was sealed with a key other than
+// the primary one
+func (e *Encryptor) NeedsRotation(ciphertext []byte) bool {
+	id, err := KeyID(ciphertext)
+	return err == nil && id != e.PrimaryKeyID()
+}
+
+// Rotate re-encrypts ciphertext with the primary key. Values already
+// sealed with it are returned unchanged.
+func (e *Encryptor) Rotate(ciphertext, aad []byte) ([]byte, error) {
+	if !e.NeedsRotation(ciphertext) {
+		if _, err := KeyID(ciphertext); err != nil {
+			return nil, err
+		}
+		return ciphertext, nil
+	}
+	plaintext, err := e.Decrypt(ciphertext, aad)
+	if err != nil {
+		return nil, err
+	}
+	return e.Encrypt(plaintext, aad)
+}
+
+// splitEnvelope returns the key ID and the nonce-prefixed sealed data
+func splitEnvelope(ciphertext []byte) (string, []byte, error) {
+	if len(ciphertext) < 2 || ciphertext[0] != envelopeVersion {
+		return "", nil, ErrMalformed
+	}
+	idEnd := 2 + int(ciphertext[1])
+	if len(ciphertext) < idEnd {
+		return "", nil, ErrMalformed
+	}
+	return string(ciphertext[2:idEnd]), ciphertext[idEnd:], nil
+}
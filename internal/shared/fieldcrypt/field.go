@@ -0,0 +1,84 @@
+package fieldcrypt
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"reflect"
+)
+
+// Field encrypts the values of one column. Its name is the associated data
+// of every ciphertext, so a value copied to another column fails to decrypt.
+//
+//	phones := encryptor.Field("customers.phone")
+//	db.ExecContext(ctx, "UPDATE customers SET phone = $1 WHERE id = $2", phones.Value(phone), id)
+//	db.QueryRowContext(ctx, "SELECT phone FROM customers WHERE id = $1", id).Scan(phones.Scan(&phone))
+type Field struct {
+	encryptor *Encryptor
+	aad       []byte
+}
+
+// Field returns the encrypted column named name, e.g. "customers.phone"
+func (e *Encryptor) Field(name string) *Field {
+	return &Field{encryptor: e, aad: []byte(name)}
+}
+
+// Seal encrypts v for this column
+func (f *Field) Seal(v any) ([]byte, error) {
+	return f.encryptor.Seal(v, f.aad)
+}
+
+// Open decrypts a value of this column into v
+func (f *Field) Open(ciphertext []byte, v any) error {
+	return f.encryptor.Open(ciphertext, f.aad, v)
+}
+
+// Rotate re-encrypts a value of this column with the primary key
+func (f *Field) Rotate(ciphertext []byte) ([]byte, error) {
+	return f.encryptor.Rotate(ciphertext, f.aad)
+}
+
+// Value returns a query argument that stores v encrypted (bytea). A nil
+// pointer is stored as NULL.
+func (f *Field) Value(v any) driver.Valuer {
+	return valuer{field: f, value: v}
+}
+
+// Scan returns a scan destination that decrypts into dst, which must be a
+// pointer. NULL sets dst to its zero value.
+func (f *Field) Scan(dst any) sql.Scanner {
+	return scanner{field: f, dst: dst}
+}
+
+type valuer struct {
+	field *Field
+	value any
+}
+
+func (v valuer) Value() (driver.Value, error) {
+	if rv := reflect.ValueOf(v.value); v.value == nil || (rv.Kind() == reflect.Pointer && rv.IsNil()) {
+		return nil, nil
+	}
+	return v.field.Seal(v.value)
+}
+
+type scanner struct {
+	field *Field
+	dst   any
+}
+
+func (s scanner) Scan(src any) error {
+	switch data := src.(type) {
+	case nil:
+		rv := reflect.ValueOf(s.dst)
+		if rv.Kind() != reflect.Pointer || rv.IsNil() {
+			return fmt.Errorf("fieldcrypt: scan destination must be a non-nil pointer, got %T", s.dst)
+		}
+		rv.Elem().SetZero()
+		return nil
+	case []byte:
+		return s.field.Open(data, s.dst)
+	default:
+		return fmt.Errorf("fieldcrypt: cannot scan %T into an encrypted field", src)
+	}
+}
@@ -0,0 +1,144 @@
+// Package fieldcrypt encrypts individual column values, such as phone
+// numbers, with AES-256-GCM so they are stored encrypted at rest and
+// decrypted transparently when scanned.
+//
+// Every ciphertext records the ID of the key that sealed it. New values are
+// sealed with the keyring's primary key while older keys stay available for
+// decryption, so keys are rotated by adding a key, making it primary and
+// re-encrypting rows with Encryptor.Rotate at leisure. Keys come from
+// configuration (StaticKeyProvider) or from a secrets manager
+// (VaultKeyProvider), which Encryptor.Watch polls to pick up rotations.
+package fieldcrypt
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"sort"
+	"strings"
+
+	"golang-arch/internal/shared/config"
+)
+
+// KeySize is the length of AES-256 keys in bytes
+const KeySize = 32
+
+// maxKeyIDLength keeps key IDs within the one-byte length of the envelope
+const maxKeyIDLength = 255
+
+// Keyring holds the data encryption keys by ID
+type Keyring struct {
+	Primary string            // ID of the key that seals new values
+	Keys    map[string][]byte // Every key that may still have sealed stored values
+}
+
+// Validate checks key sizes and that the primary key exists
+func (k *Keyring) Validate() error {
+	if len(k.Keys) == 0 {
+		return fmt.Errorf("no encryption keys configured")
+	}
+	for id, key := range k.Keys {
+		if id == "" || len(id) > maxKeyIDLength {
+			return fmt.Errorf("invalid encryption key id %q", id)
+		}
+		if len(key) != KeySize {
+			return fmt.Errorf("encryption key %q must be %d bytes, got %d", id, KeySize, len(key))
+		}
+	}
+	if _, ok := k.Keys[k.Primary]; !ok {
+		return fmt.Errorf("primary encryption key %q is not configured", k.Primary)
+	}
+	return nil
+}
+
+// KeyProvider loads the keyring from where the secrets are kept
+type KeyProvider interface {
+	Name() string
+	Keyring(ctx context.Context) (*Keyring, error)
+}
+
+// StaticKeyProvider serves keys from configuration
+type StaticKeyProvider struct {
+	keyring *Keyring
+}
+
+// NewStaticKeyProvider builds a keyring from a ParseKeys spec. Without an
+// explicit primary, the last ID in sort order is primary, so date-based IDs
+// such as "2024-06" rotate by appending a newer key.
+func NewStaticKeyProvider(spec, primary string) (*StaticKeyProvider, error) {
+	keyring, err := parseKeyring(spec, primary)
+	if err != nil {
+		return nil, err
+	}
+	return &StaticKeyProvider{keyring: keyring}, nil
+}
+
+// parseKeyring builds a validated keyring from a ParseKeys spec, the last ID
+// in sort order being primary unless primary names one
+func parseKeyring(spec, primary string) (*Keyring, error) {
+	keys, err := ParseKeys(spec)
+	if err != nil {
+		return nil, err
+	}
+	keyring := &Keyring{Primary: primary, Keys: keys}
+	if keyring.Primary == "" && len(keys) > 0 {
+		ids := make([]string, 0, len(keys))
+		for id := range keys {
+			ids = append(ids, id)
+		}
+		sort.Strings(ids)
+		keyring.Primary = ids[len(ids)-1]
+	}
+	if err := keyring.Validate(); err != nil {
+		return nil, err
+	}
+	return keyring, nil
+}
+
+// ParseKeys parses a comma-separated "id=base64key" list
+func ParseKeys(spec string) (map[string][]byte, error) {
+	keys := make(map[string][]byte)
+
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		id, encoded, found := strings.Cut(entry, "=")
+		id = strings.TrimSpace(id)
+		if !found || id == "" {
+			return nil, fmt.Errorf("invalid encryption key entry, expected id=base64key")
+		}
+		key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+		if err != nil {
+			return nil, fmt.Errorf("encryption key %q is not valid base64: %w", id, err)
+		}
+		keys[id] = key
+	}
+
+	return keys, nil
+}
+
+// Name returns "static"
+func (p *StaticKeyProvider) Name() string { return "static" }
+
+// Keyring returns the configured keys
+func (p *StaticKeyProvider) Keyring(context.Context) (*Keyring, error) {
+	return p.keyring, nil
+}
+
+// NewKeyProvider builds the key provider named in cfg; it returns nil when
+// encryption is disabled
+func NewKeyProvider(cfg config.EncryptionConfig) (KeyProvider, error) {
+	switch cfg.Provider {
+	case "", "none":
+		return nil, nil
+	case "static":
+		return NewStaticKeyProvider(cfg.Keys, cfg.PrimaryKey)
+	case "vault":
+		return NewVaultKeyProvider(cfg.Vault)
+	default:
+		return nil, fmt.Errorf("unknown encryption key provider %q", cfg.Provider)
+	}
+}
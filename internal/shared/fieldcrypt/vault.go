@@ -0,0 +1,95 @@
+package fieldcrypt
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"golang-arch/internal/shared/config"
+)
+
+// VaultKeyProvider reads the keyring from a HashiCorp Vault KV version 2
+// secret, so keys are kept and rotated in Vault instead of in configuration.
+// The secret holds the StaticKeyProvider "keys" list and an optional
+// "primary_key":
+//
+//	vault kv put secret/golang-arch/encryption keys="2024-01=...,2024-07=..."
+//
+// Rotating is writing a new version of the secret with an added key; the
+// Encryptor picks it up on its next Reload.
+type VaultKeyProvider struct {
+	url        string
+	token      string
+	httpClient *http.Client
+}
+
+// NewVaultKeyProvider creates a provider reading cfg.Path from the KV v2
+// engine mounted at cfg.Mount
+func NewVaultKeyProvider(cfg config.VaultConfig) (*VaultKeyProvider, error) {
+	address := strings.TrimSuffix(strings.TrimSpace(cfg.Address), "/")
+	if address == "" {
+		return nil, fmt.Errorf("encryption.vault.address is required for the vault key provider")
+	}
+	if parsed, err := url.Parse(address); err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return nil, fmt.Errorf("invalid encryption.vault.address %q, expected http(s)://host:port", cfg.Address)
+	}
+	if cfg.Token == "" {
+		return nil, fmt.Errorf("encryption.vault.token is required for the vault key provider")
+	}
+	mount, path := strings.Trim(cfg.Mount, "/"), strings.Trim(cfg.Path, "/")
+	if mount == "" || path == "" {
+		return nil, fmt.Errorf("encryption.vault.mount and encryption.vault.path are required for the vault key provider")
+	}
+	timeout := cfg.Timeout
+	if timeout <= 0 {
+		timeout = 10 * time.Second
+	}
+	return &VaultKeyProvider{
+		url:        address + "/v1/" + mount + "/data/" + path,
+		token:      cfg.Token,
+		httpClient: &http.Client{Timeout: timeout},
+	}, nil
+}
+
+// Name returns "vault"
+func (p *VaultKeyProvider) Name() string { return "vault" }
+
+// vaultSecret is the response of a KV v2 read
+type vaultSecret struct {
+	Data struct {
+		Data struct {
+			Keys       string `json:"keys"`
+			PrimaryKey string `json:"primary_key"`
+		} `json:"data"`
+	} `json:"data"`
+}
+
+// Keyring reads the latest version of the secret
+func (p *VaultKeyProvider) Keyring(ctx context.Context) (*Keyring, error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, p.url, nil)
+	if err != nil {
+		return nil, err
+	}
+	request.Header.Set("X-Vault-Token", p.token)
+
+	response, err := p.httpClient.Do(request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read vault secret: %w", err)
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		_, _ = io.Copy(io.Discard, response.Body)
+		return nil, fmt.Errorf("vault answered %d for the encryption keys", response.StatusCode)
+	}
+
+	var secret vaultSecret
+	if err := json.NewDecoder(response.Body).Decode(&secret); err != nil {
+		return nil, fmt.Errorf("failed to decode vault secret: %w", err)
+	}
+	return parseKeyring(secret.Data.Data.Keys, secret.Data.Data.PrimaryKey)
+}
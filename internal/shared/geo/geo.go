@@ -0,0 +1,202 @@
+// Package geo derives a request's probable country, timezone and locale from
+// its client IP. A Resolver looks the address up (MaxMind databases in
+// production, a static table in dev and tests) and Middleware stores the
+// result in the request context as the formatting and currency defaults of
+// unauthenticated requests.
+package geo
+
+import (
+	"context"
+	"net/netip"
+	"strings"
+
+	i18n "golang-arch/internal/shared/domain/internationalization"
+)
+
+// Location is what is known or assumed about where a request comes from
+type Location struct {
+	Country  string `json:"country"`  // ISO 3166-1 alpha-2 code, e.g. "ID"
+	Timezone string `json:"timezone"` // IANA identifier, e.g. "Asia/Jakarta"
+	Locale   string `json:"locale"`   // BCP 47 tag, e.g. "id-ID"
+	Detected bool   `json:"detected"` // False when the configured defaults were used
+}
+
+// Resolver looks up the location of an IP address. ok is false when the
+// address is unknown, e.g. a private or reserved address.
+type Resolver interface {
+	Lookup(ip netip.Addr) (location Location, ok bool, err error)
+}
+
+// Preferences returns the location as a request-level source for
+// i18n.ResolveLocalePreferences; undetected locations contribute nothing but
+// the Accept-Language locale
+func (l Location) Preferences() i18n.PartialLocalePreferences {
+	if !l.Detected {
+		return i18n.PartialLocalePreferences{Locale: l.Locale}
+	}
+	return i18n.PartialLocalePreferences{Locale: l.Locale, Timezone: l.Timezone}
+}
+
+// NopResolver knows no addresses; every request gets the defaults
+type NopResolver struct{}
+
+// Lookup always reports the address as unknown
+func (NopResolver) Lookup(netip.Addr) (Location, bool, error) {
+	return Location{}, false, nil
+}
+
+// StaticResolver maps network prefixes to locations, for dev and tests
+type StaticResolver map[netip.Prefix]Location
+
+// Lookup returns the location of the most specific prefix containing ip
+func (r StaticResolver) Lookup(ip netip.Addr) (Location, bool, error) {
+	var (
+		best  Location
+		bits  = -1
+		found bool
+	)
+	for prefix, location := range r {
+		if prefix.Contains(ip) && prefix.Bits() > bits {
+			best, bits, found = location, prefix.Bits(), true
+		}
+	}
+	return best, found, nil
+}
+
+// countryDefault is the most common language and timezone of a country
+type countryDefault struct {
+	language string
+	timezone string
+}
+
+// countryDefaults fills in what a Country-level database does not provide.
+// Countries spanning several zones map to their most populous one.
+var countryDefaults = map[string]countryDefault{
+	"AE": {"ar", "Asia/Dubai"},
+	"AR": {"es", "America/Argentina/Buenos_Aires"},
+	"AT": {"de", "Europe/Vienna"},
+	"AU": {"en", "Australia/Sydney"},
+	"BE": {"nl", "Europe/Brussels"},
+	"BR": {"pt", "America/Sao_Paulo"},
+	"CA": {"en", "America/Toronto"},
+	"CH": {"de", "Europe/Zurich"},
+	"CN": {"zh", "Asia/Shanghai"},
+	"DE": {"de", "Europe/Berlin"},
+	"DK": {"da", "Europe/Copenhagen"},
+	"ES": {"es", "Europe/Madrid"},
+	"FI": {"fi", "Europe/Helsinki"},
+	"FR": {"fr", "Europe/Paris"},
+	"GB": {"en", "Europe/London"},
+	"HK": {"zh", "Asia/Hong_Kong"},
+	"ID": {"id", "Asia/Jakarta"},
+	"IE": {"en", "Europe/Dublin"},
+	"IN": {"hi", "Asia/Kolkata"},
+	"IT": {"it", "Europe/Rome"},
+	"JP": {"ja", "Asia/Tokyo"},
+	"KR": {"ko", "Asia/Seoul"},
+	"MX": {"es", "America/Mexico_City"},
+	"MY": {"ms", "Asia/Kuala_Lumpur"},
+	"NL": {"nl", "Europe/Amsterdam"},
+	"NO": {"nb", "Europe/Oslo"},
+	"NZ": {"en", "Pacific/Auckland"},
+	"PH": {"fil", "Asia/Manila"},
+	"PL": {"pl", "Europe/Warsaw"},
+	"PT": {"pt", "Europe/Lisbon"},
+	"RU": {"ru", "Europe/Moscow"},
+	"SA": {"ar", "Asia/Riyadh"},
+	"SE": {"sv", "Europe/Stockholm"},
+	"SG": {"en", "Asia/Singapore"},
+	"TH": {"th", "Asia/Bangkok"},
+	"TR": {"tr", "Europe/Istanbul"},
+	"TW": {"zh", "Asia/Taipei"},
+	"US": {"en", "America/New_York"},
+	"VN": {"vi", "Asia/Ho_Chi_Minh"},
+	"ZA": {"en", "Africa/Johannesburg"},
+}
+
+// Detect resolves ip and completes the result: a missing timezone comes from
+// the country, and the locale combines the first Accept-Language entry with
+// the country, e.g. "Accept-Language: de" from Austria gives "de-AT".
+// Anything that cannot be derived is taken from defaults.
+func Detect(resolver Resolver, ip string, acceptLanguage string, defaults Location) Location {
+	result := defaults
+	result.Detected = false
+
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return withLocale(result, acceptLanguage)
+	}
+
+	found, ok, err := resolver.Lookup(addr.Unmap())
+	if err != nil || !ok || found.Country == "" {
+		return withLocale(result, acceptLanguage)
+	}
+
+	country := strings.ToUpper(found.Country)
+	result.Country = country
+	result.Detected = true
+
+	fallback, known := countryDefaults[country]
+	switch {
+	case found.Timezone != "":
+		result.Timezone = found.Timezone
+	case known:
+		result.Timezone = fallback.timezone
+	}
+
+	switch {
+	case found.Locale != "":
+		result.Locale = found.Locale
+	case known:
+		result.Locale = fallback.language + "-" + country
+	}
+	return withLocale(result, acceptLanguage)
+}
+
+// withLocale lets the first Accept-Language entry override the locale,
+// adding the detected country as the region when the entry has none
+func withLocale(location Location, acceptLanguage string) Location {
+	tag := acceptLanguage
+	if i := strings.IndexAny(tag, ",;"); i >= 0 {
+		tag = tag[:i]
+	}
+	tag = strings.ReplaceAll(strings.TrimSpace(tag), "_", "-")
+	if tag == "" || tag == "*" {
+		return location
+	}
+
+	// Canonical casing: language lower, script title, region upper
+	subtags := strings.Split(tag, "-")
+	subtags[0] = strings.ToLower(subtags[0])
+	hasRegion := false
+	for i := 1; i < len(subtags); i++ {
+		switch len(subtags[i]) {
+		case 4:
+			subtags[i] = strings.ToUpper(subtags[i][:1]) + strings.ToLower(subtags[i][1:])
+		case 2, 3:
+			subtags[i] = strings.ToUpper(subtags[i])
+			hasRegion = true
+		default:
+			subtags[i] = strings.ToLower(subtags[i])
+		}
+	}
+	if !hasRegion && location.Country != "" {
+		subtags = append(subtags, location.Country)
+	}
+	location.Locale = strings.Join(subtags, "-")
+	return location
+}
+
+// contextKey is the request context key of the detected Location
+type contextKey struct{}
+
+// NewContext returns a copy of ctx carrying location
+func NewContext(ctx context.Context, location Location) context.Context {
+	return context.WithValue(ctx, contextKey{}, location)
+}
+
+// FromContext returns the location stored by Middleware, if any
+func FromContext(ctx context.Context) (Location, bool) {
+	location, ok := ctx.Value(contextKey{}).(Location)
+	return location, ok
+}
@@ -0,0 +1,85 @@
+package geo
+
+import (
+	"cmp"
+	"fmt"
+	"net"
+	"net/netip"
+
+	"github.com/oschwald/maxminddb-golang"
+
+	"golang-arch/internal/shared/config"
+	i18n "golang-arch/internal/shared/domain/internationalization"
+)
+
+// MaxMindResolver reads a MaxMind DB file such as GeoLite2-City.mmdb or
+// GeoLite2-Country.mmdb. Country databases carry no timezone; Detect fills it
+// in from the country.
+type MaxMindResolver struct {
+	reader *maxminddb.Reader
+}
+
+// maxMindRecord is the subset of a GeoIP2 City/Country record used here
+type maxMindRecord struct {
+	Country struct {
+		ISOCode string `maxminddb:"iso_code"`
+	} `maxminddb:"country"`
+	RegisteredCountry struct {
+		ISOCode string `maxminddb:"iso_code"`
+	} `maxminddb:"registered_country"`
+	Location struct {
+		TimeZone string `maxminddb:"time_zone"`
+	} `maxminddb:"location"`
+}
+
+// OpenMaxMind opens the database at path; Close it on shutdown
+func OpenMaxMind(path string) (*MaxMindResolver, error) {
+	reader, err := maxminddb.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open GeoIP database %s: %w", path, err)
+	}
+	return &MaxMindResolver{reader: reader}, nil
+}
+
+// Lookup returns the country and timezone recorded for ip
+func (r *MaxMindResolver) Lookup(ip netip.Addr) (Location, bool, error) {
+	var record maxMindRecord
+	_, ok, err := r.reader.LookupNetwork(net.IP(ip.AsSlice()), &record)
+	if err != nil {
+		return Location{}, false, fmt.Errorf("failed to look up %s: %w", ip, err)
+	}
+	if !ok {
+		return Location{}, false, nil
+	}
+
+	country := record.Country.ISOCode
+	if country == "" {
+		// Anonymous and satellite networks only have the registration country
+		country = record.RegisteredCountry.ISOCode
+	}
+	return Location{Country: country, Timezone: record.Location.TimeZone}, country != "", nil
+}
+
+// Close releases the database
+func (r *MaxMindResolver) Close() error {
+	return r.reader.Close()
+}
+
+// NewResolver opens the configured MaxMind database, or returns a NopResolver
+// when none is configured
+func NewResolver(cfg config.GeoConfig) (Resolver, error) {
+	if cfg.DatabasePath == "" {
+		return NopResolver{}, nil
+	}
+	return OpenMaxMind(cfg.DatabasePath)
+}
+
+// Defaults returns the configured fallback location; a timezone or locale
+// left empty is taken from the application's defaults
+func Defaults(cfg config.GeoConfig, fallback i18n.LocalePreferences) Location {
+	return Location{
+		Country:  cfg.DefaultCountry,
+		Timezone: cmp.Or(cfg.DefaultTimezone, fallback.Timezone.ID),
+		Locale:   cmp.Or(cfg.DefaultLocale, fallback.Locale.String()),
+	}
+}
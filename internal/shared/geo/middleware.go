@@ -0,0 +1,35 @@
+package geo
+
+import (
+	"github.com/gin-gonic/gin"
+
+	i18n "golang-arch/internal/shared/domain/internationalization"
+)
+
+// Middleware stores the detected Location of unauthenticated requests in the
+// request context. Requests carrying an Authorization header are skipped:
+// their defaults come from the user's stored preferences instead.
+func Middleware(resolver Resolver, defaults Location) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.GetHeader("Authorization") != "" {
+			c.Next()
+			return
+		}
+
+		location := Detect(resolver, c.ClientIP(), c.GetHeader("Accept-Language"), defaults)
+		c.Request = c.Request.WithContext(NewContext(c.Request.Context(), location))
+		c.Next()
+	}
+}
+
+// Preferences resolves the reader of a request from sources, such as the
+// signed-in user's saved preferences, then the location Middleware
+// detected, then defaults. The configured fallbacks of undetected requests
+// are left to defaults, which may be a tenant's; only their Accept-Language
+// locale counts.
+func Preferences(c *gin.Context, defaults i18n.LocaleDefaults, sources ...i18n.PartialLocalePreferences) i18n.LocalePreferences {
+	if location, ok := FromContext(c.Request.Context()); ok && (location.Detected || c.GetHeader("Accept-Language") != "") {
+		sources = append(sources, location.Preferences())
+	}
+	return defaults.Resolve(sources...)
+}
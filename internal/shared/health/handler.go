@@ -0,0 +1,52 @@
+package health
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"golang-arch/internal/shared/api"
+	"golang-arch/internal/shared/domain/validation"
+)
+
+// defaultSamples is how many results per check /status returns by default
+const defaultSamples = 60
+
+// Handler exposes the recorded health history over HTTP
+type Handler struct {
+	monitor *Monitor
+}
+
+// NewHandler creates the status page handler
+func NewHandler(monitor *Monitor) *Handler {
+	return &Handler{monitor: monitor}
+}
+
+// Register adds the status route to group:
+//
+//	GET /status?samples=60  uptime, latency and the latest results per check
+func (h *Handler) Register(group *gin.RouterGroup) {
+	group.GET("/status", h.status)
+}
+
+func (h *Handler) status(c *gin.Context) {
+	samples := defaultSamples
+	if value := c.Query("samples"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 0 || parsed > h.monitor.HistorySize() {
+			var errs validation.ValidationErrors
+			errs.Add("samples", validation.CodeOutOfRange, "samples must be between 0 and "+strconv.Itoa(h.monitor.HistorySize()),
+				map[string]any{"min": 0, "max": h.monitor.HistorySize()})
+			api.ValidationFailed(c, api.ErrValidationFailed.Error(), errs.Err())
+			return
+		}
+		samples = parsed
+	}
+
+	status, err := h.monitor.Status(c.Request.Context(), samples)
+	if err != nil {
+		api.RespondError(c, err)
+		return
+	}
+	api.Success(c, status, "status")
+}
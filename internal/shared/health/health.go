@@ -0,0 +1,291 @@
+// Package health probes the dependencies of the service and keeps a rolling
+// history of the results for a status page. /ready reports the probes of
+// the moment; the worker's health_check job records them in a Redis ring
+// buffer per check, from which /status derives uptime and latency.
+//
+// The history is shared by all instances. With several workers every one of
+// them records, which adds samples but does not skew the ratios. Results
+// are lost while Redis itself is down, so its outages show as gaps.
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+
+	"golang-arch/pkg/clock"
+)
+
+// historyKeyPrefix prefixes the ring buffers as "health:history:<check>",
+// lists of JSON results with the newest first
+const historyKeyPrefix = "health:history:"
+
+// Defaults of a Monitor
+const (
+	DefaultTimeout     = 5 * time.Second
+	DefaultHistorySize = 1440 // A day of results at one per minute
+)
+
+// Overall statuses
+const (
+	StatusUp       = "up"       // Every check passed its latest probe
+	StatusDegraded = "degraded" // Some check failed its latest probe
+	StatusDown     = "down"     // Every check failed its latest probe
+	StatusUnknown  = "unknown"  // Nothing recorded yet
+)
+
+// Check is a named dependency probe, e.g. a database ping
+type Check struct {
+	Name  string
+	Probe func(ctx context.Context) error
+}
+
+// Result is the outcome of one probe
+type Result struct {
+	At        time.Time `json:"at"`
+	Up        bool      `json:"up"`
+	LatencyMS float64   `json:"latency_ms"`
+	Error     string    `json:"error,omitempty"`
+}
+
+// Latency summarizes the probe latencies of a check's history
+type Latency struct {
+	AvgMS float64 `json:"avg_ms"`
+	P95MS float64 `json:"p95_ms"`
+	MaxMS float64 `json:"max_ms"`
+}
+
+// CheckStatus is the recorded history of one check
+type CheckStatus struct {
+	Name    string     `json:"name"`
+	Up      bool       `json:"up"`              // Outcome of the latest probe
+	Since   *time.Time `json:"since,omitempty"` // Time of the oldest result kept
+	Samples int        `json:"samples"`         // Results kept
+	Uptime  float64    `json:"uptime"`          // Share of passed probes, 0 to 1
+	Latency Latency    `json:"latency"`         // Over all results kept
+	History []Result   `json:"history"`         // The latest results, newest first
+	Error   string     `json:"error,omitempty"` // Of the latest probe
+}
+
+// Status is the recorded history of every check
+type Status struct {
+	Status string        `json:"status"`
+	Checks []CheckStatus `json:"checks"`
+}
+
+// Monitor probes the checks and records their results
+type Monitor struct {
+	client  *redis.Client
+	checks  []Check
+	clock   clock.Clock
+	logger  *zap.Logger
+	timeout time.Duration
+	size    int
+}
+
+// Option configures a Monitor
+type Option func(*Monitor)
+
+// WithClock sets the clock that timestamps results
+func WithClock(c clock.Clock) Option {
+	return func(m *Monitor) {
+		m.clock = c
+	}
+}
+
+// WithLogger sets the logger
+func WithLogger(logger *zap.Logger) Option {
+	return func(m *Monitor) {
+		m.logger = logger
+	}
+}
+
+// WithTimeout bounds each probe
+func WithTimeout(timeout time.Duration) Option {
+	return func(m *Monitor) {
+		if timeout > 0 {
+			m.timeout = timeout
+		}
+	}
+}
+
+// WithHistorySize sets how many results are kept per check
+func WithHistorySize(size int) Option {
+	return func(m *Monitor) {
+		if size > 0 {
+			m.size = size
+		}
+	}
+}
+
+// NewMonitor creates a monitor of checks recording to client
+func NewMonitor(client *redis.Client, checks []Check, options ...Option) *Monitor {
+	m := &Monitor{
+		client:  client,
+		checks:  checks,
+		clock:   clock.New(),
+		logger:  zap.NewNop(),
+		timeout: DefaultTimeout,
+		size:    DefaultHistorySize,
+	}
+	for _, option := range options {
+		option(m)
+	}
+	return m
+}
+
+// HistorySize returns how many results are kept per check
+func (m *Monitor) HistorySize() int {
+	return m.size
+}
+
+// Probe runs every check concurrently, each bounded by the timeout, and
+// returns the results by check name
+func (m *Monitor) Probe(ctx context.Context) map[string]Result {
+	results := make(map[string]Result, len(m.checks))
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for _, check := range m.checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			result := m.probe(ctx, check)
+			mu.Lock()
+			results[check.Name] = result
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+	return results
+}
+
+func (m *Monitor) probe(ctx context.Context, check Check) Result {
+	ctx, cancel := context.WithTimeout(ctx, m.timeout)
+	defer cancel()
+
+	result := Result{At: m.clock.Now(), Up: true}
+	// Latency is measured on the wall clock, whatever clock timestamps it
+	start := time.Now()
+	err := check.Probe(ctx)
+	result.LatencyMS = float64(time.Since(start).Microseconds()) / 1000
+	if err != nil {
+		result.Up, result.Error = false, err.Error()
+	}
+	return result
+}
+
+// Record probes every check and appends the results to their histories,
+// dropping the oldest beyond the history size
+func (m *Monitor) Record(ctx context.Context) error {
+	results := m.Probe(ctx)
+	_, err := m.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		for name, result := range results {
+			data, err := json.Marshal(result)
+			if err != nil {
+				return err
+			}
+			pipe.LPush(ctx, historyKeyPrefix+name, data)
+			pipe.LTrim(ctx, historyKeyPrefix+name, 0, int64(m.size-1))
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to record health results: %w", err)
+	}
+	for name, result := range results {
+		if !result.Up {
+			m.logger.Warn("Health check failed", zap.String("check", name), zap.String("error", result.Error))
+		}
+	}
+	return nil
+}
+
+// History returns up to limit recorded results of a check, newest first
+func (m *Monitor) History(ctx context.Context, name string, limit int) ([]Result, error) {
+	if limit <= 0 {
+		return []Result{}, nil
+	}
+	values, err := m.client.LRange(ctx, historyKeyPrefix+name, 0, int64(limit-1)).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read health history of %s: %w", name, err)
+	}
+	results := make([]Result, 0, len(values))
+	for _, value := range values {
+		var result Result
+		if err := json.Unmarshal([]byte(value), &result); err != nil {
+			m.logger.Warn("Skipping malformed health result", zap.String("check", name), zap.Error(err))
+			continue
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// Status summarizes the recorded history of every check, including its
+// latest samples results
+func (m *Monitor) Status(ctx context.Context, samples int) (*Status, error) {
+	status := &Status{Checks: make([]CheckStatus, 0, len(m.checks))}
+	up, recorded := 0, 0
+	for _, check := range m.checks {
+		history, err := m.History(ctx, check.Name, m.size)
+		if err != nil {
+			return nil, err
+		}
+		summary := summarize(check.Name, history)
+		summary.History = history[:min(samples, len(history))]
+		status.Checks = append(status.Checks, summary)
+		if len(history) > 0 {
+			recorded++
+			if summary.Up {
+				up++
+			}
+		}
+	}
+
+	switch {
+	case recorded == 0:
+		status.Status = StatusUnknown
+	case up == recorded:
+		status.Status = StatusUp
+	case up == 0:
+		status.Status = StatusDown
+	default:
+		status.Status = StatusDegraded
+	}
+	return status, nil
+}
+
+// summarize computes the uptime and latencies of a history, newest first
+func summarize(name string, history []Result) CheckStatus {
+	summary := CheckStatus{Name: name, Samples: len(history)}
+	if len(history) == 0 {
+		return summary
+	}
+	summary.Up, summary.Error = history[0].Up, history[0].Error
+	summary.Since = &history[len(history)-1].At
+
+	passed, total := 0, 0.0
+	latencies := make([]float64, len(history))
+	for i, result := range history {
+		if result.Up {
+			passed++
+		}
+		latencies[i] = result.LatencyMS
+		total += result.LatencyMS
+	}
+	sort.Float64s(latencies)
+	summary.Uptime = float64(passed) / float64(len(history))
+	summary.Latency = Latency{
+		AvgMS: total / float64(len(latencies)),
+		P95MS: latencies[int(math.Ceil(0.95*float64(len(latencies))))-1], // Nearest rank
+		MaxMS: latencies[len(latencies)-1],
+	}
+	return summary
+}
@@ -0,0 +1,81 @@
+package i18napi
+
+import (
+	"context"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"golang-arch/internal/shared/api"
+	i18n "golang-arch/internal/shared/domain/internationalization"
+	"golang-arch/internal/shared/domain/validation"
+)
+
+// Calendars adds the stored holidays of a named calendar to a business
+// calendar; refdata.Service implements it
+type Calendars interface {
+	Calendar(ctx context.Context, name string, base i18n.BusinessCalendar) (*i18n.BusinessCalendar, error)
+}
+
+// DueDateBody asks for the deadline of an SLA, e.g. "respond within 8
+// business hours, Berlin time"
+type DueDateBody struct {
+	Calendar *i18n.BusinessCalendar `json:"calendar"` // Working hours, weekend and extra holidays
+	Holidays string                 `json:"holidays"` // Stored holiday calendar to add, e.g. "DE"
+	Start    *time.Time             `json:"start"`    // When the clock starts, RFC 3339
+	Within   string                 `json:"within"`   // Working time, e.g. "8h" or "90m"
+}
+
+// Validate checks that the calendar and start are set and within is a
+// positive duration
+func (b DueDateBody) Validate() error {
+	var errs validation.ValidationErrors
+	if b.Calendar == nil {
+		errs.Add("calendar", validation.CodeRequired, "calendar is required", nil)
+	}
+	if b.Start == nil {
+		errs.Add("start", validation.CodeRequired, "start is required", nil)
+	}
+	if within, err := time.ParseDuration(b.Within); err != nil || within <= 0 {
+		errs.Add("within", validation.CodeInvalidFormat, "within must be a positive duration such as 8h", nil)
+	}
+	return errs.Err()
+}
+
+// DueDateResult is the deadline of an SLA in the calendar's timezone
+type DueDateResult struct {
+	Due      i18n.LocalizedDateTime `json:"due"`
+	Calendar i18n.BusinessCalendar  `json:"calendar"` // With the stored holidays added
+}
+
+// dueDate answers POST /i18n/due-date with the instant the working time in
+// the body has elapsed, skipping weekends, closing hours and holidays
+func (h *Handler) dueDate(c *gin.Context) {
+	var body DueDateBody
+	if !api.BindJSON(c, &body) {
+		return
+	}
+
+	calendar := body.Calendar
+	if body.Holidays != "" {
+		if h.calendars == nil {
+			var errs validation.ValidationErrors
+			errs.Add("holidays", validation.CodeUnsupported, "stored holiday calendars are not available", nil)
+			api.ValidationFailed(c, api.ErrValidationFailed.Error(), errs.Err())
+			return
+		}
+		var err error
+		if calendar, err = h.calendars.Calendar(c.Request.Context(), body.Holidays, *calendar); err != nil {
+			api.RespondError(c, err)
+			return
+		}
+	}
+
+	within, _ := time.ParseDuration(body.Within)
+	due, err := calendar.DueDate(*body.Start, within)
+	if err != nil {
+		api.RespondError(c, err)
+		return
+	}
+	api.Success(c, DueDateResult{Due: *due, Calendar: *calendar}, "due date")
+}
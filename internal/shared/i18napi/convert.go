@@ -0,0 +1,60 @@
+package i18napi
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"golang-arch/internal/shared/api"
+	i18n "golang-arch/internal/shared/domain/internationalization"
+	"golang-arch/internal/shared/domain/validation"
+	"golang-arch/internal/shared/rates"
+)
+
+// Converter converts amounts at the current rates; rates.Service
+// implements it
+type Converter interface {
+	Convert(ctx context.Context, amount i18n.Money, target string, mode i18n.RoundingMode) (*rates.ConvertedMoney, error)
+}
+
+// convert answers GET /i18n/convert?from=USD&to=EUR&amount=10050, where
+// amount is in minor units of from. Rounding defaults to half_even.
+func (h *Handler) convert(c *gin.Context) {
+	var errs validation.ValidationErrors
+	to := strings.ToUpper(c.Query("to"))
+	if to == "" {
+		errs.Add("to", validation.CodeRequired, "to is required", nil)
+	}
+	amount, err := strconv.ParseInt(c.Query("amount"), 10, 64)
+	if err != nil {
+		errs.Add("amount", validation.CodeInvalidFormat, "amount must be an integer number of minor units", nil)
+	}
+	money, err := i18n.NewMoneyFromPrimitive(amount, strings.ToUpper(c.Query("from")))
+	errs.Merge("from", "", err)
+	mode := i18n.RoundHalfEven
+	if name := c.Query("rounding"); name != "" {
+		var ok bool
+		if mode, ok = i18n.RoundingModeByName(name); !ok {
+			errs.Add("rounding", validation.CodeUnsupported, "rounding must be one of "+strings.Join(i18n.RoundingModes(), ", "), nil)
+		}
+	}
+	if err := errs.Err(); err != nil {
+		api.ValidationFailed(c, api.ErrValidationFailed.Error(), err)
+		return
+	}
+
+	converted, err := h.converter.Convert(c.Request.Context(), *money, to, mode)
+	if err != nil {
+		api.RespondError(c, err)
+		return
+	}
+	// The result only changes when the rate does
+	if h.convertAge > 0 {
+		c.Header("Cache-Control", "public, max-age="+strconv.Itoa(int(h.convertAge.Seconds())))
+	}
+	c.Header("Last-Modified", converted.Timestamp.Format(http.TimeFormat))
+	api.Success(c, converted, "converted")
+}
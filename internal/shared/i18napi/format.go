@@ -0,0 +1,149 @@
+package i18napi
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"golang-arch/internal/shared/api"
+	i18n "golang-arch/internal/shared/domain/internationalization"
+	"golang-arch/internal/shared/domain/validation"
+	"golang-arch/internal/shared/geo"
+	"golang-arch/internal/shared/templates"
+)
+
+// maxFormatItems bounds a formatting batch
+const maxFormatItems = 500
+
+// Time styles of FormatItem
+const (
+	StyleDate     = "date"     // e.g. "24.05.2024"
+	StyleDateTime = "datetime" // e.g. "24.05.2024 14:30 CEST"
+)
+
+// FormatBody is a batch of values to format. Locale and Timezone apply to
+// the items that set neither; both default to the request's detected ones.
+type FormatBody struct {
+	Locale   string       `json:"locale"`
+	Timezone string       `json:"tz"`
+	Items    []FormatItem `json:"items" binding:"required"`
+}
+
+// FormatItem is an amount or a point in time. Amounts need a currency;
+// points in time are formatted in the item's timezone.
+type FormatItem struct {
+	Amount   *int64 `json:"amount"` // Minor units, e.g. 10050 for USD 100.50
+	Currency string `json:"currency"`
+	Epoch    *int64 `json:"epoch"` // Unix seconds
+	Timezone string `json:"tz"`
+	Style    string `json:"style"` // date or datetime (default)
+	Locale   string `json:"locale"`
+}
+
+// Validate checks the shape of the batch. Unknown currencies, timezones and
+// locales are reported per item instead, so one bad row does not fail a
+// whole spreadsheet.
+func (b FormatBody) Validate() error {
+	var errs validation.ValidationErrors
+	if len(b.Items) == 0 || len(b.Items) > maxFormatItems {
+		errs.Add("items", validation.CodeOutOfRange, "items must hold between 1 and "+strconv.Itoa(maxFormatItems)+" values", nil)
+	}
+	for i, item := range b.Items {
+		field := "items." + strconv.Itoa(i)
+		switch {
+		case (item.Amount == nil) == (item.Epoch == nil):
+			errs.Add(field, validation.CodeInvalid, "set either amount or epoch", nil)
+		case item.Amount != nil && item.Currency == "":
+			errs.Add(validation.JoinField(field, "currency"), validation.CodeRequired, "currency is required with amount", nil)
+		case item.Epoch != nil && item.Style != "" && item.Style != StyleDate && item.Style != StyleDateTime:
+			errs.Add(validation.JoinField(field, "style"), validation.CodeUnsupported, "style must be date or datetime", nil)
+		}
+	}
+	return errs.Err()
+}
+
+// FormatResult is the formatted value of an item, or why it failed
+type FormatResult struct {
+	Value string `json:"value,omitempty"`
+	Error string `json:"error,omitempty"`
+}
+
+// formatters creates one formatter per locale and timezone of a batch
+type formatters map[string]*templates.Formatter
+
+func (f formatters) get(locale, timezone string) (*templates.Formatter, error) {
+	key := locale + " " + timezone
+	if formatter, ok := f[key]; ok {
+		return formatter, nil
+	}
+	parsed, err := i18n.ParseLocale(locale)
+	if err != nil {
+		return nil, err
+	}
+	tz, err := i18n.NewTimezoneFromID(timezone)
+	if err != nil {
+		return nil, err
+	}
+	formatter := templates.NewFormatter(i18n.LocalePreferences{Locale: parsed, Timezone: *tz}, nil)
+	f[key] = formatter
+	return formatter, nil
+}
+
+func (h *Handler) format(c *gin.Context) {
+	var body FormatBody
+	if !api.BindJSON(c, &body) {
+		return
+	}
+	locale, timezone := h.locale, h.timezone
+	if location, ok := geo.FromContext(c.Request.Context()); ok {
+		locale = firstNonEmpty(location.Locale, locale)
+		timezone = firstNonEmpty(location.Timezone, timezone)
+	}
+	locale, timezone = firstNonEmpty(body.Locale, locale), firstNonEmpty(body.Timezone, timezone)
+
+	cache := formatters{}
+	results := make([]FormatResult, len(body.Items))
+	for i, item := range body.Items {
+		value, err := formatItem(cache, item, firstNonEmpty(item.Locale, locale), firstNonEmpty(item.Timezone, timezone))
+		if err != nil {
+			results[i].Error = err.Error()
+			continue
+		}
+		results[i].Value = value
+	}
+	api.Success(c, results, "formatted")
+}
+
+// formatItem formats one validated item
+func formatItem(cache formatters, item FormatItem, locale, timezone string) (string, error) {
+	if item.Amount != nil {
+		// Amounts do not depend on the timezone, so an invalid one is ignored
+		timezone = "UTC"
+	}
+	formatter, err := cache.get(locale, timezone)
+	if err != nil {
+		return "", err
+	}
+	if item.Amount != nil {
+		money, err := i18n.NewMoneyFromPrimitive(*item.Amount, item.Currency)
+		if err != nil {
+			return "", err
+		}
+		return formatter.Money(*money), nil
+	}
+	t := time.Unix(*item.Epoch, 0).UTC()
+	if item.Style == StyleDate {
+		return formatter.Date(t)
+	}
+	return formatter.DateTime(t)
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
@@ -0,0 +1,316 @@
+// Package i18napi exposes the internationalization catalogs and helpers to
+// clients that cannot embed them, e.g. the timezone and currency pickers of
+// the frontends.
+package i18napi
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"golang-arch/internal/shared/api"
+	i18n "golang-arch/internal/shared/domain/internationalization"
+	"golang-arch/internal/shared/domain/validation"
+	"golang-arch/internal/shared/geo"
+	"golang-arch/internal/shared/respcache"
+)
+
+// Page sizes of the search endpoints
+const (
+	defaultLimit = 20
+	maxLimit     = 100
+)
+
+// Handler serves the i18n endpoints
+type Handler struct {
+	timezones  indexes[TimezoneResult]
+	currencies indexes[CurrencyResult]
+
+	// catalogCache, when set, serves the timezone and currency searches
+	catalogCache *respcache.Cache
+
+	// converter backs /i18n/convert, which is only registered when set
+	converter    Converter
+	convertAge   time.Duration
+	convertLimit gin.HandlerFunc
+
+	// calendars backs the holidays of /i18n/due-date
+	calendars Calendars
+
+	// locale and timezone are used when neither the request nor its
+	// detected location sets them
+	locale   string
+	timezone string
+}
+
+// Option configures a Handler
+type Option func(*Handler)
+
+// WithConverter enables /i18n/convert, with responses cacheable for maxAge
+func WithConverter(converter Converter, maxAge time.Duration) Option {
+	return func(h *Handler) {
+		h.converter, h.convertAge = converter, maxAge
+	}
+}
+
+// WithConvertLimit guards /i18n/convert with limit, e.g. a per-key
+// ratelimit.Limiter middleware
+func WithConvertLimit(limit gin.HandlerFunc) Option {
+	return func(h *Handler) {
+		h.convertLimit = limit
+	}
+}
+
+// WithCatalogCache serves /i18n/timezones and /i18n/currencies from cache.
+// Offsets in cached timezone results are as old as the cached response.
+func WithCatalogCache(cache *respcache.Cache) Option {
+	return func(h *Handler) {
+		h.catalogCache = cache
+	}
+}
+
+// WithCalendars lets /i18n/due-date add stored holiday calendars by name
+func WithCalendars(calendars Calendars) Option {
+	return func(h *Handler) {
+		h.calendars = calendars
+	}
+}
+
+// WithDefaults sets the locale and timezone of requests that set neither,
+// English and UTC otherwise
+func WithDefaults(prefs i18n.LocalePreferences) Option {
+	return func(h *Handler) {
+		h.locale, h.timezone = prefs.Locale.String(), prefs.Timezone.ID
+	}
+}
+
+// NewHandler creates the i18n handler
+func NewHandler(options ...Option) *Handler {
+	h := &Handler{locale: "en", timezone: "UTC"}
+	h.timezones.build = timezoneEntries
+	h.currencies.build = currencyEntries
+	for _, option := range options {
+		option(h)
+	}
+	return h
+}
+
+// Register adds the i18n routes to group:
+//
+//	GET  /i18n/timezones?q=&locale=&limit=&offset=    search timezones
+//	GET  /i18n/currencies?q=&locale=&limit=&offset=   search currencies
+//	POST /i18n/format                                 format a batch of values
+//	GET  /i18n/convert?from=&to=&amount=&rounding=    convert an amount
+//	POST /i18n/phone/parse                            normalize a phone number
+//	POST /i18n/due-date                               deadline of an SLA in business hours
+//
+// q matches IDs, codes, names and country names in the locale, ignoring
+// case and accents and tolerating a typo; without q everything is listed.
+// The locale defaults to the request's detected locale.
+func (h *Handler) Register(group *gin.RouterGroup) {
+	if h.catalogCache != nil {
+		cached := h.catalogCache.Middleware(h.catalogKey)
+		group.GET("/i18n/timezones", cached, h.searchTimezones)
+		group.GET("/i18n/currencies", cached, h.searchCurrencies)
+	} else {
+		group.GET("/i18n/timezones", h.searchTimezones)
+		group.GET("/i18n/currencies", h.searchCurrencies)
+	}
+	group.POST("/i18n/format", h.format)
+	group.POST("/i18n/phone/parse", h.parsePhone)
+	group.POST("/i18n/due-date", h.dueDate)
+	if h.converter != nil {
+		handlers := []gin.HandlerFunc{h.convert}
+		if h.convertLimit != nil {
+			handlers = append([]gin.HandlerFunc{h.convertLimit}, handlers...)
+		}
+		group.GET("/i18n/convert", handlers...)
+	}
+}
+
+// Refresh rebuilds the search indexes and purges the catalog cache, e.g.
+// after currencies were registered or unregistered
+func (h *Handler) Refresh() {
+	h.timezones.cache.Clear()
+	h.currencies.cache.Clear()
+	if h.catalogCache != nil {
+		h.catalogCache.Purge()
+	}
+}
+
+// TimezoneResult is a timezone as offered in a picker
+type TimezoneResult struct {
+	ID          string `json:"id"`                     // IANA identifier
+	Name        string `json:"name"`                   // In the requested locale, e.g. "Eastern Time"
+	Offset      string `json:"offset"`                 // Current UTC offset, e.g. "-04:00"
+	Country     string `json:"country,omitempty"`      // ISO 3166-1 alpha-2 code
+	CountryName string `json:"country_name,omitempty"` // In the requested locale
+	Comment     string `json:"comment,omitempty"`      // Part of the country covered
+}
+
+// CurrencyResult is a currency as offered in a picker
+type CurrencyResult struct {
+	Code          string   `json:"code"`
+	Symbol        string   `json:"symbol"`
+	Name          string   `json:"name"`
+	DecimalPlaces int      `json:"decimal_places"`
+	Countries     []string `json:"countries"` // Countries using it by default
+}
+
+// Page is one page of search results
+type Page[T any] struct {
+	Results    []T  `json:"results"`
+	Total      int  `json:"total"`                 // Matches across all pages
+	NextOffset *int `json:"next_offset,omitempty"` // Absent on the last page
+}
+
+// query is the parsed query string of a search
+type query struct {
+	text   string
+	locale i18n.Locale
+	limit  int
+	offset int
+}
+
+// parseQuery reads q, locale, limit and offset, reporting every invalid one
+func (h *Handler) parseQuery(c *gin.Context) (query, bool) {
+	var errs validation.ValidationErrors
+	q := query{text: strings.TrimSpace(c.Query("q")), limit: defaultLimit}
+
+	q.locale = i18n.Locale(h.locale)
+	if tag := c.Query("locale"); tag != "" {
+		locale, err := i18n.ParseLocale(tag)
+		errs.Merge("locale", "", err)
+		q.locale = locale
+	} else if location, ok := geo.FromContext(c.Request.Context()); ok && location.Locale != "" {
+		if locale, err := i18n.ParseLocale(location.Locale); err == nil {
+			q.locale = locale
+		}
+	}
+	if value := c.Query("limit"); value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil || limit < 1 || limit > maxLimit {
+			errs.Add("limit", validation.CodeOutOfRange, "limit must be between 1 and "+strconv.Itoa(maxLimit), nil)
+		}
+		q.limit = limit
+	}
+	if value := c.Query("offset"); value != "" {
+		offset, err := strconv.Atoi(value)
+		if err != nil || offset < 0 {
+			errs.Add("offset", validation.CodeOutOfRange, "offset must be a non-negative integer", nil)
+		}
+		q.offset = offset
+	}
+
+	if err := errs.Err(); err != nil {
+		api.ValidationFailed(c, api.ErrValidationFailed.Error(), err)
+		return query{}, false
+	}
+	return q, true
+}
+
+// catalogKey keys cached searches by URL and, when the query sets no
+// locale, by the locale detected for the request, which parseQuery falls
+// back to
+func (h *Handler) catalogKey(c *gin.Context) string {
+	key := respcache.ByURL(c)
+	if c.Query("locale") == "" {
+		if location, ok := geo.FromContext(c.Request.Context()); ok && location.Locale != "" {
+			key += "#" + location.Locale
+		}
+	}
+	return key
+}
+
+// paginate cuts the page of q out of results
+func paginate[T any](results []T, q query) Page[T] {
+	page := Page[T]{Results: []T{}, Total: len(results)}
+	if q.offset < len(results) {
+		end := min(q.offset+q.limit, len(results))
+		page.Results = results[q.offset:end]
+		if end < len(results) {
+			page.NextOffset = &end
+		}
+	}
+	return page
+}
+
+func (h *Handler) searchTimezones(c *gin.Context) {
+	q, ok := h.parseQuery(c)
+	if !ok {
+		return
+	}
+	page := paginate(search(h.timezones.get(q.locale), q.text), q)
+	// Offsets change with daylight saving time, so they are not indexed
+	for i := range page.Results {
+		if tz, err := i18n.NewTimezoneFromID(page.Results[i].ID); err == nil {
+			page.Results[i].Offset = tz.FormatOffset()
+		}
+	}
+	api.Success(c, page, "timezones")
+}
+
+func (h *Handler) searchCurrencies(c *gin.Context) {
+	q, ok := h.parseQuery(c)
+	if !ok {
+		return
+	}
+	api.Success(c, paginate(search(h.currencies.get(q.locale), q.text), q), "currencies")
+}
+
+// timezoneEntries indexes the timezone catalog by ID, city, name, comment
+// and country name, in locale and in English
+func timezoneEntries(locale i18n.Locale) []entry[TimezoneResult] {
+	catalog := i18n.Timezones()
+	entries := make([]entry[TimezoneResult], 0, len(catalog))
+	for _, zone := range catalog {
+		tz, err := i18n.NewTimezoneFromID(zone.ID)
+		if err != nil {
+			// Missing from the tzdata of the host
+			continue
+		}
+		result := TimezoneResult{
+			ID:          zone.ID,
+			Name:        tz.DisplayName(locale),
+			Country:     zone.Country.String(),
+			CountryName: countryName(zone.Country, locale),
+			Comment:     zone.Comment,
+		}
+		entries = append(entries, entry[TimezoneResult]{
+			item: result,
+			sort: zone.ID,
+			keys: keys(locale, zone.ID, zoneCity(zone.ID), result.Name, tz.DisplayName("en"), zone.Comment,
+				result.CountryName, countryName(zone.Country, "en")),
+		})
+	}
+	return entries
+}
+
+// currencyEntries indexes the supported currencies by code, name, symbol and
+// the names of the countries using them, in locale and in English
+func currencyEntries(locale i18n.Locale) []entry[CurrencyResult] {
+	codes := i18n.GetSupportedCurrencies()
+	entries := make([]entry[CurrencyResult], 0, len(codes))
+	for _, code := range codes {
+		currency, err := i18n.NewCurrencyFromCode(code)
+		if err != nil {
+			continue
+		}
+		result := CurrencyResult{
+			Code:          currency.Code,
+			Symbol:        currency.Symbol,
+			Name:          currency.Name,
+			DecimalPlaces: currency.DecimalPlaces,
+			Countries:     []string{},
+		}
+		names := []string{currency.Code, currency.Name, currency.Symbol}
+		for _, country := range currency.Countries() {
+			result.Countries = append(result.Countries, country.String())
+			names = append(names, countryName(country, locale), countryName(country, "en"))
+		}
+		entries = append(entries, entry[CurrencyResult]{item: result, sort: currency.Code, keys: keys(locale, names...)})
+	}
+	return entries
+}
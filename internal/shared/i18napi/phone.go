@@ -0,0 +1,67 @@
+package i18napi
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"golang-arch/internal/shared/api"
+	i18n "golang-arch/internal/shared/domain/internationalization"
+	"golang-arch/internal/shared/domain/validation"
+	"golang-arch/internal/shared/geo"
+)
+
+// PhoneBody is a phone number as typed by a user. Region is the country of
+// national numbers and defaults to the request's detected country.
+type PhoneBody struct {
+	Phone  string `json:"phone" binding:"required"`
+	Region string `json:"region"`
+}
+
+// PhoneResult is a parsed phone number
+type PhoneResult struct {
+	E164           string   `json:"e164"` // e.g. "+4917612345678"
+	Country        string   `json:"country"`
+	CallingCode    string   `json:"calling_code"`
+	NationalNumber string   `json:"national_number"`
+	Type           string   `json:"type"`          // mobile, fixed_line, fixed_line_or_mobile or toll_free
+	International  string   `json:"international"` // e.g. "+49 17612345678"
+	Timezones      []string `json:"timezones"`     // Zones of the country
+}
+
+// parsePhone answers POST /i18n/phone/parse. Numbers travel in the body to
+// keep them out of URLs and access logs.
+func (h *Handler) parsePhone(c *gin.Context) {
+	var body PhoneBody
+	if !api.BindJSON(c, &body) {
+		return
+	}
+	var region i18n.Country
+	if body.Region != "" {
+		country, err := i18n.ParseCountry(body.Region)
+		if err != nil {
+			var errs validation.ValidationErrors
+			errs.Merge("region", "", err)
+			api.ValidationFailed(c, api.ErrValidationFailed.Error(), errs.Err())
+			return
+		}
+		region = country
+	} else if location, ok := geo.FromContext(c.Request.Context()); ok {
+		region, _ = i18n.ParseCountry(location.Country)
+	}
+
+	parsed, err := i18n.ParsePhone(body.Phone, region)
+	if err != nil {
+		var errs validation.ValidationErrors
+		errs.Add("phone", validation.CodeInvalidFormat, err.Error(), nil)
+		api.ValidationFailed(c, api.ErrValidationFailed.Error(), errs.Err())
+		return
+	}
+	api.Success(c, PhoneResult{
+		E164:           parsed.E164(),
+		Country:        parsed.Country.String(),
+		CallingCode:    parsed.Phone.CountryCode,
+		NationalNumber: parsed.Phone.Number,
+		Type:           string(parsed.Type),
+		International:  parsed.Phone.Format(),
+		Timezones:      parsed.Timezones,
+	}, "phone parsed")
+}
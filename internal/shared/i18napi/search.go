@@ -0,0 +1,213 @@
+package i18napi
+
+import (
+	"sort"
+	"strings"
+	"sync"
+
+	"golang.org/x/text/language"
+	"golang.org/x/text/language/display"
+
+	i18n "golang-arch/internal/shared/domain/internationalization"
+)
+
+// Match scores, best first. Fuzzy matches only apply to queries long enough
+// that a typo is more likely than a different word.
+const (
+	scoreExact      = 100
+	scorePrefix     = 90
+	scoreWordPrefix = 75
+	scoreContains   = 50
+	scoreTypo       = 30 // One edit away from a word prefix, queries of 4+ runes
+	scoreSubseq     = 10 // Letters in order, queries of 3+ runes
+)
+
+// entry is one searchable item with the keys it is found by
+type entry[T any] struct {
+	item T
+	sort string   // Orders equally scored matches
+	keys []string // SearchKeys of the ID, names and aliases
+}
+
+// match is an entry with its score for a query
+type match[T any] struct {
+	entry *entry[T]
+	score int
+}
+
+// search returns the items matching query, best first. An empty query
+// matches everything in catalog order.
+func search[T any](entries []entry[T], query string) []T {
+	query = i18n.SearchKey(query)
+	var matches []match[T]
+	for i := range entries {
+		score := 0
+		if query == "" {
+			score = 1
+		}
+		for _, key := range entries[i].keys {
+			score = max(score, scoreKey(key, query))
+		}
+		if score > 0 {
+			matches = append(matches, match[T]{entry: &entries[i], score: score})
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].score != matches[j].score {
+			return matches[i].score > matches[j].score
+		}
+		return matches[i].entry.sort < matches[j].entry.sort
+	})
+	items := make([]T, len(matches))
+	for i, m := range matches {
+		items[i] = m.entry.item
+	}
+	return items
+}
+
+// scoreKey rates how well query matches key; both are SearchKeys
+func scoreKey(key, query string) int {
+	switch {
+	case query == "":
+		return 0
+	case key == query:
+		return scoreExact
+	case strings.HasPrefix(key, query):
+		return scorePrefix
+	}
+	words := strings.FieldsFunc(key, isSeparator)
+	for _, word := range words {
+		if strings.HasPrefix(word, query) {
+			return scoreWordPrefix
+		}
+	}
+	if strings.Contains(key, query) {
+		return scoreContains
+	}
+	runes := []rune(query)
+	if len(runes) >= 4 {
+		for _, word := range words {
+			if prefixWithinOneEdit([]rune(word), runes) {
+				return scoreTypo
+			}
+		}
+	}
+	if len(runes) >= 3 && subsequence(key, runes) {
+		return scoreSubseq
+	}
+	return 0
+}
+
+// isSeparator splits keys into words: "america/new_york" has the words
+// "america", "new" and "york"
+func isSeparator(r rune) bool {
+	return r == ' ' || r == '/' || r == '_' || r == '-' || r == '(' || r == ')' || r == ','
+}
+
+// prefixWithinOneEdit reports whether some prefix of word is at most one
+// insertion, deletion or substitution away from query
+func prefixWithinOneEdit(word, query []rune) bool {
+	// Levenshtein distance between query and every prefix of word, keeping
+	// the previous row only; the minimum of the last row is the best prefix
+	prev := make([]int, len(word)+1)
+	curr := make([]int, len(word)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(query); i++ {
+		curr[0] = i
+		for j := 1; j <= len(word); j++ {
+			cost := 1
+			if query[i-1] == word[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	best := prev[0]
+	for _, d := range prev {
+		best = min(best, d)
+	}
+	return best <= 1
+}
+
+// subsequence reports whether the runes of query appear in key in order
+func subsequence(key string, query []rune) bool {
+	i := 0
+	for _, r := range key {
+		if i < len(query) && r == query[i] {
+			i++
+		}
+	}
+	return i == len(query)
+}
+
+// countryName returns the name of country in locale, or "" when unknown
+func countryName(country i18n.Country, locale i18n.Locale) string {
+	if country == "" {
+		return ""
+	}
+	region, err := language.ParseRegion(country.String())
+	if err != nil {
+		return ""
+	}
+	return display.Regions(language.Make(locale.String())).Name(region)
+}
+
+// keys returns the distinct SearchKeys of names, adding the locale's
+// transliteration of each so "Zürich" is found by "zuerich" in German
+func keys(locale i18n.Locale, names ...string) []string {
+	seen := make(map[string]bool, len(names)*2)
+	var keys []string
+	add := func(name string) {
+		if key := i18n.SearchKey(name); key != "" && !seen[key] {
+			seen[key] = true
+			keys = append(keys, key)
+		}
+	}
+	for _, name := range names {
+		add(name)
+		add(i18n.Transliterate(name, locale))
+	}
+	return keys
+}
+
+// zoneCity returns the city of an IANA ID, e.g. "Sao Paulo" for
+// America/Sao_Paulo
+func zoneCity(id string) string {
+	city := id[strings.LastIndexByte(id, '/')+1:]
+	return strings.ReplaceAll(city, "_", " ")
+}
+
+// displayTags are the languages country names are available in
+var (
+	displayTags    = display.Supported.Tags()
+	displayMatcher = language.NewMatcher(displayTags)
+)
+
+// displayLocale returns the supported display language closest to locale,
+// English when none is close. Indexes are built per display language, so
+// arbitrary client locales cannot grow the cache without bound.
+func displayLocale(locale i18n.Locale) i18n.Locale {
+	_, index, confidence := displayMatcher.Match(language.Make(locale.String()))
+	if confidence == language.No {
+		return "en"
+	}
+	return i18n.Locale(displayTags[index].String())
+}
+
+// indexes builds the entries of a catalog once per display language
+type indexes[T any] struct {
+	build func(i18n.Locale) []entry[T]
+	cache sync.Map // i18n.Locale -> []entry[T]
+}
+
+func (x *indexes[T]) get(locale i18n.Locale) []entry[T] {
+	locale = displayLocale(locale)
+	if cached, ok := x.cache.Load(locale); ok {
+		return cached.([]entry[T])
+	}
+	entries, _ := x.cache.LoadOrStore(locale, x.build(locale))
+	return entries.([]entry[T])
+}
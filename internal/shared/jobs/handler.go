@@ -0,0 +1,181 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"golang-arch/internal/shared/api"
+	"golang-arch/internal/shared/domain/validation"
+	"golang-arch/internal/shared/templates"
+)
+
+// DefaultMaxWait bounds a long-poll, and the silence between two events of
+// a stream, when the handler is given none
+const DefaultMaxWait = 30 * time.Second
+
+// Status is a job as shown to the user who started it
+type Status struct {
+	ID        string          `json:"id"`
+	Kind      string          `json:"kind"`
+	State     State           `json:"state"`
+	Version   int64           `json:"version"` // Pass back as ?version= to await the next change
+	Done      int64           `json:"done"`
+	Total     int64           `json:"total,omitempty"`
+	Percent   *int            `json:"percent,omitempty"` // Only with a known total
+	Message   string          `json:"message"`           // In the reader's locale
+	Result    json.RawMessage `json:"result,omitempty"`
+	Error     string          `json:"error,omitempty"`
+	CreatedAt time.Time       `json:"created_at"`
+	UpdatedAt time.Time       `json:"updated_at"`
+}
+
+// Handler lets clients await jobs instead of polling them
+type Handler struct {
+	store   *Store
+	views   *templates.Engine
+	maxWait time.Duration
+}
+
+// NewHandler creates the job status handler; progress messages are
+// translated with the catalog of views
+func NewHandler(store *Store, views *templates.Engine, maxWait time.Duration) *Handler {
+	if maxWait <= 0 {
+		maxWait = DefaultMaxWait
+	}
+	return &Handler{store: store, views: views, maxWait: maxWait}
+}
+
+// Register adds the job routes to group:
+//
+//	GET /jobs/:id?version=&wait=  the job, once it changed past version
+//	GET /jobs/:id/events          server-sent "status" events until it is done
+//
+// Job IDs are unguessable and are what grants access to a job.
+func (h *Handler) Register(group *gin.RouterGroup) {
+	group.GET("/jobs/:id", h.get)
+	group.GET("/jobs/:id/events", h.events)
+}
+
+// get answers at once without version. With version it long-polls for up
+// to wait, by default the handler's maximum, and answers with the job as it
+// is when nothing changed.
+func (h *Handler) get(c *gin.Context) {
+	var errs validation.ValidationErrors
+	var seen int64
+	var wait time.Duration
+	if value := c.Query("version"); value != "" {
+		version, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || version < 0 {
+			errs.Add("version", validation.CodeInvalidFormat, "version must be a non-negative integer", nil)
+		}
+		seen, wait = version, h.maxWait
+	}
+	if value := c.Query("wait"); value != "" {
+		parsed, err := time.ParseDuration(value)
+		if err != nil || parsed < 0 || parsed > h.maxWait {
+			errs.Add("wait", validation.CodeOutOfRange, "wait must be a duration up to "+h.maxWait.String(),
+				map[string]any{"max": h.maxWait.String()})
+		}
+		wait = parsed
+	}
+	if err := errs.Err(); err != nil {
+		api.ValidationFailed(c, api.ErrValidationFailed.Error(), err)
+		return
+	}
+
+	var record *Record
+	var err error
+	if wait > 0 {
+		ctx, cancel := context.WithTimeout(c.Request.Context(), wait)
+		defer cancel()
+		record, err = h.store.Wait(ctx, c.Param("id"), seen)
+	} else {
+		record, err = h.store.Get(c.Request.Context(), c.Param("id"))
+	}
+	if err != nil {
+		api.RespondError(c, err)
+		return
+	}
+	c.Header("Cache-Control", "no-store")
+	api.Success(c, h.status(c, record), "job")
+}
+
+// events streams every change of the job as a "status" event and ends with
+// the final one. Quiet periods are filled with comments so proxies keep the
+// connection open.
+func (h *Handler) events(c *gin.Context) {
+	id := c.Param("id")
+	var seen int64
+	for {
+		ctx, cancel := context.WithTimeout(c.Request.Context(), h.maxWait)
+		record, err := h.store.Wait(ctx, id, seen)
+		cancel()
+		switch {
+		case err != nil && seen == 0:
+			api.RespondError(c, err)
+			return
+		case err != nil:
+			c.SSEvent("error", gin.H{"message": err.Error()})
+			c.Writer.Flush()
+			return
+		case c.Request.Context().Err() != nil:
+			return
+		}
+
+		if seen == 0 {
+			c.Header("Cache-Control", "no-store")
+			c.Header("X-Accel-Buffering", "no")
+		}
+		if record.Version > seen {
+			c.SSEvent("status", h.status(c, record))
+			seen = record.Version
+		} else {
+			fmt.Fprint(c.Writer, ": keep-alive\n\n")
+		}
+		c.Writer.Flush()
+		if record.State.Done() {
+			return
+		}
+	}
+}
+
+// status renders a record for the request's reader. Without a progress
+// message the state is described, with the counts when they are known.
+func (h *Handler) status(c *gin.Context, record *Record) Status {
+	status := Status{
+		ID:        record.ID,
+		Kind:      record.Kind,
+		State:     record.State,
+		Version:   record.Version,
+		Done:      record.Progress.Done,
+		Total:     record.Progress.Total,
+		Result:    record.Result,
+		Error:     record.Error,
+		CreatedAt: record.CreatedAt,
+		UpdatedAt: record.UpdatedAt,
+	}
+	if total := record.Progress.Total; total > 0 {
+		percent := int(min(record.Progress.Done*100/total, 100))
+		status.Percent = &percent
+	}
+
+	formatter := h.views.Formatter(h.views.Preferences(c))
+	key, args := "job."+string(record.State), []any{}
+	switch {
+	case record.Progress.Message != "" && !record.State.Done():
+		key = record.Progress.Message
+		for name, value := range record.Progress.Args {
+			args = append(args, name, value)
+		}
+	case record.State == StateRunning && record.Progress.Total > 0:
+		key = "job.progress"
+		args = append(args, "done", formatter.Number(record.Progress.Done), "total", formatter.Number(record.Progress.Total))
+	}
+	status.Message = formatter.Translate(key, args...)
+	return status
+}
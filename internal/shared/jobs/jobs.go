@@ -0,0 +1,231 @@
+// Package jobs tracks the state and result of asynchronous jobs, such as
+// exports, so clients can await them. Records live in Redis for a TTL after
+// their last update; the job that owns a record is its only writer.
+//
+// A record's Version grows with every update. Wait blocks until a record
+// moves past the version a client has seen, which lets GET /jobs/:id
+// long-poll and /jobs/:id/events stream instead of clients polling.
+package jobs
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"golang-arch/internal/shared/domain/domainerror"
+	"golang-arch/pkg/clock"
+)
+
+// keyPrefix prefixes the records as "jobs:<id>"
+const keyPrefix = "jobs:"
+
+// Defaults of a Store
+const (
+	DefaultTTL          = 24 * time.Hour
+	DefaultPollInterval = 250 * time.Millisecond
+)
+
+// State is the lifecycle stage of a job
+type State string
+
+// Job states
+const (
+	StatePending   State = "pending"
+	StateRunning   State = "running"
+	StateSucceeded State = "succeeded"
+	StateFailed    State = "failed"
+)
+
+// Done reports whether the state is final
+func (s State) Done() bool {
+	return s == StateSucceeded || s == StateFailed
+}
+
+// Progress is how far a running job got. Message is a template catalog key
+// with {name} placeholders filled from Args, e.g. "job.export.rows".
+type Progress struct {
+	Done    int64             `json:"done"`
+	Total   int64             `json:"total,omitempty"` // 0 when unknown
+	Message string            `json:"message,omitempty"`
+	Args    map[string]string `json:"args,omitempty"`
+}
+
+// Record is the stored state of a job
+type Record struct {
+	ID        string          `json:"id"`
+	Kind      string          `json:"kind"` // e.g. "export"
+	State     State           `json:"state"`
+	Version   int64           `json:"version"`
+	Progress  Progress        `json:"progress"`
+	Result    json.RawMessage `json:"result,omitempty"` // Set on success
+	Error     string          `json:"error,omitempty"`  // Set on failure, shown to the user
+	CreatedAt time.Time       `json:"created_at"`
+	UpdatedAt time.Time       `json:"updated_at"`
+}
+
+// Store keeps job records in Redis
+type Store struct {
+	client *redis.Client
+	clock  clock.Clock
+	ttl    time.Duration
+	poll   time.Duration
+}
+
+// Option configures a Store
+type Option func(*Store)
+
+// WithClock sets the clock that timestamps records
+func WithClock(c clock.Clock) Option {
+	return func(s *Store) {
+		s.clock = c
+	}
+}
+
+// WithTTL sets how long records are kept after their last update
+func WithTTL(ttl time.Duration) Option {
+	return func(s *Store) {
+		if ttl > 0 {
+			s.ttl = ttl
+		}
+	}
+}
+
+// WithPollInterval sets how often Wait rereads a record
+func WithPollInterval(interval time.Duration) Option {
+	return func(s *Store) {
+		if interval > 0 {
+			s.poll = interval
+		}
+	}
+}
+
+// NewStore creates a job store on client
+func NewStore(client *redis.Client, options ...Option) *Store {
+	s := &Store{client: client, clock: clock.New(), ttl: DefaultTTL, poll: DefaultPollInterval}
+	for _, option := range options {
+		option(s)
+	}
+	return s
+}
+
+// Create stores a pending job of kind under a new unguessable ID, which
+// is all a client needs to follow it
+func (s *Store) Create(ctx context.Context, kind string) (*Record, error) {
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return nil, fmt.Errorf("failed to generate job id: %w", err)
+	}
+	now := s.clock.Now()
+	record := &Record{ID: hex.EncodeToString(id), Kind: kind, State: StatePending, Version: 1, CreatedAt: now, UpdatedAt: now}
+	if err := s.save(ctx, record); err != nil {
+		return nil, err
+	}
+	return record, nil
+}
+
+// Get returns the record of a job
+func (s *Store) Get(ctx context.Context, id string) (*Record, error) {
+	data, err := s.client.Get(ctx, keyPrefix+id).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, domainerror.NotFoundf("job %s not found", id)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read job %s: %w", id, err)
+	}
+	var record Record
+	if err := json.Unmarshal(data, &record); err != nil {
+		return nil, fmt.Errorf("failed to decode job %s: %w", id, err)
+	}
+	return &record, nil
+}
+
+// Start marks a job as running
+func (s *Store) Start(ctx context.Context, id string) error {
+	return s.update(ctx, id, func(r *Record) {
+		r.State = StateRunning
+	})
+}
+
+// SetProgress records the progress of a running job
+func (s *Store) SetProgress(ctx context.Context, id string, progress Progress) error {
+	return s.update(ctx, id, func(r *Record) {
+		r.State, r.Progress = StateRunning, progress
+	})
+}
+
+// Complete marks a job as succeeded with result, encoded as JSON
+func (s *Store) Complete(ctx context.Context, id string, result any) error {
+	data, err := json.Marshal(result)
+	if err != nil {
+		return fmt.Errorf("failed to encode result of job %s: %w", id, err)
+	}
+	return s.update(ctx, id, func(r *Record) {
+		r.State, r.Result = StateSucceeded, data
+	})
+}
+
+// Fail marks a job as failed; cause is shown to the user
+func (s *Store) Fail(ctx context.Context, id string, cause error) error {
+	return s.update(ctx, id, func(r *Record) {
+		r.State, r.Error = StateFailed, cause.Error()
+	})
+}
+
+// Wait returns the record once its version is past seen or the job is
+// done. When ctx ends first the current record is returned, so a
+// long-poll answers with what it has.
+func (s *Store) Wait(ctx context.Context, id string, seen int64) (*Record, error) {
+	record, err := s.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	for record.Version <= seen && !record.State.Done() {
+		select {
+		case <-ctx.Done():
+			return record, nil
+		case <-time.After(s.poll):
+		}
+		next, err := s.Get(ctx, id)
+		if err != nil {
+			if ctx.Err() != nil {
+				return record, nil
+			}
+			return nil, err
+		}
+		record = next
+	}
+	return record, nil
+}
+
+// update applies change to a job's record and stores it as a new version.
+// Final records are not changed any more.
+func (s *Store) update(ctx context.Context, id string, change func(*Record)) error {
+	record, err := s.Get(ctx, id)
+	if err != nil {
+		return err
+	}
+	if record.State.Done() {
+		return domainerror.Conflictf("job %s is already %s", id, record.State)
+	}
+	change(record)
+	record.Version++
+	record.UpdatedAt = s.clock.Now()
+	return s.save(ctx, record)
+}
+
+func (s *Store) save(ctx context.Context, record *Record) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to encode job %s: %w", record.ID, err)
+	}
+	if err := s.client.Set(ctx, keyPrefix+record.ID, data, s.ttl).Err(); err != nil {
+		return fmt.Errorf("failed to store job %s: %w", record.ID, err)
+	}
+	return nil
+}
@@ -0,0 +1,42 @@
+package loadshed
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"golang-arch/internal/shared/api"
+)
+
+// Middleware admits requests through the limiter and answers shed ones 503
+// with code SERVICE_UNAVAILABLE (api.ErrCodeUnavailable) and a Retry-After
+// of retryAfter. Paths starting with one of priority, e.g. health checks,
+// bypass the limit so probes keep answering while the instance is
+// overloaded.
+func (l *Limiter) Middleware(retryAfter time.Duration, priority ...string) gin.HandlerFunc {
+	seconds := strconv.Itoa(max(int(retryAfter.Round(time.Second)/time.Second), 1))
+	return func(c *gin.Context) {
+		path := c.Request.URL.Path
+		for _, prefix := range priority {
+			if strings.HasPrefix(path, prefix) {
+				c.Next()
+				return
+			}
+		}
+
+		if err := l.Acquire(c.Request.Context()); err != nil {
+			if errors.Is(err, ErrOverloaded) {
+				c.Header("Retry-After", seconds)
+			}
+			api.RespondError(c, api.NewUnavailableError(err.Error()))
+			c.Abort()
+			return
+		}
+		start := l.clock.Now()
+		defer func() { l.Release(l.clock.Since(start)) }()
+		c.Next()
+	}
+}
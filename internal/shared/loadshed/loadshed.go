@@ -0,0 +1,237 @@
+// Package loadshed caps the number of requests handled at once, so an
+// overloaded instance answers 503 early instead of letting every request
+// slow down until all of them time out.
+//
+// Requests over the limit wait in a short FIFO queue; those that find the
+// queue full, or wait longer than the queue timeout, are shed. With a target
+// latency the limit adapts: it shrinks while requests run slower than the
+// target and grows back by one slot per limit's worth of faster requests.
+//
+// Shedding is distinct from rate limiting: it protects the instance from
+// its total load rather than other callers from one caller.
+package loadshed
+
+import (
+	"container/list"
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"golang-arch/pkg/clock"
+	"golang-arch/pkg/metrics"
+)
+
+// ErrOverloaded is returned for shed requests
+var ErrOverloaded = errors.New("server overloaded")
+
+// Reasons a request is shed, the reason label of the shed counter
+const (
+	ReasonQueueFull    = "queue_full"
+	ReasonQueueTimeout = "queue_timeout"
+)
+
+// decrease is the factor the limit shrinks by on each slow request
+const decrease = 0.9
+
+// Options are the limits of a Limiter
+type Options struct {
+	MaxInFlight   int           // Requests handled at once; the limit never grows past it
+	MinInFlight   int           // Floor of the adaptive limit; defaults to 1
+	MaxQueue      int           // Requests waiting for a slot; 0 sheds as soon as the limit is reached
+	QueueTimeout  time.Duration // Longest wait for a slot before the request is shed
+	TargetLatency time.Duration // Latency the limit adapts to; 0 keeps it at MaxInFlight
+}
+
+// Validate checks the limits are consistent
+func (o Options) Validate() error {
+	if o.MaxInFlight <= 0 {
+		return fmt.Errorf("max in-flight requests must be positive, got %d", o.MaxInFlight)
+	}
+	if o.MinInFlight < 0 || o.MinInFlight > o.MaxInFlight {
+		return fmt.Errorf("min in-flight requests must be between 0 and %d, got %d", o.MaxInFlight, o.MinInFlight)
+	}
+	if o.MaxQueue < 0 || o.QueueTimeout < 0 || o.TargetLatency < 0 {
+		return fmt.Errorf("queue size, queue timeout and target latency must not be negative")
+	}
+	if o.MaxQueue > 0 && o.QueueTimeout == 0 {
+		return fmt.Errorf("queue timeout is required with a queue")
+	}
+	return nil
+}
+
+// Limiter admits requests up to its limit
+type Limiter struct {
+	opts   Options
+	clock  clock.Clock
+	logger *zap.Logger
+
+	shed       *metrics.Counter
+	inFlight   *metrics.Gauge
+	queued     *metrics.Gauge
+	limitGauge *metrics.Gauge
+
+	mu      sync.Mutex
+	limit   float64
+	running int
+	waiters *list.List // of chan struct{}, closed when granted a slot
+}
+
+// Option customizes a Limiter
+type Option func(*Limiter)
+
+// WithClock sets the clock queue timeouts are measured with
+func WithClock(c clock.Clock) Option {
+	return func(l *Limiter) {
+		l.clock = c
+	}
+}
+
+// WithLogger sets the logger
+func WithLogger(logger *zap.Logger) Option {
+	return func(l *Limiter) {
+		l.logger = logger
+	}
+}
+
+// WithMetrics records shed requests by reason and the in-flight requests,
+// queued requests and current limit
+func WithMetrics(shed *metrics.Counter, inFlight, queued, limit *metrics.Gauge) Option {
+	return func(l *Limiter) {
+		l.shed = shed
+		l.inFlight = inFlight
+		l.queued = queued
+		l.limitGauge = limit
+	}
+}
+
+// NewLimiter creates a limiter starting at MaxInFlight
+func NewLimiter(opts Options, options ...Option) (*Limiter, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+	if opts.MinInFlight == 0 {
+		opts.MinInFlight = 1
+	}
+	l := &Limiter{
+		opts:    opts,
+		clock:   clock.New(),
+		logger:  zap.NewNop(),
+		limit:   float64(opts.MaxInFlight),
+		waiters: list.New(),
+	}
+	for _, option := range options {
+		option(l)
+	}
+	l.record()
+	return l, nil
+}
+
+// Limit returns the current limit
+func (l *Limiter) Limit() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return int(l.limit)
+}
+
+// InFlight returns the number of admitted requests not released yet
+func (l *Limiter) InFlight() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.running
+}
+
+// Acquire admits a request, waiting in the queue when the limit is reached.
+// It returns an error wrapping ErrOverloaded when the request is shed, or the
+// context's error when it ends while queued. Every admitted request must be
+// released.
+func (l *Limiter) Acquire(ctx context.Context) error {
+	l.mu.Lock()
+	if l.running < int(l.limit) && l.waiters.Len() == 0 {
+		l.running++
+		l.record()
+		l.mu.Unlock()
+		return nil
+	}
+	if l.waiters.Len() >= l.opts.MaxQueue {
+		l.mu.Unlock()
+		return l.reject(ReasonQueueFull)
+	}
+	granted := make(chan struct{})
+	waiter := l.waiters.PushBack(granted)
+	l.record()
+	l.mu.Unlock()
+
+	var err error
+	select {
+	case <-granted:
+		return nil
+	case <-l.clock.After(l.opts.QueueTimeout):
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+
+	l.mu.Lock()
+	select {
+	case <-granted:
+		// Granted while timing out: the slot is ours and must be used or
+		// released, so keep it
+		l.mu.Unlock()
+		return nil
+	default:
+	}
+	l.waiters.Remove(waiter)
+	l.record()
+	l.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	return l.reject(ReasonQueueTimeout)
+}
+
+// Release ends an admitted request that took latency, adapting the limit
+// and handing the slot to the oldest queued request
+func (l *Limiter) Release(latency time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.running--
+	if target := l.opts.TargetLatency; target > 0 {
+		if latency > target {
+			l.limit = math.Max(l.limit*decrease, float64(l.opts.MinInFlight))
+		} else {
+			l.limit = math.Min(l.limit+1/l.limit, float64(l.opts.MaxInFlight))
+		}
+	}
+	for l.waiters.Len() > 0 && l.running < int(l.limit) {
+		granted := l.waiters.Remove(l.waiters.Front()).(chan struct{})
+		l.running++
+		close(granted)
+	}
+	l.record()
+}
+
+// reject counts a shed request
+func (l *Limiter) reject(reason string) error {
+	if l.shed != nil {
+		l.shed.Inc(metrics.Labels{"reason": reason})
+	}
+	l.logger.Debug("Request shed", zap.String("reason", reason))
+	return fmt.Errorf("%w: %s", ErrOverloaded, reason)
+}
+
+// record updates the gauges; the caller holds mu, except in NewLimiter
+func (l *Limiter) record() {
+	if l.inFlight != nil {
+		l.inFlight.Set(float64(l.running), nil)
+	}
+	if l.queued != nil {
+		l.queued.Set(float64(l.waiters.Len()), nil)
+	}
+	if l.limitGauge != nil {
+		l.limitGauge.Set(math.Floor(l.limit), nil)
+	}
+}
@@ -0,0 +1,98 @@
+package metering
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"golang-arch/internal/shared/api"
+	"golang-arch/internal/shared/domain/validation"
+)
+
+// maxHistoryPeriods bounds the periods of a history request
+const maxHistoryPeriods = 36
+
+// Handler exposes a consumer's usage over HTTP
+type Handler struct {
+	meter  *Meter
+	header string
+}
+
+// NewHandler creates the usage HTTP handler; header carries the API key
+func NewHandler(meter *Meter, header string) *Handler {
+	if header == "" {
+		header = DefaultHeader
+	}
+	return &Handler{meter: meter, header: header}
+}
+
+// Register adds the usage routes of the calling consumer to group:
+//
+//	GET /usage                     the current period, read live
+//	GET /usage/history?periods=12  flushed usage of past periods
+func (h *Handler) Register(group *gin.RouterGroup) {
+	group.GET("/usage", h.current)
+	group.GET("/usage/history", h.history)
+}
+
+func (h *Handler) current(c *gin.Context) {
+	consumer, ok := h.consumer(c)
+	if !ok {
+		return
+	}
+	usage, err := h.meter.Usage(c.Request.Context(), consumer)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	api.Success(c, usage, "usage")
+}
+
+func (h *Handler) history(c *gin.Context) {
+	consumer, ok := h.consumer(c)
+	if !ok {
+		return
+	}
+	periods := 12
+	if value := c.Query("periods"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed <= 0 || parsed > maxHistoryPeriods {
+			var errs validation.ValidationErrors
+			errs.Add("periods", validation.CodeOutOfRange, "periods must be between 1 and 36",
+				map[string]any{"min": 1, "max": maxHistoryPeriods})
+			api.ValidationFailed(c, api.ErrValidationFailed.Error(), errs.Err())
+			return
+		}
+		periods = parsed
+	}
+
+	history, err := h.meter.History(c.Request.Context(), consumer, periods)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	if history == nil {
+		history = []Usage{}
+	}
+	api.Success(c, history, "usage history")
+}
+
+// consumer rejects requests that identify no consumer
+func (h *Handler) consumer(c *gin.Context) (string, bool) {
+	consumer := Consumer(c, h.header)
+	if consumer == "" {
+		api.RespondError(c, api.NewUnauthorizedError("an API key is required"))
+		return "", false
+	}
+	return consumer, true
+}
+
+// respondError answers 401 for API keys without a plan
+func respondError(c *gin.Context, err error) {
+	if errors.Is(err, ErrUnknownConsumer) {
+		api.RespondError(c, api.NewUnauthorizedError("unknown API key"))
+		return
+	}
+	api.RespondError(c, err)
+}
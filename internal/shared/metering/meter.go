@@ -0,0 +1,308 @@
+// Package metering counts API requests per consumer and period and enforces
+// the request quotas of the consumer's plan. Counters live in Redis so every
+// instance sees the same usage; the worker's metering_flush job copies them
+// to Postgres, which keeps the history after the Redis keys expire.
+//
+// Quotas are distinct from burst rate limiting: an exhausted quota answers
+// 429 with code QUOTA_EXCEEDED and a Retry-After until the period resets.
+package metering
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+
+	"golang-arch/pkg/clock"
+)
+
+// Redis keys, all under the "metering:" prefix
+const (
+	usageKeyPrefix = "metering:usage:" // Hash {endpoint: count, "*": total} per period and consumer
+	dirtyKey       = "metering:dirty"  // Set of usage keys changed since the last flush
+)
+
+// flushGrace keeps counters after their period ends so a late flush still
+// finds them
+const flushGrace = 24 * time.Hour
+
+// flushBatch bounds the usage keys flushed per round trip
+const flushBatch = 100
+
+// recordScript counts a request unless the endpoint or plan quota is used
+// up, and returns {allowed, endpoint count, total count}
+var recordScript = redis.NewScript(`
+local endpoint = tonumber(redis.call('HGET', KEYS[1], ARGV[1]) or '0')
+local total = tonumber(redis.call('HGET', KEYS[1], '*') or '0')
+local endpointLimit, totalLimit = tonumber(ARGV[2]), tonumber(ARGV[3])
+if (endpointLimit > 0 and endpoint >= endpointLimit) or (totalLimit > 0 and total >= totalLimit) then
+	return {0, endpoint, total}
+end
+endpoint = redis.call('HINCRBY', KEYS[1], ARGV[1], 1)
+total = redis.call('HINCRBY', KEYS[1], '*', 1)
+redis.call('PEXPIRE', KEYS[1], ARGV[4])
+redis.call('SADD', KEYS[2], KEYS[1])
+return {1, endpoint, total}
+`)
+
+// Decision is the outcome of recording a request
+type Decision struct {
+	Allowed   bool
+	Limit     int64     // The tightest applicable quota; 0 is unlimited
+	Remaining int64     // Requests left under Limit
+	Reset     time.Time // End of the period
+	Exceeded  string    // "endpoint" or "plan" when not allowed
+}
+
+// Usage is a consumer's request count in one period
+type Usage struct {
+	Consumer  string          `json:"consumer"`
+	Plan      string          `json:"plan,omitempty"`
+	Period    string          `json:"period"`
+	Reset     *time.Time      `json:"reset,omitempty"` // Only for the current period
+	Requests  int64           `json:"requests"`
+	Limit     int64           `json:"limit,omitempty"`
+	Endpoints []EndpointUsage `json:"endpoints"`
+}
+
+// EndpointUsage is the request count of one route
+type EndpointUsage struct {
+	Route    string `json:"route"`
+	Requests int64  `json:"requests"`
+	Limit    int64  `json:"limit,omitempty"`
+}
+
+// Meter records requests against plan quotas
+type Meter struct {
+	client *redis.Client
+	plans  PlanResolver
+	store  Store
+	clock  clock.Clock
+	logger *zap.Logger
+}
+
+// Option configures a Meter
+type Option func(*Meter)
+
+// WithClock sets the clock that decides the current period
+func WithClock(c clock.Clock) Option {
+	return func(m *Meter) {
+		m.clock = c
+	}
+}
+
+// WithLogger sets the logger
+func WithLogger(logger *zap.Logger) Option {
+	return func(m *Meter) {
+		m.logger = logger
+	}
+}
+
+// NewMeter creates a meter counting in client and flushing to store
+func NewMeter(client *redis.Client, plans PlanResolver, store Store, options ...Option) *Meter {
+	m := &Meter{
+		client: client,
+		plans:  plans,
+		store:  store,
+		clock:  clock.New(),
+		logger: zap.NewNop(),
+	}
+	for _, option := range options {
+		option(m)
+	}
+	return m
+}
+
+// Record counts a request of consumer to route ("METHOD /path") unless it
+// would exceed the plan's endpoint or total quota. Rejected requests are not
+// counted.
+func (m *Meter) Record(ctx context.Context, consumer, route string) (Decision, error) {
+	plan, err := m.plans.Plan(ctx, consumer)
+	if err != nil {
+		return Decision{}, fmt.Errorf("failed to resolve plan: %w", err)
+	}
+	now := m.clock.Now()
+	period, _, end := plan.Period.Bounds(now)
+	endpointLimit := plan.EndpointLimit(route)
+
+	result, err := recordScript.Run(ctx, m.client,
+		[]string{usageKey(period, consumer), dirtyKey},
+		route, endpointLimit, plan.Requests, (end.Sub(now) + flushGrace).Milliseconds(),
+	).Int64Slice()
+	if err != nil {
+		return Decision{}, fmt.Errorf("failed to record usage: %w", err)
+	}
+
+	decision := Decision{Allowed: result[0] == 1, Reset: end}
+	endpointCount, total := result[1], result[2]
+	if endpointLimit > 0 {
+		decision.Limit, decision.Remaining = endpointLimit, endpointLimit-endpointCount
+	}
+	if plan.Requests > 0 && (decision.Limit == 0 || plan.Requests-total < decision.Remaining) {
+		decision.Limit, decision.Remaining = plan.Requests, plan.Requests-total
+	}
+	decision.Remaining = max(decision.Remaining, 0)
+	if !decision.Allowed {
+		decision.Exceeded = "plan"
+		if endpointLimit > 0 && endpointCount >= endpointLimit {
+			decision.Exceeded = "endpoint"
+		}
+	}
+	return decision, nil
+}
+
+// Usage returns the consumer's counts in the current period, with every
+// limited endpoint listed even when unused
+func (m *Meter) Usage(ctx context.Context, consumer string) (Usage, error) {
+	plan, err := m.plans.Plan(ctx, consumer)
+	if err != nil {
+		return Usage{}, fmt.Errorf("failed to resolve plan: %w", err)
+	}
+	period, _, end := plan.Period.Bounds(m.clock.Now())
+
+	counts, err := m.client.HGetAll(ctx, usageKey(period, consumer)).Result()
+	if err != nil {
+		return Usage{}, fmt.Errorf("failed to read usage: %w", err)
+	}
+	rows := make([]Row, 0, len(counts)+len(plan.Endpoints))
+	for endpoint, value := range counts {
+		requests, _ := strconv.ParseInt(value, 10, 64)
+		rows = append(rows, Row{Consumer: consumer, Period: period, Endpoint: endpoint, Requests: requests})
+	}
+	for route := range plan.Endpoints {
+		if _, ok := counts[route]; !ok {
+			rows = append(rows, Row{Consumer: consumer, Period: period, Endpoint: route})
+		}
+	}
+
+	usage := buildUsage(consumer, period, rows, plan)
+	usage.Plan, usage.Reset = plan.Name, &end
+	return usage, nil
+}
+
+// History returns the flushed usage of the consumer's latest periods,
+// newest first, with the limits of the current plan. The current period
+// may lag behind Usage until the next flush.
+func (m *Meter) History(ctx context.Context, consumer string, periods int) ([]Usage, error) {
+	rows, err := m.store.History(ctx, consumer, periods)
+	if err != nil {
+		return nil, err
+	}
+	plan, err := m.plans.Plan(ctx, consumer)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve plan: %w", err)
+	}
+
+	var history []Usage
+	for start := 0; start < len(rows); {
+		end := start
+		for end < len(rows) && rows[end].Period == rows[start].Period {
+			end++
+		}
+		history = append(history, buildUsage(consumer, rows[start].Period, rows[start:end], plan))
+		start = end
+	}
+	return history, nil
+}
+
+// Flush copies the counters changed since the last flush to the store. It
+// is the metering_flush worker job. Keys that fail to save are marked
+// changed again for the next run.
+func (m *Meter) Flush(ctx context.Context) error {
+	flushed := 0
+	for {
+		keys, err := m.client.SPopN(ctx, dirtyKey, flushBatch).Result()
+		if err != nil {
+			return fmt.Errorf("failed to read changed usage: %w", err)
+		}
+		if len(keys) == 0 {
+			break
+		}
+		if err := m.flushKeys(ctx, keys); err != nil {
+			if restoreErr := m.client.SAdd(ctx, dirtyKey, toAny(keys)...).Err(); restoreErr != nil {
+				m.logger.Error("Failed to requeue usage after a failed flush", zap.Error(restoreErr), zap.Int("keys", len(keys)))
+			}
+			return err
+		}
+		flushed += len(keys)
+	}
+	if flushed > 0 {
+		m.logger.Debug("Flushed usage counters", zap.Int("keys", flushed))
+	}
+	return nil
+}
+
+func (m *Meter) flushKeys(ctx context.Context, keys []string) error {
+	pipe := m.client.Pipeline()
+	commands := make([]*redis.MapStringStringCmd, len(keys))
+	for i, key := range keys {
+		commands[i] = pipe.HGetAll(ctx, key)
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to read usage: %w", err)
+	}
+
+	var rows []Row
+	for i, key := range keys {
+		period, consumer, ok := parseUsageKey(key)
+		if !ok {
+			m.logger.Warn("Skipping malformed usage key", zap.String("key", key))
+			continue
+		}
+		for endpoint, value := range commands[i].Val() {
+			requests, _ := strconv.ParseInt(value, 10, 64)
+			rows = append(rows, Row{Consumer: consumer, Period: period, Endpoint: endpoint, Requests: requests})
+		}
+	}
+	if len(rows) == 0 {
+		return nil
+	}
+	return m.store.Save(ctx, rows)
+}
+
+// buildUsage groups one period's rows, taking limits from plan
+func buildUsage(consumer, period string, rows []Row, plan Plan) Usage {
+	usage := Usage{Consumer: consumer, Period: period, Limit: plan.Requests, Endpoints: []EndpointUsage{}}
+	for _, row := range rows {
+		if row.Endpoint == TotalEndpoint {
+			usage.Requests = row.Requests
+			continue
+		}
+		usage.Endpoints = append(usage.Endpoints, EndpointUsage{
+			Route:    row.Endpoint,
+			Requests: row.Requests,
+			Limit:    plan.EndpointLimit(row.Endpoint),
+		})
+	}
+	sort.Slice(usage.Endpoints, func(i, j int) bool {
+		return usage.Endpoints[i].Route < usage.Endpoints[j].Route
+	})
+	return usage
+}
+
+// usageKey is the counter hash of consumer in period; periods never contain
+// a colon, so the key splits back unambiguously
+func usageKey(period, consumer string) string {
+	return usageKeyPrefix + period + ":" + consumer
+}
+
+func parseUsageKey(key string) (string, string, bool) {
+	rest, ok := strings.CutPrefix(key, usageKeyPrefix)
+	if !ok {
+		return "", "", false
+	}
+	return strings.Cut(rest, ":")
+}
+
+func toAny(values []string) []any {
+	result := make([]any, len(values))
+	for i, value := range values {
+		result[i] = value
+	}
+	return result
+}
@@ -0,0 +1,124 @@
+package metering
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"golang-arch/internal/shared/api"
+	"golang-arch/internal/shared/respcache"
+)
+
+// Quota response headers
+const (
+	HeaderQuotaLimit     = "X-Quota-Limit"
+	HeaderQuotaRemaining = "X-Quota-Remaining"
+	HeaderQuotaReset     = "X-Quota-Reset" // Unix seconds
+)
+
+// DefaultHeader is the request header carrying the API key
+const DefaultHeader = "X-API-Key"
+
+// AnonymousConsumer is the consumer requests without an API key or a
+// SetConsumer ID are metered as; all of them share its quota
+const AnonymousConsumer = "anonymous"
+
+// keyPrefix starts the consumer IDs of API keys
+const keyPrefix = "key_"
+
+// consumerKey is the gin context key of the metered consumer
+const consumerKey = "metering.consumer"
+
+// SetConsumer sets the consumer of the request, e.g. the tenant an
+// authentication middleware resolved; it takes precedence over the API key
+// header. Call it before the metering middleware runs.
+func SetConsumer(c *gin.Context, id string) {
+	c.Set(consumerKey, id)
+}
+
+// Tenant returns the consumer set with SetConsumer, such as a tenant ID, or
+// "" when the request has none
+func Tenant(c *gin.Context) string {
+	return c.GetString(consumerKey)
+}
+
+// ConsumerID is the consumer ID of an API key. Keys are hashed so usage
+// rows and logs never hold them.
+func ConsumerID(apiKey string) string {
+	sum := sha256.Sum256([]byte(apiKey))
+	return keyPrefix + hex.EncodeToString(sum[:8])
+}
+
+// IsAPIKey reports whether consumer is the ConsumerID of an API key, as
+// opposed to an ID set with SetConsumer
+func IsAPIKey(consumer string) bool {
+	return strings.HasPrefix(consumer, keyPrefix)
+}
+
+// Consumer returns the request's consumer: the one set with SetConsumer,
+// else the hashed API key in header, else ""
+func Consumer(c *gin.Context, header string) string {
+	if id := c.GetString(consumerKey); id != "" {
+		return id
+	}
+	if apiKey := c.GetHeader(header); apiKey != "" {
+		return ConsumerID(apiKey)
+	}
+	return ""
+}
+
+// Middleware counts requests to matched routes and rejects them with
+// QUOTA_EXCEEDED once the consumer's quota is used up. Requests without a
+// consumer count as AnonymousConsumer; API keys without a plan are rejected
+// with 401. Background refreshes of cached responses are not counted. When
+// Redis fails requests are let through.
+func (m *Meter) Middleware(header string) gin.HandlerFunc {
+	if header == "" {
+		header = DefaultHeader
+	}
+	return func(c *gin.Context) {
+		if c.FullPath() == "" || respcache.Replayed(c.Request.Context()) {
+			c.Next()
+			return
+		}
+		consumer := Consumer(c, header)
+		if consumer == "" {
+			consumer = AnonymousConsumer
+		}
+
+		decision, err := m.Record(c.Request.Context(), consumer, c.Request.Method+" "+c.FullPath())
+		if errors.Is(err, ErrUnknownConsumer) {
+			api.RespondError(c, api.NewUnauthorizedError("unknown API key"))
+			c.Abort()
+			return
+		}
+		if err != nil {
+			m.logger.Warn("Usage metering failed, request not counted", zap.Error(err), zap.String("consumer", consumer))
+			c.Next()
+			return
+		}
+		if decision.Limit > 0 {
+			c.Header(HeaderQuotaLimit, strconv.FormatInt(decision.Limit, 10))
+			c.Header(HeaderQuotaRemaining, strconv.FormatInt(decision.Remaining, 10))
+			c.Header(HeaderQuotaReset, strconv.FormatInt(decision.Reset.Unix(), 10))
+		}
+		if !decision.Allowed {
+			retryAfter := int(decision.Reset.Sub(m.clock.Now()).Round(time.Second) / time.Second)
+			c.Header("Retry-After", strconv.Itoa(max(retryAfter, 1)))
+			message := "request quota of the plan exceeded"
+			if decision.Exceeded == "endpoint" {
+				message = "request quota of this endpoint exceeded"
+			}
+			api.RespondError(c, api.NewQuotaExceededError(message))
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
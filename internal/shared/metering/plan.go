@@ -0,0 +1,155 @@
+package metering
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"golang-arch/internal/shared/config"
+)
+
+// Period is the window a plan's quotas reset after, aligned to UTC
+type Period string
+
+const (
+	PeriodDay   Period = "day"
+	PeriodMonth Period = "month"
+)
+
+// ParsePeriod parses a configured period; empty means month
+func ParsePeriod(value string) (Period, error) {
+	switch Period(strings.ToLower(value)) {
+	case "", PeriodMonth:
+		return PeriodMonth, nil
+	case PeriodDay:
+		return PeriodDay, nil
+	}
+	return "", fmt.Errorf("unknown metering period %q", value)
+}
+
+// Bounds returns the ID ("2024-01" or "2024-01-15") and the start and end
+// of the period containing t
+func (p Period) Bounds(t time.Time) (string, time.Time, time.Time) {
+	t = t.UTC()
+	if p == PeriodDay {
+		start := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+		return start.Format("2006-01-02"), start, start.AddDate(0, 0, 1)
+	}
+	start := time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+	return start.Format("2006-01"), start, start.AddDate(0, 1, 0)
+}
+
+// Plan holds the request quotas of a consumer
+type Plan struct {
+	Name      string
+	Period    Period
+	Requests  int64            // Across all endpoints; 0 is unlimited
+	Endpoints map[string]int64 // Per route ("GET /api/v1/usage"); missing is unlimited
+}
+
+// EndpointLimit returns the quota of route; 0 is unlimited
+func (p Plan) EndpointLimit(route string) int64 {
+	return p.Endpoints[route]
+}
+
+// Unlimited is the plan of authenticated consumers without a configured
+// plan when no default plan is set, and of AnonymousConsumer when no
+// anonymous plan is; their requests are still counted
+var Unlimited = Plan{Name: "unlimited", Period: PeriodMonth}
+
+// ErrUnknownConsumer is returned for an API key no plan is assigned to
+var ErrUnknownConsumer = errors.New("unknown consumer")
+
+// PlanResolver finds the plan of a consumer
+type PlanResolver interface {
+	// Plan returns the plan of consumer, or ErrUnknownConsumer when the
+	// consumer may not use the API
+	Plan(ctx context.Context, consumer string) (Plan, error)
+}
+
+// StaticPlans assigns plans from configuration. API keys must be listed;
+// consumers set with SetConsumer were vouched for by an authentication
+// middleware and get the default plan when they are not.
+type StaticPlans struct {
+	plans     map[string]Plan
+	consumers map[string]string
+	fallback  Plan // Of unlisted SetConsumer consumers
+	anonymous Plan // Of AnonymousConsumer
+}
+
+// NewStaticPlans builds the plans and consumer assignments of cfg. Plan
+// names are case-insensitive, as viper lower-cases map keys.
+func NewStaticPlans(cfg config.MeteringConfig) (*StaticPlans, error) {
+	s := &StaticPlans{
+		plans:     make(map[string]Plan, len(cfg.Plans)),
+		consumers: make(map[string]string, len(cfg.Consumers)),
+		fallback:  Unlimited,
+		anonymous: Unlimited,
+	}
+	for name, planCfg := range cfg.Plans {
+		period, err := ParsePeriod(planCfg.Period)
+		if err != nil {
+			return nil, fmt.Errorf("plan %s: %w", name, err)
+		}
+		plan := Plan{Name: name, Period: period, Requests: planCfg.Requests, Endpoints: make(map[string]int64)}
+		for _, endpoint := range planCfg.Endpoints {
+			route, err := normalizeRoute(endpoint.Route)
+			if err != nil {
+				return nil, fmt.Errorf("plan %s: %w", name, err)
+			}
+			plan.Endpoints[route] = endpoint.Requests
+		}
+		s.plans[strings.ToLower(name)] = plan
+	}
+	for _, consumer := range cfg.Consumers {
+		name := strings.ToLower(consumer.Plan)
+		if _, ok := s.plans[name]; !ok {
+			return nil, fmt.Errorf("consumer %s: unknown plan %q", consumer.ID, consumer.Plan)
+		}
+		s.consumers[consumer.ID] = name
+	}
+	if cfg.DefaultPlan != "" {
+		plan, ok := s.plans[strings.ToLower(cfg.DefaultPlan)]
+		if !ok {
+			return nil, fmt.Errorf("unknown default plan %q", cfg.DefaultPlan)
+		}
+		s.fallback = plan
+	}
+	if cfg.AnonymousPlan != "" {
+		plan, ok := s.plans[strings.ToLower(cfg.AnonymousPlan)]
+		if !ok {
+			return nil, fmt.Errorf("unknown anonymous plan %q", cfg.AnonymousPlan)
+		}
+		s.anonymous = plan
+	}
+	return s, nil
+}
+
+// Plan returns the consumer's assigned plan. Unlisted API keys fail with
+// ErrUnknownConsumer, AnonymousConsumer gets the anonymous plan and other
+// consumers the default plan.
+func (s *StaticPlans) Plan(_ context.Context, consumer string) (Plan, error) {
+	if name, ok := s.consumers[consumer]; ok {
+		return s.plans[name], nil
+	}
+	switch {
+	case consumer == AnonymousConsumer:
+		return s.anonymous, nil
+	case IsAPIKey(consumer):
+		return Plan{}, ErrUnknownConsumer
+	default:
+		return s.fallback, nil
+	}
+}
+
+// normalizeRoute upper-cases the method of "METHOD /path"
+func normalizeRoute(route string) (string, error) {
+	method, path, ok := strings.Cut(strings.TrimSpace(route), " ")
+	path = strings.TrimSpace(path)
+	if !ok || method == "" || !strings.HasPrefix(path, "/") {
+		return "", fmt.Errorf("invalid route %q, expected \"METHOD /path\"", route)
+	}
+	return strings.ToUpper(method) + " " + path, nil
+}
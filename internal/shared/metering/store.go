@@ -0,0 +1,152 @@
+package metering
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// Row is the request count of one consumer, period and endpoint. The
+// endpoint TotalEndpoint holds the count across all endpoints.
+type Row struct {
+	Consumer string
+	Period   string
+	Endpoint string
+	Requests int64
+}
+
+// TotalEndpoint is the endpoint of rows counting all requests of a period
+const TotalEndpoint = "*"
+
+// Store persists flushed usage counters
+type Store interface {
+	// Save records the counts; a count never lowers a stored one, so
+	// saving the same counters twice is harmless
+	Save(ctx context.Context, rows []Row) error
+	// History returns the rows of the consumer's latest periods, newest
+	// period first and endpoints in order
+	History(ctx context.Context, consumer string, periods int) ([]Row, error)
+}
+
+// PostgresStore keeps usage in the api_usage table
+type PostgresStore struct {
+	db *sql.DB
+}
+
+// NewPostgresStore creates a store on db
+func NewPostgresStore(db *sql.DB) *PostgresStore {
+	return &PostgresStore{db: db}
+}
+
+// Save upserts the rows in one transaction
+func (s *PostgresStore) Save(ctx context.Context, rows []Row) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin usage transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	for _, row := range rows {
+		_, err := tx.ExecContext(ctx,
+			`INSERT INTO api_usage (consumer, period, endpoint, requests)
+			VALUES ($1, $2, $3, $4)
+			ON CONFLICT (consumer, period, endpoint) DO UPDATE SET
+				requests = GREATEST(api_usage.requests, EXCLUDED.requests),
+				updated_at = NOW()`,
+			row.Consumer, row.Period, row.Endpoint, row.Requests)
+		if err != nil {
+			return fmt.Errorf("failed to save usage: %w", err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit usage: %w", err)
+	}
+	return nil
+}
+
+// History selects the rows of the latest periods
+func (s *PostgresStore) History(ctx context.Context, consumer string, periods int) ([]Row, error) {
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT consumer, period, endpoint, requests FROM api_usage
+		WHERE consumer = $1 AND period IN (
+			SELECT DISTINCT period FROM api_usage WHERE consumer = $1 ORDER BY period DESC LIMIT $2
+		)
+		ORDER BY period DESC, endpoint`,
+		consumer, periods)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query usage: %w", err)
+	}
+	defer rows.Close()
+
+	var history []Row
+	for rows.Next() {
+		var row Row
+		if err := rows.Scan(&row.Consumer, &row.Period, &row.Endpoint, &row.Requests); err != nil {
+			return nil, fmt.Errorf("failed to scan usage: %w", err)
+		}
+		history = append(history, row)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read usage: %w", err)
+	}
+	return history, nil
+}
+
+// MemoryStore keeps usage in memory, for tests and the dev profile
+type MemoryStore struct {
+	mu   sync.Mutex
+	rows map[[3]string]int64
+}
+
+// NewMemoryStore creates an empty in-memory store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{rows: make(map[[3]string]int64)}
+}
+
+// Save stores the rows, keeping the larger count
+func (s *MemoryStore) Save(_ context.Context, rows []Row) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, row := range rows {
+		key := [3]string{row.Consumer, row.Period, row.Endpoint}
+		s.rows[key] = max(s.rows[key], row.Requests)
+	}
+	return nil
+}
+
+// History returns the rows of the latest periods
+func (s *MemoryStore) History(_ context.Context, consumer string, periods int) ([]Row, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var history []Row
+	seen := make(map[string]bool)
+	for key, requests := range s.rows {
+		if key[0] == consumer {
+			history = append(history, Row{Consumer: key[0], Period: key[1], Endpoint: key[2], Requests: requests})
+			seen[key[1]] = true
+		}
+	}
+	sort.Slice(history, func(i, j int) bool {
+		if history[i].Period != history[j].Period {
+			return history[i].Period > history[j].Period
+		}
+		return history[i].Endpoint < history[j].Endpoint
+	})
+
+	if len(seen) <= periods {
+		return history, nil
+	}
+	kept := 0
+	for i, row := range history {
+		if i == 0 || row.Period != history[i-1].Period {
+			if kept == periods {
+				return history[:i], nil
+			}
+			kept++
+		}
+	}
+	return history, nil
+}
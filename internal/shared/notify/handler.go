@@ -0,0 +1,89 @@
+package notify
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"golang-arch/internal/shared/api"
+	i18n "golang-arch/internal/shared/domain/internationalization"
+	"golang-arch/internal/shared/geo"
+	"golang-arch/internal/shared/rbac"
+)
+
+// PermissionAdmin lets an operator read or replace any recipient's
+// preferences
+const PermissionAdmin rbac.Permission = "notify:admin"
+
+// Handler exposes notification preferences over HTTP
+type Handler struct {
+	router *Router
+	authz  *rbac.Authorizer
+}
+
+// NewHandler creates the notification preferences handler; authz guards
+// every route
+func NewHandler(router *Router, authz *rbac.Authorizer) *Handler {
+	return &Handler{router: router, authz: authz}
+}
+
+// Register adds the preference routes to group. Only the recipient's own
+// principal or one with notify:admin may use them:
+//
+//	GET /notifications/preferences/:recipient  the recipient's preferences
+//	PUT /notifications/preferences/:recipient  replace them
+func (h *Handler) Register(group *gin.RouterGroup) {
+	owner := h.authz.RequireOwner("recipient", PermissionAdmin)
+
+	group.GET("/notifications/preferences/:recipient", owner, h.get)
+	group.PUT("/notifications/preferences/:recipient", owner, h.put)
+}
+
+// PreferencesBody replaces a recipient's preferences
+type PreferencesBody struct {
+	Locale     i18n.Locale          `json:"locale"`   // Defaults to the request's detected locale
+	Timezone   string               `json:"timezone"` // Defaults to the request's detected timezone
+	Channels   []Channel            `json:"channels"`
+	Events     map[string][]Channel `json:"events"`
+	Addresses  map[Channel]string   `json:"addresses"`
+	QuietHours *i18n.ContactWindow  `json:"quiet_hours"`
+}
+
+func (h *Handler) get(c *gin.Context) {
+	prefs, err := h.router.Preferences(c.Request.Context(), c.Param("recipient"))
+	if err != nil {
+		api.RespondError(c, err)
+		return
+	}
+	api.Success(c, prefs, "notification preferences")
+}
+
+func (h *Handler) put(c *gin.Context) {
+	var body PreferencesBody
+	if !api.BindJSON(c, &body) {
+		return
+	}
+
+	prefs := Preferences{
+		RecipientID: c.Param("recipient"),
+		Locale:      body.Locale,
+		Timezone:    body.Timezone,
+		Channels:    body.Channels,
+		Events:      body.Events,
+		Addresses:   body.Addresses,
+		QuietHours:  body.QuietHours,
+	}
+	if location, ok := geo.FromContext(c.Request.Context()); ok {
+		if prefs.Locale == "" {
+			prefs.Locale = i18n.Locale(location.Locale)
+		}
+		if prefs.Timezone == "" {
+			prefs.Timezone = location.Timezone
+		}
+	}
+
+	saved, err := h.router.SavePreferences(c.Request.Context(), prefs)
+	if err != nil {
+		api.RespondError(c, err)
+		return
+	}
+	api.Success(c, saved, "notification preferences saved")
+}
@@ -0,0 +1,224 @@
+// Package notify routes notifications to the channel a recipient prefers.
+// A Notification names an event and a recipient; the Router looks up the
+// recipient's stored Preferences, picks the first channel that has an
+// address, a Sender and, for purposes that need it, the recipient's consent,
+// and hands the message to that channel's Sender.
+//
+// SMS and push are not sent during the recipient's quiet hours, which are
+// evaluated in their timezone; such messages are deferred until the quiet
+// hours end, and consent is checked again when they are sent. Email and
+// webhooks are delivered at once.
+package notify
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"net/mail"
+	"net/url"
+	"slices"
+	"time"
+
+	"go.uber.org/zap"
+
+	"golang-arch/internal/shared/consent"
+	i18n "golang-arch/internal/shared/domain/internationalization"
+	"golang-arch/internal/shared/domain/validation"
+)
+
+// Channel is a way of reaching a recipient
+type Channel string
+
+// Channels
+const (
+	ChannelEmail   Channel = "email"
+	ChannelSMS     Channel = "sms"
+	ChannelPush    Channel = "push"
+	ChannelWebhook Channel = "webhook"
+)
+
+// Validate checks that the channel is known
+func (c Channel) Validate() error {
+	var errs validation.ValidationErrors
+	c.validate(&errs, "channel")
+	return errs.Err()
+}
+
+// validate adds an error for field to errs unless the channel is known
+func (c Channel) validate(errs *validation.ValidationErrors, field string) {
+	switch c {
+	case ChannelEmail, ChannelSMS, ChannelPush, ChannelWebhook:
+	default:
+		errs.Add(field, validation.CodeUnsupported, fmt.Sprintf("unsupported channel %q (expected email, sms, push or webhook)", c), nil)
+	}
+}
+
+// ParseChannels reads channel names such as "push", in the given order
+func ParseChannels(names []string) ([]Channel, error) {
+	channels := make([]Channel, 0, len(names))
+	for _, name := range names {
+		channel := Channel(name)
+		if err := channel.Validate(); err != nil {
+			return nil, err
+		}
+		channels = append(channels, channel)
+	}
+	return channels, nil
+}
+
+// consentChannel is the consent channel of c; webhooks are set up by the
+// recipient and need none
+func (c Channel) consentChannel() (consent.Channel, bool) {
+	switch c {
+	case ChannelEmail, ChannelSMS, ChannelPush:
+		return consent.Channel(c), true
+	default:
+		return "", false
+	}
+}
+
+// interrupts reports whether the channel disturbs the recipient, so quiet
+// hours apply to it
+func (c Channel) interrupts() bool {
+	return c == ChannelSMS || c == ChannelPush
+}
+
+// Notification is something a recipient should be told about
+type Notification struct {
+	Event       string          // e.g. "export.completed"; selects per-event preferences
+	RecipientID string          // Whose preferences apply
+	Purpose     consent.Purpose // Consent the channel needs; empty for service messages such as a finished export
+	Template    string          // Message template or catalog key, rendered by the Sender
+	Data        any             // Template data
+	Urgent      bool            // Sent during quiet hours too, e.g. a security alert
+}
+
+// Message is a notification routed to one channel, as handed to its Sender
+type Message struct {
+	Notification
+	Channel  Channel
+	Address  string      // Email address, E.164 phone number, device token or URL
+	Locale   i18n.Locale // Language to render in; empty for the default
+	Timezone string      // IANA zone to show times in; empty for UTC
+}
+
+// Sender delivers messages of one channel, e.g. through a mail server, an
+// SMS gateway or a push service
+type Sender interface {
+	Send(ctx context.Context, message Message) error
+}
+
+// Reacher is implemented by senders that keep the recipients' addresses
+// themselves, such as push with its registered devices. When a recipient's
+// preferences hold no address for the channel, the router asks the sender
+// whether it reaches them and routes the message with an empty Address.
+type Reacher interface {
+	Reaches(ctx context.Context, recipientID string) (bool, error)
+}
+
+// SenderFunc adapts a function to Sender
+type SenderFunc func(ctx context.Context, message Message) error
+
+// Send calls f
+func (f SenderFunc) Send(ctx context.Context, message Message) error {
+	return f(ctx, message)
+}
+
+// LogSender writes messages to a logger instead of delivering them, for
+// development and channels without a provider
+type LogSender struct {
+	logger *zap.Logger
+}
+
+// NewLogSender creates a sender that logs to logger
+func NewLogSender(logger *zap.Logger) *LogSender {
+	return &LogSender{logger: logger}
+}
+
+// Send logs the message
+func (s *LogSender) Send(_ context.Context, message Message) error {
+	s.logger.Info("Notification (log sender)",
+		zap.String("channel", string(message.Channel)),
+		zap.String("address", message.Address),
+		zap.String("event", message.Event),
+		zap.String("template", message.Template),
+		zap.String("locale", message.Locale.String()))
+	return nil
+}
+
+// Preferences are how a recipient wants to be notified
+type Preferences struct {
+	RecipientID string               `json:"recipient_id"`
+	Locale      i18n.Locale          `json:"locale,omitempty"`
+	Timezone    string               `json:"timezone,omitempty"`    // IANA zone of the quiet hours; the default of the locale when empty
+	Channels    []Channel            `json:"channels,omitempty"`    // In order of preference; the router's default when empty
+	Events      map[string][]Channel `json:"events,omitempty"`      // Per-event channels; an empty list mutes the event
+	Addresses   map[Channel]string   `json:"addresses,omitempty"`   // Where each channel reaches the recipient
+	QuietHours  *i18n.ContactWindow  `json:"quiet_hours,omitempty"` // e.g. 22:00–07:00 local time
+	UpdatedAt   time.Time            `json:"updated_at"`
+}
+
+// Validate checks the channels, addresses, timezone and quiet hours
+func (p Preferences) Validate() error {
+	var errs validation.ValidationErrors
+	if p.RecipientID == "" {
+		errs.Add("recipient_id", validation.CodeRequired, "recipient_id is required", nil)
+	}
+	if p.Locale != "" {
+		if _, err := i18n.ParseLocale(string(p.Locale)); err != nil {
+			errs.Add("locale", validation.CodeInvalidFormat, err.Error(), nil)
+		}
+	}
+	if p.Timezone != "" {
+		if _, err := time.LoadLocation(p.Timezone); err != nil {
+			errs.Add("timezone", validation.CodeInvalidFormat, fmt.Sprintf("unknown timezone %q", p.Timezone), nil)
+		}
+	}
+	if p.QuietHours != nil {
+		if err := p.QuietHours.Validate(); err != nil {
+			errs.Add("quiet_hours", validation.CodeInvalidFormat, err.Error(), nil)
+		}
+	}
+	for i, channel := range p.Channels {
+		channel.validate(&errs, fmt.Sprintf("channels[%d]", i))
+	}
+	for _, event := range slices.Sorted(maps.Keys(p.Events)) {
+		for i, channel := range p.Events[event] {
+			channel.validate(&errs, fmt.Sprintf("events.%s[%d]", event, i))
+		}
+	}
+	for _, channel := range slices.Sorted(maps.Keys(p.Addresses)) {
+		address, field := p.Addresses[channel], "addresses."+string(channel)
+		if channel.Validate() != nil {
+			channel.validate(&errs, field)
+			continue
+		}
+		if err := validateAddress(channel, address); err != nil {
+			errs.Add(field, validation.CodeInvalidFormat, err.Error(), nil)
+		}
+	}
+	return errs.Err()
+}
+
+// validateAddress checks that address suits channel
+func validateAddress(channel Channel, address string) error {
+	switch channel {
+	case ChannelEmail:
+		if _, err := mail.ParseAddress(address); err != nil {
+			return fmt.Errorf("invalid email address %q", address)
+		}
+	case ChannelSMS:
+		if _, err := i18n.FromPrimitivePhone(address); err != nil {
+			return fmt.Errorf("invalid phone number %q", address)
+		}
+	case ChannelWebhook:
+		if target, err := url.Parse(address); err != nil || target.Scheme != "https" || target.Host == "" {
+			return fmt.Errorf("webhook URL must be an https URL")
+		}
+	default:
+		if address == "" {
+			return fmt.Errorf("%s address is empty", channel)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,378 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"golang-arch/internal/shared/consent"
+	"golang-arch/internal/shared/domain/domainerror"
+	i18n "golang-arch/internal/shared/domain/internationalization"
+	"golang-arch/internal/shared/erasure"
+	"golang-arch/internal/shared/events"
+	"golang-arch/pkg/clock"
+)
+
+// DefaultChannels are tried for recipients without channel preferences
+var DefaultChannels = []Channel{ChannelPush, ChannelEmail, ChannelSMS}
+
+// ErrUndeliverable is returned when no channel can reach the recipient
+var ErrUndeliverable = errors.New("no channel can reach the recipient")
+
+// Reasons a channel was passed over
+const (
+	SkipNoAddress = "no_address" // The recipient has no address on the channel
+	SkipNoSender  = "no_sender"  // The channel has no provider
+	SkipNoConsent = "no_consent" // The recipient did not consent to the purpose on the channel
+)
+
+// Skip is a channel the router passed over, and why
+type Skip struct {
+	Channel Channel `json:"channel"`
+	Reason  string  `json:"reason"`
+}
+
+// Route is where and when a notification goes
+type Route struct {
+	Message Message
+	At      time.Time // When it is sent; later than now while quiet hours last
+	Skipped []Skip    // Preferred channels passed over
+}
+
+// Deferred reports whether the message waits for quiet hours to end
+func (r Route) Deferred(now time.Time) bool {
+	return r.At.After(now)
+}
+
+// Scheduler runs a deferred delivery at a later time, e.g. through the
+// worker's EnqueueAt
+type Scheduler func(name string, at time.Time, run func(ctx context.Context) error)
+
+// Router chooses the channel of each notification and delivers it
+type Router struct {
+	store     Store
+	consent   *consent.Service
+	senders   map[Channel]Sender
+	defaults  []Channel
+	locales   i18n.LocaleDefaults
+	clock     clock.Clock
+	logger    *zap.Logger
+	scheduler Scheduler
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// Option configures a Router
+type Option func(*Router)
+
+// WithClock sets the clock quiet hours are evaluated with
+func WithClock(c clock.Clock) Option {
+	return func(r *Router) {
+		r.clock = c
+	}
+}
+
+// WithLogger sets the logger
+func WithLogger(logger *zap.Logger) Option {
+	return func(r *Router) {
+		r.logger = logger
+	}
+}
+
+// WithSender delivers the channel's messages through sender
+func WithSender(channel Channel, sender Sender) Option {
+	return func(r *Router) {
+		r.senders[channel] = sender
+	}
+}
+
+// WithConsent checks notifications that have a purpose against the
+// recipient's consent; without it they are not sent on consent channels
+func WithConsent(service *consent.Service) Option {
+	return func(r *Router) {
+		r.consent = service
+	}
+}
+
+// WithDefaultChannels sets the channels of recipients without preferences
+func WithDefaultChannels(channels ...Channel) Option {
+	return func(r *Router) {
+		if len(channels) > 0 {
+			r.defaults = channels
+		}
+	}
+}
+
+// WithLocaleDefaults sets the timezone of the quiet hours of recipients
+// without one: the default of their locale, else the application's
+func WithLocaleDefaults(defaults i18n.LocaleDefaults) Option {
+	return func(r *Router) {
+		r.locales = defaults
+	}
+}
+
+// WithScheduler runs deferred deliveries through scheduler instead of the
+// router's own timers
+func WithScheduler(scheduler Scheduler) Option {
+	return func(r *Router) {
+		r.scheduler = scheduler
+	}
+}
+
+// NewRouter creates a router reading preferences from store
+func NewRouter(store Store, options ...Option) *Router {
+	ctx, cancel := context.WithCancel(context.Background())
+	r := &Router{
+		store:    store,
+		senders:  make(map[Channel]Sender),
+		defaults: DefaultChannels,
+		locales:  i18n.BuiltInLocaleDefaults(),
+		clock:    clock.New(),
+		logger:   zap.NewNop(),
+		ctx:      ctx,
+		cancel:   cancel,
+	}
+	for _, option := range options {
+		option(r)
+	}
+	if r.scheduler == nil {
+		r.scheduler = r.schedule
+	}
+	return r
+}
+
+// Preferences returns the recipient's preferences; recipients without
+// stored ones get empty preferences
+func (r *Router) Preferences(ctx context.Context, recipientID string) (Preferences, error) {
+	prefs, found, err := r.store.Get(ctx, recipientID)
+	if err != nil {
+		return Preferences{}, err
+	}
+	if !found {
+		return Preferences{RecipientID: recipientID}, nil
+	}
+	return prefs, nil
+}
+
+// SavePreferences validates and stores the recipient's preferences
+func (r *Router) SavePreferences(ctx context.Context, prefs Preferences) (Preferences, error) {
+	if err := prefs.Validate(); err != nil {
+		return Preferences{}, err
+	}
+	prefs.UpdatedAt = r.clock.Now().UTC()
+	if err := r.store.Save(ctx, prefs); err != nil {
+		return Preferences{}, err
+	}
+	return prefs, nil
+}
+
+// Erase deletes the subject's preferences, which hold their addresses; it
+// is the preference store's erasure.Eraser. The recipient falls back to the
+// default channels and has no address to reach.
+func (r *Router) Erase(ctx context.Context, subject erasure.Subject, _ *erasure.Anonymizer) (int, error) {
+	deleted, err := r.store.Delete(ctx, subject.ID)
+	if err != nil || !deleted {
+		return 0, err
+	}
+	return 1, nil
+}
+
+// Route chooses the channel of n: the first of the recipient's channels
+// for the event that has a sender, an address and the needed consent.
+// During quiet hours SMS and push are scheduled for their end unless n is
+// urgent. A muted event or no usable channel is ErrUndeliverable.
+func (r *Router) Route(ctx context.Context, n Notification) (Route, error) {
+	prefs, err := r.Preferences(ctx, n.RecipientID)
+	if err != nil {
+		return Route{}, err
+	}
+
+	channels := r.defaults
+	if len(prefs.Channels) > 0 {
+		channels = prefs.Channels
+	}
+	if eventChannels, ok := prefs.Events[n.Event]; ok {
+		channels = eventChannels
+	}
+
+	var route Route
+	for _, channel := range channels {
+		sender, ok := r.senders[channel]
+		if !ok {
+			route.Skipped = append(route.Skipped, Skip{Channel: channel, Reason: SkipNoSender})
+			continue
+		}
+		address := prefs.Addresses[channel]
+		reaches, err := r.reaches(ctx, sender, n.RecipientID, address)
+		if err != nil {
+			return Route{}, err
+		}
+		if !reaches {
+			route.Skipped = append(route.Skipped, Skip{Channel: channel, Reason: SkipNoAddress})
+			continue
+		}
+		allowed, err := r.allowed(ctx, n, channel)
+		if err != nil {
+			return Route{}, err
+		}
+		if !allowed {
+			route.Skipped = append(route.Skipped, Skip{Channel: channel, Reason: SkipNoConsent})
+			continue
+		}
+
+		route.Message = Message{Notification: n, Channel: channel, Address: address, Locale: prefs.Locale, Timezone: prefs.Timezone}
+		route.At = r.clock.Now()
+		if prefs.QuietHours != nil && channel.interrupts() && !n.Urgent {
+			if route.At, err = quietUntil(route.At, *prefs.QuietHours, r.timezone(prefs)); err != nil {
+				return Route{}, err
+			}
+		}
+		return route, nil
+	}
+	return route, fmt.Errorf("%w %s for %q", ErrUndeliverable, n.RecipientID, n.Event)
+}
+
+// Send routes n and delivers it, now or once the recipient's quiet hours
+// end. A deferred message checks consent again before it is sent.
+func (r *Router) Send(ctx context.Context, n Notification) (Route, error) {
+	route, err := r.Route(ctx, n)
+	if err != nil {
+		return route, err
+	}
+	if !route.Deferred(r.clock.Now()) {
+		return route, r.deliver(ctx, route.Message)
+	}
+
+	message := route.Message
+	r.scheduler("notify_"+string(message.Channel), route.At, func(ctx context.Context) error {
+		allowed, err := r.allowed(ctx, message.Notification, message.Channel)
+		if err != nil || !allowed {
+			return err
+		}
+		return r.deliver(ctx, message)
+	})
+	r.logger.Debug("Notification deferred to the end of quiet hours",
+		zap.String("recipient", n.RecipientID), zap.String("event", n.Event), zap.Time("at", route.At))
+	return route, nil
+}
+
+// Subscribe sends a notification for every event named name on bus.
+// notification builds it from the event; events it returns false for, e.g.
+// without a recipient, are ignored. Undeliverable notifications are logged.
+func (r *Router) Subscribe(bus *events.Bus, name string, notification func(events.Event) (Notification, bool)) {
+	bus.Subscribe(name, func(ctx context.Context, event events.Event) error {
+		n, ok := notification(event)
+		if !ok {
+			return nil
+		}
+		if n.Event == "" {
+			n.Event = event.EventName()
+		}
+		if _, err := r.Send(ctx, n); err != nil {
+			if errors.Is(err, ErrUndeliverable) {
+				r.logger.Info("Notification not sent", zap.String("recipient", n.RecipientID), zap.String("event", n.Event), zap.Error(err))
+				return nil
+			}
+			return err
+		}
+		return nil
+	})
+}
+
+// reaches reports whether sender can reach the recipient: with an address
+// from their preferences, or through the sender's own addresses
+func (r *Router) reaches(ctx context.Context, sender Sender, recipientID, address string) (bool, error) {
+	if address != "" {
+		return true, nil
+	}
+	reacher, ok := sender.(Reacher)
+	if !ok {
+		return false, nil
+	}
+	return reacher.Reaches(ctx, recipientID)
+}
+
+// allowed reports whether n may be sent on channel. Without a purpose, or
+// on channels without consent, it always may.
+func (r *Router) allowed(ctx context.Context, n Notification, channel Channel) (bool, error) {
+	consentChannel, ok := channel.consentChannel()
+	if n.Purpose == "" || !ok {
+		return true, nil
+	}
+	if r.consent == nil {
+		return false, nil
+	}
+	return r.consent.Allowed(ctx, n.RecipientID, n.Purpose, consentChannel)
+}
+
+// deliver hands the message to its channel's sender
+func (r *Router) deliver(ctx context.Context, message Message) error {
+	if err := r.senders[message.Channel].Send(ctx, message); err != nil {
+		return fmt.Errorf("failed to send %s notification to %s: %w", message.Channel, message.RecipientID, err)
+	}
+	return nil
+}
+
+// schedule is the default Scheduler: a timer on the router's clock. Waiting
+// deliveries are dropped by Close.
+func (r *Router) schedule(name string, at time.Time, run func(ctx context.Context) error) {
+	due := r.clock.After(at.Sub(r.clock.Now()))
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+		select {
+		case <-due:
+			if err := run(r.ctx); err != nil {
+				r.logger.Error("Deferred notification failed", zap.String("job", name), zap.Error(err))
+			}
+		case <-r.ctx.Done():
+			r.logger.Warn("Dropping deferred notification at shutdown", zap.String("job", name), zap.Time("run_at", at))
+		}
+	}()
+}
+
+// Close drops the deliveries waiting on the router's own timers
+func (r *Router) Close() error {
+	r.cancel()
+	r.wg.Wait()
+	return nil
+}
+
+// timezone is the recipient's timezone, else the default of their locale
+func (r *Router) timezone(prefs Preferences) string {
+	if prefs.Timezone != "" {
+		return prefs.Timezone
+	}
+	return r.locales.Resolve(i18n.PartialLocalePreferences{Locale: prefs.Locale.String()}).Timezone.ID
+}
+
+// quietUntil returns now, or the end of the quiet hours when now falls in
+// them in timezone
+func quietUntil(now time.Time, quiet i18n.ContactWindow, timezone string) (time.Time, error) {
+	tz, err := i18n.MakeTimezone(timezone)
+	if err != nil {
+		return time.Time{}, domainerror.Invalidf("invalid timezone %q in notification preferences", timezone)
+	}
+	inside, err := quiet.Contains(now, tz)
+	if err != nil || !inside {
+		return now, err
+	}
+
+	loc, err := tz.GetLocation()
+	if err != nil {
+		return time.Time{}, err
+	}
+	local := now.In(loc)
+	year, month, day := local.Date()
+	end := time.Date(year, month, day, quiet.End/60, quiet.End%60, 0, 0, loc)
+	if !end.After(local) {
+		end = time.Date(year, month, day+1, quiet.End/60, quiet.End%60, 0, 0, loc)
+	}
+	return end.In(now.Location()), nil
+}
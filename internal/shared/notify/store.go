@@ -0,0 +1,150 @@
+package notify
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+
+	"golang-arch/internal/shared/fieldcrypt"
+)
+
+// Store persists the notification preferences of recipients
+type Store interface {
+	// Get returns the preferences; found is false when none were saved
+	Get(ctx context.Context, recipientID string) (Preferences, bool, error)
+	// Save inserts or replaces the preferences
+	Save(ctx context.Context, prefs Preferences) error
+	// Delete removes the preferences and reports whether there were any
+	Delete(ctx context.Context, recipientID string) (bool, error)
+}
+
+// PostgresStore keeps preferences as JSON in the
+// notification_preferences table. With encryption, the addresses are
+// sealed in the addresses column instead of the JSON.
+type PostgresStore struct {
+	db        *sql.DB
+	addresses *fieldcrypt.Field // Seals Preferences.Addresses when set
+}
+
+// StoreOption configures a PostgresStore
+type StoreOption func(*PostgresStore)
+
+// WithEncryption seals the recipients' addresses with encryptor. Rows saved
+// before keep their plaintext addresses until they are saved again.
+func WithEncryption(encryptor *fieldcrypt.Encryptor) StoreOption {
+	return func(s *PostgresStore) {
+		if encryptor != nil {
+			s.addresses = encryptor.Field("notification_preferences.addresses")
+		}
+	}
+}
+
+// NewPostgresStore creates a store on db
+func NewPostgresStore(db *sql.DB, opts ...StoreOption) *PostgresStore {
+	store := &PostgresStore{db: db}
+	for _, opt := range opts {
+		opt(store)
+	}
+	return store
+}
+
+// Get selects the recipient's preferences
+func (s *PostgresStore) Get(ctx context.Context, recipientID string) (Preferences, bool, error) {
+	var data, sealed []byte
+	err := s.db.QueryRowContext(ctx,
+		`SELECT preferences, addresses FROM notification_preferences WHERE recipient_id = $1`, recipientID).Scan(&data, &sealed)
+	if errors.Is(err, sql.ErrNoRows) {
+		return Preferences{}, false, nil
+	}
+	if err != nil {
+		return Preferences{}, false, fmt.Errorf("failed to read notification preferences: %w", err)
+	}
+	var prefs Preferences
+	if err := json.Unmarshal(data, &prefs); err != nil {
+		return Preferences{}, false, fmt.Errorf("failed to decode notification preferences of %s: %w", recipientID, err)
+	}
+	if sealed != nil {
+		if s.addresses == nil {
+			return Preferences{}, false, fmt.Errorf("notification addresses of %s are encrypted but encryption is disabled", recipientID)
+		}
+		if err := s.addresses.Open(sealed, &prefs.Addresses); err != nil {
+			return Preferences{}, false, fmt.Errorf("failed to decrypt notification addresses of %s: %w", recipientID, err)
+		}
+	}
+	return prefs, true, nil
+}
+
+// Save upserts the preferences
+func (s *PostgresStore) Save(ctx context.Context, prefs Preferences) error {
+	var sealed any
+	if s.addresses != nil {
+		sealed = s.addresses.Value(prefs.Addresses)
+		prefs.Addresses = nil
+	}
+	data, err := json.Marshal(prefs)
+	if err != nil {
+		return fmt.Errorf("failed to encode notification preferences: %w", err)
+	}
+	_, err = s.db.ExecContext(ctx,
+		`INSERT INTO notification_preferences (recipient_id, preferences, addresses, updated_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (recipient_id) DO UPDATE
+		SET preferences = EXCLUDED.preferences, addresses = EXCLUDED.addresses, updated_at = EXCLUDED.updated_at`,
+		prefs.RecipientID, data, sealed, prefs.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save notification preferences: %w", err)
+	}
+	return nil
+}
+
+// Delete removes the recipient's preferences
+func (s *PostgresStore) Delete(ctx context.Context, recipientID string) (bool, error) {
+	result, err := s.db.ExecContext(ctx, `DELETE FROM notification_preferences WHERE recipient_id = $1`, recipientID)
+	if err != nil {
+		return false, fmt.Errorf("failed to delete notification preferences: %w", err)
+	}
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to delete notification preferences: %w", err)
+	}
+	return deleted > 0, nil
+}
+
+// MemoryStore keeps preferences in memory, for tests and the dev profile
+type MemoryStore struct {
+	mu    sync.Mutex
+	prefs map[string]Preferences
+}
+
+// NewMemoryStore creates an empty in-memory store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{prefs: make(map[string]Preferences)}
+}
+
+// Get returns the stored preferences
+func (s *MemoryStore) Get(_ context.Context, recipientID string) (Preferences, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	prefs, ok := s.prefs[recipientID]
+	return prefs, ok, nil
+}
+
+// Save stores the preferences
+func (s *MemoryStore) Save(_ context.Context, prefs Preferences) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.prefs[prefs.RecipientID] = prefs
+	return nil
+}
+
+// Delete removes the stored preferences
+func (s *MemoryStore) Delete(_ context.Context, recipientID string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, ok := s.prefs[recipientID]
+	delete(s.prefs, recipientID)
+	return ok, nil
+}
@@ -0,0 +1,87 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"golang-arch/pkg/clock"
+)
+
+// SignatureHeader carries "t=<unix time>,v1=<hex HMAC-SHA256>" of the
+// timestamp, a dot and the body, so receivers can verify webhooks and
+// reject replays
+const SignatureHeader = "X-Notification-Signature"
+
+// DefaultWebhookTimeout bounds one webhook request
+const DefaultWebhookTimeout = 10 * time.Second
+
+// WebhookPayload is the JSON body of a webhook
+type WebhookPayload struct {
+	Event       string    `json:"event"`
+	RecipientID string    `json:"recipient_id"`
+	Template    string    `json:"template,omitempty"`
+	Data        any       `json:"data,omitempty"`
+	SentAt      time.Time `json:"sent_at"`
+}
+
+// WebhookSender posts messages as JSON to the recipient's URL
+type WebhookSender struct {
+	client *http.Client
+	secret []byte
+	clock  clock.Clock
+}
+
+// NewWebhookSender creates a webhook sender signing with secret; an empty
+// secret sends no signature
+func NewWebhookSender(secret string, timeout time.Duration, clk clock.Clock) *WebhookSender {
+	if timeout <= 0 {
+		timeout = DefaultWebhookTimeout
+	}
+	return &WebhookSender{client: &http.Client{Timeout: timeout}, secret: []byte(secret), clock: clk}
+}
+
+// Send posts the message; any status but 2xx is an error
+func (s *WebhookSender) Send(ctx context.Context, message Message) error {
+	now := s.clock.Now().UTC()
+	body, err := json.Marshal(WebhookPayload{
+		Event:       message.Event,
+		RecipientID: message.RecipientID,
+		Template:    message.Template,
+		Data:        message.Data,
+		SentAt:      now,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode webhook: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, message.Address, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("invalid webhook URL: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if len(s.secret) > 0 {
+		timestamp := strconv.FormatInt(now.Unix(), 10)
+		mac := hmac.New(sha256.New, s.secret)
+		mac.Write([]byte(timestamp + "."))
+		mac.Write(body)
+		req.Header.Set(SignatureHeader, "t="+timestamp+",v1="+hex.EncodeToString(mac.Sum(nil)))
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("webhook request failed: %w", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook answered %s", resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,149 @@
+package otp
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	i18n "golang-arch/internal/shared/domain/internationalization"
+)
+
+// Delivery is a rendered verification message waiting in the outbox. It
+// holds the address and the code until sent; with WithEncryption the outbox
+// stores it sealed, otherwise the outbox must stay private to the
+// application's Redis.
+type Delivery struct {
+	Channel   Channel   `json:"channel"`
+	Address   string    `json:"address"`
+	Purpose   string    `json:"purpose"`
+	Locale    string    `json:"locale"`
+	Subject   string    `json:"subject,omitempty"` // Email only
+	Body      string    `json:"body"`
+	ExpiresAt time.Time `json:"expires_at"` // Sending after the code expires is pointless
+	Attempts  int       `json:"attempts"`   // Failed send attempts so far
+}
+
+// Sender hands a delivery to an SMS gateway or mail server
+type Sender interface {
+	Send(ctx context.Context, delivery Delivery) error
+}
+
+// SenderFunc adapts a function to Sender
+type SenderFunc func(ctx context.Context, delivery Delivery) error
+
+// Send calls f
+func (f SenderFunc) Send(ctx context.Context, delivery Delivery) error {
+	return f(ctx, delivery)
+}
+
+// LogSender writes deliveries, including the code, to a logger. It lets
+// developers sign in without a gateway and must not be used in production.
+type LogSender struct {
+	logger *zap.Logger
+}
+
+// NewLogSender creates a sender that logs to logger
+func NewLogSender(logger *zap.Logger) *LogSender {
+	return &LogSender{logger: logger}
+}
+
+// Send logs the delivery
+func (s *LogSender) Send(_ context.Context, delivery Delivery) error {
+	s.logger.Info("Verification code (log sender)",
+		zap.String("channel", string(delivery.Channel)),
+		zap.String("address", delivery.Address),
+		zap.String("purpose", delivery.Purpose),
+		zap.String("body", delivery.Body))
+	return nil
+}
+
+// NewSenders builds the sender named by name for every channel
+func NewSenders(name string, logger *zap.Logger) (map[Channel]Sender, error) {
+	switch name {
+	case "", "log":
+		sender := NewLogSender(logger)
+		return map[Channel]Sender{ChannelSMS: sender, ChannelEmail: sender}, nil
+	default:
+		return nil, fmt.Errorf("unknown otp sender %q", name)
+	}
+}
+
+// Messages are the localized templates of verification messages.
+// Templates may use {code} and {minutes}.
+type Messages struct {
+	SMS          i18n.LocalizedString
+	EmailSubject i18n.LocalizedString
+	EmailBody    i18n.LocalizedString
+}
+
+// DefaultMessages returns the built-in templates
+func DefaultMessages() Messages {
+	return Messages{
+		SMS: mustLocalized(map[string]string{
+			"en": "Your verification code is {code}. It expires in {minutes} minutes. Never share it with anyone.",
+			"id": "Kode verifikasi Anda adalah {code}. Berlaku selama {minutes} menit. Jangan bagikan kepada siapa pun.",
+			"de": "Ihr Bestätigungscode lautet {code}. Er ist {minutes} Minuten gültig. Geben Sie ihn niemals weiter.",
+			"es": "Tu código de verificación es {code}. Caduca en {minutes} minutos. No lo compartas con nadie.",
+			"fr": "Votre code de vérification est {code}. Il expire dans {minutes} minutes. Ne le partagez avec personne.",
+			"pt": "Seu código de verificação é {code}. Ele expira em {minutes} minutos. Não o compartilhe com ninguém.",
+			"ja": "認証コードは {code} です。有効期限は{minutes}分です。他人に教えないでください。",
+		}),
+		EmailSubject: mustLocalized(map[string]string{
+			"en": "Your verification code",
+			"id": "Kode verifikasi Anda",
+			"de": "Ihr Bestätigungscode",
+			"es": "Tu código de verificación",
+			"fr": "Votre code de vérification",
+			"pt": "Seu código de verificação",
+			"ja": "認証コード",
+		}),
+		EmailBody: mustLocalized(map[string]string{
+			"en": "Your verification code is {code}.\n\nIt expires in {minutes} minutes. If you did not request it, you can ignore this email.",
+			"id": "Kode verifikasi Anda adalah {code}.\n\nBerlaku selama {minutes} menit. Jika Anda tidak memintanya, abaikan email ini.",
+			"de": "Ihr Bestätigungscode lautet {code}.\n\nEr ist {minutes} Minuten gültig. Wenn Sie ihn nicht angefordert haben, ignorieren Sie diese E-Mail.",
+			"es": "Tu código de verificación es {code}.\n\nCaduca en {minutes} minutos. Si no lo solicitaste, ignora este correo.",
+			"fr": "Votre code de vérification est {code}.\n\nIl expire dans {minutes} minutes. Si vous ne l'avez pas demandé, ignorez cet e-mail.",
+			"pt": "Seu código de verificação é {code}.\n\nEle expira em {minutes} minutos. Se você não o solicitou, ignore este e-mail.",
+			"ja": "認証コードは {code} です。\n\n有効期限は{minutes}分です。心当たりがない場合は、このメールを無視してください。",
+		}),
+	}
+}
+
+// Render builds the delivery of code in locale's language, falling back to
+// fallback's, then to that of i18n.DefaultLocale
+func (m Messages) Render(recipient Recipient, purpose, code string, ttl time.Duration, locale, fallback i18n.Locale) Delivery {
+	minutes := int((ttl + time.Minute - 1) / time.Minute)
+	replacer := strings.NewReplacer("{code}", code, "{minutes}", strconv.Itoa(minutes))
+	pick := func(text i18n.LocalizedString) string {
+		value, _ := text.Get(locale, fallback, i18n.DefaultLocale)
+		return replacer.Replace(value)
+	}
+
+	delivery := Delivery{
+		Channel: recipient.Channel,
+		Address: recipient.Address,
+		Purpose: purpose,
+		Locale:  locale.String(),
+	}
+	switch recipient.Channel {
+	case ChannelEmail:
+		delivery.Subject = pick(m.EmailSubject)
+		delivery.Body = pick(m.EmailBody)
+	default:
+		delivery.Body = pick(m.SMS)
+	}
+	return delivery
+}
+
+// mustLocalized builds a LocalizedString from built-in templates
+func mustLocalized(values map[string]string) i18n.LocalizedString {
+	text, err := i18n.NewLocalizedString(values)
+	if err != nil {
+		panic(err)
+	}
+	return *text
+}
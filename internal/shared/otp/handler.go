@@ -0,0 +1,147 @@
+package otp
+
+import (
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"golang-arch/internal/shared/api"
+	i18n "golang-arch/internal/shared/domain/internationalization"
+	"golang-arch/internal/shared/domain/validation"
+	"golang-arch/internal/shared/geo"
+	"golang-arch/internal/shared/metering"
+)
+
+// Handler exposes the OTP service over HTTP
+type Handler struct {
+	service *Service
+}
+
+// NewHandler creates the OTP HTTP handler
+func NewHandler(service *Service) *Handler {
+	return &Handler{service: service}
+}
+
+// Register adds POST /otp/request and POST /otp/verify to group
+func (h *Handler) Register(group *gin.RouterGroup) {
+	group.POST("/otp/request", h.request)
+	group.POST("/otp/verify", h.verify)
+}
+
+// RequestBody asks for a code to be sent
+type RequestBody struct {
+	Channel   Channel `json:"channel" binding:"required"`
+	Recipient string  `json:"recipient" binding:"required"` // Phone number or email address
+	Purpose   string  `json:"purpose" binding:"required"`   // e.g. "login", "phone_change"
+	Locale    string  `json:"locale"`                       // Message language; defaults to the request's
+}
+
+// Validate checks the locale
+func (b RequestBody) Validate() error {
+	if b.Locale == "" {
+		return nil
+	}
+	var errs validation.ValidationErrors
+	_, err := i18n.ParseLocale(b.Locale)
+	errs.Merge("locale", "", err)
+	return errs.Err()
+}
+
+// VerifyBody submits a received code
+type VerifyBody struct {
+	Channel   Channel `json:"channel" binding:"required"`
+	Recipient string  `json:"recipient" binding:"required"`
+	Purpose   string  `json:"purpose" binding:"required"`
+	Code      string  `json:"code" binding:"required"`
+}
+
+// requestResponse tells the client when the code expires and when it may ask again
+type requestResponse struct {
+	Recipient  string `json:"recipient"`
+	ExpiresIn  int    `json:"expires_in"`  // Seconds
+	RetryAfter int    `json:"retry_after"` // Seconds
+}
+
+// request handles POST /otp/request and answers 202 Accepted, since the
+// code is sent by the worker
+func (h *Handler) request(c *gin.Context) {
+	var body RequestBody
+	if !api.BindJSON(c, &body) {
+		return
+	}
+	recipient, err := NewRecipient(body.Channel, body.Recipient)
+	if err != nil {
+		api.ValidationFailed(c, api.ErrValidationFailed.Error(), recipientError(err))
+		return
+	}
+
+	challenge, err := h.service.Request(c.Request.Context(), recipient, body.Purpose, h.requestLocale(c, body.Locale))
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	api.Render(c, http.StatusAccepted, api.Response{
+		Success: true,
+		Message: "verification code sent",
+		Data: requestResponse{
+			Recipient:  challenge.Recipient.Address,
+			ExpiresIn:  seconds(challenge.ExpiresIn.Seconds()),
+			RetryAfter: seconds(challenge.RetryAfter.Seconds()),
+		},
+	})
+}
+
+// verify handles POST /otp/verify
+func (h *Handler) verify(c *gin.Context) {
+	var body VerifyBody
+	if !api.BindJSON(c, &body) {
+		return
+	}
+	recipient, err := NewRecipient(body.Channel, body.Recipient)
+	if err != nil {
+		api.ValidationFailed(c, api.ErrValidationFailed.Error(), recipientError(err))
+		return
+	}
+
+	if err := h.service.Verify(c.Request.Context(), recipient, body.Purpose, body.Code); err != nil {
+		h.respondError(c, err)
+		return
+	}
+	api.Success(c, gin.H{"verified": true}, "verification code accepted")
+}
+
+// respondError renders rate limits as 429 with Retry-After, and everything
+// else through the shared error mapping
+func (h *Handler) respondError(c *gin.Context, err error) {
+	var limited *RateLimitError
+	if errors.As(err, &limited) {
+		c.Header("Retry-After", strconv.Itoa(seconds(limited.RetryAfter.Seconds())))
+		api.RespondError(c, api.NewTooManyRequestsError(limited.Error()))
+		return
+	}
+	api.RespondError(c, err)
+}
+
+// requestLocale is the explicit locale, else the detected one, else the
+// default of the request's tenant (see metering.SetConsumer) or the
+// application
+func (h *Handler) requestLocale(c *gin.Context, explicit string) i18n.Locale {
+	defaults := h.service.locales.ForTenant(metering.Tenant(c))
+	return geo.Preferences(c, defaults, i18n.PartialLocalePreferences{Locale: explicit}).Locale
+}
+
+// recipientError reports an invalid recipient on the recipient field
+func recipientError(err error) error {
+	var errs validation.ValidationErrors
+	errs.Merge("recipient", "", err)
+	return errs.Err()
+}
+
+// seconds rounds a positive duration in seconds up to whole seconds
+func seconds(value float64) int {
+	return int(math.Ceil(value))
+}
@@ -0,0 +1,461 @@
+// Package otp issues and checks one-time verification codes sent by SMS or
+// email. Codes are stored in Redis only as HMAC digests with a TTL, keyed by
+// an HMAC of the recipient rather than the address, requests are rate
+// limited per recipient, checks are constant-time and limited in
+// attempts, and the message is rendered in the recipient's language. Delivery
+// goes through a Redis outbox that the worker's otp_delivery job drains into
+// the configured Senders, so API latency never depends on an SMS gateway.
+package otp
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/mail"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+
+	"golang-arch/internal/shared/domain/domainerror"
+	i18n "golang-arch/internal/shared/domain/internationalization"
+	"golang-arch/internal/shared/domain/validation"
+	"golang-arch/internal/shared/erasure"
+	"golang-arch/internal/shared/fieldcrypt"
+	"golang-arch/internal/shared/phoneverify"
+	"golang-arch/pkg/clock"
+)
+
+// Channel is how a code reaches its recipient
+type Channel string
+
+// Delivery channels
+const (
+	ChannelSMS   Channel = "sms"
+	ChannelEmail Channel = "email"
+)
+
+// purposePattern restricts purposes to short identifiers used in Redis keys
+var purposePattern = regexp.MustCompile(`^[a-z0-9_]{1,32}$`)
+
+// Recipient is a normalized phone number (E.164) or email address
+type Recipient struct {
+	Channel Channel `json:"channel"`
+	Address string  `json:"address"`
+}
+
+// NewRecipient validates and normalizes address for channel, so
+// "+1 555 123 4567" and "+15551234567" share codes and rate limits
+func NewRecipient(channel Channel, address string) (Recipient, error) {
+	switch channel {
+	case ChannelSMS:
+		phone, err := i18n.NewPhoneFromString(address)
+		if err != nil {
+			return Recipient{}, err
+		}
+		return Recipient{Channel: channel, Address: phone.FormatCompact()}, nil
+	case ChannelEmail:
+		parsed, err := mail.ParseAddress(strings.TrimSpace(address))
+		if err != nil || parsed.Name != "" {
+			return Recipient{}, domainerror.Invalidf("invalid email address %q", address)
+		}
+		return Recipient{Channel: channel, Address: strings.ToLower(parsed.Address)}, nil
+	default:
+		return Recipient{}, domainerror.Invalidf("unsupported channel %q (expected sms or email)", channel)
+	}
+}
+
+// key identifies the recipient in HMAC inputs; Redis keys use
+// Service.recipientKey so addresses are not stored in clear
+func (r Recipient) key() string {
+	return string(r.Channel) + ":" + r.Address
+}
+
+// Challenge describes an issued code without revealing it
+type Challenge struct {
+	Recipient  Recipient
+	ExpiresIn  time.Duration // Until the code stops being accepted
+	RetryAfter time.Duration // Until another code may be requested
+}
+
+// RateLimitError reports that a recipient requested codes too often
+type RateLimitError struct {
+	RetryAfter time.Duration
+}
+
+// Error implements the error interface
+func (e *RateLimitError) Error() string {
+	return fmt.Sprintf("too many verification codes requested, retry in %s", e.RetryAfter.Round(time.Second))
+}
+
+// Service issues and verifies codes
+type Service struct {
+	store    *store
+	senders  map[Channel]Sender
+	phones   phoneverify.PhoneVerifier
+	clock    clock.Clock
+	logger   *zap.Logger
+	secret   []byte
+	messages Messages
+	locales  i18n.LocaleDefaults
+
+	length      int
+	ttl         time.Duration
+	maxAttempts int
+	cooldown    time.Duration
+	sendLimit   int
+	sendWindow  time.Duration
+}
+
+// Option customizes a Service
+type Option func(*Service)
+
+// WithClock replaces the system clock used to stamp deliveries
+func WithClock(c clock.Clock) Option {
+	return func(s *Service) {
+		s.clock = c
+	}
+}
+
+// WithLogger sets the logger used to report deliveries
+func WithLogger(logger *zap.Logger) Option {
+	return func(s *Service) {
+		s.logger = logger
+	}
+}
+
+// WithSecret sets the HMAC key codes are hashed with; every instance must
+// share it
+func WithSecret(secret string) Option {
+	return func(s *Service) {
+		s.secret = []byte(secret)
+	}
+}
+
+// WithCodeLength sets the number of digits in a code
+func WithCodeLength(length int) Option {
+	return func(s *Service) {
+		s.length = length
+	}
+}
+
+// WithTTL sets how long a code is accepted
+func WithTTL(ttl time.Duration) Option {
+	return func(s *Service) {
+		s.ttl = ttl
+	}
+}
+
+// WithMaxAttempts sets how many wrong guesses invalidate a code
+func WithMaxAttempts(attempts int) Option {
+	return func(s *Service) {
+		s.maxAttempts = attempts
+	}
+}
+
+// WithCooldown sets the minimum time between two codes for a recipient
+func WithCooldown(cooldown time.Duration) Option {
+	return func(s *Service) {
+		s.cooldown = cooldown
+	}
+}
+
+// WithSendLimit allows at most limit codes per recipient within window
+func WithSendLimit(limit int, window time.Duration) Option {
+	return func(s *Service) {
+		s.sendLimit = limit
+		s.sendWindow = window
+	}
+}
+
+// WithMessages replaces the message templates
+func WithMessages(messages Messages) Option {
+	return func(s *Service) {
+		s.messages = messages
+	}
+}
+
+// WithLocaleDefaults sets the language of codes requested without a locale,
+// and of messages that have no text in the requested one
+func WithLocaleDefaults(defaults i18n.LocaleDefaults) Option {
+	return func(s *Service) {
+		s.locales = defaults
+	}
+}
+
+// WithPhoneVerifier rejects SMS codes for disconnected numbers before
+// sending; lookup failures are logged and do not block the request
+func WithPhoneVerifier(verifier phoneverify.PhoneVerifier) Option {
+	return func(s *Service) {
+		s.phones = verifier
+	}
+}
+
+// WithEncryption seals queued deliveries, which hold the address and the
+// code until sent, with the "otp.outbox" field of encryptor
+func WithEncryption(encryptor *fieldcrypt.Encryptor) Option {
+	return func(s *Service) {
+		if encryptor != nil {
+			s.store.outbox = encryptor.Field("otp.outbox")
+		}
+	}
+}
+
+// NewService creates an OTP service storing codes in client. Without
+// WithSecret a random key is used, so codes only verify on this instance.
+func NewService(client *redis.Client, senders map[Channel]Sender, options ...Option) *Service {
+	s := &Service{
+		store:       newStore(client),
+		senders:     senders,
+		clock:       clock.New(),
+		logger:      zap.NewNop(),
+		messages:    DefaultMessages(),
+		locales:     i18n.BuiltInLocaleDefaults(),
+		length:      6,
+		ttl:         5 * time.Minute,
+		maxAttempts: 5,
+		cooldown:    time.Minute,
+		sendLimit:   5,
+		sendWindow:  time.Hour,
+	}
+	for _, option := range options {
+		option(s)
+	}
+	if len(s.secret) == 0 {
+		s.secret = make([]byte, 32)
+		_, _ = rand.Read(s.secret)
+		s.logger.Warn("No OTP secret configured, codes only verify on this instance until restart")
+	}
+	return s
+}
+
+// Request issues a new code for purpose (e.g. "login") to recipient and
+// queues its delivery in locale's language, the default one when empty. A
+// previous unexpired code for
+// the same purpose stops working. Fails with a *RateLimitError when the
+// recipient asked too often.
+func (s *Service) Request(ctx context.Context, recipient Recipient, purpose string, locale i18n.Locale) (*Challenge, error) {
+	if err := validatePurpose(purpose); err != nil {
+		return nil, err
+	}
+	if _, ok := s.senders[recipient.Channel]; !ok {
+		return nil, domainerror.Unavailablef("no sender configured for channel %s", recipient.Channel)
+	}
+	if retryAfter, err := s.store.acquireCooldown(ctx, s.recipientKey(recipient), s.cooldown); err != nil {
+		return nil, err
+	} else if retryAfter > 0 {
+		return nil, &RateLimitError{RetryAfter: retryAfter}
+	}
+	if retryAfter, err := s.store.countSend(ctx, s.recipientKey(recipient), s.sendLimit, s.sendWindow); err != nil {
+		return nil, err
+	} else if retryAfter > 0 {
+		return nil, &RateLimitError{RetryAfter: retryAfter}
+	}
+	// Checked after the rate limits, as lookups cost money
+	if err := s.checkReachable(ctx, recipient); err != nil {
+		return nil, err
+	}
+
+	code, err := generateCode(s.length)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.store.saveCode(ctx, purpose, s.recipientKey(recipient), s.digest(purpose, recipient, code), s.ttl); err != nil {
+		return nil, err
+	}
+
+	if locale == "" {
+		locale = s.locales.Preferences.Locale
+	}
+	delivery := s.messages.Render(recipient, purpose, code, s.ttl, locale, s.locales.Preferences.Locale)
+	delivery.ExpiresAt = s.clock.Now().Add(s.ttl)
+	if err := s.store.enqueue(ctx, delivery); err != nil {
+		return nil, err
+	}
+
+	return &Challenge{Recipient: recipient, ExpiresIn: s.ttl, RetryAfter: s.cooldown}, nil
+}
+
+// Verify checks code for purpose and recipient. A correct code is consumed;
+// a wrong one counts as an attempt, and the code is invalidated once the
+// attempts run out. Failures are validation errors on field "code".
+func (s *Service) Verify(ctx context.Context, recipient Recipient, purpose, code string) error {
+	if err := validatePurpose(purpose); err != nil {
+		return err
+	}
+
+	attempts, digest, found, err := s.store.attempt(ctx, purpose, s.recipientKey(recipient))
+	if err != nil {
+		return err
+	}
+	if !found {
+		return codeError(validation.CodeInvalid, "verification code has expired or was not requested")
+	}
+	if attempts > s.maxAttempts {
+		if err := s.store.deleteCode(ctx, purpose, s.recipientKey(recipient)); err != nil {
+			return err
+		}
+		return codeError(validation.CodeOutOfRange, "too many attempts, request a new verification code")
+	}
+
+	expected, err := hex.DecodeString(digest)
+	if err != nil {
+		return fmt.Errorf("corrupt stored verification code: %w", err)
+	}
+	actual, _ := hex.DecodeString(s.digest(purpose, recipient, strings.TrimSpace(code)))
+	if !hmac.Equal(expected, actual) {
+		return codeError(validation.CodeInvalid, "verification code is incorrect")
+	}
+
+	// Only the request that deletes the code succeeds, so a code is used once
+	consumed, err := s.store.consumeCode(ctx, purpose, s.recipientKey(recipient))
+	if err != nil {
+		return err
+	}
+	if !consumed {
+		return codeError(validation.CodeInvalid, "verification code has expired or was not requested")
+	}
+	return nil
+}
+
+// Deliver sends the queued codes through the senders. Failed deliveries are
+// queued again for the next run, up to three attempts, and expired ones are
+// dropped. It is the otp_delivery worker job.
+func (s *Service) Deliver(ctx context.Context) error {
+	var retry []Delivery
+	failed := 0
+	for {
+		delivery, found, err := s.store.dequeue(ctx)
+		if err != nil {
+			return err
+		}
+		if !found {
+			break
+		}
+		if !s.clock.Now().Before(delivery.ExpiresAt) {
+			s.logger.Warn("Dropping expired verification code", zap.String("channel", string(delivery.Channel)))
+			continue
+		}
+
+		if err := s.send(ctx, delivery); err != nil {
+			failed++
+			s.logger.Warn("Failed to send verification code",
+				zap.String("channel", string(delivery.Channel)),
+				zap.Int("attempt", delivery.Attempts+1),
+				zap.Error(err))
+			if delivery.Attempts++; delivery.Attempts < maxDeliveryAttempts {
+				retry = append(retry, delivery)
+			}
+		}
+	}
+
+	for _, delivery := range retry {
+		if err := s.store.enqueue(ctx, delivery); err != nil {
+			return err
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d verification code deliveries failed, %d queued again", failed, len(retry))
+	}
+	return nil
+}
+
+// Erase deletes the codes, rate-limit counters and queued deliveries of the
+// subject's email addresses and phone numbers; it is the OTP store's
+// erasure.Eraser
+func (s *Service) Erase(ctx context.Context, subject erasure.Subject, _ *erasure.Anonymizer) (int, error) {
+	var recipients []Recipient
+	for _, email := range subject.Emails {
+		// An address that does not parse never received a code
+		if recipient, err := NewRecipient(ChannelEmail, email); err == nil {
+			recipients = append(recipients, recipient)
+		}
+	}
+	for _, phone := range subject.Phones {
+		recipients = append(recipients, Recipient{Channel: ChannelSMS, Address: phone.FormatCompact()})
+	}
+	if len(recipients) == 0 {
+		return 0, nil
+	}
+	keys := make([]string, len(recipients))
+	for i, recipient := range recipients {
+		keys[i] = s.recipientKey(recipient)
+	}
+	return s.store.erase(ctx, keys, recipients)
+}
+
+// send hands delivery to the sender of its channel
+func (s *Service) send(ctx context.Context, delivery Delivery) error {
+	sender, ok := s.senders[delivery.Channel]
+	if !ok {
+		return fmt.Errorf("no sender configured for channel %s", delivery.Channel)
+	}
+	return sender.Send(ctx, delivery)
+}
+
+// checkReachable rejects SMS recipients whose numbers are disconnected
+func (s *Service) checkReachable(ctx context.Context, recipient Recipient) error {
+	if s.phones == nil || recipient.Channel != ChannelSMS {
+		return nil
+	}
+	phone, err := i18n.NewPhoneFromString(recipient.Address)
+	if err != nil {
+		return err
+	}
+	_, err = phoneverify.RequireReachable(ctx, s.phones, *phone)
+	var errs validation.ValidationErrors
+	if err != nil && !errors.As(err, &errs) {
+		s.logger.Warn("Phone verification failed, sending anyway", zap.Error(err))
+		return nil
+	}
+	return err
+}
+
+// recipientKey identifies recipient in Redis keys: its channel and an HMAC
+// of its address, so the keys do not reveal who asked for codes
+func (s *Service) recipientKey(recipient Recipient) string {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte("recipient\x00" + recipient.key()))
+	return string(recipient.Channel) + ":" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// digest is the HMAC of a code bound to its purpose and recipient
+func (s *Service) digest(purpose string, recipient Recipient, code string) string {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(purpose + "\x00" + recipient.key() + "\x00" + code))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// generateCode returns length uniformly random decimal digits
+func generateCode(length int) (string, error) {
+	limit := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(length)), nil)
+	n, err := rand.Int(rand.Reader, limit)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate verification code: %w", err)
+	}
+	return fmt.Sprintf("%0*d", length, n), nil
+}
+
+// validatePurpose ensures purpose is a short lower-case identifier
+func validatePurpose(purpose string) error {
+	if !purposePattern.MatchString(purpose) {
+		var errs validation.ValidationErrors
+		errs.Add("purpose", validation.CodeInvalidFormat, "purpose must be 1-32 lower-case letters, digits or underscores", nil)
+		return errs.Err()
+	}
+	return nil
+}
+
+// codeError is a validation error on the code field
+func codeError(code, message string) error {
+	var errs validation.ValidationErrors
+	errs.Add("code", code, message, nil)
+	return errs.Err()
+}
@@ -0,0 +1,233 @@
+package otp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"golang-arch/internal/shared/fieldcrypt"
+)
+
+// Redis keys, all under the "otp:" prefix. Recipients appear as their
+// channel and an HMAC of the address (Service.recipientKey).
+const (
+	codeKeyPrefix     = "otp:code:"     // Hash {digest, attempts} per purpose and recipient
+	sendsKeyPrefix    = "otp:sends:"    // Codes issued to a recipient in the current window
+	cooldownKeyPrefix = "otp:cooldown:" // Present while a recipient must wait
+	outboxKey         = "otp:outbox"    // List of pending deliveries
+)
+
+// maxDeliveryAttempts bounds how often a failed delivery is retried
+const maxDeliveryAttempts = 3
+
+// attemptScript counts a verification attempt on an existing code and
+// returns the attempts so far with the stored digest
+var attemptScript = redis.NewScript(`
+if redis.call('EXISTS', KEYS[1]) == 0 then return false end
+local attempts = redis.call('HINCRBY', KEYS[1], 'attempts', 1)
+return {attempts, redis.call('HGET', KEYS[1], 'digest')}
+`)
+
+// countScript counts an event in a fixed window and returns the count and
+// the window's remaining milliseconds
+var countScript = redis.NewScript(`
+local count = redis.call('INCR', KEYS[1])
+if count == 1 then redis.call('PEXPIRE', KEYS[1], ARGV[1]) end
+return {count, redis.call('PTTL', KEYS[1])}
+`)
+
+// store keeps codes, rate-limit counters and the delivery outbox in Redis
+type store struct {
+	client *redis.Client
+	outbox *fieldcrypt.Field // Seals deliveries when set
+}
+
+func newStore(client *redis.Client) *store {
+	return &store{client: client}
+}
+
+// saveCode replaces the code for purpose and recipient, resetting attempts
+func (s *store) saveCode(ctx context.Context, purpose, recipient, digest string, ttl time.Duration) error {
+	key := codeKeyPrefix + purpose + ":" + recipient
+	_, err := s.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Del(ctx, key)
+		pipe.HSet(ctx, key, "digest", digest, "attempts", 0)
+		pipe.PExpire(ctx, key, ttl)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to store verification code: %w", err)
+	}
+	return nil
+}
+
+// attempt records a verification attempt; found is false when no code exists
+func (s *store) attempt(ctx context.Context, purpose, recipient string) (int, string, bool, error) {
+	result, err := attemptScript.Run(ctx, s.client, []string{codeKeyPrefix + purpose + ":" + recipient}).Slice()
+	if errors.Is(err, redis.Nil) {
+		return 0, "", false, nil
+	}
+	if err != nil {
+		return 0, "", false, fmt.Errorf("failed to read verification code: %w", err)
+	}
+	attempts, _ := result[0].(int64)
+	digest, _ := result[1].(string)
+	return int(attempts), digest, true, nil
+}
+
+// consumeCode deletes the code and reports whether this call deleted it
+func (s *store) consumeCode(ctx context.Context, purpose, recipient string) (bool, error) {
+	deleted, err := s.client.Del(ctx, codeKeyPrefix+purpose+":"+recipient).Result()
+	if err != nil {
+		return false, fmt.Errorf("failed to consume verification code: %w", err)
+	}
+	return deleted == 1, nil
+}
+
+// deleteCode invalidates the code
+func (s *store) deleteCode(ctx context.Context, purpose, recipient string) error {
+	_, err := s.consumeCode(ctx, purpose, recipient)
+	return err
+}
+
+// acquireCooldown starts the cooldown for recipient, or returns how long the
+// running one still lasts
+func (s *store) acquireCooldown(ctx context.Context, recipient string, cooldown time.Duration) (time.Duration, error) {
+	if cooldown <= 0 {
+		return 0, nil
+	}
+	key := cooldownKeyPrefix + recipient
+	acquired, err := s.client.SetNX(ctx, key, 1, cooldown).Result()
+	if err != nil {
+		return 0, fmt.Errorf("failed to check verification code cooldown: %w", err)
+	}
+	if acquired {
+		return 0, nil
+	}
+	remaining, err := s.client.PTTL(ctx, key).Result()
+	if err != nil {
+		return 0, fmt.Errorf("failed to check verification code cooldown: %w", err)
+	}
+	return max(remaining, time.Millisecond), nil
+}
+
+// countSend counts a code for recipient and returns how long until the
+// window resets when the limit is exceeded
+func (s *store) countSend(ctx context.Context, recipient string, limit int, window time.Duration) (time.Duration, error) {
+	if limit <= 0 {
+		return 0, nil
+	}
+	result, err := countScript.Run(ctx, s.client, []string{sendsKeyPrefix + recipient}, window.Milliseconds()).Slice()
+	if err != nil {
+		return 0, fmt.Errorf("failed to count verification codes: %w", err)
+	}
+	count, _ := result[0].(int64)
+	remaining, _ := result[1].(int64)
+	if int(count) <= limit {
+		return 0, nil
+	}
+	return max(time.Duration(remaining)*time.Millisecond, time.Millisecond), nil
+}
+
+// enqueue appends a delivery to the outbox
+func (s *store) enqueue(ctx context.Context, delivery Delivery) error {
+	var data []byte
+	var err error
+	if s.outbox != nil {
+		data, err = s.outbox.Seal(delivery)
+	} else {
+		data, err = json.Marshal(delivery)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to encode verification code delivery: %w", err)
+	}
+	if err := s.client.RPush(ctx, outboxKey, data).Err(); err != nil {
+		return fmt.Errorf("failed to queue verification code delivery: %w", err)
+	}
+	return nil
+}
+
+// dequeue pops the oldest delivery; found is false when the outbox is empty
+func (s *store) dequeue(ctx context.Context) (Delivery, bool, error) {
+	data, err := s.client.LPop(ctx, outboxKey).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return Delivery{}, false, nil
+	}
+	if err != nil {
+		return Delivery{}, false, fmt.Errorf("failed to read verification code outbox: %w", err)
+	}
+
+	delivery, err := s.decode(data)
+	if err != nil {
+		return Delivery{}, false, err
+	}
+	return delivery, true, nil
+}
+
+// decode reads an outbox entry. Deliveries queued before encryption was
+// enabled are plain JSON.
+func (s *store) decode(data []byte) (Delivery, error) {
+	var delivery Delivery
+	var err error
+	if len(data) > 0 && data[0] == '{' {
+		err = json.Unmarshal(data, &delivery)
+	} else if s.outbox != nil {
+		err = s.outbox.Open(data, &delivery)
+	} else {
+		err = fmt.Errorf("delivery is encrypted and encryption is disabled")
+	}
+	if err != nil {
+		return Delivery{}, fmt.Errorf("failed to decode verification code delivery: %w", err)
+	}
+	return delivery, nil
+}
+
+// erase deletes the codes, counters and queued deliveries of the
+// recipients, given by Redis key and by Recipient, and returns how many
+// entries it removed
+func (s *store) erase(ctx context.Context, keys []string, recipients []Recipient) (int, error) {
+	var doomed []string
+	for _, recipient := range keys {
+		doomed = append(doomed, sendsKeyPrefix+recipient, cooldownKeyPrefix+recipient)
+		iter := s.client.Scan(ctx, 0, codeKeyPrefix+"*:"+recipient, 100).Iterator()
+		for iter.Next(ctx) {
+			doomed = append(doomed, iter.Val())
+		}
+		if err := iter.Err(); err != nil {
+			return 0, fmt.Errorf("failed to find verification codes: %w", err)
+		}
+	}
+	removed := 0
+	if len(doomed) > 0 {
+		deleted, err := s.client.Del(ctx, doomed...).Result()
+		if err != nil {
+			return 0, fmt.Errorf("failed to delete verification codes: %w", err)
+		}
+		removed = int(deleted)
+	}
+
+	queued, err := s.client.LRange(ctx, outboxKey, 0, -1).Result()
+	if err != nil {
+		return removed, fmt.Errorf("failed to read verification code outbox: %w", err)
+	}
+	for _, entry := range queued {
+		delivery, err := s.decode([]byte(entry))
+		if err != nil {
+			return removed, err
+		}
+		if !slices.Contains(recipients, Recipient{Channel: delivery.Channel, Address: delivery.Address}) {
+			continue
+		}
+		deleted, err := s.client.LRem(ctx, outboxKey, 1, entry).Result()
+		if err != nil {
+			return removed, fmt.Errorf("failed to remove verification code delivery: %w", err)
+		}
+		removed += int(deleted)
+	}
+	return removed, nil
+}
@@ -0,0 +1,151 @@
+package phoneverify
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+
+	"go.uber.org/zap"
+
+	"golang-arch/internal/shared/cache"
+	i18n "golang-arch/internal/shared/domain/internationalization"
+	"golang-arch/internal/shared/erasure"
+)
+
+// CachingVerifier caches the results of another verifier in Redis. Only
+// results a provider was sure about are cached for the full TTL; absent and
+// unknown numbers may change soon and use the shorter retry TTL. Errors are
+// never cached.
+type CachingVerifier struct {
+	next     PhoneVerifier
+	cache    *cache.RedisCache
+	logger   *zap.Logger
+	secret   []byte
+	ttl      time.Duration
+	retryTTL time.Duration
+}
+
+// CacheOption customizes a CachingVerifier
+type CacheOption func(*CachingVerifier)
+
+// WithLogger sets the logger used to report cache failures
+func WithLogger(logger *zap.Logger) CacheOption {
+	return func(v *CachingVerifier) {
+		v.logger = logger
+	}
+}
+
+// WithSecret sets the HMAC key numbers are hashed with before they become
+// cache keys; every instance must share it
+func WithSecret(secret string) CacheOption {
+	return func(v *CachingVerifier) {
+		v.secret = []byte(secret)
+	}
+}
+
+// WithTTL sets how long reachable and disconnected results are kept
+func WithTTL(ttl time.Duration) CacheOption {
+	return func(v *CachingVerifier) {
+		v.ttl = ttl
+	}
+}
+
+// WithRetryTTL sets how long absent and unknown results are kept
+func WithRetryTTL(ttl time.Duration) CacheOption {
+	return func(v *CachingVerifier) {
+		v.retryTTL = ttl
+	}
+}
+
+// NewCachingVerifier wraps next with a Redis cache; the defaults keep
+// results for 24 hours and retry absent or unknown numbers after an hour.
+// Without WithSecret a random key is used, so results are only found again
+// by this instance.
+func NewCachingVerifier(next PhoneVerifier, rc *cache.RedisCache, options ...CacheOption) *CachingVerifier {
+	v := &CachingVerifier{
+		next:     next,
+		cache:    rc,
+		logger:   zap.NewNop(),
+		ttl:      24 * time.Hour,
+		retryTTL: time.Hour,
+	}
+	for _, option := range options {
+		option(v)
+	}
+	if len(v.secret) == 0 {
+		v.secret = make([]byte, 32)
+		_, _ = rand.Read(v.secret)
+	}
+	return v
+}
+
+// Name returns the name of the wrapped verifier
+func (v *CachingVerifier) Name() string {
+	return v.next.Name()
+}
+
+// Verify returns the cached result for phone, or asks the wrapped verifier.
+// Cache failures are logged and fall through to the provider.
+func (v *CachingVerifier) Verify(ctx context.Context, phone i18n.Phone) (Result, error) {
+	key := v.key(phone)
+
+	var cached Result
+	found, err := v.cache.Get(ctx, key, &cached)
+	if err != nil {
+		v.logger.Warn("Failed to read cached phone verification", zap.Error(err))
+	}
+	if found && err == nil {
+		return cached, nil
+	}
+
+	result, err := v.next.Verify(ctx, phone)
+	if err != nil {
+		return Result{}, err
+	}
+
+	ttl := v.ttl
+	if result.Status == StatusAbsent || result.Status == StatusUnknown {
+		ttl = v.retryTTL
+	}
+	if err := v.cache.Set(ctx, key, result, ttl); err != nil {
+		v.logger.Warn("Failed to cache phone verification", zap.Error(err))
+	}
+	return result, nil
+}
+
+// Forget drops the cached result for phone, e.g. after a user reports that a
+// number works again
+func (v *CachingVerifier) Forget(ctx context.Context, phone i18n.Phone) error {
+	return v.cache.Delete(ctx, v.key(phone))
+}
+
+// Erase drops the cached results for the subject's phone numbers and
+// returns how many there were. It is the phone_verifications eraser.
+func (v *CachingVerifier) Erase(ctx context.Context, subject erasure.Subject, _ *erasure.Anonymizer) (int, error) {
+	erased := 0
+	for _, phone := range subject.Phones {
+		var cached Result
+		found, err := v.cache.Get(ctx, v.key(phone), &cached)
+		if err != nil {
+			return erased, err
+		}
+		if !found {
+			continue
+		}
+		if err := v.cache.Delete(ctx, v.key(phone)); err != nil {
+			return erased, err
+		}
+		erased++
+	}
+	return erased, nil
+}
+
+// key is the cache key of phone, an HMAC so Redis never holds the number
+func (v *CachingVerifier) key(phone i18n.Phone) string {
+	mac := hmac.New(sha256.New, v.secret)
+	mac.Write([]byte("phone\x00" + phone.FormatCompact()))
+	return hex.EncodeToString(mac.Sum(nil))
+}
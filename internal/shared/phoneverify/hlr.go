@@ -0,0 +1,119 @@
+package phoneverify
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	i18n "golang-arch/internal/shared/domain/internationalization"
+	"golang-arch/pkg/clock"
+)
+
+// HLRLookupsVerifier queries the home location register of a number through
+// the hlr-lookups.com v2 API, which reports whether it is connected to its
+// network right now
+type HLRLookupsVerifier struct {
+	baseURL    string
+	key        string
+	secret     string
+	clock      clock.Clock
+	httpClient *http.Client
+}
+
+// NewHLRLookupsVerifier creates a verifier for the API at baseURL, e.g.
+// "https://www.hlr-lookups.com/api/v2"
+func NewHLRLookupsVerifier(baseURL, key, secret string, clk clock.Clock) *HLRLookupsVerifier {
+	return &HLRLookupsVerifier{
+		baseURL:    strings.TrimSuffix(baseURL, "/"),
+		key:        key,
+		secret:     secret,
+		clock:      clk,
+		httpClient: &http.Client{Timeout: 15 * time.Second},
+	}
+}
+
+// hlrResponse is the subset of an HLR lookup response used here
+type hlrResponse struct {
+	ConnectivityStatus  string `json:"connectivity_status"`
+	OriginalNetworkName string `json:"original_network_name"`
+	PortedNetworkName   string `json:"ported_network_name"`
+	IsPorted            bool   `json:"is_ported"`
+}
+
+// hlrStatuses maps HLR connectivity to reachability
+var hlrStatuses = map[string]Status{
+	"CONNECTED":      StatusReachable,
+	"ABSENT":         StatusAbsent,
+	"INVALID_MSISDN": StatusDisconnected,
+	"UNDETERMINED":   StatusUnknown,
+}
+
+// Name returns "hlrlookups"
+func (v *HLRLookupsVerifier) Name() string {
+	return "hlrlookups"
+}
+
+// Verify runs a synchronous HLR lookup. HLR only covers mobile numbers, so
+// every result has LineMobile or, when undetermined, LineUnknown.
+func (v *HLRLookupsVerifier) Verify(ctx context.Context, phone i18n.Phone) (Result, error) {
+	number := phone.FormatCompact()
+	payload, err := json.Marshal(map[string]string{"msisdn": number})
+	if err != nil {
+		return Result{}, fmt.Errorf("failed to encode HLR request: %w", err)
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, v.baseURL+"/hlr-lookup", bytes.NewReader(payload))
+	if err != nil {
+		return Result{}, fmt.Errorf("failed to build HLR request: %w", err)
+	}
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("X-Basic", v.basicDigest())
+
+	response, err := v.httpClient.Do(request)
+	if err != nil {
+		return Result{}, fmt.Errorf("failed to call HLR lookup: %w", err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return Result{}, fmt.Errorf("HLR lookup returned status %d", response.StatusCode)
+	}
+
+	var decoded hlrResponse
+	if err := json.NewDecoder(response.Body).Decode(&decoded); err != nil {
+		return Result{}, fmt.Errorf("failed to decode HLR response: %w", err)
+	}
+
+	status, ok := hlrStatuses[decoded.ConnectivityStatus]
+	if !ok {
+		status = StatusUnknown
+	}
+	result := Result{
+		Phone:     number,
+		Status:    status,
+		LineType:  LineMobile,
+		Carrier:   decoded.OriginalNetworkName,
+		Ported:    decoded.IsPorted,
+		Provider:  v.Name(),
+		CheckedAt: v.clock.Now(),
+	}
+	if decoded.IsPorted && decoded.PortedNetworkName != "" {
+		result.Carrier = decoded.PortedNetworkName
+	}
+	if status == StatusUnknown || status == StatusDisconnected {
+		result.LineType = LineUnknown
+	}
+	return result, nil
+}
+
+// basicDigest is the SHA-256 of "key:secret" the API expects in X-Basic
+func (v *HLRLookupsVerifier) basicDigest() string {
+	sum := sha256.Sum256([]byte(v.key + ":" + v.secret))
+	return hex.EncodeToString(sum[:])
+}
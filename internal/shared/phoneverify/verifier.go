@@ -0,0 +1,141 @@
+// Package phoneverify checks whether phone numbers are in service before
+// the application spends an SMS or call on them. A PhoneVerifier asks a
+// number-lookup provider (an HLR lookup in production, a static table in dev
+// and tests); CachingVerifier keeps results in Redis so repeated checks of the
+// same number cost nothing, and RequireReachable turns a disconnected number
+// into a validation error for signup and OTP flows.
+package phoneverify
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"golang-arch/internal/shared/config"
+	i18n "golang-arch/internal/shared/domain/internationalization"
+	"golang-arch/internal/shared/domain/validation"
+	"golang-arch/pkg/clock"
+)
+
+// Status is the reachability of a phone number
+type Status string
+
+// Reachability statuses
+const (
+	StatusReachable    Status = "reachable"    // Connected to a network
+	StatusAbsent       Status = "absent"       // In service but temporarily unreachable, e.g. switched off
+	StatusDisconnected Status = "disconnected" // Not assigned or no longer in service
+	StatusUnknown      Status = "unknown"      // The provider could not tell
+)
+
+// LineType is the kind of line a number belongs to
+type LineType string
+
+// Line types
+const (
+	LineMobile   LineType = "mobile"
+	LineLandline LineType = "landline"
+	LineVoIP     LineType = "voip"
+	LineUnknown  LineType = ""
+)
+
+// Result is what a lookup found out about a number
+type Result struct {
+	Phone     string    `json:"phone"`     // E.164, e.g. "+4917612345678"
+	Status    Status    `json:"status"`    // Reachability
+	LineType  LineType  `json:"line_type"` // Mobile, landline or VoIP when known
+	Carrier   string    `json:"carrier"`   // Current network, after porting
+	Ported    bool      `json:"ported"`    // Moved away from the original network
+	Provider  string    `json:"provider"`  // Verifier that produced the result
+	CheckedAt time.Time `json:"checked_at"`
+}
+
+// Deliverable reports whether messages to the number can be expected to
+// arrive; absent numbers are deliverable once they reconnect
+func (r Result) Deliverable() bool {
+	return r.Status != StatusDisconnected
+}
+
+// PhoneVerifier looks up the reachability of phone numbers
+type PhoneVerifier interface {
+	Name() string
+	Verify(ctx context.Context, phone i18n.Phone) (Result, error)
+}
+
+// StaticVerifier reports numbers from a fixed table, for dev and tests;
+// numbers not in the table are reachable
+type StaticVerifier struct {
+	statuses map[string]Status
+	clock    clock.Clock
+}
+
+// NewStaticVerifier creates a verifier from statuses keyed by E.164 number,
+// e.g. {"+15550000000": "disconnected"}
+func NewStaticVerifier(statuses map[string]string, clk clock.Clock) (*StaticVerifier, error) {
+	v := &StaticVerifier{statuses: make(map[string]Status, len(statuses)), clock: clk}
+	for number, value := range statuses {
+		phone, err := i18n.NewPhoneFromString(number)
+		if err != nil {
+			return nil, fmt.Errorf("invalid static phone %s: %w", number, err)
+		}
+		status := Status(strings.ToLower(value))
+		switch status {
+		case StatusReachable, StatusAbsent, StatusDisconnected, StatusUnknown:
+		default:
+			return nil, fmt.Errorf("invalid static phone status %q for %s", value, number)
+		}
+		v.statuses[phone.FormatCompact()] = status
+	}
+	return v, nil
+}
+
+// Name returns "static"
+func (v *StaticVerifier) Name() string {
+	return "static"
+}
+
+// Verify returns the configured status of phone
+func (v *StaticVerifier) Verify(_ context.Context, phone i18n.Phone) (Result, error) {
+	number := phone.FormatCompact()
+	status, ok := v.statuses[number]
+	if !ok {
+		status = StatusReachable
+	}
+	return Result{Phone: number, Status: status, Provider: v.Name(), CheckedAt: v.clock.Now()}, nil
+}
+
+// RequireReachable verifies phone and returns a validation error on field
+// "phone" when it is disconnected. Absent and unknown numbers pass, so a
+// provider that cannot tell never blocks a user; provider failures are
+// returned as they are for the caller to decide.
+func RequireReachable(ctx context.Context, verifier PhoneVerifier, phone i18n.Phone) (Result, error) {
+	if err := phone.Validate(); err != nil {
+		return Result{}, err
+	}
+	result, err := verifier.Verify(ctx, phone)
+	if err != nil {
+		return Result{}, fmt.Errorf("failed to verify phone with %s: %w", verifier.Name(), err)
+	}
+	if !result.Deliverable() {
+		var errs validation.ValidationErrors
+		errs.Add("phone", validation.CodeInvalid, "phone number is not in service", map[string]any{"status": string(result.Status)})
+		return result, errs.Err()
+	}
+	return result, nil
+}
+
+// NewVerifier builds the verifier selected by cfg.Provider
+func NewVerifier(cfg config.PhoneVerifyConfig, clk clock.Clock) (PhoneVerifier, error) {
+	switch cfg.Provider {
+	case "", "static":
+		return NewStaticVerifier(cfg.Static, clk)
+	case "hlrlookups":
+		if cfg.APIKey == "" || cfg.APISecret == "" {
+			return nil, fmt.Errorf("phone verify provider hlrlookups requires api_key and api_secret")
+		}
+		return NewHLRLookupsVerifier(cfg.URL, cfg.APIKey, cfg.APISecret, clk), nil
+	default:
+		return nil, fmt.Errorf("unknown phone verify provider %q", cfg.Provider)
+	}
+}
@@ -0,0 +1,236 @@
+package projection
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"golang-arch/pkg/clock"
+)
+
+// Checkpoint is how far a projection got
+type Checkpoint struct {
+	Name string `json:"name"`
+	// Position is the journal position of the last processed event
+	Position int64 `json:"position"`
+	// Rebuild is set when a rebuild was requested and not started yet
+	Rebuild   bool      `json:"rebuild"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Checkpoints stores projection checkpoints
+type Checkpoints interface {
+	// Get returns a checkpoint; a projection without one starts at 0
+	Get(ctx context.Context, name string) (Checkpoint, error)
+	// List returns every stored checkpoint, by name
+	List(ctx context.Context) ([]Checkpoint, error)
+	// Advance moves a checkpoint to position, keeping its rebuild request
+	Advance(ctx context.Context, name string, position int64) error
+	// RequestRebuild flags a projection for a rebuild by the next run
+	RequestRebuild(ctx context.Context, name string) error
+	// Restart moves a checkpoint back to 0 and clears its rebuild request
+	Restart(ctx context.Context, name string) error
+	// TryLock takes the projection's lock unless another runner holds it;
+	// release gives it back
+	TryLock(ctx context.Context, name string) (release func(), ok bool, err error)
+}
+
+// PostgresCheckpoints keeps checkpoints in the projection_checkpoints table;
+// locks are session-level advisory locks
+type PostgresCheckpoints struct {
+	db *sql.DB
+}
+
+// NewPostgresCheckpoints creates a checkpoint store on db
+func NewPostgresCheckpoints(db *sql.DB) *PostgresCheckpoints {
+	return &PostgresCheckpoints{db: db}
+}
+
+// Get selects a checkpoint
+func (c *PostgresCheckpoints) Get(ctx context.Context, name string) (Checkpoint, error) {
+	checkpoint := Checkpoint{Name: name}
+	err := c.db.QueryRowContext(ctx,
+		`SELECT position, rebuild, updated_at FROM projection_checkpoints WHERE name = $1`, name).
+		Scan(&checkpoint.Position, &checkpoint.Rebuild, &checkpoint.UpdatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return checkpoint, nil
+	}
+	if err != nil {
+		return Checkpoint{}, fmt.Errorf("failed to read checkpoint of %s: %w", name, err)
+	}
+	return checkpoint, nil
+}
+
+// List selects every checkpoint
+func (c *PostgresCheckpoints) List(ctx context.Context) ([]Checkpoint, error) {
+	rows, err := c.db.QueryContext(ctx,
+		`SELECT name, position, rebuild, updated_at FROM projection_checkpoints ORDER BY name`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list checkpoints: %w", err)
+	}
+	defer rows.Close()
+
+	var checkpoints []Checkpoint
+	for rows.Next() {
+		var checkpoint Checkpoint
+		if err := rows.Scan(&checkpoint.Name, &checkpoint.Position, &checkpoint.Rebuild, &checkpoint.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to read checkpoint: %w", err)
+		}
+		checkpoints = append(checkpoints, checkpoint)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list checkpoints: %w", err)
+	}
+	return checkpoints, nil
+}
+
+// Advance upserts the position
+func (c *PostgresCheckpoints) Advance(ctx context.Context, name string, position int64) error {
+	_, err := c.db.ExecContext(ctx,
+		`INSERT INTO projection_checkpoints (name, position, updated_at) VALUES ($1, $2, NOW())
+		ON CONFLICT (name) DO UPDATE SET position = EXCLUDED.position, updated_at = EXCLUDED.updated_at`,
+		name, position)
+	if err != nil {
+		return fmt.Errorf("failed to save checkpoint of %s: %w", name, err)
+	}
+	return nil
+}
+
+// RequestRebuild upserts the rebuild flag
+func (c *PostgresCheckpoints) RequestRebuild(ctx context.Context, name string) error {
+	_, err := c.db.ExecContext(ctx,
+		`INSERT INTO projection_checkpoints (name, rebuild, updated_at) VALUES ($1, TRUE, NOW())
+		ON CONFLICT (name) DO UPDATE SET rebuild = TRUE, updated_at = EXCLUDED.updated_at`,
+		name)
+	if err != nil {
+		return fmt.Errorf("failed to request a rebuild of %s: %w", name, err)
+	}
+	return nil
+}
+
+// Restart resets the position and the flag
+func (c *PostgresCheckpoints) Restart(ctx context.Context, name string) error {
+	_, err := c.db.ExecContext(ctx,
+		`INSERT INTO projection_checkpoints (name, position, rebuild, updated_at) VALUES ($1, 0, FALSE, NOW())
+		ON CONFLICT (name) DO UPDATE SET position = 0, rebuild = FALSE, updated_at = EXCLUDED.updated_at`,
+		name)
+	if err != nil {
+		return fmt.Errorf("failed to restart %s: %w", name, err)
+	}
+	return nil
+}
+
+// TryLock takes an advisory lock on a dedicated connection, which is
+// returned to the pool on release
+func (c *PostgresCheckpoints) TryLock(ctx context.Context, name string) (func(), bool, error) {
+	conn, err := c.db.Conn(ctx)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to lock projection %s: %w", name, err)
+	}
+	var locked bool
+	if err := conn.QueryRowContext(ctx, `SELECT pg_try_advisory_lock(hashtext($1))`, "projection:"+name).Scan(&locked); err != nil {
+		conn.Close()
+		return nil, false, fmt.Errorf("failed to lock projection %s: %w", name, err)
+	}
+	if !locked {
+		conn.Close()
+		return nil, false, nil
+	}
+	return func() {
+		_, _ = conn.ExecContext(context.WithoutCancel(ctx), `SELECT pg_advisory_unlock(hashtext($1))`, "projection:"+name)
+		conn.Close()
+	}, true, nil
+}
+
+// MemoryCheckpoints keeps checkpoints in memory, for tests and the dev
+// profile
+type MemoryCheckpoints struct {
+	clock clock.Clock
+
+	mu          sync.Mutex
+	checkpoints map[string]Checkpoint
+	locked      map[string]bool
+}
+
+// NewMemoryCheckpoints creates an empty in-memory checkpoint store
+func NewMemoryCheckpoints(clk clock.Clock) *MemoryCheckpoints {
+	return &MemoryCheckpoints{
+		clock:       clk,
+		checkpoints: make(map[string]Checkpoint),
+		locked:      make(map[string]bool),
+	}
+}
+
+// Get returns a checkpoint
+func (c *MemoryCheckpoints) Get(_ context.Context, name string) (Checkpoint, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	checkpoint, ok := c.checkpoints[name]
+	if !ok {
+		checkpoint.Name = name
+	}
+	return checkpoint, nil
+}
+
+// List returns every checkpoint
+func (c *MemoryCheckpoints) List(_ context.Context) ([]Checkpoint, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	checkpoints := make([]Checkpoint, 0, len(c.checkpoints))
+	for _, checkpoint := range c.checkpoints {
+		checkpoints = append(checkpoints, checkpoint)
+	}
+	sort.Slice(checkpoints, func(i, j int) bool { return checkpoints[i].Name < checkpoints[j].Name })
+	return checkpoints, nil
+}
+
+// Advance moves the position
+func (c *MemoryCheckpoints) Advance(_ context.Context, name string, position int64) error {
+	c.update(name, func(checkpoint *Checkpoint) { checkpoint.Position = position })
+	return nil
+}
+
+// RequestRebuild sets the flag
+func (c *MemoryCheckpoints) RequestRebuild(_ context.Context, name string) error {
+	c.update(name, func(checkpoint *Checkpoint) { checkpoint.Rebuild = true })
+	return nil
+}
+
+// Restart resets the position and the flag
+func (c *MemoryCheckpoints) Restart(_ context.Context, name string) error {
+	c.update(name, func(checkpoint *Checkpoint) { checkpoint.Position, checkpoint.Rebuild = 0, false })
+	return nil
+}
+
+// TryLock takes the in-process lock
+func (c *MemoryCheckpoints) TryLock(_ context.Context, name string) (func(), bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.locked[name] {
+		return nil, false, nil
+	}
+	c.locked[name] = true
+	return func() {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		delete(c.locked, name)
+	}, true, nil
+}
+
+func (c *MemoryCheckpoints) update(name string, change func(*Checkpoint)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	checkpoint := c.checkpoints[name]
+	checkpoint.Name = name
+	change(&checkpoint)
+	checkpoint.UpdatedAt = c.clock.Now().UTC()
+	c.checkpoints[name] = checkpoint
+}
@@ -0,0 +1,287 @@
+// Package projection maintains denormalized read models from the events of
+// event-sourced aggregates, the query side of the architecture's CQRS
+// direction.
+//
+// A Projection handles the journal's events in append order and writes its
+// read tables. The Runner, run by the worker's projections job, feeds each
+// projection the events after its checkpoint and advances the checkpoint
+// after every batch, so a crashed run repeats at most one batch; handlers
+// must tolerate seeing an event twice, e.g. by upserting.
+package projection
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"sync"
+
+	"go.uber.org/zap"
+
+	"golang-arch/internal/shared/domain/domainerror"
+	"golang-arch/internal/shared/events"
+	"golang-arch/internal/shared/eventsource"
+	"golang-arch/pkg/metrics"
+)
+
+// DefaultBatchSize is the number of events read at a time
+const DefaultBatchSize = 500
+
+// Projection is a read model built from events
+type Projection struct {
+	// Name identifies the projection's checkpoint; keep it stable
+	Name string
+	// Events lists the event names the projection handles; nil handles all
+	Events []string
+	// Handle applies one event to the read model
+	Handle func(ctx context.Context, event events.Event, record eventsource.Record) error
+	// Reset empties the read model before a rebuild
+	Reset func(ctx context.Context) error
+}
+
+// Status is how far a projection got
+type Status struct {
+	Checkpoint
+	Head       int64 `json:"head"`       // Position of the latest stored event
+	Lag        int64 `json:"lag"`        // Events not processed yet
+	Registered bool  `json:"registered"` // Whether this process runs the projection
+}
+
+// Option configures a Runner
+type Option func(*Runner)
+
+// WithLogger sets the logger
+func WithLogger(logger *zap.Logger) Option {
+	return func(r *Runner) {
+		r.logger = logger
+	}
+}
+
+// WithLagGauge reports each projection's lag on gauge, labeled with
+// "projection"
+func WithLagGauge(gauge *metrics.Gauge) Option {
+	return func(r *Runner) {
+		r.lag = gauge
+	}
+}
+
+// WithBatchSize sets the number of events read at a time
+func WithBatchSize(size int) Option {
+	return func(r *Runner) {
+		if size > 0 {
+			r.batchSize = size
+		}
+	}
+}
+
+// Runner feeds stored events to projections
+type Runner struct {
+	journal     eventsource.Store
+	schemas     *events.Registry
+	checkpoints Checkpoints
+	logger      *zap.Logger
+	lag         *metrics.Gauge
+	batchSize   int
+
+	mu          sync.RWMutex
+	projections []Projection
+}
+
+// NewRunner creates a runner reading journal, decoding events through
+// schemas and keeping positions in checkpoints
+func NewRunner(journal eventsource.Store, schemas *events.Registry, checkpoints Checkpoints, options ...Option) *Runner {
+	r := &Runner{
+		journal:     journal,
+		schemas:     schemas,
+		checkpoints: checkpoints,
+		logger:      zap.NewNop(),
+		batchSize:   DefaultBatchSize,
+	}
+	for _, option := range options {
+		option(r)
+	}
+	return r
+}
+
+// Register adds a projection. It panics on a duplicate or incomplete
+// projection, a programming error.
+func (r *Runner) Register(projection Projection) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if projection.Name == "" || projection.Handle == nil || projection.Reset == nil {
+		panic("projection: needs a name, Handle and Reset")
+	}
+	for _, registered := range r.projections {
+		if registered.Name == projection.Name {
+			panic(fmt.Sprintf("projection: %s registered twice", projection.Name))
+		}
+	}
+	r.projections = append(r.projections, projection)
+}
+
+// Run brings every registered projection up to the latest stored event. It
+// is the projections worker job. A projection another runner is working on
+// is skipped; a failing one stops at the failed event and is retried by the
+// next run, while the others go on.
+func (r *Runner) Run(ctx context.Context) error {
+	var errs []error
+	for _, projection := range r.registered() {
+		if err := r.run(ctx, projection); err != nil {
+			errs = append(errs, fmt.Errorf("projection %s: %w", projection.Name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Rebuild requests that a projection is emptied and rebuilt from the first
+// event; the next run does it. The projection must be registered here or
+// have a checkpoint.
+func (r *Runner) Rebuild(ctx context.Context, name string) error {
+	if _, ok := r.projection(name); !ok {
+		checkpoints, err := r.checkpoints.List(ctx)
+		if err != nil {
+			return err
+		}
+		if !slices.ContainsFunc(checkpoints, func(c Checkpoint) bool { return c.Name == name }) {
+			return domainerror.NotFoundf("projection %s not found", name)
+		}
+	}
+	return r.checkpoints.RequestRebuild(ctx, name)
+}
+
+// Status reports the registered projections and those with a checkpoint
+func (r *Runner) Status(ctx context.Context) ([]Status, error) {
+	head, err := r.journal.Head(ctx)
+	if err != nil {
+		return nil, err
+	}
+	checkpoints, err := r.checkpoints.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	byName := make(map[string]*Status, len(checkpoints))
+	for _, checkpoint := range checkpoints {
+		byName[checkpoint.Name] = &Status{Checkpoint: checkpoint}
+	}
+	for _, projection := range r.registered() {
+		status, ok := byName[projection.Name]
+		if !ok {
+			status = &Status{Checkpoint: Checkpoint{Name: projection.Name}}
+			byName[projection.Name] = status
+		}
+		status.Registered = true
+	}
+
+	statuses := make([]Status, 0, len(byName))
+	for _, status := range byName {
+		status.Head = head
+		status.Lag = max(head-status.Position, 0)
+		statuses = append(statuses, *status)
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Name < statuses[j].Name })
+	return statuses, nil
+}
+
+// run catches one projection up under its lock
+func (r *Runner) run(ctx context.Context, projection Projection) error {
+	release, ok, err := r.checkpoints.TryLock(ctx, projection.Name)
+	if err != nil || !ok {
+		return err
+	}
+	defer release()
+
+	checkpoint, err := r.checkpoints.Get(ctx, projection.Name)
+	if err != nil {
+		return err
+	}
+	if checkpoint.Rebuild {
+		r.logger.Info("Rebuilding projection", zap.String("projection", projection.Name))
+		if err := projection.Reset(ctx); err != nil {
+			return fmt.Errorf("failed to reset: %w", err)
+		}
+		if err := r.checkpoints.Restart(ctx, projection.Name); err != nil {
+			return err
+		}
+		checkpoint.Position = 0
+	}
+
+	position := checkpoint.Position
+	defer func() { r.reportLag(ctx, projection.Name, position) }()
+	for ctx.Err() == nil {
+		records, err := r.journal.ReadAll(ctx, position, r.batchSize)
+		if err != nil {
+			return err
+		}
+		if len(records) == 0 {
+			return nil
+		}
+
+		handled, handleErr := r.handle(ctx, projection, records)
+		if handled > position {
+			position = handled
+			if err := r.checkpoints.Advance(ctx, projection.Name, position); err != nil {
+				return err
+			}
+		}
+		if handleErr != nil {
+			return handleErr
+		}
+		if len(records) < r.batchSize {
+			return nil
+		}
+	}
+	return ctx.Err()
+}
+
+// handle applies records in order and returns the position of the last one
+// handled, stopping at the first failure
+func (r *Runner) handle(ctx context.Context, projection Projection, records []eventsource.Record) (int64, error) {
+	var position int64
+	for _, record := range records {
+		if projection.Events == nil || slices.Contains(projection.Events, record.Envelope.Name) {
+			event, err := r.schemas.Decode(record.Envelope)
+			if err != nil {
+				return position, fmt.Errorf("event at position %d: %w", record.Position, err)
+			}
+			if err := projection.Handle(ctx, event, record); err != nil {
+				return position, fmt.Errorf("event at position %d: %w", record.Position, err)
+			}
+		}
+		position = record.Position
+	}
+	return position, nil
+}
+
+// reportLag sets the lag gauge for a projection at position
+func (r *Runner) reportLag(ctx context.Context, name string, position int64) {
+	if r.lag == nil {
+		return
+	}
+	head, err := r.journal.Head(ctx)
+	if err != nil {
+		r.logger.Warn("Failed to measure projection lag", zap.String("projection", name), zap.Error(err))
+		return
+	}
+	r.lag.Set(float64(max(head-position, 0)), metrics.Labels{"projection": name})
+}
+
+// registered returns a copy of the registered projections
+func (r *Runner) registered() []Projection {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return append([]Projection(nil), r.projections...)
+}
+
+// projection returns the registered projection named name
+func (r *Runner) projection(name string) (Projection, bool) {
+	for _, projection := range r.registered() {
+		if projection.Name == name {
+			return projection, true
+		}
+	}
+	return Projection{}, false
+}
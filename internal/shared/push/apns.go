@@ -0,0 +1,179 @@
+package push
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"golang-arch/pkg/clock"
+)
+
+// APNs base URLs
+const (
+	APNsProductionURL = "https://api.push.apple.com"
+	APNsSandboxURL    = "https://api.sandbox.push.apple.com"
+)
+
+// apnsTokenLifetime is how long a provider token is reused. APNs rejects
+// tokens older than an hour and refreshes more often than every 20 minutes.
+const apnsTokenLifetime = 50 * time.Minute
+
+// apnsInvalidReasons are the rejections that mean the token will never work
+var apnsInvalidReasons = map[string]bool{
+	"BadDeviceToken":         true,
+	"DeviceTokenNotForTopic": true,
+	"Unregistered":           true,
+}
+
+// APNsSender sends through the Apple Push Notification service with a
+// token-based (.p8) provider key
+type APNsSender struct {
+	baseURL    string
+	key        *ecdsa.PrivateKey
+	keyID      string
+	teamID     string
+	topic      string
+	clock      clock.Clock
+	httpClient *http.Client
+
+	mu       sync.Mutex
+	token    string
+	issuedAt time.Time
+}
+
+// NewAPNsSender creates a sender for the API at baseURL, e.g.
+// APNsProductionURL, from the PEM-encoded .p8 signing key, its key ID, the
+// developer team ID and the app's bundle ID as topic
+func NewAPNsSender(baseURL string, signingKey []byte, keyID, teamID, topic string, clk clock.Clock) (*APNsSender, error) {
+	if keyID == "" || teamID == "" || topic == "" {
+		return nil, errors.New("APNs needs a key ID, team ID and topic")
+	}
+	block, _ := pem.Decode(signingKey)
+	if block == nil {
+		return nil, errors.New("invalid APNs signing key: no PEM block")
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("invalid APNs signing key: %w", err)
+	}
+	key, ok := parsed.(*ecdsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("invalid APNs signing key: not an ECDSA key")
+	}
+	return &APNsSender{
+		baseURL:    strings.TrimSuffix(baseURL, "/"),
+		key:        key,
+		keyID:      keyID,
+		teamID:     teamID,
+		topic:      topic,
+		clock:      clk,
+		httpClient: &http.Client{Timeout: 15 * time.Second},
+	}, nil
+}
+
+// Name returns "apns"
+func (s *APNsSender) Name() string {
+	return "apns"
+}
+
+// apnsAlert is the visible part of a notification
+type apnsAlert struct {
+	Title string `json:"title"`
+	Body  string `json:"body"`
+}
+
+// Send posts an alert to the device token, with the payload's data as
+// custom keys beside "aps". A 410 answer, or a 400 naming a bad or foreign
+// token, is ErrInvalidToken.
+func (s *APNsSender) Send(ctx context.Context, device Device, payload Payload) error {
+	message := make(map[string]any, len(payload.Data)+1)
+	for key, value := range payload.Data {
+		message[key] = value
+	}
+	message["aps"] = map[string]any{
+		"alert": apnsAlert{Title: payload.Title, Body: payload.Body},
+		"sound": "default",
+	}
+	body, err := json.Marshal(message)
+	if err != nil {
+		return fmt.Errorf("failed to encode APNs notification: %w", err)
+	}
+
+	token, err := s.providerToken()
+	if err != nil {
+		return err
+	}
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, s.baseURL+"/3/device/"+url.PathEscape(device.Token), bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to build APNs request: %w", err)
+	}
+	request.Header.Set("Authorization", "bearer "+token)
+	request.Header.Set("apns-topic", s.topic)
+	request.Header.Set("apns-push-type", "alert")
+	request.Header.Set("apns-priority", "10")
+
+	response, err := s.httpClient.Do(request)
+	if err != nil {
+		return fmt.Errorf("failed to call APNs: %w", err)
+	}
+	defer response.Body.Close()
+	if response.StatusCode == http.StatusOK {
+		return nil
+	}
+
+	var decoded struct {
+		Reason string `json:"reason"`
+	}
+	_ = json.NewDecoder(response.Body).Decode(&decoded)
+	if response.StatusCode == http.StatusGone || apnsInvalidReasons[decoded.Reason] {
+		return fmt.Errorf("%w: APNs answered %s", ErrInvalidToken, decoded.Reason)
+	}
+	if decoded.Reason == "ExpiredProviderToken" {
+		s.mu.Lock()
+		s.token = ""
+		s.mu.Unlock()
+	}
+	return fmt.Errorf("APNs returned status %d %s", response.StatusCode, decoded.Reason)
+}
+
+// providerToken returns the cached ES256 provider token, signing a new one
+// once it is apnsTokenLifetime old
+func (s *APNsSender) providerToken() (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.clock.Now()
+	if s.token != "" && now.Sub(s.issuedAt) < apnsTokenLifetime {
+		return s.token, nil
+	}
+
+	token, err := signJWT(map[string]string{"alg": "ES256", "kid": s.keyID}, map[string]any{
+		"iss": s.teamID,
+		"iat": now.Unix(),
+	}, func(digest []byte) ([]byte, error) {
+		r, sig, err := ecdsa.Sign(rand.Reader, s.key, digest)
+		if err != nil {
+			return nil, err
+		}
+		// JWS wants the fixed-size r || s form, not ASN.1
+		signature := make([]byte, 64)
+		r.FillBytes(signature[:32])
+		sig.FillBytes(signature[32:])
+		return signature, nil
+	})
+	if err != nil {
+		return "", err
+	}
+	s.token, s.issuedAt = token, now
+	return token, nil
+}
@@ -0,0 +1,246 @@
+package push
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"golang-arch/pkg/clock"
+)
+
+// FCMScope is the OAuth scope of the FCM HTTP v1 API
+const FCMScope = "https://www.googleapis.com/auth/firebase.messaging"
+
+// DefaultFCMURL is the base URL of the FCM HTTP v1 API
+const DefaultFCMURL = "https://fcm.googleapis.com"
+
+// ServiceAccount is the subset of a Google service account key file used
+// to sign in to FCM
+type ServiceAccount struct {
+	ProjectID   string `json:"project_id"`
+	ClientEmail string `json:"client_email"`
+	PrivateKey  string `json:"private_key"` // PEM-encoded PKCS #8 RSA key
+	TokenURI    string `json:"token_uri"`
+}
+
+// FCMSender sends through the Firebase Cloud Messaging HTTP v1 API,
+// signing in with a service account
+type FCMSender struct {
+	baseURL    string
+	account    ServiceAccount
+	key        *rsa.PrivateKey
+	clock      clock.Clock
+	httpClient *http.Client
+
+	mu          sync.Mutex
+	accessToken string
+	expiresAt   time.Time
+}
+
+// NewFCMSender creates a sender for the API at baseURL, e.g. DefaultFCMURL,
+// from the JSON key file of a service account
+func NewFCMSender(baseURL string, credentials []byte, clk clock.Clock) (*FCMSender, error) {
+	var account ServiceAccount
+	if err := json.Unmarshal(credentials, &account); err != nil {
+		return nil, fmt.Errorf("invalid FCM service account: %w", err)
+	}
+	if account.ProjectID == "" || account.ClientEmail == "" || account.TokenURI == "" {
+		return nil, errors.New("FCM service account needs project_id, client_email and token_uri")
+	}
+	key, err := parseRSAKey(account.PrivateKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid FCM service account key: %w", err)
+	}
+	return &FCMSender{
+		baseURL:    strings.TrimSuffix(baseURL, "/"),
+		account:    account,
+		key:        key,
+		clock:      clk,
+		httpClient: &http.Client{Timeout: 15 * time.Second},
+	}, nil
+}
+
+// Name returns "fcm"
+func (s *FCMSender) Name() string {
+	return "fcm"
+}
+
+// fcmMessage is the body of a send request
+type fcmMessage struct {
+	Message struct {
+		Token        string `json:"token"`
+		Notification struct {
+			Title string `json:"title"`
+			Body  string `json:"body"`
+		} `json:"notification"`
+		Data map[string]string `json:"data,omitempty"`
+	} `json:"message"`
+}
+
+// fcmError is the error body of the API
+type fcmError struct {
+	Error struct {
+		Status  string `json:"status"`
+		Message string `json:"message"`
+		Details []struct {
+			ErrorCode string `json:"errorCode"`
+		} `json:"details"`
+	} `json:"error"`
+}
+
+// Send posts one message to the device's registration token. An
+// UNREGISTERED or NOT_FOUND answer is ErrInvalidToken.
+func (s *FCMSender) Send(ctx context.Context, device Device, payload Payload) error {
+	var message fcmMessage
+	message.Message.Token = device.Token
+	message.Message.Notification.Title = payload.Title
+	message.Message.Notification.Body = payload.Body
+	message.Message.Data = payload.Data
+	body, err := json.Marshal(message)
+	if err != nil {
+		return fmt.Errorf("failed to encode FCM message: %w", err)
+	}
+
+	accessToken, err := s.token(ctx)
+	if err != nil {
+		return err
+	}
+	endpoint := s.baseURL + "/v1/projects/" + url.PathEscape(s.account.ProjectID) + "/messages:send"
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to build FCM request: %w", err)
+	}
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("Authorization", "Bearer "+accessToken)
+
+	response, err := s.httpClient.Do(request)
+	if err != nil {
+		return fmt.Errorf("failed to call FCM: %w", err)
+	}
+	defer response.Body.Close()
+	if response.StatusCode == http.StatusOK {
+		return nil
+	}
+
+	var decoded fcmError
+	_ = json.NewDecoder(response.Body).Decode(&decoded)
+	if response.StatusCode == http.StatusUnauthorized {
+		s.mu.Lock()
+		s.accessToken = ""
+		s.mu.Unlock()
+	}
+	reason := decoded.Error.Status
+	for _, detail := range decoded.Error.Details {
+		if detail.ErrorCode != "" {
+			reason = detail.ErrorCode
+		}
+	}
+	if reason == "UNREGISTERED" || reason == "NOT_FOUND" {
+		return fmt.Errorf("%w: FCM answered %s", ErrInvalidToken, reason)
+	}
+	return fmt.Errorf("FCM returned status %d %s: %s", response.StatusCode, reason, decoded.Error.Message)
+}
+
+// token returns a cached access token, exchanging a signed assertion for a
+// new one shortly before it expires
+func (s *FCMSender) token(ctx context.Context) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.clock.Now()
+	if s.accessToken != "" && now.Before(s.expiresAt.Add(-time.Minute)) {
+		return s.accessToken, nil
+	}
+
+	assertion, err := signJWT(map[string]string{"alg": "RS256", "typ": "JWT"}, map[string]any{
+		"iss":   s.account.ClientEmail,
+		"scope": FCMScope,
+		"aud":   s.account.TokenURI,
+		"iat":   now.Unix(),
+		"exp":   now.Add(time.Hour).Unix(),
+	}, func(digest []byte) ([]byte, error) {
+		return rsa.SignPKCS1v15(rand.Reader, s.key, crypto.SHA256, digest)
+	})
+	if err != nil {
+		return "", err
+	}
+
+	form := url.Values{"grant_type": {"urn:ietf:params:oauth:grant-type:jwt-bearer"}, "assertion": {assertion}}
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, s.account.TokenURI, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", fmt.Errorf("failed to build FCM token request: %w", err)
+	}
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	response, err := s.httpClient.Do(request)
+	if err != nil {
+		return "", fmt.Errorf("failed to request FCM access token: %w", err)
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("FCM token endpoint returned status %d", response.StatusCode)
+	}
+
+	var decoded struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int64  `json:"expires_in"`
+	}
+	if err := json.NewDecoder(response.Body).Decode(&decoded); err != nil || decoded.AccessToken == "" {
+		return "", fmt.Errorf("invalid FCM token response")
+	}
+	s.accessToken = decoded.AccessToken
+	s.expiresAt = now.Add(time.Duration(decoded.ExpiresIn) * time.Second)
+	return s.accessToken, nil
+}
+
+// parseRSAKey reads a PEM-encoded PKCS #8 or PKCS #1 RSA private key
+func parseRSAKey(data string) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode([]byte(data))
+	if block == nil {
+		return nil, errors.New("no PEM block")
+	}
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	key, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("not an RSA key")
+	}
+	return key, nil
+}
+
+// signJWT encodes header and claims and signs their SHA-256 digest with
+// sign
+func signJWT(header map[string]string, claims map[string]any, sign func(digest []byte) ([]byte, error)) (string, error) {
+	headerJSON, err := json.Marshal(header)
+	if err != nil {
+		return "", err
+	}
+	claimsJSON, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+	unsigned := base64.RawURLEncoding.EncodeToString(headerJSON) + "." + base64.RawURLEncoding.EncodeToString(claimsJSON)
+	digest := sha256.Sum256([]byte(unsigned))
+	signature, err := sign(digest[:])
+	if err != nil {
+		return "", fmt.Errorf("failed to sign token: %w", err)
+	}
+	return unsigned + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
@@ -0,0 +1,82 @@
+package push
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"golang-arch/internal/shared/api"
+	i18n "golang-arch/internal/shared/domain/internationalization"
+	"golang-arch/internal/shared/geo"
+	"golang-arch/internal/shared/rbac"
+)
+
+// PermissionAdmin lets an operator list, register and unregister any
+// recipient's devices
+const PermissionAdmin rbac.Permission = "push:admin"
+
+// Handler exposes device registration over HTTP
+type Handler struct {
+	service *Service
+	authz   *rbac.Authorizer
+}
+
+// NewHandler creates the device registration handler; authz guards every
+// route
+func NewHandler(service *Service, authz *rbac.Authorizer) *Handler {
+	return &Handler{service: service, authz: authz}
+}
+
+// Register adds the device routes to group. Only the recipient's own
+// principal or one with push:admin may use them:
+//
+//	GET    /push/devices/:recipient         the recipient's devices
+//	POST   /push/devices/:recipient         register or refresh a device
+//	DELETE /push/devices/:recipient/:token  unregister a device
+func (h *Handler) Register(group *gin.RouterGroup) {
+	owner := h.authz.RequireOwner("recipient", PermissionAdmin)
+
+	group.GET("/push/devices/:recipient", owner, h.list)
+	group.POST("/push/devices/:recipient", owner, h.register)
+	group.DELETE("/push/devices/:recipient/:token", owner, h.unregister)
+}
+
+// DeviceBody registers a device
+type DeviceBody struct {
+	Token    string      `json:"token"`
+	Platform Platform    `json:"platform"`
+	Locale   i18n.Locale `json:"locale"` // Defaults to the request's detected locale
+}
+
+func (h *Handler) list(c *gin.Context) {
+	devices, err := h.service.Devices(c.Request.Context(), c.Param("recipient"))
+	if err != nil {
+		api.RespondError(c, err)
+		return
+	}
+	api.Success(c, devices, "push devices")
+}
+
+func (h *Handler) register(c *gin.Context) {
+	var body DeviceBody
+	if !api.BindJSON(c, &body) {
+		return
+	}
+
+	device := Device{Token: body.Token, RecipientID: c.Param("recipient"), Platform: body.Platform, Locale: body.Locale}
+	if location, ok := geo.FromContext(c.Request.Context()); ok && device.Locale == "" {
+		device.Locale = i18n.Locale(location.Locale)
+	}
+	registered, err := h.service.Register(c.Request.Context(), device)
+	if err != nil {
+		api.RespondError(c, err)
+		return
+	}
+	api.Success(c, registered, "push device registered")
+}
+
+func (h *Handler) unregister(c *gin.Context) {
+	if err := h.service.Unregister(c.Request.Context(), c.Param("recipient"), c.Param("token")); err != nil {
+		api.RespondError(c, err)
+		return
+	}
+	api.Success(c, nil, "push device unregistered")
+}
@@ -0,0 +1,163 @@
+// Package push delivers push notifications to the devices recipients
+// register. Service is the notify.Sender of the push channel: it renders a
+// message's title and body from the template catalog in the recipient's
+// language and queues one delivery per device in Redis. The push_delivery
+// worker job hands them to the platform's PushSender, Firebase Cloud
+// Messaging for Android and web or the Apple Push Notification service for
+// iOS, retrying failures with backoff. Devices whose token a provider
+// reports as no longer valid are removed.
+package push
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"go.uber.org/zap"
+
+	"golang-arch/internal/shared/config"
+	i18n "golang-arch/internal/shared/domain/internationalization"
+	"golang-arch/internal/shared/domain/validation"
+	"golang-arch/pkg/clock"
+)
+
+// Platform is the operating system family of a device, which selects the
+// push provider
+type Platform string
+
+// Platforms
+const (
+	PlatformAndroid Platform = "android"
+	PlatformIOS     Platform = "ios"
+	PlatformWeb     Platform = "web"
+)
+
+// Validate checks that the platform is known
+func (p Platform) Validate() error {
+	switch p {
+	case PlatformAndroid, PlatformIOS, PlatformWeb:
+		return nil
+	default:
+		var errs validation.ValidationErrors
+		errs.Add("platform", validation.CodeUnsupported, fmt.Sprintf("unsupported platform %q (expected android, ios or web)", p), nil)
+		return errs.Err()
+	}
+}
+
+// ErrInvalidToken is returned by a PushSender when the provider no longer
+// accepts the device token, e.g. because the app was uninstalled
+var ErrInvalidToken = errors.New("device token is no longer valid")
+
+// Device is an app installation that receives a recipient's pushes
+type Device struct {
+	Token       string      `json:"token"` // Registration token (FCM) or device token (APNs)
+	RecipientID string      `json:"recipient_id"`
+	Platform    Platform    `json:"platform"`
+	Locale      i18n.Locale `json:"locale,omitempty"` // The device's language, used when the recipient has none
+	CreatedAt   time.Time   `json:"created_at"`
+	LastSeenAt  time.Time   `json:"last_seen_at"` // Last registration of the token
+}
+
+// Validate checks the token, recipient, platform and locale
+func (d Device) Validate() error {
+	var errs validation.ValidationErrors
+	if d.Token == "" {
+		errs.Add("token", validation.CodeRequired, "token is required", nil)
+	} else if len(d.Token) > maxTokenLength {
+		errs.Add("token", validation.CodeOutOfRange, fmt.Sprintf("token is longer than %d characters", maxTokenLength), nil)
+	}
+	if d.RecipientID == "" {
+		errs.Add("recipient_id", validation.CodeRequired, "recipient_id is required", nil)
+	}
+	errs.Merge("", "", d.Platform.Validate())
+	if d.Locale != "" {
+		if _, err := i18n.ParseLocale(string(d.Locale)); err != nil {
+			errs.Add("locale", validation.CodeInvalidFormat, err.Error(), nil)
+		}
+	}
+	return errs.Err()
+}
+
+// maxTokenLength bounds device tokens; FCM tokens are about 160 characters
+// and APNs tokens 64
+const maxTokenLength = 4096
+
+// Payload is what a device shows and hands to the app
+type Payload struct {
+	Title string            `json:"title"`
+	Body  string            `json:"body"`
+	Data  map[string]string `json:"data,omitempty"` // Passed to the app, e.g. the event and the IDs it refers to
+}
+
+// PushSender delivers a payload to one device through a push provider.
+// It returns an error wrapping ErrInvalidToken when the provider rejects
+// the token for good.
+type PushSender interface {
+	Name() string
+	Send(ctx context.Context, device Device, payload Payload) error
+}
+
+// LogSender writes pushes to a logger instead of delivering them, for
+// development and platforms without a configured provider
+type LogSender struct {
+	logger *zap.Logger
+}
+
+// NewLogSender creates a sender that logs to logger
+func NewLogSender(logger *zap.Logger) *LogSender {
+	return &LogSender{logger: logger}
+}
+
+// Name returns "log"
+func (s *LogSender) Name() string {
+	return "log"
+}
+
+// Send logs the push
+func (s *LogSender) Send(_ context.Context, device Device, payload Payload) error {
+	s.logger.Info("Push notification (log sender)",
+		zap.String("platform", string(device.Platform)),
+		zap.String("recipient", device.RecipientID),
+		zap.String("title", payload.Title),
+		zap.String("body", payload.Body))
+	return nil
+}
+
+// NewSenders builds the sender of every platform: FCM for Android and web
+// and APNs for iOS when configured, the log sender otherwise
+func NewSenders(cfg config.PushConfig, clk clock.Clock, logger *zap.Logger) (map[Platform]PushSender, error) {
+	logSender := NewLogSender(logger)
+	senders := map[Platform]PushSender{PlatformAndroid: logSender, PlatformWeb: logSender, PlatformIOS: logSender}
+
+	if cfg.FCM.CredentialsFile != "" {
+		credentials, err := os.ReadFile(cfg.FCM.CredentialsFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read FCM credentials: %w", err)
+		}
+		fcm, err := NewFCMSender(cmp.Or(cfg.FCM.URL, DefaultFCMURL), credentials, clk)
+		if err != nil {
+			return nil, err
+		}
+		senders[PlatformAndroid], senders[PlatformWeb] = fcm, fcm
+	}
+
+	if cfg.APNs.KeyFile != "" {
+		signingKey, err := os.ReadFile(cfg.APNs.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read APNs signing key: %w", err)
+		}
+		baseURL := APNsProductionURL
+		if cfg.APNs.Sandbox {
+			baseURL = APNsSandboxURL
+		}
+		apns, err := NewAPNsSender(baseURL, signingKey, cfg.APNs.KeyID, cfg.APNs.TeamID, cfg.APNs.Topic, clk)
+		if err != nil {
+			return nil, err
+		}
+		senders[PlatformIOS] = apns
+	}
+	return senders, nil
+}
@@ -0,0 +1,310 @@
+package push
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+
+	"golang-arch/internal/shared/domain/domainerror"
+	i18n "golang-arch/internal/shared/domain/internationalization"
+	"golang-arch/internal/shared/erasure"
+	"golang-arch/internal/shared/notify"
+	"golang-arch/internal/shared/templates"
+	"golang-arch/pkg/clock"
+)
+
+// Defaults of a Service
+const (
+	DefaultMaxAttempts  = 5
+	DefaultRetryBackoff = 30 * time.Second
+)
+
+// deliverBatch is the number of deliveries taken from the outbox at once
+const deliverBatch = 100
+
+// Service registers devices and queues, localizes and delivers pushes
+type Service struct {
+	devices     DeviceStore
+	outbox      *outbox
+	senders     map[Platform]PushSender
+	views       *templates.Engine
+	clock       clock.Clock
+	logger      *zap.Logger
+	maxAttempts int
+	backoff     time.Duration
+}
+
+// Option configures a Service
+type Option func(*Service)
+
+// WithClock sets the clock deliveries are scheduled with
+func WithClock(c clock.Clock) Option {
+	return func(s *Service) {
+		s.clock = c
+	}
+}
+
+// WithLogger sets the logger
+func WithLogger(logger *zap.Logger) Option {
+	return func(s *Service) {
+		s.logger = logger
+	}
+}
+
+// WithViews translates titles and bodies through the engine's catalog;
+// without it pushes show the catalog keys
+func WithViews(views *templates.Engine) Option {
+	return func(s *Service) {
+		s.views = views
+	}
+}
+
+// WithMaxAttempts sets how often a delivery is tried before it is dropped
+func WithMaxAttempts(attempts int) Option {
+	return func(s *Service) {
+		if attempts > 0 {
+			s.maxAttempts = attempts
+		}
+	}
+}
+
+// WithRetryBackoff sets the wait before the first retry; it doubles with
+// every further attempt
+func WithRetryBackoff(backoff time.Duration) Option {
+	return func(s *Service) {
+		if backoff > 0 {
+			s.backoff = backoff
+		}
+	}
+}
+
+// NewService creates a push service keeping devices in devices and the
+// outbox in Redis. Platforms without a sender cannot be registered.
+func NewService(devices DeviceStore, client *redis.Client, senders map[Platform]PushSender, options ...Option) *Service {
+	s := &Service{
+		devices:     devices,
+		outbox:      &outbox{client: client},
+		senders:     senders,
+		clock:       clock.New(),
+		logger:      zap.NewNop(),
+		maxAttempts: DefaultMaxAttempts,
+		backoff:     DefaultRetryBackoff,
+	}
+	for _, option := range options {
+		option(s)
+	}
+	return s
+}
+
+// Register adds a device to its recipient, or refreshes a known token. A
+// token registered by another recipient moves to this one, e.g. after a
+// different user signs in on the device.
+func (s *Service) Register(ctx context.Context, device Device) (Device, error) {
+	if err := device.Validate(); err != nil {
+		return Device{}, err
+	}
+	if _, ok := s.senders[device.Platform]; !ok {
+		return Device{}, domainerror.Invalidf("push notifications to %s devices are not configured", device.Platform)
+	}
+	device.LastSeenAt = s.clock.Now().UTC()
+	return s.devices.Save(ctx, device)
+}
+
+// Unregister removes one of the recipient's devices, e.g. at sign-out
+func (s *Service) Unregister(ctx context.Context, recipientID, token string) error {
+	devices, err := s.devices.Devices(ctx, recipientID)
+	if err != nil {
+		return err
+	}
+	if !slices.ContainsFunc(devices, func(device Device) bool { return device.Token == token }) {
+		return domainerror.NotFoundf("device is not registered to %s", recipientID)
+	}
+	_, err = s.devices.Delete(ctx, token)
+	return err
+}
+
+// Devices returns the recipient's devices
+func (s *Service) Devices(ctx context.Context, recipientID string) ([]Device, error) {
+	return s.devices.Devices(ctx, recipientID)
+}
+
+// Erase removes the subject's devices and their queued pushes; it is the
+// device store's erasure.Eraser. A device token cannot be anonymized and
+// stay useful, so the devices are deleted.
+func (s *Service) Erase(ctx context.Context, subject erasure.Subject, _ *erasure.Anonymizer) (int, error) {
+	deleted, err := s.devices.DeleteRecipient(ctx, subject.ID)
+	if err != nil {
+		return deleted, err
+	}
+	dropped, err := s.outbox.drop(ctx, subject.ID)
+	return deleted + dropped, err
+}
+
+// Reaches reports whether the recipient has a device, so the notification
+// router can route pushes without an address in the preferences
+func (s *Service) Reaches(ctx context.Context, recipientID string) (bool, error) {
+	devices, err := s.devices.Devices(ctx, recipientID)
+	return len(devices) > 0, err
+}
+
+// Send queues the message for the recipient's devices, or for the one whose
+// token is the message's address. Title and body are the catalog's
+// "<template>.title" and "<template>.body" in the recipient's language,
+// else the device's, with {name} placeholders filled from the message data.
+func (s *Service) Send(ctx context.Context, message notify.Message) error {
+	devices, err := s.devices.Devices(ctx, message.RecipientID)
+	if err != nil {
+		return err
+	}
+	if message.Address != "" {
+		devices = slices.DeleteFunc(devices, func(device Device) bool { return device.Token != message.Address })
+	}
+	if len(devices) == 0 {
+		return fmt.Errorf("%s has no registered device", message.RecipientID)
+	}
+
+	data, err := stringData(message.Data)
+	if err != nil {
+		return err
+	}
+	if message.Event != "" {
+		data["event"] = message.Event
+	}
+	now := s.clock.Now()
+	for _, device := range devices {
+		locale := message.Locale
+		if locale == "" {
+			locale = device.Locale
+		}
+		delivery := Delivery{ID: uuid.NewString(), Device: device, Payload: s.render(message, locale, data)}
+		if err := s.outbox.enqueue(ctx, delivery, now); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// render builds the payload of message in locale
+func (s *Service) render(message notify.Message, locale i18n.Locale, data map[string]string) Payload {
+	key := message.Template
+	if key == "" {
+		key = message.Event
+	}
+	translate := func(key string) string { return key }
+	if s.views != nil {
+		args := make([]any, 0, 2*len(data))
+		for _, name := range slices.Sorted(maps.Keys(data)) {
+			args = append(args, name, data[name])
+		}
+		formatter := s.views.Formatter(i18n.LocalePreferences{Locale: locale})
+		translate = func(key string) string { return formatter.Translate(key, args...) }
+	}
+	return Payload{Title: translate(key + ".title"), Body: translate(key + ".body"), Data: data}
+}
+
+// Deliver sends the due deliveries through their platform's sender. Tokens
+// the provider rejects are unregistered; other failures are retried with
+// backoff until the delivery has been tried WithMaxAttempts times.
+func (s *Service) Deliver(ctx context.Context) error {
+	var retry []Delivery
+	failed := 0
+	for {
+		deliveries, err := s.outbox.due(ctx, s.clock.Now(), deliverBatch)
+		if err != nil {
+			return err
+		}
+		for _, delivery := range deliveries {
+			if err := s.send(ctx, delivery); err != nil {
+				failed++
+				if delivery.Attempts++; delivery.Attempts < s.maxAttempts {
+					retry = append(retry, delivery)
+				} else {
+					s.logger.Error("Dropping push after repeated failures",
+						zap.String("recipient", delivery.Device.RecipientID), zap.Int("attempts", delivery.Attempts))
+				}
+			}
+		}
+		if len(deliveries) < deliverBatch {
+			break
+		}
+	}
+
+	now := s.clock.Now()
+	for _, delivery := range retry {
+		due := now.Add(s.backoff << (delivery.Attempts - 1))
+		if err := s.outbox.enqueue(ctx, delivery, due); err != nil {
+			return err
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d push deliveries failed, %d queued again", failed, len(retry))
+	}
+	return nil
+}
+
+// send hands a delivery to its platform's sender, unregistering the device
+// when its token is rejected. Only failures worth retrying are returned.
+func (s *Service) send(ctx context.Context, delivery Delivery) error {
+	device := delivery.Device
+	logger := s.logger.With(zap.String("platform", string(device.Platform)), zap.String("recipient", device.RecipientID))
+	sender, ok := s.senders[device.Platform]
+	if !ok {
+		logger.Warn("Dropping push for a platform without a sender")
+		return nil
+	}
+
+	err := sender.Send(ctx, device, delivery.Payload)
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, ErrInvalidToken):
+		logger.Info("Unregistering device with an invalid token", zap.String("provider", sender.Name()), zap.Error(err))
+		if _, err := s.devices.Delete(ctx, device.Token); err != nil {
+			logger.Error("Failed to unregister device", zap.Error(err))
+		}
+		return nil
+	default:
+		logger.Warn("Failed to send push",
+			zap.String("provider", sender.Name()), zap.Int("attempt", delivery.Attempts+1), zap.Error(err))
+		return err
+	}
+}
+
+// Pending returns the number of queued deliveries, including retries
+func (s *Service) Pending(ctx context.Context) (int64, error) {
+	return s.outbox.size(ctx)
+}
+
+// stringData flattens message data to the string map providers accept.
+// Strings are kept as they are; other values are JSON-encoded.
+func stringData(value any) (map[string]string, error) {
+	data := make(map[string]string)
+	if value == nil {
+		return data, nil
+	}
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode push data: %w", err)
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(encoded, &fields); err != nil {
+		return nil, fmt.Errorf("push data must be an object, got %T", value)
+	}
+	for name, raw := range fields {
+		var text string
+		if json.Unmarshal(raw, &text) == nil {
+			data[name] = text
+		} else {
+			data[name] = string(raw)
+		}
+	}
+	return data, nil
+}
@@ -0,0 +1,303 @@
+package push
+
+import (
+	"cmp"
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"golang-arch/internal/shared/fieldcrypt"
+)
+
+// DeviceStore persists the registered devices
+type DeviceStore interface {
+	// Save inserts the device or, for a known token, moves it to the
+	// device's recipient and refreshes LastSeenAt
+	Save(ctx context.Context, device Device) (Device, error)
+	// Devices returns the recipient's devices, oldest first
+	Devices(ctx context.Context, recipientID string) ([]Device, error)
+	// Delete removes the token and reports whether it was registered
+	Delete(ctx context.Context, token string) (bool, error)
+	// DeleteRecipient removes the recipient's devices and returns how many
+	// it removed
+	DeleteRecipient(ctx context.Context, recipientID string) (int, error)
+}
+
+// PostgresStore keeps devices in the push_devices table, keyed by the
+// SHA-256 of their token. With encryption the token itself is sealed in
+// sealed_token and the token column stays NULL.
+type PostgresStore struct {
+	db     *sql.DB
+	tokens *fieldcrypt.Field // Seals device tokens when set
+}
+
+// StoreOption configures a PostgresStore
+type StoreOption func(*PostgresStore)
+
+// WithEncryption seals device tokens with encryptor. Devices registered
+// before keep their plaintext token until the app registers them again.
+func WithEncryption(encryptor *fieldcrypt.Encryptor) StoreOption {
+	return func(s *PostgresStore) {
+		if encryptor != nil {
+			s.tokens = encryptor.Field("push_devices.token")
+		}
+	}
+}
+
+// NewPostgresStore creates a store on db
+func NewPostgresStore(db *sql.DB, opts ...StoreOption) *PostgresStore {
+	store := &PostgresStore{db: db}
+	for _, opt := range opts {
+		opt(store)
+	}
+	return store
+}
+
+// tokenHash is the lookup key of token. Device tokens are long random
+// strings, so an unkeyed hash does not let them be guessed back.
+func tokenHash(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// Save upserts the device by token
+func (s *PostgresStore) Save(ctx context.Context, device Device) (Device, error) {
+	var plain, sealed any = device.Token, nil
+	if s.tokens != nil {
+		plain, sealed = nil, s.tokens.Value(device.Token)
+	}
+	err := s.db.QueryRowContext(ctx,
+		`INSERT INTO push_devices (token_hash, token, sealed_token, recipient_id, platform, locale, created_at, last_seen_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $7)
+		ON CONFLICT (token_hash) DO UPDATE SET token = EXCLUDED.token, sealed_token = EXCLUDED.sealed_token,
+			recipient_id = EXCLUDED.recipient_id, platform = EXCLUDED.platform,
+			locale = EXCLUDED.locale, last_seen_at = EXCLUDED.last_seen_at
+		RETURNING created_at`,
+		tokenHash(device.Token), plain, sealed, device.RecipientID, device.Platform, device.Locale, device.LastSeenAt).Scan(&device.CreatedAt)
+	if err != nil {
+		return Device{}, fmt.Errorf("failed to save push device: %w", err)
+	}
+	return device, nil
+}
+
+// Devices selects the recipient's devices
+func (s *PostgresStore) Devices(ctx context.Context, recipientID string) ([]Device, error) {
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT token, sealed_token, recipient_id, platform, locale, created_at, last_seen_at
+		FROM push_devices WHERE recipient_id = $1 ORDER BY created_at, token_hash`, recipientID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read push devices: %w", err)
+	}
+	defer rows.Close()
+
+	var devices []Device
+	for rows.Next() {
+		var (
+			device Device
+			plain  sql.NullString
+			sealed []byte
+		)
+		if err := rows.Scan(&plain, &sealed, &device.RecipientID, &device.Platform, &device.Locale, &device.CreatedAt, &device.LastSeenAt); err != nil {
+			return nil, fmt.Errorf("failed to read push devices: %w", err)
+		}
+		device.Token = plain.String
+		if sealed != nil {
+			if s.tokens == nil {
+				return nil, fmt.Errorf("push device tokens of %s are encrypted but encryption is disabled", recipientID)
+			}
+			if err := s.tokens.Open(sealed, &device.Token); err != nil {
+				return nil, fmt.Errorf("failed to decrypt push device token of %s: %w", recipientID, err)
+			}
+		}
+		devices = append(devices, device)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read push devices: %w", err)
+	}
+	return devices, nil
+}
+
+// Delete removes the token
+func (s *PostgresStore) Delete(ctx context.Context, token string) (bool, error) {
+	result, err := s.db.ExecContext(ctx, `DELETE FROM push_devices WHERE token_hash = $1`, tokenHash(token))
+	if err != nil {
+		return false, fmt.Errorf("failed to delete push device: %w", err)
+	}
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to delete push device: %w", err)
+	}
+	return deleted > 0, nil
+}
+
+// DeleteRecipient removes the recipient's devices
+func (s *PostgresStore) DeleteRecipient(ctx context.Context, recipientID string) (int, error) {
+	result, err := s.db.ExecContext(ctx, `DELETE FROM push_devices WHERE recipient_id = $1`, recipientID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete push devices: %w", err)
+	}
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete push devices: %w", err)
+	}
+	return int(deleted), nil
+}
+
+// MemoryStore keeps devices in memory, for tests and the dev profile
+type MemoryStore struct {
+	mu      sync.Mutex
+	devices map[string]Device
+}
+
+// NewMemoryStore creates an empty in-memory store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{devices: make(map[string]Device)}
+}
+
+// Save stores the device, keeping the CreatedAt of a known token
+func (s *MemoryStore) Save(_ context.Context, device Device) (Device, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	device.CreatedAt = device.LastSeenAt
+	if existing, ok := s.devices[device.Token]; ok {
+		device.CreatedAt = existing.CreatedAt
+	}
+	s.devices[device.Token] = device
+	return device, nil
+}
+
+// Devices returns the recipient's devices
+func (s *MemoryStore) Devices(_ context.Context, recipientID string) ([]Device, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var devices []Device
+	for _, device := range s.devices {
+		if device.RecipientID == recipientID {
+			devices = append(devices, device)
+		}
+	}
+	slices.SortFunc(devices, func(a, b Device) int {
+		return cmp.Or(a.CreatedAt.Compare(b.CreatedAt), strings.Compare(a.Token, b.Token))
+	})
+	return devices, nil
+}
+
+// Delete removes the token
+func (s *MemoryStore) Delete(_ context.Context, token string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, ok := s.devices[token]
+	delete(s.devices, token)
+	return ok, nil
+}
+
+// DeleteRecipient removes the recipient's devices
+func (s *MemoryStore) DeleteRecipient(_ context.Context, recipientID string) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	deleted := 0
+	for token, device := range s.devices {
+		if device.RecipientID == recipientID {
+			delete(s.devices, token)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+// outboxKey is the Redis sorted set of pending deliveries, scored by the
+// Unix millisecond they are due
+const outboxKey = "push:outbox"
+
+// Delivery is a rendered push waiting in the outbox for one device
+type Delivery struct {
+	ID       string  `json:"id"` // Distinguishes deliveries with equal content in the sorted set
+	Device   Device  `json:"device"`
+	Payload  Payload `json:"payload"`
+	Attempts int     `json:"attempts"` // Failed send attempts so far
+}
+
+// popDueScript removes and returns up to ARGV[2] deliveries due at ARGV[1]
+var popDueScript = redis.NewScript(`
+local due = redis.call('ZRANGEBYSCORE', KEYS[1], '-inf', ARGV[1], 'LIMIT', 0, ARGV[2])
+if #due > 0 then redis.call('ZREM', KEYS[1], unpack(due)) end
+return due
+`)
+
+// outbox queues deliveries in Redis until they are due
+type outbox struct {
+	client *redis.Client
+}
+
+// enqueue adds a delivery due at the given time
+func (o *outbox) enqueue(ctx context.Context, delivery Delivery, due time.Time) error {
+	data, err := json.Marshal(delivery)
+	if err != nil {
+		return fmt.Errorf("failed to encode push delivery: %w", err)
+	}
+	if err := o.client.ZAdd(ctx, outboxKey, redis.Z{Score: float64(due.UnixMilli()), Member: data}).Err(); err != nil {
+		return fmt.Errorf("failed to queue push delivery: %w", err)
+	}
+	return nil
+}
+
+// due removes and returns up to limit deliveries due at now
+func (o *outbox) due(ctx context.Context, now time.Time, limit int) ([]Delivery, error) {
+	members, err := popDueScript.Run(ctx, o.client, []string{outboxKey},
+		strconv.FormatInt(now.UnixMilli(), 10), limit).StringSlice()
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return nil, fmt.Errorf("failed to read push outbox: %w", err)
+	}
+	deliveries := make([]Delivery, 0, len(members))
+	for _, member := range members {
+		var delivery Delivery
+		if err := json.Unmarshal([]byte(member), &delivery); err != nil {
+			return nil, fmt.Errorf("failed to decode push delivery: %w", err)
+		}
+		deliveries = append(deliveries, delivery)
+	}
+	return deliveries, nil
+}
+
+// drop removes the queued deliveries to recipientID's devices and returns
+// how many it removed
+func (o *outbox) drop(ctx context.Context, recipientID string) (int, error) {
+	members, err := o.client.ZRange(ctx, outboxKey, 0, -1).Result()
+	if err != nil {
+		return 0, fmt.Errorf("failed to read push outbox: %w", err)
+	}
+	var matching []any
+	for _, member := range members {
+		var delivery Delivery
+		if err := json.Unmarshal([]byte(member), &delivery); err != nil {
+			return 0, fmt.Errorf("failed to decode push delivery: %w", err)
+		}
+		if delivery.Device.RecipientID == recipientID {
+			matching = append(matching, member)
+		}
+	}
+	if len(matching) == 0 {
+		return 0, nil
+	}
+	removed, err := o.client.ZRem(ctx, outboxKey, matching...).Result()
+	if err != nil {
+		return 0, fmt.Errorf("failed to remove push deliveries: %w", err)
+	}
+	return int(removed), nil
+}
+
+// size returns the number of queued deliveries
+func (o *outbox) size(ctx context.Context) (int64, error) {
+	return o.client.ZCard(ctx, outboxKey).Result()
+}
@@ -0,0 +1,131 @@
+// Package ratelimit caps how often one key, usually an API key or client IP,
+// may call an endpoint within a short fixed window. Counters live in Redis so
+// every instance enforces the same limit.
+//
+// Limits are distinct from metering quotas: they smooth bursts over seconds
+// or minutes, and an exceeded limit answers 429 with code TOO_MANY_REQUESTS.
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+
+	"golang-arch/internal/shared/api"
+	"golang-arch/internal/shared/metering"
+)
+
+// keyPrefix prefixes the Redis counters as "ratelimit:<name>:<key>"
+const keyPrefix = "ratelimit:"
+
+// Response headers describing the limit
+const (
+	HeaderLimit     = "X-RateLimit-Limit"
+	HeaderRemaining = "X-RateLimit-Remaining"
+)
+
+// countScript counts a request in a fixed window and returns the count and
+// the window's remaining milliseconds
+var countScript = redis.NewScript(`
+local count = redis.call('INCR', KEYS[1])
+if count == 1 then redis.call('PEXPIRE', KEYS[1], ARGV[1]) end
+return {count, redis.call('PTTL', KEYS[1])}
+`)
+
+// Decision is the outcome of counting a request
+type Decision struct {
+	Allowed    bool
+	Remaining  int           // Requests left in the window
+	RetryAfter time.Duration // Until the window resets, when not allowed
+}
+
+// Limiter allows Limit requests per key and window
+type Limiter struct {
+	client *redis.Client
+	name   string
+	limit  int
+	window time.Duration
+	logger *zap.Logger
+}
+
+// Option customizes a Limiter
+type Option func(*Limiter)
+
+// WithLogger sets the logger
+func WithLogger(logger *zap.Logger) Option {
+	return func(l *Limiter) {
+		l.logger = logger
+	}
+}
+
+// NewLimiter creates a limiter; name separates its counters from those of
+// other limiters. A limit of 0 allows everything.
+func NewLimiter(client *redis.Client, name string, limit int, window time.Duration, options ...Option) *Limiter {
+	l := &Limiter{client: client, name: name, limit: limit, window: window, logger: zap.NewNop()}
+	for _, option := range options {
+		option(l)
+	}
+	return l
+}
+
+// Allow counts a request of key
+func (l *Limiter) Allow(ctx context.Context, key string) (Decision, error) {
+	if l.limit <= 0 {
+		return Decision{Allowed: true}, nil
+	}
+	result, err := countScript.Run(ctx, l.client, []string{keyPrefix + l.name + ":" + key}, l.window.Milliseconds()).Slice()
+	if err != nil {
+		return Decision{}, fmt.Errorf("failed to count request: %w", err)
+	}
+	count, _ := result[0].(int64)
+	remaining, _ := result[1].(int64)
+	if int(count) <= l.limit {
+		return Decision{Allowed: true, Remaining: l.limit - int(count)}, nil
+	}
+	return Decision{RetryAfter: max(time.Duration(remaining)*time.Millisecond, time.Millisecond)}, nil
+}
+
+// KeyFunc names the caller a request is counted against
+type KeyFunc func(c *gin.Context) string
+
+// ByConsumer counts requests per metering consumer, i.e. per API key in
+// header, and per client IP for requests without one
+func ByConsumer(header string) KeyFunc {
+	return func(c *gin.Context) string {
+		if consumer := metering.Consumer(c, header); consumer != "" {
+			return consumer
+		}
+		return "ip:" + c.ClientIP()
+	}
+}
+
+// Middleware rejects requests over the limit with 429 and a Retry-After.
+// When Redis fails requests are let through.
+func (l *Limiter) Middleware(key KeyFunc) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if l.limit <= 0 {
+			c.Next()
+			return
+		}
+		decision, err := l.Allow(c.Request.Context(), key(c))
+		if err != nil {
+			l.logger.Warn("Rate limiting failed, request let through", zap.String("limiter", l.name), zap.Error(err))
+			c.Next()
+			return
+		}
+		c.Header(HeaderLimit, strconv.Itoa(l.limit))
+		c.Header(HeaderRemaining, strconv.Itoa(decision.Remaining))
+		if !decision.Allowed {
+			c.Header("Retry-After", strconv.Itoa(max(int(decision.RetryAfter.Round(time.Second)/time.Second), 1)))
+			api.RespondError(c, api.NewTooManyRequestsError("rate limit exceeded, slow down"))
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,41 @@
+package rates
+
+import (
+	"context"
+	"time"
+
+	i18n "golang-arch/internal/shared/domain/internationalization"
+)
+
+// SourceOverride is the source of rates pinned by an administrator
+const SourceOverride = "override"
+
+// ConvertedMoney is an amount converted to another currency with the rate
+// used and where that rate came from
+type ConvertedMoney struct {
+	Amount    i18n.Money        `json:"amount"`    // In the original currency
+	Result    i18n.Money        `json:"result"`    // In the target currency
+	Rate      i18n.ExchangeRate `json:"rate"`      // Applied rate, possibly inverted or crossed
+	Source    string            `json:"source"`    // Provider name, or "override"
+	Timestamp time.Time         `json:"timestamp"` // When the rate was observed
+}
+
+// Convert converts amount to target at the current rate, rounding to the
+// target's minor units with mode
+func (s *Service) Convert(ctx context.Context, amount i18n.Money, target string, mode i18n.RoundingMode) (*ConvertedMoney, error) {
+	rate, source, err := s.lookup(ctx, amount.Currency.Code, target)
+	if err != nil {
+		return nil, err
+	}
+	result, err := rate.Convert(amount, mode)
+	if err != nil {
+		return nil, err
+	}
+	return &ConvertedMoney{
+		Amount:    amount,
+		Result:    *result,
+		Rate:      *rate,
+		Source:    source,
+		Timestamp: rate.Timestamp.ToTime().UTC(),
+	}, nil
+}
@@ -0,0 +1,53 @@
+package rates
+
+import (
+	"context"
+
+	i18n "golang-arch/internal/shared/domain/internationalization"
+)
+
+// Converter adapts a RateSource, such as Service, to i18n.CurrencyConverter
+// so its rates can be used with Money.ConvertTo
+type Converter struct {
+	ctx    context.Context
+	source RateSource
+}
+
+// NewConverter creates a converter looking rates up in source with ctx
+func NewConverter(ctx context.Context, source RateSource) *Converter {
+	return &Converter{ctx: ctx, source: source}
+}
+
+// ExchangeRate returns the source's current rate from base to quote
+func (c *Converter) ExchangeRate(base, quote i18n.Currency) (*i18n.ExchangeRate, error) {
+	return c.source.Rate(c.ctx, base.Code, quote.Code)
+}
+
+// Converter returns a converter answering from the current rates with ctx,
+// e.g. a request's context
+func (s *Service) Converter(ctx context.Context) *Converter {
+	return NewConverter(ctx, s)
+}
+
+// StaticConverter converts at a fixed table of rates held in memory, for
+// tests and fixed-rate setups. Pairs the table does not quote directly are
+// inverted or crossed like Service does.
+type StaticConverter struct {
+	rates []i18n.ExchangeRate
+}
+
+// NewStaticConverter creates a converter from rates
+func NewStaticConverter(rates ...i18n.ExchangeRate) *StaticConverter {
+	return &StaticConverter{rates: rates}
+}
+
+// Rate returns the rate from base to quote, so a StaticConverter is also a
+// RateSource
+func (c *StaticConverter) Rate(_ context.Context, base, quote string) (*i18n.ExchangeRate, error) {
+	return findRate(c.rates, base, quote)
+}
+
+// ExchangeRate returns the rate from base to quote
+func (c *StaticConverter) ExchangeRate(base, quote i18n.Currency) (*i18n.ExchangeRate, error) {
+	return findRate(c.rates, base.Code, quote.Code)
+}
@@ -0,0 +1,80 @@
+package rates
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"time"
+
+	i18n "golang-arch/internal/shared/domain/internationalization"
+)
+
+// DefaultECBURL is the European Central Bank's daily reference rates feed
+const DefaultECBURL = "https://www.ecb.europa.eu/stats/eurofxref/eurofxref-daily.xml"
+
+// ecbPublication is when the reference rates of a day are published,
+// 16:00 CET. Rates are stamped at 14:00 UTC, 16:00 CEST, so they never look
+// newer than they are.
+const ecbPublication = 14 * time.Hour
+
+// ECBProvider reads the euro reference rates the European Central Bank
+// publishes once every TARGET business day. The rates are quoted against
+// EUR; Service inverts and crosses them for other bases.
+type ECBProvider struct {
+	url  string
+	feed *feed
+}
+
+// NewECBProvider creates a provider reading the feed at url, DefaultECBURL
+// when empty
+func NewECBProvider(url string, timeout time.Duration) *ECBProvider {
+	if url == "" {
+		url = DefaultECBURL
+	}
+	return &ECBProvider{url: url, feed: newFeed("ECB", timeout)}
+}
+
+// Name returns "ecb"
+func (p *ECBProvider) Name() string {
+	return "ecb"
+}
+
+// FetchRates returns the latest reference rates, stamped with their day
+func (p *ECBProvider) FetchRates(ctx context.Context) ([]i18n.ExchangeRate, error) {
+	return p.feed.fetch(ctx, p.url, parseECB)
+}
+
+// ecbEnvelope is the subset of the eurofxref XML document used here:
+// <Cube><Cube time="2024-01-02"><Cube currency="USD" rate="1.0956"/>...
+type ecbEnvelope struct {
+	Days []struct {
+		Time  string `xml:"time,attr"`
+		Rates []struct {
+			Currency string `xml:"currency,attr"`
+			Rate     string `xml:"rate,attr"`
+		} `xml:"Cube"`
+	} `xml:"Cube>Cube"`
+}
+
+// parseECB decodes the most recent day of a eurofxref document
+func parseECB(body io.Reader) ([]i18n.ExchangeRate, error) {
+	var envelope ecbEnvelope
+	if err := xml.NewDecoder(body).Decode(&envelope); err != nil {
+		return nil, err
+	}
+	if len(envelope.Days) == 0 || len(envelope.Days[0].Rates) == 0 {
+		return nil, fmt.Errorf("feed has no rates")
+	}
+
+	day := envelope.Days[0]
+	date, err := time.Parse(time.DateOnly, day.Time)
+	if err != nil {
+		return nil, fmt.Errorf("invalid rate date %q", day.Time)
+	}
+	quotes := make(map[string]string, len(day.Rates))
+	for _, rate := range day.Rates {
+		quotes[rate.Currency] = rate.Rate
+	}
+	return quoteRates("EUR", quotes, date.Add(ecbPublication))
+}
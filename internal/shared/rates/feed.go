@@ -0,0 +1,127 @@
+package rates
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	neturl "net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"golang-arch/internal/shared/domain/domainerror"
+	i18n "golang-arch/internal/shared/domain/internationalization"
+)
+
+// DefaultFetchTimeout bounds each request of the HTTP providers
+const DefaultFetchTimeout = 10 * time.Second
+
+// feed fetches a rate document over HTTP. It remembers the validators of
+// the last response, so a refresh of an unchanged feed is answered with 304
+// Not Modified and the rates parsed before.
+type feed struct {
+	name       string
+	httpClient *http.Client
+
+	mu           sync.Mutex
+	etag         string
+	lastModified string
+	rates        []i18n.ExchangeRate
+}
+
+func newFeed(name string, timeout time.Duration) *feed {
+	if timeout <= 0 {
+		timeout = DefaultFetchTimeout
+	}
+	return &feed{name: name, httpClient: &http.Client{Timeout: timeout}}
+}
+
+// fetch GETs url and parses a changed document with parse
+func (f *feed) fetch(ctx context.Context, url string, parse func(io.Reader) ([]i18n.ExchangeRate, error)) ([]i18n.ExchangeRate, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build %s request: %w", f.name, err)
+	}
+	if f.rates != nil {
+		if f.etag != "" {
+			request.Header.Set("If-None-Match", f.etag)
+		}
+		if f.lastModified != "" {
+			request.Header.Set("If-Modified-Since", f.lastModified)
+		}
+	}
+
+	response, err := f.httpClient.Do(request)
+	if err != nil {
+		// Without the URL, which may carry credentials
+		var urlErr *neturl.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return nil, domainerror.Unavailablef("failed to call %s: %v", f.name, err)
+	}
+	defer response.Body.Close()
+
+	switch {
+	case response.StatusCode == http.StatusNotModified && f.rates != nil:
+		return f.rates, nil
+	case response.StatusCode == http.StatusTooManyRequests || response.StatusCode >= 500:
+		return nil, domainerror.Unavailablef("%s returned status %d", f.name, response.StatusCode)
+	case response.StatusCode != http.StatusOK:
+		body, _ := io.ReadAll(io.LimitReader(response.Body, 512))
+		return nil, fmt.Errorf("%s returned status %d: %s", f.name, response.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	rates, err := parse(response.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode %s rates: %w", f.name, err)
+	}
+	f.rates = rates
+	f.etag, f.lastModified = response.Header.Get("ETag"), response.Header.Get("Last-Modified")
+	return rates, nil
+}
+
+// quoteRates builds the rates of base from decimal rates keyed by quote
+// currency code. Currencies the application does not support are skipped.
+func quoteRates(base string, quotes map[string]string, observed time.Time) ([]i18n.ExchangeRate, error) {
+	baseCurrency, err := i18n.NewCurrencyFromCode(base)
+	if err != nil {
+		return nil, fmt.Errorf("unsupported base currency %s: %w", base, err)
+	}
+
+	timestamp := i18n.NewTimeFromTime(observed)
+	rates := make([]i18n.ExchangeRate, 0, len(quotes))
+	for code, value := range quotes {
+		quote, err := i18n.NewCurrencyFromCode(code)
+		if err != nil || code == base {
+			continue
+		}
+		rate, err := i18n.NewExchangeRateFromDecimal(*baseCurrency, *quote, plainDecimal(value), *timestamp)
+		if err != nil {
+			return nil, fmt.Errorf("invalid rate %s/%s: %w", base, code, err)
+		}
+		rates = append(rates, *rate)
+	}
+	sort.Slice(rates, func(i, j int) bool { return rates[i].Quote.Code < rates[j].Quote.Code })
+	return rates, nil
+}
+
+// plainDecimal rewrites a number in exponent notation, e.g. "1.2e-05", as
+// a plain decimal with RatePrecision decimal places
+func plainDecimal(value string) string {
+	if !strings.ContainsAny(value, "eE") {
+		return value
+	}
+	r, ok := new(big.Rat).SetString(value)
+	if !ok {
+		return value
+	}
+	return strings.TrimRight(strings.TrimRight(r.FloatString(i18n.RatePrecision), "0"), ".")
+}
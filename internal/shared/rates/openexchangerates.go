@@ -0,0 +1,74 @@
+package rates
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+	"time"
+
+	i18n "golang-arch/internal/shared/domain/internationalization"
+)
+
+// DefaultOpenExchangeRatesURL is the root of the OpenExchangeRates API
+const DefaultOpenExchangeRatesURL = "https://openexchangerates.org/api"
+
+// OpenExchangeRatesProvider reads the latest rates from OpenExchangeRates.
+// Free plans quote against USD only; other bases need a paid plan.
+type OpenExchangeRatesProvider struct {
+	url  string
+	feed *feed
+}
+
+// NewOpenExchangeRatesProvider creates a provider for the API at baseURL,
+// DefaultOpenExchangeRatesURL when empty, authenticated with appID
+func NewOpenExchangeRatesProvider(baseURL, appID, base string, timeout time.Duration) *OpenExchangeRatesProvider {
+	if baseURL == "" {
+		baseURL = DefaultOpenExchangeRatesURL
+	}
+	query := url.Values{"app_id": {appID}}
+	if base != "" && base != "USD" {
+		query.Set("base", base)
+	}
+	return &OpenExchangeRatesProvider{
+		url:  strings.TrimSuffix(baseURL, "/") + "/latest.json?" + query.Encode(),
+		feed: newFeed("OpenExchangeRates", timeout),
+	}
+}
+
+// Name returns "openexchangerates"
+func (p *OpenExchangeRatesProvider) Name() string {
+	return "openexchangerates"
+}
+
+// FetchRates returns the latest rates, stamped with the time
+// OpenExchangeRates observed them
+func (p *OpenExchangeRatesProvider) FetchRates(ctx context.Context) ([]i18n.ExchangeRate, error) {
+	return p.feed.fetch(ctx, p.url, parseOpenExchangeRates)
+}
+
+// parseOpenExchangeRates decodes a latest.json response. Rates are read as
+// json.Number so they are kept exact.
+func parseOpenExchangeRates(body io.Reader) ([]i18n.ExchangeRate, error) {
+	var response struct {
+		Timestamp int64                  `json:"timestamp"`
+		Base      string                 `json:"base"`
+		Rates     map[string]json.Number `json:"rates"`
+	}
+	decoder := json.NewDecoder(body)
+	decoder.UseNumber()
+	if err := decoder.Decode(&response); err != nil {
+		return nil, err
+	}
+	if response.Base == "" || len(response.Rates) == 0 {
+		return nil, fmt.Errorf("response has no rates")
+	}
+
+	quotes := make(map[string]string, len(response.Rates))
+	for code, rate := range response.Rates {
+		quotes[code] = rate.String()
+	}
+	return quoteRates(response.Base, quotes, time.Unix(response.Timestamp, 0))
+}
@@ -0,0 +1,91 @@
+// Package rates keeps the application's exchange rates current: a Provider
+// fetches them, the refresh job stores every fetch in Postgres as history and
+// caches the current set in Redis, and Service answers rate lookups for
+// handlers and services, deriving inverse and cross rates when needed.
+package rates
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"golang-arch/internal/shared/config"
+	i18n "golang-arch/internal/shared/domain/internationalization"
+	"golang-arch/pkg/clock"
+)
+
+// Provider fetches the current exchange rates from a rate source
+type Provider interface {
+	Name() string
+	FetchRates(ctx context.Context) ([]i18n.ExchangeRate, error)
+}
+
+// StaticProvider serves a fixed table of rates against one base currency.
+// Every fetch is stamped with the current time, so the rates never go stale.
+type StaticProvider struct {
+	rates []i18n.ExchangeRate
+	clock clock.Clock
+}
+
+// NewStaticProvider creates a provider from decimal rates keyed by quote
+// currency code, e.g. {"EUR": "0.9221"} against base "USD"
+func NewStaticProvider(baseCode string, rates map[string]string, clk clock.Clock) (*StaticProvider, error) {
+	base, err := i18n.NewCurrencyFromCode(strings.ToUpper(baseCode))
+	if err != nil {
+		return nil, fmt.Errorf("invalid static rates base: %w", err)
+	}
+
+	now := i18n.NewTimeFromTime(clk.Now())
+	provider := &StaticProvider{clock: clk}
+	for code, value := range rates {
+		quote, err := i18n.NewCurrencyFromCode(strings.ToUpper(code))
+		if err != nil {
+			return nil, fmt.Errorf("invalid static rate %s: %w", code, err)
+		}
+
+		rate, err := i18n.NewExchangeRateFromDecimal(*base, *quote, value, *now)
+		if err != nil {
+			return nil, fmt.Errorf("invalid static rate %s: %w", code, err)
+		}
+		provider.rates = append(provider.rates, *rate)
+	}
+
+	sort.Slice(provider.rates, func(i, j int) bool {
+		return provider.rates[i].Quote.Code < provider.rates[j].Quote.Code
+	})
+	return provider, nil
+}
+
+// Name returns "static"
+func (p *StaticProvider) Name() string {
+	return "static"
+}
+
+// FetchRates returns the configured rates stamped with the current time
+func (p *StaticProvider) FetchRates(context.Context) ([]i18n.ExchangeRate, error) {
+	now := i18n.NewTimeFromTime(p.clock.Now())
+	rates := make([]i18n.ExchangeRate, len(p.rates))
+	for i, rate := range p.rates {
+		rate.Timestamp = *now
+		rates[i] = rate
+	}
+	return rates, nil
+}
+
+// NewProvider builds the provider selected by cfg.Provider
+func NewProvider(cfg config.RatesConfig, clk clock.Clock) (Provider, error) {
+	switch cfg.Provider {
+	case "", "static":
+		return NewStaticProvider(cfg.Base, cfg.Static, clk)
+	case "ecb":
+		return NewECBProvider(cfg.ECBURL, cfg.FetchTimeout), nil
+	case "openexchangerates":
+		if cfg.OXRAppID == "" {
+			return nil, fmt.Errorf("rates provider openexchangerates requires oxr_app_id")
+		}
+		return NewOpenExchangeRatesProvider(cfg.OXRURL, cfg.OXRAppID, strings.ToUpper(cfg.Base), cfg.FetchTimeout), nil
+	default:
+		return nil, fmt.Errorf("unknown rates provider %q", cfg.Provider)
+	}
+}
@@ -0,0 +1,251 @@
+package rates
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"time"
+
+	"go.uber.org/zap"
+
+	"golang-arch/internal/shared/cache"
+	"golang-arch/internal/shared/domain/domainerror"
+	i18n "golang-arch/internal/shared/domain/internationalization"
+	"golang-arch/pkg/clock"
+	"golang-arch/pkg/singleflight"
+)
+
+// currentKey is the cache key of the current rate set
+const currentKey = "current"
+
+// Service refreshes exchange rates and answers rate lookups. The current
+// rates are read from Redis, falling back to the latest rows in Postgres
+// when the cache is empty.
+type Service struct {
+	provider Provider
+	store    Store
+	cache    *cache.RedisCache
+	clock    clock.Clock
+	logger   *zap.Logger
+	cacheTTL time.Duration
+	maxAge   time.Duration
+
+	// overrides supplies manually pinned rates; nil when there are none
+	overrides OverrideSource
+
+	// current collapses concurrent lookups of the current rates
+	current singleflight.Group[string, []i18n.ExchangeRate]
+}
+
+// Option customizes a Service
+type Option func(*Service)
+
+// WithClock replaces the system clock used for staleness checks
+func WithClock(c clock.Clock) Option {
+	return func(s *Service) {
+		s.clock = c
+	}
+}
+
+// WithLogger sets the logger used to report refreshes
+func WithLogger(logger *zap.Logger) Option {
+	return func(s *Service) {
+		s.logger = logger
+	}
+}
+
+// WithCacheTTL sets how long the current rates live in Redis; zero keeps them
+// until the next refresh
+func WithCacheTTL(ttl time.Duration) Option {
+	return func(s *Service) {
+		s.cacheTTL = ttl
+	}
+}
+
+// WithMaxAge makes Rate fail with an Unavailable error for rates older than
+// maxAge; zero accepts rates of any age
+func WithMaxAge(maxAge time.Duration) Option {
+	return func(s *Service) {
+		s.maxAge = maxAge
+	}
+}
+
+// OverrideSource supplies rates pinned by an operator, which take precedence
+// over the provider's
+type OverrideSource interface {
+	// Overrides returns the pinned rates currently in effect
+	Overrides(ctx context.Context) ([]i18n.ExchangeRate, error)
+}
+
+// WithOverrides makes Rate answer from source first. Pinned rates are never
+// stale.
+func WithOverrides(source OverrideSource) Option {
+	return func(s *Service) {
+		s.overrides = source
+	}
+}
+
+// NewService creates a rates service
+func NewService(provider Provider, store Store, rc *cache.RedisCache, options ...Option) *Service {
+	s := &Service{
+		provider: provider,
+		store:    store,
+		cache:    rc,
+		clock:    clock.New(),
+		logger:   zap.NewNop(),
+	}
+	for _, option := range options {
+		option(s)
+	}
+	return s
+}
+
+// Refresh fetches the rates from the provider, appends them to the history
+// and replaces the cached current rates
+func (s *Service) Refresh(ctx context.Context) error {
+	rates, err := s.provider.FetchRates(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to fetch rates from %s: %w", s.provider.Name(), err)
+	}
+	if len(rates) == 0 {
+		return fmt.Errorf("rates provider %s returned no rates", s.provider.Name())
+	}
+
+	if err := s.store.Save(ctx, s.provider.Name(), rates); err != nil {
+		return err
+	}
+	if err := s.cache.Set(ctx, currentKey, rates, s.cacheTTL); err != nil {
+		return err
+	}
+	// Lookups already in flight may still return the previous rates
+	s.current.Forget(currentKey)
+
+	s.logger.Info("Exchange rates refreshed",
+		zap.String("provider", s.provider.Name()),
+		zap.Int("count", len(rates)))
+	return nil
+}
+
+// Current returns the current rates. Concurrent calls share one cache read
+// and, on a miss, one database query.
+func (s *Service) Current(ctx context.Context) ([]i18n.ExchangeRate, error) {
+	rates, shared, err := s.current.Do(ctx, currentKey, s.loadCurrent)
+	if shared {
+		rates = slices.Clone(rates)
+	}
+	return rates, err
+}
+
+// loadCurrent reads the current rates from the cache, falling back to the
+// store
+func (s *Service) loadCurrent(ctx context.Context) ([]i18n.ExchangeRate, error) {
+	var rates []i18n.ExchangeRate
+	found, err := s.cache.Get(ctx, currentKey, &rates)
+	if err != nil {
+		s.logger.Warn("Failed to read cached rates", zap.Error(err))
+	}
+	if found && err == nil {
+		return rates, nil
+	}
+
+	rates, err = s.store.Latest(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if len(rates) > 0 {
+		if err := s.cache.Set(ctx, currentKey, rates, s.cacheTTL); err != nil {
+			s.logger.Warn("Failed to cache rates", zap.Error(err))
+		}
+	}
+	return rates, nil
+}
+
+// Rate returns the current rate from base to quote, preferring a pinned
+// override of the pair. Rates the provider does not quote directly are
+// derived by inverting a quoted rate or crossing two rates through a shared
+// currency.
+func (s *Service) Rate(ctx context.Context, base, quote string) (*i18n.ExchangeRate, error) {
+	rate, _, err := s.lookup(ctx, base, quote)
+	return rate, err
+}
+
+// lookup is Rate, also returning where the rate came from: SourceOverride
+// or the provider's name
+func (s *Service) lookup(ctx context.Context, base, quote string) (*i18n.ExchangeRate, string, error) {
+	if s.overrides != nil {
+		overrides, err := s.overrides.Overrides(ctx)
+		if err != nil {
+			return nil, "", err
+		}
+		if rate, err := findRate(overrides, base, quote); err == nil {
+			return rate, SourceOverride, nil
+		}
+	}
+
+	rates, err := s.Current(ctx)
+	if err != nil {
+		return nil, "", err
+	}
+
+	rate, err := findRate(rates, base, quote)
+	if err != nil {
+		return nil, "", err
+	}
+	if s.maxAge > 0 {
+		if age := s.clock.Since(rate.Timestamp.ToTime()); age > s.maxAge {
+			return nil, "", domainerror.Unavailablef("exchange rate %s/%s is stale: observed %s ago", base, quote, age)
+		}
+	}
+	return rate, s.provider.Name(), nil
+}
+
+// History returns the stored rates of a pair observed since the given time
+func (s *Service) History(ctx context.Context, base, quote string, since time.Time) ([]i18n.ExchangeRate, error) {
+	return s.store.History(ctx, base, quote, since)
+}
+
+// findRate looks for base/quote directly, inverted, or crossed through one
+// intermediate currency
+func findRate(rates []i18n.ExchangeRate, base, quote string) (*i18n.ExchangeRate, error) {
+	if base == quote {
+		return nil, domainerror.Invalidf("no exchange rate between %s and itself", base)
+	}
+
+	// Index every quoted pair in both directions
+	legs := make(map[string]map[string]i18n.ExchangeRate)
+	addLeg := func(rate i18n.ExchangeRate) {
+		if legs[rate.Base.Code] == nil {
+			legs[rate.Base.Code] = make(map[string]i18n.ExchangeRate)
+		}
+		if _, exists := legs[rate.Base.Code][rate.Quote.Code]; !exists {
+			legs[rate.Base.Code][rate.Quote.Code] = rate
+		}
+	}
+	for _, rate := range rates {
+		addLeg(rate)
+	}
+	for _, rate := range rates {
+		if inverse, err := rate.Invert(); err == nil {
+			addLeg(*inverse)
+		}
+	}
+
+	if rate, ok := legs[base][quote]; ok {
+		return &rate, nil
+	}
+
+	// Try intermediates in a fixed order so repeated lookups agree
+	vias := make([]string, 0, len(legs[base]))
+	for via := range legs[base] {
+		vias = append(vias, via)
+	}
+	sort.Strings(vias)
+	for _, via := range vias {
+		if second, ok := legs[via][quote]; ok {
+			first := legs[base][via]
+			return first.Cross(&second)
+		}
+	}
+	return nil, domainerror.NotFoundf("no exchange rate from %s to %s", base, quote)
+}
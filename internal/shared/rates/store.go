@@ -0,0 +1,110 @@
+package rates
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	i18n "golang-arch/internal/shared/domain/internationalization"
+)
+
+// Store persists every fetched rate so past conversions can be audited
+type Store interface {
+	// Save appends the rates fetched from provider to the history
+	Save(ctx context.Context, provider string, rates []i18n.ExchangeRate) error
+	// Latest returns the most recent rate of every currency pair
+	Latest(ctx context.Context) ([]i18n.ExchangeRate, error)
+	// History returns the rates of one pair observed since the given time, oldest first
+	History(ctx context.Context, base, quote string, since time.Time) ([]i18n.ExchangeRate, error)
+}
+
+// PostgresStore keeps the rate history in the exchange_rates table
+type PostgresStore struct {
+	db *sql.DB
+}
+
+// NewPostgresStore creates a store on db
+func NewPostgresStore(db *sql.DB) *PostgresStore {
+	return &PostgresStore{db: db}
+}
+
+// Save inserts the rates in one transaction
+func (s *PostgresStore) Save(ctx context.Context, provider string, rates []i18n.ExchangeRate) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin rates transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	for _, rate := range rates {
+		base, quote, value, scale, observedAt := rate.ToPrimitive()
+		_, err := tx.ExecContext(ctx,
+			`INSERT INTO exchange_rates (provider, base_code, quote_code, rate, scale, observed_at)
+			VALUES ($1, $2, $3, $4, $5, $6)`,
+			provider, base, quote, value, scale, observedAt,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to save rate %s/%s: %w", base, quote, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit rates: %w", err)
+	}
+	return nil
+}
+
+// Latest returns the newest row of every pair
+func (s *PostgresStore) Latest(ctx context.Context) ([]i18n.ExchangeRate, error) {
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT DISTINCT ON (base_code, quote_code) base_code, quote_code, rate, scale, observed_at
+		FROM exchange_rates
+		ORDER BY base_code, quote_code, observed_at DESC, id DESC`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query latest rates: %w", err)
+	}
+	return scanRates(rows)
+}
+
+// History returns the rows of one pair observed since the given time
+func (s *PostgresStore) History(ctx context.Context, base, quote string, since time.Time) ([]i18n.ExchangeRate, error) {
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT base_code, quote_code, rate, scale, observed_at
+		FROM exchange_rates
+		WHERE base_code = $1 AND quote_code = $2 AND observed_at >= $3
+		ORDER BY observed_at, id`,
+		base, quote, since.Unix())
+	if err != nil {
+		return nil, fmt.Errorf("failed to query %s/%s rate history: %w", base, quote, err)
+	}
+	return scanRates(rows)
+}
+
+// scanRates reads (base_code, quote_code, rate, scale, observed_at) rows
+func scanRates(rows *sql.Rows) ([]i18n.ExchangeRate, error) {
+	defer rows.Close()
+
+	var rates []i18n.ExchangeRate
+	for rows.Next() {
+		var (
+			base, quote string
+			value       int64
+			scale       int
+			observedAt  int64
+		)
+		if err := rows.Scan(&base, &quote, &value, &scale, &observedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan rate: %w", err)
+		}
+
+		rate, err := i18n.NewExchangeRateFromPrimitive(base, quote, value, scale, observedAt)
+		if err != nil {
+			return nil, err
+		}
+		rates = append(rates, *rate)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read rates: %w", err)
+	}
+	return rates, nil
+}
@@ -0,0 +1,116 @@
+package rates
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	i18n "golang-arch/internal/shared/domain/internationalization"
+	"golang-arch/pkg/clock"
+)
+
+// RateSource looks up the rate from base to quote; Service implements it
+type RateSource interface {
+	Rate(ctx context.Context, base, quote string) (*i18n.ExchangeRate, error)
+}
+
+// Valuation values multi-currency holdings in a single currency, e.g. the
+// "total balance in USD" of a dashboard
+type Valuation struct {
+	rates RateSource
+	mode  i18n.RoundingMode
+	clock clock.Clock
+}
+
+// ValuationOption customizes a Valuation
+type ValuationOption func(*Valuation)
+
+// WithRoundingMode sets how converted amounts are rounded to the target
+// currency's minor units; the default is half-even
+func WithRoundingMode(mode i18n.RoundingMode) ValuationOption {
+	return func(v *Valuation) {
+		v.mode = mode
+	}
+}
+
+// WithValuationClock replaces the system clock used to stamp results
+func WithValuationClock(c clock.Clock) ValuationOption {
+	return func(v *Valuation) {
+		v.clock = c
+	}
+}
+
+// NewValuation creates a valuation backed by source
+func NewValuation(source RateSource, options ...ValuationOption) *Valuation {
+	v := &Valuation{
+		rates: source,
+		mode:  i18n.RoundHalfEven,
+		clock: clock.New(),
+	}
+	for _, option := range options {
+		option(v)
+	}
+	return v
+}
+
+// ValuationLine is one currency of a valuation
+type ValuationLine struct {
+	Amount    i18n.Money         `json:"amount"`         // Holding in its own currency
+	Converted i18n.Money         `json:"converted"`      // Holding in the target currency
+	Rate      *i18n.ExchangeRate `json:"rate,omitempty"` // Rate applied; nil when already in the target currency
+}
+
+// ValuationResult is the total of a MoneyBag in one currency with the
+// breakdown and rates it was computed from
+type ValuationResult struct {
+	Total     i18n.Money          `json:"total"`
+	Breakdown []ValuationLine     `json:"breakdown"`
+	Rates     []i18n.ExchangeRate `json:"rates"`
+	ValuedAt  time.Time           `json:"valued_at"`
+}
+
+// Value converts every currency in bag to target and sums the results. Each
+// line is rounded to target's minor units before summing, so the breakdown
+// always adds up to Total.
+func (v *Valuation) Value(ctx context.Context, bag *i18n.MoneyBag, target string) (*ValuationResult, error) {
+	targetCurrency, err := i18n.NewCurrencyFromCode(target)
+	if err != nil {
+		return nil, fmt.Errorf("invalid valuation currency: %w", err)
+	}
+
+	result := &ValuationResult{
+		Total:     i18n.Money{Currency: *targetCurrency},
+		Breakdown: []ValuationLine{},
+		Rates:     []i18n.ExchangeRate{},
+		ValuedAt:  v.clock.Now(),
+	}
+	if bag == nil {
+		return result, nil
+	}
+
+	for _, amount := range bag.Amounts() {
+		line := ValuationLine{Amount: amount, Converted: amount}
+		if amount.Currency.Code != targetCurrency.Code {
+			rate, err := v.rates.Rate(ctx, amount.Currency.Code, targetCurrency.Code)
+			if err != nil {
+				return nil, fmt.Errorf("failed to value %s in %s: %w", amount.Currency.Code, targetCurrency.Code, err)
+			}
+
+			converted, err := rate.Convert(amount, v.mode)
+			if err != nil {
+				return nil, fmt.Errorf("failed to value %s in %s: %w", amount.Currency.Code, targetCurrency.Code, err)
+			}
+			line.Converted = *converted
+			line.Rate = rate
+			result.Rates = append(result.Rates, *rate)
+		}
+
+		total, err := result.Total.Add(&line.Converted)
+		if err != nil {
+			return nil, err
+		}
+		result.Total = *total
+		result.Breakdown = append(result.Breakdown, line)
+	}
+	return result, nil
+}
@@ -0,0 +1,146 @@
+package rbac
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"golang-arch/internal/shared/api"
+	"golang-arch/internal/shared/config"
+	"golang-arch/internal/shared/domain/domainerror"
+)
+
+// Permission names an action a role may perform, e.g. "i18n:write"
+type Permission string
+
+// Principal is the authenticated caller of a request
+type Principal struct {
+	Name        string
+	Roles       []string
+	permissions map[Permission]bool
+}
+
+// Can reports whether the principal's roles grant permission
+func (p Principal) Can(permission Permission) bool {
+	return p.permissions[permission]
+}
+
+// Authorizer resolves bearer tokens to principals and checks their permissions
+type Authorizer struct {
+	principals map[[sha256.Size]byte]Principal
+}
+
+// NewAuthorizer builds the token table of cfg. Principals without a name or
+// token, duplicate tokens and unknown roles are errors.
+func NewAuthorizer(cfg config.RBACConfig) (*Authorizer, error) {
+	a := &Authorizer{principals: make(map[[sha256.Size]byte]Principal, len(cfg.Principals))}
+	for i, pc := range cfg.Principals {
+		if strings.TrimSpace(pc.Name) == "" || pc.Token == "" {
+			return nil, fmt.Errorf("rbac principal %d needs a name and a token", i)
+		}
+		principal := Principal{Name: pc.Name, Roles: pc.Roles, permissions: make(map[Permission]bool)}
+		for _, role := range pc.Roles {
+			permissions, ok := cfg.Roles[role]
+			if !ok {
+				return nil, fmt.Errorf("rbac principal %s has unknown role %q", pc.Name, role)
+			}
+			for _, permission := range permissions {
+				principal.permissions[Permission(permission)] = true
+			}
+		}
+
+		key := sha256.Sum256([]byte(pc.Token))
+		if _, exists := a.principals[key]; exists {
+			return nil, fmt.Errorf("rbac principal %s reuses the token of another principal", pc.Name)
+		}
+		a.principals[key] = principal
+	}
+	return a, nil
+}
+
+// Authenticate returns the principal holding token. Tokens are compared by
+// their SHA-256 digest, so lookups take the same time whatever the input.
+func (a *Authorizer) Authenticate(token string) (Principal, bool) {
+	if token == "" {
+		return Principal{}, false
+	}
+	principal, ok := a.principals[sha256.Sum256([]byte(token))]
+	return principal, ok
+}
+
+// Require rejects requests without a known bearer token with 401 and those
+// whose principal lacks any of the permissions with 403. The principal is
+// stored in the request context for handlers.
+func (a *Authorizer) Require(permissions ...Permission) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		principal, ok := a.authenticate(c)
+		if !ok {
+			return
+		}
+
+		if missing := slices.IndexFunc(permissions, func(p Permission) bool { return !principal.Can(p) }); missing >= 0 {
+			api.RespondError(c, domainerror.Forbiddenf("%s lacks the %s permission", principal.Name, permissions[missing]))
+			c.Abort()
+			return
+		}
+
+		c.Request = c.Request.WithContext(NewContext(c.Request.Context(), principal))
+		c.Next()
+	}
+}
+
+// RequireOwner is Require for routes about one subject: the principal named
+// by path parameter param may use them without permission, e.g. a user's own
+// preferences, while anyone else needs permission.
+func (a *Authorizer) RequireOwner(param string, permission Permission) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		principal, ok := a.authenticate(c)
+		if !ok {
+			return
+		}
+
+		if principal.Name != c.Param(param) && !principal.Can(permission) {
+			api.RespondError(c, domainerror.Forbiddenf("%s may only access its own records without the %s permission", principal.Name, permission))
+			c.Abort()
+			return
+		}
+
+		c.Request = c.Request.WithContext(NewContext(c.Request.Context(), principal))
+		c.Next()
+	}
+}
+
+// authenticate returns the principal of the request, answering 401 and
+// aborting when there is none
+func (a *Authorizer) authenticate(c *gin.Context) (Principal, bool) {
+	principal, ok := FromContext(c.Request.Context())
+	if !ok {
+		token, found := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+		if found {
+			principal, ok = a.Authenticate(token)
+		}
+	}
+	if !ok {
+		c.Header("WWW-Authenticate", "Bearer")
+		api.RespondError(c, api.NewUnauthorizedError("a valid bearer token is required"))
+		c.Abort()
+	}
+	return principal, ok
+}
+
+type contextKey struct{}
+
+// NewContext returns ctx carrying principal
+func NewContext(ctx context.Context, principal Principal) context.Context {
+	return context.WithValue(ctx, contextKey{}, principal)
+}
+
+// FromContext returns the principal authenticated for the request
+func FromContext(ctx context.Context) (Principal, bool) {
+	principal, ok := ctx.Value(contextKey{}).(Principal)
+	return principal, ok
+}
@@ -0,0 +1,219 @@
+package refdata
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"golang-arch/internal/shared/api"
+	"golang-arch/internal/shared/rbac"
+)
+
+// Permissions checked by the admin routes
+const (
+	PermissionRead  rbac.Permission = "i18n:read"
+	PermissionWrite rbac.Permission = "i18n:write"
+)
+
+// Handler exposes the reference data administration API
+type Handler struct {
+	service *Service
+	authz   *rbac.Authorizer
+}
+
+// NewHandler creates the admin handler; authz guards every route
+func NewHandler(service *Service, authz *rbac.Authorizer) *Handler {
+	return &Handler{service: service, authz: authz}
+}
+
+// Register adds the admin routes to group. Reads need i18n:read, writes
+// i18n:write:
+//
+//	GET    /admin/i18n/currencies                       all currencies
+//	GET    /admin/i18n/currencies/:code                 one currency
+//	PUT    /admin/i18n/currencies/:code                 create or replace
+//	DELETE /admin/i18n/currencies/:code
+//	GET    /admin/i18n/rate-overrides                   all overrides
+//	PUT    /admin/i18n/rate-overrides/:base/:quote      pin a rate
+//	DELETE /admin/i18n/rate-overrides/:base/:quote
+//	GET    /admin/i18n/translations?locale=             translations
+//	PUT    /admin/i18n/translations/:locale/:key        create or replace
+//	DELETE /admin/i18n/translations/:locale/:key
+//	GET    /admin/i18n/holidays?calendar=               holidays
+//	PUT    /admin/i18n/holidays/:calendar/:date         create or replace
+//	DELETE /admin/i18n/holidays/:calendar/:date
+func (h *Handler) Register(group *gin.RouterGroup) {
+	admin := group.Group("/admin/i18n")
+	read, write := h.authz.Require(PermissionRead), h.authz.Require(PermissionWrite)
+
+	admin.GET("/currencies", read, h.listCurrencies)
+	admin.GET("/currencies/:code", read, h.getCurrency)
+	admin.PUT("/currencies/:code", write, h.saveCurrency)
+	admin.DELETE("/currencies/:code", write, h.deleteCurrency)
+
+	admin.GET("/rate-overrides", read, h.listRateOverrides)
+	admin.PUT("/rate-overrides/:base/:quote", write, h.saveRateOverride)
+	admin.DELETE("/rate-overrides/:base/:quote", write, h.deleteRateOverride)
+
+	admin.GET("/translations", read, h.listTranslations)
+	admin.PUT("/translations/:locale/:key", write, h.saveTranslation)
+	admin.DELETE("/translations/:locale/:key", write, h.deleteTranslation)
+
+	admin.GET("/holidays", read, h.listHolidays)
+	admin.PUT("/holidays/:calendar/:date", write, h.saveHoliday)
+	admin.DELETE("/holidays/:calendar/:date", write, h.deleteHoliday)
+}
+
+// CurrencyBody creates or replaces a currency
+type CurrencyBody struct {
+	Symbol        string `json:"symbol" binding:"required"`
+	Name          string `json:"name" binding:"required"`
+	DecimalPlaces *int   `json:"decimal_places" binding:"required"`
+	Enabled       *bool  `json:"enabled"` // Defaults to true
+}
+
+// RateOverrideBody pins the rate of a pair
+type RateOverrideBody struct {
+	Rate      string     `json:"rate" binding:"required"` // Decimal units of quote per base
+	Reason    string     `json:"reason"`
+	ExpiresAt *time.Time `json:"expires_at"` // RFC 3339; omit to pin until deleted
+}
+
+// TranslationBody sets the text of a key in a locale
+type TranslationBody struct {
+	Text string `json:"text" binding:"required"`
+}
+
+// HolidayBody names a holiday
+type HolidayBody struct {
+	Name string `json:"name" binding:"required"`
+}
+
+// actor names the principal making the change
+func actor(c *gin.Context) string {
+	principal, _ := rbac.FromContext(c.Request.Context())
+	return principal.Name
+}
+
+// respond sends data, or err mapped to its status
+func respond[T any](c *gin.Context, data T, err error, message string) {
+	if err != nil {
+		api.RespondError(c, err)
+		return
+	}
+	api.Success(c, data, message)
+}
+
+// nonNil keeps empty lists as [] in responses
+func nonNil[T any](items []T) []T {
+	if items == nil {
+		return []T{}
+	}
+	return items
+}
+
+func (h *Handler) listCurrencies(c *gin.Context) {
+	currencies, err := h.service.Currencies(c.Request.Context())
+	respond(c, nonNil(currencies), err, "currencies")
+}
+
+func (h *Handler) getCurrency(c *gin.Context) {
+	currency, err := h.service.Currency(c.Request.Context(), c.Param("code"))
+	respond(c, currency, err, "currency")
+}
+
+func (h *Handler) saveCurrency(c *gin.Context) {
+	var body CurrencyBody
+	if !api.BindJSON(c, &body) {
+		return
+	}
+	enabled := body.Enabled == nil || *body.Enabled
+	currency, err := h.service.SaveCurrency(c.Request.Context(), Currency{
+		Code:          c.Param("code"),
+		Symbol:        body.Symbol,
+		Name:          body.Name,
+		DecimalPlaces: *body.DecimalPlaces,
+		Enabled:       enabled,
+		UpdatedBy:     actor(c),
+	})
+	respond(c, currency, err, "currency saved")
+}
+
+func (h *Handler) deleteCurrency(c *gin.Context) {
+	err := h.service.DeleteCurrency(c.Request.Context(), c.Param("code"))
+	respond[any](c, nil, err, "currency deleted")
+}
+
+func (h *Handler) listRateOverrides(c *gin.Context) {
+	overrides, err := h.service.RateOverrides(c.Request.Context())
+	respond(c, nonNil(overrides), err, "rate overrides")
+}
+
+func (h *Handler) saveRateOverride(c *gin.Context) {
+	var body RateOverrideBody
+	if !api.BindJSON(c, &body) {
+		return
+	}
+	override, err := h.service.SaveRateOverride(c.Request.Context(), RateOverride{
+		Base:      c.Param("base"),
+		Quote:     c.Param("quote"),
+		Rate:      body.Rate,
+		Reason:    body.Reason,
+		ExpiresAt: body.ExpiresAt,
+		UpdatedBy: actor(c),
+	})
+	respond(c, override, err, "rate override saved")
+}
+
+func (h *Handler) deleteRateOverride(c *gin.Context) {
+	err := h.service.DeleteRateOverride(c.Request.Context(), c.Param("base"), c.Param("quote"))
+	respond[any](c, nil, err, "rate override deleted")
+}
+
+func (h *Handler) listTranslations(c *gin.Context) {
+	translations, err := h.service.Translations(c.Request.Context(), c.Query("locale"))
+	respond(c, nonNil(translations), err, "translations")
+}
+
+func (h *Handler) saveTranslation(c *gin.Context) {
+	var body TranslationBody
+	if !api.BindJSON(c, &body) {
+		return
+	}
+	translation, err := h.service.SaveTranslation(c.Request.Context(), Translation{
+		Key:       c.Param("key"),
+		Locale:    c.Param("locale"),
+		Text:      body.Text,
+		UpdatedBy: actor(c),
+	})
+	respond(c, translation, err, "translation saved")
+}
+
+func (h *Handler) deleteTranslation(c *gin.Context) {
+	err := h.service.DeleteTranslation(c.Request.Context(), c.Param("key"), c.Param("locale"))
+	respond[any](c, nil, err, "translation deleted")
+}
+
+func (h *Handler) listHolidays(c *gin.Context) {
+	holidays, err := h.service.Holidays(c.Request.Context(), c.Query("calendar"))
+	respond(c, nonNil(holidays), err, "holidays")
+}
+
+func (h *Handler) saveHoliday(c *gin.Context) {
+	var body HolidayBody
+	if !api.BindJSON(c, &body) {
+		return
+	}
+	holiday, err := h.service.SaveHoliday(c.Request.Context(), Holiday{
+		Calendar:  c.Param("calendar"),
+		Date:      c.Param("date"),
+		Name:      body.Name,
+		UpdatedBy: actor(c),
+	})
+	respond(c, holiday, err, "holiday saved")
+}
+
+func (h *Handler) deleteHoliday(c *gin.Context) {
+	err := h.service.DeleteHoliday(c.Request.Context(), c.Param("calendar"), c.Param("date"))
+	respond[any](c, nil, err, "holiday deleted")
+}
@@ -0,0 +1,193 @@
+package refdata
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/lib/pq"
+	"go.uber.org/zap"
+)
+
+// Notifier broadcasts changes to every instance of the application,
+// including the one making them
+type Notifier interface {
+	// Notify announces change
+	Notify(ctx context.Context, change Change) error
+	// Subscribe calls handler with every change announced from now on
+	Subscribe(handler func(Change)) error
+	// Close stops listening
+	Close() error
+}
+
+// handlers is the subscriber list shared by the notifiers
+type handlers struct {
+	mu   sync.Mutex
+	list []func(Change)
+}
+
+func (h *handlers) add(handler func(Change)) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.list = append(h.list, handler)
+}
+
+func (h *handlers) dispatch(change Change) {
+	h.mu.Lock()
+	list := h.list
+	h.mu.Unlock()
+	for _, handler := range list {
+		handler(change)
+	}
+}
+
+// LocalNotifier delivers changes within the process, for tests, the dev
+// profile and single-instance deployments
+type LocalNotifier struct {
+	handlers handlers
+}
+
+// NewLocalNotifier creates an in-process notifier
+func NewLocalNotifier() *LocalNotifier {
+	return &LocalNotifier{}
+}
+
+// Notify calls the subscribers synchronously
+func (n *LocalNotifier) Notify(_ context.Context, change Change) error {
+	n.handlers.dispatch(change)
+	return nil
+}
+
+// Subscribe adds handler
+func (n *LocalNotifier) Subscribe(handler func(Change)) error {
+	n.handlers.add(handler)
+	return nil
+}
+
+// Close does nothing
+func (n *LocalNotifier) Close() error {
+	return nil
+}
+
+// listenerPingInterval is how often an idle LISTEN connection is checked
+const listenerPingInterval = 90 * time.Second
+
+// PostgresNotifier announces changes with NOTIFY and receives them with
+// LISTEN on a dedicated connection. After the connection is lost and
+// re-established, subscribers get a Change without Kind because
+// notifications sent in between were missed.
+type PostgresNotifier struct {
+	db      *sql.DB
+	dsn     string
+	channel string
+	logger  *zap.Logger
+
+	handlers handlers
+	once     sync.Once
+	listener *pq.Listener
+	done     chan struct{}
+}
+
+// NewPostgresNotifier creates a notifier that sends on db and listens on
+// its own connection to dsn
+func NewPostgresNotifier(db *sql.DB, dsn, channel string, logger *zap.Logger) *PostgresNotifier {
+	return &PostgresNotifier{db: db, dsn: dsn, channel: channel, logger: logger, done: make(chan struct{})}
+}
+
+// Notify sends change as the JSON payload of a notification on the channel
+func (n *PostgresNotifier) Notify(ctx context.Context, change Change) error {
+	payload, err := json.Marshal(change)
+	if err != nil {
+		return fmt.Errorf("failed to encode change: %w", err)
+	}
+	if _, err := n.db.ExecContext(ctx, `SELECT pg_notify($1, $2)`, n.channel, string(payload)); err != nil {
+		return fmt.Errorf("failed to notify %s: %w", n.channel, err)
+	}
+	return nil
+}
+
+// Subscribe adds handler, starting to listen on the first call. Connecting
+// happens in the background, so an unreachable database is retried rather
+// than failing startup.
+func (n *PostgresNotifier) Subscribe(handler func(Change)) error {
+	n.handlers.add(handler)
+	n.once.Do(func() {
+		n.listener = pq.NewListener(n.dsn, time.Second, time.Minute, n.event)
+		go n.run()
+	})
+	return nil
+}
+
+// event logs the state changes of the listener connection
+func (n *PostgresNotifier) event(event pq.ListenerEventType, err error) {
+	switch event {
+	case pq.ListenerEventConnectionAttemptFailed:
+		n.logger.Warn("Reference data listener failed to connect", zap.Error(err))
+	case pq.ListenerEventDisconnected:
+		n.logger.Warn("Reference data listener disconnected", zap.Error(err))
+	case pq.ListenerEventReconnected:
+		n.logger.Info("Reference data listener reconnected")
+	}
+}
+
+func (n *PostgresNotifier) run() {
+	// Listen blocks until the first connection succeeds
+	if err := n.listener.Listen(n.channel); err != nil {
+		select {
+		case <-n.done:
+		default:
+			n.logger.Error("Reference data listener stopped", zap.String("channel", n.channel), zap.Error(err))
+		}
+		return
+	}
+
+	ticker := time.NewTicker(listenerPingInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case notification, ok := <-n.listener.Notify:
+			if !ok {
+				return
+			}
+			n.handlers.dispatch(n.decode(notification))
+		case <-ticker.C:
+			go func() {
+				if err := n.listener.Ping(); err != nil {
+					n.logger.Debug("Reference data listener ping failed", zap.Error(err))
+				}
+			}()
+		case <-n.done:
+			return
+		}
+	}
+}
+
+// decode parses a notification; nil, sent after a reconnect, and payloads
+// that cannot be parsed invalidate everything
+func (n *PostgresNotifier) decode(notification *pq.Notification) Change {
+	if notification == nil {
+		return Change{}
+	}
+	var change Change
+	if err := json.Unmarshal([]byte(notification.Extra), &change); err != nil {
+		n.logger.Warn("Malformed reference data notification, dropping all caches", zap.String("payload", notification.Extra), zap.Error(err))
+		return Change{}
+	}
+	return change
+}
+
+// Close stops listening and closes the listener connection
+func (n *PostgresNotifier) Close() error {
+	if n.listener == nil {
+		return nil
+	}
+	close(n.done)
+	if err := n.listener.Close(); err != nil {
+		return fmt.Errorf("failed to close reference data listener: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,142 @@
+package refdata
+
+import (
+	"strings"
+	"time"
+
+	i18n "golang-arch/internal/shared/domain/internationalization"
+	"golang-arch/internal/shared/domain/validation"
+)
+
+// Kind names one table of reference data
+type Kind string
+
+const (
+	KindCurrencies   Kind = "currencies"
+	KindRates        Kind = "rate_overrides"
+	KindTranslations Kind = "translations"
+	KindHolidays     Kind = "holidays"
+)
+
+// Change announces that reference data was modified. An empty Kind means
+// any of it may have changed, e.g. after notifications were missed.
+type Change struct {
+	Kind Kind   `json:"kind,omitempty"`
+	Key  string `json:"key,omitempty"` // Identifies the changed row, for logs
+}
+
+// Currency is a currency the application accepts
+type Currency struct {
+	Code          string    `json:"code"`
+	Symbol        string    `json:"symbol"`
+	Name          string    `json:"name"`
+	DecimalPlaces int       `json:"decimal_places"`
+	Enabled       bool      `json:"enabled"` // Disabled currencies are kept but not offered
+	UpdatedBy     string    `json:"updated_by"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// Validate checks the currency with the i18n rules
+func (c Currency) Validate() error {
+	var errs validation.ValidationErrors
+	_, err := i18n.NewCurrency(c.Code, c.Symbol, c.Name, c.DecimalPlaces)
+	errs.Merge("", "", err)
+	return errs.Err()
+}
+
+// RateOverride pins the exchange rate of a pair, taking precedence over the
+// rates provider until it expires or is deleted
+type RateOverride struct {
+	Base      string     `json:"base"`
+	Quote     string     `json:"quote"`
+	Rate      string     `json:"rate"` // Decimal units of Quote per Base, e.g. "0.9221"
+	Reason    string     `json:"reason"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"` // Nil pins the rate until deleted
+	UpdatedBy string     `json:"updated_by"`
+	UpdatedAt time.Time  `json:"updated_at"`
+}
+
+// Validate checks the currencies and the rate
+func (o RateOverride) Validate() error {
+	var errs validation.ValidationErrors
+	_, err := i18n.NewCurrencyFromCode(o.Base)
+	errs.Merge("base", "", err)
+	_, err = i18n.NewCurrencyFromCode(o.Quote)
+	errs.Merge("quote", "", err)
+	if len(errs) > 0 {
+		return errs.Err()
+	}
+
+	if strings.EqualFold(o.Base, o.Quote) {
+		errs.Add("quote", validation.CodeInvalid, "quote must differ from base", nil)
+	}
+	_, err = o.ExchangeRate()
+	errs.Merge("rate", "", err)
+	return errs.Err()
+}
+
+// Active reports whether the override applies at now
+func (o RateOverride) Active(now time.Time) bool {
+	return o.ExpiresAt == nil || now.Before(*o.ExpiresAt)
+}
+
+// ExchangeRate returns the override as a rate observed when it was saved
+func (o RateOverride) ExchangeRate() (*i18n.ExchangeRate, error) {
+	base, err := i18n.NewCurrencyFromCode(o.Base)
+	if err != nil {
+		return nil, err
+	}
+	quote, err := i18n.NewCurrencyFromCode(o.Quote)
+	if err != nil {
+		return nil, err
+	}
+	return i18n.NewExchangeRateFromDecimal(*base, *quote, o.Rate, *i18n.NewTimeFromTime(o.UpdatedAt))
+}
+
+// Translation is the text of one message key in one locale
+type Translation struct {
+	Key       string    `json:"key"`
+	Locale    string    `json:"locale"` // BCP 47 tag, e.g. "pt-BR"
+	Text      string    `json:"text"`
+	UpdatedBy string    `json:"updated_by"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Validate checks the key, locale and text
+func (t Translation) Validate() error {
+	var errs validation.ValidationErrors
+	if strings.TrimSpace(t.Key) == "" {
+		errs.Add("key", validation.CodeRequired, "key is required", nil)
+	}
+	if _, err := i18n.ParseLocale(t.Locale); err != nil {
+		errs.Merge("locale", "", err)
+	}
+	if t.Text == "" {
+		errs.Add("text", validation.CodeRequired, "text is required", nil)
+	}
+	return errs.Err()
+}
+
+// Holiday is a non-working day of a named business calendar
+type Holiday struct {
+	Calendar  string    `json:"calendar"` // e.g. "ID" or "NYSE"
+	Date      string    `json:"date"`     // YYYY-MM-DD in the calendar's timezone
+	Name      string    `json:"name"`
+	UpdatedBy string    `json:"updated_by"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Validate checks the calendar, date and name
+func (h Holiday) Validate() error {
+	var errs validation.ValidationErrors
+	if strings.TrimSpace(h.Calendar) == "" {
+		errs.Add("calendar", validation.CodeRequired, "calendar is required", nil)
+	}
+	if _, err := i18n.ParseDate(h.Date); err != nil {
+		errs.Merge("date", "", err)
+	}
+	if strings.TrimSpace(h.Name) == "" {
+		errs.Add("name", validation.CodeRequired, "name is required", nil)
+	}
+	return errs.Err()
+}
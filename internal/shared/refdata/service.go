@@ -0,0 +1,467 @@
+package refdata
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"sync"
+
+	"go.uber.org/zap"
+
+	"golang-arch/internal/shared/domain/domainerror"
+	i18n "golang-arch/internal/shared/domain/internationalization"
+	"golang-arch/internal/shared/templates"
+	"golang-arch/pkg/clock"
+)
+
+// Service manages the reference data and serves reads from per-kind caches.
+// Every write is announced through the notifier, and every instance drops
+// the cache of the changed kind when the announcement arrives.
+type Service struct {
+	store    Store
+	notifier Notifier
+	clock    clock.Clock
+	logger   *zap.Logger
+
+	currencies   cached[Currency]
+	overrides    cached[RateOverride]
+	translations cached[Translation]
+	holidays     cached[Holiday]
+
+	// registry, when set, holds the enabled currencies; synced are those
+	// registered by SyncCurrencies, which it may update or remove again
+	registry *i18n.CurrencyRegistry
+	syncMu   sync.Mutex
+	synced   map[string]Currency
+
+	hooksMu sync.Mutex
+	hooks   []func(Change)
+}
+
+// Option configures a Service
+type Option func(*Service)
+
+// WithClock sets the clock used to timestamp changes and expire overrides
+func WithClock(c clock.Clock) Option {
+	return func(s *Service) {
+		s.clock = c
+	}
+}
+
+// WithLogger sets the logger
+func WithLogger(logger *zap.Logger) Option {
+	return func(s *Service) {
+		s.logger = logger
+	}
+}
+
+// WithCurrencyRegistry keeps registry in step with the stored currencies:
+// enabled ones are registered and disabled or deleted ones unregistered.
+// Built-in currencies of the package registry stay registered regardless.
+func WithCurrencyRegistry(registry *i18n.CurrencyRegistry) Option {
+	return func(s *Service) {
+		s.registry = registry
+	}
+}
+
+// NewService creates the service on store and subscribes to notifier
+func NewService(store Store, notifier Notifier, options ...Option) (*Service, error) {
+	s := &Service{
+		store:    store,
+		notifier: notifier,
+		clock:    clock.New(),
+		logger:   zap.NewNop(),
+		synced:   make(map[string]Currency),
+	}
+	for _, option := range options {
+		option(s)
+	}
+	if err := notifier.Subscribe(s.invalidate); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// OnChange calls handler with every change, local or announced, after the
+// caches were dropped, so reads from handler see the new data. Handlers run
+// on the notifier's goroutine and may see a change more than once.
+func (s *Service) OnChange(handler func(Change)) {
+	s.hooksMu.Lock()
+	defer s.hooksMu.Unlock()
+	s.hooks = append(s.hooks, handler)
+}
+
+// invalidate drops the caches named by change, resyncs the currency
+// registry and runs the OnChange handlers
+func (s *Service) invalidate(change Change) {
+	s.logger.Debug("Reference data changed", zap.String("kind", string(change.Kind)), zap.String("key", change.Key))
+	switch change.Kind {
+	case KindCurrencies:
+		s.currencies.invalidate()
+	case KindRates:
+		s.overrides.invalidate()
+	case KindTranslations:
+		s.translations.invalidate()
+	case KindHolidays:
+		s.holidays.invalidate()
+	default:
+		s.currencies.invalidate()
+		s.overrides.invalidate()
+		s.translations.invalidate()
+		s.holidays.invalidate()
+	}
+
+	if change.Kind == KindCurrencies || change.Kind == "" {
+		if err := s.SyncCurrencies(context.Background()); err != nil {
+			s.logger.Error("Failed to sync the currency registry", zap.Error(err))
+		}
+	}
+
+	s.hooksMu.Lock()
+	hooks := s.hooks
+	s.hooksMu.Unlock()
+	for _, hook := range hooks {
+		hook(change)
+	}
+}
+
+// changed drops the local cache and tells the other instances. The write
+// already succeeded, so a failed announcement is logged rather than
+// returned; other instances catch up when their listener reconnects.
+func (s *Service) changed(ctx context.Context, change Change) {
+	s.invalidate(change)
+	if err := s.notifier.Notify(ctx, change); err != nil {
+		s.logger.Error("Failed to announce reference data change",
+			zap.String("kind", string(change.Kind)), zap.String("key", change.Key), zap.Error(err))
+	}
+}
+
+// Currencies returns every currency ordered by code
+func (s *Service) Currencies(ctx context.Context) ([]Currency, error) {
+	return s.currencies.get(ctx, s.store.ListCurrencies)
+}
+
+// Currency returns one currency
+func (s *Service) Currency(ctx context.Context, code string) (Currency, error) {
+	currencies, err := s.Currencies(ctx)
+	if err != nil {
+		return Currency{}, err
+	}
+	code = strings.ToUpper(code)
+	i := slices.IndexFunc(currencies, func(c Currency) bool { return c.Code == code })
+	if i < 0 {
+		return Currency{}, domainerror.NotFoundf("currency %s not found", code)
+	}
+	return currencies[i], nil
+}
+
+// SyncCurrencies registers the enabled currencies in the registry set with
+// WithCurrencyRegistry and unregisters those it registered before that are
+// now disabled, changed or deleted. It does nothing without a registry.
+func (s *Service) SyncCurrencies(ctx context.Context) error {
+	if s.registry == nil {
+		return nil
+	}
+	currencies, err := s.Currencies(ctx)
+	if err != nil {
+		return err
+	}
+
+	s.syncMu.Lock()
+	defer s.syncMu.Unlock()
+	wanted := make(map[string]Currency, len(currencies))
+	for _, currency := range currencies {
+		if currency.Enabled {
+			wanted[currency.Code] = currency
+			continue
+		}
+		if _, ok := s.synced[currency.Code]; !ok {
+			if _, exists := s.registry.Lookup(currency.Code); exists {
+				s.logger.Warn("Currency registered outside the reference data cannot be disabled", zap.String("code", currency.Code))
+			}
+		}
+	}
+
+	for code, previous := range s.synced {
+		if current, ok := wanted[code]; ok && sameCurrency(current, previous) {
+			continue
+		}
+		if err := s.registry.Unregister(code); err != nil && !errors.Is(err, domainerror.NotFound) {
+			return err
+		}
+		delete(s.synced, code)
+	}
+	for code, currency := range wanted {
+		if _, ok := s.synced[code]; ok {
+			continue
+		}
+		if _, exists := s.registry.Lookup(code); exists {
+			// Built in or registered by the application itself
+			continue
+		}
+		registered, err := i18n.NewCurrency(currency.Code, currency.Symbol, currency.Name, currency.DecimalPlaces)
+		if err == nil {
+			err = s.registry.Register(*registered)
+		}
+		if err != nil {
+			s.logger.Warn("Skipping currency the registry refuses", zap.String("code", code), zap.Error(err))
+			continue
+		}
+		s.synced[code] = currency
+	}
+	return nil
+}
+
+// sameCurrency reports whether a and b register the same currency
+func sameCurrency(a, b Currency) bool {
+	return a.Symbol == b.Symbol && a.Name == b.Name && a.DecimalPlaces == b.DecimalPlaces
+}
+
+// SaveCurrency validates and stores the currency
+func (s *Service) SaveCurrency(ctx context.Context, currency Currency) (Currency, error) {
+	currency.Code = strings.ToUpper(currency.Code)
+	if err := currency.Validate(); err != nil {
+		return Currency{}, err
+	}
+	currency.UpdatedAt = s.clock.Now().UTC()
+	if err := s.store.SaveCurrency(ctx, currency); err != nil {
+		return Currency{}, err
+	}
+	s.changed(ctx, Change{Kind: KindCurrencies, Key: currency.Code})
+	return currency, nil
+}
+
+// DeleteCurrency removes the currency
+func (s *Service) DeleteCurrency(ctx context.Context, code string) error {
+	code = strings.ToUpper(code)
+	return s.delete(ctx, "currency", Change{Kind: KindCurrencies, Key: code}, func() (bool, error) {
+		return s.store.DeleteCurrency(ctx, code)
+	})
+}
+
+// RateOverrides returns every override, expired ones included
+func (s *Service) RateOverrides(ctx context.Context) ([]RateOverride, error) {
+	return s.overrides.get(ctx, s.store.ListRateOverrides)
+}
+
+// Overrides returns the overrides in effect as exchange rates; the rates
+// service prefers them over the provider's rates
+func (s *Service) Overrides(ctx context.Context) ([]i18n.ExchangeRate, error) {
+	overrides, err := s.RateOverrides(ctx)
+	if err != nil {
+		return nil, err
+	}
+	now := s.clock.Now()
+	var rates []i18n.ExchangeRate
+	for _, override := range overrides {
+		if !override.Active(now) {
+			continue
+		}
+		rate, err := override.ExchangeRate()
+		if err != nil {
+			s.logger.Warn("Skipping invalid rate override",
+				zap.String("base", override.Base), zap.String("quote", override.Quote), zap.Error(err))
+			continue
+		}
+		rates = append(rates, *rate)
+	}
+	return rates, nil
+}
+
+// SaveRateOverride validates and stores the override of its pair
+func (s *Service) SaveRateOverride(ctx context.Context, override RateOverride) (RateOverride, error) {
+	override.Base, override.Quote = strings.ToUpper(override.Base), strings.ToUpper(override.Quote)
+	override.UpdatedAt = s.clock.Now().UTC()
+	if err := override.Validate(); err != nil {
+		return RateOverride{}, err
+	}
+	if err := s.store.SaveRateOverride(ctx, override); err != nil {
+		return RateOverride{}, err
+	}
+	s.changed(ctx, Change{Kind: KindRates, Key: override.Base + "/" + override.Quote})
+	return override, nil
+}
+
+// DeleteRateOverride removes the override of base/quote
+func (s *Service) DeleteRateOverride(ctx context.Context, base, quote string) error {
+	base, quote = strings.ToUpper(base), strings.ToUpper(quote)
+	return s.delete(ctx, "rate override", Change{Kind: KindRates, Key: base + "/" + quote}, func() (bool, error) {
+		return s.store.DeleteRateOverride(ctx, base, quote)
+	})
+}
+
+// Translations returns the translations of locale, or of every locale when
+// it is empty
+func (s *Service) Translations(ctx context.Context, locale string) ([]Translation, error) {
+	translations, err := s.translations.get(ctx, s.store.ListTranslations)
+	if err != nil || locale == "" {
+		return translations, err
+	}
+	canonical, err := i18n.ParseLocale(locale)
+	if err != nil {
+		return nil, err
+	}
+	return filter(translations, func(t Translation) bool { return t.Locale == string(canonical) }), nil
+}
+
+// Catalog returns the translations as a template catalog, to be merged over
+// the built-in messages
+func (s *Service) Catalog(ctx context.Context) (templates.Catalog, error) {
+	translations, err := s.Translations(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+	texts := make(map[string]map[string]string)
+	for _, t := range translations {
+		if texts[t.Key] == nil {
+			texts[t.Key] = make(map[string]string)
+		}
+		texts[t.Key][t.Locale] = t.Text
+	}
+	catalog := make(templates.Catalog, len(texts))
+	for key, byLocale := range texts {
+		localized, err := i18n.NewLocalizedString(byLocale)
+		if err != nil {
+			return nil, err
+		}
+		catalog[key] = *localized
+	}
+	return catalog, nil
+}
+
+// SaveTranslation validates and stores the translation
+func (s *Service) SaveTranslation(ctx context.Context, translation Translation) (Translation, error) {
+	if err := translation.Validate(); err != nil {
+		return Translation{}, err
+	}
+	locale, _ := i18n.ParseLocale(translation.Locale)
+	translation.Locale = string(locale)
+	translation.UpdatedAt = s.clock.Now().UTC()
+	if err := s.store.SaveTranslation(ctx, translation); err != nil {
+		return Translation{}, err
+	}
+	s.changed(ctx, Change{Kind: KindTranslations, Key: translation.Key + "/" + translation.Locale})
+	return translation, nil
+}
+
+// DeleteTranslation removes the text of key in locale
+func (s *Service) DeleteTranslation(ctx context.Context, key, locale string) error {
+	canonical, err := i18n.ParseLocale(locale)
+	if err != nil {
+		return err
+	}
+	return s.delete(ctx, "translation", Change{Kind: KindTranslations, Key: key + "/" + string(canonical)}, func() (bool, error) {
+		return s.store.DeleteTranslation(ctx, key, string(canonical))
+	})
+}
+
+// Holidays returns the holidays of calendar, or of every calendar when it
+// is empty
+func (s *Service) Holidays(ctx context.Context, calendar string) ([]Holiday, error) {
+	holidays, err := s.holidays.get(ctx, s.store.ListHolidays)
+	if err != nil || calendar == "" {
+		return holidays, err
+	}
+	return filter(holidays, func(h Holiday) bool { return h.Calendar == calendar }), nil
+}
+
+// Calendar returns base with the stored holidays of the named calendar added
+func (s *Service) Calendar(ctx context.Context, name string, base i18n.BusinessCalendar) (*i18n.BusinessCalendar, error) {
+	holidays, err := s.Holidays(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	calendar := &base
+	for _, holiday := range holidays {
+		if calendar, err = calendar.WithHoliday(holiday.Date, holiday.Name); err != nil {
+			return nil, err
+		}
+	}
+	return calendar, nil
+}
+
+// SaveHoliday validates and stores the holiday
+func (s *Service) SaveHoliday(ctx context.Context, holiday Holiday) (Holiday, error) {
+	if err := holiday.Validate(); err != nil {
+		return Holiday{}, err
+	}
+	date, _ := i18n.ParseDate(holiday.Date)
+	holiday.Date = date.String()
+	holiday.UpdatedAt = s.clock.Now().UTC()
+	if err := s.store.SaveHoliday(ctx, holiday); err != nil {
+		return Holiday{}, err
+	}
+	s.changed(ctx, Change{Kind: KindHolidays, Key: holiday.Calendar + "/" + holiday.Date})
+	return holiday, nil
+}
+
+// DeleteHoliday removes the holiday of calendar on date
+func (s *Service) DeleteHoliday(ctx context.Context, calendar, date string) error {
+	return s.delete(ctx, "holiday", Change{Kind: KindHolidays, Key: calendar + "/" + date}, func() (bool, error) {
+		return s.store.DeleteHoliday(ctx, calendar, date)
+	})
+}
+
+// delete runs remove and announces the change; a missing row is NotFound
+func (s *Service) delete(ctx context.Context, what string, change Change, remove func() (bool, error)) error {
+	found, err := remove()
+	if err != nil {
+		return err
+	}
+	if !found {
+		return domainerror.NotFoundf("%s %s not found", what, change.Key)
+	}
+	s.changed(ctx, change)
+	return nil
+}
+
+func filter[T any](items []T, keep func(T) bool) []T {
+	var kept []T
+	for _, item := range items {
+		if keep(item) {
+			kept = append(kept, item)
+		}
+	}
+	return kept
+}
+
+// cached holds the rows of one kind until they are invalidated. A load that
+// overlaps an invalidation is returned but not kept, so a change announced
+// while reading is never masked.
+type cached[T any] struct {
+	mu         sync.Mutex
+	items      []T
+	valid      bool
+	generation uint64
+}
+
+func (c *cached[T]) get(ctx context.Context, load func(context.Context) ([]T, error)) ([]T, error) {
+	c.mu.Lock()
+	if c.valid {
+		items := slices.Clone(c.items)
+		c.mu.Unlock()
+		return items, nil
+	}
+	generation := c.generation
+	c.mu.Unlock()
+
+	items, err := load(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.generation == generation {
+		c.items, c.valid = items, true
+	}
+	return slices.Clone(items), nil
+}
+
+func (c *cached[T]) invalidate() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.items, c.valid = nil, false
+	c.generation++
+}
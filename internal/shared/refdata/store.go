@@ -0,0 +1,381 @@
+package refdata
+
+import (
+	"cmp"
+	"context"
+	"database/sql"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+)
+
+// Store persists the reference data. Lists are small and read whole; the
+// Service caches them.
+type Store interface {
+	// ListCurrencies returns every currency ordered by code
+	ListCurrencies(ctx context.Context) ([]Currency, error)
+	// SaveCurrency inserts or replaces the currency
+	SaveCurrency(ctx context.Context, currency Currency) error
+	// DeleteCurrency removes the currency; found is false when it did not exist
+	DeleteCurrency(ctx context.Context, code string) (bool, error)
+
+	// ListRateOverrides returns every override ordered by pair
+	ListRateOverrides(ctx context.Context) ([]RateOverride, error)
+	// SaveRateOverride inserts or replaces the override of the pair
+	SaveRateOverride(ctx context.Context, override RateOverride) error
+	// DeleteRateOverride removes the override of the pair
+	DeleteRateOverride(ctx context.Context, base, quote string) (bool, error)
+
+	// ListTranslations returns every translation ordered by key and locale
+	ListTranslations(ctx context.Context) ([]Translation, error)
+	// SaveTranslation inserts or replaces the translation
+	SaveTranslation(ctx context.Context, translation Translation) error
+	// DeleteTranslation removes one locale's text of key
+	DeleteTranslation(ctx context.Context, key, locale string) (bool, error)
+
+	// ListHolidays returns every holiday ordered by calendar and date
+	ListHolidays(ctx context.Context) ([]Holiday, error)
+	// SaveHoliday inserts or replaces the holiday
+	SaveHoliday(ctx context.Context, holiday Holiday) error
+	// DeleteHoliday removes the holiday of calendar on date
+	DeleteHoliday(ctx context.Context, calendar, date string) (bool, error)
+}
+
+// PostgresStore keeps the reference data in the i18n_* tables
+type PostgresStore struct {
+	db *sql.DB
+}
+
+// NewPostgresStore creates a store on db
+func NewPostgresStore(db *sql.DB) *PostgresStore {
+	return &PostgresStore{db: db}
+}
+
+// ListCurrencies selects all currencies
+func (s *PostgresStore) ListCurrencies(ctx context.Context) ([]Currency, error) {
+	return query(ctx, s.db, "currencies",
+		`SELECT code, symbol, name, decimal_places, enabled, updated_by, updated_at
+		FROM i18n_currencies ORDER BY code`,
+		func(row rowScanner) (c Currency, err error) {
+			err = row.Scan(&c.Code, &c.Symbol, &c.Name, &c.DecimalPlaces, &c.Enabled, &c.UpdatedBy, &c.UpdatedAt)
+			return c, err
+		})
+}
+
+// SaveCurrency upserts the currency
+func (s *PostgresStore) SaveCurrency(ctx context.Context, c Currency) error {
+	_, err := s.db.ExecContext(ctx,
+		`INSERT INTO i18n_currencies (code, symbol, name, decimal_places, enabled, updated_by, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (code) DO UPDATE SET
+			symbol = EXCLUDED.symbol,
+			name = EXCLUDED.name,
+			decimal_places = EXCLUDED.decimal_places,
+			enabled = EXCLUDED.enabled,
+			updated_by = EXCLUDED.updated_by,
+			updated_at = EXCLUDED.updated_at`,
+		c.Code, c.Symbol, c.Name, c.DecimalPlaces, c.Enabled, c.UpdatedBy, c.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save currency %s: %w", c.Code, err)
+	}
+	return nil
+}
+
+// DeleteCurrency deletes the currency row
+func (s *PostgresStore) DeleteCurrency(ctx context.Context, code string) (bool, error) {
+	return s.delete(ctx, "currency", `DELETE FROM i18n_currencies WHERE code = $1`, code)
+}
+
+// ListRateOverrides selects all rate overrides
+func (s *PostgresStore) ListRateOverrides(ctx context.Context) ([]RateOverride, error) {
+	return query(ctx, s.db, "rate overrides",
+		`SELECT base_code, quote_code, rate, reason, expires_at, updated_by, updated_at
+		FROM i18n_rate_overrides ORDER BY base_code, quote_code`,
+		func(row rowScanner) (o RateOverride, err error) {
+			var expiresAt sql.NullTime
+			err = row.Scan(&o.Base, &o.Quote, &o.Rate, &o.Reason, &expiresAt, &o.UpdatedBy, &o.UpdatedAt)
+			if expiresAt.Valid {
+				o.ExpiresAt = &expiresAt.Time
+			}
+			return o, err
+		})
+}
+
+// SaveRateOverride upserts the override
+func (s *PostgresStore) SaveRateOverride(ctx context.Context, o RateOverride) error {
+	var expiresAt sql.NullTime
+	if o.ExpiresAt != nil {
+		expiresAt = sql.NullTime{Time: *o.ExpiresAt, Valid: true}
+	}
+	_, err := s.db.ExecContext(ctx,
+		`INSERT INTO i18n_rate_overrides (base_code, quote_code, rate, reason, expires_at, updated_by, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (base_code, quote_code) DO UPDATE SET
+			rate = EXCLUDED.rate,
+			reason = EXCLUDED.reason,
+			expires_at = EXCLUDED.expires_at,
+			updated_by = EXCLUDED.updated_by,
+			updated_at = EXCLUDED.updated_at`,
+		o.Base, o.Quote, o.Rate, o.Reason, expiresAt, o.UpdatedBy, o.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save rate override %s/%s: %w", o.Base, o.Quote, err)
+	}
+	return nil
+}
+
+// DeleteRateOverride deletes the override row
+func (s *PostgresStore) DeleteRateOverride(ctx context.Context, base, quote string) (bool, error) {
+	return s.delete(ctx, "rate override",
+		`DELETE FROM i18n_rate_overrides WHERE base_code = $1 AND quote_code = $2`, base, quote)
+}
+
+// ListTranslations selects all translations
+func (s *PostgresStore) ListTranslations(ctx context.Context) ([]Translation, error) {
+	return query(ctx, s.db, "translations",
+		`SELECT message_key, locale, text, updated_by, updated_at
+		FROM i18n_translations ORDER BY message_key, locale`,
+		func(row rowScanner) (t Translation, err error) {
+			err = row.Scan(&t.Key, &t.Locale, &t.Text, &t.UpdatedBy, &t.UpdatedAt)
+			return t, err
+		})
+}
+
+// SaveTranslation upserts the translation
+func (s *PostgresStore) SaveTranslation(ctx context.Context, t Translation) error {
+	_, err := s.db.ExecContext(ctx,
+		`INSERT INTO i18n_translations (message_key, locale, text, updated_by, updated_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (message_key, locale) DO UPDATE SET
+			text = EXCLUDED.text,
+			updated_by = EXCLUDED.updated_by,
+			updated_at = EXCLUDED.updated_at`,
+		t.Key, t.Locale, t.Text, t.UpdatedBy, t.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save translation %s/%s: %w", t.Key, t.Locale, err)
+	}
+	return nil
+}
+
+// DeleteTranslation deletes the translation row
+func (s *PostgresStore) DeleteTranslation(ctx context.Context, key, locale string) (bool, error) {
+	return s.delete(ctx, "translation",
+		`DELETE FROM i18n_translations WHERE message_key = $1 AND locale = $2`, key, locale)
+}
+
+// ListHolidays selects all holidays
+func (s *PostgresStore) ListHolidays(ctx context.Context) ([]Holiday, error) {
+	return query(ctx, s.db, "holidays",
+		`SELECT calendar, holiday_date, name, updated_by, updated_at
+		FROM i18n_holidays ORDER BY calendar, holiday_date`,
+		func(row rowScanner) (h Holiday, err error) {
+			var date time.Time
+			err = row.Scan(&h.Calendar, &date, &h.Name, &h.UpdatedBy, &h.UpdatedAt)
+			h.Date = date.Format(time.DateOnly)
+			return h, err
+		})
+}
+
+// SaveHoliday upserts the holiday
+func (s *PostgresStore) SaveHoliday(ctx context.Context, h Holiday) error {
+	_, err := s.db.ExecContext(ctx,
+		`INSERT INTO i18n_holidays (calendar, holiday_date, name, updated_by, updated_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (calendar, holiday_date) DO UPDATE SET
+			name = EXCLUDED.name,
+			updated_by = EXCLUDED.updated_by,
+			updated_at = EXCLUDED.updated_at`,
+		h.Calendar, h.Date, h.Name, h.UpdatedBy, h.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save holiday %s/%s: %w", h.Calendar, h.Date, err)
+	}
+	return nil
+}
+
+// DeleteHoliday deletes the holiday row
+func (s *PostgresStore) DeleteHoliday(ctx context.Context, calendar, date string) (bool, error) {
+	return s.delete(ctx, "holiday",
+		`DELETE FROM i18n_holidays WHERE calendar = $1 AND holiday_date = $2`, calendar, date)
+}
+
+func (s *PostgresStore) delete(ctx context.Context, what, statement string, args ...any) (bool, error) {
+	result, err := s.db.ExecContext(ctx, statement, args...)
+	if err != nil {
+		return false, fmt.Errorf("failed to delete %s: %w", what, err)
+	}
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to delete %s: %w", what, err)
+	}
+	return deleted > 0, nil
+}
+
+// rowScanner is satisfied by *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+// query runs statement and scans every row
+func query[T any](ctx context.Context, db *sql.DB, what, statement string, scan func(rowScanner) (T, error)) ([]T, error) {
+	rows, err := db.QueryContext(ctx, statement)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query %s: %w", what, err)
+	}
+	defer rows.Close()
+
+	var items []T
+	for rows.Next() {
+		item, err := scan(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan %s: %w", what, err)
+		}
+		items = append(items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", what, err)
+	}
+	return items, nil
+}
+
+// MemoryStore keeps the reference data in memory, for tests and the dev profile
+type MemoryStore struct {
+	mu           sync.Mutex
+	currencies   map[string]Currency
+	overrides    map[string]RateOverride
+	translations map[string]Translation
+	holidays     map[string]Holiday
+}
+
+// NewMemoryStore creates an empty in-memory store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		currencies:   make(map[string]Currency),
+		overrides:    make(map[string]RateOverride),
+		translations: make(map[string]Translation),
+		holidays:     make(map[string]Holiday),
+	}
+}
+
+func memoryKey(parts ...string) string {
+	key := parts[0]
+	for _, part := range parts[1:] {
+		key += "\x00" + part
+	}
+	return key
+}
+
+// sortedValues returns the values of m ordered by key
+func sortedValues[T any](m map[string]T) []T {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	slices.SortFunc(keys, cmp.Compare[string])
+	values := make([]T, len(keys))
+	for i, key := range keys {
+		values[i] = m[key]
+	}
+	return values
+}
+
+func deleteKey[T any](m map[string]T, key string) bool {
+	_, found := m[key]
+	delete(m, key)
+	return found
+}
+
+// ListCurrencies returns the stored currencies
+func (s *MemoryStore) ListCurrencies(context.Context) ([]Currency, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return sortedValues(s.currencies), nil
+}
+
+// SaveCurrency stores the currency
+func (s *MemoryStore) SaveCurrency(_ context.Context, c Currency) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.currencies[c.Code] = c
+	return nil
+}
+
+// DeleteCurrency removes the currency
+func (s *MemoryStore) DeleteCurrency(_ context.Context, code string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return deleteKey(s.currencies, code), nil
+}
+
+// ListRateOverrides returns the stored overrides
+func (s *MemoryStore) ListRateOverrides(context.Context) ([]RateOverride, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return sortedValues(s.overrides), nil
+}
+
+// SaveRateOverride stores the override
+func (s *MemoryStore) SaveRateOverride(_ context.Context, o RateOverride) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if o.ExpiresAt != nil {
+		expiresAt := *o.ExpiresAt
+		o.ExpiresAt = &expiresAt
+	}
+	s.overrides[memoryKey(o.Base, o.Quote)] = o
+	return nil
+}
+
+// DeleteRateOverride removes the override
+func (s *MemoryStore) DeleteRateOverride(_ context.Context, base, quote string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return deleteKey(s.overrides, memoryKey(base, quote)), nil
+}
+
+// ListTranslations returns the stored translations
+func (s *MemoryStore) ListTranslations(context.Context) ([]Translation, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return sortedValues(s.translations), nil
+}
+
+// SaveTranslation stores the translation
+func (s *MemoryStore) SaveTranslation(_ context.Context, t Translation) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.translations[memoryKey(t.Key, t.Locale)] = t
+	return nil
+}
+
+// DeleteTranslation removes the translation
+func (s *MemoryStore) DeleteTranslation(_ context.Context, key, locale string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return deleteKey(s.translations, memoryKey(key, locale)), nil
+}
+
+// ListHolidays returns the stored holidays
+func (s *MemoryStore) ListHolidays(context.Context) ([]Holiday, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return sortedValues(s.holidays), nil
+}
+
+// SaveHoliday stores the holiday
+func (s *MemoryStore) SaveHoliday(_ context.Context, h Holiday) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.holidays[memoryKey(h.Calendar, h.Date)] = h
+	return nil
+}
+
+// DeleteHoliday removes the holiday
+func (s *MemoryStore) DeleteHoliday(_ context.Context, calendar, date string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return deleteKey(s.holidays, memoryKey(calendar, date)), nil
+}
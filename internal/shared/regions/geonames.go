@@ -0,0 +1,144 @@
+package regions
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang-arch/internal/shared/config"
+	"golang-arch/internal/shared/domain/domainerror"
+	i18n "golang-arch/internal/shared/domain/internationalization"
+)
+
+// geoNamesLimitCodes are the GeoNames status codes for exceeded credits
+var geoNamesLimitCodes = map[int]bool{18: true, 19: true, 20: true}
+
+// GeoNamesProvider reads first-level administrative divisions from the
+// GeoNames web services (countryInfoJSON, then childrenJSON)
+type GeoNamesProvider struct {
+	baseURL    string
+	username   string
+	httpClient *http.Client
+}
+
+// NewGeoNamesProvider creates a provider for the GeoNames service at baseURL,
+// e.g. "https://secure.geonames.org"
+func NewGeoNamesProvider(baseURL, username string) *GeoNamesProvider {
+	return &GeoNamesProvider{
+		baseURL:    strings.TrimSuffix(baseURL, "/"),
+		username:   username,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// geoNamesResponse is the subset of a GeoNames JSON response used here
+type geoNamesResponse struct {
+	Geonames []struct {
+		GeonameID   int    `json:"geonameId"`
+		Name        string `json:"name"`
+		AdminCode1  string `json:"adminCode1"`
+		AdminCodes1 struct {
+			ISO31662 string `json:"ISO3166_2"`
+		} `json:"adminCodes1"`
+	} `json:"geonames"`
+	Status *struct {
+		Message string `json:"message"`
+		Value   int    `json:"value"`
+	} `json:"status"`
+}
+
+// Name returns "geonames"
+func (p *GeoNamesProvider) Name() string {
+	return "geonames"
+}
+
+// Subdivisions returns the country's first-level divisions, or nil when
+// GeoNames does not know the country
+func (p *GeoNamesProvider) Subdivisions(ctx context.Context, country i18n.Country) ([]i18n.Subdivision, error) {
+	info, err := p.get(ctx, "countryInfoJSON", url.Values{"country": {country.String()}})
+	if err != nil {
+		return nil, err
+	}
+	if len(info.Geonames) == 0 {
+		return nil, nil
+	}
+
+	children, err := p.get(ctx, "childrenJSON", url.Values{"geonameId": {strconv.Itoa(info.Geonames[0].GeonameID)}})
+	if err != nil {
+		return nil, err
+	}
+
+	subdivisions := make([]i18n.Subdivision, 0, len(children.Geonames))
+	for _, child := range children.Geonames {
+		code := child.AdminCodes1.ISO31662
+		if code == "" {
+			code = child.AdminCode1
+		}
+		if code == "" || child.Name == "" {
+			continue
+		}
+		subdivision := i18n.Subdivision{
+			Code:    country.String() + "-" + strings.ToUpper(code),
+			Country: country,
+			Type:    "administrative division",
+			Name:    child.Name,
+		}
+		if subdivision.Validate() != nil {
+			continue
+		}
+		subdivisions = append(subdivisions, subdivision)
+	}
+	return subdivisions, nil
+}
+
+// get calls a GeoNames JSON endpoint and decodes its response
+func (p *GeoNamesProvider) get(ctx context.Context, endpoint string, query url.Values) (*geoNamesResponse, error) {
+	query.Set("username", p.username)
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, p.baseURL+"/"+endpoint+"?"+query.Encode(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build GeoNames request: %w", err)
+	}
+
+	response, err := p.httpClient.Do(request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call GeoNames %s: %w", endpoint, err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("GeoNames %s returned status %d", endpoint, response.StatusCode)
+	}
+
+	var decoded geoNamesResponse
+	if err := json.NewDecoder(response.Body).Decode(&decoded); err != nil {
+		return nil, fmt.Errorf("failed to decode GeoNames %s response: %w", endpoint, err)
+	}
+	if decoded.Status != nil {
+		if geoNamesLimitCodes[decoded.Status.Value] {
+			return nil, domainerror.Unavailablef("GeoNames credits exhausted: %s", decoded.Status.Message)
+		}
+		return nil, fmt.Errorf("GeoNames %s failed: %s (code %d)", endpoint, decoded.Status.Message, decoded.Status.Value)
+	}
+	return &decoded, nil
+}
+
+// NewProvider builds the remote provider selected by cfg.Provider; "none"
+// returns nil, limiting lookups to the embedded data
+func NewProvider(cfg config.RegionsConfig) (Provider, error) {
+	switch cfg.Provider {
+	case "", "none":
+		return nil, nil
+	case "geonames":
+		if cfg.GeoNamesUsername == "" {
+			return nil, fmt.Errorf("regions provider geonames requires geonames_username")
+		}
+		return NewGeoNamesProvider(cfg.GeoNamesURL, cfg.GeoNamesUsername), nil
+	default:
+		return nil, fmt.Errorf("unknown regions provider %q", cfg.Provider)
+	}
+}
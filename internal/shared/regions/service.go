@@ -0,0 +1,184 @@
+// Package regions answers region metadata lookups (ISO 3166-2 states,
+// provinces and territories) so addresses and phone numbers can be validated
+// against known subdivisions. Embedded data from the i18n package is used
+// first; countries without it can be fetched from a remote Provider such as
+// GeoNames, which Service rate-limits and caches in memory.
+package regions
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"golang-arch/internal/shared/domain/domainerror"
+	i18n "golang-arch/internal/shared/domain/internationalization"
+	"golang-arch/internal/shared/domain/validation"
+	"golang-arch/pkg/clock"
+)
+
+// Provider fetches the subdivisions of a country from a remote source
+type Provider interface {
+	Name() string
+	Subdivisions(ctx context.Context, country i18n.Country) ([]i18n.Subdivision, error)
+}
+
+// Service looks up subdivisions: embedded data first, then the remote
+// provider, at most once per min interval and cached for the cache TTL
+type Service struct {
+	provider    Provider
+	clock       clock.Clock
+	logger      *zap.Logger
+	cacheTTL    time.Duration
+	minInterval time.Duration
+
+	mu          sync.Mutex
+	cache       map[i18n.Country]cacheEntry
+	nextRequest time.Time
+}
+
+// cacheEntry is a remote result and when it was fetched
+type cacheEntry struct {
+	subdivisions []i18n.Subdivision
+	fetchedAt    time.Time
+}
+
+// Option customizes a Service
+type Option func(*Service)
+
+// WithClock replaces the system clock used for caching and rate limiting
+func WithClock(c clock.Clock) Option {
+	return func(s *Service) {
+		s.clock = c
+	}
+}
+
+// WithLogger sets the logger used to report remote fetches
+func WithLogger(logger *zap.Logger) Option {
+	return func(s *Service) {
+		s.logger = logger
+	}
+}
+
+// WithCacheTTL sets how long remote results are reused; zero keeps them for
+// the lifetime of the service
+func WithCacheTTL(ttl time.Duration) Option {
+	return func(s *Service) {
+		s.cacheTTL = ttl
+	}
+}
+
+// WithMinInterval sets the minimum time between remote requests; lookups
+// that would exceed it fail with an Unavailable error instead of waiting
+func WithMinInterval(interval time.Duration) Option {
+	return func(s *Service) {
+		s.minInterval = interval
+	}
+}
+
+// NewService creates a regions service; a nil provider serves embedded data only
+func NewService(provider Provider, options ...Option) *Service {
+	s := &Service{
+		provider: provider,
+		clock:    clock.New(),
+		logger:   zap.NewNop(),
+		cache:    make(map[i18n.Country]cacheEntry),
+	}
+	for _, option := range options {
+		option(s)
+	}
+	return s
+}
+
+// Subdivisions returns the subdivisions of country ordered as the source
+// lists them, or nil when no source knows the country
+func (s *Service) Subdivisions(ctx context.Context, country i18n.Country) ([]i18n.Subdivision, error) {
+	if err := country.Validate(); err != nil {
+		return nil, err
+	}
+	if subdivisions := country.Subdivisions(); subdivisions != nil {
+		return subdivisions, nil
+	}
+	if s.provider == nil {
+		return nil, nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.clock.Now()
+	entry, cached := s.cache[country]
+	if cached && (s.cacheTTL == 0 || now.Sub(entry.fetchedAt) < s.cacheTTL) {
+		return entry.subdivisions, nil
+	}
+	if now.Before(s.nextRequest) {
+		if cached {
+			return entry.subdivisions, nil
+		}
+		return nil, domainerror.Unavailablef("region lookup for %s is rate limited, retry in %s",
+			country, s.nextRequest.Sub(now))
+	}
+	s.nextRequest = now.Add(s.minInterval)
+
+	subdivisions, err := s.provider.Subdivisions(ctx, country)
+	if err != nil {
+		if cached {
+			s.logger.Warn("Failed to refresh regions, serving cached copy",
+				zap.String("provider", s.provider.Name()),
+				zap.String("country", country.String()),
+				zap.Error(err))
+			return entry.subdivisions, nil
+		}
+		return nil, fmt.Errorf("failed to fetch regions of %s from %s: %w", country, s.provider.Name(), err)
+	}
+
+	s.cache[country] = cacheEntry{subdivisions: subdivisions, fetchedAt: now}
+	s.logger.Info("Regions fetched",
+		zap.String("provider", s.provider.Name()),
+		zap.String("country", country.String()),
+		zap.Int("count", len(subdivisions)))
+	return subdivisions, nil
+}
+
+// Find returns the subdivision of country matching a code or name, e.g.
+// "NY", "US-NY" or "new york"
+func (s *Service) Find(ctx context.Context, country i18n.Country, value string) (i18n.Subdivision, bool, error) {
+	subdivisions, err := s.Subdivisions(ctx, country)
+	if err != nil {
+		return i18n.Subdivision{}, false, err
+	}
+	subdivision, ok := i18n.MatchSubdivision(subdivisions, value)
+	return subdivision, ok, nil
+}
+
+// ValidateRegion checks region against the subdivisions of country, which
+// may be a code or a name ("United States"). An empty region, or a country
+// no source knows, is accepted since it cannot be checked.
+func (s *Service) ValidateRegion(ctx context.Context, country, region string) error {
+	if region == "" {
+		return nil
+	}
+	code, ok := i18n.FindCountry(country)
+	if !ok {
+		return nil
+	}
+	subdivisions, err := s.Subdivisions(ctx, code)
+	if err != nil || len(subdivisions) == 0 {
+		return err
+	}
+	if _, ok := i18n.MatchSubdivision(subdivisions, region); ok {
+		return nil
+	}
+
+	var errs validation.ValidationErrors
+	errs.Add("region", validation.CodeInvalid,
+		fmt.Sprintf("%q is not a known region of %s", region, code), map[string]any{"country": string(code)})
+	return errs.Err()
+}
+
+// ValidatePhone checks the region of a localized phone number
+func (s *Service) ValidatePhone(ctx context.Context, phone i18n.LocalizedPhone) error {
+	return s.ValidateRegion(ctx, phone.Country, phone.Region)
+}
@@ -0,0 +1,318 @@
+package repository
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/lib/pq"
+
+	"golang-arch/internal/shared/database"
+	"golang-arch/internal/shared/erasure"
+)
+
+// Entry is the audit record of one write
+type Entry struct {
+	ID        string          `json:"id"`
+	Entity    string          `json:"entity"`
+	EntityID  string          `json:"entity_id"`
+	Operation Operation       `json:"operation"`
+	Actor     string          `json:"actor,omitempty"`
+	Old       json.RawMessage `json:"old,omitempty"`
+	New       json.RawMessage `json:"new,omitempty"`
+	Fields    []string        `json:"fields,omitempty"` // Top-level fields an update changed
+	At        time.Time       `json:"at"`
+}
+
+// AuditLog keeps the audit trail of entity writes. Entries are append-only,
+// except for erasure.
+type AuditLog interface {
+	// Append stores entry through q, the write's transaction
+	Append(ctx context.Context, q database.Querier, entry Entry) error
+	// History returns the entries of an entity, oldest first
+	History(ctx context.Context, q database.Querier, entity, id string) ([]Entry, error)
+	// Erase redacts the subject's personal data from the entries and
+	// returns how many entries changed; see Redact
+	Erase(ctx context.Context, q database.Querier, subject erasure.Subject, anonymizer *erasure.Anonymizer) (int, error)
+}
+
+// Redact removes the subject's personal data from entry and reports whether
+// anything changed. The old and new values of the subject's own entity, the
+// one whose ID is the subject's ID, are dropped, since fields such as names
+// and addresses cannot be recognised by value; the operation, actor, changed
+// fields and time stay. Elsewhere the subject's emails and phones, within
+// strings or as {"country_code", "number"} objects, are replaced with
+// anonymized ones. Redact is idempotent.
+func Redact(entry Entry, subject erasure.Subject, anonymizer *erasure.Anonymizer) (Entry, bool) {
+	if subject.ID != "" && entry.EntityID == subject.ID {
+		changed := entry.Old != nil || entry.New != nil
+		entry.Old, entry.New = nil, nil
+		return entry, changed
+	}
+	old, oldChanged := redactValue(entry.Old, subject, anonymizer)
+	new, newChanged := redactValue(entry.New, subject, anonymizer)
+	entry.Old, entry.New = old, new
+	return entry, oldChanged || newChanged
+}
+
+// redactValue replaces the subject's contact details in a JSON value
+func redactValue(value json.RawMessage, subject erasure.Subject, anonymizer *erasure.Anonymizer) (json.RawMessage, bool) {
+	if value == nil {
+		return nil, false
+	}
+	decoder := json.NewDecoder(bytes.NewReader(value))
+	decoder.UseNumber()
+	var decoded any
+	if decoder.Decode(&decoded) != nil {
+		return value, false
+	}
+	decoded, changed := redactContacts(decoded, subject, anonymizer)
+	if !changed {
+		return value, false
+	}
+	data, err := json.Marshal(decoded)
+	if err != nil {
+		return value, false
+	}
+	return data, true
+}
+
+// redactContacts walks a decoded JSON value
+func redactContacts(value any, subject erasure.Subject, anonymizer *erasure.Anonymizer) (any, bool) {
+	switch value := value.(type) {
+	case string:
+		redacted := value
+		for _, email := range subject.Emails {
+			if email = strings.TrimSpace(email); email != "" {
+				redacted = replaceFold(redacted, email, anonymizer.Email(email))
+			}
+		}
+		for _, phone := range subject.Phones {
+			if phone.Number != "" {
+				replacement := anonymizer.Phone(phone)
+				redacted = strings.ReplaceAll(redacted, phone.Format(), replacement.Format())
+				redacted = strings.ReplaceAll(redacted, phone.FormatCompact(), replacement.FormatCompact())
+			}
+		}
+		return redacted, redacted != value
+	case map[string]any:
+		changed := false
+		countryCode, _ := value["country_code"].(string)
+		number, _ := value["number"].(string)
+		for _, phone := range subject.Phones {
+			if phone.Number != "" && countryCode == phone.CountryCode && number == phone.Number {
+				value["number"] = anonymizer.Phone(phone).Number
+				changed = true
+			}
+		}
+		for key, field := range value {
+			redacted, fieldChanged := redactContacts(field, subject, anonymizer)
+			value[key], changed = redacted, changed || fieldChanged
+		}
+		return value, changed
+	case []any:
+		changed := false
+		for i, item := range value {
+			redacted, itemChanged := redactContacts(item, subject, anonymizer)
+			value[i], changed = redacted, changed || itemChanged
+		}
+		return value, changed
+	default:
+		return value, false
+	}
+}
+
+// PostgresAuditLog keeps entries in the audit_log table
+type PostgresAuditLog struct{}
+
+// NewPostgresAuditLog creates an audit log on the audit_log table
+func NewPostgresAuditLog() *PostgresAuditLog {
+	return &PostgresAuditLog{}
+}
+
+// Append inserts entry
+func (PostgresAuditLog) Append(ctx context.Context, q database.Querier, entry Entry) error {
+	_, err := q.ExecContext(ctx,
+		`INSERT INTO audit_log (id, entity, entity_id, operation, actor, old_value, new_value, fields, changed_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`,
+		entry.ID, entry.Entity, entry.EntityID, string(entry.Operation), entry.Actor,
+		nullJSON(entry.Old), nullJSON(entry.New), pq.Array(entry.Fields), entry.At,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to audit %s %s: %w", entry.Entity, entry.EntityID, err)
+	}
+	return nil
+}
+
+// History selects the entries of an entity
+func (PostgresAuditLog) History(ctx context.Context, q database.Querier, entity, id string) ([]Entry, error) {
+	rows, err := q.QueryContext(ctx,
+		`SELECT id, entity, entity_id, operation, actor, old_value, new_value, fields, changed_at
+		FROM audit_log WHERE entity = $1 AND entity_id = $2 ORDER BY changed_at, id`,
+		entity, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query audit log: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []Entry
+	for rows.Next() {
+		var (
+			entry     Entry
+			operation string
+			old, new  []byte
+		)
+		if err := rows.Scan(&entry.ID, &entry.Entity, &entry.EntityID, &operation, &entry.Actor,
+			&old, &new, pq.Array(&entry.Fields), &entry.At); err != nil {
+			return nil, fmt.Errorf("failed to scan audit entry: %w", err)
+		}
+		entry.Operation, entry.Old, entry.New = Operation(operation), old, new
+		entries = append(entries, entry)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read audit log: %w", err)
+	}
+	return entries, nil
+}
+
+// replaceFold replaces the occurrences of old in s, ignoring case
+func replaceFold(s, old, new string) string {
+	lower, target := strings.ToLower(s), strings.ToLower(old)
+	if len(lower) != len(s) || !strings.Contains(lower, target) {
+		return s
+	}
+	var b strings.Builder
+	for {
+		i := strings.Index(lower, target)
+		if i < 0 {
+			break
+		}
+		b.WriteString(s[:i])
+		b.WriteString(new)
+		s, lower = s[i+len(target):], lower[i+len(target):]
+	}
+	b.WriteString(s)
+	return b.String()
+}
+
+// Erase redacts the entries that may hold the subject's personal data: those
+// of the subject's entity and those whose values contain one of the
+// subject's emails or phone numbers. The text search only narrows the
+// candidates; Redact decides.
+func (PostgresAuditLog) Erase(ctx context.Context, q database.Querier, subject erasure.Subject, anonymizer *erasure.Anonymizer) (int, error) {
+	var patterns []string
+	for _, email := range subject.Emails {
+		if email = strings.TrimSpace(email); email != "" {
+			patterns = append(patterns, "%"+email+"%")
+		}
+	}
+	for _, phone := range subject.Phones {
+		if phone.Number != "" {
+			patterns = append(patterns, "%"+phone.Number+"%")
+		}
+	}
+
+	rows, err := q.QueryContext(ctx,
+		`SELECT id, entity, entity_id, old_value, new_value FROM audit_log
+		WHERE entity_id = $1 OR old_value::text ILIKE ANY($2) OR new_value::text ILIKE ANY($2)`,
+		subject.ID, pq.Array(patterns))
+	if err != nil {
+		return 0, fmt.Errorf("failed to query audit log: %w", err)
+	}
+	var candidates []Entry
+	for rows.Next() {
+		var (
+			entry    Entry
+			old, new []byte
+		)
+		if err := rows.Scan(&entry.ID, &entry.Entity, &entry.EntityID, &old, &new); err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("failed to scan audit entry: %w", err)
+		}
+		entry.Old, entry.New = old, new
+		candidates = append(candidates, entry)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, fmt.Errorf("failed to read audit log: %w", err)
+	}
+
+	erased := 0
+	for _, entry := range candidates {
+		redacted, changed := Redact(entry, subject, anonymizer)
+		if !changed {
+			continue
+		}
+		if _, err := q.ExecContext(ctx,
+			`UPDATE audit_log SET old_value = $2, new_value = $3 WHERE id = $1`,
+			entry.ID, nullJSON(redacted.Old), nullJSON(redacted.New)); err != nil {
+			return erased, fmt.Errorf("failed to redact audit entry %s: %w", entry.ID, err)
+		}
+		erased++
+	}
+	return erased, nil
+}
+
+// nullJSON maps an absent value to NULL
+func nullJSON(value json.RawMessage) any {
+	if value == nil {
+		return nil
+	}
+	return []byte(value)
+}
+
+// MemoryAuditLog keeps entries in memory, for tests and the dev profile. It
+// ignores the transaction, so entries of rolled-back writes stay.
+type MemoryAuditLog struct {
+	mu      sync.Mutex
+	entries []Entry
+}
+
+// NewMemoryAuditLog creates an empty in-memory audit log
+func NewMemoryAuditLog() *MemoryAuditLog {
+	return &MemoryAuditLog{}
+}
+
+// Append records entry
+func (l *MemoryAuditLog) Append(_ context.Context, _ database.Querier, entry Entry) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.entries = append(l.entries, entry)
+	return nil
+}
+
+// History returns the entries of an entity
+func (l *MemoryAuditLog) History(_ context.Context, _ database.Querier, entity, id string) ([]Entry, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	var entries []Entry
+	for _, entry := range l.entries {
+		if entry.Entity == entity && entry.EntityID == id {
+			entries = append(entries, entry)
+		}
+	}
+	slices.SortStableFunc(entries, func(a, b Entry) int { return a.At.Compare(b.At) })
+	return entries, nil
+}
+
+// Erase redacts the subject's entries in place
+func (l *MemoryAuditLog) Erase(_ context.Context, _ database.Querier, subject erasure.Subject, anonymizer *erasure.Anonymizer) (int, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	erased := 0
+	for i, entry := range l.entries {
+		if redacted, changed := Redact(entry, subject, anonymizer); changed {
+			l.entries[i] = redacted
+			erased++
+		}
+	}
+	return erased, nil
+}
@@ -0,0 +1,353 @@
+// Package repository is the write side of the repository base: hooks run
+// around each create, update and delete of an entity.
+//
+// A repository builds one Hooks per entity type and routes its writes
+// through Create, Update and Delete. Each write runs in a transaction with
+// the entity's before and after hooks, so a hook can veto or amend the
+// change. Configured per entity, the write also appends an audit entry with
+// the old and new values in the same transaction and, once committed,
+// emits a "<entity>.created", ".updated" or ".deleted" event.
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+
+	"go.uber.org/zap"
+
+	"golang-arch/internal/shared/events"
+	"golang-arch/internal/shared/rbac"
+	"golang-arch/pkg/clock"
+)
+
+// Operation is the kind of a write
+type Operation string
+
+// Operations, named as the suffix of their event
+const (
+	Created Operation = "created"
+	Updated Operation = "updated"
+	Deleted Operation = "deleted"
+)
+
+// Stage is when a hook runs relative to the write
+type Stage int
+
+// Hook stages
+const (
+	// Before hooks run ahead of the write and may change Change.New or
+	// return an error to cancel it
+	Before Stage = iota
+	// After hooks run after the write, in its transaction
+	After
+)
+
+// Change is one write of an entity
+type Change[T any] struct {
+	Entity    string
+	ID        string
+	Operation Operation
+	Old       *T // nil on create
+	New       *T // nil on delete
+	Actor     string
+}
+
+// Hook runs inside the write's transaction; an error rolls it back. The
+// write itself has the same signature.
+type Hook[T any] func(ctx context.Context, tx *sql.Tx, change *Change[T]) error
+
+// Beginner starts transactions; *sql.DB implements it
+type Beginner interface {
+	BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error)
+}
+
+// Changed is the event emitted for a write, named "<entity>.<operation>"
+type Changed struct {
+	events.Base
+	Entity    string          `json:"entity"`
+	Operation Operation       `json:"operation"`
+	Old       json.RawMessage `json:"old,omitempty"`
+	New       json.RawMessage `json:"new,omitempty"`
+	Fields    []string        `json:"fields,omitempty"` // Top-level fields an update changed
+	Actor     string          `json:"actor,omitempty"`
+}
+
+// EventName returns "<entity>.<operation>"
+func (e Changed) EventName() string {
+	return e.Entity + "." + string(e.Operation)
+}
+
+// Option configures the hooks of an entity
+type Option func(*options)
+
+type options struct {
+	clock  clock.Clock
+	logger *zap.Logger
+	audit  AuditLog
+	bus    *events.Bus
+	outbox bool
+	redact []string
+	actor  func(context.Context) string
+}
+
+// WithClock sets the clock audit entries are stamped with
+func WithClock(c clock.Clock) Option {
+	return func(o *options) {
+		o.clock = c
+	}
+}
+
+// WithLogger sets the logger
+func WithLogger(logger *zap.Logger) Option {
+	return func(o *options) {
+		o.logger = logger
+	}
+}
+
+// WithAudit appends an entry to log for every write, in its transaction
+func WithAudit(log AuditLog) Option {
+	return func(o *options) {
+		o.audit = log
+	}
+}
+
+// WithEvents publishes a Changed event on bus, asynchronously, once the
+// write is committed
+func WithEvents(bus *events.Bus) Option {
+	return func(o *options) {
+		o.bus = bus
+	}
+}
+
+// WithOutbox also writes the Changed event to the event_outbox table in the
+// write's transaction, for delivery to other services
+func WithOutbox() Option {
+	return func(o *options) {
+		o.outbox = true
+	}
+}
+
+// WithRedact leaves top-level JSON fields, such as secrets or personal data
+// kept elsewhere, out of audit entries and events
+func WithRedact(fields ...string) Option {
+	return func(o *options) {
+		o.redact = append(o.redact, fields...)
+	}
+}
+
+// WithActor sets how the acting user is found; by default it is the rbac
+// principal of the context
+func WithActor(actor func(context.Context) string) Option {
+	return func(o *options) {
+		o.actor = actor
+	}
+}
+
+// principalName is the default actor
+func principalName(ctx context.Context) string {
+	principal, _ := rbac.FromContext(ctx)
+	return principal.Name
+}
+
+// Hooks wraps the writes of one entity type. Register hooks before the
+// repository is used.
+type Hooks[T any] struct {
+	entity string
+	hooks  map[Operation]map[Stage][]Hook[T]
+	options
+}
+
+// NewHooks creates the hooks of entity, e.g. "order", which names its
+// audit entries and events
+func NewHooks[T any](entity string, opts ...Option) *Hooks[T] {
+	h := &Hooks[T]{
+		entity: entity,
+		hooks:  make(map[Operation]map[Stage][]Hook[T]),
+		options: options{
+			clock:  clock.New(),
+			logger: zap.NewNop(),
+			actor:  principalName,
+		},
+	}
+	for _, opt := range opts {
+		opt(&h.options)
+	}
+	return h
+}
+
+// OnCreate adds a hook run at stage of each create
+func (h *Hooks[T]) OnCreate(stage Stage, hook Hook[T]) { h.on(Created, stage, hook) }
+
+// OnUpdate adds a hook run at stage of each update
+func (h *Hooks[T]) OnUpdate(stage Stage, hook Hook[T]) { h.on(Updated, stage, hook) }
+
+// OnDelete adds a hook run at stage of each delete
+func (h *Hooks[T]) OnDelete(stage Stage, hook Hook[T]) { h.on(Deleted, stage, hook) }
+
+func (h *Hooks[T]) on(operation Operation, stage Stage, hook Hook[T]) {
+	if h.hooks[operation] == nil {
+		h.hooks[operation] = make(map[Stage][]Hook[T])
+	}
+	h.hooks[operation][stage] = append(h.hooks[operation][stage], hook)
+}
+
+// Create runs write, which inserts change.New, with the create hooks
+func (h *Hooks[T]) Create(ctx context.Context, db Beginner, id string, entity T, write Hook[T]) error {
+	return h.run(ctx, db, &Change[T]{ID: id, Operation: Created, New: &entity}, write)
+}
+
+// Update runs write, which stores change.New in place of old, with the
+// update hooks. When new equals old after the before hooks, nothing is
+// written, audited or emitted.
+func (h *Hooks[T]) Update(ctx context.Context, db Beginner, id string, old, entity T, write Hook[T]) error {
+	return h.run(ctx, db, &Change[T]{ID: id, Operation: Updated, Old: &old, New: &entity}, write)
+}
+
+// Delete runs write, which removes the entity, with the delete hooks
+func (h *Hooks[T]) Delete(ctx context.Context, db Beginner, id string, old T, write Hook[T]) error {
+	return h.run(ctx, db, &Change[T]{ID: id, Operation: Deleted, Old: &old}, write)
+}
+
+// run executes the write and its hooks in a transaction, then emits the
+// event
+func (h *Hooks[T]) run(ctx context.Context, db Beginner, change *Change[T], write Hook[T]) error {
+	change.Entity, change.Actor = h.entity, h.actor(ctx)
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin %s %s: %w", h.entity, change.Operation, err)
+	}
+	defer tx.Rollback()
+
+	stages := h.hooks[change.Operation]
+	for _, hook := range stages[Before] {
+		if err := hook(ctx, tx, change); err != nil {
+			return err
+		}
+	}
+	if change.Operation == Updated && reflect.DeepEqual(*change.Old, *change.New) {
+		return nil
+	}
+	if err := write(ctx, tx, change); err != nil {
+		return err
+	}
+	for _, hook := range stages[After] {
+		if err := hook(ctx, tx, change); err != nil {
+			return err
+		}
+	}
+
+	event, err := h.event(change)
+	if err != nil {
+		return err
+	}
+	if h.audit != nil {
+		if err := h.audit.Append(ctx, tx, h.entry(event)); err != nil {
+			return err
+		}
+	}
+	if h.outbox {
+		envelope, err := events.NewEnvelope(event)
+		if err != nil {
+			return err
+		}
+		if err := events.NewOutbox(tx).Publish(ctx, envelope); err != nil {
+			return err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit %s %s: %w", h.entity, change.Operation, err)
+	}
+
+	if h.bus != nil {
+		if err := h.bus.PublishAsync(ctx, event); err != nil {
+			h.logger.Warn("Failed to publish change", zap.String("entity", h.entity),
+				zap.String("id", change.ID), zap.Error(err))
+		}
+	}
+	return nil
+}
+
+// event builds the Changed event of change, redacted
+func (h *Hooks[T]) event(change *Change[T]) (Changed, error) {
+	event := Changed{
+		Base:      events.Base{Metadata: events.NewMetadata(h.clock, h.entity, change.ID)},
+		Entity:    h.entity,
+		Operation: change.Operation,
+		Actor:     change.Actor,
+	}
+	var oldFields, newFields map[string]json.RawMessage
+	var err error
+	if change.Old != nil {
+		if event.Old, oldFields, err = h.encode(*change.Old); err != nil {
+			return Changed{}, err
+		}
+	}
+	if change.New != nil {
+		if event.New, newFields, err = h.encode(*change.New); err != nil {
+			return Changed{}, err
+		}
+	}
+	if change.Operation == Updated {
+		event.Fields = changedFields(oldFields, newFields)
+	}
+	return event, nil
+}
+
+// encode marshals value without the redacted fields. Values that are not
+// JSON objects are kept whole.
+func (h *Hooks[T]) encode(value T) (json.RawMessage, map[string]json.RawMessage, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to encode %s: %w", h.entity, err)
+	}
+	var fields map[string]json.RawMessage
+	if json.Unmarshal(data, &fields) != nil {
+		return data, nil, nil
+	}
+	if len(h.redact) == 0 {
+		return data, fields, nil
+	}
+	for _, name := range h.redact {
+		delete(fields, name)
+	}
+	data, err = json.Marshal(fields)
+	return data, fields, err
+}
+
+// entry builds the audit entry of event
+func (h *Hooks[T]) entry(event Changed) Entry {
+	return Entry{
+		ID:        event.Metadata.ID,
+		Entity:    event.Entity,
+		EntityID:  event.Metadata.AggregateID,
+		Operation: event.Operation,
+		Actor:     event.Actor,
+		Old:       event.Old,
+		New:       event.New,
+		Fields:    event.Fields,
+		At:        h.clock.Now().UTC(),
+	}
+}
+
+// changedFields returns the sorted names of the fields that differ
+func changedFields(old, new map[string]json.RawMessage) []string {
+	var fields []string
+	for name := range maps.Keys(old) {
+		if value, ok := new[name]; !ok || string(value) != string(old[name]) {
+			fields = append(fields, name)
+		}
+	}
+	for name := range maps.Keys(new) {
+		if _, ok := old[name]; !ok {
+			fields = append(fields, name)
+		}
+	}
+	slices.Sort(fields)
+	return fields
+}
@@ -0,0 +1,402 @@
+// Package respcache memoizes whole GET responses in process, for endpoints
+// whose answer is the same for every caller asking the same question, such
+// as reference data catalogs. A cached response is served without running
+// the handler, so hot endpoints cost a map lookup and a write.
+//
+// Entries are fresh for a TTL, then stale for a grace period: a stale entry
+// is still served while one refresh runs in the background. Concurrent misses
+// of the same key are coalesced, so a cold cache runs the handler once per
+// key however many requests arrive. Only 200 responses are kept.
+package respcache
+
+import (
+	"bytes"
+	"container/list"
+	"context"
+	"net/http"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"golang-arch/pkg/clock"
+	"golang-arch/pkg/metrics"
+)
+
+// HeaderCache tells clients how the response was served: HIT, STALE or MISS
+const HeaderCache = "X-Cache"
+
+// Lookup results, the result label of the lookups counter
+const (
+	ResultHit       = "hit"
+	ResultStale     = "stale"
+	ResultMiss      = "miss"
+	ResultCoalesced = "coalesced" // Waited for a miss another request was producing
+)
+
+// refreshTimeout bounds a background refresh
+const refreshTimeout = 30 * time.Second
+
+// credentialHeaders are removed from replayed requests, so a background
+// refresh is not authorized or metered as the caller who found the entry
+// stale
+var credentialHeaders = []string{"Authorization", "Cookie", "X-API-Key"}
+
+// KeyFunc names the response a request asks for. Requests with the same key
+// get the same response, so it must cover every input the handler reads.
+type KeyFunc func(c *gin.Context) string
+
+// ByURL keys requests by path and query string, with parameters sorted
+func ByURL(c *gin.Context) string {
+	return c.Request.URL.Path + "?" + c.Request.URL.Query().Encode()
+}
+
+// entry is a stored response; it is never modified once stored
+type entry struct {
+	key      string
+	status   int
+	header   http.Header // Headers set by the handler
+	body     []byte
+	storedAt time.Time
+}
+
+// Cache holds responses up to a number of entries, evicting the least
+// recently used
+type Cache struct {
+	ttl        time.Duration
+	stale      time.Duration
+	maxEntries int
+	clock      clock.Clock
+	replay     http.Handler
+	strip      []string // Request headers left out of replays
+	lookups    *metrics.Counter
+	logger     *zap.Logger
+
+	mu         sync.Mutex
+	entries    map[string]*list.Element // of *entry
+	recent     *list.List               // Most recently used first
+	loading    map[string]chan struct{} // Keys a request is producing, closed when done
+	refreshing map[string]bool          // Keys refreshed in the background
+}
+
+// Option customizes a Cache
+type Option func(*Cache)
+
+// WithClock sets the clock entries are aged with
+func WithClock(c clock.Clock) Option {
+	return func(rc *Cache) {
+		rc.clock = c
+	}
+}
+
+// WithMaxEntries bounds the number of cached responses; 0 is unbounded
+func WithMaxEntries(n int) Option {
+	return func(rc *Cache) {
+		rc.maxEntries = n
+	}
+}
+
+// WithReplay refreshes stale entries in the background by replaying their
+// request through handler, usually the server's router. Without it the first
+// request to find an entry stale refreshes it while the others get the stale
+// copy.
+func WithReplay(handler http.Handler) Option {
+	return func(rc *Cache) {
+		rc.replay = handler
+	}
+}
+
+// WithStripHeaders leaves more request headers out of replays, such as a
+// custom API key header; Authorization, Cookie and X-API-Key always are
+func WithStripHeaders(names ...string) Option {
+	return func(rc *Cache) {
+		rc.strip = append(rc.strip, names...)
+	}
+}
+
+// WithLookupsCounter counts lookups by result
+func WithLookupsCounter(counter *metrics.Counter) Option {
+	return func(rc *Cache) {
+		rc.lookups = counter
+	}
+}
+
+// WithLogger sets the logger
+func WithLogger(logger *zap.Logger) Option {
+	return func(rc *Cache) {
+		rc.logger = logger
+	}
+}
+
+// New creates a cache whose entries are fresh for ttl, then served stale for
+// up to stale more while they are refreshed
+func New(ttl, stale time.Duration, options ...Option) *Cache {
+	rc := &Cache{
+		ttl:        ttl,
+		stale:      stale,
+		clock:      clock.New(),
+		strip:      slices.Clone(credentialHeaders),
+		logger:     zap.NewNop(),
+		entries:    make(map[string]*list.Element),
+		recent:     list.New(),
+		loading:    make(map[string]chan struct{}),
+		refreshing: make(map[string]bool),
+	}
+	for _, option := range options {
+		option(rc)
+	}
+	return rc
+}
+
+// Len returns the number of cached responses
+func (rc *Cache) Len() int {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	return rc.recent.Len()
+}
+
+// Purge drops every cached response, e.g. after the data behind them changed
+func (rc *Cache) Purge() {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	clear(rc.entries)
+	rc.recent.Init()
+}
+
+// refreshKey marks the context of a replayed request
+type refreshKey struct{}
+
+// Replayed reports whether ctx is that of a background refresh, for
+// middleware such as metering that should only count client requests
+func Replayed(ctx context.Context) bool {
+	return ctx.Value(refreshKey{}) != nil
+}
+
+// Middleware serves GET requests from the cache, keyed by key, and stores
+// the responses of the handlers after it
+func (rc *Cache) Middleware(key KeyFunc) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request.Method != http.MethodGet {
+			c.Next()
+			return
+		}
+		k := key(c)
+		if Replayed(c.Request.Context()) {
+			rc.produce(c, k)
+			return
+		}
+
+		e, wait, lead := rc.lookup(c.Request, k)
+		switch {
+		case lead != nil:
+			defer rc.done(k, lead)
+			rc.produce(c, k)
+		case wait != nil:
+			// Another request is producing the response: wait for it, and
+			// produce our own if it was not cacheable
+			select {
+			case <-wait:
+			case <-c.Request.Context().Done():
+				c.Abort()
+				return
+			}
+			if e := rc.cached(k); e != nil {
+				rc.serve(c, e)
+			} else {
+				rc.produce(c, k)
+			}
+		default:
+			rc.serve(c, e)
+		}
+	}
+}
+
+// lookup finds the entry of k. It returns the entry to serve, or a channel
+// to wait on while another request produces it, or a channel the caller
+// must pass to done after producing it.
+func (rc *Cache) lookup(r *http.Request, k string) (*entry, <-chan struct{}, chan struct{}) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	now := rc.clock.Now()
+
+	if element, ok := rc.entries[k]; ok {
+		e := element.Value.(*entry)
+		age := now.Sub(e.storedAt)
+		if age < rc.ttl {
+			rc.recent.MoveToFront(element)
+			rc.count(ResultHit)
+			return e, nil, nil
+		}
+		if age < rc.ttl+rc.stale {
+			rc.recent.MoveToFront(element)
+			rc.count(ResultStale)
+			if _, loading := rc.loading[k]; loading || rc.refreshing[k] {
+				return e, nil, nil
+			}
+			if rc.replay != nil {
+				rc.refreshing[k] = true
+				// Copied here: once lookup returns, the caller's handlers
+				// and gin may still change or reuse r
+				replay, cancel := rc.replayRequest(r)
+				go rc.refresh(k, replay, cancel)
+				return e, nil, nil
+			}
+			lead := make(chan struct{})
+			rc.loading[k] = lead
+			return nil, nil, lead
+		}
+	}
+
+	if wait, loading := rc.loading[k]; loading {
+		rc.count(ResultCoalesced)
+		return nil, wait, nil
+	}
+	rc.count(ResultMiss)
+	lead := make(chan struct{})
+	rc.loading[k] = lead
+	return nil, nil, lead
+}
+
+// cached returns the entry of k while it may be served, fresh or stale
+func (rc *Cache) cached(k string) *entry {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	if element, ok := rc.entries[k]; ok {
+		e := element.Value.(*entry)
+		if rc.clock.Since(e.storedAt) < rc.ttl+rc.stale {
+			return e
+		}
+	}
+	return nil
+}
+
+// done releases the requests waiting for k
+func (rc *Cache) done(k string, lead chan struct{}) {
+	rc.mu.Lock()
+	delete(rc.loading, k)
+	rc.mu.Unlock()
+	close(lead)
+}
+
+// produce runs the handlers after the middleware, recording the response
+// and storing it when it is a 200
+func (rc *Cache) produce(c *gin.Context, k string) {
+	before := c.Writer.Header().Clone()
+	c.Header(HeaderCache, "MISS")
+	rec := &recorder{ResponseWriter: c.Writer}
+	c.Writer = rec
+	c.Next()
+	c.Writer = rec.ResponseWriter
+
+	if c.Writer.Status() != http.StatusOK {
+		return
+	}
+	header := http.Header{}
+	for name, values := range c.Writer.Header() {
+		if name != HeaderCache && !slices.Equal(before[name], values) {
+			header[name] = slices.Clone(values)
+		}
+	}
+	rc.store(&entry{key: k, status: http.StatusOK, header: header, body: rec.body.Bytes(), storedAt: rc.clock.Now()})
+}
+
+// store adds or replaces an entry, evicting the least recently used ones
+// beyond the bound
+func (rc *Cache) store(e *entry) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	if element, ok := rc.entries[e.key]; ok {
+		element.Value = e
+		rc.recent.MoveToFront(element)
+	} else {
+		rc.entries[e.key] = rc.recent.PushFront(e)
+	}
+	for rc.maxEntries > 0 && rc.recent.Len() > rc.maxEntries {
+		oldest := rc.recent.Back()
+		rc.recent.Remove(oldest)
+		delete(rc.entries, oldest.Value.(*entry).key)
+	}
+}
+
+// serve writes a cached response
+func (rc *Cache) serve(c *gin.Context, e *entry) {
+	for name, values := range e.header {
+		c.Writer.Header()[name] = slices.Clone(values)
+	}
+	age := rc.clock.Now().Sub(e.storedAt)
+	result := "HIT"
+	if age >= rc.ttl {
+		result = "STALE"
+	}
+	c.Header(HeaderCache, result)
+	c.Header("Age", strconv.Itoa(int(age/time.Second)))
+	c.Data(e.status, e.header.Get("Content-Type"), e.body)
+	c.Abort()
+}
+
+// replayRequest copies r for a background refresh, detached from the
+// caller's context and without its credentials
+func (rc *Cache) replayRequest(r *http.Request) (*http.Request, context.CancelFunc) {
+	ctx, cancel := context.WithTimeout(context.WithValue(context.Background(), refreshKey{}, true), refreshTimeout)
+	replay := r.Clone(ctx)
+	for _, name := range rc.strip {
+		replay.Header.Del(name)
+	}
+	return replay, cancel
+}
+
+// refresh replays the request of a stale entry, whose middleware stores the
+// new response
+func (rc *Cache) refresh(k string, r *http.Request, cancel context.CancelFunc) {
+	defer func() {
+		cancel()
+		rc.mu.Lock()
+		delete(rc.refreshing, k)
+		rc.mu.Unlock()
+	}()
+
+	w := &discard{header: http.Header{}, status: http.StatusOK}
+	rc.replay.ServeHTTP(w, r)
+	if w.status != http.StatusOK {
+		rc.logger.Warn("Cached response refresh failed, serving stale until it expires",
+			zap.String("key", k), zap.Int("status", w.status))
+	}
+}
+
+// count records a lookup
+func (rc *Cache) count(result string) {
+	if rc.lookups != nil {
+		rc.lookups.Inc(metrics.Labels{"result": result})
+	}
+}
+
+// recorder copies the body written by the handlers
+type recorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (r *recorder) Write(b []byte) (int, error) {
+	r.body.Write(b)
+	return r.ResponseWriter.Write(b)
+}
+
+func (r *recorder) WriteString(s string) (int, error) {
+	r.body.WriteString(s)
+	return r.ResponseWriter.WriteString(s)
+}
+
+// discard is the response writer of replayed requests
+type discard struct {
+	header http.Header
+	status int
+}
+
+func (d *discard) Header() http.Header { return d.header }
+
+func (d *discard) Write(b []byte) (int, error) { return len(b), nil }
+
+func (d *discard) WriteHeader(status int) { d.status = status }
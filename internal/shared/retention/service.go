@@ -0,0 +1,243 @@
+// Package retention enforces how long rows are kept. Tables register a
+// Policy, e.g. audit entries for 180 days, and the worker's retention job
+// deletes rows past their age in batches. Policies that archive write each
+// batch to the blob store as gzipped JSON lines before the deletion is
+// committed, so rows are either archived or still in the table.
+package retention
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"golang-arch/internal/shared/database"
+	"golang-arch/internal/shared/storage"
+	"golang-arch/pkg/clock"
+	"golang-arch/pkg/metrics"
+)
+
+// Defaults of a Service
+const (
+	DefaultBatchSize = 1000
+	DefaultPrefix    = "retention"
+)
+
+// Policy is how long the rows of a table are kept
+type Policy struct {
+	// Name identifies the policy in configuration, metrics and archive keys
+	Name string
+	// Table holds the rows
+	Table string
+	// TimeColumn is the timestamp rows age from; rows where it is NULL are
+	// kept
+	TimeColumn string
+	// MaxAge is how long rows are kept; zero keeps them forever
+	MaxAge time.Duration
+	// Archive copies rows to the blob store before deleting them
+	Archive bool
+}
+
+// Option configures a Service
+type Option func(*Service)
+
+// WithClock sets the clock rows age by
+func WithClock(c clock.Clock) Option {
+	return func(s *Service) {
+		s.clock = c
+	}
+}
+
+// WithLogger sets the logger
+func WithLogger(logger *zap.Logger) Option {
+	return func(s *Service) {
+		s.logger = logger
+	}
+}
+
+// WithBatchSize sets the number of rows deleted, and archived, at a time
+func WithBatchSize(size int) Option {
+	return func(s *Service) {
+		if size > 0 {
+			s.batchSize = size
+		}
+	}
+}
+
+// WithPrefix sets the blob key prefix of archives
+func WithPrefix(prefix string) Option {
+	return func(s *Service) {
+		if prefix != "" {
+			s.prefix = prefix
+		}
+	}
+}
+
+// WithMaxAges overrides the MaxAge of policies by name, e.g. from
+// configuration
+func WithMaxAges(maxAges map[string]time.Duration) Option {
+	return func(s *Service) {
+		s.maxAges = maxAges
+	}
+}
+
+// WithRowsCounter counts archived and deleted rows on counter, labeled with
+// "policy" and "action"
+func WithRowsCounter(counter *metrics.Counter) Option {
+	return func(s *Service) {
+		s.rows = counter
+	}
+}
+
+// Service runs the registered retention policies
+type Service struct {
+	store     Store
+	blobs     storage.BlobStore
+	clock     clock.Clock
+	logger    *zap.Logger
+	batchSize int
+	prefix    string
+	maxAges   map[string]time.Duration
+	rows      *metrics.Counter
+
+	mu       sync.RWMutex
+	policies []Policy
+}
+
+// NewService creates a service deleting through store and archiving to
+// blobs
+func NewService(store Store, blobs storage.BlobStore, options ...Option) *Service {
+	s := &Service{
+		store:     store,
+		blobs:     blobs,
+		clock:     clock.New(),
+		logger:    zap.NewNop(),
+		batchSize: DefaultBatchSize,
+		prefix:    DefaultPrefix,
+	}
+	for _, option := range options {
+		option(s)
+	}
+	return s
+}
+
+// Register adds a policy; a configured max age replaces policy.MaxAge. It
+// panics on a duplicate name or on table and column names that are not
+// plain identifiers, programming errors.
+func (s *Service) Register(policy Policy) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if policy.Name == "" || !database.ValidIdentifier(policy.Table) || !database.ValidIdentifier(policy.TimeColumn) {
+		panic(fmt.Sprintf("retention: invalid policy %q on %s.%s", policy.Name, policy.Table, policy.TimeColumn))
+	}
+	for _, registered := range s.policies {
+		if registered.Name == policy.Name {
+			panic(fmt.Sprintf("retention: policy %s registered twice", policy.Name))
+		}
+	}
+	if maxAge, ok := s.maxAges[policy.Name]; ok {
+		policy.MaxAge = maxAge
+	}
+	s.policies = append(s.policies, policy)
+}
+
+// Policies returns the registered policies, with their configured max ages
+func (s *Service) Policies() []Policy {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return append([]Policy(nil), s.policies...)
+}
+
+// Run purges the expired rows of every policy. It is the retention worker
+// job. A failing policy stops at the failed batch and is retried by the
+// next run, while the others go on.
+func (s *Service) Run(ctx context.Context) error {
+	var errs []error
+	for _, policy := range s.Policies() {
+		if policy.MaxAge <= 0 {
+			continue
+		}
+		if err := s.run(ctx, policy); err != nil {
+			errs = append(errs, fmt.Errorf("retention %s: %w", policy.Name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// run purges one policy's expired rows batch by batch until none are left
+func (s *Service) run(ctx context.Context, policy Policy) error {
+	started := s.clock.Now().UTC()
+	cutoff := started.Add(-policy.MaxAge)
+	logger := s.logger.With(zap.String("policy", policy.Name))
+
+	total := 0
+	for batch := 1; ; batch++ {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		var archive func([]json.RawMessage) error
+		if policy.Archive {
+			key := s.archiveKey(policy, started, batch)
+			archive = func(rows []json.RawMessage) error { return s.archive(ctx, key, rows) }
+		}
+
+		purged, err := s.store.Purge(ctx, policy, cutoff, s.batchSize, archive)
+		if err != nil {
+			return err
+		}
+		total += purged
+		s.count(policy, purged)
+		logger.Debug("Purged expired rows", zap.Int("batch", batch), zap.Int("rows", purged), zap.Int("total", total))
+
+		if purged < s.batchSize {
+			break
+		}
+	}
+	if total > 0 {
+		logger.Info("Retention policy applied", zap.Int("rows", total), zap.Time("cutoff", cutoff),
+			zap.Bool("archived", policy.Archive))
+	}
+	return nil
+}
+
+// archiveKey names the blob of one batch:
+// <prefix>/<policy>/<yyyy>/<mm>/<dd>/<hhmmss>-<batch>.jsonl.gz
+func (s *Service) archiveKey(policy Policy, started time.Time, batch int) string {
+	return fmt.Sprintf("%s/%s/%s-%04d.jsonl.gz", s.prefix, policy.Name, started.Format("2006/01/02/150405"), batch)
+}
+
+// archive writes rows to key as gzipped JSON lines
+func (s *Service) archive(ctx context.Context, key string, rows []json.RawMessage) error {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	for _, row := range rows {
+		zw.Write(row)
+		zw.Write([]byte{'\n'})
+	}
+	if err := zw.Close(); err != nil {
+		return fmt.Errorf("failed to compress archive %s: %w", key, err)
+	}
+	if _, err := s.blobs.Put(ctx, key, &buf, storage.PutOptions{ContentType: "application/gzip"}); err != nil {
+		return fmt.Errorf("failed to archive to %s: %w", key, err)
+	}
+	return nil
+}
+
+// count records a batch on the rows counter
+func (s *Service) count(policy Policy, purged int) {
+	if s.rows == nil || purged == 0 {
+		return
+	}
+	if policy.Archive {
+		s.rows.Add(float64(purged), metrics.Labels{"policy": policy.Name, "action": "archived"})
+	}
+	s.rows.Add(float64(purged), metrics.Labels{"policy": policy.Name, "action": "deleted"})
+}
@@ -0,0 +1,131 @@
+package retention
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+)
+
+// Store deletes expired rows
+type Store interface {
+	// Purge deletes up to limit rows of the policy's table older than
+	// before, oldest first, and returns how many it deleted. When archive
+	// is not nil it gets the deleted rows as JSON objects before the
+	// deletion is committed; an archive error keeps the rows.
+	Purge(ctx context.Context, policy Policy, before time.Time, limit int, archive func([]json.RawMessage) error) (int, error)
+}
+
+// PostgresStore purges rows of Postgres tables
+type PostgresStore struct {
+	db *sql.DB
+}
+
+// NewPostgresStore creates a store on db
+func NewPostgresStore(db *sql.DB) *PostgresStore {
+	return &PostgresStore{db: db}
+}
+
+// Purge deletes a batch in a transaction, returning the rows as JSON.
+// Rows another worker is purging are skipped rather than waited for.
+func (s *PostgresStore) Purge(ctx context.Context, policy Policy, before time.Time, limit int, archive func([]json.RawMessage) error) (int, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin purge of %s: %w", policy.Table, err)
+	}
+	defer tx.Rollback()
+
+	// Table and column names are checked by Register
+	rows, err := tx.QueryContext(ctx, fmt.Sprintf(
+		`DELETE FROM %[1]s AS expired WHERE ctid IN (
+			SELECT ctid FROM %[1]s WHERE %[2]s < $1 ORDER BY %[2]s LIMIT $2 FOR UPDATE SKIP LOCKED
+		) RETURNING row_to_json(expired)::text`, policy.Table, policy.TimeColumn),
+		before, limit)
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge %s: %w", policy.Table, err)
+	}
+	defer rows.Close()
+
+	var purged []json.RawMessage
+	for rows.Next() {
+		var row string
+		if err := rows.Scan(&row); err != nil {
+			return 0, fmt.Errorf("failed to read purged row of %s: %w", policy.Table, err)
+		}
+		purged = append(purged, json.RawMessage(row))
+	}
+	if err := rows.Err(); err != nil {
+		return 0, fmt.Errorf("failed to purge %s: %w", policy.Table, err)
+	}
+
+	if archive != nil && len(purged) > 0 {
+		if err := archive(purged); err != nil {
+			return 0, err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit purge of %s: %w", policy.Table, err)
+	}
+	return len(purged), nil
+}
+
+// MemoryStore keeps rows in memory, for tests and the dev profile
+type MemoryStore struct {
+	mu     sync.Mutex
+	tables map[string][]memoryRow
+}
+
+type memoryRow struct {
+	at   time.Time
+	data json.RawMessage
+}
+
+// NewMemoryStore creates an empty in-memory store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{tables: make(map[string][]memoryRow)}
+}
+
+// Add inserts a row of table timestamped at
+func (s *MemoryStore) Add(table string, at time.Time, data json.RawMessage) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.tables[table] = append(s.tables[table], memoryRow{at: at, data: data})
+}
+
+// Len returns the number of rows left in table
+func (s *MemoryStore) Len(table string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return len(s.tables[table])
+}
+
+// Purge deletes the oldest expired rows
+func (s *MemoryStore) Purge(_ context.Context, policy Policy, before time.Time, limit int, archive func([]json.RawMessage) error) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	table := slices.Clone(s.tables[policy.Table])
+	slices.SortStableFunc(table, func(a, b memoryRow) int { return a.at.Compare(b.at) })
+
+	var purged []json.RawMessage
+	kept := table[:0]
+	for _, row := range table {
+		if row.at.Before(before) && len(purged) < limit {
+			purged = append(purged, row.data)
+			continue
+		}
+		kept = append(kept, row)
+	}
+	if archive != nil && len(purged) > 0 {
+		if err := archive(purged); err != nil {
+			return 0, err
+		}
+	}
+	s.tables[policy.Table] = kept
+	return len(purged), nil
+}
@@ -0,0 +1,336 @@
+package saga
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"golang-arch/internal/shared/domain/domainerror"
+	"golang-arch/internal/shared/events"
+	"golang-arch/pkg/clock"
+)
+
+// Defaults of an Orchestrator
+const (
+	DefaultMaxAttempts  = 3
+	DefaultRetryBackoff = 10 * time.Second
+	DefaultLease        = time.Minute
+)
+
+// resumeBatch is the number of instances claimed by one Resume
+const resumeBatch = 50
+
+// Event names published when an instance finishes
+const (
+	EventCompleted   = "saga.completed"
+	EventCompensated = "saga.compensated"
+	EventFailed      = "saga.failed"
+)
+
+// Finished is published when an instance completes, is compensated or
+// fails
+type Finished struct {
+	events.Base
+	Name       string `json:"name"` // One of EventCompleted, EventCompensated, EventFailed
+	InstanceID string `json:"instance_id"`
+	Saga       string `json:"saga"`
+	Error      string `json:"error,omitempty"`
+}
+
+// EventName returns the event's name
+func (e Finished) EventName() string {
+	return e.Name
+}
+
+// Orchestrator runs saga instances and resumes interrupted ones
+type Orchestrator struct {
+	store       Store
+	clock       clock.Clock
+	logger      *zap.Logger
+	bus         *events.Bus
+	maxAttempts int
+	backoff     time.Duration
+	lease       time.Duration
+
+	mu          sync.RWMutex
+	definitions map[string]Definition
+}
+
+// Option configures an Orchestrator
+type Option func(*Orchestrator)
+
+// WithClock sets the clock retries and leases are timed with
+func WithClock(c clock.Clock) Option {
+	return func(o *Orchestrator) {
+		o.clock = c
+	}
+}
+
+// WithLogger sets the logger
+func WithLogger(logger *zap.Logger) Option {
+	return func(o *Orchestrator) {
+		o.logger = logger
+	}
+}
+
+// WithEvents publishes a Finished event on bus when an instance finishes
+func WithEvents(bus *events.Bus) Option {
+	return func(o *Orchestrator) {
+		o.bus = bus
+	}
+}
+
+// WithMaxAttempts sets the tries of a step or compensation for definitions
+// without their own
+func WithMaxAttempts(attempts int) Option {
+	return func(o *Orchestrator) {
+		if attempts > 0 {
+			o.maxAttempts = attempts
+		}
+	}
+}
+
+// WithRetryBackoff sets the wait before the first retry; it doubles with
+// every further attempt
+func WithRetryBackoff(backoff time.Duration) Option {
+	return func(o *Orchestrator) {
+		if backoff > 0 {
+			o.backoff = backoff
+		}
+	}
+}
+
+// WithLease sets how long an orchestrator owns an instance it runs. Another
+// instance of the application resumes it once the lease ran out, so it must
+// exceed the longest step.
+func WithLease(lease time.Duration) Option {
+	return func(o *Orchestrator) {
+		if lease > 0 {
+			o.lease = lease
+		}
+	}
+}
+
+// NewOrchestrator creates an orchestrator storing instances in store
+func NewOrchestrator(store Store, options ...Option) *Orchestrator {
+	o := &Orchestrator{
+		store:       store,
+		clock:       clock.New(),
+		logger:      zap.NewNop(),
+		maxAttempts: DefaultMaxAttempts,
+		backoff:     DefaultRetryBackoff,
+		lease:       DefaultLease,
+		definitions: make(map[string]Definition),
+	}
+	for _, option := range options {
+		option(o)
+	}
+	return o
+}
+
+// Register adds a definition; instances of unknown sagas are not resumed.
+// It panics on a duplicate or empty definition, a programming error.
+func (o *Orchestrator) Register(definition Definition) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if definition.Name == "" || len(definition.Steps) == 0 {
+		panic("saga: definition needs a name and steps")
+	}
+	if _, ok := o.definitions[definition.Name]; ok {
+		panic(fmt.Sprintf("saga: %s registered twice", definition.Name))
+	}
+	o.definitions[definition.Name] = definition
+}
+
+// Start creates an instance of the named saga with the initial data and
+// runs it until it finishes or a step must be retried; retries are left to
+// Resume. The returned instance tells which.
+func (o *Orchestrator) Start(ctx context.Context, name string, data Data) (Instance, error) {
+	definition, ok := o.definition(name)
+	if !ok {
+		return Instance{}, domainerror.NotFoundf("unknown saga %q", name)
+	}
+	if data == nil {
+		data = Data{}
+	}
+
+	now := o.clock.Now().UTC()
+	instance := Instance{
+		ID:            uuid.NewString(),
+		Saga:          name,
+		Status:        StatusRunning,
+		Data:          data,
+		NextAttemptAt: now,
+		LockedUntil:   now.Add(o.lease),
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+	if err := o.store.Create(ctx, instance); err != nil {
+		return Instance{}, err
+	}
+	return o.run(ctx, definition, instance)
+}
+
+// Get returns an instance
+func (o *Orchestrator) Get(ctx context.Context, id string) (Instance, error) {
+	instance, found, err := o.store.Get(ctx, id)
+	if err != nil {
+		return Instance{}, err
+	}
+	if !found {
+		return Instance{}, domainerror.NotFoundf("saga instance %s not found", id)
+	}
+	return instance, nil
+}
+
+// Resume claims the instances that are due for a retry or whose runner's
+// lease expired, e.g. after a crash, and runs them on. It is the saga_resume
+// worker job.
+func (o *Orchestrator) Resume(ctx context.Context) error {
+	instances, err := o.store.Claim(ctx, o.clock.Now().UTC(), o.lease, resumeBatch)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, instance := range instances {
+		definition, ok := o.definition(instance.Saga)
+		if !ok {
+			o.logger.Error("Cannot resume instance of an unregistered saga",
+				zap.String("saga", instance.Saga), zap.String("instance", instance.ID))
+			continue
+		}
+		if _, err := o.run(ctx, definition, instance); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// run advances the instance as far as it can, saving it after every step.
+// Step failures are recorded in the instance, not returned; the error is
+// only set when the instance could not be saved.
+func (o *Orchestrator) run(ctx context.Context, definition Definition, instance Instance) (Instance, error) {
+	logger := o.logger.With(zap.String("saga", instance.Saga), zap.String("instance", instance.ID))
+	maxAttempts := definition.MaxAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = o.maxAttempts
+	}
+
+	for !instance.Status.Finished() {
+		var err error
+		switch instance.Status {
+		case StatusRunning:
+			if instance.Step >= len(definition.Steps) {
+				instance.Status = StatusCompleted
+				break
+			}
+			step := definition.Steps[instance.Step]
+			if err = runStep(ctx, step.Run, instance.Data); err == nil {
+				instance.Step++
+				instance.Attempts = 0
+				break
+			}
+			instance.Attempts++
+			if errors.Is(err, ErrAbort) || instance.Attempts >= maxAttempts {
+				logger.Warn("Saga step failed, compensating", zap.String("step", step.Name), zap.Error(err))
+				instance.Status = StatusCompensating
+				instance.Error = fmt.Sprintf("%s: %v", step.Name, err)
+				instance.Attempts = 0
+				err = nil
+			}
+
+		case StatusCompensating:
+			if instance.Step == 0 {
+				instance.Status = StatusCompensated
+				break
+			}
+			step := definition.Steps[instance.Step-1]
+			if step.Compensate == nil {
+				instance.Step--
+				break
+			}
+			if err = runStep(ctx, step.Compensate, instance.Data); err == nil {
+				instance.Step--
+				instance.Attempts = 0
+				break
+			}
+			if instance.Attempts++; instance.Attempts >= maxAttempts {
+				logger.Error("Saga compensation failed, manual repair needed", zap.String("step", step.Name), zap.Error(err))
+				instance.Status = StatusFailed
+				instance.Error = fmt.Sprintf("%s; compensating %s: %v", instance.Error, step.Name, err)
+				err = nil
+			}
+		}
+
+		now := o.clock.Now().UTC()
+		instance.UpdatedAt = now
+		if err != nil {
+			// Retry later, from Resume
+			logger.Warn("Saga step failed, retrying", zap.Int("attempt", instance.Attempts), zap.Error(err))
+			instance.NextAttemptAt = now.Add(o.backoff << (instance.Attempts - 1))
+			instance.LockedUntil = time.Time{}
+			return instance, o.store.Save(ctx, instance)
+		}
+		if instance.Status.Finished() {
+			instance.LockedUntil = time.Time{}
+		} else {
+			instance.LockedUntil = now.Add(o.lease)
+		}
+		if err := o.store.Save(ctx, instance); err != nil {
+			return instance, err
+		}
+	}
+
+	o.finish(ctx, instance, logger)
+	return instance, nil
+}
+
+// finish logs the outcome and publishes it
+func (o *Orchestrator) finish(ctx context.Context, instance Instance, logger *zap.Logger) {
+	name := map[Status]string{StatusCompleted: EventCompleted, StatusCompensated: EventCompensated, StatusFailed: EventFailed}[instance.Status]
+	logger.Info("Saga finished", zap.String("status", string(instance.Status)))
+	if o.bus == nil {
+		return
+	}
+	event := Finished{
+		Base:       events.Base{Metadata: events.NewMetadata(o.clock, "saga", instance.ID)},
+		Name:       name,
+		InstanceID: instance.ID,
+		Saga:       instance.Saga,
+		Error:      instance.Error,
+	}
+	if err := o.bus.PublishAsync(ctx, event); err != nil {
+		logger.Warn("Failed to announce saga outcome", zap.Error(err))
+	}
+}
+
+// definition returns the registered definition named name
+func (o *Orchestrator) definition(name string) (Definition, bool) {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+	definition, ok := o.definitions[name]
+	return definition, ok
+}
+
+// runStep runs fn, turning a panic into an error. Changes a failed fn made
+// to data are discarded.
+func runStep(ctx context.Context, fn func(ctx context.Context, data Data) error, data Data) (err error) {
+	snapshot := maps.Clone(data)
+	defer func() {
+		if value := recover(); value != nil {
+			err = fmt.Errorf("step panicked: %v", value)
+		}
+		if err != nil {
+			clear(data)
+			maps.Copy(data, snapshot)
+		}
+	}()
+	return fn(ctx, data)
+}
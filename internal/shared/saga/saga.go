@@ -0,0 +1,106 @@
+// Package saga runs multi-step workflows that span services, such as
+// "charge → convert → payout", reliably. A Definition lists the Steps of a
+// workflow, each with an action and the compensation that undoes it. The
+// Orchestrator stores every instance in Postgres after each step, so a
+// crashed instance is resumed by the saga_resume worker job where it left
+// off.
+//
+// A failing step is retried with backoff. Once its attempts are used up, or
+// when it returns an error wrapping ErrAbort, the completed steps are
+// compensated in reverse order. Steps and compensations may run more than
+// once and must be idempotent, e.g. by passing the instance ID as the
+// idempotency key of the services they call.
+package saga
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrAbort marks a step error that retrying cannot fix, e.g. a declined
+// card; the saga is compensated at once
+var ErrAbort = errors.New("saga aborted")
+
+// Abort returns an error that compensates the saga without retrying the step
+func Abort(reason string) error {
+	return fmt.Errorf("%w: %s", ErrAbort, reason)
+}
+
+// Status is the progress of a saga instance
+type Status string
+
+// Statuses
+const (
+	StatusRunning      Status = "running"      // Steps are being run
+	StatusCompensating Status = "compensating" // A step failed; completed steps are being undone
+	StatusCompleted    Status = "completed"    // Every step ran
+	StatusCompensated  Status = "compensated"  // Every completed step was undone
+	StatusFailed       Status = "failed"       // A compensation kept failing; needs manual repair
+)
+
+// Finished reports whether the instance will not run again
+func (s Status) Finished() bool {
+	return s == StatusCompleted || s == StatusCompensated || s == StatusFailed
+}
+
+// Step is one action of a saga and the compensation that undoes it
+type Step struct {
+	Name string
+	// Run performs the step; it may read and write the instance data
+	Run func(ctx context.Context, data Data) error
+	// Compensate undoes Run after a later step failed; nil when there is
+	// nothing to undo, e.g. for a read-only step
+	Compensate func(ctx context.Context, data Data) error
+}
+
+// Definition is a named workflow
+type Definition struct {
+	Name  string
+	Steps []Step
+	// MaxAttempts bounds the tries of each step and compensation; the
+	// orchestrator's default when zero
+	MaxAttempts int
+}
+
+// Instance is one run of a saga
+type Instance struct {
+	ID            string    `json:"id"`
+	Saga          string    `json:"saga"`
+	Status        Status    `json:"status"`
+	Step          int       `json:"step"` // Index of the next step to run, or of the last one to compensate plus one
+	Data          Data      `json:"data"`
+	Error         string    `json:"error,omitempty"` // The failure that started compensation
+	Attempts      int       `json:"attempts"`        // Failed tries of the current step or compensation
+	NextAttemptAt time.Time `json:"next_attempt_at"`
+	LockedUntil   time.Time `json:"-"` // Lease of the orchestrator running the instance
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// Data is the state steps share, stored as JSON by key
+type Data map[string]json.RawMessage
+
+// Get decodes the value under key into v; found is false when it is unset
+func (d Data) Get(key string, v any) (bool, error) {
+	raw, ok := d[key]
+	if !ok {
+		return false, nil
+	}
+	if err := json.Unmarshal(raw, v); err != nil {
+		return true, fmt.Errorf("failed to decode saga data %q: %w", key, err)
+	}
+	return true, nil
+}
+
+// Set stores v under key
+func (d Data) Set(key string, v any) error {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("failed to encode saga data %q: %w", key, err)
+	}
+	d[key] = raw
+	return nil
+}
@@ -0,0 +1,217 @@
+package saga
+
+import (
+	"cmp"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+)
+
+// Store persists saga instances
+type Store interface {
+	// Create inserts a new instance
+	Create(ctx context.Context, instance Instance) error
+	// Save replaces an instance
+	Save(ctx context.Context, instance Instance) error
+	// Get returns an instance; found is false when it does not exist
+	Get(ctx context.Context, id string) (Instance, bool, error)
+	// Claim leases up to limit unfinished instances due at now whose lease
+	// expired, until now+lease, and returns them oldest due first
+	Claim(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]Instance, error)
+}
+
+// PostgresStore keeps instances in the sagas table
+type PostgresStore struct {
+	db *sql.DB
+}
+
+// NewPostgresStore creates a store on db
+func NewPostgresStore(db *sql.DB) *PostgresStore {
+	return &PostgresStore{db: db}
+}
+
+const sagaColumns = `id, saga, status, step, data, error, attempts, next_attempt_at, locked_until, created_at, updated_at`
+
+// Create inserts the instance
+func (s *PostgresStore) Create(ctx context.Context, instance Instance) error {
+	data, err := json.Marshal(instance.Data)
+	if err != nil {
+		return fmt.Errorf("failed to encode saga data: %w", err)
+	}
+	_, err = s.db.ExecContext(ctx,
+		`INSERT INTO sagas (`+sagaColumns+`) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)`,
+		instance.ID, instance.Saga, string(instance.Status), instance.Step, data, instance.Error, instance.Attempts,
+		instance.NextAttemptAt, instance.LockedUntil, instance.CreatedAt, instance.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create saga instance: %w", err)
+	}
+	return nil
+}
+
+// Save updates the instance
+func (s *PostgresStore) Save(ctx context.Context, instance Instance) error {
+	data, err := json.Marshal(instance.Data)
+	if err != nil {
+		return fmt.Errorf("failed to encode saga data: %w", err)
+	}
+	_, err = s.db.ExecContext(ctx,
+		`UPDATE sagas SET status = $2, step = $3, data = $4, error = $5, attempts = $6,
+			next_attempt_at = $7, locked_until = $8, updated_at = $9
+		WHERE id = $1`,
+		instance.ID, string(instance.Status), instance.Step, data, instance.Error, instance.Attempts,
+		instance.NextAttemptAt, instance.LockedUntil, instance.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save saga instance %s: %w", instance.ID, err)
+	}
+	return nil
+}
+
+// Get selects one instance
+func (s *PostgresStore) Get(ctx context.Context, id string) (Instance, bool, error) {
+	instance, err := scanInstance(s.db.QueryRowContext(ctx, `SELECT `+sagaColumns+` FROM sagas WHERE id = $1`, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return Instance{}, false, nil
+	}
+	if err != nil {
+		return Instance{}, false, fmt.Errorf("failed to read saga instance: %w", err)
+	}
+	return instance, true, nil
+}
+
+// Claim leases due instances; SKIP LOCKED lets several workers claim
+// concurrently without waiting on each other
+func (s *PostgresStore) Claim(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]Instance, error) {
+	rows, err := s.db.QueryContext(ctx,
+		`UPDATE sagas SET locked_until = $2
+		WHERE id IN (
+			SELECT id FROM sagas
+			WHERE status IN ('running', 'compensating') AND next_attempt_at <= $1 AND locked_until <= $1
+			ORDER BY next_attempt_at
+			LIMIT $3
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING `+sagaColumns,
+		now, now.Add(lease), limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim saga instances: %w", err)
+	}
+	defer rows.Close()
+
+	var instances []Instance
+	for rows.Next() {
+		instance, err := scanInstance(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read saga instance: %w", err)
+		}
+		instances = append(instances, instance)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to claim saga instances: %w", err)
+	}
+	slices.SortFunc(instances, func(a, b Instance) int { return a.NextAttemptAt.Compare(b.NextAttemptAt) })
+	return instances, nil
+}
+
+// scanner is satisfied by *sql.Row and *sql.Rows
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+func scanInstance(row scanner) (Instance, error) {
+	var instance Instance
+	var status string
+	var data []byte
+	err := row.Scan(&instance.ID, &instance.Saga, &status, &instance.Step, &data, &instance.Error, &instance.Attempts,
+		&instance.NextAttemptAt, &instance.LockedUntil, &instance.CreatedAt, &instance.UpdatedAt)
+	if err != nil {
+		return Instance{}, err
+	}
+	instance.Status = Status(status)
+	if err := json.Unmarshal(data, &instance.Data); err != nil {
+		return Instance{}, fmt.Errorf("failed to decode saga data of %s: %w", instance.ID, err)
+	}
+	if instance.Data == nil {
+		instance.Data = Data{}
+	}
+	return instance, nil
+}
+
+// MemoryStore keeps instances in memory, for tests and the dev profile
+type MemoryStore struct {
+	mu        sync.Mutex
+	instances map[string]Instance
+}
+
+// NewMemoryStore creates an empty in-memory store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{instances: make(map[string]Instance)}
+}
+
+// Create stores a new instance
+func (s *MemoryStore) Create(_ context.Context, instance Instance) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.instances[instance.ID]; ok {
+		return fmt.Errorf("saga instance %s already exists", instance.ID)
+	}
+	s.instances[instance.ID] = copyInstance(instance)
+	return nil
+}
+
+// Save replaces an instance
+func (s *MemoryStore) Save(_ context.Context, instance Instance) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.instances[instance.ID] = copyInstance(instance)
+	return nil
+}
+
+// Get returns an instance
+func (s *MemoryStore) Get(_ context.Context, id string) (Instance, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	instance, ok := s.instances[id]
+	return copyInstance(instance), ok, nil
+}
+
+// Claim leases due instances
+func (s *MemoryStore) Claim(_ context.Context, now time.Time, lease time.Duration, limit int) ([]Instance, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var due []Instance
+	for _, instance := range s.instances {
+		if !instance.Status.Finished() && !instance.NextAttemptAt.After(now) && !instance.LockedUntil.After(now) {
+			due = append(due, instance)
+		}
+	}
+	slices.SortFunc(due, func(a, b Instance) int {
+		return cmp.Or(a.NextAttemptAt.Compare(b.NextAttemptAt), cmp.Compare(a.ID, b.ID))
+	})
+	if len(due) > limit {
+		due = due[:limit]
+	}
+	for i := range due {
+		due[i].LockedUntil = now.Add(lease)
+		s.instances[due[i].ID] = due[i]
+		due[i] = copyInstance(due[i])
+	}
+	return due, nil
+}
+
+// copyInstance copies the instance's data so callers cannot change the
+// stored one
+func copyInstance(instance Instance) Instance {
+	if instance.Data != nil {
+		data := make(Data, len(instance.Data))
+		for key, value := range instance.Data {
+			data[key] = append([]byte(nil), value...)
+		}
+		instance.Data = data
+	}
+	return instance
+}
@@ -0,0 +1,268 @@
+// Package schema generates JSON Schema (draft 2020-12) documents and GraphQL
+// scalar definitions from Go types.
+//
+// Struct fields are described from their json tags; fields without omitempty
+// are required. Types that serialize differently from their struct layout, or
+// that carry constraints, implement Extender to adjust the generated schema.
+// Named struct types referenced by a root type are emitted once under $defs.
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Draft is the JSON Schema dialect of generated documents
+const Draft = "https://json-schema.org/draft/2020-12/schema"
+
+// Schema is a JSON Schema document or subschema
+type Schema struct {
+	Schema               string             `json:"$schema,omitempty"`
+	ID                   string             `json:"$id,omitempty"`
+	Ref                  string             `json:"$ref,omitempty"`
+	Title                string             `json:"title,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Pattern              string             `json:"pattern,omitempty"`
+	MinLength            *int               `json:"minLength,omitempty"`
+	MaxLength            *int               `json:"maxLength,omitempty"`
+	Minimum              *float64           `json:"minimum,omitempty"`
+	Maximum              *float64           `json:"maximum,omitempty"`
+	Enum                 []any              `json:"enum,omitempty"`
+	ReadOnly             bool               `json:"readOnly,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties *bool              `json:"additionalProperties,omitempty"`
+	Examples             []any              `json:"examples,omitempty"`
+	Defs                 map[string]*Schema `json:"$defs,omitempty"`
+}
+
+// Extender is implemented by types that refine their generated schema
+type Extender interface {
+	JSONSchemaExtend(s *Schema)
+}
+
+// Int returns a pointer to n, for MinLength and MaxLength
+func Int(n int) *int {
+	return &n
+}
+
+// Number returns a pointer to n, for Minimum and Maximum
+func Number(n float64) *float64 {
+	return &n
+}
+
+// Bool returns a pointer to b, for AdditionalProperties
+func Bool(b bool) *bool {
+	return &b
+}
+
+var extenderType = reflect.TypeOf((*Extender)(nil)).Elem()
+
+// Generate builds the schema document for the type of v. baseURL prefixes the
+// document $id, which is the type's FileName.
+func Generate(v any, baseURL string) (*Schema, error) {
+	t := reflect.TypeOf(v)
+	for t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("schema: %T is not a struct type", v)
+	}
+
+	g := &generator{root: t, defs: make(map[string]*Schema)}
+	root := g.describeStruct(t)
+	root.Schema = Draft
+	root.ID = baseURL + FileName(t.Name())
+	root.Title = t.Name()
+	if len(g.defs) > 0 {
+		root.Defs = g.defs
+	}
+	return root, nil
+}
+
+// FileName returns the schema file name for a type name, e.g.
+// "LocalizedDateTime" -> "localized_date_time.schema.json"
+func FileName(typeName string) string {
+	var b strings.Builder
+	for i, r := range typeName {
+		if unicode.IsUpper(r) {
+			if i > 0 {
+				b.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String() + ".schema.json"
+}
+
+type generator struct {
+	root reflect.Type
+	defs map[string]*Schema
+}
+
+// describe returns the schema for a field of type t, registering named
+// structs under $defs
+func (g *generator) describe(t reflect.Type) *Schema {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	if t.Kind() == reflect.Struct && t.Name() != "" {
+		if t == g.root {
+			return &Schema{Ref: "#"}
+		}
+		if _, ok := g.defs[t.Name()]; !ok {
+			g.defs[t.Name()] = nil // guard against recursive types
+			g.defs[t.Name()] = g.describeStruct(t)
+		}
+		return &Schema{Ref: "#/$defs/" + t.Name()}
+	}
+
+	var s *Schema
+	switch t.Kind() {
+	case reflect.String:
+		s = &Schema{Type: "string"}
+	case reflect.Bool:
+		s = &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		s = &Schema{Type: "integer"}
+	case reflect.Float32, reflect.Float64:
+		s = &Schema{Type: "number"}
+	case reflect.Slice, reflect.Array:
+		s = &Schema{Type: "array", Items: g.describe(t.Elem())}
+	case reflect.Map:
+		s = &Schema{Type: "object"}
+	case reflect.Struct:
+		s = g.describeStruct(t)
+	default:
+		s = &Schema{}
+	}
+	extend(t, s)
+	return s
+}
+
+// describeStruct describes the exported, json-visible fields of t
+func (g *generator) describeStruct(t reflect.Type) *Schema {
+	s := &Schema{
+		Type:                 "object",
+		Properties:           make(map[string]*Schema),
+		AdditionalProperties: Bool(false),
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name, omitempty := jsonName(field)
+		if name == "-" {
+			continue
+		}
+
+		s.Properties[name] = g.describe(field.Type)
+		if !omitempty {
+			s.Required = append(s.Required, name)
+		}
+	}
+
+	extend(t, s)
+	if len(s.Properties) == 0 {
+		s.Properties = nil
+	}
+	sort.Strings(s.Required)
+	return s
+}
+
+// jsonName returns the encoded name of a struct field and whether it is
+// optional
+func jsonName(field reflect.StructField) (string, bool) {
+	tag := field.Tag.Get("json")
+	name, options, _ := strings.Cut(tag, ",")
+	if name == "" {
+		name = field.Name
+	}
+	return name, strings.Contains(options, "omitempty")
+}
+
+// extend applies the Extender of t, if any
+func extend(t reflect.Type, s *Schema) {
+	if t.Implements(extenderType) {
+		reflect.Zero(t).Interface().(Extender).JSONSchemaExtend(s)
+	}
+}
+
+// Marshal encodes a schema document as indented JSON with a trailing newline
+func Marshal(s *Schema) ([]byte, error) {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
+
+// GraphQLFileName is the file WriteFiles writes the GraphQL scalars to
+const GraphQLFileName = "schema.graphql"
+
+// GraphQL renders a GraphQL scalar definition for each schema document. The
+// scalars reuse the schema description and point to the document through
+// @specifiedBy.
+func GraphQL(documents ...*Schema) string {
+	var b strings.Builder
+	b.WriteString("# Code generated by \"golang-arch schema\". DO NOT EDIT.\n")
+	for _, doc := range documents {
+		b.WriteString("\n")
+		if doc.Description != "" {
+			b.WriteString("\"\"\"\n")
+			b.WriteString(strings.ReplaceAll(doc.Description, `"""`, `\"""`))
+			b.WriteString("\n\"\"\"\n")
+		}
+		fmt.Fprintf(&b, "scalar %s @specifiedBy(url: %q)\n", doc.Title, doc.ID)
+	}
+	return b.String()
+}
+
+// WriteFiles generates a schema document per value plus the GraphQL scalar
+// file into dir, returning the written paths
+func WriteFiles(dir, baseURL string, values ...any) ([]string, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("schema: failed to create %s: %w", dir, err)
+	}
+
+	documents := make([]*Schema, 0, len(values))
+	paths := make([]string, 0, len(values)+1)
+	for _, v := range values {
+		doc, err := Generate(v, baseURL)
+		if err != nil {
+			return nil, err
+		}
+		data, err := Marshal(doc)
+		if err != nil {
+			return nil, fmt.Errorf("schema: failed to encode %s: %w", doc.Title, err)
+		}
+
+		path := filepath.Join(dir, FileName(doc.Title))
+		if err := os.WriteFile(path, data, 0o644); err != nil {
+			return nil, fmt.Errorf("schema: failed to write %s: %w", path, err)
+		}
+		documents = append(documents, doc)
+		paths = append(paths, path)
+	}
+
+	path := filepath.Join(dir, GraphQLFileName)
+	if err := os.WriteFile(path, []byte(GraphQL(documents...)), 0o644); err != nil {
+		return nil, fmt.Errorf("schema: failed to write %s: %w", path, err)
+	}
+	return append(paths, path), nil
+}
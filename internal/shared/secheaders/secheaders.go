@@ -0,0 +1,211 @@
+// Package secheaders sets HTTP security headers (HSTS, CSP, framing,
+// sniffing and referrer policies) on every response, with overrides for
+// routes that need a different policy, such as embeddable widgets or HTML
+// pages that load scripts.
+package secheaders
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"golang-arch/internal/shared/config"
+)
+
+// Header names
+const (
+	HeaderHSTS                = "Strict-Transport-Security"
+	HeaderContentTypeOptions  = "X-Content-Type-Options"
+	HeaderFrameOptions        = "X-Frame-Options"
+	HeaderReferrerPolicy      = "Referrer-Policy"
+	HeaderCSP                 = "Content-Security-Policy"
+	HeaderCSPReportOnly       = "Content-Security-Policy-Report-Only"
+	HeaderCrossOriginOpener   = "Cross-Origin-Opener-Policy"
+	HeaderCrossOriginResource = "Cross-Origin-Resource-Policy"
+	HeaderPermissionsPolicy   = "Permissions-Policy"
+)
+
+// DefaultContentSecurityPolicy forbids loading, framing and submitting
+// anything, which suits JSON responses
+const DefaultContentSecurityPolicy = "default-src 'none'; frame-ancestors 'none'; base-uri 'none'; form-action 'none'"
+
+// contextKey stores the effective Policy in the gin context
+const contextKey = "secheaders.policy"
+
+// Policy is the set of security headers of a response. Empty fields omit
+// their header.
+type Policy struct {
+	HSTSMaxAge            time.Duration // Zero omits HSTS, which is only sent over HTTPS
+	HSTSIncludeSubdomains bool
+	HSTSPreload           bool
+	ContentTypeNosniff    bool
+	FrameOptions          string // DENY or SAMEORIGIN
+	ReferrerPolicy        string
+	ContentSecurityPolicy string
+	CSPReportOnly         bool // Report violations without enforcing the policy
+	CrossOriginOpener     string
+	CrossOriginResource   string
+	PermissionsPolicy     string
+}
+
+// DefaultPolicy suits a JSON API: nothing may be framed, loaded or
+// embedded, and HSTS lasts a year
+func DefaultPolicy() Policy {
+	return Policy{
+		HSTSMaxAge:            365 * 24 * time.Hour,
+		HSTSIncludeSubdomains: true,
+		ContentTypeNosniff:    true,
+		FrameOptions:          "DENY",
+		ReferrerPolicy:        "strict-origin-when-cross-origin",
+		ContentSecurityPolicy: DefaultContentSecurityPolicy,
+		CrossOriginOpener:     "same-origin",
+		CrossOriginResource:   "same-origin",
+	}
+}
+
+// Apply writes the policy's headers to h, removing those the policy omits.
+// secure reports whether the request arrived over HTTPS.
+func (p Policy) Apply(h http.Header, secure bool) {
+	set := func(name, value string) {
+		if value == "" {
+			h.Del(name)
+		} else {
+			h.Set(name, value)
+		}
+	}
+
+	hsts := ""
+	if secure && p.HSTSMaxAge > 0 {
+		hsts = "max-age=" + strconv.FormatInt(int64(p.HSTSMaxAge/time.Second), 10)
+		if p.HSTSIncludeSubdomains {
+			hsts += "; includeSubDomains"
+		}
+		if p.HSTSPreload {
+			hsts += "; preload"
+		}
+	}
+	set(HeaderHSTS, hsts)
+
+	nosniff := ""
+	if p.ContentTypeNosniff {
+		nosniff = "nosniff"
+	}
+	set(HeaderContentTypeOptions, nosniff)
+	set(HeaderFrameOptions, p.FrameOptions)
+	set(HeaderReferrerPolicy, p.ReferrerPolicy)
+
+	csp, cspReportOnly := p.ContentSecurityPolicy, ""
+	if p.CSPReportOnly {
+		csp, cspReportOnly = "", p.ContentSecurityPolicy
+	}
+	set(HeaderCSP, csp)
+	set(HeaderCSPReportOnly, cspReportOnly)
+	set(HeaderCrossOriginOpener, p.CrossOriginOpener)
+	set(HeaderCrossOriginResource, p.CrossOriginResource)
+	set(HeaderPermissionsPolicy, p.PermissionsPolicy)
+}
+
+// Route overrides the policy for paths starting with PathPrefix
+type Route struct {
+	PathPrefix string
+	Apply      func(*Policy)
+}
+
+// Middleware sets policy's headers before the handler runs. The most
+// specific matching route override is applied first; handlers and route
+// middleware can still adjust the policy with Override.
+func Middleware(policy Policy, routes ...Route) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		effective := policy
+		if route, ok := matchRoute(routes, c.Request.URL.Path); ok {
+			route.Apply(&effective)
+		}
+		c.Set(contextKey, effective)
+		effective.Apply(c.Writer.Header(), isSecure(c.Request))
+		c.Next()
+	}
+}
+
+// Override adjusts the policy for the routes it is attached to, e.g.
+//
+//	router.GET("/widget", secheaders.Override(func(p *secheaders.Policy) {
+//		p.FrameOptions = ""
+//		p.ContentSecurityPolicy = "frame-ancestors https://partner.example"
+//	}), widgetHandler)
+func Override(apply func(*Policy)) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		policy := DefaultPolicy()
+		if current, ok := c.Get(contextKey); ok {
+			policy = current.(Policy)
+		}
+		apply(&policy)
+		c.Set(contextKey, policy)
+		policy.Apply(c.Writer.Header(), isSecure(c.Request))
+		c.Next()
+	}
+}
+
+// PolicyFromConfig builds the base policy and route overrides from cfg
+func PolicyFromConfig(cfg config.SecurityHeadersConfig) (Policy, []Route) {
+	policy := Policy{
+		HSTSMaxAge:            cfg.HSTSMaxAge,
+		HSTSIncludeSubdomains: cfg.HSTSIncludeSubdomains,
+		HSTSPreload:           cfg.HSTSPreload,
+		ContentTypeNosniff:    cfg.ContentTypeNosniff,
+		FrameOptions:          cfg.FrameOptions,
+		ReferrerPolicy:        cfg.ReferrerPolicy,
+		ContentSecurityPolicy: cfg.ContentSecurityPolicy,
+		CSPReportOnly:         cfg.CSPReportOnly,
+		CrossOriginOpener:     cfg.CrossOriginOpenerPolicy,
+		CrossOriginResource:   cfg.CrossOriginResourcePolicy,
+		PermissionsPolicy:     cfg.PermissionsPolicy,
+	}
+
+	routes := make([]Route, 0, len(cfg.Routes))
+	for _, override := range cfg.Routes {
+		routes = append(routes, Route{
+			PathPrefix: override.PathPrefix,
+			Apply: func(p *Policy) {
+				if override.FrameOptions != nil {
+					p.FrameOptions = *override.FrameOptions
+				}
+				if override.ReferrerPolicy != nil {
+					p.ReferrerPolicy = *override.ReferrerPolicy
+				}
+				if override.ContentSecurityPolicy != nil {
+					p.ContentSecurityPolicy = *override.ContentSecurityPolicy
+				}
+				if override.CSPReportOnly != nil {
+					p.CSPReportOnly = *override.CSPReportOnly
+				}
+				if override.CrossOriginResourcePolicy != nil {
+					p.CrossOriginResource = *override.CrossOriginResourcePolicy
+				}
+			},
+		})
+	}
+	return policy, routes
+}
+
+// matchRoute returns the route with the longest prefix of path
+func matchRoute(routes []Route, path string) (Route, bool) {
+	var (
+		best  Route
+		found bool
+	)
+	for _, route := range routes {
+		if strings.HasPrefix(path, route.PathPrefix) && (!found || len(route.PathPrefix) > len(best.PathPrefix)) {
+			best, found = route, true
+		}
+	}
+	return best, found
+}
+
+// isSecure reports whether the request arrived over HTTPS, directly or
+// through a TLS-terminating proxy
+func isSecure(r *http.Request) bool {
+	return r.TLS != nil || strings.EqualFold(r.Header.Get("X-Forwarded-Proto"), "https")
+}
@@ -0,0 +1,276 @@
+package storage
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"golang-arch/internal/shared/api"
+	"golang-arch/internal/shared/domain/domainerror"
+	"golang-arch/pkg/clock"
+)
+
+// LocalStore keeps blobs as files under a root directory. Its presigned
+// URLs point at this API (Register mounts them under /blobs) and carry an
+// HMAC signature and expiry instead of credentials. The content type is
+// derived from the key's extension.
+type LocalStore struct {
+	root          string
+	publicURL     string
+	secret        []byte
+	maxUploadSize int64
+	clock         clock.Clock
+}
+
+// DefaultMaxUploadSize bounds the body of a presigned PUT unless
+// WithMaxUploadSize says otherwise
+const DefaultMaxUploadSize int64 = 100 << 20
+
+// LocalOption configures a LocalStore
+type LocalOption func(*LocalStore)
+
+// WithMaxUploadSize bounds the body of a presigned PUT to size bytes. Larger
+// uploads are refused with 413 and nothing is stored; size <= 0 keeps
+// DefaultMaxUploadSize.
+func WithMaxUploadSize(size int64) LocalOption {
+	return func(s *LocalStore) {
+		if size > 0 {
+			s.maxUploadSize = size
+		}
+	}
+}
+
+// NewLocalStore creates a store under root. Presigned URLs start with
+// publicURL and are signed with secret; without a secret Presign fails.
+func NewLocalStore(root, publicURL, secret string, clk clock.Clock, opts ...LocalOption) *LocalStore {
+	store := &LocalStore{
+		root:          root,
+		publicURL:     strings.TrimSuffix(publicURL, "/"),
+		secret:        []byte(secret),
+		maxUploadSize: DefaultMaxUploadSize,
+		clock:         clk,
+	}
+	for _, opt := range opts {
+		opt(store)
+	}
+	return store
+}
+
+// Name returns "local"
+func (s *LocalStore) Name() string {
+	return "local"
+}
+
+// Put writes body to a temporary file and renames it over key, so readers
+// never see a partial blob
+func (s *LocalStore) Put(ctx context.Context, key string, body io.Reader, opts PutOptions) (Info, error) {
+	if err := checkKey(key); err != nil {
+		return Info{}, err
+	}
+	target := s.path(key)
+	if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+		return Info{}, fmt.Errorf("failed to create directory for blob %s: %w", key, err)
+	}
+
+	file, err := os.CreateTemp(filepath.Dir(target), ".upload-*")
+	if err != nil {
+		return Info{}, fmt.Errorf("failed to create blob %s: %w", key, err)
+	}
+	defer os.Remove(file.Name())
+
+	_, err = io.Copy(file, contextReader{ctx, body})
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return Info{}, fmt.Errorf("failed to write blob %s: %w", key, err)
+	}
+	if err := os.Rename(file.Name(), target); err != nil {
+		return Info{}, fmt.Errorf("failed to store blob %s: %w", key, err)
+	}
+	return s.Stat(ctx, key)
+}
+
+// Get opens the file
+func (s *LocalStore) Get(ctx context.Context, key string) (io.ReadCloser, Info, error) {
+	if err := checkKey(key); err != nil {
+		return nil, Info{}, err
+	}
+	file, err := os.Open(s.path(key))
+	if err != nil {
+		return nil, Info{}, s.fileError(key, err)
+	}
+	stat, err := file.Stat()
+	if err == nil && stat.IsDir() {
+		err = fs.ErrNotExist
+	}
+	if err != nil {
+		file.Close()
+		return nil, Info{}, s.fileError(key, err)
+	}
+	return file, s.info(key, stat), nil
+}
+
+// Stat describes the file
+func (s *LocalStore) Stat(ctx context.Context, key string) (Info, error) {
+	if err := checkKey(key); err != nil {
+		return Info{}, err
+	}
+	stat, err := os.Stat(s.path(key))
+	if err == nil && stat.IsDir() {
+		err = fs.ErrNotExist
+	}
+	if err != nil {
+		return Info{}, s.fileError(key, err)
+	}
+	return s.info(key, stat), nil
+}
+
+// Delete removes the file
+func (s *LocalStore) Delete(ctx context.Context, key string) error {
+	if err := checkKey(key); err != nil {
+		return err
+	}
+	if err := os.Remove(s.path(key)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("failed to delete blob %s: %w", key, err)
+	}
+	return nil
+}
+
+// Presign returns publicURL/blobs/key?expires=...&signature=...
+func (s *LocalStore) Presign(ctx context.Context, method, key string, ttl time.Duration) (string, error) {
+	if err := checkPresign(method, key, ttl); err != nil {
+		return "", err
+	}
+	if len(s.secret) == 0 {
+		return "", errors.New("local storage needs storage.url_secret to presign URLs")
+	}
+	expires := strconv.FormatInt(s.clock.Now().Add(ttl).Unix(), 10)
+	query := url.Values{"expires": {expires}, "signature": {s.sign(method, key, expires)}}
+	return s.publicURL + "/blobs/" + escapePath(key) + "?" + query.Encode(), nil
+}
+
+// Register mounts the presigned URL endpoints: GET and HEAD download a
+// blob, PUT uploads one
+func (s *LocalStore) Register(group *gin.RouterGroup) {
+	group.GET("/blobs/*key", s.serve)
+	group.HEAD("/blobs/*key", s.serve)
+	group.PUT("/blobs/*key", s.upload)
+}
+
+func (s *LocalStore) serve(c *gin.Context) {
+	key, ok := s.verify(c, http.MethodGet)
+	if !ok {
+		return
+	}
+	file, info, err := s.Get(c.Request.Context(), key)
+	if err != nil {
+		api.RespondError(c, err)
+		return
+	}
+	defer file.Close()
+
+	c.Header("Content-Type", info.ContentType)
+	c.Header("Cache-Control", "private, no-store")
+	http.ServeContent(c.Writer, c.Request, path.Base(key), info.LastModified, file.(io.ReadSeeker))
+}
+
+func (s *LocalStore) upload(c *gin.Context) {
+	key, ok := s.verify(c, http.MethodPut)
+	if !ok {
+		return
+	}
+	tooLarge := api.NewAPIError(api.ErrCodePayloadTooLarge, fmt.Sprintf("blob exceeds %d bytes", s.maxUploadSize))
+	if c.Request.ContentLength > s.maxUploadSize {
+		api.RespondError(c, tooLarge)
+		return
+	}
+	body := http.MaxBytesReader(c.Writer, c.Request.Body, s.maxUploadSize)
+	info, err := s.Put(c.Request.Context(), key, body, PutOptions{})
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		err = tooLarge
+	}
+	if err != nil {
+		api.RespondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, info)
+}
+
+// verify checks the signature and expiry of a presigned request
+func (s *LocalStore) verify(c *gin.Context, method string) (string, bool) {
+	key := strings.TrimPrefix(c.Param("key"), "/")
+	expires := c.Query("expires")
+	signature, err := hex.DecodeString(c.Query("signature"))
+	if err != nil || len(s.secret) == 0 || checkKey(key) != nil {
+		api.RespondError(c, domainerror.Forbiddenf("invalid blob URL"))
+		return "", false
+	}
+	expected, _ := hex.DecodeString(s.sign(method, key, expires))
+	if !hmac.Equal(signature, expected) {
+		api.RespondError(c, domainerror.Forbiddenf("invalid blob URL"))
+		return "", false
+	}
+	if deadline, err := strconv.ParseInt(expires, 10, 64); err != nil || s.clock.Now().Unix() > deadline {
+		api.RespondError(c, domainerror.Forbiddenf("blob URL expired"))
+		return "", false
+	}
+	return key, true
+}
+
+// sign computes the signature of a presigned URL
+func (s *LocalStore) sign(method, key, expires string) string {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(method + "\n" + key + "\n" + expires))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// path maps a checked key to its file
+func (s *LocalStore) path(key string) string {
+	return filepath.Join(s.root, filepath.FromSlash(key))
+}
+
+func (s *LocalStore) info(key string, stat fs.FileInfo) Info {
+	contentType := mime.TypeByExtension(path.Ext(key))
+	if contentType == "" {
+		contentType = defaultContentType
+	}
+	return Info{Key: key, Size: stat.Size(), ContentType: contentType, LastModified: stat.ModTime().UTC()}
+}
+
+func (s *LocalStore) fileError(key string, err error) error {
+	if errors.Is(err, fs.ErrNotExist) {
+		return notFound(key)
+	}
+	return fmt.Errorf("failed to read blob %s: %w", key, err)
+}
+
+// contextReader stops a copy once ctx is done
+type contextReader struct {
+	ctx    context.Context
+	reader io.Reader
+}
+
+func (r contextReader) Read(p []byte) (int, error) {
+	if err := r.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return r.reader.Read(p)
+}
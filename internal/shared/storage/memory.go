@@ -0,0 +1,118 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/url"
+	"sync"
+	"time"
+
+	"golang-arch/pkg/clock"
+)
+
+// MemoryStore keeps blobs in memory, for tests and the dev profile. Its
+// presigned URLs use the memory:// scheme and are not served.
+type MemoryStore struct {
+	mu    sync.RWMutex
+	blobs map[string]memoryBlob
+	clock clock.Clock
+}
+
+type memoryBlob struct {
+	data []byte
+	info Info
+}
+
+// NewMemoryStore creates an empty store
+func NewMemoryStore(clk clock.Clock) *MemoryStore {
+	return &MemoryStore{blobs: make(map[string]memoryBlob), clock: clk}
+}
+
+// Name returns "memory"
+func (s *MemoryStore) Name() string {
+	return "memory"
+}
+
+// Put stores a copy of body
+func (s *MemoryStore) Put(ctx context.Context, key string, body io.Reader, opts PutOptions) (Info, error) {
+	if err := checkKey(key); err != nil {
+		return Info{}, err
+	}
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return Info{}, fmt.Errorf("failed to read blob %s: %w", key, err)
+	}
+
+	sum := md5.Sum(data)
+	info := Info{
+		Key:          key,
+		Size:         int64(len(data)),
+		ContentType:  opts.ContentType,
+		ETag:         hex.EncodeToString(sum[:]),
+		LastModified: s.clock.Now().UTC(),
+	}
+	if info.ContentType == "" {
+		info.ContentType = defaultContentType
+	}
+
+	s.mu.Lock()
+	s.blobs[key] = memoryBlob{data: data, info: info}
+	s.mu.Unlock()
+	return info, nil
+}
+
+// Get returns a reader over the stored bytes
+func (s *MemoryStore) Get(ctx context.Context, key string) (io.ReadCloser, Info, error) {
+	if err := checkKey(key); err != nil {
+		return nil, Info{}, err
+	}
+	s.mu.RLock()
+	blob, ok := s.blobs[key]
+	s.mu.RUnlock()
+	if !ok {
+		return nil, Info{}, notFound(key)
+	}
+	return io.NopCloser(bytes.NewReader(blob.data)), blob.info, nil
+}
+
+// Stat describes the blob
+func (s *MemoryStore) Stat(ctx context.Context, key string) (Info, error) {
+	if err := checkKey(key); err != nil {
+		return Info{}, err
+	}
+	s.mu.RLock()
+	blob, ok := s.blobs[key]
+	s.mu.RUnlock()
+	if !ok {
+		return Info{}, notFound(key)
+	}
+	return blob.info, nil
+}
+
+// Delete removes the blob
+func (s *MemoryStore) Delete(ctx context.Context, key string) error {
+	if err := checkKey(key); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	delete(s.blobs, key)
+	s.mu.Unlock()
+	return nil
+}
+
+// Presign returns memory://key?method=...&expires=..., which tests can
+// inspect but nothing serves
+func (s *MemoryStore) Presign(ctx context.Context, method, key string, ttl time.Duration) (string, error) {
+	if err := checkPresign(method, key, ttl); err != nil {
+		return "", err
+	}
+	query := url.Values{
+		"method":  {method},
+		"expires": {fmt.Sprint(s.clock.Now().Add(ttl).Unix())},
+	}
+	return "memory://" + escapePath(key) + "?" + query.Encode(), nil
+}
@@ -0,0 +1,398 @@
+package storage
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang-arch/internal/shared/domain/domainerror"
+	"golang-arch/pkg/clock"
+)
+
+// S3Options configures an S3Store
+type S3Options struct {
+	Endpoint  string // e.g. "https://minio.internal:9000"; empty for AWS in Region
+	Region    string // Signing region; defaults to us-east-1
+	Bucket    string
+	AccessKey string
+	SecretKey string
+	PathStyle bool // Address the bucket as endpoint/bucket instead of bucket.endpoint
+}
+
+// S3Store stores blobs in an S3 bucket or an S3-compatible service (MinIO,
+// Cloudflare R2, GCS interoperability), signing requests with AWS
+// Signature Version 4. Single PUTs limit blobs to 5 GiB.
+type S3Store struct {
+	name       string
+	endpoint   *url.URL
+	region     string
+	bucket     string
+	accessKey  string
+	secretKey  string
+	pathStyle  bool
+	clock      clock.Clock
+	httpClient *http.Client
+}
+
+// unsignedPayload skips hashing streamed bodies; TLS protects them instead
+const unsignedPayload = "UNSIGNED-PAYLOAD"
+
+// NewS3Store creates a store for the bucket described by opts
+func NewS3Store(opts S3Options, clk clock.Clock) *S3Store {
+	if opts.Region == "" {
+		opts.Region = "us-east-1"
+	}
+	if opts.Endpoint == "" {
+		opts.Endpoint = "https://s3." + opts.Region + ".amazonaws.com"
+	}
+	endpoint, err := url.Parse(strings.TrimSuffix(opts.Endpoint, "/"))
+	if err != nil || endpoint.Host == "" {
+		// Reported by the first request rather than at startup
+		endpoint = &url.URL{Scheme: "https", Host: "invalid-endpoint"}
+	}
+	return &S3Store{
+		name:      "s3",
+		endpoint:  endpoint,
+		region:    opts.Region,
+		bucket:    opts.Bucket,
+		accessKey: opts.AccessKey,
+		secretKey: opts.SecretKey,
+		pathStyle: opts.PathStyle,
+		clock:     clk,
+		// No client timeout: bodies may be large, callers bound requests with ctx
+		httpClient: &http.Client{},
+	}
+}
+
+// NewGCSStore creates a store for a Google Cloud Storage bucket through its
+// S3-compatible XML API, authenticated with an HMAC key of a service account
+func NewGCSStore(bucket, accessKey, secretKey string, clk clock.Clock) *S3Store {
+	store := NewS3Store(S3Options{
+		Endpoint:  "https://storage.googleapis.com",
+		Region:    "auto",
+		Bucket:    bucket,
+		AccessKey: accessKey,
+		SecretKey: secretKey,
+		PathStyle: true,
+	}, clk)
+	store.name = "gcs"
+	return store
+}
+
+// Name returns "s3" or "gcs"
+func (s *S3Store) Name() string {
+	return s.name
+}
+
+// Put uploads body in a single request. S3 needs the length up front, so a
+// body of unknown length is spooled to a temporary file first.
+func (s *S3Store) Put(ctx context.Context, key string, body io.Reader, opts PutOptions) (Info, error) {
+	if err := checkKey(key); err != nil {
+		return Info{}, err
+	}
+	body, size, cleanup, err := sizedBody(body)
+	if err != nil {
+		return Info{}, fmt.Errorf("failed to buffer blob %s: %w", key, err)
+	}
+	defer cleanup()
+
+	// The transport closes request bodies; the caller's reader stays open
+	request, err := s.newRequest(ctx, http.MethodPut, key, io.NopCloser(body))
+	if err != nil {
+		return Info{}, err
+	}
+	request.ContentLength = size
+	if size == 0 {
+		request.Body = http.NoBody
+	}
+	contentType := opts.ContentType
+	if contentType == "" {
+		contentType = defaultContentType
+	}
+	request.Header.Set("Content-Type", contentType)
+	if opts.ContentDisposition != "" {
+		request.Header.Set("Content-Disposition", opts.ContentDisposition)
+	}
+
+	response, err := s.do(request, key)
+	if err != nil {
+		return Info{}, err
+	}
+	response.Body.Close()
+	return Info{
+		Key:          key,
+		Size:         size,
+		ContentType:  contentType,
+		ETag:         strings.Trim(response.Header.Get("ETag"), `"`),
+		LastModified: s.clock.Now().UTC(),
+	}, nil
+}
+
+// Get streams the blob
+func (s *S3Store) Get(ctx context.Context, key string) (io.ReadCloser, Info, error) {
+	if err := checkKey(key); err != nil {
+		return nil, Info{}, err
+	}
+	request, err := s.newRequest(ctx, http.MethodGet, key, nil)
+	if err != nil {
+		return nil, Info{}, err
+	}
+	response, err := s.do(request, key)
+	if err != nil {
+		return nil, Info{}, err
+	}
+	return response.Body, infoFromHeader(key, response.Header), nil
+}
+
+// Stat sends a HEAD request
+func (s *S3Store) Stat(ctx context.Context, key string) (Info, error) {
+	if err := checkKey(key); err != nil {
+		return Info{}, err
+	}
+	request, err := s.newRequest(ctx, http.MethodHead, key, nil)
+	if err != nil {
+		return Info{}, err
+	}
+	response, err := s.do(request, key)
+	if err != nil {
+		return Info{}, err
+	}
+	response.Body.Close()
+	return infoFromHeader(key, response.Header), nil
+}
+
+// Delete removes the blob; S3 answers 204 whether or not it existed
+func (s *S3Store) Delete(ctx context.Context, key string) error {
+	if err := checkKey(key); err != nil {
+		return err
+	}
+	request, err := s.newRequest(ctx, http.MethodDelete, key, nil)
+	if err != nil {
+		return err
+	}
+	response, err := s.do(request, key)
+	if err != nil {
+		if errors.Is(err, domainerror.NotFound) {
+			return nil
+		}
+		return err
+	}
+	response.Body.Close()
+	return nil
+}
+
+// Presign returns a query-signed URL. Clients uploading with a presigned
+// PUT may send any Content-Type, since only the host header is signed.
+func (s *S3Store) Presign(ctx context.Context, method, key string, ttl time.Duration) (string, error) {
+	if err := checkPresign(method, key, ttl); err != nil {
+		return "", err
+	}
+	target := s.objectURL(key)
+	now := s.clock.Now().UTC()
+	date := now.Format("20060102")
+
+	query := url.Values{
+		"X-Amz-Algorithm":     {"AWS4-HMAC-SHA256"},
+		"X-Amz-Credential":    {s.accessKey + "/" + s.scope(date)},
+		"X-Amz-Date":          {now.Format("20060102T150405Z")},
+		"X-Amz-Expires":       {strconv.Itoa(int(ttl.Seconds()))},
+		"X-Amz-SignedHeaders": {"host"},
+	}
+	header := http.Header{"Host": {target.Host}}
+	canonical := canonicalRequest(method, target.EscapedPath(), canonicalQuery(query), header, []string{"host"}, unsignedPayload)
+	query.Set("X-Amz-Signature", s.signature(now, canonical))
+
+	target.RawQuery = canonicalQuery(query)
+	return target.String(), nil
+}
+
+// newRequest builds a signed request for the object key
+func (s *S3Store) newRequest(ctx context.Context, method, key string, body io.Reader) (*http.Request, error) {
+	request, err := http.NewRequestWithContext(ctx, method, s.objectURL(key).String(), body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build %s request for blob %s: %w", s.name, key, err)
+	}
+	now := s.clock.Now().UTC()
+	request.Header.Set("X-Amz-Date", now.Format("20060102T150405Z"))
+	request.Header.Set("X-Amz-Content-Sha256", unsignedPayload)
+
+	// Headers Put adds later, such as Content-Type, are deliberately unsigned
+	signed := []string{"host", "x-amz-content-sha256", "x-amz-date"}
+	signing := request.Header.Clone()
+	signing.Set("Host", request.URL.Host)
+	canonical := canonicalRequest(method, request.URL.EscapedPath(), "", signing, signed, unsignedPayload)
+	request.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s.accessKey, s.scope(now.Format("20060102")), strings.Join(signed, ";"), s.signature(now, canonical)))
+	return request, nil
+}
+
+// do sends request and turns error statuses into errors
+func (s *S3Store) do(request *http.Request, key string) (*http.Response, error) {
+	response, err := s.httpClient.Do(request)
+	if err != nil {
+		return nil, fmt.Errorf("%s %s %s: %w", s.name, request.Method, key, err)
+	}
+	if response.StatusCode < 300 {
+		return response, nil
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode == http.StatusNotFound {
+		return nil, notFound(key)
+	}
+	var failure struct {
+		Code    string `xml:"Code"`
+		Message string `xml:"Message"`
+	}
+	_ = xml.NewDecoder(io.LimitReader(response.Body, 64<<10)).Decode(&failure)
+	if failure.Code != "" {
+		return nil, fmt.Errorf("%s %s %s: status %d: %s: %s", s.name, request.Method, key, response.StatusCode, failure.Code, failure.Message)
+	}
+	return nil, fmt.Errorf("%s %s %s: status %d", s.name, request.Method, key, response.StatusCode)
+}
+
+// objectURL addresses key path-style or virtual-hosted-style
+func (s *S3Store) objectURL(key string) *url.URL {
+	target := *s.endpoint
+	prefix := strings.TrimSuffix(s.endpoint.Path, "/")
+	if s.pathStyle {
+		prefix += "/" + s.bucket
+	} else {
+		target.Host = s.bucket + "." + s.endpoint.Host
+	}
+	target.Path = prefix + "/" + key
+	target.RawPath = escapePath(prefix) + "/" + escapePath(key)
+	return &target
+}
+
+// scope is the credential scope of date
+func (s *S3Store) scope(date string) string {
+	return date + "/" + s.region + "/s3/aws4_request"
+}
+
+// signature signs a canonical request made at now
+func (s *S3Store) signature(now time.Time, canonical string) string {
+	date := now.Format("20060102")
+	hash := sha256.Sum256([]byte(canonical))
+	stringToSign := "AWS4-HMAC-SHA256\n" + now.Format("20060102T150405Z") + "\n" + s.scope(date) + "\n" + hex.EncodeToString(hash[:])
+
+	signingKey := hmacSHA256([]byte("AWS4"+s.secretKey), date)
+	for _, part := range []string{s.region, "s3", "aws4_request"} {
+		signingKey = hmacSHA256(signingKey, part)
+	}
+	return hex.EncodeToString(hmacSHA256(signingKey, stringToSign))
+}
+
+// canonicalRequest builds the SigV4 canonical request; signed lists the
+// lowercase header names in sorted order
+func canonicalRequest(method, path, query string, header http.Header, signed []string, payloadHash string) string {
+	var headers strings.Builder
+	for _, name := range signed {
+		headers.WriteString(name + ":" + strings.TrimSpace(header.Get(name)) + "\n")
+	}
+	return strings.Join([]string{method, path, query, headers.String(), strings.Join(signed, ";"), payloadHash}, "\n")
+}
+
+// canonicalQuery sorts and strictly encodes query parameters
+func canonicalQuery(query url.Values) string {
+	keys := make([]string, 0, len(query))
+	for key := range query {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	parts := make([]string, 0, len(keys))
+	for _, key := range keys {
+		for _, value := range query[key] {
+			parts = append(parts, uriEncode(key, true)+"="+uriEncode(value, true))
+		}
+	}
+	return strings.Join(parts, "&")
+}
+
+// escapePath encodes a key for a URL path, keeping the slashes
+func escapePath(key string) string {
+	return uriEncode(key, false)
+}
+
+// uriEncode percent-encodes everything but unreserved characters, as SigV4
+// requires; slashes are kept unless encodeSlash is set
+func uriEncode(value string, encodeSlash bool) string {
+	var out strings.Builder
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+		switch {
+		case 'A' <= c && c <= 'Z', 'a' <= c && c <= 'z', '0' <= c && c <= '9', c == '-', c == '_', c == '.', c == '~':
+			out.WriteByte(c)
+		case c == '/' && !encodeSlash:
+			out.WriteByte(c)
+		default:
+			fmt.Fprintf(&out, "%%%02X", c)
+		}
+	}
+	return out.String()
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+// infoFromHeader reads the blob metadata of a GET or HEAD response
+func infoFromHeader(key string, header http.Header) Info {
+	info := Info{
+		Key:         key,
+		ContentType: header.Get("Content-Type"),
+		ETag:        strings.Trim(header.Get("ETag"), `"`),
+	}
+	info.Size, _ = strconv.ParseInt(header.Get("Content-Length"), 10, 64)
+	info.LastModified, _ = http.ParseTime(header.Get("Last-Modified"))
+	return info
+}
+
+// sizedBody returns body with its length, spooling it to a temporary file
+// when the length cannot be known otherwise
+func sizedBody(body io.Reader) (io.Reader, int64, func(), error) {
+	noop := func() {}
+	switch b := body.(type) {
+	case interface{ Len() int }:
+		return body, int64(b.Len()), noop, nil
+	case *os.File:
+		if info, err := b.Stat(); err == nil && info.Mode().IsRegular() {
+			offset, err := b.Seek(0, io.SeekCurrent)
+			if err == nil {
+				return body, info.Size() - offset, noop, nil
+			}
+		}
+	}
+
+	spool, err := os.CreateTemp("", "blob-*")
+	if err != nil {
+		return nil, 0, noop, err
+	}
+	cleanup := func() {
+		spool.Close()
+		os.Remove(spool.Name())
+	}
+	size, err := io.Copy(spool, body)
+	if err == nil {
+		_, err = spool.Seek(0, io.SeekStart)
+	}
+	if err != nil {
+		cleanup()
+		return nil, 0, noop, err
+	}
+	return spool, size, cleanup, nil
+}
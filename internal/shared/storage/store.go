@@ -0,0 +1,122 @@
+// Package storage stores blobs such as report exports and invoice PDFs in
+// S3, Google Cloud Storage or the local filesystem behind one interface,
+// and hands out presigned URLs so clients download them directly.
+package storage
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"golang-arch/internal/shared/config"
+	"golang-arch/internal/shared/domain/domainerror"
+	"golang-arch/pkg/clock"
+)
+
+// BlobStore reads and writes blobs by key. Keys are slash-separated paths
+// such as "exports/2024/06/report.csv".
+type BlobStore interface {
+	// Name identifies the backend, e.g. "s3"
+	Name() string
+	// Put streams body to key, replacing any existing blob
+	Put(ctx context.Context, key string, body io.Reader, opts PutOptions) (Info, error)
+	// Get opens the blob; the caller closes the reader. A missing blob is a
+	// NotFound error.
+	Get(ctx context.Context, key string) (io.ReadCloser, Info, error)
+	// Stat describes the blob without reading it
+	Stat(ctx context.Context, key string) (Info, error)
+	// Delete removes the blob; deleting a missing blob is not an error
+	Delete(ctx context.Context, key string) error
+	// Presign returns a URL allowing method (GET or PUT) on key without
+	// credentials until ttl has passed
+	Presign(ctx context.Context, method, key string, ttl time.Duration) (string, error)
+}
+
+// Info describes a stored blob
+type Info struct {
+	Key          string    `json:"key"`
+	Size         int64     `json:"size"`
+	ContentType  string    `json:"content_type,omitempty"`
+	ETag         string    `json:"etag,omitempty"`
+	LastModified time.Time `json:"last_modified,omitempty"`
+}
+
+// PutOptions describes the blob being written
+type PutOptions struct {
+	ContentType        string // Defaults to application/octet-stream
+	ContentDisposition string // e.g. `attachment; filename="invoice.pdf"`
+}
+
+// defaultContentType is used when a blob's type is unknown
+const defaultContentType = "application/octet-stream"
+
+// maxPresignTTL is the longest validity SigV4 allows for a presigned URL
+const maxPresignTTL = 7 * 24 * time.Hour
+
+// NewBlobStore creates the store selected by cfg.Provider. It does not
+// contact the backend.
+func NewBlobStore(cfg config.StorageConfig, clk clock.Clock) (BlobStore, error) {
+	switch cfg.Provider {
+	case "", "local":
+		return NewLocalStore(cfg.LocalDir, cfg.PublicURL, cfg.URLSecret, clk, WithMaxUploadSize(cfg.MaxUploadSize)), nil
+	case "memory":
+		return NewMemoryStore(clk), nil
+	case "s3":
+		if cfg.Bucket == "" || cfg.AccessKey == "" || cfg.SecretKey == "" {
+			return nil, fmt.Errorf("storage provider s3 requires bucket, access_key and secret_key")
+		}
+		if cfg.Endpoint != "" {
+			if endpoint, err := url.Parse(cfg.Endpoint); err != nil || endpoint.Host == "" {
+				return nil, fmt.Errorf("invalid storage endpoint %q", cfg.Endpoint)
+			}
+		}
+		return NewS3Store(S3Options{
+			Endpoint:  cfg.Endpoint,
+			Region:    cfg.Region,
+			Bucket:    cfg.Bucket,
+			AccessKey: cfg.AccessKey,
+			SecretKey: cfg.SecretKey,
+			PathStyle: cfg.PathStyle,
+		}, clk), nil
+	case "gcs":
+		if cfg.Bucket == "" || cfg.AccessKey == "" || cfg.SecretKey == "" {
+			return nil, fmt.Errorf("storage provider gcs requires bucket, access_key and secret_key (an HMAC key)")
+		}
+		return NewGCSStore(cfg.Bucket, cfg.AccessKey, cfg.SecretKey, clk), nil
+	default:
+		return nil, fmt.Errorf("unknown storage provider %q", cfg.Provider)
+	}
+}
+
+// checkKey rejects keys that are empty or could escape a directory
+func checkKey(key string) error {
+	if key == "" || strings.HasPrefix(key, "/") || strings.Contains(key, "\\") {
+		return domainerror.Invalidf("invalid blob key %q", key)
+	}
+	for _, segment := range strings.Split(key, "/") {
+		if segment == "" || segment == "." || segment == ".." {
+			return domainerror.Invalidf("invalid blob key %q", key)
+		}
+	}
+	return nil
+}
+
+// checkPresign validates the arguments of Presign
+func checkPresign(method, key string, ttl time.Duration) error {
+	if method != http.MethodGet && method != http.MethodPut {
+		return domainerror.Invalidf("cannot presign %s, only GET and PUT", method)
+	}
+	if ttl <= 0 || ttl > maxPresignTTL {
+		return domainerror.Invalidf("presigned URL lifetime must be between 0 and %s", maxPresignTTL)
+	}
+	return checkKey(key)
+}
+
+// notFound is the error of a missing blob
+func notFound(key string) error {
+	return domainerror.NotFoundf("blob %s not found", key)
+}
@@ -0,0 +1,107 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"path"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"golang-arch/internal/shared/api"
+)
+
+// Download copies the blob to w and returns the number of bytes written
+func Download(ctx context.Context, store BlobStore, key string, w io.Writer) (int64, error) {
+	body, _, err := store.Get(ctx, key)
+	if err != nil {
+		return 0, err
+	}
+	defer body.Close()
+
+	written, err := io.Copy(w, body)
+	if err != nil {
+		return written, fmt.Errorf("failed to download blob %s: %w", key, err)
+	}
+	return written, nil
+}
+
+// Writer uploads what is written to it, so a report can be encoded straight
+// into storage without holding it in memory. Close completes the upload and
+// returns its error.
+type Writer struct {
+	pipe *io.PipeWriter
+	done chan struct{}
+	info Info
+	err  error
+}
+
+// NewWriter starts an upload of key that is fed by the returned writer
+func NewWriter(ctx context.Context, store BlobStore, key string, opts PutOptions) *Writer {
+	reader, pipe := io.Pipe()
+	w := &Writer{pipe: pipe, done: make(chan struct{})}
+	go func() {
+		defer close(w.done)
+		w.info, w.err = store.Put(ctx, key, reader, opts)
+		// Unblock Write when Put failed before reading everything
+		reader.CloseWithError(fmt.Errorf("upload of blob %s ended", key))
+	}()
+	return w
+}
+
+// Write implements io.Writer
+func (w *Writer) Write(p []byte) (int, error) {
+	n, err := w.pipe.Write(p)
+	if err != nil {
+		<-w.done
+		if w.err != nil {
+			return n, w.err
+		}
+	}
+	return n, err
+}
+
+// Close finishes the upload and reports the stored blob
+func (w *Writer) Close() error {
+	w.pipe.Close()
+	<-w.done
+	return w.err
+}
+
+// Abort cancels the upload; nothing is stored under the key unless the
+// backend had already committed it
+func (w *Writer) Abort(err error) error {
+	w.pipe.CloseWithError(err)
+	<-w.done
+	return err
+}
+
+// Info describes the stored blob once Close returned nil
+func (w *Writer) Info() Info {
+	return w.info
+}
+
+// Serve streams the blob as a download named filename; an empty filename
+// uses the last element of the key
+func Serve(c *gin.Context, store BlobStore, key, filename string) {
+	body, info, err := store.Get(c.Request.Context(), key)
+	if err != nil {
+		api.RespondError(c, err)
+		return
+	}
+	defer body.Close()
+
+	if filename == "" {
+		filename = path.Base(key)
+	}
+	c.Header("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": filename}))
+	c.Header("Content-Type", info.ContentType)
+	if info.Size > 0 {
+		c.Header("Content-Length", strconv.FormatInt(info.Size, 10))
+	}
+	c.Status(http.StatusOK)
+	_, _ = io.Copy(c.Writer, body)
+}
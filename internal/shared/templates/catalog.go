@@ -0,0 +1,60 @@
+package templates
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"maps"
+
+	i18n "golang-arch/internal/shared/domain/internationalization"
+)
+
+// Catalog holds the translated labels of templates by key, e.g.
+// "invoice.total". Labels may contain {name} placeholders.
+type Catalog map[string]i18n.LocalizedString
+
+// fallbackLocale is used when a label has no translation for the reader
+var fallbackLocale = i18n.MustParseLocale("en")
+
+// Merge returns a catalog with the labels of other replacing those of c
+func (c Catalog) Merge(other Catalog) Catalog {
+	merged := maps.Clone(c)
+	if merged == nil {
+		merged = make(Catalog, len(other))
+	}
+	maps.Copy(merged, other)
+	return merged
+}
+
+// ParseCatalog reads a catalog from JSON:
+//
+//	{"email.greeting": {"en": "Hello {name}", "de": "Hallo {name}"}}
+func ParseCatalog(data []byte) (Catalog, error) {
+	var catalog Catalog
+	if err := json.Unmarshal(data, &catalog); err != nil {
+		return nil, fmt.Errorf("invalid catalog: %w", err)
+	}
+	return catalog, nil
+}
+
+// LoadCatalogs merges the catalogs in the files matching pattern in fsys,
+// in lexical order
+func LoadCatalogs(fsys fs.FS, pattern string) (Catalog, error) {
+	paths, err := fs.Glob(fsys, pattern)
+	if err != nil {
+		return nil, err
+	}
+	catalog := Catalog{}
+	for _, path := range paths {
+		data, err := fs.ReadFile(fsys, path)
+		if err != nil {
+			return nil, err
+		}
+		parsed, err := ParseCatalog(data)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		catalog = catalog.Merge(parsed)
+	}
+	return catalog, nil
+}
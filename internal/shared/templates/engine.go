@@ -0,0 +1,395 @@
+// Package templates renders server-side HTML pages and emails. Templates
+// format money, dates, numbers and phone numbers for the reader and
+// translate labels through a Catalog.
+//
+// A template source is a file system laid out as:
+//
+//	layouts/*.html   wrap pages: {{ block "content" . }}{{ end }}
+//	partials/*.html  shared snippets: {{ template "partials/alert.html" . }}
+//	pages/*.html     admin pages: {{ template "layouts/page.html" . }}{{ define "content" }}...{{ end }}
+//	emails/*.html    HTML bodies of emails, laid out like pages
+//	emails/*.txt     plain-text bodies; layouts/*.txt and partials/*.txt are shared
+//	locales/*.json   catalogs merged in lexical order
+//	assets/*         static files served under /assets
+//
+// Later sources replace files of earlier ones with the same path; the
+// built-in source comes first.
+package templates
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"embed"
+	"encoding/hex"
+	"fmt"
+	"html"
+	htmltemplate "html/template"
+	"io"
+	"io/fs"
+	"net/http"
+	"path"
+	"sort"
+	"strings"
+	"sync/atomic"
+	texttemplate "text/template"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"golang-arch/internal/shared/api"
+	i18n "golang-arch/internal/shared/domain/internationalization"
+	"golang-arch/internal/shared/geo"
+	"golang-arch/internal/shared/metering"
+)
+
+//go:embed all:builtin
+var builtin embed.FS
+
+// Builtin holds the built-in layouts, partials and assets
+var Builtin fs.FS = mustSub(builtin, "builtin")
+
+// assetPrefix is where Register serves assets
+const assetPrefix = "/assets/"
+
+// Engine renders pages and emails from parsed templates. It is safe for
+// concurrent use.
+type Engine struct {
+	sources  []fs.FS
+	files    Catalog                 // Labels of locales/*.json
+	catalog  atomic.Pointer[Catalog] // files with the WithCatalog or UseCatalog labels
+	extra    Catalog
+	defaults i18n.LocaleDefaults
+
+	html     map[string]*htmltemplate.Template // By path, e.g. "pages/users.html"
+	text     map[string]*texttemplate.Template // By path, e.g. "emails/welcome.txt"
+	assets   map[string][]byte
+	versions map[string]string // Content hash of each asset, for cache busting
+}
+
+// Email is a rendered email
+type Email struct {
+	Subject string
+	HTML    string
+	Text    string // Empty when the email has no .txt template
+}
+
+// Option configures an Engine
+type Option func(*Engine)
+
+// WithFS adds a template source on top of the previous ones
+func WithFS(fsys fs.FS) Option {
+	return func(e *Engine) {
+		e.sources = append(e.sources, fsys)
+	}
+}
+
+// WithCatalog adds labels, replacing those loaded from locales/*.json
+func WithCatalog(catalog Catalog) Option {
+	return func(e *Engine) {
+		e.extra = e.extra.Merge(catalog)
+	}
+}
+
+// WithDefaults sets the preferences of readers nothing is known about
+func WithDefaults(prefs i18n.LocalePreferences) Option {
+	return func(e *Engine) {
+		e.defaults.Preferences = prefs
+	}
+}
+
+// WithLocaleDefaults sets the preferences of readers nothing is known about
+// and narrows every reader's locale to the supported ones
+func WithLocaleDefaults(defaults i18n.LocaleDefaults) Option {
+	return func(e *Engine) {
+		e.defaults = defaults
+	}
+}
+
+// DefaultPreferences are English, UTC and US dollars
+func DefaultPreferences() i18n.LocalePreferences {
+	prefs, err := i18n.NewLocalePreferencesFromPrimitive("en", "UTC", "USD", string(i18n.MeasurementMetric), 1)
+	if err != nil {
+		panic(err)
+	}
+	return *prefs
+}
+
+// NewEngine parses the templates of the built-in source and those added
+// with WithFS
+func NewEngine(options ...Option) (*Engine, error) {
+	e := &Engine{sources: []fs.FS{Builtin}, defaults: i18n.LocaleDefaults{Preferences: DefaultPreferences()}}
+	for _, option := range options {
+		option(e)
+	}
+
+	files, err := collect(e.sources)
+	if err != nil {
+		return nil, err
+	}
+	if err := e.load(files); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+// collect reads the files of all sources, later ones replacing earlier ones
+func collect(sources []fs.FS) (map[string][]byte, error) {
+	files := make(map[string][]byte)
+	for _, source := range sources {
+		err := fs.WalkDir(source, ".", func(name string, entry fs.DirEntry, err error) error {
+			if err != nil || entry.IsDir() {
+				return err
+			}
+			data, err := fs.ReadFile(source, name)
+			if err != nil {
+				return err
+			}
+			files[name] = data
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to read templates: %w", err)
+		}
+	}
+	return files, nil
+}
+
+func (e *Engine) load(files map[string][]byte) error {
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	// Functions are bound to the reader's formatter when executing
+	funcs := e.funcs(NewFormatter(e.defaults.Preferences, nil))
+	htmlBase := htmltemplate.New("").Funcs(funcs)
+	textBase := texttemplate.New("").Funcs(funcs)
+	e.files = Catalog{}
+	e.assets = make(map[string][]byte)
+	e.versions = make(map[string]string)
+
+	for _, name := range names {
+		dir, ext := path.Dir(name), path.Ext(name)
+		switch {
+		case dir == "assets" || strings.HasPrefix(dir, "assets/"):
+			asset := strings.TrimPrefix(name, "assets/")
+			sum := sha256.Sum256(files[name])
+			e.assets[asset] = files[name]
+			e.versions[asset] = hex.EncodeToString(sum[:4])
+		case dir == "locales" && ext == ".json":
+			catalog, err := ParseCatalog(files[name])
+			if err != nil {
+				return fmt.Errorf("%s: %w", name, err)
+			}
+			e.files = e.files.Merge(catalog)
+		case (dir == "layouts" || dir == "partials") && ext == ".html":
+			if _, err := htmlBase.New(name).Parse(string(files[name])); err != nil {
+				return fmt.Errorf("failed to parse template: %w", err)
+			}
+		case (dir == "layouts" || dir == "partials") && ext == ".txt":
+			if _, err := textBase.New(name).Parse(string(files[name])); err != nil {
+				return fmt.Errorf("failed to parse template: %w", err)
+			}
+		}
+	}
+	e.UseCatalog(e.extra)
+
+	e.html = make(map[string]*htmltemplate.Template)
+	e.text = make(map[string]*texttemplate.Template)
+	for _, name := range names {
+		dir, ext := path.Dir(name), path.Ext(name)
+		if dir != "pages" && dir != "emails" {
+			continue
+		}
+		switch ext {
+		case ".html":
+			page, err := htmlBase.Clone()
+			if err == nil {
+				_, err = page.New(name).Parse(string(files[name]))
+			}
+			if err != nil {
+				return fmt.Errorf("failed to parse template: %w", err)
+			}
+			e.html[name] = page
+		case ".txt":
+			page, err := textBase.Clone()
+			if err == nil {
+				_, err = page.New(name).Parse(string(files[name]))
+			}
+			if err != nil {
+				return fmt.Errorf("failed to parse template: %w", err)
+			}
+			e.text[name] = page
+		}
+	}
+	return nil
+}
+
+// UseCatalog replaces the labels added with WithCatalog, e.g. when
+// operators change translations at runtime; renders in progress finish with
+// the previous labels
+func (e *Engine) UseCatalog(catalog Catalog) {
+	merged := e.files.Merge(catalog)
+	e.catalog.Store(&merged)
+}
+
+// Formatter returns the formatter templates use for prefs
+func (e *Engine) Formatter(prefs i18n.LocalePreferences) *Formatter {
+	return NewFormatter(prefs, *e.catalog.Load())
+}
+
+// funcs adds the asset function to the formatter's:
+//
+//	<link rel="stylesheet" href="{{ asset "base.css" }}">
+func (e *Engine) funcs(f *Formatter) map[string]any {
+	funcs := f.FuncMap()
+	funcs["asset"] = e.AssetURL
+	return funcs
+}
+
+// AssetURL returns the URL of an asset, versioned by its content so it can
+// be cached for good
+func (e *Engine) AssetURL(name string) (string, error) {
+	version, ok := e.versions[name]
+	if !ok {
+		return "", fmt.Errorf("unknown asset %q", name)
+	}
+	return assetPrefix + name + "?v=" + version, nil
+}
+
+// Render executes the HTML template at path, e.g. "pages/users.html", for
+// the reader described by prefs
+func (e *Engine) Render(w io.Writer, name string, prefs i18n.LocalePreferences, data any) error {
+	page, ok := e.html[name]
+	if !ok {
+		return fmt.Errorf("unknown template %q", name)
+	}
+	if err := e.executeHTML(w, page, name, prefs, data); err != nil {
+		return fmt.Errorf("failed to render %s: %w", name, err)
+	}
+	return nil
+}
+
+// RenderText executes the text template at path, e.g. "emails/welcome.txt"
+func (e *Engine) RenderText(w io.Writer, name string, prefs i18n.LocalePreferences, data any) error {
+	page, ok := e.text[name]
+	if !ok {
+		return fmt.Errorf("unknown template %q", name)
+	}
+	if err := e.executeText(w, page, name, prefs, data); err != nil {
+		return fmt.Errorf("failed to render %s: %w", name, err)
+	}
+	return nil
+}
+
+// executeHTML runs the named template of a clone of page bound to the
+// reader's formatter
+func (e *Engine) executeHTML(w io.Writer, page *htmltemplate.Template, name string, prefs i18n.LocalePreferences, data any) error {
+	page, err := page.Clone()
+	if err != nil {
+		return err
+	}
+	return page.Funcs(e.funcs(e.Formatter(prefs))).ExecuteTemplate(w, name, data)
+}
+
+// executeText is executeHTML for text templates
+func (e *Engine) executeText(w io.Writer, page *texttemplate.Template, name string, prefs i18n.LocalePreferences, data any) error {
+	page, err := page.Clone()
+	if err != nil {
+		return err
+	}
+	return page.Funcs(e.funcs(e.Formatter(prefs))).ExecuteTemplate(w, name, data)
+}
+
+// RenderEmail renders emails/<name>.html and, when it exists,
+// emails/<name>.txt. The subject is the "subject" template of the text
+// body, or of the HTML body when the text one has none.
+func (e *Engine) RenderEmail(name string, prefs i18n.LocalePreferences, data any) (Email, error) {
+	htmlName, textName := "emails/"+name+".html", "emails/"+name+".txt"
+	var email Email
+
+	var body bytes.Buffer
+	if err := e.Render(&body, htmlName, prefs, data); err != nil {
+		return Email{}, err
+	}
+	email.HTML = body.String()
+
+	var subject strings.Builder
+	var err error
+	if page, ok := e.text[textName]; ok {
+		body.Reset()
+		if err := e.RenderText(&body, textName, prefs, data); err != nil {
+			return Email{}, err
+		}
+		// Drop the blank lines {{ define }} blocks leave around the body
+		email.Text = strings.TrimSpace(body.String()) + "\n"
+		if page.Lookup("subject") != nil {
+			err = e.executeText(&subject, page, "subject", prefs, data)
+			email.Subject = strings.TrimSpace(subject.String())
+		}
+	}
+	if email.Subject == "" && err == nil {
+		if e.html[htmlName].Lookup("subject") == nil {
+			return Email{}, fmt.Errorf("email %s defines no subject", name)
+		}
+		err = e.executeHTML(&subject, e.html[htmlName], "subject", prefs, data)
+		email.Subject = strings.TrimSpace(html.UnescapeString(subject.String()))
+	}
+	if err != nil {
+		return Email{}, fmt.Errorf("failed to render subject of email %s: %w", name, err)
+	}
+	return email, nil
+}
+
+// Preferences resolves the reader of a request from sources, such as the
+// signed-in user's saved preferences, then the location geo.Middleware
+// detected, then the defaults of the request's tenant (see
+// metering.SetConsumer) and the engine's. The locale is narrowed to the
+// supported ones.
+func (e *Engine) Preferences(c *gin.Context, sources ...i18n.PartialLocalePreferences) i18n.LocalePreferences {
+	return geo.Preferences(c, e.defaults.ForTenant(metering.Tenant(c)), sources...)
+}
+
+// HTML renders the page for the request's reader and writes it with status.
+// Nothing is written before the page rendered, so a failure is answered
+// with an error response.
+func (e *Engine) HTML(c *gin.Context, status int, name string, data any, sources ...i18n.PartialLocalePreferences) {
+	var page bytes.Buffer
+	if err := e.Render(&page, name, e.Preferences(c, sources...), data); err != nil {
+		api.RespondError(c, err)
+		return
+	}
+	c.Data(status, "text/html; charset=utf-8", page.Bytes())
+}
+
+// Register serves the assets under /assets. Versioned URLs from AssetURL
+// are cached for a year.
+func (e *Engine) Register(group *gin.RouterGroup) {
+	group.GET(assetPrefix+"*name", e.serveAsset)
+	group.HEAD(assetPrefix+"*name", e.serveAsset)
+}
+
+func (e *Engine) serveAsset(c *gin.Context) {
+	name := strings.TrimPrefix(c.Param("name"), "/")
+	data, ok := e.assets[name]
+	if !ok {
+		c.Status(http.StatusNotFound)
+		return
+	}
+	if c.Query("v") == e.versions[name] {
+		c.Header("Cache-Control", "public, max-age=31536000, immutable")
+	} else {
+		c.Header("Cache-Control", "no-cache")
+	}
+	c.Header("ETag", `"`+e.versions[name]+`"`)
+	http.ServeContent(c.Writer, c.Request, path.Base(name), time.Time{}, bytes.NewReader(data))
+}
+
+func mustSub(fsys fs.FS, dir string) fs.FS {
+	sub, err := fs.Sub(fsys, dir)
+	if err != nil {
+		panic(fmt.Sprintf("templates: missing built-in directory: %v", err))
+	}
+	return sub
+}
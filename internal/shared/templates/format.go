@@ -0,0 +1,204 @@
+package templates
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+	"golang.org/x/text/number"
+
+	i18n "golang-arch/internal/shared/domain/internationalization"
+)
+
+// Formatter renders values for one reader: numbers and amounts in their
+// locale, dates and times in their timezone and labels in their language
+type Formatter struct {
+	prefs   i18n.LocalePreferences
+	printer *message.Printer
+	catalog Catalog
+}
+
+// NewFormatter creates a formatter for prefs translating with catalog
+func NewFormatter(prefs i18n.LocalePreferences, catalog Catalog) *Formatter {
+	return &Formatter{
+		prefs:   prefs,
+		printer: message.NewPrinter(language.Make(prefs.Locale.String())),
+		catalog: catalog,
+	}
+}
+
+// Money formats m with the locale's separators and symbol position, e.g.
+// "$1,234.56" in en-US and "1.234,56 €" in de-DE
+func (f *Formatter) Money(m i18n.Money) string {
+	return m.FormatLocalized(f.prefs.Locale)
+}
+
+// Number formats value with the locale's separators and at most two
+// fraction digits
+func (f *Formatter) Number(value any) string {
+	return f.printer.Sprint(number.Decimal(value, number.MaxFractionDigits(2)))
+}
+
+// dateLayouts are the numeric date layouts by locale, then by language
+var dateLayouts = map[string]string{
+	"en-US": "01/02/2006",
+	"en-CA": "2006-01-02",
+	"en":    "02/01/2006",
+	"de":    "02.01.2006",
+	"fr":    "02/01/2006",
+	"es":    "02/01/2006",
+	"it":    "02/01/2006",
+	"pt":    "02/01/2006",
+	"id":    "02/01/2006",
+	"nl":    "02-01-2006",
+	"pl":    "02.01.2006",
+	"ru":    "02.01.2006",
+	"ja":    "2006/01/02",
+	"zh":    "2006/01/02",
+	"ko":    "2006. 01. 02.",
+}
+
+// twelveHourLocales show times as "3:04 PM"
+var twelveHourLocales = map[string]bool{"en-US": true, "en-CA": true, "en-AU": true, "en-PH": true}
+
+// Date formats a date, or a point in time converted to the reader's
+// timezone, in the locale's numeric date layout
+func (f *Formatter) Date(value any) (string, error) {
+	if date, ok := value.(i18n.Date); ok {
+		return date.In(time.UTC).Format(f.dateLayout()), nil
+	}
+	t, err := f.localize(value)
+	if err != nil {
+		return "", err
+	}
+	return t.Format(f.dateLayout()), nil
+}
+
+// DateTime formats a point in time in the reader's timezone with the
+// zone's abbreviation, e.g. "24.05.2024 14:30 CEST"
+func (f *Formatter) DateTime(value any) (string, error) {
+	t, err := f.localize(value)
+	if err != nil {
+		return "", err
+	}
+	timeLayout := "15:04"
+	if twelveHourLocales[f.prefs.Locale.String()] {
+		timeLayout = "3:04 PM"
+	}
+	return t.Format(f.dateLayout() + " " + timeLayout + " MST"), nil
+}
+
+func (f *Formatter) dateLayout() string {
+	if layout, ok := dateLayouts[f.prefs.Locale.String()]; ok {
+		return layout
+	}
+	if layout, ok := dateLayouts[f.prefs.Locale.Language().String()]; ok {
+		return layout
+	}
+	return "2006-01-02"
+}
+
+// localize converts a point in time to the reader's timezone. The zone's
+// rules apply at that time, so a summer date stays in summer time.
+func (f *Formatter) localize(value any) (time.Time, error) {
+	var t *i18n.Time
+	switch v := value.(type) {
+	case time.Time:
+		t = i18n.NewTimeFromTime(v)
+	case *time.Time:
+		t = i18n.NewTimeFromTime(*v)
+	case i18n.Time:
+		t = &v
+	case *i18n.Time:
+		t = v
+	case i18n.LocalizedDateTime:
+		t = &v.Time
+	case *i18n.LocalizedDateTime:
+		t = &v.Time
+	default:
+		return time.Time{}, fmt.Errorf("cannot format %T as a date", value)
+	}
+	ldt, err := i18n.NewLocalizedDateTime(*t, f.prefs.Timezone)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if loc, err := ldt.Timezone.GetLocation(); err == nil {
+		return ldt.ToUTC().In(loc), nil
+	}
+	return ldt.ToTime(), nil
+}
+
+// Text picks the reader's translation of text, falling back to English
+func (f *Formatter) Text(text i18n.LocalizedString) string {
+	value, _ := text.Get(f.prefs.Locale, fallbackLocale)
+	return value
+}
+
+// Translate looks up key in the catalog and replaces {name} placeholders
+// with the following name, value pairs. Unknown keys render as the key so
+// they stand out in a proof.
+func (f *Formatter) Translate(key string, args ...any) string {
+	text, ok := f.catalog[key]
+	if !ok {
+		return key
+	}
+	value := f.Text(text)
+	if len(args) == 0 {
+		return value
+	}
+	replacements := make([]string, 0, len(args))
+	for i := 0; i+1 < len(args); i += 2 {
+		replacements = append(replacements, "{"+fmt.Sprint(args[i])+"}", fmt.Sprint(args[i+1]))
+	}
+	return strings.NewReplacer(replacements...).Replace(value)
+}
+
+// Phone formats a phone number in international format
+func (f *Formatter) Phone(value any) (string, error) {
+	switch v := value.(type) {
+	case i18n.Phone:
+		return v.Format(), nil
+	case *i18n.Phone:
+		return v.Format(), nil
+	case i18n.LocalizedPhone:
+		return v.Phone.Format(), nil
+	case *i18n.LocalizedPhone:
+		return v.Phone.Format(), nil
+	case string:
+		phone, err := i18n.NewPhoneFromString(v)
+		if err != nil {
+			return "", err
+		}
+		return phone.Format(), nil
+	default:
+		return "", fmt.Errorf("cannot format %T as a phone number", value)
+	}
+}
+
+// Preferences returns the reader's preferences
+func (f *Formatter) Preferences() i18n.LocalePreferences {
+	return f.prefs
+}
+
+// FuncMap exposes the formatter to templates:
+//
+//	{{ t "invoice.title" }}  {{ money .Total }}  {{ date .DueDate }}
+//	{{ datetime .IssuedAt }}  {{ number 7.5 }}  {{ text .Description }}
+//	{{ phone .Contact }}  <html lang="{{ locale }}" dir="{{ direction }}">
+//
+// The map suits both html/template and text/template.
+func (f *Formatter) FuncMap() map[string]any {
+	return map[string]any{
+		"t":         f.Translate,
+		"text":      f.Text,
+		"money":     f.Money,
+		"number":    f.Number,
+		"date":      f.Date,
+		"datetime":  f.DateTime,
+		"phone":     f.Phone,
+		"locale":    func() string { return f.prefs.Locale.String() },
+		"direction": func() string { return f.prefs.Locale.Direction().String() },
+	}
+}
@@ -0,0 +1,35 @@
+// Package testutil provides helpers shared by the test suites under tests/.
+package testutil
+
+import (
+	"testing"
+	"time"
+
+	"golang-arch/internal/shared/domain/internationalization"
+	"golang-arch/pkg/clock"
+)
+
+// FreezeTime installs a fake clock frozen at the given instant for the
+// internationalization package and restores the real clock when the test ends.
+// The returned clock can be advanced to time-travel across DST transitions or
+// expiry deadlines.
+func FreezeTime(t testing.TB, at time.Time) *clock.Fake {
+	t.Helper()
+
+	fake := clock.NewFake(at)
+	restore := internationalization.SetClock(fake)
+	t.Cleanup(restore)
+
+	return fake
+}
+
+// MustParseTime parses an RFC3339 timestamp or fails the test
+func MustParseTime(t testing.TB, value string) time.Time {
+	t.Helper()
+
+	parsed, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		t.Fatalf("invalid RFC3339 time %q: %v", value, err)
+	}
+	return parsed
+}
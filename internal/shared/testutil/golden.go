@@ -0,0 +1,102 @@
+package testutil
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// UpdateGoldenEnv is the environment variable that rewrites golden files
+// instead of comparing against them:
+//
+//	UPDATE_GOLDEN=1 go test ./tests/internationalization/ -run Golden
+//
+// An environment variable rather than a -update flag, so importing the
+// package does not add a flag to every test binary.
+const UpdateGoldenEnv = "UPDATE_GOLDEN"
+
+// GoldenDir is the directory, relative to the test's package, holding golden files
+const GoldenDir = "testdata"
+
+// AssertGolden compares actual with testdata/<name>.golden and fails the test
+// with a line diff on mismatch. Run the test with UPDATE_GOLDEN=1 to (re)write the file.
+func AssertGolden(t testing.TB, name string, actual []byte) {
+	t.Helper()
+
+	path := filepath.Join(GoldenDir, name+".golden")
+
+	if shouldUpdateGolden() {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("failed to create golden directory: %v", err)
+		}
+		if err := os.WriteFile(path, actual, 0o644); err != nil {
+			t.Fatalf("failed to update golden file %s: %v", path, err)
+		}
+		return
+	}
+
+	expected, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read golden file %s (run with UPDATE_GOLDEN=1 to create it): %v", path, err)
+	}
+
+	if !bytes.Equal(expected, actual) {
+		t.Errorf("output does not match %s (run with UPDATE_GOLDEN=1 to accept):\n%s", path, LineDiff(string(expected), string(actual)))
+	}
+}
+
+// AssertGoldenLines joins lines with newlines and compares them with the
+// golden file; formatter tables usually render one "input\toutput" line per case
+func AssertGoldenLines(t testing.TB, name string, lines []string) {
+	t.Helper()
+	AssertGolden(t, name, []byte(strings.Join(lines, "\n")+"\n"))
+}
+
+// shouldUpdateGolden reports whether golden files should be rewritten
+func shouldUpdateGolden() bool {
+	return os.Getenv(UpdateGoldenEnv) == "1"
+}
+
+// LineDiff returns a line diff of expected and actual based on their longest
+// common subsequence. Only changed lines are listed, prefixed with "-" (only in
+// expected) or "+" (only in actual) and their line number in that input.
+func LineDiff(expected, actual string) string {
+	a := strings.Split(expected, "\n")
+	b := strings.Split(actual, "\n")
+
+	// lcs[i][j] is the LCS length of a[i:] and b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var builder strings.Builder
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			i++
+			j++
+		case i < len(a) && (j == len(b) || lcs[i+1][j] >= lcs[i][j+1]):
+			fmt.Fprintf(&builder, "-%4d  %s\n", i+1, a[i])
+			i++
+		default:
+			fmt.Fprintf(&builder, "+%4d  %s\n", j+1, b[j])
+			j++
+		}
+	}
+
+	return builder.String()
+}
@@ -0,0 +1,221 @@
+package testutil
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"golang-arch/internal/shared/api"
+	"golang-arch/internal/shared/domain/validation"
+)
+
+// APIClient issues in-process requests against an http.Handler and decodes the
+// standard api.Response envelope
+type APIClient struct {
+	t       testing.TB
+	handler http.Handler
+	headers http.Header
+}
+
+// NewAPIClient creates a client for the given handler (usually a gin engine or
+// bootstrap.Server)
+func NewAPIClient(t testing.TB, handler http.Handler) *APIClient {
+	return &APIClient{
+		t:       t,
+		handler: handler,
+		headers: make(http.Header),
+	}
+}
+
+// WithHeader returns a copy of the client that sends the header on every request
+func (c *APIClient) WithHeader(key, value string) *APIClient {
+	headers := c.headers.Clone()
+	headers.Set(key, value)
+
+	return &APIClient{t: c.t, handler: c.handler, headers: headers}
+}
+
+// Get performs a GET request
+func (c *APIClient) Get(path string) *APIResponse {
+	return c.Do(http.MethodGet, path, nil)
+}
+
+// Post performs a POST request with a JSON body
+func (c *APIClient) Post(path string, body any) *APIResponse {
+	return c.Do(http.MethodPost, path, body)
+}
+
+// Put performs a PUT request with a JSON body
+func (c *APIClient) Put(path string, body any) *APIResponse {
+	return c.Do(http.MethodPut, path, body)
+}
+
+// Delete performs a DELETE request
+func (c *APIClient) Delete(path string) *APIResponse {
+	return c.Do(http.MethodDelete, path, nil)
+}
+
+// Do performs a request; non-nil bodies are JSON encoded unless they are
+// already a string or byte slice
+func (c *APIClient) Do(method, path string, body any) *APIResponse {
+	c.t.Helper()
+
+	var reader io.Reader
+	switch payload := body.(type) {
+	case nil:
+	case string:
+		reader = strings.NewReader(payload)
+	case []byte:
+		reader = bytes.NewReader(payload)
+	default:
+		encoded, err := json.Marshal(payload)
+		if err != nil {
+			c.t.Fatalf("failed to encode request body: %v", err)
+		}
+		reader = bytes.NewReader(encoded)
+	}
+
+	request := httptest.NewRequest(method, path, reader)
+	for key, values := range c.headers {
+		request.Header[key] = values
+	}
+	if reader != nil && request.Header.Get("Content-Type") == "" {
+		request.Header.Set("Content-Type", "application/json")
+	}
+
+	recorder := httptest.NewRecorder()
+	c.handler.ServeHTTP(recorder, request)
+
+	response := &APIResponse{
+		StatusCode: recorder.Code,
+		Header:     recorder.Header(),
+		Body:       recorder.Body.Bytes(),
+	}
+	response.decodeEnvelope()
+
+	return response
+}
+
+// APIResponse is a recorded response with the decoded envelope
+type APIResponse struct {
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+
+	// Envelope is the decoded api.Response; Data is kept raw for DecodeData
+	Envelope struct {
+		Success bool            `json:"success"`
+		Message string          `json:"message"`
+		Data    json.RawMessage `json:"data"`
+		Error   json.RawMessage `json:"error"`
+
+		Code   string                      `json:"code"`
+		Errors validation.ValidationErrors `json:"errors"`
+	}
+	envelopeErr error
+}
+
+// decodeEnvelope parses the body as an api.Response when possible
+func (r *APIResponse) decodeEnvelope() {
+	if len(r.Body) == 0 {
+		return
+	}
+	r.envelopeErr = json.Unmarshal(r.Body, &r.Envelope)
+}
+
+// DecodeData unmarshals the envelope's data field into target
+func (r *APIResponse) DecodeData(t testing.TB, target any) {
+	t.Helper()
+
+	if r.envelopeErr != nil {
+		t.Fatalf("response is not an API envelope: %v\nbody: %s", r.envelopeErr, r.Body)
+	}
+	if len(r.Envelope.Data) == 0 {
+		t.Fatalf("response envelope has no data\nbody: %s", r.Body)
+	}
+	if err := json.Unmarshal(r.Envelope.Data, target); err != nil {
+		t.Fatalf("failed to decode response data: %v\nbody: %s", err, r.Body)
+	}
+}
+
+// APIError returns the error carried by the envelope. Both structured
+// {"code": ..., "message": ...} errors and the "CODE: message" string form
+// produced by api.Error are understood.
+func (r *APIResponse) APIError() *api.APIError {
+	if len(r.Envelope.Error) == 0 {
+		return nil
+	}
+
+	var structured api.APIError
+	if err := json.Unmarshal(r.Envelope.Error, &structured); err == nil && structured.Code != "" {
+		return &structured
+	}
+
+	var message string
+	if err := json.Unmarshal(r.Envelope.Error, &message); err != nil || message == "" {
+		return nil
+	}
+
+	if code, text, found := strings.Cut(message, ": "); found && isErrorCode(code) {
+		return &api.APIError{Code: code, Message: text}
+	}
+	return &api.APIError{Message: message}
+}
+
+// isErrorCode reports whether s looks like an UPPER_SNAKE error code
+func isErrorCode(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, char := range s {
+		if (char < 'A' || char > 'Z') && char != '_' && (char < '0' || char > '9') {
+			return false
+		}
+	}
+	return true
+}
+
+// AssertStatus fails the test when the status code differs
+func AssertStatus(t testing.TB, response *APIResponse, expected int) {
+	t.Helper()
+
+	if response.StatusCode != expected {
+		t.Errorf("expected status %d, got %d\nbody: %s", expected, response.StatusCode, response.Body)
+	}
+}
+
+// AssertSuccess fails the test unless the envelope reports success
+func AssertSuccess(t testing.TB, response *APIResponse) {
+	t.Helper()
+
+	if response.envelopeErr != nil {
+		t.Errorf("response is not an API envelope: %v\nbody: %s", response.envelopeErr, response.Body)
+		return
+	}
+	if !response.Envelope.Success {
+		t.Errorf("expected successful response, got failure\nbody: %s", response.Body)
+	}
+}
+
+// AssertErrorCode fails the test unless the envelope carries the error code
+func AssertErrorCode(t testing.TB, response *APIResponse, code string) {
+	t.Helper()
+
+	if response.Envelope.Success {
+		t.Errorf("expected error %s, got successful response\nbody: %s", code, response.Body)
+		return
+	}
+
+	apiErr := response.APIError()
+	if apiErr == nil {
+		t.Errorf("expected error %s, response has no error\nbody: %s", code, response.Body)
+		return
+	}
+	if apiErr.Code != code {
+		t.Errorf("expected error code %s, got %s\nbody: %s", code, apiErr.Code, response.Body)
+	}
+}
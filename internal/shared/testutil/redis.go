@@ -0,0 +1,21 @@
+package testutil
+
+import (
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+// NewRedis starts an in-memory Redis server and connects a client to it.
+// Both are released when the test ends; use the server to inspect keys or
+// FastForward expiry.
+func NewRedis(t testing.TB) (*miniredis.Miniredis, *redis.Client) {
+	t.Helper()
+
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { client.Close() })
+
+	return server, client
+}
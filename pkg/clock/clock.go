@@ -0,0 +1,128 @@
+// Package clock abstracts access to the current time so that time-dependent
+// code (DST offsets, expiries, schedules) can be driven deterministically in tests.
+package clock
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// Clock provides the current time and timers
+type Clock interface {
+	Now() time.Time
+	Since(t time.Time) time.Duration
+	After(d time.Duration) <-chan time.Time
+}
+
+// Real is the Clock backed by the system time
+type Real struct{}
+
+// New returns the system clock
+func New() Clock {
+	return Real{}
+}
+
+// Now returns the current system time
+func (Real) Now() time.Time {
+	return time.Now()
+}
+
+// Since returns the time elapsed since t
+func (Real) Since(t time.Time) time.Duration {
+	return time.Since(t)
+}
+
+// After waits for the duration to elapse and then sends the current time
+func (Real) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
+
+// Fake is a manually controlled Clock for tests. Time only moves when
+// Advance or Set is called; pending After channels fire once their deadline
+// has been reached.
+type Fake struct {
+	mu      sync.Mutex
+	now     time.Time
+	waiters []fakeWaiter
+}
+
+// fakeWaiter is a pending After call
+type fakeWaiter struct {
+	deadline time.Time
+	channel  chan time.Time
+}
+
+// NewFake creates a fake clock frozen at the given time
+func NewFake(now time.Time) *Fake {
+	return &Fake{now: now}
+}
+
+// Now returns the fake current time
+func (f *Fake) Now() time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.now
+}
+
+// Since returns the fake time elapsed since t
+func (f *Fake) Since(t time.Time) time.Duration {
+	return f.Now().Sub(t)
+}
+
+// After returns a channel that fires when the fake clock reaches now+d
+func (f *Fake) After(d time.Duration) <-chan time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	channel := make(chan time.Time, 1)
+	deadline := f.now.Add(d)
+	if d <= 0 {
+		channel <- f.now
+		return channel
+	}
+
+	f.waiters = append(f.waiters, fakeWaiter{deadline: deadline, channel: channel})
+	sort.Slice(f.waiters, func(i, j int) bool {
+		return f.waiters[i].deadline.Before(f.waiters[j].deadline)
+	})
+	return channel
+}
+
+// Advance moves the fake clock forward by d and fires due timers
+func (f *Fake) Advance(d time.Duration) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.now = f.now.Add(d)
+	f.fireDue()
+}
+
+// Set moves the fake clock to t and fires due timers
+func (f *Fake) Set(t time.Time) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.now = t
+	f.fireDue()
+}
+
+// PendingTimers returns the number of After channels that have not fired yet
+func (f *Fake) PendingTimers() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.waiters)
+}
+
+// fireDue notifies every waiter whose deadline has passed. Callers must hold f.mu.
+func (f *Fake) fireDue() {
+	remaining := f.waiters[:0]
+	for _, waiter := range f.waiters {
+		if waiter.deadline.After(f.now) {
+			remaining = append(remaining, waiter)
+			continue
+		}
+		waiter.channel <- f.now
+	}
+	f.waiters = remaining
+}
@@ -0,0 +1,169 @@
+package logger
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Common logger names used across the application
+const (
+	NameHTTP        = "api.http"
+	NameWorkerJobs  = "worker.jobs"
+	NameI18n        = "i18n"
+	NameEvents      = "events"
+	NameRates       = "rates"
+	NameRegions     = "regions"
+	NamePhoneVerify = "phoneverify"
+	NameOTP         = "otp"
+	NameErasure     = "erasure"
+	NameConsent     = "consent"
+	NameMetering    = "metering"
+	NameRefData     = "refdata"
+	NameHealth      = "health"
+	NameExport      = "export"
+	NameNotify      = "notify"
+	NamePush        = "push"
+	NameSaga        = "saga"
+	NameProjection  = "projection"
+	NameRetention   = "retention"
+	NameChaos       = "chaos"
+	NameLoadShed    = "loadshed"
+	NameFieldCrypt  = "fieldcrypt"
+)
+
+// Factory creates named loggers that share encoding and output but can have
+// their own level. Overrides are matched by dotted prefix, so an override for
+// "worker" also applies to "worker.jobs" unless a more specific one exists.
+type Factory struct {
+	encoder      zapcore.Encoder
+	output       zapcore.WriteSyncer
+	defaultLevel zapcore.Level
+	overrides    map[string]zapcore.Level
+	root         *zap.Logger
+	nop          bool
+
+	mu      sync.Mutex
+	loggers map[string]*zap.Logger
+}
+
+// NewFactory creates a logger factory from the base level, format and a level
+// override spec such as "worker.jobs=debug,api.http=warn".
+func NewFactory(level, format, levels string) (*Factory, error) {
+	var defaultLevel zapcore.Level
+	if err := defaultLevel.UnmarshalText([]byte(level)); err != nil {
+		return nil, err
+	}
+
+	overrides, err := ParseLevelOverrides(levels)
+	if err != nil {
+		return nil, err
+	}
+
+	factory := &Factory{
+		encoder:      newEncoder(format),
+		output:       zapcore.Lock(zapcore.AddSync(os.Stdout)),
+		defaultLevel: defaultLevel,
+		overrides:    overrides,
+		loggers:      make(map[string]*zap.Logger),
+	}
+	factory.root = factory.build("", defaultLevel)
+
+	return factory, nil
+}
+
+// NewNopFactory returns a factory whose loggers discard everything, for tests
+func NewNopFactory() *Factory {
+	return &Factory{
+		overrides: make(map[string]zapcore.Level),
+		root:      zap.NewNop(),
+		nop:       true,
+		loggers:   make(map[string]*zap.Logger),
+	}
+}
+
+// Root returns the unnamed application logger using the default level
+func (f *Factory) Root() *zap.Logger {
+	return f.root
+}
+
+// Named returns the logger for the given subsystem name, creating it on first use
+func (f *Factory) Named(name string) *zap.Logger {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if logger, exists := f.loggers[name]; exists {
+		return logger
+	}
+
+	logger := f.build(name, f.LevelFor(name))
+	f.loggers[name] = logger
+	return logger
+}
+
+// LevelFor resolves the effective level for a logger name using the most
+// specific matching override
+func (f *Factory) LevelFor(name string) zapcore.Level {
+	for candidate := name; candidate != ""; {
+		if level, exists := f.overrides[candidate]; exists {
+			return level
+		}
+
+		index := strings.LastIndex(candidate, ".")
+		if index < 0 {
+			break
+		}
+		candidate = candidate[:index]
+	}
+
+	return f.defaultLevel
+}
+
+// Sync flushes any buffered log entries
+func (f *Factory) Sync() error {
+	return f.root.Sync()
+}
+
+// build creates a logger with its own level on top of the shared encoder and output
+func (f *Factory) build(name string, level zapcore.Level) *zap.Logger {
+	if f.nop {
+		return zap.NewNop()
+	}
+
+	core := zapcore.NewCore(f.encoder.Clone(), f.output, level)
+	logger := zap.New(core, zap.AddCaller(), zap.AddStacktrace(zapcore.ErrorLevel))
+	if name != "" {
+		logger = logger.Named(name)
+	}
+	return logger
+}
+
+// ParseLevelOverrides parses a comma-separated "name=level" list
+func ParseLevelOverrides(spec string) (map[string]zapcore.Level, error) {
+	overrides := make(map[string]zapcore.Level)
+
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		name, value, found := strings.Cut(entry, "=")
+		name = strings.TrimSpace(name)
+		if !found || name == "" {
+			return nil, fmt.Errorf("invalid log level override %q, expected name=level", entry)
+		}
+
+		var level zapcore.Level
+		if err := level.UnmarshalText([]byte(strings.TrimSpace(value))); err != nil {
+			return nil, fmt.Errorf("invalid log level for %s: %w", name, err)
+		}
+		overrides[name] = level
+	}
+
+	return overrides, nil
+}
@@ -15,22 +15,9 @@ func NewLogger(level, format string) (*zap.Logger, error) {
 		return nil, err
 	}
 
-	// Configure encoder
-	var encoder zapcore.Encoder
-	encoderConfig := zap.NewProductionEncoderConfig()
-	encoderConfig.TimeKey = "timestamp"
-	encoderConfig.EncodeTime = zapcore.ISO8601TimeEncoder
-	encoderConfig.EncodeLevel = zapcore.CapitalLevelEncoder
-
-	if format == "json" {
-		encoder = zapcore.NewJSONEncoder(encoderConfig)
-	} else {
-		encoder = zapcore.NewConsoleEncoder(encoderConfig)
-	}
-
 	// Create core
 	core := zapcore.NewCore(
-		encoder,
+		newEncoder(format),
 		zapcore.AddSync(os.Stdout),
 		zapLevel,
 	)
@@ -40,3 +27,16 @@ func NewLogger(level, format string) (*zap.Logger, error) {
 
 	return logger, nil
 }
+
+// newEncoder creates the shared encoder for the given format ("json" or console)
+func newEncoder(format string) zapcore.Encoder {
+	encoderConfig := zap.NewProductionEncoderConfig()
+	encoderConfig.TimeKey = "timestamp"
+	encoderConfig.EncodeTime = zapcore.ISO8601TimeEncoder
+	encoderConfig.EncodeLevel = zapcore.CapitalLevelEncoder
+
+	if format == "json" {
+		return zapcore.NewJSONEncoder(encoderConfig)
+	}
+	return zapcore.NewConsoleEncoder(encoderConfig)
+}
@@ -0,0 +1,142 @@
+package metrics
+
+import (
+	"runtime/debug"
+	"strconv"
+)
+
+// Identity labels attach to every series of the process, under these names,
+// so the server and the worker can share dashboards filtered by them
+const (
+	LabelService = "service"
+	LabelEnv     = "env"
+	LabelVersion = "version"
+)
+
+// Labels of the shared instruments
+const (
+	LabelMethod = "method" // HTTP method
+	LabelRoute  = "route"  // Route pattern, never the raw path
+	LabelStatus = "status" // HTTP status code or job outcome
+	LabelJob    = "job"    // Background job name
+)
+
+// Namespaces prefix instrument names by the part of the system they observe
+const (
+	NamespaceHTTP   = "http.server"
+	NamespaceWorker = "worker"
+	NamespaceJobs   = "worker.jobs"
+	NamespaceI18n   = "i18n"
+	NamespaceChaos  = "chaos"
+)
+
+// Identity is the process emitting metrics
+type Identity struct {
+	Service string // e.g. "golang-arch"
+	Env     string // Deployment environment, e.g. "production"
+	Version string // Release, e.g. a tag or commit; see BuildVersion
+}
+
+// Labels returns the identity as series labels, omitting empty fields
+func (id Identity) Labels() Labels {
+	return nonEmpty(Labels{LabelService: id.Service, LabelEnv: id.Env, LabelVersion: id.Version})
+}
+
+// Resource returns the identity as OpenTelemetry resource attributes,
+// omitting empty fields
+func (id Identity) Resource() Labels {
+	return nonEmpty(Labels{
+		"service.name":           id.Service,
+		"deployment.environment": id.Env,
+		"service.version":        id.Version,
+	})
+}
+
+// nonEmpty drops labels without a value
+func nonEmpty(labels Labels) Labels {
+	for name, value := range labels {
+		if value == "" {
+			delete(labels, name)
+		}
+	}
+	return labels
+}
+
+// BuildVersion returns the version of the running binary: the module version
+// when built from a tagged module, else the VCS revision stamped by go build,
+// with a "-dirty" suffix for uncommitted changes, else "unknown"
+func BuildVersion() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return "unknown"
+	}
+	if version := info.Main.Version; version != "" && version != "(devel)" {
+		return version
+	}
+
+	var revision string
+	var modified bool
+	for _, setting := range info.Settings {
+		switch setting.Key {
+		case "vcs.revision":
+			revision = setting.Value
+		case "vcs.modified":
+			modified = setting.Value == "true"
+		}
+	}
+	if revision == "" {
+		return "unknown"
+	}
+	if len(revision) > 12 {
+		revision = revision[:12]
+	}
+	if modified {
+		revision += "-dirty"
+	}
+	return revision
+}
+
+// Namespace defines instruments under a common name prefix
+type Namespace struct {
+	registry *Registry
+	prefix   string
+}
+
+// Namespace returns the constructors for instruments named prefix.<name>
+func (r *Registry) Namespace(prefix string) Namespace {
+	return Namespace{registry: r, prefix: prefix}
+}
+
+// Name returns the full name of an instrument in the namespace
+func (n Namespace) Name(name string) string {
+	return n.prefix + "." + name
+}
+
+// Counter defines (or returns the existing) counter prefix.<name>
+func (n Namespace) Counter(name, description, unit string) (*Counter, error) {
+	return n.registry.Counter(n.Name(name), description, unit)
+}
+
+// Gauge defines (or returns the existing) gauge prefix.<name>
+func (n Namespace) Gauge(name, description, unit string) (*Gauge, error) {
+	return n.registry.Gauge(n.Name(name), description, unit)
+}
+
+// Histogram defines (or returns the existing) histogram prefix.<name>
+func (n Namespace) Histogram(name, description, unit string, buckets []float64) (*Histogram, error) {
+	return n.registry.Histogram(n.Name(name), description, unit, buckets)
+}
+
+// HTTPLabels returns the labels of the HTTP server instruments
+func HTTPLabels(method, route string, status int) Labels {
+	return Labels{LabelMethod: method, LabelRoute: route, LabelStatus: strconv.Itoa(status)}
+}
+
+// JobLabels returns the labels of the job instruments; the status is omitted
+// when empty, as for gauges that describe the job rather than a run
+func JobLabels(job, status string) Labels {
+	if status == "" {
+		return Labels{LabelJob: job}
+	}
+	return Labels{LabelJob: job, LabelStatus: status}
+}
@@ -0,0 +1,82 @@
+package metrics
+
+import "errors"
+
+// Instruments holds the instrument definitions shared by every exporter
+type Instruments struct {
+	HTTPRequests        *Counter
+	HTTPRequestDuration *Histogram
+	HTTPInFlight        *Gauge
+	HTTPQueued          *Gauge
+	HTTPConcurrency     *Gauge
+	HTTPShed            *Counter
+	HTTPResponseCache   *Counter
+	HTTPDeprecated      *Counter
+	JobsProcessed       *Counter
+	JobDuration         *Histogram
+	JobsPaused          *Gauge
+	ProjectionLag       *Gauge
+	RetentionRows       *Counter
+	MoneyJSONLegacy     *Counter
+	ChaosFaults         *Counter
+}
+
+// newInstruments defines the application instruments on the registry,
+// failing when a name is taken by an instrument of another kind or buckets
+func newInstruments(registry *Registry) (*Instruments, error) {
+	var errs []error
+	counter := func(namespace Namespace, name, description, unit string) *Counter {
+		instrument, err := namespace.Counter(name, description, unit)
+		errs = append(errs, err)
+		return instrument
+	}
+	gauge := func(namespace Namespace, name, description, unit string) *Gauge {
+		instrument, err := namespace.Gauge(name, description, unit)
+		errs = append(errs, err)
+		return instrument
+	}
+	histogram := func(namespace Namespace, name, description, unit string, buckets []float64) *Histogram {
+		instrument, err := namespace.Histogram(name, description, unit, buckets)
+		errs = append(errs, err)
+		return instrument
+	}
+
+	http := registry.Namespace(NamespaceHTTP)
+	worker := registry.Namespace(NamespaceWorker)
+	jobs := registry.Namespace(NamespaceJobs)
+	i18n := registry.Namespace(NamespaceI18n)
+	chaos := registry.Namespace(NamespaceChaos)
+	instruments := &Instruments{
+		HTTPRequests: counter(http, "requests",
+			"Number of HTTP requests handled", "{request}"),
+		HTTPRequestDuration: histogram(http, "request.duration",
+			"Duration of HTTP requests in seconds", "s", DefaultBuckets),
+		HTTPInFlight: gauge(http, "requests.inflight",
+			"Number of HTTP requests admitted by load shedding and not finished", "{request}"),
+		HTTPQueued: gauge(http, "requests.queued",
+			"Number of HTTP requests waiting for a load shedding slot", "{request}"),
+		HTTPConcurrency: gauge(http, "concurrency.limit",
+			"Current limit on HTTP requests handled at once", "{request}"),
+		HTTPShed: counter(http, "requests.shed",
+			"Number of HTTP requests answered 503 by load shedding, by reason", "{request}"),
+		HTTPResponseCache: counter(http, "cache.lookups",
+			"Number of in-process response cache lookups, by result", "{lookup}"),
+		HTTPDeprecated: counter(http, "deprecated.requests",
+			"Number of requests to deprecated routes", "{request}"),
+		JobsProcessed: counter(jobs, "processed",
+			"Number of background job runs", "{job}"),
+		JobDuration: histogram(jobs, "duration",
+			"Duration of background job runs in seconds", "s", DefaultBuckets),
+		JobsPaused: gauge(jobs, "paused",
+			"Whether a job is paused by its circuit breaker (1) or running (0)", "{job}"),
+		ProjectionLag: gauge(worker, "projections.lag",
+			"Number of stored events a read-model projection has not processed yet", "{event}"),
+		RetentionRows: counter(worker, "retention.rows",
+			"Number of expired rows archived or deleted by retention policies", "{row}"),
+		MoneyJSONLegacy: counter(i18n, "money.json.legacy",
+			"Number of Money payloads decoded through a deprecated lenient path", "{payload}"),
+		ChaosFaults: counter(chaos, "faults",
+			"Number of latency and error faults injected for resilience testing", "{fault}"),
+	}
+	return instruments, errors.Join(errs...)
+}
@@ -0,0 +1,106 @@
+// Package metrics defines the application's metric instruments once in a shared
+// registry and exports them either through a Prometheus pull endpoint or by
+// pushing OTLP/HTTP to an OpenTelemetry collector. Both exporters read the same
+// instruments, so dashboards see the same series regardless of the transport
+// selected in configuration.
+//
+// Every binary follows the same convention (convention.go): instruments are
+// named within a namespace such as "http.server" or "worker.jobs", share
+// label names such as "route" and "job", and every series carries the
+// process identity as "service", "env" and "version" labels, so the server
+// and the worker can be charted on one dashboard.
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// Supported exporters
+const (
+	ExporterNone       = "none"
+	ExporterPrometheus = "prometheus"
+	ExporterOTLP       = "otlp"
+)
+
+// Options configures the metrics provider
+type Options struct {
+	Exporter       string        // "prometheus", "otlp" or "none"
+	OTLPEndpoint   string        // Collector host:port or URL for OTLP/HTTP
+	OTLPInsecure   bool          // Use plain HTTP towards the collector
+	ExportInterval time.Duration // Push interval for OTLP
+	Identity       Identity      // Labels every series, and the OTLP resource
+	Resource       Labels        // Extra resource attributes attached to OTLP exports
+}
+
+// Provider owns the instrument registry and the selected exporter
+type Provider struct {
+	exporter    string
+	registry    *Registry
+	handler     http.Handler
+	otlp        *OTLPExporter
+	Instruments *Instruments
+}
+
+// NewProvider creates a metrics provider for the configured exporter
+func NewProvider(opts Options) (*Provider, error) {
+	registry := NewRegistry()
+	registry.SetConstLabels(opts.Identity.Labels())
+	instruments, err := newInstruments(registry)
+	if err != nil {
+		return nil, fmt.Errorf("failed to define instruments: %w", err)
+	}
+	provider := &Provider{
+		exporter:    opts.Exporter,
+		registry:    registry,
+		Instruments: instruments,
+	}
+
+	switch opts.Exporter {
+	case ExporterPrometheus:
+		provider.handler = PrometheusHandler(registry)
+	case ExporterOTLP:
+		resource := opts.Identity.Resource()
+		for name, value := range opts.Resource {
+			resource[name] = value
+		}
+		provider.otlp = NewOTLPExporter(registry, opts.OTLPEndpoint, opts.OTLPInsecure, opts.ExportInterval, resource)
+		provider.otlp.Start()
+	case ExporterNone, "":
+	default:
+		return nil, fmt.Errorf("unsupported metrics exporter: %s", opts.Exporter)
+	}
+
+	return provider, nil
+}
+
+// NewNoopProvider returns a provider that records values but never exports them
+func NewNoopProvider() *Provider {
+	provider, _ := NewProvider(Options{Exporter: ExporterNone})
+	return provider
+}
+
+// Registry returns the registry used to define additional instruments
+func (p *Provider) Registry() *Registry {
+	return p.registry
+}
+
+// Handler returns the Prometheus scrape handler, or nil for other exporters
+func (p *Provider) Handler() http.Handler {
+	return p.handler
+}
+
+// Exporter returns the configured exporter name
+func (p *Provider) Exporter() string {
+	return p.exporter
+}
+
+// Shutdown flushes pending metrics and stops the push loop
+func (p *Provider) Shutdown(ctx context.Context) error {
+	if p.otlp != nil {
+		return p.otlp.Shutdown(ctx)
+	}
+	return nil
+}
@@ -0,0 +1,212 @@
+package metrics
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// OTLP aggregation temporality and scope identifiers
+const (
+	otlpTemporalityCumulative = 2
+	otlpScopeName             = "golang-arch"
+)
+
+// OTLPExporter periodically pushes the registry to an OTel collector using
+// OTLP/HTTP with the JSON encoding.
+type OTLPExporter struct {
+	registry   *Registry
+	endpoint   string
+	interval   time.Duration
+	resource   Labels
+	httpClient *http.Client
+
+	stop chan struct{}
+	done chan struct{}
+	once sync.Once
+}
+
+// NewOTLPExporter creates an exporter posting to endpoint (host:port or URL)
+func NewOTLPExporter(registry *Registry, endpoint string, insecure bool, interval time.Duration, resource Labels) *OTLPExporter {
+	if interval <= 0 {
+		interval = 30 * time.Second
+	}
+
+	return &OTLPExporter{
+		registry:   registry,
+		endpoint:   otlpURL(endpoint, insecure),
+		interval:   interval,
+		resource:   resource,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+		stop:       make(chan struct{}),
+		done:       make(chan struct{}),
+	}
+}
+
+// Start begins pushing metrics on the configured interval
+func (e *OTLPExporter) Start() {
+	go func() {
+		defer close(e.done)
+
+		ticker := time.NewTicker(e.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				ctx, cancel := context.WithTimeout(context.Background(), e.interval)
+				_ = e.Export(ctx)
+				cancel()
+			case <-e.stop:
+				return
+			}
+		}
+	}()
+}
+
+// Shutdown stops the push loop and performs a final export
+func (e *OTLPExporter) Shutdown(ctx context.Context) error {
+	e.once.Do(func() { close(e.stop) })
+
+	select {
+	case <-e.done:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	return e.Export(ctx)
+}
+
+// Export sends the current registry snapshot to the collector
+func (e *OTLPExporter) Export(ctx context.Context) error {
+	payload, err := json.Marshal(e.buildRequest(time.Now()))
+	if err != nil {
+		return fmt.Errorf("failed to encode otlp metrics: %w", err)
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, e.endpoint, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("failed to create otlp request: %w", err)
+	}
+	request.Header.Set("Content-Type", "application/json")
+
+	response, err := e.httpClient.Do(request)
+	if err != nil {
+		return fmt.Errorf("failed to push otlp metrics: %w", err)
+	}
+	defer response.Body.Close()
+	_, _ = io.Copy(io.Discard, response.Body)
+
+	if response.StatusCode >= 300 {
+		return fmt.Errorf("otlp collector responded with status %d", response.StatusCode)
+	}
+
+	return nil
+}
+
+// buildRequest converts the registry snapshot into an ExportMetricsServiceRequest
+func (e *OTLPExporter) buildRequest(now time.Time) map[string]any {
+	start := strconv.FormatInt(e.registry.StartTime().UnixNano(), 10)
+	timestamp := strconv.FormatInt(now.UnixNano(), 10)
+
+	var metrics []map[string]any
+	for _, snapshot := range e.registry.Snapshot() {
+		metric := map[string]any{
+			"name":        snapshot.Name,
+			"description": snapshot.Description,
+			"unit":        snapshot.Unit,
+		}
+
+		var points []map[string]any
+		for _, current := range snapshot.Series {
+			point := map[string]any{
+				"attributes":        otlpAttributes(current.Labels),
+				"startTimeUnixNano": start,
+				"timeUnixNano":      timestamp,
+			}
+
+			if snapshot.Kind == KindHistogram {
+				bucketCounts := make([]string, len(current.BucketCounts))
+				for i, count := range current.BucketCounts {
+					bucketCounts[i] = strconv.FormatUint(count, 10)
+				}
+				point["count"] = strconv.FormatUint(current.Count, 10)
+				point["sum"] = current.Sum
+				point["bucketCounts"] = bucketCounts
+				point["explicitBounds"] = snapshot.Buckets
+			} else {
+				point["asDouble"] = current.Value
+			}
+
+			points = append(points, point)
+		}
+
+		switch snapshot.Kind {
+		case KindCounter:
+			metric["sum"] = map[string]any{
+				"dataPoints":             points,
+				"aggregationTemporality": otlpTemporalityCumulative,
+				"isMonotonic":            true,
+			}
+		case KindHistogram:
+			metric["histogram"] = map[string]any{
+				"dataPoints":             points,
+				"aggregationTemporality": otlpTemporalityCumulative,
+			}
+		default:
+			metric["gauge"] = map[string]any{"dataPoints": points}
+		}
+
+		metrics = append(metrics, metric)
+	}
+
+	return map[string]any{
+		"resourceMetrics": []map[string]any{{
+			"resource": map[string]any{"attributes": otlpAttributes(e.resource)},
+			"scopeMetrics": []map[string]any{{
+				"scope":   map[string]any{"name": otlpScopeName},
+				"metrics": metrics,
+			}},
+		}},
+	}
+}
+
+// otlpAttributes converts labels into OTLP KeyValue attributes
+func otlpAttributes(labels Labels) []map[string]any {
+	attributes := make([]map[string]any, 0, len(labels))
+	for name, value := range labels {
+		attributes = append(attributes, map[string]any{
+			"key":   name,
+			"value": map[string]any{"stringValue": value},
+		})
+	}
+	return attributes
+}
+
+// otlpURL builds the collector metrics URL from an endpoint setting
+func otlpURL(endpoint string, insecure bool) string {
+	if endpoint == "" {
+		endpoint = "localhost:4318"
+	}
+
+	if !strings.HasPrefix(endpoint, "http://") && !strings.HasPrefix(endpoint, "https://") {
+		scheme := "https://"
+		if insecure {
+			scheme = "http://"
+		}
+		endpoint = scheme + endpoint
+	}
+
+	if !strings.HasSuffix(endpoint, "/v1/metrics") {
+		endpoint = strings.TrimSuffix(endpoint, "/") + "/v1/metrics"
+	}
+
+	return endpoint
+}
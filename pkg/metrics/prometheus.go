@@ -0,0 +1,115 @@
+package metrics
+
+import (
+	"bufio"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// PrometheusHandler serves the registry in the Prometheus text exposition format
+func PrometheusHandler(registry *Registry) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+
+		writer := bufio.NewWriter(w)
+		defer writer.Flush()
+
+		for _, snapshot := range registry.Snapshot() {
+			writePrometheus(writer, snapshot)
+		}
+	})
+}
+
+// writePrometheus renders a single instrument
+func writePrometheus(w *bufio.Writer, snapshot InstrumentSnapshot) {
+	name := PrometheusName(snapshot.Name)
+	promType := "gauge"
+	switch snapshot.Kind {
+	case KindCounter:
+		name += "_total"
+		promType = "counter"
+	case KindHistogram:
+		promType = "histogram"
+	}
+
+	if snapshot.Description != "" {
+		w.WriteString("# HELP " + name + " " + snapshot.Description + "\n")
+	}
+	w.WriteString("# TYPE " + name + " " + promType + "\n")
+
+	for _, current := range snapshot.Series {
+		if snapshot.Kind != KindHistogram {
+			writeSample(w, name, current.Labels, "", "", current.Value)
+			continue
+		}
+
+		var cumulative uint64
+		for i, bound := range snapshot.Buckets {
+			cumulative += current.BucketCounts[i]
+			writeSample(w, name+"_bucket", current.Labels, "le", formatFloat(bound), float64(cumulative))
+		}
+		cumulative += current.BucketCounts[len(snapshot.Buckets)]
+		writeSample(w, name+"_bucket", current.Labels, "le", "+Inf", float64(cumulative))
+		writeSample(w, name+"_sum", current.Labels, "", "", current.Sum)
+		writeSample(w, name+"_count", current.Labels, "", "", float64(current.Count))
+	}
+}
+
+// writeSample renders one sample line with an optional extra label
+func writeSample(w *bufio.Writer, name string, labels Labels, extraName, extraValue string, value float64) {
+	w.WriteString(name)
+
+	names := make([]string, 0, len(labels))
+	for labelName := range labels {
+		names = append(names, labelName)
+	}
+	sort.Strings(names)
+
+	if len(names) > 0 || extraName != "" {
+		w.WriteByte('{')
+		for i, labelName := range names {
+			if i > 0 {
+				w.WriteByte(',')
+			}
+			writeLabel(w, PrometheusName(labelName), labels[labelName])
+		}
+		if extraName != "" {
+			if len(names) > 0 {
+				w.WriteByte(',')
+			}
+			writeLabel(w, extraName, extraValue)
+		}
+		w.WriteByte('}')
+	}
+
+	w.WriteByte(' ')
+	w.WriteString(formatFloat(value))
+	w.WriteByte('\n')
+}
+
+// writeLabel renders name="value" with Prometheus escaping
+func writeLabel(w *bufio.Writer, name, value string) {
+	w.WriteString(name)
+	w.WriteString(`="`)
+	w.WriteString(labelEscaper.Replace(value))
+	w.WriteByte('"')
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+
+// PrometheusName converts a dotted OpenTelemetry-style name to a Prometheus name
+func PrometheusName(name string) string {
+	return strings.Map(func(r rune) rune {
+		if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') || r == '_' {
+			return r
+		}
+		return '_'
+	}, name)
+}
+
+// formatFloat renders a float the way Prometheus expects
+func formatFloat(value float64) string {
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
@@ -0,0 +1,327 @@
+package metrics
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Instrument kinds
+const (
+	KindCounter   = "counter"
+	KindGauge     = "gauge"
+	KindHistogram = "histogram"
+)
+
+// DefaultBuckets are the histogram bucket bounds used for durations in seconds
+var DefaultBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// Labels is a set of metric attributes
+type Labels map[string]string
+
+// key returns a canonical representation of the label set
+func (l Labels) key() string {
+	if len(l) == 0 {
+		return ""
+	}
+
+	names := make([]string, 0, len(l))
+	for name := range l {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var builder strings.Builder
+	for i, name := range names {
+		if i > 0 {
+			builder.WriteByte(',')
+		}
+		builder.WriteString(name)
+		builder.WriteByte('=')
+		builder.WriteString(l[name])
+	}
+	return builder.String()
+}
+
+// clone copies the label set so callers can reuse their map
+func (l Labels) clone() Labels {
+	cloned := make(Labels, len(l))
+	for name, value := range l {
+		cloned[name] = value
+	}
+	return cloned
+}
+
+// Registry holds every instrument defined by the application. Exporters read
+// from the registry, so each instrument is defined exactly once regardless of
+// how metrics leave the process.
+type Registry struct {
+	mu          sync.RWMutex
+	instruments []*instrument
+	byName      map[string]*instrument
+	constLabels Labels
+	startTime   time.Time
+}
+
+// NewRegistry creates an empty instrument registry
+func NewRegistry() *Registry {
+	return &Registry{
+		byName:    make(map[string]*instrument),
+		startTime: time.Now(),
+	}
+}
+
+// SetConstLabels attaches labels to every series in snapshots, such as the
+// process identity. They take precedence over labels of the same name passed
+// when recording.
+func (r *Registry) SetConstLabels(labels Labels) {
+	r.mu.Lock()
+	r.constLabels = labels.clone()
+	r.mu.Unlock()
+}
+
+// Counter defines (or returns the existing) monotonic counter. It fails when
+// the name is already registered as another kind.
+func (r *Registry) Counter(name, description, unit string) (*Counter, error) {
+	inst, err := r.register(name, description, unit, KindCounter, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &Counter{inst}, nil
+}
+
+// Gauge defines (or returns the existing) gauge. It fails when the name is
+// already registered as another kind.
+func (r *Registry) Gauge(name, description, unit string) (*Gauge, error) {
+	inst, err := r.register(name, description, unit, KindGauge, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &Gauge{inst}, nil
+}
+
+// Histogram defines (or returns the existing) histogram with the given
+// bucket bounds, DefaultBuckets when empty. It fails when the bounds are not
+// increasing, or when the name is already registered as another kind or with
+// other bounds.
+func (r *Registry) Histogram(name, description, unit string, buckets []float64) (*Histogram, error) {
+	if len(buckets) == 0 {
+		buckets = DefaultBuckets
+	}
+	for i := range buckets {
+		if math.IsNaN(buckets[i]) || (i > 0 && buckets[i] <= buckets[i-1]) {
+			return nil, fmt.Errorf("metric %s: bucket bounds must be increasing, got %v", name, buckets)
+		}
+	}
+	inst, err := r.register(name, description, unit, KindHistogram, buckets)
+	if err != nil {
+		return nil, err
+	}
+	return &Histogram{inst}, nil
+}
+
+// register adds an instrument or returns the existing one with the same
+// name, which must have the same kind and buckets
+func (r *Registry) register(name, description, unit, kind string, buckets []float64) (*instrument, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if existing, exists := r.byName[name]; exists {
+		if existing.kind != kind {
+			return nil, fmt.Errorf("metric %s already registered as %s, not %s", name, existing.kind, kind)
+		}
+		if !slices.Equal(existing.buckets, buckets) {
+			return nil, fmt.Errorf("metric %s already registered with buckets %v, not %v", name, existing.buckets, buckets)
+		}
+		return existing, nil
+	}
+
+	inst := &instrument{
+		name:        name,
+		description: description,
+		unit:        unit,
+		kind:        kind,
+		buckets:     append([]float64(nil), buckets...),
+		series:      make(map[string]*series),
+	}
+	r.instruments = append(r.instruments, inst)
+	r.byName[name] = inst
+	return inst, nil
+}
+
+// Snapshot returns a consistent copy of every instrument and its series
+func (r *Registry) Snapshot() []InstrumentSnapshot {
+	r.mu.RLock()
+	instruments := append([]*instrument(nil), r.instruments...)
+	constLabels := r.constLabels
+	r.mu.RUnlock()
+
+	snapshots := make([]InstrumentSnapshot, 0, len(instruments))
+	for _, inst := range instruments {
+		snapshot := inst.snapshot()
+		for _, current := range snapshot.Series {
+			for name, value := range constLabels {
+				current.Labels[name] = value
+			}
+		}
+		snapshots = append(snapshots, snapshot)
+	}
+	return snapshots
+}
+
+// StartTime returns when the registry began accumulating values
+func (r *Registry) StartTime() time.Time {
+	return r.startTime
+}
+
+// InstrumentSnapshot is a point-in-time copy of an instrument
+type InstrumentSnapshot struct {
+	Name        string
+	Description string
+	Unit        string
+	Kind        string
+	Buckets     []float64
+	Series      []SeriesSnapshot
+}
+
+// SeriesSnapshot is a point-in-time copy of one labelled series
+type SeriesSnapshot struct {
+	Labels       Labels
+	Value        float64  // Counter and gauge value
+	Count        uint64   // Histogram observation count
+	Sum          float64  // Histogram observation sum
+	BucketCounts []uint64 // Histogram per-bucket counts (len(Buckets)+1)
+}
+
+// instrument is the shared storage behind counters, gauges and histograms
+type instrument struct {
+	name        string
+	description string
+	unit        string
+	kind        string
+	buckets     []float64
+
+	mu     sync.Mutex
+	series map[string]*series
+	order  []string
+}
+
+// series holds the accumulated values for one label set
+type series struct {
+	labels       Labels
+	value        float64
+	count        uint64
+	sum          float64
+	bucketCounts []uint64
+}
+
+// seriesFor returns the series for the label set, creating it when needed.
+// Callers must hold inst.mu.
+func (inst *instrument) seriesFor(labels Labels) *series {
+	key := labels.key()
+	if existing, exists := inst.series[key]; exists {
+		return existing
+	}
+
+	created := &series{labels: labels.clone()}
+	if inst.kind == KindHistogram {
+		created.bucketCounts = make([]uint64, len(inst.buckets)+1)
+	}
+	inst.series[key] = created
+	inst.order = append(inst.order, key)
+	return created
+}
+
+// snapshot copies the instrument state
+func (inst *instrument) snapshot() InstrumentSnapshot {
+	inst.mu.Lock()
+	defer inst.mu.Unlock()
+
+	snapshot := InstrumentSnapshot{
+		Name:        inst.name,
+		Description: inst.description,
+		Unit:        inst.unit,
+		Kind:        inst.kind,
+		Buckets:     append([]float64(nil), inst.buckets...),
+		Series:      make([]SeriesSnapshot, 0, len(inst.order)),
+	}
+
+	for _, key := range inst.order {
+		current := inst.series[key]
+		snapshot.Series = append(snapshot.Series, SeriesSnapshot{
+			Labels:       current.labels.clone(),
+			Value:        current.value,
+			Count:        current.count,
+			Sum:          current.sum,
+			BucketCounts: append([]uint64(nil), current.bucketCounts...),
+		})
+	}
+
+	return snapshot
+}
+
+// Counter is a monotonically increasing value
+type Counter struct {
+	inst *instrument
+}
+
+// Add increases the counter by a non-negative delta
+func (c *Counter) Add(delta float64, labels Labels) {
+	if delta < 0 || math.IsNaN(delta) {
+		return
+	}
+
+	c.inst.mu.Lock()
+	c.inst.seriesFor(labels).value += delta
+	c.inst.mu.Unlock()
+}
+
+// Inc increases the counter by one
+func (c *Counter) Inc(labels Labels) {
+	c.Add(1, labels)
+}
+
+// Gauge is a value that can go up and down
+type Gauge struct {
+	inst *instrument
+}
+
+// Set replaces the gauge value
+func (g *Gauge) Set(value float64, labels Labels) {
+	g.inst.mu.Lock()
+	g.inst.seriesFor(labels).value = value
+	g.inst.mu.Unlock()
+}
+
+// Add adjusts the gauge value by delta
+func (g *Gauge) Add(delta float64, labels Labels) {
+	g.inst.mu.Lock()
+	g.inst.seriesFor(labels).value += delta
+	g.inst.mu.Unlock()
+}
+
+// Histogram records the distribution of observed values
+type Histogram struct {
+	inst *instrument
+}
+
+// Observe records a single value
+func (h *Histogram) Observe(value float64, labels Labels) {
+	index := sort.SearchFloat64s(h.inst.buckets, value)
+
+	h.inst.mu.Lock()
+	current := h.inst.seriesFor(labels)
+	current.count++
+	current.sum += value
+	current.bucketCounts[index]++
+	h.inst.mu.Unlock()
+}
+
+// ObserveDuration records the time elapsed since start in seconds
+func (h *Histogram) ObserveDuration(start time.Time, labels Labels) {
+	h.Observe(time.Since(start).Seconds(), labels)
+}
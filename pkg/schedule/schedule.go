@@ -0,0 +1,270 @@
+// Package schedule computes when recurring background jobs run. It supports
+// standard five-field cron expressions, the usual @-descriptors and fixed
+// intervals, and evaluates cron fields in an explicit IANA timezone so
+// "09:00 in New York" stays 09:00 across DST changes.
+package schedule
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schedule returns the next activation time strictly after t
+type Schedule interface {
+	Next(t time.Time) time.Time
+}
+
+// Interval is a Schedule that fires every fixed duration
+type Interval time.Duration
+
+// Every returns a Schedule firing every d
+func Every(d time.Duration) Interval {
+	return Interval(d)
+}
+
+// Next returns t plus the interval
+func (i Interval) Next(t time.Time) time.Time {
+	return t.Add(time.Duration(i))
+}
+
+// String returns the @every form of the interval
+func (i Interval) String() string {
+	return "@every " + time.Duration(i).String()
+}
+
+// CronSchedule is a parsed five-field cron expression:
+//
+//	minute hour day-of-month month day-of-week
+//
+// Fields accept "*", values, ranges ("1-5"), lists ("1,15") and steps
+// ("*/15", "0-30/10"). Day-of-week runs from 0 (Sunday) to 6, with 7 also
+// meaning Sunday. As in Vixie cron, when both day fields are restricted a
+// day matches if either one does.
+type CronSchedule struct {
+	expr     string
+	location *time.Location
+
+	minutes  uint64
+	hours    uint64
+	days     uint64
+	months   uint64
+	weekdays uint64
+
+	// anyDay and anyWeekday record "*" day fields for the either/or rule
+	anyDay     bool
+	anyWeekday bool
+}
+
+// descriptors maps the @-shorthands to their five-field expressions
+var descriptors = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// tzPrefix selects the timezone of a cron expression, e.g.
+// "CRON_TZ=Europe/Berlin 0 6 * * *"
+const tzPrefix = "CRON_TZ="
+
+// Parse parses a cron expression, an @-descriptor or "@every <duration>".
+// Cron fields are evaluated in UTC unless the expression starts with
+// CRON_TZ=<IANA zone>.
+func Parse(expr string) (Schedule, error) {
+	trimmed := strings.TrimSpace(expr)
+	if rest, ok := strings.CutPrefix(trimmed, "@every "); ok {
+		d, err := time.ParseDuration(strings.TrimSpace(rest))
+		if err != nil || d <= 0 {
+			return nil, fmt.Errorf("invalid schedule %q: @every needs a positive duration", expr)
+		}
+		return Every(d), nil
+	}
+	return ParseCron(trimmed)
+}
+
+// ParseCron parses a five-field cron expression or @-descriptor, optionally
+// prefixed with CRON_TZ=<IANA zone>
+func ParseCron(expr string) (*CronSchedule, error) {
+	spec := strings.TrimSpace(expr)
+	location := time.UTC
+	if strings.HasPrefix(spec, tzPrefix) {
+		zone, rest, _ := strings.Cut(spec[len(tzPrefix):], " ")
+		loc, err := time.LoadLocation(zone)
+		if err != nil {
+			return nil, fmt.Errorf("invalid schedule %q: %w", expr, err)
+		}
+		location, spec = loc, strings.TrimSpace(rest)
+	}
+	if full, ok := descriptors[spec]; ok {
+		spec = full
+	}
+
+	fields := strings.Fields(spec)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("invalid schedule %q: expected 5 fields, got %d", expr, len(fields))
+	}
+
+	s := &CronSchedule{
+		expr:       strings.TrimSpace(expr),
+		location:   location,
+		anyDay:     fields[2] == "*",
+		anyWeekday: fields[4] == "*",
+	}
+
+	bounds := []struct {
+		name     string
+		min, max int
+		bits     *uint64
+	}{
+		{"minute", 0, 59, &s.minutes},
+		{"hour", 0, 23, &s.hours},
+		{"day of month", 1, 31, &s.days},
+		{"month", 1, 12, &s.months},
+		{"day of week", 0, 7, &s.weekdays},
+	}
+	for i, b := range bounds {
+		bits, err := parseField(fields[i], b.min, b.max)
+		if err != nil {
+			return nil, fmt.Errorf("invalid schedule %q: %s: %w", expr, b.name, err)
+		}
+		*b.bits = bits
+	}
+
+	// Sunday may be written as 7
+	if s.weekdays&(1<<7) != 0 {
+		s.weekdays |= 1
+	}
+	return s, nil
+}
+
+// MustParse is like Parse but panics on error; use it for package-level schedules
+func MustParse(expr string) Schedule {
+	s, err := Parse(expr)
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+// parseField turns one cron field into a bit set of the values it matches
+func parseField(field string, min, max int) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(field, ",") {
+		rangePart, stepPart, hasStep := strings.Cut(part, "/")
+
+		step := 1
+		if hasStep {
+			n, err := strconv.Atoi(stepPart)
+			if err != nil || n <= 0 {
+				return 0, fmt.Errorf("invalid step %q", stepPart)
+			}
+			step = n
+		}
+
+		low, high := min, max
+		switch {
+		case rangePart == "*":
+		case strings.Contains(rangePart, "-"):
+			lowPart, highPart, _ := strings.Cut(rangePart, "-")
+			var err error
+			if low, err = parseValue(lowPart, min, max); err != nil {
+				return 0, err
+			}
+			if high, err = parseValue(highPart, min, max); err != nil {
+				return 0, err
+			}
+			if low > high {
+				return 0, fmt.Errorf("invalid range %q", rangePart)
+			}
+		default:
+			value, err := parseValue(rangePart, min, max)
+			if err != nil {
+				return 0, err
+			}
+			low = value
+			if !hasStep {
+				high = value
+			}
+		}
+
+		for v := low; v <= high; v += step {
+			bits |= 1 << v
+		}
+	}
+	return bits, nil
+}
+
+// parseValue parses a single number within [min, max]
+func parseValue(value string, min, max int) (int, error) {
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value %q", value)
+	}
+	if n < min || n > max {
+		return 0, fmt.Errorf("value %d out of range [%d, %d]", n, min, max)
+	}
+	return n, nil
+}
+
+// maxSearchYears bounds the search for expressions that can never fire,
+// such as "0 0 30 2 *"
+const maxSearchYears = 5
+
+// Next returns the first matching minute strictly after t, in t's location.
+// The zero time is returned when the expression never matches.
+func (s *CronSchedule) Next(t time.Time) time.Time {
+	original := t.Location()
+	t = t.In(s.location).Truncate(time.Minute).Add(time.Minute)
+	limit := t.Year() + maxSearchYears
+
+	for t.Year() <= limit {
+		year, month, day := t.Date()
+		switch {
+		case s.months&(1<<uint(month)) == 0:
+			t = advance(t, time.Date(year, month+1, 1, 0, 0, 0, 0, s.location))
+		case !s.dayMatches(t):
+			t = advance(t, time.Date(year, month, day+1, 0, 0, 0, 0, s.location))
+		case s.hours&(1<<uint(t.Hour())) == 0:
+			t = advance(t, time.Date(year, month, day, t.Hour()+1, 0, 0, 0, s.location))
+		case s.minutes&(1<<uint(t.Minute())) == 0:
+			t = t.Add(time.Minute)
+		default:
+			return t.In(original)
+		}
+	}
+	return time.Time{}
+}
+
+// advance returns next, unless a DST gap made time.Date normalize it to an
+// instant at or before t; then it moves one hour forward to keep progressing
+func advance(t, next time.Time) time.Time {
+	if next.After(t) {
+		return next
+	}
+	return t.Add(time.Hour)
+}
+
+// dayMatches applies the cron rule for combining day-of-month and day-of-week
+func (s *CronSchedule) dayMatches(t time.Time) bool {
+	dayOK := s.days&(1<<uint(t.Day())) != 0
+	weekdayOK := s.weekdays&(1<<uint(t.Weekday())) != 0
+	if s.anyDay || s.anyWeekday {
+		return dayOK && weekdayOK
+	}
+	return dayOK || weekdayOK
+}
+
+// Location returns the timezone the cron fields are evaluated in
+func (s *CronSchedule) Location() *time.Location {
+	return s.location
+}
+
+// String returns the expression the schedule was parsed from
+func (s *CronSchedule) String() string {
+	return s.expr
+}
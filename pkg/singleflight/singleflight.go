@@ -0,0 +1,99 @@
+// Package singleflight collapses concurrent calls for the same key into one
+// execution whose result every caller shares, so a burst of identical cache
+// misses (500 requests asking for EUR/USD at once) reaches the backend once.
+package singleflight
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// Group runs at most one call per key at a time. The zero value is ready to
+// use; a Group must not be copied after first use.
+type Group[K comparable, V any] struct {
+	mu    sync.Mutex
+	calls map[K]*call[V]
+}
+
+// call is an in-flight or completed execution
+type call[V any] struct {
+	done    chan struct{}
+	value   V
+	err     error
+	callers int
+}
+
+// PanicError is returned to every caller of a call that panicked. fn runs
+// on its own goroutine, where an unrecovered panic would crash the process.
+type PanicError struct {
+	Value any
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("singleflight: call panicked: %v", e.Value)
+}
+
+// Do runs fn for key, or waits for the call already running for key, and
+// returns its result. shared reports whether the result went to more than
+// one caller; values such as slices and maps are then shared too and must
+// not be modified.
+//
+// fn runs detached from the cancellation of ctx, so one caller giving up
+// does not fail the others; a caller whose ctx is done stops waiting and
+// gets ctx.Err().
+func (g *Group[K, V]) Do(ctx context.Context, key K, fn func(context.Context) (V, error)) (value V, shared bool, err error) {
+	g.mu.Lock()
+	if g.calls == nil {
+		g.calls = make(map[K]*call[V])
+	}
+	if c, ok := g.calls[key]; ok {
+		c.callers++
+		g.mu.Unlock()
+		return g.wait(ctx, c)
+	}
+	c := &call[V]{done: make(chan struct{}), callers: 1}
+	g.calls[key] = c
+	g.mu.Unlock()
+
+	go g.run(context.WithoutCancel(ctx), key, c, fn)
+	return g.wait(ctx, c)
+}
+
+// run executes fn and releases the waiters
+func (g *Group[K, V]) run(ctx context.Context, key K, c *call[V], fn func(context.Context) (V, error)) {
+	defer func() {
+		if r := recover(); r != nil {
+			c.err = &PanicError{Value: r}
+		}
+		g.mu.Lock()
+		if g.calls[key] == c {
+			delete(g.calls, key)
+		}
+		g.mu.Unlock()
+		close(c.done)
+	}()
+	c.value, c.err = fn(ctx)
+}
+
+// wait blocks until c completes or ctx is done
+func (g *Group[K, V]) wait(ctx context.Context, c *call[V]) (V, bool, error) {
+	select {
+	case <-c.done:
+		g.mu.Lock()
+		shared := c.callers > 1
+		g.mu.Unlock()
+		return c.value, shared, c.err
+	case <-ctx.Done():
+		var zero V
+		return zero, false, ctx.Err()
+	}
+}
+
+// Forget makes the next Do for key start a new call instead of joining the
+// one in flight, e.g. after the underlying value was invalidated
+func (g *Group[K, V]) Forget(key K) {
+	g.mu.Lock()
+	delete(g.calls, key)
+	g.mu.Unlock()
+}
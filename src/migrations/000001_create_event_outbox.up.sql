@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS event_outbox (
+    id             UUID PRIMARY KEY,
+    name           VARCHAR(255) NOT NULL,
+    aggregate_type VARCHAR(255) NOT NULL,
+    aggregate_id   VARCHAR(255) NOT NULL,
+    occurred_at    BIGINT       NOT NULL,
+    timezone       VARCHAR(64)  NOT NULL,
+    payload        JSONB        NOT NULL,
+    created_at     TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    published_at   TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_event_outbox_unpublished
+    ON event_outbox (created_at)
+    WHERE published_at IS NULL;
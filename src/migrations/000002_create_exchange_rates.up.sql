@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS exchange_rates (
+    id          BIGSERIAL    PRIMARY KEY,
+    provider    VARCHAR(64)  NOT NULL,
+    base_code   VARCHAR(3)   NOT NULL,
+    quote_code  VARCHAR(3)   NOT NULL,
+    rate        BIGINT       NOT NULL,
+    scale       SMALLINT     NOT NULL,
+    observed_at BIGINT       NOT NULL,
+    fetched_at  TIMESTAMPTZ  NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_exchange_rates_pair
+    ON exchange_rates (base_code, quote_code, observed_at DESC);
@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS erasure_audit (
+    id           UUID         PRIMARY KEY,
+    subject_id   VARCHAR(255) NOT NULL,
+    strategy     VARCHAR(32)  NOT NULL,
+    reason       VARCHAR(255) NOT NULL,
+    requested_by VARCHAR(255) NOT NULL,
+    status       VARCHAR(32)  NOT NULL,
+    requested_at TIMESTAMPTZ  NOT NULL,
+    completed_at TIMESTAMPTZ  NOT NULL,
+    results      JSONB        NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_erasure_audit_subject
+    ON erasure_audit (subject_id, requested_at);
@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS consents (
+    subject_id       VARCHAR(255) NOT NULL,
+    purpose          VARCHAR(64)  NOT NULL,
+    channel          VARCHAR(16)  NOT NULL,
+    granted_at       BIGINT,
+    granted_timezone VARCHAR(64),
+    revoked_at       BIGINT,
+    revoked_timezone VARCHAR(64),
+    source           VARCHAR(255) NOT NULL DEFAULT '',
+    updated_at       TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (subject_id, purpose, channel)
+);
+
+CREATE INDEX IF NOT EXISTS idx_consents_granted
+    ON consents (purpose, channel, subject_id)
+    WHERE granted_at IS NOT NULL AND revoked_at IS NULL;
@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS api_usage (
+    consumer   VARCHAR(255) NOT NULL,
+    period     VARCHAR(10)  NOT NULL,
+    endpoint   VARCHAR(512) NOT NULL,
+    requests   BIGINT       NOT NULL DEFAULT 0,
+    updated_at TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (consumer, period, endpoint)
+);
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS i18n_holidays;
+DROP TABLE IF EXISTS i18n_translations;
+DROP TABLE IF EXISTS i18n_rate_overrides;
+DROP TABLE IF EXISTS i18n_currencies;
@@ -0,0 +1,38 @@
+CREATE TABLE IF NOT EXISTS i18n_currencies (
+    code           VARCHAR(3)   PRIMARY KEY,
+    symbol         VARCHAR(16)  NOT NULL,
+    name           VARCHAR(255) NOT NULL,
+    decimal_places SMALLINT     NOT NULL,
+    enabled        BOOLEAN      NOT NULL DEFAULT TRUE,
+    updated_by     VARCHAR(255) NOT NULL DEFAULT '',
+    updated_at     TIMESTAMPTZ  NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS i18n_rate_overrides (
+    base_code  VARCHAR(3)   NOT NULL,
+    quote_code VARCHAR(3)   NOT NULL,
+    rate       VARCHAR(64)  NOT NULL,
+    reason     TEXT         NOT NULL DEFAULT '',
+    expires_at TIMESTAMPTZ,
+    updated_by VARCHAR(255) NOT NULL DEFAULT '',
+    updated_at TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (base_code, quote_code)
+);
+
+CREATE TABLE IF NOT EXISTS i18n_translations (
+    message_key VARCHAR(255) NOT NULL,
+    locale      VARCHAR(35)  NOT NULL,
+    text        TEXT         NOT NULL,
+    updated_by  VARCHAR(255) NOT NULL DEFAULT '',
+    updated_at  TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (message_key, locale)
+);
+
+CREATE TABLE IF NOT EXISTS i18n_holidays (
+    calendar     VARCHAR(64)  NOT NULL,
+    holiday_date DATE         NOT NULL,
+    name         VARCHAR(255) NOT NULL,
+    updated_by   VARCHAR(255) NOT NULL DEFAULT '',
+    updated_at   TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (calendar, holiday_date)
+);
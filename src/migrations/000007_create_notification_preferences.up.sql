@@ -0,0 +1,5 @@
+CREATE TABLE IF NOT EXISTS notification_preferences (
+    recipient_id VARCHAR(255) PRIMARY KEY,
+    preferences  JSONB        NOT NULL,
+    updated_at   TIMESTAMPTZ  NOT NULL DEFAULT NOW()
+);
@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS push_devices (
+    token        TEXT         PRIMARY KEY,
+    recipient_id VARCHAR(255) NOT NULL,
+    platform     VARCHAR(16)  NOT NULL,
+    locale       VARCHAR(35)  NOT NULL DEFAULT '',
+    created_at   TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    last_seen_at TIMESTAMPTZ  NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_push_devices_recipient_id ON push_devices (recipient_id);
@@ -0,0 +1,17 @@
+CREATE TABLE IF NOT EXISTS sagas (
+    id              UUID         PRIMARY KEY,
+    saga            VARCHAR(255) NOT NULL,
+    status          VARCHAR(16)  NOT NULL,
+    step            INTEGER      NOT NULL DEFAULT 0,
+    data            JSONB        NOT NULL DEFAULT '{}',
+    error           TEXT         NOT NULL DEFAULT '',
+    attempts        INTEGER      NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    locked_until    TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    created_at      TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    updated_at      TIMESTAMPTZ  NOT NULL DEFAULT NOW()
+);
+
+-- Resume only looks at unfinished instances
+CREATE INDEX IF NOT EXISTS idx_sagas_due ON sagas (next_attempt_at)
+    WHERE status IN ('running', 'compensating');
@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS processed_messages (
+    message_id   VARCHAR(255) NOT NULL,
+    handler      VARCHAR(255) NOT NULL,
+    processed_at TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (message_id, handler)
+);
+
+CREATE INDEX IF NOT EXISTS idx_processed_messages_processed_at ON processed_messages (processed_at);
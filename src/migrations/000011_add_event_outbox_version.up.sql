@@ -0,0 +1,2 @@
+-- Schema version of the payload, for consumers to upcast older events
+ALTER TABLE event_outbox ADD COLUMN IF NOT EXISTS version INTEGER NOT NULL DEFAULT 1;
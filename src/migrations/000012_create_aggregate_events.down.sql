@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS aggregate_snapshots;
+DROP TABLE IF EXISTS aggregate_events;
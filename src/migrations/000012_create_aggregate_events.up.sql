@@ -0,0 +1,25 @@
+-- Append-only events of event-sourced aggregates. Each aggregate's events
+-- are numbered from 1; the primary key rejects concurrent appends.
+CREATE TABLE IF NOT EXISTS aggregate_events (
+    aggregate_type VARCHAR(255) NOT NULL,
+    aggregate_id   VARCHAR(255) NOT NULL,
+    version        INTEGER      NOT NULL,
+    position       BIGSERIAL    NOT NULL UNIQUE,
+    id             UUID         NOT NULL UNIQUE,
+    name           VARCHAR(255) NOT NULL,
+    schema_version INTEGER      NOT NULL DEFAULT 1,
+    occurred_at    BIGINT       NOT NULL,
+    timezone       VARCHAR(64)  NOT NULL,
+    payload        JSONB        NOT NULL,
+    recorded_at    TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (aggregate_type, aggregate_id, version)
+);
+
+CREATE TABLE IF NOT EXISTS aggregate_snapshots (
+    aggregate_type VARCHAR(255) NOT NULL,
+    aggregate_id   VARCHAR(255) NOT NULL,
+    version        INTEGER      NOT NULL,
+    state          JSONB        NOT NULL,
+    taken_at       TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (aggregate_type, aggregate_id)
+);
@@ -0,0 +1,8 @@
+-- How far each read-model projection got through aggregate_events, and
+-- whether a rebuild was requested for the worker to carry out.
+CREATE TABLE IF NOT EXISTS projection_checkpoints (
+    name       VARCHAR(255) PRIMARY KEY,
+    position   BIGINT       NOT NULL DEFAULT 0,
+    rebuild    BOOLEAN      NOT NULL DEFAULT FALSE,
+    updated_at TIMESTAMPTZ  NOT NULL DEFAULT NOW()
+);
@@ -0,0 +1,15 @@
+-- Append-only audit trail of entity writes made through repository hooks,
+-- with the entity's redacted JSON before and after the write.
+CREATE TABLE IF NOT EXISTS audit_log (
+    id         UUID         PRIMARY KEY,
+    entity     VARCHAR(255) NOT NULL,
+    entity_id  VARCHAR(255) NOT NULL,
+    operation  VARCHAR(16)  NOT NULL,
+    actor      VARCHAR(255) NOT NULL DEFAULT '',
+    old_value  JSONB,
+    new_value  JSONB,
+    fields     TEXT[]       NOT NULL DEFAULT '{}',
+    changed_at TIMESTAMPTZ  NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_audit_log_entity ON audit_log (entity, entity_id, changed_at);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_event_outbox_published_at;
+DROP INDEX IF EXISTS idx_audit_log_changed_at;
@@ -0,0 +1,6 @@
+-- Retention policies purge the oldest rows by these timestamps
+CREATE INDEX IF NOT EXISTS idx_audit_log_changed_at ON audit_log (changed_at);
+
+CREATE INDEX IF NOT EXISTS idx_event_outbox_published_at
+    ON event_outbox (published_at)
+    WHERE published_at IS NOT NULL;
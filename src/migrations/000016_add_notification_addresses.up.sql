@@ -0,0 +1,3 @@
+-- Recipients' addresses sealed with fieldcrypt; NULL while they are still
+-- kept in the preferences JSON
+ALTER TABLE notification_preferences ADD COLUMN IF NOT EXISTS addresses BYTEA;
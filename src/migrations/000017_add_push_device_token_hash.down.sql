@@ -0,0 +1,6 @@
+-- Sealed tokens cannot be decrypted here; their devices register again
+DELETE FROM push_devices WHERE token IS NULL;
+ALTER TABLE push_devices DROP CONSTRAINT IF EXISTS push_devices_pkey;
+ALTER TABLE push_devices ADD PRIMARY KEY (token);
+ALTER TABLE push_devices DROP COLUMN IF EXISTS sealed_token;
+ALTER TABLE push_devices DROP COLUMN IF EXISTS token_hash;
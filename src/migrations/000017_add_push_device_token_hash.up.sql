@@ -0,0 +1,10 @@
+-- Devices are looked up by the SHA-256 of their token, so the token itself
+-- can be sealed with fieldcrypt in sealed_token and left NULL in token
+ALTER TABLE push_devices ADD COLUMN IF NOT EXISTS token_hash CHAR(64);
+ALTER TABLE push_devices ADD COLUMN IF NOT EXISTS sealed_token BYTEA;
+UPDATE push_devices SET token_hash = encode(sha256(convert_to(token, 'UTF8')), 'hex') WHERE token_hash IS NULL;
+
+ALTER TABLE push_devices DROP CONSTRAINT IF EXISTS push_devices_pkey;
+ALTER TABLE push_devices ALTER COLUMN token DROP NOT NULL;
+ALTER TABLE push_devices ALTER COLUMN token_hash SET NOT NULL;
+ALTER TABLE push_devices ADD PRIMARY KEY (token_hash);
@@ -0,0 +1,63 @@
+package cache_test
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"golang-arch/internal/shared/cache"
+	"golang-arch/internal/shared/testutil"
+)
+
+func TestLoader_CollapsesMisses(t *testing.T) {
+	server, client := testutil.NewRedis(t)
+	loader := cache.NewLoader[[]string](cache.NewRedisCache(client, cache.WithPrefix("regions:")), time.Hour)
+	ctx := context.Background()
+
+	var loads atomic.Int32
+	load := func(context.Context) ([]string, error) {
+		loads.Add(1)
+		time.Sleep(20 * time.Millisecond)
+		return []string{"ID-JK", "ID-JB"}, nil
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			value, err := loader.Get(ctx, "ID", load)
+			assert.NoError(t, err)
+			assert.Equal(t, []string{"ID-JK", "ID-JB"}, value)
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, int32(1), loads.Load())
+	assert.Equal(t, time.Hour, server.TTL("regions:ID"))
+
+	// Served from Redis until invalidated
+	_, err := loader.Get(ctx, "ID", load)
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), loads.Load())
+
+	require.NoError(t, loader.Invalidate(ctx, "ID"))
+	_, err = loader.Get(ctx, "ID", load)
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), loads.Load())
+}
+
+func TestLoader_LoadError(t *testing.T) {
+	server, client := testutil.NewRedis(t)
+	loader := cache.NewLoader[int](cache.NewRedisCache(client), 0)
+
+	_, err := loader.Get(context.Background(), "n", func(context.Context) (int, error) {
+		return 0, assert.AnError
+	})
+	assert.ErrorIs(t, err, assert.AnError)
+	assert.False(t, server.Exists("n"), "failed loads are not cached")
+}
@@ -0,0 +1,66 @@
+package cache_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"golang-arch/internal/shared/cache"
+	"golang-arch/internal/shared/codec"
+	i18n "golang-arch/internal/shared/domain/internationalization"
+	"golang-arch/internal/shared/testutil"
+)
+
+func TestRedisCache_RoundTrip(t *testing.T) {
+	server, client := testutil.NewRedis(t)
+	ctx := context.Background()
+
+	for _, c := range []codec.Codec{codec.MsgPack, codec.CBOR, codec.JSON} {
+		t.Run(c.Name(), func(t *testing.T) {
+			rc := cache.NewRedisCache(client, cache.WithCodec(c), cache.WithPrefix("rates:"))
+
+			price, err := i18n.MakeMoney(9200, "EUR")
+			require.NoError(t, err)
+			require.NoError(t, rc.Set(ctx, "EUR", price, time.Minute))
+			assert.True(t, server.Exists("rates:EUR"))
+
+			var cached i18n.Money
+			found, err := rc.Get(ctx, "EUR", &cached)
+			require.NoError(t, err)
+			assert.True(t, found)
+			assert.Equal(t, price, cached)
+
+			require.NoError(t, rc.Delete(ctx, "EUR"))
+			found, err = rc.Get(ctx, "EUR", &cached)
+			require.NoError(t, err)
+			assert.False(t, found)
+		})
+	}
+}
+
+func TestRedisCache_DefaultsToCompactMsgPack(t *testing.T) {
+	server, client := testutil.NewRedis(t)
+	rc := cache.NewRedisCache(client)
+
+	ldt, err := i18n.MakeLocalizedDateTime(1703520000, "Asia/Tokyo")
+	require.NoError(t, err)
+	require.NoError(t, rc.Set(context.Background(), "due", ldt, 0))
+
+	stored, err := server.Get("due")
+	require.NoError(t, err)
+	assert.Less(t, len(stored), 20, "expected the [epoch, tz] compact form")
+	assert.Equal(t, time.Duration(0), server.TTL("due"))
+}
+
+func TestRedisCache_DecodeError(t *testing.T) {
+	server, client := testutil.NewRedis(t)
+	require.NoError(t, server.Set("bad", "\xc1"))
+
+	var money i18n.Money
+	found, err := cache.NewRedisCache(client).Get(context.Background(), "bad", &money)
+	assert.Error(t, err)
+	assert.False(t, found)
+}
@@ -0,0 +1,187 @@
+package chaos_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"golang-arch/internal/shared/chaos"
+	"golang-arch/internal/shared/testutil"
+	"golang-arch/pkg/clock"
+	"golang-arch/pkg/metrics"
+)
+
+// always makes every rate hit
+func always() float64 { return 0 }
+
+// never makes every rate miss
+func never() float64 { return 0.999 }
+
+func newInjector(t *testing.T, faults map[string]chaos.Fault, options ...chaos.Option) *chaos.Injector {
+	t.Helper()
+	injector, err := chaos.NewInjector(faults, options...)
+	require.NoError(t, err)
+	return injector
+}
+
+func TestFaultValidate(t *testing.T) {
+	assert.NoError(t, chaos.Fault{LatencyRate: 1, Latency: time.Second, ErrorRate: 0.5}.Validate())
+	assert.Error(t, chaos.Fault{ErrorRate: 1.5}.Validate())
+	assert.Error(t, chaos.Fault{LatencyRate: -0.1}.Validate())
+	assert.Error(t, chaos.Fault{Latency: -time.Second}.Validate())
+
+	_, err := chaos.NewInjector(map[string]chaos.Fault{chaos.TargetHTTP: {ErrorRate: 2}})
+	assert.ErrorContains(t, err, "invalid http fault")
+}
+
+func TestInjectErrors(t *testing.T) {
+	registry := metrics.NewRegistry()
+	counter, err := registry.Counter("chaos.faults", "Injected faults", "1")
+	require.NoError(t, err)
+	injector := newInjector(t, map[string]chaos.Fault{chaos.TargetDatabase: {ErrorRate: 0.1}},
+		chaos.WithRandom(always), chaos.WithFaultsCounter(counter))
+
+	err = injector.Inject(context.Background(), chaos.TargetDatabase, "query")
+	assert.ErrorIs(t, err, chaos.ErrInjected)
+	assert.ErrorContains(t, err, "database query")
+	assert.NoError(t, injector.Inject(context.Background(), chaos.TargetRedis, "get"))
+
+	series := registry.Snapshot()[0].Series
+	require.Len(t, series, 1)
+	assert.Equal(t, metrics.Labels{"target": "database", "kind": "error"}, series[0].Labels)
+	assert.Equal(t, float64(1), series[0].Value)
+
+	quiet := newInjector(t, map[string]chaos.Fault{chaos.TargetDatabase: {ErrorRate: 0.1}}, chaos.WithRandom(never))
+	assert.NoError(t, quiet.Inject(context.Background(), chaos.TargetDatabase, "query"))
+}
+
+func TestInjectLatency(t *testing.T) {
+	clk := clock.NewFake(time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC))
+	injector := newInjector(t, map[string]chaos.Fault{chaos.TargetRedis: {LatencyRate: 1, Latency: time.Second}},
+		chaos.WithClock(clk), chaos.WithRandom(always))
+
+	done := make(chan error, 1)
+	go func() { done <- injector.Inject(context.Background(), chaos.TargetRedis, "get") }()
+
+	require.Eventually(t, func() bool { return clk.PendingTimers() == 1 }, time.Second, time.Millisecond)
+	select {
+	case <-done:
+		t.Fatal("call returned before the delay")
+	default:
+	}
+	clk.Advance(time.Second)
+	assert.NoError(t, <-done)
+}
+
+func TestInjectLatencyCanceled(t *testing.T) {
+	clk := clock.NewFake(time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC))
+	injector := newInjector(t, map[string]chaos.Fault{chaos.TargetRedis: {LatencyRate: 1, Latency: time.Minute}},
+		chaos.WithClock(clk), chaos.WithRandom(always))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.ErrorIs(t, injector.Inject(ctx, chaos.TargetRedis, "get"), context.Canceled)
+}
+
+func TestNilInjector(t *testing.T) {
+	var injector *chaos.Injector
+	assert.NoError(t, injector.Inject(context.Background(), chaos.TargetHTTP, "GET /"))
+	assert.Equal(t, chaos.Fault{}, injector.Fault(chaos.TargetHTTP))
+
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+	base := chaos.DriverConnector(db.Driver(), "")
+	assert.Equal(t, base, injector.Connector(base))
+	mock.ExpectClose()
+}
+
+func TestMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	injector := newInjector(t, map[string]chaos.Fault{chaos.TargetHTTP: {ErrorRate: 0.5}}, chaos.WithRandom(always))
+
+	router := gin.New()
+	router.Use(injector.Middleware("/health"))
+	router.GET("/health", func(c *gin.Context) { c.Status(http.StatusOK) })
+	router.GET("/api/v1/users", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/users", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.Contains(t, w.Body.String(), "injected fault")
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/health", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestConnector(t *testing.T) {
+	mockDB, mock, err := sqlmock.NewWithDSN("chaos_connector")
+	require.NoError(t, err)
+	defer mockDB.Close()
+
+	injector := newInjector(t, map[string]chaos.Fault{chaos.TargetDatabase: {ErrorRate: 1}}, chaos.WithRandom(always))
+	db := sql.OpenDB(injector.Connector(chaos.DriverConnector(mockDB.Driver(), "chaos_connector")))
+	defer db.Close()
+
+	// Pings are left alone so readiness probes keep working
+	mock.ExpectPing()
+	require.NoError(t, db.PingContext(context.Background()))
+
+	_, err = db.ExecContext(context.Background(), "DELETE FROM users")
+	assert.ErrorIs(t, err, chaos.ErrInjected)
+	_, err = db.QueryContext(context.Background(), "SELECT 1")
+	assert.ErrorIs(t, err, chaos.ErrInjected)
+	_, err = db.BeginTx(context.Background(), nil)
+	assert.ErrorIs(t, err, chaos.ErrInjected)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestConnectorPassesThrough(t *testing.T) {
+	mockDB, mock, err := sqlmock.NewWithDSN("chaos_passthrough")
+	require.NoError(t, err)
+	defer mockDB.Close()
+
+	injector := newInjector(t, map[string]chaos.Fault{chaos.TargetDatabase: {ErrorRate: 0.5}}, chaos.WithRandom(never))
+	db := sql.OpenDB(injector.Connector(chaos.DriverConnector(mockDB.Driver(), "chaos_passthrough")))
+	defer db.Close()
+
+	mock.ExpectExec("DELETE FROM users").WillReturnResult(sqlmock.NewResult(0, 2))
+	result, err := db.ExecContext(context.Background(), "DELETE FROM users")
+	require.NoError(t, err)
+	affected, err := result.RowsAffected()
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), affected)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestInstrumentRedis(t *testing.T) {
+	server, client := testutil.NewRedis(t)
+
+	injector := newInjector(t, map[string]chaos.Fault{chaos.TargetRedis: {ErrorRate: 1}}, chaos.WithRandom(always))
+	injector.Instrument(client)
+	ctx := context.Background()
+
+	require.NoError(t, client.Ping(ctx).Err())
+
+	err := client.Set(ctx, "key", "value", 0).Err()
+	assert.ErrorIs(t, err, chaos.ErrInjected)
+	assert.False(t, server.Exists("key"))
+
+	_, err = client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Set(ctx, "key", "value", 0)
+		return nil
+	})
+	assert.True(t, errors.Is(err, chaos.ErrInjected))
+	assert.False(t, server.Exists("key"))
+}
@@ -0,0 +1,160 @@
+package consent_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"golang-arch/internal/shared/config"
+	"golang-arch/internal/shared/consent"
+	"golang-arch/internal/shared/rbac"
+)
+
+// Bearer tokens of the test principals: an operator and subject u1
+const (
+	adminToken   = "admin-token"
+	subjectToken = "u1-token"
+)
+
+func newRouter(t *testing.T, service *consent.Service) *gin.Engine {
+	t.Helper()
+	authz, err := rbac.NewAuthorizer(config.RBACConfig{
+		Principals: []config.PrincipalConfig{
+			{Name: "ops", Token: adminToken, Roles: []string{"privacy-admin"}},
+			{Name: "u1", Token: subjectToken},
+		},
+		Roles: map[string][]string{"privacy-admin": {string(consent.PermissionAdmin)}},
+	})
+	require.NoError(t, err)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	consent.NewHandler(service, authz).Register(router.Group("/api/v1"))
+	return router
+}
+
+func do(router *gin.Engine, method, path, body string) *httptest.ResponseRecorder {
+	return doAs(router, adminToken, method, path, body)
+}
+
+func doAs(router *gin.Engine, token, method, path, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestHandler(t *testing.T) {
+	service, _ := newService()
+	router := newRouter(t, service)
+
+	rec := do(router, http.MethodPut, "/api/v1/consents/u1/marketing/email", `{"granted":true,"source":"preference_center","timezone":"Europe/Paris"}`)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var granted struct {
+		Data struct {
+			Purpose   string `json:"purpose"`
+			GrantedAt *struct {
+				Timezone struct {
+					ID string `json:"id"`
+				} `json:"timezone"`
+			} `json:"granted_at"`
+		} `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &granted))
+	assert.Equal(t, "marketing", granted.Data.Purpose)
+	require.NotNil(t, granted.Data.GrantedAt)
+
+	rec = do(router, http.MethodPut, "/api/v1/consents/u2/marketing/email", `{"granted":true}`)
+	require.Equal(t, http.StatusOK, rec.Code)
+	rec = do(router, http.MethodPut, "/api/v1/consents/u2/marketing/email", `{"granted":false}`)
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), "revoked_at")
+
+	rec = do(router, http.MethodGet, "/api/v1/consents/u1/marketing/email", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), "preference_center")
+
+	rec = do(router, http.MethodGet, "/api/v1/consents/u1", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"subject_id":"u1"`)
+
+	rec = do(router, http.MethodGet, "/api/v1/consents?purpose=marketing&channel=email&limit=1", "")
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Contains(t, rec.Body.String(), `"subjects":["u1"]`)
+	assert.Contains(t, rec.Body.String(), `"next":"u1"`)
+
+	rec = do(router, http.MethodGet, "/api/v1/consents?purpose=marketing&channel=email&after=u1", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"subjects":[]`, "u2 revoked")
+}
+
+func TestHandlerValidation(t *testing.T) {
+	service, _ := newService()
+	router := newRouter(t, service)
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		body   string
+	}{
+		{"missing granted", http.MethodPut, "/api/v1/consents/u1/marketing/email", `{}`},
+		{"bad channel", http.MethodPut, "/api/v1/consents/u1/marketing/fax", `{"granted":true}`},
+		{"bad timezone", http.MethodPut, "/api/v1/consents/u1/marketing/email", `{"granted":true,"timezone":"Mars/Olympus"}`},
+		{"bad purpose", http.MethodGet, "/api/v1/consents/u1/Marketing!/email", ""},
+		{"bad limit", http.MethodGet, "/api/v1/consents?purpose=marketing&channel=email&limit=x", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := do(router, tt.method, tt.path, tt.body)
+			assert.Equal(t, http.StatusBadRequest, rec.Code, rec.Body.String())
+		})
+	}
+}
+
+func TestHandlerAuthorization(t *testing.T) {
+	service, _ := newService()
+	router := newRouter(t, service)
+
+	tests := []struct {
+		name   string
+		token  string
+		method string
+		path   string
+		body   string
+		status int
+	}{
+		{"anonymous write", "", http.MethodPut, "/api/v1/consents/u1/marketing/email", `{"granted":true}`, http.StatusUnauthorized},
+		{"anonymous read", "", http.MethodGet, "/api/v1/consents/u1", "", http.StatusUnauthorized},
+		{"unknown token", "stolen", http.MethodGet, "/api/v1/consents/u1", "", http.StatusUnauthorized},
+		{"subject writes own", subjectToken, http.MethodPut, "/api/v1/consents/u1/marketing/email", `{"granted":true}`, http.StatusOK},
+		{"subject reads own", subjectToken, http.MethodGet, "/api/v1/consents/u1", "", http.StatusOK},
+		{"subject reads own decision", subjectToken, http.MethodGet, "/api/v1/consents/u1/marketing/email", "", http.StatusOK},
+		{"subject writes another", subjectToken, http.MethodPut, "/api/v1/consents/u2/marketing/email", `{"granted":true}`, http.StatusForbidden},
+		{"subject reads another", subjectToken, http.MethodGet, "/api/v1/consents/u2", "", http.StatusForbidden},
+		{"subject lists subjects", subjectToken, http.MethodGet, "/api/v1/consents?purpose=marketing&channel=email", "", http.StatusForbidden},
+		{"admin writes any", adminToken, http.MethodPut, "/api/v1/consents/u2/marketing/email", `{"granted":true}`, http.StatusOK},
+		{"admin lists subjects", adminToken, http.MethodGet, "/api/v1/consents?purpose=marketing&channel=email", "", http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := doAs(router, tt.token, tt.method, tt.path, tt.body)
+			assert.Equal(t, tt.status, rec.Code, rec.Body.String())
+		})
+	}
+
+	allowed, err := service.Allowed(context.Background(), "u2", consent.PurposeMarketing, consent.ChannelEmail)
+	require.NoError(t, err)
+	assert.True(t, allowed, "only the admin's grant for u2 was recorded")
+}
@@ -0,0 +1,205 @@
+package consent_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"golang-arch/internal/shared/consent"
+	"golang-arch/internal/shared/domain/domainerror"
+	i18n "golang-arch/internal/shared/domain/internationalization"
+	"golang-arch/internal/shared/domain/validation"
+	"golang-arch/internal/shared/erasure"
+	"golang-arch/pkg/clock"
+)
+
+var start = time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+func newService() (*consent.Service, *clock.Fake) {
+	clk := clock.NewFake(start)
+	return consent.NewService(consent.NewMemoryStore(), consent.WithClock(clk)), clk
+}
+
+func marketingEmail(subject string) consent.Decision {
+	return consent.Decision{SubjectID: subject, Purpose: consent.PurposeMarketing, Channel: consent.ChannelEmail, Source: "signup_form"}
+}
+
+func TestGrantAndRevoke(t *testing.T) {
+	service, clk := newService()
+	ctx := context.Background()
+
+	allowed, err := service.Allowed(ctx, "u1", consent.PurposeMarketing, consent.ChannelEmail)
+	require.NoError(t, err)
+	assert.False(t, allowed, "no decision means no consent")
+
+	decision := marketingEmail("u1")
+	decision.Timezone = "Asia/Jakarta"
+	granted, err := service.Grant(ctx, decision)
+	require.NoError(t, err)
+	assert.True(t, granted.Granted())
+	assert.Equal(t, start.Unix(), granted.GrantedAt.Time.Epoch)
+	assert.Equal(t, "Asia/Jakarta", granted.GrantedAt.Timezone.ID)
+	assert.Equal(t, "signup_form", granted.Source)
+
+	allowed, err = service.Allowed(ctx, "u1", consent.PurposeMarketing, consent.ChannelEmail)
+	require.NoError(t, err)
+	assert.True(t, allowed)
+	allowed, err = service.Allowed(ctx, "u1", consent.PurposeMarketing, consent.ChannelSMS)
+	require.NoError(t, err)
+	assert.False(t, allowed, "consent is per channel")
+
+	clk.Advance(time.Hour)
+	revoked, err := service.Revoke(ctx, consent.Decision{SubjectID: "u1", Purpose: consent.PurposeMarketing, Channel: consent.ChannelEmail, Source: "unsubscribe_link"})
+	require.NoError(t, err)
+	assert.False(t, revoked.Granted())
+	assert.Equal(t, start.Unix(), revoked.GrantedAt.Time.Epoch, "the original grant is kept")
+	assert.Equal(t, start.Add(time.Hour).Unix(), revoked.RevokedAt.Time.Epoch)
+	assert.Equal(t, "UTC", revoked.RevokedAt.Timezone.ID)
+	assert.ErrorIs(t, service.Require(ctx, "u1", consent.PurposeMarketing, consent.ChannelEmail), domainerror.Forbidden)
+
+	clk.Advance(time.Hour)
+	regranted, err := service.Grant(ctx, marketingEmail("u1"))
+	require.NoError(t, err)
+	assert.True(t, regranted.Granted())
+	assert.Nil(t, regranted.RevokedAt)
+	assert.NoError(t, service.Require(ctx, "u1", consent.PurposeMarketing, consent.ChannelEmail))
+
+	// Refusals without a grant are recorded
+	refused, err := service.Revoke(ctx, consent.Decision{SubjectID: "u2", Purpose: consent.PurposeAnalytics, Channel: consent.ChannelPush})
+	require.NoError(t, err)
+	assert.Nil(t, refused.GrantedAt)
+	assert.NotNil(t, refused.RevokedAt)
+}
+
+func TestDefaultTimezone(t *testing.T) {
+	defaults, err := i18n.NewLocaleDefaults("de-DE", "Europe/Berlin", "EUR", nil)
+	require.NoError(t, err)
+	service := consent.NewService(consent.NewMemoryStore(),
+		consent.WithClock(clock.NewFake(start)),
+		consent.WithLocaleDefaults(*defaults),
+	)
+
+	granted, err := service.Grant(context.Background(), marketingEmail("u1"))
+	require.NoError(t, err)
+	assert.Equal(t, "Europe/Berlin", granted.GrantedAt.Timezone.ID)
+}
+
+func TestDecisionValidation(t *testing.T) {
+	service, _ := newService()
+	ctx := context.Background()
+
+	_, err := service.Grant(ctx, consent.Decision{Purpose: "Marketing!", Channel: "fax", Timezone: "Mars/Olympus"})
+	fields := validation.FromError(err).ByField()
+	for _, field := range []string{"subject_id", "purpose", "channel", "timezone"} {
+		assert.Contains(t, fields, field)
+	}
+}
+
+func TestListAndSubjects(t *testing.T) {
+	service, _ := newService()
+	ctx := context.Background()
+
+	for _, subject := range []string{"u3", "u1", "u2", "u4"} {
+		_, err := service.Grant(ctx, marketingEmail(subject))
+		require.NoError(t, err)
+	}
+	_, err := service.Revoke(ctx, marketingEmail("u2"))
+	require.NoError(t, err)
+	_, err = service.Grant(ctx, consent.Decision{SubjectID: "u1", Purpose: consent.PurposeAnalytics, Channel: consent.ChannelPush})
+	require.NoError(t, err)
+
+	consents, err := service.List(ctx, "u1")
+	require.NoError(t, err)
+	require.Len(t, consents, 2)
+	assert.Equal(t, consent.PurposeAnalytics, consents[0].Purpose)
+	assert.Equal(t, consent.PurposeMarketing, consents[1].Purpose)
+
+	page, err := service.Subjects(ctx, consent.PurposeMarketing, consent.ChannelEmail, "", 2)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"u1", "u3"}, page)
+	page, err = service.Subjects(ctx, consent.PurposeMarketing, consent.ChannelEmail, "u3", 2)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"u4"}, page)
+
+	_, err = service.Subjects(ctx, consent.PurposeMarketing, "fax", "", 2)
+	assert.Error(t, err)
+}
+
+func TestGuard(t *testing.T) {
+	service, _ := newService()
+	ctx := context.Background()
+
+	sent := 0
+	send := func(context.Context) error {
+		sent++
+		return nil
+	}
+	job := service.Guard("u1", consent.PurposeMarketing, consent.ChannelSMS, send)
+
+	require.NoError(t, job(ctx))
+	assert.Equal(t, 0, sent, "blocked without consent")
+
+	_, err := service.Grant(ctx, consent.Decision{SubjectID: "u1", Purpose: consent.PurposeMarketing, Channel: consent.ChannelSMS})
+	require.NoError(t, err)
+	require.NoError(t, job(ctx))
+	assert.Equal(t, 1, sent)
+
+	// Checked at run time, so a later revocation still blocks
+	_, err = service.Revoke(ctx, consent.Decision{SubjectID: "u1", Purpose: consent.PurposeMarketing, Channel: consent.ChannelSMS})
+	require.NoError(t, err)
+	require.NoError(t, job(ctx))
+	assert.Equal(t, 1, sent)
+
+	failing := errors.New("smtp down")
+	_, err = service.Grant(ctx, consent.Decision{SubjectID: "u1", Purpose: consent.PurposeMarketing, Channel: consent.ChannelEmail})
+	require.NoError(t, err)
+	err = service.Guard("u1", consent.PurposeMarketing, consent.ChannelEmail, func(context.Context) error { return failing })(ctx)
+	assert.ErrorIs(t, err, failing)
+}
+
+func TestPostgresStore(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+	store := consent.NewPostgresStore(db)
+	service := consent.NewService(store, consent.WithClock(clock.NewFake(start)))
+	ctx := context.Background()
+	columns := []string{"subject_id", "purpose", "channel", "granted_at", "granted_timezone", "revoked_at", "revoked_timezone", "source"}
+
+	mock.ExpectQuery("SELECT (.+) FROM consents WHERE subject_id").
+		WithArgs("u1", "marketing", "email").
+		WillReturnRows(sqlmock.NewRows(columns).AddRow("u1", "marketing", "email", start.Unix()-60, "Europe/Berlin", nil, nil, "signup_form"))
+	mock.ExpectExec("INSERT INTO consents").
+		WithArgs("u1", "marketing", "email", start.Unix()-60, "Europe/Berlin", start.Unix(), "UTC", "unsubscribe_link").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	revoked, err := service.Revoke(ctx, consent.Decision{SubjectID: "u1", Purpose: consent.PurposeMarketing, Channel: consent.ChannelEmail, Source: "unsubscribe_link"})
+	require.NoError(t, err)
+	assert.Equal(t, "Europe/Berlin", revoked.GrantedAt.Timezone.ID)
+
+	mock.ExpectQuery("SELECT (.+) FROM consents WHERE subject_id").
+		WithArgs("u9", "marketing", "sms").
+		WillReturnRows(sqlmock.NewRows(columns))
+	allowed, err := service.Allowed(ctx, "u9", consent.PurposeMarketing, consent.ChannelSMS)
+	require.NoError(t, err)
+	assert.False(t, allowed)
+
+	mock.ExpectQuery("SELECT subject_id FROM consents").
+		WithArgs("marketing", "email", "u1", 2).
+		WillReturnRows(sqlmock.NewRows([]string{"subject_id"}).AddRow("u2").AddRow("u3"))
+	subjects, err := service.Subjects(ctx, consent.PurposeMarketing, consent.ChannelEmail, "u1", 2)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"u2", "u3"}, subjects)
+
+	mock.ExpectExec("DELETE FROM consents WHERE subject_id").
+		WithArgs("u1").
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	erased, err := service.Erase(ctx, erasure.Subject{ID: "u1"}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, 2, erased)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,202 @@
+package csvmap_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"golang-arch/internal/shared/api"
+	"golang-arch/internal/shared/csvmap"
+	"golang-arch/internal/shared/domain/domainerror"
+	i18n "golang-arch/internal/shared/domain/internationalization"
+	"golang-arch/internal/shared/testutil"
+)
+
+type paymentRow struct {
+	Reference string                 `csv:"reference"`
+	Amount    i18n.Money             `csv:"amount"`
+	PaidAt    i18n.LocalizedDateTime `csv:"paid_at"`
+	Contact   i18n.Phone             `csv:"contact"`
+	Refunded  bool                   `csv:"refunded"`
+	Rate      float64                `csv:"rate"`
+	Internal  string                 `csv:"-"`
+}
+
+func samplePayments(t *testing.T) []paymentRow {
+	usd, err := i18n.MakeMoney(1234567, "USD")
+	require.NoError(t, err)
+	jpy, err := i18n.MakeMoney(-500, "JPY")
+	require.NoError(t, err)
+	ny, err := i18n.MakeLocalizedDateTime(1703520000, "America/New_York")
+	require.NoError(t, err)
+	tokyo, err := i18n.MakeLocalizedDateTime(1703520000, "Asia/Tokyo")
+	require.NoError(t, err)
+	us, err := i18n.MakePhone("1", "5551234567")
+	require.NoError(t, err)
+	jp, err := i18n.MakePhone("81", "312345678")
+	require.NoError(t, err)
+
+	return []paymentRow{
+		{Reference: "INV-1", Amount: usd, PaidAt: ny, Contact: us, Rate: 1.5},
+		{Reference: "INV-2, rush", Amount: jpy, PaidAt: tokyo, Contact: jp, Refunded: true, Rate: 0.25},
+	}
+}
+
+func TestMarshal_Golden(t *testing.T) {
+	tests := []struct {
+		name string
+		opts csvmap.Options
+	}{
+		{"payments_en_US", csvmap.LocaleOptions("en-US")},
+		{"payments_de_DE", csvmap.LocaleOptions("de-DE")},
+		{"payments_minor_units_layout", csvmap.Options{AmountFormat: csvmap.AmountMinorUnits, TimeLayout: "2006-01-02 15:04"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := csvmap.Marshal(samplePayments(t), tt.opts)
+			require.NoError(t, err)
+			testutil.AssertGolden(t, tt.name, data)
+		})
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	for _, opts := range []csvmap.Options{
+		{},
+		csvmap.LocaleOptions("id-ID"),
+		{AmountFormat: csvmap.AmountMinorUnits, TimeLayout: "2006-01-02T15:04:05"},
+	} {
+		rows := samplePayments(t)
+		data, err := csvmap.Marshal(rows, opts)
+		require.NoError(t, err)
+
+		var decoded []paymentRow
+		require.NoError(t, csvmap.Unmarshal(data, &decoded, opts))
+		assert.Equal(t, rows, decoded)
+	}
+}
+
+func TestDecode_ColumnOrderAndPointers(t *testing.T) {
+	input := "\ufeffcontact,paid_at_tz,amount_currency,reference,amount_amount,paid_at,refunded,rate,extra\n" +
+		"+44 2079460958,Europe/London,gbp,A-1,19.99,1703520000,false,,ignored\n"
+
+	var rows []*paymentRow
+	require.NoError(t, csvmap.Unmarshal([]byte(input), &rows, csvmap.Options{}))
+	require.Len(t, rows, 1)
+	assert.Equal(t, "A-1", rows[0].Reference)
+	assert.Equal(t, int64(1999), rows[0].Amount.Amount)
+	assert.Equal(t, "GBP", rows[0].Amount.Currency.Code)
+	assert.Equal(t, "Europe/London", rows[0].PaidAt.Timezone.ID)
+	assert.Equal(t, "44", rows[0].Contact.CountryCode)
+}
+
+func TestDecode_MissingColumns(t *testing.T) {
+	var rows []paymentRow
+	err := csvmap.Unmarshal([]byte("reference,amount_amount\nA,1\n"), &rows, csvmap.Options{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "missing columns: amount_currency, paid_at, paid_at_tz")
+}
+
+func TestDecode_CollectsRowErrors(t *testing.T) {
+	input := "reference,amount_amount,amount_currency,paid_at,paid_at_tz,contact,refunded,rate\n" +
+		"OK,1.00,USD,1703520000,UTC,+1 5551234567,false,1\n" +
+		"BAD,1.005,XXX,soon,Mars/Olympus,nope,maybe,x\n" +
+		"OK2,2.00,EUR,1703520000,UTC,+1 5551234567,true,2\n"
+
+	var rows []paymentRow
+	err := csvmap.Unmarshal([]byte(input), &rows, csvmap.Options{})
+	require.Error(t, err)
+	assert.Len(t, rows, 2, "valid rows are still decoded")
+	assert.ErrorIs(t, err, domainerror.Invalid)
+
+	var rowErr *csvmap.RowError
+	require.True(t, errors.As(err, &rowErr))
+	assert.Equal(t, 3, rowErr.Line)
+
+	fields := csvmap.FieldErrors(err).ByField()
+	assert.Contains(t, fields, "line[3].amount_amount")
+	assert.Contains(t, fields, "line[3].paid_at")
+	assert.Contains(t, fields, "line[3].contact")
+	assert.Contains(t, fields, "line[3].refunded")
+	assert.Contains(t, fields, "line[3].rate")
+}
+
+func TestDecoder_Streaming(t *testing.T) {
+	data, err := csvmap.Marshal(samplePayments(t), csvmap.Options{})
+	require.NoError(t, err)
+
+	dec := csvmap.NewDecoder(bytes.NewReader(data), csvmap.Options{})
+	var count int
+	for {
+		var row paymentRow
+		err := dec.Decode(&row)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		require.NoError(t, err)
+		count++
+	}
+	assert.Equal(t, 2, count)
+}
+
+func TestEncoder_RejectsMixedRows(t *testing.T) {
+	var b strings.Builder
+	enc := csvmap.NewEncoder(&b, csvmap.Options{})
+	require.NoError(t, enc.Encode(samplePayments(t)[0]))
+	assert.Error(t, enc.Encode(struct{ Name string }{"x"}))
+}
+
+func TestMarshal_EmptyWritesHeader(t *testing.T) {
+	data, err := csvmap.Marshal([]paymentRow{}, csvmap.Options{})
+	require.NoError(t, err)
+	assert.Equal(t, "reference,amount_amount,amount_currency,paid_at,paid_at_tz,contact,refunded,rate\n", string(data))
+}
+
+func TestLocaleOptions(t *testing.T) {
+	assert.Equal(t, ';', csvmap.LocaleOptions("de-DE").Comma)
+	assert.Equal(t, ',', csvmap.LocaleOptions("de_AT").DecimalSeparator)
+	assert.Equal(t, ',', csvmap.LocaleOptions("en-GB,en;q=0.9").Comma)
+	assert.Equal(t, ';', csvmap.LocaleOptions("fr;q=0.8").Comma)
+	assert.Equal(t, csvmap.Options{}, csvmap.LocaleOptions("xx"))
+}
+
+func TestAPI_CSVExportAndImport(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	payments := samplePayments(t)
+
+	router.GET("/payments.csv", func(c *gin.Context) {
+		api.CSV(c, "payments.csv", payments, api.CSVOptions(c))
+	})
+	router.POST("/payments/import", func(c *gin.Context) {
+		var rows []paymentRow
+		if !api.BindCSV(c, &rows, api.CSVOptions(c)) {
+			return
+		}
+		api.Success(c, gin.H{"imported": len(rows)}, "imported")
+	})
+
+	client := testutil.NewAPIClient(t, router).WithHeader("Accept-Language", "de-DE")
+	response := client.Get("/payments.csv")
+	testutil.AssertStatus(t, response, http.StatusOK)
+	assert.Equal(t, api.ContentTypeCSV, response.Header.Get("Content-Type"))
+	assert.Contains(t, response.Header.Get("Content-Disposition"), `filename="payments.csv"`)
+	assert.Contains(t, string(response.Body), "12345,67;USD")
+
+	response = client.WithHeader("Content-Type", "text/csv").Post("/payments/import", response.Body)
+	testutil.AssertStatus(t, response, http.StatusOK)
+
+	bad := "reference;amount_amount;amount_currency;paid_at;paid_at_tz;contact;refunded;rate\nX;abc;USD;1;UTC;+1 5551234567;false;1\n"
+	response = client.WithHeader("Content-Type", "text/csv").Post("/payments/import", bad)
+	testutil.AssertStatus(t, response, http.StatusBadRequest)
+	fields := response.Envelope.Errors.ByField()
+	assert.Contains(t, fields, "line[2].amount_amount")
+}
@@ -0,0 +1,100 @@
+package database_test
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"golang-arch/internal/shared/database"
+)
+
+// fakeConnector opens connections that record the parameters set on them
+type fakeConnector struct {
+	reject string // setting name the server rejects
+	opened []*fakeConn
+}
+
+func (f *fakeConnector) Connect(context.Context) (driver.Conn, error) {
+	conn := &fakeConn{reject: f.reject}
+	f.opened = append(f.opened, conn)
+	return conn, nil
+}
+
+func (f *fakeConnector) Driver() driver.Driver { return nil }
+
+type fakeConn struct {
+	reject string
+	set    []database.Setting
+	closed bool
+}
+
+func (c *fakeConn) ExecContext(_ context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	if query != "SELECT set_config($1, $2, false)" {
+		return nil, errors.New("unexpected query " + query)
+	}
+	setting := database.Setting{Name: args[0].Value.(string), Value: args[1].Value.(string)}
+	if setting.Name == c.reject {
+		return nil, errors.New("invalid value for parameter")
+	}
+	c.set = append(c.set, setting)
+	return driver.RowsAffected(0), nil
+}
+
+func (c *fakeConn) Ping(context.Context) error               { return nil }
+func (c *fakeConn) Prepare(string) (driver.Stmt, error)      { return nil, errors.New("not supported") }
+func (c *fakeConn) Close() error                             { c.closed = true; return nil }
+func (c *fakeConn) Begin() (driver.Tx, error)                { return nil, errors.New("not supported") }
+func (c *fakeConn) ResetSession(context.Context) error       { return nil }
+func (c *fakeConn) IsValid() bool                            { return !c.closed }
+func (c *fakeConn) CheckNamedValue(*driver.NamedValue) error { return nil }
+
+func TestConnector_AppliesSessionSettings(t *testing.T) {
+	base := &fakeConnector{}
+	connector := database.NewConnector(base,
+		database.WithTimeZone("UTC"),
+		database.WithLocale("C"),
+		database.WithTimeZone(""), // empty keeps the server's default
+		database.WithSetting("application_name", "golang-arch"),
+	)
+	db := sql.OpenDB(connector)
+	defer db.Close()
+	ctx := context.Background()
+
+	// Two connections in use at once, so both get the settings
+	first, err := db.Conn(ctx)
+	require.NoError(t, err)
+	defer first.Close()
+	second, err := db.Conn(ctx)
+	require.NoError(t, err)
+	defer second.Close()
+
+	want := []database.Setting{
+		{Name: "TimeZone", Value: "UTC"},
+		{Name: "lc_monetary", Value: "C"},
+		{Name: "lc_numeric", Value: "C"},
+		{Name: "lc_time", Value: "C"},
+		{Name: "application_name", Value: "golang-arch"},
+	}
+	assert.Equal(t, want, connector.Settings())
+	require.Len(t, base.opened, 2)
+	for _, conn := range base.opened {
+		assert.Equal(t, want, conn.set)
+	}
+}
+
+func TestConnector_RejectedSettingFailsConnection(t *testing.T) {
+	base := &fakeConnector{reject: "TimeZone"}
+	db := sql.OpenDB(database.NewConnector(base, database.WithTimeZone("Mars/Olympus")))
+	defer db.Close()
+
+	err := db.PingContext(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `failed to set TimeZone to "Mars/Olympus"`)
+	require.NotEmpty(t, base.opened)
+	assert.True(t, base.opened[0].closed, "the half-configured connection is not pooled")
+}
@@ -0,0 +1,90 @@
+package database_test
+
+import (
+	"bufio"
+	"bytes"
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// withoutContext lists the methods of database/sql types whose queries
+// cannot be cancelled; each has a ...Context counterpart
+var withoutContext = map[string]bool{
+	"Exec": true, "Query": true, "QueryRow": true, "Prepare": true, "Begin": true, "Ping": true,
+}
+
+// TestQueriesTakeContext type-checks the application's packages and fails
+// on calls to database/sql methods that take no context, so every query is
+// bounded by its request's deadline.
+func TestQueriesTakeContext(t *testing.T) {
+	if testing.Short() {
+		t.Skip("type-checks every package")
+	}
+	const root = "../.."
+	patterns := []string{"./cmd/...", "./internal/...", "./pkg/..."}
+
+	exports := make(map[string]string)
+	for _, line := range goList(t, root, append([]string{"-export", "-deps", "-f", "{{.ImportPath}}\t{{.Export}}"}, patterns...)...) {
+		path, export, _ := strings.Cut(line, "\t")
+		exports[path] = export
+	}
+	fset := token.NewFileSet()
+	imports := importer.ForCompiler(fset, "gc", func(path string) (io.ReadCloser, error) {
+		return os.Open(exports[path])
+	})
+
+	var violations []string
+	for _, line := range goList(t, root, append([]string{"-f", "{{.ImportPath}}\t{{.Dir}}\t{{join .GoFiles \" \"}}"}, patterns...)...) {
+		fields := strings.SplitN(line, "\t", 3)
+		if len(fields) < 3 || fields[2] == "" {
+			continue
+		}
+		var files []*ast.File
+		for _, name := range strings.Fields(fields[2]) {
+			file, err := parser.ParseFile(fset, filepath.Join(fields[1], name), nil, 0)
+			require.NoError(t, err)
+			files = append(files, file)
+		}
+		info := &types.Info{Selections: make(map[*ast.SelectorExpr]*types.Selection)}
+		_, err := (&types.Config{Importer: imports}).Check(fields[0], fset, files, info)
+		require.NoError(t, err, fields[0])
+
+		for expr, selection := range info.Selections {
+			method, ok := selection.Obj().(*types.Func)
+			if !ok || !withoutContext[method.Name()] || method.Pkg() == nil || method.Pkg().Path() != "database/sql" {
+				continue
+			}
+			violations = append(violations, fset.Position(expr.Sel.Pos()).String()+": "+method.Name()+" without a context")
+		}
+	}
+	require.Empty(t, violations, "use the ...Context methods")
+}
+
+// goList runs go list in dir and returns its output lines
+func goList(t *testing.T, dir string, args ...string) []string {
+	t.Helper()
+	cmd := exec.Command("go", append([]string{"list"}, args...)...)
+	cmd.Dir = dir
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	require.NoError(t, err, stderr.String())
+
+	var lines []string
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	return lines
+}
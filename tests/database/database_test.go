@@ -0,0 +1,113 @@
+package database_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gin-gonic/gin"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"golang-arch/internal/shared/api"
+	"golang-arch/internal/shared/database"
+	"golang-arch/internal/shared/domain/domainerror"
+)
+
+func TestWithTimeout(t *testing.T) {
+	ctx, cancel := database.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	deadline, ok := ctx.Deadline()
+	require.True(t, ok)
+	assert.WithinDuration(t, time.Now().Add(time.Minute), deadline, time.Second)
+
+	sooner, cancelSooner := context.WithTimeout(context.Background(), time.Second)
+	defer cancelSooner()
+	kept, cancel := database.WithTimeout(sooner, time.Minute)
+	defer cancel()
+	assert.Equal(t, sooner, kept, "an earlier deadline is kept")
+
+	unbounded, cancel := database.WithTimeout(context.Background(), 0)
+	defer cancel()
+	_, ok = unbounded.Deadline()
+	assert.False(t, ok)
+}
+
+func TestMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(database.Middleware(time.Minute, "/jobs/:id/events"))
+	hasDeadline := func(c *gin.Context) {
+		_, ok := c.Request.Context().Deadline()
+		c.JSON(http.StatusOK, ok)
+	}
+	router.GET("/jobs/:id", hasDeadline)
+	router.GET("/jobs/:id/events", hasDeadline)
+
+	for path, want := range map[string]string{"/jobs/1": "true", "/jobs/1/events": "false"} {
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		assert.Equal(t, want, rec.Body.String(), path)
+	}
+}
+
+func TestMiddleware_CancelsSlowQueries(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+	mock.ExpectQuery("SELECT pg_sleep").WillDelayFor(time.Second).WillReturnRows(sqlmock.NewRows([]string{"x"}))
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(api.ErrorHandler(), database.Middleware(20*time.Millisecond))
+	router.GET("/slow", func(c *gin.Context) {
+		var x int
+		err := db.QueryRowContext(c.Request.Context(), "SELECT pg_sleep(1)").Scan(&x)
+		api.AbortWithError(c, database.MapTimeout(c.Request.Context(), err))
+	})
+
+	rec := httptest.NewRecorder()
+	started := time.Now()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/slow", nil))
+	assert.Less(t, time.Since(started), 500*time.Millisecond, "the query does not outlive the request")
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+}
+
+func TestStatementTimeout(t *testing.T) {
+	assert.Equal(t, "options='-c statement_timeout=1500'", database.StatementTimeout(1500*time.Millisecond))
+	assert.Empty(t, database.StatementTimeout(0))
+}
+
+func TestSetLocalStatementTimeout(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+	mock.ExpectBegin()
+	mock.ExpectExec("SET LOCAL statement_timeout = 120000").WillReturnResult(sqlmock.NewResult(0, 0))
+
+	tx, err := db.BeginTx(context.Background(), nil)
+	require.NoError(t, err)
+	require.NoError(t, database.SetLocalStatementTimeout(context.Background(), tx, 2*time.Minute))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMapTimeout(t *testing.T) {
+	ctx := context.Background()
+	canceled := &pq.Error{Code: "57014", Message: "canceling statement due to statement timeout"}
+	assert.Equal(t, domainerror.Unavailable, domainerror.KindOf(database.MapTimeout(ctx, canceled)))
+	assert.Equal(t, domainerror.Unavailable, domainerror.KindOf(database.MapTimeout(ctx, context.DeadlineExceeded)))
+	assert.ErrorIs(t, database.MapTimeout(ctx, canceled), canceled)
+
+	other := errors.New("syntax error")
+	assert.Equal(t, other, database.MapTimeout(ctx, other))
+	assert.NoError(t, database.MapTimeout(ctx, nil))
+
+	expired, cancel := context.WithDeadline(ctx, time.Now().Add(-time.Second))
+	defer cancel()
+	assert.Equal(t, domainerror.Unavailable, domainerror.KindOf(database.MapTimeout(expired, errors.New("driver: canceled"))))
+}
@@ -0,0 +1,129 @@
+package database_test
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"golang-arch/internal/shared/database"
+	"golang-arch/internal/shared/domain/domainerror"
+)
+
+var (
+	orderID      = database.NewColumn[int64]("id")
+	orderStatus  = database.NewColumn[string]("status")
+	orderTotal   = database.NewColumn[int64]("total")
+	orderNote    = database.NewColumn[string]("note")
+	orderCreated = database.NewColumn[time.Time]("created_at")
+)
+
+func TestQuery_Build(t *testing.T) {
+	var noFilter database.Predicate
+	query, args := database.Select("orders", "id", "status").
+		Where(
+			orderStatus.In("paid", "shipped"),
+			noFilter,
+			database.Or(orderTotal.Ge(1000), database.Not(orderNote.IsNull())),
+			database.ILike(orderNote, "%"+database.EscapeLike("50%_off")+"%"),
+		).
+		OrderBy(orderCreated.Desc(), orderID.Asc()).
+		Limit(20).
+		Build()
+
+	assert.Equal(t, "SELECT id, status FROM orders"+
+		" WHERE status IN ($1, $2) AND (total >= $3 OR NOT (note IS NULL)) AND note ILIKE $4"+
+		" ORDER BY created_at DESC, id LIMIT $5", query)
+	assert.Equal(t, []any{"paid", "shipped", int64(1000), `%50\%\_off%`, 20}, args)
+}
+
+func TestQuery_BuildEdgeCases(t *testing.T) {
+	query, args := database.Select("orders").Build()
+	assert.Equal(t, "SELECT * FROM orders", query)
+	assert.Empty(t, args)
+
+	query, _ = database.Select("orders", "id").Where(orderStatus.In(), database.Or(), database.And()).Build()
+	assert.Equal(t, "SELECT id FROM orders WHERE FALSE AND FALSE AND TRUE", query)
+
+	query, _ = database.Select("orders", "id").Where(database.Not(nil)).Build()
+	assert.Equal(t, "SELECT id FROM orders", query, "Not(nil) is an absent filter")
+	assert.Nil(t, database.Not(nil))
+
+	query, args = database.Select("orders", "id").Limit(0).Build()
+	assert.Equal(t, "SELECT id FROM orders LIMIT $1", query)
+	assert.Equal(t, []any{0}, args)
+
+	assert.Panics(t, func() { database.NewColumn[string]("name; DROP TABLE orders") })
+	assert.Panics(t, func() { database.Select("orders", "id, (SELECT 1)") })
+	assert.Panics(t, func() { database.Select("orders").OrderBy(orderID.Asc()).After(1, 2) })
+}
+
+func TestQuery_Keyset(t *testing.T) {
+	created := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	query, args := database.Select("orders", "id").
+		Where(orderStatus.Eq("paid")).
+		OrderBy(orderCreated.Desc(), orderID.Asc()).
+		After(created, int64(42)).
+		Build()
+
+	assert.Equal(t, "SELECT id FROM orders WHERE status = $1"+
+		" AND (created_at < $2 OR (created_at = $2 AND id > $3)) ORDER BY created_at DESC, id", query)
+	assert.Equal(t, []any{"paid", created, int64(42)}, args)
+}
+
+type order struct {
+	ID      int64
+	Created time.Time
+}
+
+func scanOrder(row database.Scanner) (order, error) {
+	var o order
+	err := row.Scan(&o.ID, &o.Created)
+	return o, err
+}
+
+func orderKey(o order) []any { return []any{o.Created, o.ID} }
+
+func TestQueryPage(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+	ctx := context.Background()
+	day := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	newQuery := func() *database.Query {
+		return database.Select("orders", "id", "created_at").OrderBy(orderCreated.Desc(), orderID.Desc())
+	}
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id, created_at FROM orders ORDER BY created_at DESC, id DESC LIMIT $1")).
+		WithArgs(3).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).
+			AddRow(int64(9), day.Add(2*time.Hour)).AddRow(int64(8), day.Add(time.Hour)).AddRow(int64(7), day.Add(time.Hour)))
+	first, err := database.QueryPage(ctx, db, newQuery(), database.Page{Limit: 2}, scanOrder, orderKey)
+	require.NoError(t, err)
+	require.Len(t, first.Items, 2)
+	assert.NotEmpty(t, first.Next)
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id, created_at FROM orders"+
+		" WHERE (created_at < $1 OR (created_at = $1 AND id < $2)) ORDER BY created_at DESC, id DESC LIMIT $3")).
+		WithArgs(day.Add(time.Hour), int64(8), 3).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow(int64(7), day.Add(time.Hour)))
+	// The same query serves the next page: QueryPage did not change it
+	query := newQuery()
+	before, _ := query.Build()
+	second, err := database.QueryPage(ctx, db, query, database.Page{Limit: 2, After: first.Next}, scanOrder, orderKey)
+	require.NoError(t, err)
+	assert.Equal(t, []order{{7, day.Add(time.Hour)}}, second.Items)
+	assert.Empty(t, second.Next, "the last page has no cursor")
+	after, _ := query.Build()
+	assert.Equal(t, before, after)
+	assert.NoError(t, mock.ExpectationsWereMet())
+
+	for _, cursor := range []string{"not base64!", "WzFd", "WyJ4IiwiMSJd"} {
+		_, err = database.QueryPage(ctx, db, newQuery(), database.Page{Limit: 2, After: cursor}, scanOrder, orderKey)
+		assert.Equal(t, domainerror.Invalid, domainerror.KindOf(err), cursor)
+	}
+}
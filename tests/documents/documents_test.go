@@ -0,0 +1,244 @@
+package documents_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"golang-arch/internal/shared/config"
+	"golang-arch/internal/shared/documents"
+	"golang-arch/internal/shared/domain/domainerror"
+	i18n "golang-arch/internal/shared/domain/internationalization"
+	"golang-arch/internal/shared/storage"
+	"golang-arch/internal/shared/templates"
+	"golang-arch/pkg/clock"
+)
+
+func prefs(t *testing.T, locale, timezone, currency string) i18n.LocalePreferences {
+	t.Helper()
+	p, err := i18n.NewLocalePreferencesFromPrimitive(locale, timezone, currency, "metric", 1)
+	require.NoError(t, err)
+	return *p
+}
+
+func money(t *testing.T, amount int64, currency string) i18n.Money {
+	t.Helper()
+	m, err := i18n.NewMoneyFromPrimitive(amount, currency)
+	require.NoError(t, err)
+	return *m
+}
+
+func TestFormatter_Translate(t *testing.T) {
+	f := templates.NewFormatter(prefs(t, "de-AT", "UTC", "EUR"), documents.DefaultCatalog())
+	assert.Equal(t, "Rechnung", f.Translate("invoice.title"))
+	assert.Equal(t, "MwSt. (20 %)", f.Translate("invoice.tax", "rate", f.Number(20)))
+	assert.Equal(t, "invoice.unknown", f.Translate("invoice.unknown"))
+
+	// Languages without a translation fall back to English
+	f = templates.NewFormatter(prefs(t, "it-IT", "UTC", "EUR"), documents.DefaultCatalog())
+	assert.Equal(t, "Invoice", f.Translate("invoice.title"))
+}
+
+func TestAddress_Format(t *testing.T) {
+	us := documents.Address{
+		Name: "Jane Doe", Organization: "Acme Inc.", Street: []string{"350 Fifth Avenue", ""},
+		City: "New York", Region: "NY", PostalCode: "10118", Country: "US",
+	}
+	assert.Equal(t, []string{"Jane Doe", "Acme Inc.", "350 Fifth Avenue", "New York, NY 10118", "United States"},
+		us.Format(i18n.MustParseLocale("en")))
+
+	de := documents.Address{Name: "Max Mustermann", Street: []string{"Unter den Linden 1"}, City: "Berlin", PostalCode: "10117", Country: "DE"}
+	assert.Equal(t, []string{"Max Mustermann", "Unter den Linden 1", "10117 Berlin", "Allemagne"},
+		de.Format(i18n.MustParseLocale("fr")))
+
+	gb := documents.Address{Name: "Ann", Street: []string{"10 Downing Street"}, City: "London", PostalCode: "SW1A 2AA", Country: "GB"}
+	assert.Equal(t, []string{"Ann", "10 Downing Street", "London", "SW1A 2AA", "Vereinigtes Königreich"},
+		gb.Format(i18n.MustParseLocale("de")))
+}
+
+func testInvoice(t *testing.T) documents.Invoice {
+	t.Helper()
+	eur, err := i18n.NewCurrencyFromCode("EUR")
+	require.NoError(t, err)
+	hosting, err := i18n.NewLocalizedString(map[string]string{"en": "Hosting", "de": "Hosting-Paket"})
+	require.NoError(t, err)
+	support, err := i18n.NewLocalizedString(map[string]string{"en": "Support hours", "de": "Supportstunden"})
+	require.NoError(t, err)
+	due, err := i18n.NewDate(2024, time.June, 7)
+	require.NoError(t, err)
+
+	return documents.Invoice{
+		Number:   "INV-2024-0042",
+		IssuedAt: time.Date(2024, 5, 24, 23, 30, 0, 0, time.UTC),
+		DueDate:  due,
+		Seller:   documents.Address{Name: "Goarch GmbH", Street: []string{"Hauptstraße 5"}, City: "München", PostalCode: "80331", Country: "DE"},
+		Customer: documents.Address{Name: "Erika Musterfrau", Street: []string{"Ring 3"}, City: "Wien", PostalCode: "1010", Country: "AT"},
+		Currency: *eur,
+		Lines: []documents.InvoiceLine{
+			{Description: *hosting, Quantity: 1, UnitPrice: money(t, 99900, "EUR")},
+			{Description: *support, Quantity: 3, UnitPrice: money(t, 8505, "EUR")},
+		},
+		TaxRate: 2000,
+	}
+}
+
+func TestInvoice_Totals(t *testing.T) {
+	inv := testInvoice(t)
+	totals, err := inv.Totals()
+	require.NoError(t, err)
+	assert.Equal(t, int64(25515), totals.Lines[1].Amount)
+	assert.Equal(t, int64(125415), totals.Subtotal.Amount)
+	assert.Equal(t, int64(25083), totals.Tax.Amount) // 250.83
+	assert.Equal(t, int64(150498), totals.Total.Amount)
+
+	inv.Lines[0].UnitPrice = money(t, 100, "USD")
+	_, err = inv.Totals()
+	assert.ErrorIs(t, err, domainerror.Invalid)
+
+	inv = testInvoice(t)
+	inv.Number = "../etc"
+	_, err = inv.Totals()
+	assert.ErrorIs(t, err, domainerror.Invalid)
+}
+
+func TestService_RenderInvoiceHTML(t *testing.T) {
+	service, err := documents.NewService(nil, nil)
+	require.NoError(t, err)
+	inv := testInvoice(t)
+	totals, err := inv.Totals()
+	require.NoError(t, err)
+
+	var html bytes.Buffer
+	err = service.Render(&html, documents.InvoiceTemplate, prefs(t, "de-AT", "Europe/Vienna", "EUR"), struct {
+		documents.Invoice
+		Totals     documents.InvoiceTotals
+		TaxPercent float64
+	}{inv, totals, 20})
+	require.NoError(t, err)
+
+	out := html.String()
+	assert.Contains(t, out, `<html lang="de-AT" dir="ltr">`)
+	assert.Contains(t, out, "<h1>Rechnung</h1>")
+	assert.Contains(t, out, "25.05.2024")
+	assert.Contains(t, out, "Supportstunden")
+	assert.Contains(t, out, "1010 Wien<br>Österreich")
+	assert.Contains(t, out, "MwSt. (20 %)")
+	assert.Contains(t, out, "1\u00a0504,98\u00a0€", "de-AT groups with a no-break space")
+}
+
+func TestService_CustomTemplatesAndCatalog(t *testing.T) {
+	label, err := i18n.NewLocalizedString(map[string]string{"en": "Report for {name}", "de": "Bericht für {name}"})
+	require.NoError(t, err)
+	files := fstest.MapFS{
+		"report.html": {Data: []byte(`{{ t "report.title" "name" .Name }}: {{ money .Total }}`)},
+	}
+	service, err := documents.NewService(nil, nil,
+		documents.WithTemplates(files, "*.html"),
+		documents.WithCatalog(templates.Catalog{"report.title": *label}))
+	require.NoError(t, err)
+
+	var out bytes.Buffer
+	require.NoError(t, service.Render(&out, "report.html", prefs(t, "de-DE", "UTC", "EUR"), map[string]any{
+		"Name": "<Q2>", "Total": money(t, 500, "EUR"),
+	}))
+	assert.Equal(t, "Bericht für &lt;Q2&gt;: 5,00\u00a0€", out.String())
+
+	_, err = documents.NewService(nil, nil, documents.WithTemplates(fstest.MapFS{"bad.html": {Data: []byte("{{ nope }}")}}, "*.html"))
+	assert.Error(t, err)
+}
+
+func TestGotenbergConverter(t *testing.T) {
+	var uploaded string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/forms/chromium/convert/html", r.URL.Path)
+		_, params, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+		require.NoError(t, err)
+		part, err := multipart.NewReader(r.Body, params["boundary"]).NextPart()
+		require.NoError(t, err)
+		assert.Equal(t, "index.html", part.FileName())
+		data, _ := io.ReadAll(part)
+		uploaded = string(data)
+		if uploaded == "fail" {
+			http.Error(w, "chromium crashed", http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte("%PDF-1.7"))
+	}))
+	defer server.Close()
+
+	converter, err := documents.NewConverter(config.DocumentsConfig{PDFProvider: "gotenberg", GotenbergURL: server.URL + "/"})
+	require.NoError(t, err)
+
+	var pdf bytes.Buffer
+	require.NoError(t, converter.Convert(context.Background(), strings.NewReader("<p>hi</p>"), &pdf))
+	assert.Equal(t, "<p>hi</p>", uploaded)
+	assert.Equal(t, "%PDF-1.7", pdf.String())
+
+	err = converter.Convert(context.Background(), strings.NewReader("fail"), io.Discard)
+	assert.ErrorContains(t, err, "status 503: chromium crashed")
+}
+
+func TestCommandConverter(t *testing.T) {
+	converter := documents.NewCommandConverter([]string{"sh", "-c", `printf '%%PDF '; cat`}, time.Second)
+	var pdf bytes.Buffer
+	require.NoError(t, converter.Convert(context.Background(), strings.NewReader("<p>hi</p>"), &pdf))
+	assert.Equal(t, "%PDF <p>hi</p>", pdf.String())
+
+	converter = documents.NewCommandConverter([]string{"sh", "-c", "echo broken >&2; exit 3"}, time.Second)
+	assert.ErrorContains(t, converter.Convert(context.Background(), strings.NewReader(""), io.Discard), "broken")
+}
+
+func TestNewConverter(t *testing.T) {
+	converter, err := documents.NewConverter(config.DocumentsConfig{})
+	require.NoError(t, err)
+	assert.ErrorIs(t, converter.Convert(context.Background(), strings.NewReader(""), io.Discard), domainerror.Unavailable)
+
+	for _, cfg := range []config.DocumentsConfig{
+		{PDFProvider: "gotenberg"},
+		{PDFProvider: "command"},
+		{PDFProvider: "prince"},
+	} {
+		_, err := documents.NewConverter(cfg)
+		assert.Error(t, err, cfg.PDFProvider)
+	}
+}
+
+func TestService_StoreInvoice(t *testing.T) {
+	blobs := storage.NewMemoryStore(clock.New())
+	converter := documents.NewCommandConverter([]string{"cat"}, time.Second)
+	service, err := documents.NewService(converter, blobs)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	info, err := service.StoreInvoice(ctx, testInvoice(t), prefs(t, "en-GB", "Europe/London", "EUR"))
+	require.NoError(t, err)
+	assert.Equal(t, "invoices/INV-2024-0042.pdf", info.Key)
+	assert.Equal(t, "application/pdf", info.ContentType)
+
+	var stored strings.Builder
+	_, err = storage.Download(ctx, blobs, info.Key, &stored)
+	require.NoError(t, err)
+	assert.Contains(t, stored.String(), "€1,504.98")
+	assert.Contains(t, stored.String(), "25/05/2024")
+
+	// A failed conversion stores nothing
+	failing, err := documents.NewService(documents.NewCommandConverter([]string{"false"}, time.Second), blobs)
+	require.NoError(t, err)
+	inv := testInvoice(t)
+	inv.Number = "INV-2024-0043"
+	_, err = failing.StoreInvoice(ctx, inv, prefs(t, "en-GB", "Europe/London", "EUR"))
+	assert.Error(t, err)
+	_, err = blobs.Stat(ctx, "invoices/INV-2024-0043.pdf")
+	assert.ErrorIs(t, err, domainerror.NotFound)
+}
@@ -0,0 +1,69 @@
+package domainerror_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"golang-arch/internal/shared/domain/domainerror"
+	i18n "golang-arch/internal/shared/domain/internationalization"
+	"golang-arch/internal/shared/domain/validation"
+)
+
+func TestKindOf(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		kind domainerror.Kind
+	}{
+		{"nil", nil, domainerror.Unknown},
+		{"plain", errors.New("boom"), domainerror.Unknown},
+		{"not found", domainerror.NotFoundf("user %d", 7), domainerror.NotFound},
+		{"conflict", domainerror.Conflictf("duplicate"), domainerror.Conflict},
+		{"forbidden", domainerror.Forbiddenf("nope"), domainerror.Forbidden},
+		{"unavailable", domainerror.Unavailablef("rates down"), domainerror.Unavailable},
+		{"wrapped", fmt.Errorf("load: %w", domainerror.NotFoundf("user")), domainerror.NotFound},
+		{"outermost wins", domainerror.Wrap(domainerror.Conflict, domainerror.Invalidf("bad"), "save"), domainerror.Conflict},
+		{"validation errors", validation.Validate(i18n.Phone{}), domainerror.Invalid},
+		{"bare kind", domainerror.Forbidden, domainerror.Forbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.kind, domainerror.KindOf(tt.err))
+		})
+	}
+}
+
+func TestError_IsAndMessage(t *testing.T) {
+	cause := errors.New("connection refused")
+	err := domainerror.Wrap(domainerror.Unavailable, cause, "fetch rates")
+
+	assert.True(t, errors.Is(err, domainerror.Unavailable))
+	assert.False(t, errors.Is(err, domainerror.NotFound))
+	assert.True(t, errors.Is(err, cause))
+	assert.Equal(t, "fetch rates: connection refused", err.Error())
+
+	assert.Nil(t, domainerror.Wrap(domainerror.Invalid, nil, "ignored"))
+	assert.Equal(t, "not found", domainerror.New(domainerror.NotFound, "").Error())
+	assert.Equal(t, "missing", domainerror.New(domainerror.NotFound, "missing").Error())
+}
+
+func TestInternationalizationErrorsAreInvalid(t *testing.T) {
+	_, err := i18n.NewCurrencyFromCode("XXX")
+	assert.ErrorIs(t, err, domainerror.Invalid)
+	assert.Contains(t, err.Error(), "unsupported currency code")
+
+	_, err = i18n.NewPhone("1", "12")
+	assert.ErrorIs(t, err, domainerror.Invalid)
+
+	usd, _ := i18n.NewMoneyFromPrimitive(100, "USD")
+	eur, _ := i18n.NewMoneyFromPrimitive(100, "EUR")
+	_, err = usd.Add(eur)
+	assert.ErrorIs(t, err, domainerror.Invalid)
+
+	_, err = i18n.NewLocalizedDateTimeFromPrimitive(1703520000, "Mars/Olympus")
+	assert.ErrorIs(t, err, domainerror.Invalid)
+}
@@ -0,0 +1,212 @@
+package erasure_test
+
+import (
+	"context"
+	"errors"
+	"net/mail"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"golang-arch/internal/shared/domain/domainerror"
+	i18n "golang-arch/internal/shared/domain/internationalization"
+	"golang-arch/internal/shared/erasure"
+	"golang-arch/pkg/clock"
+)
+
+var start = time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+func phone(t *testing.T, value string) i18n.Phone {
+	t.Helper()
+	p, err := i18n.NewPhoneFromString(value)
+	require.NoError(t, err)
+	return *p
+}
+
+// customer is a record of a fake repository
+type customer struct {
+	UserID string
+	Name   string
+	Email  string
+	Phone  i18n.Phone
+	Street string
+}
+
+// customers is a fake repository that erases by user ID
+type customers struct {
+	rows []*customer
+}
+
+func (c *customers) Erase(_ context.Context, subject erasure.Subject, anonymizer *erasure.Anonymizer) (int, error) {
+	count := 0
+	for _, row := range c.rows {
+		if row.UserID != subject.ID {
+			continue
+		}
+		row.Name = anonymizer.Name(row.Name)
+		row.Email = anonymizer.Email(row.Email)
+		row.Phone = anonymizer.Phone(row.Phone)
+		row.Street = anonymizer.Text(row.Street)
+		count++
+	}
+	return count, nil
+}
+
+func TestAnonymizerShapes(t *testing.T) {
+	for _, strategy := range []erasure.Strategy{erasure.StrategyScrub, erasure.StrategyPseudonymize} {
+		t.Run(string(strategy), func(t *testing.T) {
+			anonymizer := erasure.NewAnonymizer(strategy, []byte("secret"))
+			original := phone(t, "+4917612345678")
+
+			replaced := anonymizer.Phone(original)
+			assert.Equal(t, original.CountryCode, replaced.CountryCode)
+			assert.Len(t, replaced.Number, len(original.Number))
+			assert.NotEqual(t, original.Number, replaced.Number)
+			assert.NoError(t, replaced.Validate())
+
+			email := anonymizer.Email("Jane.Doe@example.com")
+			_, err := mail.ParseAddress(email)
+			assert.NoError(t, err)
+			assert.NotContains(t, email, "jane")
+			assert.Contains(t, email, "@erased.invalid")
+
+			name := anonymizer.Name("Jane Doe")
+			assert.NotContains(t, name, "Jane")
+			assert.Equal(t, erasure.ErasedText, anonymizer.Text("1 Main St"))
+
+			for _, empty := range []string{anonymizer.Name(""), anonymizer.Email(""), anonymizer.Text("")} {
+				assert.Empty(t, empty, "empty fields stay empty")
+			}
+		})
+	}
+}
+
+func TestAnonymizerStrategies(t *testing.T) {
+	pseudonyms := erasure.NewAnonymizer(erasure.StrategyPseudonymize, []byte("secret"))
+	assert.Equal(t, pseudonyms.Email("jane@example.com"), pseudonyms.Email(" JANE@example.com"),
+		"pseudonyms are consistent so erased records can be joined")
+	assert.Equal(t, pseudonyms.Phone(phone(t, "+15551234567")), pseudonyms.Phone(phone(t, "+15551234567")))
+	assert.NotEqual(t, pseudonyms.Token("name", "jane"), pseudonyms.Token("email", "jane"))
+
+	otherKey := erasure.NewAnonymizer(erasure.StrategyPseudonymize, []byte("other"))
+	assert.NotEqual(t, pseudonyms.Email("jane@example.com"), otherKey.Email("jane@example.com"))
+
+	scrubs := erasure.NewAnonymizer(erasure.StrategyScrub, []byte("secret"))
+	assert.NotEqual(t, scrubs.Email("jane@example.com"), scrubs.Email("jane@example.com"),
+		"scrubbed values cannot be linked")
+
+	_, err := erasure.ParseStrategy("shred")
+	assert.Error(t, err)
+	strategy, err := erasure.ParseStrategy(" Pseudonymize ")
+	require.NoError(t, err)
+	assert.Equal(t, erasure.StrategyPseudonymize, strategy)
+}
+
+func TestErase(t *testing.T) {
+	audit := erasure.NewMemoryAuditStore()
+	service := erasure.NewService(audit, erasure.WithClock(clock.NewFake(start)), erasure.WithSecret("secret"))
+
+	repo := &customers{rows: []*customer{
+		{UserID: "u1", Name: "Jane Doe", Email: "jane@example.com", Phone: phone(t, "+15551234567"), Street: "1 Main St"},
+		{UserID: "u1", Name: "Jane D.", Email: "jane@work.example"},
+		{UserID: "u2", Name: "John Roe", Email: "john@example.com"},
+	}}
+	var seen erasure.Subject
+	service.Register("customers", repo)
+	service.Register("newsletter", erasure.EraserFunc(func(_ context.Context, subject erasure.Subject, _ *erasure.Anonymizer) (int, error) {
+		seen = subject
+		return len(subject.Emails), nil
+	}))
+	assert.Equal(t, []string{"customers", "newsletter"}, service.Repositories())
+	assert.Panics(t, func() { service.Register("customers", repo) })
+
+	subject := erasure.Subject{ID: "u1", Emails: []string{"jane@example.com"}}
+	record, err := service.Erase(context.Background(), erasure.Request{Subject: subject, Reason: "gdpr_art17", RequestedBy: "support@example.com"})
+	require.NoError(t, err)
+
+	assert.Equal(t, subject, seen)
+	assert.Equal(t, erasure.StatusCompleted, record.Status())
+	assert.Equal(t, 3, record.Records())
+	assert.Equal(t, erasure.StrategyScrub, record.Strategy)
+	assert.Equal(t, start, record.RequestedAt)
+	assert.Equal(t, []erasure.Result{{Repository: "customers", Records: 2}, {Repository: "newsletter", Records: 1}}, record.Results)
+
+	assert.NotContains(t, repo.rows[0].Name, "Jane")
+	assert.NotEqual(t, "jane@example.com", repo.rows[0].Email)
+	assert.Equal(t, erasure.ErasedText, repo.rows[0].Street)
+	assert.Equal(t, "John Roe", repo.rows[2].Name, "other subjects are untouched")
+
+	history, err := service.History(context.Background(), "u1")
+	require.NoError(t, err)
+	require.Len(t, history, 1)
+	assert.Equal(t, record.ID, history[0].ID)
+	assert.Equal(t, "gdpr_art17", history[0].Reason)
+}
+
+func TestErasePartialFailure(t *testing.T) {
+	audit := erasure.NewMemoryAuditStore()
+	service := erasure.NewService(audit)
+	failure := errors.New("connection refused")
+
+	calls := 0
+	service.Register("orders", erasure.EraserFunc(func(context.Context, erasure.Subject, *erasure.Anonymizer) (int, error) {
+		return 0, failure
+	}))
+	service.Register("profiles", erasure.EraserFunc(func(context.Context, erasure.Subject, *erasure.Anonymizer) (int, error) {
+		calls++
+		return 1, nil
+	}))
+
+	record, err := service.Erase(context.Background(), erasure.Request{Subject: erasure.Subject{ID: "u1"}, Strategy: erasure.StrategyPseudonymize})
+	assert.ErrorIs(t, err, failure)
+	assert.Equal(t, 1, calls, "later repositories still run")
+	assert.Equal(t, erasure.StatusPartial, record.Status())
+	assert.Equal(t, erasure.StrategyPseudonymize, record.Strategy)
+	assert.Equal(t, "connection refused", record.Results[0].Error)
+
+	history, err := audit.ForSubject(context.Background(), "u1")
+	require.NoError(t, err)
+	assert.Len(t, history, 1, "partial erasures are audited")
+
+	_, err = service.Erase(context.Background(), erasure.Request{Subject: erasure.Subject{ID: " "}})
+	assert.ErrorIs(t, err, domainerror.Invalid)
+	_, err = service.Erase(context.Background(), erasure.Request{Subject: erasure.Subject{ID: "u1"}, Strategy: "shred"})
+	assert.ErrorIs(t, err, domainerror.Invalid)
+}
+
+func TestPostgresAuditStore(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+	store := erasure.NewPostgresAuditStore(db)
+	ctx := context.Background()
+
+	record := erasure.Record{
+		ID:          "5f1d7c52-8f1e-4b8e-9d0a-1f2e3d4c5b6a",
+		SubjectID:   "u1",
+		Strategy:    erasure.StrategyScrub,
+		Reason:      "gdpr_art17",
+		RequestedBy: "support",
+		RequestedAt: start,
+		CompletedAt: start.Add(time.Second),
+		Results:     []erasure.Result{{Repository: "orders", Records: 0, Error: "timeout"}},
+	}
+	results := []byte(`[{"repository":"orders","records":0,"error":"timeout"}]`)
+
+	mock.ExpectExec("INSERT INTO erasure_audit").
+		WithArgs(record.ID, "u1", "scrub", "gdpr_art17", "support", "partial", start, start.Add(time.Second), results).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	require.NoError(t, store.Save(ctx, record))
+
+	mock.ExpectQuery("SELECT (.+) FROM erasure_audit").
+		WithArgs("u1").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "subject_id", "strategy", "reason", "requested_by", "requested_at", "completed_at", "results"}).
+			AddRow(record.ID, "u1", "scrub", "gdpr_art17", "support", start, start.Add(time.Second), results))
+	records, err := store.ForSubject(ctx, "u1")
+	require.NoError(t, err)
+	assert.Equal(t, []erasure.Record{record}, records)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
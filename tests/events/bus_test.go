@@ -0,0 +1,230 @@
+package events_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"golang-arch/internal/bootstrap"
+	i18n "golang-arch/internal/shared/domain/internationalization"
+	"golang-arch/internal/shared/events"
+	"golang-arch/internal/shared/testutil"
+	"golang-arch/pkg/clock"
+)
+
+type moneyConverted struct {
+	events.Base
+	From i18n.Money `json:"from"`
+	To   i18n.Money `json:"to"`
+}
+
+func (moneyConverted) EventName() string { return "money.converted" }
+
+type userLocaleChanged struct {
+	events.Base
+	Locale string `json:"locale"`
+}
+
+func (userLocaleChanged) EventName() string { return "user.locale_changed" }
+
+var testClock = clock.NewFake(time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC))
+
+func newMoneyConverted(t *testing.T) moneyConverted {
+	from, err := i18n.MakeMoney(10000, "USD")
+	require.NoError(t, err)
+	to, err := i18n.MakeMoney(9200, "EUR")
+	require.NoError(t, err)
+
+	return moneyConverted{
+		Base: events.Base{Metadata: events.NewMetadata(testClock, "wallet", "w-1")},
+		From: from,
+		To:   to,
+	}
+}
+
+func TestNewMetadata(t *testing.T) {
+	meta := events.NewMetadata(testClock, "user", "42")
+
+	assert.NotEmpty(t, meta.ID)
+	assert.Equal(t, testClock.Now().Unix(), meta.OccurredAt.Time.Epoch)
+	assert.Equal(t, "UTC", meta.OccurredAt.Timezone.ID)
+	assert.Equal(t, "user", meta.AggregateType)
+	assert.Equal(t, "42", meta.AggregateID)
+	assert.NotEqual(t, meta.ID, events.NewMetadata(testClock, "user", "42").ID)
+}
+
+func TestBus_PublishSync(t *testing.T) {
+	bus := events.NewBus(zap.NewNop())
+	defer bus.Close()
+
+	var calls []string
+	bus.Subscribe("money.converted", func(_ context.Context, event events.Event) error {
+		calls = append(calls, "named:"+event.EventName())
+		return nil
+	})
+	bus.Subscribe(events.AllEvents, func(_ context.Context, event events.Event) error {
+		calls = append(calls, "all:"+event.EventName())
+		return nil
+	})
+
+	userEvent := userLocaleChanged{Base: events.Base{Metadata: events.NewMetadata(testClock, "user", "1")}, Locale: "id-ID"}
+	require.NoError(t, bus.Publish(context.Background(), newMoneyConverted(t), userEvent))
+
+	assert.Equal(t, []string{"named:money.converted", "all:money.converted", "all:user.locale_changed"}, calls)
+}
+
+func TestBus_PublishJoinsErrorsAndRecoversPanics(t *testing.T) {
+	bus := events.NewBus(zap.NewNop())
+	defer bus.Close()
+
+	errBoom := errors.New("boom")
+	var ran bool
+	bus.Subscribe("money.converted", func(context.Context, events.Event) error { return errBoom })
+	bus.Subscribe("money.converted", func(context.Context, events.Event) error { panic("bad handler") })
+	bus.Subscribe("money.converted", func(context.Context, events.Event) error {
+		ran = true
+		return nil
+	})
+
+	err := bus.Publish(context.Background(), newMoneyConverted(t))
+	require.Error(t, err)
+	assert.ErrorIs(t, err, errBoom)
+	assert.Contains(t, err.Error(), "panicked: bad handler")
+	assert.True(t, ran, "a failing handler must not stop the others")
+}
+
+func TestBus_PublishAsync(t *testing.T) {
+	bus := events.NewBus(zap.NewNop(), events.WithWorkers(2), events.WithQueueSize(1))
+
+	var handled atomic.Int32
+	bus.Subscribe(events.AllEvents, func(ctx context.Context, _ events.Event) error {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		handled.Add(1)
+		return nil
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	for i := 0; i < 10; i++ {
+		require.NoError(t, bus.PublishAsync(ctx, newMoneyConverted(t)))
+	}
+	cancel() // handlers must not observe the publisher's cancellation
+
+	require.NoError(t, bus.Close())
+	assert.Equal(t, int32(10), handled.Load())
+	assert.ErrorIs(t, bus.PublishAsync(context.Background(), newMoneyConverted(t)), events.ErrBusClosed)
+	assert.NoError(t, bus.Close())
+}
+
+func TestBus_ConcurrentSubscribeAndPublish(t *testing.T) {
+	bus := events.NewBus(zap.NewNop())
+	defer bus.Close()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			bus.Subscribe("money.converted", func(context.Context, events.Event) error { return nil })
+		}()
+		go func() {
+			defer wg.Done()
+			assert.NoError(t, bus.Publish(context.Background(), newMoneyConverted(t)))
+		}()
+	}
+	wg.Wait()
+}
+
+func TestNewEnvelope(t *testing.T) {
+	event := newMoneyConverted(t)
+
+	envelope, err := events.NewEnvelope(event)
+	require.NoError(t, err)
+
+	assert.Equal(t, event.Metadata.ID, envelope.ID)
+	assert.Equal(t, "money.converted", envelope.Name)
+	assert.Equal(t, "wallet", envelope.AggregateType)
+	assert.Equal(t, "w-1", envelope.AggregateID)
+	assert.Equal(t, testClock.Now().Unix(), envelope.OccurredAt)
+	assert.Equal(t, "UTC", envelope.Timezone)
+
+	var payload struct {
+		From struct {
+			Amount int64 `json:"amount"`
+		} `json:"from"`
+	}
+	require.NoError(t, json.Unmarshal(envelope.Payload, &payload))
+	assert.Equal(t, int64(10000), payload.From.Amount)
+}
+
+func TestForward_Outbox(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	event := newMoneyConverted(t)
+	mock.ExpectExec("INSERT INTO event_outbox").
+		WithArgs(event.Metadata.ID, "money.converted", 1, "wallet", "w-1", testClock.Now().Unix(), "UTC", sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	bus := events.NewBus(zap.NewNop())
+	defer bus.Close()
+	bus.Subscribe(events.AllEvents, events.Forward(events.NewOutbox(db)))
+
+	require.NoError(t, bus.Publish(context.Background(), event))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestForward_RedisBroker(t *testing.T) {
+	_, client := testutil.NewRedis(t)
+
+	ctx := context.Background()
+	subscription := client.Subscribe(ctx, "events.money.converted")
+	defer subscription.Close()
+	_, err := subscription.Receive(ctx)
+	require.NoError(t, err)
+
+	bus := events.NewBus(zap.NewNop())
+	defer bus.Close()
+	bus.Subscribe("money.converted", events.Forward(events.NewRedisBroker(client, "events.")))
+
+	event := newMoneyConverted(t)
+	require.NoError(t, bus.Publish(ctx, event))
+
+	message, err := subscription.ReceiveMessage(ctx)
+	require.NoError(t, err)
+
+	var envelope events.Envelope
+	require.NoError(t, json.Unmarshal([]byte(message.Payload), &envelope))
+	assert.Equal(t, event.Metadata.ID, envelope.ID)
+}
+
+func TestTestContainer_FakeBroker(t *testing.T) {
+	tc, err := bootstrap.NewTestContainer()
+	require.NoError(t, err)
+	defer tc.Close()
+
+	tc.Events.Subscribe(events.AllEvents, events.Forward(tc.Broker))
+
+	event := newMoneyConverted(t)
+	require.NoError(t, tc.Events.Publish(context.Background(), event))
+	require.Len(t, tc.FakeBroker.Published(), 1)
+	assert.Equal(t, event.Metadata.ID, tc.FakeBroker.Published()[0].ID)
+
+	errDown := errors.New("broker down")
+	tc.FakeBroker.FailWith(errDown)
+	assert.ErrorIs(t, tc.Events.Publish(context.Background(), event), errDown)
+
+	tc.FakeBroker.Reset()
+	assert.Empty(t, tc.FakeBroker.Published())
+}
@@ -0,0 +1,115 @@
+package events_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"golang-arch/internal/shared/events"
+	"golang-arch/pkg/clock"
+)
+
+func TestIdempotent_SkipsRedeliveries(t *testing.T) {
+	ledger := events.NewMemoryLedger(clock.NewFake(testClock.Now()))
+	bus := events.NewBus(zap.NewNop())
+	defer bus.Close()
+
+	var credited, audited int
+	bus.Subscribe("money.converted", events.Idempotent(ledger, "wallet.credit", func(context.Context, events.Event) error {
+		credited++
+		return nil
+	}))
+	bus.Subscribe("money.converted", events.Idempotent(ledger, "audit.record", func(context.Context, events.Event) error {
+		audited++
+		return nil
+	}))
+
+	ctx := context.Background()
+	event := newMoneyConverted(t)
+	require.NoError(t, bus.Publish(ctx, event))
+	require.NoError(t, bus.Publish(ctx, event))
+	assert.Equal(t, 1, credited)
+	assert.Equal(t, 1, audited, "each handler processes the message once")
+
+	require.NoError(t, bus.Publish(ctx, newMoneyConverted(t)))
+	assert.Equal(t, 2, credited)
+}
+
+func TestIdempotent_RetriesFailedHandling(t *testing.T) {
+	ledger := events.NewMemoryLedger(clock.NewFake(testClock.Now()))
+	attempts := 0
+	consume := events.IdempotentEnvelope(ledger, "ledger.sync", func(context.Context, events.Envelope) error {
+		attempts++
+		if attempts == 1 {
+			return errors.New("ledger unavailable")
+		}
+		return nil
+	})
+
+	ctx := context.Background()
+	envelope := events.Envelope{ID: "m-1", Name: "money.converted"}
+	assert.Error(t, consume(ctx, envelope))
+	require.NoError(t, consume(ctx, envelope), "a failed message is handled again")
+	require.NoError(t, consume(ctx, envelope))
+	assert.Equal(t, 2, attempts)
+}
+
+func TestMemoryLedger_Prune(t *testing.T) {
+	clk := clock.NewFake(testClock.Now())
+	ledger := events.NewMemoryLedger(clk)
+	ctx := context.Background()
+
+	_, err := ledger.Claim(ctx, "m-1", "wallet.credit")
+	require.NoError(t, err)
+	clk.Advance(time.Hour)
+	_, err = ledger.Claim(ctx, "m-2", "wallet.credit")
+	require.NoError(t, err)
+
+	pruned, err := ledger.Prune(ctx, clk.Now().Add(-time.Minute))
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, pruned)
+
+	claimed, err := ledger.Claim(ctx, "m-1", "wallet.credit")
+	require.NoError(t, err)
+	assert.True(t, claimed, "a pruned message is processed again")
+	claimed, err = ledger.Claim(ctx, "m-2", "wallet.credit")
+	require.NoError(t, err)
+	assert.False(t, claimed)
+}
+
+func TestPostgresLedger_Once(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectExec("INSERT INTO processed_messages").WithArgs("m-1", "wallet.credit").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("INSERT INTO processed_messages").WithArgs("m-1", "wallet.credit").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("INSERT INTO processed_messages").WithArgs("m-2", "wallet.credit").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("DELETE FROM processed_messages WHERE message_id").WithArgs("m-2", "wallet.credit").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	ledger := events.NewPostgresLedger(db)
+	ctx := context.Background()
+	runs := 0
+	credit := func(context.Context) error {
+		runs++
+		return nil
+	}
+	require.NoError(t, events.Once(ctx, ledger, "m-1", "wallet.credit", credit))
+	require.NoError(t, events.Once(ctx, ledger, "m-1", "wallet.credit", credit))
+	assert.Equal(t, 1, runs, "a recorded message is skipped")
+
+	errDown := errors.New("wallet down")
+	err = events.Once(ctx, ledger, "m-2", "wallet.credit", func(context.Context) error { return errDown })
+	assert.ErrorIs(t, err, errDown)
+	assert.NoError(t, mock.ExpectationsWereMet(), "a failed handler releases its claim")
+}
@@ -0,0 +1,116 @@
+package events_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"golang-arch/internal/shared/events"
+)
+
+// orderPlaced is version 2 of order.placed; version 1 had a single "amount"
+// in cents and no currency
+type orderPlaced struct {
+	events.Base
+	OrderID  string `json:"order_id"`
+	Total    int64  `json:"total"`
+	Currency string `json:"currency"`
+}
+
+func (orderPlaced) EventName() string { return "order.placed" }
+
+func (e orderPlaced) Validate() error {
+	if e.OrderID == "" || e.Currency == "" {
+		return errors.New("order_id and currency are required")
+	}
+	return nil
+}
+
+func orderPlacedSchema() events.Schema {
+	return events.Schema{
+		Name:    "order.placed",
+		Version: 2,
+		Type:    orderPlaced{},
+		Upcasters: map[int]events.Upcaster{
+			1: func(payload json.RawMessage) (json.RawMessage, error) {
+				var v1 map[string]any
+				if err := json.Unmarshal(payload, &v1); err != nil {
+					return nil, err
+				}
+				v1["total"], v1["currency"] = v1["amount"], "USD"
+				delete(v1, "amount")
+				return json.Marshal(v1)
+			},
+		},
+	}
+}
+
+func newRegistry(t *testing.T) *events.Registry {
+	t.Helper()
+	registry := events.NewRegistry()
+	require.NoError(t, registry.Register(orderPlacedSchema()))
+	return registry
+}
+
+func TestRegistry_Register(t *testing.T) {
+	registry := newRegistry(t)
+	assert.Error(t, registry.Register(orderPlacedSchema()), "duplicate")
+
+	missing := orderPlacedSchema()
+	missing.Upcasters = nil
+	assert.Error(t, events.NewRegistry().Register(missing), "no upcaster from v1")
+
+	mismatched := orderPlacedSchema()
+	mismatched.Name = "order.shipped"
+	assert.Error(t, events.NewRegistry().Register(mismatched))
+}
+
+func TestRegistry_PublisherSealsEnvelopes(t *testing.T) {
+	registry := newRegistry(t)
+	broker := events.NewMemoryBroker()
+	publish := events.Forward(registry.Publisher(broker))
+	ctx := context.Background()
+
+	placed := orderPlaced{Base: events.Base{Metadata: events.NewMetadata(testClock, "order", "o-1")},
+		OrderID: "o-1", Total: 1250, Currency: "EUR"}
+	require.NoError(t, publish(ctx, placed))
+	require.Len(t, broker.Published(), 1)
+	assert.Equal(t, 2, broker.Published()[0].Version)
+
+	placed.Currency = ""
+	err := publish(ctx, placed)
+	assert.ErrorIs(t, err, events.ErrInvalidPayload)
+	assert.Len(t, broker.Published(), 1, "invalid events are not sent")
+
+	require.NoError(t, publish(ctx, newMoneyConverted(t)))
+	assert.Equal(t, 1, broker.Published()[1].Version, "events without a schema pass through")
+}
+
+func TestRegistry_ConsumerUpcasts(t *testing.T) {
+	registry := newRegistry(t)
+	var received events.Envelope
+	consume := registry.Consumer(func(_ context.Context, envelope events.Envelope) error {
+		received = envelope
+		return nil
+	})
+	ctx := context.Background()
+
+	v1 := events.Envelope{ID: "e-1", Name: "order.placed", Payload: json.RawMessage(`{"order_id":"o-1","amount":990}`)}
+	require.NoError(t, consume(ctx, v1))
+	assert.Equal(t, 2, received.Version)
+	assert.JSONEq(t, `{"order_id":"o-1","total":990,"currency":"USD"}`, string(received.Payload))
+
+	event, err := registry.Decode(v1)
+	require.NoError(t, err)
+	assert.Equal(t, orderPlaced{OrderID: "o-1", Total: 990, Currency: "USD"}, event)
+
+	v3 := events.Envelope{ID: "e-2", Name: "order.placed", Version: 3, Payload: json.RawMessage(`{}`)}
+	assert.ErrorIs(t, consume(ctx, v3), events.ErrUnknownVersion)
+
+	invalid := events.Envelope{ID: "e-3", Name: "order.placed", Version: 2, Payload: json.RawMessage(`{"total":"ten"}`)}
+	assert.ErrorIs(t, consume(ctx, invalid), events.ErrInvalidPayload)
+}
@@ -0,0 +1,222 @@
+package eventsource_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"golang-arch/internal/shared/domain/domainerror"
+	"golang-arch/internal/shared/events"
+	"golang-arch/internal/shared/eventsource"
+	"golang-arch/pkg/clock"
+)
+
+var start = time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+type deposited struct {
+	events.Base
+	Amount int64 `json:"amount"`
+}
+
+func (deposited) EventName() string { return "account.deposited" }
+
+type withdrawn struct {
+	events.Base
+	Amount int64 `json:"amount"`
+}
+
+func (withdrawn) EventName() string { return "account.withdrawn" }
+
+// account is an event-sourced balance
+type account struct {
+	Balance int64 `json:"balance"`
+	Entries int   `json:"entries"`
+}
+
+func (a *account) Apply(event events.Event) error {
+	switch e := event.(type) {
+	case deposited:
+		a.Balance += e.Amount
+	case withdrawn:
+		if e.Amount > a.Balance {
+			return errors.New("insufficient funds")
+		}
+		a.Balance -= e.Amount
+	default:
+		return errors.New("unknown event " + event.EventName())
+	}
+	a.Entries++
+	return nil
+}
+
+type fixture struct {
+	clock    *clock.Fake
+	store    *eventsource.MemoryStore
+	schemas  *events.Registry
+	accounts *eventsource.Repository[*account]
+}
+
+func newFixture(t *testing.T, options ...eventsource.Option) *fixture {
+	t.Helper()
+	f := &fixture{clock: clock.NewFake(start), schemas: events.NewRegistry()}
+	f.store = eventsource.NewMemoryStore(f.clock)
+	require.NoError(t, f.schemas.Register(events.Schema{Name: "account.deposited", Version: 1, Type: deposited{}}))
+	// withdrawn v1 stored the amount as "value"
+	require.NoError(t, f.schemas.Register(events.Schema{
+		Name: "account.withdrawn", Version: 2, Type: withdrawn{},
+		Upcasters: map[int]events.Upcaster{1: func(payload json.RawMessage) (json.RawMessage, error) {
+			var v1 struct {
+				Value int64 `json:"value"`
+			}
+			if err := json.Unmarshal(payload, &v1); err != nil {
+				return nil, err
+			}
+			return json.Marshal(map[string]int64{"amount": v1.Value})
+		}},
+	}))
+	options = append([]eventsource.Option{eventsource.WithClock(f.clock)}, options...)
+	f.accounts = eventsource.NewRepository(f.store, f.schemas, "account", func() *account { return &account{} }, options...)
+	return f
+}
+
+func (f *fixture) deposit(amount int64) deposited {
+	return deposited{Base: events.Base{Metadata: events.NewMetadata(f.clock, "account", "a-1")}, Amount: amount}
+}
+
+func TestRepository_SaveAndLoad(t *testing.T) {
+	f := newFixture(t)
+	ctx := context.Background()
+
+	_, err := f.accounts.Load(ctx, "a-1")
+	assert.Equal(t, domainerror.NotFound, domainerror.KindOf(err))
+
+	stream := f.accounts.New("a-1")
+	require.NoError(t, stream.Record(f.deposit(100)))
+	require.NoError(t, stream.Record(withdrawn{Amount: 30}))
+	assert.Error(t, stream.Record(withdrawn{Amount: 500}), "the aggregate rejects invalid changes")
+	require.NoError(t, f.accounts.Save(ctx, stream))
+	assert.Equal(t, 2, stream.Version)
+	assert.Empty(t, stream.Pending())
+
+	loaded, err := f.accounts.Load(ctx, "a-1")
+	require.NoError(t, err)
+	assert.Equal(t, 2, loaded.Version)
+	assert.Equal(t, &account{Balance: 70, Entries: 2}, loaded.State)
+
+	history, err := f.accounts.History(ctx, "a-1")
+	require.NoError(t, err)
+	require.Len(t, history, 2)
+	assert.Equal(t, "account.withdrawn", history[1].Envelope.Name)
+	assert.Equal(t, 2, history[1].Envelope.Version, "events are stored with their schema version")
+	assert.Equal(t, "a-1", history[1].Envelope.AggregateID)
+}
+
+func TestRepository_ConcurrentSaveConflicts(t *testing.T) {
+	f := newFixture(t)
+	ctx := context.Background()
+	stream := f.accounts.New("a-1")
+	require.NoError(t, stream.Record(f.deposit(100)))
+	require.NoError(t, f.accounts.Save(ctx, stream))
+
+	first, err := f.accounts.Load(ctx, "a-1")
+	require.NoError(t, err)
+	second, err := f.accounts.Load(ctx, "a-1")
+	require.NoError(t, err)
+	require.NoError(t, first.Record(withdrawn{Amount: 80}))
+	require.NoError(t, second.Record(withdrawn{Amount: 80}))
+
+	require.NoError(t, f.accounts.Save(ctx, first))
+	err = f.accounts.Save(ctx, second)
+	assert.Equal(t, domainerror.Conflict, domainerror.KindOf(err), "the second writer must reload")
+}
+
+func TestRepository_Snapshots(t *testing.T) {
+	f := newFixture(t, eventsource.WithSnapshotEvery(3))
+	ctx := context.Background()
+	stream := f.accounts.New("a-1")
+	for range 4 {
+		require.NoError(t, stream.Record(f.deposit(10)))
+		require.NoError(t, f.accounts.Save(ctx, stream))
+	}
+
+	snapshot, found, err := f.store.LoadSnapshot(ctx, "account", "a-1")
+	require.NoError(t, err)
+	require.True(t, found)
+	assert.Equal(t, 3, snapshot.Version)
+	assert.JSONEq(t, `{"balance":30,"entries":3}`, string(snapshot.State))
+
+	// A snapshot that disagrees with the events shows Load starts from it
+	require.NoError(t, f.store.SaveSnapshot(ctx, "account", "a-1",
+		eventsource.Snapshot{Version: 4, State: json.RawMessage(`{"balance":999,"entries":4}`)}))
+	loaded, err := f.accounts.Load(ctx, "a-1")
+	require.NoError(t, err)
+	assert.EqualValues(t, 999, loaded.State.Balance)
+
+	replayed, err := f.accounts.Replay(ctx, "a-1", 0)
+	require.NoError(t, err)
+	assert.EqualValues(t, 40, replayed.State.Balance, "replay ignores snapshots")
+	replayed, err = f.accounts.Replay(ctx, "a-1", 2)
+	require.NoError(t, err)
+	assert.Equal(t, 2, replayed.Version)
+	assert.EqualValues(t, 20, replayed.State.Balance)
+}
+
+func TestRepository_UpcastsStoredEvents(t *testing.T) {
+	f := newFixture(t)
+	ctx := context.Background()
+	require.NoError(t, f.store.Append(ctx, "account", "a-1", 0, []events.Envelope{
+		{ID: "e-1", Name: "account.deposited", Version: 1, Payload: json.RawMessage(`{"amount":50}`)},
+		{ID: "e-2", Name: "account.withdrawn", Version: 1, Payload: json.RawMessage(`{"value":20}`)},
+	}))
+
+	loaded, err := f.accounts.Load(ctx, "a-1")
+	require.NoError(t, err)
+	assert.EqualValues(t, 30, loaded.State.Balance)
+}
+
+func TestRepository_PublishesSavedEvents(t *testing.T) {
+	bus := events.NewBus(zap.NewNop())
+	defer bus.Close()
+	f := newFixture(t, eventsource.WithEvents(bus))
+	received := make(chan deposited, 1)
+	bus.Subscribe("account.deposited", func(_ context.Context, event events.Event) error {
+		received <- event.(deposited)
+		return nil
+	})
+
+	stream := f.accounts.New("a-1")
+	require.NoError(t, stream.Record(f.deposit(25)))
+	require.NoError(t, f.accounts.Save(context.Background(), stream))
+	select {
+	case event := <-received:
+		assert.EqualValues(t, 25, event.Amount)
+	case <-time.After(time.Second):
+		t.Fatal("no account.deposited event")
+	}
+}
+
+func TestMemoryStore_ReadAll(t *testing.T) {
+	f := newFixture(t)
+	ctx := context.Background()
+	for _, id := range []string{"a-1", "a-2", "a-1"} {
+		stream, err := f.accounts.Load(ctx, id)
+		if err != nil {
+			stream = f.accounts.New(id)
+		}
+		require.NoError(t, stream.Record(f.deposit(5)))
+		require.NoError(t, f.accounts.Save(ctx, stream))
+	}
+
+	records, err := f.store.ReadAll(ctx, 1, 10)
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+	assert.Equal(t, int64(2), records[0].Position)
+	assert.Equal(t, "a-2", records[0].Envelope.AggregateID)
+	assert.Equal(t, 2, records[1].Version)
+}
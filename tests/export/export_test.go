@@ -0,0 +1,317 @@
+package export_test
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	i18n "golang-arch/internal/shared/domain/internationalization"
+	"golang-arch/internal/shared/events"
+	"golang-arch/internal/shared/export"
+	"golang-arch/internal/shared/geo"
+	"golang-arch/internal/shared/jobs"
+	"golang-arch/internal/shared/storage"
+	"golang-arch/internal/shared/templates"
+	"golang-arch/internal/shared/testutil"
+	"golang-arch/pkg/clock"
+)
+
+type orderRow struct {
+	ID       string                 `csv:"id" json:"id"`
+	Quantity int                    `csv:"quantity" json:"quantity"`
+	Total    i18n.Money             `csv:"total" json:"total"`
+	PlacedAt i18n.LocalizedDateTime `csv:"placed_at" json:"placed_at"`
+}
+
+func orders(t *testing.T) []orderRow {
+	t.Helper()
+	total, err := i18n.NewMoneyFromPrimitive(123450, "EUR")
+	require.NoError(t, err)
+	placed, err := i18n.MakeLocalizedDateTime(1718452800, "UTC") // 2024-06-15 12:00 UTC
+	require.NoError(t, err)
+	return []orderRow{
+		{ID: "A-1", Quantity: 3, Total: *total, PlacedAt: placed},
+		{ID: "A-2", Quantity: 1, Total: *total, PlacedAt: placed},
+	}
+}
+
+func query(rows []orderRow) export.Query {
+	return func(ctx context.Context, emit func(row any) error) error {
+		for _, row := range rows {
+			if err := emit(row); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+type fixture struct {
+	service *export.Service
+	store   *jobs.Store
+	blobs   *storage.MemoryStore
+	bus     *events.Bus
+}
+
+func newFixture(t *testing.T, options ...export.Option) *fixture {
+	t.Helper()
+	_, client := testutil.NewRedis(t)
+
+	clk := clock.NewFake(time.Date(2024, 6, 15, 12, 0, 0, 0, time.UTC))
+	f := &fixture{
+		store: jobs.NewStore(client, jobs.WithPollInterval(5*time.Millisecond)),
+		blobs: storage.NewMemoryStore(clk),
+		bus:   events.NewBus(zap.NewNop()),
+	}
+	options = append([]export.Option{export.WithClock(clk), export.WithEvents(f.bus)}, options...)
+	f.service = export.NewService(f.store, f.blobs, options...)
+	t.Cleanup(func() {
+		f.service.Close()
+		f.bus.Close()
+	})
+	return f
+}
+
+// await returns the job once it is done
+func (f *fixture) await(t *testing.T, id string) *jobs.Record {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	for {
+		record, err := f.store.Wait(ctx, id, 1<<62)
+		require.NoError(t, err)
+		if record.State.Done() {
+			return record
+		}
+		require.NoError(t, ctx.Err(), "export did not finish")
+	}
+}
+
+// file runs an export and returns its result and file
+func (f *fixture) file(t *testing.T, req export.Request) (export.Result, []byte) {
+	t.Helper()
+	record, err := f.service.Start(context.Background(), req)
+	require.NoError(t, err)
+	assert.Equal(t, export.JobKind, record.Kind)
+
+	record = f.await(t, record.ID)
+	require.Equal(t, jobs.StateSucceeded, record.State, record.Error)
+	var result export.Result
+	require.NoError(t, json.Unmarshal(record.Result, &result))
+
+	body, info, err := f.blobs.Get(context.Background(), result.Key)
+	require.NoError(t, err)
+	defer body.Close()
+	assert.Equal(t, req.Format.ContentType(), info.ContentType)
+	data, err := io.ReadAll(body)
+	require.NoError(t, err)
+	return result, data
+}
+
+func TestService_CSVInReaderLocale(t *testing.T) {
+	f := newFixture(t)
+	result, data := f.file(t, export.Request{
+		Name:    "orders",
+		Format:  export.FormatCSV,
+		Options: export.Options{Locale: "de-DE", Timezone: "Europe/Berlin"},
+		Query:   query(orders(t)),
+	})
+
+	assert.Equal(t, "orders.csv", result.Filename)
+	assert.Equal(t, int64(2), result.Rows)
+	assert.Equal(t, int64(len(data)), result.Size)
+	assert.True(t, strings.HasPrefix(result.Key, "exports/2024/06/15/"), result.Key)
+	assert.NotEmpty(t, result.URL)
+	assert.Equal(t, time.Date(2024, 6, 16, 12, 0, 0, 0, time.UTC), result.ExpiresAt.UTC())
+	assert.Equal(t, "id;quantity;total_amount;total_currency;placed_at;placed_at_tz\n"+
+		"A-1;3;1234,50;EUR;2024-06-15 14:00:00;Europe/Berlin\n"+
+		"A-2;1;1234,50;EUR;2024-06-15 14:00:00;Europe/Berlin\n", string(data))
+}
+
+func TestService_XLSX(t *testing.T) {
+	f := newFixture(t)
+	_, data := f.file(t, export.Request{
+		Name:    "orders",
+		Format:  export.FormatXLSX,
+		Options: export.Options{Locale: "de-DE"},
+		Query:   query(orders(t)[:1]),
+	})
+
+	archive, err := zip.NewReader(strings.NewReader(string(data)), int64(len(data)))
+	require.NoError(t, err)
+	var sheet string
+	for _, file := range archive.File {
+		if file.Name == "xl/worksheets/sheet1.xml" {
+			body, err := file.Open()
+			require.NoError(t, err)
+			content, err := io.ReadAll(body)
+			require.NoError(t, err)
+			sheet = string(content)
+		}
+	}
+	assert.Contains(t, sheet, `<c t="inlineStr" s="1"><is><t>total_amount</t></is></c>`)
+	// Amounts and counts are numbers in neutral notation, whatever the locale
+	assert.Contains(t, sheet, `<c><v>3</v></c><c><v>1234.50</v></c>`)
+	assert.Contains(t, sheet, `<t xml:space="preserve">2024-06-15 12:00:00</t>`)
+}
+
+func TestService_JSON(t *testing.T) {
+	f := newFixture(t)
+	_, data := f.file(t, export.Request{Name: "orders", Format: export.FormatJSON, Query: query(orders(t))})
+
+	var rows []map[string]any
+	require.NoError(t, json.Unmarshal(data, &rows), string(data))
+	require.Len(t, rows, 2)
+	assert.Equal(t, "A-2", rows[1]["id"])
+
+	_, data = f.file(t, export.Request{Name: "empty", Format: export.FormatJSON, Query: query(nil)})
+	assert.JSONEq(t, `[]`, string(data))
+}
+
+func TestService_ProgressAndEvents(t *testing.T) {
+	f := newFixture(t, export.WithProgressInterval(1))
+	completed := make(chan export.Completed, 1)
+	f.bus.Subscribe(export.EventCompleted, func(ctx context.Context, event events.Event) error {
+		completed <- event.(export.Completed)
+		return nil
+	})
+
+	result, _ := f.file(t, export.Request{Name: "orders", Requester: "alice", Query: query(orders(t))})
+	select {
+	case event := <-completed:
+		assert.Equal(t, "alice", event.Requester)
+		assert.Equal(t, result, event.Result)
+		assert.Equal(t, export.JobKind, event.EventMetadata().AggregateType)
+	case <-time.After(time.Second):
+		t.Fatal("no export.completed event")
+	}
+
+	record, err := f.store.Get(context.Background(), jobID(t, result))
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), record.Progress.Done)
+	assert.Equal(t, "job.export.rows", record.Progress.Message)
+	assert.Equal(t, "2", record.Progress.Args["rows"])
+}
+
+// jobID returns the job ID of a result from its blob key
+func jobID(t *testing.T, result export.Result) string {
+	t.Helper()
+	parts := strings.Split(result.Key, "/")
+	require.Len(t, parts, 6)
+	return parts[4]
+}
+
+func TestService_Failure(t *testing.T) {
+	f := newFixture(t)
+	failed := make(chan export.Failed, 1)
+	f.bus.Subscribe(export.EventFailed, func(ctx context.Context, event events.Event) error {
+		failed <- event.(export.Failed)
+		return nil
+	})
+
+	record, err := f.service.Start(context.Background(), export.Request{
+		Name: "orders",
+		Query: func(ctx context.Context, emit func(row any) error) error {
+			if err := emit(orders(t)[0]); err != nil {
+				return err
+			}
+			return errors.New("connection reset by peer")
+		},
+	})
+	require.NoError(t, err)
+
+	record = f.await(t, record.ID)
+	assert.Equal(t, jobs.StateFailed, record.State)
+	assert.Equal(t, "the export could not be completed", record.Error, "internal causes are not shown")
+	select {
+	case event := <-failed:
+		assert.Equal(t, record.ID, event.JobID)
+	case <-time.After(time.Second):
+		t.Fatal("no export.failed event")
+	}
+
+	_, err = f.service.Start(context.Background(), export.Request{
+		Name: "orders", Options: export.Options{Timezone: "Mars/Olympus"}, Query: query(nil),
+	})
+	assert.Error(t, err)
+}
+
+func TestNegotiate(t *testing.T) {
+	for _, tc := range []struct {
+		format, accept string
+		want           export.Format
+	}{
+		{"", "", export.FormatCSV},
+		{"", "*/*", export.FormatCSV},
+		{"", "application/json", export.FormatJSON},
+		{"", "text/html, " + export.ContentTypeXLSX + ";q=0.9", export.FormatXLSX},
+		{"XLSX", "application/json", export.FormatXLSX},
+	} {
+		got, err := export.Negotiate(tc.format, tc.accept)
+		require.NoError(t, err)
+		assert.Equal(t, tc.want, got, "%q %q", tc.format, tc.accept)
+	}
+
+	_, err := export.Negotiate("pdf", "")
+	assert.Error(t, err)
+}
+
+func TestHandler_Start(t *testing.T) {
+	f := newFixture(t)
+	views, err := templates.NewEngine()
+	require.NoError(t, err)
+	handler := export.NewHandler(f.service, views, "/api/v1/jobs")
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		location := geo.Location{Country: "FR", Timezone: "Europe/Paris", Locale: "fr-FR", Detected: true}
+		c.Request = c.Request.WithContext(geo.NewContext(c.Request.Context(), location))
+	})
+	router.GET("/orders/export", func(c *gin.Context) {
+		handler.Start(c, "orders", query(orders(t)))
+	})
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/orders/export?format=csv", nil))
+	require.Equal(t, http.StatusAccepted, rec.Code, rec.Body.String())
+	var body struct {
+		Data jobs.Record `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	assert.Equal(t, "/api/v1/jobs/"+body.Data.ID, rec.Header().Get("Location"))
+
+	record := f.await(t, body.Data.ID)
+	var result export.Result
+	require.NoError(t, json.Unmarshal(record.Result, &result))
+	_, data := download(t, f.blobs, result.Key)
+	assert.Contains(t, data, "A-1;3;1234,50;EUR;2024-06-15 14:00:00;Europe/Paris")
+
+	for _, path := range []string{"/orders/export?format=pdf", "/orders/export?timezone=Mars/Olympus"} {
+		rec = httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		assert.Equal(t, http.StatusBadRequest, rec.Code, path)
+	}
+}
+
+func download(t *testing.T, blobs storage.BlobStore, key string) (storage.Info, string) {
+	t.Helper()
+	var b strings.Builder
+	_, err := storage.Download(context.Background(), blobs, key, &b)
+	require.NoError(t, err)
+	info, err := blobs.Stat(context.Background(), key)
+	require.NoError(t, err)
+	return info, b.String()
+}
@@ -0,0 +1,177 @@
+package fieldcrypt_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"golang-arch/internal/shared/config"
+	i18n "golang-arch/internal/shared/domain/internationalization"
+	"golang-arch/internal/shared/fieldcrypt"
+)
+
+var (
+	oldKey = base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{1}, fieldcrypt.KeySize))
+	newKey = base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{2}, fieldcrypt.KeySize))
+)
+
+func encryptor(t *testing.T, spec, primary string) *fieldcrypt.Encryptor {
+	t.Helper()
+	provider, err := fieldcrypt.NewStaticKeyProvider(spec, primary)
+	require.NoError(t, err)
+	e, err := fieldcrypt.NewEncryptor(context.Background(), provider)
+	require.NoError(t, err)
+	return e
+}
+
+func phone(t *testing.T, value string) i18n.Phone {
+	t.Helper()
+	p, err := i18n.NewPhoneFromString(value)
+	require.NoError(t, err)
+	return *p
+}
+
+func TestEncryptDecrypt(t *testing.T) {
+	e := encryptor(t, "2024-01="+oldKey, "")
+	aad := []byte("customers.note")
+
+	first, err := e.Encrypt([]byte("secret"), aad)
+	require.NoError(t, err)
+	second, err := e.Encrypt([]byte("secret"), aad)
+	require.NoError(t, err)
+	assert.NotEqual(t, first, second, "nonces are random")
+	assert.NotContains(t, string(first), "secret")
+
+	plaintext, err := e.Decrypt(first, aad)
+	require.NoError(t, err)
+	assert.Equal(t, "secret", string(plaintext))
+
+	_, err = e.Decrypt(first, []byte("customers.other"))
+	assert.Error(t, err, "ciphertexts are bound to their column")
+
+	tampered := bytes.Clone(first)
+	tampered[len(tampered)-1] ^= 1
+	_, err = e.Decrypt(tampered, aad)
+	assert.Error(t, err)
+
+	_, err = e.Decrypt([]byte("plain text"), aad)
+	assert.ErrorIs(t, err, fieldcrypt.ErrMalformed)
+}
+
+func TestKeyRotation(t *testing.T) {
+	old := encryptor(t, "2024-01="+oldKey, "")
+	field := old.Field("customers.phone")
+	sealed, err := field.Seal(phone(t, "+15551234567"))
+	require.NoError(t, err)
+
+	// The newest ID becomes primary; the retired key still decrypts
+	rotated := encryptor(t, "2024-01="+oldKey+", 2024-07="+newKey, "")
+	assert.Equal(t, "2024-07", rotated.PrimaryKeyID())
+	field = rotated.Field("customers.phone")
+	assert.True(t, rotated.NeedsRotation(sealed))
+
+	var decoded i18n.Phone
+	require.NoError(t, field.Open(sealed, &decoded))
+	assert.Equal(t, "+15551234567", decoded.FormatCompact())
+
+	resealed, err := field.Rotate(sealed)
+	require.NoError(t, err)
+	id, err := fieldcrypt.KeyID(resealed)
+	require.NoError(t, err)
+	assert.Equal(t, "2024-07", id)
+	assert.False(t, rotated.NeedsRotation(resealed))
+
+	unchanged, err := field.Rotate(resealed)
+	require.NoError(t, err)
+	assert.Equal(t, resealed, unchanged)
+
+	// Once the old key is dropped, unrotated values no longer open
+	current := encryptor(t, "2024-07="+newKey, "")
+	assert.Error(t, current.Field("customers.phone").Open(sealed, &decoded))
+	assert.NoError(t, current.Field("customers.phone").Open(resealed, &decoded))
+
+	// An explicit primary wins over sort order
+	pinned := encryptor(t, "2024-01="+oldKey+",2024-07="+newKey, "2024-01")
+	assert.Equal(t, "2024-01", pinned.PrimaryKeyID())
+}
+
+func TestFieldValueAndScan(t *testing.T) {
+	e := encryptor(t, "k1="+oldKey, "")
+	phones := e.Field("customers.phone")
+
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	stored, err := phones.Value(phone(t, "+4917612345678")).Value()
+	require.NoError(t, err)
+	assert.IsType(t, []byte{}, stored)
+
+	mock.ExpectExec("UPDATE customers").
+		WithArgs(sqlmock.AnyArg(), 7).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	_, err = db.Exec("UPDATE customers SET phone = $1 WHERE id = $2", phones.Value(phone(t, "+4917612345678")), 7)
+	require.NoError(t, err)
+
+	mock.ExpectQuery("SELECT phone").WillReturnRows(sqlmock.NewRows([]string{"phone"}).AddRow(stored))
+	var scanned i18n.Phone
+	require.NoError(t, db.QueryRow("SELECT phone FROM customers WHERE id = $1", 7).Scan(phones.Scan(&scanned)))
+	assert.Equal(t, "+4917612345678", scanned.FormatCompact())
+
+	// NULL round-trips through nil pointers
+	var missing *i18n.Phone
+	value, err := phones.Value(missing).Value()
+	require.NoError(t, err)
+	assert.Nil(t, value)
+
+	optional := &scanned
+	require.NoError(t, phones.Scan(&optional).Scan(nil))
+	assert.Nil(t, optional)
+
+	assert.Error(t, phones.Scan(&scanned).Scan("not bytes"))
+	assert.Error(t, e.Field("customers.email").Scan(&scanned).Scan(stored))
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestFieldLocalizedPhone(t *testing.T) {
+	e := encryptor(t, "k1="+oldKey, "")
+	field := e.Field("customers.contact")
+
+	original, err := i18n.NewLocalizedPhoneFromPrimitive("+15551234567", "United States", "US-NY", "America/New_York")
+	require.NoError(t, err)
+	sealed, err := field.Seal(original)
+	require.NoError(t, err)
+
+	var decoded i18n.LocalizedPhone
+	require.NoError(t, field.Open(sealed, &decoded))
+	assert.Equal(t, original.Phone.FormatCompact(), decoded.Phone.FormatCompact())
+	assert.Equal(t, original.Region, decoded.Region)
+}
+
+func TestNewKeyProvider(t *testing.T) {
+	provider, err := fieldcrypt.NewKeyProvider(config.EncryptionConfig{Provider: "none"})
+	require.NoError(t, err)
+	assert.Nil(t, provider)
+
+	provider, err = fieldcrypt.NewKeyProvider(config.EncryptionConfig{Provider: "static", Keys: "k1=" + oldKey})
+	require.NoError(t, err)
+	assert.Equal(t, "static", provider.Name())
+
+	for _, cfg := range []config.EncryptionConfig{
+		{Provider: "static"},
+		{Provider: "static", Keys: "k1=c2hvcnQ="},
+		{Provider: "static", Keys: "k1=not base64"},
+		{Provider: "static", Keys: "=" + oldKey},
+		{Provider: "static", Keys: "k1=" + oldKey, PrimaryKey: "k2"},
+		{Provider: "vault"},
+		{Provider: "kms"},
+	} {
+		_, err := fieldcrypt.NewKeyProvider(cfg)
+		assert.Error(t, err, cfg.Keys)
+	}
+}
@@ -0,0 +1,142 @@
+package fieldcrypt_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"golang-arch/internal/shared/config"
+	"golang-arch/internal/shared/fieldcrypt"
+)
+
+// vault serves the KV v2 secret secret/app/encryption to token "root"
+type vault struct {
+	mu    sync.Mutex
+	keys  string
+	down  bool
+	reads int
+}
+
+func (v *vault) set(keys string) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.keys = keys
+}
+
+func (v *vault) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.reads++
+	if v.down {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+	if r.Header.Get("X-Vault-Token") != "root" {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+	if r.Method != http.MethodGet || r.URL.Path != "/v1/secret/data/app/encryption" {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	_ = json.NewEncoder(w).Encode(map[string]any{
+		"data": map[string]any{
+			"data":     map[string]string{"keys": v.keys},
+			"metadata": map[string]any{"version": v.reads},
+		},
+	})
+}
+
+func vaultConfig(address string) config.VaultConfig {
+	return config.VaultConfig{Address: address, Token: "root", Mount: "secret", Path: "app/encryption"}
+}
+
+func TestVaultKeyProvider(t *testing.T) {
+	secrets := &vault{keys: "2024-01=" + oldKey}
+	server := httptest.NewServer(secrets)
+	t.Cleanup(server.Close)
+
+	provider, err := fieldcrypt.NewKeyProvider(config.EncryptionConfig{Provider: "vault", Vault: vaultConfig(server.URL + "/")})
+	require.NoError(t, err)
+	assert.Equal(t, "vault", provider.Name())
+
+	e, err := fieldcrypt.NewEncryptor(context.Background(), provider)
+	require.NoError(t, err)
+	assert.Equal(t, "2024-01", e.PrimaryKeyID())
+
+	wrongToken := vaultConfig(server.URL)
+	wrongToken.Token = "guest"
+	provider, err = fieldcrypt.NewVaultKeyProvider(wrongToken)
+	require.NoError(t, err)
+	_, err = fieldcrypt.NewEncryptor(context.Background(), provider)
+	assert.ErrorContains(t, err, "vault answered 403")
+
+	secrets.set("2024-01=c2hvcnQ=")
+	provider, err = fieldcrypt.NewVaultKeyProvider(vaultConfig(server.URL))
+	require.NoError(t, err)
+	_, err = fieldcrypt.NewEncryptor(context.Background(), provider)
+	assert.Error(t, err, "invalid keys are rejected")
+}
+
+func TestNewVaultKeyProvider_Validation(t *testing.T) {
+	for name, cfg := range map[string]config.VaultConfig{
+		"no address":  {Token: "root", Mount: "secret", Path: "app"},
+		"bad address": {Address: "vault:8200", Token: "root", Mount: "secret", Path: "app"},
+		"no token":    {Address: "http://vault:8200", Mount: "secret", Path: "app"},
+		"no path":     {Address: "http://vault:8200", Token: "root", Mount: "secret"},
+	} {
+		_, err := fieldcrypt.NewVaultKeyProvider(cfg)
+		assert.Error(t, err, name)
+	}
+}
+
+func TestEncryptor_WatchPicksUpRotation(t *testing.T) {
+	secrets := &vault{keys: "2024-01=" + oldKey}
+	server := httptest.NewServer(secrets)
+	t.Cleanup(server.Close)
+
+	provider, err := fieldcrypt.NewVaultKeyProvider(vaultConfig(server.URL))
+	require.NoError(t, err)
+	e, err := fieldcrypt.NewEncryptor(context.Background(), provider)
+	require.NoError(t, err)
+	sealed, err := e.Field("customers.phone").Seal(phone(t, "+15551234567"))
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errs := make(chan error, 10)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		e.Watch(ctx, 10*time.Millisecond, func(err error) { errs <- err })
+	}()
+	t.Cleanup(func() {
+		cancel()
+		<-done
+	})
+
+	// A new key version in the secret becomes primary without a restart
+	secrets.set("2024-01=" + oldKey + ",2024-07=" + newKey)
+	require.Eventually(t, func() bool { return e.PrimaryKeyID() == "2024-07" }, 5*time.Second, 10*time.Millisecond)
+	assert.True(t, e.NeedsRotation(sealed))
+
+	// A failed reload is reported and keeps the current keys
+	secrets.mu.Lock()
+	secrets.down = true
+	secrets.mu.Unlock()
+	select {
+	case err := <-errs:
+		assert.ErrorContains(t, err, "vault answered 503")
+	case <-time.After(5 * time.Second):
+		t.Fatal("reload failure was not reported")
+	}
+	assert.Equal(t, "2024-07", e.PrimaryKeyID())
+	var decoded any
+	assert.NoError(t, e.Field("customers.phone").Open(sealed, &decoded))
+}
@@ -0,0 +1,122 @@
+package geo_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"path/filepath"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"golang-arch/internal/shared/config"
+	i18n "golang-arch/internal/shared/domain/internationalization"
+	"golang-arch/internal/shared/geo"
+)
+
+var (
+	defaults = geo.Location{Timezone: "UTC", Locale: "en-US"}
+
+	resolver = geo.StaticResolver{
+		netip.MustParsePrefix("203.0.113.0/24"):  {Country: "id"},
+		netip.MustParsePrefix("203.0.113.64/26"): {Country: "AT"},
+		netip.MustParsePrefix("198.51.100.0/24"): {Country: "US", Timezone: "America/Los_Angeles"},
+		netip.MustParsePrefix("2001:db8::/32"):   {Country: "JP"},
+		netip.MustParsePrefix("192.0.2.0/24"):    {Country: "AQ"},
+	}
+)
+
+func TestDetect(t *testing.T) {
+	tests := []struct {
+		name           string
+		ip             string
+		acceptLanguage string
+		want           geo.Location
+	}{
+		{"country defaults", "203.0.113.10", "", geo.Location{Country: "ID", Timezone: "Asia/Jakarta", Locale: "id-ID", Detected: true}},
+		{"most specific prefix", "203.0.113.70", "", geo.Location{Country: "AT", Timezone: "Europe/Vienna", Locale: "de-AT", Detected: true}},
+		{"database timezone wins", "198.51.100.1", "", geo.Location{Country: "US", Timezone: "America/Los_Angeles", Locale: "en-US", Detected: true}},
+		{"ipv6", "2001:db8::1", "", geo.Location{Country: "JP", Timezone: "Asia/Tokyo", Locale: "ja-JP", Detected: true}},
+		{"ipv4-mapped ipv6", "::ffff:203.0.113.10", "", geo.Location{Country: "ID", Timezone: "Asia/Jakarta", Locale: "id-ID", Detected: true}},
+		{"language gets the country as region", "203.0.113.70", "en;q=0.9, de;q=0.8", geo.Location{Country: "AT", Timezone: "Europe/Vienna", Locale: "en-AT", Detected: true}},
+		{"explicit region kept", "203.0.113.10", "en_gb", geo.Location{Country: "ID", Timezone: "Asia/Jakarta", Locale: "en-GB", Detected: true}},
+		{"script subtag", "2001:db8::1", "zh-hant", geo.Location{Country: "JP", Timezone: "Asia/Tokyo", Locale: "zh-Hant-JP", Detected: true}},
+		{"unknown country table entry", "192.0.2.1", "", geo.Location{Country: "AQ", Timezone: "UTC", Locale: "en-US", Detected: true}},
+		{"unknown address", "10.0.0.1", "", defaults},
+		{"unknown address with language", "10.0.0.1", "fr", geo.Location{Timezone: "UTC", Locale: "fr"}},
+		{"wildcard language ignored", "10.0.0.1", "*", defaults},
+		{"invalid address", "not-an-ip", "", defaults},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, geo.Detect(resolver, tt.ip, tt.acceptLanguage, defaults))
+		})
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(geo.Middleware(resolver, defaults))
+	router.GET("/whereami", func(c *gin.Context) {
+		location, ok := geo.FromContext(c.Request.Context())
+		if !ok {
+			c.Status(http.StatusNoContent)
+			return
+		}
+		c.JSON(http.StatusOK, location)
+	})
+
+	request := httptest.NewRequest(http.MethodGet, "/whereami", nil)
+	request.RemoteAddr = "203.0.113.10:52100"
+	request.Header.Set("Accept-Language", "en")
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	require.Equal(t, http.StatusOK, recorder.Code)
+	assert.JSONEq(t, `{"country":"ID","timezone":"Asia/Jakarta","locale":"en-ID","detected":true}`, recorder.Body.String())
+
+	// Authenticated requests use stored preferences instead
+	request = httptest.NewRequest(http.MethodGet, "/whereami", nil)
+	request.RemoteAddr = "203.0.113.10:52100"
+	request.Header.Set("Authorization", "Bearer token")
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+	assert.Equal(t, http.StatusNoContent, recorder.Code)
+}
+
+func TestFromContext_Empty(t *testing.T) {
+	_, ok := geo.FromContext(context.Background())
+	assert.False(t, ok)
+}
+
+func TestNewResolver(t *testing.T) {
+	resolver, err := geo.NewResolver(config.GeoConfig{})
+	require.NoError(t, err)
+	assert.IsType(t, geo.NopResolver{}, resolver)
+
+	_, err = geo.NewResolver(config.GeoConfig{DatabasePath: filepath.Join(t.TempDir(), "missing.mmdb")})
+	assert.Error(t, err)
+
+	fallback, err := i18n.NewLocaleDefaults("de-DE", "Europe/Berlin", "EUR", nil)
+	require.NoError(t, err)
+	assert.Equal(t, geo.Location{Country: "SG", Timezone: "Asia/Singapore", Locale: "en-SG"},
+		geo.Defaults(config.GeoConfig{DefaultCountry: "SG", DefaultTimezone: "Asia/Singapore", DefaultLocale: "en-SG"}, fallback.Preferences))
+	assert.Equal(t, geo.Location{Timezone: "Europe/Berlin", Locale: "de-DE"},
+		geo.Defaults(config.GeoConfig{}, fallback.Preferences), "empty values fall back to the i18n defaults")
+}
+
+func TestLocation_Preferences(t *testing.T) {
+	detected := geo.Detect(resolver, "203.0.113.10", "", defaults)
+	prefs := detected.Preferences()
+	assert.Equal(t, "id-ID", prefs.Locale)
+	assert.Equal(t, "Asia/Jakarta", prefs.Timezone)
+
+	undetected := geo.Detect(resolver, "10.0.0.1", "fr", defaults)
+	prefs = undetected.Preferences()
+	assert.Equal(t, "fr", prefs.Locale)
+	assert.Empty(t, prefs.Timezone, "default timezones are not request preferences")
+}
@@ -0,0 +1,144 @@
+package health_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"golang-arch/internal/shared/health"
+	"golang-arch/internal/shared/testutil"
+	"golang-arch/pkg/clock"
+)
+
+var start = time.Date(2024, 7, 1, 12, 0, 0, 0, time.UTC)
+
+// fixture monitors a database check whose outcome the test controls, and a
+// check that always passes
+type fixture struct {
+	monitor *health.Monitor
+	clock   *clock.Fake
+	dbErr   error
+}
+
+func newFixture(t *testing.T, options ...health.Option) *fixture {
+	t.Helper()
+	_, client := testutil.NewRedis(t)
+
+	f := &fixture{clock: clock.NewFake(start)}
+	checks := []health.Check{
+		{Name: "database", Probe: func(context.Context) error { return f.dbErr }},
+		{Name: "cache", Probe: func(context.Context) error { return nil }},
+	}
+	f.monitor = health.NewMonitor(client, checks, append([]health.Option{health.WithClock(f.clock)}, options...)...)
+	return f
+}
+
+// record records one round of results a minute after the previous one
+func (f *fixture) record(t *testing.T, dbErr error) {
+	t.Helper()
+	f.dbErr = dbErr
+	require.NoError(t, f.monitor.Record(context.Background()))
+	f.clock.Advance(time.Minute)
+}
+
+func TestMonitor_Probe(t *testing.T) {
+	f := newFixture(t)
+	f.dbErr = errors.New("connection refused")
+
+	results := f.monitor.Probe(context.Background())
+	require.Len(t, results, 2)
+	assert.False(t, results["database"].Up)
+	assert.Equal(t, "connection refused", results["database"].Error)
+	assert.True(t, results["cache"].Up)
+	assert.Equal(t, start, results["cache"].At)
+
+	// Probes are bounded by the timeout
+	slow := health.NewMonitor(nil, []health.Check{{Name: "slow", Probe: func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}}}, health.WithTimeout(10*time.Millisecond))
+	assert.Equal(t, context.DeadlineExceeded.Error(), slow.Probe(context.Background())["slow"].Error)
+}
+
+func TestMonitor_Status(t *testing.T) {
+	f := newFixture(t, health.WithHistorySize(4))
+	ctx := context.Background()
+
+	status, err := f.monitor.Status(ctx, 10)
+	require.NoError(t, err)
+	assert.Equal(t, health.StatusUnknown, status.Status)
+	require.Len(t, status.Checks, 2)
+	assert.Zero(t, status.Checks[0].Samples)
+	assert.Empty(t, status.Checks[0].History)
+
+	f.record(t, nil)
+	f.record(t, errors.New("timeout"))
+	f.record(t, nil)
+	f.record(t, nil)
+	f.record(t, errors.New("timeout"))
+
+	status, err = f.monitor.Status(ctx, 2)
+	require.NoError(t, err)
+	assert.Equal(t, health.StatusDegraded, status.Status)
+
+	database := status.Checks[0]
+	assert.Equal(t, "database", database.Name)
+	assert.False(t, database.Up)
+	assert.Equal(t, "timeout", database.Error)
+	assert.Equal(t, 4, database.Samples, "the oldest result is dropped beyond the history size")
+	assert.InDelta(t, 0.5, database.Uptime, 1e-9)
+	require.NotNil(t, database.Since)
+	assert.Equal(t, start.Add(time.Minute), *database.Since)
+	require.Len(t, database.History, 2)
+	assert.Equal(t, start.Add(4*time.Minute), database.History[0].At, "newest first")
+	assert.True(t, database.History[1].Up)
+	assert.LessOrEqual(t, database.Latency.P95MS, database.Latency.MaxMS)
+
+	cache := status.Checks[1]
+	assert.True(t, cache.Up)
+	assert.InDelta(t, 1.0, cache.Uptime, 1e-9)
+
+	f.record(t, nil)
+	status, err = f.monitor.Status(ctx, 0)
+	require.NoError(t, err)
+	assert.Equal(t, health.StatusUp, status.Status)
+	assert.Empty(t, status.Checks[0].History)
+}
+
+func TestHandler_Status(t *testing.T) {
+	f := newFixture(t, health.WithHistorySize(100))
+	f.record(t, nil)
+	f.record(t, nil)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	health.NewHandler(f.monitor).Register(&router.RouterGroup)
+	get := func(path string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		return rec
+	}
+
+	rec := get("/status?samples=1")
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var body struct {
+		Data health.Status `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	assert.Equal(t, health.StatusUp, body.Data.Status)
+	require.Len(t, body.Data.Checks, 2)
+	assert.Equal(t, 2, body.Data.Checks[0].Samples)
+	assert.Len(t, body.Data.Checks[0].History, 1)
+
+	for _, query := range []string{"samples=-1", "samples=101", "samples=many"} {
+		assert.Equal(t, http.StatusBadRequest, get("/status?"+query).Code, query)
+	}
+}
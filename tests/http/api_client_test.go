@@ -0,0 +1,72 @@
+package http_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"golang-arch/internal/shared/api"
+	"golang-arch/internal/shared/testutil"
+)
+
+func newTestRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+
+	router.GET("/items/:id", func(c *gin.Context) {
+		api.Success(c, gin.H{"id": c.Param("id"), "name": "widget"}, "found")
+	})
+	router.POST("/items", func(c *gin.Context) {
+		var body struct {
+			Name string `json:"name"`
+		}
+		if err := c.ShouldBindJSON(&body); err != nil || body.Name == "" {
+			api.BadRequest(c, "invalid item", api.NewValidationError("name is required"))
+			return
+		}
+		api.Created(c, body, "created")
+	})
+
+	return router
+}
+
+func TestAPIClient_DecodeData(t *testing.T) {
+	client := testutil.NewAPIClient(t, newTestRouter())
+
+	response := client.Get("/items/42")
+	testutil.AssertStatus(t, response, http.StatusOK)
+	testutil.AssertSuccess(t, response)
+
+	var item struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	}
+	response.DecodeData(t, &item)
+	assert.Equal(t, "42", item.ID)
+	assert.Equal(t, "widget", item.Name)
+	assert.Equal(t, "found", response.Envelope.Message)
+}
+
+func TestAPIClient_ErrorCode(t *testing.T) {
+	client := testutil.NewAPIClient(t, newTestRouter())
+
+	response := client.Post("/items", map[string]string{})
+	testutil.AssertStatus(t, response, http.StatusBadRequest)
+	testutil.AssertErrorCode(t, response, api.ErrCodeValidationFailed)
+
+	apiErr := response.APIError()
+	if assert.NotNil(t, apiErr) {
+		assert.Equal(t, "name is required", apiErr.Message)
+	}
+}
+
+func TestAPIClient_Created(t *testing.T) {
+	client := testutil.NewAPIClient(t, newTestRouter()).WithHeader("X-Request-ID", "test")
+
+	response := client.Post("/items", map[string]string{"name": "gadget"})
+	testutil.AssertStatus(t, response, http.StatusCreated)
+	testutil.AssertSuccess(t, response)
+	assert.Nil(t, response.APIError())
+}
@@ -0,0 +1,106 @@
+package http_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"golang-arch/internal/shared/api"
+	"golang-arch/internal/shared/domain/domainerror"
+	"golang-arch/pkg/metrics"
+)
+
+var (
+	deprecatedSince = time.Date(2025, 1, 31, 0, 0, 0, 0, time.UTC)
+	sunset          = time.Date(2025, 7, 31, 0, 0, 0, 0, time.UTC)
+)
+
+func serve(router *gin.Engine, method, path string) (*httptest.ResponseRecorder, api.Response) {
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(method, path, nil))
+	var body api.Response
+	_ = json.Unmarshal(w.Body.Bytes(), &body)
+	return w, body
+}
+
+func TestDeprecations_Route(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	registry := metrics.NewRegistry()
+	counter, err := registry.Counter("deprecated", "", "")
+	require.NoError(t, err)
+	deprecations := api.NewDeprecations(api.WithUsageCounter(counter))
+
+	router := gin.New()
+	router.GET("/users/:id", deprecations.Route(api.Deprecation{
+		Since:  deprecatedSince,
+		Sunset: sunset,
+		Link:   "https://docs.example.com/migrations/users-v2",
+	}), func(c *gin.Context) { api.Success(c, gin.H{"id": c.Param("id")}, "found") })
+	router.GET("/accounts/:id", func(c *gin.Context) { api.Success(c, nil, "found") })
+
+	w, body := serve(router, http.MethodGet, "/users/7")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "@1738281600", w.Header().Get("Deprecation"))
+	assert.Equal(t, "Thu, 31 Jul 2025 00:00:00 GMT", w.Header().Get("Sunset"))
+	assert.Equal(t, `<https://docs.example.com/migrations/users-v2>; rel="deprecation"; type="text/html"`, w.Header().Get("Link"))
+	require.Len(t, body.Warnings, 1)
+	assert.Equal(t, api.Warning{
+		Code:         api.WarnDeprecated,
+		Message:      "This endpoint is deprecated and will be removed on 2025-07-31",
+		SunsetDate:   "2025-07-31",
+		MigrationURL: "https://docs.example.com/migrations/users-v2",
+	}, body.Warnings[0])
+
+	w, body = serve(router, http.MethodGet, "/accounts/7")
+	assert.Empty(t, w.Header().Get("Deprecation"))
+	assert.Empty(t, body.Warnings)
+	assert.NotContains(t, w.Body.String(), "warnings")
+
+	series := registry.Snapshot()[0].Series
+	require.Len(t, series, 1)
+	assert.Equal(t, metrics.Labels{"method": "GET", "route": "/users/:id"}, series[0].Labels)
+	assert.Equal(t, float64(1), series[0].Value)
+}
+
+func TestDeprecations_Middleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	deprecations := api.NewDeprecations()
+	require.NoError(t, deprecations.Mark(http.MethodDelete, "/users/:id", api.Deprecation{
+		Since:   deprecatedSince,
+		Message: "Deactivate users instead",
+	}))
+
+	router := gin.New()
+	router.Use(deprecations.Middleware())
+	router.GET("/users/:id", func(c *gin.Context) { api.Success(c, nil, "found") })
+	router.DELETE("/users/:id", func(c *gin.Context) {
+		api.RespondError(c, domainerror.NotFoundf("user not found"))
+	})
+
+	// Warnings are added to error responses too
+	w, body := serve(router, http.MethodDelete, "/users/7")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Equal(t, "@1738281600", w.Header().Get("Deprecation"))
+	assert.Empty(t, w.Header().Get("Sunset"))
+	require.Len(t, body.Warnings, 1)
+	assert.Equal(t, "Deactivate users instead", body.Warnings[0].Message)
+	assert.Empty(t, body.Warnings[0].SunsetDate)
+
+	w, _ = serve(router, http.MethodGet, "/users/7")
+	assert.Empty(t, w.Header().Get("Deprecation"))
+}
+
+func TestDeprecation_Validate(t *testing.T) {
+	assert.NoError(t, api.Deprecation{Since: deprecatedSince, Sunset: sunset}.Validate())
+	assert.Error(t, api.Deprecation{}.Validate())
+	assert.Error(t, api.Deprecation{Since: sunset, Sunset: deprecatedSince}.Validate())
+
+	assert.Error(t, api.NewDeprecations().Mark(http.MethodGet, "/users", api.Deprecation{}))
+	assert.Panics(t, func() { api.NewDeprecations().Route(api.Deprecation{}) })
+}
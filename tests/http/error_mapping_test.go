@@ -0,0 +1,92 @@
+package http_test
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"golang-arch/internal/shared/api"
+	"golang-arch/internal/shared/domain/domainerror"
+	i18n "golang-arch/internal/shared/domain/internationalization"
+	"golang-arch/internal/shared/testutil"
+)
+
+func TestMapError(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		status int
+		code   string
+	}{
+		{"not found", domainerror.NotFoundf("order 1"), http.StatusNotFound, api.ErrCodeNotFound},
+		{"conflict", fmt.Errorf("save: %w", domainerror.Conflictf("version")), http.StatusConflict, api.ErrCodeConflict},
+		{"invalid", domainerror.Invalidf("bad"), http.StatusBadRequest, api.ErrCodeInvalidInput},
+		{"forbidden", domainerror.Forbiddenf("no"), http.StatusForbidden, api.ErrCodeForbidden},
+		{"unavailable", domainerror.Unavailablef("down"), http.StatusServiceUnavailable, api.ErrCodeUnavailable},
+		{"validation", i18n.Phone{}.Validate(), http.StatusBadRequest, api.ErrCodeValidationFailed},
+		{"api error", api.NewUnauthorizedError("login"), http.StatusUnauthorized, api.ErrCodeUnauthorized},
+		{"rate limited", api.NewTooManyRequestsError("slow down"), http.StatusTooManyRequests, api.ErrCodeTooManyRequests},
+		{"quota exceeded", api.NewQuotaExceededError("plan limit"), http.StatusTooManyRequests, api.ErrCodeQuotaExceeded},
+		{"sentinel", fmt.Errorf("lookup: %w", api.ErrNotFound), http.StatusNotFound, api.ErrCodeNotFound},
+		{"unknown", errors.New("secret db password leaked"), http.StatusInternalServerError, api.ErrCodeInternalServer},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, apiErr := api.MapError(tt.err)
+			assert.Equal(t, tt.status, status)
+			assert.Equal(t, tt.code, apiErr.Code)
+		})
+	}
+}
+
+func newErrorRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(api.ErrorHandler())
+
+	router.GET("/orders/:id", func(c *gin.Context) {
+		api.AbortWithError(c, domainerror.NotFoundf("order %s not found", c.Param("id")))
+	})
+	router.GET("/currencies/:code", func(c *gin.Context) {
+		currency, err := i18n.NewCurrencyFromCode(c.Param("code"))
+		if err != nil {
+			_ = c.Error(err)
+			return
+		}
+		api.Success(c, currency, "found")
+	})
+	router.GET("/internal", func(c *gin.Context) {
+		_ = c.Error(errors.New("secret detail"))
+	})
+
+	return router
+}
+
+func TestErrorHandler_MapsDomainErrors(t *testing.T) {
+	client := testutil.NewAPIClient(t, newErrorRouter())
+
+	response := client.Get("/orders/42")
+	testutil.AssertStatus(t, response, http.StatusNotFound)
+	assert.Equal(t, api.ErrCodeNotFound, response.Envelope.Code)
+	assert.Contains(t, string(response.Envelope.Error), "order 42 not found")
+
+	response = client.Get("/currencies/XXX")
+	testutil.AssertStatus(t, response, http.StatusBadRequest)
+	assert.Equal(t, api.ErrCodeInvalidInput, response.Envelope.Code)
+
+	response = client.Get("/currencies/USD")
+	testutil.AssertStatus(t, response, http.StatusOK)
+}
+
+func TestErrorHandler_HidesInternalErrors(t *testing.T) {
+	client := testutil.NewAPIClient(t, newErrorRouter())
+
+	response := client.Get("/internal")
+	testutil.AssertStatus(t, response, http.StatusInternalServerError)
+	assert.NotContains(t, string(response.Body), "secret detail")
+}
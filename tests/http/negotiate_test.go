@@ -0,0 +1,94 @@
+package http_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"golang-arch/internal/shared/api"
+	"golang-arch/internal/shared/codec"
+	i18n "golang-arch/internal/shared/domain/internationalization"
+	"golang-arch/internal/shared/testutil"
+)
+
+func newPriceRouter(t *testing.T) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+
+	price, err := i18n.MakeMoney(10050, "USD")
+	require.NoError(t, err)
+
+	router.GET("/price", func(c *gin.Context) {
+		api.Success(c, gin.H{"price": price}, "found")
+	})
+	return router
+}
+
+func TestRender_Negotiation(t *testing.T) {
+	tests := []struct {
+		accept      string
+		contentType string
+	}{
+		{"", codec.ContentTypeJSON},
+		{"*/*", codec.ContentTypeJSON},
+		{"application/msgpack", codec.ContentTypeMsgPack},
+		{"application/x-msgpack", codec.ContentTypeMsgPack},
+		{"application/cbor", codec.ContentTypeCBOR},
+		{"application/json;q=0.5, application/cbor", codec.ContentTypeCBOR},
+		{"application/cbor;q=0.2, application/json", codec.ContentTypeJSON},
+		{"application/cbor;q=0", codec.ContentTypeJSON},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.accept, func(t *testing.T) {
+			client := testutil.NewAPIClient(t, newPriceRouter(t))
+			if tt.accept != "" {
+				client = client.WithHeader("Accept", tt.accept)
+			}
+
+			response := client.Get("/price")
+			testutil.AssertStatus(t, response, http.StatusOK)
+			assert.Contains(t, response.Header.Get("Content-Type"), tt.contentType)
+			assert.Equal(t, "Accept", response.Header.Get("Vary"))
+		})
+	}
+}
+
+func TestRender_BinaryEnvelopeDecodes(t *testing.T) {
+	for _, c := range []codec.Codec{codec.MsgPack, codec.CBOR} {
+		client := testutil.NewAPIClient(t, newPriceRouter(t)).WithHeader("Accept", c.ContentType())
+		response := client.Get("/price")
+
+		var envelope struct {
+			Success bool   `json:"success"`
+			Message string `json:"message"`
+			Data    struct {
+				Price i18n.Money `json:"price"`
+			} `json:"data"`
+		}
+		require.NoError(t, c.Unmarshal(response.Body, &envelope), c.Name())
+		assert.True(t, envelope.Success)
+		assert.Equal(t, "found", envelope.Message)
+		assert.Equal(t, int64(10050), envelope.Data.Price.Amount)
+		assert.Equal(t, "USD", envelope.Data.Price.Currency.Code)
+
+		jsonResponse := testutil.NewAPIClient(t, newPriceRouter(t)).Get("/price")
+		assert.Less(t, len(response.Body), len(jsonResponse.Body), c.Name())
+	}
+}
+
+func TestCodec_Lookup(t *testing.T) {
+	c, ok := codec.ByName("CBOR")
+	require.True(t, ok)
+	assert.Equal(t, codec.CBOR, c)
+
+	c, ok = codec.ForContentType("application/msgpack; charset=binary")
+	require.True(t, ok)
+	assert.Equal(t, codec.MsgPack, c)
+
+	_, ok = codec.ForContentType("text/html")
+	assert.False(t, ok)
+}
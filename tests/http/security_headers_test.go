@@ -0,0 +1,122 @@
+package http_test
+
+import (
+	"crypto/tls"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"golang-arch/internal/shared/config"
+	"golang-arch/internal/shared/secheaders"
+)
+
+func newHeadersRouter(policy secheaders.Policy, routes ...secheaders.Route) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(secheaders.Middleware(policy, routes...))
+
+	ok := func(c *gin.Context) { c.String(http.StatusOK, "ok") }
+	router.GET("/api/v1/orders", ok)
+	router.GET("/embed/widget", ok)
+	router.GET("/docs", secheaders.Override(func(p *secheaders.Policy) {
+		p.ContentSecurityPolicy = "default-src 'self'"
+		p.CSPReportOnly = true
+	}), ok)
+	return router
+}
+
+func get(router *gin.Engine, path string, secure bool) http.Header {
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	if secure {
+		req.TLS = &tls.ConnectionState{}
+	}
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	return rec.Header()
+}
+
+func TestSecurityHeadersDefaults(t *testing.T) {
+	router := newHeadersRouter(secheaders.DefaultPolicy())
+
+	headers := get(router, "/api/v1/orders", true)
+	assert.Equal(t, "max-age=31536000; includeSubDomains", headers.Get(secheaders.HeaderHSTS))
+	assert.Equal(t, "nosniff", headers.Get(secheaders.HeaderContentTypeOptions))
+	assert.Equal(t, "DENY", headers.Get(secheaders.HeaderFrameOptions))
+	assert.Equal(t, "strict-origin-when-cross-origin", headers.Get(secheaders.HeaderReferrerPolicy))
+	assert.Equal(t, secheaders.DefaultContentSecurityPolicy, headers.Get(secheaders.HeaderCSP))
+	assert.Equal(t, "same-origin", headers.Get(secheaders.HeaderCrossOriginOpener))
+	assert.Empty(t, headers.Get(secheaders.HeaderPermissionsPolicy))
+
+	plain := get(router, "/api/v1/orders", false)
+	assert.Empty(t, plain.Get(secheaders.HeaderHSTS), "HSTS is only sent over HTTPS")
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/orders", nil)
+	req.Header.Set("X-Forwarded-Proto", "https")
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	assert.NotEmpty(t, rec.Header().Get(secheaders.HeaderHSTS))
+
+	// Unmatched routes still get the headers
+	assert.Equal(t, "DENY", get(router, "/missing", false).Get(secheaders.HeaderFrameOptions))
+}
+
+func TestSecurityHeadersOverrides(t *testing.T) {
+	router := newHeadersRouter(secheaders.DefaultPolicy(),
+		secheaders.Route{PathPrefix: "/embed/", Apply: func(p *secheaders.Policy) {
+			p.FrameOptions = ""
+			p.ContentSecurityPolicy = "frame-ancestors https://partner.example"
+		}},
+		secheaders.Route{PathPrefix: "/", Apply: func(p *secheaders.Policy) {
+			p.ReferrerPolicy = "no-referrer"
+		}},
+	)
+
+	embed := get(router, "/embed/widget", false)
+	assert.Empty(t, embed.Get(secheaders.HeaderFrameOptions))
+	assert.Equal(t, "frame-ancestors https://partner.example", embed.Get(secheaders.HeaderCSP))
+	assert.Equal(t, "strict-origin-when-cross-origin", embed.Get(secheaders.HeaderReferrerPolicy), "the longest prefix wins")
+
+	assert.Equal(t, "no-referrer", get(router, "/api/v1/orders", false).Get(secheaders.HeaderReferrerPolicy))
+
+	docs := get(router, "/docs", false)
+	assert.Empty(t, docs.Get(secheaders.HeaderCSP))
+	assert.Equal(t, "default-src 'self'", docs.Get(secheaders.HeaderCSPReportOnly))
+	assert.Equal(t, "no-referrer", docs.Get(secheaders.HeaderReferrerPolicy), "route overrides build on the prefix policy")
+}
+
+func TestSecurityHeadersFromConfig(t *testing.T) {
+	v := viper.New()
+	v.SetConfigType("yaml")
+	require.NoError(t, v.ReadConfig(strings.NewReader(`
+hsts_max_age: 1h
+hsts_preload: true
+content_type_nosniff: true
+frame_options: SAMEORIGIN
+content_security_policy: "default-src 'none'"
+routes:
+  - path_prefix: /embed/
+    frame_options: ""
+    csp_report_only: true
+`)))
+	var cfg config.SecurityHeadersConfig
+	require.NoError(t, v.Unmarshal(&cfg))
+
+	policy, routes := secheaders.PolicyFromConfig(cfg)
+	router := newHeadersRouter(policy, routes...)
+
+	headers := get(router, "/api/v1/orders", true)
+	assert.Equal(t, "max-age=3600; preload", headers.Get(secheaders.HeaderHSTS))
+	assert.Equal(t, "SAMEORIGIN", headers.Get(secheaders.HeaderFrameOptions))
+	assert.Empty(t, headers.Get(secheaders.HeaderReferrerPolicy))
+
+	embed := get(router, "/embed/widget", true)
+	assert.Empty(t, embed.Get(secheaders.HeaderFrameOptions))
+	assert.Empty(t, embed.Get(secheaders.HeaderCSP))
+	assert.Equal(t, "default-src 'none'", embed.Get(secheaders.HeaderCSPReportOnly))
+}
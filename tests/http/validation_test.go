@@ -0,0 +1,75 @@
+package http_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"golang-arch/internal/shared/api"
+	"golang-arch/internal/shared/domain/validation"
+	"golang-arch/internal/shared/testutil"
+)
+
+type priceRequest struct {
+	Amount   int64  `json:"amount" binding:"min=1"`
+	Currency string `json:"currency" binding:"required"`
+	Label    string `json:"label"`
+}
+
+func (r priceRequest) Validate() error {
+	var errs validation.ValidationErrors
+	if len(r.Label) > 10 {
+		errs.Add("label", validation.CodeOutOfRange, "label is too long", map[string]any{"max": 10})
+	}
+	return errs.Err()
+}
+
+func newValidationRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+
+	router.POST("/prices", func(c *gin.Context) {
+		var body priceRequest
+		if !api.BindJSON(c, &body) {
+			return
+		}
+		api.Created(c, body, "created")
+	})
+
+	return router
+}
+
+func TestBindJSON_BindingErrors(t *testing.T) {
+	client := testutil.NewAPIClient(t, newValidationRouter())
+
+	response := client.Post("/prices", map[string]any{"amount": 0})
+	testutil.AssertStatus(t, response, http.StatusBadRequest)
+
+	fields := response.Envelope.Errors.ByField()
+	require.Len(t, response.Envelope.Errors, 2)
+	assert.Equal(t, "min", fields["Amount"][0].Code)
+	assert.Equal(t, "1", fields["Amount"][0].Params["param"])
+	assert.Equal(t, validation.CodeRequired, fields["Currency"][0].Code)
+}
+
+func TestBindJSON_DomainValidation(t *testing.T) {
+	client := testutil.NewAPIClient(t, newValidationRouter())
+
+	response := client.Post("/prices", map[string]any{"amount": 5, "currency": "USD", "label": "far too long label"})
+	testutil.AssertStatus(t, response, http.StatusBadRequest)
+
+	require.Len(t, response.Envelope.Errors, 1)
+	assert.Equal(t, "label", response.Envelope.Errors[0].Field)
+	assert.Equal(t, validation.CodeOutOfRange, response.Envelope.Errors[0].Code)
+}
+
+func TestBindJSON_Success(t *testing.T) {
+	client := testutil.NewAPIClient(t, newValidationRouter())
+
+	response := client.Post("/prices", map[string]any{"amount": 5, "currency": "USD"})
+	testutil.AssertStatus(t, response, http.StatusCreated)
+	assert.Empty(t, response.Envelope.Errors)
+}
@@ -0,0 +1,81 @@
+package i18napi_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	i18n "golang-arch/internal/shared/domain/internationalization"
+	"golang-arch/internal/shared/i18napi"
+)
+
+// storedHolidays knows German Unity Day in the "DE" calendar
+type storedHolidays struct{}
+
+func (storedHolidays) Calendar(_ context.Context, name string, base i18n.BusinessCalendar) (*i18n.BusinessCalendar, error) {
+	if name != "DE" {
+		return &base, nil
+	}
+	return base.WithHoliday("2024-10-03", "Tag der Deutschen Einheit")
+}
+
+func dueDate(t *testing.T, router *gin.Engine, body string) (*httptest.ResponseRecorder, i18napi.DueDateResult) {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/i18n/due-date", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(rec, req)
+	var response struct {
+		Data i18napi.DueDateResult `json:"data"`
+	}
+	if rec.Code == http.StatusOK {
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response), rec.Body.String())
+	}
+	return rec, response.Data
+}
+
+// berlin works 09:00-17:00 and asks for 8 business hours from Wednesday
+// 2 October 2024, 16:00 Berlin time
+const berlinSLA = `"calendar": {"timezone": "Europe/Berlin", "start": "09:00", "end": "17:00"},
+	"start": "2024-10-02T14:00:00Z", "within": "8h"`
+
+func TestDueDate(t *testing.T) {
+	router := newConvertRouter(i18napi.WithCalendars(storedHolidays{}))
+
+	rec, result := dueDate(t, router, `{`+berlinSLA+`}`)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Equal(t, time.Date(2024, 10, 3, 14, 0, 0, 0, time.UTC), result.Due.Time.ToTime().UTC(), "Thursday 16:00 Berlin time")
+	assert.Equal(t, "Europe/Berlin", result.Due.Timezone.ID)
+
+	rec, result = dueDate(t, router, `{`+berlinSLA+`, "holidays": "DE"}`)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Equal(t, time.Date(2024, 10, 4, 14, 0, 0, 0, time.UTC), result.Due.Time.ToTime().UTC(), "the stored holiday is skipped")
+	assert.Contains(t, result.Calendar.Holidays, "2024-10-03")
+}
+
+func TestDueDateErrors(t *testing.T) {
+	router := newConvertRouter(i18napi.WithCalendars(storedHolidays{}))
+	for _, body := range []string{
+		`{}`,
+		`{"calendar": {"timezone": "Europe/Berlin", "start": "09:00", "end": "17:00"}, "start": "2024-10-02T14:00:00Z", "within": "soon"}`,
+		`{"calendar": {"timezone": "Europe/Berlin", "start": "09:00", "end": "17:00"}, "start": "2024-10-02T14:00:00Z", "within": "-1h"}`,
+		`{"calendar": {"timezone": "Mars/Olympus", "start": "09:00", "end": "17:00"}, "start": "2024-10-02T14:00:00Z", "within": "1h"}`,
+	} {
+		rec, _ := dueDate(t, router, body)
+		assert.Equal(t, http.StatusBadRequest, rec.Code, body)
+	}
+
+	withoutStore := newConvertRouter()
+	rec, _ := dueDate(t, withoutStore, `{`+berlinSLA+`, "holidays": "DE"}`)
+	assert.Equal(t, http.StatusBadRequest, rec.Code, "stored calendars need WithCalendars")
+	rec, _ = dueDate(t, withoutStore, `{`+berlinSLA+`}`)
+	assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+}
@@ -0,0 +1,107 @@
+package i18napi_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"golang-arch/internal/shared/domain/domainerror"
+	i18n "golang-arch/internal/shared/domain/internationalization"
+	"golang-arch/internal/shared/i18napi"
+	"golang-arch/internal/shared/rates"
+)
+
+var observed = time.Date(2024, 7, 1, 12, 0, 0, 0, time.UTC)
+
+// fixedRate converts USD to EUR at 0.9221 and knows no other pair
+type fixedRate struct{}
+
+func (fixedRate) Convert(_ context.Context, amount i18n.Money, target string, mode i18n.RoundingMode) (*rates.ConvertedMoney, error) {
+	if amount.Currency.Code != "USD" || target != "EUR" {
+		return nil, domainerror.NotFoundf("no exchange rate for %s/%s", amount.Currency.Code, target)
+	}
+	eur, _ := i18n.NewCurrencyFromCode("EUR")
+	rate, err := i18n.NewExchangeRateFromDecimal(amount.Currency, *eur, "0.9221", *i18n.NewTimeFromTime(observed))
+	if err != nil {
+		return nil, err
+	}
+	result, err := rate.Convert(amount, mode)
+	if err != nil {
+		return nil, err
+	}
+	return &rates.ConvertedMoney{Amount: amount, Result: *result, Rate: *rate, Source: "static", Timestamp: observed}, nil
+}
+
+func newConvertRouter(options ...i18napi.Option) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	i18napi.NewHandler(options...).Register(router.Group("/api/v1"))
+	return router
+}
+
+func convert(router *gin.Engine, query string) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/i18n/convert?"+query, nil))
+	return rec
+}
+
+func TestConvert(t *testing.T) {
+	router := newConvertRouter(i18napi.WithConverter(fixedRate{}, time.Minute))
+
+	rec := convert(router, "from=USD&to=EUR&amount=10050")
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Equal(t, "public, max-age=60", rec.Header().Get("Cache-Control"))
+	assert.Equal(t, "Mon, 01 Jul 2024 12:00:00 GMT", rec.Header().Get("Last-Modified"))
+
+	var body struct {
+		Data rates.ConvertedMoney `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	assert.Equal(t, int64(10050), body.Data.Amount.Amount)
+	assert.Equal(t, int64(9267), body.Data.Result.Amount)
+	assert.Equal(t, "EUR", body.Data.Result.Currency.Code)
+	assert.Equal(t, "0.9221", body.Data.Rate.DecimalString())
+	assert.Equal(t, "static", body.Data.Source)
+	assert.Equal(t, observed, body.Data.Timestamp)
+
+	rec = convert(router, "from=usd&to=eur&amount=10050&rounding=up")
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	assert.Equal(t, int64(9268), body.Data.Result.Amount, "codes are case-insensitive and rounding is selectable")
+
+	rec = convert(router, "from=USD&to=JPY&amount=1")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	assert.Empty(t, rec.Header().Get("Cache-Control"), "errors are not cacheable")
+
+	for _, query := range []string{
+		"to=EUR&amount=1",
+		"from=XXX&to=EUR&amount=1",
+		"from=USD&amount=1",
+		"from=USD&to=EUR&amount=1.5",
+		"from=USD&to=EUR&amount=1&rounding=sideways",
+	} {
+		assert.Equal(t, http.StatusBadRequest, convert(router, query).Code, query)
+	}
+}
+
+func TestConvertOptional(t *testing.T) {
+	router := newConvertRouter()
+	assert.Equal(t, http.StatusNotFound, convert(router, "from=USD&to=EUR&amount=1").Code,
+		"the route is only registered with a converter")
+
+	calls := 0
+	limit := func(c *gin.Context) {
+		calls++
+		c.AbortWithStatus(http.StatusTooManyRequests)
+	}
+	router = newConvertRouter(i18napi.WithConverter(fixedRate{}, 0), i18napi.WithConvertLimit(limit))
+	assert.Equal(t, http.StatusTooManyRequests, convert(router, "from=USD&to=EUR&amount=1").Code)
+	assert.Equal(t, 1, calls)
+}
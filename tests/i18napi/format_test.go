@@ -0,0 +1,102 @@
+package i18napi_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"golang-arch/internal/shared/geo"
+	"golang-arch/internal/shared/i18napi"
+)
+
+func format(t *testing.T, router *gin.Engine, body string) (int, []i18napi.FormatResult) {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/i18n/format", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(rec, req)
+	var response struct {
+		Data []i18napi.FormatResult `json:"data"`
+	}
+	if rec.Code == http.StatusOK {
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response), rec.Body.String())
+	}
+	return rec.Code, response.Data
+}
+
+func TestFormat(t *testing.T) {
+	router := newRouter(nil)
+
+	// 2024-07-01T12:30:00Z
+	code, results := format(t, router, `{"items": [
+		{"amount": 123456, "currency": "USD"},
+		{"amount": 123456, "currency": "EUR", "locale": "de-DE"},
+		{"amount": 10050, "currency": "JPY", "locale": "ja-JP"},
+		{"epoch": 1719837000, "tz": "Europe/Berlin", "locale": "de-DE"},
+		{"epoch": 1719837000, "tz": "America/New_York", "locale": "en-US", "style": "date"},
+		{"epoch": 1719837000}
+	]}`)
+	require.Equal(t, http.StatusOK, code)
+	assert.Equal(t, []i18napi.FormatResult{
+		{Value: "$1,234.56"},
+		{Value: "1.234,56\u00a0€"},
+		{Value: "¥10,050"},
+		{Value: "01.07.2024 14:30 CEST"},
+		{Value: "07/01/2024"},
+		{Value: "01/07/2024 12:30 UTC"},
+	}, results)
+}
+
+func TestFormatDefaults(t *testing.T) {
+	// Items without locale or timezone use the batch's, then the detected ones
+	router := newRouter(&geo.Location{Country: "ID", Timezone: "Asia/Jakarta", Locale: "id-ID", Detected: true})
+
+	_, results := format(t, router, `{"items": [{"epoch": 1719837000}]}`)
+	require.Len(t, results, 1)
+	assert.Equal(t, "01/07/2024 19:30 WIB", results[0].Value)
+
+	_, results = format(t, router, `{"locale": "de", "tz": "Europe/Berlin", "items": [
+		{"epoch": 1719837000},
+		{"epoch": 1719837000, "tz": "UTC"}
+	]}`)
+	require.Len(t, results, 2)
+	assert.Equal(t, "01.07.2024 14:30 CEST", results[0].Value)
+	assert.Equal(t, "01.07.2024 12:30 UTC", results[1].Value)
+}
+
+func TestFormatErrors(t *testing.T) {
+	router := newRouter(nil)
+
+	// Unknown values fail their item only
+	code, results := format(t, router, `{"items": [
+		{"amount": 100, "currency": "XXX"},
+		{"epoch": 0, "tz": "Mars/Olympus_Mons"},
+		{"amount": 100, "currency": "USD", "locale": "not a locale"},
+		{"amount": 100, "currency": "USD", "tz": "Mars/Olympus_Mons"}
+	]}`)
+	require.Equal(t, http.StatusOK, code)
+	require.Len(t, results, 4)
+	assert.Contains(t, results[0].Error, "XXX")
+	assert.Contains(t, results[1].Error, "Mars/Olympus_Mons")
+	assert.Contains(t, results[2].Error, "invalid locale")
+	assert.Equal(t, "$1.00", results[3].Value, "amounts ignore the timezone")
+
+	// Malformed batches fail as a whole
+	for _, body := range []string{
+		`{"items": []}`,
+		`{"items": [{"currency": "USD"}]}`,
+		`{"items": [{"amount": 1, "epoch": 1}]}`,
+		`{"items": [{"amount": 1}]}`,
+		`{"items": [{"epoch": 1, "style": "relative"}]}`,
+		`{"items": [` + strings.Repeat(`{"epoch": 1},`, 500) + `{"epoch": 1}]}`,
+	} {
+		code, _ := format(t, router, body)
+		assert.Equal(t, http.StatusBadRequest, code, body[:min(len(body), 60)])
+	}
+}
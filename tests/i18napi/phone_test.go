@@ -0,0 +1,72 @@
+package i18napi_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"golang-arch/internal/shared/geo"
+	"golang-arch/internal/shared/i18napi"
+)
+
+func parsePhone(t *testing.T, router *gin.Engine, body string) (*httptest.ResponseRecorder, i18napi.PhoneResult) {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/i18n/phone/parse", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(rec, req)
+	var response struct {
+		Data i18napi.PhoneResult `json:"data"`
+	}
+	if rec.Code == http.StatusOK {
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response), rec.Body.String())
+	}
+	return rec, response.Data
+}
+
+func TestParsePhone(t *testing.T) {
+	router := newRouter(nil)
+
+	rec, result := parsePhone(t, router, `{"phone": "+49 (0)176 1234-5678"}`)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Equal(t, i18napi.PhoneResult{
+		E164:           "+4917612345678",
+		Country:        "DE",
+		CallingCode:    "49",
+		NationalNumber: "17612345678",
+		Type:           "mobile",
+		International:  "+49 17612345678",
+		Timezones:      []string{"Europe/Berlin", "Europe/Busingen"},
+	}, result)
+
+	rec, result = parsePhone(t, router, `{"phone": "020 7946 0958", "region": "gb"}`)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Equal(t, "+442079460958", result.E164)
+	assert.Equal(t, "fixed_line", result.Type)
+
+	for _, body := range []string{
+		`{}`,
+		`{"phone": "020 7946 0958"}`,
+		`{"phone": "020 7946 0958", "region": "GBR"}`,
+		`{"phone": "+44 20 7946"}`,
+	} {
+		rec, _ = parsePhone(t, router, body)
+		assert.Equal(t, http.StatusBadRequest, rec.Code, body)
+	}
+}
+
+func TestParsePhone_DetectedRegion(t *testing.T) {
+	router := newRouter(&geo.Location{Country: "ID", Timezone: "Asia/Jakarta", Locale: "id-ID", Detected: true})
+
+	rec, result := parsePhone(t, router, `{"phone": "0812-3456-7890"}`)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Equal(t, "+6281234567890", result.E164)
+	assert.Equal(t, "ID", result.Country)
+	assert.Contains(t, result.Timezones, "Asia/Jakarta")
+}
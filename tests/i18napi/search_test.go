@@ -0,0 +1,208 @@
+package i18napi_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	i18n "golang-arch/internal/shared/domain/internationalization"
+	"golang-arch/internal/shared/geo"
+	"golang-arch/internal/shared/i18napi"
+	"golang-arch/internal/shared/respcache"
+)
+
+func newRouter(location *geo.Location) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	if location != nil {
+		router.Use(func(c *gin.Context) {
+			c.Request = c.Request.WithContext(geo.NewContext(c.Request.Context(), *location))
+		})
+	}
+	i18napi.NewHandler().Register(router.Group("/api/v1"))
+	return router
+}
+
+func get[T any](t *testing.T, router *gin.Engine, path string) (int, i18napi.Page[T]) {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+	var body struct {
+		Data i18napi.Page[T] `json:"data"`
+	}
+	if rec.Code == http.StatusOK {
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body), rec.Body.String())
+	}
+	return rec.Code, body.Data
+}
+
+func timezoneIDs(page i18napi.Page[i18napi.TimezoneResult]) []string {
+	ids := make([]string, len(page.Results))
+	for i, result := range page.Results {
+		ids[i] = result.ID
+	}
+	return ids
+}
+
+func TestSearchTimezones(t *testing.T) {
+	router := newRouter(nil)
+
+	code, page := get[i18napi.TimezoneResult](t, router, "/api/v1/i18n/timezones?q=york")
+	require.Equal(t, http.StatusOK, code)
+	require.NotEmpty(t, page.Results)
+	newYork := page.Results[0]
+	assert.Equal(t, "America/New_York", newYork.ID)
+	assert.Equal(t, "US", newYork.Country)
+	assert.Equal(t, "United States", newYork.CountryName)
+	assert.Regexp(t, `^-0[45]:00$`, newYork.Offset)
+
+	_, page = get[i18napi.TimezoneResult](t, router, "/api/v1/i18n/timezones?q=Jakrta")
+	assert.Contains(t, timezoneIDs(page), "Asia/Jakarta", "one typo is tolerated")
+
+	_, page = get[i18napi.TimezoneResult](t, router, "/api/v1/i18n/timezones?q=SAO%20PAULO")
+	require.NotEmpty(t, page.Results)
+	assert.Equal(t, "America/Sao_Paulo", page.Results[0].ID, "case and accents are ignored")
+
+	_, page = get[i18napi.TimezoneResult](t, router, "/api/v1/i18n/timezones?q=indonesia")
+	assert.Subset(t, timezoneIDs(page), []string{"Asia/Jakarta", "Asia/Makassar", "Asia/Jayapura"}, "country names match")
+
+	_, page = get[i18napi.TimezoneResult](t, router, "/api/v1/i18n/timezones?q=qqqqq")
+	assert.Empty(t, page.Results)
+	assert.Zero(t, page.Total)
+}
+
+func TestSearchTimezonesLocale(t *testing.T) {
+	router := newRouter(nil)
+
+	_, page := get[i18napi.TimezoneResult](t, router, "/api/v1/i18n/timezones?q=Deutschland&locale=de")
+	require.NotEmpty(t, page.Results)
+	assert.Equal(t, "Europe/Berlin", page.Results[0].ID)
+	assert.Equal(t, "Deutschland", page.Results[0].CountryName)
+
+	_, page = get[i18napi.TimezoneResult](t, router, "/api/v1/i18n/timezones?q=germany&locale=de")
+	assert.Contains(t, timezoneIDs(page), "Europe/Berlin", "English names match in every locale")
+
+	// Without a locale parameter, the detected locale applies
+	router = newRouter(&geo.Location{Country: "FR", Locale: "fr-FR", Detected: true})
+	_, page = get[i18napi.TimezoneResult](t, router, "/api/v1/i18n/timezones?q=allemagne")
+	require.NotEmpty(t, page.Results)
+	assert.Equal(t, "Allemagne", page.Results[0].CountryName)
+}
+
+func TestSearchCurrencies(t *testing.T) {
+	router := newRouter(nil)
+
+	code, page := get[i18napi.CurrencyResult](t, router, "/api/v1/i18n/currencies?q=dol")
+	require.Equal(t, http.StatusOK, code)
+	codes := make([]string, len(page.Results))
+	for i, result := range page.Results {
+		codes[i] = result.Code
+	}
+	assert.Subset(t, codes, []string{"USD", "AUD", "CAD", "NZD", "SGD", "HKD"})
+
+	_, page = get[i18napi.CurrencyResult](t, router, "/api/v1/i18n/currencies?q=eur")
+	require.NotEmpty(t, page.Results)
+	euro := page.Results[0]
+	assert.Equal(t, "EUR", euro.Code, "an exact code ranks first")
+	assert.Contains(t, euro.Countries, "DE")
+
+	_, page = get[i18napi.CurrencyResult](t, router, "/api/v1/i18n/currencies?q=japon&locale=es")
+	require.NotEmpty(t, page.Results)
+	assert.Equal(t, "JPY", page.Results[0].Code, "localized country names match")
+
+	_, page = get[i18napi.CurrencyResult](t, router, "/api/v1/i18n/currencies?q=bitcoin")
+	require.NotEmpty(t, page.Results)
+	assert.Equal(t, "BTC", page.Results[0].Code)
+	assert.Empty(t, page.Results[0].Countries)
+}
+
+func TestSearchPagination(t *testing.T) {
+	router := newRouter(nil)
+
+	_, all := get[i18napi.CurrencyResult](t, router, "/api/v1/i18n/currencies?limit=100")
+	assert.Equal(t, len(i18n.GetSupportedCurrencies()), all.Total)
+	assert.Len(t, all.Results, all.Total)
+	assert.Nil(t, all.NextOffset)
+
+	_, first := get[i18napi.CurrencyResult](t, router, "/api/v1/i18n/currencies?limit=10")
+	require.Len(t, first.Results, 10)
+	require.NotNil(t, first.NextOffset)
+	assert.Equal(t, 10, *first.NextOffset)
+	assert.Equal(t, all.Results[:10], first.Results)
+
+	_, last := get[i18napi.CurrencyResult](t, router, "/api/v1/i18n/currencies?limit=10&offset=30")
+	assert.Equal(t, all.Results[30:], last.Results)
+	assert.Nil(t, last.NextOffset)
+
+	_, beyond := get[i18napi.CurrencyResult](t, router, "/api/v1/i18n/currencies?offset=1000")
+	assert.Empty(t, beyond.Results)
+	assert.Equal(t, all.Total, beyond.Total)
+
+	for _, path := range []string{
+		"/api/v1/i18n/currencies?limit=0",
+		"/api/v1/i18n/currencies?limit=101",
+		"/api/v1/i18n/currencies?offset=-1",
+		"/api/v1/i18n/timezones?locale=not%20a%20locale",
+	} {
+		code, _ := get[i18napi.CurrencyResult](t, router, path)
+		assert.Equal(t, http.StatusBadRequest, code, path)
+	}
+}
+
+func TestSearchCatalogCache(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	cache := respcache.New(time.Minute, 0)
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		if locale := c.GetHeader("X-Test-Locale"); locale != "" {
+			c.Request = c.Request.WithContext(geo.NewContext(c.Request.Context(), geo.Location{Locale: locale}))
+		}
+	})
+	i18napi.NewHandler(i18napi.WithCatalogCache(cache)).Register(router.Group("/api/v1"))
+
+	search := func(locale string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/i18n/currencies?q=euro", nil)
+		req.Header.Set("X-Test-Locale", locale)
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	assert.Equal(t, "MISS", search("").Header().Get(respcache.HeaderCache))
+	cached := search("")
+	assert.Equal(t, "HIT", cached.Header().Get(respcache.HeaderCache))
+	assert.Contains(t, cached.Body.String(), `"EUR"`)
+
+	// The detected locale changes the names, so it is part of the key
+	assert.Equal(t, "MISS", search("de").Header().Get(respcache.HeaderCache))
+	assert.Equal(t, "HIT", search("de").Header().Get(respcache.HeaderCache))
+}
+
+func TestSearchRefresh(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	cache := respcache.New(time.Minute, 0)
+	handler := i18napi.NewHandler(i18napi.WithCatalogCache(cache))
+	router := gin.New()
+	handler.Register(router.Group("/api/v1"))
+	const path = "/api/v1/i18n/currencies?q=loyalty"
+
+	_, page := get[i18napi.CurrencyResult](t, router, path)
+	assert.Empty(t, page.Results)
+
+	require.NoError(t, i18n.RegisterCurrency(i18n.Currency{Code: "XLP", Symbol: "pts", Name: "Loyalty Points"}))
+	t.Cleanup(func() { _ = i18n.UnregisterCurrency("XLP") })
+	_, page = get[i18napi.CurrencyResult](t, router, path)
+	assert.Empty(t, page.Results, "served from the cache until refreshed")
+
+	handler.Refresh()
+	assert.Zero(t, cache.Len())
+	_, page = get[i18napi.CurrencyResult](t, router, path)
+	require.Len(t, page.Results, 1)
+	assert.Equal(t, "XLP", page.Results[0].Code)
+}
@@ -0,0 +1,82 @@
+package integration_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"golang-arch/internal/bootstrap"
+	"golang-arch/internal/shared/api"
+	"golang-arch/internal/shared/config"
+)
+
+func TestAdminServerDisabled(t *testing.T) {
+	tc, err := bootstrap.NewTestContainer()
+	require.NoError(t, err)
+	defer tc.Close()
+
+	server, err := bootstrap.NewAdminServer(tc.Container)
+	require.NoError(t, err)
+	assert.Nil(t, server)
+
+	tc.Config.Admin = config.AdminConfig{Enabled: true}
+	_, err = bootstrap.NewAdminServer(tc.Container)
+	assert.ErrorContains(t, err, "admin.token")
+}
+
+func TestAdminServerAuth(t *testing.T) {
+	cfg := bootstrap.DefaultTestConfig()
+	cfg.Admin = config.AdminConfig{Enabled: true, Host: "127.0.0.1", Port: 6060, Token: "s3cret"}
+	tc, err := bootstrap.NewTestContainer(bootstrap.WithTestConfig(cfg))
+	require.NoError(t, err)
+	defer tc.Close()
+
+	server, err := bootstrap.NewAdminServer(tc.Container)
+	require.NoError(t, err)
+	require.NotNil(t, server)
+	assert.Equal(t, "127.0.0.1:6060", server.Addr)
+
+	tests := []struct {
+		name          string
+		authorization string
+		status        int
+		code          string
+	}{
+		{name: "missing", status: http.StatusUnauthorized, code: api.ErrCodeUnauthorized},
+		{name: "not a bearer token", authorization: "Basic czNjcmV0", status: http.StatusUnauthorized, code: api.ErrCodeUnauthorized},
+		{name: "empty bearer token", authorization: "Bearer ", status: http.StatusUnauthorized, code: api.ErrCodeUnauthorized},
+		{name: "wrong token", authorization: "Bearer guess", status: http.StatusForbidden, code: api.ErrCodeForbidden},
+		{name: "token prefix", authorization: "Bearer s3c", status: http.StatusForbidden, code: api.ErrCodeForbidden},
+		{name: "valid", authorization: "Bearer s3cret", status: http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/debug/vars", nil)
+			if tt.authorization != "" {
+				req.Header.Set("Authorization", tt.authorization)
+			}
+			w := httptest.NewRecorder()
+			server.Handler.ServeHTTP(w, req)
+
+			require.Equal(t, tt.status, w.Code)
+			if tt.code == "" {
+				assert.Contains(t, w.Body.String(), "memstats")
+				return
+			}
+
+			var envelope api.Response
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &envelope))
+			assert.False(t, envelope.Success)
+			assert.Equal(t, tt.code, envelope.Code)
+			assert.Equal(t, http.StatusText(tt.status), envelope.Message)
+			assert.NotEmpty(t, envelope.Error)
+			if tt.status == http.StatusUnauthorized {
+				assert.Equal(t, "Bearer", w.Header().Get("WWW-Authenticate"))
+			}
+		})
+	}
+}
@@ -0,0 +1,23 @@
+package integration_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"golang-arch/internal/bootstrap"
+)
+
+func TestNewContainer_ChaosOnlyInListedEnvironments(t *testing.T) {
+	for _, environment := range []string{"production", "Production", "prod", "", "qa-eu"} {
+		t.Run(environment, func(t *testing.T) {
+			cfg := bootstrap.DefaultTestConfig()
+			cfg.Chaos.Enabled = true
+			cfg.Metrics.Environment = environment
+
+			container, err := bootstrap.NewContainer(cfg)
+			assert.ErrorContains(t, err, "chaos.enabled is only allowed when metrics.environment is one of")
+			assert.Nil(t, container)
+		})
+	}
+}
@@ -0,0 +1,103 @@
+package integration_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"golang-arch/internal/bootstrap"
+	"golang-arch/internal/shared/geo"
+)
+
+// ipRecorder is a geo resolver remembering the client IP it was asked for
+type ipRecorder struct {
+	ip netip.Addr
+}
+
+func (r *ipRecorder) Lookup(ip netip.Addr) (geo.Location, bool, error) {
+	r.ip = ip
+	return geo.Location{}, false, nil
+}
+
+func TestServer_ClientIP(t *testing.T) {
+	tests := []struct {
+		name     string
+		proxies  []string
+		platform string
+		headers  map[string]string
+		want     string
+	}{
+		{
+			name:    "no trusted proxies ignores forwarding headers",
+			headers: map[string]string{"X-Forwarded-For": "203.0.113.7"},
+			want:    "192.0.2.1",
+		},
+		{
+			name:    "trusted proxy forwards the client IP",
+			proxies: []string{" 192.0.2.0/24"},
+			headers: map[string]string{"X-Forwarded-For": "203.0.113.7, 192.0.2.9"},
+			want:    "203.0.113.7",
+		},
+		{
+			name:    "X-Real-IP from a trusted proxy",
+			proxies: []string{"192.0.2.1"},
+			headers: map[string]string{"X-Real-IP": "203.0.113.8"},
+			want:    "203.0.113.8",
+		},
+		{
+			name:    "untrusted peer cannot spoof",
+			proxies: []string{"10.0.0.0/8"},
+			headers: map[string]string{"X-Forwarded-For": "203.0.113.7"},
+			want:    "192.0.2.1",
+		},
+		{
+			name:     "trusted platform header",
+			platform: "cloudflare",
+			headers:  map[string]string{"CF-Connecting-IP": "203.0.113.9"},
+			want:     "203.0.113.9",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := bootstrap.DefaultTestConfig()
+			cfg.Server.TrustedProxies = tt.proxies
+			cfg.Server.RemoteIPHeaders = []string{"X-Forwarded-For", "X-Real-IP"}
+			cfg.Server.TrustedPlatform = tt.platform
+			tc, err := bootstrap.NewTestContainer(bootstrap.WithTestConfig(cfg))
+			require.NoError(t, err)
+			defer tc.Close()
+
+			recorder := &ipRecorder{}
+			tc.Geo = recorder
+			server, err := bootstrap.NewServer(tc.Container)
+			require.NoError(t, err)
+
+			req := httptest.NewRequest(http.MethodGet, "/health", nil)
+			for name, value := range tt.headers {
+				req.Header.Set(name, value)
+			}
+			rec := httptest.NewRecorder()
+			server.ServeHTTP(rec, req)
+
+			require.Equal(t, http.StatusOK, rec.Code)
+			assert.Equal(t, tt.want, recorder.ip.String())
+		})
+	}
+}
+
+func TestServer_InvalidTrustedProxies(t *testing.T) {
+	cfg := bootstrap.DefaultTestConfig()
+	cfg.Server.TrustedProxies = []string{"10.0.0.0/8", "not-an-ip"}
+	tc, err := bootstrap.NewTestContainer(bootstrap.WithTestConfig(cfg))
+	require.NoError(t, err)
+	defer tc.Close()
+
+	// Trusting no proxy instead would silently log every client as the proxy
+	server, err := bootstrap.NewServer(tc.Container)
+	assert.Nil(t, server)
+	assert.ErrorContains(t, err, "server.trusted_proxies")
+}
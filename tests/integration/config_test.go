@@ -0,0 +1,119 @@
+package integration_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+
+	"golang-arch/internal/bootstrap"
+	"golang-arch/internal/shared/config"
+	"golang-arch/pkg/logger"
+)
+
+// loadConfig runs LoadConfig against the given config.yaml
+func loadConfig(t *testing.T, yaml string) (*config.AppConfig, error) {
+	t.Helper()
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(dir+"/config.yaml", []byte(yaml), 0o644))
+
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+	require.NoError(t, os.Chdir(dir))
+	viper.Reset()
+	t.Cleanup(func() {
+		viper.Reset()
+		require.NoError(t, os.Chdir(wd))
+	})
+	return bootstrap.LoadConfig()
+}
+
+func TestLoadConfig_Expansion(t *testing.T) {
+	t.Setenv("APP_DB_HOST", "db.internal")
+	t.Setenv("APP_OTP_SECRET", "s3cret")
+
+	cfg, err := loadConfig(t, `
+database:
+  host: "${APP_DB_HOST}"
+  name: "${APP_DB_NAME:-golang_arch}"
+  user: "${database.name}_user"
+otp:
+  secret: "${APP_OTP_SECRET}"
+admin:
+  dump_dir: "/var/dumps/${database.host}:${database.port}"
+security:
+  headers:
+    content_security_policy: "default-src 'self' $${nonce}"
+metering:
+  consumers:
+    - id: "${APP_OTP_SECRET}-consumer"
+      plan: "free"
+`)
+	require.NoError(t, err)
+	assert.Equal(t, "db.internal", cfg.Database.Host)
+	assert.Equal(t, "golang_arch", cfg.Database.Name)
+	assert.Equal(t, "golang_arch_user", cfg.Database.User)
+	assert.Equal(t, "s3cret", cfg.OTP.Secret)
+	assert.Equal(t, "/var/dumps/db.internal:5432", cfg.Admin.DumpDir)
+	assert.Equal(t, "default-src 'self' ${nonce}", cfg.Security.Headers.ContentSecurityPolicy)
+	require.Len(t, cfg.Metering.Consumers, 1)
+	assert.Equal(t, "s3cret-consumer", cfg.Metering.Consumers[0].ID)
+}
+
+func TestLoadConfig_ExpansionErrors(t *testing.T) {
+	_, err := loadConfig(t, `
+database:
+  host: "${APP_UNSET_VARIABLE}"
+  name: "${database.user}"
+  user: "${database.name}"
+  password: "${database.nope}"
+`)
+	require.Error(t, err)
+	assert.ErrorContains(t, err, "database.host: unresolved placeholder ${APP_UNSET_VARIABLE}")
+	assert.ErrorContains(t, err, "reference cycle")
+	assert.ErrorContains(t, err, "database.password: unresolved placeholder ${database.nope}")
+}
+
+func TestLoadConfig_LogLevels(t *testing.T) {
+	const yaml = `
+log:
+  level: "warn"
+  levels: "worker=error"
+`
+	tests := []struct {
+		name      string
+		level     string // LOG_LEVEL
+		levels    string // LOG_LEVELS
+		logger    string
+		want      zapcore.Level
+		wantError string
+	}{
+		{name: "config level", logger: "rates", want: zapcore.WarnLevel},
+		{name: "config override", logger: "worker.jobs", want: zapcore.ErrorLevel},
+		{name: "env level wins", level: "debug", logger: "rates", want: zapcore.DebugLevel},
+		{name: "config override wins over env level", level: "debug", logger: "worker", want: zapcore.ErrorLevel},
+		{name: "env overrides replace config ones", levels: "api.http=debug", logger: "worker", want: zapcore.WarnLevel},
+		{name: "env override", levels: "api.http=debug", logger: "api.http", want: zapcore.DebugLevel},
+		{name: "invalid env level", level: "loud", wantError: "loud"},
+		{name: "invalid env override", levels: "worker=loud", wantError: "invalid log level for worker"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("LOG_LEVEL", tt.level)
+			t.Setenv("LOG_LEVELS", tt.levels)
+			cfg, err := loadConfig(t, yaml)
+			require.NoError(t, err)
+
+			factory, err := logger.NewFactory(cfg.Log.Level, cfg.Log.Format, cfg.Log.Levels)
+			if tt.wantError != "" {
+				assert.ErrorContains(t, err, tt.wantError)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, factory.LevelFor(tt.logger))
+		})
+	}
+}
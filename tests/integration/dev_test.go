@@ -0,0 +1,70 @@
+package integration_test
+
+import (
+	"bytes"
+	"errors"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"golang-arch/internal/bootstrap"
+)
+
+func TestNewDevContainer_RefusedOutsideDevBuilds(t *testing.T) {
+	if bootstrap.DevModeAvailable {
+		t.Skip("built with -tags dev")
+	}
+
+	container, err := bootstrap.NewDevContainer(bootstrap.DefaultTestConfig())
+	assert.ErrorIs(t, err, bootstrap.ErrDevModeUnavailable)
+	assert.Nil(t, container)
+}
+
+// TestMainCommand_DevRefused builds cmd/main without the dev tag, as
+// production images are, and checks it refuses the dev profile
+func TestMainCommand_DevRefused(t *testing.T) {
+	if testing.Short() {
+		t.Skip("builds the server binary")
+	}
+	root, err := filepath.Abs(filepath.Join("..", ".."))
+	require.NoError(t, err)
+	binary := filepath.Join(t.TempDir(), "main")
+	build := exec.Command("go", "build", "-o", binary, "./cmd/main")
+	build.Dir = root
+	out, err := build.CombinedOutput()
+	require.NoError(t, err, string(out))
+
+	tests := []struct {
+		name   string
+		args   []string
+		status int
+		output string
+	}{
+		{name: "serve --dev", args: []string{"serve", "--dev"}, status: 1, output: bootstrap.ErrDevModeUnavailable.Error()},
+		{name: "--dev without command", args: []string{"--dev"}, status: 1, output: bootstrap.ErrDevModeUnavailable.Error()},
+		{name: "unknown command", args: []string{"dev"}, status: 2, output: `unknown command "dev"`},
+		{name: "help", args: []string{"help"}, status: 0, output: "Commands:"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := exec.Command(binary, tt.args...)
+			cmd.Dir = root
+			var output bytes.Buffer
+			cmd.Stdout, cmd.Stderr = &output, &output
+			err := cmd.Run()
+
+			status := 0
+			var exitErr *exec.ExitError
+			if errors.As(err, &exitErr) {
+				status = exitErr.ExitCode()
+			} else {
+				require.NoError(t, err)
+			}
+			assert.Equal(t, tt.status, status, output.String())
+			assert.Contains(t, output.String(), tt.output)
+		})
+	}
+}
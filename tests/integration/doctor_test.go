@@ -0,0 +1,118 @@
+package integration_test
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"golang-arch/internal/bootstrap"
+	"golang-arch/internal/shared/testutil"
+)
+
+func TestCheckConfig(t *testing.T) {
+	cfg := bootstrap.DefaultTestConfig()
+	result := bootstrap.CheckConfig(cfg)
+	assert.NotEqual(t, bootstrap.CheckFail, result.Status, result.Problems)
+
+	cfg.Rates.RefreshSchedule = "every now and then"
+	cfg.Erasure.Strategy = "shred"
+	cfg.Admin.Enabled = true
+	cfg.Server.TrustedProxies = []string{"bogus"}
+	result = bootstrap.CheckConfig(cfg)
+	assert.Equal(t, bootstrap.CheckFail, result.Status)
+	problems := strings.Join(result.Problems, "\n")
+	for _, section := range []string{"rates.refresh_schedule:", "erasure:", "admin:", "server:"} {
+		assert.Contains(t, problems, section)
+	}
+}
+
+func TestCheckConnections(t *testing.T) {
+	server, client := testutil.NewRedis(t)
+	assert.Equal(t, bootstrap.CheckOK, bootstrap.CheckRedis(context.Background(), client).Status)
+
+	server.Close()
+	assert.Equal(t, bootstrap.CheckFail, bootstrap.CheckRedis(context.Background(), client).Status)
+
+	db, mock, err := sqlmock.New(sqlmock.MonitorPingsOption(true))
+	require.NoError(t, err)
+	defer db.Close()
+	mock.ExpectPing()
+	assert.Equal(t, bootstrap.CheckOK, bootstrap.CheckDatabase(context.Background(), db).Status)
+}
+
+func TestCheckMigrations(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"000001_init.up.sql", "000001_init.down.sql", "000002_users.up.sql", "README.md"} {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), nil, 0o644))
+	}
+
+	tests := []struct {
+		name    string
+		version int64
+		dirty   bool
+		status  bootstrap.CheckStatus
+		detail  string
+	}{
+		{"current", 2, false, bootstrap.CheckOK, "current at version 2"},
+		{"pending", 1, false, bootstrap.CheckFail, "at version 1, 1 pending (latest 2)"},
+		{"dirty", 2, true, bootstrap.CheckFail, "version 2 is dirty, a migration failed halfway"},
+		{"ahead", 3, false, bootstrap.CheckWarn, "at version 3, newer than the latest migration 2 in " + dir},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			require.NoError(t, err)
+			defer db.Close()
+			mock.ExpectQuery("SELECT version, dirty FROM schema_migrations").
+				WillReturnRows(sqlmock.NewRows([]string{"version", "dirty"}).AddRow(tt.version, tt.dirty))
+
+			result := bootstrap.CheckMigrations(context.Background(), db, dir)
+			assert.Equal(t, tt.status, result.Status)
+			assert.Equal(t, tt.detail, result.Detail)
+		})
+	}
+
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+	mock.ExpectQuery("SELECT version, dirty FROM schema_migrations").WillReturnError(assert.AnError)
+	assert.Equal(t, bootstrap.CheckFail, bootstrap.CheckMigrations(context.Background(), db, dir).Status)
+	assert.Equal(t, bootstrap.CheckFail, bootstrap.CheckMigrations(context.Background(), db, t.TempDir()).Status)
+}
+
+func TestCheckTimezones(t *testing.T) {
+	result := bootstrap.CheckTimezones("UTC", "Asia/Jakarta", "")
+	assert.Equal(t, bootstrap.CheckOK, result.Status)
+
+	result = bootstrap.CheckTimezones("UTC", "Mars/Olympus_Mons")
+	assert.Equal(t, bootstrap.CheckFail, result.Status)
+	assert.Len(t, result.Problems, 1)
+}
+
+func TestWriteDoctorReport(t *testing.T) {
+	var out bytes.Buffer
+	failed := bootstrap.WriteDoctorReport(&out, []bootstrap.CheckResult{
+		{Name: "config", Status: bootstrap.CheckWarn, Detail: "valid with 1 warning(s)", Problems: []string{"otp: no secret"}},
+		{Name: "redis", Status: bootstrap.CheckOK, Detail: "reachable"},
+	}, false)
+	assert.False(t, failed)
+	assert.Equal(t, "[WARN] config     valid with 1 warning(s)\n"+
+		"       - otp: no secret\n"+
+		"[OK  ] redis      reachable\n"+
+		"\nAll checks passed\n", out.String())
+
+	out.Reset()
+	failed = bootstrap.WriteDoctorReport(&out, []bootstrap.CheckResult{
+		{Name: "database", Status: bootstrap.CheckFail, Detail: "refused"},
+	}, true)
+	assert.True(t, failed)
+	assert.Contains(t, out.String(), "\033[31mFAIL\033[0m")
+	assert.Contains(t, out.String(), "Some checks failed")
+}
@@ -0,0 +1,117 @@
+package integration_test
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"golang-arch/internal/bootstrap"
+	"golang-arch/internal/shared/consent"
+	i18n "golang-arch/internal/shared/domain/internationalization"
+	"golang-arch/internal/shared/erasure"
+	"golang-arch/internal/shared/notify"
+	"golang-arch/internal/shared/otp"
+	"golang-arch/internal/shared/push"
+	"golang-arch/internal/shared/repository"
+)
+
+// TestErasure_RemovesSubjectData stores a subject's data in every store the
+// container registers an eraser for and checks none of it survives Erase
+func TestErasure_RemovesSubjectData(t *testing.T) {
+	tc, err := bootstrap.NewTestContainer()
+	require.NoError(t, err)
+	defer func() { assert.NoError(t, tc.Close()) }()
+	ctx := context.Background()
+
+	assert.Equal(t, []string{"consents", "notification_preferences", "push_devices", "otp", "audit_log"}, tc.Erasure.Repositories())
+
+	const subjectID, otherID = "user-1", "user-2"
+	phone, err := i18n.NewPhoneFromString("+15551234567")
+	require.NoError(t, err)
+	subject := erasure.Subject{ID: subjectID, Emails: []string{"Ada@Example.com"}, Phones: []i18n.Phone{*phone}}
+
+	for _, id := range []string{subjectID, otherID} {
+		_, err = tc.Consent.Grant(ctx, consent.Decision{SubjectID: id, Purpose: consent.PurposeMarketing, Channel: consent.ChannelEmail})
+		require.NoError(t, err)
+		_, err = tc.Notify.SavePreferences(ctx, notify.Preferences{
+			RecipientID: id,
+			Addresses:   map[notify.Channel]string{notify.ChannelEmail: id + "@example.com"},
+		})
+		require.NoError(t, err)
+		_, err = tc.Push.Register(ctx, push.Device{Token: "token-" + id, RecipientID: id, Platform: push.PlatformAndroid})
+		require.NoError(t, err)
+		require.NoError(t, tc.Push.Send(ctx, notify.Message{Notification: notify.Notification{RecipientID: id, Template: "welcome"}}))
+	}
+	for _, address := range []string{"ada@example.com", "grace@example.com"} {
+		recipient, err := otp.NewRecipient(otp.ChannelEmail, address)
+		require.NoError(t, err)
+		_, err = tc.OTP.Request(ctx, recipient, "login", i18n.MustParseLocale("en"))
+		require.NoError(t, err)
+	}
+	recipient, err := otp.NewRecipient(otp.ChannelSMS, phone.FormatCompact())
+	require.NoError(t, err)
+	_, err = tc.OTP.Request(ctx, recipient, "login", i18n.MustParseLocale("en"))
+	require.NoError(t, err)
+	require.NoError(t, tc.Audit.Append(ctx, tc.DB, repository.Entry{ID: "a-1", Entity: "user", EntityID: subjectID,
+		Operation: repository.Created, New: json.RawMessage(`{"name":"Ada","email":"ada@example.com"}`)}))
+	redisKeys := len(tc.Miniredis.Keys())
+
+	record, err := tc.Erasure.Erase(ctx, erasure.Request{Subject: subject, Reason: "gdpr_art17", RequestedBy: "test"})
+	require.NoError(t, err)
+	assert.Equal(t, erasure.StatusCompleted, record.Status())
+	counts := make(map[string]int)
+	for _, result := range record.Results {
+		counts[result.Repository] = result.Records
+	}
+	assert.Equal(t, map[string]int{
+		"consents":                 1,
+		"notification_preferences": 1,
+		"push_devices":             2, // The device and its queued push
+		"otp":                      8, // Code, send counter and cooldown per address, and two queued deliveries
+		"audit_log":                1,
+	}, counts)
+
+	consents, err := tc.Consent.List(ctx, subjectID)
+	require.NoError(t, err)
+	assert.Empty(t, consents)
+	prefs, err := tc.Notify.Preferences(ctx, subjectID)
+	require.NoError(t, err)
+	assert.Empty(t, prefs.Addresses)
+	devices, err := tc.Push.Devices(ctx, subjectID)
+	require.NoError(t, err)
+	assert.Empty(t, devices)
+	pending, err := tc.Push.Pending(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), pending, "only the other recipient's push is queued")
+	assert.Len(t, tc.Miniredis.Keys(), redisKeys-6)
+	queued, err := tc.Miniredis.List("otp:outbox")
+	require.NoError(t, err)
+	require.Len(t, queued, 1, "only the other address's code is queued")
+	history, err := tc.Audit.History(ctx, tc.DB, "user", subjectID)
+	require.NoError(t, err)
+	require.Len(t, history, 1)
+	assert.Nil(t, history[0].New)
+
+	// The other subject is untouched
+	consents, err = tc.Consent.List(ctx, otherID)
+	require.NoError(t, err)
+	assert.Len(t, consents, 1)
+	prefs, err = tc.Notify.Preferences(ctx, otherID)
+	require.NoError(t, err)
+	assert.Equal(t, otherID+"@example.com", prefs.Addresses[notify.ChannelEmail])
+	devices, err = tc.Push.Devices(ctx, otherID)
+	require.NoError(t, err)
+	assert.Len(t, devices, 1)
+
+	// Erasing again is a no-op
+	record, err = tc.Erasure.Erase(ctx, erasure.Request{Subject: subject, Reason: "gdpr_art17", RequestedBy: "test"})
+	require.NoError(t, err)
+	assert.Zero(t, record.Records())
+	for _, key := range tc.Miniredis.Keys() {
+		assert.False(t, strings.Contains(key, "ada@example.com") || strings.Contains(key, "+15551234567"), key)
+	}
+}
@@ -0,0 +1,364 @@
+package integration_test
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/http2"
+
+	"golang-arch/internal/bootstrap"
+	"golang-arch/internal/shared/config"
+)
+
+// fakeGRPC stands in for *grpc.Server: it serves HTTP/2 connections and
+// answers every request with "grpc"
+type fakeGRPC struct {
+	mu       sync.Mutex
+	listener net.Listener
+	stopped  bool
+}
+
+func (f *fakeGRPC) Serve(listener net.Listener) error {
+	f.mu.Lock()
+	f.listener = listener
+	f.mu.Unlock()
+	server := &http2.Server{}
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/grpc")
+		io.WriteString(w, "grpc")
+	})
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			return err
+		}
+		go server.ServeConn(conn, &http2.ServeConnOpts{Handler: handler})
+	}
+}
+
+func (f *fakeGRPC) GracefulStop() { f.Stop() }
+
+func (f *fakeGRPC) Stop() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.stopped = true
+	if f.listener != nil {
+		f.listener.Close()
+	}
+}
+
+// h2cClient speaks HTTP/2 with prior knowledge over plain TCP
+func h2cClient() *http.Client {
+	return &http.Client{Transport: &http2.Transport{
+		AllowHTTP: true,
+		DialTLSContext: func(ctx context.Context, network, addr string, _ *tls.Config) (net.Conn, error) {
+			var dialer net.Dialer
+			return dialer.DialContext(ctx, network, addr)
+		},
+	}}
+}
+
+// serve starts the server on a random port and returns its base URL
+func serve(t *testing.T, server *bootstrap.Server) (string, <-chan error) {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	done := make(chan error, 1)
+	go func() { done <- server.Serve(listener) }()
+	return "http://" + listener.Addr().String(), done
+}
+
+func get(t *testing.T, client *http.Client, url, contentType string) (*http.Response, string) {
+	t.Helper()
+	req, err := http.NewRequest(http.MethodPost, url, strings.NewReader(""))
+	require.NoError(t, err)
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	resp, err := client.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return resp, string(body)
+}
+
+func TestServer_H2C(t *testing.T) {
+	cfg := bootstrap.DefaultTestConfig()
+	cfg.Server.H2C = true
+	tc, err := bootstrap.NewTestContainer(bootstrap.WithTestConfig(cfg))
+	require.NoError(t, err)
+	defer tc.Close()
+
+	server, err := bootstrap.NewServer(tc.Container)
+	require.NoError(t, err)
+	url, done := serve(t, server)
+
+	resp, err := h2cClient().Get(url + "/health")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, 2, resp.ProtoMajor)
+
+	resp, err = http.Get(url + "/health")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, 1, resp.ProtoMajor)
+
+	require.NoError(t, server.Shutdown(context.Background()))
+	assert.ErrorIs(t, <-done, http.ErrServerClosed)
+}
+
+func TestServer_GRPCSharesPort(t *testing.T) {
+	cfg := bootstrap.DefaultTestConfig()
+	cfg.Server.H2C = true
+	cfg.Server.GRPC = true
+	tc, err := bootstrap.NewTestContainer(bootstrap.WithTestConfig(cfg))
+	require.NoError(t, err)
+	defer tc.Close()
+
+	server, err := bootstrap.NewServer(tc.Container)
+	require.NoError(t, err)
+	grpcServer := &fakeGRPC{}
+	server.RegisterGRPC(grpcServer)
+	url, done := serve(t, server)
+
+	_, body := get(t, h2cClient(), url+"/pkg.Service/Method", "application/grpc")
+	assert.Equal(t, "grpc", body)
+
+	resp, _ := get(t, h2cClient(), url+"/health", "")
+	// The router has no POST /health, but the request reached it
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+	health, err := http.Get(url + "/health")
+	require.NoError(t, err)
+	health.Body.Close()
+	assert.Equal(t, http.StatusOK, health.StatusCode)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	require.NoError(t, server.Shutdown(ctx))
+	select {
+	case err := <-done:
+		assert.True(t, errors.Is(err, http.ErrServerClosed), err)
+	case <-time.After(time.Second):
+		t.Fatal("Serve did not return after Shutdown")
+	}
+	assert.True(t, grpcServer.stopped)
+}
+
+func TestServer_ShutdownBeforeServe(t *testing.T) {
+	for _, grpc := range []bool{false, true} {
+		t.Run("grpc="+strconv.FormatBool(grpc), func(t *testing.T) {
+			cfg := bootstrap.DefaultTestConfig()
+			cfg.Server.GRPC = grpc
+			tc, err := bootstrap.NewTestContainer(bootstrap.WithTestConfig(cfg))
+			require.NoError(t, err)
+			defer tc.Close()
+
+			server, err := bootstrap.NewServer(tc.Container)
+			require.NoError(t, err)
+			server.RegisterGRPC(&fakeGRPC{})
+			require.NoError(t, server.Shutdown(context.Background()))
+
+			_, done := serve(t, server)
+			select {
+			case err := <-done:
+				assert.ErrorIs(t, err, http.ErrServerClosed)
+			case <-time.After(time.Second):
+				t.Fatal("Serve started after Shutdown")
+			}
+		})
+	}
+}
+
+func TestServer_ShutdownClosesSharedListener(t *testing.T) {
+	cfg := bootstrap.DefaultTestConfig()
+	cfg.Server.GRPC = true
+	tc, err := bootstrap.NewTestContainer(bootstrap.WithTestConfig(cfg))
+	require.NoError(t, err)
+	defer tc.Close()
+
+	server, err := bootstrap.NewServer(tc.Container)
+	require.NoError(t, err)
+	server.RegisterGRPC(&fakeGRPC{})
+	url, done := serve(t, server)
+	require.Eventually(t, func() bool {
+		resp, err := http.Get(url + "/health")
+		if err != nil {
+			return false
+		}
+		resp.Body.Close()
+		return resp.StatusCode == http.StatusOK
+	}, time.Second, 10*time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	require.NoError(t, server.Shutdown(ctx))
+	<-done
+
+	_, err = net.DialTimeout("tcp", strings.TrimPrefix(url, "http://"), time.Second)
+	assert.Error(t, err, "the port is released")
+}
+
+func TestServer_GRPCDisabledIgnoresRegistration(t *testing.T) {
+	tc, err := bootstrap.NewTestContainer()
+	require.NoError(t, err)
+	defer tc.Close()
+
+	server, err := bootstrap.NewServer(tc.Container)
+	require.NoError(t, err)
+	grpcServer := &fakeGRPC{}
+	server.RegisterGRPC(grpcServer)
+	url, done := serve(t, server)
+
+	resp, err := http.Get(url + "/health")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	require.NoError(t, server.Shutdown(context.Background()))
+	assert.ErrorIs(t, <-done, http.ErrServerClosed)
+	assert.False(t, grpcServer.stopped)
+}
+
+func TestListen_UnixSocket(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.sock")
+	// A socket left behind by a crashed run is replaced
+	stale, err := net.Listen("unix", path)
+	require.NoError(t, err)
+	stale.(*net.UnixListener).SetUnlinkOnClose(false)
+	stale.Close()
+
+	listener, err := bootstrap.Listen(config.ServerConfig{Socket: path, SocketMode: "0600"})
+	require.NoError(t, err)
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+
+	tc, err := bootstrap.NewTestContainer()
+	require.NoError(t, err)
+	defer tc.Close()
+	server, err := bootstrap.NewServer(tc.Container)
+	require.NoError(t, err)
+	done := make(chan error, 1)
+	go func() { done <- server.Serve(listener) }()
+
+	client := &http.Client{Transport: &http.Transport{
+		DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+			var dialer net.Dialer
+			return dialer.DialContext(ctx, "unix", path)
+		},
+	}}
+	resp, err := client.Get("http://unix/health")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	require.NoError(t, server.Shutdown(context.Background()))
+	assert.ErrorIs(t, <-done, http.ErrServerClosed)
+	_, err = os.Stat(path)
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestListen_RefusesToReplaceRegularFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.sock")
+	require.NoError(t, os.WriteFile(path, []byte("data"), 0o644))
+
+	_, err := bootstrap.Listen(config.ServerConfig{Socket: path})
+	assert.ErrorContains(t, err, "not a socket")
+
+	_, err = bootstrap.Listen(config.ServerConfig{Socket: filepath.Join(t.TempDir(), "x.sock"), SocketMode: "rw"})
+	assert.ErrorContains(t, err, "socket_mode")
+}
+
+func TestListen_SystemdActivation(t *testing.T) {
+	// Variables meant for another process are ignored
+	t.Setenv("LISTEN_PID", "1")
+	t.Setenv("LISTEN_FDS", "1")
+	listener, err := bootstrap.Listen(config.ServerConfig{SystemdActivation: true, Port: 0})
+	require.NoError(t, err)
+	assert.IsType(t, &net.TCPListener{}, listener)
+	listener.Close()
+
+	// A missing named socket is an error, and the variables are consumed
+	t.Setenv("LISTEN_PID", strconv.Itoa(os.Getpid()))
+	t.Setenv("LISTEN_FDNAMES", "metrics")
+	_, err = bootstrap.Listen(config.ServerConfig{SystemdActivation: true, SystemdName: "http"})
+	assert.ErrorContains(t, err, `no socket named "http"`)
+	assert.Empty(t, os.Getenv("LISTEN_FDS"))
+}
+
+func TestListen_ReusePort(t *testing.T) {
+	if runtime.GOOS == "windows" || runtime.GOOS == "solaris" || runtime.GOOS == "illumos" {
+		t.Skip("SO_REUSEPORT is not supported on " + runtime.GOOS)
+	}
+	first, err := bootstrap.Listen(config.ServerConfig{ReusePort: true})
+	require.NoError(t, err)
+	defer first.Close()
+	port := first.Addr().(*net.TCPAddr).Port
+
+	// The next release binds the port while this one still listens
+	second, err := bootstrap.Listen(config.ServerConfig{Port: port, ReusePort: true})
+	require.NoError(t, err)
+	defer second.Close()
+
+	_, err = bootstrap.Listen(config.ServerConfig{Port: port})
+	assert.Error(t, err, "without reuse_port the port stays taken")
+}
+
+func TestServer_DrainFailsReadiness(t *testing.T) {
+	tc, err := bootstrap.NewTestContainer()
+	require.NoError(t, err)
+	defer tc.Close()
+
+	server, err := bootstrap.NewServer(tc.Container)
+	require.NoError(t, err)
+	url, done := serve(t, server)
+
+	resp, err := http.Get(url + "/ready")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	drained := make(chan struct{})
+	go func() {
+		server.Drain(context.Background(), 5*time.Second)
+		close(drained)
+	}()
+	require.Eventually(t, server.Draining, time.Second, time.Millisecond)
+
+	resp, err = http.Get(url + "/ready")
+	require.NoError(t, err)
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+	assert.JSONEq(t, `{"status":"draining"}`, string(body))
+	assert.True(t, resp.Close, "keep-alive connections are closed while draining")
+
+	select {
+	case <-drained:
+		t.Fatal("Drain returned before the delay")
+	default:
+	}
+	tc.FakeClock.Advance(5 * time.Second)
+	<-drained
+
+	require.NoError(t, server.Shutdown(context.Background()))
+	assert.ErrorIs(t, <-done, http.ErrServerClosed)
+}
@@ -0,0 +1,95 @@
+package integration_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"golang-arch/internal/bootstrap"
+	"golang-arch/internal/shared/config"
+)
+
+func findRoute(routes []bootstrap.RouteInfo, method, path string) (bootstrap.RouteInfo, bool) {
+	for _, route := range routes {
+		if route.Method == method && route.Path == path {
+			return route, true
+		}
+	}
+	return bootstrap.RouteInfo{}, false
+}
+
+func TestListRoutes(t *testing.T) {
+	cfg := bootstrap.DefaultTestConfig()
+	cfg.Metering = config.MeteringConfig{Enabled: true, Header: "X-API-Key"}
+	tc, err := bootstrap.NewTestContainer(bootstrap.WithTestConfig(cfg))
+	require.NoError(t, err)
+	defer tc.Close()
+
+	routes, err := bootstrap.ListRoutes(tc.Container)
+	require.NoError(t, err)
+
+	health, ok := findRoute(routes, "GET", "/health")
+	require.True(t, ok)
+	assert.Equal(t, "bootstrap.(*Server).healthCheck", health.Handler)
+	assert.Empty(t, health.Auth)
+	assert.Contains(t, health.Middleware, "api.ErrorHandler")
+	assert.NotContains(t, health.Middleware, "metering.(*Meter).Middleware")
+
+	usage, ok := findRoute(routes, "GET", "/api/v1/usage")
+	require.True(t, ok)
+	assert.Equal(t, "metering.(*Handler).current", usage.Handler)
+	assert.Equal(t, "api key (X-API-Key)", usage.Auth)
+	assert.Equal(t, "metering.(*Meter).Middleware", usage.Middleware[len(usage.Middleware)-1])
+
+	otp, ok := findRoute(routes, "POST", "/api/v1/otp/request")
+	require.True(t, ok)
+	assert.Empty(t, otp.Auth)
+	assert.Contains(t, otp.Middleware, "metering.(*Meter).Middleware")
+
+	// Sorted by path, then method
+	for i := 1; i < len(routes); i++ {
+		prev, cur := routes[i-1], routes[i]
+		assert.True(t, prev.Path < cur.Path || prev.Path == cur.Path && prev.Method < cur.Method, "%v before %v", prev, cur)
+	}
+}
+
+func TestServer_RegisterAPIConflicts(t *testing.T) {
+	tc, err := bootstrap.NewTestContainer()
+	require.NoError(t, err)
+	defer tc.Close()
+	server, err := bootstrap.NewServer(tc.Container)
+	require.NoError(t, err)
+	handler := func(*gin.Context) {}
+
+	require.NoError(t, server.RegisterAPI("bearer token", func(group *gin.RouterGroup) {
+		group.GET("/widgets/:id", handler)
+	}))
+	widget, ok := findRoute(server.Routes(), "GET", "/api/v1/widgets/:id")
+	require.True(t, ok)
+	assert.Equal(t, "bearer token", widget.Auth)
+
+	err = server.RegisterAPI("", func(group *gin.RouterGroup) {
+		group.POST("/otp/request", handler)
+	})
+	assert.ErrorContains(t, err, "conflicting route registration")
+
+	err = server.RegisterAPI("", func(group *gin.RouterGroup) {
+		group.GET("/widgets/:name", handler)
+	})
+	assert.ErrorContains(t, err, "conflicting route registration")
+}
+
+func TestWriteRoutes(t *testing.T) {
+	var out bytes.Buffer
+	require.NoError(t, bootstrap.WriteRoutes(&out, []bootstrap.RouteInfo{
+		{Method: "GET", Path: "/health", Handler: "bootstrap.(*Server).healthCheck", Middleware: []string{"gin.CustomRecoveryWithWriter"}},
+		{Method: "GET", Path: "/api/v1/usage", Handler: "metering.(*Handler).current", Auth: "api key (X-API-Key)"},
+	}))
+	assert.Equal(t, ""+
+		"METHOD  PATH           HANDLER                          AUTH                 MIDDLEWARE\n"+
+		"GET     /health        bootstrap.(*Server).healthCheck  -                    gin.CustomRecoveryWithWriter\n"+
+		"GET     /api/v1/usage  metering.(*Handler).current      api key (X-API-Key)  \n", out.String())
+}
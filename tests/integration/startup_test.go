@@ -0,0 +1,86 @@
+package integration_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"golang-arch/internal/bootstrap"
+	"golang-arch/internal/shared/config"
+)
+
+var retryFast = config.StartupConfig{
+	WaitTimeout:  time.Second,
+	RetryInitial: time.Millisecond,
+	RetryMax:     4 * time.Millisecond,
+}
+
+func TestWaitForDependency(t *testing.T) {
+	var attempts int
+	err := bootstrap.WaitForDependency(context.Background(), "db", func(context.Context) error {
+		attempts++
+		if attempts < 3 {
+			return assert.AnError
+		}
+		return nil
+	}, retryFast)
+	require.NoError(t, err)
+	assert.Equal(t, 3, attempts)
+}
+
+func TestWaitForDependency_GivesUp(t *testing.T) {
+	cfg := retryFast
+	cfg.WaitTimeout = 20 * time.Millisecond
+	var attempts int
+	err := bootstrap.WaitForDependency(context.Background(), "db", func(context.Context) error {
+		attempts++
+		return assert.AnError
+	}, cfg)
+	assert.ErrorIs(t, err, bootstrap.ErrDependencyUnavailable)
+	assert.ErrorIs(t, err, assert.AnError)
+	assert.Greater(t, attempts, 1)
+
+	// Without a wait timeout the first failure is final
+	attempts = 0
+	err = bootstrap.WaitForDependency(context.Background(), "db", func(context.Context) error {
+		attempts++
+		return assert.AnError
+	}, config.StartupConfig{})
+	assert.ErrorIs(t, err, bootstrap.ErrDependencyUnavailable)
+	assert.Equal(t, 1, attempts)
+}
+
+func TestServer_Readiness(t *testing.T) {
+	tc, err := bootstrap.NewTestContainer()
+	require.NoError(t, err)
+	defer tc.Close()
+	server, err := bootstrap.NewServer(tc.Container)
+	require.NoError(t, err)
+
+	ready := func() (int, map[string]any) {
+		rec := httptest.NewRecorder()
+		server.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/ready", nil))
+		var body map[string]any
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+		return rec.Code, body
+	}
+
+	code, body := ready()
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "ready", body["status"])
+	assert.Equal(t, map[string]any{"database": "ok", "redis": "ok"}, body["checks"])
+
+	tc.Miniredis.Close()
+	code, body = ready()
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Equal(t, "unavailable", body["status"])
+	checks := body["checks"].(map[string]any)
+	assert.Equal(t, "ok", checks["database"])
+	assert.NotEqual(t, "ok", checks["redis"])
+}
@@ -0,0 +1,43 @@
+package integration_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"golang-arch/internal/bootstrap"
+)
+
+func TestNewTestContainer(t *testing.T) {
+	start := time.Date(2024, 6, 1, 9, 0, 0, 0, time.UTC)
+
+	tc, err := bootstrap.NewTestContainer(bootstrap.WithTestStartTime(start))
+	require.NoError(t, err)
+	defer func() {
+		assert.NoError(t, tc.Close())
+		assert.NoError(t, tc.SQLMock.ExpectationsWereMet())
+	}()
+
+	ctx := context.Background()
+
+	tc.SQLMock.ExpectQuery("SELECT 1").WillReturnRows(sqlmock.NewRows([]string{"one"}).AddRow(1))
+	var one int
+	require.NoError(t, tc.DB.QueryRowContext(ctx, "SELECT 1").Scan(&one))
+	assert.Equal(t, 1, one)
+
+	require.NoError(t, tc.Redis.Set(ctx, "greeting", "hello", 0).Err())
+	value, err := tc.Miniredis.Get("greeting")
+	require.NoError(t, err)
+	assert.Equal(t, "hello", value)
+
+	assert.Equal(t, start, tc.Clock.Now())
+	tc.FakeClock.Advance(time.Hour)
+	assert.Equal(t, start.Add(time.Hour), tc.Clock.Now())
+
+	assert.NotNil(t, tc.Loggers.Named("api.http"))
+	assert.Nil(t, tc.Metrics.Handler())
+}
@@ -0,0 +1,219 @@
+package integration_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"golang-arch/internal/bootstrap"
+	"golang-arch/pkg/schedule"
+
+	i18n "golang-arch/internal/shared/domain/internationalization"
+)
+
+func TestWorker_RatesRefreshJob(t *testing.T) {
+	cfg := bootstrap.DefaultTestConfig()
+	cfg.Rates.RefreshSchedule = "@hourly"
+
+	start := time.Date(2024, 6, 1, 9, 30, 0, 0, time.UTC)
+	tc, err := bootstrap.NewTestContainer(bootstrap.WithTestConfig(cfg), bootstrap.WithTestStartTime(start))
+	require.NoError(t, err)
+	defer func() {
+		assert.NoError(t, tc.Close())
+		assert.NoError(t, tc.SQLMock.ExpectationsWereMet())
+	}()
+
+	worker := bootstrap.NewWorker(tc.Container)
+	require.Len(t, worker.Jobs(), 1)
+	assert.Equal(t, "rates_refresh", worker.Jobs()[0].Name)
+
+	go worker.Start()
+	require.Eventually(t, func() bool { return tc.FakeClock.PendingTimers() > 0 }, time.Second, time.Millisecond)
+	assert.False(t, tc.Miniredis.Exists("rates:current"))
+
+	tc.SQLMock.ExpectBegin()
+	for range cfg.Rates.Static {
+		tc.SQLMock.ExpectExec("INSERT INTO exchange_rates").WillReturnResult(sqlmock.NewResult(1, 1))
+	}
+	tc.SQLMock.ExpectCommit()
+
+	// The next activation of @hourly is 10:00
+	tc.FakeClock.Advance(30 * time.Minute)
+	require.Eventually(t, func() bool { return tc.Miniredis.Exists("rates:current") }, time.Second, time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	require.NoError(t, worker.Shutdown(ctx))
+
+	rate, err := tc.Rates.Rate(context.Background(), "USD", "EUR")
+	require.NoError(t, err)
+	assert.Equal(t, start.Add(30*time.Minute).Unix(), rate.Timestamp.Epoch)
+}
+
+func TestWorker_RunJob(t *testing.T) {
+	tc, err := bootstrap.NewTestContainer()
+	require.NoError(t, err)
+	defer tc.Close()
+
+	worker := bootstrap.NewWorker(tc.Container)
+	assert.Empty(t, worker.Jobs(), "no refresh schedule is configured in the default test config")
+
+	var runs int
+	worker.Register(bootstrap.Job{
+		Name:     "flaky",
+		Schedule: schedule.Every(time.Minute),
+		Run: func(context.Context) error {
+			runs++
+			return errors.New("boom")
+		},
+	})
+	require.Len(t, worker.Jobs(), 1)
+
+	worker.RunJob(worker.Jobs()[0])
+	assert.Equal(t, 1, runs)
+}
+
+func TestWorker_EnqueueAtContactWindow(t *testing.T) {
+	// 23:00 in Jakarta: the SMS waits for 09:00 local
+	start := time.Date(2024, 7, 1, 16, 0, 0, 0, time.UTC)
+	tc, err := bootstrap.NewTestContainer(bootstrap.WithTestStartTime(start))
+	require.NoError(t, err)
+	defer tc.Close()
+
+	jakarta, err := i18n.MakeTimezone("Asia/Jakarta")
+	require.NoError(t, err)
+	at, err := jakarta.NextContactTime(tc.Clock.Now(), i18n.DefaultCallingHours)
+	require.NoError(t, err)
+
+	worker := bootstrap.NewWorker(tc.Container)
+	sent := make(chan time.Time, 1)
+	worker.EnqueueAt(bootstrap.Job{
+		Name: "send_sms",
+		Run: func(context.Context) error {
+			sent <- tc.Clock.Now()
+			return nil
+		},
+	}, at)
+	assert.Equal(t, 1, tc.FakeClock.PendingTimers())
+
+	tc.FakeClock.Advance(9*time.Hour + 59*time.Minute)
+	assert.Empty(t, sent)
+
+	tc.FakeClock.Advance(time.Minute)
+	select {
+	case sentAt := <-sent:
+		assert.Equal(t, time.Date(2024, 7, 2, 2, 0, 0, 0, time.UTC), sentAt)
+	case <-time.After(time.Second):
+		t.Fatal("delayed job did not run")
+	}
+
+	// Jobs still waiting at shutdown are dropped
+	worker.EnqueueAt(bootstrap.Job{Name: "never", Run: func(context.Context) error {
+		t.Error("job ran after shutdown")
+		return nil
+	}}, tc.Clock.Now().Add(time.Hour))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	require.NoError(t, worker.Shutdown(ctx))
+}
+
+// reportRecorder is an ErrorReporter keeping what it was given
+type reportRecorder struct {
+	errs []error
+	tags []map[string]string
+}
+
+func (r *reportRecorder) Report(_ context.Context, err error, tags map[string]string) {
+	r.errs = append(r.errs, err)
+	r.tags = append(r.tags, tags)
+}
+
+func TestWorker_RunJobRecoversPanics(t *testing.T) {
+	tc, err := bootstrap.NewTestContainer()
+	require.NoError(t, err)
+	defer tc.Close()
+
+	reporter := &reportRecorder{}
+	worker := bootstrap.NewWorker(tc.Container, bootstrap.WithErrorReporter(reporter))
+	worker.RunJob(bootstrap.Job{Name: "nil_map", Run: func(context.Context) error {
+		var m map[string]int
+		m["boom"]++
+		return nil
+	}})
+
+	require.Len(t, reporter.errs, 1)
+	var panicked *bootstrap.PanicError
+	require.ErrorAs(t, reporter.errs[0], &panicked)
+	assert.Equal(t, "nil_map", panicked.Job)
+	assert.Contains(t, string(panicked.Stack), "worker_test.go")
+	assert.Equal(t, map[string]string{"job": "nil_map", "status": "panic"}, reporter.tags[0])
+
+	worker.RunJob(bootstrap.Job{Name: "ok", Run: func(context.Context) error { return nil }})
+	assert.Len(t, reporter.errs, 1, "successful runs are not reported")
+}
+
+func TestWorker_CircuitBreaker(t *testing.T) {
+	cfg := bootstrap.DefaultTestConfig()
+	cfg.Worker.BreakerThreshold = 3
+	cfg.Worker.BreakerCooldown = 10 * time.Minute
+	tc, err := bootstrap.NewTestContainer(bootstrap.WithTestConfig(cfg))
+	require.NoError(t, err)
+	defer tc.Close()
+
+	worker := bootstrap.NewWorker(tc.Container)
+	var runs int
+	failing := true
+	job := bootstrap.Job{Name: "flaky", Run: func(context.Context) error {
+		runs++
+		if failing {
+			panic("boom")
+		}
+		return nil
+	}}
+
+	for i := 0; i < 3; i++ {
+		_, paused := worker.Paused("flaky")
+		require.False(t, paused)
+		worker.RunJob(job)
+	}
+	until, paused := worker.Paused("flaky")
+	require.True(t, paused)
+	assert.Equal(t, tc.Clock.Now().Add(10*time.Minute), until)
+
+	// Runs while paused are skipped
+	worker.RunJob(job)
+	assert.Equal(t, 3, runs)
+
+	// The trial run after the cooldown fails and pauses the job again
+	tc.FakeClock.Advance(10 * time.Minute)
+	worker.RunJob(job)
+	assert.Equal(t, 4, runs)
+	_, paused = worker.Paused("flaky")
+	assert.True(t, paused)
+
+	// A successful trial closes the breaker
+	tc.FakeClock.Advance(10 * time.Minute)
+	failing = false
+	worker.RunJob(job)
+	_, paused = worker.Paused("flaky")
+	assert.False(t, paused)
+
+	// Other jobs are unaffected, and Resume closes a breaker by hand
+	failing = true
+	for i := 0; i < 3; i++ {
+		worker.RunJob(job)
+	}
+	_, paused = worker.Paused("flaky")
+	require.True(t, paused)
+	_, paused = worker.Paused("other")
+	assert.False(t, paused)
+	worker.Resume("flaky")
+	_, paused = worker.Paused("flaky")
+	assert.False(t, paused)
+}
@@ -0,0 +1,58 @@
+package internationalization_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	i18n "golang-arch/internal/shared/domain/internationalization"
+)
+
+func TestAppendFormat_MatchesFormat(t *testing.T) {
+	money := &i18n.Money{Amount: -1234567, Currency: i18n.Currency{Code: "EUR", Symbol: "€", Name: "Euro", DecimalPlaces: 2}}
+	phone := &i18n.Phone{CountryCode: "44", Number: "2079460958"}
+	ldt, err := i18n.NewLocalizedDateTimeFromPrimitive(1703520000, "Asia/Kathmandu")
+	require.NoError(t, err)
+
+	prefix := []byte("row:")
+
+	assert.Equal(t, "row:"+money.Format(), string(money.AppendFormat(prefix)))
+	assert.Equal(t, "row:"+money.Currency.Format(42), string(money.Currency.AppendFormat(prefix, 42)))
+	assert.Equal(t, "row:"+phone.Format(), string(phone.AppendFormat(prefix)))
+	assert.Equal(t, "row:"+ldt.Format("2006-01-02 15:04 -0700"), string(ldt.AppendFormat(prefix, "2006-01-02 15:04 -0700")))
+	assert.Equal(t, "row:"+ldt.Time.Format("15:04", nil), string(ldt.Time.AppendFormat(prefix, "15:04", nil)))
+	assert.Equal(t, "row:", string(prefix), "AppendFormat must not modify the caller's bytes")
+}
+
+func TestAppendFormat_ReusedBufferDoesNotAllocate(t *testing.T) {
+	money := &i18n.Money{Amount: 1999, Currency: i18n.Currency{Code: "USD", Symbol: "$", Name: "US Dollar", DecimalPlaces: 2}}
+	phone := &i18n.Phone{CountryCode: "1", Number: "2125551234"}
+	ldt, err := i18n.NewLocalizedDateTimeFromPrimitive(1703520000, "America/New_York")
+	require.NoError(t, err)
+
+	buf := make([]byte, 0, 256)
+	allocs := testing.AllocsPerRun(100, func() {
+		buf = money.AppendFormat(buf[:0])
+		buf = phone.AppendFormat(buf)
+		buf = ldt.AppendFormat(buf, "2006-01-02T15:04:05Z07:00")
+	})
+	assert.Zero(t, allocs)
+}
+
+func TestAppendFormat_UnvalidatedTimezones(t *testing.T) {
+	at, err := i18n.NewTime(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC).Unix())
+	require.NoError(t, err)
+
+	// Offsets outside what Timezone.Validate accepts still format, uncached
+	tz := i18n.Timezone{ID: "Far/Away", Offset: 3000}
+	assert.Equal(t, "2024-01-03 14:00 +5000", string(at.AppendFormat(nil, "2006-01-02 15:04 -0700", &tz)))
+
+	// Past the cache size, zones are built on each call
+	for offset := -1439; offset < 1440; offset++ {
+		tz := i18n.Timezone{ID: "Etc/Test", Offset: offset}
+		want := at.ToTime().In(time.FixedZone("Etc/Test", offset*60)).Format(time.RFC3339)
+		require.Equal(t, want, string(at.AppendFormat(nil, time.RFC3339, &tz)))
+	}
+}
@@ -0,0 +1,89 @@
+package internationalization_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	i18n "golang-arch/internal/shared/domain/internationalization"
+)
+
+func TestLocale_Direction(t *testing.T) {
+	tests := []struct {
+		locale i18n.Locale
+		want   i18n.TextDirection
+	}{
+		{"en-US", i18n.TextLTR},
+		{"ar", i18n.TextRTL},
+		{"ar-AE", i18n.TextRTL},
+		{"he-IL", i18n.TextRTL},
+		{"fa-IR", i18n.TextRTL},
+		{"ur-PK", i18n.TextRTL},
+		{"pa", i18n.TextLTR},
+		{"pa-Arab-PK", i18n.TextRTL},
+		{"az-Latn", i18n.TextLTR},
+		{"ug-Cyrl", i18n.TextLTR},
+		{"zh-Hant-TW", i18n.TextLTR},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.locale), func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.locale.Direction())
+		})
+	}
+
+	assert.Equal(t, i18n.TextRTL, i18n.Language("he").Direction())
+	assert.True(t, i18n.Language("yi").Direction().IsRTL())
+	assert.Equal(t, "Hant", i18n.Locale("zh-Hant-TW").Script())
+	assert.Equal(t, "", i18n.Locale("en-US").Script())
+}
+
+func TestDetectDirection(t *testing.T) {
+	tests := []struct {
+		text string
+		want i18n.TextDirection
+		ok   bool
+	}{
+		{"Hello", i18n.TextLTR, true},
+		{"مرحبا", i18n.TextRTL, true},
+		{"שלום", i18n.TextRTL, true},
+		{"123 مرحبا", i18n.TextRTL, true},
+		{"(Hello) مرحبا", i18n.TextLTR, true},
+		{"+62 812-3456-7890", "", false},
+		{"$1,234.50", "", false},
+		{"", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			got, ok := i18n.DetectDirection(tt.text)
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestWrapBidi(t *testing.T) {
+	lrm, rlm := i18n.LeftToRightMark, i18n.RightToLeftMark
+	assert.Equal(t, "\u200E", lrm)
+	assert.Equal(t, "\u200F", rlm)
+
+	tests := []struct {
+		name    string
+		value   string
+		context i18n.TextDirection
+		want    string
+	}{
+		{"phone in RTL", "+62 812-3456-7890", i18n.TextRTL, lrm + "+62 812-3456-7890" + lrm},
+		{"money in RTL", "$1,234.50", i18n.TextRTL, lrm + "$1,234.50" + lrm},
+		{"latin name in RTL", "John", i18n.TextRTL, lrm + "John" + lrm},
+		{"arabic in RTL", "محمد", i18n.TextRTL, "محمد"},
+		{"arabic in LTR", "محمد", i18n.TextLTR, rlm + "محمد" + rlm},
+		{"phone in LTR", "+62 812-3456-7890", i18n.TextLTR, "+62 812-3456-7890"},
+		{"empty context", "John", "", "John"},
+		{"empty value", "", i18n.TextRTL, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, i18n.WrapBidi(tt.value, tt.context))
+		})
+	}
+}
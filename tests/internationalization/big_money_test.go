@@ -0,0 +1,112 @@
+package internationalization_test
+
+import (
+	"encoding/json"
+	"math"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"golang-arch/internal/shared/domain/domainerror"
+
+	i18n "golang-arch/internal/shared/domain/internationalization"
+)
+
+func TestBigMoney_BeyondInt64(t *testing.T) {
+	balance, err := i18n.ParseBigMoney("12.5 ETH")
+	require.NoError(t, err)
+	assert.Equal(t, "12500000000000000000", balance.Amount().String(), "12.5 ETH in wei overflows int64")
+
+	_, err = i18n.ParseMoney("12.5 ETH")
+	assert.ErrorIs(t, err, domainerror.Invalid)
+
+	doubled, err := balance.Add(*balance)
+	require.NoError(t, err)
+	assert.Equal(t, "25.000000000000000000 ETH", doubled.FormatWithCode())
+	assert.Equal(t, "Ξ25.000000000000000000", doubled.Format())
+	assert.Equal(t, "Ξ25.000000000000000000", doubled.Multiply(1).String())
+
+	spent, err := i18n.ParseBigMoney("ETH 30")
+	require.NoError(t, err)
+	left, err := balance.Subtract(*spent)
+	require.NoError(t, err)
+	assert.True(t, left.IsNegative())
+	assert.Equal(t, "-17.500000000000000000", left.DecimalString())
+	assert.Equal(t, "-17,500000000000000000\u00a0Ξ", left.FormatLocalized("de-DE"))
+
+	third, err := spent.Divide(3, i18n.RoundHalfEven)
+	require.NoError(t, err)
+	assert.Equal(t, "10.000000000000000000", third.DecimalString())
+	oneWei, err := i18n.NewBigMoneyFromPrimitive("1", "ETH")
+	require.NoError(t, err)
+	half, err := oneWei.Divide(-2, i18n.RoundHalfEven)
+	require.NoError(t, err)
+	assert.True(t, half.IsZero(), "-0.5 wei rounds half-even to 0")
+
+	cmp, err := balance.Cmp(*spent)
+	require.NoError(t, err)
+	assert.Equal(t, -1, cmp)
+
+	usd, err := i18n.ParseBigMoney("1 USD")
+	require.NoError(t, err)
+	_, err = balance.Add(*usd)
+	assert.ErrorIs(t, err, domainerror.Invalid)
+}
+
+func TestBigMoney_MoneyRoundTrip(t *testing.T) {
+	price, err := i18n.MakeMoney(10050, "USD")
+	require.NoError(t, err)
+
+	asBig := price.ToBig()
+	back, err := asBig.ToMoney()
+	require.NoError(t, err)
+	assert.True(t, price.Equal(back))
+
+	huge := price.ToBig().Multiply(math.MaxInt64)
+	_, err = huge.ToMoney()
+	assert.ErrorIs(t, err, domainerror.Invalid)
+
+	var zero i18n.BigMoney
+	assert.True(t, zero.IsZero(), "the zero value is a zero amount")
+}
+
+func TestBigMoney_AmountIsImmutable(t *testing.T) {
+	amount := big.NewInt(100)
+	money, err := i18n.NewBigMoney(amount, currency(t, "USD"))
+	require.NoError(t, err)
+
+	amount.SetInt64(1)
+	money.Amount().SetInt64(2)
+	assert.Equal(t, "100", money.Amount().String())
+}
+
+func TestBigMoney_JSONCompatibleWithMoney(t *testing.T) {
+	price, err := i18n.MakeMoney(10050, "USD")
+	require.NoError(t, err)
+	moneyJSON, err := json.Marshal(price)
+	require.NoError(t, err)
+	bigJSON, err := json.Marshal(price.ToBig())
+	require.NoError(t, err)
+	assert.JSONEq(t, string(moneyJSON), string(bigJSON))
+
+	var decoded i18n.BigMoney
+	require.NoError(t, json.Unmarshal(moneyJSON, &decoded))
+	assert.Equal(t, "100.50", decoded.DecimalString())
+
+	balance, err := i18n.ParseBigMoney("12.5 ETH")
+	require.NoError(t, err)
+	data, err := json.Marshal(balance)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"amount":12500000000000000000,"decimal":12.5`)
+	var roundTrip i18n.BigMoney
+	require.NoError(t, json.Unmarshal(data, &roundTrip))
+	assert.True(t, balance.Equal(&roundTrip))
+
+	eth := `{"code": "ETH", "symbol": "Ξ", "name": "Ethereum", "decimal_places": 18}`
+	require.NoError(t, json.Unmarshal([]byte(`{"amount": "12500000000000000000", "currency": `+eth+`}`), &roundTrip))
+	assert.True(t, balance.Equal(&roundTrip), "string amounts are accepted")
+	assert.ErrorIs(t, json.Unmarshal([]byte(`{"amount": 12.5, "currency": `+eth+`}`), &roundTrip), domainerror.Invalid)
+	assert.ErrorIs(t, json.Unmarshal([]byte(`{"currency": `+eth+`}`), &roundTrip), domainerror.Invalid)
+}
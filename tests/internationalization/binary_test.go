@@ -0,0 +1,169 @@
+package internationalization_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vmihailenco/msgpack/v5"
+
+	"golang-arch/internal/shared/codec"
+	i18n "golang-arch/internal/shared/domain/internationalization"
+)
+
+type binaryFixture struct {
+	name  string
+	value any
+	empty func() any
+}
+
+func binaryFixtures(t *testing.T) []binaryFixture {
+	money, err := i18n.MakeMoney(10050, "USD")
+	require.NoError(t, err)
+	tm, err := i18n.MakeTime(1703520000)
+	require.NoError(t, err)
+	tz, err := i18n.MakeTimezone("America/New_York")
+	require.NoError(t, err)
+	phone, err := i18n.MakePhone("1", "5551234567")
+	require.NoError(t, err)
+	ldt, err := i18n.MakeLocalizedDateTime(1703520000, "Asia/Tokyo")
+	require.NoError(t, err)
+	lp, err := i18n.NewLocalizedPhone(phone, "United States", "New York", tz)
+	require.NoError(t, err)
+	currency, err := i18n.NewCurrencyFromCode("JPY")
+	require.NoError(t, err)
+
+	return []binaryFixture{
+		{"Currency", *currency, func() any { return new(i18n.Currency) }},
+		{"Time", tm, func() any { return new(i18n.Time) }},
+		{"Timezone", tz, func() any { return new(i18n.Timezone) }},
+		{"Money", money, func() any { return new(i18n.Money) }},
+		{"Phone", phone, func() any { return new(i18n.Phone) }},
+		{"LocalizedDateTime", ldt, func() any { return new(i18n.LocalizedDateTime) }},
+		{"LocalizedPhone", *lp, func() any { return new(i18n.LocalizedPhone) }},
+	}
+}
+
+func TestBinaryCodecs_RoundTrip(t *testing.T) {
+	for _, c := range []codec.Codec{codec.MsgPack, codec.CBOR} {
+		for _, fixture := range binaryFixtures(t) {
+			t.Run(c.Name()+"/"+fixture.name, func(t *testing.T) {
+				data, err := c.Marshal(fixture.value)
+				require.NoError(t, err)
+
+				decoded := fixture.empty()
+				require.NoError(t, c.Unmarshal(data, decoded))
+				assert.Equal(t, fixture.value, deref(decoded))
+
+				jsonData, err := json.Marshal(fixture.value)
+				require.NoError(t, err)
+				assert.Less(t, len(data), len(jsonData), "binary form should be smaller than JSON")
+			})
+		}
+	}
+}
+
+func TestBinaryCodecs_CompactForms(t *testing.T) {
+	money, err := i18n.MakeMoney(10050, "USD")
+	require.NoError(t, err)
+
+	data, err := msgpack.Marshal(money)
+	require.NoError(t, err)
+	var msgpackRaw []any
+	require.NoError(t, msgpack.Unmarshal(data, &msgpackRaw))
+	assert.EqualValues(t, []any{int64(10050), "USD"}, normalizeInts(msgpackRaw))
+
+	data, err = cbor.Marshal(money)
+	require.NoError(t, err)
+	var cborRaw []any
+	require.NoError(t, cbor.Unmarshal(data, &cborRaw))
+	assert.EqualValues(t, []any{int64(10050), "USD"}, normalizeInts(cborRaw))
+
+	data, err = cbor.Marshal(i18n.Time{Epoch: 1703520000})
+	require.NoError(t, err)
+	assert.Len(t, data, 5, "epoch should use a 4-byte CBOR integer")
+}
+
+func TestBinaryCodecs_NestedInStructs(t *testing.T) {
+	type invoice struct {
+		Total  i18n.Money             `json:"total"`
+		DueAt  i18n.LocalizedDateTime `json:"due_at"`
+		Amount []i18n.Money           `json:"lines"`
+	}
+
+	total, err := i18n.MakeMoney(-2500, "EUR")
+	require.NoError(t, err)
+	due, err := i18n.MakeLocalizedDateTime(1703520000, "Europe/Paris")
+	require.NoError(t, err)
+	in := invoice{Total: total, DueAt: due, Amount: []i18n.Money{total, total}}
+
+	for _, c := range []codec.Codec{codec.MsgPack, codec.CBOR} {
+		data, err := c.Marshal(in)
+		require.NoError(t, err)
+
+		var out invoice
+		require.NoError(t, c.Unmarshal(data, &out), c.Name())
+		assert.Equal(t, in, out, c.Name())
+	}
+}
+
+func TestBinaryCodecs_RejectInvalid(t *testing.T) {
+	for _, c := range []codec.Codec{codec.MsgPack, codec.CBOR} {
+		data, err := c.Marshal([]any{100, "XXX"})
+		require.NoError(t, err)
+		assert.Error(t, c.Unmarshal(data, new(i18n.Money)), c.Name())
+
+		data, err = c.Marshal("Mars/Olympus")
+		require.NoError(t, err)
+		assert.Error(t, c.Unmarshal(data, new(i18n.Timezone)), c.Name())
+
+		data, err = c.Marshal(-5)
+		require.NoError(t, err)
+		assert.Error(t, c.Unmarshal(data, new(i18n.Time)), c.Name())
+	}
+}
+
+func deref(v any) any {
+	switch p := v.(type) {
+	case *i18n.Currency:
+		return *p
+	case *i18n.Time:
+		return *p
+	case *i18n.Timezone:
+		return *p
+	case *i18n.Money:
+		return *p
+	case *i18n.Phone:
+		return *p
+	case *i18n.LocalizedDateTime:
+		return *p
+	case *i18n.LocalizedPhone:
+		return *p
+	}
+	return v
+}
+
+func normalizeInts(values []any) []any {
+	out := make([]any, len(values))
+	for i, v := range values {
+		switch n := v.(type) {
+		case int8:
+			out[i] = int64(n)
+		case int16:
+			out[i] = int64(n)
+		case int32:
+			out[i] = int64(n)
+		case uint16:
+			out[i] = int64(n)
+		case uint32:
+			out[i] = int64(n)
+		case uint64:
+			out[i] = int64(n)
+		default:
+			out[i] = v
+		}
+	}
+	return out
+}
@@ -0,0 +1,170 @@
+package internationalization_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	i18n "golang-arch/internal/shared/domain/internationalization"
+)
+
+func berlinCalendar(t *testing.T) i18n.BusinessCalendar {
+	t.Helper()
+	calendar, err := i18n.NewBusinessCalendar(timezone(t, "Europe/Berlin"), i18n.Country("DE").Week(), "09:00", "17:00")
+	require.NoError(t, err)
+	calendar, err = calendar.WithHoliday("2025-12-25", "Christmas Day")
+	require.NoError(t, err)
+	calendar, err = calendar.WithHoliday("2025-12-26", "Boxing Day")
+	require.NoError(t, err)
+	return *calendar
+}
+
+func berlinTime(t *testing.T, value string) time.Time {
+	t.Helper()
+	loc, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+	parsed, err := time.ParseInLocation("2006-01-02 15:04", value, loc)
+	require.NoError(t, err)
+	return parsed
+}
+
+func TestNewBusinessCalendar_Invalid(t *testing.T) {
+	utc := timezone(t, "UTC")
+	_, err := i18n.NewBusinessCalendar(utc, i18n.DefaultWeek, "17:00", "09:00")
+	assert.Error(t, err)
+	_, err = i18n.NewBusinessCalendar(utc, i18n.DefaultWeek, "9am", "17:00")
+	assert.Error(t, err)
+	_, err = i18n.NewBusinessCalendar(utc, i18n.WeekDefinition{FirstDay: 9}, "09:00", "17:00")
+	assert.Error(t, err)
+
+	calendar, err := i18n.NewBusinessCalendar(utc, i18n.DefaultWeek, "09:00", "17:00")
+	require.NoError(t, err)
+	_, err = calendar.WithHoliday("25.12.2025", "Christmas Day")
+	assert.Error(t, err)
+}
+
+func TestBusinessCalendar_AddWorkingTime(t *testing.T) {
+	calendar := berlinCalendar(t)
+
+	tests := []struct {
+		name   string
+		start  string
+		within time.Duration
+		want   string
+	}{
+		{"same day", "2025-12-01 10:00", 2 * time.Hour, "2025-12-01 12:00"},
+		{"before opening", "2025-12-01 07:30", time.Hour, "2025-12-01 10:00"},
+		{"ends at closing", "2025-12-01 09:00", 8 * time.Hour, "2025-12-01 17:00"},
+		{"spills to next day", "2025-12-01 16:00", 2 * time.Hour, "2025-12-02 10:00"},
+		{"after closing", "2025-12-01 18:00", 30 * time.Minute, "2025-12-02 09:30"},
+		{"over the weekend", "2025-12-05 16:00", 2 * time.Hour, "2025-12-08 10:00"},
+		{"starts on weekend", "2025-12-06 11:00", 8 * time.Hour, "2025-12-08 17:00"},
+		{"over holidays", "2025-12-24 16:00", 8 * time.Hour, "2025-12-29 16:00"},
+		{"zero moves to opening", "2025-12-25 12:00", 0, "2025-12-29 09:00"},
+		{"multi day", "2025-12-01 09:00", 40 * time.Hour, "2025-12-05 17:00"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			due, err := calendar.AddWorkingTime(berlinTime(t, tt.start), tt.within)
+			require.NoError(t, err)
+			assert.True(t, berlinTime(t, tt.want).Equal(due), "got %s", due)
+		})
+	}
+
+	_, err := calendar.AddWorkingTime(berlinTime(t, "2025-12-01 10:00"), -time.Hour)
+	assert.Error(t, err)
+}
+
+func TestBusinessCalendar_AddWorkingTime_DST(t *testing.T) {
+	calendar, err := i18n.NewBusinessCalendar(timezone(t, "Europe/Berlin"), i18n.DefaultWeek, "00:00", "06:00")
+	require.NoError(t, err)
+
+	// 2025-03-30 is a Sunday; use a week with Friday–Saturday weekend so it is a working day
+	calendar.Week = i18n.Country("AE").Week()
+	due, err := calendar.AddWorkingTime(berlinTime(t, "2025-03-30 00:00"), 5*time.Hour)
+	require.NoError(t, err)
+	assert.True(t, berlinTime(t, "2025-03-30 06:00").Equal(due), "the DST night has five working hours, got %s", due)
+}
+
+func TestBusinessCalendar_WorkingTimeBetween(t *testing.T) {
+	calendar := berlinCalendar(t)
+
+	tests := []struct {
+		name string
+		from string
+		to   string
+		want time.Duration
+	}{
+		{"same day", "2025-12-01 10:00", "2025-12-01 12:30", 150 * time.Minute},
+		{"outside hours", "2025-12-01 18:00", "2025-12-02 08:00", 0},
+		{"across weekend", "2025-12-05 16:00", "2025-12-08 10:00", 2 * time.Hour},
+		{"across holidays", "2025-12-24 09:00", "2025-12-29 09:00", 8 * time.Hour},
+		{"reversed", "2025-12-02 10:00", "2025-12-01 10:00", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := calendar.WorkingTimeBetween(berlinTime(t, tt.from), berlinTime(t, tt.to))
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestBusinessCalendar_DueDate(t *testing.T) {
+	calendar := berlinCalendar(t)
+
+	// Friday 15:00 UTC is 16:00 in Berlin; 8 business hours end Monday 16:00 Berlin time
+	due, err := calendar.DueDate(time.Date(2025, 12, 5, 15, 0, 0, 0, time.UTC), 8*time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, "Europe/Berlin", due.Timezone.ID)
+	assert.True(t, berlinTime(t, "2025-12-08 16:00").Equal(due.Time.ToTime()), "got %s", due.Time.ToTime())
+
+	open, err := calendar.IsWorkingTime(berlinTime(t, "2025-12-08 16:59"))
+	require.NoError(t, err)
+	assert.True(t, open)
+	open, err = calendar.IsWorkingTime(berlinTime(t, "2025-12-25 10:00"))
+	require.NoError(t, err)
+	assert.False(t, open)
+
+	name, ok, err := calendar.Holiday(time.Date(2025, 12, 24, 23, 30, 0, 0, time.UTC))
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, "Christmas Day", name)
+}
+
+func TestBusinessCalendar_NoWorkingDays(t *testing.T) {
+	calendar, err := i18n.NewBusinessCalendar(timezone(t, "UTC"), i18n.DefaultWeek, "09:00", "17:00")
+	require.NoError(t, err)
+	calendar.Week = i18n.WeekDefinition{FirstDay: time.Monday, WeekendStart: time.Monday, WeekendEnd: time.Sunday}
+
+	_, err = calendar.AddWorkingTime(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), time.Hour)
+	assert.Error(t, err)
+}
+
+func TestBusinessCalendar_JSON(t *testing.T) {
+	calendar := berlinCalendar(t)
+
+	data, err := json.Marshal(calendar)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"timezone": "Europe/Berlin",
+		"week": {"first_day": 1, "weekend_start": 6, "weekend_end": 0},
+		"start": "09:00",
+		"end": "17:00",
+		"holidays": {"2025-12-25": "Christmas Day", "2025-12-26": "Boxing Day"}
+	}`, string(data))
+
+	var decoded i18n.BusinessCalendar
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, calendar.Holidays, decoded.Holidays)
+	assert.Equal(t, calendar.Start, decoded.Start)
+
+	require.NoError(t, json.Unmarshal([]byte(`{"timezone":"UTC","start":"08:00","end":"16:00"}`), &decoded))
+	assert.Equal(t, i18n.DefaultWeek, decoded.Week, "week defaults to ISO")
+
+	assert.Error(t, json.Unmarshal([]byte(`{"timezone":"UTC","start":"16:00","end":"08:00"}`), &decoded))
+	assert.Error(t, json.Unmarshal([]byte(`{"timezone":"UTC","start":"08:00","end":"16:00","holidays":{"x":"y"}}`), &decoded))
+}
@@ -0,0 +1,85 @@
+package internationalization_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"golang-arch/internal/shared/testutil"
+	"golang-arch/pkg/clock"
+
+	i18n "golang-arch/internal/shared/domain/internationalization"
+)
+
+func TestNewTimezoneFromID_FrozenClock(t *testing.T) {
+	tests := []struct {
+		name           string
+		id             string
+		at             string
+		expectedOffset int
+	}{
+		{"New York winter", "America/New_York", "2024-01-15T12:00:00Z", -300},
+		{"New York summer", "America/New_York", "2024-07-15T12:00:00Z", -240},
+		{"Sydney summer", "Australia/Sydney", "2024-01-15T12:00:00Z", 660},
+		{"Sydney winter", "Australia/Sydney", "2024-07-15T12:00:00Z", 600},
+		{"UTC", "UTC", "2024-07-15T12:00:00Z", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testutil.FreezeTime(t, testutil.MustParseTime(t, tt.at))
+
+			tz, err := i18n.NewTimezoneFromID(tt.id)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedOffset, tz.Offset)
+		})
+	}
+}
+
+func TestNewTimezoneFromID_TimeTravelAcrossDST(t *testing.T) {
+	// One hour before the 2024 US spring-forward transition
+	fake := testutil.FreezeTime(t, testutil.MustParseTime(t, "2024-03-10T06:00:00Z"))
+
+	before, err := i18n.NewTimezoneFromID("America/New_York")
+	require.NoError(t, err)
+	assert.Equal(t, -300, before.Offset)
+
+	fake.Advance(2 * time.Hour)
+
+	after, err := i18n.NewTimezoneFromID("America/New_York")
+	require.NoError(t, err)
+	assert.Equal(t, -240, after.Offset)
+}
+
+func TestFakeClock(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	fake := clock.NewFake(start)
+
+	assert.Equal(t, start, fake.Now())
+
+	fired := fake.After(10 * time.Minute)
+	assert.Equal(t, 1, fake.PendingTimers())
+
+	fake.Advance(5 * time.Minute)
+	select {
+	case <-fired:
+		t.Fatal("timer fired before its deadline")
+	default:
+	}
+	assert.Equal(t, 5*time.Minute, fake.Since(start))
+
+	fake.Advance(5 * time.Minute)
+	select {
+	case at := <-fired:
+		assert.Equal(t, start.Add(10*time.Minute), at)
+	default:
+		t.Fatal("timer did not fire at its deadline")
+	}
+	assert.Equal(t, 0, fake.PendingTimers())
+
+	later := start.Add(24 * time.Hour)
+	fake.Set(later)
+	assert.Equal(t, later, fake.Now())
+}
@@ -0,0 +1,83 @@
+package internationalization_test
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"golang-arch/internal/shared/domain/domainerror"
+
+	i18n "golang-arch/internal/shared/domain/internationalization"
+)
+
+func TestConstraints_EnforcedByMoneyConstructors(t *testing.T) {
+	allowed, err := i18n.AllowedCurrencies("usd", "EUR")
+	require.NoError(t, err)
+	restore := i18n.SetConstraints(i18n.Constraints{Money: []i18n.Constraint[i18n.Money]{i18n.NonNegativeAmount(), allowed}})
+	defer restore()
+
+	_, err = i18n.MakeMoney(100, "USD")
+	assert.NoError(t, err)
+	_, err = i18n.MakeMoney(-100, "USD")
+	assert.ErrorIs(t, err, domainerror.Invalid)
+	assert.ErrorContains(t, err, "violates non_negative_amount")
+
+	_, err = i18n.NewMoneyFromPrimitive(100, "GBP")
+	assert.ErrorIs(t, err, domainerror.Invalid)
+	assert.ErrorContains(t, err, "currency GBP is not one of USD, EUR")
+	_, err = i18n.NewMoneyFromDecimal(-1.5, currency(t, "EUR"))
+	assert.ErrorIs(t, err, domainerror.Invalid)
+	_, err = i18n.ParseMoney("-3 USD")
+	assert.ErrorIs(t, err, domainerror.Invalid)
+
+	var decoded i18n.Money
+	err = json.Unmarshal([]byte(`{"amount": 100, "currency": {"code": "JPY", "decimal_places": 0}}`), &decoded)
+	assert.ErrorIs(t, err, domainerror.Invalid, "decoding goes through the constructors")
+
+	small, err := i18n.MakeMoney(100, "USD")
+	require.NoError(t, err)
+	large, err := i18n.MakeMoney(500, "USD")
+	require.NoError(t, err)
+	_, err = small.Subtract(&large)
+	assert.ErrorIs(t, err, domainerror.Invalid, "arithmetic results are constrained too")
+
+	_, err = i18n.NewCurrencyFromCode("GBP")
+	assert.NoError(t, err, "currency lookups are not constrained")
+
+	restore()
+	_, err = i18n.MakeMoney(-100, "GBP")
+	assert.NoError(t, err, "restore lifts the constraints")
+}
+
+func TestConstraints_PhoneAndTimezone(t *testing.T) {
+	defer i18n.SetConstraints(i18n.Constraints{
+		Phone: []i18n.Constraint[i18n.Phone]{i18n.AllowedCallingCodes("+44", "1")},
+		Timezone: []i18n.Constraint[i18n.Timezone]{{Name: "europe_only", Check: func(tz i18n.Timezone) error {
+			if !strings.HasPrefix(tz.ID, "Europe/") {
+				return errors.New("only European timezones")
+			}
+			return nil
+		}}},
+	})()
+
+	_, err := i18n.NewPhoneFromString("+44 20 7946 0958")
+	assert.NoError(t, err)
+	_, err = i18n.NewPhoneFromString("+33 1 42 68 53 00")
+	assert.ErrorIs(t, err, domainerror.Invalid)
+	assert.ErrorContains(t, err, "calling code +33 is not one of +44, +1")
+
+	_, err = i18n.MakeTimezone("Europe/Paris")
+	assert.NoError(t, err)
+	_, err = i18n.MakeTimezone("Asia/Tokyo")
+	assert.ErrorIs(t, err, domainerror.Invalid, "custom errors are tagged Invalid")
+	assert.ErrorContains(t, err, "violates europe_only: only European timezones")
+}
+
+func TestAllowedCurrencies_RejectsUnknownCodes(t *testing.T) {
+	_, err := i18n.AllowedCurrencies("USD", "XYZ")
+	assert.ErrorIs(t, err, domainerror.Invalid)
+}
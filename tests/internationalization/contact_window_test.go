@@ -0,0 +1,151 @@
+package internationalization_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"golang-arch/internal/shared/domain/domainerror"
+
+	i18n "golang-arch/internal/shared/domain/internationalization"
+)
+
+func timezone(t *testing.T, id string) i18n.Timezone {
+	t.Helper()
+	tz, err := i18n.MakeTimezone(id)
+	require.NoError(t, err)
+	return tz
+}
+
+func TestNewContactWindow(t *testing.T) {
+	window, err := i18n.NewContactWindow("09:00", "21:30", time.Monday, time.Friday)
+	require.NoError(t, err)
+	assert.Equal(t, 9*60, window.Start)
+	assert.Equal(t, 21*60+30, window.End)
+	assert.Equal(t, "09:00–21:30", window.String())
+
+	for _, tc := range [][2]string{{"9am", "21:00"}, {"09:00", "24:00"}, {"09:00", "09:00"}} {
+		_, err := i18n.NewContactWindow(tc[0], tc[1])
+		assert.ErrorIs(t, err, domainerror.Invalid, "%s–%s", tc[0], tc[1])
+	}
+
+	_, err = i18n.NewContactWindow("09:00", "17:00", time.Weekday(7))
+	assert.ErrorIs(t, err, domainerror.Invalid)
+}
+
+func TestTimezone_IsWithinCallingHours(t *testing.T) {
+	newYork := timezone(t, "America/New_York")
+	jakarta := timezone(t, "Asia/Jakarta")
+
+	tests := []struct {
+		name string
+		now  time.Time
+		tz   i18n.Timezone
+		want bool
+	}{
+		{"new york morning", time.Date(2024, 7, 1, 13, 0, 0, 0, time.UTC), newYork, true}, // 09:00 EDT
+		{"new york before opening", time.Date(2024, 7, 1, 12, 59, 0, 0, time.UTC), newYork, false},
+		{"new york closing excluded", time.Date(2024, 7, 2, 1, 0, 0, 0, time.UTC), newYork, false}, // 21:00 EDT
+		{"new york winter", time.Date(2024, 1, 15, 13, 30, 0, 0, time.UTC), newYork, false},        // 08:30 EST
+		{"jakarta evening", time.Date(2024, 7, 1, 13, 0, 0, 0, time.UTC), jakarta, true},           // 20:00 WIB
+		{"jakarta night", time.Date(2024, 7, 1, 15, 0, 0, 0, time.UTC), jakarta, false},            // 22:00 WIB
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.tz.IsWithinCallingHours(tt.now, i18n.DefaultCallingHours)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestContactWindow_Overnight(t *testing.T) {
+	// Night shift, opening Friday evenings only
+	window, err := i18n.NewContactWindow("22:00", "06:00", time.Friday)
+	require.NoError(t, err)
+	utc := timezone(t, "UTC")
+
+	friday := time.Date(2024, 7, 5, 0, 0, 0, 0, time.UTC)
+	for _, tc := range []struct {
+		at   time.Duration
+		want bool
+	}{
+		{23 * time.Hour, true},                // Friday 23:00
+		{21 * time.Hour, false},               // Friday 21:00
+		{29 * time.Hour, true},                // Saturday 05:00, still Friday's window
+		{30 * time.Hour, false},               // Saturday 06:00
+		{-19 * time.Hour, false},              // Thursday 05:00, Wednesday's window is closed
+		{7*24*time.Hour + 23*time.Hour, true}, // next Friday 23:00
+		{7*24*time.Hour - 1*time.Hour, false}, // Thursday 23:00
+	} {
+		got, err := window.Contains(friday.Add(tc.at), utc)
+		require.NoError(t, err)
+		assert.Equal(t, tc.want, got, "%s", friday.Add(tc.at))
+	}
+}
+
+func TestContactWindow_NextOpen(t *testing.T) {
+	newYork := timezone(t, "America/New_York")
+	weekdays, err := i18n.NewContactWindow("09:00", "17:00",
+		time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday)
+	require.NoError(t, err)
+
+	// Inside the window: deliver now
+	now := time.Date(2024, 7, 1, 14, 0, 0, 0, time.UTC) // Monday 10:00 EDT
+	next, err := weekdays.NextOpen(now, newYork)
+	require.NoError(t, err)
+	assert.Equal(t, now, next)
+
+	// Monday 20:00 EDT: wait until Tuesday 09:00 EDT
+	next, err = newYork.NextContactTime(time.Date(2024, 7, 2, 0, 0, 0, 0, time.UTC), *weekdays)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2024, 7, 2, 13, 0, 0, 0, time.UTC), next)
+	assert.Equal(t, time.UTC, next.Location(), "the result keeps the caller's location")
+
+	// Friday evening: wait for Monday
+	next, err = weekdays.NextOpen(time.Date(2024, 7, 5, 22, 0, 0, 0, time.UTC), newYork)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2024, 7, 8, 13, 0, 0, 0, time.UTC), next)
+
+	// Across the spring-forward change the window follows local time: 08:00 EST to 09:00 EDT
+	next, err = i18n.DefaultCallingHours.NextOpen(time.Date(2024, 3, 9, 13, 0, 0, 0, time.UTC), newYork)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2024, 3, 9, 14, 0, 0, 0, time.UTC), next)
+	next, err = i18n.DefaultCallingHours.NextOpen(time.Date(2024, 3, 10, 3, 0, 0, 0, time.UTC), newYork)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2024, 3, 10, 13, 0, 0, 0, time.UTC), next)
+}
+
+func TestLocalizedPhone_CallingHours(t *testing.T) {
+	phone, err := i18n.NewPhone("62", "81234567890")
+	require.NoError(t, err)
+	lp, err := i18n.NewLocalizedPhone(*phone, "Indonesia", "Jakarta", timezone(t, "Asia/Jakarta"))
+	require.NoError(t, err)
+
+	night := time.Date(2024, 7, 1, 16, 0, 0, 0, time.UTC) // 23:00 WIB
+	ok, err := lp.IsWithinCallingHours(night, i18n.DefaultCallingHours)
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	next, err := lp.NextContactTime(night, i18n.DefaultCallingHours)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2024, 7, 2, 2, 0, 0, 0, time.UTC), next) // 09:00 WIB
+}
+
+func TestContactWindow_JSON(t *testing.T) {
+	window, err := i18n.NewContactWindow("08:30", "18:00", time.Saturday)
+	require.NoError(t, err)
+
+	data, err := json.Marshal(window)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"start":"08:30","end":"18:00","weekdays":[6]}`, string(data))
+
+	var decoded i18n.ContactWindow
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, *window, decoded)
+
+	assert.Error(t, json.Unmarshal([]byte(`{"start":"25:00","end":"18:00"}`), &decoded))
+}
@@ -0,0 +1,105 @@
+package internationalization_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"golang-arch/internal/shared/domain/validation"
+
+	i18n "golang-arch/internal/shared/domain/internationalization"
+)
+
+func TestCountry_DefaultCurrency(t *testing.T) {
+	tests := []struct {
+		country i18n.Country
+		want    string
+		ok      bool
+	}{
+		{"DE", "EUR", true},
+		{"US", "USD", true},
+		{"EC", "USD", true},
+		{"ID", "IDR", true},
+		{"JP", "JPY", true},
+		{"LI", "CHF", true},
+		{"AR", "", false},
+		{"ZZ", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.country), func(t *testing.T) {
+			currency, ok := tt.country.DefaultCurrency()
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.want, currency.Code)
+		})
+	}
+}
+
+func TestCurrencyForLocale(t *testing.T) {
+	currency, ok := i18n.CurrencyForLocale("pt-BR")
+	assert.True(t, ok)
+	assert.Equal(t, "BRL", currency.Code)
+	assert.Equal(t, 2, currency.DecimalPlaces)
+
+	_, ok = i18n.CurrencyForLocale("pt")
+	assert.False(t, ok)
+	_, ok = i18n.CurrencyForLocale("es-419")
+	assert.False(t, ok)
+}
+
+func TestCurrency_Countries(t *testing.T) {
+	chf, err := i18n.NewCurrencyFromCode("CHF")
+	require.NoError(t, err)
+	assert.Equal(t, []i18n.Country{"CH", "LI"}, chf.Countries())
+
+	btc, err := i18n.NewCurrencyFromCode("BTC")
+	require.NoError(t, err)
+	assert.Empty(t, btc.Countries())
+}
+
+func TestCurrencyRules(t *testing.T) {
+	rules, err := i18n.NewCurrencyRules(map[string]string{"ch": "eur", "LI": "EUR"}, "eur")
+	require.NoError(t, err)
+	require.NoError(t, rules.Validate())
+	assert.Equal(t, map[i18n.Country]string{"CH": "EUR", "LI": "EUR"}, rules.Overrides)
+	assert.Equal(t, "EUR", rules.Fallback)
+
+	assert.Equal(t, "EUR", rules.ForCountry("CH").Code, "override")
+	assert.Equal(t, "GBP", rules.ForCountry("GB").Code, "country default")
+	assert.Equal(t, "EUR", rules.ForCountry("AR").Code, "fallback")
+	assert.Equal(t, "EUR", rules.ForLocale("de-CH").Code)
+	assert.Equal(t, "JPY", rules.ForLocale("ja-JP").Code)
+	assert.Equal(t, "EUR", rules.ForLocale("de").Code, "no region uses the fallback")
+}
+
+func TestCurrencyRules_Invalid(t *testing.T) {
+	_, err := i18n.NewCurrencyRules(map[string]string{"CHE": "EUR", "GB": "XXX"}, "???")
+	require.Error(t, err)
+	assert.ElementsMatch(t, []string{"overrides.CHE", "overrides.GB", "fallback"}, keys(validation.FromError(err).ByField()))
+
+	invalid := i18n.CurrencyRules{Overrides: map[i18n.Country]string{"gb": "GBP", "FR": "eur"}, Fallback: "USD"}
+	assert.Error(t, invalid.Validate())
+
+	assert.Equal(t, "USD", i18n.CurrencyRules{}.ForCountry("AR").Code, "zero rules fall back to USD")
+}
+
+func TestCurrencyRules_JSON(t *testing.T) {
+	var rules i18n.CurrencyRules
+	require.NoError(t, json.Unmarshal([]byte(`{"overrides":{"ch":"EUR"},"fallback":"usd"}`), &rules))
+	assert.Equal(t, "EUR", rules.ForCountry("CH").Code)
+
+	data, err := json.Marshal(rules)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"overrides":{"CH":"EUR"},"fallback":"USD"}`, string(data))
+
+	assert.Error(t, json.Unmarshal([]byte(`{"fallback":"XXX"}`), &rules))
+}
+
+func keys[V any](m map[string]V) []string {
+	result := make([]string, 0, len(m))
+	for key := range m {
+		result = append(result, key)
+	}
+	return result
+}
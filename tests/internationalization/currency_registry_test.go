@@ -0,0 +1,139 @@
+package internationalization_test
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"golang-arch/internal/shared/domain/domainerror"
+	i18n "golang-arch/internal/shared/domain/internationalization"
+)
+
+var loyaltyPoints = i18n.Currency{Code: "XLP", Symbol: "pts", Name: "Loyalty Points", DecimalPlaces: 0}
+
+// registerCurrency adds c to the package registry for the duration of the test
+func registerCurrency(t *testing.T, c i18n.Currency) {
+	t.Helper()
+	require.NoError(t, i18n.RegisterCurrency(c))
+	t.Cleanup(func() { _ = i18n.UnregisterCurrency(c.Code) })
+}
+
+func TestCurrencyRegistry(t *testing.T) {
+	registry, err := i18n.NewCurrencyRegistry(loyaltyPoints)
+	require.NoError(t, err)
+
+	currency, ok := registry.Lookup("xlp")
+	require.True(t, ok)
+	assert.Equal(t, loyaltyPoints, *currency)
+	_, ok = registry.Lookup("USD")
+	assert.False(t, ok, "a new registry has no built-in currencies")
+
+	err = registry.Register(i18n.Currency{Code: "xlp", Symbol: "p", Name: "Points", DecimalPlaces: 0})
+	assert.True(t, errors.Is(err, domainerror.Conflict))
+
+	err = registry.Register(i18n.Currency{Code: "GOLD", Symbol: "g", Name: "Gold", DecimalPlaces: 0})
+	assert.True(t, errors.Is(err, domainerror.Invalid))
+
+	require.NoError(t, registry.Register(i18n.Currency{Code: "XGC", Symbol: "◎", Name: "Gems", DecimalPlaces: 2}))
+	assert.Equal(t, []string{"XLP", "XGC"}, registry.Codes())
+
+	require.NoError(t, registry.Unregister("XLP"))
+	assert.Equal(t, []string{"XGC"}, registry.Codes())
+	_, ok = registry.Lookup("XLP")
+	assert.False(t, ok)
+	assert.True(t, errors.Is(registry.Unregister("XLP"), domainerror.NotFound))
+}
+
+func TestCurrencyRegistry_RegisterCopies(t *testing.T) {
+	registry, err := i18n.NewCurrencyRegistry()
+	require.NoError(t, err)
+	c := loyaltyPoints
+	require.NoError(t, registry.Register(c))
+	c.Name = "Changed"
+
+	currency, _ := registry.Lookup("XLP")
+	assert.Equal(t, "Loyalty Points", currency.Name)
+}
+
+func TestRegisterCurrency(t *testing.T) {
+	_, err := i18n.NewCurrencyFromCode("XLP")
+	require.Error(t, err)
+	assert.False(t, i18n.IsSupported("XLP"))
+
+	registerCurrency(t, loyaltyPoints)
+
+	currency, err := i18n.NewCurrencyFromCode("XLP")
+	require.NoError(t, err)
+	same, err := i18n.NewCurrencyFromCode("xlp")
+	require.NoError(t, err)
+	assert.Equal(t, currency, same)
+	assert.NotSame(t, currency, same, "lookups return copies")
+	assert.True(t, i18n.IsSupported("XLP"))
+	assert.Contains(t, i18n.GetSupportedCurrencies(), "XLP")
+	assert.Equal(t, "USD", i18n.GetSupportedCurrencies()[0], "built-in currencies come first")
+
+	// Registered currencies work wherever a currency code is decoded
+	points, err := i18n.ParseMoney("1500 XLP")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1500), points.Amount)
+
+	stored, err := i18n.NewMoneyFromPrimitive(250, "XLP")
+	require.NoError(t, err)
+	assert.Equal(t, "Loyalty Points", stored.Currency.Name)
+
+	rate, err := i18n.NewExchangeRateFromPrimitive("USD", "XLP", 100, 0, 1717200000)
+	require.NoError(t, err)
+	assert.Equal(t, "XLP", rate.Quote.Code)
+}
+
+func TestRegisterCurrency_BuiltInConflict(t *testing.T) {
+	err := i18n.RegisterCurrency(i18n.Currency{Code: "USD", Symbol: "$", Name: "Dollar", DecimalPlaces: 2})
+	assert.True(t, errors.Is(err, domainerror.Conflict))
+}
+
+func TestUnregisterCurrency(t *testing.T) {
+	registerCurrency(t, loyaltyPoints)
+	points, err := i18n.NewMoneyFromPrimitive(10, "XLP")
+	require.NoError(t, err)
+
+	require.NoError(t, i18n.UnregisterCurrency("XLP"))
+	_, err = i18n.NewMoneyFromPrimitive(10, "XLP")
+	assert.Error(t, err)
+	assert.Equal(t, "XLP", points.Currency.Code, "existing values keep their currency")
+	assert.False(t, i18n.IsSupported("XLP"))
+
+	for _, code := range []string{"USD", "eur"} {
+		assert.True(t, errors.Is(i18n.UnregisterCurrency(code), domainerror.Forbidden), code)
+	}
+	assert.True(t, i18n.IsSupported("USD"), "built-in currencies stay registered")
+	assert.True(t, i18n.IsSupported("EUR"))
+}
+
+func TestCurrencyRegistry_Concurrent(t *testing.T) {
+	var wg sync.WaitGroup
+	for i := range 8 {
+		code := fmt.Sprintf("XQ%c", 'A'+i)
+		t.Cleanup(func() { _ = i18n.UnregisterCurrency(code) })
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			assert.NoError(t, i18n.RegisterCurrency(i18n.Currency{Code: code, Symbol: code, Name: code, DecimalPlaces: 2}))
+		}()
+		go func() {
+			defer wg.Done()
+			for range 100 {
+				_, err := i18n.NewCurrencyFromCode("EUR")
+				assert.NoError(t, err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	for i := range 8 {
+		assert.True(t, i18n.IsSupported(fmt.Sprintf("XQ%c", 'A'+i)))
+	}
+}
@@ -1,6 +1,7 @@
 package internationalization_test
 
 import (
+	"math"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -414,7 +415,29 @@ func TestCurrency_Format(t *testing.T) {
 				DecimalPlaces: 2,
 			},
 			amount:   -10050, // -$100.50
-			expected: "$-100.50",
+			expected: "-$100.50",
+		},
+		{
+			name: "Large 18-decimal amount renders exactly",
+			currency: i18n.Currency{
+				Code:          "ETH",
+				Symbol:        "Ξ",
+				Name:          "Ethereum",
+				DecimalPlaces: 18,
+			},
+			amount:   math.MaxInt64,
+			expected: "Ξ9.223372036854775807",
+		},
+		{
+			name: "Smallest amount",
+			currency: i18n.Currency{
+				Code:          "USD",
+				Symbol:        "$",
+				Name:          "US Dollar",
+				DecimalPlaces: 2,
+			},
+			amount:   math.MinInt64,
+			expected: "-$92233720368547758.08",
 		},
 	}
 
@@ -556,3 +579,42 @@ func TestCurrency_String(t *testing.T) {
 
 	assert.Equal(t, expected, actual)
 }
+
+func TestCurrency_FormatWithCodeAndName(t *testing.T) {
+	eth := i18n.Currency{Code: "ETH", Symbol: "Ξ", Name: "Ethereum", DecimalPlaces: 18}
+	jpy := i18n.Currency{Code: "JPY", Symbol: "¥", Name: "Japanese Yen", DecimalPlaces: 0}
+
+	assert.Equal(t, "-0.000000000000000001 ETH", eth.FormatWithCode(-1))
+	assert.Equal(t, "0.123456789012345678 Ethereum", eth.FormatWithName(123456789012345678))
+	assert.Equal(t, "-1000 JPY", jpy.FormatWithCode(-1000))
+	assert.Equal(t, "0 Japanese Yen", jpy.FormatWithName(0))
+}
+
+func TestNewCurrencyFromCode_ReturnsCopies(t *testing.T) {
+	first, err := i18n.NewCurrencyFromCode("USD")
+	assert.NoError(t, err)
+	second, err := i18n.NewCurrencyFromCode("usd")
+	assert.NoError(t, err)
+
+	assert.NotSame(t, first, second)
+	assert.Equal(t, first, second)
+	assert.Len(t, i18n.GetSupportedCurrencies(), 34)
+
+	// Changing a result leaves the registry untouched
+	first.Symbol = "US$"
+	first.DecimalPlaces = 4
+	again, err := i18n.NewCurrencyFromCode("USD")
+	assert.NoError(t, err)
+	assert.Equal(t, "$", again.Symbol)
+	assert.Equal(t, 2, again.DecimalPlaces)
+	lookedUp, _ := i18n.LookupCurrency("USD")
+	lookedUp.Name = "Changed"
+	lookedUp, _ = i18n.LookupCurrency("USD")
+	assert.Equal(t, "US Dollar", lookedUp.Name)
+
+	// The copy is the only allocation
+	allocs := testing.AllocsPerRun(100, func() {
+		_, _ = i18n.NewCurrencyFromCode("EUR")
+	})
+	assert.LessOrEqual(t, allocs, float64(1))
+}
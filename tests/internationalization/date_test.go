@@ -0,0 +1,150 @@
+package internationalization_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"golang-arch/internal/shared/domain/domainerror"
+
+	i18n "golang-arch/internal/shared/domain/internationalization"
+)
+
+func date(t *testing.T, value string) i18n.Date {
+	t.Helper()
+	d, err := i18n.ParseDate(value)
+	require.NoError(t, err)
+	return d
+}
+
+func localizedAt(t *testing.T, value, timezoneID string) i18n.LocalizedDateTime {
+	t.Helper()
+	loc, err := time.LoadLocation(timezoneID)
+	require.NoError(t, err)
+	parsed, err := time.ParseInLocation("2006-01-02 15:04", value, loc)
+	require.NoError(t, err)
+	ldt, err := i18n.NewLocalizedDateTime(*i18n.NewTimeFromTime(parsed), timezone(t, timezoneID))
+	require.NoError(t, err)
+	return *ldt
+}
+
+func TestNewDate(t *testing.T) {
+	d, err := i18n.NewDate(2024, time.February, 29)
+	require.NoError(t, err)
+	assert.Equal(t, "2024-02-29", d.String())
+	assert.Equal(t, "2024-02-29", d.ToPrimitive())
+
+	for _, invalid := range []i18n.Date{
+		{Year: 2023, Month: time.February, Day: 29},
+		{Year: 2024, Month: time.April, Day: 31},
+		{Year: 2024, Month: 13, Day: 1},
+		{Year: 2024, Month: time.January, Day: 0},
+		{},
+	} {
+		_, err := i18n.NewDate(invalid.Year, invalid.Month, invalid.Day)
+		assert.ErrorIs(t, err, domainerror.Invalid, invalid.String())
+	}
+
+	_, err = i18n.ParseDate("2023-02-29")
+	assert.Error(t, err)
+	_, err = i18n.ParseDate("17/05/1990")
+	assert.Error(t, err)
+}
+
+func TestDate_Arithmetic(t *testing.T) {
+	assert.Equal(t, date(t, "2024-03-01"), date(t, "2024-02-28").AddDays(2))
+	assert.Equal(t, date(t, "2023-12-31"), date(t, "2024-01-01").AddDays(-1))
+	assert.Equal(t, date(t, "2024-02-29"), date(t, "2024-01-31").AddMonths(1))
+	assert.Equal(t, date(t, "2023-02-28"), date(t, "2023-01-31").AddMonths(1))
+	assert.Equal(t, date(t, "2023-11-30"), date(t, "2024-01-30").AddMonths(-2))
+	assert.Equal(t, date(t, "2025-02-28"), date(t, "2024-02-29").AddYears(1))
+	assert.Equal(t, 366, date(t, "2024-01-01").DaysUntil(date(t, "2025-01-01")))
+	assert.Equal(t, -1, date(t, "2024-01-01").DaysUntil(date(t, "2023-12-31")))
+
+	assert.True(t, date(t, "2024-01-01").Before(date(t, "2024-01-02")))
+	assert.True(t, date(t, "2024-02-01").After(date(t, "2024-01-31")))
+	assert.Equal(t, 0, date(t, "2024-01-01").Compare(date(t, "2024-01-01")))
+}
+
+func TestDateDifference(t *testing.T) {
+	tests := []struct {
+		from, to string
+		want     i18n.Period
+		iso      string
+	}{
+		{"2024-01-15", "2024-01-15", i18n.Period{}, "P0D"},
+		{"2024-01-15", "2025-03-18", i18n.Period{Years: 1, Months: 2, Days: 3}, "P1Y2M3D"},
+		{"2024-01-31", "2024-03-01", i18n.Period{Months: 1, Days: 1}, "P1M1D"},
+		{"2023-01-31", "2023-03-01", i18n.Period{Months: 1, Days: 1}, "P1M1D"},
+		{"2024-01-31", "2024-02-29", i18n.Period{Days: 29}, "P29D"},
+		{"2000-02-29", "2018-02-28", i18n.Period{Years: 17, Months: 11, Days: 30}, "P17Y11M30D"},
+		{"2000-02-29", "2018-03-01", i18n.Period{Years: 18, Days: 1}, "P18Y1D"},
+		{"2025-03-18", "2024-01-15", i18n.Period{Years: -1, Months: -2, Days: -3}, "P-1Y-2M-3D"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.from+" "+tt.to, func(t *testing.T) {
+			got := i18n.DateDifference(date(t, tt.from), date(t, tt.to))
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.iso, got.String())
+		})
+	}
+}
+
+func TestAge(t *testing.T) {
+	tests := []struct {
+		name  string
+		birth string
+		asOf  i18n.LocalizedDateTime
+		want  int
+	}{
+		{"day before birthday", "2006-05-17", localizedAt(t, "2024-05-16 12:00", "UTC"), 17},
+		{"on birthday", "2006-05-17", localizedAt(t, "2024-05-17 00:00", "UTC"), 18},
+		{"leap day, non-leap year Feb 28", "2004-02-29", localizedAt(t, "2022-02-28 12:00", "UTC"), 17},
+		{"leap day, non-leap year Mar 1", "2004-02-29", localizedAt(t, "2022-03-01 12:00", "UTC"), 18},
+		{"leap day, leap year", "2004-02-29", localizedAt(t, "2024-02-29 12:00", "UTC"), 20},
+		// 23:30 on May 16 in New York is already May 17 in UTC, but age uses the local date
+		{"timezone boundary behind", "2006-05-17", localizedAt(t, "2024-05-16 23:30", "America/New_York"), 17},
+		// 08:00 on May 17 in Tokyo is still May 16 in UTC
+		{"timezone boundary ahead", "2006-05-17", localizedAt(t, "2024-05-17 08:00", "Asia/Tokyo"), 18},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			age, err := i18n.Age(date(t, tt.birth), tt.asOf)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, age)
+		})
+	}
+
+	_, err := i18n.Age(date(t, "2030-01-01"), localizedAt(t, "2024-05-16 12:00", "UTC"))
+	assert.ErrorIs(t, err, domainerror.Invalid)
+	_, err = i18n.Age(i18n.Date{Year: 2023, Month: time.February, Day: 29}, localizedAt(t, "2024-05-16 12:00", "UTC"))
+	assert.Error(t, err)
+}
+
+func TestMeetsMinimumAge(t *testing.T) {
+	asOf := localizedAt(t, "2024-05-17 09:00", "Asia/Jakarta")
+
+	ok, err := i18n.MeetsMinimumAge(date(t, "2006-05-17"), 18, asOf)
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	ok, err = i18n.MeetsMinimumAge(date(t, "2006-05-18"), 18, asOf)
+	require.NoError(t, err)
+	assert.False(t, ok)
+}
+
+func TestDate_JSON(t *testing.T) {
+	data, err := json.Marshal(struct {
+		BirthDate i18n.Date `json:"birth_date"`
+	}{date(t, "1990-05-17")})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"birth_date":"1990-05-17"}`, string(data))
+
+	var decoded i18n.Date
+	require.NoError(t, json.Unmarshal([]byte(`"2024-02-29"`), &decoded))
+	assert.Equal(t, date(t, "2024-02-29"), decoded)
+	assert.Error(t, json.Unmarshal([]byte(`"2023-02-29"`), &decoded))
+}
@@ -0,0 +1,191 @@
+package internationalization_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"golang-arch/internal/shared/domain/domainerror"
+	"golang-arch/internal/shared/domain/validation"
+	"golang-arch/internal/shared/testutil"
+
+	i18n "golang-arch/internal/shared/domain/internationalization"
+)
+
+const rateEpoch = 1703520000
+
+func currency(t *testing.T, code string) i18n.Currency {
+	t.Helper()
+	c, err := i18n.NewCurrencyFromCode(code)
+	require.NoError(t, err)
+	return *c
+}
+
+func exchangeRate(t *testing.T, base, quote, rate string) *i18n.ExchangeRate {
+	t.Helper()
+	r, err := i18n.NewExchangeRateFromDecimal(currency(t, base), currency(t, quote), rate, i18n.Time{Epoch: rateEpoch})
+	require.NoError(t, err)
+	return r
+}
+
+func TestNewExchangeRateFromDecimal(t *testing.T) {
+	tests := []struct {
+		rate      string
+		wantRate  int64
+		wantScale int
+		wantText  string
+	}{
+		{"0.9221", 9221, 4, "0.9221"},
+		{"1.50", 15, 1, "1.5"},
+		{"15500", 15500, 0, "15500"},
+		{"0.0000645", 645, 7, "0.0000645"},
+		{"2.000", 2, 0, "2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.rate, func(t *testing.T) {
+			r := exchangeRate(t, "USD", "EUR", tt.rate)
+			assert.Equal(t, tt.wantRate, r.Rate)
+			assert.Equal(t, tt.wantScale, r.Scale)
+			assert.Equal(t, tt.wantText, r.DecimalString())
+		})
+	}
+
+	for _, invalid := range []string{"", "abc", "-1", "+1", ".5", "1.2.3", "0", "0.000"} {
+		_, err := i18n.NewExchangeRateFromDecimal(currency(t, "USD"), currency(t, "EUR"), invalid, i18n.Time{Epoch: rateEpoch})
+		assert.ErrorIs(t, err, domainerror.Invalid, "rate %q", invalid)
+	}
+}
+
+func TestExchangeRate_Validate(t *testing.T) {
+	_, err := i18n.NewExchangeRate(currency(t, "USD"), currency(t, "USD"), -1, 31, i18n.Time{Epoch: -1})
+	require.Error(t, err)
+
+	fields := validation.FromError(err).ByField()
+	assert.Contains(t, fields, "quote")
+	assert.Contains(t, fields, "rate")
+	assert.Contains(t, fields, "scale")
+	assert.Contains(t, fields, "timestamp.epoch")
+}
+
+func TestExchangeRate_String(t *testing.T) {
+	r := exchangeRate(t, "USD", "EUR", "0.9221")
+	assert.Equal(t, "1 USD = 0.9221 EUR", r.String())
+	assert.InDelta(t, 0.9221, r.Float64(), 1e-12)
+}
+
+func TestExchangeRate_Invert(t *testing.T) {
+	usdEur := exchangeRate(t, "USD", "EUR", "0.9221")
+
+	eurUsd, err := usdEur.Invert()
+	require.NoError(t, err)
+	assert.Equal(t, "EUR", eurUsd.Base.Code)
+	assert.Equal(t, "USD", eurUsd.Quote.Code)
+	assert.Equal(t, "1.08448107581", eurUsd.DecimalString())
+	assert.Equal(t, usdEur.Timestamp, eurUsd.Timestamp)
+
+	// Exact inverses stay exact
+	half, err := exchangeRate(t, "USD", "GBP", "0.5").Invert()
+	require.NoError(t, err)
+	assert.Equal(t, "2", half.DecimalString())
+
+	// Tiny rates keep their significant digits
+	idrUsd, err := exchangeRate(t, "USD", "IDR", "15500").Invert()
+	require.NoError(t, err)
+	assert.Equal(t, "0.0000645161290323", idrUsd.DecimalString())
+}
+
+func TestExchangeRate_Cross(t *testing.T) {
+	usdEur := exchangeRate(t, "USD", "EUR", "0.9221")
+	eurGbp := exchangeRate(t, "EUR", "GBP", "0.8587")
+	eurGbp.Timestamp = i18n.Time{Epoch: rateEpoch - 60}
+
+	usdGbp, err := usdEur.Cross(eurGbp)
+	require.NoError(t, err)
+	assert.Equal(t, "USD", usdGbp.Base.Code)
+	assert.Equal(t, "GBP", usdGbp.Quote.Code)
+	assert.Equal(t, "0.79180727", usdGbp.DecimalString())
+	assert.Equal(t, int64(rateEpoch-60), usdGbp.Timestamp.Epoch, "cross rate carries the older timestamp")
+
+	_, err = eurGbp.Cross(usdEur)
+	assert.ErrorIs(t, err, domainerror.Invalid)
+	_, err = usdEur.Cross(nil)
+	assert.ErrorIs(t, err, domainerror.Invalid)
+}
+
+func TestExchangeRate_Convert(t *testing.T) {
+	usdEur := exchangeRate(t, "USD", "EUR", "0.9221")
+
+	price, err := i18n.MakeMoney(10050, "USD") // $100.50
+	require.NoError(t, err)
+
+	euros, err := usdEur.Convert(price, i18n.RoundHalfEven)
+	require.NoError(t, err)
+	assert.Equal(t, "EUR", euros.Currency.Code)
+	assert.Equal(t, int64(9267), euros.Amount) // 92.671...
+
+	ceiling, err := usdEur.Convert(price, i18n.RoundCeiling)
+	require.NoError(t, err)
+	assert.Equal(t, int64(9268), ceiling.Amount)
+
+	// Currencies with different decimal places
+	usdJpy := exchangeRate(t, "USD", "JPY", "142.35")
+	yen, err := usdJpy.Convert(price, i18n.RoundHalfUp)
+	require.NoError(t, err)
+	assert.Equal(t, int64(14306), yen.Amount) // 14306.175
+
+	jpyUsd, err := usdJpy.Invert()
+	require.NoError(t, err)
+	back, err := jpyUsd.Convert(*yen, i18n.RoundHalfUp)
+	require.NoError(t, err)
+	assert.Equal(t, int64(10050), back.Amount)
+
+	_, err = usdEur.Convert(*euros, i18n.RoundHalfUp)
+	assert.ErrorIs(t, err, domainerror.Invalid)
+}
+
+func TestExchangeRate_Staleness(t *testing.T) {
+	fake := testutil.FreezeTime(t, time.Unix(rateEpoch, 0).Add(30*time.Minute))
+
+	r := exchangeRate(t, "USD", "EUR", "0.9221")
+	assert.Equal(t, 30*time.Minute, r.Age())
+	assert.False(t, r.IsStale(time.Hour))
+
+	fake.Advance(time.Hour)
+	assert.True(t, r.IsStale(time.Hour))
+}
+
+func TestExchangeRate_PrimitiveRoundTrip(t *testing.T) {
+	r := exchangeRate(t, "USD", "EUR", "0.9221")
+
+	base, quote, rate, scale, epoch := r.ToPrimitive()
+	assert.Equal(t, "USD", base)
+	assert.Equal(t, "EUR", quote)
+
+	back, err := i18n.NewExchangeRateFromPrimitive(base, quote, rate, scale, epoch)
+	require.NoError(t, err)
+	assert.True(t, r.Equal(back))
+
+	// Equal compares values, not representations
+	unnormalized := *r
+	unnormalized.Rate, unnormalized.Scale = 92210, 5
+	assert.True(t, r.Equal(&unnormalized))
+
+	_, err = i18n.NewExchangeRateFromPrimitive("USD", "XXX", rate, scale, epoch)
+	assert.Error(t, err)
+}
+
+func TestExchangeRate_JSONRoundTrip(t *testing.T) {
+	r := exchangeRate(t, "USD", "EUR", "0.9221")
+
+	data, err := json.Marshal(r)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"rate":9221,"scale":4`)
+
+	var back i18n.ExchangeRate
+	require.NoError(t, json.Unmarshal(data, &back))
+	assert.True(t, r.Equal(&back))
+}
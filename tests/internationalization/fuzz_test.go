@@ -0,0 +1,139 @@
+package internationalization_test
+
+import (
+	"testing"
+
+	i18n "golang-arch/internal/shared/domain/internationalization"
+)
+
+// The fuzz targets below check that the parsers never panic and that every
+// value they accept survives a parse -> format -> parse round trip. Run one
+// with e.g. `make fuzz FUZZ=FuzzParseMoney`.
+
+func FuzzNewPhoneFromString(f *testing.F) {
+	for _, seed := range []string{
+		"+1 2125551234",
+		"+12125551234",
+		"001 212 555 1234",
+		"(212) 555-1234",
+		"212-555-1234",
+		"+44 20 7946 0958",
+		"+852 21234567",
+		"+420 123456789",
+		"447911123456",
+		"+",
+		"00",
+		"",
+		"   ",
+		"+1abcdefgh",
+		"+999999999999999999",
+	} {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, input string) {
+		phone, err := i18n.NewPhoneFromString(input)
+		if err != nil {
+			return
+		}
+		if err := phone.Validate(); err != nil {
+			t.Fatalf("NewPhoneFromString(%q) returned an invalid phone: %v", input, err)
+		}
+
+		for _, formatted := range []string{phone.Format(), phone.FormatCompact()} {
+			reparsed, err := i18n.NewPhoneFromString(formatted)
+			if err != nil {
+				t.Fatalf("NewPhoneFromString(%q) = %+v, but reparsing %q failed: %v", input, phone, formatted, err)
+			}
+			if !reparsed.Equal(phone) {
+				t.Fatalf("round trip of %q: %+v formatted as %q reparsed as %+v", input, phone, formatted, reparsed)
+			}
+		}
+	})
+}
+
+func FuzzParseMoney(f *testing.F) {
+	for _, seed := range []string{
+		"100.50 USD",
+		"USD 100.50",
+		"-100.50 usd",
+		"+7 EUR",
+		"0.00000001 BTC",
+		"9223372036.854775807 ETH",
+		"-9223372036.854775808 ETH",
+		"92233720368547758.08 USD",
+		"1000 JPY",
+		"1.5 JPY",
+		"1. USD",
+		".5 USD",
+		"USD",
+		"100 XXX",
+		"  12.34\tGBP  ",
+	} {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, input string) {
+		money, err := i18n.ParseMoney(input)
+		if err != nil {
+			return
+		}
+		if err := money.Validate(); err != nil {
+			t.Fatalf("ParseMoney(%q) returned invalid money: %v", input, err)
+		}
+
+		code := money.Currency.Code
+		for _, formatted := range []string{money.DecimalString() + " " + code, code + " " + money.DecimalString()} {
+			reparsed, err := i18n.ParseMoney(formatted)
+			if err != nil {
+				t.Fatalf("ParseMoney(%q) = %d %s, but reparsing %q failed: %v", input, money.Amount, code, formatted, err)
+			}
+			if !reparsed.Equal(money) {
+				t.Fatalf("round trip of %q: %d %s formatted as %q reparsed as %d", input, money.Amount, code, formatted, reparsed.Amount)
+			}
+		}
+	})
+}
+
+func FuzzTimeUnmarshalJSON(f *testing.F) {
+	for _, seed := range []string{
+		"0",
+		"1705320000",
+		`"1705320000"`,
+		"4133980799",
+		"4133980800",
+		"-1",
+		`""`,
+		`"`,
+		"null",
+		"1.5",
+		"1e9",
+		"1705320000abc",
+		"9223372036854775808",
+	} {
+		f.Add([]byte(seed))
+	}
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		var parsed i18n.Time
+		if err := parsed.UnmarshalJSON(data); err != nil {
+			return
+		}
+		if err := parsed.Validate(); err != nil {
+			t.Fatalf("UnmarshalJSON(%q) accepted an invalid time: %v", data, err)
+		}
+
+		encoded, err := parsed.MarshalJSON()
+		if err != nil {
+			t.Fatalf("MarshalJSON after UnmarshalJSON(%q) failed: %v", data, err)
+		}
+
+		var reparsed i18n.Time
+		if err := reparsed.UnmarshalJSON(encoded); err != nil {
+			t.Fatalf("UnmarshalJSON(%q) ok, but reparsing %s failed: %v", data, encoded, err)
+		}
+		if !reparsed.Equal(&parsed) {
+			t.Fatalf("round trip of %q: %d encoded as %s reparsed as %d", data, parsed.Epoch, encoded, reparsed.Epoch)
+		}
+	})
+}
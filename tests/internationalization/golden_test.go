@@ -0,0 +1,122 @@
+package internationalization_test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"golang-arch/internal/shared/testutil"
+
+	i18n "golang-arch/internal/shared/domain/internationalization"
+)
+
+// goldenAmounts covers zero, sub-unit, negative and large minor-unit values
+var goldenAmounts = []int64{0, 1, -1, 5, 99, 100, 12345, -12345, 100000000, 123456789012}
+
+func TestGolden_CurrencyFormat(t *testing.T) {
+	var lines []string
+	for _, code := range i18n.GetSupportedCurrencies() {
+		currency, err := i18n.NewCurrencyFromCode(code)
+		require.NoError(t, err)
+
+		for _, amount := range goldenAmounts {
+			lines = append(lines, fmt.Sprintf("%s\t%d\t%s\t%s\t%s",
+				code, amount, currency.Format(amount), currency.FormatWithCode(amount), currency.FormatWithName(amount)))
+		}
+	}
+
+	testutil.AssertGoldenLines(t, "currency_format", lines)
+}
+
+func TestGolden_LocalizedDateTimeFormat(t *testing.T) {
+	instants := []string{
+		"2024-01-15T12:00:00Z",
+		"2024-03-10T06:59:59Z",
+		"2024-07-04T23:30:00Z",
+		"2024-12-31T23:59:59Z",
+	}
+	timezones := []string{
+		"UTC", "America/New_York", "America/Los_Angeles", "Europe/London", "Europe/Paris",
+		"Asia/Tokyo", "Asia/Kolkata", "Asia/Kathmandu", "Australia/Sydney", "Pacific/Auckland",
+	}
+	layouts := []string{time.RFC3339, "2006-01-02 15:04 MST", "Mon, 02 Jan 2006 3:04PM"}
+
+	var lines []string
+	for _, instant := range instants {
+		at := testutil.MustParseTime(t, instant)
+		epoch := at.Unix()
+
+		// Timezone offsets are resolved against the clock, so freeze it at the
+		// instant being formatted to keep the output independent of today's DST
+		testutil.FreezeTime(t, at)
+
+		for _, tz := range timezones {
+			ldt, err := i18n.NewLocalizedDateTimeFromPrimitive(epoch, tz)
+			require.NoError(t, err)
+
+			formatted := make([]string, len(layouts))
+			for i, layout := range layouts {
+				formatted[i] = ldt.Format(layout)
+			}
+			lines = append(lines, fmt.Sprintf("%s\t%s\t%s", instant, tz, strings.Join(formatted, "\t")))
+		}
+	}
+
+	testutil.AssertGoldenLines(t, "localized_datetime_format", lines)
+}
+
+func TestGolden_LocalizedPhoneFormat(t *testing.T) {
+	phones := []struct {
+		phone, country, region, timezone string
+	}{
+		{"+1 2125551234", "United States", "New York", "America/New_York"},
+		{"+1 4155550100", "United States", "California", "America/Los_Angeles"},
+		{"+44 2079460958", "United Kingdom", "", "Europe/London"},
+		{"+33 142685300", "France", "Île-de-France", "Europe/Paris"},
+		{"+81 312345678", "Japan", "Tokyo", "Asia/Tokyo"},
+		{"+91 9876543210", "India", "Maharashtra", "Asia/Kolkata"},
+		{"+61 293744000", "Australia", "New South Wales", "Australia/Sydney"},
+	}
+
+	var lines []string
+	for _, p := range phones {
+		lp, err := i18n.NewLocalizedPhoneFromPrimitive(p.phone, p.country, p.region, p.timezone)
+		require.NoError(t, err)
+
+		lines = append(lines, fmt.Sprintf("%s\t%s\t%s\t%s\t%s",
+			p.phone, lp.Format(), lp.Phone.FormatCompact(), lp.Phone.FormatLocal(), lp.GetFullLocation()))
+	}
+
+	testutil.AssertGoldenLines(t, "localized_phone_format", lines)
+}
+
+func TestLineDiff(t *testing.T) {
+	diff := testutil.LineDiff("a\nb\nc\n", "a\nB\nc\nd\n")
+	assert.Equal(t, "-   2  b\n+   2  B\n+   4  d\n", diff)
+	assert.Empty(t, testutil.LineDiff("same\n", "same\n"))
+}
+
+func TestAssertGolden_UpdateFromEnv(t *testing.T) {
+	// Golden files are relative to the working directory of the test
+	dir := t.TempDir()
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+	require.NoError(t, os.Chdir(dir))
+	t.Cleanup(func() { _ = os.Chdir(wd) })
+
+	t.Setenv(testutil.UpdateGoldenEnv, "1")
+	testutil.AssertGolden(t, "nested/sample", []byte("written\n"))
+	written, err := os.ReadFile(filepath.Join(dir, testutil.GoldenDir, "nested", "sample.golden"))
+	require.NoError(t, err)
+	assert.Equal(t, "written\n", string(written))
+
+	// Without the variable the file is compared, not rewritten
+	t.Setenv(testutil.UpdateGoldenEnv, "")
+	testutil.AssertGolden(t, "nested/sample", []byte("written\n"))
+}
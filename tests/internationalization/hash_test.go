@@ -0,0 +1,49 @@
+package internationalization_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	i18n "golang-arch/internal/shared/domain/internationalization"
+)
+
+func TestKey_IgnoresDisplayFields(t *testing.T) {
+	usd := currency(t, "USD")
+	legacy := i18n.Currency{Code: "usd", Symbol: "US$", Name: "Dollar", DecimalPlaces: 2}
+	assert.NotEqual(t, usd, legacy, "the structs differ")
+	assert.Equal(t, usd.Key(), legacy.Key())
+	assert.Equal(t, usd.Hash(), legacy.Hash())
+
+	totals := map[i18n.MoneyKey]int{}
+	totals[i18n.Money{Amount: 100, Currency: usd}.Key()]++
+	totals[i18n.Money{Amount: 100, Currency: legacy}.Key()]++
+	totals[i18n.Money{Amount: 101, Currency: usd}.Key()]++
+	assert.Len(t, totals, 2)
+
+	phone, err := i18n.NewPhoneFromString("+44 20 7946 0958")
+	require.NoError(t, err)
+	assert.Equal(t, phone.Key(), i18n.Phone{CountryCode: "+44", Number: "20-7946-0958"}.Key())
+	assert.NotEqual(t, phone.Key(), i18n.Phone{CountryCode: "4", Number: "42079460958"}.Key())
+	assert.NotEqual(t, phone.Hash(), i18n.Phone{CountryCode: "4", Number: "42079460958"}.Hash())
+
+	tz, err := i18n.MakeTimezone("Europe/London")
+	require.NoError(t, err)
+	assert.Equal(t, tz.Key(), i18n.Timezone{ID: "Europe/London", Offset: 60}.Key())
+	assert.Equal(t, tz.Hash(), i18n.Timezone{ID: "Europe/London", Name: "BST"}.Hash())
+}
+
+func TestHash_IsStable(t *testing.T) {
+	usd := currency(t, "USD")
+	phone := i18n.Phone{CountryCode: "1", Number: "2125551234"}
+	tz := i18n.Timezone{ID: "America/New_York"}
+
+	// Hashes are persisted in caches and shard assignments; they must not
+	// change between releases
+	assert.Equal(t, uint64(0x9183e6ef668aa968), usd.Hash())
+	assert.Equal(t, uint64(0x86abccc2eb1a8403), i18n.Money{Amount: 10050, Currency: usd}.Hash())
+	assert.Equal(t, uint64(0x29144f108b0740cc), phone.Hash())
+	assert.Equal(t, uint64(0x1d3820e4fa907079), tz.Hash())
+	assert.NotEqual(t, i18n.Money{Amount: 1, Currency: usd}.Hash(), i18n.Money{Amount: 2, Currency: usd}.Hash())
+}
@@ -0,0 +1,104 @@
+package internationalization_test
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	i18n "golang-arch/internal/shared/domain/internationalization"
+)
+
+// The reflect* types have the same fields and tags as the domain types but no
+// methods, so encoding/json encodes them by reflection. They are the reference
+// the hand-written encoders must match byte for byte.
+type (
+	reflectCurrency i18n.Currency
+	reflectTimezone i18n.Timezone
+	reflectMoney    struct {
+		Amount   int64           `json:"amount"`
+		Decimal  float64         `json:"decimal"`
+		Currency reflectCurrency `json:"currency"`
+	}
+	reflectLocalizedDateTime struct {
+		Time     int64           `json:"time"`
+		Timezone reflectTimezone `json:"timezone"`
+	}
+)
+
+func assertMatchesReflection(t *testing.T, reference any, marshaler json.Marshaler) {
+	t.Helper()
+
+	expected, err := json.Marshal(reference)
+	require.NoError(t, err)
+
+	actual, err := marshaler.MarshalJSON()
+	require.NoError(t, err)
+	assert.Equal(t, string(expected), string(actual))
+
+	// The output must also survive encoding/json's validation when embedded
+	wrapped, err := json.Marshal(map[string]json.Marshaler{"value": marshaler})
+	require.NoError(t, err)
+	assert.Equal(t, `{"value":`+string(expected)+`}`, string(wrapped))
+}
+
+func TestCurrency_MarshalJSON_MatchesReflection(t *testing.T) {
+	currencies := []i18n.Currency{
+		{Code: "USD", Symbol: "$", Name: "US Dollar", DecimalPlaces: 2},
+		{Code: "AED", Symbol: "د.إ", Name: "UAE Dirham", DecimalPlaces: 2},
+		{Code: "XSS", Symbol: "<script>&\"'", Name: "tab\tnewline\n  \x00\x1f\\", DecimalPlaces: 0},
+		{Code: "BAD", Symbol: "\xff\xfe", Name: "half \xe2\x82", DecimalPlaces: 18},
+	}
+
+	for _, currency := range currencies {
+		currency := currency
+		assertMatchesReflection(t, reflectCurrency(currency), &currency)
+	}
+}
+
+func TestMoney_MarshalJSON_MatchesReflection(t *testing.T) {
+	currencies := []i18n.Currency{
+		{Code: "USD", Symbol: "$", Name: "US Dollar", DecimalPlaces: 2},
+		{Code: "JPY", Symbol: "¥", Name: "Japanese Yen", DecimalPlaces: 0},
+		{Code: "ETH", Symbol: "Ξ", Name: "Ethereum", DecimalPlaces: 18},
+	}
+	amounts := []int64{0, 1, -1, 10050, -10050, 123456789, math.MaxInt64, math.MinInt64}
+
+	for _, currency := range currencies {
+		for _, amount := range amounts {
+			money := &i18n.Money{Amount: amount, Currency: currency}
+			reference := reflectMoney{Amount: amount, Decimal: money.ToDecimal(), Currency: reflectCurrency(currency)}
+			assertMatchesReflection(t, reference, money)
+		}
+	}
+}
+
+func TestLocalizedDateTime_MarshalJSON_MatchesReflection(t *testing.T) {
+	for _, id := range []string{"UTC", "America/New_York", "Asia/Kathmandu"} {
+		ldt, err := i18n.NewLocalizedDateTimeFromPrimitive(1703520000, id)
+		require.NoError(t, err)
+
+		reference := reflectLocalizedDateTime{Time: ldt.Time.Epoch, Timezone: reflectTimezone(ldt.Timezone)}
+		assertMatchesReflection(t, reference, ldt)
+	}
+}
+
+func FuzzCurrency_MarshalJSON(f *testing.F) {
+	f.Add("USD", "$", "US Dollar", 2)
+	f.Add("<&>", " ", "\xff", -1)
+
+	f.Fuzz(func(t *testing.T, code, symbol, name string, decimalPlaces int) {
+		currency := &i18n.Currency{Code: code, Symbol: symbol, Name: name, DecimalPlaces: decimalPlaces}
+
+		expected, err := json.Marshal(reflectCurrency(*currency))
+		if err != nil {
+			t.Fatal(err)
+		}
+		actual, _ := currency.MarshalJSON()
+		if string(expected) != string(actual) {
+			t.Fatalf("MarshalJSON = %s, encoding/json = %s", actual, expected)
+		}
+	})
+}
@@ -0,0 +1,164 @@
+package internationalization_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"golang-arch/internal/shared/domain/domainerror"
+	i18n "golang-arch/internal/shared/domain/internationalization"
+	"golang-arch/internal/shared/domain/validation"
+)
+
+func ledgerEntry(t *testing.T, transactionID, account string, direction i18n.Direction, amount int64, code string) i18n.LedgerEntry {
+	t.Helper()
+
+	money, err := i18n.MakeMoney(amount, code)
+	require.NoError(t, err)
+	postedAt, err := i18n.MakeLocalizedDateTime(1703520000, "America/New_York")
+	require.NoError(t, err)
+
+	entry, err := i18n.NewLedgerEntry(transactionID, account, direction, money, postedAt, "INV-1")
+	require.NoError(t, err)
+	return *entry
+}
+
+func TestParseDirection(t *testing.T) {
+	direction, err := i18n.ParseDirection("credit")
+	require.NoError(t, err)
+	assert.Equal(t, i18n.Credit, direction)
+	assert.Equal(t, int64(-1), direction.Sign())
+	assert.Equal(t, i18n.Debit, direction.Opposite())
+
+	_, err = i18n.ParseDirection("DEBIT")
+	assert.ErrorIs(t, err, domainerror.Invalid)
+}
+
+func TestNewLedgerEntry_Validation(t *testing.T) {
+	usd, err := i18n.MakeMoney(-100, "USD")
+	require.NoError(t, err)
+	postedAt, err := i18n.MakeLocalizedDateTime(1703520000, "UTC")
+	require.NoError(t, err)
+
+	_, err = i18n.NewLedgerEntry("", "", "sideways", usd, postedAt, "")
+	require.Error(t, err)
+	assert.ErrorIs(t, err, domainerror.Invalid)
+
+	fields := validation.FromError(err).ByField()
+	assert.Contains(t, fields, "transaction_id")
+	assert.Contains(t, fields, "account")
+	assert.Contains(t, fields, "direction")
+	require.Contains(t, fields, "amount")
+	assert.Equal(t, validation.CodeOutOfRange, fields["amount"][0].Code)
+}
+
+func TestLedgerEntry_PrimitiveRoundTrip(t *testing.T) {
+	entry := ledgerEntry(t, "tx-1", "cash", i18n.Debit, 10050, "USD")
+
+	primitive := entry.ToPrimitive()
+	assert.Equal(t, i18n.LedgerEntryPrimitive{
+		TransactionID: "tx-1",
+		Account:       "cash",
+		Direction:     "debit",
+		Amount:        10050,
+		CurrencyCode:  "USD",
+		PostedAt:      1703520000,
+		PostedAtTZ:    "America/New_York",
+		Reference:     "INV-1",
+	}, primitive)
+
+	back, err := i18n.NewLedgerEntryFromPrimitive(primitive)
+	require.NoError(t, err)
+	assert.Equal(t, entry, *back)
+
+	primitive.Direction = "both"
+	_, err = i18n.NewLedgerEntryFromPrimitive(primitive)
+	assert.Error(t, err)
+}
+
+func TestLedgerEntry_JSONRoundTrip(t *testing.T) {
+	entry := ledgerEntry(t, "tx-1", "revenue", i18n.Credit, 10050, "USD")
+
+	data, err := json.Marshal(entry)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"direction":"credit"`)
+
+	var back i18n.LedgerEntry
+	require.NoError(t, json.Unmarshal(data, &back))
+	assert.Equal(t, entry.ToPrimitive(), back.ToPrimitive())
+}
+
+func TestRunningBalances(t *testing.T) {
+	usd := ledgerEntry(t, "tx-1", "cash", i18n.Debit, 1, "USD").Amount.Currency
+	entries := []i18n.LedgerEntry{
+		ledgerEntry(t, "tx-1", "cash", i18n.Debit, 10000, "USD"),
+		ledgerEntry(t, "tx-2", "cash", i18n.Credit, 2500, "USD"),
+		ledgerEntry(t, "tx-3", "cash", i18n.Credit, 9000, "USD"),
+	}
+
+	balances, err := i18n.RunningBalances(usd, entries...)
+	require.NoError(t, err)
+	require.Len(t, balances, 3)
+	assert.Equal(t, int64(10000), balances[0].Amount)
+	assert.Equal(t, int64(7500), balances[1].Amount)
+	assert.Equal(t, int64(-1500), balances[2].Amount)
+
+	balance, err := i18n.Balance(usd, entries...)
+	require.NoError(t, err)
+	assert.Equal(t, int64(-1500), balance.Amount)
+
+	empty, err := i18n.Balance(usd)
+	require.NoError(t, err)
+	assert.True(t, empty.IsZero())
+	assert.Equal(t, "USD", empty.Currency.Code)
+
+	mixed := append(entries, ledgerEntry(t, "tx-4", "cash", i18n.Debit, 100, "EUR"))
+	_, err = i18n.RunningBalances(usd, mixed...)
+	assert.ErrorIs(t, err, domainerror.Invalid)
+}
+
+func TestValidateBalanced(t *testing.T) {
+	balanced := []i18n.LedgerEntry{
+		ledgerEntry(t, "tx-1", "cash", i18n.Debit, 10050, "USD"),
+		ledgerEntry(t, "tx-1", "revenue", i18n.Credit, 10000, "USD"),
+		ledgerEntry(t, "tx-1", "tax", i18n.Credit, 50, "USD"),
+		ledgerEntry(t, "tx-2", "cash", i18n.Debit, 500, "EUR"),
+		ledgerEntry(t, "tx-2", "revenue", i18n.Credit, 500, "EUR"),
+	}
+	assert.NoError(t, i18n.ValidateBalanced(balanced))
+	assert.NoError(t, i18n.ValidateBalanced(nil))
+
+	unbalanced := append(balanced,
+		ledgerEntry(t, "tx-3", "cash", i18n.Debit, 100, "USD"),
+		ledgerEntry(t, "tx-3", "revenue", i18n.Credit, 100, "EUR"),
+	)
+	err := i18n.ValidateBalanced(unbalanced)
+	require.Error(t, err)
+	assert.ErrorIs(t, err, domainerror.Invalid)
+
+	errs := validation.FromError(err)
+	require.Len(t, errs, 2)
+	assert.Equal(t, "transaction[tx-3]", errs[0].Field)
+	assert.Equal(t, "EUR", errs[0].Params["currency"])
+	assert.Equal(t, "-100", errs[0].Params["difference"])
+	assert.Equal(t, "USD", errs[1].Params["currency"])
+	assert.Equal(t, "100", errs[1].Params["difference"])
+}
+
+func TestLedgerEntry_Reverse(t *testing.T) {
+	original := ledgerEntry(t, "tx-1", "cash", i18n.Debit, 10050, "USD")
+	reversal, err := original.Reverse("tx-1-reversal", original.PostedAt)
+	require.NoError(t, err)
+
+	assert.Equal(t, i18n.Credit, reversal.Direction)
+	assert.Equal(t, original.Amount, reversal.Amount)
+	assert.Equal(t, original.SignedAmount(), -reversal.SignedAmount())
+
+	// The reversal alone does not balance, but original plus reversal nets to zero
+	usd := original.Amount.Currency
+	balance, err := i18n.Balance(usd, original, *reversal)
+	require.NoError(t, err)
+	assert.True(t, balance.IsZero())
+}
@@ -0,0 +1,230 @@
+package internationalization_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"golang-arch/internal/shared/domain/validation"
+
+	i18n "golang-arch/internal/shared/domain/internationalization"
+)
+
+func defaultPreferences(t *testing.T) i18n.LocalePreferences {
+	t.Helper()
+	prefs, err := i18n.NewLocalePreferences("en-US", timezone(t, "UTC"), currency(t, "USD"), i18n.MeasurementUS, time.Sunday)
+	require.NoError(t, err)
+	return *prefs
+}
+
+func weekday(day time.Weekday) *time.Weekday {
+	return &day
+}
+
+func TestNewLocalePreferences_Validation(t *testing.T) {
+	_, err := i18n.NewLocalePreferences("en-us", timezone(t, "UTC"), i18n.Currency{}, "imperial", time.Weekday(9))
+	require.Error(t, err)
+
+	byField := validation.FromError(err).ByField()
+	assert.Contains(t, byField, "locale")
+	assert.Contains(t, byField, "currency.code")
+	assert.Contains(t, byField, "measurement_system")
+	assert.Contains(t, byField, "first_day_of_week")
+	assert.NotContains(t, byField, "timezone")
+}
+
+func TestLocalePreferences_Primitive(t *testing.T) {
+	prefs, err := i18n.NewLocalePreferencesFromPrimitive("de_de", "Europe/Berlin", "EUR", "metric", 1)
+	require.NoError(t, err)
+	assert.Equal(t, i18n.Locale("de-DE"), prefs.Locale)
+	assert.Equal(t, time.Monday, prefs.FirstDayOfWeek)
+
+	locale, timezoneID, currencyCode, measurement, firstDay := prefs.ToPrimitive()
+	assert.Equal(t, "de-DE", locale)
+	assert.Equal(t, "Europe/Berlin", timezoneID)
+	assert.Equal(t, "EUR", currencyCode)
+	assert.Equal(t, "metric", measurement)
+	assert.Equal(t, 1, firstDay)
+
+	_, err = i18n.NewLocalePreferencesFromPrimitive("de-DE", "Mars/Olympus", "XXX", "metric", 1)
+	require.Error(t, err)
+	byField := validation.FromError(err).ByField()
+	assert.Contains(t, byField, "timezone")
+	assert.Contains(t, byField, "currency")
+}
+
+func TestLocalePreferences_JSON(t *testing.T) {
+	prefs := defaultPreferences(t)
+
+	data, err := json.Marshal(prefs)
+	require.NoError(t, err)
+
+	var decoded i18n.LocalePreferences
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, prefs.Locale, decoded.Locale)
+	assert.Equal(t, prefs.Timezone.ID, decoded.Timezone.ID)
+	assert.Equal(t, prefs.Currency.Code, decoded.Currency.Code)
+	assert.Equal(t, prefs.MeasurementSystem, decoded.MeasurementSystem)
+
+	var raw map[string]any
+	require.NoError(t, json.Unmarshal(data, &raw))
+	raw["measurement_system"] = "imperial"
+	invalid, err := json.Marshal(raw)
+	require.NoError(t, err)
+	assert.Error(t, json.Unmarshal(invalid, &decoded))
+}
+
+func TestResolveLocalePreferences(t *testing.T) {
+	defaults := defaultPreferences(t)
+
+	stored := i18n.PartialLocalePreferences{Timezone: "Europe/Berlin", FirstDayOfWeek: weekday(time.Monday)}
+	request := i18n.PartialLocalePreferences{Locale: "en-GB", Timezone: "Europe/London", Currency: "GBP"}
+
+	prefs := i18n.ResolveLocalePreferences(defaults, stored, request)
+	assert.Equal(t, i18n.Locale("en-GB"), prefs.Locale, "from the request")
+	assert.Equal(t, "Europe/Berlin", prefs.Timezone.ID, "stored preferences win over the request")
+	assert.Equal(t, "GBP", prefs.Currency.Code)
+	assert.Equal(t, i18n.MeasurementUK, prefs.MeasurementSystem, "derived from the resolved locale")
+	assert.Equal(t, time.Monday, prefs.FirstDayOfWeek)
+	assert.NoError(t, prefs.Validate())
+}
+
+func TestResolveLocalePreferences_Precedence(t *testing.T) {
+	defaults := defaultPreferences(t)
+
+	// A source that sets a field to the default value still outranks later sources
+	first := i18n.PartialLocalePreferences{Currency: "USD", FirstDayOfWeek: weekday(time.Sunday), MeasurementSystem: "metric"}
+	second := i18n.PartialLocalePreferences{Locale: "ja-JP", Currency: "JPY", FirstDayOfWeek: weekday(time.Monday), MeasurementSystem: "us"}
+
+	prefs := i18n.ResolveLocalePreferences(defaults, first, second)
+	assert.Equal(t, "USD", prefs.Currency.Code)
+	assert.Equal(t, time.Sunday, prefs.FirstDayOfWeek)
+	assert.Equal(t, i18n.MeasurementMetric, prefs.MeasurementSystem)
+	assert.Equal(t, i18n.Locale("ja-JP"), prefs.Locale)
+}
+
+func TestResolveLocalePreferences_SkipsInvalidValues(t *testing.T) {
+	defaults := defaultPreferences(t)
+
+	untrusted := i18n.PartialLocalePreferences{
+		Locale:            "<script>",
+		Timezone:          "Not/AZone",
+		Currency:          "???",
+		MeasurementSystem: "cubits",
+		FirstDayOfWeek:    weekday(time.Weekday(12)),
+	}
+	fallback := i18n.PartialLocalePreferences{Locale: "fr"}
+
+	prefs := i18n.ResolveLocalePreferences(defaults, untrusted, fallback)
+	assert.Equal(t, i18n.Locale("fr"), prefs.Locale)
+	assert.Equal(t, defaults.Timezone.ID, prefs.Timezone.ID)
+	assert.Equal(t, defaults.Currency.Code, prefs.Currency.Code)
+	assert.Equal(t, defaults.MeasurementSystem, prefs.MeasurementSystem, "a locale without region keeps the default system")
+	assert.Equal(t, defaults.FirstDayOfWeek, prefs.FirstDayOfWeek)
+
+	assert.Equal(t, defaults, i18n.ResolveLocalePreferences(defaults))
+}
+
+func TestLocalePreferences_Partial(t *testing.T) {
+	stored, err := i18n.NewLocalePreferencesFromPrimitive("id-ID", "Asia/Jakarta", "IDR", "metric", 1)
+	require.NoError(t, err)
+
+	prefs := i18n.ResolveLocalePreferences(defaultPreferences(t), stored.Partial(),
+		i18n.PartialLocalePreferences{Locale: "en-US", Currency: "USD"})
+	assert.Equal(t, stored.Locale, prefs.Locale)
+	assert.Equal(t, stored.Currency.Code, prefs.Currency.Code)
+	assert.Equal(t, stored.FirstDayOfWeek, prefs.FirstDayOfWeek)
+}
+
+func TestResolveLocalePreferences_CurrencyFromRegion(t *testing.T) {
+	defaults := defaultPreferences(t)
+
+	prefs := i18n.ResolveLocalePreferences(defaults, i18n.PartialLocalePreferences{Locale: "de-AT"})
+	assert.Equal(t, "EUR", prefs.Currency.Code)
+
+	prefs = i18n.ResolveLocalePreferences(defaults, i18n.PartialLocalePreferences{Locale: "es-AR"})
+	assert.Equal(t, defaults.Currency.Code, prefs.Currency.Code, "unsupported currency keeps the default")
+
+	prefs = i18n.ResolveLocalePreferences(defaults,
+		i18n.PartialLocalePreferences{Locale: "ja-JP"}, i18n.PartialLocalePreferences{Currency: "USD"})
+	assert.Equal(t, "USD", prefs.Currency.Code, "an explicit currency wins over the region")
+}
+
+func TestNewLocaleDefaults(t *testing.T) {
+	defaults, err := i18n.NewLocaleDefaults("de-DE", "Europe/Berlin", "EUR", []string{"de", "en-GB"})
+	require.NoError(t, err)
+	assert.Equal(t, i18n.Locale("de-DE"), defaults.Preferences.Locale)
+	assert.Equal(t, "Europe/Berlin", defaults.Preferences.Timezone.ID)
+	assert.Equal(t, "EUR", defaults.Preferences.Currency.Code)
+	assert.Equal(t, i18n.MeasurementMetric, defaults.Preferences.MeasurementSystem)
+	assert.Equal(t, time.Monday, defaults.Preferences.FirstDayOfWeek)
+	assert.Equal(t, []i18n.Locale{"de", "en-GB"}, defaults.Supported)
+
+	builtin, err := i18n.NewLocaleDefaults("", "", "", nil)
+	require.NoError(t, err)
+	assert.Equal(t, defaultPreferences(t), builtin.Preferences)
+
+	_, err = i18n.NewLocaleDefaults("en-US", "Mars/Olympus", "XXX", []string{"en", "not a locale"})
+	require.Error(t, err)
+	byField := validation.FromError(err).ByField()
+	assert.Contains(t, byField, "default_timezone")
+	assert.Contains(t, byField, "default_currency")
+	assert.Contains(t, byField, "supported_locales[1]")
+}
+
+func TestLocaleDefaults_Negotiate(t *testing.T) {
+	defaults, err := i18n.NewLocaleDefaults("de-DE", "Europe/Berlin", "EUR", []string{"de", "en-GB", "en-US"})
+	require.NoError(t, err)
+
+	assert.Equal(t, i18n.Locale("en-US"), defaults.Negotiate("en-US"), "exact match")
+	assert.Equal(t, i18n.Locale("en-GB"), defaults.Negotiate("en-AU"), "first with the same language")
+	assert.Equal(t, i18n.Locale("de"), defaults.Negotiate("de-AT"))
+	assert.Equal(t, i18n.Locale("de-DE"), defaults.Negotiate("fr-FR"), "unsupported language gets the default")
+
+	open := i18n.LocaleDefaults{Preferences: defaults.Preferences}
+	assert.Equal(t, i18n.Locale("fr-FR"), open.Negotiate("fr-FR"), "no supported locales accepts any")
+
+	prefs := defaults.Resolve(i18n.PartialLocalePreferences{Locale: "en-AU"})
+	assert.Equal(t, i18n.Locale("en-GB"), prefs.Locale)
+	assert.Equal(t, "AUD", prefs.Currency.Code, "currency follows the requested region")
+	assert.Equal(t, "Europe/Berlin", prefs.Timezone.ID)
+}
+
+func TestLocaleDefaults_LocalesAndTenants(t *testing.T) {
+	defaults, err := i18n.NewLocaleDefaults("en-US", "UTC", "USD", []string{"en", "de"})
+	require.NoError(t, err)
+	require.NoError(t, defaults.AddLocale("de-CH", i18n.PartialLocalePreferences{Timezone: "Europe/Zurich", Currency: "EUR"}))
+	require.NoError(t, defaults.AddLocale("de", i18n.PartialLocalePreferences{Timezone: "Europe/Berlin"}))
+	require.NoError(t, defaults.AddTenant("acme", i18n.PartialLocalePreferences{Locale: "de-DE", Currency: "EUR"}))
+
+	prefs := defaults.Resolve(i18n.PartialLocalePreferences{Locale: "de-CH"})
+	assert.Equal(t, i18n.Locale("de"), prefs.Locale)
+	assert.Equal(t, "Europe/Zurich", prefs.Timezone.ID)
+	assert.Equal(t, "EUR", prefs.Currency.Code, "the locale's default beats its national currency")
+
+	prefs = defaults.Resolve(i18n.PartialLocalePreferences{Locale: "de-AT", Timezone: "Europe/Vienna"})
+	assert.Equal(t, "Europe/Vienna", prefs.Timezone.ID, "sources beat the locale's default")
+	assert.Equal(t, "EUR", prefs.Currency.Code)
+
+	prefs = defaults.Resolve()
+	assert.Equal(t, i18n.Locale("en"), prefs.Locale)
+	assert.Equal(t, "UTC", prefs.Timezone.ID)
+
+	acme := defaults.ForTenant("acme")
+	prefs = acme.Resolve()
+	assert.Equal(t, i18n.Locale("de"), prefs.Locale)
+	assert.Equal(t, "Europe/Berlin", prefs.Timezone.ID, "the default of the tenant's language")
+	assert.Equal(t, "EUR", prefs.Currency.Code)
+	prefs = acme.Resolve(i18n.PartialLocalePreferences{Locale: "en-GB"})
+	assert.Equal(t, i18n.Locale("en"), prefs.Locale)
+	assert.Equal(t, "EUR", prefs.Currency.Code, "the tenant's currency beats the region's")
+	assert.Equal(t, "UTC", defaults.ForTenant("unknown").Resolve().Timezone.ID)
+
+	err = defaults.AddLocale("fr", i18n.PartialLocalePreferences{Timezone: "Mars/Olympus"})
+	assert.ErrorContains(t, err, "timezone")
+	assert.Error(t, defaults.AddTenant("", i18n.PartialLocalePreferences{}))
+	assert.Error(t, defaults.AddTenant("other", i18n.PartialLocalePreferences{Currency: "XXX"}))
+}
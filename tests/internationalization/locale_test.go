@@ -0,0 +1,73 @@
+package internationalization_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"golang-arch/internal/shared/domain/domainerror"
+
+	i18n "golang-arch/internal/shared/domain/internationalization"
+)
+
+func TestParseLocale(t *testing.T) {
+	tests := []struct {
+		tag      string
+		want     i18n.Locale
+		language i18n.Language
+		region   string
+	}{
+		{"en", "en", "en", ""},
+		{"en-US", "en-US", "en", "US"},
+		{"pt_br", "pt-BR", "pt", "BR"},
+		{"ZH-hant-tw", "zh-Hant-TW", "zh", "TW"},
+		{"zh-Hant", "zh-Hant", "zh", ""},
+		{"es-419", "es-419", "es", "419"},
+		{"fil-PH", "fil-PH", "fil", "PH"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.tag, func(t *testing.T) {
+			locale, err := i18n.ParseLocale(tt.tag)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, locale)
+			assert.Equal(t, tt.language, locale.Language())
+			assert.Equal(t, tt.region, locale.Region())
+			assert.NoError(t, locale.Validate())
+		})
+	}
+
+	for _, tag := range []string{"", "e", "english", "en-", "en-USA", "en-US-x", "12-US", "en-Latn-US-POSIX"} {
+		_, err := i18n.ParseLocale(tag)
+		assert.ErrorIs(t, err, domainerror.Invalid, tag)
+	}
+
+	assert.ErrorIs(t, i18n.Locale("en-us").Validate(), domainerror.Invalid, "non-canonical casing")
+	assert.Panics(t, func() { i18n.MustParseLocale("??") })
+}
+
+func TestLocale_WithRegion(t *testing.T) {
+	locale, err := i18n.MustParseLocale("en-US").WithRegion("gb")
+	require.NoError(t, err)
+	assert.Equal(t, i18n.Locale("en-GB"), locale)
+
+	locale, err = i18n.MustParseLocale("zh-Hant").WithRegion("TW")
+	require.NoError(t, err)
+	assert.Equal(t, i18n.Locale("zh-Hant-TW"), locale)
+
+	_, err = i18n.MustParseLocale("en").WithRegion("??")
+	assert.Error(t, err)
+}
+
+func TestLocale_MeasurementSystem(t *testing.T) {
+	assert.Equal(t, i18n.MeasurementUS, i18n.MustParseLocale("en-US").MeasurementSystem())
+	assert.Equal(t, i18n.MeasurementUK, i18n.MustParseLocale("en-GB").MeasurementSystem())
+	assert.Equal(t, i18n.MeasurementMetric, i18n.MustParseLocale("id-ID").MeasurementSystem())
+	assert.Equal(t, i18n.MeasurementMetric, i18n.MustParseLocale("en").MeasurementSystem())
+
+	system, err := i18n.ParseMeasurementSystem(" US ")
+	require.NoError(t, err)
+	assert.Equal(t, i18n.MeasurementUS, system)
+	_, err = i18n.ParseMeasurementSystem("imperial")
+	assert.ErrorIs(t, err, domainerror.Invalid)
+}
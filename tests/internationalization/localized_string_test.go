@@ -0,0 +1,102 @@
+package internationalization_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"golang-arch/internal/shared/domain/validation"
+
+	i18n "golang-arch/internal/shared/domain/internationalization"
+)
+
+func TestNewLocalizedString(t *testing.T) {
+	s, err := i18n.NewLocalizedString(map[string]string{"en": "Blue Shirt", "de_de": "Blaues Hemd"})
+	require.NoError(t, err)
+	assert.Equal(t, 2, s.Len())
+	assert.Equal(t, []i18n.Locale{"de-DE", "en"}, s.Locales())
+	assert.Equal(t, map[string]string{"de-DE": "Blaues Hemd", "en": "Blue Shirt"}, s.Texts())
+
+	_, err = i18n.NewLocalizedString(map[string]string{"en-US": "Color", "en_us": "Colour", "x": "Bad", "fr": " "})
+	require.Error(t, err)
+	byField := validation.FromError(err).ByField()
+	assert.Contains(t, byField, "x")
+	assert.Contains(t, byField, "fr")
+	assert.Len(t, byField, 3)
+}
+
+func TestLocalizedString_Get(t *testing.T) {
+	s, err := i18n.NewLocalizedString(map[string]string{"en-GB": "Colour", "en-US": "Color", "de": "Farbe", "pt-BR": "Cor"})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name        string
+		preferences []i18n.Locale
+		want        string
+		ok          bool
+	}{
+		{"exact", []i18n.Locale{"en-US"}, "Color", true},
+		{"bare language", []i18n.Locale{"de-AT"}, "Farbe", true},
+		{"sibling region", []i18n.Locale{"en-AU"}, "Colour", true},
+		{"sibling of bare language", []i18n.Locale{"pt"}, "Cor", true},
+		{"fallback order", []i18n.Locale{"fr-FR", "de-CH", "en"}, "Farbe", true},
+		{"no match", []i18n.Locale{"fr"}, "", false},
+		{"no preferences", nil, "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := s.Get(tt.preferences...)
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestLocalizedString_With(t *testing.T) {
+	s, err := i18n.NewLocalizedString(map[string]string{"en": "Shirt"})
+	require.NoError(t, err)
+
+	updated, err := s.With("de", "Hemd")
+	require.NoError(t, err)
+	assert.Equal(t, 1, s.Len(), "original is unchanged")
+	assert.Equal(t, 2, updated.Len())
+
+	_, err = s.With("de", "")
+	assert.Error(t, err)
+}
+
+func TestLocalizedString_Slugs(t *testing.T) {
+	s, err := i18n.NewLocalizedString(map[string]string{"en": "Beautiful Greetings", "de-DE": "Schöne Grüße", "ru": "Привет"})
+	require.NoError(t, err)
+
+	assert.Equal(t, map[i18n.Locale]string{
+		"en":    "beautiful-greetings",
+		"de-DE": "schoene-gruesse",
+		"ru":    "privet",
+	}, s.Slugs())
+
+	slug, ok := s.Slug("de-AT")
+	assert.True(t, ok)
+	assert.Equal(t, "schoene-gruesse", slug)
+
+	_, ok = s.Slug("ja")
+	assert.False(t, ok)
+}
+
+func TestLocalizedString_JSON(t *testing.T) {
+	s, err := i18n.NewLocalizedString(map[string]string{"en": "Shirt", "de-DE": "Hemd"})
+	require.NoError(t, err)
+
+	data, err := s.ToPrimitive()
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"de-DE":"Hemd","en":"Shirt"}`, string(data))
+
+	restored, err := i18n.NewLocalizedStringFromPrimitive(data)
+	require.NoError(t, err)
+	assert.Equal(t, s, restored)
+
+	var decoded i18n.LocalizedString
+	assert.Error(t, json.Unmarshal([]byte(`{"english":"Shirt"}`), &decoded))
+}
@@ -0,0 +1,106 @@
+package internationalization_test
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"golang-arch/internal/shared/domain/domainerror"
+
+	i18n "golang-arch/internal/shared/domain/internationalization"
+)
+
+func TestNewMoneyBag(t *testing.T) {
+	bag, err := i18n.NewMoneyBag(*money(10000, currency(t, "USD")), *money(5000, currency(t, "EUR")), *money(2500, currency(t, "USD")))
+	require.NoError(t, err)
+
+	assert.Equal(t, 2, bag.Len())
+	assert.Equal(t, []string{"EUR", "USD"}, bag.Currencies())
+
+	usd, ok := bag.Get("USD")
+	require.True(t, ok)
+	assert.Equal(t, int64(12500), usd.Amount)
+
+	_, ok = bag.Get("JPY")
+	assert.False(t, ok)
+
+	amounts := bag.Amounts()
+	require.Len(t, amounts, 2)
+	assert.Equal(t, "EUR", amounts[0].Currency.Code)
+	assert.Equal(t, "€50.00 + $125.00", bag.String())
+}
+
+func TestMoneyBag_ZeroValue(t *testing.T) {
+	var bag i18n.MoneyBag
+	assert.Equal(t, 0, bag.Len())
+	assert.True(t, bag.IsZero())
+	assert.Empty(t, bag.Amounts())
+	assert.Equal(t, "0", bag.String())
+
+	next, err := bag.Add(*money(100, currency(t, "JPY")))
+	require.NoError(t, err)
+	assert.Equal(t, 1, next.Len())
+	assert.False(t, next.IsZero())
+}
+
+func TestMoneyBag_AddIsImmutable(t *testing.T) {
+	bag, err := i18n.NewMoneyBag(*money(100, currency(t, "USD")))
+	require.NoError(t, err)
+
+	next, err := bag.Add(*money(50, currency(t, "USD")))
+	require.NoError(t, err)
+
+	original, _ := bag.Get("USD")
+	updated, _ := next.Get("USD")
+	assert.Equal(t, int64(100), original.Amount)
+	assert.Equal(t, int64(150), updated.Amount)
+}
+
+func TestMoneyBag_AddOverflow(t *testing.T) {
+	bag, err := i18n.NewMoneyBag(*money(math.MaxInt64, currency(t, "USD")))
+	require.NoError(t, err)
+
+	_, err = bag.Add(*money(1, currency(t, "USD")))
+	assert.ErrorIs(t, err, domainerror.Invalid)
+
+	_, err = bag.Add(i18n.Money{Amount: 1, Currency: i18n.Currency{Code: "???"}})
+	assert.Error(t, err)
+}
+
+func TestMoneyBag_Merge(t *testing.T) {
+	left, err := i18n.NewMoneyBag(*money(100, currency(t, "USD")), *money(200, currency(t, "EUR")))
+	require.NoError(t, err)
+	right, err := i18n.NewMoneyBag(*money(1, currency(t, "USD")), *money(300, currency(t, "GBP")))
+	require.NoError(t, err)
+
+	merged, err := left.Merge(right)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"EUR", "GBP", "USD"}, merged.Currencies())
+	usd, _ := merged.Get("USD")
+	assert.Equal(t, int64(101), usd.Amount)
+
+	same, err := left.Merge(nil)
+	require.NoError(t, err)
+	assert.Equal(t, left.Amounts(), same.Amounts())
+}
+
+func TestMoneyBag_JSON(t *testing.T) {
+	bag, err := i18n.NewMoneyBag(*money(10050, currency(t, "USD")), *money(2000, currency(t, "EUR")))
+	require.NoError(t, err)
+
+	data, err := json.Marshal(bag)
+	require.NoError(t, err)
+
+	var decoded []map[string]any
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	require.Len(t, decoded, 2)
+	assert.EqualValues(t, 2000, decoded[0]["amount"])
+	assert.EqualValues(t, 10050, decoded[1]["amount"])
+
+	var roundTrip i18n.MoneyBag
+	require.NoError(t, json.Unmarshal(data, &roundTrip))
+	assert.Equal(t, bag.Amounts(), roundTrip.Amounts())
+}
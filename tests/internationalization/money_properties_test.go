@@ -0,0 +1,137 @@
+package internationalization_test
+
+import (
+	"math/rand"
+	"testing"
+	"testing/quick"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	i18n "golang-arch/internal/shared/domain/internationalization"
+	"golang-arch/internal/shared/domain/internationalization/invariants"
+)
+
+var propertyCurrencies = []i18n.Currency{
+	{Code: "USD", Symbol: "$", Name: "US Dollar", DecimalPlaces: 2},
+	{Code: "JPY", Symbol: "¥", Name: "Japanese Yen", DecimalPlaces: 0},
+	{Code: "BTC", Symbol: "₿", Name: "Bitcoin", DecimalPlaces: 8},
+	{Code: "ETH", Symbol: "Ξ", Name: "Ethereum", DecimalPlaces: 18},
+}
+
+// quickConfig keeps property runs deterministic across CI executions
+func quickConfig() *quick.Config {
+	return &quick.Config{MaxCount: 2000, Rand: rand.New(rand.NewSource(42))}
+}
+
+func money(amount int64, currency i18n.Currency) *i18n.Money {
+	return &i18n.Money{Amount: amount, Currency: currency}
+}
+
+func TestMoney_Allocate(t *testing.T) {
+	usd := propertyCurrencies[0]
+
+	tests := []struct {
+		name     string
+		amount   int64
+		ratios   []int64
+		expected []int64
+	}{
+		{"even split", 100, []int64{1, 1}, []int64{50, 50}},
+		{"remainder to first parts", 100, []int64{1, 1, 1}, []int64{34, 33, 33}},
+		{"weighted", 1000, []int64{70, 20, 10}, []int64{700, 200, 100}},
+		{"negative amount", -100, []int64{1, 1, 1}, []int64{-34, -33, -33}},
+		{"zero ratio receives nothing", 5, []int64{0, 1, 1}, []int64{0, 3, 2}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parts, err := money(tt.amount, usd).Allocate(tt.ratios...)
+			require.NoError(t, err)
+
+			amounts := make([]int64, len(parts))
+			for i, part := range parts {
+				amounts[i] = part.Amount
+				assert.Equal(t, "USD", part.Currency.Code)
+			}
+			assert.Equal(t, tt.expected, amounts)
+		})
+	}
+
+	_, err := money(100, usd).Allocate()
+	assert.Error(t, err)
+	_, err = money(100, usd).Allocate(0, 0)
+	assert.Error(t, err)
+	_, err = money(100, usd).Allocate(1, -1)
+	assert.Error(t, err)
+}
+
+func TestMoneyProperties(t *testing.T) {
+	for _, currency := range propertyCurrencies {
+		currency := currency
+
+		t.Run(currency.Code, func(t *testing.T) {
+			associative := func(a, b, c int64) bool {
+				return invariants.CheckAddAssociative(money(a, currency), money(b, currency), money(c, currency)) == nil
+			}
+			assert.NoError(t, quick.Check(associative, quickConfig()))
+
+			commutative := func(a, b int64) bool {
+				return invariants.CheckAddCommutative(money(a, currency), money(b, currency)) == nil
+			}
+			assert.NoError(t, quick.Check(commutative, quickConfig()))
+
+			inverse := func(a, b int64) bool {
+				return invariants.CheckSubtractInverse(money(a, currency), money(b, currency)) == nil
+			}
+			assert.NoError(t, quick.Check(inverse, quickConfig()))
+
+			allocation := func(amount int64, a, b, c uint16) bool {
+				ratios := []int64{int64(a), int64(b), int64(c)}
+				return invariants.CheckAllocationConserves(money(amount, currency), ratios, nil) == nil
+			}
+			assert.NoError(t, quick.Check(allocation, quickConfig()))
+
+			roundTrip := func(amount int64) bool {
+				return invariants.CheckDecimalRoundTrip(money(amount>>14, currency)) == nil
+			}
+			assert.NoError(t, quick.Check(roundTrip, quickConfig()))
+
+			identity := func(amount int64) bool {
+				return invariants.CheckMultiplyIdentity(money(amount, currency)) == nil
+			}
+			assert.NoError(t, quick.Check(identity, quickConfig()))
+		})
+	}
+}
+
+func TestMoneyProperties_CurrencyMismatch(t *testing.T) {
+	usd, jpy := propertyCurrencies[0], propertyCurrencies[1]
+	assert.NoError(t, invariants.CheckAddRejectsCurrencyMismatch(money(100, usd), money(100, jpy)))
+}
+
+func FuzzMoneyAllocate(f *testing.F) {
+	f.Add(int64(100), int64(1), int64(1), int64(1))
+	f.Add(int64(-1), int64(3), int64(0), int64(7))
+	f.Add(int64(9223372036854775807), int64(1), int64(2), int64(3))
+	f.Add(int64(-9223372036854775808), int64(5), int64(5), int64(1))
+
+	f.Fuzz(func(t *testing.T, amount, a, b, c int64) {
+		m := money(amount, propertyCurrencies[0])
+		if err := invariants.CheckAllocationConserves(m, []int64{a, b, c}, nil); err != nil {
+			t.Fatal(err)
+		}
+	})
+}
+
+func FuzzMoneyAddAssociative(f *testing.F) {
+	f.Add(int64(1), int64(2), int64(3))
+	f.Add(int64(9223372036854775807), int64(-1), int64(1))
+
+	f.Fuzz(func(t *testing.T, a, b, c int64) {
+		usd := propertyCurrencies[0]
+		if err := invariants.CheckAddAssociative(money(a, usd), money(b, usd), money(c, usd)); err != nil {
+			t.Fatal(err)
+		}
+	})
+}
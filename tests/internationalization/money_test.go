@@ -2,9 +2,12 @@ package internationalization
 
 import (
 	"encoding/json"
+	"errors"
 	"math"
+	"strings"
 	"testing"
 
+	"golang-arch/internal/shared/domain/domainerror"
 	"golang-arch/internal/shared/domain/internationalization"
 )
 
@@ -339,6 +342,67 @@ func TestMoney_Format(t *testing.T) {
 	}
 }
 
+func TestParseMoney(t *testing.T) {
+	tests := []struct {
+		name         string
+		input        string
+		expectError  bool
+		expected     int64
+		expectedCode string
+	}{
+		{name: "amount then code", input: "100.50 USD", expected: 10050, expectedCode: "USD"},
+		{name: "code then amount", input: "USD 100.50", expected: 10050, expectedCode: "USD"},
+		{name: "negative lowercase code", input: "-100.5 usd", expected: -10050, expectedCode: "USD"},
+		{name: "no fraction", input: "1000 JPY", expected: 1000, expectedCode: "JPY"},
+		{name: "18 decimals exact", input: "9.223372036854775807 ETH", expected: math.MaxInt64, expectedCode: "ETH"},
+		{name: "too many decimals", input: "1.5 JPY", expectError: true},
+		{name: "overflow", input: "92233720368547758.08 USD", expectError: true},
+		{name: "unsupported currency", input: "100 XXX", expectError: true},
+		{name: "missing currency", input: "100.50", expectError: true},
+		{name: "dangling point", input: "1. USD", expectError: true},
+		{name: "not a number", input: "1e3 USD", expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			money, err := internationalization.ParseMoney(tt.input)
+			if tt.expectError {
+				if err == nil {
+					t.Errorf("expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if money.Amount != tt.expected || money.Currency.Code != tt.expectedCode {
+				t.Errorf("expected %d %s, got %d %s", tt.expected, tt.expectedCode, money.Amount, money.Currency.Code)
+			}
+		})
+	}
+}
+
+func TestMoney_DecimalString(t *testing.T) {
+	usd := internationalization.Currency{Code: "USD", Symbol: "$", Name: "US Dollar", DecimalPlaces: 2}
+	eth := internationalization.Currency{Code: "ETH", Symbol: "Ξ", Name: "Ethereum", DecimalPlaces: 18}
+
+	tests := []struct {
+		money    *internationalization.Money
+		expected string
+	}{
+		{&internationalization.Money{Amount: 10050, Currency: usd}, "100.50"},
+		{&internationalization.Money{Amount: -5, Currency: usd}, "-0.05"},
+		{&internationalization.Money{Amount: 0, Currency: usd}, "0.00"},
+		{&internationalization.Money{Amount: math.MinInt64, Currency: eth}, "-9.223372036854775808"},
+	}
+
+	for _, tt := range tests {
+		if result := tt.money.DecimalString(); result != tt.expected {
+			t.Errorf("expected %s, got %s", tt.expected, result)
+		}
+	}
+}
+
 func TestMoney_MarshalJSON(t *testing.T) {
 	money := &internationalization.Money{
 		Amount:   10050, // $100.50
@@ -412,6 +476,64 @@ func TestMoney_UnmarshalJSON(t *testing.T) {
 	}
 }
 
+func TestMoney_UnmarshalJSONStrict(t *testing.T) {
+	const usd = `{"code": "USD", "symbol": "$", "name": "US Dollar", "decimal_places": 2}`
+	var legacy []string
+	defer internationalization.SetLegacyMoneyJSONHook(func(reason string) { legacy = append(legacy, reason) })()
+
+	var money internationalization.Money
+	if err := json.Unmarshal([]byte(`{"amount": 100.5, "currency": `+usd+`}`), &money); err != nil {
+		t.Fatalf("lenient mode: unexpected error: %v", err)
+	}
+	if money.Amount != 10050 || len(legacy) != 1 || legacy[0] != internationalization.LegacyDecimalAmount {
+		t.Errorf("lenient mode: expected 10050 and one legacy decode, got %d and %v", money.Amount, legacy)
+	}
+
+	restore := internationalization.SetStrictMoneyJSON(true)
+	tests := []struct {
+		name        string
+		json        string
+		expectError bool
+	}{
+		{name: "minor units", json: `{"amount": 10050, "currency": ` + usd + `}`},
+		{name: "matching decimal", json: `{"amount": 10050, "decimal": 100.5, "currency": ` + usd + `}`},
+		{name: "fractional amount", json: `{"amount": 100.5, "currency": ` + usd + `}`, expectError: true},
+		{name: "float amount", json: `{"amount": 10050.0, "currency": ` + usd + `}`, expectError: true},
+		{name: "mismatched decimal", json: `{"amount": 100, "decimal": 100, "currency": ` + usd + `}`, expectError: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var money internationalization.Money
+			err := json.Unmarshal([]byte(tt.json), &money)
+			if tt.expectError && !errors.Is(err, domainerror.Invalid) {
+				t.Errorf("expected an invalid error, got %v", err)
+			}
+			if !tt.expectError && (err != nil || money.Amount != 10050) {
+				t.Errorf("expected 10050, got %d (%v)", money.Amount, err)
+			}
+		})
+	}
+	restore()
+	if len(legacy) != 1 {
+		t.Errorf("strict mode must not report legacy decodes, got %v", legacy)
+	}
+
+	// StrictMoney is strict whatever the global mode
+	var body struct {
+		Price internationalization.StrictMoney `json:"price"`
+	}
+	if err := json.Unmarshal([]byte(`{"price": {"amount": 100.5, "currency": `+usd+`}}`), &body); err == nil {
+		t.Errorf("StrictMoney: expected an error for a decimal amount")
+	}
+	if err := json.Unmarshal([]byte(`{"price": {"amount": 10050, "currency": `+usd+`}}`), &body); err != nil || body.Price.Amount != 10050 {
+		t.Errorf("StrictMoney: expected 10050, got %d (%v)", body.Price.Amount, err)
+	}
+	data, _ := json.Marshal(body)
+	if !strings.Contains(string(data), `"amount":10050`) {
+		t.Errorf("StrictMoney must encode like Money, got %s", data)
+	}
+}
+
 func TestMoney_Validate(t *testing.T) {
 	tests := []struct {
 		name        string
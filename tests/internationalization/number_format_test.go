@@ -0,0 +1,56 @@
+package internationalization_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	i18n "golang-arch/internal/shared/domain/internationalization"
+)
+
+func TestCurrency_FormatLocalized(t *testing.T) {
+	tests := []struct {
+		locale i18n.Locale
+		amount int64
+		code   string
+		want   string
+	}{
+		{"en-US", 123456, "USD", "$1,234.56"},
+		{"en-US", -123456, "USD", "-$1,234.56"},
+		{"en-US", 5, "USD", "$0.05"},
+		{"en-US", 100, "USD", "$1.00"},
+		{"de-DE", 123456, "EUR", "1.234,56\u00a0€"},
+		{"de-DE", -123456789, "EUR", "-1.234.567,89\u00a0€"},
+		{"de-CH", 123456, "CHF", "CHF1’234.56"},
+		{"fr-FR", 123456, "EUR", "1\u00a0234,56\u00a0€"},
+		{"nl-NL", 123456, "EUR", "€1.234,56"},
+		{"pt-BR", 123456, "BRL", "R$1.234,56"},
+		{"pt-PT", 123456, "EUR", "1\u00a0234,56\u00a0€"},
+		{"en-IN", 1234567800, "INR", "₹1,23,45,678.00"},
+		{"ja-JP", 1234, "JPY", "¥1,234"},
+		{"ja-JP", 999, "JPY", "¥999"},
+		{"id-ID", 1500000, "IDR", "Rp1.500.000"},
+		{"xx", 123456, "USD", "$1,234.56"},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.locale)+" "+tt.want, func(t *testing.T) {
+			assert.Equal(t, tt.want, currency(t, tt.code).FormatLocalized(tt.amount, tt.locale))
+		})
+	}
+}
+
+func TestCurrency_FormatLocalizedIsExact(t *testing.T) {
+	usd := currency(t, "USD")
+	assert.Equal(t, "$92,233,720,368,547,758.07", usd.FormatLocalized(math.MaxInt64, "en-US"))
+	assert.Equal(t, "-92.233.720.368.547.758,08\u00a0$", usd.FormatLocalized(math.MinInt64, "de-DE"))
+}
+
+func TestMoney_FormatLocalized(t *testing.T) {
+	money := i18n.Money{Amount: 123456, Currency: currency(t, "EUR")}
+	assert.Equal(t, "1.234,56\u00a0€", money.FormatLocalized("de-DE"))
+	assert.Equal(t, "row:1.234,56\u00a0€", string(money.Currency.AppendFormatLocalized([]byte("row:"), money.Amount, "de-DE")))
+
+	noSymbol := i18n.Currency{Code: "XTS", DecimalPlaces: 2}
+	assert.Equal(t, "XTS1,234.56", noSymbol.FormatLocalized(123456, "en-US"))
+}
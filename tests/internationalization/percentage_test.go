@@ -0,0 +1,106 @@
+package internationalization_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"golang-arch/internal/shared/domain/domainerror"
+	i18n "golang-arch/internal/shared/domain/internationalization"
+)
+
+func TestParsePercentage(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected i18n.Percentage
+		str      string
+	}{
+		{"11%", 1100, "11%"},
+		{"12.5", 1250, "12.5%"},
+		{" -0.25 % ", -25, "-0.25%"},
+		{"0.05%", 5, "0.05%"},
+		{"100%", i18n.OneHundredPercent, "100%"},
+		{"0", 0, "0%"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			p, err := i18n.ParsePercentage(tt.input)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, p)
+			assert.Equal(t, tt.str, p.String())
+		})
+	}
+
+	for _, input := range []string{"", "%", "abc", "1.234%", "1,5%"} {
+		_, err := i18n.ParsePercentage(input)
+		assert.ErrorIs(t, err, domainerror.Invalid, input)
+	}
+}
+
+func TestMoney_ApplyPercentage(t *testing.T) {
+	usd, err := i18n.NewCurrencyFromCode("USD")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		amount   int64
+		rate     i18n.Percentage
+		mode     i18n.RoundingMode
+		expected int64
+	}{
+		{"exact", 10000, 1100, i18n.RoundHalfUp, 1100},
+		{"half up", 125, 1000, i18n.RoundHalfUp, 13},            // 12.5 cents
+		{"half even", 125, 1000, i18n.RoundHalfEven, 12},        // 12.5 cents
+		{"negative half up", -125, 1000, i18n.RoundHalfUp, -13}, // refunds round symmetrically
+		{"discount", 1999, -1500, i18n.RoundHalfUp, -300},
+		{"whole", 1999, i18n.OneHundredPercent, i18n.RoundDown, 1999},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := i18n.NewMoneyFromInteger(tt.amount, *usd)
+			require.NoError(t, err)
+			result, err := m.ApplyPercentage(tt.rate, tt.mode)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, result.Amount)
+			assert.Equal(t, "USD", result.Currency.Code)
+		})
+	}
+
+	m, err := i18n.NewMoneyFromInteger(100, *usd)
+	require.NoError(t, err)
+	_, err = m.ApplyPercentage(1000, nil)
+	assert.ErrorIs(t, err, domainerror.Invalid)
+}
+
+func TestMoney_PercentageOf(t *testing.T) {
+	usd, err := i18n.NewCurrencyFromCode("USD")
+	require.NoError(t, err)
+	eur, err := i18n.NewCurrencyFromCode("EUR")
+	require.NoError(t, err)
+
+	part, _ := i18n.NewMoneyFromInteger(2500, *usd)
+	whole, _ := i18n.NewMoneyFromInteger(20000, *usd)
+	p, err := part.PercentageOf(whole)
+	require.NoError(t, err)
+	assert.Equal(t, i18n.Percentage(1250), p)
+
+	third, _ := i18n.NewMoneyFromInteger(1, *usd)
+	three, _ := i18n.NewMoneyFromInteger(3, *usd)
+	p, err = third.PercentageOf(three)
+	require.NoError(t, err)
+	assert.Equal(t, "33.33%", p.String())
+
+	zero, _ := i18n.NewMoneyFromInteger(0, *usd)
+	_, err = part.PercentageOf(zero)
+	assert.ErrorIs(t, err, domainerror.Invalid)
+
+	euros, _ := i18n.NewMoneyFromInteger(100, *eur)
+	_, err = part.PercentageOf(euros)
+	assert.ErrorIs(t, err, domainerror.Invalid)
+
+	_, err = part.PercentageOf(nil)
+	assert.ErrorIs(t, err, domainerror.Invalid)
+}
@@ -0,0 +1,82 @@
+package internationalization_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"golang-arch/internal/shared/domain/domainerror"
+	"golang-arch/internal/shared/domain/internationalization"
+)
+
+func TestParsePhone(t *testing.T) {
+	tests := []struct {
+		name    string
+		raw     string
+		region  internationalization.Country
+		e164    string
+		country internationalization.Country
+		kind    internationalization.PhoneType
+	}{
+		{"international with separators", "+1 (212) 555-0100", "", "+12125550100", "US", internationalization.PhoneFixedLineOrMobile},
+		{"canadian area code", "+1 416 555 0100", "", "+14165550100", "CA", internationalization.PhoneFixedLineOrMobile},
+		{"NANP trunk prefix", "1-800-555-0100", "US", "+18005550100", "US", internationalization.PhoneTollFree},
+		{"NANP international prefix", "011 44 20 7946 0958", "US", "+442079460958", "GB", internationalization.PhoneFixedLine},
+		{"00 prefix", "0049 30 1234567", "", "+49301234567", "DE", internationalization.PhoneFixedLine},
+		{"national with trunk prefix", "020 7946 0958", "GB", "+442079460958", "GB", internationalization.PhoneFixedLine},
+		{"national mobile", "07700 900123", "GB", "+447700900123", "GB", internationalization.PhoneMobile},
+		{"bracketed trunk prefix", "+49 (0)176 1234-5678", "", "+4917612345678", "DE", internationalization.PhoneMobile},
+		{"region is only a default", "+33 6 12 34 56 78", "DE", "+33612345678", "FR", internationalization.PhoneMobile},
+		{"leading zero without trunk prefix", "06 1234 5678", "IT", "+390612345678", "IT", internationalization.PhoneFixedLine},
+		{"kazakh +7", "+7 701 123 4567", "", "+77011234567", "KZ", internationalization.PhoneMobile},
+		{"russian trunk prefix", "8 (812) 123-45-67", "RU", "+78121234567", "RU", internationalization.PhoneFixedLine},
+		{"russian area code starting with 8", "812 123 4567", "RU", "+78121234567", "RU", internationalization.PhoneFixedLine},
+		{"indonesian mobile", "0812-3456-7890", "ID", "+6281234567890", "ID", internationalization.PhoneMobile},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parsed, err := internationalization.ParsePhone(tt.raw, tt.region)
+			require.NoError(t, err)
+			assert.Equal(t, tt.e164, parsed.E164())
+			assert.Equal(t, tt.country, parsed.Country)
+			assert.Equal(t, tt.kind, parsed.Type)
+		})
+	}
+}
+
+func TestParsePhone_Timezones(t *testing.T) {
+	parsed, err := internationalization.ParsePhone("+44 20 7946 0958", "")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Europe/London"}, parsed.Timezones)
+
+	parsed, err = internationalization.ParsePhone("+1 212 555 0100", "")
+	require.NoError(t, err)
+	assert.Contains(t, parsed.Timezones, "America/New_York")
+	assert.Contains(t, parsed.Timezones, "America/Los_Angeles")
+}
+
+func TestParsePhone_Invalid(t *testing.T) {
+	tests := []struct {
+		name   string
+		raw    string
+		region internationalization.Country
+	}{
+		{"empty", "", "US"},
+		{"letters", "+1 212 CALL NOW", ""},
+		{"national without region", "020 7946 0958", ""},
+		{"region without plan", "0123456789", "AQ"},
+		{"unsupported calling code", "+999 1234 5678", ""},
+		{"too short", "+44 20 7946", ""},
+		{"too long", "+1 212 555 01001", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := internationalization.ParsePhone(tt.raw, tt.region)
+			require.Error(t, err)
+			assert.ErrorIs(t, err, domainerror.Invalid)
+		})
+	}
+}
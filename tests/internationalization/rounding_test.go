@@ -0,0 +1,278 @@
+package internationalization_test
+
+import (
+	"math"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"golang-arch/internal/shared/domain/domainerror"
+	i18n "golang-arch/internal/shared/domain/internationalization"
+	"golang-arch/internal/shared/domain/internationalization/invariants"
+)
+
+func TestRoundingModes(t *testing.T) {
+	// Each row divides num by 10, so the fractional part is num%10 tenths
+	quotients := []int64{25, 15, 11, 19, -11, -15, -25, 20}
+
+	tests := []struct {
+		mode i18n.RoundingMode
+		want []int64
+	}{
+		{i18n.RoundHalfUp, []int64{3, 2, 1, 2, -1, -2, -3, 2}},
+		{i18n.RoundHalfDown, []int64{2, 1, 1, 2, -1, -1, -2, 2}},
+		{i18n.RoundHalfEven, []int64{2, 2, 1, 2, -1, -2, -2, 2}},
+		{i18n.RoundUp, []int64{3, 2, 2, 2, -2, -2, -3, 2}},
+		{i18n.RoundDown, []int64{2, 1, 1, 1, -1, -1, -2, 2}},
+		{i18n.RoundCeiling, []int64{3, 2, 2, 2, -1, -1, -2, 2}},
+		{i18n.RoundFloor, []int64{2, 1, 1, 1, -2, -2, -3, 2}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.mode.Name(), func(t *testing.T) {
+			for i, num := range quotients {
+				got := tt.mode.Round(big.NewInt(num), big.NewInt(10))
+				assert.Equal(t, tt.want[i], got.Int64(), "%d/10", num)
+			}
+		})
+	}
+}
+
+func TestRoundingModes_DoNotModifyArguments(t *testing.T) {
+	num, den := big.NewInt(-25), big.NewInt(10)
+	i18n.RoundHalfEven.Round(num, den)
+	i18n.NewIncrementRounding("nickel", 5, i18n.RoundHalfUp).Round(num, den)
+
+	assert.Equal(t, int64(-25), num.Int64())
+	assert.Equal(t, int64(10), den.Int64())
+}
+
+func TestMoney_RoundIncrement(t *testing.T) {
+	chf := i18n.Currency{Code: "CHF", Symbol: "CHF", Name: "Swiss Franc", DecimalPlaces: 2}
+	cash := i18n.NewIncrementRounding("chf_cash_test", 5, i18n.RoundHalfUp)
+
+	tests := []struct {
+		amount int64
+		want   int64
+	}{
+		{1233, 1235},
+		{1232, 1230},
+		{1237, 1235},
+		{1238, 1240},
+		{-1233, -1235},
+		{1235, 1235},
+	}
+
+	for _, tt := range tests {
+		rounded, err := money(tt.amount, chf).Round(cash)
+		require.NoError(t, err)
+		assert.Equal(t, tt.want, rounded.Amount, "amount %d", tt.amount)
+		assert.Equal(t, "CHF", rounded.Currency.Code)
+	}
+
+	// Plain modes leave whole minor units alone
+	same, err := money(1233, chf).Round(i18n.RoundCeiling)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1233), same.Amount)
+
+	_, err = money(1233, chf).Round(nil)
+	assert.ErrorIs(t, err, domainerror.Invalid)
+}
+
+func TestMoney_RoundOverflow(t *testing.T) {
+	usd := propertyCurrencies[0]
+	_, err := money(9223372036854775807, usd).Round(i18n.NewIncrementRounding("dollar_up", 100, i18n.RoundUp))
+	assert.ErrorIs(t, err, domainerror.Invalid)
+}
+
+func TestMoney_Divide(t *testing.T) {
+	usd := propertyCurrencies[0]
+
+	tests := []struct {
+		amount, divisor int64
+		mode            i18n.RoundingMode
+		want            int64
+	}{
+		{1000, 3, i18n.RoundHalfUp, 333},
+		{1000, 3, i18n.RoundCeiling, 334},
+		{10, 4, i18n.RoundHalfUp, 3},
+		{10, 4, i18n.RoundHalfEven, 2},
+		{30, 4, i18n.RoundHalfEven, 8},
+		{-10, 4, i18n.RoundHalfUp, -3},
+		{-10, 4, i18n.RoundFloor, -3},
+		{-10, 4, i18n.RoundCeiling, -2},
+		{10, -4, i18n.RoundFloor, -3},
+		{10, -4, i18n.RoundCeiling, -2},
+		{1200, 4, i18n.RoundDown, 300},
+	}
+
+	for _, tt := range tests {
+		quotient, err := money(tt.amount, usd).Divide(tt.divisor, tt.mode)
+		require.NoError(t, err)
+		assert.Equal(t, tt.want, quotient.Amount, "%d / %d %s", tt.amount, tt.divisor, tt.mode.Name())
+		assert.Equal(t, "USD", quotient.Currency.Code)
+	}
+
+	_, err := money(1000, usd).Divide(0, i18n.RoundHalfUp)
+	assert.ErrorIs(t, err, domainerror.Invalid)
+	_, err = money(1000, usd).Divide(3, nil)
+	assert.ErrorIs(t, err, domainerror.Invalid)
+	_, err = money(math.MinInt64, usd).Divide(-1, i18n.RoundHalfUp)
+	assert.ErrorIs(t, err, domainerror.Invalid)
+}
+
+func TestNewIncrementRounding_PanicsOnInvalidIncrement(t *testing.T) {
+	assert.Panics(t, func() { i18n.NewIncrementRounding("broken", 0, i18n.RoundHalfUp) })
+}
+
+func TestMoney_AllocateWith(t *testing.T) {
+	usd := propertyCurrencies[0]
+
+	tests := []struct {
+		name     string
+		strategy i18n.AllocationStrategy
+		amount   int64
+		ratios   []int64
+		want     []int64
+	}{
+		{"round robin", i18n.AllocateRoundRobin, 100, []int64{1, 1, 1}, []int64{34, 33, 33}},
+		{"round robin skips zero ratios", i18n.AllocateRoundRobin, 5, []int64{0, 1, 1}, []int64{0, 3, 2}},
+		{"largest remainder", i18n.AllocateLargestRemainder, 100, []int64{1, 1, 1}, []int64{34, 33, 33}},
+		{"largest remainder favors fractions", i18n.AllocateLargestRemainder, 10, []int64{3, 3, 4}, []int64{3, 3, 4}},
+		{"largest remainder picks biggest fraction", i18n.AllocateLargestRemainder, 100, []int64{2, 5, 3}, []int64{20, 50, 30}},
+		{"largest remainder uneven", i18n.AllocateLargestRemainder, 7, []int64{1, 2, 4}, []int64{1, 2, 4}},
+		{"largest remainder skips small fractions", i18n.AllocateLargestRemainder, 11, []int64{1, 3, 3}, []int64{1, 5, 5}},
+		{"largest remainder negative", i18n.AllocateLargestRemainder, -11, []int64{1, 3, 3}, []int64{-1, -5, -5}},
+		{"to last", i18n.AllocateToLast, 100, []int64{1, 1, 1}, []int64{33, 33, 34}},
+		{"to last skips trailing zero", i18n.AllocateToLast, 100, []int64{1, 1, 1, 0}, []int64{33, 33, 34, 0}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parts, err := money(tt.amount, usd).AllocateWith(tt.strategy, tt.ratios...)
+			require.NoError(t, err)
+
+			amounts := make([]int64, len(parts))
+			for i, part := range parts {
+				amounts[i] = part.Amount
+			}
+			assert.Equal(t, tt.want, amounts)
+		})
+	}
+}
+
+func TestMoney_AllocateMatchesRoundRobin(t *testing.T) {
+	usd := propertyCurrencies[0]
+	for _, ratios := range [][]int64{{1, 1, 1}, {0, 7, 3}, {5}, {2, 0, 0, 9}} {
+		want, err := money(-1001, usd).AllocateWith(i18n.AllocateRoundRobin, ratios...)
+		require.NoError(t, err)
+		got, err := money(-1001, usd).Allocate(ratios...)
+		require.NoError(t, err)
+		assert.Equal(t, want, got)
+	}
+}
+
+// brokenAllocation drops every remainder, violating conservation
+type brokenAllocation struct{}
+
+func (brokenAllocation) Name() string { return "broken" }
+
+func (brokenAllocation) Allocate(amount int64, ratios []int64, total int64) ([]int64, error) {
+	shares := make([]int64, len(ratios))
+	for i, ratio := range ratios {
+		shares[i] = amount * ratio / total
+	}
+	return shares, nil
+}
+
+func TestMoney_AllocateWithRejectsNonConservingStrategy(t *testing.T) {
+	usd := propertyCurrencies[0]
+
+	_, err := money(100, usd).AllocateWith(brokenAllocation{}, 1, 1, 1)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `"broken"`)
+
+	_, err = money(100, usd).AllocateWith(nil, 1)
+	assert.ErrorIs(t, err, domainerror.Invalid)
+	_, err = money(100, usd).AllocateWith(i18n.AllocateLargestRemainder)
+	assert.ErrorIs(t, err, domainerror.Invalid)
+}
+
+func TestRoundingRegistry(t *testing.T) {
+	for _, name := range []string{"half_up", "half_down", "half_even", "up", "down", "ceiling", "floor"} {
+		mode, ok := i18n.RoundingModeByName(name)
+		require.True(t, ok, name)
+		assert.Equal(t, name, mode.Name())
+	}
+
+	// Round to whole pesos, ties toward zero. The registry is global, so only
+	// register on the first run when the test is repeated with -count.
+	ars := i18n.NewRoundingMode("ars_cash_test", func(num, den *big.Int) *big.Int {
+		return i18n.NewIncrementRounding("", 100, i18n.RoundHalfDown).Round(num, den)
+	})
+	if _, ok := i18n.RoundingModeByName(ars.Name()); !ok {
+		require.NoError(t, i18n.RegisterRoundingMode(ars))
+	}
+	assert.Contains(t, i18n.RoundingModes(), "ars_cash_test")
+
+	mode, ok := i18n.RoundingModeByName("ars_cash_test")
+	require.True(t, ok)
+	pesos := i18n.Currency{Code: "ARS", Symbol: "$", Name: "Argentine Peso", DecimalPlaces: 2}
+	rounded, err := money(12350, pesos).Round(mode)
+	require.NoError(t, err)
+	assert.Equal(t, int64(12300), rounded.Amount)
+
+	assert.ErrorIs(t, i18n.RegisterRoundingMode(ars), domainerror.Conflict)
+	assert.ErrorIs(t, i18n.RegisterRoundingMode(i18n.NewRoundingMode("half_up", nil)), domainerror.Conflict)
+	assert.ErrorIs(t, i18n.RegisterRoundingMode(nil), domainerror.Invalid)
+}
+
+// firstPartAllocation gives everything to the first part, for registry tests
+type firstPartAllocation struct{}
+
+func (firstPartAllocation) Name() string { return "first_part_test" }
+
+func (firstPartAllocation) Allocate(amount int64, ratios []int64, _ int64) ([]int64, error) {
+	shares := make([]int64, len(ratios))
+	shares[0] = amount
+	return shares, nil
+}
+
+func TestAllocationRegistry(t *testing.T) {
+	for _, name := range []string{"round_robin", "largest_remainder", "last"} {
+		strategy, ok := i18n.AllocationStrategyByName(name)
+		require.True(t, ok, name)
+		assert.Equal(t, name, strategy.Name())
+	}
+
+	if _, ok := i18n.AllocationStrategyByName("first_part_test"); !ok {
+		require.NoError(t, i18n.RegisterAllocationStrategy(firstPartAllocation{}))
+	}
+	assert.Contains(t, i18n.AllocationStrategies(), "first_part_test")
+	assert.ErrorIs(t, i18n.RegisterAllocationStrategy(firstPartAllocation{}), domainerror.Conflict)
+	assert.ErrorIs(t, i18n.RegisterAllocationStrategy(nil), domainerror.Invalid)
+
+	strategy, ok := i18n.AllocationStrategyByName("first_part_test")
+	require.True(t, ok)
+	parts, err := money(100, propertyCurrencies[0]).AllocateWith(strategy, 1, 1)
+	require.NoError(t, err)
+	assert.Equal(t, int64(100), parts[0].Amount)
+	assert.Equal(t, int64(0), parts[1].Amount)
+}
+
+func FuzzMoneyAllocateLargestRemainder(f *testing.F) {
+	f.Add(int64(100), int64(1), int64(1), int64(1))
+	f.Add(int64(-11), int64(1), int64(3), int64(3))
+	f.Add(int64(9223372036854775807), int64(1), int64(2), int64(3))
+	f.Add(int64(-9223372036854775808), int64(5), int64(5), int64(1))
+
+	allocate := invariants.StrategyAllocator(i18n.AllocateLargestRemainder)
+	f.Fuzz(func(t *testing.T, amount, a, b, c int64) {
+		m := money(amount, propertyCurrencies[0])
+		if err := invariants.CheckAllocationConserves(m, []int64{a, b, c}, allocate); err != nil {
+			t.Fatal(err)
+		}
+	})
+}
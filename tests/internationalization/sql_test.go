@@ -0,0 +1,99 @@
+package internationalization_test
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"golang-arch/internal/shared/domain/domainerror"
+
+	i18n "golang-arch/internal/shared/domain/internationalization"
+)
+
+func TestValuer_StoresPrimitives(t *testing.T) {
+	price, err := i18n.MakeMoney(10050, "USD")
+	require.NoError(t, err)
+	phone, err := i18n.MakePhone("44", "2079460958")
+	require.NoError(t, err)
+	tz, err := i18n.MakeTimezone("Europe/London")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name   string
+		valuer driver.Valuer
+		want   driver.Value
+	}{
+		{"currency", price.Currency, "USD"},
+		{"phone", phone, "+44 2079460958"},
+		{"timezone", tz, "Europe/London"},
+		{"time", i18n.Time{Epoch: rateEpoch}, int64(rateEpoch)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			value, err := tt.valuer.Value()
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, value)
+		})
+	}
+
+	value, err := price.Value()
+	require.NoError(t, err)
+	require.IsType(t, "", value)
+	var scanned i18n.Money
+	require.NoError(t, scanned.Scan(value))
+	assert.True(t, price.Equal(&scanned))
+}
+
+func TestScanner_ReadsRows(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	created := time.Date(2024, 3, 1, 12, 0, 0, 500, time.UTC)
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT amount, currency_code, phone, timezone_id, created_at, paid_at FROM payments")).
+		WillReturnRows(sqlmock.NewRows([]string{"amount", "currency_code", "phone", "timezone_id", "created_at", "paid_at"}).
+			AddRow(int64(10050), []byte("EUR"), "+44 20 7946 0958", "Europe/Berlin", created, nil))
+
+	var (
+		price     i18n.Money
+		phone     i18n.Phone
+		tz        i18n.Timezone
+		createdAt i18n.Time
+		paidAt    sql.Null[i18n.Time]
+	)
+	err = db.QueryRow("SELECT amount, currency_code, phone, timezone_id, created_at, paid_at FROM payments").
+		Scan(&price.Amount, &price.Currency, &phone, &tz, &createdAt, &paidAt)
+	require.NoError(t, err)
+
+	assert.Equal(t, int64(10050), price.Amount)
+	assert.Equal(t, "€", price.Currency.Symbol, "the currency is looked up, not just its code")
+	assert.Equal(t, "2079460958", phone.Number)
+	assert.Equal(t, "Europe/Berlin", tz.ID)
+	assert.Equal(t, created.Unix(), createdAt.Epoch)
+	assert.False(t, paidAt.Valid)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestScanner_RejectsInvalidValues(t *testing.T) {
+	var currency i18n.Currency
+	assert.ErrorIs(t, currency.Scan(nil), domainerror.Invalid)
+	assert.ErrorIs(t, currency.Scan(42), domainerror.Invalid)
+	assert.Error(t, currency.Scan("XXX"))
+
+	var tz i18n.Timezone
+	assert.Error(t, tz.Scan("Mars/Olympus"))
+
+	var epoch i18n.Time
+	assert.NoError(t, epoch.Scan([]byte("1703520000")))
+	assert.Equal(t, int64(1703520000), epoch.Epoch)
+	assert.ErrorIs(t, epoch.Scan("yesterday"), domainerror.Invalid)
+
+	var price i18n.Money
+	assert.ErrorIs(t, price.Scan("10050 USD"), domainerror.Invalid)
+}
@@ -0,0 +1,184 @@
+package internationalization_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"golang-arch/internal/shared/domain/domainerror"
+	"golang-arch/internal/shared/domain/validation"
+
+	i18n "golang-arch/internal/shared/domain/internationalization"
+)
+
+func TestLookupSubdivision(t *testing.T) {
+	ny, ok := i18n.LookupSubdivision("us-ny")
+	require.True(t, ok)
+	assert.Equal(t, "US-NY", ny.Code)
+	assert.Equal(t, i18n.Country("US"), ny.Country)
+	assert.Equal(t, "state", ny.Type)
+	assert.Equal(t, "New York", ny.Name)
+	assert.Equal(t, "NY", ny.LocalCode())
+
+	_, ok = i18n.LookupSubdivision("US-ZZ")
+	assert.False(t, ok)
+	_, ok = i18n.LookupSubdivision("NY")
+	assert.False(t, ok)
+	_, ok = i18n.LookupSubdivision("FR-75")
+	assert.False(t, ok, "no embedded data for FR")
+}
+
+func TestCountry_FindSubdivision(t *testing.T) {
+	tests := []struct {
+		country i18n.Country
+		value   string
+		want    string
+	}{
+		{"US", "New York", "US-NY"},
+		{"US", "ny", "US-NY"},
+		{"US", "US-NY", "US-NY"},
+		{"US", "  new   york ", "US-NY"},
+		{"CA", "Québec", "CA-QC"},
+		{"CA", "quebec", "CA-QC"},
+		{"DE", "Bavaria", "DE-BY"},
+		{"DE", "bayern", "DE-BY"},
+		{"DE", "Thuringen", "DE-TH"},
+		{"ID", "DKI Jakarta", "ID-JK"},
+		{"ID", "West Java", "ID-JB"},
+		{"AU", "WA", "AU-WA"},
+		{"GB", "Cymru", "GB-WLS"},
+		{"BR", "Sao Paulo", "BR-SP"},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.country)+"/"+tt.value, func(t *testing.T) {
+			subdivision, ok := tt.country.FindSubdivision(tt.value)
+			require.True(t, ok)
+			assert.Equal(t, tt.want, subdivision.Code)
+		})
+	}
+
+	_, ok := i18n.Country("US").FindSubdivision("Bavaria")
+	assert.False(t, ok)
+	_, ok = i18n.Country("US").FindSubdivision("")
+	assert.False(t, ok)
+}
+
+func TestCountry_Subdivisions(t *testing.T) {
+	us := i18n.Country("US").Subdivisions()
+	assert.Len(t, us, 56, "50 states, DC and 5 outlying areas")
+	for i := 1; i < len(us); i++ {
+		assert.Less(t, us[i-1].Code, us[i].Code)
+	}
+	for _, country := range []i18n.Country{"AU", "BR", "CA", "DE", "GB", "ID", "US"} {
+		assert.True(t, country.HasSubdivisions(), country)
+		for _, subdivision := range country.Subdivisions() {
+			assert.NoError(t, subdivision.Validate(), subdivision.Code)
+		}
+	}
+	assert.Len(t, i18n.Country("ID").Subdivisions(), 38)
+	assert.Len(t, i18n.Country("DE").Subdivisions(), 16)
+
+	assert.Nil(t, i18n.Country("FR").Subdivisions())
+	assert.False(t, i18n.Country("FR").HasSubdivisions())
+
+	// Callers cannot modify the embedded data
+	us[0].Name = "changed"
+	assert.NotEqual(t, "changed", i18n.Country("US").Subdivisions()[0].Name)
+}
+
+func TestSubdivision_LocalizedName(t *testing.T) {
+	bavaria, ok := i18n.LookupSubdivision("DE-BY")
+	require.True(t, ok)
+	assert.Equal(t, "Bavaria", bavaria.LocalizedName(i18n.MustParseLocale("en-US")))
+	assert.Equal(t, "Bayern", bavaria.LocalizedName(i18n.MustParseLocale("de-DE")))
+	assert.Equal(t, "Bayern", bavaria.LocalizedName(i18n.MustParseLocale("ja-JP")))
+
+	quebec, ok := i18n.LookupSubdivision("CA-QC")
+	require.True(t, ok)
+	assert.Equal(t, "Québec", quebec.LocalizedName(i18n.MustParseLocale("fr-CA")))
+	assert.Equal(t, "Quebec", quebec.LocalizedName(i18n.MustParseLocale("en-CA")))
+}
+
+func TestSubdivision_Validate(t *testing.T) {
+	valid := i18n.Subdivision{Code: "FR-75", Country: "FR", Name: "Paris"}
+	assert.NoError(t, valid.Validate())
+
+	invalid := i18n.Subdivision{Code: "US-NEWY", Country: "us"}
+	err := invalid.Validate()
+	require.Error(t, err)
+	assert.ElementsMatch(t, []string{"country", "code", "name"}, keys(validation.FromError(err).ByField()))
+}
+
+func TestSubdivision_Primitive(t *testing.T) {
+	subdivision, err := i18n.NewSubdivisionFromPrimitive("ID-JK")
+	require.NoError(t, err)
+	assert.Equal(t, "ID-JK", subdivision.ToPrimitive())
+
+	_, err = i18n.NewSubdivisionFromPrimitive("ID-XX")
+	assert.ErrorIs(t, err, domainerror.NotFound)
+}
+
+func TestSubdivision_JSON(t *testing.T) {
+	quebec, _ := i18n.LookupSubdivision("CA-QC")
+	data, err := json.Marshal(quebec)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"code":"CA-QC","country":"CA","type":"province","name":"Quebec","names":{"fr":"Québec"}}`, string(data))
+}
+
+func TestFindCountry(t *testing.T) {
+	tests := []struct {
+		value string
+		want  i18n.Country
+		ok    bool
+	}{
+		{"United States", "US", true},
+		{"united states of america", "US", true},
+		{"USA", "US", true},
+		{"Deutschland", "DE", true},
+		{"UK", "GB", true},
+		{"us", "US", true},
+		{"FR", "FR", true},
+		{"Atlantis", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			country, ok := i18n.FindCountry(tt.value)
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.want, country)
+		})
+	}
+}
+
+func TestLocalizedPhone_ValidateRegion(t *testing.T) {
+	phone, err := i18n.NewPhone("1", "5551234567")
+	require.NoError(t, err)
+	tz := timezone(t, "America/New_York")
+
+	tests := []struct {
+		name    string
+		country string
+		region  string
+		valid   bool
+	}{
+		{"state name", "United States", "New York", true},
+		{"state code", "US", "NY", true},
+		{"empty region", "United States", "", true},
+		{"country without data", "France", "Île-de-France", true},
+		{"unknown state", "United States", "Bavaria", false},
+		{"unknown province", "Canada", "Texas", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lp := i18n.LocalizedPhone{Phone: *phone, Country: tt.country, Region: tt.region, Timezone: tz}
+			err := lp.ValidateRegion()
+			if tt.valid {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, validation.FromError(err).ByField(), "region")
+		})
+	}
+}
@@ -0,0 +1,75 @@
+package internationalization_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	i18n "golang-arch/internal/shared/domain/internationalization"
+)
+
+func TestNormalize(t *testing.T) {
+	decomposed := "José"
+	assert.Equal(t, "José", i18n.NormalizeNFC(decomposed))
+	assert.NotEqual(t, decomposed, "José")
+
+	assert.Equal(t, "file ABC 1", i18n.NormalizeNFKC("ﬁle ＡＢＣ ¹"))
+	assert.Equal(t, "ﬁle", i18n.NormalizeNFC("ﬁle"), "NFC keeps compatibility characters")
+}
+
+func TestFoldCase(t *testing.T) {
+	assert.Equal(t, "strasse", i18n.FoldCase("STRAẞE"))
+	assert.Equal(t, "strasse", i18n.FoldCase("Straße"))
+	assert.Equal(t, "σοφοσ", i18n.FoldCase("ΣΟΦΟΣ"))
+	assert.Equal(t, "σοφοσ", i18n.FoldCase("σοφος"))
+}
+
+func TestRemoveAccents(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"José", "Jose"},
+		{"José", "Jose"},
+		{"Crème Brûlée", "Creme Brulee"},
+		{"Łódź", "Lodz"},
+		{"Søren", "Soren"},
+		{"Ёлка", "Елка"},
+		{"Ελλάδα", "Ελλαδα"},
+		{"東京", "東京"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			assert.Equal(t, tt.want, i18n.RemoveAccents(tt.in))
+		})
+	}
+}
+
+func TestSearchKey(t *testing.T) {
+	assert.Equal(t, "creme brulee", i18n.SearchKey("  Crème \t Brûlée "))
+	assert.Equal(t, "strasse", i18n.SearchKey("STRAẞE"))
+	assert.Equal(t, "file", i18n.SearchKey("ﬁle"))
+	assert.Equal(t, "", i18n.SearchKey("   "))
+}
+
+func TestEqualFold(t *testing.T) {
+	assert.True(t, i18n.EqualFold("José", "JOSÉ"))
+	assert.True(t, i18n.EqualFold("José", "josé"))
+	assert.True(t, i18n.EqualFold("Straße", "STRASSE"))
+	assert.False(t, i18n.EqualFold("José", "Jose"))
+}
+
+func TestEqualIgnoringAccents(t *testing.T) {
+	assert.True(t, i18n.EqualIgnoringAccents("José", "Jose"))
+	assert.True(t, i18n.EqualIgnoringAccents("José  García", "jose garcia"))
+	assert.True(t, i18n.EqualIgnoringAccents("Łódź", "LODZ"))
+	assert.False(t, i18n.EqualIgnoringAccents("José", "Josef"))
+}
+
+func TestContainsIgnoringAccents(t *testing.T) {
+	assert.True(t, i18n.ContainsIgnoringAccents("Café de Flore", "cafe"))
+	assert.True(t, i18n.ContainsIgnoringAccents("Café de Flore", "DE   FLORE"))
+	assert.True(t, i18n.ContainsIgnoringAccents("anything", ""))
+	assert.False(t, i18n.ContainsIgnoringAccents("Café", "caff"))
+}
@@ -0,0 +1,99 @@
+package internationalization_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"golang-arch/internal/shared/domain/domainerror"
+
+	i18n "golang-arch/internal/shared/domain/internationalization"
+)
+
+func TestTimePolicy_FromTime(t *testing.T) {
+	exact := time.Date(2024, 5, 17, 9, 30, 0, 0, time.UTC)
+	fraction := exact.Add(750 * time.Millisecond)
+	half := exact.Add(500 * time.Millisecond)
+
+	got, err := i18n.DefaultTimePolicy.FromTime(exact)
+	require.NoError(t, err)
+	assert.Equal(t, exact.Unix(), got.Epoch)
+
+	_, err = i18n.DefaultTimePolicy.FromTime(fraction)
+	assert.ErrorIs(t, err, domainerror.Invalid)
+
+	truncate := i18n.TimePolicy{SubSecond: i18n.SubSecondTruncate, LeapSecond: i18n.LeapSecondReject}
+	got, err = truncate.FromTime(fraction)
+	require.NoError(t, err)
+	assert.Equal(t, exact.Unix(), got.Epoch)
+
+	round := i18n.TimePolicy{SubSecond: i18n.SubSecondRound, LeapSecond: i18n.LeapSecondReject}
+	got, err = round.FromTime(fraction)
+	require.NoError(t, err)
+	assert.Equal(t, exact.Unix()+1, got.Epoch)
+	got, err = round.FromTime(half)
+	require.NoError(t, err)
+	assert.Equal(t, exact.Unix()+1, got.Epoch)
+
+	_, err = i18n.TimePolicy{SubSecond: "floor", LeapSecond: "smear"}.FromTime(exact)
+	assert.Error(t, err)
+}
+
+func TestTimePolicy_ParseRFC3339Nano(t *testing.T) {
+	clamp := i18n.TimePolicy{SubSecond: i18n.SubSecondTruncate, LeapSecond: i18n.LeapSecondClamp}
+	roll := i18n.TimePolicy{SubSecond: i18n.SubSecondReject, LeapSecond: i18n.LeapSecondRoll}
+
+	tests := []struct {
+		name   string
+		policy i18n.TimePolicy
+		input  string
+		want   string
+		err    bool
+	}{
+		{"exact", i18n.DefaultTimePolicy, "2024-05-17T09:30:00Z", "2024-05-17T09:30:00Z", false},
+		{"offset", i18n.DefaultTimePolicy, "2024-05-17T16:30:00+07:00", "2024-05-17T09:30:00Z", false},
+		{"zero fraction", i18n.DefaultTimePolicy, "2024-05-17T09:30:00.000Z", "2024-05-17T09:30:00Z", false},
+		{"fraction rejected", i18n.DefaultTimePolicy, "2024-05-17T09:30:00.250Z", "", true},
+		{"fraction truncated", clamp, "2024-05-17T09:30:00.999999999Z", "2024-05-17T09:30:00Z", false},
+		{"leap second rejected", i18n.DefaultTimePolicy, "2016-12-31T23:59:60Z", "", true},
+		{"leap second clamped", clamp, "2016-12-31T23:59:60.5Z", "2016-12-31T23:59:59Z", false},
+		{"leap second rolled", roll, "2016-12-31T23:59:60Z", "2017-01-01T00:00:00Z", false},
+		{"leap second with offset", roll, "2017-01-01T00:59:60+01:00", "2017-01-01T00:00:00Z", false},
+		{"leap second in June", clamp, "2015-06-30T23:59:60Z", "2015-06-30T23:59:59Z", false},
+		{"leap second at wrong time", clamp, "2016-12-31T12:59:60Z", "", true},
+		{"leap second on wrong day", clamp, "2016-11-30T23:59:60Z", "", true},
+		{"malformed", i18n.DefaultTimePolicy, "2024-05-17 09:30:00", "", true},
+		{"out of range", i18n.DefaultTimePolicy, "1969-12-31T23:59:59Z", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.policy.ParseRFC3339Nano(tt.input)
+			if tt.err {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got.FormatRFC3339Nano())
+		})
+	}
+}
+
+func TestTimePolicy_RoundTrip(t *testing.T) {
+	original := time.Date(2024, 5, 17, 9, 30, 15, 0, time.UTC)
+	formatted := i18n.NewTimeFromTime(original).FormatRFC3339Nano()
+	parsed, err := i18n.DefaultTimePolicy.ParseRFC3339Nano(formatted)
+	require.NoError(t, err)
+	assert.Equal(t, original.Unix(), parsed.Epoch)
+}
+
+func TestTimePolicy_JSON(t *testing.T) {
+	var policy i18n.TimePolicy
+	require.NoError(t, json.Unmarshal([]byte(`{"sub_second":"round","leap_second":"clamp"}`), &policy))
+	assert.NoError(t, policy.Validate())
+	assert.Equal(t, "sub_second=round leap_second=clamp", policy.String())
+
+	assert.Error(t, i18n.TimePolicy{}.Validate())
+}
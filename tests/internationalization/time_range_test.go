@@ -0,0 +1,158 @@
+package internationalization_test
+
+import (
+	"encoding/json"
+	"math/rand"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	i18n "golang-arch/internal/shared/domain/internationalization"
+)
+
+// baseEpoch is 2024-05-17T00:00:00Z
+const baseEpoch int64 = 1715904000
+
+func timeRange(t *testing.T, startMinutes, endMinutes int64) i18n.TimeRange {
+	t.Helper()
+	r, err := i18n.NewTimeRangeFromPrimitive(baseEpoch+startMinutes*60, baseEpoch+endMinutes*60)
+	require.NoError(t, err)
+	return *r
+}
+
+func TestNewTimeRange(t *testing.T) {
+	r := timeRange(t, 60, 90)
+	assert.Equal(t, 30*time.Minute, r.Duration())
+	assert.Equal(t, "2024-05-17T01:00:00Z/2024-05-17T01:30:00Z", r.String())
+
+	start, end := r.ToPrimitive()
+	assert.Equal(t, baseEpoch+3600, start)
+	assert.Equal(t, baseEpoch+5400, end)
+
+	_, err := i18n.NewTimeRangeFromPrimitive(baseEpoch, baseEpoch)
+	assert.Error(t, err, "empty range")
+	_, err = i18n.NewTimeRangeFromPrimitive(baseEpoch, baseEpoch-1)
+	assert.Error(t, err, "reversed range")
+	_, err = i18n.NewTimeRangeFromPrimitive(-10, baseEpoch)
+	assert.Error(t, err, "invalid start")
+}
+
+func TestTimeRange_Relations(t *testing.T) {
+	r := timeRange(t, 60, 120)
+
+	assert.True(t, r.Contains(i18n.Time{Epoch: baseEpoch + 3600}))
+	assert.False(t, r.Contains(i18n.Time{Epoch: baseEpoch + 7200}), "end is exclusive")
+
+	assert.True(t, r.Overlaps(timeRange(t, 90, 150)))
+	assert.True(t, r.Overlaps(timeRange(t, 0, 180)))
+	assert.False(t, r.Overlaps(timeRange(t, 120, 180)), "adjacent ranges do not overlap")
+	assert.False(t, r.Overlaps(timeRange(t, 0, 60)))
+
+	overlap, ok := r.Intersect(timeRange(t, 90, 150))
+	assert.True(t, ok)
+	assert.Equal(t, timeRange(t, 90, 120), overlap)
+	_, ok = r.Intersect(timeRange(t, 120, 150))
+	assert.False(t, ok)
+}
+
+func TestTimeRange_JSON(t *testing.T) {
+	r := timeRange(t, 0, 60)
+	data, err := json.Marshal(r)
+	require.NoError(t, err)
+
+	var decoded i18n.TimeRange
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, r, decoded)
+
+	assert.Error(t, json.Unmarshal([]byte(`{"start":{"epoch":1715904000},"end":{"epoch":1715904000}}`), &decoded))
+}
+
+func TestTimeRangeTree(t *testing.T) {
+	tree := i18n.NewTimeRangeTree[string]()
+	require.NoError(t, tree.Insert("standup", timeRange(t, 540, 555)))
+	require.NoError(t, tree.Insert("review", timeRange(t, 600, 660)))
+	require.NoError(t, tree.Insert("lunch", timeRange(t, 720, 780)))
+	require.NoError(t, tree.Insert("offsite", timeRange(t, 480, 1020)))
+	assert.Equal(t, 4, tree.Len())
+
+	keys := func(entries []i18n.TimeRangeEntry[string]) []string {
+		var result []string
+		for _, entry := range entries {
+			result = append(result, entry.Key)
+		}
+		return result
+	}
+
+	assert.Equal(t, []string{"offsite", "review", "lunch"}, keys(tree.QueryOverlapping(timeRange(t, 630, 750))))
+	assert.Equal(t, []string{"offsite"}, keys(tree.QueryOverlapping(timeRange(t, 555, 600))), "touching bounds do not conflict")
+	assert.Empty(t, tree.QueryOverlapping(timeRange(t, 1020, 1080)))
+	assert.Equal(t, []string{"offsite", "standup"}, keys(tree.QueryPoint(i18n.Time{Epoch: baseEpoch + 545*60})))
+	assert.True(t, tree.HasOverlap(timeRange(t, 1000, 1100)))
+	assert.False(t, tree.HasOverlap(timeRange(t, 0, 480)))
+
+	// Re-inserting a key moves it
+	require.NoError(t, tree.Insert("lunch", timeRange(t, 1080, 1140)))
+	assert.Equal(t, 4, tree.Len())
+	got, ok := tree.Get("lunch")
+	assert.True(t, ok)
+	assert.Equal(t, timeRange(t, 1080, 1140), got)
+	assert.Equal(t, []string{"offsite", "review"}, keys(tree.QueryOverlapping(timeRange(t, 630, 750))))
+
+	assert.True(t, tree.Remove("offsite"))
+	assert.False(t, tree.Remove("offsite"))
+	assert.Equal(t, []string{"standup", "review", "lunch"}, keys(tree.Entries()))
+
+	assert.Error(t, tree.Insert("broken", i18n.TimeRange{}))
+	assert.Equal(t, 3, tree.Len())
+}
+
+func TestTimeRangeTree_MatchesBruteForce(t *testing.T) {
+	rng := rand.New(rand.NewSource(42))
+	tree := i18n.NewTimeRangeTree[int]()
+	stored := map[int]i18n.TimeRange{}
+
+	randomRange := func() i18n.TimeRange {
+		start := rng.Int63n(10_000)
+		return timeRange(t, start, start+1+rng.Int63n(240))
+	}
+
+	for i := 0; i < 5_000; i++ {
+		key := rng.Intn(2_000)
+		if rng.Intn(4) == 0 {
+			_, exists := stored[key]
+			assert.Equal(t, exists, tree.Remove(key))
+			delete(stored, key)
+			continue
+		}
+		r := randomRange()
+		require.NoError(t, tree.Insert(key, r))
+		stored[key] = r
+	}
+	require.Equal(t, len(stored), tree.Len())
+
+	for i := 0; i < 500; i++ {
+		q := randomRange()
+
+		var want []int
+		for key, r := range stored {
+			if r.Overlaps(q) {
+				want = append(want, key)
+			}
+		}
+		var got []int
+		entries := tree.QueryOverlapping(q)
+		for j, entry := range entries {
+			got = append(got, entry.Key)
+			if j > 0 {
+				assert.LessOrEqual(t, entries[j-1].Range.Start.Epoch, entry.Range.Start.Epoch, "ordered by start")
+			}
+		}
+		sort.Ints(want)
+		sort.Ints(got)
+		assert.Equal(t, want, got)
+		assert.Equal(t, len(want) > 0, tree.HasOverlap(q))
+	}
+}
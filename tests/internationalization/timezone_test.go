@@ -1,6 +1,7 @@
 package internationalization_test
 
 import (
+	"sync"
 	"testing"
 	"time"
 
@@ -457,3 +458,70 @@ func TestTimezone_String(t *testing.T) {
 
 	assert.Equal(t, expected, actual)
 }
+
+func TestTimezones(t *testing.T) {
+	zones := i18n.Timezones()
+	assert.NotEmpty(t, zones)
+	assert.Equal(t, "UTC", zones[0].ID)
+	for _, zone := range zones[1:] {
+		assert.NoError(t, zone.Country.Validate(), zone.ID)
+	}
+	assert.Contains(t, zones, i18n.TimezoneEntry{ID: "Asia/Jakarta", Country: "ID", Comment: "Java, Sumatra"})
+}
+
+func TestTimezone_DisplayName(t *testing.T) {
+	tests := []struct {
+		id       string
+		locale   i18n.Locale
+		expected string
+	}{
+		{"Europe/Berlin", "en-US", "Central European Time"},
+		{"Europe/Berlin", "de-DE", "Mitteleuropäische Zeit"},
+		{"Europe/Paris", "fr", "heure d’Europe centrale"},
+		{"America/New_York", "es-MX", "hora oriental"},
+		{"Asia/Tokyo", "ja-JP", "日本時間"},
+		{"Asia/Shanghai", "zh-CN", "中国时间"},
+		{"Asia/Shanghai", "zh-TW", "China Time"}, // No Traditional Chinese data
+		{"UTC", "de", "Koordinierte Weltzeit"},
+		{"Africa/Casablanca", "en", "Casablanca Time"},
+		{"Africa/Casablanca", "de", "Casablanca (Ortszeit)"},
+		{"America/Santiago", "fr", "heure : Santiago"},
+		{"America/Sao_Paulo", "nl-NL", "Brasilia Time"}, // No Dutch data
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.id+"/"+string(tt.locale), func(t *testing.T) {
+			tz, err := i18n.NewTimezoneFromID(tt.id)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, tz.DisplayName(tt.locale))
+		})
+	}
+}
+
+func TestTimezone_DisplayName_Concurrent(t *testing.T) {
+	tz, err := i18n.NewTimezoneFromID("Europe/Madrid")
+	assert.NoError(t, err)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 16; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for _, locale := range []i18n.Locale{"en", "de", "es-ES", "ja"} {
+				assert.NotEmpty(t, tz.DisplayName(locale))
+			}
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, "hora de Europa central", tz.DisplayName("es-ES"))
+}
+
+func TestNewTimezoneFromID_CLDRName(t *testing.T) {
+	berlin, err := i18n.NewTimezoneFromID("Europe/Berlin")
+	assert.NoError(t, err)
+	assert.Equal(t, "Central European Time", berlin.Name)
+
+	tokyo, err := i18n.NewTimezoneFromID("Asia/Tokyo")
+	assert.NoError(t, err)
+	assert.Equal(t, "Japan Standard Time", tokyo.Name)
+}
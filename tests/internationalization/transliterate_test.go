@@ -0,0 +1,57 @@
+package internationalization_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	i18n "golang-arch/internal/shared/domain/internationalization"
+)
+
+func TestTransliterate(t *testing.T) {
+	tests := []struct {
+		text   string
+		locale i18n.Locale
+		want   string
+	}{
+		{"Grüße aus Köln", "de-DE", "Gruesse aus Koeln"},
+		{"Grüße aus Köln", "en", "Grusse aus Koln"},
+		{"Ærø Å", "da", "Aeroe Aa"},
+		{"Ærø Å", "fr", "AEro A"},
+		{"Crème brûlée", "fr-FR", "Creme brulee"},
+		{"Łódź", "pl", "Lodz"},
+		{"ﬁle №1", "en", "file No1"},
+		{"Москва", "ru", "Moskva"},
+		{"Київ", "uk", "Kyiv"},
+		{"Αθήνα", "el", "Athina"},
+		{"東京 Tokyo", "ja", " Tokyo"},
+		{"Café", "en", "Cafe"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.text+" "+string(tt.locale), func(t *testing.T) {
+			assert.Equal(t, tt.want, i18n.Transliterate(tt.text, tt.locale))
+		})
+	}
+}
+
+func TestSlugify(t *testing.T) {
+	tests := []struct {
+		text   string
+		locale i18n.Locale
+		want   string
+	}{
+		{"Hello, World!", "en", "hello-world"},
+		{"  Crème Brûlée & Co.  ", "fr-FR", "creme-brulee-co"},
+		{"Schöne Grüße", "de", "schoene-gruesse"},
+		{"L'Été — 2024", "fr", "lete-2024"},
+		{"Joe’s Diner", "en", "joes-diner"},
+		{"Блины с икрой", "ru", "bliny-s-ikroy"},
+		{"東京", "ja", ""},
+		{"東京 2020", "ja", "2020"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			assert.Equal(t, tt.want, i18n.Slugify(tt.text, tt.locale))
+		})
+	}
+}
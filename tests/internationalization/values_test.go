@@ -0,0 +1,95 @@
+package internationalization_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	i18n "golang-arch/internal/shared/domain/internationalization"
+)
+
+func TestMakeConstructors_MatchPointerConstructors(t *testing.T) {
+	money, err := i18n.MakeMoney(10050, "USD")
+	require.NoError(t, err)
+	moneyPtr, err := i18n.NewMoneyFromPrimitive(10050, "USD")
+	require.NoError(t, err)
+	assert.Equal(t, *moneyPtr, money)
+
+	tm, err := i18n.MakeTime(1703520000)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1703520000), tm.Epoch)
+
+	tz, err := i18n.MakeTimezone("Asia/Tokyo")
+	require.NoError(t, err)
+	assert.Equal(t, "Asia/Tokyo", tz.ID)
+
+	phone, err := i18n.MakePhone("1", "5551234567")
+	require.NoError(t, err)
+	assert.Equal(t, "+1 5551234567", phone.String())
+
+	ldt, err := i18n.MakeLocalizedDateTime(1703520000, "Asia/Tokyo")
+	require.NoError(t, err)
+	ldtPtr, err := i18n.NewLocalizedDateTimeFromPrimitive(1703520000, "Asia/Tokyo")
+	require.NoError(t, err)
+	assert.Equal(t, *ldtPtr, ldt)
+}
+
+func TestMakeConstructors_Errors(t *testing.T) {
+	_, err := i18n.MakeMoney(100, "XXX")
+	assert.Error(t, err)
+
+	_, err = i18n.MakeTime(-1)
+	assert.Error(t, err)
+
+	_, err = i18n.MakeTimezone("Mars/Olympus")
+	assert.Error(t, err)
+
+	_, err = i18n.MakePhone("", "123")
+	assert.Error(t, err)
+
+	_, err = i18n.MakeLocalizedDateTime(1703520000, "Mars/Olympus")
+	assert.Error(t, err)
+}
+
+func TestValueSemantics_SlicesAndMaps(t *testing.T) {
+	prices := make([]i18n.Money, 0, 3)
+	for _, amount := range []int64{100, 250, 999} {
+		price, err := i18n.MakeMoney(amount, "EUR")
+		require.NoError(t, err)
+		prices = append(prices, price)
+	}
+
+	total := prices[0]
+	for _, price := range prices[1:] {
+		sum, err := total.Add(&price)
+		require.NoError(t, err)
+		total = *sum
+	}
+	assert.Equal(t, int64(1349), total.Amount)
+	assert.Equal(t, int64(100), prices[0].Amount, "Add must not mutate its receiver")
+
+	byCode := map[string]i18n.Money{"EUR": total}
+	assert.Equal(t, "€13.49", byCode["EUR"].Format())
+	assert.True(t, prices[1].IsPositive())
+}
+
+func TestValueSemantics_JSONOfNonAddressableValues(t *testing.T) {
+	money, err := i18n.MakeMoney(10050, "USD")
+	require.NoError(t, err)
+	ldt, err := i18n.MakeLocalizedDateTime(1703520000, "UTC")
+	require.NoError(t, err)
+
+	// Map values are not addressable, so only value-receiver marshalers apply.
+	data, err := json.Marshal(map[string]any{"price": money, "at": ldt})
+	require.NoError(t, err)
+
+	moneyJSON, err := money.MarshalJSON()
+	require.NoError(t, err)
+	ldtJSON, err := ldt.MarshalJSON()
+	require.NoError(t, err)
+
+	assert.JSONEq(t, `{"price":`+string(moneyJSON)+`,"at":`+string(ldtJSON)+`}`, string(data))
+	assert.Contains(t, string(data), `"decimal":100.5`)
+}
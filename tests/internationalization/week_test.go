@@ -0,0 +1,163 @@
+package internationalization_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"golang-arch/internal/shared/domain/domainerror"
+
+	i18n "golang-arch/internal/shared/domain/internationalization"
+)
+
+func TestParseCountry(t *testing.T) {
+	country, err := i18n.ParseCountry(" id ")
+	require.NoError(t, err)
+	assert.Equal(t, i18n.Country("ID"), country)
+
+	for _, code := range []string{"", "I", "IDN", "1D"} {
+		_, err := i18n.ParseCountry(code)
+		assert.ErrorIs(t, err, domainerror.Invalid, code)
+	}
+	assert.ErrorIs(t, i18n.Country("id").Validate(), domainerror.Invalid)
+
+	assert.Equal(t, i18n.Country("TW"), i18n.MustParseLocale("zh-Hant-TW").Country())
+	assert.Equal(t, i18n.Country(""), i18n.MustParseLocale("es-419").Country())
+	assert.Equal(t, i18n.Country(""), i18n.MustParseLocale("en").Country())
+}
+
+func TestCountry_Week(t *testing.T) {
+	tests := []struct {
+		country  i18n.Country
+		firstDay time.Weekday
+		weekend  []time.Weekday
+	}{
+		{"DE", time.Monday, []time.Weekday{time.Saturday, time.Sunday}},
+		{"US", time.Sunday, []time.Weekday{time.Saturday, time.Sunday}},
+		{"AE", time.Saturday, []time.Weekday{time.Friday, time.Saturday}},
+		{"IL", time.Sunday, []time.Weekday{time.Friday, time.Saturday}},
+		{"IR", time.Saturday, []time.Weekday{time.Friday}},
+		{"IN", time.Sunday, []time.Weekday{time.Sunday}},
+		{"AF", time.Saturday, []time.Weekday{time.Thursday, time.Friday}},
+		{"MV", time.Friday, []time.Weekday{time.Saturday, time.Sunday}},
+		{"ZZ", time.Monday, []time.Weekday{time.Saturday, time.Sunday}},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.country), func(t *testing.T) {
+			week := tt.country.Week()
+			assert.Equal(t, tt.firstDay, week.FirstDay)
+			assert.Equal(t, tt.weekend, week.Weekend())
+			for day := time.Sunday; day <= time.Saturday; day++ {
+				assert.Equal(t, contains(tt.weekend, day), week.IsWeekend(day), day.String())
+			}
+		})
+	}
+
+	assert.Equal(t, i18n.Country("AE").Week(), i18n.MustParseLocale("ar-AE").Week())
+	assert.Equal(t, i18n.DefaultWeek, i18n.MustParseLocale("ar").Week())
+}
+
+func contains(days []time.Weekday, day time.Weekday) bool {
+	for _, d := range days {
+		if d == day {
+			return true
+		}
+	}
+	return false
+}
+
+func TestWeekDefinition_StartOfWeek(t *testing.T) {
+	jakarta, _ := time.LoadLocation("Asia/Jakarta")
+	wednesday := time.Date(2024, 7, 3, 15, 30, 0, 0, jakarta)
+
+	assert.Equal(t, time.Date(2024, 7, 1, 0, 0, 0, 0, jakarta), i18n.DefaultWeek.StartOfWeek(wednesday))
+	assert.Equal(t, time.Date(2024, 6, 30, 0, 0, 0, 0, jakarta), i18n.Country("ID").Week().StartOfWeek(wednesday))
+	assert.Equal(t, time.Date(2024, 6, 29, 0, 0, 0, 0, jakarta), i18n.Country("AE").Week().StartOfWeek(wednesday))
+
+	sunday := time.Date(2024, 6, 30, 9, 0, 0, 0, jakarta)
+	assert.Equal(t, time.Date(2024, 6, 24, 0, 0, 0, 0, jakarta), i18n.DefaultWeek.StartOfWeek(sunday))
+	assert.Equal(t, time.Date(2024, 6, 30, 0, 0, 0, 0, jakarta), i18n.Country("US").Week().StartOfWeek(sunday))
+}
+
+func TestWeekDefinition_AddBusinessDays(t *testing.T) {
+	thursday := time.Date(2024, 7, 4, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		week i18n.WeekDefinition
+		from time.Time
+		n    int
+		want time.Time
+	}{
+		{"over the weekend", i18n.DefaultWeek, thursday, 2, time.Date(2024, 7, 8, 10, 0, 0, 0, time.UTC)},
+		{"friday-saturday weekend", i18n.Country("AE").Week(), thursday, 2, time.Date(2024, 7, 8, 10, 0, 0, 0, time.UTC)},
+		{"friday-saturday weekend, one day", i18n.Country("AE").Week(), thursday, 1, time.Date(2024, 7, 7, 10, 0, 0, 0, time.UTC)},
+		{"backwards", i18n.DefaultWeek, time.Date(2024, 7, 8, 10, 0, 0, 0, time.UTC), -1, time.Date(2024, 7, 5, 10, 0, 0, 0, time.UTC)},
+		{"from a weekend", i18n.DefaultWeek, time.Date(2024, 7, 6, 10, 0, 0, 0, time.UTC), 1, time.Date(2024, 7, 8, 10, 0, 0, 0, time.UTC)},
+		{"zero", i18n.DefaultWeek, time.Date(2024, 7, 6, 10, 0, 0, 0, time.UTC), 0, time.Date(2024, 7, 6, 10, 0, 0, 0, time.UTC)},
+		{"ten days", i18n.DefaultWeek, thursday, 10, time.Date(2024, 7, 18, 10, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.week.AddBusinessDays(tt.from, tt.n)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	_, err := i18n.WeekDefinition{FirstDay: time.Monday, WeekendStart: time.Monday, WeekendEnd: time.Sunday}.AddBusinessDays(thursday, 1)
+	assert.ErrorIs(t, err, domainerror.Invalid)
+	_, err = i18n.WeekDefinition{FirstDay: 9}.AddBusinessDays(thursday, 1)
+	assert.ErrorIs(t, err, domainerror.Invalid)
+}
+
+func TestWeekDefinition_AddBusinessDaysKeepsWallClockAcrossDST(t *testing.T) {
+	berlin, _ := time.LoadLocation("Europe/Berlin")
+	friday := time.Date(2024, 3, 29, 9, 0, 0, 0, berlin) // clocks change on Sunday the 31st
+
+	monday, err := i18n.DefaultWeek.AddBusinessDays(friday, 1)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2024, 4, 1, 9, 0, 0, 0, berlin), monday)
+	assert.Equal(t, 71*time.Hour, monday.Sub(friday))
+}
+
+func TestWeekDefinition_BusinessDaysBetween(t *testing.T) {
+	monday := time.Date(2024, 7, 1, 23, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, 0, i18n.DefaultWeek.BusinessDaysBetween(monday, monday))
+	assert.Equal(t, 5, i18n.DefaultWeek.BusinessDaysBetween(monday, monday.AddDate(0, 0, 7)))
+	assert.Equal(t, 4, i18n.DefaultWeek.BusinessDaysBetween(monday, monday.AddDate(0, 0, 4)))
+	assert.Equal(t, 13, i18n.DefaultWeek.BusinessDaysBetween(monday, monday.AddDate(0, 0, 17)))
+	assert.Equal(t, -5, i18n.DefaultWeek.BusinessDaysBetween(monday.AddDate(0, 0, 7), monday))
+	assert.Equal(t, 6, i18n.Country("IN").Week().BusinessDaysBetween(monday, monday.AddDate(0, 0, 7)))
+
+	// Calendar dates are taken in from's location
+	jakarta, _ := time.LoadLocation("Asia/Jakarta")
+	tuesdayJakarta := monday.In(jakarta) // already Tuesday 06:00 in Jakarta; to is Saturday there
+	assert.Equal(t, 4, i18n.DefaultWeek.BusinessDaysBetween(tuesdayJakarta, monday.AddDate(0, 0, 4)))
+}
+
+func TestLocalizedDateTime_Week(t *testing.T) {
+	ldt, err := i18n.NewLocalizedDateTimeFromPrimitive(time.Date(2024, 7, 5, 20, 0, 0, 0, time.UTC).Unix(), "Asia/Dubai")
+	require.NoError(t, err)
+	week := i18n.Country("AE").Week()
+
+	// Saturday 00:00 in Dubai
+	start, err := ldt.StartOfWeek(week)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2024, 7, 6, 0, 0, 0, 0, time.FixedZone("", 4*3600)).Unix(), start.Time.Epoch)
+	assert.Equal(t, "Asia/Dubai", start.Timezone.ID)
+
+	// Saturday in Dubai plus one business day is Sunday, same wall-clock time
+	next, err := ldt.AddBusinessDays(week, 1)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2024, 7, 7, 0, 0, 0, 0, time.FixedZone("", 4*3600)).Unix(), next.Time.Epoch)
+}
+
+func TestResolveLocalePreferences_FirstDayFromLocale(t *testing.T) {
+	prefs := i18n.ResolveLocalePreferences(defaultPreferences(t), i18n.PartialLocalePreferences{Locale: "de-DE"})
+	assert.Equal(t, time.Monday, prefs.FirstDayOfWeek)
+	assert.Equal(t, i18n.MeasurementMetric, prefs.MeasurementSystem)
+}
@@ -0,0 +1,184 @@
+package jobs_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"golang-arch/internal/shared/domain/domainerror"
+	"golang-arch/internal/shared/geo"
+	"golang-arch/internal/shared/jobs"
+	"golang-arch/internal/shared/templates"
+	"golang-arch/internal/shared/testutil"
+)
+
+func newStore(t *testing.T) *jobs.Store {
+	t.Helper()
+	_, client := testutil.NewRedis(t)
+	return jobs.NewStore(client, jobs.WithPollInterval(5*time.Millisecond))
+}
+
+func TestStore_Lifecycle(t *testing.T) {
+	store := newStore(t)
+	ctx := context.Background()
+
+	record, err := store.Create(ctx, "export")
+	require.NoError(t, err)
+	assert.Len(t, record.ID, 32)
+	assert.Equal(t, jobs.StatePending, record.State)
+	assert.Equal(t, int64(1), record.Version)
+
+	require.NoError(t, store.SetProgress(ctx, record.ID, jobs.Progress{Done: 50, Total: 200}))
+	require.NoError(t, store.Complete(ctx, record.ID, map[string]string{"url": "https://files.example.com/export.csv"}))
+
+	record, err = store.Get(ctx, record.ID)
+	require.NoError(t, err)
+	assert.Equal(t, jobs.StateSucceeded, record.State)
+	assert.Equal(t, int64(3), record.Version)
+	assert.Equal(t, int64(50), record.Progress.Done)
+	assert.JSONEq(t, `{"url": "https://files.example.com/export.csv"}`, string(record.Result))
+
+	err = store.Fail(ctx, record.ID, errors.New("too late"))
+	assert.ErrorIs(t, err, domainerror.Conflict, "final records are not changed")
+
+	_, err = store.Get(ctx, "missing")
+	assert.ErrorIs(t, err, domainerror.NotFound)
+}
+
+func TestStore_Wait(t *testing.T) {
+	store := newStore(t)
+	ctx := context.Background()
+	record, err := store.Create(ctx, "export")
+	require.NoError(t, err)
+
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		_ = store.Start(ctx, record.ID)
+	}()
+	waited, err := store.Wait(ctx, record.ID, record.Version)
+	require.NoError(t, err)
+	assert.Equal(t, jobs.StateRunning, waited.State)
+	assert.Equal(t, int64(2), waited.Version)
+
+	// An ended wait answers with the current record
+	short, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+	defer cancel()
+	waited, err = store.Wait(short, record.ID, waited.Version)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), waited.Version)
+}
+
+func newRouter(t *testing.T, store *jobs.Store, location *geo.Location) *gin.Engine {
+	t.Helper()
+	views, err := templates.NewEngine()
+	require.NoError(t, err)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	if location != nil {
+		router.Use(func(c *gin.Context) {
+			c.Request = c.Request.WithContext(geo.NewContext(c.Request.Context(), *location))
+		})
+	}
+	jobs.NewHandler(store, views, time.Second).Register(router.Group("/api/v1"))
+	return router
+}
+
+func getStatus(t *testing.T, router *gin.Engine, path string) (int, jobs.Status) {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+	var body struct {
+		Data jobs.Status `json:"data"`
+	}
+	if rec.Code == http.StatusOK {
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body), rec.Body.String())
+	}
+	return rec.Code, body.Data
+}
+
+func TestHandler_LongPoll(t *testing.T) {
+	store := newStore(t)
+	router := newRouter(t, store, &geo.Location{Country: "DE", Timezone: "Europe/Berlin", Locale: "de-DE", Detected: true})
+	ctx := context.Background()
+	record, err := store.Create(ctx, "export")
+	require.NoError(t, err)
+
+	code, status := getStatus(t, router, "/api/v1/jobs/"+record.ID)
+	require.Equal(t, http.StatusOK, code)
+	assert.Equal(t, jobs.StatePending, status.State)
+	assert.Equal(t, "Wartet auf den Start…", status.Message)
+
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		_ = store.SetProgress(ctx, record.ID, jobs.Progress{Done: 1500, Total: 6000})
+	}()
+	code, status = getStatus(t, router, "/api/v1/jobs/"+record.ID+"?version=1")
+	require.Equal(t, http.StatusOK, code)
+	assert.Equal(t, int64(2), status.Version)
+	require.NotNil(t, status.Percent)
+	assert.Equal(t, 25, *status.Percent)
+	assert.Equal(t, "1.500 von 6.000 erledigt", status.Message)
+
+	// Nothing changes: the poll answers with the job after wait
+	started := time.Now()
+	code, status = getStatus(t, router, "/api/v1/jobs/"+record.ID+"?version=2&wait=50ms")
+	require.Equal(t, http.StatusOK, code)
+	assert.Equal(t, int64(2), status.Version)
+	assert.GreaterOrEqual(t, time.Since(started), 50*time.Millisecond)
+
+	code, _ = getStatus(t, router, "/api/v1/jobs/unknown")
+	assert.Equal(t, http.StatusNotFound, code)
+	for _, query := range []string{"version=-1", "version=x", "wait=2s", "wait=soon"} {
+		code, _ = getStatus(t, router, "/api/v1/jobs/"+record.ID+"?"+query)
+		assert.Equal(t, http.StatusBadRequest, code, query)
+	}
+}
+
+func TestHandler_Events(t *testing.T) {
+	store := newStore(t)
+	router := newRouter(t, store, nil)
+	ctx := context.Background()
+	record, err := store.Create(ctx, "export")
+	require.NoError(t, err)
+
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		_ = store.SetProgress(ctx, record.ID, jobs.Progress{Done: 10})
+		time.Sleep(20 * time.Millisecond)
+		_ = store.Fail(ctx, record.ID, errors.New("the report has no rows"))
+	}()
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/jobs/"+record.ID+"/events", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "text/event-stream", rec.Header().Get("Content-Type"))
+
+	var statuses []jobs.Status
+	scanner := bufio.NewScanner(strings.NewReader(rec.Body.String()))
+	for scanner.Scan() {
+		if data, ok := strings.CutPrefix(scanner.Text(), "data:"); ok {
+			var status jobs.Status
+			require.NoError(t, json.Unmarshal([]byte(data), &status))
+			statuses = append(statuses, status)
+		}
+	}
+	require.Len(t, statuses, 3, "the stream ends with the final state")
+	assert.Equal(t, "Waiting to start…", statuses[0].Message)
+	assert.Equal(t, "In progress…", statuses[1].Message)
+	assert.Equal(t, jobs.StateFailed, statuses[2].State)
+	assert.Equal(t, "the report has no rows", statuses[2].Error)
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/jobs/unknown/events", nil))
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
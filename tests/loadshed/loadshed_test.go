@@ -0,0 +1,167 @@
+package loadshed_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"golang-arch/internal/shared/api"
+	"golang-arch/internal/shared/loadshed"
+	"golang-arch/pkg/clock"
+	"golang-arch/pkg/metrics"
+)
+
+var now = time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+
+func newLimiter(t *testing.T, opts loadshed.Options, options ...loadshed.Option) *loadshed.Limiter {
+	t.Helper()
+	limiter, err := loadshed.NewLimiter(opts, options...)
+	require.NoError(t, err)
+	return limiter
+}
+
+func TestOptionsValidate(t *testing.T) {
+	assert.NoError(t, loadshed.Options{MaxInFlight: 10, MaxQueue: 5, QueueTimeout: time.Second}.Validate())
+	assert.Error(t, loadshed.Options{}.Validate())
+	assert.Error(t, loadshed.Options{MaxInFlight: 10, MinInFlight: 11}.Validate())
+	assert.Error(t, loadshed.Options{MaxInFlight: 10, MaxQueue: -1}.Validate())
+	assert.Error(t, loadshed.Options{MaxInFlight: 10, MaxQueue: 5}.Validate(), "a queue needs a timeout")
+}
+
+func TestAcquireShedsWhenQueueFull(t *testing.T) {
+	registry := metrics.NewRegistry()
+	shed, err := registry.Counter("shed", "", "")
+	require.NoError(t, err)
+	inFlight, err := registry.Gauge("inflight", "", "")
+	require.NoError(t, err)
+	queued, err := registry.Gauge("queued", "", "")
+	require.NoError(t, err)
+	limit, err := registry.Gauge("limit", "", "")
+	require.NoError(t, err)
+	limiter := newLimiter(t, loadshed.Options{MaxInFlight: 2}, loadshed.WithMetrics(shed, inFlight, queued, limit))
+	ctx := context.Background()
+
+	require.NoError(t, limiter.Acquire(ctx))
+	require.NoError(t, limiter.Acquire(ctx))
+	err = limiter.Acquire(ctx)
+	assert.ErrorIs(t, err, loadshed.ErrOverloaded)
+	assert.ErrorContains(t, err, loadshed.ReasonQueueFull)
+	assert.Equal(t, 2, limiter.InFlight())
+
+	limiter.Release(time.Millisecond)
+	assert.NoError(t, limiter.Acquire(ctx))
+
+	for _, instrument := range registry.Snapshot() {
+		switch instrument.Name {
+		case "shed":
+			require.Len(t, instrument.Series, 1)
+			assert.Equal(t, metrics.Labels{"reason": loadshed.ReasonQueueFull}, instrument.Series[0].Labels)
+			assert.Equal(t, float64(1), instrument.Series[0].Value)
+		case "inflight":
+			assert.Equal(t, float64(2), instrument.Series[0].Value)
+		}
+	}
+}
+
+func TestAcquireQueuesUntilReleased(t *testing.T) {
+	clk := clock.NewFake(now)
+	limiter := newLimiter(t, loadshed.Options{MaxInFlight: 1, MaxQueue: 1, QueueTimeout: time.Second},
+		loadshed.WithClock(clk))
+	ctx := context.Background()
+	require.NoError(t, limiter.Acquire(ctx))
+
+	done := make(chan error, 1)
+	go func() { done <- limiter.Acquire(ctx) }()
+	require.Eventually(t, func() bool { return clk.PendingTimers() == 1 }, time.Second, time.Millisecond)
+
+	// The queue holds one request, so the next one is shed at once
+	assert.ErrorIs(t, limiter.Acquire(ctx), loadshed.ErrOverloaded)
+
+	limiter.Release(time.Millisecond)
+	assert.NoError(t, <-done)
+	assert.Equal(t, 1, limiter.InFlight())
+}
+
+func TestAcquireQueueTimeout(t *testing.T) {
+	clk := clock.NewFake(now)
+	limiter := newLimiter(t, loadshed.Options{MaxInFlight: 1, MaxQueue: 1, QueueTimeout: time.Second},
+		loadshed.WithClock(clk))
+	ctx := context.Background()
+	require.NoError(t, limiter.Acquire(ctx))
+
+	done := make(chan error, 1)
+	go func() { done <- limiter.Acquire(ctx) }()
+	require.Eventually(t, func() bool { return clk.PendingTimers() == 1 }, time.Second, time.Millisecond)
+	clk.Advance(time.Second)
+
+	err := <-done
+	assert.ErrorIs(t, err, loadshed.ErrOverloaded)
+	assert.ErrorContains(t, err, loadshed.ReasonQueueTimeout)
+
+	// The timed out request left the queue, so the slot goes unused
+	limiter.Release(time.Millisecond)
+	assert.Equal(t, 0, limiter.InFlight())
+}
+
+func TestAcquireCanceledWhileQueued(t *testing.T) {
+	limiter := newLimiter(t, loadshed.Options{MaxInFlight: 1, MaxQueue: 1, QueueTimeout: time.Minute})
+	require.NoError(t, limiter.Acquire(context.Background()))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.ErrorIs(t, limiter.Acquire(ctx), context.Canceled)
+}
+
+func TestAdaptiveLimit(t *testing.T) {
+	limiter := newLimiter(t, loadshed.Options{MaxInFlight: 10, MinInFlight: 5, TargetLatency: 100 * time.Millisecond})
+	ctx := context.Background()
+
+	// Slow requests shrink the limit down to the floor
+	for range 20 {
+		require.NoError(t, limiter.Acquire(ctx))
+		limiter.Release(time.Second)
+	}
+	assert.Equal(t, 5, limiter.Limit())
+
+	// Fast requests grow it back, never past the maximum
+	for range 100 {
+		require.NoError(t, limiter.Acquire(ctx))
+		limiter.Release(time.Millisecond)
+	}
+	assert.Equal(t, 10, limiter.Limit())
+}
+
+func TestMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	limiter := newLimiter(t, loadshed.Options{MaxInFlight: 1})
+	require.NoError(t, limiter.Acquire(context.Background()))
+
+	router := gin.New()
+	router.Use(limiter.Middleware(2*time.Second, "/health"))
+	router.GET("/health", func(c *gin.Context) { c.Status(http.StatusOK) })
+	router.GET("/api/v1/users", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/users", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.Equal(t, "2", w.Header().Get("Retry-After"))
+	assert.Contains(t, w.Body.String(), `"code":"`+api.ErrCodeUnavailable+`"`)
+
+	// Health checks bypass the limit
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/health", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	// Admitted requests release their slot
+	limiter.Release(time.Millisecond)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/users", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, 0, limiter.InFlight())
+}
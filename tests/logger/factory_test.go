@@ -0,0 +1,85 @@
+package logger_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+
+	"golang-arch/pkg/logger"
+)
+
+func TestFactory_LevelFor(t *testing.T) {
+	factory, err := logger.NewFactory("info", "json", "worker=warn, worker.jobs=debug,api.http=error")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name string
+		want zapcore.Level
+	}{
+		{"", zapcore.InfoLevel},
+		{"rates", zapcore.InfoLevel},
+		{"worker", zapcore.WarnLevel},
+		{"worker.outbox", zapcore.WarnLevel},
+		{"worker.jobs", zapcore.DebugLevel},
+		{"worker.jobs.rates", zapcore.DebugLevel},
+		{"workers", zapcore.InfoLevel},
+		{"api", zapcore.InfoLevel},
+		{"api.http", zapcore.ErrorLevel},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, factory.LevelFor(tt.name))
+		})
+	}
+
+	// Named loggers are built with their resolved level
+	assert.True(t, factory.Named("worker.jobs").Core().Enabled(zapcore.DebugLevel))
+	assert.False(t, factory.Named("worker").Core().Enabled(zapcore.InfoLevel))
+	assert.Same(t, factory.Named("worker"), factory.Named("worker"))
+}
+
+func TestNewFactory_Levels(t *testing.T) {
+	tests := []struct {
+		name    string
+		level   string
+		levels  string
+		wantErr string
+	}{
+		{name: "empty base level is info", level: ""},
+		{name: "upper case", level: "WARN", levels: "api.http=DEBUG"},
+		{name: "blank entries", level: "info", levels: " , api.http=debug ,"},
+		{name: "unknown base level", level: "verbose", wantErr: "verbose"},
+		{name: "unknown override level", level: "info", levels: "api.http=loud", wantErr: "invalid log level for api.http"},
+		{name: "override without level", level: "info", levels: "api.http", wantErr: "expected name=level"},
+		{name: "override without name", level: "info", levels: "=debug", wantErr: "expected name=level"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			factory, err := logger.NewFactory(tt.level, "console", tt.levels)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.NotNil(t, factory.Root())
+		})
+	}
+}
+
+func TestParseLevelOverrides(t *testing.T) {
+	overrides, err := logger.ParseLevelOverrides("worker.jobs = debug, api.http=warn")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]zapcore.Level{"worker.jobs": zapcore.DebugLevel, "api.http": zapcore.WarnLevel}, overrides)
+
+	overrides, err = logger.ParseLevelOverrides("")
+	require.NoError(t, err)
+	assert.Empty(t, overrides)
+}
+
+func TestNewNopFactory(t *testing.T) {
+	factory := logger.NewNopFactory()
+	assert.Equal(t, zapcore.InfoLevel, factory.LevelFor("worker.jobs"))
+	assert.False(t, factory.Named("worker.jobs").Core().Enabled(zapcore.ErrorLevel))
+}
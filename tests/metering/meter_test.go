@@ -0,0 +1,293 @@
+package metering_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"golang-arch/internal/shared/config"
+	"golang-arch/internal/shared/metering"
+	"golang-arch/internal/shared/testutil"
+	"golang-arch/pkg/clock"
+)
+
+var start = time.Date(2024, 1, 31, 22, 0, 0, 0, time.UTC)
+
+func testConfig() config.MeteringConfig {
+	return config.MeteringConfig{
+		Enabled:       true,
+		DefaultPlan:   "free",
+		AnonymousPlan: "anonymous",
+		Plans: map[string]config.MeteringPlan{
+			"free": {
+				Period:   "month",
+				Requests: 5,
+				Endpoints: []config.MeteringEndpointQuota{
+					{Route: "post /api/v1/otp/request", Requests: 2},
+				},
+			},
+			"pro":       {Period: "day", Requests: 100},
+			"anonymous": {Period: "day", Requests: 3},
+		},
+		Consumers: []config.MeteringConsumerPlan{
+			{ID: "tenant-pro", Plan: "Pro"},
+			{ID: metering.ConsumerID("k1"), Plan: "free"},
+			{ID: metering.ConsumerID("k2"), Plan: "free"},
+		},
+	}
+}
+
+type fixture struct {
+	meter *metering.Meter
+	store *metering.MemoryStore
+	redis *miniredis.Miniredis
+	clock *clock.Fake
+}
+
+func newFixture(t *testing.T) *fixture {
+	t.Helper()
+	server, client := testutil.NewRedis(t)
+
+	plans, err := metering.NewStaticPlans(testConfig())
+	require.NoError(t, err)
+	f := &fixture{store: metering.NewMemoryStore(), redis: server, clock: clock.NewFake(start)}
+	f.meter = metering.NewMeter(client, plans, f.store, metering.WithClock(f.clock))
+	return f
+}
+
+func TestPeriodBounds(t *testing.T) {
+	at := time.Date(2024, 2, 29, 23, 30, 0, 0, time.FixedZone("UTC+2", 2*3600))
+
+	id, from, to := metering.PeriodMonth.Bounds(at)
+	assert.Equal(t, "2024-02", id)
+	assert.Equal(t, time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC), from)
+	assert.Equal(t, time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), to)
+
+	id, from, to = metering.PeriodDay.Bounds(at)
+	assert.Equal(t, "2024-02-29", id)
+	assert.Equal(t, time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC), from)
+	assert.Equal(t, time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), to)
+}
+
+func TestNewStaticPlans(t *testing.T) {
+	plans, err := metering.NewStaticPlans(testConfig())
+	require.NoError(t, err)
+
+	pro, err := plans.Plan(context.Background(), "tenant-pro")
+	require.NoError(t, err)
+	assert.Equal(t, "pro", pro.Name)
+	assert.Equal(t, metering.PeriodDay, pro.Period)
+
+	free, err := plans.Plan(context.Background(), "anyone")
+	require.NoError(t, err)
+	assert.Equal(t, "free", free.Name, "authenticated consumers get the default plan")
+	assert.Equal(t, int64(2), free.EndpointLimit("POST /api/v1/otp/request"))
+
+	keyed, err := plans.Plan(context.Background(), metering.ConsumerID("k1"))
+	require.NoError(t, err)
+	assert.Equal(t, "free", keyed.Name)
+	_, err = plans.Plan(context.Background(), metering.ConsumerID("made-up"))
+	assert.ErrorIs(t, err, metering.ErrUnknownConsumer, "API keys never fall back to the default plan")
+
+	anonymous, err := plans.Plan(context.Background(), metering.AnonymousConsumer)
+	require.NoError(t, err)
+	assert.Equal(t, "anonymous", anonymous.Name)
+
+	unlimited, err := metering.NewStaticPlans(config.MeteringConfig{})
+	require.NoError(t, err)
+	plan, err := unlimited.Plan(context.Background(), "anyone")
+	require.NoError(t, err)
+	assert.Equal(t, metering.Unlimited, plan)
+	plan, err = unlimited.Plan(context.Background(), metering.AnonymousConsumer)
+	require.NoError(t, err)
+	assert.Equal(t, metering.Unlimited, plan)
+}
+
+func TestNewStaticPlansRejectsBadConfig(t *testing.T) {
+	tests := map[string]func(*config.MeteringConfig){
+		"unknown period": func(c *config.MeteringConfig) { c.Plans["free"] = config.MeteringPlan{Period: "week"} },
+		"malformed route": func(c *config.MeteringConfig) {
+			c.Plans["free"] = config.MeteringPlan{Endpoints: []config.MeteringEndpointQuota{{Route: "/x"}}}
+		},
+		"unknown consumer plan":  func(c *config.MeteringConfig) { c.Consumers[0].Plan = "gold" },
+		"unknown default plan":   func(c *config.MeteringConfig) { c.DefaultPlan = "gold" },
+		"unknown anonymous plan": func(c *config.MeteringConfig) { c.AnonymousPlan = "gold" },
+	}
+	for name, mutate := range tests {
+		t.Run(name, func(t *testing.T) {
+			cfg := testConfig()
+			mutate(&cfg)
+			_, err := metering.NewStaticPlans(cfg)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestRecordEnforcesEndpointQuota(t *testing.T) {
+	f := newFixture(t)
+	ctx := context.Background()
+	const route = "POST /api/v1/otp/request"
+
+	for want := int64(1); want >= 0; want-- {
+		decision, err := f.meter.Record(ctx, "c1", route)
+		require.NoError(t, err)
+		assert.True(t, decision.Allowed)
+		assert.Equal(t, int64(2), decision.Limit)
+		assert.Equal(t, want, decision.Remaining)
+		assert.Equal(t, time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC), decision.Reset)
+	}
+
+	decision, err := f.meter.Record(ctx, "c1", route)
+	require.NoError(t, err)
+	assert.False(t, decision.Allowed)
+	assert.Equal(t, "endpoint", decision.Exceeded)
+
+	// Other endpoints still have the plan's remaining requests
+	decision, err = f.meter.Record(ctx, "c1", "GET /api/v1/usage")
+	require.NoError(t, err)
+	assert.True(t, decision.Allowed)
+	assert.Equal(t, int64(5), decision.Limit)
+	assert.Equal(t, int64(2), decision.Remaining)
+}
+
+func TestRecordEnforcesPlanQuota(t *testing.T) {
+	f := newFixture(t)
+	ctx := context.Background()
+
+	for i := 0; i < 5; i++ {
+		decision, err := f.meter.Record(ctx, "c1", "GET /api/v1/usage")
+		require.NoError(t, err)
+		require.True(t, decision.Allowed)
+	}
+	decision, err := f.meter.Record(ctx, "c1", "GET /api/v1/usage")
+	require.NoError(t, err)
+	assert.False(t, decision.Allowed)
+	assert.Equal(t, "plan", decision.Exceeded)
+	assert.Zero(t, decision.Remaining)
+
+	// Rejected requests are not counted and the next period starts afresh
+	usage, err := f.meter.Usage(ctx, "c1")
+	require.NoError(t, err)
+	assert.Equal(t, int64(5), usage.Requests)
+
+	f.clock.Advance(2 * time.Hour)
+	decision, err = f.meter.Record(ctx, "c1", "GET /api/v1/usage")
+	require.NoError(t, err)
+	assert.True(t, decision.Allowed)
+	assert.Equal(t, int64(4), decision.Remaining)
+}
+
+func TestRecordSetsExpiry(t *testing.T) {
+	f := newFixture(t)
+	_, err := f.meter.Record(context.Background(), "c1", "GET /api/v1/usage")
+	require.NoError(t, err)
+
+	// Two hours to the end of January plus a day of grace for the flush
+	assert.Equal(t, 26*time.Hour, f.redis.TTL("metering:usage:2024-01:c1"))
+}
+
+func TestUsage(t *testing.T) {
+	f := newFixture(t)
+	ctx := context.Background()
+	_, err := f.meter.Record(ctx, "c1", "GET /api/v1/usage")
+	require.NoError(t, err)
+
+	usage, err := f.meter.Usage(ctx, "c1")
+	require.NoError(t, err)
+	assert.Equal(t, "free", usage.Plan)
+	assert.Equal(t, "2024-01", usage.Period)
+	assert.Equal(t, int64(1), usage.Requests)
+	assert.Equal(t, int64(5), usage.Limit)
+	require.NotNil(t, usage.Reset)
+	assert.Equal(t, []metering.EndpointUsage{
+		{Route: "GET /api/v1/usage", Requests: 1},
+		{Route: "POST /api/v1/otp/request", Requests: 0, Limit: 2},
+	}, usage.Endpoints)
+}
+
+func TestFlushAndHistory(t *testing.T) {
+	f := newFixture(t)
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		_, err := f.meter.Record(ctx, "c1", "GET /api/v1/usage")
+		require.NoError(t, err)
+	}
+	require.NoError(t, f.meter.Flush(ctx))
+
+	f.clock.Advance(2 * time.Hour)
+	_, err := f.meter.Record(ctx, "c1", "POST /api/v1/otp/request")
+	require.NoError(t, err)
+	require.NoError(t, f.meter.Flush(ctx))
+	// Nothing changed, nothing to flush
+	require.NoError(t, f.meter.Flush(ctx))
+
+	history, err := f.meter.History(ctx, "c1", 12)
+	require.NoError(t, err)
+	require.Len(t, history, 2)
+	assert.Equal(t, "2024-02", history[0].Period)
+	assert.Equal(t, int64(1), history[0].Requests)
+	assert.Equal(t, []metering.EndpointUsage{{Route: "POST /api/v1/otp/request", Requests: 1, Limit: 2}}, history[0].Endpoints)
+	assert.Equal(t, "2024-01", history[1].Period)
+	assert.Equal(t, int64(3), history[1].Requests)
+
+	history, err = f.meter.History(ctx, "c1", 1)
+	require.NoError(t, err)
+	require.Len(t, history, 1)
+	assert.Equal(t, "2024-02", history[0].Period)
+}
+
+// failingStore rejects every save
+type failingStore struct {
+	*metering.MemoryStore
+}
+
+func (*failingStore) Save(context.Context, []metering.Row) error {
+	return assert.AnError
+}
+
+func TestFlushRequeuesOnFailure(t *testing.T) {
+	server, client := testutil.NewRedis(t)
+	plans, err := metering.NewStaticPlans(testConfig())
+	require.NoError(t, err)
+	meter := metering.NewMeter(client, plans, &failingStore{metering.NewMemoryStore()}, metering.WithClock(clock.NewFake(start)))
+
+	_, err = meter.Record(context.Background(), "c1", "GET /api/v1/usage")
+	require.NoError(t, err)
+	assert.ErrorIs(t, meter.Flush(context.Background()), assert.AnError)
+
+	members, err := server.Members("metering:dirty")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"metering:usage:2024-01:c1"}, members)
+}
+
+func TestPostgresStore(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+	store := metering.NewPostgresStore(db)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`INSERT INTO api_usage .* GREATEST`).
+		WithArgs("c1", "2024-01", "*", int64(3)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	require.NoError(t, store.Save(context.Background(), []metering.Row{
+		{Consumer: "c1", Period: "2024-01", Endpoint: "*", Requests: 3},
+	}))
+
+	mock.ExpectQuery(`SELECT consumer, period, endpoint, requests FROM api_usage`).
+		WithArgs("c1", 2).
+		WillReturnRows(sqlmock.NewRows([]string{"consumer", "period", "endpoint", "requests"}).
+			AddRow("c1", "2024-02", "*", 1).
+			AddRow("c1", "2024-01", "*", 3))
+	rows, err := store.History(context.Background(), "c1", 2)
+	require.NoError(t, err)
+	assert.Len(t, rows, 2)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
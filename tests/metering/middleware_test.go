@@ -0,0 +1,136 @@
+package metering_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"golang-arch/internal/shared/api"
+	"golang-arch/internal/shared/metering"
+)
+
+func newRouter(f *fixture) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	v1 := router.Group("/api/v1")
+	v1.Use(f.meter.Middleware(""))
+	metering.NewHandler(f.meter, "").Register(v1)
+	v1.POST("/otp/request", func(c *gin.Context) { c.Status(http.StatusNoContent) })
+	v1.GET("/tenant", func(c *gin.Context) { c.Status(http.StatusNoContent) })
+	return router
+}
+
+func request(router http.Handler, method, path, apiKey string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, nil)
+	if apiKey != "" {
+		req.Header.Set(metering.DefaultHeader, apiKey)
+	}
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestConsumerID(t *testing.T) {
+	id := metering.ConsumerID("secret-key")
+	assert.Regexp(t, `^key_[0-9a-f]{16}$`, id)
+	assert.NotContains(t, id, "secret")
+	assert.Equal(t, id, metering.ConsumerID("secret-key"))
+}
+
+func TestMiddlewareQuotaHeadersAndRejection(t *testing.T) {
+	router := newRouter(newFixture(t))
+
+	rec := request(router, http.MethodPost, "/api/v1/otp/request", "k1")
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	assert.Equal(t, "2", rec.Header().Get(metering.HeaderQuotaLimit))
+	assert.Equal(t, "1", rec.Header().Get(metering.HeaderQuotaRemaining))
+	assert.Equal(t, "1706745600", rec.Header().Get(metering.HeaderQuotaReset))
+
+	request(router, http.MethodPost, "/api/v1/otp/request", "k1")
+	rec = request(router, http.MethodPost, "/api/v1/otp/request", "k1")
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+	assert.Equal(t, "7200", rec.Header().Get("Retry-After"))
+
+	var body api.Response
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	assert.Equal(t, api.ErrCodeQuotaExceeded, body.Code)
+
+	// Another key has its own quota
+	rec = request(router, http.MethodPost, "/api/v1/otp/request", "k2")
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+}
+
+func TestMiddlewareMetersAnonymousRequests(t *testing.T) {
+	router := newRouter(newFixture(t))
+	for i := 0; i < 3; i++ {
+		rec := request(router, http.MethodGet, "/api/v1/tenant", "")
+		assert.Equal(t, http.StatusNoContent, rec.Code)
+		assert.Equal(t, "3", rec.Header().Get(metering.HeaderQuotaLimit))
+	}
+
+	// Requests without a key share one quota
+	rec := request(router, http.MethodPost, "/api/v1/otp/request", "")
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+}
+
+func TestMiddlewareRejectsUnknownKeys(t *testing.T) {
+	router := newRouter(newFixture(t))
+
+	rec := request(router, http.MethodGet, "/api/v1/tenant", "made-up")
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	assert.Empty(t, rec.Header().Get(metering.HeaderQuotaLimit))
+
+	rec = request(router, http.MethodGet, "/api/v1/usage", "made-up")
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+}
+
+func TestMiddlewareFailsOpen(t *testing.T) {
+	f := newFixture(t)
+	router := newRouter(f)
+	f.redis.Close()
+
+	rec := request(router, http.MethodPost, "/api/v1/otp/request", "k1")
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+}
+
+func TestSetConsumerTakesPrecedence(t *testing.T) {
+	f := newFixture(t)
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	v1 := router.Group("/api/v1")
+	v1.Use(func(c *gin.Context) { metering.SetConsumer(c, "tenant-pro") })
+	v1.Use(f.meter.Middleware(""))
+	v1.GET("/tenant", func(c *gin.Context) { c.Status(http.StatusNoContent) })
+
+	rec := request(router, http.MethodGet, "/api/v1/tenant", "k1")
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	assert.Equal(t, "100", rec.Header().Get(metering.HeaderQuotaLimit))
+}
+
+func TestUsageEndpoints(t *testing.T) {
+	f := newFixture(t)
+	router := newRouter(f)
+
+	request(router, http.MethodPost, "/api/v1/otp/request", "k1")
+	rec := request(router, http.MethodGet, "/api/v1/usage", "k1")
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var body struct {
+		Data metering.Usage `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	assert.Equal(t, metering.ConsumerID("k1"), body.Data.Consumer)
+	// The usage request itself is counted before the handler reads
+	assert.Equal(t, int64(2), body.Data.Requests)
+
+	rec = request(router, http.MethodGet, "/api/v1/usage/history?periods=0", "k1")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	rec = request(router, http.MethodGet, "/api/v1/usage", "")
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+}
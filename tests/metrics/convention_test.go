@@ -0,0 +1,67 @@
+package metrics_test
+
+import (
+	"io"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"golang-arch/pkg/metrics"
+)
+
+func scrape(t *testing.T, provider *metrics.Provider) string {
+	t.Helper()
+	recorder := httptest.NewRecorder()
+	provider.Handler().ServeHTTP(recorder, httptest.NewRequest("GET", "/metrics", nil))
+	body, err := io.ReadAll(recorder.Body)
+	require.NoError(t, err)
+	return string(body)
+}
+
+func TestProvider_IdentityLabels(t *testing.T) {
+	provider, err := metrics.NewProvider(metrics.Options{
+		Exporter: metrics.ExporterPrometheus,
+		Identity: metrics.Identity{Service: "golang-arch", Env: "staging", Version: "v1.4.0"},
+	})
+	require.NoError(t, err)
+
+	provider.Instruments.HTTPRequests.Inc(metrics.HTTPLabels("GET", "/api/v1/users/:id", 200))
+	provider.Instruments.JobsProcessed.Inc(metrics.JobLabels("rates.refresh", "ok"))
+	// Identity labels cannot be overridden when recording
+	provider.Instruments.JobsPaused.Set(1, metrics.Labels{metrics.LabelJob: "outbox", metrics.LabelEnv: "spoofed"})
+
+	body := scrape(t, provider)
+	assert.Contains(t, body, `http_server_requests_total{env="staging",method="GET",route="/api/v1/users/:id",service="golang-arch",status="200",version="v1.4.0"} 1`)
+	assert.Contains(t, body, `worker_jobs_processed_total{env="staging",job="rates.refresh",service="golang-arch",status="ok",version="v1.4.0"} 1`)
+	assert.Contains(t, body, `worker_jobs_paused{env="staging",job="outbox",service="golang-arch",version="v1.4.0"} 1`)
+}
+
+func TestIdentity_OmitsEmptyFields(t *testing.T) {
+	identity := metrics.Identity{Service: "golang-arch"}
+	assert.Equal(t, metrics.Labels{"service": "golang-arch"}, identity.Labels())
+	assert.Equal(t, metrics.Labels{"service.name": "golang-arch"}, identity.Resource())
+	assert.NotEmpty(t, metrics.BuildVersion())
+}
+
+func TestRegistry_Namespace(t *testing.T) {
+	registry := metrics.NewRegistry()
+	jobs := registry.Namespace(metrics.NamespaceJobs)
+	assert.Equal(t, "worker.jobs.processed", jobs.Name("processed"))
+
+	counter, err := jobs.Counter("processed", "", "{job}")
+	require.NoError(t, err)
+	counter.Inc(metrics.JobLabels("outbox", ""))
+	// The same name in the same namespace is the same instrument
+	again, err := jobs.Counter("processed", "", "{job}")
+	require.NoError(t, err)
+	again.Inc(metrics.JobLabels("outbox", ""))
+
+	snapshots := registry.Snapshot()
+	require.Len(t, snapshots, 1)
+	assert.Equal(t, "worker.jobs.processed", snapshots[0].Name)
+	require.Len(t, snapshots[0].Series, 1)
+	assert.Equal(t, metrics.Labels{"job": "outbox"}, snapshots[0].Series[0].Labels)
+	assert.Equal(t, float64(2), snapshots[0].Series[0].Value)
+}
@@ -0,0 +1,210 @@
+package metrics_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"golang-arch/pkg/metrics"
+)
+
+// otlpRequest is the part of an OTLP/JSON ExportMetricsServiceRequest the
+// tests look at
+type otlpRequest struct {
+	ResourceMetrics []struct {
+		Resource struct {
+			Attributes []otlpAttribute `json:"attributes"`
+		} `json:"resource"`
+		ScopeMetrics []struct {
+			Scope struct {
+				Name string `json:"name"`
+			} `json:"scope"`
+			Metrics []otlpMetric `json:"metrics"`
+		} `json:"scopeMetrics"`
+	} `json:"resourceMetrics"`
+}
+
+type otlpAttribute struct {
+	Key   string `json:"key"`
+	Value struct {
+		StringValue string `json:"stringValue"`
+	} `json:"value"`
+}
+
+type otlpMetric struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Unit        string `json:"unit"`
+	Sum         *struct {
+		DataPoints             []otlpPoint `json:"dataPoints"`
+		AggregationTemporality int         `json:"aggregationTemporality"`
+		IsMonotonic            bool        `json:"isMonotonic"`
+	} `json:"sum"`
+	Gauge *struct {
+		DataPoints []otlpPoint `json:"dataPoints"`
+	} `json:"gauge"`
+	Histogram *struct {
+		DataPoints             []otlpPoint `json:"dataPoints"`
+		AggregationTemporality int         `json:"aggregationTemporality"`
+	} `json:"histogram"`
+}
+
+type otlpPoint struct {
+	Attributes        []otlpAttribute `json:"attributes"`
+	StartTimeUnixNano string          `json:"startTimeUnixNano"`
+	TimeUnixNano      string          `json:"timeUnixNano"`
+	AsDouble          float64         `json:"asDouble"`
+	Count             string          `json:"count"`
+	Sum               float64         `json:"sum"`
+	BucketCounts      []string        `json:"bucketCounts"`
+	ExplicitBounds    []float64       `json:"explicitBounds"`
+}
+
+func attributes(list []otlpAttribute) map[string]string {
+	values := make(map[string]string, len(list))
+	for _, attribute := range list {
+		values[attribute.Key] = attribute.Value.StringValue
+	}
+	return values
+}
+
+// collector records the requests posted to it
+func collector(t *testing.T, status int) (*httptest.Server, chan *http.Request, chan []byte) {
+	t.Helper()
+	requests := make(chan *http.Request, 1)
+	bodies := make(chan []byte, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		requests <- r
+		bodies <- body
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(server.Close)
+	return server, requests, bodies
+}
+
+func TestOTLPExporter_Payload(t *testing.T) {
+	registry := metrics.NewRegistry()
+	registry.SetConstLabels(metrics.Labels{metrics.LabelEnv: "staging"})
+	requests, err := registry.Counter("http.server.requests", "Requests", "{request}")
+	require.NoError(t, err)
+	requests.Add(2, metrics.Labels{"route": "/a"})
+	inflight, err := registry.Gauge("http.server.requests.inflight", "", "{request}")
+	require.NoError(t, err)
+	inflight.Set(7, nil)
+	duration, err := registry.Histogram("worker.jobs.duration", "", "s", []float64{0.5, 1})
+	require.NoError(t, err)
+	duration.Observe(0.2, nil)
+	duration.Observe(2, nil)
+
+	server, received, bodies := collector(t, http.StatusOK)
+	// A URL endpoint is used as is, with the metrics path appended
+	exporter := metrics.NewOTLPExporter(registry, server.URL, false, time.Minute, metrics.Labels{"service.name": "golang-arch"})
+	before := time.Now()
+	require.NoError(t, exporter.Export(context.Background()))
+
+	request := <-received
+	assert.Equal(t, http.MethodPost, request.Method)
+	assert.Equal(t, "/v1/metrics", request.URL.Path)
+	assert.Equal(t, "application/json", request.Header.Get("Content-Type"))
+
+	var payload otlpRequest
+	require.NoError(t, json.Unmarshal(<-bodies, &payload))
+	require.Len(t, payload.ResourceMetrics, 1)
+	resource := payload.ResourceMetrics[0]
+	assert.Equal(t, map[string]string{"service.name": "golang-arch"}, attributes(resource.Resource.Attributes))
+	require.Len(t, resource.ScopeMetrics, 1)
+	assert.Equal(t, "golang-arch", resource.ScopeMetrics[0].Scope.Name)
+	metricsByName := make(map[string]otlpMetric)
+	for _, metric := range resource.ScopeMetrics[0].Metrics {
+		metricsByName[metric.Name] = metric
+	}
+	require.Len(t, metricsByName, 3)
+
+	counter := metricsByName["http.server.requests"]
+	assert.Equal(t, "Requests", counter.Description)
+	assert.Equal(t, "{request}", counter.Unit)
+	require.NotNil(t, counter.Sum)
+	assert.Nil(t, counter.Gauge)
+	assert.Equal(t, 2, counter.Sum.AggregationTemporality, "cumulative")
+	assert.True(t, counter.Sum.IsMonotonic)
+	require.Len(t, counter.Sum.DataPoints, 1)
+	point := counter.Sum.DataPoints[0]
+	assert.Equal(t, float64(2), point.AsDouble)
+	assert.Equal(t, map[string]string{"route": "/a", metrics.LabelEnv: "staging"}, attributes(point.Attributes))
+	assert.Equal(t, strconv.FormatInt(registry.StartTime().UnixNano(), 10), point.StartTimeUnixNano)
+	timestamp, err := strconv.ParseInt(point.TimeUnixNano, 10, 64)
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, timestamp, before.UnixNano())
+
+	gauge := metricsByName["http.server.requests.inflight"]
+	require.NotNil(t, gauge.Gauge)
+	assert.Nil(t, gauge.Sum)
+	require.Len(t, gauge.Gauge.DataPoints, 1)
+	assert.Equal(t, float64(7), gauge.Gauge.DataPoints[0].AsDouble)
+
+	histogram := metricsByName["worker.jobs.duration"]
+	require.NotNil(t, histogram.Histogram)
+	assert.Equal(t, 2, histogram.Histogram.AggregationTemporality)
+	require.Len(t, histogram.Histogram.DataPoints, 1)
+	point = histogram.Histogram.DataPoints[0]
+	assert.Equal(t, "2", point.Count, "64-bit integers are JSON strings")
+	assert.Equal(t, 2.2, point.Sum)
+	assert.Equal(t, []string{"1", "0", "1"}, point.BucketCounts)
+	assert.Equal(t, []float64{0.5, 1}, point.ExplicitBounds)
+}
+
+func TestOTLPExporter_Endpoint(t *testing.T) {
+	server, received, bodies := collector(t, http.StatusOK)
+	host := strings.TrimPrefix(server.URL, "http://")
+
+	tests := []struct {
+		name     string
+		endpoint string
+	}{
+		{name: "host and port", endpoint: host},
+		{name: "url", endpoint: server.URL + "/"},
+		{name: "full url", endpoint: server.URL + "/v1/metrics"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exporter := metrics.NewOTLPExporter(metrics.NewRegistry(), tt.endpoint, true, 0, nil)
+			require.NoError(t, exporter.Export(context.Background()))
+			assert.Equal(t, "/v1/metrics", (<-received).URL.Path)
+			<-bodies
+		})
+	}
+
+	// Without insecure a bare host:port is dialed over TLS
+	exporter := metrics.NewOTLPExporter(metrics.NewRegistry(), host, false, 0, nil)
+	assert.Error(t, exporter.Export(context.Background()))
+}
+
+func TestOTLPExporter_CollectorError(t *testing.T) {
+	server, _, _ := collector(t, http.StatusServiceUnavailable)
+	exporter := metrics.NewOTLPExporter(metrics.NewRegistry(), server.URL, false, 0, nil)
+	assert.ErrorContains(t, exporter.Export(context.Background()), "status 503")
+}
+
+func TestOTLPExporter_ShutdownFlushes(t *testing.T) {
+	registry := metrics.NewRegistry()
+	counter, err := registry.Counter("restarts", "", "")
+	require.NoError(t, err)
+	counter.Inc(nil)
+
+	server, received, bodies := collector(t, http.StatusOK)
+	exporter := metrics.NewOTLPExporter(registry, server.URL, false, time.Hour, nil)
+	exporter.Start()
+	require.NoError(t, exporter.Shutdown(context.Background()))
+	<-received
+	assert.Contains(t, string(<-bodies), `"restarts"`)
+}
@@ -0,0 +1,69 @@
+package metrics_test
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"golang-arch/pkg/metrics"
+)
+
+func TestPrometheusHandler_Exposition(t *testing.T) {
+	registry := metrics.NewRegistry()
+	registry.SetConstLabels(metrics.Labels{"service.name": "golang-arch"})
+
+	requests, err := registry.Counter("http.server.requests", "Number of HTTP requests handled", "{request}")
+	require.NoError(t, err)
+	requests.Add(3, metrics.Labels{"route": "/users/:id"})
+	requests.Inc(metrics.Labels{"route": `a"b\c` + "\n"})
+
+	queued, err := registry.Gauge("worker.queue-depth", "", "")
+	require.NoError(t, err)
+	queued.Set(-2.5, nil)
+
+	duration, err := registry.Histogram("job.duration", "Duration of jobs", "s", []float64{0.1, 1})
+	require.NoError(t, err)
+	for _, value := range []float64{0.05, 0.1, 0.5, 3} {
+		duration.Observe(value, metrics.Labels{"job": "outbox"})
+	}
+
+	recorder := httptest.NewRecorder()
+	metrics.PrometheusHandler(registry).ServeHTTP(recorder, httptest.NewRequest("GET", "/metrics", nil))
+	assert.Equal(t, "text/plain; version=0.0.4; charset=utf-8", recorder.Header().Get("Content-Type"))
+
+	want := strings.Join([]string{
+		`# HELP http_server_requests_total Number of HTTP requests handled`,
+		`# TYPE http_server_requests_total counter`,
+		`http_server_requests_total{route="/users/:id",service_name="golang-arch"} 3`,
+		`http_server_requests_total{route="a\"b\\c\n",service_name="golang-arch"} 1`,
+		`# TYPE worker_queue_depth gauge`,
+		`worker_queue_depth{service_name="golang-arch"} -2.5`,
+		`# HELP job_duration Duration of jobs`,
+		`# TYPE job_duration histogram`,
+		`job_duration_bucket{job="outbox",service_name="golang-arch",le="0.1"} 2`,
+		`job_duration_bucket{job="outbox",service_name="golang-arch",le="1"} 3`,
+		`job_duration_bucket{job="outbox",service_name="golang-arch",le="+Inf"} 4`,
+		`job_duration_sum{job="outbox",service_name="golang-arch"} 3.65`,
+		`job_duration_count{job="outbox",service_name="golang-arch"} 4`,
+	}, "\n") + "\n"
+	assert.Equal(t, want, recorder.Body.String())
+}
+
+func TestPrometheusHandler_NoLabels(t *testing.T) {
+	registry := metrics.NewRegistry()
+	counter, err := registry.Counter("restarts", "", "")
+	require.NoError(t, err)
+	counter.Inc(nil)
+
+	recorder := httptest.NewRecorder()
+	metrics.PrometheusHandler(registry).ServeHTTP(recorder, httptest.NewRequest("GET", "/metrics", nil))
+	assert.Equal(t, "# TYPE restarts_total counter\nrestarts_total 1\n", recorder.Body.String())
+}
+
+func TestPrometheusName(t *testing.T) {
+	assert.Equal(t, "http_server_request_duration", metrics.PrometheusName("http.server.request.duration"))
+	assert.Equal(t, "a_b_c_9", metrics.PrometheusName("a-b/c:9"))
+}
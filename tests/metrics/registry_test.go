@@ -0,0 +1,101 @@
+package metrics_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"golang-arch/pkg/metrics"
+)
+
+func TestRegistry_SameNameSameInstrument(t *testing.T) {
+	registry := metrics.NewRegistry()
+
+	first, err := registry.Histogram("latency", "", "s", []float64{0.1, 1})
+	require.NoError(t, err)
+	second, err := registry.Histogram("latency", "", "s", []float64{0.1, 1})
+	require.NoError(t, err)
+	first.Observe(0.05, nil)
+	second.Observe(0.5, nil)
+
+	snapshots := registry.Snapshot()
+	require.Len(t, snapshots, 1)
+	require.Len(t, snapshots[0].Series, 1)
+	assert.Equal(t, uint64(2), snapshots[0].Series[0].Count)
+	assert.Equal(t, []uint64{1, 1, 0}, snapshots[0].Series[0].BucketCounts)
+}
+
+func TestRegistry_Conflicts(t *testing.T) {
+	registry := metrics.NewRegistry()
+	_, err := registry.Counter("requests", "", "")
+	require.NoError(t, err)
+	_, err = registry.Histogram("latency", "", "s", []float64{0.1, 1})
+	require.NoError(t, err)
+
+	_, err = registry.Gauge("requests", "", "")
+	assert.ErrorContains(t, err, "metric requests already registered as counter, not gauge")
+	_, err = registry.Histogram("requests", "", "", nil)
+	assert.ErrorContains(t, err, "already registered as counter")
+	_, err = registry.Counter("latency", "", "")
+	assert.ErrorContains(t, err, "already registered as histogram")
+
+	_, err = registry.Histogram("latency", "", "s", []float64{0.1, 1, 10})
+	assert.ErrorContains(t, err, "metric latency already registered with buckets [0.1 1]")
+	_, err = registry.Histogram("latency", "", "s", nil)
+	assert.ErrorContains(t, err, "already registered with buckets", "empty buckets mean the default buckets")
+
+	// A failed definition leaves the registry unchanged
+	assert.Len(t, registry.Snapshot(), 2)
+}
+
+func TestRegistry_HistogramBuckets(t *testing.T) {
+	registry := metrics.NewRegistry()
+
+	histogram, err := registry.Histogram("duration", "", "s", nil)
+	require.NoError(t, err)
+	histogram.Observe(0.3, nil)
+	snapshots := registry.Snapshot()
+	require.Len(t, snapshots, 1)
+	assert.Equal(t, metrics.DefaultBuckets, snapshots[0].Buckets)
+
+	for _, buckets := range [][]float64{{1, 0.5}, {1, 1}, {math.NaN()}} {
+		_, err = registry.Histogram("invalid", "", "", buckets)
+		assert.ErrorContains(t, err, "bucket bounds must be increasing")
+	}
+}
+
+func TestRegistry_Recording(t *testing.T) {
+	registry := metrics.NewRegistry()
+	registry.SetConstLabels(metrics.Labels{metrics.LabelService: "golang-arch"})
+
+	counter, err := registry.Counter("requests", "", "")
+	require.NoError(t, err)
+	counter.Inc(metrics.Labels{"route": "/a"})
+	counter.Add(2, metrics.Labels{"route": "/a"})
+	counter.Add(-5, metrics.Labels{"route": "/a"})
+	counter.Add(math.NaN(), metrics.Labels{"route": "/a"})
+	counter.Inc(metrics.Labels{"route": "/b", metrics.LabelService: "spoofed"})
+
+	gauge, err := registry.Gauge("inflight", "", "")
+	require.NoError(t, err)
+	gauge.Set(4, nil)
+	gauge.Add(-1, nil)
+
+	labels := metrics.Labels{"route": "/c"}
+	counter.Inc(labels)
+	labels["route"] = "/d" // Recording copied the labels
+
+	snapshots := registry.Snapshot()
+	require.Len(t, snapshots, 2)
+	assert.Equal(t, metrics.KindCounter, snapshots[0].Kind)
+	assert.Equal(t, []metrics.SeriesSnapshot{
+		{Labels: metrics.Labels{"route": "/a", metrics.LabelService: "golang-arch"}, Value: 3},
+		{Labels: metrics.Labels{"route": "/b", metrics.LabelService: "golang-arch"}, Value: 1},
+		{Labels: metrics.Labels{"route": "/c", metrics.LabelService: "golang-arch"}, Value: 1},
+	}, snapshots[0].Series)
+	assert.Equal(t, metrics.KindGauge, snapshots[1].Kind)
+	require.Len(t, snapshots[1].Series, 1)
+	assert.Equal(t, float64(3), snapshots[1].Series[0].Value)
+}
@@ -0,0 +1,120 @@
+package notify_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"golang-arch/internal/shared/config"
+	"golang-arch/internal/shared/notify"
+	"golang-arch/internal/shared/rbac"
+)
+
+// Bearer tokens of the test principals: an operator and recipient u1
+const (
+	adminToken     = "admin-token"
+	recipientToken = "u1-token"
+)
+
+func newServer(t *testing.T, router *notify.Router) *gin.Engine {
+	t.Helper()
+	authz, err := rbac.NewAuthorizer(config.RBACConfig{
+		Principals: []config.PrincipalConfig{
+			{Name: "ops", Token: adminToken, Roles: []string{"privacy-admin"}},
+			{Name: "u1", Token: recipientToken},
+		},
+		Roles: map[string][]string{"privacy-admin": {string(notify.PermissionAdmin)}},
+	})
+	require.NoError(t, err)
+
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	notify.NewHandler(router, authz).Register(engine.Group("/api/v1"))
+	return engine
+}
+
+func do(engine *gin.Engine, method, path, body string) *httptest.ResponseRecorder {
+	return doAs(engine, recipientToken, method, path, body)
+}
+
+func doAs(engine *gin.Engine, token, method, path, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	rec := httptest.NewRecorder()
+	engine.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestHandlerPreferences(t *testing.T) {
+	f := newFixture(t)
+	engine := newServer(t, f.router)
+
+	rec := do(engine, http.MethodGet, "/api/v1/notifications/preferences/u1", "")
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	rec = do(engine, http.MethodPut, "/api/v1/notifications/preferences/u1", `{
+		"timezone": "Asia/Jakarta",
+		"channels": ["push", "email"],
+		"events": {"newsletter": []},
+		"addresses": {"email": "u1@example.com", "push": "device-token"},
+		"quiet_hours": {"start": "21:00", "end": "07:00"}
+	}`)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	rec = do(engine, http.MethodGet, "/api/v1/notifications/preferences/u1", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	var got struct {
+		Data notify.Preferences `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &got))
+	assert.Equal(t, "u1", got.Data.RecipientID)
+	assert.Equal(t, []notify.Channel{notify.ChannelPush, notify.ChannelEmail}, got.Data.Channels)
+	assert.Equal(t, "device-token", got.Data.Addresses[notify.ChannelPush])
+	require.NotNil(t, got.Data.QuietHours)
+	assert.Equal(t, 21*60, got.Data.QuietHours.Start)
+	assert.Contains(t, got.Data.Events, "newsletter")
+	assert.True(t, start.Equal(got.Data.UpdatedAt))
+
+	rec = do(engine, http.MethodPut, "/api/v1/notifications/preferences/u1", `{"channels": ["fax"], "addresses": {"webhook": "http://example.com"}}`)
+	assert.Equal(t, http.StatusBadRequest, rec.Code, rec.Body.String())
+	assert.Contains(t, rec.Body.String(), "channels[0]")
+	assert.Contains(t, rec.Body.String(), "addresses.webhook")
+}
+
+func TestHandlerAuthorization(t *testing.T) {
+	f := newFixture(t)
+	engine := newServer(t, f.router)
+	body := `{"addresses": {"email": "u2@example.com"}}`
+
+	tests := []struct {
+		name   string
+		token  string
+		method string
+		path   string
+		body   string
+		status int
+	}{
+		{"anonymous read", "", http.MethodGet, "/api/v1/notifications/preferences/u1", "", http.StatusUnauthorized},
+		{"unknown token", "stolen", http.MethodPut, "/api/v1/notifications/preferences/u1", body, http.StatusUnauthorized},
+		{"recipient reads own", recipientToken, http.MethodGet, "/api/v1/notifications/preferences/u1", "", http.StatusOK},
+		{"recipient reads another", recipientToken, http.MethodGet, "/api/v1/notifications/preferences/u2", "", http.StatusForbidden},
+		{"recipient writes another", recipientToken, http.MethodPut, "/api/v1/notifications/preferences/u2", body, http.StatusForbidden},
+		{"admin writes any", adminToken, http.MethodPut, "/api/v1/notifications/preferences/u2", body, http.StatusOK},
+		{"admin reads any", adminToken, http.MethodGet, "/api/v1/notifications/preferences/u2", "", http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := doAs(engine, tt.token, tt.method, tt.path, tt.body)
+			assert.Equal(t, tt.status, rec.Code, rec.Body.String())
+		})
+	}
+}
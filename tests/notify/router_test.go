@@ -0,0 +1,375 @@
+package notify_test
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"golang-arch/internal/shared/consent"
+	i18n "golang-arch/internal/shared/domain/internationalization"
+	"golang-arch/internal/shared/domain/validation"
+	"golang-arch/internal/shared/events"
+	"golang-arch/internal/shared/notify"
+	"golang-arch/pkg/clock"
+)
+
+// 22:00 in Asia/Jakarta
+var start = time.Date(2024, 1, 1, 15, 0, 0, 0, time.UTC)
+
+// recorder is a sender that collects the messages it is handed
+type recorder struct {
+	mu       sync.Mutex
+	messages []notify.Message
+	sent     chan notify.Message
+}
+
+func newRecorder() *recorder {
+	return &recorder{sent: make(chan notify.Message, 10)}
+}
+
+func (r *recorder) Send(_ context.Context, message notify.Message) error {
+	r.mu.Lock()
+	r.messages = append(r.messages, message)
+	r.mu.Unlock()
+	r.sent <- message
+	return nil
+}
+
+func (r *recorder) count() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.messages)
+}
+
+type fixture struct {
+	router  *notify.Router
+	consent *consent.Service
+	clock   *clock.Fake
+	sender  *recorder
+}
+
+func newFixture(t *testing.T, options ...notify.Option) *fixture {
+	t.Helper()
+	clk := clock.NewFake(start)
+	consents := consent.NewService(consent.NewMemoryStore(), consent.WithClock(clk))
+	sender := newRecorder()
+	router := notify.NewRouter(notify.NewMemoryStore(), append([]notify.Option{
+		notify.WithClock(clk),
+		notify.WithConsent(consents),
+		notify.WithSender(notify.ChannelEmail, sender),
+		notify.WithSender(notify.ChannelSMS, sender),
+		notify.WithSender(notify.ChannelPush, sender),
+	}, options...)...)
+	t.Cleanup(func() { router.Close() })
+	return &fixture{router: router, consent: consents, clock: clk, sender: sender}
+}
+
+func (f *fixture) save(t *testing.T, prefs notify.Preferences) {
+	t.Helper()
+	_, err := f.router.SavePreferences(context.Background(), prefs)
+	require.NoError(t, err)
+}
+
+func quietHours(t *testing.T) *i18n.ContactWindow {
+	t.Helper()
+	window, err := i18n.NewContactWindow("21:00", "07:00")
+	require.NoError(t, err)
+	return window
+}
+
+func TestRouteFallsBackToReachableChannel(t *testing.T) {
+	f := newFixture(t)
+	f.save(t, notify.Preferences{
+		RecipientID: "u1",
+		Locale:      "id-ID",
+		Channels:    []notify.Channel{notify.ChannelWebhook, notify.ChannelPush, notify.ChannelEmail},
+		Addresses:   map[notify.Channel]string{notify.ChannelWebhook: "https://example.com/hook", notify.ChannelEmail: "u1@example.com"},
+	})
+
+	route, err := f.router.Route(context.Background(), notify.Notification{Event: "export.completed", RecipientID: "u1"})
+	require.NoError(t, err)
+	assert.Equal(t, notify.ChannelEmail, route.Message.Channel)
+	assert.Equal(t, "u1@example.com", route.Message.Address)
+	assert.Equal(t, i18n.Locale("id-ID"), route.Message.Locale)
+	assert.Equal(t, []notify.Skip{
+		{Channel: notify.ChannelWebhook, Reason: notify.SkipNoSender},
+		{Channel: notify.ChannelPush, Reason: notify.SkipNoAddress},
+	}, route.Skipped)
+	assert.False(t, route.Deferred(f.clock.Now()))
+}
+
+func TestRouteUsesDefaultsAndPerEventChannels(t *testing.T) {
+	f := newFixture(t)
+	ctx := context.Background()
+
+	_, err := f.router.Route(ctx, notify.Notification{Event: "export.completed", RecipientID: "nobody"})
+	assert.ErrorIs(t, err, notify.ErrUndeliverable, "no stored preferences means no address")
+
+	f.save(t, notify.Preferences{
+		RecipientID: "u1",
+		Events: map[string][]notify.Channel{
+			"order.shipped": {notify.ChannelSMS},
+			"newsletter":    {},
+		},
+		Addresses: map[notify.Channel]string{notify.ChannelEmail: "u1@example.com", notify.ChannelSMS: "+6281234567890"},
+	})
+
+	route, err := f.router.Route(ctx, notify.Notification{Event: "export.completed", RecipientID: "u1"})
+	require.NoError(t, err)
+	assert.Equal(t, notify.ChannelEmail, route.Message.Channel, "the default order is push, email, sms")
+
+	route, err = f.router.Route(ctx, notify.Notification{Event: "order.shipped", RecipientID: "u1"})
+	require.NoError(t, err)
+	assert.Equal(t, notify.ChannelSMS, route.Message.Channel)
+
+	_, err = f.router.Send(ctx, notify.Notification{Event: "newsletter", RecipientID: "u1"})
+	assert.ErrorIs(t, err, notify.ErrUndeliverable, "an empty channel list mutes the event")
+	assert.Zero(t, f.sender.count())
+}
+
+func TestRouteRequiresConsentForPurpose(t *testing.T) {
+	f := newFixture(t)
+	ctx := context.Background()
+	f.save(t, notify.Preferences{
+		RecipientID: "u1",
+		Channels:    []notify.Channel{notify.ChannelEmail},
+		Addresses:   map[notify.Channel]string{notify.ChannelEmail: "u1@example.com"},
+	})
+	offer := notify.Notification{Event: "offer", RecipientID: "u1", Purpose: consent.PurposeMarketing}
+
+	route, err := f.router.Route(ctx, offer)
+	assert.ErrorIs(t, err, notify.ErrUndeliverable)
+	assert.Equal(t, []notify.Skip{{Channel: notify.ChannelEmail, Reason: notify.SkipNoConsent}}, route.Skipped)
+
+	_, err = f.consent.Grant(ctx, consent.Decision{SubjectID: "u1", Purpose: consent.PurposeMarketing, Channel: consent.ChannelEmail, Source: "test"})
+	require.NoError(t, err)
+	_, err = f.router.Send(ctx, offer)
+	require.NoError(t, err)
+	assert.Equal(t, 1, f.sender.count())
+
+	// Service messages need no consent
+	_, err = f.router.Send(ctx, notify.Notification{Event: "export.completed", RecipientID: "u1"})
+	require.NoError(t, err)
+	assert.Equal(t, 2, f.sender.count())
+}
+
+func TestQuietHoursDeferSMSAndPush(t *testing.T) {
+	f := newFixture(t)
+	ctx := context.Background()
+	f.save(t, notify.Preferences{
+		RecipientID: "u1",
+		Timezone:    "Asia/Jakarta",
+		Channels:    []notify.Channel{notify.ChannelPush},
+		Addresses:   map[notify.Channel]string{notify.ChannelPush: "device-token"},
+		QuietHours:  quietHours(t),
+	})
+
+	route, err := f.router.Send(ctx, notify.Notification{Event: "order.shipped", RecipientID: "u1"})
+	require.NoError(t, err)
+	end := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC) // 07:00 in Jakarta
+	assert.True(t, route.Deferred(f.clock.Now()))
+	assert.True(t, end.Equal(route.At), "got %s", route.At)
+	assert.Zero(t, f.sender.count())
+
+	f.clock.Set(end)
+	select {
+	case message := <-f.sender.sent:
+		assert.Equal(t, notify.ChannelPush, message.Channel)
+		assert.Equal(t, "Asia/Jakarta", message.Timezone)
+	case <-time.After(time.Second):
+		t.Fatal("the deferred push was not sent when quiet hours ended")
+	}
+
+	// Urgent notifications are sent during quiet hours
+	f.clock.Set(start)
+	route, err = f.router.Send(ctx, notify.Notification{Event: "security.alert", RecipientID: "u1", Urgent: true})
+	require.NoError(t, err)
+	assert.False(t, route.Deferred(f.clock.Now()))
+	assert.Equal(t, 2, f.sender.count())
+}
+
+func TestQuietHoursUseTheLocaleTimezone(t *testing.T) {
+	defaults := i18n.BuiltInLocaleDefaults()
+	require.NoError(t, defaults.AddLocale("id", i18n.PartialLocalePreferences{Timezone: "Asia/Jakarta"}))
+	f := newFixture(t, notify.WithLocaleDefaults(defaults))
+	f.save(t, notify.Preferences{
+		RecipientID: "u1",
+		Locale:      "id-ID",
+		Channels:    []notify.Channel{notify.ChannelSMS},
+		Addresses:   map[notify.Channel]string{notify.ChannelSMS: "+6281234567890"},
+		QuietHours:  quietHours(t),
+	})
+	f.save(t, notify.Preferences{
+		RecipientID: "u2",
+		Channels:    []notify.Channel{notify.ChannelSMS},
+		Addresses:   map[notify.Channel]string{notify.ChannelSMS: "+15551234567"},
+		QuietHours:  quietHours(t),
+	})
+
+	route, err := f.router.Send(context.Background(), notify.Notification{Event: "order.shipped", RecipientID: "u1"})
+	require.NoError(t, err)
+	end := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC) // 07:00 in Jakarta
+	assert.True(t, end.Equal(route.At), "got %s", route.At)
+
+	route, err = f.router.Send(context.Background(), notify.Notification{Event: "order.shipped", RecipientID: "u2"})
+	require.NoError(t, err)
+	assert.False(t, route.Deferred(f.clock.Now()), "outside quiet hours in the default UTC")
+}
+
+func TestQuietHoursSkipEmail(t *testing.T) {
+	f := newFixture(t)
+	f.save(t, notify.Preferences{
+		RecipientID: "u1",
+		Timezone:    "Asia/Jakarta",
+		Channels:    []notify.Channel{notify.ChannelEmail},
+		Addresses:   map[notify.Channel]string{notify.ChannelEmail: "u1@example.com"},
+		QuietHours:  quietHours(t),
+	})
+
+	route, err := f.router.Send(context.Background(), notify.Notification{Event: "export.completed", RecipientID: "u1"})
+	require.NoError(t, err)
+	assert.False(t, route.Deferred(f.clock.Now()), "email does not interrupt")
+	assert.Equal(t, 1, f.sender.count())
+}
+
+func TestDeferredSendRechecksConsent(t *testing.T) {
+	f := newFixture(t)
+	ctx := context.Background()
+	f.save(t, notify.Preferences{
+		RecipientID: "u1",
+		Timezone:    "Asia/Jakarta",
+		Channels:    []notify.Channel{notify.ChannelSMS},
+		Addresses:   map[notify.Channel]string{notify.ChannelSMS: "+6281234567890"},
+		QuietHours:  quietHours(t),
+	})
+	decision := consent.Decision{SubjectID: "u1", Purpose: consent.PurposeMarketing, Channel: consent.ChannelSMS, Source: "test"}
+	_, err := f.consent.Grant(ctx, decision)
+	require.NoError(t, err)
+
+	var mu sync.Mutex
+	var deferred func(context.Context) error
+	router := notify.NewRouter(notify.NewMemoryStore(),
+		notify.WithClock(f.clock),
+		notify.WithConsent(f.consent),
+		notify.WithSender(notify.ChannelSMS, f.sender),
+		notify.WithScheduler(func(name string, at time.Time, run func(context.Context) error) {
+			mu.Lock()
+			defer mu.Unlock()
+			assert.Equal(t, "notify_sms", name)
+			deferred = run
+		}),
+	)
+	prefs, err := f.router.Preferences(ctx, "u1")
+	require.NoError(t, err)
+	_, err = router.SavePreferences(ctx, prefs)
+	require.NoError(t, err)
+
+	_, err = router.Send(ctx, notify.Notification{Event: "offer", RecipientID: "u1", Purpose: consent.PurposeMarketing})
+	require.NoError(t, err)
+	require.NotNil(t, deferred)
+
+	_, err = f.consent.Revoke(ctx, decision)
+	require.NoError(t, err)
+	require.NoError(t, deferred(ctx))
+	assert.Zero(t, f.sender.count(), "consent revoked while waiting")
+}
+
+func TestSubscribe(t *testing.T) {
+	f := newFixture(t)
+	f.save(t, notify.Preferences{
+		RecipientID: "u1",
+		Addresses:   map[notify.Channel]string{notify.ChannelEmail: "u1@example.com"},
+	})
+	bus := events.NewBus(zap.NewNop())
+	f.router.Subscribe(bus, "order.shipped", func(e events.Event) (notify.Notification, bool) {
+		shipped := e.(orderShipped)
+		return notify.Notification{RecipientID: shipped.CustomerID, Template: "order.shipped", Data: shipped}, shipped.CustomerID != ""
+	})
+
+	ctx := context.Background()
+	require.NoError(t, bus.Publish(ctx, orderShipped{CustomerID: "u1"}))
+	require.NoError(t, bus.Publish(ctx, orderShipped{}), "events without a recipient are ignored")
+	require.NoError(t, bus.Publish(ctx, orderShipped{CustomerID: "unknown"}), "undeliverable notifications are logged")
+
+	require.Equal(t, 1, f.sender.count())
+	message := <-f.sender.sent
+	assert.Equal(t, "order.shipped", message.Event)
+	assert.Equal(t, notify.ChannelEmail, message.Channel)
+}
+
+type orderShipped struct {
+	events.Base
+	CustomerID string
+}
+
+func (orderShipped) EventName() string { return "order.shipped" }
+
+func TestPreferencesValidate(t *testing.T) {
+	prefs := notify.Preferences{
+		RecipientID: "u1",
+		Timezone:    "Mars/Olympus",
+		Channels:    []notify.Channel{"pigeon"},
+		Addresses: map[notify.Channel]string{
+			notify.ChannelEmail:   "not an email",
+			notify.ChannelSMS:     "12",
+			notify.ChannelWebhook: "http://example.com/hook",
+		},
+		QuietHours: &i18n.ContactWindow{Start: 60, End: 60},
+	}
+	var errs validation.ValidationErrors
+	require.ErrorAs(t, prefs.Validate(), &errs)
+	fields := errs.ByField()
+	for _, field := range []string{"timezone", "channels[0]", "addresses.email", "addresses.sms", "addresses.webhook", "quiet_hours"} {
+		assert.Contains(t, fields, field)
+	}
+}
+
+func TestWebhookSender(t *testing.T) {
+	var body []byte
+	var signature string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ = io.ReadAll(r.Body)
+		signature = r.Header.Get(notify.SignatureHeader)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	sender := notify.NewWebhookSender("secret", time.Second, clock.NewFake(start))
+	err := sender.Send(context.Background(), notify.Message{
+		Notification: notify.Notification{Event: "export.completed", RecipientID: "u1", Data: map[string]string{"url": "https://files"}},
+		Channel:      notify.ChannelWebhook,
+		Address:      server.URL,
+	})
+	require.NoError(t, err)
+
+	var payload notify.WebhookPayload
+	require.NoError(t, json.Unmarshal(body, &payload))
+	assert.Equal(t, "export.completed", payload.Event)
+	assert.Equal(t, "u1", payload.RecipientID)
+	assert.True(t, start.Equal(payload.SentAt))
+
+	mac := hmac.New(sha256.New, []byte("secret"))
+	mac.Write([]byte("1704121200." + string(body)))
+	assert.Equal(t, "t=1704121200,v1="+hex.EncodeToString(mac.Sum(nil)), signature)
+
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusGone)
+	}))
+	defer failing.Close()
+	err = sender.Send(context.Background(), notify.Message{Address: failing.URL})
+	require.Error(t, err)
+	assert.True(t, strings.Contains(err.Error(), "410"), err.Error())
+}
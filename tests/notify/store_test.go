@@ -0,0 +1,83 @@
+package notify_test
+
+import (
+	"bytes"
+	"context"
+	"database/sql/driver"
+	"encoding/base64"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"golang-arch/internal/shared/fieldcrypt"
+	"golang-arch/internal/shared/notify"
+)
+
+// captured matches any argument and keeps it
+type captured struct{ value driver.Value }
+
+func (c *captured) Match(v driver.Value) bool {
+	c.value = v
+	return true
+}
+
+func newEncryptor(t *testing.T) *fieldcrypt.Encryptor {
+	t.Helper()
+	key := base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{1}, fieldcrypt.KeySize))
+	provider, err := fieldcrypt.NewStaticKeyProvider("k1="+key, "")
+	require.NoError(t, err)
+	encryptor, err := fieldcrypt.NewEncryptor(context.Background(), provider)
+	require.NoError(t, err)
+	return encryptor
+}
+
+func TestPostgresStore_EncryptsAddresses(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+	store := notify.NewPostgresStore(db, notify.WithEncryption(newEncryptor(t)))
+	ctx := context.Background()
+
+	prefs := notify.Preferences{
+		RecipientID: "u1",
+		Channels:    []notify.Channel{notify.ChannelEmail},
+		Addresses:   map[notify.Channel]string{notify.ChannelEmail: "u1@example.com"},
+		UpdatedAt:   start,
+	}
+	data, sealed := &captured{}, &captured{}
+	mock.ExpectExec("INSERT INTO notification_preferences").
+		WithArgs("u1", data, sealed, start).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	require.NoError(t, store.Save(ctx, prefs))
+	assert.NotContains(t, string(data.value.([]byte)), "u1@example.com")
+	assert.NotContains(t, string(sealed.value.([]byte)), "u1@example.com")
+
+	mock.ExpectQuery("SELECT preferences, addresses FROM notification_preferences").
+		WithArgs("u1").
+		WillReturnRows(sqlmock.NewRows([]string{"preferences", "addresses"}).AddRow(data.value, sealed.value))
+	got, found, err := store.Get(ctx, "u1")
+	require.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, prefs.Addresses, got.Addresses)
+	assert.Equal(t, prefs.Channels, got.Channels)
+
+	// Rows saved before encryption keep their addresses in the JSON
+	mock.ExpectQuery("SELECT preferences, addresses FROM notification_preferences").
+		WithArgs("u2").
+		WillReturnRows(sqlmock.NewRows([]string{"preferences", "addresses"}).
+			AddRow([]byte(`{"recipient_id":"u2","addresses":{"sms":"+4917612345678"}}`), nil))
+	got, found, err = store.Get(ctx, "u2")
+	require.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, "+4917612345678", got.Addresses[notify.ChannelSMS])
+
+	// Sealed addresses cannot be read once encryption is turned off
+	mock.ExpectQuery("SELECT preferences, addresses FROM notification_preferences").
+		WithArgs("u1").
+		WillReturnRows(sqlmock.NewRows([]string{"preferences", "addresses"}).AddRow(data.value, sealed.value))
+	_, _, err = notify.NewPostgresStore(db).Get(ctx, "u1")
+	assert.ErrorContains(t, err, "encryption is disabled")
+	require.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,89 @@
+package otp_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"golang-arch/internal/shared/otp"
+)
+
+func newRouter(service *otp.Service) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	otp.NewHandler(service).Register(router.Group("/api/v1"))
+	return router
+}
+
+func post(router *gin.Engine, path, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestHandlerRequestAndVerify(t *testing.T) {
+	f := newFixture(t)
+	router := newRouter(f.service)
+
+	rec := post(router, "/api/v1/otp/request", `{"channel":"email","recipient":"Jane@Example.com","purpose":"login","locale":"fr"}`)
+	require.Equal(t, http.StatusAccepted, rec.Code, rec.Body.String())
+	var response struct {
+		Data struct {
+			Recipient  string `json:"recipient"`
+			ExpiresIn  int    `json:"expires_in"`
+			RetryAfter int    `json:"retry_after"`
+		} `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Equal(t, "jane@example.com", response.Data.Recipient)
+	assert.Equal(t, 300, response.Data.ExpiresIn)
+	assert.Equal(t, 60, response.Data.RetryAfter)
+	assert.NotContains(t, rec.Body.String(), "code\"", "the code is never returned")
+
+	require.NoError(t, f.service.Deliver(context.Background()))
+	delivery, code := f.outbox.last(t)
+	assert.Equal(t, "Votre code de vérification", delivery.Subject)
+
+	rec = post(router, "/api/v1/otp/request", `{"channel":"email","recipient":"jane@example.com","purpose":"login"}`)
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+	assert.Equal(t, "60", rec.Header().Get("Retry-After"))
+	assert.Contains(t, rec.Body.String(), "TOO_MANY_REQUESTS")
+
+	rec = post(router, "/api/v1/otp/verify", `{"channel":"email","recipient":"jane@example.com","purpose":"login","code":"`+code+`x"}`)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	rec = post(router, "/api/v1/otp/verify", `{"channel":"email","recipient":"jane@example.com","purpose":"login","code":"`+code+`"}`)
+	assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Contains(t, rec.Body.String(), `"verified":true`)
+}
+
+func TestHandlerValidation(t *testing.T) {
+	f := newFixture(t)
+	router := newRouter(f.service)
+
+	tests := []struct {
+		name  string
+		body  string
+		field string
+	}{
+		{"bad recipient", `{"channel":"sms","recipient":"12","purpose":"login"}`, "recipient"},
+		{"bad locale", `{"channel":"sms","recipient":"+15551234567","purpose":"login","locale":"!!"}`, "locale"},
+		{"bad purpose", `{"channel":"sms","recipient":"+15551234567","purpose":"Log In"}`, "purpose"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := post(router, "/api/v1/otp/request", tt.body)
+			assert.Equal(t, http.StatusBadRequest, rec.Code)
+			assert.Contains(t, rec.Body.String(), tt.field)
+		})
+	}
+}
@@ -0,0 +1,363 @@
+package otp_test
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"golang-arch/internal/shared/domain/domainerror"
+	i18n "golang-arch/internal/shared/domain/internationalization"
+	"golang-arch/internal/shared/domain/validation"
+	"golang-arch/internal/shared/fieldcrypt"
+	"golang-arch/internal/shared/otp"
+	"golang-arch/internal/shared/phoneverify"
+	"golang-arch/internal/shared/testutil"
+	"golang-arch/pkg/clock"
+)
+
+var start = time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+var codePattern = regexp.MustCompile(`\b\d{6}\b`)
+
+// outbox captures deliveries handed to the senders
+type outbox struct {
+	mu         sync.Mutex
+	deliveries []otp.Delivery
+	fail       bool
+}
+
+func (o *outbox) Send(_ context.Context, delivery otp.Delivery) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.fail {
+		return errors.New("gateway down")
+	}
+	o.deliveries = append(o.deliveries, delivery)
+	return nil
+}
+
+// last returns the most recent delivery and the code in it
+func (o *outbox) last(t *testing.T) (otp.Delivery, string) {
+	t.Helper()
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	require.NotEmpty(t, o.deliveries)
+	delivery := o.deliveries[len(o.deliveries)-1]
+	code := codePattern.FindString(delivery.Body)
+	require.NotEmpty(t, code, delivery.Body)
+	return delivery, code
+}
+
+type fixture struct {
+	service *otp.Service
+	redis   *miniredis.Miniredis
+	clock   *clock.Fake
+	outbox  *outbox
+}
+
+func newFixture(t *testing.T, options ...otp.Option) *fixture {
+	t.Helper()
+
+	redisServer, client := testutil.NewRedis(t)
+
+	f := &fixture{redis: redisServer, clock: clock.NewFake(start), outbox: &outbox{}}
+	options = append([]otp.Option{otp.WithClock(f.clock), otp.WithSecret("test-secret")}, options...)
+	f.service = otp.NewService(client, map[otp.Channel]otp.Sender{
+		otp.ChannelSMS:   f.outbox,
+		otp.ChannelEmail: f.outbox,
+	}, options...)
+	return f
+}
+
+// advance moves both the service clock and Redis key expiry forward
+func (f *fixture) advance(d time.Duration) {
+	f.clock.Advance(d)
+	f.redis.FastForward(d)
+}
+
+func recipient(t *testing.T, channel otp.Channel, address string) otp.Recipient {
+	t.Helper()
+	r, err := otp.NewRecipient(channel, address)
+	require.NoError(t, err)
+	return r
+}
+
+// codeKeys lists the stored code keys
+func codeKeys(server *miniredis.Miniredis) []string {
+	var keys []string
+	for _, key := range server.Keys() {
+		if strings.HasPrefix(key, "otp:code:") {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+func en() i18n.Locale { return i18n.MustParseLocale("en") }
+
+func TestNewRecipient(t *testing.T) {
+	sms := recipient(t, otp.ChannelSMS, "+1 555 123 4567")
+	assert.Equal(t, "+15551234567", sms.Address)
+
+	email := recipient(t, otp.ChannelEmail, " Jane.Doe@Example.COM ")
+	assert.Equal(t, "jane.doe@example.com", email.Address)
+
+	for _, tt := range []struct {
+		channel otp.Channel
+		address string
+	}{
+		{otp.ChannelEmail, "not-an-email"},
+		{otp.ChannelEmail, "Jane <jane@example.com>"},
+		{otp.ChannelSMS, "12"},
+		{"pigeon", "jane@example.com"},
+	} {
+		_, err := otp.NewRecipient(tt.channel, tt.address)
+		assert.Error(t, err, tt.address)
+	}
+}
+
+func TestRequestAndVerify(t *testing.T) {
+	f := newFixture(t)
+	ctx := context.Background()
+	to := recipient(t, otp.ChannelSMS, "+15551234567")
+
+	challenge, err := f.service.Request(ctx, to, "login", en())
+	require.NoError(t, err)
+	assert.Equal(t, 5*time.Minute, challenge.ExpiresIn)
+	assert.Equal(t, time.Minute, challenge.RetryAfter)
+	assert.Empty(t, f.outbox.deliveries, "codes are sent by the worker")
+
+	require.NoError(t, f.service.Deliver(ctx))
+	delivery, code := f.outbox.last(t)
+	assert.Equal(t, "+15551234567", delivery.Address)
+	assert.Equal(t, "login", delivery.Purpose)
+	assert.Contains(t, delivery.Body, "5 minutes")
+
+	// Codes are stored hashed, under a key that hides the number
+	keys := codeKeys(f.redis)
+	require.Len(t, keys, 1)
+	assert.Regexp(t, `^otp:code:login:sms:[0-9a-f]{64}$`, keys[0])
+	stored := f.redis.HGet(keys[0], "digest")
+	assert.NotEmpty(t, stored)
+	assert.NotContains(t, stored, code)
+
+	err = f.service.Verify(ctx, to, "signup", code)
+	assert.Contains(t, validation.FromError(err).ByField(), "code", "codes are bound to their purpose")
+
+	require.NoError(t, f.service.Verify(ctx, to, "login", code))
+	err = f.service.Verify(ctx, to, "login", code)
+	assert.Contains(t, validation.FromError(err).ByField(), "code", "codes are single use")
+}
+
+func TestVerifyExpiry(t *testing.T) {
+	f := newFixture(t)
+	ctx := context.Background()
+	to := recipient(t, otp.ChannelEmail, "jane@example.com")
+
+	_, err := f.service.Request(ctx, to, "login", en())
+	require.NoError(t, err)
+	require.NoError(t, f.service.Deliver(ctx))
+	_, code := f.outbox.last(t)
+
+	f.advance(5 * time.Minute)
+	assert.Error(t, f.service.Verify(ctx, to, "login", code))
+}
+
+func TestVerifyMaxAttempts(t *testing.T) {
+	f := newFixture(t, otp.WithMaxAttempts(3))
+	ctx := context.Background()
+	to := recipient(t, otp.ChannelSMS, "+15551234567")
+
+	_, err := f.service.Request(ctx, to, "login", en())
+	require.NoError(t, err)
+	require.NoError(t, f.service.Deliver(ctx))
+	_, code := f.outbox.last(t)
+	wrong := "000000"
+	if code == wrong {
+		wrong = "111111"
+	}
+
+	for range 3 {
+		err := f.service.Verify(ctx, to, "login", wrong)
+		assert.Equal(t, validation.CodeInvalid, validation.FromError(err).ByField()["code"][0].Code)
+	}
+	err = f.service.Verify(ctx, to, "login", code)
+	assert.Equal(t, validation.CodeOutOfRange, validation.FromError(err).ByField()["code"][0].Code)
+
+	// The code stays invalid after the attempts run out
+	assert.Error(t, f.service.Verify(ctx, to, "login", code))
+}
+
+func TestRequestNewCodeReplacesOld(t *testing.T) {
+	f := newFixture(t, otp.WithCooldown(0))
+	ctx := context.Background()
+	to := recipient(t, otp.ChannelSMS, "+15551234567")
+
+	_, err := f.service.Request(ctx, to, "login", en())
+	require.NoError(t, err)
+	require.NoError(t, f.service.Deliver(ctx))
+	_, first := f.outbox.last(t)
+
+	_, err = f.service.Request(ctx, to, "login", en())
+	require.NoError(t, err)
+	require.NoError(t, f.service.Deliver(ctx))
+	_, second := f.outbox.last(t)
+
+	if first != second {
+		assert.Error(t, f.service.Verify(ctx, to, "login", first))
+	}
+	assert.NoError(t, f.service.Verify(ctx, to, "login", second))
+}
+
+func TestRequestRateLimits(t *testing.T) {
+	f := newFixture(t, otp.WithCooldown(time.Minute), otp.WithSendLimit(2, time.Hour))
+	ctx := context.Background()
+	to := recipient(t, otp.ChannelSMS, "+15551234567")
+
+	_, err := f.service.Request(ctx, to, "login", en())
+	require.NoError(t, err)
+
+	_, err = f.service.Request(ctx, recipient(t, otp.ChannelSMS, "+1 555 123 4567"), "login", en())
+	var limited *otp.RateLimitError
+	require.ErrorAs(t, err, &limited, "formatting does not bypass the cooldown")
+	assert.Equal(t, time.Minute, limited.RetryAfter)
+
+	_, err = f.service.Request(ctx, recipient(t, otp.ChannelSMS, "+15557654321"), "login", en())
+	assert.NoError(t, err, "limits are per recipient")
+
+	f.advance(time.Minute)
+	_, err = f.service.Request(ctx, to, "login", en())
+	require.NoError(t, err)
+
+	f.advance(time.Minute)
+	_, err = f.service.Request(ctx, to, "login", en())
+	require.ErrorAs(t, err, &limited)
+	assert.Equal(t, 58*time.Minute, limited.RetryAfter)
+
+	f.advance(58 * time.Minute)
+	_, err = f.service.Request(ctx, to, "login", en())
+	assert.NoError(t, err)
+}
+
+func TestRequestValidation(t *testing.T) {
+	f := newFixture(t)
+	ctx := context.Background()
+	to := recipient(t, otp.ChannelEmail, "jane@example.com")
+
+	_, err := f.service.Request(ctx, to, "Log In!", en())
+	assert.Contains(t, validation.FromError(err).ByField(), "purpose")
+
+	noEmail := otp.NewService(redis.NewClient(&redis.Options{Addr: f.redis.Addr()}),
+		map[otp.Channel]otp.Sender{otp.ChannelSMS: f.outbox}, otp.WithSecret("s"))
+	_, err = noEmail.Request(ctx, to, "login", en())
+	assert.ErrorIs(t, err, domainerror.Unavailable)
+}
+
+func TestRequestRejectsDisconnectedPhone(t *testing.T) {
+	verifier, err := phoneverify.NewStaticVerifier(map[string]string{"+15550000000": "disconnected"}, clock.NewFake(start))
+	require.NoError(t, err)
+	f := newFixture(t, otp.WithPhoneVerifier(verifier))
+	ctx := context.Background()
+
+	_, err = f.service.Request(ctx, recipient(t, otp.ChannelSMS, "+15550000000"), "login", en())
+	assert.Contains(t, validation.FromError(err).ByField(), "phone")
+
+	_, err = f.service.Request(ctx, recipient(t, otp.ChannelSMS, "+15551234567"), "login", en())
+	assert.NoError(t, err)
+}
+
+func TestLocalizedMessages(t *testing.T) {
+	f := newFixture(t)
+	ctx := context.Background()
+
+	_, err := f.service.Request(ctx, recipient(t, otp.ChannelEmail, "jane@example.de"), "login", i18n.MustParseLocale("de-DE"))
+	require.NoError(t, err)
+	_, err = f.service.Request(ctx, recipient(t, otp.ChannelSMS, "+15551234567"), "login", i18n.MustParseLocale("sw"))
+	require.NoError(t, err)
+	require.NoError(t, f.service.Deliver(ctx))
+
+	require.Len(t, f.outbox.deliveries, 2)
+	german, fallback := f.outbox.deliveries[0], f.outbox.deliveries[1]
+	assert.Equal(t, "Ihr Bestätigungscode", german.Subject)
+	assert.Contains(t, german.Body, "5 Minuten")
+	assert.Empty(t, fallback.Subject)
+	assert.Contains(t, fallback.Body, "Your verification code", "unsupported languages fall back to English")
+}
+
+func TestDeliverRetries(t *testing.T) {
+	f := newFixture(t)
+	ctx := context.Background()
+
+	_, err := f.service.Request(ctx, recipient(t, otp.ChannelSMS, "+15551234567"), "login", en())
+	require.NoError(t, err)
+
+	f.outbox.fail = true
+	assert.Error(t, f.service.Deliver(ctx))
+	assert.Error(t, f.service.Deliver(ctx))
+	f.outbox.fail = false
+	require.NoError(t, f.service.Deliver(ctx))
+	assert.Len(t, f.outbox.deliveries, 1, "failed deliveries are retried")
+
+	// Gives up after three attempts
+	_, err = f.service.Request(ctx, recipient(t, otp.ChannelSMS, "+15557654321"), "login", en())
+	require.NoError(t, err)
+	f.outbox.fail = true
+	for range 3 {
+		assert.Error(t, f.service.Deliver(ctx))
+	}
+	f.outbox.fail = false
+	assert.NoError(t, f.service.Deliver(ctx))
+	assert.Len(t, f.outbox.deliveries, 1)
+
+	// Expired deliveries are dropped
+	_, err = f.service.Request(ctx, recipient(t, otp.ChannelSMS, "+15550001111"), "login", en())
+	require.NoError(t, err)
+	f.advance(5 * time.Minute)
+	require.NoError(t, f.service.Deliver(ctx))
+	assert.Len(t, f.outbox.deliveries, 1)
+}
+
+func TestRecipientsNotStoredInClear(t *testing.T) {
+	key := base64.StdEncoding.EncodeToString([]byte(strings.Repeat("k", fieldcrypt.KeySize)))
+	provider, err := fieldcrypt.NewStaticKeyProvider("k1="+key, "")
+	require.NoError(t, err)
+	encryptor, err := fieldcrypt.NewEncryptor(context.Background(), provider)
+	require.NoError(t, err)
+	f := newFixture(t, otp.WithEncryption(encryptor))
+	ctx := context.Background()
+
+	_, err = f.service.Request(ctx, recipient(t, otp.ChannelEmail, "Ada@Example.com"), "login", en())
+	require.NoError(t, err)
+	for _, key := range f.redis.Keys() {
+		assert.NotContains(t, key, "example.com")
+		if key != "otp:outbox" {
+			continue
+		}
+		queued, err := f.redis.List(key)
+		require.NoError(t, err)
+		require.Len(t, queued, 1)
+		assert.NotContains(t, queued[0], "example.com", "deliveries are sealed")
+	}
+
+	// Deliveries queued before encryption was enabled are still sent
+	legacy, err := json.Marshal(otp.Delivery{Channel: otp.ChannelSMS, Address: "+15551234567", Body: "Code 123456", ExpiresAt: start.Add(time.Minute)})
+	require.NoError(t, err)
+	_, err = f.redis.Push("otp:outbox", string(legacy))
+	require.NoError(t, err)
+
+	require.NoError(t, f.service.Deliver(ctx))
+	require.Len(t, f.outbox.deliveries, 2)
+	assert.Equal(t, "ada@example.com", f.outbox.deliveries[0].Address)
+	assert.Equal(t, "+15551234567", f.outbox.deliveries[1].Address)
+}
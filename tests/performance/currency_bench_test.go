@@ -0,0 +1,90 @@
+package performance_test
+
+import (
+	"testing"
+
+	i18n "golang-arch/internal/shared/domain/internationalization"
+)
+
+var benchmarkSink string
+
+func BenchmarkCurrencyFormat(b *testing.B) {
+	currencies := []i18n.Currency{
+		{Code: "USD", Symbol: "$", Name: "US Dollar", DecimalPlaces: 2},
+		{Code: "JPY", Symbol: "¥", Name: "Japanese Yen", DecimalPlaces: 0},
+		{Code: "ETH", Symbol: "Ξ", Name: "Ethereum", DecimalPlaces: 18},
+	}
+
+	for _, currency := range currencies {
+		currency := currency
+		b.Run(currency.Code, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				benchmarkSink = currency.Format(int64(i) * 12345)
+			}
+		})
+	}
+}
+
+func BenchmarkCurrencyFormatWithCode(b *testing.B) {
+	usd := i18n.Currency{Code: "USD", Symbol: "$", Name: "US Dollar", DecimalPlaces: 2}
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		benchmarkSink = usd.FormatWithCode(-int64(i) * 12345)
+	}
+}
+
+func BenchmarkNewCurrencyFromCode(b *testing.B) {
+	codes := i18n.GetSupportedCurrencies()
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := i18n.NewCurrencyFromCode(codes[i%len(codes)]); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkNewMoneyFromPrimitive mirrors hydrating one money column from a DB row
+func BenchmarkNewMoneyFromPrimitive(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := i18n.NewMoneyFromPrimitive(int64(i), "EUR"); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkMoneyGrid compares hydrating a price grid as pointers and as values
+func BenchmarkMoneyGrid(b *testing.B) {
+	const rows = 1000
+
+	b.Run("pointers", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			grid := make([]*i18n.Money, 0, rows)
+			for r := 0; r < rows; r++ {
+				price, err := i18n.NewMoneyFromPrimitive(int64(r), "EUR")
+				if err != nil {
+					b.Fatal(err)
+				}
+				grid = append(grid, price)
+			}
+		}
+	})
+
+	b.Run("values", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			grid := make([]i18n.Money, 0, rows)
+			for r := 0; r < rows; r++ {
+				price, err := i18n.MakeMoney(int64(r), "EUR")
+				if err != nil {
+					b.Fatal(err)
+				}
+				grid = append(grid, price)
+			}
+		}
+	})
+}
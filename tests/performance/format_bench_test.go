@@ -0,0 +1,47 @@
+package performance_test
+
+import (
+	"testing"
+
+	i18n "golang-arch/internal/shared/domain/internationalization"
+)
+
+func benchmarkFormatFixtures(b *testing.B) (*i18n.Money, *i18n.Phone, *i18n.LocalizedDateTime) {
+	money := &i18n.Money{Amount: 1234567, Currency: i18n.Currency{Code: "EUR", Symbol: "€", Name: "Euro", DecimalPlaces: 2}}
+	phone := &i18n.Phone{CountryCode: "44", Number: "2079460958"}
+	ldt, err := i18n.NewLocalizedDateTimeFromPrimitive(1703520000, "Europe/London")
+	if err != nil {
+		b.Fatal(err)
+	}
+	return money, phone, ldt
+}
+
+// BenchmarkFormatRow_String formats one report row through the string APIs
+func BenchmarkFormatRow_String(b *testing.B) {
+	money, phone, ldt := benchmarkFormatFixtures(b)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		benchmarkSink = money.Format()
+		benchmarkSink = phone.Format()
+		benchmarkSink = ldt.Format("2006-01-02 15:04:05")
+	}
+}
+
+// BenchmarkFormatRow_Append formats the same row into one reused buffer
+func BenchmarkFormatRow_Append(b *testing.B) {
+	money, phone, ldt := benchmarkFormatFixtures(b)
+	buf := make([]byte, 0, 256)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		buf = money.AppendFormat(buf[:0])
+		buf = append(buf, ',')
+		buf = phone.AppendFormat(buf)
+		buf = append(buf, ',')
+		buf = ldt.AppendFormat(buf, "2006-01-02 15:04:05")
+	}
+	benchmarkBytesSink = buf
+}
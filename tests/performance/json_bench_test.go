@@ -0,0 +1,67 @@
+package performance_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	i18n "golang-arch/internal/shared/domain/internationalization"
+)
+
+var benchmarkBytesSink []byte
+
+func BenchmarkMoneyMarshalJSON(b *testing.B) {
+	money := &i18n.Money{Amount: 10050, Currency: i18n.Currency{Code: "USD", Symbol: "$", Name: "US Dollar", DecimalPlaces: 2}}
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		benchmarkBytesSink, _ = money.MarshalJSON()
+	}
+}
+
+func BenchmarkTimeMarshalJSON(b *testing.B) {
+	value := &i18n.Time{Epoch: 1703520000}
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		benchmarkBytesSink, _ = value.MarshalJSON()
+	}
+}
+
+func BenchmarkLocalizedDateTimeMarshalJSON(b *testing.B) {
+	ldt, err := i18n.NewLocalizedDateTimeFromPrimitive(1703520000, "America/New_York")
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		benchmarkBytesSink, _ = json.Marshal(ldt)
+	}
+}
+
+// BenchmarkPriceListJSON encodes an API payload of 100 priced, timestamped rows
+func BenchmarkPriceListJSON(b *testing.B) {
+	type row struct {
+		Price     *i18n.Money             `json:"price"`
+		UpdatedAt *i18n.LocalizedDateTime `json:"updated_at"`
+	}
+
+	ldt, err := i18n.NewLocalizedDateTimeFromPrimitive(1703520000, "Europe/Paris")
+	if err != nil {
+		b.Fatal(err)
+	}
+	rows := make([]row, 100)
+	for i := range rows {
+		rows[i] = row{
+			Price:     &i18n.Money{Amount: int64(i) * 1999, Currency: i18n.Currency{Code: "EUR", Symbol: "€", Name: "Euro", DecimalPlaces: 2}},
+			UpdatedAt: ldt,
+		}
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		benchmarkBytesSink, _ = json.Marshal(rows)
+	}
+}
@@ -0,0 +1,62 @@
+package performance_test
+
+import (
+	"math/rand"
+	"testing"
+
+	i18n "golang-arch/internal/shared/domain/internationalization"
+)
+
+var benchmarkEntries []i18n.TimeRangeEntry[int]
+
+// bookings returns n one-hour bookings spread over a year
+func bookings(b *testing.B, n int) []i18n.TimeRange {
+	b.Helper()
+	rng := rand.New(rand.NewSource(1))
+	ranges := make([]i18n.TimeRange, n)
+	for i := range ranges {
+		start := int64(1704067200) + rng.Int63n(365*24*3600)
+		r, err := i18n.NewTimeRangeFromPrimitive(start, start+3600)
+		if err != nil {
+			b.Fatal(err)
+		}
+		ranges[i] = *r
+	}
+	return ranges
+}
+
+func BenchmarkTimeRangeTreeQueryOverlapping(b *testing.B) {
+	ranges := bookings(b, 50_000)
+	tree := i18n.NewTimeRangeTree[int]()
+	for i, r := range ranges {
+		if err := tree.Insert(i, r); err != nil {
+			b.Fatal(err)
+		}
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		benchmarkEntries = tree.QueryOverlapping(ranges[i%len(ranges)])
+	}
+}
+
+func BenchmarkTimeRangeTreeInsertRemove(b *testing.B) {
+	ranges := bookings(b, 50_000)
+	tree := i18n.NewTimeRangeTree[int]()
+	for i, r := range ranges {
+		if err := tree.Insert(i, r); err != nil {
+			b.Fatal(err)
+		}
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		key := i % len(ranges)
+		tree.Remove(key)
+		if err := tree.Insert(key, ranges[key]); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package performance_test
+
+import (
+	"testing"
+
+	i18n "golang-arch/internal/shared/domain/internationalization"
+)
+
+var benchmarkTimezoneIDs = []string{
+	"UTC", "America/New_York", "Europe/London", "Asia/Tokyo", "Australia/Sydney",
+	"America/Sao_Paulo", "Asia/Kolkata", "Africa/Nairobi",
+}
+
+func BenchmarkNewTimezoneFromID(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := i18n.NewTimezoneFromID(benchmarkTimezoneIDs[i%len(benchmarkTimezoneIDs)]); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkNewTimezoneFromID_Parallel(b *testing.B) {
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		i := 0
+		for pb.Next() {
+			if _, err := i18n.NewTimezoneFromID(benchmarkTimezoneIDs[i%len(benchmarkTimezoneIDs)]); err != nil {
+				b.Fatal(err)
+			}
+			i++
+		}
+	})
+}
+
+// BenchmarkNewLocalizedDateTimeFromPrimitive mirrors hydrating one DB row
+func BenchmarkNewLocalizedDateTimeFromPrimitive(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		id := benchmarkTimezoneIDs[i%len(benchmarkTimezoneIDs)]
+		if _, err := i18n.NewLocalizedDateTimeFromPrimitive(1705320000+int64(i), id); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkTimezoneValidate(b *testing.B) {
+	tz, err := i18n.NewTimezoneFromID("America/New_York")
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := tz.Validate(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
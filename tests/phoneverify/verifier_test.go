@@ -0,0 +1,221 @@
+package phoneverify_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"golang-arch/internal/shared/cache"
+	"golang-arch/internal/shared/config"
+	i18n "golang-arch/internal/shared/domain/internationalization"
+	"golang-arch/internal/shared/domain/validation"
+	"golang-arch/internal/shared/erasure"
+	"golang-arch/internal/shared/phoneverify"
+	"golang-arch/internal/shared/testutil"
+	"golang-arch/pkg/clock"
+)
+
+var start = time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+func phone(t *testing.T, value string) i18n.Phone {
+	t.Helper()
+	p, err := i18n.NewPhoneFromString(value)
+	require.NoError(t, err)
+	return *p
+}
+
+// hlrServer fakes the HLR lookup endpoint, answering with statuses by MSISDN
+func hlrServer(t *testing.T, statuses map[string]string) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+
+	var calls atomic.Int32
+	digest := sha256.Sum256([]byte("key:secret"))
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		assert.Equal(t, "/hlr-lookup", r.URL.Path)
+		if r.Header.Get("X-Basic") != hex.EncodeToString(digest[:]) {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		var body struct {
+			MSISDN string `json:"msisdn"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		json.NewEncoder(w).Encode(map[string]any{
+			"msisdn":                body.MSISDN,
+			"connectivity_status":   statuses[body.MSISDN],
+			"original_network_name": "Telekom",
+			"ported_network_name":   "Vodafone",
+			"is_ported":             body.MSISDN == "+4917612345678",
+		})
+	}))
+	t.Cleanup(server.Close)
+	return server, &calls
+}
+
+func TestStaticVerifier(t *testing.T) {
+	verifier, err := phoneverify.NewStaticVerifier(map[string]string{"+15550000000": "Disconnected"}, clock.NewFake(start))
+	require.NoError(t, err)
+
+	result, err := verifier.Verify(context.Background(), phone(t, "+15550000000"))
+	require.NoError(t, err)
+	assert.Equal(t, phoneverify.StatusDisconnected, result.Status)
+	assert.False(t, result.Deliverable())
+	assert.Equal(t, start, result.CheckedAt)
+
+	result, err = verifier.Verify(context.Background(), phone(t, "+15551234567"))
+	require.NoError(t, err)
+	assert.Equal(t, phoneverify.StatusReachable, result.Status)
+
+	_, err = phoneverify.NewStaticVerifier(map[string]string{"+15550000000": "gone"}, clock.New())
+	assert.Error(t, err)
+}
+
+func TestHLRLookupsVerifier(t *testing.T) {
+	server, _ := hlrServer(t, map[string]string{
+		"+4917612345678": "CONNECTED",
+		"+4915112345678": "ABSENT",
+		"+4916012345678": "INVALID_MSISDN",
+	})
+	verifier := phoneverify.NewHLRLookupsVerifier(server.URL, "key", "secret", clock.NewFake(start))
+	ctx := context.Background()
+
+	tests := []struct {
+		number   string
+		status   phoneverify.Status
+		lineType phoneverify.LineType
+	}{
+		{"+4917612345678", phoneverify.StatusReachable, phoneverify.LineMobile},
+		{"+4915112345678", phoneverify.StatusAbsent, phoneverify.LineMobile},
+		{"+4916012345678", phoneverify.StatusDisconnected, phoneverify.LineUnknown},
+		{"+4917012345678", phoneverify.StatusUnknown, phoneverify.LineUnknown},
+	}
+	for _, tt := range tests {
+		t.Run(tt.number, func(t *testing.T) {
+			result, err := verifier.Verify(ctx, phone(t, tt.number))
+			require.NoError(t, err)
+			assert.Equal(t, tt.number, result.Phone)
+			assert.Equal(t, tt.status, result.Status)
+			assert.Equal(t, tt.lineType, result.LineType)
+			assert.Equal(t, "hlrlookups", result.Provider)
+		})
+	}
+
+	result, err := verifier.Verify(ctx, phone(t, "+4917612345678"))
+	require.NoError(t, err)
+	assert.True(t, result.Ported)
+	assert.Equal(t, "Vodafone", result.Carrier)
+
+	wrongKey := phoneverify.NewHLRLookupsVerifier(server.URL, "key", "wrong", clock.New())
+	_, err = wrongKey.Verify(ctx, phone(t, "+4917612345678"))
+	assert.Error(t, err)
+}
+
+func TestCachingVerifier(t *testing.T) {
+	server, calls := hlrServer(t, map[string]string{
+		"+4917612345678": "CONNECTED",
+		"+4915112345678": "ABSENT",
+	})
+	redisServer, client := testutil.NewRedis(t)
+
+	verifier := phoneverify.NewCachingVerifier(
+		phoneverify.NewHLRLookupsVerifier(server.URL, "key", "secret", clock.NewFake(start)),
+		cache.NewRedisCache(client, cache.WithPrefix("phoneverify:")),
+		phoneverify.WithSecret("test-secret"),
+		phoneverify.WithTTL(24*time.Hour),
+		phoneverify.WithRetryTTL(time.Hour),
+	)
+	ctx := context.Background()
+	connected, absent := phone(t, "+4917612345678"), phone(t, "+4915112345678")
+	// cacheKey finds the only key holding a result with ttl
+	cacheKey := func(ttl time.Duration) string {
+		t.Helper()
+		for _, key := range redisServer.Keys() {
+			if redisServer.TTL(key) == ttl {
+				return key
+			}
+		}
+		t.Fatalf("no key with a TTL of %s in %v", ttl, redisServer.Keys())
+		return ""
+	}
+
+	first, err := verifier.Verify(ctx, connected)
+	require.NoError(t, err)
+	second, err := verifier.Verify(ctx, connected)
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), calls.Load())
+	assert.Equal(t, first.Status, second.Status)
+	assert.Equal(t, first.Carrier, second.Carrier)
+	assert.True(t, first.CheckedAt.Equal(second.CheckedAt))
+	key := cacheKey(24 * time.Hour)
+	assert.Regexp(t, `^phoneverify:[0-9a-f]{64}$`, key, "numbers are hashed")
+	assert.NotContains(t, key, "4917612345678")
+
+	_, err = verifier.Verify(ctx, absent)
+	require.NoError(t, err)
+	assert.NotEmpty(t, cacheKey(time.Hour), "absent numbers are retried sooner")
+
+	require.NoError(t, verifier.Forget(ctx, connected))
+	_, err = verifier.Verify(ctx, connected)
+	require.NoError(t, err)
+	assert.Equal(t, int32(3), calls.Load())
+
+	erased, err := verifier.Erase(ctx, erasure.Subject{Phones: []i18n.Phone{connected, absent, phone(t, "+4917600000000")}}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, 2, erased, "only cached numbers count")
+	assert.Empty(t, redisServer.Keys())
+
+	// A broken cache falls through to the provider
+	redisServer.Close()
+	result, err := verifier.Verify(ctx, connected)
+	require.NoError(t, err)
+	assert.Equal(t, phoneverify.StatusReachable, result.Status)
+	assert.Equal(t, int32(4), calls.Load())
+}
+
+func TestRequireReachable(t *testing.T) {
+	verifier, err := phoneverify.NewStaticVerifier(map[string]string{
+		"+15550000000": "disconnected",
+		"+15550000001": "absent",
+		"+15550000002": "unknown",
+	}, clock.New())
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	_, err = phoneverify.RequireReachable(ctx, verifier, phone(t, "+15550000000"))
+	require.Error(t, err)
+	assert.Contains(t, validation.FromError(err).ByField(), "phone")
+
+	for _, number := range []string{"+15550000001", "+15550000002", "+15551234567"} {
+		_, err := phoneverify.RequireReachable(ctx, verifier, phone(t, number))
+		assert.NoError(t, err, number)
+	}
+
+	_, err = phoneverify.RequireReachable(ctx, verifier, i18n.Phone{CountryCode: "1"})
+	assert.Error(t, err)
+}
+
+func TestNewVerifier(t *testing.T) {
+	verifier, err := phoneverify.NewVerifier(config.PhoneVerifyConfig{Provider: "static"}, clock.New())
+	require.NoError(t, err)
+	assert.Equal(t, "static", verifier.Name())
+
+	verifier, err = phoneverify.NewVerifier(config.PhoneVerifyConfig{Provider: "hlrlookups", APIKey: "k", APISecret: "s"}, clock.New())
+	require.NoError(t, err)
+	assert.Equal(t, "hlrlookups", verifier.Name())
+
+	_, err = phoneverify.NewVerifier(config.PhoneVerifyConfig{Provider: "hlrlookups"}, clock.New())
+	assert.Error(t, err)
+	_, err = phoneverify.NewVerifier(config.PhoneVerifyConfig{Provider: "carrier-pigeon"}, clock.New())
+	assert.Error(t, err)
+}
@@ -0,0 +1,230 @@
+package projection_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"golang-arch/internal/shared/domain/domainerror"
+	"golang-arch/internal/shared/events"
+	"golang-arch/internal/shared/eventsource"
+	"golang-arch/internal/shared/projection"
+	"golang-arch/pkg/clock"
+	"golang-arch/pkg/metrics"
+)
+
+var start = time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+type deposited struct {
+	events.Base
+	Amount int64 `json:"amount"`
+}
+
+func (deposited) EventName() string { return "account.deposited" }
+
+type renamed struct {
+	events.Base
+	Name string `json:"name"`
+}
+
+func (renamed) EventName() string { return "account.renamed" }
+
+// balances is a read model of account balances
+type balances struct {
+	rows   map[string]int64
+	seen   []int64
+	resets int
+	failAt int64
+}
+
+func (b *balances) projection() projection.Projection {
+	return projection.Projection{
+		Name:   "balances",
+		Events: []string{"account.deposited"},
+		Handle: func(_ context.Context, event events.Event, record eventsource.Record) error {
+			if record.Position == b.failAt {
+				return errors.New("database unavailable")
+			}
+			b.rows[record.Envelope.AggregateID] += event.(deposited).Amount
+			b.seen = append(b.seen, record.Position)
+			return nil
+		},
+		Reset: func(context.Context) error {
+			b.rows = make(map[string]int64)
+			b.resets++
+			return nil
+		},
+	}
+}
+
+type fixture struct {
+	journal     *eventsource.MemoryStore
+	checkpoints *projection.MemoryCheckpoints
+	registry    *metrics.Registry
+	runner      *projection.Runner
+	balances    *balances
+}
+
+func newFixture(t *testing.T) *fixture {
+	t.Helper()
+	clk := clock.NewFake(start)
+	schemas := events.NewRegistry()
+	require.NoError(t, schemas.Register(events.Schema{Name: "account.deposited", Version: 1, Type: deposited{}}))
+	require.NoError(t, schemas.Register(events.Schema{Name: "account.renamed", Version: 1, Type: renamed{}}))
+
+	f := &fixture{
+		journal:     eventsource.NewMemoryStore(clk),
+		checkpoints: projection.NewMemoryCheckpoints(clk),
+		registry:    metrics.NewRegistry(),
+		balances:    &balances{rows: make(map[string]int64)},
+	}
+	lag, err := f.registry.Gauge("worker.projections.lag", "", "{event}")
+	require.NoError(t, err)
+	f.runner = projection.NewRunner(f.journal, schemas, f.checkpoints,
+		projection.WithLagGauge(lag), projection.WithBatchSize(2))
+	f.runner.Register(f.balances.projection())
+	return f
+}
+
+// append stores events for account id after its version; payloads with a
+// name are renames
+func (f *fixture) append(t *testing.T, id string, version int, payloads ...string) {
+	t.Helper()
+	envelopes := make([]events.Envelope, 0, len(payloads))
+	for i, payload := range payloads {
+		name := "account.deposited"
+		if strings.Contains(payload, `"name"`) {
+			name = "account.renamed"
+		}
+		envelopes = append(envelopes, events.Envelope{
+			ID: fmt.Sprintf("%s-%d", id, version+i+1), Name: name, Version: 1,
+			AggregateType: "account", AggregateID: id, Payload: json.RawMessage(payload),
+		})
+	}
+	require.NoError(t, f.journal.Append(context.Background(), "account", id, version, envelopes))
+}
+
+func (f *fixture) lag() float64 {
+	for _, instrument := range f.registry.Snapshot() {
+		if instrument.Name == "worker.projections.lag" {
+			for _, series := range instrument.Series {
+				if series.Labels["projection"] == "balances" {
+					return series.Value
+				}
+			}
+		}
+	}
+	return -1
+}
+
+func TestRunner_CatchesUpAndCheckpoints(t *testing.T) {
+	f := newFixture(t)
+	ctx := context.Background()
+	f.append(t, "a-1", 0, `{"amount":10}`, `{"name":"Main"}`, `{"amount":5}`)
+	f.append(t, "a-2", 0, `{"amount":7}`, `{"amount":1}`)
+
+	require.NoError(t, f.runner.Run(ctx))
+	assert.Equal(t, map[string]int64{"a-1": 15, "a-2": 8}, f.balances.rows)
+	assert.Equal(t, []int64{1, 3, 4, 5}, f.balances.seen, "other events are skipped")
+
+	checkpoint, err := f.checkpoints.Get(ctx, "balances")
+	require.NoError(t, err)
+	assert.Equal(t, int64(5), checkpoint.Position)
+	assert.Zero(t, f.lag())
+
+	f.append(t, "a-1", 3, `{"amount":100}`)
+	require.NoError(t, f.runner.Run(ctx))
+	assert.EqualValues(t, 115, f.balances.rows["a-1"])
+	assert.Equal(t, []int64{1, 3, 4, 5, 6}, f.balances.seen, "only new events are handled")
+}
+
+func TestRunner_FailureStopsAtFailedEvent(t *testing.T) {
+	f := newFixture(t)
+	ctx := context.Background()
+	f.append(t, "a-1", 0, `{"amount":1}`, `{"amount":2}`, `{"amount":3}`, `{"amount":4}`)
+	f.balances.failAt = 3
+
+	err := f.runner.Run(ctx)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "position 3")
+	checkpoint, err := f.checkpoints.Get(ctx, "balances")
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), checkpoint.Position)
+	assert.Equal(t, float64(2), f.lag())
+
+	f.balances.failAt = 0
+	require.NoError(t, f.runner.Run(ctx))
+	assert.EqualValues(t, 10, f.balances.rows["a-1"])
+	assert.Equal(t, []int64{1, 2, 3, 4}, f.balances.seen, "the failed event is retried once")
+}
+
+func TestRunner_Rebuild(t *testing.T) {
+	f := newFixture(t)
+	ctx := context.Background()
+	f.append(t, "a-1", 0, `{"amount":10}`, `{"amount":20}`)
+	require.NoError(t, f.runner.Run(ctx))
+	f.balances.rows["a-1"] = 999 // a read model gone wrong
+
+	err := f.runner.Rebuild(ctx, "unknown")
+	assert.Equal(t, domainerror.NotFound, domainerror.KindOf(err))
+	require.NoError(t, f.runner.Rebuild(ctx, "balances"))
+	statuses, err := f.runner.Status(ctx)
+	require.NoError(t, err)
+	require.Len(t, statuses, 1)
+	assert.True(t, statuses[0].Rebuild)
+
+	require.NoError(t, f.runner.Run(ctx))
+	assert.Equal(t, 1, f.balances.resets)
+	assert.Equal(t, map[string]int64{"a-1": 30}, f.balances.rows)
+	checkpoint, err := f.checkpoints.Get(ctx, "balances")
+	require.NoError(t, err)
+	assert.False(t, checkpoint.Rebuild)
+	assert.Equal(t, int64(2), checkpoint.Position)
+}
+
+func TestRunner_Status(t *testing.T) {
+	f := newFixture(t)
+	ctx := context.Background()
+	require.NoError(t, f.checkpoints.Advance(ctx, "retired", 1))
+	f.append(t, "a-1", 0, `{"amount":1}`, `{"amount":2}`, `{"amount":3}`)
+
+	statuses, err := f.runner.Status(ctx)
+	require.NoError(t, err)
+	require.Len(t, statuses, 2)
+	assert.Equal(t, "balances", statuses[0].Name)
+	assert.True(t, statuses[0].Registered)
+	assert.Equal(t, int64(3), statuses[0].Lag)
+	assert.Equal(t, "retired", statuses[1].Name)
+	assert.False(t, statuses[1].Registered, "checkpoints of projections not registered here are listed too")
+	assert.Equal(t, int64(2), statuses[1].Lag)
+	assert.Equal(t, int64(3), statuses[1].Head)
+}
+
+func TestRunner_SkipsLockedProjection(t *testing.T) {
+	f := newFixture(t)
+	ctx := context.Background()
+	f.append(t, "a-1", 0, `{"amount":1}`)
+
+	release, ok, err := f.checkpoints.TryLock(ctx, "balances")
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.NoError(t, f.runner.Run(ctx))
+	assert.Empty(t, f.balances.seen, "another runner holds the projection")
+
+	release()
+	require.NoError(t, f.runner.Run(ctx))
+	assert.Equal(t, []int64{1}, f.balances.seen)
+}
+
+func TestRunner_RegisterRejectsDuplicates(t *testing.T) {
+	f := newFixture(t)
+	assert.Panics(t, func() { f.runner.Register(f.balances.projection()) })
+	assert.Panics(t, func() { f.runner.Register(projection.Projection{Name: "incomplete"}) })
+}
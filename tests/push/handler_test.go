@@ -0,0 +1,118 @@
+package push_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"golang-arch/internal/shared/config"
+	"golang-arch/internal/shared/push"
+	"golang-arch/internal/shared/rbac"
+)
+
+// Bearer tokens of the test principals: an operator and recipient u1
+const (
+	adminToken     = "admin-token"
+	recipientToken = "u1-token"
+)
+
+func newServer(t *testing.T, service *push.Service) *gin.Engine {
+	t.Helper()
+	authz, err := rbac.NewAuthorizer(config.RBACConfig{
+		Principals: []config.PrincipalConfig{
+			{Name: "ops", Token: adminToken, Roles: []string{"privacy-admin"}},
+			{Name: "u1", Token: recipientToken},
+		},
+		Roles: map[string][]string{"privacy-admin": {string(push.PermissionAdmin)}},
+	})
+	require.NoError(t, err)
+
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	push.NewHandler(service, authz).Register(engine.Group("/api/v1"))
+	return engine
+}
+
+func do(engine *gin.Engine, method, path, body string) *httptest.ResponseRecorder {
+	return doAs(engine, adminToken, method, path, body)
+}
+
+func doAs(engine *gin.Engine, token, method, path, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	rec := httptest.NewRecorder()
+	engine.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestHandlerDevices(t *testing.T) {
+	f := newFixture(t)
+	engine := newServer(t, f.service)
+
+	rec := do(engine, http.MethodPost, "/api/v1/push/devices/u1", `{"token":"fcm:abc","platform":"android","locale":"id-ID"}`)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	rec = do(engine, http.MethodPost, "/api/v1/push/devices/u1", `{"token":"","platform":"symbian"}`)
+	assert.Equal(t, http.StatusBadRequest, rec.Code, rec.Body.String())
+	assert.Contains(t, rec.Body.String(), "platform")
+
+	rec = do(engine, http.MethodGet, "/api/v1/push/devices/u1", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	var listed struct {
+		Data []push.Device `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &listed))
+	require.Len(t, listed.Data, 1)
+	assert.Equal(t, "fcm:abc", listed.Data[0].Token)
+	assert.Equal(t, push.PlatformAndroid, listed.Data[0].Platform)
+
+	rec = do(engine, http.MethodDelete, "/api/v1/push/devices/u2/fcm:abc", "")
+	assert.Equal(t, http.StatusNotFound, rec.Code, "only the owner unregisters a device")
+	rec = do(engine, http.MethodDelete, "/api/v1/push/devices/u1/fcm:abc", "")
+	assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+}
+
+func TestHandlerAuthorization(t *testing.T) {
+	f := newFixture(t)
+	engine := newServer(t, f.service)
+	device := `{"token":"fcm:u2","platform":"android"}`
+	require.Equal(t, http.StatusOK, do(engine, http.MethodPost, "/api/v1/push/devices/u2", device).Code)
+
+	tests := []struct {
+		name   string
+		token  string
+		method string
+		path   string
+		body   string
+		status int
+	}{
+		{"anonymous list", "", http.MethodGet, "/api/v1/push/devices/u1", "", http.StatusUnauthorized},
+		{"unknown token", "stolen", http.MethodPost, "/api/v1/push/devices/u1", `{"token":"fcm:u1","platform":"android"}`, http.StatusUnauthorized},
+		{"recipient registers own", recipientToken, http.MethodPost, "/api/v1/push/devices/u1", `{"token":"fcm:u1","platform":"android"}`, http.StatusOK},
+		{"recipient lists own", recipientToken, http.MethodGet, "/api/v1/push/devices/u1", "", http.StatusOK},
+		{"recipient lists another", recipientToken, http.MethodGet, "/api/v1/push/devices/u2", "", http.StatusForbidden},
+		{"recipient registers for another", recipientToken, http.MethodPost, "/api/v1/push/devices/u2", device, http.StatusForbidden},
+		{"recipient unregisters another", recipientToken, http.MethodDelete, "/api/v1/push/devices/u2/fcm:u2", "", http.StatusForbidden},
+		{"admin lists any", adminToken, http.MethodGet, "/api/v1/push/devices/u2", "", http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := doAs(engine, tt.token, tt.method, tt.path, tt.body)
+			assert.Equal(t, tt.status, rec.Code, rec.Body.String())
+		})
+	}
+
+	devices, err := f.service.Devices(context.Background(), "u2")
+	require.NoError(t, err)
+	assert.Len(t, devices, 1, "u2's device was not unregistered by u1")
+}
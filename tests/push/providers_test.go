@@ -0,0 +1,161 @@
+package push_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"golang-arch/internal/shared/push"
+	"golang-arch/pkg/clock"
+)
+
+var payload = push.Payload{Title: "Order shipped", Body: "Order A-17 is on its way", Data: map[string]string{"order": "A-17"}}
+
+func serviceAccount(t *testing.T, tokenURI string) []byte {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+	credentials, err := json.Marshal(map[string]string{
+		"type":         "service_account",
+		"project_id":   "demo-app",
+		"client_email": "push@demo-app.iam.gserviceaccount.com",
+		"private_key":  string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})),
+		"token_uri":    tokenURI,
+	})
+	require.NoError(t, err)
+	return credentials
+}
+
+func TestFCMSender(t *testing.T) {
+	var tokenRequests atomic.Int32
+	var message map[string]any
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /token", func(w http.ResponseWriter, r *http.Request) {
+		tokenRequests.Add(1)
+		require.NoError(t, r.ParseForm())
+		assert.Equal(t, "urn:ietf:params:oauth:grant-type:jwt-bearer", r.Form.Get("grant_type"))
+		assert.Len(t, strings.Split(r.Form.Get("assertion"), "."), 3)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = io.WriteString(w, `{"access_token":"ya29.token","expires_in":3600}`)
+	})
+	mux.HandleFunc("POST /v1/projects/demo-app/messages:send", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer ya29.token", r.Header.Get("Authorization"))
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&message))
+		if message["message"].(map[string]any)["token"] == "stale" {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = io.WriteString(w, `{"error":{"code":404,"status":"NOT_FOUND","message":"Requested entity was not found.",
+				"details":[{"@type":"type.googleapis.com/google.firebase.fcm.v1.FcmError","errorCode":"UNREGISTERED"}]}}`)
+			return
+		}
+		_, _ = io.WriteString(w, `{"name":"projects/demo-app/messages/1"}`)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	clk := clock.NewFake(start)
+	sender, err := push.NewFCMSender(server.URL, serviceAccount(t, server.URL+"/token"), clk)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	require.NoError(t, sender.Send(ctx, push.Device{Token: "fresh", Platform: push.PlatformAndroid}, payload))
+	sent := message["message"].(map[string]any)
+	assert.Equal(t, map[string]any{"title": "Order shipped", "body": "Order A-17 is on its way"}, sent["notification"])
+	assert.Equal(t, map[string]any{"order": "A-17"}, sent["data"])
+
+	err = sender.Send(ctx, push.Device{Token: "stale", Platform: push.PlatformAndroid}, payload)
+	assert.ErrorIs(t, err, push.ErrInvalidToken)
+	assert.EqualValues(t, 1, tokenRequests.Load(), "the access token is reused")
+
+	clk.Advance(time.Hour)
+	require.NoError(t, sender.Send(ctx, push.Device{Token: "fresh", Platform: push.PlatformAndroid}, payload))
+	assert.EqualValues(t, 2, tokenRequests.Load(), "an expiring access token is replaced")
+
+	_, err = push.NewFCMSender(server.URL, []byte(`{"project_id":"demo-app"}`), clk)
+	assert.Error(t, err)
+}
+
+func TestAPNsSender(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+	signingKey := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+
+	var body map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "com.example.app", r.Header.Get("apns-topic"))
+		assert.Equal(t, "alert", r.Header.Get("apns-push-type"))
+		verifyES256(t, &key.PublicKey, strings.TrimPrefix(r.Header.Get("Authorization"), "bearer "))
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+
+		switch strings.TrimPrefix(r.URL.Path, "/3/device/") {
+		case "gone":
+			w.WriteHeader(http.StatusGone)
+			_, _ = io.WriteString(w, `{"reason":"Unregistered","timestamp":1704110400000}`)
+		case "busy":
+			w.WriteHeader(http.StatusTooManyRequests)
+			_, _ = io.WriteString(w, `{"reason":"TooManyRequests"}`)
+		}
+	}))
+	defer server.Close()
+
+	sender, err := push.NewAPNsSender(server.URL, signingKey, "KEY123", "TEAM456", "com.example.app", clock.NewFake(start))
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	require.NoError(t, sender.Send(ctx, push.Device{Token: "abc123", Platform: push.PlatformIOS}, payload))
+	assert.Equal(t, "A-17", body["order"])
+	aps := body["aps"].(map[string]any)
+	assert.Equal(t, map[string]any{"title": "Order shipped", "body": "Order A-17 is on its way"}, aps["alert"])
+
+	assert.ErrorIs(t, sender.Send(ctx, push.Device{Token: "gone", Platform: push.PlatformIOS}, payload), push.ErrInvalidToken)
+	err = sender.Send(ctx, push.Device{Token: "busy", Platform: push.PlatformIOS}, payload)
+	require.Error(t, err)
+	assert.NotErrorIs(t, err, push.ErrInvalidToken)
+}
+
+// verifyES256 checks the provider token's header, claims and signature
+func verifyES256(t *testing.T, key *ecdsa.PublicKey, token string) {
+	t.Helper()
+	parts := strings.Split(token, ".")
+	require.Len(t, parts, 3)
+
+	var header, claims map[string]any
+	decode := func(part string, into *map[string]any) {
+		data, err := base64.RawURLEncoding.DecodeString(part)
+		require.NoError(t, err)
+		require.NoError(t, json.Unmarshal(data, into))
+	}
+	decode(parts[0], &header)
+	decode(parts[1], &claims)
+	assert.Equal(t, map[string]any{"alg": "ES256", "kid": "KEY123"}, header)
+	assert.Equal(t, "TEAM456", claims["iss"])
+	assert.EqualValues(t, start.Unix(), claims["iat"])
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	require.NoError(t, err)
+	require.Len(t, signature, 64)
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	r, s := new(big.Int).SetBytes(signature[:32]), new(big.Int).SetBytes(signature[32:])
+	assert.True(t, ecdsa.Verify(key, digest[:], r, s), "invalid provider token signature")
+}
@@ -0,0 +1,246 @@
+package push_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"golang-arch/internal/shared/domain/domainerror"
+	i18n "golang-arch/internal/shared/domain/internationalization"
+	"golang-arch/internal/shared/notify"
+	"golang-arch/internal/shared/push"
+	"golang-arch/internal/shared/templates"
+	"golang-arch/internal/shared/testutil"
+	"golang-arch/pkg/clock"
+)
+
+var start = time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+// provider records pushes; tokens in invalid are rejected for good and
+// failures fail that many sends first
+type provider struct {
+	mu       sync.Mutex
+	sent     []sent
+	invalid  map[string]bool
+	failures int
+}
+
+type sent struct {
+	device  push.Device
+	payload push.Payload
+}
+
+func (p *provider) Name() string { return "fake" }
+
+func (p *provider) Send(_ context.Context, device push.Device, payload push.Payload) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.invalid[device.Token] {
+		return fmt.Errorf("%w: gone", push.ErrInvalidToken)
+	}
+	if p.failures > 0 {
+		p.failures--
+		return errors.New("provider unavailable")
+	}
+	p.sent = append(p.sent, sent{device: device, payload: payload})
+	return nil
+}
+
+func (p *provider) pushes() []sent {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]sent(nil), p.sent...)
+}
+
+type fixture struct {
+	service  *push.Service
+	devices  *push.MemoryStore
+	provider *provider
+	clock    *clock.Fake
+}
+
+func newFixture(t *testing.T) *fixture {
+	t.Helper()
+	_, client := testutil.NewRedis(t)
+
+	catalog, err := templates.ParseCatalog([]byte(`{
+		"order.shipped.title": {"en": "Order shipped", "de": "Bestellung versandt"},
+		"order.shipped.body": {"en": "Order {order} is on its way", "de": "Bestellung {order} ist unterwegs"}
+	}`))
+	require.NoError(t, err)
+	views, err := templates.NewEngine(templates.WithCatalog(catalog))
+	require.NoError(t, err)
+
+	clk := clock.NewFake(start)
+	fake := &provider{invalid: map[string]bool{}}
+	devices := push.NewMemoryStore()
+	service := push.NewService(devices, client,
+		map[push.Platform]push.PushSender{push.PlatformAndroid: fake, push.PlatformIOS: fake},
+		push.WithClock(clk),
+		push.WithViews(views),
+		push.WithMaxAttempts(3),
+		push.WithRetryBackoff(time.Minute),
+	)
+	return &fixture{service: service, devices: devices, provider: fake, clock: clk}
+}
+
+func (f *fixture) register(t *testing.T, recipient, token string, platform push.Platform, locale i18n.Locale) {
+	t.Helper()
+	_, err := f.service.Register(context.Background(), push.Device{Token: token, RecipientID: recipient, Platform: platform, Locale: locale})
+	require.NoError(t, err)
+}
+
+func shipped(recipient string) notify.Message {
+	return notify.Message{
+		Notification: notify.Notification{Event: "order.shipped", RecipientID: recipient, Template: "order.shipped", Data: map[string]any{"order": "A-17", "items": 2}},
+		Channel:      notify.ChannelPush,
+	}
+}
+
+func TestRegister(t *testing.T) {
+	f := newFixture(t)
+	ctx := context.Background()
+
+	_, err := f.service.Register(ctx, push.Device{RecipientID: "u1", Platform: "blackberry"})
+	assert.ErrorIs(t, err, domainerror.Invalid)
+	_, err = f.service.Register(ctx, push.Device{Token: "t1", RecipientID: "u1", Platform: push.PlatformWeb})
+	assert.ErrorIs(t, err, domainerror.Invalid, "web has no sender")
+
+	f.register(t, "u1", "t1", push.PlatformAndroid, "")
+	f.clock.Advance(time.Hour)
+	f.register(t, "u2", "t1", push.PlatformAndroid, "de")
+
+	devices, err := f.service.Devices(ctx, "u1")
+	require.NoError(t, err)
+	assert.Empty(t, devices, "the token moved to the user now signed in")
+	devices, err = f.service.Devices(ctx, "u2")
+	require.NoError(t, err)
+	require.Len(t, devices, 1)
+	assert.Equal(t, start, devices[0].CreatedAt)
+	assert.Equal(t, start.Add(time.Hour), devices[0].LastSeenAt)
+
+	assert.ErrorIs(t, f.service.Unregister(ctx, "u1", "t1"), domainerror.NotFound)
+	require.NoError(t, f.service.Unregister(ctx, "u2", "t1"))
+	reaches, err := f.service.Reaches(ctx, "u2")
+	require.NoError(t, err)
+	assert.False(t, reaches)
+}
+
+func TestSendLocalizesPerDevice(t *testing.T) {
+	f := newFixture(t)
+	ctx := context.Background()
+	f.register(t, "u1", "phone", push.PlatformAndroid, "de")
+	f.register(t, "u1", "tablet", push.PlatformIOS, "")
+
+	require.NoError(t, f.service.Send(ctx, shipped("u1")))
+	pending, err := f.service.Pending(ctx)
+	require.NoError(t, err)
+	assert.EqualValues(t, 2, pending)
+	assert.Empty(t, f.provider.pushes(), "pushes wait for the delivery job")
+
+	require.NoError(t, f.service.Deliver(ctx))
+	pushes := f.provider.pushes()
+	require.Len(t, pushes, 2)
+	byToken := map[string]push.Payload{}
+	for _, p := range pushes {
+		byToken[p.device.Token] = p.payload
+	}
+	assert.Equal(t, "Bestellung versandt", byToken["phone"].Title)
+	assert.Equal(t, "Bestellung A-17 ist unterwegs", byToken["phone"].Body)
+	assert.Equal(t, "Order A-17 is on its way", byToken["tablet"].Body)
+	assert.Equal(t, map[string]string{"order": "A-17", "items": "2", "event": "order.shipped"}, byToken["tablet"].Data)
+
+	// The recipient's language wins over the device's
+	message := shipped("u1")
+	message.Locale = "en"
+	message.Address = "phone"
+	require.NoError(t, f.service.Send(ctx, message))
+	require.NoError(t, f.service.Deliver(ctx))
+	pushes = f.provider.pushes()
+	require.Len(t, pushes, 3, "an address selects one device")
+	assert.Equal(t, "Order shipped", pushes[2].payload.Title)
+
+	assert.Error(t, f.service.Send(ctx, shipped("nobody")))
+}
+
+func TestDeliverRetriesWithBackoff(t *testing.T) {
+	f := newFixture(t)
+	ctx := context.Background()
+	f.register(t, "u1", "phone", push.PlatformAndroid, "")
+	f.provider.failures = 2
+
+	require.NoError(t, f.service.Send(ctx, shipped("u1")))
+	assert.Error(t, f.service.Deliver(ctx))
+	require.NoError(t, f.service.Deliver(ctx), "the retry is not due yet")
+	assert.Empty(t, f.provider.pushes())
+
+	f.clock.Advance(time.Minute)
+	assert.Error(t, f.service.Deliver(ctx))
+	f.clock.Advance(time.Minute)
+	require.NoError(t, f.service.Deliver(ctx), "the second retry waits twice as long")
+	assert.Empty(t, f.provider.pushes())
+
+	f.clock.Advance(time.Minute)
+	require.NoError(t, f.service.Deliver(ctx))
+	assert.Len(t, f.provider.pushes(), 1)
+
+	// A delivery failing max_attempts times is dropped
+	f.provider.failures = 3
+	require.NoError(t, f.service.Send(ctx, shipped("u1")))
+	for range 3 {
+		assert.Error(t, f.service.Deliver(ctx))
+		f.clock.Advance(10 * time.Minute)
+	}
+	pending, err := f.service.Pending(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, pending)
+}
+
+func TestDeliverUnregistersInvalidTokens(t *testing.T) {
+	f := newFixture(t)
+	ctx := context.Background()
+	f.register(t, "u1", "old-phone", push.PlatformAndroid, "")
+	f.register(t, "u1", "phone", push.PlatformAndroid, "")
+	f.provider.invalid["old-phone"] = true
+
+	require.NoError(t, f.service.Send(ctx, shipped("u1")))
+	require.NoError(t, f.service.Deliver(ctx), "invalid tokens are not retried")
+
+	devices, err := f.service.Devices(ctx, "u1")
+	require.NoError(t, err)
+	require.Len(t, devices, 1)
+	assert.Equal(t, "phone", devices[0].Token)
+	assert.Len(t, f.provider.pushes(), 1)
+	pending, err := f.service.Pending(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, pending)
+}
+
+func TestRouterUsesRegisteredDevices(t *testing.T) {
+	f := newFixture(t)
+	ctx := context.Background()
+	router := notify.NewRouter(notify.NewMemoryStore(),
+		notify.WithClock(f.clock),
+		notify.WithSender(notify.ChannelPush, f.service),
+		notify.WithDefaultChannels(notify.ChannelPush),
+	)
+	defer router.Close()
+	n := notify.Notification{Event: "order.shipped", RecipientID: "u1", Template: "order.shipped", Data: map[string]string{"order": "A-17"}}
+
+	route, err := router.Route(ctx, n)
+	assert.ErrorIs(t, err, notify.ErrUndeliverable)
+	assert.Equal(t, []notify.Skip{{Channel: notify.ChannelPush, Reason: notify.SkipNoAddress}}, route.Skipped)
+
+	f.register(t, "u1", "phone", push.PlatformAndroid, "")
+	route, err = router.Send(ctx, n)
+	require.NoError(t, err)
+	assert.Equal(t, notify.ChannelPush, route.Message.Channel)
+	require.NoError(t, f.service.Deliver(ctx))
+	assert.Len(t, f.provider.pushes(), 1)
+}
@@ -0,0 +1,80 @@
+package push_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"database/sql/driver"
+	"encoding/base64"
+	"encoding/hex"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"golang-arch/internal/shared/fieldcrypt"
+	"golang-arch/internal/shared/push"
+)
+
+// captured matches any argument and keeps it
+type captured struct{ value driver.Value }
+
+func (c *captured) Match(v driver.Value) bool {
+	c.value = v
+	return true
+}
+
+func newEncryptor(t *testing.T) *fieldcrypt.Encryptor {
+	t.Helper()
+	key := base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{1}, fieldcrypt.KeySize))
+	provider, err := fieldcrypt.NewStaticKeyProvider("k1="+key, "")
+	require.NoError(t, err)
+	encryptor, err := fieldcrypt.NewEncryptor(context.Background(), provider)
+	require.NoError(t, err)
+	return encryptor
+}
+
+func TestPostgresStore_EncryptsTokens(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+	store := push.NewPostgresStore(db, push.WithEncryption(newEncryptor(t)))
+	ctx := context.Background()
+
+	sum := sha256.Sum256([]byte("fcm:abc"))
+	hash := hex.EncodeToString(sum[:])
+	sealed := &captured{}
+	mock.ExpectQuery("INSERT INTO push_devices").
+		WithArgs(hash, nil, sealed, "u1", push.PlatformAndroid, "", start).
+		WillReturnRows(sqlmock.NewRows([]string{"created_at"}).AddRow(start))
+	_, err = store.Save(ctx, push.Device{Token: "fcm:abc", RecipientID: "u1", Platform: push.PlatformAndroid, LastSeenAt: start})
+	require.NoError(t, err)
+	assert.NotContains(t, string(sealed.value.([]byte)), "fcm:abc")
+
+	columns := []string{"token", "sealed_token", "recipient_id", "platform", "locale", "created_at", "last_seen_at"}
+	mock.ExpectQuery("SELECT token, sealed_token").
+		WithArgs("u1").
+		WillReturnRows(sqlmock.NewRows(columns).
+			AddRow(nil, sealed.value, "u1", "android", "", start, start).
+			AddRow("apns:legacy", nil, "u1", "ios", "", start, start))
+	devices, err := store.Devices(ctx, "u1")
+	require.NoError(t, err)
+	require.Len(t, devices, 2)
+	assert.Equal(t, "fcm:abc", devices[0].Token)
+	assert.Equal(t, "apns:legacy", devices[1].Token, "devices registered before encryption stay readable")
+
+	mock.ExpectExec("DELETE FROM push_devices WHERE token_hash").
+		WithArgs(hash).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	deleted, err := store.Delete(ctx, "fcm:abc")
+	require.NoError(t, err)
+	assert.True(t, deleted)
+
+	mock.ExpectQuery("SELECT token, sealed_token").
+		WithArgs("u1").
+		WillReturnRows(sqlmock.NewRows(columns).AddRow(nil, sealed.value, "u1", "android", "", start, start))
+	_, err = push.NewPostgresStore(db).Devices(ctx, "u1")
+	assert.ErrorContains(t, err, "encryption is disabled")
+	require.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,98 @@
+package ratelimit_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"golang-arch/internal/shared/ratelimit"
+	"golang-arch/internal/shared/testutil"
+)
+
+func newClient(t *testing.T) (*redis.Client, *miniredis.Miniredis) {
+	t.Helper()
+	server, client := testutil.NewRedis(t)
+	return client, server
+}
+
+func TestLimiter_Allow(t *testing.T) {
+	client, server := newClient(t)
+	limiter := ratelimit.NewLimiter(client, "test", 2, time.Minute)
+	ctx := context.Background()
+
+	decision, err := limiter.Allow(ctx, "a")
+	require.NoError(t, err)
+	assert.True(t, decision.Allowed)
+	assert.Equal(t, 1, decision.Remaining)
+	decision, err = limiter.Allow(ctx, "a")
+	require.NoError(t, err)
+	assert.True(t, decision.Allowed)
+	assert.Zero(t, decision.Remaining)
+
+	decision, err = limiter.Allow(ctx, "a")
+	require.NoError(t, err)
+	assert.False(t, decision.Allowed)
+	assert.Equal(t, time.Minute, decision.RetryAfter)
+
+	decision, err = limiter.Allow(ctx, "b")
+	require.NoError(t, err)
+	assert.True(t, decision.Allowed, "keys are counted separately")
+
+	server.FastForward(time.Minute)
+	decision, err = limiter.Allow(ctx, "a")
+	require.NoError(t, err)
+	assert.True(t, decision.Allowed, "the window resets")
+
+	unlimited := ratelimit.NewLimiter(client, "off", 0, time.Minute)
+	for i := 0; i < 5; i++ {
+		decision, err = unlimited.Allow(ctx, "a")
+		require.NoError(t, err)
+		assert.True(t, decision.Allowed)
+	}
+}
+
+func TestLimiter_Middleware(t *testing.T) {
+	client, server := newClient(t)
+	limiter := ratelimit.NewLimiter(client, "test", 1, time.Minute)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/limited", limiter.Middleware(ratelimit.ByConsumer("X-API-Key")), func(c *gin.Context) {
+		c.Status(http.StatusNoContent)
+	})
+	call := func(apiKey string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/limited", nil)
+		if apiKey != "" {
+			req.Header.Set("X-API-Key", apiKey)
+		}
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := call("key-1")
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	assert.Equal(t, "1", rec.Header().Get(ratelimit.HeaderLimit))
+	assert.Equal(t, "0", rec.Header().Get(ratelimit.HeaderRemaining))
+
+	rec = call("key-1")
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+	assert.Equal(t, "60", rec.Header().Get("Retry-After"))
+	assert.Contains(t, rec.Body.String(), "TOO_MANY_REQUESTS")
+
+	assert.Equal(t, http.StatusNoContent, call("key-2").Code, "other API keys have their own limit")
+	assert.Equal(t, http.StatusNoContent, call("").Code, "anonymous callers are limited by IP")
+	assert.Equal(t, http.StatusTooManyRequests, call("").Code)
+
+	// Requests are let through while Redis is down
+	server.Close()
+	assert.Equal(t, http.StatusNoContent, call("key-1").Code)
+}
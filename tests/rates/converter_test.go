@@ -0,0 +1,129 @@
+package rates_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"golang-arch/internal/shared/domain/domainerror"
+	"golang-arch/internal/shared/rates"
+	"golang-arch/pkg/clock"
+
+	i18n "golang-arch/internal/shared/domain/internationalization"
+)
+
+func currency(t *testing.T, code string) i18n.Currency {
+	t.Helper()
+	c, err := i18n.NewCurrencyFromCode(code)
+	require.NoError(t, err)
+	return *c
+}
+
+func TestMoney_ConvertToStaticConverter(t *testing.T) {
+	provider, err := rates.NewStaticProvider("USD", map[string]string{"EUR": "0.9221", "GBP": "0.7918"}, clock.NewFake(start))
+	require.NoError(t, err)
+	fixed, err := provider.FetchRates(context.Background())
+	require.NoError(t, err)
+	converter := rates.NewStaticConverter(fixed...)
+
+	tests := []struct {
+		name   string
+		amount int64
+		from   string
+		to     string
+		want   int64
+	}{
+		{"quoted", 10050, "USD", "EUR", 9267},
+		{"inverted", 1000, "EUR", "USD", 1084},
+		{"crossed", 10000, "EUR", "GBP", 8587},
+		{"same currency", 10000, "GBP", "GBP", 10000},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			amount, err := i18n.NewMoneyFromPrimitive(tt.amount, tt.from)
+			require.NoError(t, err)
+			converted, err := amount.ConvertTo(currency(t, tt.to), converter)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, converted.Amount)
+			assert.Equal(t, tt.to, converted.Currency.Code)
+		})
+	}
+
+	amount, err := i18n.NewMoneyFromPrimitive(100, "USD")
+	require.NoError(t, err)
+	_, err = amount.ConvertTo(currency(t, "JPY"), converter)
+	assert.ErrorIs(t, err, domainerror.NotFound)
+	_, err = amount.ConvertTo(currency(t, "EUR"), nil)
+	assert.ErrorIs(t, err, domainerror.Invalid)
+}
+
+func TestMoney_AddConverting(t *testing.T) {
+	provider, err := rates.NewStaticProvider("USD", map[string]string{"EUR": "0.9221"}, clock.NewFake(start))
+	require.NoError(t, err)
+	fixed, err := provider.FetchRates(context.Background())
+	require.NoError(t, err)
+	converter := rates.NewStaticConverter(fixed...)
+
+	total, err := i18n.NewMoneyFromPrimitive(10000, "EUR")
+	require.NoError(t, err)
+	usd, err := i18n.NewMoneyFromPrimitive(10050, "USD")
+	require.NoError(t, err)
+
+	sum, err := total.AddConverting(usd, converter)
+	require.NoError(t, err)
+	assert.Equal(t, int64(19267), sum.Amount, "USD 100.50 is EUR 92.67")
+	assert.Equal(t, "EUR", sum.Currency.Code)
+
+	difference, err := total.SubtractConverting(usd, converter)
+	require.NoError(t, err)
+	assert.Equal(t, int64(733), difference.Amount)
+
+	same, err := total.AddConverting(total, nil)
+	require.NoError(t, err, "the same currency needs no converter")
+	assert.Equal(t, int64(20000), same.Amount)
+
+	_, err = total.AddConverting(usd, nil)
+	assert.ErrorIs(t, err, domainerror.Invalid)
+	_, err = total.Add(usd)
+	assert.ErrorIs(t, err, domainerror.Invalid, "plain Add still refuses mixed currencies")
+}
+
+func TestMoney_ConvertToRejectsMismatchedRate(t *testing.T) {
+	amount, err := i18n.NewMoneyFromPrimitive(100, "USD")
+	require.NoError(t, err)
+
+	_, err = amount.ConvertTo(currency(t, "GBP"), wrongPair{})
+	assert.ErrorIs(t, err, domainerror.Invalid)
+}
+
+func TestConverter_RateSource(t *testing.T) {
+	amount, err := i18n.NewMoneyFromPrimitive(10000, "USD")
+	require.NoError(t, err)
+	converter := rates.NewConverter(context.Background(), fixedRates{"USD/GBP": "0.7918"})
+
+	converted, err := amount.ConvertTo(currency(t, "GBP"), converter)
+	require.NoError(t, err)
+	assert.Equal(t, int64(7918), converted.Amount)
+}
+
+func TestService_Converter(t *testing.T) {
+	f := newFixture(t)
+	f.expectSave()
+	require.NoError(t, f.service.Refresh(context.Background()))
+
+	amount, err := i18n.NewMoneyFromPrimitive(10050, "USD")
+	require.NoError(t, err)
+	converted, err := amount.ConvertTo(currency(t, "EUR"), f.service.Converter(context.Background()))
+	require.NoError(t, err)
+	assert.Equal(t, int64(9267), converted.Amount)
+}
+
+// wrongPair answers every lookup with a USD/EUR rate
+type wrongPair struct{}
+
+func (wrongPair) ExchangeRate(base, _ i18n.Currency) (*i18n.ExchangeRate, error) {
+	eur, _ := i18n.NewCurrencyFromCode("EUR")
+	return i18n.NewExchangeRateFromDecimal(base, *eur, "0.9221", i18n.Time{Epoch: start.Unix()})
+}
@@ -0,0 +1,114 @@
+package rates_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"golang-arch/internal/shared/domain/domainerror"
+	"golang-arch/internal/shared/rates"
+)
+
+const ecbFeed = `<?xml version="1.0" encoding="UTF-8"?>
+<gesmes:Envelope xmlns:gesmes="http://www.gesmes.org/xml/2002-08-01" xmlns="http://www.ecb.int/vocabulary/2002-08-01/eurofxref">
+	<gesmes:subject>Reference rates</gesmes:subject>
+	<Cube>
+		<Cube time="2024-01-02">
+			<Cube currency="USD" rate="1.0956"/>
+			<Cube currency="JPY" rate="155.52"/>
+			<Cube currency="ISK" rate="150.10"/>
+		</Cube>
+	</Cube>
+</gesmes:Envelope>`
+
+func TestECBProvider(t *testing.T) {
+	var requests, revalidated atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if r.Header.Get("If-None-Match") == `"2024-01-02"` {
+			revalidated.Add(1)
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"2024-01-02"`)
+		w.Write([]byte(ecbFeed))
+	}))
+	defer server.Close()
+
+	provider := rates.NewECBProvider(server.URL, time.Second)
+	fetched, err := provider.FetchRates(context.Background())
+	require.NoError(t, err)
+	require.Len(t, fetched, 2, "currencies the application does not support are skipped")
+	assert.Equal(t, "EUR", fetched[0].Base.Code)
+	assert.Equal(t, "JPY", fetched[0].Quote.Code)
+	assert.Equal(t, "155.52", fetched[0].DecimalString())
+	assert.Equal(t, "USD", fetched[1].Quote.Code)
+	assert.Equal(t, time.Date(2024, 1, 2, 14, 0, 0, 0, time.UTC).Unix(), fetched[1].Timestamp.Epoch)
+
+	again, err := provider.FetchRates(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, fetched, again, "an unchanged feed returns the rates parsed before")
+	assert.Equal(t, int32(2), requests.Load())
+	assert.Equal(t, int32(1), revalidated.Load())
+}
+
+func TestECBProvider_Errors(t *testing.T) {
+	status := http.StatusServiceUnavailable
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if status != http.StatusOK {
+			w.WriteHeader(status)
+			return
+		}
+		w.Write([]byte(`<Envelope><Cube></Cube></Envelope>`))
+	}))
+	defer server.Close()
+	provider := rates.NewECBProvider(server.URL, time.Second)
+
+	_, err := provider.FetchRates(context.Background())
+	assert.ErrorIs(t, err, domainerror.Unavailable)
+
+	status = http.StatusOK
+	_, err = provider.FetchRates(context.Background())
+	assert.ErrorContains(t, err, "no rates")
+}
+
+func TestOpenExchangeRatesProvider(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/latest.json", r.URL.Path)
+		if r.URL.Query().Get("app_id") != "secret-id" {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"error":true,"status":401,"message":"invalid_app_id"}`))
+			return
+		}
+		assert.Equal(t, "EUR", r.URL.Query().Get("base"))
+		w.Write([]byte(`{"timestamp":1704204000,"base":"EUR","rates":{"EUR":1,"USD":1.0956,"JPY":155.52,"BTC":2.1e-05,"IDR":1.70175e4,"XAU":0.000483}}`))
+	}))
+	defer server.Close()
+
+	provider := rates.NewOpenExchangeRatesProvider(server.URL+"/api/", "secret-id", "EUR", time.Second)
+	fetched, err := provider.FetchRates(context.Background())
+	require.NoError(t, err)
+	require.Len(t, fetched, 4, "the base and unsupported currencies are skipped")
+	assert.Equal(t, "BTC", fetched[0].Quote.Code)
+	assert.Equal(t, "0.000021", fetched[0].DecimalString(), "exponent notation is read exactly")
+	assert.Equal(t, "17017.5", fetched[1].DecimalString())
+	assert.Equal(t, "USD", fetched[3].Quote.Code)
+	assert.Equal(t, "1.0956", fetched[3].DecimalString())
+	assert.Equal(t, int64(1704204000), fetched[3].Timestamp.Epoch)
+
+	_, err = rates.NewOpenExchangeRatesProvider(server.URL+"/api", "wrong-id", "EUR", time.Second).
+		FetchRates(context.Background())
+	assert.ErrorContains(t, err, "invalid_app_id")
+	assert.NotContains(t, err.Error(), "wrong-id")
+
+	server.Close()
+	_, err = provider.FetchRates(context.Background())
+	assert.ErrorIs(t, err, domainerror.Unavailable)
+	assert.NotContains(t, err.Error(), "secret-id", "the App ID is kept out of errors")
+}
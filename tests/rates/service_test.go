@@ -0,0 +1,300 @@
+package rates_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"golang-arch/internal/shared/cache"
+	"golang-arch/internal/shared/config"
+	"golang-arch/internal/shared/domain/domainerror"
+	i18n "golang-arch/internal/shared/domain/internationalization"
+	"golang-arch/internal/shared/rates"
+	"golang-arch/internal/shared/testutil"
+	"golang-arch/pkg/clock"
+)
+
+var start = time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+type fixture struct {
+	service *rates.Service
+	mock    sqlmock.Sqlmock
+	redis   *miniredis.Miniredis
+	clock   *clock.Fake
+}
+
+func newFixture(t *testing.T, options ...rates.Option) *fixture {
+	t.Helper()
+
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		db.Close()
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	server, client := testutil.NewRedis(t)
+
+	fake := clock.NewFake(start)
+	provider, err := rates.NewStaticProvider("USD", map[string]string{"EUR": "0.9221", "gbp": "0.7918"}, fake)
+	require.NoError(t, err)
+
+	options = append([]rates.Option{rates.WithClock(fake)}, options...)
+	service := rates.NewService(provider, rates.NewPostgresStore(db),
+		cache.NewRedisCache(client, cache.WithPrefix("rates:")), options...)
+
+	return &fixture{service: service, mock: mock, redis: server, clock: fake}
+}
+
+func (f *fixture) expectSave() {
+	f.mock.ExpectBegin()
+	f.mock.ExpectExec("INSERT INTO exchange_rates").
+		WithArgs("static", "USD", "EUR", int64(9221), 4, start.Unix()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	f.mock.ExpectExec("INSERT INTO exchange_rates").
+		WithArgs("static", "USD", "GBP", int64(7918), 4, start.Unix()).
+		WillReturnResult(sqlmock.NewResult(2, 1))
+	f.mock.ExpectCommit()
+}
+
+func TestService_RefreshStoresAndCaches(t *testing.T) {
+	f := newFixture(t, rates.WithCacheTTL(2*time.Hour))
+	ctx := context.Background()
+
+	f.expectSave()
+	require.NoError(t, f.service.Refresh(ctx))
+
+	assert.True(t, f.redis.Exists("rates:current"))
+	assert.Equal(t, 2*time.Hour, f.redis.TTL("rates:current"))
+
+	current, err := f.service.Current(ctx)
+	require.NoError(t, err)
+	require.Len(t, current, 2)
+	assert.Equal(t, "0.9221", current[0].DecimalString())
+	assert.Equal(t, "GBP", current[1].Quote.Code)
+}
+
+func TestService_RefreshStoreFailure(t *testing.T) {
+	f := newFixture(t)
+
+	f.mock.ExpectBegin()
+	f.mock.ExpectExec("INSERT INTO exchange_rates").WillReturnError(assert.AnError)
+	f.mock.ExpectRollback()
+
+	err := f.service.Refresh(context.Background())
+	assert.ErrorIs(t, err, assert.AnError)
+	assert.False(t, f.redis.Exists("rates:current"), "a failed refresh must not replace the cached rates")
+}
+
+func TestService_Rate(t *testing.T) {
+	f := newFixture(t)
+	ctx := context.Background()
+	f.expectSave()
+	require.NoError(t, f.service.Refresh(ctx))
+
+	direct, err := f.service.Rate(ctx, "USD", "EUR")
+	require.NoError(t, err)
+	assert.Equal(t, "1 USD = 0.9221 EUR", direct.String())
+
+	inverse, err := f.service.Rate(ctx, "EUR", "USD")
+	require.NoError(t, err)
+	assert.Equal(t, "1.08448107581", inverse.DecimalString())
+
+	cross, err := f.service.Rate(ctx, "EUR", "GBP")
+	require.NoError(t, err)
+	assert.Equal(t, "EUR", cross.Base.Code)
+	assert.Equal(t, "GBP", cross.Quote.Code)
+	assert.Equal(t, "0.8586921158264", cross.DecimalString())
+
+	_, err = f.service.Rate(ctx, "USD", "JPY")
+	assert.ErrorIs(t, err, domainerror.NotFound)
+	_, err = f.service.Rate(ctx, "USD", "USD")
+	assert.ErrorIs(t, err, domainerror.Invalid)
+}
+
+func TestService_RateStale(t *testing.T) {
+	f := newFixture(t, rates.WithMaxAge(time.Hour))
+	ctx := context.Background()
+	f.expectSave()
+	require.NoError(t, f.service.Refresh(ctx))
+
+	_, err := f.service.Rate(ctx, "USD", "EUR")
+	require.NoError(t, err)
+
+	f.clock.Advance(2 * time.Hour)
+	_, err = f.service.Rate(ctx, "USD", "EUR")
+	assert.ErrorIs(t, err, domainerror.Unavailable)
+}
+
+// pinned is an OverrideSource returning fixed rates
+type pinned []i18n.ExchangeRate
+
+func (p pinned) Overrides(context.Context) ([]i18n.ExchangeRate, error) {
+	return p, nil
+}
+
+func TestService_RateOverrides(t *testing.T) {
+	eur, err := i18n.NewCurrencyFromCode("EUR")
+	require.NoError(t, err)
+	usd, err := i18n.NewCurrencyFromCode("USD")
+	require.NoError(t, err)
+	pin, err := i18n.NewExchangeRateFromDecimal(*usd, *eur, "0.95", *i18n.NewTimeFromTime(start.Add(-48 * time.Hour)))
+	require.NoError(t, err)
+
+	f := newFixture(t, rates.WithMaxAge(time.Hour), rates.WithOverrides(pinned{*pin}))
+	ctx := context.Background()
+	f.expectSave()
+	require.NoError(t, f.service.Refresh(ctx))
+
+	rate, err := f.service.Rate(ctx, "USD", "EUR")
+	require.NoError(t, err)
+	assert.Equal(t, "0.95", rate.DecimalString(), "the override wins and is never stale")
+	inverse, err := f.service.Rate(ctx, "EUR", "USD")
+	require.NoError(t, err)
+	assert.Equal(t, "EUR", inverse.Base.Code)
+
+	other, err := f.service.Rate(ctx, "USD", "GBP")
+	require.NoError(t, err)
+	assert.Equal(t, "0.7918", other.DecimalString(), "pairs without an override come from the provider")
+}
+
+func TestService_Convert(t *testing.T) {
+	eur, err := i18n.NewCurrencyFromCode("EUR")
+	require.NoError(t, err)
+	gbp, err := i18n.NewCurrencyFromCode("GBP")
+	require.NoError(t, err)
+	pin, err := i18n.NewExchangeRateFromDecimal(*gbp, *eur, "1.2", *i18n.NewTimeFromTime(start.Add(-time.Hour)))
+	require.NoError(t, err)
+
+	f := newFixture(t, rates.WithOverrides(pinned{*pin}))
+	ctx := context.Background()
+	f.expectSave()
+	require.NoError(t, f.service.Refresh(ctx))
+
+	amount, err := i18n.NewMoneyFromPrimitive(10050, "USD")
+	require.NoError(t, err)
+	converted, err := f.service.Convert(ctx, *amount, "EUR", i18n.RoundHalfEven)
+	require.NoError(t, err)
+	assert.Equal(t, int64(9267), converted.Result.Amount, "100.50 * 0.9221 = 92.67105")
+	assert.Equal(t, "EUR", converted.Result.Currency.Code)
+	assert.Equal(t, "0.9221", converted.Rate.DecimalString())
+	assert.Equal(t, "static", converted.Source)
+	assert.Equal(t, start, converted.Timestamp)
+
+	converted, err = f.service.Convert(ctx, *amount, "EUR", i18n.RoundUp)
+	require.NoError(t, err)
+	assert.Equal(t, int64(9268), converted.Result.Amount)
+
+	pounds, err := i18n.NewMoneyFromPrimitive(1000, "GBP")
+	require.NoError(t, err)
+	converted, err = f.service.Convert(ctx, *pounds, "EUR", i18n.RoundHalfEven)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1200), converted.Result.Amount)
+	assert.Equal(t, rates.SourceOverride, converted.Source)
+	assert.Equal(t, start.Add(-time.Hour), converted.Timestamp)
+
+	_, err = f.service.Convert(ctx, *amount, "JPY", i18n.RoundHalfEven)
+	assert.ErrorIs(t, err, domainerror.NotFound)
+}
+
+func TestService_CurrentFallsBackToStore(t *testing.T) {
+	f := newFixture(t)
+	ctx := context.Background()
+
+	f.mock.ExpectQuery("SELECT DISTINCT ON").WillReturnRows(
+		sqlmock.NewRows([]string{"base_code", "quote_code", "rate", "scale", "observed_at"}).
+			AddRow("USD", "JPY", 14235, 2, start.Unix()))
+
+	current, err := f.service.Current(ctx)
+	require.NoError(t, err)
+	require.Len(t, current, 1)
+	assert.Equal(t, "142.35", current[0].DecimalString())
+	assert.True(t, f.redis.Exists("rates:current"), "rates read from the store are cached")
+
+	// Served from the cache now; no further queries are expected
+	_, err = f.service.Current(ctx)
+	require.NoError(t, err)
+}
+
+func TestService_CurrentCollapsesConcurrentMisses(t *testing.T) {
+	f := newFixture(t)
+	ctx := context.Background()
+
+	// A single query even though every caller misses the cache at once
+	f.mock.ExpectQuery("SELECT DISTINCT ON").WillDelayFor(20 * time.Millisecond).WillReturnRows(
+		sqlmock.NewRows([]string{"base_code", "quote_code", "rate", "scale", "observed_at"}).
+			AddRow("EUR", "USD", 10845, 4, start.Unix()))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			rate, err := f.service.Rate(ctx, "EUR", "USD")
+			if assert.NoError(t, err) {
+				assert.Equal(t, "1.0845", rate.DecimalString())
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestService_History(t *testing.T) {
+	f := newFixture(t)
+
+	f.mock.ExpectQuery("SELECT base_code, quote_code, rate, scale, observed_at").
+		WithArgs("USD", "EUR", start.Add(-24*time.Hour).Unix()).
+		WillReturnRows(sqlmock.NewRows([]string{"base_code", "quote_code", "rate", "scale", "observed_at"}).
+			AddRow("USD", "EUR", 9201, 4, start.Add(-time.Hour).Unix()).
+			AddRow("USD", "EUR", 9221, 4, start.Unix()))
+
+	history, err := f.service.History(context.Background(), "USD", "EUR", start.Add(-24*time.Hour))
+	require.NoError(t, err)
+	require.Len(t, history, 2)
+	assert.Equal(t, "0.9201", history[0].DecimalString())
+	assert.Equal(t, start.Unix(), history[1].Timestamp.Epoch)
+}
+
+func TestStaticProvider(t *testing.T) {
+	fake := clock.NewFake(start)
+	provider, err := rates.NewStaticProvider("usd", map[string]string{"jpy": "142.35"}, fake)
+	require.NoError(t, err)
+	assert.Equal(t, "static", provider.Name())
+
+	fake.Advance(time.Hour)
+	fetched, err := provider.FetchRates(context.Background())
+	require.NoError(t, err)
+	require.Len(t, fetched, 1)
+	assert.Equal(t, "USD", fetched[0].Base.Code)
+	assert.Equal(t, start.Add(time.Hour).Unix(), fetched[0].Timestamp.Epoch)
+
+	_, err = rates.NewStaticProvider("USD", map[string]string{"EUR": "-1"}, fake)
+	assert.Error(t, err)
+	_, err = rates.NewStaticProvider("XXX", nil, fake)
+	assert.Error(t, err)
+}
+
+func TestNewProvider(t *testing.T) {
+	provider, err := rates.NewProvider(config.RatesConfig{Provider: "static", Base: "USD"}, clock.New())
+	require.NoError(t, err)
+	assert.Equal(t, "static", provider.Name())
+
+	provider, err = rates.NewProvider(config.RatesConfig{Provider: "ecb"}, clock.New())
+	require.NoError(t, err)
+	assert.Equal(t, "ecb", provider.Name())
+
+	_, err = rates.NewProvider(config.RatesConfig{Provider: "openexchangerates"}, clock.New())
+	assert.ErrorContains(t, err, "oxr_app_id")
+	provider, err = rates.NewProvider(config.RatesConfig{Provider: "openexchangerates", OXRAppID: "key"}, clock.New())
+	require.NoError(t, err)
+	assert.Equal(t, "openexchangerates", provider.Name())
+
+	_, err = rates.NewProvider(config.RatesConfig{Provider: "carrier-pigeon"}, clock.New())
+	assert.Error(t, err)
+}
@@ -0,0 +1,126 @@
+package rates_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"golang-arch/internal/shared/domain/domainerror"
+	"golang-arch/internal/shared/rates"
+	"golang-arch/pkg/clock"
+
+	i18n "golang-arch/internal/shared/domain/internationalization"
+)
+
+// fixedRates is a RateSource serving decimal rates keyed by "BASE/QUOTE"
+type fixedRates map[string]string
+
+func (f fixedRates) Rate(_ context.Context, base, quote string) (*i18n.ExchangeRate, error) {
+	value, ok := f[base+"/"+quote]
+	if !ok {
+		return nil, domainerror.NotFoundf("no exchange rate from %s to %s", base, quote)
+	}
+	baseCurrency, _ := i18n.NewCurrencyFromCode(base)
+	quoteCurrency, _ := i18n.NewCurrencyFromCode(quote)
+	return i18n.NewExchangeRateFromDecimal(*baseCurrency, *quoteCurrency, value, i18n.Time{Epoch: start.Unix()})
+}
+
+func moneyBag(t *testing.T, amounts map[string]int64) *i18n.MoneyBag {
+	t.Helper()
+	var monies []i18n.Money
+	for code, amount := range amounts {
+		m, err := i18n.NewMoneyFromPrimitive(amount, code)
+		require.NoError(t, err)
+		monies = append(monies, *m)
+	}
+	bag, err := i18n.NewMoneyBag(monies...)
+	require.NoError(t, err)
+	return bag
+}
+
+func TestValuation_Value(t *testing.T) {
+	source := fixedRates{"EUR/USD": "1.0845", "JPY/USD": "0.0070249"}
+	valuation := rates.NewValuation(source, rates.WithValuationClock(clock.NewFake(start)))
+
+	bag := moneyBag(t, map[string]int64{"USD": 10000, "EUR": 5000, "JPY": 12345})
+	result, err := valuation.Value(context.Background(), bag, "USD")
+	require.NoError(t, err)
+
+	require.Len(t, result.Breakdown, 3)
+	eur, jpy, usd := result.Breakdown[0], result.Breakdown[1], result.Breakdown[2]
+
+	assert.Equal(t, int64(5422), eur.Converted.Amount) // 50.00 * 1.0845 = 54.225 -> 54.22 (half-even)
+	assert.Equal(t, "1.0845", eur.Rate.DecimalString())
+	assert.Equal(t, int64(8672), jpy.Converted.Amount) // 12345 * 0.0070249 = 86.7224
+	assert.Nil(t, usd.Rate)
+	assert.Equal(t, int64(10000), usd.Converted.Amount)
+
+	assert.Equal(t, "USD", result.Total.Currency.Code)
+	assert.Equal(t, int64(10000+5422+8672), result.Total.Amount, "the total is the sum of the rounded lines")
+	assert.Len(t, result.Rates, 2)
+	assert.Equal(t, start, result.ValuedAt)
+}
+
+func TestValuation_RoundingMode(t *testing.T) {
+	source := fixedRates{"EUR/USD": "1.0845"}
+	bag := moneyBag(t, map[string]int64{"EUR": 5000})
+
+	result, err := rates.NewValuation(source, rates.WithRoundingMode(i18n.RoundHalfUp)).
+		Value(context.Background(), bag, "USD")
+	require.NoError(t, err)
+	assert.Equal(t, int64(5423), result.Total.Amount)
+}
+
+func TestValuation_Errors(t *testing.T) {
+	valuation := rates.NewValuation(fixedRates{})
+	ctx := context.Background()
+
+	_, err := valuation.Value(ctx, moneyBag(t, map[string]int64{"EUR": 100}), "USD")
+	assert.ErrorIs(t, err, domainerror.NotFound)
+
+	_, err = valuation.Value(ctx, moneyBag(t, map[string]int64{"EUR": 100}), "XXX")
+	assert.ErrorIs(t, err, domainerror.Invalid)
+
+	empty, err := valuation.Value(ctx, nil, "usd")
+	require.NoError(t, err)
+	assert.True(t, empty.Total.IsZero())
+	assert.Empty(t, empty.Breakdown)
+}
+
+func TestValuation_JSON(t *testing.T) {
+	valuation := rates.NewValuation(fixedRates{"EUR/USD": "1.0845"},
+		rates.WithValuationClock(clock.NewFake(start)))
+	result, err := valuation.Value(context.Background(), moneyBag(t, map[string]int64{"EUR": 100, "USD": 100}), "USD")
+	require.NoError(t, err)
+
+	data, err := json.Marshal(result)
+	require.NoError(t, err)
+
+	var decoded struct {
+		Total     map[string]any   `json:"total"`
+		Breakdown []map[string]any `json:"breakdown"`
+		Rates     []map[string]any `json:"rates"`
+		ValuedAt  time.Time        `json:"valued_at"`
+	}
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.EqualValues(t, 208, decoded.Total["amount"])
+	assert.Contains(t, decoded.Breakdown[0], "rate")
+	assert.NotContains(t, decoded.Breakdown[1], "rate")
+	assert.Len(t, decoded.Rates, 1)
+	assert.True(t, decoded.ValuedAt.Equal(start))
+}
+
+func TestService_ImplementsRateSource(t *testing.T) {
+	f := newFixture(t)
+	f.expectSave()
+	require.NoError(t, f.service.Refresh(context.Background()))
+
+	result, err := rates.NewValuation(f.service).
+		Value(context.Background(), moneyBag(t, map[string]int64{"EUR": 9221}), "USD")
+	require.NoError(t, err)
+	assert.Equal(t, int64(10000), result.Total.Amount)
+}
@@ -0,0 +1,138 @@
+package refdata_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"golang-arch/internal/shared/config"
+	"golang-arch/internal/shared/rbac"
+	"golang-arch/internal/shared/refdata"
+)
+
+func newRouter(t *testing.T) *gin.Engine {
+	t.Helper()
+	authz, err := rbac.NewAuthorizer(config.RBACConfig{
+		Principals: []config.PrincipalConfig{
+			{Name: "ops", Token: "admin-token", Roles: []string{"i18n-admin"}},
+			{Name: "support", Token: "viewer-token", Roles: []string{"i18n-viewer"}},
+		},
+		Roles: map[string][]string{
+			"i18n-admin":  {"i18n:read", "i18n:write"},
+			"i18n-viewer": {"i18n:read"},
+		},
+	})
+	require.NoError(t, err)
+	service, _ := newService(t, refdata.NewMemoryStore(), refdata.NewLocalNotifier())
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	refdata.NewHandler(service, authz).Register(router.Group("/api/v1"))
+	return router
+}
+
+func do(router *gin.Engine, token, method, path, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestHandlerRBAC(t *testing.T) {
+	router := newRouter(t)
+
+	rec := do(router, "", http.MethodGet, "/api/v1/admin/i18n/currencies", "")
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	assert.Equal(t, "Bearer", rec.Header().Get("WWW-Authenticate"))
+	rec = do(router, "wrong-token", http.MethodGet, "/api/v1/admin/i18n/currencies", "")
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+
+	rec = do(router, "viewer-token", http.MethodGet, "/api/v1/admin/i18n/currencies", "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	rec = do(router, "viewer-token", http.MethodPut, "/api/v1/admin/i18n/currencies/EUR", `{"symbol":"€","name":"Euro","decimal_places":2}`)
+	assert.Equal(t, http.StatusForbidden, rec.Code, rec.Body.String())
+	assert.Contains(t, rec.Body.String(), "i18n:write")
+}
+
+func TestHandlerCRUD(t *testing.T) {
+	router := newRouter(t)
+
+	rec := do(router, "admin-token", http.MethodPut, "/api/v1/admin/i18n/currencies/eur", `{"symbol":"€","name":"Euro","decimal_places":2}`)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var saved struct {
+		Data refdata.Currency `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &saved))
+	assert.Equal(t, "EUR", saved.Data.Code)
+	assert.True(t, saved.Data.Enabled)
+	assert.Equal(t, "ops", saved.Data.UpdatedBy, "changes record the principal")
+
+	rec = do(router, "viewer-token", http.MethodGet, "/api/v1/admin/i18n/currencies/EUR", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"name":"Euro"`)
+
+	rec = do(router, "admin-token", http.MethodPut, "/api/v1/admin/i18n/currencies/EUR", `{"symbol":"€","name":"Euro"}`)
+	assert.Equal(t, http.StatusBadRequest, rec.Code, "decimal_places is required")
+
+	rec = do(router, "admin-token", http.MethodPut, "/api/v1/admin/i18n/rate-overrides/USD/EUR", `{"rate":"0.95","reason":"month-end close"}`)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	rec = do(router, "admin-token", http.MethodPut, "/api/v1/admin/i18n/rate-overrides/USD/EUR", `{"rate":"-1"}`)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	rec = do(router, "admin-token", http.MethodGet, "/api/v1/admin/i18n/rate-overrides", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"rate":"0.95"`)
+
+	rec = do(router, "admin-token", http.MethodPut, "/api/v1/admin/i18n/translations/de/checkout.title", `{"text":"Kasse"}`)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	rec = do(router, "admin-token", http.MethodGet, "/api/v1/admin/i18n/translations?locale=de", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), "Kasse")
+	rec = do(router, "admin-token", http.MethodGet, "/api/v1/admin/i18n/translations?locale=fr", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"data":[]`)
+
+	rec = do(router, "admin-token", http.MethodPut, "/api/v1/admin/i18n/holidays/ID/2024-08-17", `{"name":"Independence Day"}`)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	rec = do(router, "admin-token", http.MethodPut, "/api/v1/admin/i18n/holidays/ID/17-08-2024", `{"name":"Independence Day"}`)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	rec = do(router, "admin-token", http.MethodGet, "/api/v1/admin/i18n/holidays?calendar=ID", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), "Independence Day")
+
+	rec = do(router, "admin-token", http.MethodDelete, "/api/v1/admin/i18n/holidays/ID/2024-08-17", "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	rec = do(router, "admin-token", http.MethodDelete, "/api/v1/admin/i18n/holidays/ID/2024-08-17", "")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	rec = do(router, "admin-token", http.MethodDelete, "/api/v1/admin/i18n/currencies/EUR", "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	rec = do(router, "admin-token", http.MethodGet, "/api/v1/admin/i18n/currencies/EUR", "")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestNewAuthorizerValidation(t *testing.T) {
+	roles := map[string][]string{"i18n-admin": {"i18n:read", "i18n:write"}}
+
+	_, err := rbac.NewAuthorizer(config.RBACConfig{Roles: roles, Principals: []config.PrincipalConfig{
+		{Name: "ops", Token: "t", Roles: []string{"superuser"}},
+	}})
+	assert.ErrorContains(t, err, "unknown role")
+
+	_, err = rbac.NewAuthorizer(config.RBACConfig{Roles: roles, Principals: []config.PrincipalConfig{
+		{Name: "a", Token: "same", Roles: []string{"i18n-admin"}},
+		{Name: "b", Token: "same"},
+	}})
+	assert.ErrorContains(t, err, "reuses the token")
+
+	_, err = rbac.NewAuthorizer(config.RBACConfig{Principals: []config.PrincipalConfig{{Name: "ops"}}})
+	assert.ErrorContains(t, err, "needs a name and a token")
+}
@@ -0,0 +1,282 @@
+package refdata_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"golang-arch/internal/shared/domain/domainerror"
+	i18n "golang-arch/internal/shared/domain/internationalization"
+	"golang-arch/internal/shared/refdata"
+	"golang-arch/pkg/clock"
+)
+
+var start = time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+// countingStore counts how often the currencies are read
+type countingStore struct {
+	*refdata.MemoryStore
+	reads int
+}
+
+func (s *countingStore) ListCurrencies(ctx context.Context) ([]refdata.Currency, error) {
+	s.reads++
+	return s.MemoryStore.ListCurrencies(ctx)
+}
+
+func newService(t *testing.T, store refdata.Store, notifier refdata.Notifier) (*refdata.Service, *clock.Fake) {
+	t.Helper()
+	clk := clock.NewFake(start)
+	service, err := refdata.NewService(store, notifier, refdata.WithClock(clk))
+	require.NoError(t, err)
+	return service, clk
+}
+
+func TestCurrencies(t *testing.T) {
+	service, _ := newService(t, refdata.NewMemoryStore(), refdata.NewLocalNotifier())
+	ctx := context.Background()
+
+	saved, err := service.SaveCurrency(ctx, refdata.Currency{Code: "idr", Symbol: "Rp", Name: "Indonesian Rupiah", Enabled: true, UpdatedBy: "ops"})
+	require.NoError(t, err)
+	assert.Equal(t, "IDR", saved.Code)
+	assert.Equal(t, start, saved.UpdatedAt)
+
+	got, err := service.Currency(ctx, "IDR")
+	require.NoError(t, err)
+	assert.Equal(t, "ops", got.UpdatedBy)
+
+	_, err = service.SaveCurrency(ctx, refdata.Currency{Code: "US", Symbol: "$", Name: "Dollar", DecimalPlaces: 2})
+	assert.ErrorIs(t, err, domainerror.Invalid)
+
+	require.NoError(t, service.DeleteCurrency(ctx, "idr"))
+	_, err = service.Currency(ctx, "IDR")
+	assert.ErrorIs(t, err, domainerror.NotFound)
+	assert.ErrorIs(t, service.DeleteCurrency(ctx, "IDR"), domainerror.NotFound)
+}
+
+func TestCurrencyRegistrySync(t *testing.T) {
+	usd, err := i18n.NewCurrency("USD", "$", "US Dollar", 2)
+	require.NoError(t, err)
+	registry, err := i18n.NewCurrencyRegistry(*usd)
+	require.NoError(t, err)
+	store := refdata.NewMemoryStore()
+	service, err := refdata.NewService(store, refdata.NewLocalNotifier(), refdata.WithCurrencyRegistry(registry))
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	var changes []refdata.Change
+	service.OnChange(func(change refdata.Change) { changes = append(changes, change) })
+
+	_, err = service.SaveCurrency(ctx, refdata.Currency{Code: "IDR", Symbol: "Rp", Name: "Indonesian Rupiah", Enabled: true})
+	require.NoError(t, err)
+	idr, ok := registry.Lookup("IDR")
+	require.True(t, ok, "enabled currencies are registered")
+	assert.Equal(t, "Rp", idr.Symbol)
+	assert.Contains(t, changes, refdata.Change{Kind: refdata.KindCurrencies, Key: "IDR"})
+
+	_, err = service.SaveCurrency(ctx, refdata.Currency{Code: "IDR", Symbol: "IDR", Name: "Indonesian Rupiah", Enabled: true})
+	require.NoError(t, err)
+	idr, ok = registry.Lookup("IDR")
+	require.True(t, ok)
+	assert.Equal(t, "IDR", idr.Symbol, "changed currencies are registered again")
+
+	_, err = service.SaveCurrency(ctx, refdata.Currency{Code: "IDR", Symbol: "Rp", Name: "Indonesian Rupiah"})
+	require.NoError(t, err)
+	_, ok = registry.Lookup("IDR")
+	assert.False(t, ok, "disabled currencies are unregistered")
+
+	_, err = service.SaveCurrency(ctx, refdata.Currency{Code: "IDR", Symbol: "Rp", Name: "Indonesian Rupiah", Enabled: true})
+	require.NoError(t, err)
+	require.NoError(t, service.DeleteCurrency(ctx, "IDR"))
+	_, ok = registry.Lookup("IDR")
+	assert.False(t, ok, "deleted currencies are unregistered")
+
+	_, err = service.SaveCurrency(ctx, refdata.Currency{Code: "USD", Symbol: "$", Name: "US Dollar", DecimalPlaces: 2})
+	require.NoError(t, err)
+	_, ok = registry.Lookup("USD")
+	assert.True(t, ok, "currencies registered elsewhere are left alone")
+
+	// Another instance registers the stored currencies when it starts
+	_, err = service.SaveCurrency(ctx, refdata.Currency{Code: "EUR", Symbol: "€", Name: "Euro", DecimalPlaces: 2, Enabled: true})
+	require.NoError(t, err)
+	other, err := i18n.NewCurrencyRegistry()
+	require.NoError(t, err)
+	starting, err := refdata.NewService(store, refdata.NewLocalNotifier(), refdata.WithCurrencyRegistry(other))
+	require.NoError(t, err)
+	require.NoError(t, starting.SyncCurrencies(ctx))
+	assert.Equal(t, []string{"EUR"}, other.Codes())
+}
+
+func TestCacheInvalidation(t *testing.T) {
+	shared := &countingStore{MemoryStore: refdata.NewMemoryStore()}
+	notifier := refdata.NewLocalNotifier()
+	writer, _ := newService(t, shared, notifier)
+	reader, _ := newService(t, shared, notifier)
+	ctx := context.Background()
+
+	currencies, err := reader.Currencies(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, currencies)
+	_, err = reader.Currencies(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, shared.reads, "the second read is served from the cache")
+
+	_, err = writer.SaveCurrency(ctx, refdata.Currency{Code: "EUR", Symbol: "€", Name: "Euro", DecimalPlaces: 2})
+	require.NoError(t, err)
+	currencies, err = reader.Currencies(ctx)
+	require.NoError(t, err)
+	require.Len(t, currencies, 1, "the notification dropped the reader's cache")
+	assert.Equal(t, "EUR", currencies[0].Code)
+
+	// Changes of other kinds keep the currencies cached
+	reads := shared.reads
+	_, err = writer.SaveHoliday(ctx, refdata.Holiday{Calendar: "ID", Date: "2024-08-17", Name: "Independence Day"})
+	require.NoError(t, err)
+	_, err = reader.Currencies(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, reads, shared.reads)
+
+	// A change without kind, as after a reconnect, drops everything
+	require.NoError(t, notifier.Notify(ctx, refdata.Change{}))
+	_, err = reader.Currencies(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, reads+1, shared.reads)
+}
+
+func TestRateOverrides(t *testing.T) {
+	service, clk := newService(t, refdata.NewMemoryStore(), refdata.NewLocalNotifier())
+	ctx := context.Background()
+
+	expires := start.Add(time.Hour)
+	_, err := service.SaveRateOverride(ctx, refdata.RateOverride{Base: "usd", Quote: "eur", Rate: "0.95", Reason: "month-end close", ExpiresAt: &expires})
+	require.NoError(t, err)
+	_, err = service.SaveRateOverride(ctx, refdata.RateOverride{Base: "USD", Quote: "GBP", Rate: "0.8"})
+	require.NoError(t, err)
+
+	_, err = service.SaveRateOverride(ctx, refdata.RateOverride{Base: "USD", Quote: "USD", Rate: "1"})
+	assert.ErrorIs(t, err, domainerror.Invalid)
+	_, err = service.SaveRateOverride(ctx, refdata.RateOverride{Base: "USD", Quote: "JPY", Rate: "abc"})
+	assert.ErrorIs(t, err, domainerror.Invalid)
+
+	rates, err := service.Overrides(ctx)
+	require.NoError(t, err)
+	require.Len(t, rates, 2)
+	assert.Equal(t, "1 USD = 0.95 EUR", rates[0].String())
+
+	clk.Advance(2 * time.Hour)
+	rates, err = service.Overrides(ctx)
+	require.NoError(t, err)
+	require.Len(t, rates, 1, "expired overrides stop applying")
+	assert.Equal(t, "GBP", rates[0].Quote.Code)
+
+	all, err := service.RateOverrides(ctx)
+	require.NoError(t, err)
+	assert.Len(t, all, 2, "expired overrides are still listed")
+
+	require.NoError(t, service.DeleteRateOverride(ctx, "usd", "gbp"))
+	rates, err = service.Overrides(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, rates)
+}
+
+func TestTranslationsAndCatalog(t *testing.T) {
+	service, _ := newService(t, refdata.NewMemoryStore(), refdata.NewLocalNotifier())
+	ctx := context.Background()
+
+	for _, translation := range []refdata.Translation{
+		{Key: "checkout.title", Locale: "en", Text: "Checkout"},
+		{Key: "checkout.title", Locale: "pt_br", Text: "Finalizar compra"},
+		{Key: "checkout.pay", Locale: "en", Text: "Pay"},
+	} {
+		_, err := service.SaveTranslation(ctx, translation)
+		require.NoError(t, err)
+	}
+	_, err := service.SaveTranslation(ctx, refdata.Translation{Key: "x", Locale: "not a locale", Text: "x"})
+	assert.ErrorIs(t, err, domainerror.Invalid)
+
+	portuguese, err := service.Translations(ctx, "pt-BR")
+	require.NoError(t, err)
+	require.Len(t, portuguese, 1)
+	assert.Equal(t, "pt-BR", portuguese[0].Locale, "locales are stored canonical")
+
+	catalog, err := service.Catalog(ctx)
+	require.NoError(t, err)
+	require.Contains(t, catalog, "checkout.title")
+	text, ok := catalog["checkout.title"].Get(i18n.Locale("pt-BR"))
+	require.True(t, ok)
+	assert.Equal(t, "Finalizar compra", text)
+
+	require.NoError(t, service.DeleteTranslation(ctx, "checkout.title", "pt_BR"))
+	portuguese, err = service.Translations(ctx, "pt-BR")
+	require.NoError(t, err)
+	assert.Empty(t, portuguese)
+}
+
+func TestHolidaysCalendar(t *testing.T) {
+	service, _ := newService(t, refdata.NewMemoryStore(), refdata.NewLocalNotifier())
+	ctx := context.Background()
+
+	_, err := service.SaveHoliday(ctx, refdata.Holiday{Calendar: "ID", Date: "2024-08-17", Name: "Independence Day"})
+	require.NoError(t, err)
+	_, err = service.SaveHoliday(ctx, refdata.Holiday{Calendar: "SG", Date: "2024-08-09", Name: "National Day"})
+	require.NoError(t, err)
+	_, err = service.SaveHoliday(ctx, refdata.Holiday{Calendar: "ID", Date: "2024-02-30", Name: "Nope"})
+	assert.ErrorIs(t, err, domainerror.Invalid)
+
+	jakarta, err := i18n.NewTimezoneFromID("Asia/Jakarta")
+	require.NoError(t, err)
+	base, err := i18n.NewBusinessCalendar(*jakarta, i18n.Locale("id-ID").Week(), "09:00", "17:00")
+	require.NoError(t, err)
+
+	calendar, err := service.Calendar(ctx, "ID", *base)
+	require.NoError(t, err)
+	name, holiday, err := calendar.Holiday(time.Date(2024, 8, 17, 10, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	assert.True(t, holiday)
+	assert.Equal(t, "Independence Day", name)
+	_, holiday, err = calendar.Holiday(time.Date(2024, 8, 9, 10, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	assert.False(t, holiday, "holidays of other calendars are not added")
+}
+
+func TestPostgresStoreAndNotifier(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	store := refdata.NewPostgresStore(db)
+	notifier := refdata.NewPostgresNotifier(db, "host=unused", "refdata_changed", zap.NewNop())
+	service, err := refdata.NewService(store, notifier, refdata.WithClock(clock.NewFake(start)))
+	require.NoError(t, err)
+	defer notifier.Close()
+	ctx := context.Background()
+
+	mock.ExpectExec("INSERT INTO i18n_translations").
+		WithArgs("checkout.title", "de", "Kasse", "ops", start).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`SELECT pg_notify\(\$1, \$2\)`).
+		WithArgs("refdata_changed", `{"kind":"translations","key":"checkout.title/de"}`).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	_, err = service.SaveTranslation(ctx, refdata.Translation{Key: "checkout.title", Locale: "de", Text: "Kasse", UpdatedBy: "ops"})
+	require.NoError(t, err)
+
+	mock.ExpectQuery("SELECT message_key, locale, text, updated_by, updated_at FROM i18n_translations").
+		WillReturnRows(sqlmock.NewRows([]string{"message_key", "locale", "text", "updated_by", "updated_at"}).
+			AddRow("checkout.title", "de", "Kasse", "ops", start))
+	translations, err := service.Translations(ctx, "de")
+	require.NoError(t, err)
+	require.Len(t, translations, 1)
+	assert.Equal(t, "Kasse", translations[0].Text)
+
+	mock.ExpectExec("DELETE FROM i18n_holidays").
+		WithArgs("ID", "2024-08-17").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	assert.ErrorIs(t, service.DeleteHoliday(ctx, "ID", "2024-08-17"), domainerror.NotFound)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
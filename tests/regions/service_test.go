@@ -0,0 +1,197 @@
+package regions_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"golang-arch/internal/shared/config"
+	"golang-arch/internal/shared/domain/domainerror"
+	i18n "golang-arch/internal/shared/domain/internationalization"
+	"golang-arch/internal/shared/domain/validation"
+	"golang-arch/internal/shared/regions"
+	"golang-arch/pkg/clock"
+)
+
+var start = time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+// geoNames fakes the GeoNames countryInfoJSON and childrenJSON endpoints for France
+type geoNames struct {
+	server   *httptest.Server
+	requests atomic.Int32
+	fail     atomic.Bool
+}
+
+func newGeoNames(t *testing.T) *geoNames {
+	t.Helper()
+
+	g := &geoNames{}
+	g.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		g.requests.Add(1)
+		assert.Equal(t, "demo", r.URL.Query().Get("username"))
+		w.Header().Set("Content-Type", "application/json")
+		if g.fail.Load() {
+			fmt.Fprint(w, `{"status":{"message":"the hourly limit of 1000 credits for demo has been exceeded","value":19}}`)
+			return
+		}
+		switch r.URL.Path {
+		case "/countryInfoJSON":
+			if r.URL.Query().Get("country") != "FR" {
+				fmt.Fprint(w, `{"geonames":[]}`)
+				return
+			}
+			fmt.Fprint(w, `{"geonames":[{"geonameId":3017382,"countryName":"France"}]}`)
+		case "/childrenJSON":
+			assert.Equal(t, "3017382", r.URL.Query().Get("geonameId"))
+			fmt.Fprint(w, `{"geonames":[
+				{"geonameId":3012874,"name":"Île-de-France","adminCode1":"11","adminCodes1":{"ISO3166_2":"IDF"}},
+				{"geonameId":3031359,"name":"Bretagne","adminCode1":"53","adminCodes1":{"ISO3166_2":"BRE"}},
+				{"geonameId":1,"name":"","adminCode1":"00"}
+			]}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(g.server.Close)
+	return g
+}
+
+func newService(t *testing.T, g *geoNames, clk clock.Clock) *regions.Service {
+	t.Helper()
+	return regions.NewService(regions.NewGeoNamesProvider(g.server.URL, "demo"),
+		regions.WithClock(clk),
+		regions.WithCacheTTL(time.Hour),
+		regions.WithMinInterval(4*time.Second),
+	)
+}
+
+func TestService_EmbeddedDataSkipsProvider(t *testing.T) {
+	g := newGeoNames(t)
+	service := newService(t, g, clock.NewFake(start))
+
+	subdivisions, err := service.Subdivisions(context.Background(), "US")
+	require.NoError(t, err)
+	assert.Len(t, subdivisions, 56)
+	assert.Zero(t, g.requests.Load())
+}
+
+func TestService_FetchesAndCachesRemoteSubdivisions(t *testing.T) {
+	g := newGeoNames(t)
+	clk := clock.NewFake(start)
+	service := newService(t, g, clk)
+	ctx := context.Background()
+
+	subdivisions, err := service.Subdivisions(ctx, "FR")
+	require.NoError(t, err)
+	require.Len(t, subdivisions, 2, "entries without a name are skipped")
+	assert.Equal(t, "FR-IDF", subdivisions[0].Code)
+	assert.Equal(t, "Île-de-France", subdivisions[0].Name)
+	assert.Equal(t, int32(2), g.requests.Load())
+
+	clk.Advance(30 * time.Minute)
+	subdivision, ok, err := service.Find(ctx, "FR", "ILE-DE-FRANCE")
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, "FR-IDF", subdivision.Code)
+	assert.Equal(t, int32(2), g.requests.Load(), "served from cache")
+
+	clk.Advance(time.Hour)
+	_, err = service.Subdivisions(ctx, "FR")
+	require.NoError(t, err)
+	assert.Equal(t, int32(4), g.requests.Load(), "refetched after the TTL")
+}
+
+func TestService_RateLimitsRemoteRequests(t *testing.T) {
+	g := newGeoNames(t)
+	clk := clock.NewFake(start)
+	service := newService(t, g, clk)
+	ctx := context.Background()
+
+	_, err := service.Subdivisions(ctx, "FR")
+	require.NoError(t, err)
+
+	_, err = service.Subdivisions(ctx, "ES")
+	assert.ErrorIs(t, err, domainerror.Unavailable)
+	assert.Equal(t, int32(2), g.requests.Load())
+
+	clk.Advance(4 * time.Second)
+	subdivisions, err := service.Subdivisions(ctx, "ES")
+	require.NoError(t, err)
+	assert.Empty(t, subdivisions, "unknown to GeoNames")
+}
+
+func TestService_ServesStaleCacheWhenProviderFails(t *testing.T) {
+	g := newGeoNames(t)
+	clk := clock.NewFake(start)
+	service := newService(t, g, clk)
+	ctx := context.Background()
+
+	_, err := service.Subdivisions(ctx, "FR")
+	require.NoError(t, err)
+
+	g.fail.Store(true)
+	clk.Advance(2 * time.Hour)
+	subdivisions, err := service.Subdivisions(ctx, "FR")
+	require.NoError(t, err)
+	assert.Len(t, subdivisions, 2)
+
+	_, err = service.Subdivisions(ctx, "IT")
+	assert.Error(t, err, "rate limited")
+	clk.Advance(4 * time.Second)
+	_, err = service.Subdivisions(ctx, "IT")
+	assert.ErrorIs(t, err, domainerror.Unavailable, "GeoNames credits exhausted")
+}
+
+func TestService_ValidateRegion(t *testing.T) {
+	g := newGeoNames(t)
+	service := newService(t, g, clock.NewFake(start))
+	ctx := context.Background()
+
+	assert.NoError(t, service.ValidateRegion(ctx, "United States", "New York"))
+	assert.NoError(t, service.ValidateRegion(ctx, "FR", "Bretagne"))
+	assert.NoError(t, service.ValidateRegion(ctx, "Atlantis", "Poseidonis"), "unknown countries cannot be checked")
+	assert.NoError(t, service.ValidateRegion(ctx, "US", ""))
+
+	err := service.ValidateRegion(ctx, "FR", "Bavaria")
+	require.Error(t, err)
+	assert.Contains(t, validation.FromError(err).ByField(), "region")
+
+	phone, err := i18n.NewPhone("1", "5551234567")
+	require.NoError(t, err)
+	timezone, err := i18n.NewTimezoneFromID("America/New_York")
+	require.NoError(t, err)
+	lp := i18n.LocalizedPhone{Phone: *phone, Country: "United States", Region: "Ontario", Timezone: *timezone}
+	assert.Error(t, service.ValidatePhone(ctx, lp))
+}
+
+func TestService_WithoutProvider(t *testing.T) {
+	service := regions.NewService(nil)
+	subdivisions, err := service.Subdivisions(context.Background(), "FR")
+	require.NoError(t, err)
+	assert.Nil(t, subdivisions)
+
+	_, err = service.Subdivisions(context.Background(), "fr")
+	assert.ErrorIs(t, err, domainerror.Invalid)
+}
+
+func TestNewProvider(t *testing.T) {
+	provider, err := regions.NewProvider(config.RegionsConfig{Provider: "none"})
+	require.NoError(t, err)
+	assert.Nil(t, provider)
+
+	provider, err = regions.NewProvider(config.RegionsConfig{Provider: "geonames", GeoNamesUsername: "demo"})
+	require.NoError(t, err)
+	assert.Equal(t, "geonames", provider.Name())
+
+	_, err = regions.NewProvider(config.RegionsConfig{Provider: "geonames"})
+	assert.Error(t, err)
+	_, err = regions.NewProvider(config.RegionsConfig{Provider: "carrier-pigeon"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,265 @@
+package repository_test
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"golang-arch/internal/shared/domain/domainerror"
+	i18n "golang-arch/internal/shared/domain/internationalization"
+	"golang-arch/internal/shared/erasure"
+	"golang-arch/internal/shared/events"
+	"golang-arch/internal/shared/rbac"
+	"golang-arch/internal/shared/repository"
+	"golang-arch/pkg/clock"
+)
+
+type order struct {
+	Status string `json:"status"`
+	Total  int64  `json:"total"`
+	Card   string `json:"card,omitempty"`
+}
+
+var now = time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+func updateOrder(ctx context.Context, tx *sql.Tx, change *repository.Change[order]) error {
+	_, err := tx.ExecContext(ctx, "UPDATE orders SET status = $1, total = $2 WHERE id = $3",
+		change.New.Status, change.New.Total, change.ID)
+	return err
+}
+
+func newDB(t *testing.T) (*sql.DB, sqlmock.Sqlmock) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	return db, mock
+}
+
+func TestHooks_UpdateAuditsAndEmits(t *testing.T) {
+	db, mock := newDB(t)
+	audit := repository.NewMemoryAuditLog()
+	bus := events.NewBus(zap.NewNop())
+	changes := make(chan repository.Changed, 1)
+	bus.Subscribe("order.updated", func(_ context.Context, event events.Event) error {
+		changes <- event.(repository.Changed)
+		return nil
+	})
+
+	hooks := repository.NewHooks[order]("order",
+		repository.WithClock(clock.NewFake(now)),
+		repository.WithAudit(audit),
+		repository.WithEvents(bus),
+		repository.WithRedact("card"),
+	)
+	var after []string
+	hooks.OnUpdate(repository.Before, func(_ context.Context, _ *sql.Tx, change *repository.Change[order]) error {
+		change.New.Total = 1500 // a before hook amends the write
+		return nil
+	})
+	hooks.OnUpdate(repository.After, func(_ context.Context, _ *sql.Tx, change *repository.Change[order]) error {
+		after = append(after, change.New.Status)
+		return nil
+	})
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE orders")).
+		WithArgs("paid", int64(1500), "o-1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	ctx := rbac.NewContext(context.Background(), rbac.Principal{Name: "alice"})
+	old := order{Status: "new", Total: 1000, Card: "4111"}
+	err := hooks.Update(ctx, db, "o-1", old, order{Status: "paid", Total: 1000, Card: "4242"}, updateOrder)
+	require.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+	assert.Equal(t, []string{"paid"}, after)
+
+	history, err := audit.History(ctx, db, "order", "o-1")
+	require.NoError(t, err)
+	require.Len(t, history, 1)
+	entry := history[0]
+	assert.Equal(t, repository.Updated, entry.Operation)
+	assert.Equal(t, "alice", entry.Actor)
+	assert.JSONEq(t, `{"status":"new","total":1000}`, string(entry.Old))
+	assert.JSONEq(t, `{"status":"paid","total":1500}`, string(entry.New))
+	assert.Equal(t, []string{"status", "total"}, entry.Fields, "redacted fields are not reported")
+	assert.Equal(t, now, entry.At)
+
+	select {
+	case event := <-changes:
+		assert.Equal(t, "o-1", event.Metadata.AggregateID)
+		assert.Equal(t, "alice", event.Actor)
+		assert.Equal(t, entry.New, event.New)
+	case <-time.After(time.Second):
+		t.Fatal("no order.updated event")
+	}
+}
+
+func TestHooks_BeforeHookCancels(t *testing.T) {
+	db, mock := newDB(t)
+	audit := repository.NewMemoryAuditLog()
+	hooks := repository.NewHooks[order]("order", repository.WithAudit(audit))
+	hooks.OnDelete(repository.Before, func(_ context.Context, _ *sql.Tx, change *repository.Change[order]) error {
+		if change.Old.Status == "paid" {
+			return domainerror.Conflictf("order %s is paid", change.ID)
+		}
+		return nil
+	})
+
+	mock.ExpectBegin()
+	mock.ExpectRollback()
+
+	err := hooks.Delete(context.Background(), db, "o-1", order{Status: "paid"},
+		func(context.Context, *sql.Tx, *repository.Change[order]) error {
+			t.Fatal("a cancelled write must not run")
+			return nil
+		})
+	assert.Equal(t, domainerror.Conflict, domainerror.KindOf(err))
+	assert.NoError(t, mock.ExpectationsWereMet())
+
+	history, err := audit.History(context.Background(), db, "order", "o-1")
+	require.NoError(t, err)
+	assert.Empty(t, history)
+}
+
+func TestHooks_WriteErrorRollsBack(t *testing.T) {
+	db, mock := newDB(t)
+	hooks := repository.NewHooks[order]("order")
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE orders")).WillReturnError(errors.New("connection reset"))
+	mock.ExpectRollback()
+
+	err := hooks.Update(context.Background(), db, "o-1", order{Status: "new"}, order{Status: "paid"}, updateOrder)
+	assert.ErrorContains(t, err, "connection reset")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestHooks_UnchangedUpdateIsSkipped(t *testing.T) {
+	db, mock := newDB(t)
+	audit := repository.NewMemoryAuditLog()
+	hooks := repository.NewHooks[order]("order", repository.WithAudit(audit))
+
+	mock.ExpectBegin()
+	mock.ExpectRollback()
+
+	same := order{Status: "paid", Total: 1000}
+	require.NoError(t, hooks.Update(context.Background(), db, "o-1", same, same, updateOrder))
+	assert.NoError(t, mock.ExpectationsWereMet())
+
+	history, err := audit.History(context.Background(), db, "order", "o-1")
+	require.NoError(t, err)
+	assert.Empty(t, history)
+}
+
+func TestHooks_CreateWithOutboxAndPostgresAudit(t *testing.T) {
+	db, mock := newDB(t)
+	hooks := repository.NewHooks[order]("order",
+		repository.WithClock(clock.NewFake(now)),
+		repository.WithAudit(repository.NewPostgresAuditLog()),
+		repository.WithOutbox(),
+		repository.WithActor(func(context.Context) string { return "importer" }),
+	)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO orders")).
+		WithArgs("o-2", "new").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO audit_log")).
+		WithArgs(sqlmock.AnyArg(), "order", "o-2", "created", "importer",
+			nil, []byte(`{"status":"new","total":0}`), sqlmock.AnyArg(), now).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO event_outbox")).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	err := hooks.Create(context.Background(), db, "o-2", order{Status: "new"},
+		func(ctx context.Context, tx *sql.Tx, change *repository.Change[order]) error {
+			_, err := tx.ExecContext(ctx, "INSERT INTO orders (id, status) VALUES ($1, $2)", change.ID, change.New.Status)
+			return err
+		})
+	require.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAuditLog_Erase(t *testing.T) {
+	ctx := context.Background()
+	audit := repository.NewMemoryAuditLog()
+	entries := []repository.Entry{
+		{ID: "1", Entity: "user", EntityID: "u-1", Operation: repository.Created,
+			New: json.RawMessage(`{"name":"Ann Lee","email":"ann@example.com"}`)},
+		{ID: "2", Entity: "order", EntityID: "o-1", Operation: repository.Created,
+			New: json.RawMessage(`{"contact":"ANN@example.com","note":"call +62 8123456789","phone":{"country_code":"62","number":"8123456789"},"total":1500}`)},
+		{ID: "3", Entity: "order", EntityID: "o-2", Operation: repository.Created,
+			New: json.RawMessage(`{"contact":"bob@example.com","total":900}`)},
+	}
+	for _, entry := range entries {
+		require.NoError(t, audit.Append(ctx, nil, entry))
+	}
+
+	subject := erasure.Subject{ID: "u-1", Emails: []string{"ann@example.com"},
+		Phones: []i18n.Phone{{CountryCode: "62", Number: "8123456789"}}}
+	anonymizer := erasure.NewAnonymizer(erasure.StrategyPseudonymize, []byte("secret"))
+	erased, err := audit.Erase(ctx, nil, subject, anonymizer)
+	require.NoError(t, err)
+	assert.Equal(t, 2, erased)
+
+	user, err := audit.History(ctx, nil, "user", "u-1")
+	require.NoError(t, err)
+	require.Len(t, user, 1)
+	assert.Nil(t, user[0].New, "the subject's own values are dropped")
+	assert.Equal(t, repository.Created, user[0].Operation)
+
+	order, err := audit.History(ctx, nil, "order", "o-1")
+	require.NoError(t, err)
+	require.Len(t, order, 1)
+	assert.NotContains(t, string(order[0].New), "example.com")
+	assert.NotContains(t, string(order[0].New), "8123456789")
+	assert.Contains(t, string(order[0].New), `"total":1500`)
+
+	other, err := audit.History(ctx, nil, "order", "o-2")
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"contact":"bob@example.com","total":900}`, string(other[0].New))
+
+	erased, err = audit.Erase(ctx, nil, subject, anonymizer)
+	require.NoError(t, err)
+	assert.Zero(t, erased, "erasing is idempotent")
+}
+
+func TestPostgresAuditLog_Erase(t *testing.T) {
+	db, mock := newDB(t)
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id, entity, entity_id, old_value, new_value FROM audit_log")).
+		WithArgs("u-1", sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "entity", "entity_id", "old_value", "new_value"}).
+			AddRow("1", "user", "u-1", nil, []byte(`{"email":"ann_lee@example.com"}`)).
+			AddRow("2", "order", "o-1", []byte(`{"contact":"ann.lee@example.com"}`), nil))
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE audit_log SET old_value = $2, new_value = $3 WHERE id = $1")).
+		WithArgs("1", nil, nil).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	// "_" is a LIKE wildcard, so ann.lee is a candidate
+	subject := erasure.Subject{ID: "u-1", Emails: []string{"ann_lee@example.com"}}
+	erased, err := repository.NewPostgresAuditLog().Erase(context.Background(), db, subject,
+		erasure.NewAnonymizer(erasure.StrategyScrub, nil))
+	require.NoError(t, err)
+	assert.Equal(t, 1, erased, "a candidate without the subject's data is left alone")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestChanged_EventName(t *testing.T) {
+	event := repository.Changed{Entity: "order", Operation: repository.Deleted}
+	assert.Equal(t, "order.deleted", event.EventName())
+
+	data, err := json.Marshal(event)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"operation":"deleted"`)
+}
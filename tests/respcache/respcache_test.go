@@ -0,0 +1,227 @@
+package respcache_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"golang-arch/internal/shared/respcache"
+	"golang-arch/pkg/clock"
+	"golang-arch/pkg/metrics"
+)
+
+var now = time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+
+type fixture struct {
+	clock    *clock.Fake
+	router   *gin.Engine
+	cache    *respcache.Cache
+	registry *metrics.Registry
+	calls    atomic.Int64
+	status   atomic.Int64
+	seen     atomic.Pointer[http.Request] // Last request the handler ran for
+}
+
+// newFixture serves /catalog, answering the number of the handler call
+func newFixture(t *testing.T, replay bool, options ...respcache.Option) *fixture {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	f := &fixture{clock: clock.NewFake(now), router: gin.New(), registry: metrics.NewRegistry()}
+	f.status.Store(http.StatusOK)
+	lookups, err := f.registry.Counter("lookups", "", "")
+	require.NoError(t, err)
+	options = append(options, respcache.WithClock(f.clock), respcache.WithLookupsCounter(lookups))
+	if replay {
+		options = append(options, respcache.WithReplay(f.router))
+	}
+	f.cache = respcache.New(time.Minute, 5*time.Minute, options...)
+
+	f.router.Use(func(c *gin.Context) {
+		// Per-request headers of earlier middleware are not cached
+		c.Header("X-Request-ID", strconv.FormatInt(f.calls.Load(), 10))
+	})
+	f.router.GET("/catalog", f.cache.Middleware(respcache.ByURL), func(c *gin.Context) {
+		f.seen.Store(c.Request)
+		call := f.calls.Add(1)
+		c.Header("X-Catalog", "v1")
+		c.JSON(int(f.status.Load()), gin.H{"call": call})
+	})
+	return f
+}
+
+func (f *fixture) get(t *testing.T, url string) *httptest.ResponseRecorder {
+	t.Helper()
+	w := httptest.NewRecorder()
+	f.router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, url, nil))
+	return w
+}
+
+func (f *fixture) lookups(result string) float64 {
+	for _, instrument := range f.registry.Snapshot() {
+		for _, series := range instrument.Series {
+			if series.Labels["result"] == result {
+				return series.Value
+			}
+		}
+	}
+	return 0
+}
+
+func TestHitAfterMiss(t *testing.T) {
+	f := newFixture(t, false)
+
+	w := f.get(t, "/catalog?b=2&a=1")
+	assert.Equal(t, "MISS", w.Header().Get(respcache.HeaderCache))
+	assert.JSONEq(t, `{"call":1}`, w.Body.String())
+
+	f.clock.Advance(10 * time.Second)
+	w = f.get(t, "/catalog?a=1&b=2")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "HIT", w.Header().Get(respcache.HeaderCache))
+	assert.Equal(t, "10", w.Header().Get("Age"))
+	assert.Equal(t, "v1", w.Header().Get("X-Catalog"))
+	assert.Equal(t, "application/json; charset=utf-8", w.Header().Get("Content-Type"))
+	assert.Equal(t, "1", w.Header().Get("X-Request-ID"), "set by the middleware of this request")
+	assert.JSONEq(t, `{"call":1}`, w.Body.String())
+
+	assert.Equal(t, int64(1), f.calls.Load())
+	assert.Equal(t, float64(1), f.lookups(respcache.ResultMiss))
+	assert.Equal(t, float64(1), f.lookups(respcache.ResultHit))
+
+	// Another query is another response
+	assert.JSONEq(t, `{"call":2}`, f.get(t, "/catalog?a=2").Body.String())
+}
+
+func TestErrorsAreNotCached(t *testing.T) {
+	f := newFixture(t, false)
+	f.status.Store(http.StatusInternalServerError)
+
+	assert.Equal(t, http.StatusInternalServerError, f.get(t, "/catalog").Code)
+	assert.Equal(t, http.StatusInternalServerError, f.get(t, "/catalog").Code)
+	assert.Equal(t, int64(2), f.calls.Load())
+	assert.Equal(t, 0, f.cache.Len())
+}
+
+func TestStaleRefreshedByFirstRequest(t *testing.T) {
+	f := newFixture(t, false)
+	f.get(t, "/catalog")
+
+	f.clock.Advance(2 * time.Minute)
+	w := f.get(t, "/catalog")
+	assert.Equal(t, "MISS", w.Header().Get(respcache.HeaderCache))
+	assert.JSONEq(t, `{"call":2}`, w.Body.String())
+
+	w = f.get(t, "/catalog")
+	assert.Equal(t, "HIT", w.Header().Get(respcache.HeaderCache))
+	assert.JSONEq(t, `{"call":2}`, w.Body.String())
+}
+
+func TestStaleRefreshedInBackground(t *testing.T) {
+	f := newFixture(t, true)
+	f.get(t, "/catalog")
+
+	f.clock.Advance(2 * time.Minute)
+	w := f.get(t, "/catalog")
+	assert.Equal(t, "STALE", w.Header().Get(respcache.HeaderCache))
+	assert.JSONEq(t, `{"call":1}`, w.Body.String())
+
+	require.Eventually(t, func() bool {
+		return f.get(t, "/catalog").Header().Get(respcache.HeaderCache) == "HIT"
+	}, time.Second, time.Millisecond)
+	assert.JSONEq(t, `{"call":2}`, f.get(t, "/catalog").Body.String())
+	assert.Equal(t, int64(2), f.calls.Load())
+}
+
+func TestReplayDropsCredentials(t *testing.T) {
+	f := newFixture(t, true, respcache.WithStripHeaders("X-Tenant-Key"))
+	get := func() *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodGet, "/catalog", nil)
+		r.Header.Set("Authorization", "Bearer token")
+		r.Header.Set("X-API-Key", "key")
+		r.Header.Set("X-Tenant-Key", "tenant")
+		r.Header.Set("Accept-Language", "de")
+		w := httptest.NewRecorder()
+		f.router.ServeHTTP(w, r)
+		return w
+	}
+	get()
+	assert.False(t, respcache.Replayed(f.seen.Load().Context()))
+
+	f.clock.Advance(2 * time.Minute)
+	assert.Equal(t, "STALE", get().Header().Get(respcache.HeaderCache))
+	require.Eventually(t, func() bool { return f.calls.Load() == 2 }, time.Second, time.Millisecond)
+
+	replay := f.seen.Load()
+	assert.True(t, respcache.Replayed(replay.Context()))
+	assert.Empty(t, replay.Header.Get("Authorization"))
+	assert.Empty(t, replay.Header.Get("X-API-Key"))
+	assert.Empty(t, replay.Header.Get("X-Tenant-Key"))
+	assert.Equal(t, "de", replay.Header.Get("Accept-Language"), "other headers may shape the response")
+}
+
+func TestExpiredPastStale(t *testing.T) {
+	f := newFixture(t, true)
+	f.get(t, "/catalog")
+
+	f.clock.Advance(6 * time.Minute)
+	w := f.get(t, "/catalog")
+	assert.Equal(t, "MISS", w.Header().Get(respcache.HeaderCache))
+	assert.JSONEq(t, `{"call":2}`, w.Body.String())
+}
+
+func TestConcurrentMissesCoalesced(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	cache := respcache.New(time.Minute, 0)
+	release := make(chan struct{})
+	var calls atomic.Int64
+	router := gin.New()
+	router.GET("/catalog", cache.Middleware(respcache.ByURL), func(c *gin.Context) {
+		calls.Add(1)
+		<-release
+		c.String(http.StatusOK, "catalog")
+	})
+
+	var wg sync.WaitGroup
+	bodies := make([]string, 20)
+	for i := range bodies {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/catalog", nil))
+			bodies[i] = w.Body.String()
+		}()
+	}
+	require.Eventually(t, func() bool { return calls.Load() == 1 }, time.Second, time.Millisecond)
+	time.Sleep(10 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	assert.Equal(t, int64(1), calls.Load())
+	for _, body := range bodies {
+		assert.Equal(t, "catalog", body)
+	}
+}
+
+func TestMaxEntries(t *testing.T) {
+	f := newFixture(t, false, respcache.WithMaxEntries(2))
+	f.get(t, "/catalog?q=a")
+	f.get(t, "/catalog?q=b")
+	f.get(t, "/catalog?q=a") // a is now the most recently used
+	f.get(t, "/catalog?q=c")
+	assert.Equal(t, 2, f.cache.Len())
+
+	assert.Equal(t, "HIT", f.get(t, "/catalog?q=a").Header().Get(respcache.HeaderCache))
+	assert.Equal(t, "MISS", f.get(t, "/catalog?q=b").Header().Get(respcache.HeaderCache))
+
+	f.cache.Purge()
+	assert.Equal(t, 0, f.cache.Len())
+}
@@ -0,0 +1,192 @@
+package retention_test
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"golang-arch/internal/shared/retention"
+	"golang-arch/internal/shared/storage"
+	"golang-arch/pkg/clock"
+	"golang-arch/pkg/metrics"
+)
+
+var now = time.Date(2024, 6, 1, 3, 0, 0, 0, time.UTC)
+
+const day = 24 * time.Hour
+
+type fixture struct {
+	store    *retention.MemoryStore
+	blobs    *storage.MemoryStore
+	registry *metrics.Registry
+	service  *retention.Service
+}
+
+func newFixture(t *testing.T, options ...retention.Option) *fixture {
+	t.Helper()
+	clk := clock.NewFake(now)
+	f := &fixture{
+		store:    retention.NewMemoryStore(),
+		blobs:    storage.NewMemoryStore(clk),
+		registry: metrics.NewRegistry(),
+	}
+	rows, err := f.registry.Counter("worker.retention.rows", "", "{row}")
+	require.NoError(t, err)
+	f.service = retention.NewService(f.store, f.blobs,
+		append([]retention.Option{retention.WithClock(clk), retention.WithRowsCounter(rows), retention.WithBatchSize(2)}, options...)...)
+	return f
+}
+
+// addRows inserts one row per age into table
+func (f *fixture) addRows(table string, ages ...time.Duration) {
+	for i, age := range ages {
+		f.store.Add(table, now.Add(-age), json.RawMessage(fmt.Sprintf(`{"id":%d}`, i+1)))
+	}
+}
+
+func (f *fixture) rows(policy, action string) float64 {
+	for _, instrument := range f.registry.Snapshot() {
+		for _, series := range instrument.Series {
+			if series.Labels["policy"] == policy && series.Labels["action"] == action {
+				return series.Value
+			}
+		}
+	}
+	return 0
+}
+
+// archived reads the JSON lines of an archive blob
+func (f *fixture) archived(t *testing.T, key string) []string {
+	t.Helper()
+	body, info, err := f.blobs.Get(context.Background(), key)
+	require.NoError(t, err)
+	defer body.Close()
+	assert.Equal(t, "application/gzip", info.ContentType)
+
+	zr, err := gzip.NewReader(body)
+	require.NoError(t, err)
+	var lines []string
+	scanner := bufio.NewScanner(zr)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	require.NoError(t, scanner.Err())
+	return lines
+}
+
+func TestService_ArchivesAndDeletesInBatches(t *testing.T) {
+	f := newFixture(t)
+	f.service.Register(retention.Policy{Name: "audit_log", Table: "audit_log", TimeColumn: "changed_at", MaxAge: 180 * day, Archive: true})
+	f.addRows("audit_log", 400*day, 200*day, 181*day, 179*day, day)
+
+	require.NoError(t, f.service.Run(context.Background()))
+
+	assert.Equal(t, 2, f.store.Len("audit_log"), "rows younger than 180 days are kept")
+	assert.Equal(t, []string{`{"id":1}`, `{"id":2}`}, f.archived(t, "retention/audit_log/2024/06/01/030000-0001.jsonl.gz"))
+	assert.Equal(t, []string{`{"id":3}`}, f.archived(t, "retention/audit_log/2024/06/01/030000-0002.jsonl.gz"))
+	assert.Equal(t, float64(3), f.rows("audit_log", "archived"))
+	assert.Equal(t, float64(3), f.rows("audit_log", "deleted"))
+
+	require.NoError(t, f.service.Run(context.Background()))
+	assert.Equal(t, 2, f.store.Len("audit_log"), "a second run finds nothing to purge")
+}
+
+func TestService_ConfiguredMaxAges(t *testing.T) {
+	f := newFixture(t, retention.WithMaxAges(map[string]time.Duration{"outbox": 7 * day, "sessions": 0}))
+	f.service.Register(retention.Policy{Name: "outbox", Table: "event_outbox", TimeColumn: "published_at", MaxAge: 30 * day})
+	f.service.Register(retention.Policy{Name: "sessions", Table: "sessions", TimeColumn: "expires_at", MaxAge: day})
+	f.addRows("event_outbox", 10*day, 3*day)
+	f.addRows("sessions", 10*day)
+
+	require.NoError(t, f.service.Run(context.Background()))
+
+	assert.Equal(t, 1, f.store.Len("event_outbox"), "the configured age replaces the registered one")
+	assert.Equal(t, 1, f.store.Len("sessions"), "an age of 0 keeps rows forever")
+	assert.Equal(t, float64(0), f.rows("outbox", "archived"))
+	assert.Equal(t, float64(1), f.rows("outbox", "deleted"))
+	assert.Equal(t, 7*day, f.service.Policies()[0].MaxAge)
+}
+
+func TestService_FailedArchiveKeepsRows(t *testing.T) {
+	f := newFixture(t)
+	f.service = retention.NewService(f.store, failingBlobs{f.blobs}, retention.WithClock(clock.NewFake(now)))
+	f.service.Register(retention.Policy{Name: "audit_log", Table: "audit_log", TimeColumn: "changed_at", MaxAge: day, Archive: true})
+	f.service.Register(retention.Policy{Name: "outbox", Table: "event_outbox", TimeColumn: "published_at", MaxAge: day})
+	f.addRows("audit_log", 2*day)
+	f.addRows("event_outbox", 2*day)
+
+	err := f.service.Run(context.Background())
+	assert.ErrorContains(t, err, "retention audit_log")
+	assert.Equal(t, 1, f.store.Len("audit_log"))
+	assert.Equal(t, 0, f.store.Len("event_outbox"), "other policies go on")
+}
+
+func TestService_RegisterRejectsInvalidPolicies(t *testing.T) {
+	f := newFixture(t)
+	f.service.Register(retention.Policy{Name: "audit_log", Table: "audit_log", TimeColumn: "changed_at"})
+
+	assert.Panics(t, func() {
+		f.service.Register(retention.Policy{Name: "audit_log", Table: "audit_log", TimeColumn: "changed_at"})
+	})
+	assert.Panics(t, func() {
+		f.service.Register(retention.Policy{Name: "evil", Table: "users; DROP TABLE users", TimeColumn: "created_at"})
+	})
+	assert.Panics(t, func() {
+		f.service.Register(retention.Policy{Name: "", Table: "users", TimeColumn: "created_at"})
+	})
+}
+
+func TestPostgresStore_Purge(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+	store := retention.NewPostgresStore(db)
+	policy := retention.Policy{Name: "audit_log", Table: "audit_log", TimeColumn: "changed_at"}
+	before := now.Add(-day)
+	purge := regexp.QuoteMeta(`DELETE FROM audit_log AS expired WHERE ctid IN (
+			SELECT ctid FROM audit_log WHERE changed_at < $1 ORDER BY changed_at LIMIT $2 FOR UPDATE SKIP LOCKED
+		) RETURNING row_to_json(expired)::text`)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(purge).WithArgs(before, 100).
+		WillReturnRows(sqlmock.NewRows([]string{"row_to_json"}).AddRow(`{"id":"a"}`).AddRow(`{"id":"b"}`))
+	mock.ExpectCommit()
+	var archived []json.RawMessage
+	purged, err := store.Purge(context.Background(), policy, before, 100, func(rows []json.RawMessage) error {
+		archived = rows
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 2, purged)
+	assert.Equal(t, []json.RawMessage{json.RawMessage(`{"id":"a"}`), json.RawMessage(`{"id":"b"}`)}, archived)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(purge).WithArgs(before, 100).
+		WillReturnRows(sqlmock.NewRows([]string{"row_to_json"}).AddRow(`{"id":"c"}`))
+	mock.ExpectRollback()
+	_, err = store.Purge(context.Background(), policy, before, 100, func([]json.RawMessage) error {
+		return errors.New("bucket unavailable")
+	})
+	assert.ErrorContains(t, err, "bucket unavailable")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// failingBlobs rejects every upload
+type failingBlobs struct {
+	storage.BlobStore
+}
+
+func (failingBlobs) Put(context.Context, string, io.Reader, storage.PutOptions) (storage.Info, error) {
+	return storage.Info{}, errors.New("bucket unavailable")
+}
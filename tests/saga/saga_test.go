@@ -0,0 +1,302 @@
+package saga_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"golang-arch/internal/shared/domain/domainerror"
+	"golang-arch/internal/shared/events"
+	"golang-arch/internal/shared/saga"
+	"golang-arch/pkg/clock"
+)
+
+var start = time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+// ledger records the actions of the fake services a payout saga calls
+type ledger struct {
+	mu      sync.Mutex
+	actions []string
+}
+
+func (l *ledger) record(action string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.actions = append(l.actions, action)
+}
+
+func (l *ledger) list() []string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return append([]string(nil), l.actions...)
+}
+
+// step returns a step recording its action and compensation; fail decides
+// whether a try of the action fails
+func (l *ledger) step(name string, fail func() error) saga.Step {
+	return saga.Step{
+		Name: name,
+		Run: func(ctx context.Context, data saga.Data) error {
+			if fail != nil {
+				if err := fail(); err != nil {
+					return err
+				}
+			}
+			l.record(name)
+			return data.Set(name, true)
+		},
+		Compensate: func(ctx context.Context, data saga.Data) error {
+			l.record("undo " + name)
+			return nil
+		},
+	}
+}
+
+// failTimes fails the first n tries
+func failTimes(n int) func() error {
+	return func() error {
+		if n > 0 {
+			n--
+			return errors.New("service unavailable")
+		}
+		return nil
+	}
+}
+
+type fixture struct {
+	store        *saga.MemoryStore
+	clock        *clock.Fake
+	bus          *events.Bus
+	orchestrator *saga.Orchestrator
+	ledger       *ledger
+}
+
+func newFixture(t *testing.T, options ...saga.Option) *fixture {
+	t.Helper()
+	f := &fixture{
+		store:  saga.NewMemoryStore(),
+		clock:  clock.NewFake(start),
+		bus:    events.NewBus(zap.NewNop()),
+		ledger: &ledger{},
+	}
+	t.Cleanup(func() { _ = f.bus.Close() })
+	options = append([]saga.Option{
+		saga.WithClock(f.clock),
+		saga.WithEvents(f.bus),
+		saga.WithRetryBackoff(10 * time.Second),
+		saga.WithLease(time.Minute),
+	}, options...)
+	f.orchestrator = saga.NewOrchestrator(f.store, options...)
+	return f
+}
+
+func (f *fixture) register(steps ...saga.Step) {
+	f.orchestrator.Register(saga.Definition{Name: "payout", Steps: steps})
+}
+
+func TestOrchestrator_Completes(t *testing.T) {
+	f := newFixture(t)
+	f.register(f.ledger.step("charge", nil), f.ledger.step("convert", nil), f.ledger.step("payout", nil))
+	finished := make(chan saga.Finished, 1)
+	f.bus.Subscribe(saga.EventCompleted, func(ctx context.Context, event events.Event) error {
+		finished <- event.(saga.Finished)
+		return nil
+	})
+
+	data := saga.Data{}
+	require.NoError(t, data.Set("amount", 1500))
+	instance, err := f.orchestrator.Start(context.Background(), "payout", data)
+	require.NoError(t, err)
+	assert.Equal(t, saga.StatusCompleted, instance.Status)
+	assert.Equal(t, []string{"charge", "convert", "payout"}, f.ledger.list())
+
+	stored, err := f.orchestrator.Get(context.Background(), instance.ID)
+	require.NoError(t, err)
+	assert.Equal(t, saga.StatusCompleted, stored.Status)
+	var amount int
+	found, err := stored.Data.Get("amount", &amount)
+	require.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, 1500, amount)
+	found, err = stored.Data.Get("payout", new(bool))
+	require.NoError(t, err)
+	assert.True(t, found, "steps share data")
+
+	select {
+	case event := <-finished:
+		assert.Equal(t, instance.ID, event.InstanceID)
+		assert.Equal(t, "payout", event.Saga)
+		assert.Equal(t, "saga", event.EventMetadata().AggregateType)
+	case <-time.After(time.Second):
+		t.Fatal("no saga.completed event")
+	}
+
+	_, err = f.orchestrator.Start(context.Background(), "refund", nil)
+	assert.Equal(t, domainerror.NotFound, domainerror.KindOf(err))
+	_, err = f.orchestrator.Get(context.Background(), "missing")
+	assert.Equal(t, domainerror.NotFound, domainerror.KindOf(err))
+}
+
+func TestOrchestrator_RetriesWithBackoff(t *testing.T) {
+	f := newFixture(t)
+	f.register(f.ledger.step("charge", nil), f.ledger.step("convert", failTimes(2)), f.ledger.step("payout", nil))
+	ctx := context.Background()
+
+	instance, err := f.orchestrator.Start(ctx, "payout", nil)
+	require.NoError(t, err)
+	assert.Equal(t, saga.StatusRunning, instance.Status)
+	assert.Equal(t, 1, instance.Step)
+	assert.Equal(t, 1, instance.Attempts)
+	assert.Equal(t, start.Add(10*time.Second), instance.NextAttemptAt)
+
+	require.NoError(t, f.orchestrator.Resume(ctx))
+	assert.Equal(t, []string{"charge"}, f.ledger.list(), "retries wait for the backoff")
+
+	f.clock.Advance(10 * time.Second)
+	require.NoError(t, f.orchestrator.Resume(ctx))
+	instance, err = f.orchestrator.Get(ctx, instance.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 2, instance.Attempts)
+	assert.Equal(t, start.Add(30*time.Second), instance.NextAttemptAt, "the backoff doubles")
+
+	f.clock.Advance(20 * time.Second)
+	require.NoError(t, f.orchestrator.Resume(ctx))
+	instance, err = f.orchestrator.Get(ctx, instance.ID)
+	require.NoError(t, err)
+	assert.Equal(t, saga.StatusCompleted, instance.Status)
+	assert.Equal(t, []string{"charge", "convert", "payout"}, f.ledger.list())
+}
+
+func TestOrchestrator_CompensatesInReverse(t *testing.T) {
+	f := newFixture(t)
+	f.register(
+		f.ledger.step("charge", nil),
+		f.ledger.step("convert", nil),
+		f.ledger.step("payout", func() error { return saga.Abort("account closed") }),
+	)
+	compensated := make(chan saga.Finished, 1)
+	f.bus.Subscribe(saga.EventCompensated, func(ctx context.Context, event events.Event) error {
+		compensated <- event.(saga.Finished)
+		return nil
+	})
+
+	instance, err := f.orchestrator.Start(context.Background(), "payout", nil)
+	require.NoError(t, err)
+	assert.Equal(t, saga.StatusCompensated, instance.Status)
+	assert.Equal(t, "payout: saga aborted: account closed", instance.Error)
+	assert.Equal(t, []string{"charge", "convert", "undo convert", "undo charge"}, f.ledger.list(),
+		"an aborted step is not retried and the failed step is not compensated")
+
+	select {
+	case event := <-compensated:
+		assert.Equal(t, instance.Error, event.Error)
+	case <-time.After(time.Second):
+		t.Fatal("no saga.compensated event")
+	}
+}
+
+func TestOrchestrator_CompensatesAfterLastAttempt(t *testing.T) {
+	f := newFixture(t, saga.WithMaxAttempts(2))
+	f.register(f.ledger.step("charge", nil), f.ledger.step("convert", failTimes(5)))
+	ctx := context.Background()
+
+	instance, err := f.orchestrator.Start(ctx, "payout", nil)
+	require.NoError(t, err)
+	assert.Equal(t, saga.StatusRunning, instance.Status)
+
+	f.clock.Advance(10 * time.Second)
+	require.NoError(t, f.orchestrator.Resume(ctx))
+	instance, err = f.orchestrator.Get(ctx, instance.ID)
+	require.NoError(t, err)
+	assert.Equal(t, saga.StatusCompensated, instance.Status)
+	assert.Equal(t, "convert: service unavailable", instance.Error)
+	assert.Equal(t, []string{"charge", "undo charge"}, f.ledger.list())
+}
+
+func TestOrchestrator_FailsWhenCompensationFails(t *testing.T) {
+	f := newFixture(t, saga.WithMaxAttempts(1))
+	charge := f.ledger.step("charge", nil)
+	charge.Compensate = func(ctx context.Context, data saga.Data) error {
+		return errors.New("refund rejected")
+	}
+	f.register(charge, f.ledger.step("payout", func() error { return saga.Abort("account closed") }))
+	failed := make(chan saga.Finished, 1)
+	f.bus.Subscribe(saga.EventFailed, func(ctx context.Context, event events.Event) error {
+		failed <- event.(saga.Finished)
+		return nil
+	})
+
+	instance, err := f.orchestrator.Start(context.Background(), "payout", nil)
+	require.NoError(t, err)
+	assert.Equal(t, saga.StatusFailed, instance.Status)
+	assert.Contains(t, instance.Error, "compensating charge: refund rejected")
+	select {
+	case <-failed:
+	case <-time.After(time.Second):
+		t.Fatal("no saga.failed event")
+	}
+}
+
+func TestOrchestrator_DiscardsDataOfFailedStep(t *testing.T) {
+	f := newFixture(t)
+	f.register(saga.Step{
+		Name: "charge",
+		Run: func(ctx context.Context, data saga.Data) error {
+			_ = data.Set("charge_id", "ch_1")
+			panic("gateway client crashed")
+		},
+	})
+
+	instance, err := f.orchestrator.Start(context.Background(), "payout", nil)
+	require.NoError(t, err)
+	assert.Equal(t, saga.StatusRunning, instance.Status, "a panic is retried like an error")
+	assert.NotContains(t, instance.Data, "charge_id")
+}
+
+// crashingStore fails saves after the first armed one, simulating a runner
+// that dies in the middle of a saga
+type crashingStore struct {
+	*saga.MemoryStore
+	crashed bool
+}
+
+func (s *crashingStore) Save(ctx context.Context, instance saga.Instance) error {
+	if s.crashed {
+		return errors.New("runner crashed")
+	}
+	s.crashed = true
+	return s.MemoryStore.Save(ctx, instance)
+}
+
+func TestOrchestrator_ResumesAfterCrash(t *testing.T) {
+	f := newFixture(t)
+	store := &crashingStore{MemoryStore: f.store}
+	crashing := saga.NewOrchestrator(store, saga.WithClock(f.clock), saga.WithLease(time.Minute))
+	steps := []saga.Step{f.ledger.step("charge", nil), f.ledger.step("convert", nil), f.ledger.step("payout", nil)}
+	crashing.Register(saga.Definition{Name: "payout", Steps: steps})
+	f.register(steps...)
+	ctx := context.Background()
+
+	instance, err := crashing.Start(ctx, "payout", nil)
+	require.Error(t, err)
+
+	require.NoError(t, f.orchestrator.Resume(ctx))
+	stored, err := f.orchestrator.Get(ctx, instance.ID)
+	require.NoError(t, err)
+	assert.Equal(t, saga.StatusRunning, stored.Status, "the runner's lease is honoured")
+	assert.Equal(t, 1, stored.Step)
+
+	f.clock.Advance(time.Minute)
+	require.NoError(t, f.orchestrator.Resume(ctx))
+	stored, err = f.orchestrator.Get(ctx, instance.ID)
+	require.NoError(t, err)
+	assert.Equal(t, saga.StatusCompleted, stored.Status)
+	assert.Equal(t, []string{"charge", "convert", "convert", "payout"}, f.ledger.list(),
+		"the step whose result was not saved runs again")
+}
@@ -0,0 +1,108 @@
+package schedule_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"golang-arch/pkg/schedule"
+)
+
+func mustTime(t *testing.T, value string) time.Time {
+	t.Helper()
+	parsed, err := time.Parse(time.RFC3339, value)
+	require.NoError(t, err)
+	return parsed
+}
+
+func TestParse_Next(t *testing.T) {
+	tests := []struct {
+		name string
+		expr string
+		from string
+		want string
+	}{
+		{"every minute", "* * * * *", "2024-01-01T10:00:30Z", "2024-01-01T10:01:00Z"},
+		{"hourly", "@hourly", "2024-01-01T10:00:00Z", "2024-01-01T11:00:00Z"},
+		{"daily", "@daily", "2024-01-01T10:00:00Z", "2024-01-02T00:00:00Z"},
+		{"step", "*/15 * * * *", "2024-01-01T10:16:00Z", "2024-01-01T10:30:00Z"},
+		{"range with step", "0-30/10 9 * * *", "2024-01-01T09:25:00Z", "2024-01-01T09:30:00Z"},
+		{"list", "0 6,18 * * *", "2024-01-01T07:00:00Z", "2024-01-01T18:00:00Z"},
+		{"weekdays", "0 9 * * 1-5", "2024-01-05T10:00:00Z", "2024-01-08T09:00:00Z"},
+		{"sunday as 7", "0 0 * * 7", "2024-01-01T00:00:00Z", "2024-01-07T00:00:00Z"},
+		{"month rollover", "0 0 1 * *", "2024-01-31T12:00:00Z", "2024-02-01T00:00:00Z"},
+		{"leap day", "0 0 29 2 *", "2024-03-01T00:00:00Z", "2028-02-29T00:00:00Z"},
+		{"day of month or weekday", "0 0 13 * 5", "2024-01-01T00:00:00Z", "2024-01-05T00:00:00Z"},
+		{"every interval", "@every 90m", "2024-01-01T10:00:00Z", "2024-01-01T11:30:00Z"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := schedule.Parse(tt.expr)
+			require.NoError(t, err)
+			assert.Equal(t, mustTime(t, tt.want), s.Next(mustTime(t, tt.from)).UTC())
+		})
+	}
+}
+
+func TestParseCron_Timezone(t *testing.T) {
+	s, err := schedule.ParseCron("CRON_TZ=America/New_York 0 9 * * *")
+	require.NoError(t, err)
+	assert.Equal(t, "America/New_York", s.Location().String())
+
+	// 09:00 local time is 14:00 UTC in winter and 13:00 UTC in summer
+	assert.Equal(t, mustTime(t, "2024-01-15T14:00:00Z"), s.Next(mustTime(t, "2024-01-15T12:00:00Z")).UTC())
+	assert.Equal(t, mustTime(t, "2024-07-15T13:00:00Z"), s.Next(mustTime(t, "2024-07-15T12:00:00Z")).UTC())
+
+	// The hour skipped by spring-forward is not matched; the next day is
+	skipped, err := schedule.ParseCron("CRON_TZ=America/New_York 30 2 * * *")
+	require.NoError(t, err)
+	next := skipped.Next(mustTime(t, "2024-03-10T05:00:00Z"))
+	assert.Equal(t, mustTime(t, "2024-03-11T06:30:00Z"), next.UTC())
+}
+
+func TestParseCron_KeepsCallerLocation(t *testing.T) {
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	require.NoError(t, err)
+
+	s, err := schedule.ParseCron("@hourly")
+	require.NoError(t, err)
+	next := s.Next(time.Date(2024, 1, 1, 10, 20, 0, 0, tokyo))
+	assert.Equal(t, tokyo, next.Location())
+	assert.Equal(t, 11, next.Hour())
+}
+
+func TestParse_NeverFires(t *testing.T) {
+	s, err := schedule.Parse("0 0 30 2 *")
+	require.NoError(t, err)
+	assert.True(t, s.Next(mustTime(t, "2024-01-01T00:00:00Z")).IsZero())
+}
+
+func TestParse_Invalid(t *testing.T) {
+	for _, expr := range []string{
+		"",
+		"* * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"5-1 * * * *",
+		"*/0 * * * *",
+		"a * * * *",
+		"@every",
+		"@every -5m",
+		"CRON_TZ=Mars/Olympus 0 * * * *",
+	} {
+		_, err := schedule.Parse(expr)
+		assert.Error(t, err, expr)
+	}
+}
+
+func TestMustParse(t *testing.T) {
+	assert.Equal(t, "@every 5m0s", schedule.MustParse("@every 5m").(schedule.Interval).String())
+	assert.Equal(t, "0 * * * *", schedule.MustParse("0 * * * *").(*schedule.CronSchedule).String())
+	assert.Panics(t, func() { schedule.MustParse("nope") })
+}
@@ -0,0 +1,116 @@
+package schema_test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	i18n "golang-arch/internal/shared/domain/internationalization"
+	"golang-arch/internal/shared/schema"
+)
+
+const publishedDir = "../../docs/09-internationalization/schemas"
+
+type order struct {
+	ID    string            `json:"id"`
+	Note  string            `json:"note,omitempty"`
+	Tags  []string          `json:"tags"`
+	Total i18n.Money        `json:"total"`
+	Items []orderItem       `json:"items"`
+	Meta  map[string]string `json:"-"`
+	draft bool
+}
+
+type orderItem struct {
+	Quantity int `json:"quantity"`
+}
+
+func TestGenerate_StructFields(t *testing.T) {
+	doc, err := schema.Generate(order{}, "https://example.test/")
+	require.NoError(t, err)
+
+	assert.Equal(t, schema.Draft, doc.Schema)
+	assert.Equal(t, "https://example.test/order.schema.json", doc.ID)
+	assert.Equal(t, "order", doc.Title)
+	assert.Equal(t, []string{"id", "items", "tags", "total"}, doc.Required)
+	assert.NotContains(t, doc.Properties, "Meta")
+	assert.Contains(t, doc.Properties, "note")
+	assert.NotContains(t, doc.Properties, "draft")
+	assert.Equal(t, "array", doc.Properties["tags"].Type)
+	assert.Equal(t, "string", doc.Properties["tags"].Items.Type)
+	assert.Equal(t, "#/$defs/Money", doc.Properties["total"].Ref)
+	assert.Equal(t, "#/$defs/orderItem", doc.Properties["items"].Items.Ref)
+	assert.Contains(t, doc.Defs, "Currency")
+	assert.Equal(t, "integer", doc.Defs["orderItem"].Properties["quantity"].Type)
+}
+
+func TestGenerate_RejectsNonStruct(t *testing.T) {
+	_, err := schema.Generate(42, "")
+	assert.Error(t, err)
+}
+
+func TestFileName(t *testing.T) {
+	assert.Equal(t, "money.schema.json", schema.FileName("Money"))
+	assert.Equal(t, "localized_date_time.schema.json", schema.FileName("LocalizedDateTime"))
+}
+
+func TestGraphQL(t *testing.T) {
+	doc, err := schema.Generate(i18n.Money{}, "https://example.test/")
+	require.NoError(t, err)
+
+	sdl := schema.GraphQL(doc)
+	assert.Contains(t, sdl, `scalar Money @specifiedBy(url: "https://example.test/money.schema.json")`)
+	assert.Contains(t, sdl, "minor units")
+}
+
+// TestPublishedSchemasUpToDate fails when the committed documents drift from
+// the Go types; run `make schemas` to refresh them.
+func TestPublishedSchemasUpToDate(t *testing.T) {
+	dir := t.TempDir()
+	paths, err := schema.WriteFiles(dir, "", i18n.SchemaTypes...)
+	require.NoError(t, err)
+
+	for _, path := range paths {
+		generated, err := os.ReadFile(path)
+		require.NoError(t, err)
+
+		published, err := os.ReadFile(filepath.Join(publishedDir, filepath.Base(path)))
+		require.NoError(t, err, "missing published schema; run make schemas")
+		assert.Equal(t, string(generated), string(published), "%s is stale; run make schemas", filepath.Base(path))
+	}
+}
+
+// TestSchemasDescribeWireFormat checks that the JSON the types actually emit
+// only uses declared properties and includes every required one.
+func TestSchemasDescribeWireFormat(t *testing.T) {
+	money, err := i18n.MakeMoney(10050, "USD")
+	require.NoError(t, err)
+	phone, err := i18n.MakePhone("1", "5551234567")
+	require.NoError(t, err)
+	tz, err := i18n.MakeTimezone("Asia/Tokyo")
+	require.NoError(t, err)
+	ldt, err := i18n.MakeLocalizedDateTime(1703520000, "Asia/Tokyo")
+	require.NoError(t, err)
+
+	for _, value := range []any{money, phone, tz, ldt} {
+		doc, err := schema.Generate(value, "")
+		require.NoError(t, err)
+
+		data, err := json.Marshal(value)
+		require.NoError(t, err)
+
+		var decoded map[string]json.RawMessage
+		require.NoError(t, json.Unmarshal(data, &decoded))
+
+		for key := range decoded {
+			assert.Contains(t, doc.Properties, key, "%s emits undeclared property", doc.Title)
+		}
+		for _, key := range doc.Required {
+			assert.Contains(t, decoded, key, "%s omits required property", doc.Title)
+		}
+	}
+}
@@ -0,0 +1,142 @@
+package singleflight_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"golang-arch/pkg/singleflight"
+)
+
+func TestGroup_CollapsesConcurrentCalls(t *testing.T) {
+	var group singleflight.Group[string, int]
+	var calls atomic.Int32
+	release := make(chan struct{})
+
+	const callers = 50
+	var wg sync.WaitGroup
+	results := make([]int, callers)
+	shared := make([]bool, callers)
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			value, isShared, err := group.Do(context.Background(), "EUR/USD", func(context.Context) (int, error) {
+				calls.Add(1)
+				<-release
+				return 42, nil
+			})
+			assert.NoError(t, err)
+			results[i], shared[i] = value, isShared
+		}(i)
+	}
+	// Let every caller join the call in flight before it completes
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	assert.Equal(t, int32(1), calls.Load())
+	for i := range results {
+		assert.Equal(t, 42, results[i])
+		assert.True(t, shared[i])
+	}
+
+	// Completed calls are not cached
+	value, isShared, err := group.Do(context.Background(), "EUR/USD", func(context.Context) (int, error) {
+		return 7, nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 7, value)
+	assert.False(t, isShared)
+}
+
+func TestGroup_KeysAreIndependent(t *testing.T) {
+	var group singleflight.Group[string, string]
+	a, _, err := group.Do(context.Background(), "a", func(context.Context) (string, error) { return "A", nil })
+	require.NoError(t, err)
+	b, _, err := group.Do(context.Background(), "b", func(context.Context) (string, error) { return "B", nil })
+	require.NoError(t, err)
+	assert.Equal(t, []string{"A", "B"}, []string{a, b})
+
+	_, _, err = group.Do(context.Background(), "c", func(context.Context) (string, error) { return "", assert.AnError })
+	assert.ErrorIs(t, err, assert.AnError)
+}
+
+func TestGroup_CallerCancellation(t *testing.T) {
+	var group singleflight.Group[int, string]
+	release := make(chan struct{})
+	started := make(chan struct{})
+	fnErr := make(chan error, 1)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		_, _, err := group.Do(ctx, 1, func(ctx context.Context) (string, error) {
+			close(started)
+			<-release
+			fnErr <- ctx.Err()
+			return "value", nil
+		})
+		done <- err
+	}()
+	<-started
+
+	waiter := make(chan string, 1)
+	go func() {
+		value, _, err := group.Do(context.Background(), 1, func(context.Context) (string, error) {
+			t.Error("second caller started a new call")
+			return "", nil
+		})
+		assert.NoError(t, err)
+		waiter <- value
+	}()
+
+	// The first caller gives up; the call and the other caller are unaffected
+	cancel()
+	assert.ErrorIs(t, <-done, context.Canceled)
+	time.Sleep(10 * time.Millisecond)
+	close(release)
+	assert.Equal(t, "value", <-waiter)
+	assert.NoError(t, <-fnErr)
+}
+
+func TestGroup_Panic(t *testing.T) {
+	var group singleflight.Group[string, int]
+	_, _, err := group.Do(context.Background(), "k", func(context.Context) (int, error) {
+		panic("boom")
+	})
+	var panicked *singleflight.PanicError
+	require.True(t, errors.As(err, &panicked))
+	assert.Equal(t, "boom", panicked.Value)
+}
+
+func TestGroup_Forget(t *testing.T) {
+	var group singleflight.Group[string, int]
+	release := make(chan struct{})
+	first := make(chan int, 1)
+	started := make(chan struct{})
+	go func() {
+		value, _, _ := group.Do(context.Background(), "k", func(context.Context) (int, error) {
+			close(started)
+			<-release
+			return 1, nil
+		})
+		first <- value
+	}()
+	<-started
+
+	group.Forget("k")
+	value, shared, err := group.Do(context.Background(), "k", func(context.Context) (int, error) { return 2, nil })
+	require.NoError(t, err)
+	assert.Equal(t, 2, value)
+	assert.False(t, shared)
+
+	close(release)
+	assert.Equal(t, 1, <-first)
+}
@@ -0,0 +1,191 @@
+package storage_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"golang-arch/internal/shared/config"
+	"golang-arch/internal/shared/domain/domainerror"
+	"golang-arch/internal/shared/storage"
+	"golang-arch/pkg/clock"
+)
+
+func TestLocalStore_PutGetDelete(t *testing.T) {
+	store := storage.NewLocalStore(t.TempDir(), "http://localhost:8080", "secret", clock.New())
+	ctx := context.Background()
+
+	info, err := store.Put(ctx, "invoices/inv-1.pdf", strings.NewReader("%PDF-1.4"), storage.PutOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, int64(8), info.Size)
+	assert.Equal(t, "application/pdf", info.ContentType)
+
+	var buf strings.Builder
+	n, err := storage.Download(ctx, store, "invoices/inv-1.pdf", &buf)
+	require.NoError(t, err)
+	assert.Equal(t, int64(8), n)
+	assert.Equal(t, "%PDF-1.4", buf.String())
+
+	_, err = store.Stat(ctx, "invoices")
+	assert.ErrorIs(t, err, domainerror.NotFound, "directories are not blobs")
+
+	require.NoError(t, store.Delete(ctx, "invoices/inv-1.pdf"))
+	require.NoError(t, store.Delete(ctx, "invoices/inv-1.pdf"))
+	_, _, err = store.Get(ctx, "invoices/inv-1.pdf")
+	assert.ErrorIs(t, err, domainerror.NotFound)
+
+	for _, key := range []string{"", "/abs", "a/../b", "a//b", `a\b`} {
+		_, err := store.Put(ctx, key, strings.NewReader(""), storage.PutOptions{})
+		assert.ErrorIs(t, err, domainerror.Invalid, key)
+	}
+}
+
+func TestLocalStore_PresignedURLs(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	clk := clock.NewFake(time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC))
+	store := storage.NewLocalStore(t.TempDir(), "http://localhost:8080/", "secret", clk)
+	router := gin.New()
+	store.Register(&router.RouterGroup)
+	ctx := context.Background()
+
+	do := func(method, rawURL, body string) *httptest.ResponseRecorder {
+		u, err := url.Parse(rawURL)
+		require.NoError(t, err)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(method, u.RequestURI(), strings.NewReader(body)))
+		return w
+	}
+
+	putURL, err := store.Presign(ctx, http.MethodPut, "exports/report.csv", time.Hour)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(putURL, "http://localhost:8080/blobs/exports/report.csv?"), putURL)
+	assert.Equal(t, http.StatusOK, do(http.MethodPut, putURL, "a,b\n").Code)
+
+	getURL, err := store.Presign(ctx, http.MethodGet, "exports/report.csv", time.Hour)
+	require.NoError(t, err)
+	w := do(http.MethodGet, getURL, "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "a,b\n", w.Body.String())
+	assert.Contains(t, w.Header().Get("Content-Type"), "text/csv")
+
+	// A GET URL does not allow uploads, nor does it open another key
+	assert.Equal(t, http.StatusForbidden, do(http.MethodPut, getURL, "x").Code)
+	assert.Equal(t, http.StatusForbidden, do(http.MethodGet, strings.Replace(getURL, "report.csv", "other.csv", 1), "").Code)
+
+	clk.Advance(time.Hour + time.Second)
+	assert.Equal(t, http.StatusForbidden, do(http.MethodGet, getURL, "").Code)
+
+	_, err = storage.NewLocalStore(t.TempDir(), "", "", clk).Presign(ctx, http.MethodGet, "a.csv", time.Minute)
+	assert.Error(t, err)
+}
+
+func TestLocalStore_UploadSizeLimit(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	store := storage.NewLocalStore(t.TempDir(), "http://localhost:8080", "secret", clock.New(), storage.WithMaxUploadSize(8))
+	router := gin.New()
+	store.Register(&router.RouterGroup)
+	ctx := context.Background()
+
+	putURL, err := store.Presign(ctx, http.MethodPut, "uploads/a.txt", time.Hour)
+	require.NoError(t, err)
+	u, err := url.Parse(putURL)
+	require.NoError(t, err)
+	upload := func(body io.Reader, contentLength int64) int {
+		request := httptest.NewRequest(http.MethodPut, u.RequestURI(), body)
+		request.ContentLength = contentLength
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, request)
+		return w.Code
+	}
+
+	assert.Equal(t, http.StatusOK, upload(strings.NewReader("12345678"), 8))
+	assert.Equal(t, http.StatusRequestEntityTooLarge, upload(strings.NewReader("123456789"), 9))
+	// Without a length the limit applies while reading
+	assert.Equal(t, http.StatusRequestEntityTooLarge, upload(io.MultiReader(strings.NewReader("1234"), strings.NewReader("56789")), -1))
+
+	body, _, err := store.Get(ctx, "uploads/a.txt")
+	require.NoError(t, err)
+	defer body.Close()
+	content, err := io.ReadAll(body)
+	require.NoError(t, err)
+	assert.Equal(t, "12345678", string(content), "refused uploads leave the blob untouched")
+}
+
+func TestWriter_StreamsIntoStore(t *testing.T) {
+	store := storage.NewMemoryStore(clock.New())
+	ctx := context.Background()
+
+	w := storage.NewWriter(ctx, store, "exports/big.csv", storage.PutOptions{ContentType: "text/csv"})
+	for i := 0; i < 1000; i++ {
+		_, err := io.WriteString(w, "row\n")
+		require.NoError(t, err)
+	}
+	require.NoError(t, w.Close())
+	assert.Equal(t, int64(4000), w.Info().Size)
+	assert.Equal(t, "text/csv", w.Info().ContentType)
+
+	aborted := storage.NewWriter(ctx, store, "exports/aborted.csv", storage.PutOptions{})
+	io.WriteString(aborted, "partial")
+	cause := errors.New("encoding failed")
+	assert.Equal(t, cause, aborted.Abort(cause))
+	_, err := store.Stat(ctx, "exports/aborted.csv")
+	assert.ErrorIs(t, err, domainerror.NotFound)
+
+	invalid := storage.NewWriter(ctx, store, "../x", storage.PutOptions{})
+	_, err = io.WriteString(invalid, "data")
+	assert.ErrorIs(t, err, domainerror.Invalid)
+	assert.ErrorIs(t, invalid.Close(), domainerror.Invalid)
+}
+
+func TestServe_Attachment(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	store := storage.NewMemoryStore(clock.New())
+	_, err := store.Put(context.Background(), "invoices/inv-7.pdf", strings.NewReader("%PDF"), storage.PutOptions{ContentType: "application/pdf"})
+	require.NoError(t, err)
+
+	router := gin.New()
+	router.GET("/invoices/:key", func(c *gin.Context) {
+		storage.Serve(c, store, "invoices/"+c.Param("key"), "Invoice 7.pdf")
+	})
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/invoices/inv-7.pdf", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "%PDF", w.Body.String())
+	assert.Equal(t, `attachment; filename="Invoice 7.pdf"`, w.Header().Get("Content-Disposition"))
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/invoices/missing.pdf", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestNewBlobStore(t *testing.T) {
+	clk := clock.New()
+	store, err := storage.NewBlobStore(config.StorageConfig{LocalDir: t.TempDir()}, clk)
+	require.NoError(t, err)
+	assert.Equal(t, "local", store.Name())
+
+	store, err = storage.NewBlobStore(config.StorageConfig{Provider: "s3", Bucket: "b", AccessKey: "a", SecretKey: "s"}, clk)
+	require.NoError(t, err)
+	assert.Equal(t, "s3", store.Name())
+
+	for _, cfg := range []config.StorageConfig{
+		{Provider: "s3", Bucket: "b"},
+		{Provider: "s3", Bucket: "b", AccessKey: "a", SecretKey: "s", Endpoint: "not a url"},
+		{Provider: "gcs", Bucket: "b"},
+		{Provider: "azure"},
+	} {
+		_, err := storage.NewBlobStore(cfg, clk)
+		assert.Error(t, err, cfg.Provider)
+	}
+}
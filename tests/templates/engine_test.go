@@ -0,0 +1,201 @@
+package templates_test
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	i18n "golang-arch/internal/shared/domain/internationalization"
+	"golang-arch/internal/shared/geo"
+	"golang-arch/internal/shared/templates"
+)
+
+func testFS() fstest.MapFS {
+	return fstest.MapFS{
+		"pages/orders.html": {Data: []byte(`{{ template "layouts/page.html" . }}
+{{ define "title" }}{{ t "orders.title" }}{{ end }}
+{{ define "content" }}<h1>{{ t "orders.title" }}</h1>{{ template "partials/alert.html" .Alert }}
+<p>{{ money .Total }} · {{ date .Placed }}</p>{{ end }}`)},
+		"emails/welcome.html": {Data: []byte(`{{ template "layouts/email.html" . }}
+{{ define "subject" }}{{ t "welcome.subject" "name" .Name }}{{ end }}
+{{ define "content" }}<p>{{ t "welcome.greeting" "name" .Name }}</p>{{ end }}`)},
+		"emails/welcome.txt": {Data: []byte(`{{ template "layouts/email.txt" . }}
+{{ define "subject" }}{{ t "welcome.subject" "name" .Name }}{{ end }}
+{{ define "content" }}{{ t "welcome.greeting" "name" .Name }}{{ end }}`)},
+		"emails/receipt.html":   {Data: []byte(`{{ define "subject" }}Tom & Jerry{{ end }}<p>{{ phone .Phone }}</p>`)},
+		"emails/nosubject.html": {Data: []byte(`<p>hi</p>`)},
+		"locales/app.json": {Data: []byte(`{
+			"orders.title": {"en": "Orders", "de": "Bestellungen"},
+			"welcome.subject": {"en": "Welcome, {name}", "de": "Willkommen, {name}"},
+			"welcome.greeting": {"en": "Hello {name}!", "de": "Hallo {name}!"}
+		}`)},
+		"assets/app.js": {Data: []byte(`console.log("hi")`)},
+	}
+}
+
+func newEngine(t *testing.T, options ...templates.Option) *templates.Engine {
+	t.Helper()
+	engine, err := templates.NewEngine(append([]templates.Option{templates.WithFS(testFS())}, options...)...)
+	require.NoError(t, err)
+	return engine
+}
+
+func ordersData(t *testing.T, total int64) map[string]any {
+	t.Helper()
+	placed, err := i18n.NewDate(2024, 5, 24)
+	require.NoError(t, err)
+	return map[string]any{
+		"Alert":  map[string]string{"Kind": "info", "Message": "<b>3 neue</b>"},
+		"Total":  money(t, total, "EUR"),
+		"Placed": placed,
+	}
+}
+
+func TestEngine_RenderPageWithLayout(t *testing.T) {
+	engine := newEngine(t)
+
+	var out bytes.Buffer
+	err := engine.Render(&out, "pages/orders.html", prefs(t, "de-DE", "Europe/Berlin", "EUR"), ordersData(t, 123456))
+	require.NoError(t, err)
+
+	page := out.String()
+	assert.Contains(t, page, `<html lang="de-DE" dir="ltr">`)
+	assert.Contains(t, page, "<title>Bestellungen</title>")
+	assert.Contains(t, page, `<div class="alert alert-info" role="alert">&lt;b&gt;3 neue&lt;/b&gt;</div>`)
+	assert.Contains(t, page, "1.234,56 € · 24.05.2024")
+
+	css, err := engine.AssetURL("base.css")
+	require.NoError(t, err)
+	assert.Contains(t, page, `<link rel="stylesheet" href="`+css+`">`)
+
+	err = engine.Render(&out, "pages/missing.html", templates.DefaultPreferences(), nil)
+	assert.ErrorContains(t, err, "unknown template")
+}
+
+func TestEngine_RenderEmail(t *testing.T) {
+	engine := newEngine(t)
+
+	email, err := engine.RenderEmail("welcome", prefs(t, "de-AT", "UTC", "EUR"), map[string]string{"Name": "Anna & Ben"})
+	require.NoError(t, err)
+	assert.Equal(t, "Willkommen, Anna & Ben", email.Subject)
+	assert.Contains(t, email.HTML, "<p>Hallo Anna &amp; Ben!</p>")
+	assert.Contains(t, email.HTML, "Diese Nachricht wurde automatisch versendet.")
+	assert.Equal(t, "Hallo Anna & Ben!\n\n--\nDiese Nachricht wurde automatisch versendet. Bitte antworten Sie nicht darauf.\n", email.Text)
+
+	// Without a text body the subject comes from the HTML one, unescaped
+	phone, err := i18n.NewPhone("1", "2025550123")
+	require.NoError(t, err)
+	email, err = engine.RenderEmail("receipt", templates.DefaultPreferences(), map[string]any{"Phone": phone})
+	require.NoError(t, err)
+	assert.Equal(t, "Tom & Jerry", email.Subject)
+	assert.Contains(t, email.HTML, "&#43;1 2025550123")
+	assert.Empty(t, email.Text)
+
+	_, err = engine.RenderEmail("nosubject", templates.DefaultPreferences(), nil)
+	assert.ErrorContains(t, err, "defines no subject")
+}
+
+func TestEngine_SourcesAndCatalogs(t *testing.T) {
+	label, err := i18n.NewLocalizedString(map[string]string{"en": "Purchases"})
+	require.NoError(t, err)
+	override := fstest.MapFS{
+		"layouts/page.html": {Data: []byte(`[{{ block "title" . }}{{ end }}]`)},
+	}
+	engine := newEngine(t, templates.WithFS(override), templates.WithCatalog(templates.Catalog{"orders.title": *label}))
+
+	var out bytes.Buffer
+	require.NoError(t, engine.Render(&out, "pages/orders.html", templates.DefaultPreferences(), nil))
+	assert.Equal(t, "[Purchases]", strings.TrimSpace(out.String()))
+
+	_, err = templates.NewEngine(templates.WithFS(fstest.MapFS{"pages/bad.html": {Data: []byte(`{{ if }}`)}}))
+	assert.Error(t, err)
+	_, err = templates.NewEngine(templates.WithFS(fstest.MapFS{"locales/bad.json": {Data: []byte(`{"a": {"not a tag!": "x"}}`)}}))
+	assert.ErrorContains(t, err, "locales/bad.json")
+}
+
+func TestEngine_UseCatalog(t *testing.T) {
+	purchases, err := i18n.NewLocalizedString(map[string]string{"en": "Purchases"})
+	require.NoError(t, err)
+	engine := newEngine(t, templates.WithCatalog(templates.Catalog{"orders.title": *purchases}))
+	prefs := templates.DefaultPreferences()
+	assert.Equal(t, "Purchases", engine.Formatter(prefs).Translate("orders.title"))
+
+	bought, err := i18n.NewLocalizedString(map[string]string{"en": "Bought"})
+	require.NoError(t, err)
+	engine.UseCatalog(templates.Catalog{"orders.title": *bought})
+	assert.Equal(t, "Bought", engine.Formatter(prefs).Translate("orders.title"))
+
+	engine.UseCatalog(nil)
+	assert.Equal(t, "Orders", engine.Formatter(prefs).Translate("orders.title"), "the previous labels are replaced, not merged")
+}
+
+func TestEngine_HTMLUsesRequestLocation(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	engine := newEngine(t)
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		location := geo.Location{Country: "DE", Timezone: "Europe/Berlin", Locale: "de-DE", Detected: true}
+		c.Request = c.Request.WithContext(geo.NewContext(c.Request.Context(), location))
+	})
+	router.GET("/orders", func(c *gin.Context) {
+		engine.HTML(c, http.StatusOK, "pages/orders.html", ordersData(t, 500))
+	})
+	router.GET("/saved", func(c *gin.Context) {
+		saved := i18n.PartialLocalePreferences{Locale: "en-GB"}
+		engine.HTML(c, http.StatusOK, "pages/orders.html", ordersData(t, 500), saved)
+	})
+	router.GET("/broken", func(c *gin.Context) {
+		data := ordersData(t, 500)
+		data["Placed"] = "yesterday"
+		engine.HTML(c, http.StatusOK, "pages/orders.html", data)
+	})
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/orders", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "text/html; charset=utf-8", w.Header().Get("Content-Type"))
+	assert.Contains(t, w.Body.String(), "<h1>Bestellungen</h1>")
+	assert.Contains(t, w.Body.String(), "5,00 €")
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/saved", nil))
+	assert.Contains(t, w.Body.String(), `<html lang="en-GB"`, "saved preferences win over the location")
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/broken", nil))
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.NotContains(t, w.Body.String(), "<html")
+}
+
+func TestEngine_ServesAssets(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	engine := newEngine(t)
+	router := gin.New()
+	engine.Register(&router.RouterGroup)
+
+	url, err := engine.AssetURL("app.js")
+	require.NoError(t, err)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, url, nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, `console.log("hi")`, w.Body.String())
+	assert.Equal(t, "public, max-age=31536000, immutable", w.Header().Get("Cache-Control"))
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/assets/app.js", nil))
+	assert.Equal(t, "no-cache", w.Header().Get("Cache-Control"))
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/assets/missing.js", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	_, err = engine.AssetURL("missing.js")
+	assert.Error(t, err)
+}
@@ -0,0 +1,93 @@
+package templates_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	i18n "golang-arch/internal/shared/domain/internationalization"
+	"golang-arch/internal/shared/templates"
+)
+
+func prefs(t *testing.T, locale, timezone, currency string) i18n.LocalePreferences {
+	t.Helper()
+	p, err := i18n.NewLocalePreferencesFromPrimitive(locale, timezone, currency, "metric", 1)
+	require.NoError(t, err)
+	return *p
+}
+
+func money(t *testing.T, amount int64, currency string) i18n.Money {
+	t.Helper()
+	m, err := i18n.NewMoneyFromPrimitive(amount, currency)
+	require.NoError(t, err)
+	return *m
+}
+
+func TestFormatter_Money(t *testing.T) {
+	tests := []struct {
+		locale string
+		amount int64
+		code   string
+		want   string
+	}{
+		{"en-US", 123456, "USD", "$1,234.56"},
+		{"en-US", -123456, "USD", "-$1,234.56"},
+		{"de-DE", 123456, "EUR", "1.234,56\u00a0€"},
+		{"fr-FR", 123456, "EUR", "1\u00a0234,56\u00a0€"},
+		{"id-ID", 1500000, "IDR", "Rp1.500.000"},
+		{"ja-JP", 1234, "JPY", "¥1,234"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.locale, func(t *testing.T) {
+			f := templates.NewFormatter(prefs(t, tt.locale, "UTC", tt.code), nil)
+			assert.Equal(t, tt.want, f.Money(money(t, tt.amount, tt.code)))
+		})
+	}
+}
+
+func TestFormatter_DatesInReaderTimezone(t *testing.T) {
+	// 23:30 UTC is already the next day in Berlin
+	issued := time.Date(2024, 5, 24, 23, 30, 0, 0, time.UTC)
+
+	us := templates.NewFormatter(prefs(t, "en-US", "America/New_York", "USD"), nil)
+	date, err := us.Date(issued)
+	require.NoError(t, err)
+	assert.Equal(t, "05/24/2024", date)
+	dateTime, err := us.DateTime(issued)
+	require.NoError(t, err)
+	assert.Equal(t, "05/24/2024 7:30 PM EDT", dateTime)
+
+	de := templates.NewFormatter(prefs(t, "de-DE", "Europe/Berlin", "EUR"), nil)
+	date, err = de.Date(issued)
+	require.NoError(t, err)
+	assert.Equal(t, "25.05.2024", date)
+	dateTime, err = de.DateTime(issued)
+	require.NoError(t, err)
+	assert.Equal(t, "25.05.2024 01:30 CEST", dateTime)
+	dateTime, err = de.DateTime(time.Date(2024, 1, 10, 12, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	assert.Equal(t, "10.01.2024 13:00 CET", dateTime, "the zone's rules apply at the formatted time")
+
+	due, err := i18n.NewDate(2024, time.June, 7)
+	require.NoError(t, err)
+	date, err = de.Date(due)
+	require.NoError(t, err)
+	assert.Equal(t, "07.06.2024", date, "dates are not shifted by the timezone")
+
+	_, err = de.Date("yesterday")
+	assert.Error(t, err)
+}
+
+func TestFormatter_PhoneAndNumber(t *testing.T) {
+	f := templates.NewFormatter(prefs(t, "de-DE", "UTC", "EUR"), nil)
+	phone, err := f.Phone("+49 30 1234567")
+	require.NoError(t, err)
+	assert.Equal(t, "+49 301234567", phone)
+	_, err = f.Phone(42)
+	assert.Error(t, err)
+
+	assert.Equal(t, "1.234,5", f.Number(1234.5))
+	assert.Equal(t, "12", f.Number(int64(12)))
+}
@@ -0,0 +1,118 @@
+package validation_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	i18n "golang-arch/internal/shared/domain/internationalization"
+	"golang-arch/internal/shared/domain/validation"
+)
+
+var (
+	_ validation.Validator = i18n.Money{}
+	_ validation.Validator = i18n.Currency{}
+	_ validation.Validator = i18n.Time{}
+	_ validation.Validator = i18n.Timezone{}
+	_ validation.Validator = i18n.Phone{}
+	_ validation.Validator = i18n.LocalizedDateTime{}
+	_ validation.Validator = i18n.LocalizedPhone{}
+)
+
+func TestValidationErrors_Empty(t *testing.T) {
+	var errs validation.ValidationErrors
+	assert.NoError(t, errs.Err())
+	assert.Nil(t, validation.Validate(i18n.Time{Epoch: 1703520000}))
+}
+
+func TestValidationErrors_Error(t *testing.T) {
+	var errs validation.ValidationErrors
+	errs.Add("name", validation.CodeRequired, "name cannot be empty", nil)
+	assert.Equal(t, "name cannot be empty", errs.Error())
+
+	errs.Add("age", validation.CodeOutOfRange, "age must be positive", map[string]any{"min": 0})
+	assert.Equal(t, "2 validation errors: name cannot be empty; age must be positive", errs.Error())
+}
+
+func TestValidationErrors_Merge(t *testing.T) {
+	var nested validation.ValidationErrors
+	nested.Add("code", validation.CodeRequired, "code cannot be empty", nil)
+
+	var errs validation.ValidationErrors
+	errs.Merge("currency", "invalid currency", nested)
+	errs.Merge("note", "", errors.New("too long"))
+	errs.Merge("ignored", "", nil)
+
+	require.Len(t, errs, 2)
+	assert.Equal(t, "currency.code", errs[0].Field)
+	assert.Equal(t, validation.CodeRequired, errs[0].Code)
+	assert.Equal(t, "invalid currency: code cannot be empty", errs[0].Message)
+	assert.Equal(t, "note", errs[1].Field)
+	assert.Equal(t, validation.CodeInvalid, errs[1].Code)
+}
+
+func TestValidationErrors_ErrorsAsThroughWrapping(t *testing.T) {
+	_, err := i18n.NewPhone("0", "12")
+	require.Error(t, err)
+
+	var errs validation.ValidationErrors
+	require.True(t, errors.As(err, &errs))
+	assert.Len(t, errs, 2)
+
+	var fieldErr validation.FieldError
+	assert.True(t, errors.As(err, &fieldErr))
+	assert.Equal(t, "country_code", fieldErr.Field)
+}
+
+func TestValidationErrors_CollectsAllCurrencyProblems(t *testing.T) {
+	errs := validation.Validate(i18n.Currency{Code: "usd", DecimalPlaces: 19})
+
+	fields := errs.ByField()
+	assert.Len(t, errs, 4)
+	assert.Equal(t, validation.CodeInvalidFormat, fields["code"][0].Code)
+	assert.Equal(t, validation.CodeRequired, fields["symbol"][0].Code)
+	assert.Equal(t, validation.CodeRequired, fields["name"][0].Code)
+	assert.Equal(t, validation.CodeOutOfRange, fields["decimal_places"][0].Code)
+	assert.Equal(t, 18, fields["decimal_places"][0].Params["max"])
+}
+
+func TestValidationErrors_NestedComposite(t *testing.T) {
+	ldt := i18n.LocalizedDateTime{
+		Time:     i18n.Time{Epoch: -1},
+		Timezone: i18n.Timezone{ID: "Mars/Olympus", Name: "Mars"},
+	}
+
+	errs := validation.Validate(ldt)
+	require.Len(t, errs, 2)
+	assert.Equal(t, "time.epoch", errs[0].Field)
+	assert.Equal(t, "timezone.id", errs[1].Field)
+	assert.Equal(t, validation.CodeUnsupported, errs[1].Code)
+	assert.Contains(t, errs[0].Message, "invalid time in localized datetime")
+}
+
+func TestValidationErrors_Localize(t *testing.T) {
+	errs := validation.Validate(i18n.Phone{})
+
+	localized := errs.Localize(func(fe validation.FieldError) string {
+		if fe.Code == validation.CodeRequired {
+			return fmt.Sprintf("%s wajib diisi", fe.Field)
+		}
+		return ""
+	})
+
+	require.Len(t, localized, 2)
+	assert.Equal(t, "country_code wajib diisi", localized[0].Message)
+	assert.Equal(t, "number wajib diisi", localized[1].Message)
+	assert.Equal(t, "country code cannot be empty", errs[0].Message, "Localize must not modify the original")
+}
+
+func TestFromError_PlainError(t *testing.T) {
+	errs := validation.FromError(errors.New("boom"))
+	require.Len(t, errs, 1)
+	assert.Equal(t, validation.CodeInvalid, errs[0].Code)
+	assert.Empty(t, errs[0].Field)
+	assert.Nil(t, validation.FromError(nil))
+}